@@ -30,6 +30,8 @@ func main() {
 	// Start background services
 	a.StartJobManager()
 	a.StartTimelineScheduler()
+	a.StartConfigWatcher()
+	a.StartCachePurge()
 
 	// Create shutdown channel for HTTP endpoint
 	shutdownChan := make(chan struct{})
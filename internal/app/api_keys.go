@@ -0,0 +1,179 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/interfaces"
+)
+
+// APIKeyRotation reports the outcome of setting or clearing a stored API key.
+// Source says where the live client's key now comes from: "store", "env" or
+// "config". The key itself is never returned.
+type APIKeyRotation struct {
+	Name      string    `json:"name"`
+	Action    string    `json:"action"`
+	Source    string    `json:"source"`
+	Warning   string    `json:"warning,omitempty"`
+	RotatedAt time.Time `json:"rotated_at"`
+}
+
+// keyRotator swaps the key of one shared client. probe validates a candidate
+// key without touching the live client; apply swaps it in.
+type keyRotator struct {
+	probe    func(ctx context.Context, key string) error
+	apply    func(ctx context.Context, key string) error
+	fallback string // config file key, used when the stored key is cleared
+}
+
+// RotatableAPIKeys lists the key names accepted by SetAPIKey and ClearAPIKey.
+// Navexa keys are per user and are managed through the user profile instead.
+var RotatableAPIKeys = []string{"eodhd_api_key", "gemini_api_key"}
+
+func (a *App) keyRotator(name string) (keyRotator, error) {
+	switch name {
+	case "eodhd_api_key":
+		if a.eodhd == nil {
+			return keyRotator{}, fmt.Errorf("EODHD client was not configured at startup; restart the server after storing a key")
+		}
+		return keyRotator{
+			probe: a.eodhd.ProbeKey,
+			apply: func(_ context.Context, key string) error {
+				a.eodhd.SetAPIKey(key)
+				return nil
+			},
+			fallback: a.Config.Clients.EODHD.APIKey,
+		}, nil
+	case "gemini_api_key":
+		if a.gemini == nil {
+			return keyRotator{}, fmt.Errorf("Gemini client was not configured at startup; restart the server after storing a key")
+		}
+		return keyRotator{
+			probe:    a.gemini.ProbeKey,
+			apply:    a.gemini.SetAPIKey,
+			fallback: a.Config.Clients.Gemini.APIKey,
+		}, nil
+	}
+	return keyRotator{}, fmt.Errorf("unknown API key %q (expected one of: %s)", name, strings.Join(RotatableAPIKeys, ", "))
+}
+
+// SetAPIKey stores key in the system KV store and swaps it into the live
+// client without a restart. The key is first probed against the provider;
+// a rejected key changes nothing, and a failed swap restores the previously
+// stored value.
+func (a *App) SetAPIKey(ctx context.Context, name, key string) (*APIKeyRotation, error) {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return nil, fmt.Errorf("key is required (use admin_clear_api_key to remove a stored key)")
+	}
+	r, err := a.keyRotator(name)
+	if err != nil {
+		return nil, err
+	}
+
+	a.keysMu.Lock()
+	defer a.keysMu.Unlock()
+
+	if err := r.probe(ctx, key); err != nil {
+		return nil, fmt.Errorf("%s rejected, current key kept: %w", name, err)
+	}
+	sealed, err := common.SealAPIKey(name, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt %s: %w", name, err)
+	}
+	prev, _ := a.keyStore.GetSystemKV(ctx, name)
+	if err := a.keyStore.SetSystemKV(ctx, name, sealed); err != nil {
+		return nil, fmt.Errorf("failed to store %s: %w", name, err)
+	}
+	if err := r.apply(ctx, key); err != nil {
+		a.revertStoredKey(ctx, name, prev)
+		return nil, fmt.Errorf("failed to rebuild client with new %s, stored key reverted: %w", name, err)
+	}
+
+	result := &APIKeyRotation{Name: name, Action: "set", Source: "store", RotatedAt: time.Now()}
+	if env := common.APIKeyEnvOverride(name); env != "" {
+		result.Warning = fmt.Sprintf("%s is set in the environment and will take precedence over the stored key after a restart", env)
+	}
+	a.Logger.Info().Str("key", name).Msg("API key rotated")
+	return result, nil
+}
+
+// ClearAPIKey removes the stored key and swaps the live client back to the
+// key startup would resolve without it (environment, then config file). It
+// fails without changing anything when there is no key to fall back to; use
+// SetAPIKey to replace a leaked key instead.
+func (a *App) ClearAPIKey(ctx context.Context, name string) (*APIKeyRotation, error) {
+	r, err := a.keyRotator(name)
+	if err != nil {
+		return nil, err
+	}
+
+	a.keysMu.Lock()
+	defer a.keysMu.Unlock()
+
+	key, source := r.fallback, "config"
+	if env := common.APIKeyEnvOverride(name); env != "" {
+		key, _ = common.ResolveAPIKey(ctx, nil, name, "")
+		source = "env"
+	}
+	if key == "" {
+		return nil, fmt.Errorf("no environment or config key to fall back to for %s; use admin_set_api_key to replace it", name)
+	}
+
+	prev, _ := a.keyStore.GetSystemKV(ctx, name)
+	if err := a.keyStore.SetSystemKV(ctx, name, ""); err != nil {
+		return nil, fmt.Errorf("failed to clear %s: %w", name, err)
+	}
+	if err := r.apply(ctx, key); err != nil {
+		a.revertStoredKey(ctx, name, prev)
+		return nil, fmt.Errorf("failed to rebuild client for %s, stored key restored: %w", name, err)
+	}
+
+	a.Logger.Info().Str("key", name).Str("source", source).Msg("Stored API key cleared")
+	return &APIKeyRotation{Name: name, Action: "cleared", Source: source, RotatedAt: time.Now()}, nil
+}
+
+func (a *App) revertStoredKey(ctx context.Context, name, prev string) {
+	if err := a.keyStore.SetSystemKV(ctx, name, prev); err != nil {
+		a.Logger.Error().Err(err).Str("key", name).Msg("Failed to restore stored API key")
+	}
+}
+
+// storedAPIKeys are the system KV entries read by ResolveAPIKey.
+var storedAPIKeys = []string{"eodhd_api_key", "alphavantage_api_key", "gemini_api_key"}
+
+// configureKeyEncryption loads the master key and installs the cipher for
+// stored API keys. A master key is loaded even with encrypt_keys off, so keys
+// encrypted earlier keep working; encrypt_keys without one is an error.
+func configureKeyEncryption(config *common.Config) error {
+	masterKey, err := common.LoadMasterKey(config.Security.MasterKeyFile)
+	if err != nil {
+		return fmt.Errorf("[security] %w", err)
+	}
+	if masterKey == nil {
+		if config.Security.EncryptKeys {
+			return fmt.Errorf("[security] encrypt_keys requires VIRE_MASTER_KEY or master_key_file")
+		}
+		common.SetKeyCipher(nil, false)
+		return nil
+	}
+	c, err := common.NewKeyCipher(masterKey)
+	if err != nil {
+		return fmt.Errorf("[security] %w", err)
+	}
+	common.SetKeyCipher(c, config.Security.EncryptKeys)
+	return nil
+}
+
+// warnPlaintextAPIKeys logs stored keys written before encryption was
+// enabled. They still resolve; rotating them with admin_set_api_key encrypts them.
+func warnPlaintextAPIKeys(ctx context.Context, store interfaces.InternalStore, logger *common.Logger) {
+	for _, name := range storedAPIKeys {
+		if v, err := store.GetSystemKV(ctx, name); err == nil && v != "" && !common.IsEncryptedKey(v) {
+			logger.Warn().Str("key", name).Msg("Stored API key is plaintext; re-set it with admin_set_api_key to encrypt it")
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/bobmcallan/vire/internal/clients/eodhd"
+	"github.com/bobmcallan/vire/internal/common"
+)
+
+// fakeEODHD accepts only the keys in valid and records the key sent with
+// each non-probe request.
+type fakeEODHD struct {
+	mu      sync.Mutex
+	valid   map[string]bool
+	lastKey string
+}
+
+func (f *fakeEODHD) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key := r.URL.Query().Get("api_token")
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.valid[key] {
+		http.Error(w, "Unauthenticated", http.StatusUnauthorized)
+		return
+	}
+	if r.URL.Path == "/user" {
+		w.Write([]byte(`{"name":"test"}`))
+		return
+	}
+	f.lastKey = key
+	w.Write([]byte(`{"code":"BHP.AU","close":45.1}`))
+}
+
+func (f *fakeEODHD) seenKey() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.lastKey
+}
+
+func newKeyTestApp(t *testing.T, valid ...string) (*App, *fakeEODHD, *mockInternalStore) {
+	t.Helper()
+	t.Setenv("EODHD_API_KEY", "")
+	t.Setenv("VIRE_EODHD_API_KEY", "")
+
+	fake := &fakeEODHD{valid: map[string]bool{}}
+	for _, k := range valid {
+		fake.valid[k] = true
+	}
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	store := newMockInternalStore()
+	cfg := common.NewDefaultConfig()
+	cfg.Clients.EODHD.APIKey = "config-key"
+	a := &App{
+		Config:   cfg,
+		Logger:   common.NewSilentLogger(),
+		eodhd:    eodhd.NewClient("config-key", eodhd.WithBaseURL(srv.URL)),
+		keyStore: store,
+	}
+	return a, fake, store
+}
+
+func TestSetAPIKey_ValidKeySwapsClient(t *testing.T) {
+	a, fake, store := newKeyTestApp(t, "config-key", "new-key")
+	ctx := context.Background()
+
+	result, err := a.SetAPIKey(ctx, "eodhd_api_key", " new-key ")
+	if err != nil {
+		t.Fatalf("SetAPIKey: %v", err)
+	}
+	if result.Action != "set" || result.Source != "store" || result.Warning != "" {
+		t.Errorf("result = %+v, want set from store without warning", result)
+	}
+	if store.kv["eodhd_api_key"] != "new-key" {
+		t.Errorf("stored key = %q, want new-key", store.kv["eodhd_api_key"])
+	}
+	if _, err := a.eodhd.GetRealTimeQuote(ctx, "BHP.AU"); err != nil {
+		t.Fatalf("request after rotation: %v", err)
+	}
+	if got := fake.seenKey(); got != "new-key" {
+		t.Errorf("live client sent key %q, want new-key", got)
+	}
+}
+
+func TestSetAPIKey_InvalidKeyRejected(t *testing.T) {
+	a, fake, store := newKeyTestApp(t, "config-key")
+	store.kv["eodhd_api_key"] = "stored-key"
+	ctx := context.Background()
+
+	_, err := a.SetAPIKey(ctx, "eodhd_api_key", "leaked-or-wrong")
+	if err == nil || !strings.Contains(err.Error(), "rejected") {
+		t.Fatalf("SetAPIKey error = %v, want a probe rejection", err)
+	}
+	if store.kv["eodhd_api_key"] != "stored-key" {
+		t.Errorf("stored key = %q, want stored-key untouched", store.kv["eodhd_api_key"])
+	}
+	if _, err := a.eodhd.GetRealTimeQuote(ctx, "BHP.AU"); err != nil {
+		t.Fatalf("request after rejected rotation: %v", err)
+	}
+	if got := fake.seenKey(); got != "config-key" {
+		t.Errorf("live client sent key %q, want config-key kept", got)
+	}
+}
+
+func TestSetAPIKey_ArgumentErrors(t *testing.T) {
+	a, _, _ := newKeyTestApp(t, "config-key")
+	ctx := context.Background()
+
+	tests := []struct {
+		name, key, value, wantErr string
+	}{
+		{"empty key", "eodhd_api_key", "  ", "key is required"},
+		{"unknown name", "navexa_api_key", "k", "unknown API key"},
+		{"client not configured", "gemini_api_key", "k", "not configured at startup"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := a.SetAPIKey(ctx, tt.key, tt.value); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("SetAPIKey error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClearAPIKey_FallsBackToConfigKey(t *testing.T) {
+	a, fake, store := newKeyTestApp(t, "config-key", "new-key")
+	ctx := context.Background()
+	if _, err := a.SetAPIKey(ctx, "eodhd_api_key", "new-key"); err != nil {
+		t.Fatalf("SetAPIKey: %v", err)
+	}
+
+	result, err := a.ClearAPIKey(ctx, "eodhd_api_key")
+	if err != nil {
+		t.Fatalf("ClearAPIKey: %v", err)
+	}
+	if result.Action != "cleared" || result.Source != "config" {
+		t.Errorf("result = %+v, want cleared to config", result)
+	}
+	if store.kv["eodhd_api_key"] != "" {
+		t.Errorf("stored key = %q, want cleared", store.kv["eodhd_api_key"])
+	}
+	if _, err := a.eodhd.GetRealTimeQuote(ctx, "BHP.AU"); err != nil {
+		t.Fatalf("request after clear: %v", err)
+	}
+	if got := fake.seenKey(); got != "config-key" {
+		t.Errorf("live client sent key %q, want config-key", got)
+	}
+
+	// Without a config key there is nothing to fall back to
+	a.Config.Clients.EODHD.APIKey = ""
+	if _, err := a.ClearAPIKey(ctx, "eodhd_api_key"); err == nil || !strings.Contains(err.Error(), "no environment or config key") {
+		t.Errorf("ClearAPIKey error = %v, want no-fallback error", err)
+	}
+}
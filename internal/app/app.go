@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
 
+	"github.com/bobmcallan/vire/internal/clients/alphavantage"
 	"github.com/bobmcallan/vire/internal/clients/asx"
 	"github.com/bobmcallan/vire/internal/clients/eodhd"
 	"github.com/bobmcallan/vire/internal/clients/gemini"
@@ -15,9 +17,11 @@ import (
 	"github.com/bobmcallan/vire/internal/interfaces"
 	"github.com/bobmcallan/vire/internal/services/assetset"
 	"github.com/bobmcallan/vire/internal/services/cashflow"
+	"github.com/bobmcallan/vire/internal/services/fx"
 	"github.com/bobmcallan/vire/internal/services/holdingnotes"
 	"github.com/bobmcallan/vire/internal/services/jobmanager"
 	"github.com/bobmcallan/vire/internal/services/market"
+	"github.com/bobmcallan/vire/internal/services/notify"
 	"github.com/bobmcallan/vire/internal/services/plan"
 	"github.com/bobmcallan/vire/internal/services/portfolio"
 	"github.com/bobmcallan/vire/internal/services/quote"
@@ -51,12 +55,27 @@ type App struct {
 	CashFlowService    interfaces.CashFlowService
 	TradeService       interfaces.TradeService
 	AssetSetService    interfaces.AssetSetService
+	NotifyService      *notify.Service
 	JobManager         *jobmanager.JobManager
 	StartupTime        time.Time
 
-	schedulerCancel context.CancelFunc
-	warmCacheCancel context.CancelFunc
-	timelineCancel  context.CancelFunc
+	// configMu guards the hot-reloadable fields of Config (see
+	// common.ApplyHotReload). Other fields are fixed after startup.
+	configMu      sync.RWMutex
+	configWatcher *common.ConfigWatcher
+	eodhd         *eodhd.Client // concrete client, for live rate limit and key changes
+	gemini        *gemini.Client
+	geminiCache   *gemini.CachedClient // nil when the Gemini cache is disabled
+
+	// keysMu serialises API key rotations against keyStore (see api_keys.go).
+	keysMu   sync.Mutex
+	keyStore interfaces.InternalStore
+
+	schedulerCancel   context.CancelFunc
+	warmCacheCancel   context.CancelFunc
+	timelineCancel    context.CancelFunc
+	configWatchCancel context.CancelFunc
+	cachePurgeCancel  context.CancelFunc
 }
 
 // getBinaryDir returns the directory containing the executable.
@@ -110,19 +129,15 @@ func NewApp(configPath string) (*App, error) {
 		os.Exit(1)
 	}
 
-	// Resolve relative storage paths to binary directory
-	if config.Storage.DataPath != "" && !filepath.IsAbs(config.Storage.DataPath) {
-		config.Storage.DataPath = filepath.Join(binDir, config.Storage.DataPath)
+	// Validate ranges and cross-field constraints, reporting every problem at once
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("config file %s: %w", configPath, err)
 	}
 
-	// Resolve relative blob path to binary directory
-	if config.Storage.Blob.Path != "" && !filepath.IsAbs(config.Storage.Blob.Path) {
-		config.Storage.Blob.Path = filepath.Join(binDir, config.Storage.Blob.Path)
-	}
+	resolveConfigPaths(config, binDir)
 
-	// Resolve relative log file path to binary directory
-	if config.Logging.FilePath != "" && !filepath.IsAbs(config.Logging.FilePath) {
-		config.Logging.FilePath = filepath.Join(binDir, config.Logging.FilePath)
+	if err := configureKeyEncryption(config); err != nil {
+		return nil, err
 	}
 
 	// Initialize logger (initially without log store — wired below after storage init)
@@ -149,6 +164,9 @@ func NewApp(configPath string) (*App, error) {
 
 	// Resolve API keys
 	internalStore := storageManager.InternalStore()
+	if config.Security.EncryptKeys {
+		warnPlaintextAPIKeys(ctx, internalStore, logger)
+	}
 
 	// Bootstrap break-glass admin if enabled
 	if config.Auth.Breakglass {
@@ -157,12 +175,14 @@ func NewApp(configPath string) (*App, error) {
 
 	eodhdKey, err := common.ResolveAPIKey(ctx, internalStore, "eodhd_api_key", config.Clients.EODHD.APIKey)
 	if err != nil {
-		logger.Warn().Msg("EODHD API key not configured - some features may be limited")
+		logger.Warn().Err(err).Msg("EODHD API key not configured - some features may be limited")
 	}
 
+	alphaVantageKey, _ := common.ResolveAPIKey(ctx, internalStore, "alphavantage_api_key", config.Clients.AlphaVantage.APIKey)
+
 	geminiKey, err := common.ResolveAPIKey(ctx, internalStore, "gemini_api_key", config.Clients.Gemini.APIKey)
 	if err != nil {
-		logger.Warn().Msg("Gemini API key not configured - AI analysis will be unavailable")
+		logger.Warn().Err(err).Msg("Gemini API key not configured - AI analysis will be unavailable")
 	}
 
 	// Initialize API clients
@@ -171,9 +191,23 @@ func NewApp(configPath string) (*App, error) {
 		eodhdClient = eodhd.NewClient(eodhdKey,
 			eodhd.WithLogger(logger),
 			eodhd.WithRateLimit(config.Clients.EODHD.RateLimit),
+			eodhd.WithCircuitBreaker(config.Clients.EODHD.CircuitThreshold,
+				config.Clients.EODHD.GetCircuitWindow(), config.Clients.EODHD.GetCircuitCooldown()),
 		)
 	}
 
+	// Market data providers in failover order: EODHD, then Alpha Vantage
+	var marketProviders []interfaces.MarketDataProvider
+	if eodhdClient != nil {
+		marketProviders = append(marketProviders, eodhdClient)
+	}
+	if alphaVantageKey != "" {
+		marketProviders = append(marketProviders, alphavantage.NewClient(alphaVantageKey,
+			alphavantage.WithLogger(logger),
+			alphavantage.WithRateLimit(config.Clients.AlphaVantage.RateLimit),
+		))
+	}
+
 	var geminiClient *gemini.Client
 	if geminiKey != "" {
 		geminiClient, err = gemini.NewClient(ctx, geminiKey,
@@ -185,6 +219,15 @@ func NewApp(configPath string) (*App, error) {
 			logger.Warn().Err(err).Msg("Failed to initialize Gemini client")
 		}
 	}
+	var aiClient interfaces.GeminiClient
+	var geminiCache *gemini.CachedClient
+	if geminiClient != nil {
+		aiClient = geminiClient
+		if ttl := config.Clients.Gemini.GetCacheTTL(); ttl > 0 {
+			geminiCache = gemini.NewCachedClient(geminiClient, internalStore, ttl, logger)
+			aiClient = geminiCache
+		}
+	}
 
 	// Initialize ASX Markit Digital client (public API, no key required)
 	asxClient := asx.NewClient(asx.WithLogger(logger))
@@ -195,14 +238,42 @@ func NewApp(configPath string) (*App, error) {
 		quoteService = quote.NewService(eodhdClient, asxClient, storageManager, logger)
 	}
 
+	// Initialize FX service for converting holdings into a portfolio's base currency
+	var fxService interfaces.FXService
+	if eodhdClient != nil {
+		fxService = fx.NewService(eodhdClient, logger)
+	}
+
 	// Initialize services
 	signalService := signal.NewService(storageManager, eodhdClient, logger)
-	marketService := market.NewService(storageManager, eodhdClient, geminiClient, logger)
+	signalService.SetGeminiClient(aiClient)
+	signalService.SetNewsSentiment(config.Market.GetNewsSentiment())
+	macdFast, macdSlow, macdSignal := config.Market.GetMACDPeriods()
+	signalService.SetMACDPeriods(macdFast, macdSlow, macdSignal)
+	marketService := market.NewService(storageManager, eodhdClient, aiClient, logger, marketProviders...)
 	marketService.SetFilingSizeThreshold(config.JobManager.GetFilingSizeThreshold())
-	portfolioService := portfolio.NewService(storageManager, nil, eodhdClient, geminiClient, logger)
+	marketService.SetSnipeThresholds(config.Snipe.GetThresholds())
+	marketService.SetCoreCollectWorkers(config.JobManager.GetCoreCollectWorkers())
+	marketService.SetIndicatorSeriesMax(config.Market.GetIndicatorSeriesMax())
+	marketService.SetMACDPeriods(macdFast, macdSlow, macdSignal)
+	portfolioService := portfolio.NewService(storageManager, nil, eodhdClient, aiClient, logger)
+	portfolioService.SetFXService(fxService)
+	portfolioService.SetDefaultExchange(config.Portfolio.DefaultExchange)
+	portfolioService.SetNormalizeCents(config.Portfolio.GetNormalizeCents())
+	portfolioService.SetMinHoldDays(config.Portfolio.GetMinHoldDays())
+	portfolioService.SetMACDPeriods(macdFast, macdSlow, macdSignal)
+	portfolioService.SetTradeFetchWorkers(config.Portfolio.GetTradeFetchWorkers())
+	portfolioService.SetPriceFreshness(config.Portfolio.GetPriceFreshness())
+	portfolioService.SetFeeModel(config.Fees.GetFeeModel())
+	portfolioService.SetRebalanceDriftPct(config.Portfolio.GetRebalanceDriftPct())
+	portfolioService.SetContentFilteredNote(config.Clients.Gemini.GetContentFilteredNote())
+	portfolioService.SetStrictStrategyDecode(config.Portfolio.StrictStrategy)
+	portfolioService.SetMarginalTaxRate(config.Portfolio.MarginalTaxRate)
 	reportService := report.NewService(portfolioService, marketService, signalService, storageManager, logger)
 	strategyService := strategy.NewService(storageManager, logger)
+	strategyService.SetStrictDecode(config.Portfolio.StrictStrategy)
 	planService := plan.NewService(storageManager, strategyService, logger)
+	planService.SetMarketService(marketService)
 	watchlistService := watchlist.NewService(storageManager, logger)
 	holdingNoteService := holdingnotes.NewService(storageManager, logger)
 	cashflowService := cashflow.NewService(storageManager, portfolioService, logger)
@@ -214,6 +285,12 @@ func NewApp(configPath string) (*App, error) {
 	portfolioService.SetAssetSetService(assetSetService)
 	assetSetService.SetPortfolioService(portfolioService)
 
+	// Outbound webhooks for new alerts, plan triggers and watchlist target hits
+	notifyService := notify.NewService(config.Notifications.Webhooks, logger)
+	portfolioService.SetNotificationService(notifyService)
+	planService.SetNotificationService(notifyService)
+	watchlistService.SetNotificationService(notifyService)
+
 	// Wire cash flow change callback: invalidate + rebuild timeline on ledger changes
 	cashflowService.SetOnLedgerChange(func(cbCtx context.Context, portfolioName string) {
 		portfolioService.InvalidateAndRebuildTimeline(cbCtx, portfolioName)
@@ -230,6 +307,8 @@ func NewApp(configPath string) (*App, error) {
 			logger,
 			config.JobManager,
 		)
+		jobMgr.SetReportService(reportService)
+		jobMgr.SetPortfolioService(portfolioService)
 	}
 
 	a := &App{
@@ -238,7 +317,7 @@ func NewApp(configPath string) (*App, error) {
 		Storage:            storageManager,
 		EODHDClient:        eodhdClient,
 		ASXClient:          asxClient,
-		GeminiClient:       geminiClient,
+		GeminiClient:       aiClient,
 		QuoteService:       quoteService,
 		MarketService:      marketService,
 		PortfolioService:   portfolioService,
@@ -251,10 +330,18 @@ func NewApp(configPath string) (*App, error) {
 		CashFlowService:    cashflowService,
 		TradeService:       tradeService,
 		AssetSetService:    assetSetService,
+		NotifyService:      notifyService,
 		JobManager:         jobMgr,
 		StartupTime:        startupStart,
+		eodhd:              eodhdClient,
+		gemini:             geminiClient,
+		geminiCache:        geminiCache,
+		keyStore:           internalStore,
 	}
 
+	a.configWatcher = common.NewConfigWatcher(configPath, logger, a.ApplyConfig)
+	a.configWatcher.SetPrepare(func(c *common.Config) { resolveConfigPaths(c, binDir) })
+
 	logger.Info().Dur("startup", time.Since(startupStart)).Msg("App initialized")
 
 	return a, nil
@@ -265,17 +352,27 @@ func NewApp(configPath string) (*App, error) {
 // The caller must validate that the user context has a NavexaAPIKey before calling.
 func (a *App) InjectNavexaClient(ctx context.Context) context.Context {
 	if uc := common.UserContextFromContext(ctx); uc != nil && uc.NavexaAPIKey != "" {
-		client := navexa.NewClient(uc.NavexaAPIKey,
-			navexa.WithLogger(a.Logger),
-			navexa.WithRateLimit(a.Config.Clients.Navexa.RateLimit),
-		)
-		return common.WithNavexaClient(ctx, client)
+		return common.WithNavexaClient(ctx, a.NewNavexaClient(uc.NavexaAPIKey))
 	}
 	return ctx
 }
 
+// NewNavexaClient builds a Navexa client from the current (possibly
+// hot-reloaded) rate limit and retry settings.
+func (a *App) NewNavexaClient(apiKey string) *navexa.Client {
+	a.configMu.RLock()
+	cfg := a.Config.Clients.Navexa
+	a.configMu.RUnlock()
+	return navexa.NewClient(apiKey,
+		navexa.WithLogger(a.Logger),
+		navexa.WithRateLimit(cfg.RateLimit),
+		navexa.WithRetry(cfg.RetryMaxAttempts, cfg.GetRetryBaseDelay(), cfg.GetRetryJitter()),
+	)
+}
+
 // Close releases all resources held by the App.
-// Shutdown order: stop job manager, cancel scheduler, cancel warm cache, close storage.
+// Shutdown order: stop job manager, cancel scheduler, cancel warm cache,
+// stop the config watcher and cache purge, close storage.
 func (a *App) Close() {
 	if a.JobManager != nil {
 		a.JobManager.Stop()
@@ -293,6 +390,18 @@ func (a *App) Close() {
 		a.timelineCancel()
 		a.timelineCancel = nil
 	}
+	if a.configWatchCancel != nil {
+		a.configWatchCancel()
+		a.configWatchCancel = nil
+	}
+	if a.cachePurgeCancel != nil {
+		a.cachePurgeCancel()
+		a.cachePurgeCancel = nil
+	}
+	if a.NotifyService != nil {
+		a.NotifyService.Close()
+		a.NotifyService = nil
+	}
 	if a.Storage != nil {
 		a.Storage.Close()
 		a.Storage = nil
@@ -328,6 +437,32 @@ func (a *App) StartTimelineScheduler() {
 func (a *App) StartPriceScheduler() {
 	schedulerCtx, schedulerCancel := context.WithCancel(context.Background())
 	a.schedulerCancel = schedulerCancel
-	go startPriceScheduler(schedulerCtx, a.PortfolioService, a.MarketService, a.Storage, a.Logger, common.FreshnessTodayBar)
+	gate := newMarketHoursGate(a.Config.Market.GetExchangeHours())
+	go startPriceScheduler(schedulerCtx, a.PortfolioService, a.MarketService, a.WatchlistService, a.Storage, a.Logger, common.FreshnessTodayBar, gate)
 	go startLivePriceScheduler(schedulerCtx, a.MarketService, a.Storage, a.Logger)
 }
+
+// StartCachePurge launches the goroutine that deletes expired Gemini cache
+// entries. It is a no-op when the cache is disabled.
+func (a *App) StartCachePurge() {
+	if a.geminiCache == nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	a.cachePurgeCancel = cancel
+	go a.geminiCache.RunPurge(ctx)
+}
+
+// StartConfigWatcher reloads the config file whenever it changes on disk.
+func (a *App) StartConfigWatcher() {
+	if a.configWatcher == nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := a.configWatcher.Start(ctx); err != nil {
+		cancel()
+		a.Logger.Warn().Err(err).Msg("Config hot-reload disabled")
+		return
+	}
+	a.configWatchCancel = cancel
+}
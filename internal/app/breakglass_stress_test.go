@@ -251,7 +251,11 @@ func (s *raceSafeStore) GetSystemKV(_ context.Context, _ string) (string, error)
 	return "", fmt.Errorf("not found")
 }
 func (s *raceSafeStore) SetSystemKV(_ context.Context, _, _ string) error { return nil }
-func (s *raceSafeStore) Close() error                                     { return nil }
+func (s *raceSafeStore) DeleteSystemKV(_ context.Context, _ string) error { return nil }
+func (s *raceSafeStore) ListSystemKV(_ context.Context, _ string) (map[string]string, error) {
+	return nil, nil
+}
+func (s *raceSafeStore) Close() error { return nil }
 
 func TestBreakglass_RaceSafe_ConcurrentBootstrap(t *testing.T) {
 	store := newRaceSafeStore()
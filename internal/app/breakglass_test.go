@@ -3,6 +3,7 @@ package app
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/bobmcallan/vire/internal/common"
@@ -10,13 +11,15 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
-// mockInternalStore implements interfaces.InternalStore for breakglass unit tests.
+// mockInternalStore implements interfaces.InternalStore for breakglass and
+// API key unit tests.
 type mockInternalStore struct {
 	users map[string]*models.InternalUser
+	kv    map[string]string
 }
 
 func newMockInternalStore() *mockInternalStore {
-	return &mockInternalStore{users: make(map[string]*models.InternalUser)}
+	return &mockInternalStore{users: make(map[string]*models.InternalUser), kv: make(map[string]string)}
 }
 
 func (m *mockInternalStore) GetUser(_ context.Context, userID string) (*models.InternalUser, error) {
@@ -66,11 +69,33 @@ func (m *mockInternalStore) ListUserKV(_ context.Context, _ string) ([]*models.U
 	return nil, nil
 }
 
-func (m *mockInternalStore) GetSystemKV(_ context.Context, _ string) (string, error) {
-	return "", fmt.Errorf("not found")
+func (m *mockInternalStore) GetSystemKV(_ context.Context, key string) (string, error) {
+	v, ok := m.kv[key]
+	if !ok {
+		return "", fmt.Errorf("not found")
+	}
+	return v, nil
+}
+
+func (m *mockInternalStore) SetSystemKV(_ context.Context, key, value string) error {
+	m.kv[key] = value
+	return nil
 }
 
-func (m *mockInternalStore) SetSystemKV(_ context.Context, _, _ string) error { return nil }
+func (m *mockInternalStore) DeleteSystemKV(_ context.Context, key string) error {
+	delete(m.kv, key)
+	return nil
+}
+
+func (m *mockInternalStore) ListSystemKV(_ context.Context, prefix string) (map[string]string, error) {
+	entries := make(map[string]string)
+	for k, v := range m.kv {
+		if strings.HasPrefix(k, prefix) {
+			entries[k] = v
+		}
+	}
+	return entries, nil
+}
 
 func (m *mockInternalStore) Close() error { return nil }
 
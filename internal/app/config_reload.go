@@ -0,0 +1,73 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/common"
+)
+
+// resolveConfigPaths makes relative storage, blob and log paths relative to
+// the binary directory. Applied at startup and to every reloaded config, so
+// an unchanged path does not read as a restart-only change.
+func resolveConfigPaths(config *common.Config, binDir string) {
+	if config.Storage.DataPath != "" && !filepath.IsAbs(config.Storage.DataPath) {
+		config.Storage.DataPath = filepath.Join(binDir, config.Storage.DataPath)
+	}
+	if config.Storage.Blob.Path != "" && !filepath.IsAbs(config.Storage.Blob.Path) {
+		config.Storage.Blob.Path = filepath.Join(binDir, config.Storage.Blob.Path)
+	}
+	if config.Logging.FilePath != "" && !filepath.IsAbs(config.Logging.FilePath) {
+		config.Logging.FilePath = filepath.Join(binDir, config.Logging.FilePath)
+	}
+	if config.Security.MasterKeyFile != "" && !filepath.IsAbs(config.Security.MasterKeyFile) {
+		config.Security.MasterKeyFile = filepath.Join(binDir, config.Security.MasterKeyFile)
+	}
+	if f := config.Notifications.Webhooks.DeadLetterFile; f != "" && !filepath.IsAbs(f) {
+		config.Notifications.Webhooks.DeadLetterFile = filepath.Join(binDir, f)
+	}
+}
+
+// ReloadConfig re-reads the config file and applies its hot-reloadable
+// fields. An unreadable or invalid file returns an error and leaves the live
+// config untouched.
+func (a *App) ReloadConfig() (*common.ConfigReload, error) {
+	if a.configWatcher == nil {
+		return nil, fmt.Errorf("config reload unavailable: no config file")
+	}
+	return a.configWatcher.Reload()
+}
+
+// LoggingLevel returns the live (possibly hot-reloaded) logging level.
+func (a *App) LoggingLevel() string {
+	a.configMu.RLock()
+	defer a.configMu.RUnlock()
+	return a.Config.Logging.Level
+}
+
+// ApplyConfig copies the hot-reloadable fields of next into the live config
+// under configMu and pushes them into the running clients and services.
+// Fields that need a restart are reported but left as they are.
+func (a *App) ApplyConfig(next *common.Config) *common.ConfigReload {
+	a.configMu.Lock()
+	defer a.configMu.Unlock()
+
+	applied, restart := common.ApplyHotReload(a.Config, next)
+	if len(applied) > 0 {
+		live := a.Config
+		if a.Logger != nil {
+			a.Logger.SetLevel(live.Logging.Level)
+		}
+		if a.eodhd != nil {
+			a.eodhd.SetRateLimit(live.Clients.EODHD.RateLimit)
+		}
+		if ps, ok := a.PortfolioService.(interface{ SetPriceFreshness(time.Duration) }); ok {
+			ps.SetPriceFreshness(live.Portfolio.GetPriceFreshness())
+		}
+		if a.JobManager != nil {
+			a.JobManager.SetWatcherInterval(live.JobManager.GetWatcherInterval())
+		}
+	}
+	return &common.ConfigReload{Applied: applied, RestartRequired: restart}
+}
@@ -0,0 +1,139 @@
+package app
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/common"
+)
+
+const reloadTestRequired = `
+[clients.eodhd]
+api_key = "test-demo-key"
+
+[clients.gemini]
+api_key = "test-dummy-key"
+
+[auth]
+jwt_secret = "test-jwt-secret-for-unit-tests"
+
+[auth.google]
+client_id = "test-google-id"
+client_secret = "test-google-secret"
+
+[auth.github]
+client_id = "test-github-id"
+client_secret = "test-github-secret"
+`
+
+func writeReloadConfig(t *testing.T, path, body string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(reloadTestRequired+body), 0644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+}
+
+// newReloadTestApp builds an App around a config file without storage, so
+// reloads can be exercised without SurrealDB.
+func newReloadTestApp(t *testing.T, body string) (*App, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "vire.toml")
+	writeReloadConfig(t, path, body)
+	cfg, err := common.LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	a := &App{Config: cfg, Logger: common.NewSilentLogger()}
+	a.configWatcher = common.NewConfigWatcher(path, a.Logger, a.ApplyConfig)
+	return a, path
+}
+
+func TestReloadConfig_RateLimitAppliedToFreshClient(t *testing.T) {
+	a, path := newReloadTestApp(t, "\n[clients.navexa]\nrate_limit = 5\n")
+	if got := a.NewNavexaClient("key").RateLimit(); got != 5 {
+		t.Fatalf("initial client rate limit = %d, want 5", got)
+	}
+
+	writeReloadConfig(t, path, "\n[server]\nport = 9090\n\n[clients.navexa]\nrate_limit = 2\n")
+	result, err := a.ReloadConfig()
+	if err != nil {
+		t.Fatalf("ReloadConfig: %v", err)
+	}
+
+	if len(result.Applied) != 1 || result.Applied[0].Field != "clients.navexa.rate_limit" ||
+		result.Applied[0].Old != "5" || result.Applied[0].New != "2" {
+		t.Errorf("applied = %+v, want only clients.navexa.rate_limit 5 -> 2", result.Applied)
+	}
+	if len(result.RestartRequired) != 1 || result.RestartRequired[0].Field != "server.port" {
+		t.Errorf("restart_required = %+v, want server.port", result.RestartRequired)
+	}
+	if a.Config.Server.Port != 8080 {
+		t.Errorf("server port = %d, want 8080 kept until restart", a.Config.Server.Port)
+	}
+	if got := a.NewNavexaClient("key").RateLimit(); got != 2 {
+		t.Errorf("fresh client rate limit = %d, want 2", got)
+	}
+}
+
+func TestReloadConfig_InvalidFileKeepsLiveConfig(t *testing.T) {
+	a, path := newReloadTestApp(t, "\n[clients.navexa]\nrate_limit = 5\n\n[portfolio]\nprice_freshness = \"12h\"\n")
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"unparseable toml", "{{{{invalid toml", "failed to parse"},
+		{"zero rate limit", reloadTestRequired + "\n[clients.navexa]\nrate_limit = 0\n", "[clients.navexa] rate_limit"},
+		{"bad duration", reloadTestRequired + "\n[portfolio]\nprice_freshness = \"soon\"\n", "[portfolio] price_freshness"},
+		{"missing api key", "[clients.navexa]\nrate_limit = 1\n", "[clients.eodhd] api_key"},
+	}
+	t.Setenv("EODHD_API_KEY", "")
+	t.Setenv("VIRE_EODHD_API_KEY", "")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("write config: %v", err)
+			}
+			if _, err := a.ReloadConfig(); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("ReloadConfig error = %v, want one mentioning %q", err, tt.wantErr)
+			}
+			if a.Config.Clients.Navexa.RateLimit != 5 || a.Config.Portfolio.PriceFreshness != "12h" {
+				t.Errorf("live config clobbered: rate_limit %d, price_freshness %q",
+					a.Config.Clients.Navexa.RateLimit, a.Config.Portfolio.PriceFreshness)
+			}
+		})
+	}
+
+	// A deleted file must not reload as defaults
+	os.Remove(path)
+	if _, err := a.ReloadConfig(); err == nil {
+		t.Error("expected an error for a missing config file")
+	}
+	if a.Config.Clients.Navexa.RateLimit != 5 {
+		t.Errorf("rate limit = %d after missing file, want 5", a.Config.Clients.Navexa.RateLimit)
+	}
+}
+
+func TestConfigWatcher_ReloadsOnWrite(t *testing.T) {
+	a, path := newReloadTestApp(t, "\n[clients.navexa]\nrate_limit = 5\n")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := a.configWatcher.Start(ctx); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	writeReloadConfig(t, path, "\n[clients.navexa]\nrate_limit = 3\n")
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if a.NewNavexaClient("key").RateLimit() == 3 {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("rate limit not reloaded after file write: %d", a.NewNavexaClient("key").RateLimit())
+}
@@ -0,0 +1,200 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/interfaces"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+const appliedMigrationsKey = "vire_schema_migrations"
+
+// Migration transforms stored records in place from one schema version to
+// the next. Apply must be safe to re-run: a failed chain is retried from the
+// last completed step on the next startup.
+type Migration struct {
+	From        string
+	To          string
+	Description string
+	Apply       func(ctx context.Context, sm interfaces.StorageManager) error
+}
+
+// AppliedMigration is one entry of the migration history kept in the
+// system KV store.
+type AppliedMigration struct {
+	From        string    `json:"from"`
+	To          string    `json:"to"`
+	Description string    `json:"description"`
+	AppliedAt   time.Time `json:"applied_at"`
+}
+
+// schemaMigrations is the migration registry. When bumping
+// common.SchemaVersion, register a migration from the previous version so
+// stored data is converted instead of purged. Versions without a path to the
+// current one still fall back to purging derived data.
+var schemaMigrations = []Migration{
+	{
+		From:        "16",
+		To:          "17",
+		Description: "move files to collision-free record IDs",
+		Apply:       migrateFileRecordIDs,
+	},
+}
+
+// fileRecordIDMigrator is implemented by file stores that can move records
+// from the legacy record ID scheme (see surrealdb.FileStore.MigrateRecordIDs).
+type fileRecordIDMigrator interface {
+	MigrateRecordIDs(ctx context.Context) (int, error)
+}
+
+// migrateFileRecordIDs (16 -> 17) moves FileStore records to the "::"
+// record IDs and carries portfolio records forward to the new version,
+// since their contents are unchanged by this step.
+func migrateFileRecordIDs(ctx context.Context, sm interfaces.StorageManager) error {
+	if fs, ok := sm.FileStore().(fileRecordIDMigrator); ok {
+		if _, err := fs.MigrateRecordIDs(ctx); err != nil {
+			return fmt.Errorf("failed to migrate file record IDs: %w", err)
+		}
+	}
+	return rewriteUserRecords(ctx, sm, "portfolio", func(rec *models.UserRecord) (bool, error) {
+		return setDataVersion(rec, "16", "17")
+	})
+}
+
+// setDataVersion rewrites the record's data_version from one version to
+// another, leaving records at any other version alone.
+func setDataVersion(rec *models.UserRecord, from, to string) (bool, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(rec.Value), &fields); err != nil {
+		return false, fmt.Errorf("failed to decode record: %w", err)
+	}
+	var version string
+	if raw, ok := fields["data_version"]; ok {
+		if err := json.Unmarshal(raw, &version); err != nil {
+			return false, fmt.Errorf("failed to decode data_version: %w", err)
+		}
+	}
+	if version != from {
+		return false, nil
+	}
+	fields["data_version"], _ = json.Marshal(to)
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode record: %w", err)
+	}
+	rec.Value = string(data)
+	return true, nil
+}
+
+// migrationPath returns the ordered migrations leading from one version to
+// another, or false when the registry has no complete path.
+func migrationPath(registry []Migration, from, to string) ([]Migration, bool) {
+	var path []Migration
+	cur := from
+	for cur != to {
+		if len(path) > len(registry) {
+			return nil, false // cycle
+		}
+		next, ok := findMigration(registry, cur)
+		if !ok {
+			return nil, false
+		}
+		path = append(path, next)
+		cur = next.To
+	}
+	return path, true
+}
+
+func findMigration(registry []Migration, from string) (Migration, bool) {
+	for _, m := range registry {
+		if m.From == from {
+			return m, true
+		}
+	}
+	return Migration{}, false
+}
+
+// applyMigrations runs path in order, storing the schema version and
+// history after each step so a failure leaves the store at the last
+// version it fully reached. It returns the number of completed steps.
+func applyMigrations(ctx context.Context, sm interfaces.StorageManager, logger *common.Logger, path []Migration) (int, error) {
+	store := sm.InternalStore()
+	for i, m := range path {
+		start := time.Now()
+		if err := m.Apply(ctx, sm); err != nil {
+			return i, fmt.Errorf("migration %s -> %s (%s): %w", m.From, m.To, m.Description, err)
+		}
+		if err := store.SetSystemKV(ctx, schemaVersionKey, m.To); err != nil {
+			return i, fmt.Errorf("failed to store schema version %s: %w", m.To, err)
+		}
+		recordAppliedMigration(ctx, store, logger, AppliedMigration{
+			From: m.From, To: m.To, Description: m.Description, AppliedAt: time.Now(),
+		})
+		logger.Info().
+			Str("from", m.From).
+			Str("to", m.To).
+			Str("description", m.Description).
+			Dur("elapsed", time.Since(start)).
+			Msg("Schema migration applied")
+	}
+	return len(path), nil
+}
+
+func recordAppliedMigration(ctx context.Context, store interfaces.InternalStore, logger *common.Logger, applied AppliedMigration) {
+	history, _ := AppliedMigrations(ctx, store)
+	history = append(history, applied)
+	data, err := json.Marshal(history)
+	if err == nil {
+		err = store.SetSystemKV(ctx, appliedMigrationsKey, string(data))
+	}
+	if err != nil {
+		logger.Warn().Err(err).Str("to", applied.To).Msg("Failed to record applied schema migration")
+	}
+}
+
+// AppliedMigrations returns the migration history, oldest first.
+func AppliedMigrations(ctx context.Context, store interfaces.InternalStore) ([]AppliedMigration, error) {
+	raw, err := store.GetSystemKV(ctx, appliedMigrationsKey)
+	if err != nil || raw == "" {
+		return nil, nil
+	}
+	var history []AppliedMigration
+	if err := json.Unmarshal([]byte(raw), &history); err != nil {
+		return nil, fmt.Errorf("corrupt migration history: %w", err)
+	}
+	return history, nil
+}
+
+// rewriteUserRecords passes every stored record of subject, for every user,
+// to fn and saves the ones it reports as changed. It is the building block
+// for migrations of user domain data (portfolios, strategies, plans, ...).
+func rewriteUserRecords(ctx context.Context, sm interfaces.StorageManager, subject string, fn func(rec *models.UserRecord) (bool, error)) error {
+	userIDs, err := sm.InternalStore().ListUsers(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list users: %w", err)
+	}
+	store := sm.UserDataStore()
+	for _, userID := range userIDs {
+		records, err := store.List(ctx, userID, subject)
+		if err != nil {
+			return fmt.Errorf("failed to list %s records for %s: %w", subject, userID, err)
+		}
+		for _, rec := range records {
+			changed, err := fn(rec)
+			if err != nil {
+				return fmt.Errorf("%s %s/%s: %w", subject, userID, rec.Key, err)
+			}
+			if !changed {
+				continue
+			}
+			if err := store.Put(ctx, rec); err != nil {
+				return fmt.Errorf("failed to save %s %s/%s: %w", subject, userID, rec.Key, err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,294 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/interfaces"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// memUserDataStore is a minimal interfaces.UserDataStore keyed by user/subject/key.
+type memUserDataStore struct {
+	interfaces.UserDataStore
+	records map[string]*models.UserRecord
+}
+
+func (s *memUserDataStore) Put(_ context.Context, rec *models.UserRecord) error {
+	copied := *rec
+	s.records[rec.UserID+"/"+rec.Subject+"/"+rec.Key] = &copied
+	return nil
+}
+
+func (s *memUserDataStore) List(_ context.Context, userID, subject string) ([]*models.UserRecord, error) {
+	var out []*models.UserRecord
+	for _, rec := range s.records {
+		if rec.UserID == userID && rec.Subject == subject {
+			copied := *rec
+			out = append(out, &copied)
+		}
+	}
+	return out, nil
+}
+
+// migrationTestStorage counts purges; unused StorageManager methods panic.
+type migrationTestStorage struct {
+	interfaces.StorageManager
+	internal *mockInternalStore
+	userData *memUserDataStore
+	files    interfaces.FileStore
+	purges   int
+}
+
+func (m *migrationTestStorage) InternalStore() interfaces.InternalStore { return m.internal }
+func (m *migrationTestStorage) UserDataStore() interfaces.UserDataStore { return m.userData }
+func (m *migrationTestStorage) FileStore() interfaces.FileStore         { return m.files }
+func (m *migrationTestStorage) PurgeDerivedData(_ context.Context) (map[string]int, error) {
+	m.purges++
+	return map[string]int{}, nil
+}
+
+func newMigrationTestStorage(storedVersion string) *migrationTestStorage {
+	sm := &migrationTestStorage{
+		internal: newMockInternalStore(),
+		userData: &memUserDataStore{records: map[string]*models.UserRecord{}},
+	}
+	sm.internal.kv[schemaVersionKey] = storedVersion
+	return sm
+}
+
+// priorVersions returns the two schema versions before the current one.
+func priorVersions(t *testing.T) (string, string) {
+	t.Helper()
+	cur, err := strconv.Atoi(common.SchemaVersion)
+	if err != nil || cur < 3 {
+		t.Fatalf("unexpected SchemaVersion %q", common.SchemaVersion)
+	}
+	return strconv.Itoa(cur - 2), strconv.Itoa(cur - 1)
+}
+
+func TestMigrateSchema_SingleStep(t *testing.T) {
+	_, prev := priorVersions(t)
+	sm := newMigrationTestStorage(prev)
+	ctx := context.Background()
+	sm.internal.users["u1"] = &models.InternalUser{UserID: "u1"}
+	sm.userData.Put(ctx, &models.UserRecord{UserID: "u1", Subject: "strategy", Key: "SMSF", Value: `{"risk":"mod"}`})
+
+	registry := []Migration{{
+		From: prev, To: common.SchemaVersion, Description: "expand risk level",
+		Apply: func(ctx context.Context, sm interfaces.StorageManager) error {
+			return rewriteUserRecords(ctx, sm, "strategy", func(rec *models.UserRecord) (bool, error) {
+				rec.Value = strings.Replace(rec.Value, `"mod"`, `"moderate"`, 1)
+				return true, nil
+			})
+		},
+	}}
+
+	if !migrateSchema(ctx, sm, common.NewSilentLogger(), registry) {
+		t.Fatal("migrateSchema reported no change")
+	}
+	if sm.purges != 0 {
+		t.Errorf("purges = %d, want 0 when a migration path exists", sm.purges)
+	}
+	if got := sm.internal.kv[schemaVersionKey]; got != common.SchemaVersion {
+		t.Errorf("stored version = %q, want %q", got, common.SchemaVersion)
+	}
+	if got := sm.userData.records["u1/strategy/SMSF"].Value; got != `{"risk":"moderate"}` {
+		t.Errorf("migrated record = %s", got)
+	}
+	history, err := AppliedMigrations(ctx, sm.internal)
+	if err != nil || len(history) != 1 || history[0].From != prev || history[0].Description != "expand risk level" {
+		t.Errorf("history = %+v (err %v), want one %s -> %s entry", history, err, prev, common.SchemaVersion)
+	}
+}
+
+func TestMigrateSchema_TwoStepChain(t *testing.T) {
+	prev2, prev := priorVersions(t)
+	sm := newMigrationTestStorage(prev2)
+	ctx := context.Background()
+
+	var order []string
+	step := func(from, to string) Migration {
+		return Migration{From: from, To: to, Description: from + "->" + to,
+			Apply: func(ctx context.Context, sm interfaces.StorageManager) error {
+				// Each step sees the version left by the previous one
+				if v, _ := sm.InternalStore().GetSystemKV(ctx, schemaVersionKey); v != from {
+					return fmt.Errorf("step %s->%s ran at stored version %s", from, to, v)
+				}
+				order = append(order, from+"->"+to)
+				return nil
+			}}
+	}
+	// Registered out of order; the chain is resolved by version
+	registry := []Migration{step(prev, common.SchemaVersion), step(prev2, prev)}
+
+	if !migrateSchema(ctx, sm, common.NewSilentLogger(), registry) {
+		t.Fatal("migrateSchema reported no change")
+	}
+	want := []string{prev2 + "->" + prev, prev + "->" + common.SchemaVersion}
+	if strings.Join(order, ",") != strings.Join(want, ",") {
+		t.Errorf("applied order = %v, want %v", order, want)
+	}
+	if sm.purges != 0 {
+		t.Errorf("purges = %d, want 0", sm.purges)
+	}
+	if history, _ := AppliedMigrations(ctx, sm.internal); len(history) != 2 {
+		t.Errorf("history has %d entries, want 2", len(history))
+	}
+}
+
+func TestMigrateSchema_NoOpWhenCurrent(t *testing.T) {
+	_, prev := priorVersions(t)
+	sm := newMigrationTestStorage(common.SchemaVersion)
+	registry := []Migration{{From: prev, To: common.SchemaVersion, Apply: func(context.Context, interfaces.StorageManager) error {
+		t.Error("migration ran although the schema is current")
+		return nil
+	}}}
+
+	if migrateSchema(context.Background(), sm, common.NewSilentLogger(), registry) {
+		t.Error("migrateSchema reported a change for a current schema")
+	}
+	if sm.purges != 0 {
+		t.Errorf("purges = %d, want 0", sm.purges)
+	}
+	if _, ok := sm.internal.kv[appliedMigrationsKey]; ok {
+		t.Error("migration history written for a no-op")
+	}
+}
+
+func TestMigrateSchema_PurgeIsLastResort(t *testing.T) {
+	prev2, prev := priorVersions(t)
+	ctx := context.Background()
+
+	t.Run("no path", func(t *testing.T) {
+		sm := newMigrationTestStorage("1")
+		registry := []Migration{{From: prev, To: common.SchemaVersion, Apply: func(context.Context, interfaces.StorageManager) error { return nil }}}
+		if !migrateSchema(ctx, sm, common.NewSilentLogger(), registry) || sm.purges != 1 {
+			t.Errorf("purges = %d, want 1 without a migration path", sm.purges)
+		}
+		if got := sm.internal.kv[schemaVersionKey]; got != common.SchemaVersion {
+			t.Errorf("stored version = %q, want %q", got, common.SchemaVersion)
+		}
+	})
+
+	t.Run("failed step", func(t *testing.T) {
+		sm := newMigrationTestStorage(prev2)
+		registry := []Migration{
+			{From: prev2, To: prev, Apply: func(context.Context, interfaces.StorageManager) error { return nil }},
+			{From: prev, To: common.SchemaVersion, Apply: func(context.Context, interfaces.StorageManager) error { return errors.New("boom") }},
+		}
+		migrateSchema(ctx, sm, common.NewSilentLogger(), registry)
+		if sm.purges != 0 {
+			t.Errorf("purges = %d, want 0 after a failed migration", sm.purges)
+		}
+		if got := sm.internal.kv[schemaVersionKey]; got != prev {
+			t.Errorf("stored version = %q, want last completed %q", got, prev)
+		}
+	})
+}
+
+func TestMigrateSchema_FailedMiddleStepRetriesFromLastCompleted(t *testing.T) {
+	prev2, prev := priorVersions(t)
+	cur, _ := strconv.Atoi(common.SchemaVersion)
+	prev3 := strconv.Itoa(cur - 3)
+	sm := newMigrationTestStorage(prev3)
+	ctx := context.Background()
+
+	var runs []string
+	step := func(from, to string, err error) Migration {
+		return Migration{From: from, To: to, Description: from + "->" + to,
+			Apply: func(context.Context, interfaces.StorageManager) error {
+				runs = append(runs, from+"->"+to)
+				return err
+			}}
+	}
+	registry := []Migration{
+		step(prev3, prev2, nil),
+		step(prev2, prev, errors.New("boom")),
+		step(prev, common.SchemaVersion, nil),
+	}
+
+	if !migrateSchema(ctx, sm, common.NewSilentLogger(), registry) {
+		t.Error("migrateSchema reported no change after a completed first step")
+	}
+	if got := sm.internal.kv[schemaVersionKey]; got != prev2 {
+		t.Fatalf("stored version = %q, want %q (last completed step)", got, prev2)
+	}
+	if sm.purges != 0 {
+		t.Errorf("purges = %d, want 0 after a failed migration", sm.purges)
+	}
+
+	// Next startup: the middle step succeeds and the chain resumes from it
+	runs = nil
+	registry[1] = step(prev2, prev, nil)
+	if !migrateSchema(ctx, sm, common.NewSilentLogger(), registry) {
+		t.Fatal("retry reported no change")
+	}
+	want := []string{prev2 + "->" + prev, prev + "->" + common.SchemaVersion}
+	if strings.Join(runs, ",") != strings.Join(want, ",") {
+		t.Errorf("retry ran %v, want %v", runs, want)
+	}
+	if got := sm.internal.kv[schemaVersionKey]; got != common.SchemaVersion {
+		t.Errorf("stored version = %q, want %q", got, common.SchemaVersion)
+	}
+	if history, _ := AppliedMigrations(ctx, sm.internal); len(history) != 3 {
+		t.Errorf("history has %d entries, want 3", len(history))
+	}
+}
+
+func TestMigrationPath_Cycle(t *testing.T) {
+	registry := []Migration{{From: "1", To: "2"}, {From: "2", To: "1"}}
+	if _, ok := migrationPath(registry, "1", "3"); ok {
+		t.Error("cyclic registry produced a path")
+	}
+}
+
+// recordIDFileStore counts MigrateRecordIDs calls; other methods panic.
+type recordIDFileStore struct {
+	interfaces.FileStore
+	calls int
+}
+
+func (f *recordIDFileStore) MigrateRecordIDs(context.Context) (int, error) {
+	f.calls++
+	return 2, nil
+}
+
+func TestSchemaMigrations_FileRecordIDs(t *testing.T) {
+	sm := newMigrationTestStorage("16")
+	files := &recordIDFileStore{}
+	sm.files = files
+	ctx := context.Background()
+	sm.internal.users["u1"] = &models.InternalUser{UserID: "u1"}
+	sm.userData.Put(ctx, &models.UserRecord{UserID: "u1", Subject: "portfolio", Key: "SMSF", Value: `{"name":"SMSF","data_version":"16"}`})
+	sm.userData.Put(ctx, &models.UserRecord{UserID: "u1", Subject: "portfolio", Key: "Old", Value: `{"name":"Old","data_version":"12"}`})
+
+	if !migrateSchema(ctx, sm, common.NewSilentLogger(), schemaMigrations) {
+		t.Fatal("migrateSchema reported no change")
+	}
+	if files.calls != 1 {
+		t.Errorf("MigrateRecordIDs called %d times, want 1", files.calls)
+	}
+	if sm.purges != 0 {
+		t.Errorf("purges = %d, want 0 on the 16 -> %s upgrade", sm.purges, common.SchemaVersion)
+	}
+	if got := sm.internal.kv[schemaVersionKey]; got != common.SchemaVersion {
+		t.Errorf("stored version = %q, want %q", got, common.SchemaVersion)
+	}
+	if got := sm.userData.records["u1/portfolio/SMSF"].Value; !strings.Contains(got, `"data_version":"17"`) || !strings.Contains(got, `"name":"SMSF"`) {
+		t.Errorf("portfolio at 16 = %s, want data_version 17 and other fields kept", got)
+	}
+	if got := sm.userData.records["u1/portfolio/Old"].Value; !strings.Contains(got, `"data_version":"12"`) {
+		t.Errorf("portfolio at 12 = %s, want it left stale for re-sync", got)
+	}
+
+	// Re-running the step is harmless
+	if err := migrateFileRecordIDs(ctx, sm); err != nil {
+		t.Fatalf("re-run: %v", err)
+	}
+}
@@ -11,9 +11,16 @@ const schemaVersionKey = "vire_schema_version"
 const buildTimestampKey = "vire_build_timestamp"
 
 // checkSchemaVersion compares the stored schema version against the code's
-// SchemaVersion constant. On mismatch (or missing version), it purges all
-// derived data and stores the new version. Returns true if a rebuild occurred.
+// SchemaVersion constant. On mismatch it runs the registered migrations from
+// the stored version; only when no migration path exists does it fall back to
+// purging all derived data. A failed migration leaves the store at the last
+// version it reached, to be retried on the next startup. Returns true if
+// stored data was migrated or purged.
 func checkSchemaVersion(ctx context.Context, sm interfaces.StorageManager, logger *common.Logger) bool {
+	return migrateSchema(ctx, sm, logger, schemaMigrations)
+}
+
+func migrateSchema(ctx context.Context, sm interfaces.StorageManager, logger *common.Logger, registry []Migration) bool {
 	store := sm.InternalStore()
 
 	stored, err := store.GetSystemKV(ctx, schemaVersionKey)
@@ -28,11 +35,28 @@ func checkSchemaVersion(ctx context.Context, sm interfaces.StorageManager, logge
 		logger.Info().
 			Str("current", common.SchemaVersion).
 			Msg("Schema version not found — initializing (first run or pre-versioning)")
+	} else if path, ok := migrationPath(registry, stored, common.SchemaVersion); ok {
+		logger.Info().
+			Str("stored", stored).
+			Str("current", common.SchemaVersion).
+			Int("steps", len(path)).
+			Msg("Schema version behind — running migrations")
+		applied, err := applyMigrations(ctx, sm, logger, path)
+		if err != nil {
+			reached := stored
+			if applied > 0 {
+				reached = path[applied-1].To
+			}
+			logger.Error().Err(err).
+				Str("version", reached).
+				Msg("Schema migration failed — will retry from the last completed step on next startup")
+		}
+		return applied > 0
 	} else {
 		logger.Warn().
 			Str("stored", stored).
 			Str("current", common.SchemaVersion).
-			Msg("Schema version mismatch — purging derived data")
+			Msg("Schema version mismatch with no migration path — purging derived data")
 	}
 
 	counts, purgeErr := sm.PurgeDerivedData(ctx)
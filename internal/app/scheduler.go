@@ -2,15 +2,45 @@ package app
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/bobmcallan/vire/internal/common"
 	"github.com/bobmcallan/vire/internal/interfaces"
+	"github.com/bobmcallan/vire/internal/models"
 )
 
-// startPriceScheduler refreshes EOD prices on a fixed interval.
+// marketHoursGate decides which exchanges are worth a price refresh: every tick
+// while the market is open, then once after each close to pick up the final
+// EOD bar. Exchanges without configured hours are always refreshed.
+type marketHoursGate struct {
+	hours     map[string]common.ExchangeHours
+	lastClose map[string]time.Time // session close already covered by a post-close refresh
+}
+
+func newMarketHoursGate(hours map[string]common.ExchangeHours) *marketHoursGate {
+	return &marketHoursGate{hours: hours, lastClose: make(map[string]time.Time)}
+}
+
+// due reports whether exchange should be refreshed at now
+func (g *marketHoursGate) due(exchange string, now time.Time) bool {
+	h, ok := g.hours[exchange]
+	if !ok || h.IsOpen(now) {
+		return true
+	}
+	return !g.lastClose[exchange].Equal(h.LastClose(now))
+}
+
+// refreshed records a successful refresh so the post-close refresh fires once
+func (g *marketHoursGate) refreshed(exchange string, now time.Time) {
+	if h, ok := g.hours[exchange]; ok && !h.IsOpen(now) {
+		g.lastClose[exchange] = h.LastClose(now)
+	}
+}
+
+// startPriceScheduler refreshes EOD prices on a fixed interval, skipping exchanges whose market is closed.
 // It reads the portfolio from storage (no Navexa re-sync) and updates market data for active tickers.
-func startPriceScheduler(ctx context.Context, portfolioService interfaces.PortfolioService, marketService interfaces.MarketService, storage interfaces.StorageManager, logger *common.Logger, interval time.Duration) {
+func startPriceScheduler(ctx context.Context, portfolioService interfaces.PortfolioService, marketService interfaces.MarketService, watchlistService interfaces.WatchlistService, storage interfaces.StorageManager, logger *common.Logger, interval time.Duration, gate *marketHoursGate) {
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
@@ -20,7 +50,7 @@ func startPriceScheduler(ctx context.Context, portfolioService interfaces.Portfo
 			logger.Info().Msg("Price scheduler: stopped")
 			return
 		case <-ticker.C:
-			refreshPrices(ctx, portfolioService, marketService, storage, logger)
+			refreshPrices(ctx, portfolioService, marketService, watchlistService, storage, logger, gate, time.Now())
 		}
 	}
 }
@@ -69,7 +99,7 @@ func refreshLivePrices(ctx context.Context, marketService interfaces.MarketServi
 		Msg("Live price refresh: complete")
 }
 
-func refreshPrices(ctx context.Context, portfolioService interfaces.PortfolioService, marketService interfaces.MarketService, storage interfaces.StorageManager, logger *common.Logger) {
+func refreshPrices(ctx context.Context, portfolioService interfaces.PortfolioService, marketService interfaces.MarketService, watchlistService interfaces.WatchlistService, storage interfaces.StorageManager, logger *common.Logger, gate *marketHoursGate, now time.Time) {
 	start := time.Now()
 
 	portfolioName := resolvePortfolioWithFallback(ctx, portfolioService, storage, logger)
@@ -84,14 +114,56 @@ func refreshPrices(ctx context.Context, portfolioService interfaces.PortfolioSer
 		return
 	}
 
+	// Group by exchange so closed markets can be skipped
+	due := make(map[string]bool)
 	tickers := make([]string, 0, len(portfolio.Holdings))
 	for _, h := range portfolio.Holdings {
-		if len(h.Trades) > 0 {
+		if len(h.Trades) == 0 {
+			continue
+		}
+		exchange := models.EodhExchange(h.Exchange)
+		isDue, seen := due[exchange]
+		if !seen {
+			isDue = gate == nil || gate.due(exchange, now)
+			due[exchange] = isDue
+		}
+		if isDue {
 			tickers = append(tickers, h.EODHDTicker())
 		}
 	}
 
+	// Targeted watchlist tickers are refreshed with the holdings so target checks see current prices
+	var watchlist *models.PortfolioWatchlist
+	if watchlistService != nil {
+		watchlist, _ = watchlistService.GetWatchlist(ctx, portfolioName)
+	}
+	if watchlist != nil {
+		queued := make(map[string]bool, len(tickers))
+		for _, t := range tickers {
+			queued[t] = true
+		}
+		for _, item := range watchlist.Items {
+			if item.TargetPrice <= 0 || queued[item.Ticker] {
+				continue
+			}
+			exchange := "AU"
+			if i := strings.LastIndex(item.Ticker, "."); i >= 0 {
+				exchange = models.EodhExchange(item.Ticker[i+1:])
+			}
+			isDue, seen := due[exchange]
+			if !seen {
+				isDue = gate == nil || gate.due(exchange, now)
+				due[exchange] = isDue
+			}
+			if isDue {
+				tickers = append(tickers, item.Ticker)
+				queued[item.Ticker] = true
+			}
+		}
+	}
+
 	if len(tickers) == 0 {
+		logger.Debug().Str("portfolio", portfolioName).Msg("Price refresh: all markets closed, skipping")
 		return
 	}
 
@@ -100,6 +172,22 @@ func refreshPrices(ctx context.Context, portfolioService interfaces.PortfolioSer
 		return
 	}
 
+	if gate != nil {
+		for exchange, isDue := range due {
+			if isDue {
+				gate.refreshed(exchange, now)
+			}
+		}
+	}
+
+	if watchlist != nil {
+		if events, err := watchlistService.CheckTargets(ctx, portfolioName); err != nil {
+			logger.Warn().Err(err).Str("portfolio", portfolioName).Msg("Price refresh: watchlist target check failed")
+		} else if len(events) > 0 {
+			logger.Info().Str("portfolio", portfolioName).Int("hits", len(events)).Msg("Price refresh: watchlist targets hit")
+		}
+	}
+
 	logger.Info().
 		Str("portfolio", portfolioName).
 		Int("tickers", len(tickers)).
@@ -0,0 +1,148 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/common"
+)
+
+func testGate(t *testing.T) (*marketHoursGate, *time.Location) {
+	t.Helper()
+	cfg := common.MarketConfig{}
+	hours := cfg.GetExchangeHours()
+	au, ok := hours["AU"]
+	if !ok {
+		t.Fatal("expected default AU hours")
+	}
+	return newMarketHoursGate(hours), au.Location
+}
+
+// tick simulates one scheduler tick at now, marking due exchanges refreshed
+func tick(g *marketHoursGate, exchange string, now time.Time) bool {
+	if !g.due(exchange, now) {
+		return false
+	}
+	g.refreshed(exchange, now)
+	return true
+}
+
+func TestMarketHoursGate_RefreshesWhileOpen(t *testing.T) {
+	g, syd := testGate(t)
+
+	// Wednesday 11:00, 12:00, 15:00 Sydney — every tick refreshes
+	for _, hour := range []int{11, 12, 15} {
+		now := time.Date(2026, 3, 4, hour, 0, 0, 0, syd)
+		if !tick(g, "AU", now) {
+			t.Errorf("expected refresh at %s while AU is open", now.Format("15:04"))
+		}
+	}
+}
+
+func TestMarketHoursGate_FiresOnceAfterCloseThenSkips(t *testing.T) {
+	g, syd := testGate(t)
+
+	tick(g, "AU", time.Date(2026, 3, 4, 15, 0, 0, 0, syd))
+
+	if !tick(g, "AU", time.Date(2026, 3, 4, 16, 5, 0, 0, syd)) {
+		t.Fatal("expected one post-close refresh just after 16:00")
+	}
+	for _, at := range []time.Time{
+		time.Date(2026, 3, 4, 17, 5, 0, 0, syd),
+		time.Date(2026, 3, 4, 23, 5, 0, 0, syd),
+		time.Date(2026, 3, 5, 9, 5, 0, 0, syd),
+	} {
+		if tick(g, "AU", at) {
+			t.Errorf("expected skip at %s while AU is closed", at.Format("Mon 15:04"))
+		}
+	}
+
+	// Next session opens and closes again
+	if !tick(g, "AU", time.Date(2026, 3, 5, 10, 5, 0, 0, syd)) {
+		t.Error("expected refresh once AU reopens")
+	}
+	if !tick(g, "AU", time.Date(2026, 3, 5, 16, 5, 0, 0, syd)) {
+		t.Error("expected a post-close refresh for the next session")
+	}
+}
+
+func TestMarketHoursGate_WeekendSkipped(t *testing.T) {
+	g, syd := testGate(t)
+
+	// Friday post-close refresh, then nothing until Monday open
+	if !tick(g, "AU", time.Date(2026, 3, 6, 16, 5, 0, 0, syd)) {
+		t.Fatal("expected Friday post-close refresh")
+	}
+	for _, at := range []time.Time{
+		time.Date(2026, 3, 7, 12, 0, 0, 0, syd), // Saturday midday
+		time.Date(2026, 3, 8, 12, 0, 0, 0, syd), // Sunday midday
+		time.Date(2026, 3, 9, 9, 0, 0, 0, syd),  // Monday pre-open
+	} {
+		if tick(g, "AU", at) {
+			t.Errorf("expected skip at %s", at.Format("Mon 15:04"))
+		}
+	}
+}
+
+func TestMarketHoursGate_FailedRefreshRetriesPostClose(t *testing.T) {
+	g, syd := testGate(t)
+	now := time.Date(2026, 3, 4, 16, 5, 0, 0, syd)
+
+	// due but not marked refreshed (collection failed) — still due next tick
+	if !g.due("AU", now) {
+		t.Fatal("expected post-close refresh to be due")
+	}
+	if !g.due("AU", now.Add(time.Hour)) {
+		t.Error("expected post-close refresh to remain due after a failed attempt")
+	}
+}
+
+func TestMarketHoursGate_UnknownExchangeAlwaysRefreshes(t *testing.T) {
+	g, _ := testGate(t)
+	sunday := time.Date(2026, 3, 8, 3, 0, 0, 0, time.UTC)
+	if !tick(g, "XETRA", sunday) || !tick(g, "XETRA", sunday.Add(time.Hour)) {
+		t.Error("expected exchanges without configured hours to always refresh")
+	}
+}
+
+func TestMarketConfig_HoursOverride(t *testing.T) {
+	cfg := common.MarketConfig{Hours: map[string]common.ExchangeHoursConfig{
+		"au":  {Open: "07:00", Close: "19:00", Timezone: "Australia/Sydney"},
+		"US":  {Open: "bogus", Close: "16:00", Timezone: "America/New_York"},
+		"NEW": {Open: "09:00", Close: "17:00", Timezone: "Not/AZone"},
+	}}
+	hours := cfg.GetExchangeHours()
+
+	if got := hours["AU"].Close; got != 19*time.Hour {
+		t.Errorf("AU close = %v, want 19h override", got)
+	}
+	if got := hours["US"].Open; got != 9*time.Hour+30*time.Minute {
+		t.Errorf("US open = %v, want default 9h30m after invalid override", got)
+	}
+	if _, ok := hours["NEW"]; ok {
+		t.Error("expected invalid exchange without a default to be dropped")
+	}
+}
+
+func TestMarketConfig_HoursDropsUnparseableDefault(t *testing.T) {
+	orig := common.DefaultExchangeHours["AU"]
+	common.DefaultExchangeHours["AU"] = common.ExchangeHoursConfig{Open: "10:00", Close: "16:00", Timezone: "Not/AZone"}
+	defer func() { common.DefaultExchangeHours["AU"] = orig }()
+
+	cfg := common.MarketConfig{}
+	hours := cfg.GetExchangeHours()
+	if _, ok := hours["AU"]; ok {
+		t.Error("expected exchange whose default timezone fails to load to be dropped")
+	}
+	for ex, h := range hours {
+		if h.Location == nil {
+			t.Errorf("%s: nil Location", ex)
+		}
+	}
+
+	// An invalid override falling back to the broken default is dropped too
+	cfg.Hours = map[string]common.ExchangeHoursConfig{"AU": {Open: "bogus", Timezone: "Australia/Sydney"}}
+	if _, ok := cfg.GetExchangeHours()["AU"]; ok {
+		t.Error("expected AU to be dropped when both override and default are invalid")
+	}
+}
@@ -0,0 +1,395 @@
+// Package alphavantage provides a client for the Alpha Vantage API
+package alphavantage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/interfaces"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+const (
+	DefaultBaseURL   = "https://www.alphavantage.co"
+	DefaultTimeout   = 30 * time.Second
+	DefaultRateLimit = 5 // requests per minute (the free tier limit)
+)
+
+// exchangeSuffixes maps EODHD exchange codes to Alpha Vantage symbol suffixes.
+// US tickers carry no suffix; unmapped exchanges pass through unchanged.
+var exchangeSuffixes = map[string]string{
+	"US":    "",
+	"AU":    ".AX",
+	"LSE":   ".LON",
+	"TO":    ".TRT",
+	"V":     ".TRV",
+	"XETRA": ".DEX",
+}
+
+// Symbol translates an EODHD-format ticker (e.g. "BHP.AU") to Alpha Vantage
+// symbology (e.g. "BHP.AX").
+func Symbol(ticker string) string {
+	idx := strings.LastIndex(ticker, ".")
+	if idx <= 0 {
+		return ticker
+	}
+	suffix, ok := exchangeSuffixes[strings.ToUpper(ticker[idx+1:])]
+	if !ok {
+		return ticker
+	}
+	return ticker[:idx] + suffix
+}
+
+// Client implements MarketDataProvider using the Alpha Vantage API
+type Client struct {
+	apiKey     string
+	baseURL    string
+	httpClient *http.Client
+	logger     *common.Logger
+	limiter    *rate.Limiter
+}
+
+// ClientOption configures the client
+type ClientOption func(*Client)
+
+// WithBaseURL sets the base URL
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *Client) {
+		c.baseURL = baseURL
+	}
+}
+
+// WithLogger sets the logger
+func WithLogger(logger *common.Logger) ClientOption {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// WithRateLimit sets the rate limit in requests per minute. Alpha Vantage
+// quotas are per minute, unlike the per-second limits of the other clients.
+func WithRateLimit(requestsPerMinute int) ClientOption {
+	return func(c *Client) {
+		if requestsPerMinute > 0 {
+			c.limiter = newLimiter(requestsPerMinute)
+		}
+	}
+}
+
+// newLimiter spreads requestsPerMinute evenly, allowing a burst of that many.
+func newLimiter(requestsPerMinute int) *rate.Limiter {
+	return rate.NewLimiter(rate.Every(time.Minute/time.Duration(requestsPerMinute)), requestsPerMinute)
+}
+
+// WithTimeout sets the HTTP timeout
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) {
+		c.httpClient.Timeout = timeout
+	}
+}
+
+// NewClient creates a new Alpha Vantage API client
+func NewClient(apiKey string, opts ...ClientOption) *Client {
+	c := &Client{
+		apiKey:  apiKey,
+		baseURL: DefaultBaseURL,
+		httpClient: &http.Client{
+			Timeout: DefaultTimeout,
+		},
+		limiter: newLimiter(DefaultRateLimit),
+		logger:  common.NewSilentLogger(),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Name identifies the client as a market data provider
+func (c *Client) Name() string {
+	return "alphavantage"
+}
+
+// APIError represents an Alpha Vantage API error. Alpha Vantage reports
+// throttling and quota exhaustion with HTTP 200 and a "Note" or "Information"
+// body, so those are surfaced as rate-limit errors.
+type APIError struct {
+	StatusCode  int
+	Message     string
+	Function    string
+	rateLimited bool
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("Alpha Vantage API error: %s (status: %d, function: %s)", e.Message, e.StatusCode, e.Function)
+}
+
+// RateLimited reports whether the request was rejected for exceeding the
+// rate limit or daily quota.
+func (e *APIError) RateLimited() bool {
+	return e.rateLimited
+}
+
+// apiStatus holds the message fields Alpha Vantage returns in place of data
+type apiStatus struct {
+	Note         string `json:"Note"`
+	Information  string `json:"Information"`
+	ErrorMessage string `json:"Error Message"`
+}
+
+// get performs a rate-limited GET request for an Alpha Vantage function
+func (c *Client) get(ctx context.Context, function string, params url.Values, result interface{}) error {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return fmt.Errorf("rate limit wait: %w", err)
+	}
+
+	if params == nil {
+		params = url.Values{}
+	}
+	params.Set("function", function)
+	params.Set("apikey", c.apiKey)
+
+	reqURL := fmt.Sprintf("%s/query?%s", c.baseURL, params.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Log function and symbol only (never the full URL which contains the API key)
+	c.logger.Debug().Str("function", function).Str("symbol", params.Get("symbol")).Msg("Alpha Vantage API request")
+
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+	elapsed := time.Since(start)
+	if err != nil {
+		c.logger.Error().Err(err).Str("function", function).Dur("elapsed", elapsed).Msg("Alpha Vantage API request failed")
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		c.logger.Warn().Str("function", function).Int("status", resp.StatusCode).Dur("elapsed", elapsed).Msg("Alpha Vantage API non-OK response")
+		return &APIError{
+			StatusCode:  resp.StatusCode,
+			Message:     string(body),
+			Function:    function,
+			rateLimited: resp.StatusCode == http.StatusTooManyRequests,
+		}
+	}
+
+	var status apiStatus
+	if err := json.Unmarshal(body, &status); err == nil {
+		switch {
+		case status.ErrorMessage != "":
+			return &APIError{StatusCode: resp.StatusCode, Message: status.ErrorMessage, Function: function}
+		case status.Note != "" || status.Information != "":
+			msg := status.Note
+			if msg == "" {
+				msg = status.Information
+			}
+			c.logger.Warn().Str("function", function).Dur("elapsed", elapsed).Msg("Alpha Vantage API rate limited")
+			return &APIError{StatusCode: resp.StatusCode, Message: msg, Function: function, rateLimited: true}
+		}
+	}
+
+	if err := json.Unmarshal(body, result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	c.logger.Info().Str("function", function).Int("status", resp.StatusCode).Dur("elapsed", elapsed).Msg("Alpha Vantage API call")
+	return nil
+}
+
+// parseFloat parses an Alpha Vantage numeric string, treating "None", "-" and
+// other non-numeric values as zero.
+func parseFloat(s string) float64 {
+	f, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(s), "%"), 64)
+	if err != nil {
+		return 0
+	}
+	return f
+}
+
+// dailyResponse represents the TIME_SERIES_DAILY response
+type dailyResponse struct {
+	Series map[string]struct {
+		Open   string `json:"1. open"`
+		High   string `json:"2. high"`
+		Low    string `json:"3. low"`
+		Close  string `json:"4. close"`
+		Volume string `json:"5. volume"`
+	} `json:"Time Series (Daily)"`
+}
+
+// GetEOD retrieves daily bars via TIME_SERIES_DAILY, most recent first. Only
+// daily bars are available; the date range and limit are applied client-side.
+// Alpha Vantage's free tier has no adjusted close, so AdjClose is left unset
+// and consumers fall back to Close.
+func (c *Client) GetEOD(ctx context.Context, ticker string, opts ...interfaces.EODOption) (*models.EODResponse, error) {
+	params := &interfaces.EODParams{}
+	for _, opt := range opts {
+		opt(params)
+	}
+
+	// compact returns the latest 100 bars; anything older needs the full series
+	outputSize := "compact"
+	if params.From.IsZero() || time.Since(params.From) > 100*24*time.Hour {
+		outputSize = "full"
+	}
+
+	urlParams := url.Values{}
+	urlParams.Set("symbol", Symbol(ticker))
+	urlParams.Set("outputsize", outputSize)
+
+	var resp dailyResponse
+	if err := c.get(ctx, "TIME_SERIES_DAILY", urlParams, &resp); err != nil {
+		return nil, err
+	}
+
+	bars := make([]models.EODBar, 0, len(resp.Series))
+	for day, b := range resp.Series {
+		date, err := time.Parse("2006-01-02", day)
+		if err != nil {
+			continue
+		}
+		if !params.From.IsZero() && date.Before(params.From.Truncate(24*time.Hour)) {
+			continue
+		}
+		if !params.To.IsZero() && date.After(params.To) {
+			continue
+		}
+		bars = append(bars, models.EODBar{
+			Date:   date,
+			Open:   parseFloat(b.Open),
+			High:   parseFloat(b.High),
+			Low:    parseFloat(b.Low),
+			Close:  parseFloat(b.Close),
+			Volume: int64(parseFloat(b.Volume)),
+		})
+	}
+	sort.Slice(bars, func(i, j int) bool { return bars[i].Date.After(bars[j].Date) })
+	if params.Limit > 0 && len(bars) > params.Limit {
+		bars = bars[:params.Limit]
+	}
+
+	return &models.EODResponse{Data: bars}, nil
+}
+
+// globalQuoteResponse represents the GLOBAL_QUOTE response
+type globalQuoteResponse struct {
+	Quote struct {
+		Symbol        string `json:"01. symbol"`
+		Open          string `json:"02. open"`
+		High          string `json:"03. high"`
+		Low           string `json:"04. low"`
+		Price         string `json:"05. price"`
+		Volume        string `json:"06. volume"`
+		LatestDay     string `json:"07. latest trading day"`
+		PreviousClose string `json:"08. previous close"`
+		Change        string `json:"09. change"`
+		ChangePercent string `json:"10. change percent"`
+	} `json:"Global Quote"`
+}
+
+// GetRealTimeQuote retrieves the latest quote via GLOBAL_QUOTE. The returned
+// Code keeps the EODHD-format ticker that was requested.
+func (c *Client) GetRealTimeQuote(ctx context.Context, ticker string) (*models.RealTimeQuote, error) {
+	urlParams := url.Values{}
+	urlParams.Set("symbol", Symbol(ticker))
+
+	var resp globalQuoteResponse
+	if err := c.get(ctx, "GLOBAL_QUOTE", urlParams, &resp); err != nil {
+		return nil, err
+	}
+
+	q := resp.Quote
+	if q.Symbol == "" {
+		return nil, fmt.Errorf("no quote returned for %s", ticker)
+	}
+
+	timestamp := time.Now()
+	if day, err := time.Parse("2006-01-02", q.LatestDay); err == nil {
+		timestamp = day
+	}
+
+	return &models.RealTimeQuote{
+		Code:          ticker,
+		Open:          parseFloat(q.Open),
+		High:          parseFloat(q.High),
+		Low:           parseFloat(q.Low),
+		Close:         parseFloat(q.Price),
+		PreviousClose: parseFloat(q.PreviousClose),
+		Change:        parseFloat(q.Change),
+		ChangePct:     parseFloat(q.ChangePercent),
+		Volume:        int64(parseFloat(q.Volume)),
+		Timestamp:     timestamp,
+		Source:        "alphavantage",
+	}, nil
+}
+
+// overviewResponse represents the OVERVIEW response
+type overviewResponse struct {
+	Symbol            string `json:"Symbol"`
+	Name              string `json:"Name"`
+	Description       string `json:"Description"`
+	Sector            string `json:"Sector"`
+	Industry          string `json:"Industry"`
+	MarketCap         string `json:"MarketCapitalization"`
+	PERatio           string `json:"PERatio"`
+	PriceToBook       string `json:"PriceToBookRatio"`
+	EPS               string `json:"EPS"`
+	DividendYield     string `json:"DividendYield"`
+	Beta              string `json:"Beta"`
+	SharesOutstanding string `json:"SharesOutstanding"`
+}
+
+// GetFundamentals retrieves company fundamentals via OVERVIEW. Alpha Vantage
+// has no ISIN, ETF breakdown or extended financials, so only the overview
+// fields are set; the market service merges them into stored fundamentals.
+func (c *Client) GetFundamentals(ctx context.Context, ticker string) (*models.Fundamentals, error) {
+	urlParams := url.Values{}
+	urlParams.Set("symbol", Symbol(ticker))
+
+	var resp overviewResponse
+	if err := c.get(ctx, "OVERVIEW", urlParams, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Symbol == "" {
+		return nil, fmt.Errorf("no fundamentals returned for %s", ticker)
+	}
+
+	return &models.Fundamentals{
+		Ticker:            ticker,
+		Name:              resp.Name,
+		MarketCap:         parseFloat(resp.MarketCap),
+		PE:                parseFloat(resp.PERatio),
+		PB:                parseFloat(resp.PriceToBook),
+		EPS:               parseFloat(resp.EPS),
+		DividendYield:     parseFloat(resp.DividendYield),
+		Beta:              parseFloat(resp.Beta),
+		SharesOutstanding: int64(parseFloat(resp.SharesOutstanding)),
+		Sector:            resp.Sector,
+		Industry:          resp.Industry,
+		Description:       resp.Description,
+		LastUpdated:       time.Now(),
+	}, nil
+}
@@ -0,0 +1,113 @@
+package alphavantage
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/interfaces"
+)
+
+func TestSymbol_TranslatesExchangeSuffix(t *testing.T) {
+	tests := []struct {
+		ticker string
+		want   string
+	}{
+		{"BHP.AU", "BHP.AX"},
+		{"AAPL.US", "AAPL"},
+		{"VOD.LSE", "VOD.LON"},
+		{"SHOP.TO", "SHOP.TRT"},
+		{"BHP", "BHP"},
+		{"XYZ.UNKNOWN", "XYZ.UNKNOWN"},
+	}
+	for _, tt := range tests {
+		if got := Symbol(tt.ticker); got != tt.want {
+			t.Errorf("Symbol(%q) = %q, want %q", tt.ticker, got, tt.want)
+		}
+	}
+}
+
+func TestGetEOD_ParsesDailySeries(t *testing.T) {
+	var gotSymbol, gotFunction string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotSymbol = r.URL.Query().Get("symbol")
+		gotFunction = r.URL.Query().Get("function")
+		w.Write([]byte(`{"Meta Data": {}, "Time Series (Daily)": {
+			"2025-01-02": {"1. open": "40.0", "2. high": "41.0", "3. low": "39.5", "4. close": "40.5", "5. volume": "1000"},
+			"2025-01-03": {"1. open": "40.5", "2. high": "42.0", "3. low": "40.0", "4. close": "41.8", "5. volume": "2000"},
+			"2024-12-31": {"1. open": "39.0", "2. high": "40.0", "3. low": "38.5", "4. close": "39.9", "5. volume": "500"}
+		}}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient("key", WithBaseURL(srv.URL))
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	resp, err := client.GetEOD(context.Background(), "BHP.AU", interfaces.WithDateRange(from, from.AddDate(0, 0, 5)))
+	if err != nil {
+		t.Fatalf("GetEOD failed: %v", err)
+	}
+	if gotSymbol != "BHP.AX" || gotFunction != "TIME_SERIES_DAILY" {
+		t.Errorf("request symbol=%q function=%q, want BHP.AX and TIME_SERIES_DAILY", gotSymbol, gotFunction)
+	}
+	if len(resp.Data) != 2 {
+		t.Fatalf("expected 2 bars in range, got %d", len(resp.Data))
+	}
+	if !resp.Data[0].Date.Equal(time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC)) || resp.Data[0].Close != 41.8 || resp.Data[0].Volume != 2000 {
+		t.Errorf("first bar = %+v, want 2025-01-03 close 41.8 volume 2000", resp.Data[0])
+	}
+	if resp.Data[0].AdjClose != 0 {
+		t.Errorf("AdjClose = %v, want unset (no adjusted close from TIME_SERIES_DAILY)", resp.Data[0].AdjClose)
+	}
+}
+
+func TestGetRealTimeQuote_ParsesGlobalQuote(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"Global Quote": {"01. symbol": "BHP.AX", "02. open": "45.00", "03. high": "45.90",
+			"04. low": "44.80", "05. price": "45.50", "06. volume": "1234567", "07. latest trading day": "2025-01-03",
+			"08. previous close": "44.90", "09. change": "0.60", "10. change percent": "1.3363%"}}`))
+	}))
+	defer srv.Close()
+
+	quote, err := NewClient("key", WithBaseURL(srv.URL)).GetRealTimeQuote(context.Background(), "BHP.AU")
+	if err != nil {
+		t.Fatalf("GetRealTimeQuote failed: %v", err)
+	}
+	if quote.Code != "BHP.AU" || quote.Source != "alphavantage" {
+		t.Errorf("code=%q source=%q, want BHP.AU and alphavantage", quote.Code, quote.Source)
+	}
+	if quote.Close != 45.50 || quote.PreviousClose != 44.90 || quote.ChangePct != 1.3363 || quote.Volume != 1234567 {
+		t.Errorf("unexpected quote values: %+v", quote)
+	}
+}
+
+func TestGet_NoteIsRateLimited(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"Note": "Thank you for using Alpha Vantage! Our standard API call frequency is 5 calls per minute."}`))
+	}))
+	defer srv.Close()
+
+	_, err := NewClient("key", WithBaseURL(srv.URL)).GetFundamentals(context.Background(), "BHP.AU")
+	if err == nil {
+		t.Fatal("expected error for throttled response")
+	}
+	if !interfaces.IsRateLimited(err) {
+		t.Errorf("expected rate-limit error, got %v", err)
+	}
+}
+
+func TestGet_ErrorMessageIsNotRateLimited(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"Error Message": "Invalid API call."}`))
+	}))
+	defer srv.Close()
+
+	_, err := NewClient("key", WithBaseURL(srv.URL)).GetRealTimeQuote(context.Background(), "NOPE.AU")
+	if err == nil {
+		t.Fatal("expected error for invalid symbol")
+	}
+	if interfaces.IsRateLimited(err) {
+		t.Errorf("invalid-call error should not be rate limited: %v", err)
+	}
+}
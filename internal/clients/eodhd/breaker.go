@@ -0,0 +1,149 @@
+package eodhd
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/interfaces"
+)
+
+// ErrCircuitOpen is returned without calling EODHD while the circuit breaker
+// is open. It is interfaces.ErrCircuitOpen, so services can match it with
+// errors.Is without importing this package.
+var ErrCircuitOpen = interfaces.ErrCircuitOpen
+
+// breakerState is the circuit breaker state
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+func (s breakerState) String() string {
+	switch s {
+	case breakerOpen:
+		return "open"
+	case breakerHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// circuitBreaker opens after threshold consecutive failures within window,
+// rejects calls while open, and after cooldown lets a single probe through
+// (half-open). A successful probe closes the breaker; a failed one reopens it.
+type circuitBreaker struct {
+	threshold int
+	window    time.Duration
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu           sync.Mutex
+	state        breakerState
+	failures     int
+	firstFailure time.Time
+	openedAt     time.Time
+	probing      bool
+}
+
+func newCircuitBreaker(threshold int, window, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		threshold: threshold,
+		window:    window,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+// allow reports whether a call may proceed. While half-open only one probe
+// is in flight at a time.
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = breakerHalfOpen
+		b.probing = true
+		return true
+	case breakerHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+// record updates the breaker with the outcome of an allowed call. It returns
+// the state transitioned to, or the current state when nothing changed.
+func (b *circuitBreaker) record(failed bool) breakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	if !failed {
+		b.state = breakerClosed
+		b.failures = 0
+		b.probing = false
+		return b.state
+	}
+
+	if b.state == breakerHalfOpen {
+		b.state = breakerOpen
+		b.openedAt = now
+		b.probing = false
+		return b.state
+	}
+
+	if b.failures == 0 || now.Sub(b.firstFailure) > b.window {
+		b.failures = 0
+		b.firstFailure = now
+	}
+	b.failures++
+	if b.failures >= b.threshold {
+		b.state = breakerOpen
+		b.openedAt = now
+	}
+	return b.state
+}
+
+// release frees a half-open probe slot without recording an outcome
+func (b *circuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+// currentState returns the breaker state
+func (b *circuitBreaker) currentState() breakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// isBreakerFailure reports whether err means EODHD itself is unhealthy:
+// transport errors, timeouts, 5xx and 429. Client errors such as an unknown
+// ticker (404) and undecodable bodies do not count.
+func isBreakerFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= http.StatusInternalServerError || apiErr.StatusCode == http.StatusTooManyRequests
+	}
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
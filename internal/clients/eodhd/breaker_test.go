@@ -0,0 +1,129 @@
+package eodhd
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker_ClosedOpenHalfOpenClosed(t *testing.T) {
+	var failing atomic.Bool
+	failing.Store(true)
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if failing.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"code":"BHP.AU","close":45.5}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient("key", WithBaseURL(srv.URL), WithRateLimit(1000),
+		WithCircuitBreaker(3, time.Minute, 30*time.Second))
+	now := time.Now()
+	client.breaker.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	// Closed: failures reach EODHD until the threshold opens the breaker
+	for i := 0; i < 3; i++ {
+		if _, err := client.GetRealTimeQuote(ctx, "BHP.AU"); err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("call %d: expected upstream error, got %v", i, err)
+		}
+	}
+	if state := client.breaker.currentState(); state != breakerOpen {
+		t.Fatalf("state after 3 failures = %s, want open", state)
+	}
+
+	// Open: calls short-circuit without a request
+	if _, err := client.GetRealTimeQuote(ctx, "BHP.AU"); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen while open, got %v", err)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("upstream calls = %d, want 3 (open breaker must not call EODHD)", got)
+	}
+
+	// Half-open: after the cooldown a failed probe reopens the breaker
+	now = now.Add(31 * time.Second)
+	if _, err := client.GetRealTimeQuote(ctx, "BHP.AU"); err == nil || errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected probe to reach EODHD, got %v", err)
+	}
+	if state := client.breaker.currentState(); state != breakerOpen {
+		t.Fatalf("state after failed probe = %s, want open", state)
+	}
+
+	// A successful probe closes it again
+	failing.Store(false)
+	now = now.Add(31 * time.Second)
+	quote, err := client.GetRealTimeQuote(ctx, "BHP.AU")
+	if err != nil {
+		t.Fatalf("expected successful probe, got %v", err)
+	}
+	if quote.Close != 45.5 {
+		t.Errorf("close = %.2f, want 45.50", quote.Close)
+	}
+	if state := client.breaker.currentState(); state != breakerClosed {
+		t.Errorf("state after successful probe = %s, want closed", state)
+	}
+}
+
+func TestCircuitBreaker_HalfOpenAllowsSingleProbe(t *testing.T) {
+	b := newCircuitBreaker(1, time.Minute, time.Second)
+	now := time.Now()
+	b.now = func() time.Time { return now }
+
+	b.record(true)
+	now = now.Add(2 * time.Second)
+	if !b.allow() {
+		t.Fatal("expected first call after cooldown to be allowed as a probe")
+	}
+	if b.allow() {
+		t.Error("expected second concurrent call to be rejected while probing")
+	}
+	b.release()
+	if !b.allow() {
+		t.Error("expected a new probe after the previous one was released")
+	}
+}
+
+func TestCircuitBreaker_FailuresOutsideWindowDoNotOpen(t *testing.T) {
+	b := newCircuitBreaker(3, time.Minute, time.Second)
+	now := time.Now()
+	b.now = func() time.Time { return now }
+
+	b.record(true)
+	b.record(true)
+	now = now.Add(2 * time.Minute) // window expired: count restarts
+	b.record(true)
+	if state := b.currentState(); state != breakerClosed {
+		t.Errorf("state = %s, want closed", state)
+	}
+
+	// A success resets the consecutive count
+	b.record(false)
+	b.record(true)
+	b.record(true)
+	if state := b.currentState(); state != breakerClosed {
+		t.Errorf("state after reset = %s, want closed", state)
+	}
+}
+
+func TestCircuitBreaker_ClientErrorsDoNotTrip(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	client := NewClient("key", WithBaseURL(srv.URL), WithRateLimit(1000),
+		WithCircuitBreaker(2, time.Minute, time.Minute))
+	for i := 0; i < 5; i++ {
+		if _, err := client.GetFundamentals(context.Background(), "NOPE.AU"); errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("call %d: 404s must not open the breaker", i)
+		}
+	}
+}
@@ -3,8 +3,10 @@ package eodhd
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -188,3 +190,65 @@ func TestGetBulkRealTimeQuotes_SkipsMissingCode(t *testing.T) {
 		t.Errorf("expected 1 result (empty code skipped), got %d", len(result))
 	}
 }
+
+func TestGetRealTimeQuotesBatch_ChunksAndKeysByRequestedTicker(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		primary := strings.TrimPrefix(r.URL.Path, "/real-time/")
+		if primary == "T20.AU" {
+			// The second batch fails outright
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		codes := append([]string{primary}, strings.Split(r.URL.Query().Get("s"), ",")...)
+		var out []map[string]interface{}
+		for _, code := range codes {
+			if code == "T3.AU" {
+				continue // missing from the response
+			}
+			// EODHD may echo the code without the exchange suffix
+			out = append(out, map[string]interface{}{"code": strings.TrimSuffix(code, ".AU"), "close": 10.0})
+		}
+		json.NewEncoder(w).Encode(out)
+	}))
+	defer srv.Close()
+
+	tickers := make([]string, 25)
+	for i := range tickers {
+		tickers[i] = fmt.Sprintf("T%d.AU", i)
+	}
+
+	client := NewClient("test-key", WithBaseURL(srv.URL), WithRateLimit(1000))
+	result, err := client.GetRealTimeQuotesBatch(context.Background(), tickers)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("requests = %d, want 2 (batches of 20)", requests)
+	}
+	if len(result) != 19 {
+		t.Errorf("expected 19 quotes (first batch minus T3), got %d", len(result))
+	}
+	if q, ok := result["T0.AU"]; !ok || q.Close != 10 {
+		t.Errorf("expected T0.AU keyed by requested ticker, got %+v", result["T0.AU"])
+	}
+	if _, ok := result["T3.AU"]; ok {
+		t.Error("T3.AU was missing from the response and should be omitted")
+	}
+	if _, ok := result["T21.AU"]; ok {
+		t.Error("T21.AU was in the failed batch and should be omitted")
+	}
+}
+
+func TestGetRealTimeQuotesBatch_AllBatchesFail(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-key", WithBaseURL(srv.URL))
+	if _, err := client.GetRealTimeQuotesBatch(context.Background(), []string{"BHP.AU", "CBA.AU"}); err == nil {
+		t.Error("expected error when every batch fails")
+	}
+}
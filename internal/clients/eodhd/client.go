@@ -12,6 +12,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"golang.org/x/time/rate"
@@ -120,9 +121,11 @@ const (
 type Client struct {
 	baseURL    string
 	apiKey     string
+	keyMu      sync.RWMutex // guards apiKey, which SetAPIKey swaps on a live client
 	httpClient *http.Client
 	logger     *common.Logger
 	limiter    *rate.Limiter
+	breaker    *circuitBreaker // nil = disabled
 }
 
 // ClientOption configures the client
@@ -156,6 +159,17 @@ func WithTimeout(timeout time.Duration) ClientOption {
 	}
 }
 
+// WithCircuitBreaker opens the circuit after threshold consecutive failures
+// within window, failing calls fast with ErrCircuitOpen, and lets a probe
+// through after cooldown. A threshold of zero or less disables the breaker.
+func WithCircuitBreaker(threshold int, window, cooldown time.Duration) ClientOption {
+	return func(c *Client) {
+		if threshold > 0 {
+			c.breaker = newCircuitBreaker(threshold, window, cooldown)
+		}
+	}
+}
+
 // NewClient creates a new EODHD client
 func NewClient(apiKey string, opts ...ClientOption) *Client {
 	c := &Client{
@@ -175,6 +189,40 @@ func NewClient(apiKey string, opts ...ClientOption) *Client {
 	return c
 }
 
+// SetRateLimit changes the request rate of a live client. Requests already
+// waiting on the limiter are re-timed against the new rate.
+func (c *Client) SetRateLimit(requestsPerSecond int) {
+	if requestsPerSecond <= 0 {
+		return
+	}
+	c.limiter.SetBurst(requestsPerSecond)
+	c.limiter.SetLimit(rate.Limit(requestsPerSecond))
+}
+
+// SetAPIKey swaps the key used by subsequent requests on a live client.
+// Requests already in flight keep the key they were sent with.
+func (c *Client) SetAPIKey(apiKey string) {
+	c.keyMu.Lock()
+	c.apiKey = apiKey
+	c.keyMu.Unlock()
+}
+
+func (c *Client) currentKey() string {
+	c.keyMu.RLock()
+	defer c.keyMu.RUnlock()
+	return c.apiKey
+}
+
+// ProbeKey checks apiKey against the /user endpoint, which does not count
+// towards the daily quota, without changing the key the client uses.
+func (c *Client) ProbeKey(ctx context.Context, apiKey string) error {
+	var user map[string]interface{}
+	if err := c.doGetWithKey(ctx, apiKey, "/user", nil, &user); err != nil {
+		return fmt.Errorf("EODHD key probe failed: %w", err)
+	}
+	return nil
+}
+
 // APIError represents an API error
 type APIError struct {
 	StatusCode int
@@ -186,18 +234,57 @@ func (e *APIError) Error() string {
 	return fmt.Sprintf("EODHD API error: %s (status: %d, endpoint: %s)", e.Message, e.StatusCode, e.Endpoint)
 }
 
-// get performs a rate-limited GET request
+// RateLimited reports whether the request was rejected for exceeding the
+// per-minute limit (429) or the daily quota (402).
+func (e *APIError) RateLimited() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode == http.StatusPaymentRequired
+}
+
+// Name identifies the client as a market data provider
+func (c *Client) Name() string {
+	return "eodhd"
+}
+
+// get performs a rate-limited GET request, short-circuiting with
+// ErrCircuitOpen while the circuit breaker is open.
 func (c *Client) get(ctx context.Context, path string, params url.Values, result interface{}) error {
+	if c.breaker == nil {
+		return c.doGet(ctx, path, params, result)
+	}
+	if !c.breaker.allow() {
+		return fmt.Errorf("EODHD %s: %w", path, ErrCircuitOpen)
+	}
+	err := c.doGet(ctx, path, params, result)
+	if err != nil && ctx.Err() != nil {
+		// Cancelled by the caller: says nothing about EODHD's health
+		c.breaker.release()
+		return err
+	}
+	prev := c.breaker.currentState()
+	if state := c.breaker.record(isBreakerFailure(err)); state != prev {
+		c.logger.Warn().Str("path", path).Str("from", prev.String()).Str("to", state.String()).Msg("EODHD circuit breaker state change")
+	}
+	return err
+}
+
+// doGet performs the rate-limited GET request with the client's current key
+func (c *Client) doGet(ctx context.Context, path string, params url.Values, result interface{}) error {
+	return c.doGetWithKey(ctx, c.currentKey(), path, params, result)
+}
+
+// doGetWithKey performs the rate-limited GET request with apiKey
+func (c *Client) doGetWithKey(ctx context.Context, apiKey, path string, params url.Values, result interface{}) (err error) {
 	// Wait for rate limiter
 	if err := c.limiter.Wait(ctx); err != nil {
 		return fmt.Errorf("rate limit wait: %w", err)
 	}
+	defer func(start time.Time) { common.ObserveAPICall("eodhd", start, err) }(time.Now())
 
 	// Add API key
 	if params == nil {
 		params = url.Values{}
 	}
-	params.Set("api_token", c.apiKey)
+	params.Set("api_token", apiKey)
 	params.Set("fmt", "json")
 
 	reqURL := fmt.Sprintf("%s%s?%s", c.baseURL, path, params.Encode())
@@ -343,6 +430,52 @@ func (c *Client) GetBulkRealTimeQuotes(ctx context.Context, tickers []string) (m
 	return result, nil
 }
 
+// realTimeBatchSize is the most tickers sent in one real-time request.
+const realTimeBatchSize = 20
+
+// GetRealTimeQuotesBatch fetches live quotes for any number of tickers using
+// the multi-symbol real-time endpoint, realTimeBatchSize tickers per request.
+// The result is keyed by the requested ticker (EODHD may echo "BHP" for
+// "BHP.AU"); tickers missing from the response, or with a zero close, are
+// omitted so callers can fall back per ticker. A failed request only loses its
+// own batch; an error is returned only when every batch failed.
+func (c *Client) GetRealTimeQuotesBatch(ctx context.Context, tickers []string) (map[string]*models.RealTimeQuote, error) {
+	result := make(map[string]*models.RealTimeQuote, len(tickers))
+	var lastErr error
+	failed := 0
+	batches := 0
+
+	for i := 0; i < len(tickers); i += realTimeBatchSize {
+		end := i + realTimeBatchSize
+		if end > len(tickers) {
+			end = len(tickers)
+		}
+		batch := tickers[i:end]
+		batches++
+
+		quotes, err := c.GetBulkRealTimeQuotes(ctx, batch)
+		if err != nil {
+			c.logger.Warn().Err(err).Strs("batch", batch).Msg("Real-time batch fetch failed")
+			lastErr = err
+			failed++
+			continue
+		}
+		for _, ticker := range batch {
+			for code, q := range quotes {
+				if tickerMatches(ticker, code) && q.Close > 0 {
+					result[ticker] = q
+					break
+				}
+			}
+		}
+	}
+
+	if batches > 0 && failed == batches {
+		return nil, lastErr
+	}
+	return result, nil
+}
+
 // convertRealTimeResponse converts a realTimeResponse to a models.RealTimeQuote.
 func convertRealTimeResponse(resp realTimeResponse) *models.RealTimeQuote {
 	return &models.RealTimeQuote{
@@ -615,6 +748,8 @@ func (c *Client) GetFundamentals(ctx context.Context, ticker string) (*models.Fu
 		fundamentals.HistoricalFinancials = historical
 	}
 
+	fundamentals.EarningsDates = upcomingEarningsDates(resp, time.Now())
+
 	// Extract analyst ratings
 	if string(resp.AnalystRatings.Rating) != "" || resp.AnalystRatings.TargetPrice > 0 {
 		fundamentals.AnalystRatings = &models.AnalystRatings{
@@ -759,6 +894,13 @@ type fundamentalsResponse struct {
 			EquityPercent flexFloat64 `json:"Equity_%"`
 		} `json:"World_Regions"`
 	} `json:"ETF_Data"`
+	// Earnings calendar: History holds past and scheduled quarters keyed by
+	// period end; scheduled ones carry a future reportDate
+	Earnings struct {
+		History map[string]struct {
+			ReportDate string `json:"reportDate"`
+		} `json:"History"`
+	} `json:"Earnings"`
 	// Analyst ratings consensus
 	AnalystRatings struct {
 		Rating      flexString  `json:"Rating"`
@@ -946,3 +1088,21 @@ func domicileFromISIN(isin, fallbackCountry string) string {
 
 // Ensure Client implements EODHDClient
 var _ interfaces.EODHDClient = (*Client)(nil)
+
+// upcomingEarningsDates returns the distinct report dates in the earnings
+// history that fall on or after now's calendar day, ascending.
+func upcomingEarningsDates(resp fundamentalsResponse, now time.Time) []string {
+	today := now.UTC().Format("2006-01-02")
+	seen := make(map[string]bool)
+	var dates []string
+	for _, entry := range resp.Earnings.History {
+		d := entry.ReportDate
+		if _, err := time.Parse("2006-01-02", d); err != nil || d < today || seen[d] {
+			continue
+		}
+		seen[d] = true
+		dates = append(dates, d)
+	}
+	sort.Strings(dates)
+	return dates
+}
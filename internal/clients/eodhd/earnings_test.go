@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestGetFundamentals_ParsesAnalystRatings(t *testing.T) {
@@ -244,3 +245,43 @@ func TestGetFundamentals_NoAnalystRatings(t *testing.T) {
 		t.Error("expected nil analyst ratings for ETF")
 	}
 }
+
+func TestGetFundamentals_ParsesUpcomingEarningsDates(t *testing.T) {
+	today := time.Now().UTC()
+	next := today.AddDate(0, 0, 10).Format("2006-01-02")
+	later := today.AddDate(0, 3, 0).Format("2006-01-02")
+	past := today.AddDate(0, -3, 0).Format("2006-01-02")
+
+	mockResp := map[string]interface{}{
+		"General":    map[string]interface{}{"Code": "BHP", "Name": "BHP Group", "Type": "Common Stock"},
+		"Highlights": map[string]interface{}{},
+		"Earnings": map[string]interface{}{
+			"History": map[string]interface{}{
+				"2025-12-31": map[string]interface{}{"reportDate": later, "epsActual": nil},
+				"2025-09-30": map[string]interface{}{"reportDate": next, "epsActual": nil},
+				"2025-06-30": map[string]interface{}{"reportDate": past, "epsActual": 1.2},
+				"2025-03-31": map[string]interface{}{"reportDate": nil},
+			},
+		},
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(mockResp)
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-key", WithBaseURL(srv.URL))
+	fundamentals, err := client.GetFundamentals(context.Background(), "BHP.AU")
+	if err != nil {
+		t.Fatalf("GetFundamentals failed: %v", err)
+	}
+
+	got := fundamentals.EarningsDates
+	if len(got) != 2 || got[0] != next || got[1] != later {
+		t.Errorf("EarningsDates = %v, want [%s %s]", got, next, later)
+	}
+	if d, ok := fundamentals.NextEarningsDate(today); !ok || d.Format("2006-01-02") != next {
+		t.Errorf("NextEarningsDate = %v, %v; want %s", d, ok, next)
+	}
+}
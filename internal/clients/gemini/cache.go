@@ -0,0 +1,184 @@
+package gemini
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/interfaces"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// cacheKeyPrefix namespaces cached responses in the system KV store
+const cacheKeyPrefix = "gemini_cache:"
+
+// cachePurgeInterval is how often RunPurge sweeps expired entries
+const cachePurgeInterval = time.Hour
+
+// CacheStore is the subset of interfaces.InternalStore the cache needs
+type CacheStore interface {
+	GetSystemKV(ctx context.Context, key string) (string, error)
+	SetSystemKV(ctx context.Context, key, value string) error
+	DeleteSystemKV(ctx context.Context, key string) error
+	ListSystemKV(ctx context.Context, prefix string) (map[string]string, error)
+}
+
+// cacheEntry is a cached response as stored in the system KV store
+type cacheEntry struct {
+	Response  string    `json:"response"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// CachedClient wraps a GeminiClient and reuses responses for identical
+// requests until they expire. The key hashes the prompt (and URLs), the
+// model and common.SchemaVersion, so changing the model or bumping the
+// schema version misses the cache. Requests whose context carries
+// common.WithForceAIRefresh skip the lookup and overwrite the entry.
+// Streaming and PDF summaries are passed through uncached. Expired entries
+// are deleted when read and by RunPurge.
+type CachedClient struct {
+	interfaces.GeminiClient
+	store   CacheStore
+	ttl     time.Duration
+	version string // common.SchemaVersion
+	now     func() time.Time
+	logger  *common.Logger
+}
+
+// NewCachedClient wraps client with a cache of ttl backed by store
+func NewCachedClient(client interfaces.GeminiClient, store CacheStore, ttl time.Duration, logger *common.Logger) *CachedClient {
+	if logger == nil {
+		logger = common.NewSilentLogger()
+	}
+	return &CachedClient{
+		GeminiClient: client,
+		store:        store,
+		ttl:          ttl,
+		version:      common.SchemaVersion,
+		now:          time.Now,
+		logger:       logger,
+	}
+}
+
+// GenerateContent returns a cached response for prompt or generates one
+func (c *CachedClient) GenerateContent(ctx context.Context, prompt string) (string, error) {
+	key := c.cacheKey("generate", c.model("default"), prompt)
+	return c.cached(ctx, key, func() (string, error) {
+		return c.GeminiClient.GenerateContent(ctx, prompt)
+	})
+}
+
+// GenerateWithURLContext returns a cached response for prompt and urls or
+// generates one
+func (c *CachedClient) GenerateWithURLContext(ctx context.Context, prompt string, urls ...string) (string, error) {
+	key := c.cacheKey("url_context", c.model("default"), prompt, strings.Join(urls, "\n"))
+	return c.cached(ctx, key, func() (string, error) {
+		return c.GeminiClient.GenerateWithURLContext(ctx, prompt, urls...)
+	})
+}
+
+// AnalyzeStock returns a cached analysis for the same ticker and data or
+// generates one
+func (c *CachedClient) AnalyzeStock(ctx context.Context, ticker string, data *models.StockData) (string, error) {
+	key := c.cacheKey("analyze_stock", c.model(TaskAnalysis), buildStockAnalysisPrompt(ticker, data))
+	return c.cached(ctx, key, func() (string, error) {
+		return c.GeminiClient.AnalyzeStock(ctx, ticker, data)
+	})
+}
+
+// model returns the wrapped client's model for task, falling back to the
+// default model
+func (c *CachedClient) model(task string) string {
+	models := c.GeminiClient.ActiveModels()
+	if m := models[task]; m != "" {
+		return m
+	}
+	return models["default"]
+}
+
+// cacheKey hashes the schema version, operation, model and inputs
+func (c *CachedClient) cacheKey(op, model string, inputs ...string) string {
+	h := sha256.New()
+	for _, part := range append([]string{c.version, op, model}, inputs...) {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return cacheKeyPrefix + hex.EncodeToString(h.Sum(nil))
+}
+
+// cached returns the unexpired entry under key, or calls generate and stores
+// its result. Errors are never cached, and store failures only cost a call.
+func (c *CachedClient) cached(ctx context.Context, key string, generate func() (string, error)) (string, error) {
+	if !common.ForceAIRefresh(ctx) {
+		if raw, err := c.store.GetSystemKV(ctx, key); err == nil {
+			var entry cacheEntry
+			if json.Unmarshal([]byte(raw), &entry) == nil && c.now().Before(entry.ExpiresAt) {
+				c.logger.Debug().Str("key", key).Msg("Gemini cache hit")
+				common.RecordCacheLookup("gemini", true)
+				return entry.Response, nil
+			}
+			if err := c.store.DeleteSystemKV(ctx, key); err != nil {
+				c.logger.Warn().Err(err).Msg("Failed to delete expired Gemini cache entry")
+			}
+		}
+		common.RecordCacheLookup("gemini", false)
+	}
+
+	response, err := generate()
+	if err != nil {
+		return "", err
+	}
+
+	raw, _ := json.Marshal(cacheEntry{Response: response, ExpiresAt: c.now().Add(c.ttl)})
+	if err := c.store.SetSystemKV(ctx, key, string(raw)); err != nil {
+		c.logger.Warn().Err(err).Msg("Failed to cache Gemini response")
+	}
+	return response, nil
+}
+
+// PurgeExpired deletes every expired (or unreadable) cache entry and
+// returns how many were removed.
+func (c *CachedClient) PurgeExpired(ctx context.Context) (int, error) {
+	entries, err := c.store.ListSystemKV(ctx, cacheKeyPrefix)
+	if err != nil {
+		return 0, err
+	}
+	now := c.now()
+	purged := 0
+	for key, raw := range entries {
+		var entry cacheEntry
+		if json.Unmarshal([]byte(raw), &entry) == nil && now.Before(entry.ExpiresAt) {
+			continue
+		}
+		if err := c.store.DeleteSystemKV(ctx, key); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// RunPurge calls PurgeExpired every hour until ctx is cancelled.
+func (c *CachedClient) RunPurge(ctx context.Context) {
+	ticker := time.NewTicker(cachePurgeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := c.PurgeExpired(ctx)
+			if err != nil {
+				c.logger.Warn().Err(err).Msg("Gemini cache purge failed")
+				continue
+			}
+			if n > 0 {
+				c.logger.Info().Int("purged", n).Msg("Purged expired Gemini cache entries")
+			}
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package gemini
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/interfaces"
+)
+
+// memKV is an in-memory CacheStore
+type memKV map[string]string
+
+func (m memKV) GetSystemKV(_ context.Context, key string) (string, error) {
+	v, ok := m[key]
+	if !ok {
+		return "", errors.New("system KV not found")
+	}
+	return v, nil
+}
+
+func (m memKV) SetSystemKV(_ context.Context, key, value string) error {
+	m[key] = value
+	return nil
+}
+
+func (m memKV) DeleteSystemKV(_ context.Context, key string) error {
+	delete(m, key)
+	return nil
+}
+
+func (m memKV) ListSystemKV(_ context.Context, prefix string) (map[string]string, error) {
+	entries := make(map[string]string)
+	for k, v := range m {
+		if strings.HasPrefix(k, prefix) {
+			entries[k] = v
+		}
+	}
+	return entries, nil
+}
+
+// countingClient answers every prompt with a numbered response
+type countingClient struct {
+	interfaces.GeminiClient
+	calls  int
+	models map[string]string
+	err    error
+}
+
+func (c *countingClient) GenerateContent(_ context.Context, prompt string) (string, error) {
+	c.calls++
+	if c.err != nil {
+		return "", c.err
+	}
+	return prompt + " #" + string(rune('0'+c.calls)), nil
+}
+
+func (c *countingClient) ActiveModels() map[string]string {
+	if c.models == nil {
+		return map[string]string{"default": "gemini-2.5-flash"}
+	}
+	return c.models
+}
+
+func newTestCache(inner *countingClient) (*CachedClient, memKV, *time.Time) {
+	store := memKV{}
+	cache := NewCachedClient(inner, store, time.Hour, nil)
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+	return cache, store, &now
+}
+
+func TestCachedClient_HitAndMiss(t *testing.T) {
+	inner := &countingClient{}
+	cache, _, _ := newTestCache(inner)
+	ctx := context.Background()
+
+	first, err := cache.GenerateContent(ctx, "summarise SMSF")
+	if err != nil {
+		t.Fatalf("GenerateContent failed: %v", err)
+	}
+	second, _ := cache.GenerateContent(ctx, "summarise SMSF")
+	if inner.calls != 1 || second != first {
+		t.Errorf("repeat prompt: calls = %d, response %q; want 1 call returning %q", inner.calls, second, first)
+	}
+
+	if _, err := cache.GenerateContent(ctx, "summarise Personal"); err != nil {
+		t.Fatalf("GenerateContent failed: %v", err)
+	}
+	if inner.calls != 2 {
+		t.Errorf("different prompt: calls = %d, want 2", inner.calls)
+	}
+}
+
+func TestCachedClient_Expiry(t *testing.T) {
+	inner := &countingClient{}
+	cache, _, now := newTestCache(inner)
+	ctx := context.Background()
+
+	cache.GenerateContent(ctx, "summarise SMSF")
+	*now = now.Add(59 * time.Minute)
+	cache.GenerateContent(ctx, "summarise SMSF")
+	if inner.calls != 1 {
+		t.Fatalf("before expiry: calls = %d, want 1", inner.calls)
+	}
+
+	*now = now.Add(2 * time.Minute)
+	got, _ := cache.GenerateContent(ctx, "summarise SMSF")
+	if inner.calls != 2 || got != "summarise SMSF #2" {
+		t.Errorf("after expiry: calls = %d, response %q; want a fresh response", inner.calls, got)
+	}
+}
+
+func TestCachedClient_PurgeExpired(t *testing.T) {
+	inner := &countingClient{}
+	cache, store, now := newTestCache(inner)
+	ctx := context.Background()
+
+	cache.GenerateContent(ctx, "summarise SMSF")
+	*now = now.Add(30 * time.Minute)
+	cache.GenerateContent(ctx, "summarise Personal")
+	store["vire_schema_version"] = "17" // not a cache entry
+
+	*now = now.Add(45 * time.Minute) // first entry expired, second still fresh
+	n, err := cache.PurgeExpired(ctx)
+	if err != nil || n != 1 {
+		t.Fatalf("PurgeExpired = %d, %v; want 1 entry purged", n, err)
+	}
+	if len(store) != 2 || store["vire_schema_version"] != "17" {
+		t.Errorf("store after purge = %v, want the fresh entry and the unrelated key", store)
+	}
+}
+
+func TestCachedClient_ExpiredEntryDeletedOnRead(t *testing.T) {
+	inner := &countingClient{}
+	cache, store, now := newTestCache(inner)
+	ctx := context.Background()
+
+	cache.GenerateContent(ctx, "summarise SMSF")
+	*now = now.Add(2 * time.Hour)
+	inner.err = errors.New("quota") // regeneration fails, so nothing is re-cached
+	cache.GenerateContent(ctx, "summarise SMSF")
+	if len(store) != 0 {
+		t.Errorf("store = %v, want the expired entry deleted", store)
+	}
+}
+
+func TestCachedClient_ForceRefreshBypassesAndReplaces(t *testing.T) {
+	inner := &countingClient{}
+	cache, _, _ := newTestCache(inner)
+	ctx := context.Background()
+
+	cache.GenerateContent(ctx, "summarise SMSF")
+	forced, _ := cache.GenerateContent(common.WithForceAIRefresh(ctx), "summarise SMSF")
+	if inner.calls != 2 || forced != "summarise SMSF #2" {
+		t.Fatalf("force refresh: calls = %d, response %q; want a fresh response", inner.calls, forced)
+	}
+
+	// The forced response replaces the cached one
+	if got, _ := cache.GenerateContent(ctx, "summarise SMSF"); got != forced || inner.calls != 2 {
+		t.Errorf("after refresh: response %q, calls %d; want %q from cache", got, inner.calls, forced)
+	}
+}
+
+func TestCachedClient_KeyIncludesModelAndSchemaVersion(t *testing.T) {
+	inner := &countingClient{}
+	cache, _, _ := newTestCache(inner)
+
+	key := cache.cacheKey("generate", "gemini-2.5-flash", "prompt")
+	if other := cache.cacheKey("generate", "gemini-2.0-flash", "prompt"); other == key {
+		t.Error("expected model change to change the key")
+	}
+
+	if cache.version != common.SchemaVersion {
+		t.Errorf("version = %q, want common.SchemaVersion %q", cache.version, common.SchemaVersion)
+	}
+	cache.version = common.SchemaVersion + "-next"
+	if bumped := cache.cacheKey("generate", "gemini-2.5-flash", "prompt"); bumped == key {
+		t.Error("expected schema version bump to change the key")
+	}
+}
+
+func TestCachedClient_ErrorsAreNotCached(t *testing.T) {
+	inner := &countingClient{err: errors.New("quota exceeded")}
+	cache, store, _ := newTestCache(inner)
+
+	if _, err := cache.GenerateContent(context.Background(), "summarise SMSF"); err == nil {
+		t.Fatal("expected error to be returned")
+	}
+	if len(store) != 0 {
+		t.Errorf("expected nothing cached after an error, got %d entries", len(store))
+	}
+}
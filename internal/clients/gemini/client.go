@@ -3,9 +3,13 @@ package gemini
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"iter"
 	"os"
 	"strings"
+	"sync"
+	"time"
 
 	"google.golang.org/genai"
 
@@ -24,9 +28,15 @@ const (
 	TaskAnalysis      = "analysis"
 )
 
+// streamFunc streams a generation; it is Models.GenerateContentStream,
+// replaced in tests by a fake backend.
+type streamFunc func(ctx context.Context, model string, contents []*genai.Content, config *genai.GenerateContentConfig) iter.Seq2[*genai.GenerateContentResponse, error]
+
 // Client implements the GeminiClient interface
 type Client struct {
+	mu             sync.RWMutex // guards client and stream, which SetAPIKey swaps
 	client         *genai.Client
+	stream         streamFunc
 	model          string
 	models         map[string]string
 	maxURLs        int
@@ -67,16 +77,14 @@ func WithLogger(logger *common.Logger) ClientOption {
 
 // NewClient creates a new Gemini client
 func NewClient(ctx context.Context, apiKey string, opts ...ClientOption) (*Client, error) {
-	genaiClient, err := genai.NewClient(ctx, &genai.ClientConfig{
-		APIKey:  apiKey,
-		Backend: genai.BackendGeminiAPI,
-	})
+	genaiClient, err := newGenaiClient(ctx, apiKey)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create Gemini client: %w", err)
+		return nil, err
 	}
 
 	c := &Client{
 		client:         genaiClient,
+		stream:         genaiClient.Models.GenerateContentStream,
 		model:          DefaultModel,
 		maxURLs:        DefaultMaxURLs,
 		maxContentSize: DefaultMaxContentSize,
@@ -90,6 +98,60 @@ func NewClient(ctx context.Context, apiKey string, opts ...ClientOption) (*Clien
 	return c, nil
 }
 
+func newGenaiClient(ctx context.Context, apiKey string) (*genai.Client, error) {
+	genaiClient, err := genai.NewClient(ctx, &genai.ClientConfig{
+		APIKey:  apiKey,
+		Backend: genai.BackendGeminiAPI,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Gemini client: %w", err)
+	}
+	return genaiClient, nil
+}
+
+// SetAPIKey rebuilds the underlying Gemini client with apiKey. Calls already
+// in flight finish on the previous client.
+func (c *Client) SetAPIKey(ctx context.Context, apiKey string) error {
+	genaiClient, err := newGenaiClient(ctx, apiKey)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.client = genaiClient
+	c.stream = genaiClient.Models.GenerateContentStream
+	c.mu.Unlock()
+	return nil
+}
+
+// ProbeKey checks apiKey by listing a single model, which costs no
+// generation quota, without changing the key the client uses.
+func (c *Client) ProbeKey(ctx context.Context, apiKey string) error {
+	genaiClient, err := newGenaiClient(ctx, apiKey)
+	if err != nil {
+		return err
+	}
+	if _, err := genaiClient.Models.List(ctx, &genai.ListModelsConfig{PageSize: 1}); err != nil {
+		return fmt.Errorf("Gemini key probe failed: %w", err)
+	}
+	return nil
+}
+
+// api returns the current Gemini API client.
+func (c *Client) api() *genai.Client {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.client
+}
+
+// generate calls Gemini's GenerateContent and records the call in the API
+// metrics.
+func (c *Client) generate(ctx context.Context, model string, contents []*genai.Content, config *genai.GenerateContentConfig) (*genai.GenerateContentResponse, error) {
+	start := time.Now()
+	result, err := c.api().Models.GenerateContent(ctx, model, contents, config)
+	common.ObserveAPICall("gemini", start, err)
+	return result, err
+}
+
 // modelForTask returns the model for a specific task, falling back to the default model.
 func (c *Client) modelForTask(task string) string {
 	if c.models != nil {
@@ -120,7 +182,7 @@ func (c *Client) GenerateContent(ctx context.Context, prompt string) (string, er
 	c.logger.Debug().Str("model", c.model).Msg("Generating content")
 
 	contents := genai.Text(prompt)
-	result, err := c.client.Models.GenerateContent(ctx, c.model, contents, nil)
+	result, err := c.generate(ctx, c.model, contents, nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to generate content: %w", err)
 	}
@@ -128,6 +190,62 @@ func (c *Client) GenerateContent(ctx context.Context, prompt string) (string, er
 	return extractTextFromResponse(result)
 }
 
+// AnalyzeStream generates analysis for prompt with the analysis task model,
+// sending text chunks as Gemini produces them. Both channels are closed when
+// the stream ends. A failure, including a safety block, is sent on the error
+// channel first; cancelling ctx stops the stream and closes both channels
+// without an error.
+func (c *Client) AnalyzeStream(ctx context.Context, prompt string) (<-chan string, <-chan error) {
+	chunks := make(chan string)
+	errs := make(chan error, 1)
+
+	model := c.modelForTask(TaskAnalysis)
+	c.logger.Debug().Str("model", model).Msg("Streaming analysis")
+
+	c.mu.RLock()
+	stream := c.stream
+	c.mu.RUnlock()
+
+	go func() {
+		defer close(errs)
+		defer close(chunks)
+
+		start := time.Now()
+		var streamErr error
+		defer func() { common.ObserveAPICall("gemini", start, streamErr) }()
+
+		for resp, err := range stream(ctx, model, genai.Text(prompt), nil) {
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				streamErr = err
+				errs <- fmt.Errorf("failed to stream analysis: %w", err)
+				return
+			}
+			text, err := extractTextFromResponse(resp)
+			if err != nil {
+				var filtered *interfaces.ContentFilteredError
+				if errors.As(err, &filtered) {
+					errs <- err
+					return
+				}
+				continue // a chunk may carry no text (e.g. only the finish reason)
+			}
+			if text == "" {
+				continue
+			}
+			select {
+			case chunks <- text:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return chunks, errs
+}
+
 // GenerateWithURLContext generates content using Gemini's URL context tool.
 // If urls are provided, they are prepended to the prompt as reference URLs.
 func (c *Client) GenerateWithURLContext(ctx context.Context, prompt string, urls ...string) (string, error) {
@@ -151,7 +269,7 @@ func (c *Client) GenerateWithURLContext(ctx context.Context, prompt string, urls
 		Tools: []*genai.Tool{{URLContext: &genai.URLContext{}}},
 	}
 
-	result, err := c.client.Models.GenerateContent(ctx, c.model, contents, config)
+	result, err := c.generate(ctx, c.model, contents, config)
 	if err != nil {
 		return "", fmt.Errorf("failed to generate content with URL context: %w", err)
 	}
@@ -159,8 +277,27 @@ func (c *Client) GenerateWithURLContext(ctx context.Context, prompt string, urls
 	return extractTextFromResponse(result)
 }
 
-// extractTextFromResponse extracts text from a generate content response
+// safetyFinishReasons are candidate finish reasons that mean the response
+// was withheld by Gemini's content filters.
+var safetyFinishReasons = map[genai.FinishReason]bool{
+	genai.FinishReasonSafety:                 true,
+	genai.FinishReasonBlocklist:              true,
+	genai.FinishReasonProhibitedContent:      true,
+	genai.FinishReasonSPII:                   true,
+	genai.FinishReasonImageSafety:            true,
+	genai.FinishReasonImageProhibitedContent: true,
+}
+
+// extractTextFromResponse extracts text from a generate content response.
+// Safety blocks on the prompt or the first candidate are returned as
+// *interfaces.ContentFilteredError so callers can degrade gracefully.
 func extractTextFromResponse(result *genai.GenerateContentResponse) (string, error) {
+	if pf := result.PromptFeedback; pf != nil && pf.BlockReason != "" && pf.BlockReason != genai.BlockedReasonUnspecified {
+		return "", &interfaces.ContentFilteredError{Reason: string(pf.BlockReason)}
+	}
+	if len(result.Candidates) > 0 && safetyFinishReasons[result.Candidates[0].FinishReason] {
+		return "", &interfaces.ContentFilteredError{Reason: string(result.Candidates[0].FinishReason)}
+	}
 	if len(result.Candidates) == 0 || result.Candidates[0].Content == nil || len(result.Candidates[0].Content.Parts) == 0 {
 		return "", fmt.Errorf("no content generated")
 	}
@@ -182,7 +319,7 @@ func (c *Client) AnalyzeStock(ctx context.Context, ticker string, data *models.S
 
 	prompt := buildStockAnalysisPrompt(ticker, data)
 	contents := genai.Text(prompt)
-	result, err := c.client.Models.GenerateContent(ctx, model, contents, nil)
+	result, err := c.generate(ctx, model, contents, nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to generate stock analysis: %w", err)
 	}
@@ -282,14 +419,15 @@ func (c *Client) SummariseFilingPDF(ctx context.Context, pdfPath string, prompt
 		return "", fmt.Errorf("PDF file not accessible: %w", err)
 	}
 
-	uploaded, err := c.client.Files.UploadFromPath(ctx, pdfPath, &genai.UploadFileConfig{
+	api := c.api()
+	uploaded, err := api.Files.UploadFromPath(ctx, pdfPath, &genai.UploadFileConfig{
 		MIMEType: "application/pdf",
 	})
 	if err != nil {
 		return "", fmt.Errorf("failed to upload PDF to Gemini Files API: %w", err)
 	}
 	defer func() {
-		if _, delErr := c.client.Files.Delete(ctx, uploaded.Name, nil); delErr != nil {
+		if _, delErr := api.Files.Delete(ctx, uploaded.Name, nil); delErr != nil {
 			c.logger.Warn().Err(delErr).Str("file", uploaded.Name).Msg("Failed to delete uploaded file from Gemini")
 		}
 	}()
@@ -302,7 +440,7 @@ func (c *Client) SummariseFilingPDF(ctx context.Context, pdfPath string, prompt
 		},
 	}}
 
-	result, err := c.client.Models.GenerateContent(ctx, model, contents, nil)
+	result, err := c.generate(ctx, model, contents, nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to generate content from PDF: %w", err)
 	}
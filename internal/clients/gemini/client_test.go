@@ -0,0 +1,138 @@
+package gemini
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/genai"
+
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/interfaces"
+)
+
+func TestExtractTextFromResponse_SafetyBlock(t *testing.T) {
+	tests := []struct {
+		name   string
+		resp   *genai.GenerateContentResponse
+		reason string
+	}{
+		{
+			name: "prompt blocked",
+			resp: &genai.GenerateContentResponse{
+				PromptFeedback: &genai.GenerateContentResponsePromptFeedback{BlockReason: genai.BlockedReasonSafety},
+			},
+			reason: "SAFETY",
+		},
+		{
+			name: "response withheld",
+			resp: &genai.GenerateContentResponse{
+				Candidates: []*genai.Candidate{{FinishReason: genai.FinishReasonProhibitedContent}},
+			},
+			reason: "PROHIBITED_CONTENT",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := extractTextFromResponse(tt.resp)
+			var filtered *interfaces.ContentFilteredError
+			if !errors.As(err, &filtered) {
+				t.Fatalf("err = %v, want *interfaces.ContentFilteredError", err)
+			}
+			if filtered.Reason != tt.reason {
+				t.Errorf("Reason = %q, want %q", filtered.Reason, tt.reason)
+			}
+		})
+	}
+}
+
+func TestExtractTextFromResponse_Text(t *testing.T) {
+	resp := &genai.GenerateContentResponse{
+		Candidates: []*genai.Candidate{{
+			FinishReason: genai.FinishReasonStop,
+			Content:      &genai.Content{Parts: []*genai.Part{{Text: "Hello "}, {Text: "world"}}},
+		}},
+	}
+	text, err := extractTextFromResponse(resp)
+	if err != nil || text != "Hello world" {
+		t.Errorf("got %q, %v; want %q, nil", text, err, "Hello world")
+	}
+}
+
+// fakeStream yields one response per chunk, as Gemini's streaming API does.
+func fakeStream(chunks ...string) streamFunc {
+	return func(ctx context.Context, model string, contents []*genai.Content, config *genai.GenerateContentConfig) iter.Seq2[*genai.GenerateContentResponse, error] {
+		return func(yield func(*genai.GenerateContentResponse, error) bool) {
+			for _, chunk := range chunks {
+				resp := &genai.GenerateContentResponse{Candidates: []*genai.Candidate{{
+					Content: &genai.Content{Parts: []*genai.Part{{Text: chunk}}},
+				}}}
+				if !yield(resp, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func TestAnalyzeStream_EmitsChunks(t *testing.T) {
+	c := &Client{stream: fakeStream("The portfolio ", "is well ", "diversified."), model: DefaultModel, logger: common.NewSilentLogger()}
+
+	chunks, errs := c.AnalyzeStream(context.Background(), "analyse")
+	var got []string
+	for chunk := range chunks {
+		got = append(got, chunk)
+	}
+	if err := <-errs; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 3 || strings.Join(got, "") != "The portfolio is well diversified." {
+		t.Errorf("chunks = %q, want three chunks of the analysis", got)
+	}
+}
+
+func TestAnalyzeStream_CancelClosesChannels(t *testing.T) {
+	c := &Client{stream: fakeStream("one", "two", "three"), model: DefaultModel, logger: common.NewSilentLogger()}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	chunks, errs := c.AnalyzeStream(ctx, "analyse")
+	if first := <-chunks; first != "one" {
+		t.Fatalf("first chunk = %q, want one", first)
+	}
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		for range chunks {
+		}
+		for range errs {
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("channels not closed after cancellation")
+	}
+}
+
+func TestAnalyzeStream_SafetyBlock(t *testing.T) {
+	blocked := func(ctx context.Context, model string, contents []*genai.Content, config *genai.GenerateContentConfig) iter.Seq2[*genai.GenerateContentResponse, error] {
+		return func(yield func(*genai.GenerateContentResponse, error) bool) {
+			yield(&genai.GenerateContentResponse{Candidates: []*genai.Candidate{{FinishReason: genai.FinishReasonSafety}}}, nil)
+		}
+	}
+	c := &Client{stream: blocked, model: DefaultModel, logger: common.NewSilentLogger()}
+
+	chunks, errs := c.AnalyzeStream(context.Background(), "analyse")
+	for range chunks {
+	}
+	var filtered *interfaces.ContentFilteredError
+	if err := <-errs; !errors.As(err, &filtered) {
+		t.Errorf("err = %v, want *interfaces.ContentFilteredError", err)
+	}
+}
@@ -4,9 +4,11 @@ package navexa
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"math"
+	"math/rand/v2"
 	"net/http"
 	"net/url"
 	"time"
@@ -31,6 +33,11 @@ type Client struct {
 	httpClient *http.Client
 	logger     *common.Logger
 	limiter    *rate.Limiter
+
+	// Retry on 5xx and network errors (maxAttempts <= 1 = no retry)
+	maxAttempts int
+	baseDelay   time.Duration
+	jitter      time.Duration
 }
 
 // ClientOption configures the client
@@ -57,6 +64,11 @@ func WithRateLimit(requestsPerSecond int) ClientOption {
 	}
 }
 
+// RateLimit returns the client's request rate in requests per second
+func (c *Client) RateLimit() int {
+	return int(c.limiter.Limit())
+}
+
 // WithTimeout sets the HTTP timeout
 func WithTimeout(timeout time.Duration) ClientOption {
 	return func(c *Client) {
@@ -64,6 +76,18 @@ func WithTimeout(timeout time.Duration) ClientOption {
 	}
 }
 
+// WithRetry retries requests that fail with a 5xx response or a network
+// error, making at most maxAttempts attempts in total. The wait before retry n
+// is baseDelay doubled n-1 times plus a random delay of up to jitter. 4xx
+// responses are never retried.
+func WithRetry(maxAttempts int, baseDelay, jitter time.Duration) ClientOption {
+	return func(c *Client) {
+		c.maxAttempts = maxAttempts
+		c.baseDelay = baseDelay
+		c.jitter = jitter
+	}
+}
+
 // NewClient creates a new Navexa client
 func NewClient(apiKey string, opts ...ClientOption) *Client {
 	c := &Client{
@@ -94,13 +118,59 @@ func (e *APIError) Error() string {
 	return fmt.Sprintf("Navexa API error: %s (status: %d, endpoint: %s)", e.Message, e.StatusCode, e.Endpoint)
 }
 
-// get performs a rate-limited GET request with optional query parameters
+// get performs a rate-limited GET request with optional query parameters,
+// retrying transient failures as configured by WithRetry.
 func (c *Client) get(ctx context.Context, path string, params url.Values, result interface{}) error {
+	attempts := c.maxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if err = c.doGet(ctx, path, params, result); err == nil || !isRetryable(err) || attempt == attempts {
+			return err
+		}
+		delay := c.retryDelay(attempt)
+		c.logger.Warn().Err(err).Str("url", path).Int("attempt", attempt).Dur("delay", delay).Msg("Navexa API request failed, retrying")
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+	}
+	return err
+}
+
+// retryDelay returns the exponential backoff plus jitter before retry attempt+1
+func (c *Client) retryDelay(attempt int) time.Duration {
+	delay := c.baseDelay << (attempt - 1)
+	if c.jitter > 0 {
+		delay += rand.N(c.jitter)
+	}
+	return delay
+}
+
+// isRetryable reports whether err is a 5xx response or a network error.
+// Other failures, including 4xx responses and undecodable bodies, are final.
+func isRetryable(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= http.StatusInternalServerError
+	}
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+// doGet performs a single rate-limited GET request
+func (c *Client) doGet(ctx context.Context, path string, params url.Values, result interface{}) (err error) {
 	// Wait for rate limiter
 	if err := c.limiter.Wait(ctx); err != nil {
 		return fmt.Errorf("rate limit wait: %w", err)
 	}
 
+	start := time.Now()
+	defer func() { common.ObserveAPICall("navexa", start, err) }()
+
 	reqURL := c.baseURL + path
 	if len(params) > 0 {
 		reqURL += "?" + params.Encode()
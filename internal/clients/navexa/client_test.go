@@ -3,9 +3,13 @@ package navexa
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestGetHoldingTrades_NormalizesSellUnits(t *testing.T) {
@@ -47,3 +51,73 @@ func TestGetHoldingTrades_NormalizesSellUnits(t *testing.T) {
 		t.Errorf("sell units = %v, want 65 (was -65 from API)", result[2].Units)
 	}
 }
+
+// flakyTransport fails the first len(failures) requests in order — a non-zero
+// status returns that response, zero a network error — then delegates.
+type flakyTransport struct {
+	failures []int
+	attempts int
+	next     http.RoundTripper
+}
+
+func (f *flakyTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	f.attempts++
+	if f.attempts <= len(f.failures) {
+		status := f.failures[f.attempts-1]
+		if status == 0 {
+			return nil, errors.New("connection reset by peer")
+		}
+		return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader("unavailable")), Request: r}, nil
+	}
+	return f.next.RoundTrip(r)
+}
+
+func TestGet_RetriesTransientFailures(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]portfolioData{{ID: 1, Name: "SMSF", BaseCurrencyCode: "AUD"}})
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-key", WithBaseURL(srv.URL), WithRetry(3, time.Millisecond, time.Millisecond))
+	transport := &flakyTransport{failures: []int{http.StatusBadGateway, 0}, next: http.DefaultTransport}
+	client.httpClient.Transport = transport
+
+	portfolios, err := client.GetPortfolios(context.Background())
+	if err != nil {
+		t.Fatalf("GetPortfolios returned error: %v", err)
+	}
+	if len(portfolios) != 1 || portfolios[0].Name != "SMSF" {
+		t.Errorf("portfolios = %+v, want SMSF", portfolios)
+	}
+	if transport.attempts != 3 {
+		t.Errorf("attempts = %d, want 3", transport.attempts)
+	}
+}
+
+func TestGet_DoesNotRetryClientErrors(t *testing.T) {
+	client := NewClient("test-key", WithBaseURL("http://navexa.invalid"), WithRetry(3, time.Millisecond, 0))
+	transport := &flakyTransport{failures: []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusUnauthorized}}
+	client.httpClient.Transport = transport
+
+	if _, err := client.GetHoldingTrades(context.Background(), "100"); err == nil {
+		t.Fatal("expected error for 401")
+	}
+	if transport.attempts != 1 {
+		t.Errorf("attempts = %d, want 1 (4xx is not retried)", transport.attempts)
+	}
+}
+
+func TestGet_GivesUpAfterMaxAttempts(t *testing.T) {
+	client := NewClient("test-key", WithBaseURL("http://navexa.invalid"), WithRetry(2, time.Millisecond, 0))
+	transport := &flakyTransport{failures: []int{500, 500, 500}}
+	client.httpClient.Transport = transport
+
+	_, err := client.GetEnrichedHoldings(context.Background(), "1", "2024-01-01", "2024-12-31")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 500 {
+		t.Fatalf("expected final 500 APIError, got %v", err)
+	}
+	if transport.attempts != 2 {
+		t.Errorf("attempts = %d, want 2", transport.attempts)
+	}
+}
@@ -5,11 +5,13 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/bobmcallan/vire/internal/interfaces"
+	"github.com/bobmcallan/vire/internal/models"
 	toml "github.com/pelletier/go-toml/v2"
 	"github.com/ternarybob/arbor/writers"
 )
@@ -23,6 +25,179 @@ type Config struct {
 	Logging     LoggingConfig    `toml:"logging"`
 	Auth        AuthConfig       `toml:"auth"`
 	JobManager  JobManagerConfig `toml:"jobmanager"`
+	Portfolio   PortfolioConfig  `toml:"portfolio"`
+	Fees        FeeConfig        `toml:"fees"`
+	Snipe       SnipeConfig      `toml:"snipe"`
+	Market      MarketConfig     `toml:"market"`
+	Security    SecurityConfig   `toml:"security"`
+
+	Notifications NotificationsConfig `toml:"notifications"`
+}
+
+// NotificationsConfig configures outbound notifications of portfolio events.
+type NotificationsConfig struct {
+	Webhooks WebhooksConfig `toml:"webhooks"`
+}
+
+// WebhooksConfig lists the URLs that receive a signed JSON POST for each new
+// alert, plan trigger and watchlist target hit. Delivery is retried with
+// exponential backoff on errors and non-2xx responses; events that exhaust
+// MaxAttempts are written to the dead-letter log.
+type WebhooksConfig struct {
+	URLs           []string `toml:"urls"`             // empty = webhooks off
+	Secret         string   `toml:"secret"`           // HMAC-SHA256 key for the X-Vire-Signature header (empty = unsigned)
+	MaxAttempts    int      `toml:"max_attempts"`     // attempts per URL before dead-lettering (default 5)
+	Backoff        string   `toml:"backoff"`          // delay before the first retry, doubled per attempt (default "2s")
+	Timeout        string   `toml:"timeout"`          // per-request timeout (default "10s")
+	DeadLetterFile string   `toml:"dead_letter_file"` // JSON-lines file of undelivered events (empty = log only)
+}
+
+// GetMaxAttempts returns the delivery attempts per URL (default 5).
+func (c *WebhooksConfig) GetMaxAttempts() int {
+	if c.MaxAttempts <= 0 {
+		return 5
+	}
+	return c.MaxAttempts
+}
+
+// GetBackoff returns the delay before the first retry (default 2s).
+func (c *WebhooksConfig) GetBackoff() time.Duration {
+	d, err := time.ParseDuration(c.Backoff)
+	if err != nil || d <= 0 {
+		return 2 * time.Second
+	}
+	return d
+}
+
+// GetTimeout returns the per-request timeout (default 10s).
+func (c *WebhooksConfig) GetTimeout() time.Duration {
+	d, err := time.ParseDuration(c.Timeout)
+	if err != nil || d <= 0 {
+		return 10 * time.Second
+	}
+	return d
+}
+
+// SecurityConfig controls encryption at rest for API keys in the KV store.
+// The master key comes from VIRE_MASTER_KEY, or else MasterKeyFile; either
+// holds 32 random bytes, base64-encoded. With a master key but EncryptKeys
+// off, existing ciphertext still decrypts and new keys are stored plaintext.
+type SecurityConfig struct {
+	EncryptKeys   bool   `toml:"encrypt_keys"`
+	MasterKeyFile string `toml:"master_key_file"`
+}
+
+// SnipeConfig holds the default thresholds for the snipe (turnaround) scan.
+// Requests may override any of them. Zero values use the built-in defaults.
+type SnipeConfig struct {
+	OversoldRSI     float64 `toml:"oversold_rsi"`      // RSI below this scores as oversold (default 30)
+	NearOversoldRSI float64 `toml:"near_oversold_rsi"` // RSI below this scores as approaching oversold (default 40)
+	MinMomentum     float64 `toml:"min_momentum"`      // trend momentum score floor, -1 to 1 (default 0 = no filter)
+	MinVolumeRatio  float64 `toml:"min_volume_ratio"`  // current/average volume floor (default 0 = no filter)
+	MinScore        float64 `toml:"min_score"`         // minimum candidate score, 0-1 (default 0.6)
+}
+
+// GetThresholds returns the configured snipe thresholds.
+func (c *SnipeConfig) GetThresholds() models.SnipeThresholds {
+	return models.SnipeThresholds{
+		OversoldRSI:     c.OversoldRSI,
+		NearOversoldRSI: c.NearOversoldRSI,
+		MinMomentum:     c.MinMomentum,
+		MinVolumeRatio:  c.MinVolumeRatio,
+		MinScore:        c.MinScore,
+	}
+}
+
+// FeeConfig is the brokerage model applied to simulated trades and rebalances.
+type FeeConfig struct {
+	Model string          `toml:"model"` // "flat", "percentage", "tiered" (empty = no fees)
+	Flat  float64         `toml:"flat"`  // fee per trade (flat)
+	Pct   float64         `toml:"pct"`   // percent of trade value (percentage)
+	Min   float64         `toml:"min"`   // minimum fee (percentage, tiered)
+	Tiers []FeeTierConfig `toml:"tiers"` // ascending by up_to (tiered)
+}
+
+// FeeTierConfig is one band of a tiered fee schedule.
+type FeeTierConfig struct {
+	UpTo float64 `toml:"up_to"` // upper bound of trade value (0 = unbounded)
+	Flat float64 `toml:"flat"`
+	Pct  float64 `toml:"pct"`
+}
+
+// GetFeeModel returns the configured brokerage schedule.
+func (c *FeeConfig) GetFeeModel() models.FeeModel {
+	m := models.FeeModel{
+		Type: models.FeeModelType(strings.ToLower(strings.TrimSpace(c.Model))),
+		Flat: c.Flat,
+		Pct:  c.Pct,
+		Min:  c.Min,
+	}
+	for _, t := range c.Tiers {
+		m.Tiers = append(m.Tiers, models.FeeTier{UpTo: t.UpTo, Flat: t.Flat, Pct: t.Pct})
+	}
+	return m
+}
+
+// PortfolioConfig holds configuration for portfolio sync behaviour
+type PortfolioConfig struct {
+	DefaultExchange   string  `toml:"default_exchange"`    // Exchange assumed for holdings Navexa returns without one (e.g. "ASX", "US")
+	NormalizeCents    *bool   `toml:"normalize_cents"`     // default true (nil = true): divide EODHD prices quoted in cents by 100
+	MinHoldDays       int     `toml:"min_hold_days"`       // holding period before the CGT discount applies (default 365)
+	TradeFetchWorkers int     `toml:"trade_fetch_workers"` // holdings enriched concurrently during sync (default 10)
+	PriceFreshness    string  `toml:"price_freshness"`     // max age of an EOD bar used as a current price (default "24h")
+	RebalanceDriftPct float64 `toml:"rebalance_drift_pct"` // weight drift from target tolerated before a rebalance trade (default 2)
+	StrictStrategy    bool    `toml:"strict_strategy"`     // fail strategy loads on fields stored with an outdated type (default false: drop and log)
+	MarginalTaxRate   float64 `toml:"marginal_tax_rate"`   // percent applied to per-holding estimated tax on sale (default 0: no estimate)
+}
+
+// GetRebalanceDriftPct returns how far, in percentage points, a holding's
+// weight may drift from its target before a rebalance trade is suggested
+// (default 2).
+func (c *PortfolioConfig) GetRebalanceDriftPct() float64 {
+	if c.RebalanceDriftPct <= 0 {
+		return 2
+	}
+	return c.RebalanceDriftPct
+}
+
+// GetMinHoldDays returns the CGT discount holding period in days (default 365).
+func (c *PortfolioConfig) GetMinHoldDays() int {
+	if c.MinHoldDays <= 0 {
+		return 365
+	}
+	return c.MinHoldDays
+}
+
+// GetTradeFetchWorkers returns how many holdings are enriched (trade fetch
+// and cost basis) concurrently during sync (default 10).
+func (c *PortfolioConfig) GetTradeFetchWorkers() int {
+	if c.TradeFetchWorkers <= 0 {
+		return 10
+	}
+	return c.TradeFetchWorkers
+}
+
+// GetPriceFreshness returns how old an EOD bar can be before its close is
+// treated as stale rather than current (default 24h).
+func (c *PortfolioConfig) GetPriceFreshness() time.Duration {
+	if c.PriceFreshness == "" {
+		return 24 * time.Hour
+	}
+	d, err := time.ParseDuration(c.PriceFreshness)
+	if err != nil || d <= 0 {
+		return 24 * time.Hour
+	}
+	return d
+}
+
+// GetNormalizeCents returns whether EODHD prices that are ~100x the Navexa
+// price (quoted in cents rather than dollars) are normalised to dollars.
+// Default is true. Set normalize_cents = false to disable.
+func (c *PortfolioConfig) GetNormalizeCents() bool {
+	if c.NormalizeCents == nil {
+		return true
+	}
+	return *c.NormalizeCents
 }
 
 // JobManagerConfig holds configuration for the background job manager
@@ -35,6 +210,25 @@ type JobManagerConfig struct {
 	WatcherStartupDelay string `toml:"watcher_startup_delay"` // Delay before first scan (default "10s")
 	HeavyJobLimit       int    `toml:"heavy_job_limit"`       // Max concurrent PDF-heavy jobs (default 1)
 	FilingSizeThreshold int64  `toml:"filing_size_threshold"` // PDFs above this size (bytes) are processed one-at-a-time (default 5MB)
+	CoreCollectWorkers  int    `toml:"core_collect_workers"`  // Concurrent tickers in inline core collection (default 5)
+	ReportSchedule      string `toml:"report_schedule"`       // Cron expression for the default portfolio report, e.g. "0 7 * * 1-5" (empty = off)
+	ReportTimezone      string `toml:"report_timezone"`       // IANA zone the schedule is evaluated in (default: server local time)
+	EODDailyDays        int    `toml:"eod_daily_days"`        // Keep daily EOD bars this many days; older bars are compacted to weekly (0 = off, min 366)
+}
+
+// GetEODDailyRetention returns how long EOD bars stay daily before the
+// compaction pass downsamples them to weekly. Zero disables compaction.
+// Values under 366 days are raised to 366 so signals that need a year of
+// daily bars (52-week range, SMA200) are unaffected.
+func (c *JobManagerConfig) GetEODDailyRetention() time.Duration {
+	days := c.EODDailyDays
+	if days <= 0 {
+		return 0
+	}
+	if days < 366 {
+		days = 366
+	}
+	return time.Duration(days) * 24 * time.Hour
 }
 
 // GetWatcherInterval parses and returns the watcher interval duration.
@@ -104,10 +298,76 @@ func (c *JobManagerConfig) GetFilingSizeThreshold() int64 {
 	return c.FilingSizeThreshold
 }
 
+// GetCoreCollectWorkers returns the number of tickers collected concurrently
+// by CollectCoreMarketData. Default: 5.
+func (c *JobManagerConfig) GetCoreCollectWorkers() int {
+	if c.CoreCollectWorkers <= 0 {
+		return 5
+	}
+	return c.CoreCollectWorkers
+}
+
+// GetReportLocation returns the time zone the report schedule is evaluated
+// in. Unset or unknown zones fall back to the server's local time.
+func (c *JobManagerConfig) GetReportLocation() *time.Location {
+	if c.ReportTimezone == "" {
+		return time.Local
+	}
+	loc, err := time.LoadLocation(c.ReportTimezone)
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
 // ServerConfig holds HTTP server configuration
 type ServerConfig struct {
-	Host string `toml:"host"`
-	Port int    `toml:"port"`
+	Host      string                `toml:"host"`
+	Port      int                   `toml:"port"`
+	Auth      ServerAuthConfig      `toml:"auth"`
+	RateLimit ServerRateLimitConfig `toml:"ratelimit"`
+	Metrics   ServerMetricsConfig   `toml:"metrics"`
+}
+
+// ServerMetricsConfig controls the Prometheus /metrics endpoint.
+type ServerMetricsConfig struct {
+	Enabled bool `toml:"enabled"`
+}
+
+// ServerRateLimitConfig sets the per-client token bucket applied to HTTP
+// requests. Clients are keyed by bearer token, or by remote IP when
+// anonymous. RequestsPerMinute = 0 (the default) disables limiting.
+type ServerRateLimitConfig struct {
+	RequestsPerMinute int `toml:"requests_per_minute"`
+	Burst             int `toml:"burst"` // bucket size (default: requests_per_minute)
+}
+
+// GetBurst returns the configured burst, defaulting to RequestsPerMinute.
+func (c *ServerRateLimitConfig) GetBurst() int {
+	if c.Burst <= 0 {
+		return c.RequestsPerMinute
+	}
+	return c.Burst
+}
+
+// ServerAuthConfig controls mandatory bearer authentication on the HTTP API.
+// When disabled (the default), a bearer token is optional and X-Vire-*
+// headers identify the caller.
+type ServerAuthConfig struct {
+	Enabled     bool     `toml:"enabled"`
+	PublicPaths []string `toml:"public_paths"` // exact paths, or prefixes ending in "/", served without a token
+}
+
+// DefaultPublicPaths are the paths served without a token when
+// [server.auth] public_paths is not set.
+var DefaultPublicPaths = []string{"/api/health", "/api/version"}
+
+// GetPublicPaths returns the configured public paths, or DefaultPublicPaths.
+func (c *ServerAuthConfig) GetPublicPaths() []string {
+	if len(c.PublicPaths) == 0 {
+		return DefaultPublicPaths
+	}
+	return c.PublicPaths
 }
 
 // StorageConfig holds storage configuration for SurrealDB.
@@ -136,9 +396,10 @@ type BlobConfig struct {
 
 // ClientsConfig holds API client configurations
 type ClientsConfig struct {
-	EODHD  EODHDConfig  `toml:"eodhd"`
-	Navexa NavexaConfig `toml:"navexa"`
-	Gemini GeminiConfig `toml:"gemini"`
+	EODHD        EODHDConfig        `toml:"eodhd"`
+	AlphaVantage AlphaVantageConfig `toml:"alphavantage"`
+	Navexa       NavexaConfig       `toml:"navexa"`
+	Gemini       GeminiConfig       `toml:"gemini"`
 }
 
 // EODHDConfig holds EODHD API configuration
@@ -147,6 +408,12 @@ type EODHDConfig struct {
 	APIKey    string `toml:"api_key"`
 	RateLimit int    `toml:"rate_limit"`
 	Timeout   string `toml:"timeout"`
+
+	// Circuit breaker: open after CircuitThreshold consecutive failures within
+	// CircuitWindow, then probe again after CircuitCooldown. 0 disables it.
+	CircuitThreshold int    `toml:"circuit_threshold"`
+	CircuitWindow    string `toml:"circuit_window"`
+	CircuitCooldown  string `toml:"circuit_cooldown"`
 }
 
 // GetTimeout parses and returns the timeout duration
@@ -158,11 +425,54 @@ func (c *EODHDConfig) GetTimeout() time.Duration {
 	return d
 }
 
+// GetCircuitWindow parses the failure window, defaulting to 1 minute
+func (c *EODHDConfig) GetCircuitWindow() time.Duration {
+	d, err := time.ParseDuration(c.CircuitWindow)
+	if err != nil || d <= 0 {
+		return time.Minute
+	}
+	return d
+}
+
+// GetCircuitCooldown parses the open-state cooldown, defaulting to 30 seconds
+func (c *EODHDConfig) GetCircuitCooldown() time.Duration {
+	d, err := time.ParseDuration(c.CircuitCooldown)
+	if err != nil || d <= 0 {
+		return 30 * time.Second
+	}
+	return d
+}
+
+// AlphaVantageConfig holds Alpha Vantage API configuration. Alpha Vantage is
+// a failover source for prices and fundamentals when EODHD is rate limited,
+// and is only used when an API key is set.
+type AlphaVantageConfig struct {
+	BaseURL   string `toml:"base_url"`
+	APIKey    string `toml:"api_key"`
+	RateLimit int    `toml:"rate_limit"` // requests per minute
+	Timeout   string `toml:"timeout"`
+}
+
+// GetTimeout parses and returns the timeout duration
+func (c *AlphaVantageConfig) GetTimeout() time.Duration {
+	d, err := time.ParseDuration(c.Timeout)
+	if err != nil {
+		return 30 * time.Second
+	}
+	return d
+}
+
 // NavexaConfig holds Navexa API configuration
 type NavexaConfig struct {
 	BaseURL   string `toml:"base_url"`
 	RateLimit int    `toml:"rate_limit"`
 	Timeout   string `toml:"timeout"`
+
+	// Retry on 5xx and network errors: total attempts (1 = no retry), the
+	// first backoff (doubled per retry) and the maximum random jitter added.
+	RetryMaxAttempts int    `toml:"retry_max_attempts"`
+	RetryBaseDelay   string `toml:"retry_base_delay"`
+	RetryJitter      string `toml:"retry_jitter"`
 }
 
 // GetTimeout parses and returns the timeout duration
@@ -174,6 +484,24 @@ func (c *NavexaConfig) GetTimeout() time.Duration {
 	return d
 }
 
+// GetRetryBaseDelay parses the first retry backoff, defaulting to 500ms
+func (c *NavexaConfig) GetRetryBaseDelay() time.Duration {
+	d, err := time.ParseDuration(c.RetryBaseDelay)
+	if err != nil || d < 0 {
+		return 500 * time.Millisecond
+	}
+	return d
+}
+
+// GetRetryJitter parses the maximum retry jitter, defaulting to 250ms
+func (c *NavexaConfig) GetRetryJitter() time.Duration {
+	d, err := time.ParseDuration(c.RetryJitter)
+	if err != nil || d < 0 {
+		return 250 * time.Millisecond
+	}
+	return d
+}
+
 // GeminiConfig holds Gemini API configuration
 type GeminiConfig struct {
 	APIKey         string            `toml:"api_key"`
@@ -181,6 +509,31 @@ type GeminiConfig struct {
 	Models         map[string]string `toml:"models"`
 	MaxURLs        int               `toml:"max_urls"`
 	MaxContentSize string            `toml:"max_content_size"`
+	FilteredNote   string            `toml:"content_filtered_note"` // shown in place of AI output blocked by Gemini safety filters
+	CacheTTL       string            `toml:"cache_ttl"`             // how long AI responses are reused for an identical prompt; "0" disables
+}
+
+// DefaultContentFilteredNote replaces AI output that Gemini withheld on
+// safety grounds when no note is configured.
+const DefaultContentFilteredNote = "Analysis unavailable (content filtered)."
+
+// GetContentFilteredNote returns the note shown in place of AI output blocked
+// by Gemini's content filters.
+func (c *GeminiConfig) GetContentFilteredNote() string {
+	if strings.TrimSpace(c.FilteredNote) == "" {
+		return DefaultContentFilteredNote
+	}
+	return c.FilteredNote
+}
+
+// GetCacheTTL parses the AI response cache TTL, defaulting to 24h.
+// Zero disables the cache.
+func (c *GeminiConfig) GetCacheTTL() time.Duration {
+	d, err := time.ParseDuration(c.CacheTTL)
+	if err != nil || d < 0 {
+		return 24 * time.Hour
+	}
+	return d
 }
 
 // GetModel returns the model for a given task, falling back to the default Model.
@@ -321,15 +674,26 @@ func NewDefaultConfig() *Config {
 		},
 		Clients: ClientsConfig{
 			EODHD: EODHDConfig{
-				BaseURL:   "https://eodhd.com/api",
-				RateLimit: 10,
-				Timeout:   "30s",
+				BaseURL:          "https://eodhd.com/api",
+				RateLimit:        10,
+				Timeout:          "30s",
+				CircuitThreshold: 5,
+				CircuitWindow:    "1m",
+				CircuitCooldown:  "30s",
 			},
-			Navexa: NavexaConfig{
-				BaseURL:   "https://api.navexa.com.au",
+			AlphaVantage: AlphaVantageConfig{
+				BaseURL:   "https://www.alphavantage.co",
 				RateLimit: 5,
 				Timeout:   "30s",
 			},
+			Navexa: NavexaConfig{
+				BaseURL:          "https://api.navexa.com.au",
+				RateLimit:        5,
+				Timeout:          "30s",
+				RetryMaxAttempts: 3,
+				RetryBaseDelay:   "500ms",
+				RetryJitter:      "250ms",
+			},
 			Gemini: GeminiConfig{
 				Model: "gemini-2.5-flash",
 				Models: map[string]string{
@@ -338,6 +702,7 @@ func NewDefaultConfig() *Config {
 				},
 				MaxURLs:        20,
 				MaxContentSize: "34MB",
+				CacheTTL:       "24h",
 			},
 		},
 		Auth: AuthConfig{
@@ -410,6 +775,31 @@ func applyEnvOverrides(config *Config) {
 		}
 	}
 
+	if v := os.Getenv("VIRE_SERVER_AUTH_ENABLED"); v != "" {
+		config.Server.Auth.Enabled = strings.EqualFold(v, "true") || v == "1"
+	}
+	if v := os.Getenv("VIRE_SERVER_METRICS_ENABLED"); v != "" {
+		config.Server.Metrics.Enabled = strings.EqualFold(v, "true") || v == "1"
+	}
+	if v := os.Getenv("VIRE_SECURITY_ENCRYPT_KEYS"); v != "" {
+		config.Security.EncryptKeys = strings.EqualFold(v, "true") || v == "1"
+	}
+	if v := os.Getenv("VIRE_MASTER_KEY_FILE"); v != "" {
+		config.Security.MasterKeyFile = v
+	}
+	if v := os.Getenv("VIRE_WEBHOOK_URLS"); v != "" {
+		var urls []string
+		for _, u := range strings.Split(v, ",") {
+			if u = strings.TrimSpace(u); u != "" {
+				urls = append(urls, u)
+			}
+		}
+		config.Notifications.Webhooks.URLs = urls
+	}
+	if v := os.Getenv("VIRE_WEBHOOK_SECRET"); v != "" {
+		config.Notifications.Webhooks.Secret = v
+	}
+
 	if level := os.Getenv("VIRE_LOG_LEVEL"); level != "" {
 		config.Logging.Level = level
 	}
@@ -467,6 +857,12 @@ func applyEnvOverrides(config *Config) {
 			break
 		}
 	}
+	for _, envVar := range []string{"ALPHAVANTAGE_API_KEY", "VIRE_ALPHAVANTAGE_API_KEY"} {
+		if v := os.Getenv(envVar); v != "" {
+			config.Clients.AlphaVantage.APIKey = v
+			break
+		}
+	}
 	for _, envVar := range []string{"GEMINI_API_KEY", "VIRE_GEMINI_API_KEY", "GOOGLE_API_KEY"} {
 		if v := os.Getenv(envVar); v != "" {
 			config.Clients.Gemini.APIKey = v
@@ -525,6 +921,93 @@ func applyEnvOverrides(config *Config) {
 			config.JobManager.FilingSizeThreshold = n
 		}
 	}
+	if v := os.Getenv("VIRE_CORE_COLLECT_WORKERS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			config.JobManager.CoreCollectWorkers = n
+		}
+	}
+	if v := os.Getenv("VIRE_REPORT_SCHEDULE"); v != "" {
+		config.JobManager.ReportSchedule = v
+	}
+	if v := os.Getenv("VIRE_REPORT_TIMEZONE"); v != "" {
+		config.JobManager.ReportTimezone = v
+	}
+	if v := os.Getenv("VIRE_EOD_DAILY_DAYS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			config.JobManager.EODDailyDays = n
+		}
+	}
+
+	// Portfolio overrides
+	if v := os.Getenv("VIRE_DEFAULT_EXCHANGE"); v != "" {
+		config.Portfolio.DefaultExchange = v
+	}
+	if v := os.Getenv("VIRE_MIN_HOLD_DAYS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			config.Portfolio.MinHoldDays = n
+		}
+	}
+	if v := os.Getenv("VIRE_TRADE_FETCH_WORKERS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			config.Portfolio.TradeFetchWorkers = n
+		}
+	}
+	if v := os.Getenv("VIRE_PRICE_FRESHNESS"); v != "" {
+		config.Portfolio.PriceFreshness = v
+	}
+	if v := os.Getenv("VIRE_REBALANCE_DRIFT_PCT"); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			config.Portfolio.RebalanceDriftPct = f
+		}
+	}
+	if v := os.Getenv("VIRE_STRICT_STRATEGY"); v != "" {
+		config.Portfolio.StrictStrategy = strings.EqualFold(v, "true") || v == "1"
+	}
+	if v := os.Getenv("VIRE_MARGINAL_TAX_RATE"); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			config.Portfolio.MarginalTaxRate = f
+		}
+	}
+
+	// Snipe threshold overrides
+	for envVar, field := range map[string]*float64{
+		"VIRE_SNIPE_OVERSOLD_RSI":      &config.Snipe.OversoldRSI,
+		"VIRE_SNIPE_NEAR_OVERSOLD_RSI": &config.Snipe.NearOversoldRSI,
+		"VIRE_SNIPE_MIN_MOMENTUM":      &config.Snipe.MinMomentum,
+		"VIRE_SNIPE_MIN_VOLUME_RATIO":  &config.Snipe.MinVolumeRatio,
+		"VIRE_SNIPE_MIN_SCORE":         &config.Snipe.MinScore,
+	} {
+		if v := os.Getenv(envVar); v != "" {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				*field = f
+			}
+		}
+	}
+
+	// Fee model overrides
+	if v := os.Getenv("VIRE_FEE_MODEL"); v != "" {
+		config.Fees.Model = v
+	}
+	if v := os.Getenv("VIRE_FEE_FLAT"); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			config.Fees.Flat = f
+		}
+	}
+	if v := os.Getenv("VIRE_FEE_PCT"); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			config.Fees.Pct = f
+		}
+	}
+	if v := os.Getenv("VIRE_FEE_MIN"); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			config.Fees.Min = f
+		}
+	}
+	if v := os.Getenv("VIRE_NORMALIZE_CENTS"); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			config.Portfolio.NormalizeCents = &b
+		}
+	}
 }
 
 // ValidateRequired checks that all required configuration fields are set.
@@ -558,6 +1041,159 @@ func (c *Config) ValidateRequired() []string {
 	return missing
 }
 
+// KnownGeminiModels lists the Gemini model IDs accepted for [clients.gemini]
+// model and per-task models.
+var KnownGeminiModels = []string{
+	"gemini-2.0-flash",
+	"gemini-2.0-flash-lite",
+	"gemini-2.5-flash",
+	"gemini-2.5-flash-lite",
+	"gemini-2.5-pro",
+	"gemini-3-flash-preview",
+	"gemini-3-pro-preview",
+}
+
+// ConfigError lists every problem found by Config.Validate. Each problem is
+// prefixed with the TOML section and key it concerns.
+type ConfigError struct {
+	Problems []string
+}
+
+func (e *ConfigError) Error() string {
+	if len(e.Problems) == 1 {
+		return "invalid config: " + e.Problems[0]
+	}
+	return fmt.Sprintf("invalid config (%d problems):\n  - %s", len(e.Problems), strings.Join(e.Problems, "\n  - "))
+}
+
+// Validate checks value ranges and cross-field constraints, collecting every
+// problem rather than stopping at the first. It returns a *ConfigError, or
+// nil when the config is usable. Required keys are checked separately by
+// ValidateRequired.
+func (c *Config) Validate() error {
+	var problems []string
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		add("[server] port must be between 1 and 65535 (got %d)", c.Server.Port)
+	}
+
+	if c.Server.RateLimit.RequestsPerMinute < 0 {
+		add("[server.ratelimit] requests_per_minute must not be negative (got %d; 0 disables limiting)", c.Server.RateLimit.RequestsPerMinute)
+	}
+	if c.Server.RateLimit.Burst < 0 {
+		add("[server.ratelimit] burst must not be negative (got %d)", c.Server.RateLimit.Burst)
+	}
+	for _, p := range c.Server.Auth.PublicPaths {
+		if !strings.HasPrefix(p, "/") {
+			add("[server.auth] public_paths entry %q must start with \"/\"", p)
+		}
+	}
+
+	for _, rl := range []struct {
+		section string
+		value   int
+	}{
+		{"clients.eodhd", c.Clients.EODHD.RateLimit},
+		{"clients.alphavantage", c.Clients.AlphaVantage.RateLimit},
+		{"clients.navexa", c.Clients.Navexa.RateLimit},
+	} {
+		if rl.value <= 0 {
+			add("[%s] rate_limit must be greater than 0 (got %d)", rl.section, rl.value)
+		}
+	}
+	if c.Clients.Navexa.RetryMaxAttempts < 0 {
+		add("[clients.navexa] retry_max_attempts must not be negative (got %d)", c.Clients.Navexa.RetryMaxAttempts)
+	}
+
+	if !isKnownGeminiModel(c.Clients.Gemini.Model) {
+		add("[clients.gemini] model %q is not a known Gemini model (expected one of: %s)",
+			c.Clients.Gemini.Model, strings.Join(KnownGeminiModels, ", "))
+	}
+	tasks := make([]string, 0, len(c.Clients.Gemini.Models))
+	for task := range c.Clients.Gemini.Models {
+		tasks = append(tasks, task)
+	}
+	sort.Strings(tasks)
+	for _, task := range tasks {
+		if m := c.Clients.Gemini.Models[task]; m != "" && !isKnownGeminiModel(m) {
+			add("[clients.gemini.models] %s model %q is not a known Gemini model (expected one of: %s)",
+				task, m, strings.Join(KnownGeminiModels, ", "))
+		}
+	}
+
+	for _, d := range []struct {
+		section, key, value string
+	}{
+		{"clients.eodhd", "timeout", c.Clients.EODHD.Timeout},
+		{"clients.eodhd", "circuit_window", c.Clients.EODHD.CircuitWindow},
+		{"clients.eodhd", "circuit_cooldown", c.Clients.EODHD.CircuitCooldown},
+		{"clients.alphavantage", "timeout", c.Clients.AlphaVantage.Timeout},
+		{"clients.navexa", "timeout", c.Clients.Navexa.Timeout},
+		{"clients.navexa", "retry_base_delay", c.Clients.Navexa.RetryBaseDelay},
+		{"clients.navexa", "retry_jitter", c.Clients.Navexa.RetryJitter},
+		{"clients.gemini", "cache_ttl", c.Clients.Gemini.CacheTTL},
+		{"portfolio", "price_freshness", c.Portfolio.PriceFreshness},
+		{"jobmanager", "watcher_interval", c.JobManager.WatcherInterval},
+		{"jobmanager", "purge_after", c.JobManager.PurgeAfter},
+		{"jobmanager", "watcher_startup_delay", c.JobManager.WatcherStartupDelay},
+		{"notifications.webhooks", "backoff", c.Notifications.Webhooks.Backoff},
+		{"notifications.webhooks", "timeout", c.Notifications.Webhooks.Timeout},
+	} {
+		if d.value == "" {
+			continue
+		}
+		if _, err := time.ParseDuration(d.value); err != nil {
+			add("[%s] %s %q is not a valid duration (e.g. \"30s\", \"5m\", \"24h\")", d.section, d.key, d.value)
+		}
+	}
+
+	if c.Logging.Level != "" {
+		switch strings.ToLower(c.Logging.Level) {
+		case "trace", "debug", "info", "warn", "warning", "error", "fatal", "panic", "disabled":
+		default:
+			add("[logging] level %q is not a known level (expected trace, debug, info, warn or error)", c.Logging.Level)
+		}
+	}
+
+	// Cross-field constraints
+	if c.JobManager.ReportSchedule != "" && c.JobManager.ReportTimezone != "" {
+		if _, err := time.LoadLocation(c.JobManager.ReportTimezone); err != nil {
+			add("[jobmanager] report_timezone %q is not a known IANA time zone (e.g. \"Australia/Sydney\")", c.JobManager.ReportTimezone)
+		}
+	}
+	for _, u := range c.Notifications.Webhooks.URLs {
+		if !strings.HasPrefix(u, "http://") && !strings.HasPrefix(u, "https://") {
+			add("[notifications.webhooks] url %q must start with http:// or https://", u)
+		}
+	}
+	switch c.Storage.Blob.Type {
+	case "", "file":
+	case "s3":
+		if c.Storage.Blob.Bucket == "" {
+			add("[storage.blob] bucket is required when type = \"s3\" (or set VIRE_BLOB_BUCKET)")
+		}
+	default:
+		add("[storage.blob] type %q is not supported (expected \"file\" or \"s3\")", c.Storage.Blob.Type)
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return &ConfigError{Problems: problems}
+}
+
+func isKnownGeminiModel(model string) bool {
+	for _, m := range KnownGeminiModels {
+		if model == m {
+			return true
+		}
+	}
+	return false
+}
+
 // IsProduction returns true if running in production mode
 func (c *Config) IsProduction() bool {
 	env := strings.ToLower(strings.TrimSpace(c.Environment))
@@ -588,28 +1224,38 @@ func ResolveDefaultPortfolio(ctx context.Context, store interfaces.InternalStore
 	return ""
 }
 
-// ResolveAPIKey resolves an API key from environment, InternalStore, or fallback
-func ResolveAPIKey(ctx context.Context, store interfaces.InternalStore, name string, fallback string) (string, error) {
-	// Environment variable mapping
-	keyToEnvMapping := map[string][]string{
-		"eodhd_api_key":  {"EODHD_API_KEY", "VIRE_EODHD_API_KEY"},
-		"gemini_api_key": {"GEMINI_API_KEY", "VIRE_GEMINI_API_KEY", "GOOGLE_API_KEY"},
+// apiKeyEnvVars maps each API key name to the environment variables that
+// supply it, in priority order.
+var apiKeyEnvVars = map[string][]string{
+	"eodhd_api_key":        {"EODHD_API_KEY", "VIRE_EODHD_API_KEY"},
+	"alphavantage_api_key": {"ALPHAVANTAGE_API_KEY", "VIRE_ALPHAVANTAGE_API_KEY"},
+	"gemini_api_key":       {"GEMINI_API_KEY", "VIRE_GEMINI_API_KEY", "GOOGLE_API_KEY"},
+}
+
+// APIKeyEnvOverride returns the environment variable that currently supplies
+// the named API key, or "" when none is set. A key set this way takes
+// precedence over the InternalStore in ResolveAPIKey.
+func APIKeyEnvOverride(name string) string {
+	for _, envVarName := range apiKeyEnvVars[name] {
+		if os.Getenv(envVarName) != "" {
+			return envVarName
+		}
 	}
+	return ""
+}
 
+// ResolveAPIKey resolves an API key from environment, InternalStore, or fallback
+func ResolveAPIKey(ctx context.Context, store interfaces.InternalStore, name string, fallback string) (string, error) {
 	// Check environment variables first (highest priority)
-	if envVarNames, ok := keyToEnvMapping[name]; ok {
-		for _, envVarName := range envVarNames {
-			if envValue := os.Getenv(envVarName); envValue != "" {
-				return envValue, nil
-			}
-		}
+	if envVarName := APIKeyEnvOverride(name); envVarName != "" {
+		return os.Getenv(envVarName), nil
 	}
 
 	// Try InternalStore system KV (medium priority)
 	if store != nil {
 		apiKey, err := store.GetSystemKV(ctx, name)
 		if err == nil && apiKey != "" {
-			return apiKey, nil
+			return OpenAPIKey(name, apiKey)
 		}
 	}
 
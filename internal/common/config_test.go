@@ -1,6 +1,8 @@
 package common
 
 import (
+	"errors"
+	"strings"
 	"testing"
 	"time"
 )
@@ -69,6 +71,84 @@ func TestConfig_ValidateRequired_JWTDefaultRejected(t *testing.T) {
 	}
 }
 
+func TestConfig_Validate_DefaultsPass(t *testing.T) {
+	if err := NewDefaultConfig().Validate(); err != nil {
+		t.Errorf("default config should validate, got: %v", err)
+	}
+}
+
+func TestConfig_Validate_IndividualViolations(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(c *Config)
+		wantErr string
+	}{
+		{"port zero", func(c *Config) { c.Server.Port = 0 }, "[server] port must be between 1 and 65535 (got 0)"},
+		{"port too large", func(c *Config) { c.Server.Port = 70000 }, "[server] port must be between 1 and 65535 (got 70000)"},
+		{"relative public path", func(c *Config) { c.Server.Auth.PublicPaths = []string{"api/health"} }, `[server.auth] public_paths entry "api/health" must start with "/"`},
+		{"eodhd rate limit", func(c *Config) { c.Clients.EODHD.RateLimit = 0 }, "[clients.eodhd] rate_limit must be greater than 0"},
+		{"alphavantage rate limit", func(c *Config) { c.Clients.AlphaVantage.RateLimit = -1 }, "[clients.alphavantage] rate_limit must be greater than 0"},
+		{"navexa rate limit", func(c *Config) { c.Clients.Navexa.RateLimit = 0 }, "[clients.navexa] rate_limit must be greater than 0"},
+		{"navexa retry attempts", func(c *Config) { c.Clients.Navexa.RetryMaxAttempts = -2 }, "[clients.navexa] retry_max_attempts must not be negative"},
+		{"unknown gemini model", func(c *Config) { c.Clients.Gemini.Model = "gpt-4" }, `[clients.gemini] model "gpt-4" is not a known Gemini model`},
+		{"unknown per-task model", func(c *Config) { c.Clients.Gemini.Models = map[string]string{"review": "gemini-9"} }, `[clients.gemini.models] review model "gemini-9"`},
+		{"bad duration", func(c *Config) { c.Portfolio.PriceFreshness = "soon" }, `[portfolio] price_freshness "soon" is not a valid duration`},
+		{"bad log level", func(c *Config) { c.Logging.Level = "loud" }, `[logging] level "loud" is not a known level`},
+		{"bad report timezone", func(c *Config) {
+			c.JobManager.ReportSchedule = "0 7 * * 1-5"
+			c.JobManager.ReportTimezone = "Mars/Olympus"
+		}, `[jobmanager] report_timezone "Mars/Olympus" is not a known IANA time zone`},
+		{"s3 without bucket", func(c *Config) { c.Storage.Blob.Type = "s3"; c.Storage.Blob.Bucket = "" }, `[storage.blob] bucket is required when type = "s3"`},
+		{"unknown blob type", func(c *Config) { c.Storage.Blob.Type = "ftp" }, `[storage.blob] type "ftp" is not supported`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewDefaultConfig()
+			tt.mutate(cfg)
+			err := cfg.Validate()
+			var cfgErr *ConfigError
+			if !errors.As(err, &cfgErr) {
+				t.Fatalf("Validate() = %v, want *ConfigError", err)
+			}
+			if len(cfgErr.Problems) != 1 {
+				t.Fatalf("expected 1 problem, got %d: %v", len(cfgErr.Problems), cfgErr.Problems)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() = %q, want it to contain %q", err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_Validate_ReportsAllViolations(t *testing.T) {
+	cfg := NewDefaultConfig()
+	cfg.Server.Port = -1
+	cfg.Clients.EODHD.RateLimit = 0
+	cfg.Clients.Navexa.RateLimit = 0
+	cfg.Clients.Gemini.Model = "gemini-1.0-ultra"
+	cfg.JobManager.WatcherInterval = "every minute"
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+	msg := err.Error()
+	if !strings.HasPrefix(msg, "invalid config (5 problems):") {
+		t.Errorf("error should count every problem, got: %q", msg)
+	}
+	for _, want := range []string{
+		"[server] port",
+		"[clients.eodhd] rate_limit",
+		"[clients.navexa] rate_limit",
+		"[clients.gemini] model",
+		"[jobmanager] watcher_interval",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("error missing %q:\n%s", want, msg)
+		}
+	}
+}
+
 func TestConfig_EODHDKeyEnvOverride(t *testing.T) {
 	t.Setenv("EODHD_API_KEY", "from-env")
 
@@ -147,6 +227,27 @@ func TestConfig_ValidateRequired_EnvOverridesFix(t *testing.T) {
 	}
 }
 
+func TestMarketConfig_GetMACDPeriods(t *testing.T) {
+	tests := []struct {
+		name               string
+		cfg                MarketConfig
+		fast, slow, signal int
+	}{
+		{"unset", MarketConfig{}, 12, 26, 9},
+		{"configured", MarketConfig{MACDFast: 8, MACDSlow: 21, MACDSignal: 5}, 8, 21, 5},
+		{"partial", MarketConfig{MACDFast: 8}, 12, 26, 9},
+		{"fast not below slow", MarketConfig{MACDFast: 26, MACDSlow: 12, MACDSignal: 9}, 12, 26, 9},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fast, slow, signal := tt.cfg.GetMACDPeriods()
+			if fast != tt.fast || slow != tt.slow || signal != tt.signal {
+				t.Errorf("GetMACDPeriods() = %d/%d/%d, want %d/%d/%d", fast, slow, signal, tt.fast, tt.slow, tt.signal)
+			}
+		})
+	}
+}
+
 func TestJobManagerConfig_GetWatcherStartupDelay_Default(t *testing.T) {
 	cfg := &JobManagerConfig{}
 	d := cfg.GetWatcherStartupDelay()
@@ -324,3 +425,34 @@ func TestOAuth2Config_GetRefreshTokenExpiry_Default(t *testing.T) {
 		t.Errorf("GetRefreshTokenExpiry() = %v, want %v", got, want)
 	}
 }
+
+func TestConfig_SecurityEnvOverrides(t *testing.T) {
+	t.Setenv("VIRE_SECURITY_ENCRYPT_KEYS", "true")
+	t.Setenv("VIRE_MASTER_KEY_FILE", "/etc/vire/master.key")
+
+	cfg := NewDefaultConfig()
+	applyEnvOverrides(cfg)
+
+	if !cfg.Security.EncryptKeys {
+		t.Error("Security.EncryptKeys = false after env override, want true")
+	}
+	if cfg.Security.MasterKeyFile != "/etc/vire/master.key" {
+		t.Errorf("Security.MasterKeyFile = %q, want %q", cfg.Security.MasterKeyFile, "/etc/vire/master.key")
+	}
+}
+
+func TestConfig_WebhookEnvOverrides(t *testing.T) {
+	t.Setenv("VIRE_WEBHOOK_URLS", "https://a.example.com/hook, ,https://b.example.com/hook")
+	t.Setenv("VIRE_WEBHOOK_SECRET", "s3cret")
+
+	cfg := NewDefaultConfig()
+	applyEnvOverrides(cfg)
+
+	urls := cfg.Notifications.Webhooks.URLs
+	if len(urls) != 2 || urls[0] != "https://a.example.com/hook" || urls[1] != "https://b.example.com/hook" {
+		t.Errorf("Webhooks.URLs = %v, want the two non-empty URLs", urls)
+	}
+	if cfg.Notifications.Webhooks.Secret != "s3cret" {
+		t.Errorf("Webhooks.Secret = %q, want %q", cfg.Notifications.Webhooks.Secret, "s3cret")
+	}
+}
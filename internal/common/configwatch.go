@@ -0,0 +1,255 @@
+// Package common provides shared utilities for Vire
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// configReloadDebounce coalesces the burst of events editors emit for a
+// single save (truncate, write, chmod, or rename-over) into one reload.
+const configReloadDebounce = 250 * time.Millisecond
+
+// ConfigChange describes one config field whose value differs between the
+// live config and a freshly read file.
+type ConfigChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// ConfigReload reports the outcome of re-reading the config file. Applied
+// fields are live; RestartRequired fields differ on disk but keep their
+// running value until the server restarts.
+type ConfigReload struct {
+	Path            string         `json:"path"`
+	Applied         []ConfigChange `json:"applied"`
+	RestartRequired []ConfigChange `json:"restart_required"`
+	ReloadedAt      time.Time      `json:"reloaded_at"`
+}
+
+// configField reads one field for comparison and, for hot-reloadable fields,
+// copies it from a freshly loaded config into the live one.
+type configField struct {
+	name string
+	get  func(c *Config) string
+	copy func(dst, src *Config)
+}
+
+// hotReloadFields are safe to change on a running server: rate limits apply
+// to the next client built (Navexa) or the live limiter (EODHD), and the
+// freshness TTL, watcher interval and log level are read on each use.
+var hotReloadFields = []configField{
+	{"logging.level",
+		func(c *Config) string { return c.Logging.Level },
+		func(dst, src *Config) { dst.Logging.Level = src.Logging.Level }},
+	{"clients.eodhd.rate_limit",
+		func(c *Config) string { return fmt.Sprint(c.Clients.EODHD.RateLimit) },
+		func(dst, src *Config) { dst.Clients.EODHD.RateLimit = src.Clients.EODHD.RateLimit }},
+	{"clients.navexa.rate_limit",
+		func(c *Config) string { return fmt.Sprint(c.Clients.Navexa.RateLimit) },
+		func(dst, src *Config) { dst.Clients.Navexa.RateLimit = src.Clients.Navexa.RateLimit }},
+	{"clients.navexa.retry_max_attempts",
+		func(c *Config) string { return fmt.Sprint(c.Clients.Navexa.RetryMaxAttempts) },
+		func(dst, src *Config) { dst.Clients.Navexa.RetryMaxAttempts = src.Clients.Navexa.RetryMaxAttempts }},
+	{"clients.navexa.retry_base_delay",
+		func(c *Config) string { return c.Clients.Navexa.RetryBaseDelay },
+		func(dst, src *Config) { dst.Clients.Navexa.RetryBaseDelay = src.Clients.Navexa.RetryBaseDelay }},
+	{"clients.navexa.retry_jitter",
+		func(c *Config) string { return c.Clients.Navexa.RetryJitter },
+		func(dst, src *Config) { dst.Clients.Navexa.RetryJitter = src.Clients.Navexa.RetryJitter }},
+	{"portfolio.price_freshness",
+		func(c *Config) string { return c.Portfolio.PriceFreshness },
+		func(dst, src *Config) { dst.Portfolio.PriceFreshness = src.Portfolio.PriceFreshness }},
+	{"jobmanager.watcher_interval",
+		func(c *Config) string { return c.JobManager.WatcherInterval },
+		func(dst, src *Config) { dst.JobManager.WatcherInterval = src.JobManager.WatcherInterval }},
+}
+
+// restartFields are bound at startup (listeners, storage connections, log
+// writers) and are reported but never applied by a reload.
+var restartFields = []configField{
+	{name: "server.host", get: func(c *Config) string { return c.Server.Host }},
+	{name: "server.port", get: func(c *Config) string { return fmt.Sprint(c.Server.Port) }},
+	{name: "server.auth.enabled", get: func(c *Config) string { return fmt.Sprint(c.Server.Auth.Enabled) }},
+	{name: "server.metrics.enabled", get: func(c *Config) string { return fmt.Sprint(c.Server.Metrics.Enabled) }},
+	{name: "server.ratelimit.requests_per_minute", get: func(c *Config) string { return fmt.Sprint(c.Server.RateLimit.RequestsPerMinute) }},
+	{name: "server.ratelimit.burst", get: func(c *Config) string { return fmt.Sprint(c.Server.RateLimit.Burst) }},
+	{name: "server.auth.public_paths", get: func(c *Config) string { return strings.Join(c.Server.Auth.PublicPaths, ",") }},
+	{name: "storage.address", get: func(c *Config) string { return c.Storage.Address }},
+	{name: "storage.namespace", get: func(c *Config) string { return c.Storage.Namespace }},
+	{name: "storage.database", get: func(c *Config) string { return c.Storage.Database }},
+	{name: "storage.data_path", get: func(c *Config) string { return c.Storage.DataPath }},
+	{name: "storage.blob.type", get: func(c *Config) string { return c.Storage.Blob.Type }},
+	{name: "storage.blob.path", get: func(c *Config) string { return c.Storage.Blob.Path }},
+	{name: "logging.outputs", get: func(c *Config) string { return strings.Join(c.Logging.Outputs, ",") }},
+	{name: "logging.file_path", get: func(c *Config) string { return c.Logging.FilePath }},
+	{name: "security.encrypt_keys", get: func(c *Config) string { return fmt.Sprint(c.Security.EncryptKeys) }},
+	{name: "security.master_key_file", get: func(c *Config) string { return c.Security.MasterKeyFile }},
+	{name: "notifications.webhooks.urls", get: func(c *Config) string { return strings.Join(c.Notifications.Webhooks.URLs, ",") }},
+	{name: "notifications.webhooks.max_attempts", get: func(c *Config) string { return fmt.Sprint(c.Notifications.Webhooks.MaxAttempts) }},
+	{name: "notifications.webhooks.backoff", get: func(c *Config) string { return c.Notifications.Webhooks.Backoff }},
+	{name: "notifications.webhooks.timeout", get: func(c *Config) string { return c.Notifications.Webhooks.Timeout }},
+	{name: "notifications.webhooks.dead_letter_file", get: func(c *Config) string { return c.Notifications.Webhooks.DeadLetterFile }},
+}
+
+// ApplyHotReload copies the hot-reloadable fields of next into live and
+// returns what changed, plus the restart-only fields that differ. The caller
+// must hold whatever lock guards live.
+func ApplyHotReload(live, next *Config) (applied, restart []ConfigChange) {
+	applied, restart = []ConfigChange{}, []ConfigChange{}
+	for _, f := range hotReloadFields {
+		if old, cur := f.get(live), f.get(next); old != cur {
+			f.copy(live, next)
+			applied = append(applied, ConfigChange{Field: f.name, Old: old, New: cur})
+		}
+	}
+	for _, f := range restartFields {
+		if old, cur := f.get(live), f.get(next); old != cur {
+			restart = append(restart, ConfigChange{Field: f.name, Old: old, New: cur})
+		}
+	}
+	return applied, restart
+}
+
+// validateHotReload rejects a config that would break a running server if
+// applied: missing required keys plus everything Config.Validate checks, such
+// as a rate limit below 1 or an unparseable duration that would silently fall
+// back to its default.
+func validateHotReload(c *Config) error {
+	problems := c.ValidateRequired()
+	if err := c.Validate(); err != nil {
+		var cfgErr *ConfigError
+		if !errors.As(err, &cfgErr) {
+			return err
+		}
+		problems = append(problems, cfgErr.Problems...)
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	sort.Strings(problems)
+	return &ConfigError{Problems: problems}
+}
+
+// ConfigWatcher re-reads the config file when it changes on disk and hands
+// each valid result to an apply callback. A file that fails to parse or
+// validate is logged and skipped, so the live config is never replaced by a
+// broken one.
+type ConfigWatcher struct {
+	path    string
+	prepare func(*Config) // optional: normalise a loaded config (e.g. resolve relative paths)
+	apply   func(*Config) *ConfigReload
+	logger  *Logger
+	mu      sync.Mutex // serialises reloads from the watcher and Reload callers
+}
+
+// NewConfigWatcher creates a watcher for path. apply receives each valid
+// config and returns what it changed.
+func NewConfigWatcher(path string, logger *Logger, apply func(*Config) *ConfigReload) *ConfigWatcher {
+	return &ConfigWatcher{path: path, apply: apply, logger: logger}
+}
+
+// SetPrepare sets a hook run on every freshly loaded config before it is
+// validated and applied.
+func (w *ConfigWatcher) SetPrepare(fn func(*Config)) {
+	w.prepare = fn
+}
+
+// Path returns the watched config file.
+func (w *ConfigWatcher) Path() string {
+	return w.path
+}
+
+// Reload reads and validates the config file, then applies it. On a read,
+// parse or validation error nothing is applied and the error is returned.
+func (w *ConfigWatcher) Reload() (*ConfigReload, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	// LoadConfig skips missing files; a reload must not fall back to defaults
+	if _, err := os.Stat(w.path); err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", w.path, err)
+	}
+	next, err := LoadConfig(w.path)
+	if err != nil {
+		return nil, err
+	}
+	if w.prepare != nil {
+		w.prepare(next)
+	}
+	if err := validateHotReload(next); err != nil {
+		return nil, err
+	}
+
+	result := w.apply(next)
+	result.Path = w.path
+	result.ReloadedAt = time.Now()
+	for _, c := range result.Applied {
+		w.logger.Info().Str("field", c.Field).Str("old", c.Old).Str("new", c.New).Msg("Config reloaded: field applied")
+	}
+	for _, c := range result.RestartRequired {
+		w.logger.Warn().Str("field", c.Field).Str("old", c.Old).Str("new", c.New).Msg("Config reloaded: field requires restart, ignored")
+	}
+	if len(result.Applied) == 0 && len(result.RestartRequired) == 0 {
+		w.logger.Debug().Str("path", w.path).Msg("Config reloaded: no changes")
+	}
+	return result, nil
+}
+
+// Start watches the config file's directory until ctx is cancelled.
+// Watching the directory rather than the file survives editors that save by
+// renaming a temp file over the original.
+func (w *ConfigWatcher) Start(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create config watcher: %w", err)
+	}
+	dir := filepath.Dir(w.path)
+	if err := watcher.Add(dir); err != nil {
+		watcher.Close()
+		return fmt.Errorf("failed to watch %s: %w", dir, err)
+	}
+	target := filepath.Clean(w.path)
+
+	go func() {
+		defer watcher.Close()
+		var debounce <-chan time.Time
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case ev, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(ev.Name) != target || ev.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) == 0 {
+					continue
+				}
+				debounce = time.After(configReloadDebounce)
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				w.logger.Warn().Err(err).Msg("Config watcher error")
+			case <-debounce:
+				debounce = nil
+				if _, err := w.Reload(); err != nil {
+					w.logger.Error().Err(err).Str("path", w.path).Msg("Config reload rejected, keeping live config")
+				}
+			}
+		}
+	}()
+
+	w.logger.Info().Str("path", w.path).Msg("Watching config file for changes")
+	return nil
+}
@@ -0,0 +1,43 @@
+package common
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseFinancialYear parses an Australian financial year such as "2023-2024"
+// (or "2023-24") and returns its bounds: 1 July of the first year to the
+// start of 1 July of the second, so end is exclusive.
+func ParseFinancialYear(fy string) (start, end time.Time, err error) {
+	parts := strings.Split(strings.TrimSpace(fy), "-")
+	if len(parts) != 2 {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid financial year %q: expected YYYY-YYYY (e.g. 2023-2024)", fy)
+	}
+	first, err1 := strconv.Atoi(parts[0])
+	second, err2 := strconv.Atoi(parts[1])
+	if err1 != nil || err2 != nil || len(parts[0]) != 4 {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid financial year %q: expected YYYY-YYYY (e.g. 2023-2024)", fy)
+	}
+	if len(parts[1]) == 2 {
+		second += first / 100 * 100
+		if second < first {
+			second += 100
+		}
+	}
+	if second != first+1 {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid financial year %q: years must be consecutive", fy)
+	}
+	start = time.Date(first, time.July, 1, 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(1, 0, 0), nil
+}
+
+// FinancialYearOf returns the "YYYY-YYYY" financial year containing t.
+func FinancialYearOf(t time.Time) string {
+	first := t.Year()
+	if t.Month() < time.July {
+		first--
+	}
+	return fmt.Sprintf("%d-%d", first, first+1)
+}
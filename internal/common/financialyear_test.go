@@ -0,0 +1,35 @@
+package common
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseFinancialYear(t *testing.T) {
+	start, end, err := ParseFinancialYear("2023-2024")
+	if err != nil {
+		t.Fatalf("ParseFinancialYear failed: %v", err)
+	}
+	if !start.Equal(time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC)) || !end.Equal(time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("bounds = %s..%s, want 2023-07-01..2024-07-01", start, end)
+	}
+
+	if s, _, err := ParseFinancialYear("1999-00"); err != nil || s.Year() != 1999 {
+		t.Errorf("short form 1999-00: start=%s err=%v", s, err)
+	}
+
+	for _, bad := range []string{"", "2023", "2023-2025", "2024-2023", "FY24", "23-24"} {
+		if _, _, err := ParseFinancialYear(bad); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestFinancialYearOf(t *testing.T) {
+	if got := FinancialYearOf(time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)); got != "2023-2024" {
+		t.Errorf("30 Jun 2024 = %s, want 2023-2024", got)
+	}
+	if got := FinancialYearOf(time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)); got != "2024-2025" {
+		t.Errorf("1 Jul 2024 = %s, want 2024-2025", got)
+	}
+}
@@ -0,0 +1,193 @@
+package common
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+// encryptedKeyPrefix marks a stored API key as ciphertext. Values without
+// it are plaintext written before encryption was enabled.
+const encryptedKeyPrefix = "enc:v1:"
+
+// masterKeySize is the AES-256 key length required of the master key.
+const masterKeySize = 32
+
+// ErrKeyDecrypt is returned when a stored API key cannot be decrypted,
+// typically because the master key differs from the one that encrypted it.
+var ErrKeyDecrypt = errors.New("stored API key could not be decrypted (wrong master key?)")
+
+// KeyCipher envelope-encrypts stored API keys. Each value gets a random data
+// key that encrypts it with AES-GCM; the data key is in turn encrypted with
+// the master key. The key name is bound as additional data, so a ciphertext
+// cannot be moved to another key name.
+type KeyCipher struct {
+	master cipher.AEAD
+}
+
+// NewKeyCipher builds a KeyCipher from a 32-byte master key.
+func NewKeyCipher(masterKey []byte) (*KeyCipher, error) {
+	if len(masterKey) != masterKeySize {
+		return nil, fmt.Errorf("master key must be %d bytes, got %d", masterKeySize, len(masterKey))
+	}
+	aead, err := newGCM(masterKey)
+	if err != nil {
+		return nil, err
+	}
+	return &KeyCipher{master: aead}, nil
+}
+
+// ParseMasterKey decodes a base64 master key (as produced by
+// `openssl rand -base64 32`), ignoring surrounding whitespace.
+func ParseMasterKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("master key is not valid base64: %w", err)
+	}
+	if len(key) != masterKeySize {
+		return nil, fmt.Errorf("master key must decode to %d bytes, got %d", masterKeySize, len(key))
+	}
+	return key, nil
+}
+
+// LoadMasterKey returns the master key from VIRE_MASTER_KEY, or else from
+// keyFile. It returns nil without error when neither is set.
+func LoadMasterKey(keyFile string) ([]byte, error) {
+	if v := os.Getenv("VIRE_MASTER_KEY"); v != "" {
+		key, err := ParseMasterKey(v)
+		if err != nil {
+			return nil, fmt.Errorf("VIRE_MASTER_KEY: %w", err)
+		}
+		return key, nil
+	}
+	if keyFile == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read master key file: %w", err)
+	}
+	key, err := ParseMasterKey(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("master key file %s: %w", keyFile, err)
+	}
+	return key, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Encrypt returns the stored form of value for the key called name.
+func (c *KeyCipher) Encrypt(name, value string) (string, error) {
+	dataKey := make([]byte, masterKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", fmt.Errorf("failed to generate data key: %w", err)
+	}
+	data, err := newGCM(dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	// Layout: wrap nonce | wrapped data key | value nonce | ciphertext
+	out := make([]byte, 0, 2*c.master.NonceSize()+2*c.master.Overhead()+masterKeySize+len(value))
+	wrapNonce := make([]byte, c.master.NonceSize())
+	valueNonce := make([]byte, data.NonceSize())
+	if _, err := rand.Read(wrapNonce); err != nil {
+		return "", err
+	}
+	if _, err := rand.Read(valueNonce); err != nil {
+		return "", err
+	}
+	out = append(out, wrapNonce...)
+	out = c.master.Seal(out, wrapNonce, dataKey, []byte(name))
+	out = append(out, valueNonce...)
+	out = data.Seal(out, valueNonce, []byte(value), []byte(name))
+	return encryptedKeyPrefix + base64.StdEncoding.EncodeToString(out), nil
+}
+
+// Decrypt returns the plaintext of a stored value. Values without the
+// ciphertext prefix are returned unchanged.
+func (c *KeyCipher) Decrypt(name, stored string) (string, error) {
+	if !IsEncryptedKey(stored) {
+		return stored, nil
+	}
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(stored, encryptedKeyPrefix))
+	if err != nil {
+		return "", fmt.Errorf("%w: malformed ciphertext", ErrKeyDecrypt)
+	}
+	ns, wrappedLen := c.master.NonceSize(), masterKeySize+c.master.Overhead()
+	if len(raw) < 2*ns+wrappedLen+c.master.Overhead() {
+		return "", fmt.Errorf("%w: ciphertext too short", ErrKeyDecrypt)
+	}
+	dataKey, err := c.master.Open(nil, raw[:ns], raw[ns:ns+wrappedLen], []byte(name))
+	if err != nil {
+		return "", ErrKeyDecrypt
+	}
+	data, err := newGCM(dataKey)
+	if err != nil {
+		return "", err
+	}
+	rest := raw[ns+wrappedLen:]
+	value, err := data.Open(nil, rest[:ns], rest[ns:], []byte(name))
+	if err != nil {
+		return "", ErrKeyDecrypt
+	}
+	return string(value), nil
+}
+
+// IsEncryptedKey reports whether a stored value is ciphertext.
+func IsEncryptedKey(stored string) bool {
+	return strings.HasPrefix(stored, encryptedKeyPrefix)
+}
+
+// activeKeyCipher is set at startup when a master key is configured.
+var activeKeyCipher atomic.Pointer[KeyCipher]
+
+// activeKeyEncrypt reports whether new API key writes are encrypted.
+var activeKeyEncrypt atomic.Bool
+
+// SetKeyCipher installs the cipher used by ResolveAPIKey and SealAPIKey.
+// With encrypt false, existing ciphertext still decrypts but new keys are
+// stored as plaintext. A nil cipher disables both.
+func SetKeyCipher(c *KeyCipher, encrypt bool) {
+	activeKeyCipher.Store(c)
+	activeKeyEncrypt.Store(c != nil && encrypt)
+}
+
+// SealAPIKey returns the form in which the named API key should be written
+// to the KV store: ciphertext when [security] encrypt_keys is on, otherwise
+// the value unchanged.
+func SealAPIKey(name, value string) (string, error) {
+	c := activeKeyCipher.Load()
+	if value == "" || c == nil || !activeKeyEncrypt.Load() {
+		return value, nil
+	}
+	return c.Encrypt(name, value)
+}
+
+// OpenAPIKey returns the plaintext of a value read from the KV store.
+func OpenAPIKey(name, stored string) (string, error) {
+	if !IsEncryptedKey(stored) {
+		return stored, nil
+	}
+	c := activeKeyCipher.Load()
+	if c == nil {
+		return "", fmt.Errorf("stored %s is encrypted but no master key is configured (set VIRE_MASTER_KEY or [security] master_key_file)", name)
+	}
+	v, err := c.Decrypt(name, stored)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", name, err)
+	}
+	return v, nil
+}
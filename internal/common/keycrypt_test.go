@@ -0,0 +1,194 @@
+package common
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testMasterKey(t *testing.T) []byte {
+	t.Helper()
+	key := make([]byte, masterKeySize)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func testKeyCipher(t *testing.T) *KeyCipher {
+	t.Helper()
+	c, err := NewKeyCipher(testMasterKey(t))
+	if err != nil {
+		t.Fatalf("NewKeyCipher: %v", err)
+	}
+	return c
+}
+
+func installKeyCipher(t *testing.T, c *KeyCipher, encrypt bool) {
+	t.Helper()
+	SetKeyCipher(c, encrypt)
+	t.Cleanup(func() { SetKeyCipher(nil, false) })
+}
+
+func TestKeyCipher_RoundTrip(t *testing.T) {
+	c := testKeyCipher(t)
+
+	stored, err := c.Encrypt("eodhd_api_key", "secret-123")
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	if !IsEncryptedKey(stored) {
+		t.Fatalf("stored value %q lacks the ciphertext prefix", stored)
+	}
+	if strings.Contains(stored, "secret-123") {
+		t.Fatal("stored value contains the plaintext")
+	}
+
+	got, err := c.Decrypt("eodhd_api_key", stored)
+	if err != nil {
+		t.Fatalf("Decrypt: %v", err)
+	}
+	if got != "secret-123" {
+		t.Errorf("Decrypt = %q, want %q", got, "secret-123")
+	}
+
+	again, _ := c.Encrypt("eodhd_api_key", "secret-123")
+	if again == stored {
+		t.Error("two encryptions of the same value should differ")
+	}
+}
+
+func TestKeyCipher_WrongMasterKey(t *testing.T) {
+	stored, err := testKeyCipher(t).Encrypt("gemini_api_key", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = testKeyCipher(t).Decrypt("gemini_api_key", stored)
+	if !errors.Is(err, ErrKeyDecrypt) {
+		t.Errorf("Decrypt with wrong master key = %v, want ErrKeyDecrypt", err)
+	}
+}
+
+func TestKeyCipher_BoundToKeyName(t *testing.T) {
+	c := testKeyCipher(t)
+	stored, _ := c.Encrypt("eodhd_api_key", "secret")
+
+	if _, err := c.Decrypt("gemini_api_key", stored); !errors.Is(err, ErrKeyDecrypt) {
+		t.Errorf("Decrypt under another name = %v, want ErrKeyDecrypt", err)
+	}
+}
+
+func TestKeyCipher_MalformedCiphertext(t *testing.T) {
+	c := testKeyCipher(t)
+	for _, stored := range []string{encryptedKeyPrefix + "!!!", encryptedKeyPrefix + "c2hvcnQ="} {
+		if _, err := c.Decrypt("eodhd_api_key", stored); !errors.Is(err, ErrKeyDecrypt) {
+			t.Errorf("Decrypt(%q) = %v, want ErrKeyDecrypt", stored, err)
+		}
+	}
+}
+
+func TestKeyCipher_PlaintextPassesThrough(t *testing.T) {
+	got, err := testKeyCipher(t).Decrypt("eodhd_api_key", "legacy-plain")
+	if err != nil || got != "legacy-plain" {
+		t.Errorf("Decrypt(plaintext) = %q, %v; want unchanged", got, err)
+	}
+}
+
+func TestNewKeyCipher_RejectsShortKey(t *testing.T) {
+	if _, err := NewKeyCipher([]byte("too-short")); err == nil {
+		t.Error("expected error for a short master key")
+	}
+}
+
+func TestSealOpenAPIKey(t *testing.T) {
+	c := testKeyCipher(t)
+
+	t.Run("encryption off stores plaintext", func(t *testing.T) {
+		installKeyCipher(t, c, false)
+		stored, err := SealAPIKey("eodhd_api_key", "secret")
+		if err != nil || stored != "secret" {
+			t.Errorf("SealAPIKey = %q, %v; want plaintext", stored, err)
+		}
+	})
+
+	t.Run("encryption on round-trips", func(t *testing.T) {
+		installKeyCipher(t, c, true)
+		stored, err := SealAPIKey("eodhd_api_key", "secret")
+		if err != nil || !IsEncryptedKey(stored) {
+			t.Fatalf("SealAPIKey = %q, %v; want ciphertext", stored, err)
+		}
+		got, err := OpenAPIKey("eodhd_api_key", stored)
+		if err != nil || got != "secret" {
+			t.Errorf("OpenAPIKey = %q, %v; want %q", got, err, "secret")
+		}
+	})
+
+	t.Run("plaintext still reads with encryption on", func(t *testing.T) {
+		installKeyCipher(t, c, true)
+		got, err := OpenAPIKey("eodhd_api_key", "legacy-plain")
+		if err != nil || got != "legacy-plain" {
+			t.Errorf("OpenAPIKey(plaintext) = %q, %v", got, err)
+		}
+	})
+
+	t.Run("ciphertext without master key fails", func(t *testing.T) {
+		stored, _ := c.Encrypt("eodhd_api_key", "secret")
+		installKeyCipher(t, nil, false)
+		if _, err := OpenAPIKey("eodhd_api_key", stored); err == nil {
+			t.Error("expected error opening ciphertext with no master key")
+		}
+	})
+
+	t.Run("wrong master key fails", func(t *testing.T) {
+		stored, _ := c.Encrypt("eodhd_api_key", "secret")
+		installKeyCipher(t, testKeyCipher(t), true)
+		if _, err := OpenAPIKey("eodhd_api_key", stored); !errors.Is(err, ErrKeyDecrypt) {
+			t.Errorf("OpenAPIKey with wrong master key = %v, want ErrKeyDecrypt", err)
+		}
+	})
+}
+
+func TestLoadMasterKey(t *testing.T) {
+	key := testMasterKey(t)
+	encoded := base64.StdEncoding.EncodeToString(key)
+
+	t.Run("none configured", func(t *testing.T) {
+		t.Setenv("VIRE_MASTER_KEY", "")
+		got, err := LoadMasterKey("")
+		if err != nil || got != nil {
+			t.Errorf("LoadMasterKey = %v, %v; want nil, nil", got, err)
+		}
+	})
+
+	t.Run("env var", func(t *testing.T) {
+		t.Setenv("VIRE_MASTER_KEY", encoded)
+		got, err := LoadMasterKey("")
+		if err != nil || string(got) != string(key) {
+			t.Errorf("LoadMasterKey from env = %v, %v", got, err)
+		}
+	})
+
+	t.Run("key file", func(t *testing.T) {
+		t.Setenv("VIRE_MASTER_KEY", "")
+		path := filepath.Join(t.TempDir(), "master.key")
+		if err := os.WriteFile(path, []byte(encoded+"\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		got, err := LoadMasterKey(path)
+		if err != nil || string(got) != string(key) {
+			t.Errorf("LoadMasterKey from file = %v, %v", got, err)
+		}
+	})
+
+	t.Run("invalid env var", func(t *testing.T) {
+		t.Setenv("VIRE_MASTER_KEY", "not-base64!")
+		if _, err := LoadMasterKey(""); err == nil {
+			t.Error("expected error for invalid VIRE_MASTER_KEY")
+		}
+	})
+}
@@ -2,6 +2,7 @@
 package common
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -50,6 +51,9 @@ func (w *writerAdapter) Write(p []byte) (int, error) {
 	if evt.Error != "" {
 		msg += fmt.Sprintf(" error=%s", evt.Error)
 	}
+	if evt.CorrelationID != "" {
+		msg += fmt.Sprintf(" correlation_id=%s", evt.CorrelationID)
+	}
 	msg += "\n"
 	return w.out.Write([]byte(msg))
 }
@@ -172,8 +176,25 @@ func (l *Logger) AttachLogStore(store writers.ILogStore) {
 	})
 }
 
+// SetLevel changes the level of the logger's writers in place, so every
+// logger sharing them (including correlation-scoped copies) picks it up.
+func (l *Logger) SetLevel(level string) {
+	l.ILogger.WithLevelFromString(level)
+}
+
 // WithCorrelationId returns a new Logger with a correlation ID set.
 // Used by MCP handlers to trace a request through all layers.
 func (l *Logger) WithCorrelationId(id string) *Logger {
 	return &Logger{ILogger: l.ILogger.WithCorrelationId(id)}
 }
+
+// WithRequestID returns a Logger tagged with the request ID carried by ctx,
+// so service logs can be traced back to the HTTP request (and MCP tool call)
+// that caused them. Outside a request it returns l unchanged.
+func (l *Logger) WithRequestID(ctx context.Context) *Logger {
+	id := RequestIDFromContext(ctx)
+	if id == "" {
+		return l
+	}
+	return l.WithCorrelationId(id)
+}
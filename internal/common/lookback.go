@@ -0,0 +1,36 @@
+package common
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LookbackCutoff parses a lookback window such as "90d", "6mo", "1y" or "5yr"
+// and returns the earliest date inside the window relative to now.
+// Units: d (days), w (weeks), m/mo (months), y/yr (years).
+func LookbackCutoff(lookback string, now time.Time) (time.Time, error) {
+	s := strings.ToLower(strings.TrimSpace(lookback))
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	n, err := strconv.Atoi(s[:i])
+	if err != nil || n <= 0 {
+		return time.Time{}, fmt.Errorf("invalid lookback %q: expected a positive count followed by d, w, mo or y (e.g. 6mo, 1y, 5y)", lookback)
+	}
+
+	switch s[i:] {
+	case "d":
+		return now.AddDate(0, 0, -n), nil
+	case "w":
+		return now.AddDate(0, 0, -7*n), nil
+	case "m", "mo":
+		return now.AddDate(0, -n, 0), nil
+	case "y", "yr":
+		return now.AddDate(-n, 0, 0), nil
+	default:
+		return time.Time{}, fmt.Errorf("invalid lookback %q: unknown unit %q (use d, w, mo or y)", lookback, s[i:])
+	}
+}
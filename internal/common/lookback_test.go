@@ -0,0 +1,37 @@
+package common
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLookbackCutoff(t *testing.T) {
+	now := time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"90d", time.Date(2025, 3, 17, 0, 0, 0, 0, time.UTC)},
+		{"2w", time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{"6mo", time.Date(2024, 12, 15, 0, 0, 0, 0, time.UTC)},
+		{"1y", time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)},
+		{"5YR", time.Date(2020, 6, 15, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := LookbackCutoff(tt.in, now)
+		if err != nil {
+			t.Errorf("LookbackCutoff(%q) error: %v", tt.in, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("LookbackCutoff(%q) = %s, want %s", tt.in, got.Format("2006-01-02"), tt.want.Format("2006-01-02"))
+		}
+	}
+
+	for _, bad := range []string{"", "y", "0y", "-1y", "3x", "1 decade"} {
+		if _, err := LookbackCutoff(bad, now); err == nil {
+			t.Errorf("LookbackCutoff(%q) expected error", bad)
+		}
+	}
+}
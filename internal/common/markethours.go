@@ -0,0 +1,153 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	_ "time/tzdata" // embedded zone database; the alpine runtime image ships none
+)
+
+// ExchangeHoursConfig is one exchange's regular session under [market.hours].
+// Open and Close are local "HH:MM" times in Timezone (an IANA name).
+type ExchangeHoursConfig struct {
+	Open     string `toml:"open"`
+	Close    string `toml:"close"`
+	Timezone string `toml:"timezone"`
+}
+
+// MarketConfig holds exchange-level market settings.
+type MarketConfig struct {
+	Hours              map[string]ExchangeHoursConfig `toml:"hours"`                // keyed by EODHD exchange code (AU, US, ...)
+	IndicatorSeriesMax int                            `toml:"indicator_series_max"` // max days of indicator history per get_stock_data request (default 365)
+	MACDFast           int                            `toml:"macd_fast"`            // MACD fast EMA period (default 12)
+	MACDSlow           int                            `toml:"macd_slow"`            // MACD slow EMA period (default 26)
+	MACDSignal         int                            `toml:"macd_signal"`          // MACD signal EMA period (default 9)
+	NewsSentiment      *bool                          `toml:"news_sentiment"`       // default true (nil = true): score held/watchlisted tickers' headlines with Gemini
+}
+
+// GetNewsSentiment returns whether compute_signals scores news sentiment.
+// Default is true. Set news_sentiment = false to disable.
+func (c *MarketConfig) GetNewsSentiment() bool {
+	if c.NewsSentiment == nil {
+		return true
+	}
+	return *c.NewsSentiment
+}
+
+// GetMACDPeriods returns the MACD fast, slow and signal EMA periods. Unless
+// all three are positive and fast < slow, the 12/26/9 default is used.
+func (c *MarketConfig) GetMACDPeriods() (fast, slow, signal int) {
+	if c.MACDFast <= 0 || c.MACDSlow <= 0 || c.MACDSignal <= 0 || c.MACDFast >= c.MACDSlow {
+		return 12, 26, 9
+	}
+	return c.MACDFast, c.MACDSlow, c.MACDSignal
+}
+
+// GetIndicatorSeriesMax returns the most days of indicator history a
+// get_stock_data request may ask for (default 365).
+func (c *MarketConfig) GetIndicatorSeriesMax() int {
+	if c.IndicatorSeriesMax <= 0 {
+		return 365
+	}
+	return c.IndicatorSeriesMax
+}
+
+// DefaultExchangeHours are the regular sessions used when [market.hours]
+// does not override an exchange. Holidays are not modelled.
+var DefaultExchangeHours = map[string]ExchangeHoursConfig{
+	"AU":  {Open: "10:00", Close: "16:00", Timezone: "Australia/Sydney"},
+	"US":  {Open: "09:30", Close: "16:00", Timezone: "America/New_York"},
+	"LSE": {Open: "08:00", Close: "16:30", Timezone: "Europe/London"},
+	"TO":  {Open: "09:30", Close: "16:00", Timezone: "America/Toronto"},
+}
+
+// ExchangeHours is a parsed trading session. Sessions run Monday to Friday.
+type ExchangeHours struct {
+	Open     time.Duration // offset from local midnight
+	Close    time.Duration // offset from local midnight
+	Location *time.Location
+}
+
+// ParseExchangeHours parses a configured session.
+func ParseExchangeHours(c ExchangeHoursConfig) (ExchangeHours, error) {
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return ExchangeHours{}, fmt.Errorf("timezone %q: %w", c.Timezone, err)
+	}
+	open, err := parseClock(c.Open)
+	if err != nil {
+		return ExchangeHours{}, fmt.Errorf("open: %w", err)
+	}
+	closeAt, err := parseClock(c.Close)
+	if err != nil {
+		return ExchangeHours{}, fmt.Errorf("close: %w", err)
+	}
+	if closeAt <= open {
+		return ExchangeHours{}, fmt.Errorf("close %s is not after open %s", c.Close, c.Open)
+	}
+	return ExchangeHours{Open: open, Close: closeAt, Location: loc}, nil
+}
+
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q (want HH:MM)", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// GetExchangeHours returns the parsed session for every exchange: the
+// defaults overlaid with [market.hours]. Invalid entries keep the default,
+// and are dropped when there is no default or it fails to parse too.
+func (c *MarketConfig) GetExchangeHours() map[string]ExchangeHours {
+	merged := make(map[string]ExchangeHoursConfig, len(DefaultExchangeHours)+len(c.Hours))
+	for ex, h := range DefaultExchangeHours {
+		merged[ex] = h
+	}
+	for ex, h := range c.Hours {
+		merged[strings.ToUpper(ex)] = h
+	}
+
+	hours := make(map[string]ExchangeHours, len(merged))
+	for ex, h := range merged {
+		parsed, err := ParseExchangeHours(h)
+		if err != nil {
+			def, ok := DefaultExchangeHours[ex]
+			if !ok {
+				continue
+			}
+			if parsed, err = ParseExchangeHours(def); err != nil {
+				continue
+			}
+		}
+		hours[ex] = parsed
+	}
+	return hours
+}
+
+// IsOpen reports whether t falls within the session on a weekday.
+func (h ExchangeHours) IsOpen(t time.Time) bool {
+	local := t.In(h.Location)
+	if local.Weekday() == time.Saturday || local.Weekday() == time.Sunday {
+		return false
+	}
+	offset := local.Sub(localMidnight(local))
+	return offset >= h.Open && offset < h.Close
+}
+
+// LastClose returns the most recent session close at or before t.
+func (h ExchangeHours) LastClose(t time.Time) time.Time {
+	local := t.In(h.Location)
+	for day := localMidnight(local); ; day = day.AddDate(0, 0, -1) {
+		if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+			continue
+		}
+		if closeAt := day.Add(h.Close); !closeAt.After(local) {
+			return closeAt
+		}
+	}
+}
+
+func localMidnight(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
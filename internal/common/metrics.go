@@ -0,0 +1,114 @@
+package common
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// metricsRegistry holds every Vire metric plus the Go runtime and process
+// collectors. Metrics are always recorded; [server.metrics] enabled only
+// controls whether /metrics exposes them.
+var metricsRegistry = prometheus.NewRegistry()
+
+var (
+	jobsProcessed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "vire_jobs_processed_total",
+		Help: "Jobs executed by the job manager, by job type and outcome (success or error).",
+	}, []string{"job_type", "status"})
+
+	jobDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "vire_job_duration_seconds",
+		Help:    "Job execution time by job type.",
+		Buckets: prometheus.ExponentialBuckets(0.05, 2, 14), // 50ms .. ~7m
+	}, []string{"job_type"})
+
+	jobDequeueLatency = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "vire_job_dequeue_latency_seconds",
+		Help:    "Time a job waited in the queue between becoming eligible and being dequeued.",
+		Buckets: prometheus.ExponentialBuckets(0.1, 2, 14), // 100ms .. ~27m
+	})
+
+	apiRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "vire_api_requests_total",
+		Help: "Outbound API calls by service (eodhd, navexa, gemini) and outcome (success or error).",
+	}, []string{"service", "status"})
+
+	apiDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "vire_api_request_duration_seconds",
+		Help:    "Outbound API call duration by service.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"service"})
+
+	portfolioSyncDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "vire_portfolio_sync_duration_seconds",
+		Help:    "SyncPortfolio duration by outcome (success or error).",
+		Buckets: prometheus.ExponentialBuckets(0.1, 2, 12), // 100ms .. ~3m
+	}, []string{"status"})
+
+	cacheLookups = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "vire_cache_lookups_total",
+		Help: "Cache lookups by cache name and result (hit or miss).",
+	}, []string{"cache", "result"})
+)
+
+func init() {
+	metricsRegistry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		jobsProcessed, jobDuration, jobDequeueLatency,
+		apiRequests, apiDuration,
+		portfolioSyncDuration, cacheLookups,
+	)
+}
+
+// MetricsHandler serves the Prometheus exposition format for all Vire metrics.
+func MetricsHandler() http.Handler {
+	return promhttp.HandlerFor(metricsRegistry, promhttp.HandlerOpts{})
+}
+
+func outcome(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "success"
+}
+
+// ObserveJob records one job execution.
+func ObserveJob(jobType string, start time.Time, err error) {
+	jobsProcessed.WithLabelValues(jobType, outcome(err)).Inc()
+	jobDuration.WithLabelValues(jobType).Observe(time.Since(start).Seconds())
+}
+
+// ObserveJobDequeue records how long a dequeued job waited after eligibleAt.
+func ObserveJobDequeue(eligibleAt time.Time) {
+	if eligibleAt.IsZero() {
+		return
+	}
+	if wait := time.Since(eligibleAt); wait >= 0 {
+		jobDequeueLatency.Observe(wait.Seconds())
+	}
+}
+
+// ObserveAPICall records one outbound API call to service.
+func ObserveAPICall(service string, start time.Time, err error) {
+	apiRequests.WithLabelValues(service, outcome(err)).Inc()
+	apiDuration.WithLabelValues(service).Observe(time.Since(start).Seconds())
+}
+
+// ObservePortfolioSync records one SyncPortfolio run.
+func ObservePortfolioSync(start time.Time, err error) {
+	portfolioSyncDuration.WithLabelValues(outcome(err)).Observe(time.Since(start).Seconds())
+}
+
+// RecordCacheLookup counts a hit or miss on the named cache.
+func RecordCacheLookup(cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	cacheLookups.WithLabelValues(cache, result).Inc()
+}
@@ -23,6 +23,8 @@ type contextKey int
 const (
 	userContextKey       contextKey = iota
 	navexaClientOverride contextKey = iota
+	forceAIRefreshKey    contextKey = iota
+	requestIDKey         contextKey = iota
 )
 
 // WithUserContext stores a UserContext in the request context.
@@ -47,6 +49,17 @@ func NavexaClientFromContext(ctx context.Context) interfaces.NavexaClient {
 	return c
 }
 
+// WithForceAIRefresh marks the request as bypassing cached AI responses.
+func WithForceAIRefresh(ctx context.Context) context.Context {
+	return context.WithValue(ctx, forceAIRefreshKey, true)
+}
+
+// ForceAIRefresh reports whether cached AI responses must be bypassed.
+func ForceAIRefresh(ctx context.Context) bool {
+	force, _ := ctx.Value(forceAIRefreshKey).(bool)
+	return force
+}
+
 // ResolvePortfolios returns user-context portfolios if present, otherwise nil.
 func ResolvePortfolios(ctx context.Context) []string {
 	if uc := UserContextFromContext(ctx); uc != nil && len(uc.Portfolios) > 0 {
@@ -75,3 +88,14 @@ func ResolveDisplayCurrency(ctx context.Context) string {
 	}
 	return "AUD"
 }
+
+// WithRequestID stores the request ID assigned by the HTTP middleware.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+// RequestIDFromContext returns the request ID, or "" outside a request.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
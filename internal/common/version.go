@@ -9,10 +9,11 @@ import (
 )
 
 // SchemaVersion is bumped whenever model structs or computation logic changes
-// invalidate cached derived data. On startup, a mismatch triggers automatic
-// purge of derived data (Portfolio, MarketData, Signals, Reports) while
-// preserving user data (Strategy, KV).
-const SchemaVersion = "16" // field rename: equity_value → equity_holdings_value etc.
+// invalidate cached derived data. On startup, a stored version that is behind
+// is brought forward by the registered schema migrations; without a migration
+// path it triggers a purge of derived data (Portfolio, MarketData, Signals,
+// Reports) while preserving user data (Strategy, KV).
+const SchemaVersion = "17" // file store record IDs: <category>::<sha256 of category+key>
 
 // Version variables injected at build time via ldflags
 var (
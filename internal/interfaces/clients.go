@@ -3,6 +3,8 @@ package interfaces
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/bobmcallan/vire/internal/models"
@@ -16,6 +18,11 @@ type EODHDClient interface {
 	// GetBulkRealTimeQuotes fetches live OHLCV snapshots for multiple tickers in one call.
 	GetBulkRealTimeQuotes(ctx context.Context, tickers []string) (map[string]*models.RealTimeQuote, error)
 
+	// GetRealTimeQuotesBatch fetches live quotes for any number of tickers in
+	// as few calls as possible, keyed by requested ticker. Missing tickers are
+	// omitted; an error means no quote could be fetched at all.
+	GetRealTimeQuotesBatch(ctx context.Context, tickers []string) (map[string]*models.RealTimeQuote, error)
+
 	// GetEOD retrieves end-of-day price data
 	GetEOD(ctx context.Context, ticker string, opts ...EODOption) (*models.EODResponse, error)
 
@@ -77,6 +84,31 @@ func WithLimit(limit int) EODOption {
 	}
 }
 
+// MarketDataProvider is the provider-agnostic subset of market data used for
+// prices and fundamentals. Tickers are always in EODHD format (e.g. "BHP.AU");
+// each provider translates them to its own symbology.
+type MarketDataProvider interface {
+	// Name identifies the provider in logs (e.g. "eodhd", "alphavantage")
+	Name() string
+
+	// GetEOD retrieves end-of-day bars, most recent first
+	GetEOD(ctx context.Context, ticker string, opts ...EODOption) (*models.EODResponse, error)
+
+	// GetRealTimeQuote retrieves a live OHLCV snapshot for a ticker
+	GetRealTimeQuote(ctx context.Context, ticker string) (*models.RealTimeQuote, error)
+
+	// GetFundamentals retrieves fundamental data
+	GetFundamentals(ctx context.Context, ticker string) (*models.Fundamentals, error)
+}
+
+// IsRateLimited reports whether err (or any error it wraps) signals that a
+// provider's rate limit or quota has been exhausted. Provider errors opt in
+// by implementing RateLimited() bool.
+func IsRateLimited(err error) bool {
+	var rl interface{ RateLimited() bool }
+	return errors.As(err, &rl) && rl.RateLimited()
+}
+
 // ASXClient provides access to the ASX Markit Digital API for real-time quotes
 type ASXClient interface {
 	// GetRealTimeQuote retrieves a live price snapshot for an ASX-listed ticker
@@ -115,6 +147,11 @@ type GeminiClient interface {
 	// AnalyzeStock generates AI analysis for a stock
 	AnalyzeStock(ctx context.Context, ticker string, data *models.StockData) (string, error)
 
+	// AnalyzeStream generates analysis for a prompt, sending text chunks as
+	// they arrive. Both channels close when the stream ends or ctx is done;
+	// a failure is sent on the error channel before it closes.
+	AnalyzeStream(ctx context.Context, prompt string) (<-chan string, <-chan error)
+
 	// SummariseFilingPDF uploads a PDF to the Gemini Files API for native PDF
 	// comprehension, sends it with a text prompt, and returns the response.
 	SummariseFilingPDF(ctx context.Context, pdfPath string, prompt string) (string, error)
@@ -122,3 +159,19 @@ type GeminiClient interface {
 	// ActiveModels returns the resolved model map (default + per-task overrides).
 	ActiveModels() map[string]string
 }
+
+// ErrCircuitOpen is returned by a client whose circuit breaker is open: the
+// upstream API has been failing and calls are rejected without a request.
+// Callers should fall back exactly as they would for any other failed call.
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// ContentFilteredError is returned by GeminiClient methods when Gemini blocks
+// the prompt or the response on safety grounds. Reason is Gemini's block or
+// finish reason (e.g. "SAFETY", "PROHIBITED_CONTENT").
+type ContentFilteredError struct {
+	Reason string
+}
+
+func (e *ContentFilteredError) Error() string {
+	return fmt.Sprintf("content filtered by Gemini (%s)", e.Reason)
+}
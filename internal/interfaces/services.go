@@ -3,6 +3,7 @@ package interfaces
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/bobmcallan/vire/internal/models"
@@ -16,11 +17,42 @@ type QuoteService interface {
 	GetRealTimeQuote(ctx context.Context, ticker string) (*models.RealTimeQuote, error)
 }
 
+// FXService provides currency conversion rates
+type FXService interface {
+	// Rate returns how many units of quote one unit of base buys (the
+	// BASEQUOTE forex rate). Divide a quote-currency value by it to express
+	// the value in base.
+	Rate(ctx context.Context, base, quote string) (float64, error)
+}
+
+// Errors wrapped by PortfolioService so callers can tell failures apart
+// with errors.Is rather than by message.
+var (
+	// ErrPortfolioNotFound: no stored portfolio, or none of that name in Navexa.
+	ErrPortfolioNotFound = errors.New("portfolio not found")
+	// ErrNavexaUnavailable: no Navexa client for the request (missing portal
+	// headers or navexa key), or Navexa itself failed.
+	ErrNavexaUnavailable = errors.New("navexa unavailable")
+	// ErrSyncInProgress: the request gave up waiting for another sync.
+	ErrSyncInProgress = errors.New("portfolio sync already in progress")
+	// ErrAlertNotFound: no active alert for that ticker and signal.
+	ErrAlertNotFound = errors.New("alert not active")
+	// ErrHoldingNotFound: the portfolio holds no position in that ticker.
+	ErrHoldingNotFound = errors.New("holding not found")
+)
+
 // PortfolioService manages portfolio operations
 type PortfolioService interface {
 	// SyncPortfolio refreshes portfolio data from Navexa
 	SyncPortfolio(ctx context.Context, name string, force bool) (*models.Portfolio, error)
 
+	// SyncHolding refreshes a single holding's trades and price from Navexa
+	SyncHolding(ctx context.Context, portfolioName, ticker string) (*models.Holding, error)
+
+	// EnsureSynced returns the portfolio, syncing from Navexa only if the
+	// stored copy is older than maxAge
+	EnsureSynced(ctx context.Context, name string, maxAge time.Duration) (*models.Portfolio, error)
+
 	// GetPortfolio retrieves a portfolio with current data
 	GetPortfolio(ctx context.Context, name string) (*models.Portfolio, error)
 
@@ -30,6 +62,18 @@ type PortfolioService interface {
 	// ReviewPortfolio generates a portfolio review with signals
 	ReviewPortfolio(ctx context.Context, name string, options ReviewOptions) (*models.PortfolioReview, error)
 
+	// StreamPortfolioReview runs the same review without the blocking AI
+	// summary and streams the summary from Gemini: text chunks, then the
+	// terminal error (nil on success)
+	StreamPortfolioReview(ctx context.Context, name string, options ReviewOptions) (*models.PortfolioReview, <-chan string, <-chan error, error)
+
+	// ListActiveAlerts returns the alerts raised by the latest review that
+	// are still active, acknowledged or not
+	ListActiveAlerts(ctx context.Context, name string) ([]models.AlertState, error)
+
+	// AcknowledgeAlert suppresses an active alert until its condition clears
+	AcknowledgeAlert(ctx context.Context, name, ticker, signal string) (*models.AlertState, error)
+
 	// ReviewWatchlist generates a review with signals for watchlist tickers
 	ReviewWatchlist(ctx context.Context, name string, options ReviewOptions) (*models.WatchlistReview, error)
 
@@ -43,12 +87,65 @@ type PortfolioService interface {
 	// From/To zero values default to inception and yesterday respectively.
 	GetDailyGrowth(ctx context.Context, name string, opts GrowthOptions) ([]models.GrowthDataPoint, error)
 
+	// GetGrowthChart renders the daily growth series as a PNG line chart
+	GetGrowthChart(ctx context.Context, name string, opts GrowthOptions) ([]byte, error)
+
+	// FXDivisor returns the divisor converting values in currency into p's
+	// base currency: the rate recorded at sync, else a live rate, else 1
+	FXDivisor(ctx context.Context, p *models.Portfolio, currency string) float64
+
 	// GetStockTimeline returns daily value data points for a single holding within a portfolio.
 	GetStockTimeline(ctx context.Context, portfolioName, ticker string, from, to time.Time) ([]models.StockTimelinePoint, error)
 
 	// GetPortfolioIndicators computes technical indicators on the daily portfolio value time series.
 	GetPortfolioIndicators(ctx context.Context, name string) (*models.PortfolioIndicators, error)
 
+	// SnapshotPortfolio records today's headline values for the portfolio,
+	// replacing any snapshot already taken today. It writes nothing (nil
+	// snapshot) when the stored portfolio was last synced before today.
+	SnapshotPortfolio(ctx context.Context, name string) (*models.DailyPortfolioSnapshot, error)
+
+	// GetPortfolioHistory returns the stored daily snapshots between from and
+	// to (zero = unbounded), oldest first.
+	GetPortfolioHistory(ctx context.Context, name string, from, to time.Time) ([]models.DailyPortfolioSnapshot, error)
+
+	// GetPortfolioTimeline returns the daily value series from the stored
+	// snapshots, recomputing only days before the first snapshot.
+	GetPortfolioTimeline(ctx context.Context, name string, opts GrowthOptions) ([]models.GrowthDataPoint, error)
+
+	// TimeWeightedReturn chains sub-period returns between cash-flow ledger
+	// contributions/withdrawals; from/to are YYYY-MM-DD (empty = inception/today).
+	TimeWeightedReturn(ctx context.Context, portfolioName, from, to string) (float64, error)
+
+	// GetDataCompleteness scores how much of each holding's data is present and fresh.
+	GetDataCompleteness(ctx context.Context, name string) (*models.DataCompleteness, error)
+
+	// CalculateCGT reports capital gains realized in a financial year (e.g. "2023-2024"),
+	// split into discountable and non-discountable gains.
+	CalculateCGT(ctx context.Context, portfolioName string, financialYear string) (*models.CGTReport, error)
+
+	// RealizedGainTimeline reports cumulative realized gain after each date a sell changed it.
+	RealizedGainTimeline(ctx context.Context, portfolioName string) (*models.RealizedGainTimeline, error)
+
+	// SimulateTrade projects the cash and position impact of a buy or sell,
+	// including brokerage from the configured fee model.
+	SimulateTrade(ctx context.Context, portfolioName string, trade models.SimulatedTrade) (*models.TradeSimulation, error)
+
+	// SectorAllocation groups open holdings by sector with market value and weight
+	SectorAllocation(ctx context.Context, portfolioName string) (*models.SectorBreakdown, error)
+
+	// FeeSummary reports brokerage per holding and for the portfolio, with
+	// fees as a percentage of invested capital. Rebates reduce the totals.
+	FeeSummary(ctx context.Context, portfolioName string) (*models.FeeSummary, error)
+
+	// RebalanceSuggestions returns the trades that bring holdings back within
+	// the drift tolerance of the strategy's target weights
+	RebalanceSuggestions(ctx context.Context, portfolioName string) (*models.RebalancePlan, error)
+
+	// ProjectValue runs a Monte Carlo projection of portfolio value over the
+	// next years, returning p10/p50/p90 bands per year
+	ProjectValue(ctx context.Context, portfolioName string, years int, simulations int) (*models.ProjectionResult, error)
+
 	// RefreshTodaySnapshot writes today's timeline snapshot from the cached portfolio.
 	// Does not require a Navexa client — reads from storage only. Safe for background use.
 	RefreshTodaySnapshot(ctx context.Context, name string) error
@@ -84,8 +181,10 @@ type GrowthOptions struct {
 
 // ReviewOptions configures portfolio review
 type ReviewOptions struct {
-	FocusSignals []string // Signal types to focus on
-	IncludeNews  bool     // Include news in analysis
+	FocusSignals    []string // Signal types to focus on
+	IncludeNews     bool     // Include news in analysis
+	BenchmarkTicker string   // EODHD ticker to compare returns against (e.g. "STW.AU"); empty disables
+	SkipSummary     bool     // Leave Summary empty (the caller streams it separately)
 }
 
 // MarketService handles market data operations
@@ -118,6 +217,10 @@ type MarketService interface {
 	CollectTimeline(ctx context.Context, ticker string, force bool) error
 	CollectNewsIntelligence(ctx context.Context, ticker string, force bool) error
 
+	// CompactEOD downsamples stored EOD bars older than keepDaily to weekly
+	// bars and returns how many bars were removed.
+	CompactEOD(ctx context.Context, ticker string, keepDaily time.Duration) (int, error)
+
 	// ReadFiling retrieves the text content of a filing PDF by ticker and document key.
 	ReadFiling(ctx context.Context, ticker, documentKey string) (*models.FilingContent, error)
 
@@ -130,6 +233,10 @@ type MarketService interface {
 	// ScreenStocks filters stocks by quantitative criteria
 	ScreenStocks(ctx context.Context, options ScreenOptions) ([]*models.ScreenCandidate, error)
 
+	// ScreenAndScore runs an EODHD screener query and ranks the results by a
+	// weighted composite of valuation, momentum and dividend scores
+	ScreenAndScore(ctx context.Context, options models.ScreenerOptions, weights models.ScoreWeights) ([]*models.ScoredResult, error)
+
 	// FunnelScreen runs a 3-stage funnel: EODHD screener (100) -> fundamental refinement (25) -> technical scoring (5)
 	FunnelScreen(ctx context.Context, options FunnelOptions) (*models.FunnelResult, error)
 
@@ -141,6 +248,10 @@ type MarketService interface {
 
 	// ScanFields returns the available scan field definitions
 	ScanFields() *models.ScanFieldsResponse
+
+	// CorrelationMatrix computes pairwise daily-return correlations over a
+	// trailing window of trading days; pairs with too little shared history are omitted
+	CorrelationMatrix(ctx context.Context, tickers []string, window int) (map[string]map[string]float64, error)
 }
 
 // StockDataInclude specifies what to include in stock data
@@ -149,6 +260,11 @@ type StockDataInclude struct {
 	Fundamentals bool
 	Signals      bool
 	News         bool
+	Lookback     string // Chart/signal window, e.g. "6mo", "1y", "5y" ("" = latest 200 bars)
+
+	// IndicatorDays requests a trailing daily series of RSI, SMA50/200 and
+	// MACD (0 = none). Capped to the bars available and the configured maximum.
+	IndicatorDays int
 }
 
 // SnipeOptions configures snipe buy search
@@ -159,8 +275,13 @@ type SnipeOptions struct {
 	Sector      string                    // Optional sector filter
 	IncludeNews bool                      // Include news sentiment analysis
 	Strategy    *models.PortfolioStrategy // Optional portfolio strategy for filtering/scoring
+	Thresholds  models.SnipeThresholds    // Per-request overrides of the configured snipe thresholds
 }
 
+// ErrInvalidFunnelStage wraps FunnelScreen errors caused by a malformed
+// filter stage (no filters, unknown field or invalid operator).
+var ErrInvalidFunnelStage = errors.New("invalid funnel filter stage")
+
 // FunnelOptions configures the multi-stage funnel screen
 type FunnelOptions struct {
 	Exchange    string                    // Exchange to scan (e.g., "AU", "US")
@@ -168,6 +289,10 @@ type FunnelOptions struct {
 	Sector      string                    // Optional sector filter
 	IncludeNews bool                      // Include news sentiment
 	Strategy    *models.PortfolioStrategy // Optional portfolio strategy
+
+	// FilterStages, when set, replace the built-in refinement and scoring
+	// stages: screener results are narrowed through each stage in order.
+	FilterStages []models.FunnelFilterStage
 }
 
 // ScreenOptions configures the stock screen
@@ -191,6 +316,29 @@ type ReportService interface {
 
 	// GenerateTickerReport refreshes a single ticker's report within an existing portfolio report
 	GenerateTickerReport(ctx context.Context, portfolioName, ticker string) (*models.PortfolioReport, error)
+
+	// GeneratePDF renders a stored report (latest when reportID is empty) as a PDF
+	GeneratePDF(ctx context.Context, portfolioName, reportID string) ([]byte, error)
+
+	// SearchReports finds report sections containing every query term, ranked by relevance
+	SearchReports(ctx context.Context, query string, opts ReportSearchOptions) ([]models.ReportSearchResult, error)
+
+	// FormatAlertsMarkdown renders active, unacknowledged alerts as a Markdown
+	// digest grouped by severity, suitable for Slack or Discord
+	FormatAlertsMarkdown(ctx context.Context, portfolioName string) (string, error)
+
+	// ExportTradesForImport writes the portfolio's trades as another platform's import CSV
+	ExportTradesForImport(ctx context.Context, portfolioName, format string) (*models.TradeImportExport, error)
+}
+
+// ErrUnsupportedExportFormat is returned by ReportService.ExportTradesForImport
+// for a format it cannot write.
+var ErrUnsupportedExportFormat = errors.New("unsupported export format")
+
+// ReportSearchOptions configures report searches
+type ReportSearchOptions struct {
+	Portfolio string // Optional: restrict to one portfolio's report
+	Limit     int    // Maximum results (default: 20, max: 100)
 }
 
 // ReportOptions configures report generation
@@ -211,6 +359,10 @@ type StrategyService interface {
 	// DeleteStrategy removes a strategy
 	DeleteStrategy(ctx context.Context, portfolioName string) error
 
+	// ApplyStrategyToPortfolios saves one strategy to each named portfolio.
+	// All portfolios must exist; nothing is saved if any is missing.
+	ApplyStrategyToPortfolios(ctx context.Context, names []string, strategy models.PortfolioStrategy) ([]models.StrategyWarning, error)
+
 	// ValidateStrategy checks for unrealistic goals and internal contradictions
 	ValidateStrategy(ctx context.Context, strategy *models.PortfolioStrategy) []models.StrategyWarning
 }
@@ -221,8 +373,18 @@ type SignalService interface {
 	// When force is true, signals are recomputed regardless of freshness.
 	DetectSignals(ctx context.Context, tickers []string, signalTypes []string, force bool) ([]*models.TickerSignals, error)
 
-	// ComputeSignals calculates all signals for a ticker
+	// ComputeSignals calculates all signals for a ticker, with the RSI period
+	// of the strategy of the portfolio that holds it (the default period
+	// otherwise, or when there are too few bars).
 	ComputeSignals(ctx context.Context, ticker string, marketData *models.MarketData) (*models.TickerSignals, error)
+
+	// ScoreNewsSentiment fills in the signals' news sentiment from the ticker's
+	// recent headlines when its news is fresh. Failures leave it unscored.
+	ScoreNewsSentiment(ctx context.Context, sigs *models.TickerSignals, md *models.MarketData)
+
+	// BacktestThresholds replays entry-signal logic over stored EOD bars and
+	// reports how often each signal was followed by a gain over horizon trading days.
+	BacktestThresholds(ctx context.Context, ticker string, horizon int) (*models.SignalBacktest, error)
 }
 
 // WatchlistService manages portfolio watchlist operations
@@ -244,6 +406,13 @@ type WatchlistService interface {
 
 	// RemoveItem removes a stock from the watchlist by ticker
 	RemoveItem(ctx context.Context, portfolioName, ticker string) (*models.PortfolioWatchlist, error)
+
+	// CheckTargets compares current prices with item target prices and records a
+	// watchlist_target_hit event for each target crossed since the previous check
+	CheckTargets(ctx context.Context, portfolioName string) ([]models.WatchlistEvent, error)
+
+	// ListEvents returns recorded watchlist events for a portfolio, newest first
+	ListEvents(ctx context.Context, portfolioName string) ([]models.WatchlistEvent, error)
 }
 
 // HoldingNoteService manages per-holding analyst notes
@@ -262,6 +431,29 @@ type HoldingNoteService interface {
 
 	// RemoveNote removes a note by ticker
 	RemoveNote(ctx context.Context, portfolioName, ticker string) (*models.PortfolioHoldingNotes, error)
+
+	// GetAnnotations returns a portfolio's holding annotations keyed by upper-case ticker
+	GetAnnotations(ctx context.Context, portfolioName string) (map[string]*models.HoldingAnnotation, error)
+
+	// SetHoldingNote sets a holding's user note; an empty note clears it
+	SetHoldingNote(ctx context.Context, portfolioName, ticker, note string) (*models.HoldingAnnotation, error)
+
+	// SetHoldingTags replaces a holding's tags; an empty list clears them
+	SetHoldingTags(ctx context.Context, portfolioName, ticker string, tags []string) (*models.HoldingAnnotation, error)
+}
+
+// ErrWebhooksNotConfigured is returned by NotificationService.SendTestEvent
+// when no webhook URLs are configured.
+var ErrWebhooksNotConfigured = errors.New("no webhook URLs configured")
+
+// NotificationService delivers portfolio events to outbound webhooks
+type NotificationService interface {
+	// Notify queues an event for delivery to every configured webhook and
+	// returns without waiting; failed deliveries are retried, then dead-lettered
+	Notify(ctx context.Context, event models.NotificationEvent)
+
+	// SendTestEvent delivers a sample event synchronously and reports the outcome per URL
+	SendTestEvent(ctx context.Context) ([]models.WebhookDelivery, error)
 }
 
 // AssetSetService manages non-equity asset sets (property, crypto, etc.)
@@ -320,6 +512,9 @@ type PlanService interface {
 	// CheckPlanDeadlines marks overdue time-based items as expired, returns expired items
 	CheckPlanDeadlines(ctx context.Context, portfolioName string) ([]models.PlanItem, error)
 
+	// CheckTrailingStops ratchets trailing_stop peaks up to the current price, returns triggered items
+	CheckTrailingStops(ctx context.Context, portfolioName string) ([]models.PlanItem, error)
+
 	// ValidatePlanAgainstStrategy checks plan items against portfolio strategy
 	ValidatePlanAgainstStrategy(ctx context.Context, plan *models.PortfolioPlan, strategy *models.PortfolioStrategy) []models.StrategyWarning
 }
@@ -352,8 +547,18 @@ type CashFlowService interface {
 
 	// UpdateAccount updates account properties (type, is_transactional)
 	UpdateAccount(ctx context.Context, portfolioName string, accountName string, update models.CashAccountUpdate) (*models.CashFlowLedger, error)
+
+	// SetBrokerBalance records a broker-reported cash balance for a date (one per day)
+	SetBrokerBalance(ctx context.Context, portfolioName string, balance models.BrokerBalance) (*models.CashFlowLedger, error)
+
+	// Reconcile compares the ledger- and trade-derived cash balance against broker-reported balances
+	Reconcile(ctx context.Context, portfolioName string) (*models.CashReconciliation, error)
 }
 
+// ErrInvalidBrokerBalance is returned by CashFlowService.SetBrokerBalance for
+// a balance without a date or with a non-finite amount.
+var ErrInvalidBrokerBalance = errors.New("invalid broker balance")
+
 // TradeService manages trades and position derivation for manual/snapshot portfolios.
 type TradeService interface {
 	// GetTradeBook retrieves the trade book for a portfolio
@@ -3,6 +3,8 @@ package interfaces
 
 import (
 	"context"
+	"errors"
+	"io"
 	"time"
 
 	"github.com/bobmcallan/vire/internal/models"
@@ -40,6 +42,19 @@ type StorageManager interface {
 	// Returns count of deleted reports.
 	PurgeReports(ctx context.Context) (int, error)
 
+	// Backup writes all user data records (portfolios, strategies, plans,
+	// watchlists, reports, ...), market data, signals and stock index entries
+	// to w as a versioned archive. Accounts, credentials, system KV, jobs,
+	// logs and stored files are not included.
+	Backup(ctx context.Context, w io.Writer) (*models.BackupManifest, error)
+
+	// Restore replaces the backed-up tables with the records of an archive
+	// written by Backup, removing records created since. Records are staged
+	// as the archive streams in and swapped in one transaction, so a corrupt
+	// archive or failed write changes nothing. Archives from another schema
+	// version are refused.
+	Restore(ctx context.Context, r io.Reader) (*models.BackupManifest, error)
+
 	// Lifecycle
 	Close() error
 }
@@ -62,10 +77,16 @@ type InternalStore interface {
 	// System key-value (non-user-scoped)
 	GetSystemKV(ctx context.Context, key string) (string, error)
 	SetSystemKV(ctx context.Context, key, value string) error
+	DeleteSystemKV(ctx context.Context, key string) error
+	ListSystemKV(ctx context.Context, prefix string) (map[string]string, error) // entries whose key starts with prefix
 
 	Close() error
 }
 
+// ErrRecordNotFound is returned (wrapped) by UserDataStore.Get when no
+// record has that user, subject and key.
+var ErrRecordNotFound = errors.New("user record not found")
+
 // UserDataStore manages all user domain data via generic records.
 type UserDataStore interface {
 	Get(ctx context.Context, userID, subject, key string) (*models.UserRecord, error)
@@ -73,16 +94,24 @@ type UserDataStore interface {
 	Delete(ctx context.Context, userID, subject, key string) error
 	List(ctx context.Context, userID, subject string) ([]*models.UserRecord, error)
 	Query(ctx context.Context, userID, subject string, opts QueryOptions) ([]*models.UserRecord, error)
+	Count(ctx context.Context, userID, subject string, opts QueryOptions) (int, error) // records matching opts, ignoring Limit and Offset
 	DeleteBySubject(ctx context.Context, subject string) (int, error)
 	Close() error
 }
 
 // QueryOptions configures query behavior for UserDataStore.
 type QueryOptions struct {
-	Limit   int
-	OrderBy string // "datetime_desc" (default), "datetime_asc"
+	Limit     int
+	Offset    int    // records to skip before the first one returned
+	OrderBy   string // "datetime_desc" (default), "datetime_asc"; ties are ordered by key
+	Key       string // optional: only the record with this key (case-insensitive)
+	KeyPrefix string // optional: only records whose key starts with this (case-sensitive)
 }
 
+// ErrMarketDataNotFound is returned (wrapped) by
+// MarketDataStorage.GetMarketData when no record exists for the ticker.
+var ErrMarketDataNotFound = errors.New("market data not found")
+
 // MarketDataStorage handles market data persistence
 type MarketDataStorage interface {
 	// GetMarketData retrieves market data for a ticker
@@ -126,6 +155,7 @@ type FileStore interface {
 	GetFile(ctx context.Context, category, key string) ([]byte, string, error) // data, contentType, error
 	DeleteFile(ctx context.Context, category, key string) error
 	HasFile(ctx context.Context, category, key string) (bool, error)
+	StatFile(ctx context.Context, category, key string) (*models.FileMeta, error) // metadata only, no content
 }
 
 // FeedbackStore manages MCP feedback entries.
@@ -170,6 +200,10 @@ type ChangelogListOptions struct {
 	PerPage int
 }
 
+// ErrOAuthClientNotFound is returned (wrapped) by OAuthStore.GetClient when
+// no client has that ID.
+var ErrOAuthClientNotFound = errors.New("oauth client not found")
+
 // OAuthStore manages OAuth 2.1 clients, authorization codes, refresh tokens, and sessions.
 type OAuthStore interface {
 	// Clients
@@ -223,7 +257,10 @@ type JobQueueStore interface {
 	ListByBatchID(ctx context.Context, batchID string) ([]*models.Job, error)
 	CountPending(ctx context.Context) (int, error)
 	HasPendingJob(ctx context.Context, jobType, ticker string) (bool, error)
+	HasActiveJob(ctx context.Context, jobType, ticker string) (bool, error) // pending or running
 	PurgeCompleted(ctx context.Context, olderThan time.Time) (int, error)
 	CancelByTicker(ctx context.Context, ticker string) (int, error)
 	ResetRunningJobs(ctx context.Context) (int, error)
+	ListDeadLetter(ctx context.Context, limit int) ([]*models.Job, error)
+	RequeueDeadLetter(ctx context.Context, id string) (*models.Job, error) // nil job when id is not dead-lettered
 }
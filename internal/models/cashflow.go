@@ -89,13 +89,54 @@ func (tx CashTransaction) NetDeployedImpact() float64 {
 
 // CashFlowLedger stores all cash accounts and transactions for a portfolio.
 type CashFlowLedger struct {
-	PortfolioName string            `json:"portfolio_name"`
-	Version       int               `json:"version"`
-	Accounts      []CashAccount     `json:"accounts"`
-	Transactions  []CashTransaction `json:"transactions"`
-	Notes         string            `json:"notes,omitempty"`
-	CreatedAt     time.Time         `json:"created_at"`
-	UpdatedAt     time.Time         `json:"updated_at"`
+	PortfolioName  string            `json:"portfolio_name"`
+	Version        int               `json:"version"`
+	Accounts       []CashAccount     `json:"accounts"`
+	Transactions   []CashTransaction `json:"transactions"`
+	BrokerBalances []BrokerBalance   `json:"broker_balances,omitempty"` // broker-reported cash, for reconciliation
+	Notes          string            `json:"notes,omitempty"`
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+}
+
+// BrokerBalance is the cash balance a broker reported on a date (e.g. from a statement).
+type BrokerBalance struct {
+	Date    time.Time `json:"date"`
+	Balance float64   `json:"balance"`
+	Notes   string    `json:"notes,omitempty"`
+}
+
+// CashReconciliation compares the cash balance derived from the ledger and trades
+// against broker-reported balances.
+type CashReconciliation struct {
+	PortfolioName  string            `json:"portfolio_name"`
+	Contributions  float64           `json:"contributions"`            // positive contribution amounts
+	Withdrawals    float64           `json:"withdrawals"`              // negative contribution amounts, as a positive total
+	Buys           float64           `json:"buys"`                     // buy cost including fees
+	Sells          float64           `json:"sells"`                    // sell proceeds net of fees
+	Dividends      float64           `json:"dividends"`                // net dividend amounts
+	Fees           float64           `json:"fees"`                     // fee amounts, as a positive total
+	Other          float64           `json:"other"`                    // transfers and other categories (net)
+	DerivedBalance float64           `json:"derived_balance"`          // contributions - withdrawals - buys + sells + dividends - fees + other
+	BrokerBalance  *BrokerBalance    `json:"broker_balance,omitempty"` // latest broker-reported balance
+	Difference     float64           `json:"difference"`               // latest broker balance minus derived balance at that date
+	TradesIncluded bool              `json:"trades_included"`          // false when the portfolio's trades could not be loaded
+	Reconciled     bool              `json:"reconciled"`
+	Discrepancies  []CashDiscrepancy `json:"discrepancies"`
+}
+
+// CashDiscrepancy is a gap that opened between two broker statements. Unexplained
+// is the change in (broker - derived) since the previous statement: positive means
+// the broker received cash the ledger does not record (e.g. a missing contribution),
+// negative means cash left that the ledger does not record (e.g. a missing fee).
+type CashDiscrepancy struct {
+	Since          *time.Time `json:"since,omitempty"` // previous statement date; nil for the first statement
+	Date           time.Time  `json:"date"`
+	BrokerBalance  float64    `json:"broker_balance"`
+	DerivedBalance float64    `json:"derived_balance"`
+	Difference     float64    `json:"difference"`  // broker - derived at Date
+	Unexplained    float64    `json:"unexplained"` // gap introduced between Since and Date
+	Description    string     `json:"description"`
 }
 
 // CashFlowSummary contains server-computed aggregate totals for the ledger.
@@ -0,0 +1,79 @@
+package models
+
+import "math"
+
+// FeeModelType selects how brokerage is charged on a simulated trade.
+type FeeModelType string
+
+const (
+	FeeModelFlat       FeeModelType = "flat"       // Fixed fee per trade
+	FeeModelPercentage FeeModelType = "percentage" // Percent of trade value, subject to a minimum
+	FeeModelTiered     FeeModelType = "tiered"     // Fee set by the tier the trade value falls in
+)
+
+// FeeTier is one band of a tiered fee schedule. A trade falls in the first
+// tier whose UpTo is at least its value; UpTo 0 means no upper bound.
+type FeeTier struct {
+	UpTo float64 `json:"up_to"`
+	Flat float64 `json:"flat"` // Fixed component
+	Pct  float64 `json:"pct"`  // Percent of trade value
+}
+
+// FeeModel is a brokerage schedule applied to simulated trades.
+type FeeModel struct {
+	Type  FeeModelType `json:"type"`
+	Flat  float64      `json:"flat"`  // Fee per trade (flat)
+	Pct   float64      `json:"pct"`   // Percent of trade value (percentage)
+	Min   float64      `json:"min"`   // Minimum fee (percentage, tiered)
+	Tiers []FeeTier    `json:"tiers"` // Ascending by UpTo (tiered)
+}
+
+// Fee returns the brokerage for a trade of the given value. An empty or
+// unknown model charges nothing.
+func (m FeeModel) Fee(value float64) float64 {
+	value = math.Abs(value)
+	var fee float64
+	switch m.Type {
+	case FeeModelFlat:
+		return m.Flat
+	case FeeModelPercentage:
+		fee = value * m.Pct / 100
+	case FeeModelTiered:
+		for _, t := range m.Tiers {
+			if t.UpTo <= 0 || value <= t.UpTo {
+				fee = t.Flat + value*t.Pct/100
+				break
+			}
+		}
+	default:
+		return 0
+	}
+	return math.Max(fee, m.Min)
+}
+
+// SimulatedTrade is the input to a trade simulation. A zero price uses the
+// holding's current price.
+type SimulatedTrade struct {
+	Ticker string  `json:"ticker"`
+	Action string  `json:"action"` // "buy" or "sell"
+	Units  float64 `json:"units"`
+	Price  float64 `json:"price,omitempty"`
+}
+
+// TradeSimulation is the projected effect of a single trade on a portfolio.
+type TradeSimulation struct {
+	PortfolioName    string   `json:"portfolio_name"`
+	Ticker           string   `json:"ticker"`
+	Action           string   `json:"action"` // "buy" or "sell"
+	Units            float64  `json:"units"`
+	Price            float64  `json:"price"`
+	TradeValue       float64  `json:"trade_value"`
+	Fee              float64  `json:"fee"`
+	CashImpact       float64  `json:"cash_impact"` // Signed change in available cash, fee included
+	CashBefore       float64  `json:"cash_before"`
+	CashAfter        float64  `json:"cash_after"`
+	UnitsAfter       float64  `json:"units_after"`
+	WeightAfterPct   float64  `json:"weight_after_pct"`
+	FeeModel         FeeModel `json:"fee_model"`
+	InsufficientCash bool     `json:"insufficient_cash,omitempty"`
+}
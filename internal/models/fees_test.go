@@ -0,0 +1,42 @@
+package models
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFeeModel_Fee(t *testing.T) {
+	tiered := FeeModel{
+		Type: FeeModelTiered,
+		Tiers: []FeeTier{
+			{UpTo: 1000, Flat: 5},
+			{UpTo: 10000, Flat: 10},
+			{Pct: 0.1},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		model FeeModel
+		value float64
+		want  float64
+	}{
+		{"no model", FeeModel{}, 5000, 0},
+		{"unknown type", FeeModel{Type: "bogus", Flat: 10}, 5000, 0},
+		{"flat", FeeModel{Type: FeeModelFlat, Flat: 9.50}, 5000, 9.50},
+		{"percentage", FeeModel{Type: FeeModelPercentage, Pct: 0.1}, 50000, 50},
+		{"percentage below minimum", FeeModel{Type: FeeModelPercentage, Pct: 0.1, Min: 10}, 5000, 10},
+		{"sell value is absolute", FeeModel{Type: FeeModelPercentage, Pct: 0.1}, -50000, 50},
+		{"tier 1", tiered, 800, 5},
+		{"tier 1 upper bound", tiered, 1000, 5},
+		{"tier 2", tiered, 5000, 10},
+		{"unbounded tier", tiered, 50000, 50},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.model.Fee(tt.value); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Fee(%.2f) = %.4f, want %.4f", tt.value, got, tt.want)
+			}
+		})
+	}
+}
@@ -53,6 +53,7 @@ type HoldingNote struct {
 	SignalOverrides  string           `json:"signal_overrides,omitempty"`  // Context for signal interpretation
 	Notes            string           `json:"notes,omitempty"`             // Free-form notes
 	StaleDays        int              `json:"stale_days,omitempty"`        // Days until stale (default 90)
+	AlertsMuted      *bool            `json:"alerts_muted,omitempty"`      // Suppress review alerts for this holding (nil = not muted)
 	CreatedAt        time.Time        `json:"created_at"`
 	ReviewedAt       time.Time        `json:"reviewed_at"` // When note was last reviewed/updated
 	UpdatedAt        time.Time        `json:"updated_at"`
@@ -67,6 +68,12 @@ func (n *HoldingNote) IsStale() bool {
 	return time.Since(n.ReviewedAt) > time.Duration(ttl)*24*time.Hour
 }
 
+// Muted returns true if review alerts are muted for this holding.
+// Safe to call on a nil note.
+func (n *HoldingNote) Muted() bool {
+	return n != nil && n.AlertsMuted != nil && *n.AlertsMuted
+}
+
 // DeriveSignalConfidence returns the signal confidence level based on asset type
 // and liquidity profile.
 //
@@ -125,3 +132,15 @@ func (h *PortfolioHoldingNotes) NoteMap() map[string]*HoldingNote {
 	}
 	return m
 }
+
+// HoldingAnnotation is a user's own note and tags on one holding. Stored per
+// portfolio and ticker in UserDataStore (subject="holding_annotations"),
+// apart from the synced portfolio, so syncs never overwrite it and it
+// outlives the position.
+type HoldingAnnotation struct {
+	PortfolioName string    `json:"portfolio_name"`
+	Ticker        string    `json:"ticker"`
+	Note          string    `json:"note,omitempty"`
+	Tags          []string  `json:"tags,omitempty"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
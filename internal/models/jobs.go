@@ -36,7 +36,7 @@ type Job struct {
 	Ticker      string    `json:"ticker"`
 	BatchID     string    `json:"batch_id,omitempty"`
 	Priority    int       `json:"priority"`
-	Status      string    `json:"status"` // "pending", "running", "completed", "failed", "cancelled"
+	Status      string    `json:"status"` // "pending", "running", "completed", "failed", "dead_letter", "cancelled"
 	CreatedAt   time.Time `json:"created_at"`
 	StartedAt   time.Time `json:"started_at"`
 	CompletedAt time.Time `json:"completed_at"`
@@ -44,6 +44,9 @@ type Job struct {
 	Attempts    int       `json:"attempts"`
 	MaxAttempts int       `json:"max_attempts"`
 	DurationMS  int64     `json:"duration_ms"`
+	// NextAttemptAt holds a re-queued failed job back until its retry
+	// backoff has elapsed. Zero means the job is eligible immediately.
+	NextAttemptAt time.Time `json:"next_attempt_at"`
 }
 
 // Job type constants
@@ -59,6 +62,9 @@ const (
 	JobTypeCollectNewsIntel       = "collect_news_intel"
 	JobTypeComputeSignals         = "compute_signals"
 	JobTypeCollectLivePrices      = "collect_live_prices"
+	JobTypeGenerateReport         = "generate_report"    // Ticker = portfolio name
+	JobTypeCompactEOD             = "compact_eod"        // Ticker empty: compacts every stock index ticker
+	JobTypeSnapshotPortfolio      = "snapshot_portfolio" // Ticker = portfolio name
 )
 
 // Job status constants
@@ -68,6 +74,9 @@ const (
 	JobStatusCompleted = "completed"
 	JobStatusFailed    = "failed"
 	JobStatusCancelled = "cancelled"
+	// JobStatusDeadLetter holds jobs that failed on every allowed attempt.
+	// They keep their final error and stay out of the queue until requeued.
+	JobStatusDeadLetter = "dead_letter"
 )
 
 // Default priorities (higher = processed first)
@@ -84,6 +93,9 @@ const (
 	PriorityCollectTimeline        = 2
 	PriorityCollectLivePrices      = 11 // Higher than EOD (10) — live data is more urgent
 	PriorityNewStock               = 15 // New stocks get elevated priority
+	PriorityGenerateReport         = 6  // Scheduled reports run after core data collection
+	PriorityCompactEOD             = 1  // Storage housekeeping runs when the queue is otherwise idle
+	PrioritySnapshotPortfolio      = 6  // Same level as scheduled reports; reads stored data only
 )
 
 // DefaultPriority returns the default priority for a job type.
@@ -111,6 +123,12 @@ func DefaultPriority(jobType string) int {
 		return PriorityComputeSignals
 	case JobTypeCollectLivePrices:
 		return PriorityCollectLivePrices
+	case JobTypeGenerateReport:
+		return PriorityGenerateReport
+	case JobTypeCompactEOD:
+		return PriorityCompactEOD
+	case JobTypeSnapshotPortfolio:
+		return PrioritySnapshotPortfolio
 	default:
 		return 0
 	}
@@ -154,3 +172,22 @@ type JobEvent struct {
 	Timestamp time.Time `json:"timestamp"`
 	QueueSize int       `json:"queue_size"` // Current pending count
 }
+
+// JobQueueExport is a diagnostic snapshot of the job queue with derived stats.
+type JobQueueExport struct {
+	ExportedAt   time.Time      `json:"exported_at"`
+	TotalJobs    int            `json:"total_jobs"`
+	StatusCounts map[string]int `json:"status_counts"` // status -> job count
+	TypeStats    []JobTypeStats `json:"type_stats"`    // sorted by job type
+	Jobs         []*Job         `json:"jobs"`
+}
+
+// JobTypeStats summarises throughput for a single job type.
+type JobTypeStats struct {
+	JobType        string         `json:"job_type"`
+	Count          int            `json:"count"`
+	StatusCounts   map[string]int `json:"status_counts"`
+	AvgDurationMS  float64        `json:"avg_duration_ms"`  // mean over finished jobs with a recorded duration
+	AvgWaitMS      float64        `json:"avg_wait_ms"`      // mean created→started delay over started jobs
+	FailureRatePct float64        `json:"failure_rate_pct"` // failed / (completed + failed) × 100
+}
@@ -2,6 +2,7 @@
 package models
 
 import (
+	"sort"
 	"time"
 )
 
@@ -64,6 +65,9 @@ type MarketData struct {
 	// Used by consumers to show current intraday movement during market hours.
 	LivePrice          *RealTimeQuote `json:"live_price,omitempty"`
 	LivePriceUpdatedAt time.Time      `json:"live_price_updated_at"`
+	// EarningsDate is the next scheduled earnings report (YYYY-MM-DD) as of the
+	// last fundamentals refresh. Empty when EODHD lists none.
+	EarningsDate string `json:"earnings_date,omitempty"`
 }
 
 // EODBar represents a single day's price data
@@ -77,6 +81,41 @@ type EODBar struct {
 	Volume   int64     `json:"volume"`
 }
 
+// NormalizeEOD sorts bars newest first (index 0 = most recent), the order every
+// EOD consumer assumes, and keeps one bar per calendar date. Of duplicates,
+// an adjusted bar (AdjClose > 0) beats an unadjusted one; otherwise the one
+// later in bars wins, as the most recently appended. Returns bars unchanged
+// when already normalized.
+func NormalizeEOD(bars []EODBar) []EODBar {
+	normalized := true
+	for i := 1; i < len(bars); i++ {
+		if !bars[i-1].Date.After(bars[i].Date) || bars[i-1].Date.Format("2006-01-02") == bars[i].Date.Format("2006-01-02") {
+			normalized = false
+			break
+		}
+	}
+	if normalized {
+		return bars
+	}
+
+	byDate := make(map[string]int, len(bars))
+	out := make([]EODBar, 0, len(bars))
+	for _, b := range bars {
+		key := b.Date.Format("2006-01-02")
+		i, seen := byDate[key]
+		if !seen {
+			byDate[key] = len(out)
+			out = append(out, b)
+			continue
+		}
+		if b.AdjClose > 0 || out[i].AdjClose <= 0 {
+			out[i] = b
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Date.After(out[j].Date) })
+	return out
+}
+
 // DividendEvent represents a historical dividend payment from EODHD
 type DividendEvent struct {
 	Date            time.Time `json:"date"`             // Ex-dividend date
@@ -137,6 +176,26 @@ type Fundamentals struct {
 	MostRecentQuarter  string  `json:"most_recent_quarter,omitempty"`
 	// Historical financials from EODHD Income_Statement (P2 backfill)
 	HistoricalFinancials []HistoricalPeriod `json:"historical_financials,omitempty"`
+	// Scheduled earnings report dates (YYYY-MM-DD, ascending) from EODHD Earnings.History,
+	// limited to dates on or after the fetch day
+	EarningsDates []string `json:"earnings_dates,omitempty"`
+}
+
+// NextEarningsDate returns the first scheduled earnings report date on or
+// after now's calendar day. ok is false when none is known, including when
+// every stored date has passed since the fundamentals were fetched. Safe on nil.
+func (f *Fundamentals) NextEarningsDate(now time.Time) (time.Time, bool) {
+	if f == nil {
+		return time.Time{}, false
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	for _, d := range f.EarningsDates {
+		date, err := time.Parse("2006-01-02", d)
+		if err == nil && !date.Before(today) {
+			return date, true
+		}
+	}
+	return time.Time{}, false
 }
 
 // HistoricalPeriod represents one year of historical financial data from EODHD
@@ -196,9 +255,12 @@ type StockData struct {
 	Name     string `json:"name"`
 	// Layer 1: Technical Profile
 	Price        *PriceData     `json:"price,omitempty"`
-	Candles      []EODBar       `json:"candles,omitempty"` // Historical OHLC bars (most recent first)
+	Candles      []EODBar       `json:"candles,omitempty"`  // Historical OHLC bars (most recent first)
+	Lookback     string         `json:"lookback,omitempty"` // Requested chart/signal window (e.g. "1y"), empty for default
 	Fundamentals *Fundamentals  `json:"fundamentals,omitempty"`
 	Signals      *TickerSignals `json:"signals,omitempty"`
+	// Indicator history (optional): RSI, SMA50/200 and MACD per bar, most recent first
+	IndicatorSeries []IndicatorPoint `json:"indicator_series,omitempty"`
 	// News (optional)
 	News             []*NewsItem       `json:"news,omitempty"`
 	NewsIntelligence *NewsIntelligence `json:"news_intelligence,omitempty"`
@@ -273,6 +335,54 @@ type SnipeBuy struct {
 	Analysis    string         `json:"analysis,omitempty"` // AI analysis
 }
 
+// SnipeThresholds tunes what the snipe scan treats as snipe-worthy. Zero
+// fields fall back to the defaults (see WithDefaults).
+type SnipeThresholds struct {
+	OversoldRSI     float64 `json:"oversold_rsi,omitempty"`      // RSI below this scores as oversold (default 30)
+	NearOversoldRSI float64 `json:"near_oversold_rsi,omitempty"` // RSI below this scores as approaching oversold (default 40)
+	MinMomentum     float64 `json:"min_momentum,omitempty"`      // trend momentum score (-1 to 1) floor; 0 disables the filter
+	MinVolumeRatio  float64 `json:"min_volume_ratio,omitempty"`  // current/average volume floor; 0 disables the filter
+	MinScore        float64 `json:"min_score,omitempty"`         // minimum candidate score, 0-1 (default 0.6)
+}
+
+// Merge returns t with every non-zero field of o applied on top.
+func (t SnipeThresholds) Merge(o SnipeThresholds) SnipeThresholds {
+	if o.OversoldRSI != 0 {
+		t.OversoldRSI = o.OversoldRSI
+	}
+	if o.NearOversoldRSI != 0 {
+		t.NearOversoldRSI = o.NearOversoldRSI
+	}
+	if o.MinMomentum != 0 {
+		t.MinMomentum = o.MinMomentum
+	}
+	if o.MinVolumeRatio != 0 {
+		t.MinVolumeRatio = o.MinVolumeRatio
+	}
+	if o.MinScore != 0 {
+		t.MinScore = o.MinScore
+	}
+	return t
+}
+
+// WithDefaults fills unset thresholds. NearOversoldRSI is never below
+// OversoldRSI, so raising only the oversold level keeps the bands ordered.
+func (t SnipeThresholds) WithDefaults() SnipeThresholds {
+	if t.OversoldRSI <= 0 {
+		t.OversoldRSI = 30
+	}
+	if t.NearOversoldRSI <= 0 {
+		t.NearOversoldRSI = 40
+	}
+	if t.NearOversoldRSI < t.OversoldRSI {
+		t.NearOversoldRSI = t.OversoldRSI
+	}
+	if t.MinScore <= 0 {
+		t.MinScore = 0.6
+	}
+	return t
+}
+
 // ScreenCandidate represents a stock passing the quality-value screen
 type ScreenCandidate struct {
 	Ticker           string         `json:"ticker"`
@@ -488,6 +598,31 @@ type ScreenerResult struct {
 	AvgVol200d     float64 `json:"avgvol_200d"`
 }
 
+// ScoreWeights weights the factors of a composite screen score. Weights are
+// relative; zero-weight factors are left out. All zero = equal weights.
+type ScoreWeights struct {
+	Valuation float64 `json:"valuation"` // lower P/E scores higher
+	Momentum  float64 `json:"momentum"`  // stored RSI
+	Dividend  float64 `json:"dividend"`  // dividend yield
+}
+
+// Composite score factor names
+const (
+	ScoreFactorValuation = "valuation"
+	ScoreFactorMomentum  = "momentum"
+	ScoreFactorDividend  = "dividend"
+)
+
+// ScoredResult is a screener result ranked by a composite 0-100 score.
+// Factors holds each available factor's normalised 0-100 score; factors
+// without data are absent and excluded from Score.
+type ScoredResult struct {
+	ScreenerResult
+	Ticker  string             `json:"ticker"`
+	Score   float64            `json:"score"`
+	Factors map[string]float64 `json:"factors"`
+}
+
 // FunnelResult holds the output of a multi-stage funnel screen
 type FunnelResult struct {
 	Candidates []*ScreenCandidate `json:"candidates"`
@@ -506,6 +641,13 @@ type FunnelStage struct {
 	Filters     string        `json:"filters,omitempty"` // human-readable description
 }
 
+// FunnelFilterStage is one caller-defined funnel stage. A candidate survives
+// the stage when it passes every filter (market_scan fields and operators).
+type FunnelFilterStage struct {
+	Name    string       `json:"name"`
+	Filters []ScanFilter `json:"filters"`
+}
+
 // SearchRecord stores a screen/snipe/funnel search result for history
 type SearchRecord struct {
 	ID           string    `json:"id"`
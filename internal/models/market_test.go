@@ -0,0 +1,49 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func eodDay(day int) time.Time {
+	return time.Date(2025, 3, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestNormalizeEOD_SortsNewestFirstAndDeduplicates(t *testing.T) {
+	bars := []EODBar{
+		{Date: eodDay(3), Close: 103, AdjClose: 103},
+		{Date: eodDay(1), Close: 101, AdjClose: 101},
+		{Date: eodDay(5), Close: 105}, // unadjusted...
+		{Date: eodDay(2), Close: 102, AdjClose: 102},
+		{Date: eodDay(5), Close: 105.5, AdjClose: 105.2},                     // ...replaced by the adjusted revision
+		{Date: eodDay(3).Add(10 * time.Hour), Close: 103.4, AdjClose: 103.4}, // same day, later fetch
+		{Date: eodDay(4), Close: 104, AdjClose: 104},
+		{Date: eodDay(4), Close: 999}, // unadjusted duplicate does not replace an adjusted bar
+	}
+
+	got := NormalizeEOD(bars)
+
+	wantCloses := []float64{105.5, 104, 103.4, 102, 101}
+	if len(got) != len(wantCloses) {
+		t.Fatalf("got %d bars, want %d: %+v", len(got), len(wantCloses), got)
+	}
+	for i, want := range wantCloses {
+		if got[i].Close != want {
+			t.Errorf("bar %d (%s) close = %v, want %v", i, got[i].Date.Format("2006-01-02"), got[i].Close, want)
+		}
+		if i > 0 && !got[i-1].Date.After(got[i].Date) {
+			t.Errorf("bars %d and %d are not newest first", i-1, i)
+		}
+	}
+}
+
+func TestNormalizeEOD_AlreadyNormalized(t *testing.T) {
+	bars := []EODBar{{Date: eodDay(3)}, {Date: eodDay(2)}, {Date: eodDay(1)}}
+	got := NormalizeEOD(bars)
+	if &got[0] != &bars[0] {
+		t.Error("normalized input should be returned as is, without copying")
+	}
+	if len(NormalizeEOD(nil)) != 0 {
+		t.Error("nil input should stay empty")
+	}
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// Notification event types sent to outbound webhooks.
+const (
+	NotificationAlertRaised     = "alert_raised"
+	NotificationPlanTriggered   = "plan_item_triggered"
+	NotificationWatchlistTarget = WatchlistEventTargetHit
+	NotificationTest            = "test"
+)
+
+// NotificationEvent is the JSON payload POSTed to each configured webhook.
+// Data carries the source record: an AlertState, PlanItem or WatchlistEvent.
+type NotificationEvent struct {
+	ID            string    `json:"id"`
+	Type          string    `json:"type"`
+	PortfolioName string    `json:"portfolio_name,omitempty"`
+	Ticker        string    `json:"ticker,omitempty"`
+	Message       string    `json:"message"`
+	OccurredAt    time.Time `json:"occurred_at"`
+	Data          any       `json:"data,omitempty"`
+}
+
+// WebhookDelivery reports the outcome of delivering one event to one URL.
+// StatusCode is the last HTTP status received (0 if no response).
+type WebhookDelivery struct {
+	URL        string `json:"url"`
+	EventID    string `json:"event_id"`
+	Delivered  bool   `json:"delivered"`
+	Attempts   int    `json:"attempts"`
+	StatusCode int    `json:"status_code,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
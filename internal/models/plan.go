@@ -11,8 +11,9 @@ import (
 type PlanItemType string
 
 const (
-	PlanItemTypeTime  PlanItemType = "time"
-	PlanItemTypeEvent PlanItemType = "event"
+	PlanItemTypeTime         PlanItemType = "time"
+	PlanItemTypeEvent        PlanItemType = "event"
+	PlanItemTypeTrailingStop PlanItemType = "trailing_stop"
 )
 
 // PlanItemStatus tracks plan item lifecycle
@@ -37,20 +38,44 @@ type PlanItem struct {
 	Ticker      string          `json:"ticker,omitempty"`     // event-based target ticker
 	Action      RuleAction      `json:"action,omitempty"`
 	TargetValue float64         `json:"target_value,omitempty"`
+	TrailPct    float64         `json:"trail_pct,omitempty"`  // trailing_stop: trigger this % below the peak
+	PeakPrice   float64         `json:"peak_price,omitempty"` // trailing_stop: highest price seen since creation (never lowered)
+	Trail       *TrailStatus    `json:"trail,omitempty"`      // trailing_stop: state at the last status check
 	CreatedAt   time.Time       `json:"created_at"`
 	UpdatedAt   time.Time       `json:"updated_at"`
 	CompletedAt *time.Time      `json:"completed_at,omitempty"`
 	Notes       string          `json:"notes,omitempty"`
 }
 
+// TrailStatus reports where a trailing stop stands at the last check.
+type TrailStatus struct {
+	CurrentPrice float64   `json:"current_price"`
+	TrailLevel   float64   `json:"trail_level"`  // peak_price × (1 - trail_pct/100)
+	DistancePct  float64   `json:"distance_pct"` // % the price can fall before triggering
+	CheckedAt    time.Time `json:"checked_at"`
+}
+
 // PortfolioPlan is a versioned collection of time-based and event-based action items.
 type PortfolioPlan struct {
-	PortfolioName string     `json:"portfolio_name"`
-	Version       int        `json:"version"`
-	Items         []PlanItem `json:"items"`
-	Notes         string     `json:"notes,omitempty"`
-	CreatedAt     time.Time  `json:"created_at"`
-	UpdatedAt     time.Time  `json:"updated_at"`
+	PortfolioName     string     `json:"portfolio_name"`
+	Version           int        `json:"version"`
+	Items             []PlanItem `json:"items"`
+	Notes             string     `json:"notes,omitempty"`
+	DriftTolerancePct float64    `json:"drift_tolerance_pct,omitempty"` // allowed deviation from item target_value before a plan_drift alert (0 = default 20%)
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// DefaultPlanDriftTolerancePct is the deviation from a plan item's target value
+// tolerated before the holding is reported as drifting from plan.
+const DefaultPlanDriftTolerancePct = 20.0
+
+// GetDriftTolerancePct returns the configured drift tolerance or the default.
+func (p *PortfolioPlan) GetDriftTolerancePct() float64 {
+	if p.DriftTolerancePct > 0 {
+		return p.DriftTolerancePct
+	}
+	return DefaultPlanDriftTolerancePct
 }
 
 // ToMarkdown renders the plan as a readable markdown document.
@@ -159,6 +184,12 @@ func writePlanItem(b *strings.Builder, item PlanItem) {
 	if item.Ticker != "" {
 		b.WriteString(fmt.Sprintf(" | Ticker: %s", item.Ticker))
 	}
+	if item.Type == PlanItemTypeTrailingStop && item.TrailPct > 0 {
+		b.WriteString(fmt.Sprintf(" | Trail: %.1f%%", item.TrailPct))
+		if item.Trail != nil {
+			b.WriteString(fmt.Sprintf(" (peak %.2f, stop %.2f, %.1f%% away)", item.PeakPrice, item.Trail.TrailLevel, item.Trail.DistancePct))
+		}
+	}
 	if item.Action != "" {
 		b.WriteString(fmt.Sprintf(" | Action: %s", string(item.Action)))
 	}
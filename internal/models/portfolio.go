@@ -53,11 +53,15 @@ type Portfolio struct {
 	EquityHoldingsReturn     float64             `json:"equity_holdings_return"`
 	EquityHoldingsReturnPct  float64             `json:"equity_holdings_return_pct"`
 	Currency                 string              `json:"currency"`
-	FXRate                   float64             `json:"fx_rate,omitempty"` // AUDUSD rate used for currency conversion at sync time
+	BaseCurrency             string              `json:"base_currency,omitempty"` // currency holdings are converted into (defaults to AUD)
+	FXRate                   float64             `json:"fx_rate,omitempty"`       // base/USD rate (AUDUSD for AUD portfolios) used at sync time
+	FXRates                  map[string]float64  `json:"fx_rates,omitempty"`      // base/quote rate per converted holding currency at sync time
 	EquityHoldingsRealized   float64             `json:"equity_holdings_realized"`
 	EquityHoldingsUnrealized float64             `json:"equity_holdings_unrealized"`
 	IncomeDividendsForecast  float64             `json:"income_dividends_forecast"`    // forecasted dividends (Navexa total minus holdings with confirmed ledger payments)
 	IncomeDividendsReceived  float64             `json:"income_dividends_received"`    // confirmed dividends from cash flow ledger
+	PortfolioDividendIncome  float64             `json:"portfolio_dividend_income"`    // dividend trades summed across holdings (each ticker once)
+	PortfolioTotalFees       float64             `json:"portfolio_total_fees"`         // brokerage summed across holdings, rebates netted
 	CalculationMethod        string              `json:"calculation_method,omitempty"` // documents return % methodology (e.g. "average_cost")
 	DataVersion              string              `json:"data_version,omitempty"`       // schema version at save time — mismatch triggers re-sync
 	CapitalGross             float64             `json:"capital_gross"`
@@ -72,10 +76,15 @@ type Portfolio struct {
 	UpdatedAt                time.Time           `json:"updated_at"`
 
 	// Aggregate historical values — computed on response, not persisted
-	PortfolioYesterdayValue     float64 `json:"portfolio_yesterday_value,omitempty"`      // Total value at yesterday's close
-	PortfolioYesterdayChangePct float64 `json:"portfolio_yesterday_change_pct,omitempty"` // % change from yesterday
-	PortfolioLastWeekValue      float64 `json:"portfolio_last_week_value,omitempty"`      // Total value at last week's close
-	PortfolioLastWeekChangePct  float64 `json:"portfolio_last_week_change_pct,omitempty"` // % change from last week
+	PortfolioYesterdayValue       float64      `json:"portfolio_yesterday_value,omitempty"`         // Total value at yesterday's close
+	PortfolioYesterdayChangePct   float64      `json:"portfolio_yesterday_change_pct,omitempty"`    // % change from yesterday
+	PortfolioLastWeekValue        float64      `json:"portfolio_last_week_value,omitempty"`         // Total value at last week's close
+	PortfolioLastWeekChangePct    float64      `json:"portfolio_last_week_change_pct,omitempty"`    // % change from last week
+	PortfolioLastMonthValue       float64      `json:"portfolio_last_month_value,omitempty"`        // Total value at the close a month ago
+	PortfolioLastMonthChangePct   float64      `json:"portfolio_last_month_change_pct,omitempty"`   // % change from last month
+	PortfolioLastQuarterValue     float64      `json:"portfolio_last_quarter_value,omitempty"`      // Total value at the close three months ago
+	PortfolioLastQuarterChangePct float64      `json:"portfolio_last_quarter_change_pct,omitempty"` // % change from last quarter
+	ReturnContributionPeriod      ReturnPeriod `json:"return_contribution_period,omitempty"`        // Window of the holdings' return_contribution_pct
 
 	// Net cash flow fields — computed on response, not persisted
 	NetCashYesterdayFlow float64 `json:"net_cash_yesterday_flow,omitempty"` // Net cash flow yesterday (deposits - withdrawals)
@@ -87,6 +96,71 @@ type Portfolio struct {
 	TimelineRebuilding bool              `json:"timeline_rebuilding,omitempty"` // true when a full timeline rebuild is in progress
 }
 
+// ReturnPeriod names the window a holding's ReturnContributionPct covers.
+type ReturnPeriod string
+
+const (
+	ReturnPeriodDay     ReturnPeriod = "day"
+	ReturnPeriodWeek    ReturnPeriod = "week" // default
+	ReturnPeriodMonth   ReturnPeriod = "month"
+	ReturnPeriodQuarter ReturnPeriod = "quarter"
+)
+
+// ValidReturnPeriod reports whether p is a known ReturnPeriod.
+func ValidReturnPeriod(p ReturnPeriod) bool {
+	switch p {
+	case ReturnPeriodDay, ReturnPeriodWeek, ReturnPeriodMonth, ReturnPeriodQuarter:
+		return true
+	}
+	return false
+}
+
+// SetReturnContributions sets each holding's share of the portfolio's return
+// over period: its value change (units × the close at the period start to
+// market value) divided by the portfolio value at the period start. With
+// cash unchanged over the period the contributions sum to the period's
+// portfolio change percent. Needs the historical values populated.
+func (p *Portfolio) SetReturnContributions(period ReturnPeriod) {
+	var start float64
+	switch period {
+	case ReturnPeriodDay:
+		start = p.PortfolioYesterdayValue
+	case ReturnPeriodMonth:
+		start = p.PortfolioLastMonthValue
+	case ReturnPeriodQuarter:
+		start = p.PortfolioLastQuarterValue
+	default:
+		period = ReturnPeriodWeek
+		start = p.PortfolioLastWeekValue
+	}
+	p.ReturnContributionPeriod = ""
+	for i := range p.Holdings {
+		p.Holdings[i].ReturnContributionPct = 0
+	}
+	if start <= 0 {
+		return
+	}
+	p.ReturnContributionPeriod = period
+	for i := range p.Holdings {
+		h := &p.Holdings[i]
+		var startClose float64
+		switch period {
+		case ReturnPeriodDay:
+			startClose = h.YesterdayClosePrice
+		case ReturnPeriodWeek:
+			startClose = h.LastWeekClosePrice
+		case ReturnPeriodMonth:
+			startClose = h.LastMonthClosePrice
+		case ReturnPeriodQuarter:
+			startClose = h.LastQuarterClosePrice
+		}
+		if h.Units <= 0 || startClose <= 0 {
+			continue
+		}
+		h.ReturnContributionPct = (h.MarketValue - startClose*h.Units) / start * 100
+	}
+}
+
 // MetricChange tracks raw and percentage change for a single metric.
 type MetricChange struct {
 	Current     float64 `json:"current"`              // Current value
@@ -143,6 +217,9 @@ type PortfolioBreadth struct {
 type Holding struct {
 	Ticker                     string         `json:"ticker"`
 	Exchange                   string         `json:"exchange"`
+	ExchangeInferred           bool           `json:"exchange_inferred,omitempty"` // true when Exchange was inferred because the source omitted it
+	PriceInCents               bool           `json:"price_in_cents,omitempty"`    // true when EODHD quotes this ticker in cents; its prices are divided by 100
+	SplitSuspected             bool           `json:"split_suspected,omitempty"`   // true when units look out of step with an unrecorded split in the EOD series; units are not adjusted
 	Name                       string         `json:"name"`
 	SourceType                 SourceType     `json:"source_type,omitempty"` // navexa, manual, snapshot, csv
 	SourceRef                  string         `json:"source_ref,omitempty"`  // free-form provenance tag
@@ -157,9 +234,12 @@ type Holding struct {
 	CostBasis                  float64        `json:"cost_basis"`             // Remaining cost basis (average cost * remaining units)
 	GrossInvested              float64        `json:"gross_invested"`         // Sum of all buy costs + fees (total capital deployed)
 	GrossProceeds              float64        `json:"gross_proceeds"`         // Sum of all sell proceeds (units × price − fees)
+	TotalFees                  float64        `json:"total_fees"`             // Sum of buy and sell brokerage; rebates (negative fees) reduce it
 	RealizedReturn             float64        `json:"realized_return"`        // P&L from sold portions
 	UnrealizedReturn           float64        `json:"unrealized_return"`      // P&L on remaining position
 	DividendReturn             float64        `json:"dividend_return"`
+	DividendsReceived          float64        `json:"dividends_received"`            // Sum of dividend trade values
+	TrailingYieldPct           float64        `json:"trailing_yield_pct"`            // Dividends received in the last 12 months / market value × 100
 	AnnualizedCapitalReturnPct float64        `json:"annualized_capital_return_pct"` // XIRR annualised return (capital gains only, excl. dividends)
 	AnnualizedTotalReturnPct   float64        `json:"annualized_total_return_pct"`   // XIRR annualised return (including dividends)
 	TimeWeightedReturnPct      float64        `json:"time_weighted_return_pct"`      // Time-weighted return (computed locally)
@@ -174,14 +254,25 @@ type Holding struct {
 	TrueBreakevenPrice *float64 `json:"true_breakeven_price"`
 
 	// Historical values — computed on response, not persisted
-	YesterdayClosePrice     float64 `json:"yesterday_close_price,omitempty"`       // Previous trading day close (AUD)
-	YesterdayPriceChangePct float64 `json:"yesterday_price_change_pct,omitempty"`  // % change from yesterday to today
-	LastWeekClosePrice      float64 `json:"last_week_close_price,omitempty"`       // Last Friday close (AUD)
-	LastWeekPriceChangePct  float64 `json:"last_week_price_change_pct,omitempty"`  // % change from last week to today
-	LastMonthClosePrice     float64 `json:"last_month_close_price,omitempty"`      // ~22 trading days ago close (AUD)
-	LastMonthPriceChangePct float64 `json:"last_month_price_change_pct,omitempty"` // % change from last month to today
-	TrendLabel              string  `json:"trend_label,omitempty"`                 // "Strong Uptrend", "Uptrend", "Consolidating", "Downtrend", "Strong Downtrend"
-	TrendScore              float64 `json:"trend_score,omitempty"`                 // -1.0 to +1.0 from signal engine
+	YesterdayClosePrice       float64 `json:"yesterday_close_price,omitempty"`         // Previous trading day close (AUD)
+	YesterdayPriceChangePct   float64 `json:"yesterday_price_change_pct,omitempty"`    // % change from yesterday to today
+	LastWeekClosePrice        float64 `json:"last_week_close_price,omitempty"`         // Last Friday close (AUD)
+	LastWeekPriceChangePct    float64 `json:"last_week_price_change_pct,omitempty"`    // % change from last week to today
+	LastMonthClosePrice       float64 `json:"last_month_close_price,omitempty"`        // Close on or before a month ago, ~21 trading days (AUD)
+	LastMonthPriceChangePct   float64 `json:"last_month_price_change_pct,omitempty"`   // % change from last month to today
+	LastQuarterClosePrice     float64 `json:"last_quarter_close_price,omitempty"`      // Close on or before three months ago, ~63 trading days (AUD)
+	LastQuarterPriceChangePct float64 `json:"last_quarter_price_change_pct,omitempty"` // % change from last quarter to today
+	ReturnContributionPct     float64 `json:"return_contribution_pct,omitempty"`       // Holding's value change over return_contribution_period / portfolio value at its start × 100
+	TrendLabel                string  `json:"trend_label,omitempty"`                   // "Strong Uptrend", "Uptrend", "Consolidating", "Downtrend", "Strong Downtrend"
+	TrendScore                float64 `json:"trend_score,omitempty"`                   // -1.0 to +1.0 from signal engine
+
+	// Sale estimate — computed on response when a marginal tax rate is configured
+	EstimatedTax  float64 `json:"estimated_tax,omitempty"`   // CGT on selling all units today, after discount and loss offsets
+	AfterTaxValue float64 `json:"after_tax_value,omitempty"` // Market value less brokerage and EstimatedTax
+
+	// User annotations — merged from holding_annotations on response, never set by sync
+	UserNote string   `json:"user_note,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
 }
 
 // EODHDTicker returns the full EODHD-format ticker (e.g. "BHP.AU", "CBOE.US").
@@ -192,21 +283,98 @@ func (h Holding) EODHDTicker() string {
 
 // PortfolioReview contains the analysis results for a portfolio
 type PortfolioReview struct {
-	PortfolioName           string               `json:"portfolio_name"`
-	ReviewDate              time.Time            `json:"review_date"`
-	PortfolioValue          float64              `json:"portfolio_value"`
-	EquityHoldingsCost      float64              `json:"equity_holdings_cost"`
-	EquityHoldingsReturn    float64              `json:"equity_holdings_return"`
-	EquityHoldingsReturnPct float64              `json:"equity_holdings_return_pct"`
-	PortfolioDayChange      float64              `json:"portfolio_day_change"`
-	PortfolioDayChangePct   float64              `json:"portfolio_day_change_pct"`
-	FXRate                  float64              `json:"fx_rate,omitempty"` // AUDUSD rate used for currency conversion
-	HoldingReviews          []HoldingReview      `json:"holding_reviews"`
-	Alerts                  []Alert              `json:"alerts"`
-	Summary                 string               `json:"summary"` // AI-generated summary
-	Recommendations         []string             `json:"recommendations"`
-	PortfolioBalance        *PortfolioBalance    `json:"portfolio_balance,omitempty"`
-	PortfolioIndicators     *PortfolioIndicators `json:"portfolio_indicators,omitempty"`
+	PortfolioName           string                `json:"portfolio_name"`
+	ReviewDate              time.Time             `json:"review_date"`
+	PortfolioValue          float64               `json:"portfolio_value"`
+	EquityHoldingsCost      float64               `json:"equity_holdings_cost"`
+	EquityHoldingsReturn    float64               `json:"equity_holdings_return"`
+	EquityHoldingsReturnPct float64               `json:"equity_holdings_return_pct"`
+	PortfolioDayChange      float64               `json:"portfolio_day_change"`
+	PortfolioDayChangePct   float64               `json:"portfolio_day_change_pct"`
+	FXRate                  float64               `json:"fx_rate,omitempty"` // AUDUSD rate used for currency conversion
+	HoldingReviews          []HoldingReview       `json:"holding_reviews"`
+	Alerts                  []Alert               `json:"alerts"`
+	SuppressedAlerts        int                   `json:"suppressed_alerts,omitempty"` // acknowledged alerts left out of Alerts while their condition holds
+	Summary                 string                `json:"summary"`                     // AI-generated summary
+	Recommendations         []string              `json:"recommendations"`
+	PortfolioBalance        *PortfolioBalance     `json:"portfolio_balance,omitempty"`
+	PortfolioIndicators     *PortfolioIndicators  `json:"portfolio_indicators,omitempty"`
+	CGTWarnings             []CGTShortHoldWarning `json:"cgt_warnings,omitempty"` // planned sells that would forfeit the CGT discount
+	BenchmarkTicker         string                `json:"benchmark_ticker,omitempty"`
+	BenchmarkReturn         float64               `json:"benchmark_return,omitempty"` // benchmark % return over the comparison window
+	ExcessReturn            float64               `json:"excess_return,omitempty"`    // portfolio % return minus benchmark % return
+	CashDragPct             float64               `json:"cash_drag_pct,omitempty"`    // return forgone by holding idle cash, percentage points
+	Waterfall               *ValueWaterfall       `json:"waterfall,omitempty"`
+	SectorAllocation        *SectorBreakdown      `json:"sector_allocation,omitempty"`
+	HHI                     float64               `json:"hhi"`                // Herfindahl-Hirschman Index of holding weights (0-1)
+	EffectiveHoldings       float64               `json:"effective_holdings"` // 1 / HHI: number of equal-weight holdings with the same concentration
+}
+
+// ValueWaterfall reconciles the portfolio's starting value to its ending value.
+// Every component covers the window from StartDate to now. Unexplained is
+// what they leave of EndValue (asset sets, transfers, pricing gaps), so the
+// components always sum to EndValue.
+type ValueWaterfall struct {
+	StartDate        time.Time `json:"start_date"`
+	StartValue       float64   `json:"start_value"`
+	Contributions    float64   `json:"contributions"`     // net capital contributions after the start date
+	RealizedGains    float64   `json:"realized_gains"`    // net return locked in by sells after the start date
+	UnrealizedChange float64   `json:"unrealized_change"` // change in net return on open positions since the start date
+	Dividends        float64   `json:"dividends"`         // dividends received after the start date
+	Other            float64   `json:"other"`             // fees, interest and other ledger cash flows after the start date
+	Unexplained      float64   `json:"unexplained"`
+	EndValue         float64   `json:"end_value"`
+}
+
+// Total returns the sum of the starting value and every named component,
+// excluding Unexplained.
+func (w *ValueWaterfall) Total() float64 {
+	return w.StartValue + w.Contributions + w.RealizedGains + w.UnrealizedChange + w.Dividends + w.Other
+}
+
+// CGTShortHoldWarning flags a planned sale that would dispose of units held
+// for less than the minimum holding period, forfeiting the CGT discount.
+type CGTShortHoldWarning struct {
+	Ticker               string    `json:"ticker"`
+	PlanItemID           string    `json:"plan_item_id,omitempty"`
+	UnitsSold            float64   `json:"units_sold"`             // units the sale would dispose of (FIFO)
+	ShortHeldUnits       float64   `json:"short_held_units"`       // of those, units held less than MinHoldDays
+	AcquiredDate         time.Time `json:"acquired_date"`          // most recent acquisition among short-held lots
+	SaleDate             time.Time `json:"sale_date"`              // planned sale date (deadline or review date)
+	DiscountEligibleDate time.Time `json:"discount_eligible_date"` // date all disposed units qualify for the discount
+	DaysShort            int       `json:"days_short"`
+	MinHoldDays          int       `json:"min_hold_days"`
+}
+
+// CGTReport summarises capital gains realized in one financial year,
+// separating gains eligible for the CGT discount from those that are not.
+type CGTReport struct {
+	PortfolioName        string          `json:"portfolio_name"`
+	FinancialYear        string          `json:"financial_year"` // e.g. "2023-2024"
+	PeriodStart          time.Time       `json:"period_start"`   // 1 July
+	PeriodEnd            time.Time       `json:"period_end"`     // 30 June
+	CostBasisMethod      CostBasisMethod `json:"cost_basis_method"`
+	DiscountableGains    float64         `json:"discountable_gains"`     // gross gains on lots held at least MinHoldDays
+	NonDiscountableGains float64         `json:"non_discountable_gains"` // gains on lots held less than MinHoldDays
+	CapitalLosses        float64         `json:"capital_losses"`         // sum of losses (positive number)
+	DiscountRate         float64         `json:"discount_rate"`          // e.g. 0.3333 for an SMSF
+	NetCapitalGain       float64         `json:"net_capital_gain"`       // after losses, then discount
+	MinHoldDays          int             `json:"min_hold_days"`
+	Disposals            []CGTDisposal   `json:"disposals"`
+	Warnings             []string        `json:"warnings,omitempty"`
+}
+
+// CGTDisposal is one sell matched against one buy lot.
+type CGTDisposal struct {
+	Ticker       string    `json:"ticker"`
+	AcquiredDate time.Time `json:"acquired_date"`
+	DisposalDate time.Time `json:"disposal_date"`
+	Units        float64   `json:"units"`
+	Proceeds     float64   `json:"proceeds"`  // net of sell fees
+	CostBase     float64   `json:"cost_base"` // incl. buy fees and cost base adjustments
+	Gain         float64   `json:"gain"`      // negative for a loss
+	HeldDays     int       `json:"held_days"`
+	Discountable bool      `json:"discountable"`
 }
 
 // PortfolioBalance contains sector/industry allocation analysis
@@ -226,23 +394,60 @@ type SectorAllocation struct {
 	Holdings []string `json:"holdings"`
 }
 
+// UnknownSector groups holdings without cached fundamentals.
+const UnknownSector = "Unknown"
+
+// SectorExposure is one sector's share of the portfolio.
+type SectorExposure struct {
+	MarketValue float64  `json:"market_value"`
+	WeightPct   float64  `json:"weight_pct"` // sum of holding weights (share of portfolio value)
+	Holdings    []string `json:"holdings"`
+}
+
+// SectorBreakdown maps each sector to its exposure across open holdings.
+type SectorBreakdown struct {
+	PortfolioName string                    `json:"portfolio_name"`
+	Sectors       map[string]SectorExposure `json:"sectors"`
+}
+
+// FeeSummary breaks down brokerage per holding and across the portfolio,
+// as an amount and as a percentage of gross invested capital.
+type FeeSummary struct {
+	PortfolioName   string        `json:"portfolio_name"`
+	TotalFees       float64       `json:"total_fees"`
+	GrossInvested   float64       `json:"gross_invested"`
+	FeesPctInvested float64       `json:"fees_pct_invested"`
+	Holdings        []HoldingFees `json:"holdings"`
+}
+
+// HoldingFees is one holding's line in a FeeSummary.
+type HoldingFees struct {
+	Ticker          string  `json:"ticker"`
+	Name            string  `json:"name,omitempty"`
+	Status          string  `json:"status"`
+	TotalFees       float64 `json:"total_fees"`
+	GrossInvested   float64 `json:"gross_invested"`
+	FeesPctInvested float64 `json:"fees_pct_invested"` // TotalFees / GrossInvested × 100
+}
+
 // HoldingReview contains the analysis for a single holding
 type HoldingReview struct {
-	Holding          Holding           `json:"holding"`
-	Signals          *TickerSignals    `json:"signals,omitempty"`
-	Fundamentals     *Fundamentals     `json:"fundamentals,omitempty"`
-	OvernightMove    float64           `json:"overnight_move"`
-	OvernightPct     float64           `json:"overnight_pct"`
-	NewsImpact       string            `json:"news_impact,omitempty"`
-	NewsIntelligence *NewsIntelligence `json:"news_intelligence,omitempty"`
-	FilingSummaries  []FilingSummary   `json:"filing_summaries,omitempty"`
-	Timeline         *CompanyTimeline  `json:"timeline,omitempty"`
-	ActionRequired   string            `json:"action_required"` // BUY, SELL, HOLD, WATCH
-	ActionReason     string            `json:"action_reason"`
-	Compliance       *ComplianceResult `json:"compliance,omitempty"`
-	HoldingNote      *HoldingNote      `json:"holding_note,omitempty"`      // Analyst context note
-	SignalConfidence SignalConfidence  `json:"signal_confidence,omitempty"` // high/medium/low based on asset type
-	NoteStale        bool              `json:"note_stale,omitempty"`        // True if note needs review
+	Holding          Holding            `json:"holding"`
+	Signals          *TickerSignals     `json:"signals,omitempty"`
+	Fundamentals     *Fundamentals      `json:"fundamentals,omitempty"`
+	OvernightMove    float64            `json:"overnight_move"`
+	OvernightPct     float64            `json:"overnight_pct"`
+	NewsImpact       string             `json:"news_impact,omitempty"`
+	NewsIntelligence *NewsIntelligence  `json:"news_intelligence,omitempty"`
+	FilingSummaries  []FilingSummary    `json:"filing_summaries,omitempty"`
+	Timeline         *CompanyTimeline   `json:"timeline,omitempty"`
+	ActionRequired   string             `json:"action_required"` // BUY, SELL, HOLD, WATCH
+	ActionReason     string             `json:"action_reason"`
+	Compliance       *ComplianceResult  `json:"compliance,omitempty"`
+	HoldingNote      *HoldingNote       `json:"holding_note,omitempty"`      // Analyst context note
+	SignalConfidence SignalConfidence   `json:"signal_confidence,omitempty"` // high/medium/low based on asset type
+	NoteStale        bool               `json:"note_stale,omitempty"`        // True if note needs review
+	DerivedMetrics   map[string]float64 `json:"derived_metrics,omitempty"`   // Strategy-defined formulas evaluated for this holding
 }
 
 // Alert represents a portfolio alert
@@ -254,6 +459,23 @@ type Alert struct {
 	Signal   string    `json:"signal,omitempty"`
 }
 
+// AlertState tracks one alert, identified by portfolio, ticker and signal,
+// across reviews. Stored in UserDataStore (subject="alert_state") while the
+// alert's condition holds and deleted once a review no longer raises it, so
+// a condition that clears and returns starts a fresh, unacknowledged episode.
+type AlertState struct {
+	PortfolioName  string     `json:"portfolio_name"`
+	Ticker         string     `json:"ticker,omitempty"`
+	Signal         string     `json:"signal"`
+	Type           AlertType  `json:"type"`
+	Severity       string     `json:"severity"`
+	Message        string     `json:"message"` // latest message
+	FirstRaised    time.Time  `json:"first_raised"`
+	LastSeen       time.Time  `json:"last_seen"`
+	Acknowledged   bool       `json:"acknowledged"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at,omitempty"`
+}
+
 // PortfolioSnapshot represents the reconstructed state of a portfolio at a historical date.
 // Computed on demand from trade history and EOD prices — not stored.
 type PortfolioSnapshot struct {
@@ -267,6 +489,24 @@ type PortfolioSnapshot struct {
 	EquityHoldingsReturnPct float64
 }
 
+// DailyPortfolioSnapshot is the headline portfolio state recorded once per
+// day by the snapshot_portfolio job, so history survives later changes to
+// trades or price data. Stored under the "portfolio_snapshot" subject, keyed
+// by portfolio name and date.
+type DailyPortfolioSnapshot struct {
+	PortfolioName  string    `json:"portfolio_name"`
+	Date           time.Time `json:"date"`                       // calendar day (UTC midnight)
+	PortfolioValue float64   `json:"portfolio_value"`            // holdings + available cash + asset sets
+	EquityValue    float64   `json:"equity_value"`               // equity holdings only
+	EquityCost     float64   `json:"equity_cost,omitempty"`      // equity holdings net capital
+	AssetSetsValue float64   `json:"asset_sets_value,omitempty"` // non-equity asset sets
+	NetCashBalance float64   `json:"net_cash_balance"`           // uninvested cash (capital_available)
+	NetReturn      float64   `json:"net_return"`                 // equity holdings net return
+	HoldingCount   int       `json:"holding_count,omitempty"`    // open positions
+	SyncedAt       time.Time `json:"synced_at"`                  // LastSynced of the portfolio record the values came from
+	RecordedAt     time.Time `json:"recorded_at"`
+}
+
 // SnapshotHolding represents a single position within a historical portfolio snapshot.
 type SnapshotHolding struct {
 	Ticker, Name              string
@@ -390,3 +630,23 @@ const (
 	AlertTypeRisk     AlertType = "risk"
 	AlertTypeStrategy AlertType = "strategy"
 )
+
+// DataCompleteness scores how much of each holding's data is present and
+// fresh, indicating whether a portfolio review can be trusted.
+type DataCompleteness struct {
+	PortfolioName string                `json:"portfolio_name"`
+	ScorePct      float64               `json:"score_pct"` // mean of holding scores (0-100)
+	Holdings      []HoldingCompleteness `json:"holdings"`
+	ComputedAt    time.Time             `json:"computed_at"`
+}
+
+// HoldingCompleteness is the per-holding breakdown of a DataCompleteness score.
+// Each component is "fresh" (full credit), "stale" (half) or "missing" (none).
+type HoldingCompleteness struct {
+	Ticker       string  `json:"ticker"`
+	ScorePct     float64 `json:"score_pct"`
+	EOD          string  `json:"eod"`
+	Fundamentals string  `json:"fundamentals"`
+	Signals      string  `json:"signals"`
+	Trades       string  `json:"trades"`
+}
@@ -1,6 +1,9 @@
 package models
 
-import "testing"
+import (
+	"math"
+	"testing"
+)
 
 func TestEodhExchange(t *testing.T) {
 	tests := []struct {
@@ -21,3 +24,31 @@ func TestEodhExchange(t *testing.T) {
 		}
 	}
 }
+
+func TestSetReturnContributions_NonDefaultWindow(t *testing.T) {
+	// BHP 100 × 40 → 50 (+1000), CBA 50 × 120 → 100 (-1000) over the month,
+	// 2000 cash. Month-ago value 4000 + 6000 + 2000 = 12000.
+	p := &Portfolio{
+		PortfolioLastWeekValue:  11500,
+		PortfolioLastMonthValue: 12000,
+		Holdings: []Holding{
+			{Ticker: "BHP", Units: 100, MarketValue: 5000, LastWeekClosePrice: 45, LastMonthClosePrice: 40},
+			{Ticker: "CBA", Units: 50, MarketValue: 5000, LastWeekClosePrice: 110, LastMonthClosePrice: 120},
+		},
+	}
+
+	p.SetReturnContributions(ReturnPeriodMonth)
+	if p.ReturnContributionPeriod != ReturnPeriodMonth {
+		t.Errorf("period = %q, want month", p.ReturnContributionPeriod)
+	}
+	bhp, cba := p.Holdings[0].ReturnContributionPct, p.Holdings[1].ReturnContributionPct
+	if math.Abs(bhp-1000.0/12000*100) > 1e-9 || math.Abs(cba+1000.0/12000*100) > 1e-9 {
+		t.Errorf("month contributions = %.4f, %.4f; want ±%.4f", bhp, cba, 1000.0/12000*100)
+	}
+
+	// No start value for the window: contributions are cleared, not left from another window
+	p.SetReturnContributions(ReturnPeriodQuarter)
+	if p.ReturnContributionPeriod != "" || p.Holdings[0].ReturnContributionPct != 0 {
+		t.Errorf("quarter without history = %q, %.4f; want none", p.ReturnContributionPeriod, p.Holdings[0].ReturnContributionPct)
+	}
+}
@@ -0,0 +1,38 @@
+package models
+
+// ProjectionHolding is the return model used for one holding in a
+// portfolio value projection. Drift and Volatility are annualised log-return
+// figures as decimals (0.08 = 8%).
+type ProjectionHolding struct {
+	Ticker      string  `json:"ticker"`
+	MarketValue float64 `json:"market_value"`
+	Drift       float64 `json:"drift"`                  // simulated drift: SampleDrift shrunk towards the long-run prior and capped
+	SampleDrift float64 `json:"sample_drift,omitempty"` // mean historical log return, annualised
+	Volatility  float64 `json:"volatility"`
+	HistoryDays int     `json:"history_days"`                 // daily returns the estimate was drawn from
+	Default     bool    `json:"default_assumption,omitempty"` // true when history was too short and the default drift/volatility were used
+}
+
+// ProjectionBand is the simulated portfolio value distribution at the end of
+// one projection year.
+type ProjectionBand struct {
+	Year int     `json:"year"`
+	P10  float64 `json:"p10"`
+	P50  float64 `json:"p50"`
+	P90  float64 `json:"p90"`
+}
+
+// ProjectionResult is a Monte Carlo projection of portfolio value. Only
+// equity holdings are simulated; cash and asset sets are carried at their
+// current value in FlatValue.
+type ProjectionResult struct {
+	PortfolioName string              `json:"portfolio_name"`
+	StartValue    float64             `json:"start_value"`
+	FlatValue     float64             `json:"flat_value"`
+	Years         int                 `json:"years"`
+	Simulations   int                 `json:"simulations"`
+	Bands         []ProjectionBand    `json:"bands"`
+	Holdings      []ProjectionHolding `json:"holdings"`
+	Assumptions   []string            `json:"assumptions"` // how drift and volatility were derived
+	Warnings      []string            `json:"warnings,omitempty"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// RealizedGainComponent is one holding's realized gain or loss on a date.
+type RealizedGainComponent struct {
+	Ticker   string  `json:"ticker"`
+	Realized float64 `json:"realized"`
+}
+
+// RealizedGainPoint is the realized gain booked on one date and the
+// portfolio's running total after it.
+type RealizedGainPoint struct {
+	Date       time.Time               `json:"date"`
+	Realized   float64                 `json:"realized"`   // booked on this date, all holdings
+	Cumulative float64                 `json:"cumulative"` // total realized to this date
+	Components []RealizedGainComponent `json:"components"`
+}
+
+// RealizedGainTimeline is a portfolio's cumulative realized gain over time,
+// one point per date on which a sell (or a cost base adjustment while
+// nothing was held) changed it. Amounts are in the portfolio's base currency.
+type RealizedGainTimeline struct {
+	PortfolioName   string              `json:"portfolio_name"`
+	CostBasisMethod CostBasisMethod     `json:"cost_basis_method"`
+	TotalRealized   float64             `json:"total_realized"`
+	Points          []RealizedGainPoint `json:"points"`
+	Warnings        []string            `json:"warnings,omitempty"`
+}
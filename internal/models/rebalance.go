@@ -0,0 +1,34 @@
+package models
+
+// RebalanceSuggestion is one trade that brings a holding back within the
+// drift tolerance of its target weight.
+type RebalanceSuggestion struct {
+	Ticker           string  `json:"ticker"`
+	Action           string  `json:"action"` // "buy" or "sell"
+	CurrentWeightPct float64 `json:"current_weight_pct"`
+	TargetWeightPct  float64 `json:"target_weight_pct"`
+	DriftPct         float64 `json:"drift_pct"` // current - target, in percentage points
+	Amount           float64 `json:"amount"`    // trade value, always positive
+	Price            float64 `json:"price,omitempty"`
+	Units            float64 `json:"units,omitempty"` // Amount / Price when a price is known
+	Fee              float64 `json:"fee"`
+	NoTarget         bool    `json:"no_target,omitempty"` // held but absent from target_weights: sell to zero
+}
+
+// RebalancePlan lists the trades that move a portfolio to its strategy's
+// target weights and their net effect on available cash.
+type RebalancePlan struct {
+	PortfolioName    string                `json:"portfolio_name"`
+	PortfolioValue   float64               `json:"portfolio_value"`
+	TolerancePct     float64               `json:"tolerance_pct"`
+	TargetsTotalPct  float64               `json:"targets_total_pct"`
+	AvailableCash    float64               `json:"available_cash"`
+	TotalBuys        float64               `json:"total_buys"`
+	TotalSells       float64               `json:"total_sells"`
+	TotalFees        float64               `json:"total_fees"`
+	NetCashRequired  float64               `json:"net_cash_required"` // buys + fees - sells; negative frees cash
+	CashAfter        float64               `json:"cash_after"`
+	InsufficientCash bool                  `json:"insufficient_cash"`
+	Suggestions      []RebalanceSuggestion `json:"suggestions"`
+	Warnings         []string              `json:"warnings,omitempty"`
+}
@@ -7,11 +7,17 @@ import (
 
 // PortfolioReport is a stored report for a portfolio
 type PortfolioReport struct {
-	Portfolio       string         `json:"portfolio"`
-	GeneratedAt     time.Time      `json:"generated_at"`
-	SummaryMarkdown string         `json:"summary_markdown"`
-	TickerReports   []TickerReport `json:"ticker_reports"`
-	Tickers         []string       `json:"tickers"`
+	Portfolio       string          `json:"portfolio"`
+	GeneratedAt     time.Time       `json:"generated_at"`
+	SummaryMarkdown string          `json:"summary_markdown"`
+	TickerReports   []TickerReport  `json:"ticker_reports"`
+	Tickers         []string        `json:"tickers"`
+	Waterfall       *ValueWaterfall `json:"waterfall,omitempty"`
+}
+
+// ID identifies a generated report by its generation time (UTC, to the second).
+func (r *PortfolioReport) ID() string {
+	return r.GeneratedAt.UTC().Format("20060102T150405Z")
 }
 
 // TickerReport is a stored report for a single ticker within a portfolio
@@ -21,3 +27,15 @@ type TickerReport struct {
 	IsETF    bool   `json:"is_etf"`
 	Markdown string `json:"markdown"`
 }
+
+// ReportSearchResult is a ranked match from a report search. Ticker is empty
+// when the match is in the portfolio summary rather than a ticker section.
+type ReportSearchResult struct {
+	Portfolio   string    `json:"portfolio"`
+	ReportID    string    `json:"report_id"`
+	GeneratedAt time.Time `json:"generated_at"`
+	Ticker      string    `json:"ticker,omitempty"`
+	Title       string    `json:"title"`
+	Snippet     string    `json:"snippet"`
+	Score       float64   `json:"score"`
+}
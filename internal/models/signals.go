@@ -5,6 +5,10 @@ import (
 	"time"
 )
 
+// MinNewsSentimentArticles is the fewest recent headlines needed for a news
+// sentiment score. With fewer, the score is left at 0 and no alert is raised.
+const MinNewsSentimentArticles = 3
+
 // TickerSignals contains all computed signals for a ticker
 type TickerSignals struct {
 	Ticker           string    `json:"ticker"`
@@ -14,8 +18,10 @@ type TickerSignals struct {
 	// Core price data
 	Price PriceSignals `json:"price"`
 
-	// Technical signals
+	// Technical signals. RSIPeriod is the lookback Technical.RSI was computed
+	// over (0 on signals stored before it was recorded: DefaultRSIPeriod).
 	Technical TechnicalSignals `json:"technical"`
+	RSIPeriod int              `json:"rsi_period,omitempty"`
 
 	// Advanced signals
 	PBAS          PBASSignal    `json:"pbas"`
@@ -31,6 +37,34 @@ type TickerSignals struct {
 	// Risk tracking
 	RiskFlags       []string `json:"risk_flags"`
 	RiskDescription string   `json:"risk_description"`
+
+	// News sentiment: mean headline score from -1 (negative) to +1 (positive)
+	// over NewsSentimentCount recent articles. Count 0 = not scored.
+	NewsSentiment      float64   `json:"news_sentiment"`
+	NewsSentimentCount int       `json:"news_sentiment_count"`
+	NewsSentimentAt    time.Time `json:"news_sentiment_at"`
+	NewsSentimentHash  string    `json:"news_sentiment_hash,omitempty"` // hash of the headlines scored`
+}
+
+// KeepNewsSentiment copies prev's news sentiment into s when s was not
+// scored, so signals recomputed from EOD keep the last score.
+func (s *TickerSignals) KeepNewsSentiment(prev *TickerSignals) {
+	if prev == nil || !s.NewsSentimentAt.IsZero() || prev.NewsSentimentAt.IsZero() {
+		return
+	}
+	s.NewsSentiment = prev.NewsSentiment
+	s.NewsSentimentCount = prev.NewsSentimentCount
+	s.NewsSentimentAt = prev.NewsSentimentAt
+	s.NewsSentimentHash = prev.NewsSentimentHash
+}
+
+// GetRSIPeriod returns the RSI period the signals were computed with, or
+// DefaultRSIPeriod when not recorded. Safe on nil signals.
+func (s *TickerSignals) GetRSIPeriod() int {
+	if s == nil || s.RSIPeriod < 2 {
+		return DefaultRSIPeriod
+	}
+	return s.RSIPeriod
 }
 
 // PriceSignals contains price-based signal data
@@ -48,16 +82,18 @@ type PriceSignals struct {
 
 // TechnicalSignals contains technical indicator values
 type TechnicalSignals struct {
-	RSI           float64 `json:"rsi"`
-	RSISignal     string  `json:"rsi_signal"` // oversold, neutral, overbought
-	MACD          float64 `json:"macd"`
-	MACDSignal    float64 `json:"macd_signal"`
-	MACDHistogram float64 `json:"macd_histogram"`
-	MACDCrossover string  `json:"macd_crossover"` // bullish, bearish, none
-	VolumeRatio   float64 `json:"volume_ratio"`   // Current vs average
-	VolumeSignal  string  `json:"volume_signal"`  // spike, normal, low
-	ATR           float64 `json:"atr"`
-	ATRPct        float64 `json:"atr_pct"` // ATR as % of price
+	RSI            float64 `json:"rsi"`
+	RSISignal      string  `json:"rsi_signal"` // oversold, neutral, overbought
+	MACD           float64 `json:"macd"`
+	MACDSignal     float64 `json:"macd_signal"`
+	MACDHistogram  float64 `json:"macd_histogram"`
+	MACDCrossover  string  `json:"macd_crossover"`             // bullish, bearish, none
+	MACDCrossEvent string  `json:"macd_cross_event,omitempty"` // bullish_cross, bearish_cross when the histogram flipped sign on the latest bar
+	VolumeRatio    float64 `json:"volume_ratio"`               // Current vs average
+	AvgVolume20    int64   `json:"avg_volume_20"`              // 20-day average volume (0 = missing volume data)
+	VolumeSignal   string  `json:"volume_signal"`              // spike, normal, low
+	ATR            float64 `json:"atr"`
+	ATRPct         float64 `json:"atr_pct"` // ATR as % of price
 
 	// SMA crossover signals
 	SMA20CrossSMA50  string `json:"sma_20_cross_50"`  // golden_cross, death_cross, none
@@ -69,6 +105,14 @@ type TechnicalSignals struct {
 	NearResistance  bool    `json:"near_resistance"`
 	SupportLevel    float64 `json:"support_level"`
 	ResistanceLevel float64 `json:"resistance_level"`
+
+	// Bollinger Bands (20-bar SMA, 2 std dev); zero with fewer than 20 bars
+	BBUpper    float64 `json:"bb_upper"`
+	BBMiddle   float64 `json:"bb_middle"`
+	BBLower    float64 `json:"bb_lower"`
+	BBWidth    float64 `json:"bb_width"`              // (upper - lower) / middle
+	BBSqueeze  bool    `json:"bb_squeeze"`            // width in the lowest decile of the trailing 126 bars
+	BBBreakout string  `json:"bb_breakout,omitempty"` // up, down: close outside the bands shortly after a squeeze
 }
 
 // PBASSignal represents Price-Book-Accumulation Score
@@ -150,6 +194,27 @@ type TrendMomentum struct {
 	Description    string             `json:"description"`      // Human-readable narrative
 }
 
+// SignalBacktest reports how often historical entry signals for a ticker
+// were followed by a gain over a fixed forward horizon. Computed on demand
+// from stored EOD bars — not persisted.
+type SignalBacktest struct {
+	Ticker           string                 `json:"ticker"`
+	HorizonDays      int                    `json:"horizon_days"`   // forward window in trading days
+	BarsEvaluated    int                    `json:"bars_evaluated"` // bars with enough history and a full forward window
+	Results          []SignalBacktestResult `json:"results"`        // per-signal accuracy, plus the combined entry_criteria row
+	ComputeTimestamp time.Time              `json:"compute_timestamp"`
+}
+
+// SignalBacktestResult is the hit-rate for a single signal over the backtest window.
+type SignalBacktestResult struct {
+	Signal          string  `json:"signal"`              // e.g. "rsi_oversold", "golden_cross", "entry_criteria"
+	Occurrences     int     `json:"occurrences"`         // bars where the signal fired
+	Hits            int     `json:"hits"`                // occurrences followed by a gain over the horizon
+	HitRatePct      float64 `json:"hit_rate_pct"`        // hits / occurrences × 100
+	AvgReturnPct    float64 `json:"avg_return_pct"`      // mean forward return across occurrences
+	ThresholdDetail string  `json:"threshold,omitempty"` // threshold applied (e.g. "RSI < 30")
+}
+
 // TrendType classifies overall trend
 type TrendType string
 
@@ -190,3 +255,17 @@ func AllSignalTypes() []string {
 		SignalTypeTrendMomentum,
 	}
 }
+
+// IndicatorPoint holds indicator values as of one EOD bar, computed from that
+// bar and the bars before it. SMAs and MACD are zero, and RSI is the neutral
+// 50, until enough history precedes the bar.
+type IndicatorPoint struct {
+	Date          time.Time `json:"date"`
+	Close         float64   `json:"close"`
+	RSI           float64   `json:"rsi"`
+	SMA50         float64   `json:"sma_50"`
+	SMA200        float64   `json:"sma_200"`
+	MACD          float64   `json:"macd"`
+	MACDSignal    float64   `json:"macd_signal"`
+	MACDHistogram float64   `json:"macd_histogram"`
+}
@@ -36,6 +36,16 @@ type InternalUser struct {
 	ModifiedAt   time.Time `json:"modified_at"`
 }
 
+// FileMeta describes a stored file without its content.
+type FileMeta struct {
+	Category    string    `json:"category"`
+	Key         string    `json:"key"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	CreatedAt   time.Time `json:"created_at"` // first save; kept across overwrites
+	UpdatedAt   time.Time `json:"updated_at"` // latest save
+}
+
 // UserKeyValue represents a per-user configuration key-value pair.
 type UserKeyValue struct {
 	UserID   string    `json:"user_id"`
@@ -55,3 +65,13 @@ type UserRecord struct {
 	Version  int       `json:"version"`
 	DateTime time.Time `json:"datetime"`
 }
+
+// BackupManifest summarises an archive written by StorageManager.Backup or
+// loaded by StorageManager.Restore.
+type BackupManifest struct {
+	SchemaVersion string         `json:"schema_version"`
+	AppVersion    string         `json:"app_version,omitempty"`
+	CreatedAt     time.Time      `json:"created_at"`
+	Records       map[string]int `json:"records"`           // per table
+	Skipped       map[string]int `json:"skipped,omitempty"` // restore only: records of unknown tables
+}
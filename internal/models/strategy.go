@@ -3,6 +3,7 @@ package models
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 )
@@ -50,6 +51,15 @@ type Rule struct {
 	Enabled    bool            `json:"enabled"`
 }
 
+// DerivedMetric is a user-defined per-holding formula, e.g.
+// {Name: "value_to_cost", Expression: "market_value / cost_basis"}.
+// Bare names are holding fields; dotted names are rule field paths.
+type DerivedMetric struct {
+	Name        string `json:"name"`
+	Expression  string `json:"expression"`
+	Description string `json:"description,omitempty"`
+}
+
 // CompanyFilter defines stock selection criteria for the portfolio strategy.
 type CompanyFilter struct {
 	MinMarketCap     float64  `json:"min_market_cap,omitempty"`
@@ -71,6 +81,32 @@ const (
 	AccountTypeTrading AccountType = "trading" // Standard trading account
 )
 
+// CostBasisMethod selects how sells are matched against buy lots when
+// computing realized gains and remaining cost base.
+type CostBasisMethod string
+
+const (
+	CostBasisAverage CostBasisMethod = "average" // Weighted-average cost (default)
+	CostBasisFIFO    CostBasisMethod = "fifo"    // Sells consume the oldest lots first
+	CostBasisLIFO    CostBasisMethod = "lifo"    // Sells consume the newest lots first
+)
+
+// PriceSource selects which provider's price is authoritative for a holding
+// during portfolio sync.
+type PriceSource string
+
+const (
+	PriceSourceAuto   PriceSource = "auto"   // EODHD overrides Navexa when it has a fresher bar (default)
+	PriceSourceNavexa PriceSource = "navexa" // Always keep Navexa's price (e.g. illiquid names)
+	PriceSourceEODHD  PriceSource = "eodhd"  // Use EODHD's latest close whenever one exists
+)
+
+// TickerPriceSource overrides the portfolio price source for one holding.
+type TickerPriceSource struct {
+	Ticker string      `json:"ticker"` // "BHP" or "BHP.AU"
+	Source PriceSource `json:"source"`
+}
+
 // DefaultDisclaimer is pre-populated on new strategies.
 const DefaultDisclaimer = "This portfolio strategy is a personal planning document and does not constitute financial advice. Always consult a licensed financial adviser before making investment decisions."
 
@@ -86,12 +122,19 @@ type PortfolioStrategy struct {
 	IncomeRequirements  IncomeRequirements  `json:"income_requirements"`
 	SectorPreferences   SectorPreferences   `json:"sector_preferences"`
 	PositionSizing      PositionSizing      `json:"position_sizing"`
-	ReferenceStrategies []ReferenceStrategy `json:"reference_strategies"`     // Named strategies displayed in ToMarkdown(), not used in AI prompts
-	Rules               []Rule              `json:"rules,omitempty"`          // Declarative trading rules evaluated against live data
-	CompanyFilter       CompanyFilter       `json:"company_filter,omitempty"` // Stock selection criteria
-	RebalanceFrequency  string              `json:"rebalance_frequency"`      // "monthly", "quarterly", "annually"
-	Notes               string              `json:"notes"`                    // Free-form markdown
-	Disclaimer          string              `json:"disclaimer"`               // "Not financial advice" disclaimer
+	ReferenceStrategies []ReferenceStrategy `json:"reference_strategies"`            // Named strategies displayed in ToMarkdown(), not used in AI prompts
+	Rules               []Rule              `json:"rules,omitempty"`                 // Declarative trading rules evaluated against live data
+	CompanyFilter       CompanyFilter       `json:"company_filter,omitempty"`        // Stock selection criteria
+	RebalanceFrequency  string              `json:"rebalance_frequency"`             // "monthly", "quarterly", "annually"
+	TargetWeights       map[string]float64  `json:"target_weights,omitempty"`        // Ticker → target % of portfolio value, used by rebalance plans
+	CostBasisMethod     CostBasisMethod     `json:"cost_basis_method"`               // "average" (default), "fifo", "lifo"
+	DerivedMetrics      []DerivedMetric     `json:"derived_metrics"`                 // Custom per-holding formulas shown in reviews
+	PriceSource         PriceSource         `json:"price_source"`                    // Authoritative price during sync: "auto" (default), "navexa", "eodhd"
+	PriceSourceByTicker []TickerPriceSource `json:"price_source_by_ticker"`          // Per-holding overrides of PriceSource, keyed by ticker
+	RSIPeriod           int                 `json:"rsi_period,omitempty"`            // RSI lookback in bars for the portfolio's holdings (0 = DefaultRSIPeriod)
+	VolumeSpikeMultiple float64             `json:"volume_spike_multiple,omitempty"` // Volume over 20-day average that raises volume_spike (0 = DefaultVolumeSpikeMultiple)
+	Notes               string              `json:"notes"`                           // Free-form markdown
+	Disclaimer          string              `json:"disclaimer"`                      // "Not financial advice" disclaimer
 	CreatedAt           time.Time           `json:"created_at"`
 	UpdatedAt           time.Time           `json:"updated_at"`
 	LastReviewedAt      time.Time           `json:"last_reviewed_at"` // When strategy was last used in a review
@@ -112,8 +155,9 @@ type TargetReturns struct {
 
 // IncomeRequirements defines dividend/income targets for a portfolio strategy
 type IncomeRequirements struct {
-	DividendYieldPct float64 `json:"dividend_yield_pct"`
-	Description      string  `json:"description"`
+	DividendYieldPct         float64 `json:"dividend_yield_pct"`
+	Description              string  `json:"description"`
+	IncludeDividendsInReturn bool    `json:"include_dividends_in_return"` // Add dividends received to each holding's net return
 }
 
 // SectorPreferences defines preferred and excluded sectors
@@ -126,6 +170,15 @@ type SectorPreferences struct {
 type PositionSizing struct {
 	MaxPositionPct float64 `json:"max_position_pct"` // Max single position %
 	MaxSectorPct   float64 `json:"max_sector_pct"`   // Max sector %
+	MaxHHI         float64 `json:"max_hhi"`          // Max Herfindahl-Hirschman Index of holding weights (0-1)
+	MaxCorrelation float64 `json:"max_correlation"`  // Max daily-return correlation between two holdings (0-1, 0 = no check)
+	StopLossPct    float64 `json:"stop_loss_pct"`    // Exit when unrealized return falls this % below breakeven
+	TakeProfitPct  float64 `json:"take_profit_pct"`  // Take profit when unrealized return rises this % above breakeven
+	// Earnings event risk: alert when a holding reports within EarningsWindowDays
+	// (0 = no check), and WATCH it when its weight is at least EarningsWatchPct
+	// (0 = alert only)
+	EarningsWindowDays int     `json:"earnings_window_days"`
+	EarningsWatchPct   float64 `json:"earnings_watch_pct"`
 }
 
 // ReferenceStrategy is a named investment approach referenced in the strategy document
@@ -141,6 +194,68 @@ type StrategyWarning struct {
 	Message  string `json:"message"`  // Human-readable warning
 }
 
+// DefaultRSIPeriod is the RSI lookback used when a strategy does not set one.
+const DefaultRSIPeriod = 14
+
+// GetRSIPeriod returns the strategy's RSI period, or DefaultRSIPeriod when
+// unset or invalid. Safe on a nil strategy.
+func (s *PortfolioStrategy) GetRSIPeriod() int {
+	if s == nil || s.RSIPeriod < 2 {
+		return DefaultRSIPeriod
+	}
+	return s.RSIPeriod
+}
+
+// RSIThresholds returns the RSI overbought (sell) and oversold (buy)
+// thresholds for the strategy's risk appetite level: moderate (70/30) when
+// unset or unknown. Safe on a nil strategy.
+func (s *PortfolioStrategy) RSIThresholds() (overbought, oversold float64) {
+	if s == nil {
+		return 70, 30
+	}
+	switch strings.ToLower(s.RiskAppetite.Level) {
+	case "conservative":
+		return 65, 35 // sell earlier, buy later (more cautious)
+	case "aggressive":
+		return 80, 25 // sell later, buy earlier (more risk-tolerant)
+	default: // "moderate" or unknown
+		return 70, 30
+	}
+}
+
+// DefaultVolumeSpikeMultiple is the volume-to-average ratio that raises a
+// volume_spike alert when a strategy does not set one.
+const DefaultVolumeSpikeMultiple = 2.0
+
+// GetVolumeSpikeMultiple returns the strategy's volume spike multiple, or
+// DefaultVolumeSpikeMultiple when unset. Safe on a nil strategy.
+func (s *PortfolioStrategy) GetVolumeSpikeMultiple() float64 {
+	if s == nil || s.VolumeSpikeMultiple <= 0 {
+		return DefaultVolumeSpikeMultiple
+	}
+	return s.VolumeSpikeMultiple
+}
+
+// PriceSourceFor returns the price source for a holding: a per-ticker
+// override (matched on the plain or exchange-qualified ticker, case-insensitive),
+// else the portfolio setting, else auto. Safe on a nil strategy.
+func (s *PortfolioStrategy) PriceSourceFor(tickers ...string) PriceSource {
+	if s == nil {
+		return PriceSourceAuto
+	}
+	for _, t := range tickers {
+		for _, o := range s.PriceSourceByTicker {
+			if o.Source != "" && strings.EqualFold(o.Ticker, t) {
+				return o.Source
+			}
+		}
+	}
+	if s.PriceSource != "" {
+		return s.PriceSource
+	}
+	return PriceSourceAuto
+}
+
 // ToMarkdown renders the strategy as a readable markdown document.
 func (s *PortfolioStrategy) ToMarkdown() string {
 	var b strings.Builder
@@ -180,7 +295,7 @@ func (s *PortfolioStrategy) ToMarkdown() string {
 	b.WriteString("\n")
 
 	// Income Requirements
-	if s.IncomeRequirements.DividendYieldPct > 0 || s.IncomeRequirements.Description != "" {
+	if s.IncomeRequirements.DividendYieldPct > 0 || s.IncomeRequirements.Description != "" || s.IncomeRequirements.IncludeDividendsInReturn {
 		b.WriteString("## Income Requirements\n\n")
 		if s.IncomeRequirements.DividendYieldPct > 0 {
 			b.WriteString(fmt.Sprintf("- **Dividend Yield Target:** %.1f%%\n", s.IncomeRequirements.DividendYieldPct))
@@ -188,6 +303,9 @@ func (s *PortfolioStrategy) ToMarkdown() string {
 		if s.IncomeRequirements.Description != "" {
 			b.WriteString(fmt.Sprintf("- %s\n", s.IncomeRequirements.Description))
 		}
+		if s.IncomeRequirements.IncludeDividendsInReturn {
+			b.WriteString("- **Net Return:** includes dividends received\n")
+		}
 		b.WriteString("\n")
 	}
 
@@ -211,6 +329,27 @@ func (s *PortfolioStrategy) ToMarkdown() string {
 	if s.PositionSizing.MaxSectorPct > 0 {
 		b.WriteString(fmt.Sprintf("- **Max Sector Allocation:** %.1f%%\n", s.PositionSizing.MaxSectorPct))
 	}
+	if s.PositionSizing.MaxHHI > 0 {
+		b.WriteString(fmt.Sprintf("- **Max Concentration (HHI):** %.2f\n", s.PositionSizing.MaxHHI))
+	}
+	if s.PositionSizing.MaxCorrelation > 0 {
+		b.WriteString(fmt.Sprintf("- **Max Holding Correlation:** %.2f\n", s.PositionSizing.MaxCorrelation))
+	}
+	if s.PositionSizing.EarningsWindowDays > 0 {
+		b.WriteString(fmt.Sprintf("- **Earnings Alert Window:** %d days\n", s.PositionSizing.EarningsWindowDays))
+		if s.PositionSizing.EarningsWatchPct > 0 {
+			b.WriteString(fmt.Sprintf("- **Watch Before Earnings Above:** %.1f%%\n", s.PositionSizing.EarningsWatchPct))
+		}
+	}
+	if s.PositionSizing.StopLossPct > 0 {
+		b.WriteString(fmt.Sprintf("- **Stop Loss:** -%.1f%%\n", s.PositionSizing.StopLossPct))
+	}
+	if s.PositionSizing.TakeProfitPct > 0 {
+		b.WriteString(fmt.Sprintf("- **Take Profit:** +%.1f%%\n", s.PositionSizing.TakeProfitPct))
+	}
+	if s.RSIPeriod > 0 && s.RSIPeriod != DefaultRSIPeriod {
+		b.WriteString(fmt.Sprintf("- **RSI Period:** %d bars\n", s.RSIPeriod))
+	}
 	b.WriteString("\n")
 
 	// Reference Strategies
@@ -286,6 +425,22 @@ func (s *PortfolioStrategy) ToMarkdown() string {
 	if s.RebalanceFrequency != "" {
 		b.WriteString(fmt.Sprintf("**Rebalancing:** %s\n\n", s.RebalanceFrequency))
 	}
+	if len(s.TargetWeights) > 0 {
+		tickers := make([]string, 0, len(s.TargetWeights))
+		for t := range s.TargetWeights {
+			tickers = append(tickers, t)
+		}
+		sort.Strings(tickers)
+		b.WriteString("**Target Weights:**\n")
+		for _, t := range tickers {
+			b.WriteString(fmt.Sprintf("- %s: %.1f%%\n", t, s.TargetWeights[t]))
+		}
+		b.WriteString("\n")
+	}
+
+	if s.CostBasisMethod != "" {
+		b.WriteString(fmt.Sprintf("**Cost Basis:** %s\n\n", s.CostBasisMethod))
+	}
 
 	// Notes
 	if s.Notes != "" {
@@ -0,0 +1,141 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// maxStrategyDecodeRepairs bounds how many mismatched fields DecodeStrategy
+// will drop before giving up on a record.
+const maxStrategyDecodeRepairs = 32
+
+// DecodeStrategy decodes a stored strategy written by any earlier release.
+//
+// Fields added since the record was written are missing from the JSON and
+// are filled with their defaults (see applyStrategyDefaults). Fields whose
+// stored type no longer matches the model are dropped so they keep their
+// zero value, and every other setting survives; a mistyped array element is
+// dropped on its own. The JSON paths of dropped fields are returned so
+// callers can log them.
+//
+// In strict mode a type mismatch is returned as an error instead. Malformed
+// JSON is an error in either mode.
+func DecodeStrategy(data []byte, strict bool) (*PortfolioStrategy, []string, error) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal strategy: %w", err)
+	}
+
+	var dropped []string
+	for attempt := 0; ; attempt++ {
+		var strategy PortfolioStrategy
+		err := json.Unmarshal(data, &strategy)
+		if err == nil {
+			applyStrategyDefaults(&strategy, raw)
+			return &strategy, dropped, nil
+		}
+
+		var typeErr *json.UnmarshalTypeError
+		if strict || !errors.As(err, &typeErr) || typeErr.Field == "" || attempt >= maxStrategyDecodeRepairs {
+			return nil, dropped, fmt.Errorf("failed to unmarshal strategy: %w", err)
+		}
+		field := dropJSONPath(raw, strings.Split(typeErr.Field, "."))
+		if field == "" {
+			return nil, dropped, fmt.Errorf("failed to unmarshal strategy: %w", err)
+		}
+		dropped = append(dropped, field)
+		if data, err = json.Marshal(raw); err != nil {
+			return nil, dropped, fmt.Errorf("failed to re-encode strategy: %w", err)
+		}
+	}
+}
+
+// dropJSONPath removes the value at path from a decoded JSON object and
+// returns the path actually removed, with array indices written as "[i]".
+// Numeric segments of the type error's path index arrays; when the path
+// passes through one, only the innermost element on it is removed so its
+// siblings survive. Returns "" when nothing could be removed.
+func dropJSONPath(obj map[string]interface{}, path []string) string {
+	cut, label, cutLabel := len(path), "", ""
+	var cur interface{} = obj
+	for i, seg := range path {
+		switch c := cur.(type) {
+		case map[string]interface{}:
+			v, ok := c[seg]
+			if !ok {
+				return ""
+			}
+			if i > 0 {
+				label += "."
+			}
+			label += seg
+			cur = v
+		case []interface{}:
+			idx, err := strconv.Atoi(seg)
+			if err != nil || idx < 0 || idx >= len(c) {
+				return ""
+			}
+			label += "[" + seg + "]"
+			cut, cutLabel = i+1, label
+			cur = c[idx]
+		default:
+			return ""
+		}
+	}
+	if cut == len(path) {
+		cutLabel = label
+	}
+	if _, ok := removeJSONPath(obj, path[:cut]); !ok {
+		return ""
+	}
+	return cutLabel
+}
+
+// removeJSONPath removes the value at path below v and returns v with the
+// removal applied; arrays are re-sliced, so callers store the result back.
+func removeJSONPath(v interface{}, path []string) (interface{}, bool) {
+	switch c := v.(type) {
+	case map[string]interface{}:
+		child, ok := c[path[0]]
+		if !ok {
+			return v, false
+		}
+		if len(path) == 1 {
+			delete(c, path[0])
+			return c, true
+		}
+		child, ok = removeJSONPath(child, path[1:])
+		c[path[0]] = child
+		return c, ok
+	case []interface{}:
+		idx, err := strconv.Atoi(path[0])
+		if err != nil || idx < 0 || idx >= len(c) {
+			return v, false
+		}
+		if len(path) == 1 {
+			return append(c[:idx:idx], c[idx+1:]...), true
+		}
+		child, ok := removeJSONPath(c[idx], path[1:])
+		c[idx] = child
+		return c, ok
+	}
+	return v, false
+}
+
+// applyStrategyDefaults fills fields that did not exist when the record was
+// written. Only keys absent from the stored JSON are touched, so a value the
+// user deliberately cleared stays cleared.
+func applyStrategyDefaults(s *PortfolioStrategy, raw map[string]interface{}) {
+	if _, ok := raw["cost_basis_method"]; !ok && s.CostBasisMethod == "" {
+		s.CostBasisMethod = CostBasisAverage
+	}
+	if _, ok := raw["price_source"]; !ok && s.PriceSource == "" {
+		s.PriceSource = PriceSourceAuto
+	}
+	if _, ok := raw["disclaimer"]; !ok && s.Disclaimer == "" {
+		s.Disclaimer = DefaultDisclaimer
+	}
+}
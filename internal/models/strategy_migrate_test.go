@@ -0,0 +1,96 @@
+package models
+
+import (
+	"testing"
+)
+
+// oldStrategyJSON predates cost_basis_method, price_source, disclaimer and
+// target_weights, and stored max_position_pct as a string.
+const oldStrategyJSON = `{
+	"portfolio_name": "SMSF",
+	"version": 3,
+	"account_type": "smsf",
+	"investment_universe": ["AU"],
+	"risk_appetite": {"level": "moderate", "max_drawdown_pct": 15},
+	"position_sizing": {"max_position_pct": "10", "max_sector_pct": 30},
+	"rebalance_frequency": "quarterly",
+	"notes": "keep it simple"
+}`
+
+func TestDecodeStrategy_OldFormat(t *testing.T) {
+	s, dropped, err := DecodeStrategy([]byte(oldStrategyJSON), false)
+	if err != nil {
+		t.Fatalf("DecodeStrategy: %v", err)
+	}
+
+	// Old settings survive
+	if s.PortfolioName != "SMSF" || s.Version != 3 || s.AccountType != AccountTypeSMSF {
+		t.Errorf("identity = %s v%d %s, want SMSF v3 smsf", s.PortfolioName, s.Version, s.AccountType)
+	}
+	if s.RiskAppetite.Level != "moderate" || s.RiskAppetite.MaxDrawdownPct != 15 {
+		t.Errorf("RiskAppetite = %+v, want moderate/15", s.RiskAppetite)
+	}
+	if s.PositionSizing.MaxSectorPct != 30 || s.RebalanceFrequency != "quarterly" || s.Notes != "keep it simple" {
+		t.Errorf("sibling fields lost: sector=%v freq=%q notes=%q", s.PositionSizing.MaxSectorPct, s.RebalanceFrequency, s.Notes)
+	}
+
+	// The mistyped field is reset and reported
+	if s.PositionSizing.MaxPositionPct != 0 {
+		t.Errorf("MaxPositionPct = %v, want 0 after reset", s.PositionSizing.MaxPositionPct)
+	}
+	if len(dropped) != 1 || dropped[0] != "position_sizing.max_position_pct" {
+		t.Errorf("dropped = %v, want [position_sizing.max_position_pct]", dropped)
+	}
+
+	// New fields take their defaults
+	if s.CostBasisMethod != CostBasisAverage || s.PriceSource != PriceSourceAuto || s.Disclaimer != DefaultDisclaimer {
+		t.Errorf("defaults = %q %q %q", s.CostBasisMethod, s.PriceSource, s.Disclaimer)
+	}
+	if s.TargetWeights != nil {
+		t.Errorf("TargetWeights = %v, want nil", s.TargetWeights)
+	}
+}
+
+func TestDecodeStrategy_Strict(t *testing.T) {
+	if _, _, err := DecodeStrategy([]byte(oldStrategyJSON), true); err == nil {
+		t.Error("expected strict decode to fail on the mistyped field")
+	}
+	if _, _, err := DecodeStrategy([]byte(`{"portfolio_name": `), false); err == nil {
+		t.Error("expected malformed JSON to fail")
+	}
+}
+
+func TestDecodeStrategy_ClearedFieldsStayCleared(t *testing.T) {
+	s, _, err := DecodeStrategy([]byte(`{"portfolio_name": "X", "disclaimer": "", "price_source": "navexa"}`), false)
+	if err != nil {
+		t.Fatalf("DecodeStrategy: %v", err)
+	}
+	if s.Disclaimer != "" || s.PriceSource != PriceSourceNavexa {
+		t.Errorf("stored values overwritten: disclaimer=%q price_source=%q", s.Disclaimer, s.PriceSource)
+	}
+}
+
+func TestDecodeStrategy_DropsOnlyTheMistypedArrayElement(t *testing.T) {
+	data := `{
+		"portfolio_name": "SMSF",
+		"rules": [
+			{"name": "first", "priority": 1},
+			{"name": "second", "priority": "high"},
+			{"name": "third", "conditions": [{"field": "signals.rsi"}, {"field": 7}]}
+		]
+	}`
+	s, dropped, err := DecodeStrategy([]byte(data), false)
+	if err != nil {
+		t.Fatalf("DecodeStrategy: %v", err)
+	}
+	if len(s.Rules) != 2 || s.Rules[0].Name != "first" || s.Rules[1].Name != "third" {
+		t.Fatalf("rules = %+v, want first and third kept", s.Rules)
+	}
+	if conds := s.Rules[1].Conditions; len(conds) != 1 || conds[0].Field != "signals.rsi" {
+		t.Errorf("third rule conditions = %+v, want only the well-typed one", conds)
+	}
+	want := []string{"rules[1]", "rules[1].conditions[1]"}
+	if len(dropped) != len(want) || dropped[0] != want[0] || dropped[1] != want[1] {
+		t.Errorf("dropped = %v, want %v", dropped, want)
+	}
+}
@@ -128,5 +128,6 @@ type DerivedHolding struct {
 	MarketValue      float64 `json:"market_value"`
 	GrossInvested    float64 `json:"gross_invested"`
 	GrossProceeds    float64 `json:"gross_proceeds"`
+	TotalFees        float64 `json:"total_fees"`
 	TradeCount       int     `json:"trade_count"`
 }
@@ -0,0 +1,23 @@
+package models
+
+// TradeExportSkip is a trade left out of an import file, with the reason.
+type TradeExportSkip struct {
+	Ticker string  `json:"ticker"`
+	Date   string  `json:"date"`
+	Type   string  `json:"type"`
+	Units  float64 `json:"units"`
+	Value  float64 `json:"value,omitempty"`
+	Reason string  `json:"reason"`
+}
+
+// TradeImportExport is a portfolio's trade history written as another
+// platform's trade import CSV. Trades the format cannot express are listed
+// in Skipped rather than written, so they can be entered by hand.
+type TradeImportExport struct {
+	PortfolioName string            `json:"portfolio_name"`
+	Format        string            `json:"format"`
+	CSV           string            `json:"csv"`
+	Rows          int               `json:"rows"` // data rows, excluding the header
+	Skipped       []TradeExportSkip `json:"skipped,omitempty"`
+	Warnings      []string          `json:"warnings,omitempty"`
+}
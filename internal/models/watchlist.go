@@ -50,15 +50,17 @@ func ValidWatchlistVerdict(v WatchlistVerdict) bool {
 
 // WatchlistItem represents a stock verdict on the watchlist
 type WatchlistItem struct {
-	Ticker     string           `json:"ticker"`      // e.g. "SGI.AU"
-	Name       string           `json:"name"`        // Company name
-	Verdict    WatchlistVerdict `json:"verdict"`     // PASS/WATCH/FAIL
-	Reason     string           `json:"reason"`      // Summary reasoning
-	KeyMetrics string           `json:"key_metrics"` // Revenue, PE, etc snapshot
-	Notes      string           `json:"notes"`       // Additional notes
-	ReviewedAt time.Time        `json:"reviewed_at"` // When verdict was last changed
-	CreatedAt  time.Time        `json:"created_at"`  // First added
-	UpdatedAt  time.Time        `json:"updated_at"`  // Last modified
+	Ticker      string           `json:"ticker"`                 // e.g. "SGI.AU"
+	Name        string           `json:"name"`                   // Company name
+	Verdict     WatchlistVerdict `json:"verdict"`                // PASS/WATCH/FAIL
+	Reason      string           `json:"reason"`                 // Summary reasoning
+	KeyMetrics  string           `json:"key_metrics"`            // Revenue, PE, etc snapshot
+	Notes       string           `json:"notes"`                  // Additional notes
+	TargetPrice float64          `json:"target_price,omitempty"` // Price that raises watchlist_target_hit when crossed
+	LastPrice   float64          `json:"last_price,omitempty"`   // Price seen by the previous target check
+	ReviewedAt  time.Time        `json:"reviewed_at"`            // When verdict was last changed
+	CreatedAt   time.Time        `json:"created_at"`             // First added
+	UpdatedAt   time.Time        `json:"updated_at"`             // Last modified
 }
 
 // PortfolioWatchlist is a versioned collection of stock verdicts
@@ -176,7 +178,26 @@ func writeWatchlistItem(b *strings.Builder, item WatchlistItem) {
 	if item.KeyMetrics != "" {
 		b.WriteString(fmt.Sprintf("  - Metrics: %s\n", item.KeyMetrics))
 	}
+	if item.TargetPrice > 0 {
+		b.WriteString(fmt.Sprintf("  - Target: $%.2f\n", item.TargetPrice))
+	}
 	if item.Notes != "" {
 		b.WriteString(fmt.Sprintf("  - *%s*\n", item.Notes))
 	}
 }
+
+// WatchlistEventTargetHit is the event type recorded when a price crosses a watchlist target
+const WatchlistEventTargetHit = "watchlist_target_hit"
+
+// WatchlistEvent records a price event on a watchlist item.
+// Direction is "up" when the price rose through the target and "down" when it fell through it.
+type WatchlistEvent struct {
+	Type          string    `json:"type"`
+	PortfolioName string    `json:"portfolio_name"`
+	Ticker        string    `json:"ticker"`
+	TargetPrice   float64   `json:"target_price"`
+	PreviousPrice float64   `json:"previous_price"`
+	Price         float64   `json:"price"`
+	Direction     string    `json:"direction"`
+	OccurredAt    time.Time `json:"occurred_at"`
+}
@@ -319,7 +319,7 @@ func buildToolCatalog() []models.ToolDefinition {
 			Method:      "GET",
 			Path:        "/api/admin/jobs",
 			Params: []models.ParamDefinition{
-				{Name: "status", Type: "string", Description: "Filter by status: pending, running, completed, failed", In: "query"},
+				{Name: "status", Type: "string", Description: "Filter by status: pending, running, completed, failed, dead_letter", In: "query"},
 				{Name: "ticker", Type: "string", Description: "Filter by ticker symbol (e.g. 'BHP.AU')", In: "query"},
 				{Name: "limit", Type: "number", Description: "Maximum results (default: 100, max: 1000)", In: "query"},
 			},
@@ -331,6 +331,33 @@ func buildToolCatalog() []models.ToolDefinition {
 			Path:        "/api/admin/jobs/queue",
 			Params:      []models.ParamDefinition{},
 		},
+		{
+			Name:        "admin_export_job_queue",
+			Description: "Export the full job queue (pending, running, completed, failed, cancelled) as JSON with timestamps, errors, status counts, and per-type stats (average duration, queue wait, failure rate) for offline throughput diagnosis. Admin access required.",
+			Method:      "GET",
+			Path:        "/api/admin/jobs/export",
+			Params: []models.ParamDefinition{
+				{Name: "limit", Type: "number", Description: "Maximum jobs to export, most recent first (default: 1000, max: 10000)", In: "query"},
+			},
+		},
+		{
+			Name:        "admin_list_dead_letter_jobs",
+			Description: "List dead-lettered jobs: jobs that failed on every allowed attempt, most recent first, with their final error. They stay out of the queue until requeued. Admin access required.",
+			Method:      "GET",
+			Path:        "/api/admin/jobs/dead-letter",
+			Params: []models.ParamDefinition{
+				{Name: "limit", Type: "number", Description: "Maximum results (default: 100, max: 1000)", In: "query"},
+			},
+		},
+		{
+			Name:        "admin_requeue_dead_letter_job",
+			Description: "Return a dead-lettered job to the queue as pending, resetting its attempts and clearing its error. Admin access required.",
+			Method:      "POST",
+			Path:        "/api/admin/jobs/{id}/requeue",
+			Params: []models.ParamDefinition{
+				{Name: "id", Type: "string", Description: "Job ID from admin_list_dead_letter_jobs", Required: true, In: "path"},
+			},
+		},
 		{
 			Name:        "admin_enqueue_job",
 			Description: "Manually enqueue a background job. Bypasses freshness checks. Admin access required. Job types: collect_eod, collect_fundamentals, collect_filings, collect_filing_pdfs, collect_filing_summaries, collect_timeline, collect_news, collect_news_intel, compute_signals.",
@@ -349,6 +376,53 @@ func buildToolCatalog() []models.ToolDefinition {
 			Path:        "/api/admin/stock-index",
 			Params:      []models.ParamDefinition{},
 		},
+		{
+			Name:        "admin_reload_config",
+			Description: "Force a re-read of the server config file without a restart. Hot-reloadable fields (logging.level, EODHD and Navexa rate limits, Navexa retry settings, portfolio.price_freshness, jobmanager.watcher_interval) are applied and listed under applied with old and new values. Fields that need a restart (server host/port, storage, log outputs) are listed under restart_required and left unchanged. An unparseable or invalid file is rejected and the live config is kept. The file is also watched and reloaded automatically on save. Admin access required.",
+			Method:      "POST",
+			Path:        "/api/admin/config/reload",
+			Params:      []models.ParamDefinition{},
+		},
+		{
+			Name:        "admin_set_api_key",
+			Description: "Rotate a server-wide API key without a restart. The new key is probed against the provider first (EODHD /user, Gemini model list); a rejected key changes nothing. A valid key is saved to the system KV store and swapped into the live client. If an environment variable also supplies the key, the response warns that it will win after a restart. Navexa keys are per user and set via the user profile. Admin access required.",
+			Method:      "POST",
+			Path:        "/api/admin/api-keys/{name}",
+			Params: []models.ParamDefinition{
+				{Name: "name", Type: "string", Description: "Key name: 'eodhd_api_key' or 'gemini_api_key'", Required: true, In: "path"},
+				{Name: "key", Type: "string", Description: "The new API key", Required: true, In: "body"},
+			},
+		},
+		{
+			Name:        "admin_clear_api_key",
+			Description: "Remove a stored server-wide API key and switch the live client back to the key from the environment or config file. Fails without changes when there is no key to fall back to; use admin_set_api_key to replace a leaked key. Admin access required.",
+			Method:      "DELETE",
+			Path:        "/api/admin/api-keys/{name}",
+			Params: []models.ParamDefinition{
+				{Name: "name", Type: "string", Description: "Key name: 'eodhd_api_key' or 'gemini_api_key'", Required: true, In: "path"},
+			},
+		},
+		{
+			Name:        "admin_test_webhook",
+			Description: "Send a sample event (type 'test') to every webhook configured under [notifications.webhooks] and report each delivery: delivered or not, attempts made, last HTTP status and error. Uses the same HMAC signing (X-Vire-Signature: sha256=<hex>), retries and dead-letter log as live events, and waits for retries to finish. Live events are sent for newly raised review alerts, triggered plan items and watchlist target hits. Admin access required.",
+			Method:      "POST",
+			Path:        "/api/admin/webhooks/test",
+		},
+		{
+			Name:        "admin_backup_data",
+			Description: "Write a full backup of the data store to the file store: every user data record (portfolios, strategies, plans, watchlists, reports, notes), market data, signals and the stock index, as a gzip JSON-lines archive stamped with the schema version. Returns the archive key and a download path (GET /api/admin/backups/{key}). Archives over 7 MB cannot be kept in the file store; download those directly from GET /api/admin/backups/export. Accounts, credentials, system settings, jobs, logs and stored files are not included. Admin access required.",
+			Method:      "POST",
+			Path:        "/api/admin/backups",
+		},
+		{
+			Name:        "admin_restore_data",
+			Description: "Restore a backup created by admin_backup_data. Records are staged as the archive is read and written in one transaction, so a corrupt archive or failed write changes nothing; archives from a different schema version than this server are refused. This is a point-in-time restore: the backed-up tables (user data, market data, signals, stock index) are replaced, so records created since the backup are removed. An archive can also be uploaded directly as the raw gzip POST body. Admin access required.",
+			Method:      "POST",
+			Path:        "/api/admin/backups/restore",
+			Params: []models.ParamDefinition{
+				{Name: "key", Type: "string", Description: "Backup key returned by admin_backup_data (e.g. 'vire-backup-20260101T000000Z.jsonl.gz')", Required: true, In: "body"},
+			},
+		},
 		{
 			Name:        "admin_rebuild_timeline",
 			Description: "Force-rebuild a portfolio's timeline from scratch. Deletes all persisted timeline data and triggers a full recompute including cash balance integration. Admin access required. This is an async operation — the timeline rebuilds in the background.",
@@ -382,7 +456,7 @@ func buildToolCatalog() []models.ToolDefinition {
 		},
 		{
 			Name:        "portfolio_get",
-			Description: "FAST: Get current portfolio holdings — tickers, names, values, weights, and net returns. By default, only open positions (units > 0) are returned. Set include_closed=true to include closed (fully sold) positions. Return percentages use total capital invested as denominator (average cost basis for partial sells). Includes realized/unrealized net return breakdown and true breakeven price (accounts for prior realized P&L). Includes portfolio and per-holding historical values (portfolio_yesterday_value, portfolio_yesterday_change_pct, portfolio_last_week_value, portfolio_last_week_change_pct from EOD data). Includes net_cash_yesterday_flow and net_cash_last_week_flow (net cash deposits minus withdrawals for adjusting daily/weekly change). Includes capital_performance (XIRR annualized return, simple return, total capital in/out) from manual transactions or auto-derived from trade history. Key value fields: portfolio_value (equity_holdings_value + capital_available), equity_holdings_cost (net capital in equities from trades), capital_available (capital_gross - equity_holdings_cost), portfolio_return/portfolio_return_pct (vs capital_contributions_net). Includes income_dividends_navexa (portfolio-level sum of holding dividend_return, already FX-converted to AUD). Includes income_dividends_forecast (forecasted dividends: Navexa total minus Navexa forecast amounts for holdings with confirmed ledger payments). Includes income_dividends_received (confirmed dividends from cash flow ledger, distinct from income_dividends_navexa which is Navexa-calculated). Includes per-holding last_month_close_price, last_month_price_change_pct (22 trading days), trend_label (from cached signals: Strong Uptrend/Uptrend/Consolidating/Downtrend/Strong Downtrend), and trend_score (-1.0 to +1.0). Trades are excluded from portfolio response; use portfolio_get_stock for trade history. No signals, charts, or AI analysis. Use portfolio_review_compliance for full analysis.",
+			Description: "FAST: Get current portfolio holdings — tickers, names, values, weights, and net returns. By default, only open positions (units > 0) are returned. Set include_closed=true to include closed (fully sold) positions. Return percentages use total capital invested as denominator (average cost basis for partial sells). Includes realized/unrealized net return breakdown and true breakeven price (accounts for prior realized P&L). Includes portfolio and per-holding historical values (portfolio_yesterday_value, portfolio_yesterday_change_pct, portfolio_last_week_value, portfolio_last_week_change_pct from EOD data). Includes net_cash_yesterday_flow and net_cash_last_week_flow (net cash deposits minus withdrawals for adjusting daily/weekly change). Includes capital_performance (XIRR annualized return, simple return, total capital in/out) from manual transactions or auto-derived from trade history. Key value fields: portfolio_value (equity_holdings_value + capital_available), equity_holdings_cost (net capital in equities from trades), capital_available (capital_gross - equity_holdings_cost), portfolio_return/portfolio_return_pct (vs capital_contributions_net). Includes income_dividends_navexa (portfolio-level sum of holding dividend_return, already FX-converted to AUD). Includes income_dividends_forecast (forecasted dividends: Navexa total minus Navexa forecast amounts for holdings with confirmed ledger payments). Includes income_dividends_received (confirmed dividends from cash flow ledger, distinct from income_dividends_navexa which is Navexa-calculated). Includes per-holding last_month_close_price, last_month_price_change_pct (~21 trading days), last_quarter_close_price, last_quarter_price_change_pct (~63 trading days), and portfolio_last_month_value/change_pct and portfolio_last_quarter_value/change_pct, trend_label (from cached signals: Strong Uptrend/Uptrend/Consolidating/Downtrend/Strong Downtrend), and trend_score (-1.0 to +1.0). Trades are excluded from portfolio response; use portfolio_get_stock for trade history. No signals, charts, or AI analysis. Use portfolio_review_compliance for full analysis.",
 			Method:      "GET",
 			Path:        "/api/portfolios/{portfolio_name}",
 			Params: []models.ParamDefinition{
@@ -405,6 +479,12 @@ func buildToolCatalog() []models.ToolDefinition {
 					Description: "Include closed positions (units = 0) in the holdings array (default: false)",
 					In:          "query",
 				},
+				{
+					Name:        "contribution_period",
+					Type:        "string",
+					Description: "Window for each holding's return_contribution_pct: day, week, month or quarter (default: week)",
+					In:          "query",
+				},
 			},
 		},
 		{
@@ -429,6 +509,38 @@ func buildToolCatalog() []models.ToolDefinition {
 				},
 			},
 		},
+		{
+			Name:        "portfolio_sync_holding",
+			Description: "Refresh a single holding from Navexa: re-fetches its trades and price, replaces it in the stored portfolio and recomputes the portfolio totals and weights. Faster than a full portfolio sync when one position has changed. Other holdings keep their stored values. Returns the refreshed holding; errors if the ticker is not in the portfolio.",
+			Method:      "POST",
+			Path:        "/api/portfolios/{portfolio_name}/stock/{ticker}/sync",
+			Params: []models.ParamDefinition{
+				portfolioParam,
+				{Name: "ticker", Type: "string", Required: true, Description: "Ticker symbol (e.g. 'BHP' or 'BHP.AU')", In: "path"},
+			},
+		},
+		{
+			Name:        "holding_note_annotate",
+			Description: "Set your own short note on a holding (e.g. 'core position', 'trimming'). Shown as user_note on the holding, kept across syncs and after the position is closed. An empty note clears it. This is a one-line label stored with the holding's tags; it is separate from the structured research notes of holding_note_* (thesis, behaviours, alert muting), which it neither reads nor changes.",
+			Method:      "PUT",
+			Path:        "/api/portfolios/{portfolio_name}/stock/{ticker}/note",
+			Params: []models.ParamDefinition{
+				portfolioParam,
+				{Name: "ticker", Type: "string", Required: true, Description: "Ticker symbol (e.g. 'BHP.AU')", In: "path"},
+				{Name: "note", Type: "string", Required: true, Description: "Note text (max 2000 chars). Empty clears the note.", In: "body"},
+			},
+		},
+		{
+			Name:        "holding_note_set_tags",
+			Description: "Replace the tags on a holding (e.g. ['core', 'income']). Shown as tags on the holding and kept across syncs. An empty list clears them.",
+			Method:      "PUT",
+			Path:        "/api/portfolios/{portfolio_name}/stock/{ticker}/tags",
+			Params: []models.ParamDefinition{
+				portfolioParam,
+				{Name: "ticker", Type: "string", Required: true, Description: "Ticker symbol (e.g. 'BHP.AU')", In: "path"},
+				{Name: "tags", Type: "array", Required: true, Description: "Tags (max 20, each up to 40 chars). Duplicates are dropped.", In: "body"},
+			},
+		},
 		{
 			Name:        "portfolio_get_stock_timeline",
 			Description: "Get daily value timeline for a single stock holding within a portfolio. Shows units, cost basis, close price, market value, and returns per day from first trade to today. No signals or fundamentals — use market_get_stock_data for market analysis.",
@@ -467,6 +579,51 @@ func buildToolCatalog() []models.ToolDefinition {
 					Description: "Include news sentiment analysis (default: false)",
 					In:          "body",
 				},
+				{
+					Name:        "benchmark_ticker",
+					Type:        "string",
+					Description: "EODHD ticker to compare against (e.g., 'STW.AU', 'GSPC.INDX'). Adds benchmark_return and excess_return over the window covered by both the portfolio history and the benchmark's EOD data.",
+					In:          "body",
+				},
+				{
+					Name:        "force_refresh",
+					Type:        "boolean",
+					Description: "Regenerate the AI summary instead of reusing a cached one for identical inputs (default: false)",
+					In:          "body",
+				},
+			},
+		},
+		{
+			Name:        "portfolio_review_stream",
+			Description: "Runs the portfolio_review_compliance review (same market data refresh, strategy and signals) and streams it as server-sent events: a \"review\" event with the review (no summary), \"summary\" events carrying AI summary text chunks as Gemini produces them, then \"done\" (or \"error\"). Unlike portfolio_review_compliance it returns no strategy or growth series, and the summary is always generated fresh. Requires Gemini.",
+			Method:      "POST",
+			Path:        "/api/portfolios/{portfolio_name}/review/stream",
+			Params: []models.ParamDefinition{
+				{
+					Name:        "portfolio_name",
+					Type:        "string",
+					Description: "Name of the portfolio to review. Uses default portfolio if not specified.",
+					In:          "path",
+					DefaultFrom: "user_config.default_portfolio",
+				},
+				{
+					Name:        "focus_signals",
+					Type:        "array",
+					Description: "Signal types to focus on: sma, rsi, volume, pbas, vli, regime, trend, support_resistance, macd",
+					In:          "body",
+				},
+				{
+					Name:        "include_news",
+					Type:        "boolean",
+					Description: "Include news sentiment analysis (default: false)",
+					In:          "body",
+				},
+				{
+					Name:        "benchmark_ticker",
+					Type:        "string",
+					Description: "EODHD ticker to compare against (e.g., 'STW.AU', 'GSPC.INDX').",
+					In:          "body",
+				},
 			},
 		},
 		{
@@ -496,6 +653,21 @@ func buildToolCatalog() []models.ToolDefinition {
 				},
 			},
 		},
+		{
+			Name:        "report_generate_pdf",
+			Description: "Render the stored portfolio report as a PDF (summary tables, growth chart and one section per holding) and save it for download. Generates a report first when none exists. Returns report_id, size_bytes and url; GET the url to download the PDF. Portfolios without holdings produce a one-page \"no holdings\" PDF.",
+			Method:      "POST",
+			Path:        "/api/portfolios/{portfolio_name}/report/pdf",
+			Params: []models.ParamDefinition{
+				portfolioParam,
+				{
+					Name:        "report_id",
+					Type:        "string",
+					Description: "Report to render, as returned by a previous call (generation time, e.g. 20260301T093000Z). Defaults to the latest report.",
+					In:          "body",
+				},
+			},
+		},
 		{
 			Name:        "portfolio_get_summary",
 			Description: "FAST: Get portfolio summary. Auto-generates if no cached report exists.",
@@ -516,6 +688,111 @@ func buildToolCatalog() []models.ToolDefinition {
 				portfolioParam,
 			},
 		},
+		{
+			Name:        "portfolio_get_data_completeness",
+			Description: "FAST: Score how much of each holding's data (EOD, fundamentals, signals, trades) is present and fresh, with a portfolio-level score (0-100). Use to judge whether a portfolio review is trustworthy.",
+			Method:      "GET",
+			Path:        "/api/portfolios/{portfolio_name}/completeness",
+			Params: []models.ParamDefinition{
+				portfolioParam,
+			},
+		},
+		{
+			Name:        "portfolio_get_cgt_report",
+			Description: "Capital gains tax report for an Australian financial year. Pairs each sell with buy lots by date and splits realized gains into discountable (held 12+ months) and non-discountable, with losses and the net capital gain after discount. Lists each disposal with acquisition/disposal dates, proceeds, cost base and gain.",
+			Method:      "GET",
+			Path:        "/api/portfolios/{portfolio_name}/cgt",
+			Params: []models.ParamDefinition{
+				portfolioParam,
+				{
+					Name:        "financial_year",
+					Type:        "string",
+					Description: "Financial year as YYYY-YYYY, e.g. \"2023-2024\" (1 July 2023 to 30 June 2024). Defaults to the current financial year.",
+					In:          "query",
+				},
+			},
+		},
+		{
+			Name:        "portfolio_get_realized_timeline",
+			Description: "Cumulative realized gain/loss over time. Replays each holding's trades in date order under the strategy's cost basis method and returns one point per date a sell changed realized P&L, with that date's amount, the running total and the per-ticker components. Re-buying after an exit does not reset gains already realized. Amounts are in the portfolio's base currency.",
+			Method:      "GET",
+			Path:        "/api/portfolios/{portfolio_name}/realized-timeline",
+			Params: []models.ParamDefinition{
+				portfolioParam,
+			},
+		},
+		{
+			Name:        "portfolio_export_for_import",
+			Description: "Export the portfolio's trade history as a CSV for another platform's trade import. Format 'sharesight' (the default) writes Trade Date (DD/MM/YYYY), Market Code, Instrument Code, Transaction Type, Quantity, Price and Brokerage, one row per buy, sell or opening balance, oldest first, with prices in the listing's currency. Cost base adjustments, splits, dividends and other trade types the import cannot express are not written; each is listed under `skipped` with the reason so it can be entered by hand. Returns csv, rows, skipped and warnings.",
+			Method:      "GET",
+			Path:        "/api/portfolios/{portfolio_name}/export",
+			Params: []models.ParamDefinition{
+				portfolioParam,
+				{Name: "format", Type: "string", Description: "Target import format. Supported: sharesight (default).", In: "query"},
+			},
+		},
+		{
+			Name:        "portfolio_get_twr",
+			Description: "Time-weighted return (percent) of the whole portfolio. Contributions and withdrawals in the cash flow ledger split the range into sub-periods whose returns are chained, so deposits mid-period don't distort performance the way the dollar-weighted net return does. Errors when daily portfolio value snapshots are missing from the range.",
+			Method:      "GET",
+			Path:        "/api/portfolios/{portfolio_name}/twr",
+			Params: []models.ParamDefinition{
+				portfolioParam,
+				{Name: "from", Type: "string", Description: "Start date (YYYY-MM-DD). Defaults to portfolio inception.", In: "query"},
+				{Name: "to", Type: "string", Description: "End date (YYYY-MM-DD). Defaults to today.", In: "query"},
+			},
+		},
+		{
+			Name:        "portfolio_get_sector_allocation",
+			Description: "Sector allocation of open holdings. Maps each sector (from cached fundamentals) to total market value, weight percent of portfolio value and the holdings in it. Holdings without fundamentals are grouped under \"Unknown\". Also attached to portfolio_review_compliance as sector_allocation, where sectors above the strategy's max_sector_pct raise a strategy_sector_concentration alert.",
+			Method:      "GET",
+			Path:        "/api/portfolios/{portfolio_name}/sectors",
+			Params: []models.ParamDefinition{
+				portfolioParam,
+			},
+		},
+		{
+			Name:        "portfolio_get_fee_summary",
+			Description: "Brokerage paid per holding and across the portfolio, from synced buy, opening balance and sell trades. Each holding shows total_fees, gross_invested and fees_pct_invested (fees as a percentage of invested capital), largest fees first. Negative fees (rebates) reduce the totals.",
+			Method:      "GET",
+			Path:        "/api/portfolios/{portfolio_name}/fees",
+			Params: []models.ParamDefinition{
+				portfolioParam,
+			},
+		},
+		{
+			Name:        "portfolio_get_rebalance_plan",
+			Description: "Rebalance plan against the strategy's target_weights (ticker → % of portfolio value). Lists buy/sell dollar amounts and units for holdings outside the server's drift tolerance, with brokerage from the fee model. Held tickers with no target are sold to zero. Totals the net cash required against available cash and flags insufficient_cash. Warns when targets do not sum to 100%.",
+			Method:      "GET",
+			Path:        "/api/portfolios/{portfolio_name}/rebalance",
+			Params: []models.ParamDefinition{
+				portfolioParam,
+			},
+		},
+		{
+			Name:        "portfolio_project",
+			Description: "Monte Carlo projection of portfolio value. Each open holding's volatility is estimated from up to 3 years of EOD closes; its historical mean return (sample_drift) is shrunk towards a 6%/yr long-run prior, weighted by the length of the history, and capped at ±15%/yr (holdings with under ~6 months of history assume 30% volatility and no drift) and simulated with yearly shocks correlated by the holdings' daily return correlation (the same ticker across accounts is one holding); cash and asset sets are held flat. Returns p10/p50/p90 value bands for each year, the per-holding drift and volatility, the assumptions used, and warnings. A statistical illustration, not a forecast.",
+			Method:      "GET",
+			Path:        "/api/portfolios/{portfolio_name}/projection",
+			Params: []models.ParamDefinition{
+				portfolioParam,
+				{Name: "years", Type: "number", Description: "Years to project (1-30). Default: 5.", In: "query"},
+				{Name: "simulations", Type: "number", Description: "Simulated paths (1-10000). Default: 1000.", In: "query"},
+			},
+		},
+		{
+			Name:        "portfolio_simulate_trade",
+			Description: "Simulate a buy or sell without recording it. Returns trade value, brokerage from the server's fee model (flat, percentage or tiered), cash impact including the fee, cash before/after, resulting units and position weight.",
+			Method:      "POST",
+			Path:        "/api/portfolios/{portfolio_name}/simulate",
+			Params: []models.ParamDefinition{
+				portfolioParam,
+				{Name: "ticker", Type: "string", Description: "Ticker to trade (e.g. BHP or BHP.AU)", Required: true, In: "body"},
+				{Name: "action", Type: "string", Description: "buy or sell", Required: true, In: "body"},
+				{Name: "units", Type: "number", Description: "Units to trade", Required: true, In: "body"},
+				{Name: "price", Type: "number", Description: "Trade price. Defaults to the holding's current price; required for tickers not held.", In: "body"},
+			},
+		},
 		// --- Trades ---
 		{
 			Name:        "portfolio_create",
@@ -810,6 +1087,27 @@ func buildToolCatalog() []models.ToolDefinition {
 				portfolioParam,
 			},
 		},
+		{
+			Name:        "cash_reconcile",
+			Description: "Reconcile the cash balance derived from the ledger (contributions, withdrawals, dividends, fees, transfers) and buy/sell trades against broker-reported balances. Returns category totals, the derived balance, and dated discrepancies: each gap that opened between two broker statements, with its amount and sign (positive = broker holds more cash than recorded, e.g. a missing contribution). Record statement balances with cash_set_broker_balance.",
+			Method:      "GET",
+			Path:        "/api/portfolios/{portfolio_name}/cash-reconcile",
+			Params: []models.ParamDefinition{
+				portfolioParam,
+			},
+		},
+		{
+			Name:        "cash_set_broker_balance",
+			Description: "Record the cash balance reported by the broker on a date (one per day; re-recording a date replaces it), then return the reconciliation as cash_reconcile does.",
+			Method:      "POST",
+			Path:        "/api/portfolios/{portfolio_name}/cash-reconcile",
+			Params: []models.ParamDefinition{
+				portfolioParam,
+				{Name: "date", Type: "string", Description: "Statement date (YYYY-MM-DD).", Required: true, In: "body"},
+				{Name: "balance", Type: "number", Description: "Cash balance reported by the broker.", Required: true, In: "body"},
+				{Name: "notes", Type: "string", Description: "Optional notes (e.g. statement reference).", In: "body"},
+			},
+		},
 		{
 			Name:        "cash_update_account",
 			Description: "Update a cash account's properties (type, is_transactional, currency). All accounts contribute to capital_gross.",
@@ -853,7 +1151,7 @@ func buildToolCatalog() []models.ToolDefinition {
 		},
 		{
 			Name:        "portfolio_get_timeline",
-			Description: "Get daily portfolio value timeline with capital allocation breakdown (holdings value, cash balance, total capital, net deployed). Use to chart portfolio value vs capital invested for P&L analysis. Cash balance and net deployed are computed from the cash transactions ledger. Returns snake_case fields in data_points array (date, equity_holdings_value, equity_holdings_cost, equity_holdings_return, equity_holdings_return_pct, holding_count, capital_gross, capital_available, portfolio_value, capital_contributions_net).",
+			Description: "Get daily portfolio value timeline with capital allocation breakdown (holdings value, cash balance, total capital, net deployed). Use to chart portfolio value vs capital invested for P&L analysis. Served from the daily snapshots a background job stores, so past days keep the values recorded at the time; days before the first snapshot are recomputed from trades, with cash balance and net deployed from the cash transactions ledger (capital_gross and capital_contributions_net are only present on recomputed days). Returns snake_case fields in data_points array (date, equity_holdings_value, equity_holdings_cost, equity_holdings_return, equity_holdings_return_pct, holding_count, capital_gross, capital_available, portfolio_value, capital_contributions_net).",
 			Method:      "GET",
 			Path:        "/api/portfolios/{portfolio_name}/timeline",
 			Params: []models.ParamDefinition{
@@ -895,7 +1193,7 @@ func buildToolCatalog() []models.ToolDefinition {
 				{
 					Name:        "items",
 					Type:        "array",
-					Description: "Array of watchlist items. Each: {ticker, name, verdict (PASS|WATCH|FAIL), reason, key_metrics, notes}.",
+					Description: "Array of watchlist items. Each: {ticker, name, verdict (PASS|WATCH|FAIL), reason, key_metrics, notes, target_price}.",
 					Required:    true,
 					In:          "body",
 				},
@@ -952,6 +1250,12 @@ func buildToolCatalog() []models.ToolDefinition {
 					Description: "Free-form notes.",
 					In:          "body",
 				},
+				{
+					Name:        "target_price",
+					Type:        "number",
+					Description: "Target price. The price scheduler records a watchlist_target_hit event when the price crosses it.",
+					In:          "body",
+				},
 			},
 		},
 		{
@@ -998,11 +1302,17 @@ func buildToolCatalog() []models.ToolDefinition {
 					Description: "Updated notes.",
 					In:          "body",
 				},
+				{
+					Name:        "target_price",
+					Type:        "number",
+					Description: "Updated target price.",
+					In:          "body",
+				},
 			},
 		},
 		{
 			Name:        "watchlist_remove_item",
-			Description: "Remove a stock from the watchlist by ticker.",
+			Description: "Remove a stock from the watchlist by ticker. Removing a ticker that is not on the watchlist is a no-op.",
 			Method:      "DELETE",
 			Path:        "/api/portfolios/{portfolio_name}/watchlist/items/{ticker}",
 			Params: []models.ParamDefinition{
@@ -1016,6 +1326,15 @@ func buildToolCatalog() []models.ToolDefinition {
 				},
 			},
 		},
+		{
+			Name:        "watchlist_events",
+			Description: "List watchlist_target_hit events for a portfolio, newest first. Events are recorded by the price scheduler when a watchlist item's price crosses its target_price.",
+			Method:      "GET",
+			Path:        "/api/portfolios/{portfolio_name}/watchlist/events",
+			Params: []models.ParamDefinition{
+				portfolioParam,
+			},
+		},
 		{
 			Name:        "watchlist_review",
 			Description: "Review watchlist stocks for signals, overnight movement, and actionable observations. Runs the same signal/compliance pipeline as portfolio_review_compliance but for watchlist tickers instead of portfolio holdings.",
@@ -1027,9 +1346,53 @@ func buildToolCatalog() []models.ToolDefinition {
 				{Name: "include_news", Type: "boolean", Description: "Include news sentiment analysis (default: false)", In: "body"},
 			},
 		},
+		{
+			Name:        "portfolio_list_active_alerts",
+			Description: "List the alerts raised by the latest portfolio review that are still active, with when each was first raised and whether it has been acknowledged.",
+			Method:      "GET",
+			Path:        "/api/portfolios/{portfolio_name}/alerts",
+			Params: []models.ParamDefinition{
+				portfolioParam,
+			},
+		},
+		{
+			Name:        "portfolio_acknowledge_alert",
+			Description: "Acknowledge an active alert so reviews stop repeating it. It stays suppressed while its condition holds and fires again if the condition clears and later returns.",
+			Method:      "POST",
+			Path:        "/api/portfolios/{portfolio_name}/alerts/acknowledge",
+			Params: []models.ParamDefinition{
+				portfolioParam,
+				{Name: "ticker", Type: "string", Description: "Ticker the alert is for (e.g. 'BHP' or 'BHP.AU'). Omit for portfolio-level alerts.", In: "body"},
+				{Name: "signal", Type: "string", Required: true, Description: "Alert signal as shown by portfolio_list_active_alerts (e.g. 'rsi_overbought').", In: "body"},
+			},
+		},
+		{
+			Name:        "portfolio_get_alert_digest",
+			Description: "Get the portfolio's active alerts as a compact Markdown digest for Slack or Discord: grouped by severity (high first), strategy alerts before technical ones within each group, one line per alert with ticker, signal and message. Acknowledged alerts are left out. Returns an 'all clear' message when nothing is active. Alerts come from the latest review.",
+			Method:      "GET",
+			Path:        "/api/portfolios/{portfolio_name}/alerts/digest",
+			Params: []models.ParamDefinition{
+				portfolioParam,
+			},
+		},
+		{
+			Name:        "portfolio_get_correlation",
+			Description: "Get pairwise correlations of daily returns between the portfolio's open holdings over a trailing window, from stored EOD bars. Returns `matrix` keyed by EODHD ticker (e.g. BHP.AU) with 1 on the diagonal. Pairs with fewer than 20 shared trading days are left out. Values near 1 mean the holdings move together and add little diversification; the review raises `strategy_correlation_high` when a pair exceeds the strategy's `max_correlation`.",
+			Method:      "GET",
+			Path:        "/api/portfolios/{portfolio_name}/correlation",
+			Params: []models.ParamDefinition{
+				portfolioParam,
+				{
+					Name:        "window",
+					Type:        "number",
+					Description: "Trailing window in trading days (minimum 20). Default: 60.",
+					In:          "query",
+				},
+			},
+		},
 		{
 			Name:        "holding_note_get",
-			Description: "Get holding notes for a portfolio.",
+			Description: "Get the portfolio's structured holding research notes: thesis, known behaviours, signal overrides, alert muting and staleness. These are separate from the short user_note and tags set with holding_note_annotate and holding_note_set_tags.",
 			Method:      "GET",
 			Path:        "/api/portfolios/{portfolio_name}/notes",
 			Params: []models.ParamDefinition{
@@ -1038,7 +1401,7 @@ func buildToolCatalog() []models.ToolDefinition {
 		},
 		{
 			Name:        "holding_note_set",
-			Description: "Replace all holding notes for a portfolio.",
+			Description: "Replace all structured holding research notes for a portfolio. Does not change the user_note or tags set with holding_note_annotate and holding_note_set_tags.",
 			Method:      "PUT",
 			Path:        "/api/portfolios/{portfolio_name}/notes",
 			Params: []models.ParamDefinition{
@@ -1114,6 +1477,12 @@ func buildToolCatalog() []models.ToolDefinition {
 					Description: "Days until stale (default 90).",
 					In:          "body",
 				},
+				{
+					Name:        "alerts_muted",
+					Type:        "boolean",
+					Description: "Mute review alerts for this holding, e.g. a core position you won't trade (default: false). Survives portfolio resync.",
+					In:          "body",
+				},
 			},
 		},
 		{
@@ -1172,6 +1541,12 @@ func buildToolCatalog() []models.ToolDefinition {
 					Description: "Days until stale.",
 					In:          "body",
 				},
+				{
+					Name:        "alerts_muted",
+					Type:        "boolean",
+					Description: "Set true to mute review alerts for this holding, false to unmute.",
+					In:          "body",
+				},
 			},
 		},
 		{
@@ -1215,10 +1590,13 @@ func buildToolCatalog() []models.ToolDefinition {
 						"Optional fields: account_type (smsf|trading), investment_universe ([\"AU\",\"US\"]), " +
 						"risk_appetite {level, max_drawdown_pct, description}, " +
 						"target_returns {annual_pct, timeframe}, income_requirements {dividend_yield_pct, description}, " +
-						"sector_preferences {preferred [], excluded []}, position_sizing {max_position_pct, max_sector_pct}, " +
+						"sector_preferences {preferred [], excluded []}, position_sizing {max_position_pct, max_sector_pct, max_hhi, max_correlation (0-1: alert when two holdings' daily returns correlate above it), stop_loss_pct, take_profit_pct, earnings_window_days (alert when a holding reports earnings within N days), earnings_watch_pct (also WATCH holdings at or above this weight % inside the window)}, " +
 						"company_filter {min_market_cap, max_market_cap, max_pe, min_dividend_yield, allowed_sectors [], excluded_sectors []}, " +
 						"rules [{name, conditions [{field, operator, value}], action (SELL|BUY|HOLD|WATCH), reason, priority, enabled}], " +
-						"rebalance_frequency, notes (free-form markdown).",
+						"rebalance_frequency, target_weights {ticker: pct} (target % of portfolio value for portfolio_get_rebalance_plan), cost_basis_method (average|fifo|lifo, default average), " +
+						"derived_metrics [{name, expression, description}] (arithmetic over holding fields, e.g. \"market_value / cost_basis\"), " +
+						"price_source (auto|navexa|eodhd, default auto), price_source_by_ticker [{ticker, source}] (per-holding override), " +
+						"rsi_period (RSI lookback in bars for compute_signals, default 14), volume_spike_multiple (volume over the 20-day average that raises volume_spike, default 2.0), notes (free-form markdown).",
 					Required: true,
 					In:       "body",
 				},
@@ -1233,6 +1611,28 @@ func buildToolCatalog() []models.ToolDefinition {
 				portfolioParam,
 			},
 		},
+		{
+			Name:        "strategy_apply",
+			Description: "Apply one strategy to multiple portfolios at once (replaces each portfolio's strategy). All portfolios must exist.",
+			Method:      "POST",
+			Path:        "/api/strategies/apply",
+			Params: []models.ParamDefinition{
+				{
+					Name:        "portfolio_names",
+					Type:        "array",
+					Description: "Names of the portfolios to apply the strategy to.",
+					Required:    true,
+					In:          "body",
+				},
+				{
+					Name:        "strategy",
+					Type:        "object",
+					Description: "Strategy fields as a JSON object (same shape as strategy_set).",
+					Required:    true,
+					In:          "body",
+				},
+			},
+		},
 
 		// --- Plan ---
 		{
@@ -1255,10 +1655,10 @@ func buildToolCatalog() []models.ToolDefinition {
 					Name: "items",
 					Type: "array",
 					Description: "Plan action items. Array of objects: " +
-						"{type (time|event), description, status (pending|triggered|completed|expired|cancelled), " +
+						"{type (time|event|trailing_stop), description, status (pending|triggered|completed|expired|cancelled), " +
 						"deadline (ISO date, time-based), ticker (event-based), " +
 						"conditions [{field, operator, value}] (event-based), " +
-						"action (SELL|BUY|HOLD|WATCH), target_value, notes}.",
+						"action (SELL|BUY|HOLD|WATCH), target_value, trail_pct (trailing_stop), notes}.",
 					Required: true,
 					In:       "body",
 				},
@@ -1268,6 +1668,12 @@ func buildToolCatalog() []models.ToolDefinition {
 					Description: "Free-form plan notes.",
 					In:          "body",
 				},
+				{
+					Name:        "drift_tolerance_pct",
+					Type:        "number",
+					Description: "Allowed % deviation of a holding's value from a BUY item's target_value before portfolio review raises a plan_drift alert (default 20).",
+					In:          "body",
+				},
 			},
 		},
 		{
@@ -1280,7 +1686,7 @@ func buildToolCatalog() []models.ToolDefinition {
 				{
 					Name:        "type",
 					Type:        "string",
-					Description: "Item type: time_based or event_based.",
+					Description: "Item type: time, event or trailing_stop.",
 					Required:    true,
 					In:          "body",
 				},
@@ -1321,6 +1727,12 @@ func buildToolCatalog() []models.ToolDefinition {
 					Description: "Target value for the action.",
 					In:          "body",
 				},
+				{
+					Name:        "trail_pct",
+					Type:        "number",
+					Description: "Trailing stop distance below the peak price, in percent (for trailing_stop items).",
+					In:          "body",
+				},
 				{
 					Name:        "notes",
 					Type:        "string",
@@ -1381,7 +1793,7 @@ func buildToolCatalog() []models.ToolDefinition {
 		},
 		{
 			Name:        "plan_check_status",
-			Description: "Evaluate plan status: checks event triggers and deadline expiry.",
+			Description: "Evaluate plan status: checks event triggers, deadline expiry and trailing stops. Returns trailing_stops with each pending stop's peak_price and trail {current_price, trail_level, distance_pct}.",
 			Method:      "GET",
 			Path:        "/api/portfolios/{portfolio_name}/plan/status",
 			Params: []models.ParamDefinition{
@@ -1407,7 +1819,7 @@ func buildToolCatalog() []models.ToolDefinition {
 		},
 		{
 			Name:        "market_get_stock_data",
-			Description: "Get comprehensive stock data including price, fundamentals, signals, and news for a specific ticker. Use force_refresh=true to re-collect EOD and fundamentals from EODHD and enqueue background jobs for filings, AI summaries, and timeline. Without force, returns cached data. When price is included, also returns `candles` array of historical OHLC bars (up to 200 trading days, most recent first) for candlestick pattern analysis.",
+			Description: "Get comprehensive stock data including price, fundamentals, signals, and news for a specific ticker. Use force_refresh=true to re-collect EOD and fundamentals from EODHD and enqueue background jobs for filings, AI summaries, and timeline. Without force, returns cached data. When price is included, also returns `candles` array of historical OHLC bars (up to 200 trading days, most recent first) for candlestick pattern analysis. The response is always `{data, advisory, background_jobs}` with the stock data under `data`.",
 			Method:      "GET",
 			Path:        "/api/market/stocks/{ticker}",
 			Params: []models.ParamDefinition{
@@ -1430,6 +1842,18 @@ func buildToolCatalog() []models.ToolDefinition {
 					Description: "Force re-collection of EOD and fundamentals inline, and enqueue background jobs for filings, AI summaries, and timeline. Response includes an advisory when background jobs are enqueued.",
 					In:          "query",
 				},
+				{
+					Name:        "lookback",
+					Type:        "string",
+					Description: "Window for candles and signals, e.g. '6mo', '1y', '5y'. Default: latest 200 bars. If stored history is shorter, available data is returned with an advisory.",
+					In:          "query",
+				},
+				{
+					Name:        "indicator_days",
+					Type:        "number",
+					Description: "Return `indicator_series`: RSI, SMA50, SMA200 and MACD (line, signal, histogram) for each of the last N trading days, most recent first, for charting. Computed the same way as `signals`, so the first point matches the current values. Capped to stored history and the server maximum (default 365), with an advisory when shortened. Default: 0 (no series).",
+					In:          "query",
+				},
 			},
 		},
 		{
@@ -1463,6 +1887,16 @@ func buildToolCatalog() []models.ToolDefinition {
 				},
 			},
 		},
+		{
+			Name:        "market_backtest_signals",
+			Description: "Backtest entry signals for a ticker against its stored EOD history. For each historical bar, checks whether ENTRY CRITERIA MET would have fired (RSI oversold, golden cross) and whether price was higher after the horizon. The RSI period and oversold threshold come from the strategy of the portfolio holding the ticker (risk appetite: conservative 35, moderate 30, aggressive 25), as in portfolio reviews; tickers no portfolio holds use RSI(14) < 30. Returns per-signal occurrences, hits, hit_rate_pct, and avg_return_pct.",
+			Method:      "GET",
+			Path:        "/api/market/stocks/{ticker}/signal-backtest",
+			Params: []models.ParamDefinition{
+				{Name: "ticker", Type: "string", Description: "Stock ticker with exchange suffix (e.g., 'BHP.AU')", Required: true, In: "path"},
+				{Name: "horizon", Type: "number", Description: "Forward window in trading days used to score each signal (default 20, max 250)", In: "query"},
+			},
+		},
 
 		{
 			Name:        "market_refresh_stock_data",
@@ -1587,6 +2021,36 @@ func buildToolCatalog() []models.ToolDefinition {
 					Description: "Filter criteria: oversold_rsi, near_support, underpriced, accumulating, regime_shift (technical mode only)",
 					In:          "body",
 				},
+				{
+					Name:        "oversold_rsi",
+					Type:        "number",
+					Description: "RSI below this scores as oversold (default: 30, technical mode only)",
+					In:          "body",
+				},
+				{
+					Name:        "near_oversold_rsi",
+					Type:        "number",
+					Description: "RSI below this scores as approaching oversold (default: 40, technical mode only)",
+					In:          "body",
+				},
+				{
+					Name:        "min_momentum",
+					Type:        "number",
+					Description: "Minimum trend momentum score from -1 to 1; candidates below are skipped (default: no filter, technical mode only)",
+					In:          "body",
+				},
+				{
+					Name:        "min_volume_ratio",
+					Type:        "number",
+					Description: "Minimum current/average volume ratio; candidates below are skipped (default: no filter, technical mode only)",
+					In:          "body",
+				},
+				{
+					Name:        "min_score",
+					Type:        "number",
+					Description: "Minimum candidate score from 0 to 1 (default: 0.6, technical mode only)",
+					In:          "body",
+				},
 				{
 					Name:        "sector",
 					Type:        "string",
@@ -1609,10 +2073,44 @@ func buildToolCatalog() []models.ToolDefinition {
 			},
 		},
 
+		{
+			Name:        "market_screen_scored",
+			Description: "Run an EODHD screener query and rank the results by a 0-100 composite score. Factors: valuation (lower P/E scores higher), momentum (stored RSI) and dividend yield. Weights are relative; a factor without data for a stock is left out of its average and the remaining weights renormalised. Results are sorted by score, highest first.",
+			Method:      "POST",
+			Path:        "/api/screen/scored",
+			Params: []models.ParamDefinition{
+				{
+					Name:        "exchange",
+					Type:        "string",
+					Description: "Exchange to screen (e.g., 'AU' for ASX, 'US' for NYSE/NASDAQ)",
+					Required:    true,
+					In:          "body",
+				},
+				{
+					Name:        "filters",
+					Type:        "array",
+					Description: "Extra EODHD screener filters [{field, operator, value}], e.g. {\"field\": \"market_capitalization\", \"operator\": \">\", \"value\": 1000000000}",
+					In:          "body",
+				},
+				{
+					Name:        "weights",
+					Type:        "object",
+					Description: "Factor weights {valuation, momentum, dividend}. Omit or all zero for equal weights.",
+					In:          "body",
+				},
+				{
+					Name:        "limit",
+					Type:        "number",
+					Description: "Maximum results to return (default: 20, max: 100)",
+					In:          "body",
+				},
+			},
+		},
+
 		// --- Reports ---
 		{
 			Name:        "report_list",
-			Description: "List available portfolio reports with their generation timestamps.",
+			Description: "List available portfolio reports with their generation timestamps, newest first. Returns {items, total, next_cursor}; pass next_cursor as cursor to fetch the next page.",
 			Method:      "GET",
 			Path:        "/api/reports",
 			Params: []models.ParamDefinition{
@@ -1622,6 +2120,32 @@ func buildToolCatalog() []models.ToolDefinition {
 					Description: "Optional: filter to a specific portfolio name",
 					In:          "query",
 				},
+				{Name: "limit", Type: "number", Description: "Max results (default: 50, max: 200)", In: "query"},
+				{Name: "offset", Type: "number", Description: "Pagination offset", In: "query"},
+				{Name: "cursor", Type: "string", Description: "next_cursor from a previous page (overrides offset)", In: "query"},
+			},
+		},
+		{
+			Name:        "report_search",
+			Description: "Full-text search over stored portfolio reports. Case-insensitive; every term must appear (AND), and a term also matches longer words it prefixes. Returns ranked sections (portfolio summary or ticker) with snippets.",
+			Method:      "GET",
+			Path:        "/api/reports/search",
+			Params: []models.ParamDefinition{
+				{Name: "q", Type: "string", Description: "Search terms, e.g. 'BHP dividend'", Required: true, In: "query"},
+				{Name: "portfolio_name", Type: "string", Description: "Optional: restrict to a specific portfolio's report", In: "query"},
+				{Name: "limit", Type: "number", Description: "Max results (default: 20, max: 100)", In: "query"},
+			},
+		},
+		{
+			Name:        "report_list_tickers",
+			Description: "List the tickers in a portfolio's latest report, alphabetically. Returns {items, total, next_cursor}; pass next_cursor as cursor to fetch the next page.",
+			Method:      "GET",
+			Path:        "/api/portfolios/{portfolio_name}/tickers",
+			Params: []models.ParamDefinition{
+				portfolioParam,
+				{Name: "limit", Type: "number", Description: "Max results (default: 50, max: 200)", In: "query"},
+				{Name: "offset", Type: "number", Description: "Pagination offset", In: "query"},
+				{Name: "cursor", Type: "string", Description: "next_cursor from a previous page (overrides offset)", In: "query"},
 			},
 		},
 
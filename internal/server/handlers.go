@@ -3,9 +3,12 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -13,6 +16,7 @@ import (
 	"github.com/bobmcallan/vire/internal/interfaces"
 	"github.com/bobmcallan/vire/internal/models"
 	"github.com/bobmcallan/vire/internal/services/portfolio"
+	"github.com/bobmcallan/vire/internal/signals"
 )
 
 // slimHoldingReview strips heavy analysis data from a HoldingReview,
@@ -115,6 +119,11 @@ func (s *Server) handlePortfolioGet(w http.ResponseWriter, r *http.Request, name
 
 	ctx := s.app.InjectNavexaClient(r.Context())
 	forceRefresh := r.URL.Query().Get("force_refresh") == "true"
+	period := models.ReturnPeriod(r.URL.Query().Get("contribution_period"))
+	if period != "" && !models.ValidReturnPeriod(period) {
+		WriteError(w, http.StatusBadRequest, fmt.Sprintf("invalid contribution_period '%s' (day, week, month or quarter)", period))
+		return
+	}
 
 	var portfolio *models.Portfolio
 	var err error
@@ -124,9 +133,12 @@ func (s *Server) handlePortfolioGet(w http.ResponseWriter, r *http.Request, name
 		portfolio, err = s.app.PortfolioService.GetPortfolio(ctx, name)
 	}
 	if err != nil {
-		WriteError(w, http.StatusNotFound, fmt.Sprintf("Portfolio not found: %v", err))
+		writePortfolioLoadError(w, err)
 		return
 	}
+	if period != "" {
+		portfolio.SetReturnContributions(period)
+	}
 
 	// Strip trades from portfolio-level response; individual stock endpoint returns them
 	for i := range portfolio.Holdings {
@@ -196,7 +208,7 @@ func (s *Server) handlePortfolioStock(w http.ResponseWriter, r *http.Request, na
 		portfolio, err = s.app.PortfolioService.GetPortfolio(ctx, name)
 	}
 	if err != nil {
-		WriteError(w, http.StatusNotFound, fmt.Sprintf("Portfolio not found: %v", err))
+		writePortfolioLoadError(w, err)
 		return
 	}
 
@@ -251,36 +263,26 @@ func (s *Server) handlePortfolioReview(w http.ResponseWriter, r *http.Request, n
 	}
 
 	var req struct {
-		FocusSignals []string `json:"focus_signals"`
-		IncludeNews  bool     `json:"include_news"`
+		FocusSignals    []string `json:"focus_signals"`
+		IncludeNews     bool     `json:"include_news"`
+		BenchmarkTicker string   `json:"benchmark_ticker"`
+		ForceRefresh    bool     `json:"force_refresh"`
 	}
 	if r.Body != nil {
 		json.NewDecoder(r.Body).Decode(&req)
 	}
 
 	ctx := s.app.InjectNavexaClient(r.Context())
-
-	// Warm EOD + fundamentals only (fast path). Filing collection, PDF
-	// downloads, and AI summarization are handled asynchronously by the job
-	// manager — they must not block the review request.
-	var tickers []string
-	if portfolio, err := s.app.PortfolioService.GetPortfolio(ctx, name); err == nil {
-		tickers = make([]string, 0, len(portfolio.Holdings))
-		for _, h := range portfolio.Holdings {
-			if h.Units > 0 {
-				tickers = append(tickers, h.EODHDTicker())
-			}
-		}
-		if len(tickers) > 0 {
-			if err := s.app.MarketService.CollectCoreMarketData(ctx, tickers, false); err != nil {
-				s.logger.Warn().Err(err).Msg("Pre-review core market data collection failed")
-			}
-		}
+	if req.ForceRefresh {
+		ctx = common.WithForceAIRefresh(ctx)
 	}
 
+	tickers := s.warmReviewMarketData(ctx, name)
+
 	review, err := s.app.PortfolioService.ReviewPortfolio(ctx, name, interfaces.ReviewOptions{
-		FocusSignals: req.FocusSignals,
-		IncludeNews:  req.IncludeNews,
+		FocusSignals:    req.FocusSignals,
+		IncludeNews:     req.IncludeNews,
+		BenchmarkTicker: req.BenchmarkTicker,
 	})
 	if err != nil {
 		WriteError(w, http.StatusInternalServerError, fmt.Sprintf("Review error: %v", err))
@@ -302,8 +304,35 @@ func (s *Server) handlePortfolioReview(w http.ResponseWriter, r *http.Request, n
 		"growth":   portfolio.GrowthPointsToTimeSeries(dailyPoints),
 	})
 
-	// Demand-driven: enqueue background jobs for stale slow data (filings, summaries, etc.)
-	// Reuses the tickers slice already extracted for CollectCoreMarketData above.
+	s.enqueueReviewJobs(tickers)
+}
+
+// warmReviewMarketData collects EOD + fundamentals for the portfolio's open
+// holdings before a review (fast path) and returns their tickers. Filing
+// collection, PDF downloads, and AI summarization are handled asynchronously
+// by the job manager — they must not block the review request.
+func (s *Server) warmReviewMarketData(ctx context.Context, name string) []string {
+	portfolio, err := s.app.PortfolioService.GetPortfolio(ctx, name)
+	if err != nil {
+		return nil
+	}
+	tickers := make([]string, 0, len(portfolio.Holdings))
+	for _, h := range portfolio.Holdings {
+		if h.Units > 0 {
+			tickers = append(tickers, h.EODHDTicker())
+		}
+	}
+	if len(tickers) > 0 {
+		if err := s.app.MarketService.CollectCoreMarketData(ctx, tickers, false); err != nil {
+			s.logger.Warn().Err(err).Msg("Pre-review core market data collection failed")
+		}
+	}
+	return tickers
+}
+
+// enqueueReviewJobs demand-driven: enqueues background jobs for stale slow
+// data (filings, summaries, etc.) for the reviewed tickers.
+func (s *Server) enqueueReviewJobs(tickers []string) {
 	if s.app.JobManager != nil && len(tickers) > 0 {
 		go func() {
 			defer func() { recover() }()
@@ -312,6 +341,70 @@ func (s *Server) handlePortfolioReview(w http.ResponseWriter, r *http.Request, n
 	}
 }
 
+// handlePortfolioReviewStream runs the same review as handlePortfolioReview
+// and streams its AI summary as server-sent events: a "review" event, then
+// "summary" text chunks as Gemini produces them, then "done" or "error".
+func (s *Server) handlePortfolioReviewStream(w http.ResponseWriter, r *http.Request, name string) {
+	if !RequireMethod(w, r, http.MethodPost) {
+		return
+	}
+	if s.app.GeminiClient == nil {
+		WriteError(w, http.StatusServiceUnavailable, "AI analysis unavailable: Gemini not configured")
+		return
+	}
+
+	var req struct {
+		FocusSignals    []string `json:"focus_signals"`
+		IncludeNews     bool     `json:"include_news"`
+		BenchmarkTicker string   `json:"benchmark_ticker"`
+	}
+	if r.Body != nil {
+		json.NewDecoder(r.Body).Decode(&req)
+	}
+
+	ctx := s.app.InjectNavexaClient(r.Context())
+	tickers := s.warmReviewMarketData(ctx, name)
+
+	review, chunks, errs, err := s.app.PortfolioService.StreamPortfolioReview(ctx, name, interfaces.ReviewOptions{
+		FocusSignals:    req.FocusSignals,
+		IncludeNews:     req.IncludeNews,
+		BenchmarkTicker: req.BenchmarkTicker,
+	})
+	if err != nil {
+		WriteError(w, http.StatusInternalServerError, fmt.Sprintf("Review error: %v", err))
+		return
+	}
+	s.enqueueReviewJobs(tickers)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	if err := WriteSSE(w, "review", toSlimReview(review)); err != nil {
+		s.logger.Warn().Err(err).Msg("Review stream: client disconnected")
+		return
+	}
+
+	for chunk := range chunks {
+		if err := WriteSSE(w, "summary", map[string]string{"text": chunk}); err != nil {
+			s.logger.Warn().Err(err).Msg("Review stream: client disconnected")
+			return // ctx is cancelled with the request, which ends the stream
+		}
+	}
+
+	var filtered *interfaces.ContentFilteredError
+	switch err := <-errs; {
+	case errors.As(err, &filtered):
+		WriteSSE(w, "summary", map[string]string{"text": s.app.Config.Clients.Gemini.GetContentFilteredNote()})
+		WriteSSE(w, "done", map[string]bool{"filtered": true})
+	case err != nil:
+		s.logger.Warn().Err(err).Msg("Review stream: AI summary failed")
+		WriteSSE(w, "error", ErrorResponse{Error: err.Error()})
+	default:
+		WriteSSE(w, "done", map[string]bool{"filtered": false})
+	}
+}
+
 func (s *Server) handlePortfolioSync(w http.ResponseWriter, r *http.Request, name string) {
 	if !RequireMethod(w, r, http.MethodPost) {
 		return
@@ -331,7 +424,7 @@ func (s *Server) handlePortfolioSync(w http.ResponseWriter, r *http.Request, nam
 	ctx := s.app.InjectNavexaClient(r.Context())
 	portfolio, err := s.app.PortfolioService.SyncPortfolio(ctx, name, req.Force)
 	if err != nil {
-		WriteError(w, http.StatusInternalServerError, fmt.Sprintf("Sync error: %v", err))
+		WriteError(w, portfolioErrorStatus(err, http.StatusInternalServerError), fmt.Sprintf("Sync error: %v", err))
 		return
 	}
 
@@ -354,6 +447,32 @@ func (s *Server) handlePortfolioSync(w http.ResponseWriter, r *http.Request, nam
 	}
 }
 
+// handleHoldingSync refreshes a single holding from Navexa without
+// re-syncing the rest of the portfolio.
+func (s *Server) handleHoldingSync(w http.ResponseWriter, r *http.Request, name, ticker string) {
+	if !RequireMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	if ticker == "" {
+		WriteError(w, http.StatusBadRequest, "ticker is required in path")
+		return
+	}
+
+	if !s.requireNavexaContext(w, r) {
+		return
+	}
+
+	ctx := s.app.InjectNavexaClient(r.Context())
+	holding, err := s.app.PortfolioService.SyncHolding(ctx, name, ticker)
+	if err != nil {
+		WriteError(w, portfolioErrorStatus(err, http.StatusInternalServerError), fmt.Sprintf("Sync error: %v", err))
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, holding)
+}
+
 func (s *Server) handlePortfolioRebuild(w http.ResponseWriter, r *http.Request, name string) {
 	if !RequireMethod(w, r, http.MethodPost) {
 		return
@@ -378,7 +497,7 @@ func (s *Server) handlePortfolioRebuild(w http.ResponseWriter, r *http.Request,
 	// Step 3: Re-sync portfolio
 	p, err := s.app.PortfolioService.SyncPortfolio(ctx, name, true)
 	if err != nil {
-		WriteError(w, http.StatusInternalServerError, fmt.Sprintf("Rebuild: sync failed: %v", err))
+		WriteError(w, portfolioErrorStatus(err, http.StatusInternalServerError), fmt.Sprintf("Rebuild: sync failed: %v", err))
 		return
 	}
 
@@ -518,7 +637,7 @@ func (s *Server) handlePortfolioHistory(w http.ResponseWriter, r *http.Request,
 
 	ctx := s.app.InjectNavexaClient(r.Context())
 
-	points, err := s.app.PortfolioService.GetDailyGrowth(ctx, name, opts)
+	points, err := s.app.PortfolioService.GetPortfolioTimeline(ctx, name, opts)
 	if err != nil {
 		WriteError(w, http.StatusInternalServerError, fmt.Sprintf("History error: %v", err))
 		return
@@ -672,6 +791,21 @@ func (s *Server) handleMarketStocks(w http.ResponseWriter, r *http.Request) {
 	forceRefresh := r.URL.Query().Get("force_refresh") == "true"
 
 	include := parseStockDataInclude(r.URL.Query()["include"])
+	if lookback := strings.TrimSpace(r.URL.Query().Get("lookback")); lookback != "" {
+		if _, err := common.LookbackCutoff(lookback, time.Now()); err != nil {
+			WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		include.Lookback = lookback
+	}
+	if v := r.URL.Query().Get("indicator_days"); v != "" {
+		days, err := strconv.Atoi(v)
+		if err != nil || days < 0 {
+			WriteError(w, http.StatusBadRequest, "indicator_days must be a non-negative integer")
+			return
+		}
+		include.IndicatorDays = days
+	}
 
 	// Apply a timeout to prevent indefinite blocking on slow API calls
 	ctx, cancel := context.WithTimeout(r.Context(), 90*time.Second)
@@ -695,20 +829,17 @@ func (s *Server) handleMarketStocks(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if forceRefresh {
-		var advisory *string
-		if backgroundJobs > 0 {
-			msg := fmt.Sprintf("EOD and fundamentals refreshed. %d background jobs enqueued for filings, AI summaries, and timeline. Re-request after jobs complete for full refresh.", backgroundJobs)
-			advisory = &msg
-		}
-		WriteJSON(w, http.StatusOK, map[string]interface{}{
-			"data":     stockData,
-			"advisory": advisory,
-		})
-		return
+	// Always the same envelope, so clients never branch on the response shape.
+	var advisory *string
+	if backgroundJobs > 0 {
+		msg := fmt.Sprintf("EOD and fundamentals refreshed. %d background jobs enqueued for filings, AI summaries, and timeline. Re-request after jobs complete for full refresh.", backgroundJobs)
+		advisory = &msg
 	}
-
-	WriteJSON(w, http.StatusOK, stockData)
+	WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"data":            stockData,
+		"advisory":        advisory,
+		"background_jobs": backgroundJobs,
+	})
 }
 
 func (s *Server) handleFilingSummaries(w http.ResponseWriter, r *http.Request, ticker string) {
@@ -802,6 +933,42 @@ func (s *Server) handleMarketSignals(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// handleSignalBacktest replays entry-signal logic over a ticker's stored EOD
+// history and returns per-signal hit rates over the requested horizon.
+func (s *Server) handleSignalBacktest(w http.ResponseWriter, r *http.Request, ticker string) {
+	if !RequireMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	ticker, errMsg := validateTicker(ticker)
+	if errMsg != "" {
+		WriteError(w, http.StatusBadRequest, errMsg)
+		return
+	}
+
+	horizon := 20
+	if v := r.URL.Query().Get("horizon"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > 250 {
+			WriteError(w, http.StatusBadRequest, "horizon must be an integer between 1 and 250")
+			return
+		}
+		horizon = n
+	}
+
+	result, err := s.app.SignalService.BacktestThresholds(r.Context(), ticker, horizon)
+	if err != nil {
+		if errors.Is(err, interfaces.ErrMarketDataNotFound) {
+			WriteError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, result)
+}
+
 func (s *Server) handleMarketCollect(w http.ResponseWriter, r *http.Request) {
 	if !RequireMethod(w, r, http.MethodPost) {
 		return
@@ -880,7 +1047,7 @@ func (s *Server) handleStockDataRefresh(w http.ResponseWriter, r *http.Request)
 		}
 	}
 	for ex := range exchanges {
-		_ = s.app.JobManager.EnqueueIfNeeded(r.Context(), models.JobTypeCollectLivePrices, ex, models.PriorityCollectLivePrices)
+		_, _ = s.app.JobManager.EnqueueIfNeeded(r.Context(), models.JobTypeCollectLivePrices, ex, models.PriorityCollectLivePrices)
 	}
 
 	WriteJSON(w, http.StatusOK, map[string]interface{}{
@@ -1084,6 +1251,7 @@ func (s *Server) handleScreenSnipe(w http.ResponseWriter, r *http.Request) {
 		Sector      string   `json:"sector"`
 		IncludeNews bool     `json:"include_news"`
 		Portfolio   string   `json:"portfolio_name"`
+		models.SnipeThresholds
 	}
 	if !DecodeJSON(w, r, &req) {
 		return
@@ -1112,6 +1280,7 @@ func (s *Server) handleScreenSnipe(w http.ResponseWriter, r *http.Request) {
 		Sector:      req.Sector,
 		IncludeNews: req.IncludeNews,
 		Strategy:    strategy,
+		Thresholds:  req.SnipeThresholds,
 	})
 	if err != nil {
 		WriteError(w, http.StatusInternalServerError, fmt.Sprintf("Snipe error: %v", err))
@@ -1145,6 +1314,8 @@ func (s *Server) handleScreenStocks(w http.ResponseWriter, r *http.Request) {
 		Criteria    []string `json:"criteria"`
 		IncludeNews bool     `json:"include_news"`
 		Portfolio   string   `json:"portfolio_name"`
+		// Technical mode only
+		models.SnipeThresholds
 	}
 	if !DecodeJSON(w, r, &req) {
 		return
@@ -1205,6 +1376,7 @@ func (s *Server) handleScreenStocks(w http.ResponseWriter, r *http.Request) {
 			Sector:      req.Sector,
 			IncludeNews: req.IncludeNews,
 			Strategy:    strategy,
+			Thresholds:  req.SnipeThresholds,
 		})
 		if err != nil {
 			WriteError(w, http.StatusInternalServerError, fmt.Sprintf("Snipe error: %v", err))
@@ -1228,11 +1400,12 @@ func (s *Server) handleScreenFunnel(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req struct {
-		Exchange    string `json:"exchange"`
-		Limit       int    `json:"limit"`
-		Sector      string `json:"sector"`
-		IncludeNews bool   `json:"include_news"`
-		Portfolio   string `json:"portfolio_name"`
+		Exchange    string                     `json:"exchange"`
+		Limit       int                        `json:"limit"`
+		Sector      string                     `json:"sector"`
+		IncludeNews bool                       `json:"include_news"`
+		Portfolio   string                     `json:"portfolio_name"`
+		Stages      []models.FunnelFilterStage `json:"stages"`
 	}
 	if !DecodeJSON(w, r, &req) {
 		return
@@ -1255,18 +1428,23 @@ func (s *Server) handleScreenFunnel(w http.ResponseWriter, r *http.Request) {
 	strategy, _ := s.app.StrategyService.GetStrategy(ctx, portfolioName)
 
 	result, err := s.app.MarketService.FunnelScreen(ctx, interfaces.FunnelOptions{
-		Exchange:    req.Exchange,
-		Limit:       req.Limit,
-		Sector:      req.Sector,
-		IncludeNews: req.IncludeNews,
-		Strategy:    strategy,
+		Exchange:     req.Exchange,
+		Limit:        req.Limit,
+		Sector:       req.Sector,
+		IncludeNews:  req.IncludeNews,
+		Strategy:     strategy,
+		FilterStages: req.Stages,
 	})
+	if errors.Is(err, interfaces.ErrInvalidFunnelStage) {
+		WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	if err != nil {
 		WriteError(w, http.StatusInternalServerError, fmt.Sprintf("Funnel screen error: %v", err))
 		return
 	}
 
-	searchID := s.autoSaveFunnelSearch(ctx, result, req.Exchange, req.Sector, strategy)
+	searchID := s.autoSaveFunnelSearch(ctx, result, req.Exchange, req.Sector, req.Stages, strategy)
 
 	WriteJSON(w, http.StatusOK, map[string]interface{}{
 		"candidates": result.Candidates,
@@ -1276,6 +1454,59 @@ func (s *Server) handleScreenFunnel(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// handleScreenScored runs an EODHD screener query and ranks the results by a
+// weighted composite of valuation, momentum and dividend scores.
+func (s *Server) handleScreenScored(w http.ResponseWriter, r *http.Request) {
+	if !RequireMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	var req struct {
+		Exchange string                  `json:"exchange"`
+		Filters  []models.ScreenerFilter `json:"filters"`
+		Limit    int                     `json:"limit"`
+		Weights  models.ScoreWeights     `json:"weights"`
+	}
+	if !DecodeJSON(w, r, &req) {
+		return
+	}
+
+	if req.Exchange == "" {
+		WriteError(w, http.StatusBadRequest, "exchange is required")
+		return
+	}
+	if req.Weights.Valuation < 0 || req.Weights.Momentum < 0 || req.Weights.Dividend < 0 {
+		WriteError(w, http.StatusBadRequest, "weights must not be negative")
+		return
+	}
+	if req.Limit <= 0 {
+		req.Limit = 20
+	}
+	if req.Limit > 100 {
+		req.Limit = 100
+	}
+
+	filters := append([]models.ScreenerFilter{{Field: "exchange", Operator: "=", Value: req.Exchange}}, req.Filters...)
+	results, err := s.app.MarketService.ScreenAndScore(r.Context(), models.ScreenerOptions{
+		Filters: filters,
+		Sort:    "market_capitalization.desc",
+		Limit:   100,
+	}, req.Weights)
+	if err != nil {
+		WriteError(w, http.StatusInternalServerError, fmt.Sprintf("Scored screen error: %v", err))
+		return
+	}
+	if len(results) > req.Limit {
+		results = results[:req.Limit]
+	}
+
+	WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"results": results,
+		"count":   len(results),
+		"weights": req.Weights,
+	})
+}
+
 // --- Report handlers ---
 
 func (s *Server) handleReportList(w http.ResponseWriter, r *http.Request) {
@@ -1283,10 +1514,24 @@ func (s *Server) handleReportList(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	page, errMsg := ParsePage(r)
+	if errMsg != "" {
+		WriteError(w, http.StatusBadRequest, errMsg)
+		return
+	}
+
 	filterName := r.URL.Query().Get("portfolio_name")
 	userID := common.ResolveUserID(r.Context())
 
-	records, err := s.app.Storage.UserDataStore().List(r.Context(), userID, "report")
+	// Newest first, ties by portfolio name; the store applies the page
+	store := s.app.Storage.UserDataStore()
+	opts := interfaces.QueryOptions{Limit: page.Limit, Offset: page.Offset, Key: filterName}
+	total, err := store.Count(r.Context(), userID, "report", opts)
+	if err != nil {
+		WriteError(w, http.StatusInternalServerError, fmt.Sprintf("Error listing reports: %v", err))
+		return
+	}
+	records, err := store.Query(r.Context(), userID, "report", opts)
 	if err != nil {
 		WriteError(w, http.StatusInternalServerError, fmt.Sprintf("Error listing reports: %v", err))
 		return
@@ -1298,11 +1543,8 @@ func (s *Server) handleReportList(w http.ResponseWriter, r *http.Request) {
 		TickerCount   int       `json:"ticker_count"`
 	}
 
-	var result []reportInfo
+	result := make([]reportInfo, 0, len(records))
 	for _, rec := range records {
-		if filterName != "" && !strings.EqualFold(rec.Key, filterName) {
-			continue
-		}
 		var report models.PortfolioReport
 		if err := json.Unmarshal([]byte(rec.Value), &report); err != nil {
 			continue
@@ -1315,7 +1557,39 @@ func (s *Server) handleReportList(w http.ResponseWriter, r *http.Request) {
 	}
 
 	WriteJSON(w, http.StatusOK, map[string]interface{}{
-		"reports": result,
+		"items":       result,
+		"total":       total,
+		"next_cursor": page.Next(len(records), total),
+	})
+}
+
+func (s *Server) handleReportSearch(w http.ResponseWriter, r *http.Request) {
+	if !RequireMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		WriteError(w, http.StatusBadRequest, "q is required")
+		return
+	}
+	opts := interfaces.ReportSearchOptions{Portfolio: r.URL.Query().Get("portfolio_name")}
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		if n, err := strconv.Atoi(limit); err == nil && n > 0 {
+			opts.Limit = n
+		}
+	}
+
+	results, err := s.app.ReportService.SearchReports(r.Context(), query, opts)
+	if err != nil {
+		WriteError(w, http.StatusInternalServerError, fmt.Sprintf("Error searching reports: %v", err))
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"query":   query,
+		"results": results,
+		"count":   len(results),
 	})
 }
 
@@ -1365,6 +1639,82 @@ func (s *Server) handlePortfolioReport(w http.ResponseWriter, r *http.Request, n
 	})
 }
 
+// reportPDFCategory is the FileStore category for rendered report PDFs.
+const reportPDFCategory = "report_pdf"
+
+// handlePortfolioReportPDF renders a stored report to PDF and saves it to the
+// FileStore (POST), or serves a previously rendered PDF (GET {report_id}).
+func (s *Server) handlePortfolioReportPDF(w http.ResponseWriter, r *http.Request, name, reportID string) {
+	ctx := s.app.InjectNavexaClient(r.Context())
+	userID := common.ResolveUserID(ctx)
+
+	switch r.Method {
+	case http.MethodGet:
+		if reportID == "" {
+			WriteError(w, http.StatusBadRequest, "report_id is required")
+			return
+		}
+		data, _, err := s.app.Storage.FileStore().GetFile(ctx, reportPDFCategory, reportPDFKey(userID, name, reportID))
+		if err != nil {
+			WriteError(w, http.StatusNotFound, fmt.Sprintf("PDF not found: %v", err))
+			return
+		}
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name+"-"+reportID+".pdf"))
+		w.WriteHeader(http.StatusOK)
+		w.Write(data)
+
+	case http.MethodPost:
+		var req struct {
+			ReportID string `json:"report_id"`
+		}
+		if r.Body != nil {
+			json.NewDecoder(r.Body).Decode(&req)
+		}
+
+		report, err := s.app.ReportService.GetReport(ctx, name)
+		if err != nil {
+			if req.ReportID != "" {
+				WriteError(w, http.StatusNotFound, fmt.Sprintf("Report not found: %v", err))
+				return
+			}
+			report, err = s.app.ReportService.GenerateReport(ctx, name, interfaces.ReportOptions{})
+			if err != nil {
+				WriteError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to generate report: %v", err))
+				return
+			}
+		}
+		if req.ReportID == "" {
+			req.ReportID = report.ID()
+		}
+
+		data, err := s.app.ReportService.GeneratePDF(ctx, name, req.ReportID)
+		if err != nil {
+			WriteError(w, http.StatusNotFound, fmt.Sprintf("PDF generation error: %v", err))
+			return
+		}
+		if err := s.app.Storage.FileStore().SaveFile(ctx, reportPDFCategory, reportPDFKey(userID, name, req.ReportID), data, "application/pdf"); err != nil {
+			WriteError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to store PDF: %v", err))
+			return
+		}
+
+		WriteJSON(w, http.StatusOK, map[string]interface{}{
+			"report_id":    req.ReportID,
+			"generated_at": report.GeneratedAt,
+			"size_bytes":   len(data),
+			"url":          fmt.Sprintf("/api/portfolios/%s/report/pdf/%s", url.PathEscape(name), url.PathEscape(req.ReportID)),
+		})
+
+	default:
+		RequireMethod(w, r, http.MethodGet, http.MethodPost)
+	}
+}
+
+// reportPDFKey scopes stored PDFs by user and portfolio.
+func reportPDFKey(userID, portfolioName, reportID string) string {
+	return userID + "/" + portfolioName + "/" + reportID + ".pdf"
+}
+
 func (s *Server) handlePortfolioTickerReport(w http.ResponseWriter, r *http.Request, portfolioName, ticker string) {
 	ctx := s.app.InjectNavexaClient(r.Context())
 
@@ -1436,6 +1786,12 @@ func (s *Server) handlePortfolioTickers(w http.ResponseWriter, r *http.Request,
 		return
 	}
 
+	page, errMsg := ParsePage(r)
+	if errMsg != "" {
+		WriteError(w, http.StatusBadRequest, errMsg)
+		return
+	}
+
 	report, err := s.app.ReportService.GetReport(r.Context(), name)
 	if err != nil {
 		WriteError(w, http.StatusNotFound, fmt.Sprintf("Report not found for '%s': %v", name, err))
@@ -1448,7 +1804,7 @@ func (s *Server) handlePortfolioTickers(w http.ResponseWriter, r *http.Request,
 		IsETF  bool   `json:"is_etf"`
 	}
 
-	var tickers []tickerInfo
+	tickers := make([]tickerInfo, 0, len(report.TickerReports))
 	for _, tr := range report.TickerReports {
 		tickers = append(tickers, tickerInfo{
 			Ticker: tr.Ticker,
@@ -1456,11 +1812,15 @@ func (s *Server) handlePortfolioTickers(w http.ResponseWriter, r *http.Request,
 			IsETF:  tr.IsETF,
 		})
 	}
+	sort.Slice(tickers, func(i, j int) bool { return tickers[i].Ticker < tickers[j].Ticker })
 
+	start, end, next := page.Bounds(len(tickers))
 	WriteJSON(w, http.StatusOK, map[string]interface{}{
 		"portfolio":    name,
 		"generated_at": report.GeneratedAt,
-		"tickers":      tickers,
+		"items":        tickers[start:end],
+		"total":        len(tickers),
+		"next_cursor":  next,
 	})
 }
 
@@ -1596,16 +1956,64 @@ func (s *Server) handlePortfolioStrategy(w http.ResponseWriter, r *http.Request,
 	}
 }
 
-// --- Plan handlers ---
+// handleStrategyApply applies one strategy to several portfolios at once.
+func (s *Server) handleStrategyApply(w http.ResponseWriter, r *http.Request) {
+	if !RequireMethod(w, r, http.MethodPost) {
+		return
+	}
 
-func (s *Server) handlePortfolioPlan(w http.ResponseWriter, r *http.Request, name string) {
-	ctx := r.Context()
+	var req struct {
+		PortfolioNames []string        `json:"portfolio_names"`
+		StrategyJSON   json.RawMessage `json:"strategy"`
+	}
+	if !DecodeJSON(w, r, &req) {
+		return
+	}
+	if len(req.PortfolioNames) == 0 {
+		WriteError(w, http.StatusBadRequest, "portfolio_names is required")
+		return
+	}
 
-	switch r.Method {
-	case http.MethodGet:
-		plan, err := s.app.PlanService.GetPlan(ctx, name)
-		if err != nil {
-			WriteJSON(w, http.StatusOK, map[string]interface{}{
+	// Unwrap string-encoded JSON (see handlePortfolioStrategy)
+	strategyBytes := []byte(req.StrategyJSON)
+	if len(strategyBytes) > 0 && strategyBytes[0] == '"' {
+		var unwrapped string
+		if err := json.Unmarshal(strategyBytes, &unwrapped); err == nil {
+			strategyBytes = []byte(unwrapped)
+		}
+	}
+	var strategy models.PortfolioStrategy
+	if err := json.Unmarshal(strategyBytes, &strategy); err != nil {
+		WriteError(w, http.StatusBadRequest, fmt.Sprintf("Error parsing strategy: %v", err))
+		return
+	}
+
+	warnings, err := s.app.StrategyService.ApplyStrategyToPortfolios(r.Context(), req.PortfolioNames, strategy)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			WriteError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		WriteError(w, http.StatusBadRequest, fmt.Sprintf("Error applying strategy: %v", err))
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"applied":  req.PortfolioNames,
+		"warnings": warnings,
+	})
+}
+
+// --- Plan handlers ---
+
+func (s *Server) handlePortfolioPlan(w http.ResponseWriter, r *http.Request, name string) {
+	ctx := r.Context()
+
+	switch r.Method {
+	case http.MethodGet:
+		plan, err := s.app.PlanService.GetPlan(ctx, name)
+		if err != nil {
+			WriteJSON(w, http.StatusOK, map[string]interface{}{
 				"exists":  false,
 				"message": fmt.Sprintf("No plan found for portfolio '%s'", name),
 			})
@@ -1615,15 +2023,21 @@ func (s *Server) handlePortfolioPlan(w http.ResponseWriter, r *http.Request, nam
 
 	case http.MethodPut:
 		var raw struct {
-			Items json.RawMessage `json:"items"`
-			Notes string          `json:"notes"`
+			Items             json.RawMessage `json:"items"`
+			Notes             string          `json:"notes"`
+			DriftTolerancePct float64         `json:"drift_tolerance_pct"`
 		}
 		if !DecodeJSON(w, r, &raw) {
 			return
 		}
+		if raw.DriftTolerancePct < 0 {
+			WriteError(w, http.StatusBadRequest, "drift_tolerance_pct must not be negative")
+			return
+		}
 		var plan models.PortfolioPlan
 		plan.PortfolioName = name
 		plan.Notes = raw.Notes
+		plan.DriftTolerancePct = raw.DriftTolerancePct
 		if len(raw.Items) > 0 {
 			if err := UnmarshalArrayParam(raw.Items, &plan.Items); err != nil {
 				WriteError(w, http.StatusBadRequest, "Invalid items: "+err.Error())
@@ -1667,12 +2081,30 @@ func (s *Server) handlePlanStatus(w http.ResponseWriter, r *http.Request, name s
 		return
 	}
 
+	stopped, err := s.app.PlanService.CheckTrailingStops(ctx, name)
+	if err != nil {
+		WriteError(w, http.StatusInternalServerError, fmt.Sprintf("Error checking trailing stops: %v", err))
+		return
+	}
+	triggered = append(triggered, stopped...)
+
 	plan, _ := s.app.PlanService.GetPlan(ctx, name)
 
+	// Pending trailing stops with their current trail level and distance to trigger
+	trailingStops := []models.PlanItem{}
+	if plan != nil {
+		for _, item := range plan.Items {
+			if item.Type == models.PlanItemTypeTrailingStop && item.Status == models.PlanItemStatusPending {
+				trailingStops = append(trailingStops, item)
+			}
+		}
+	}
+
 	WriteJSON(w, http.StatusOK, map[string]interface{}{
-		"triggered": triggered,
-		"expired":   expired,
-		"plan":      plan,
+		"triggered":      triggered,
+		"expired":        expired,
+		"trailing_stops": trailingStops,
+		"plan":           plan,
 	})
 }
 
@@ -1871,6 +2303,24 @@ func (s *Server) handleWatchlistReview(w http.ResponseWriter, r *http.Request, n
 	}
 }
 
+func (s *Server) handleWatchlistEvents(w http.ResponseWriter, r *http.Request, name string) {
+	if !RequireMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	events, err := s.app.WatchlistService.ListEvents(r.Context(), name)
+	if err != nil {
+		WriteError(w, http.StatusInternalServerError, fmt.Sprintf("Error listing watchlist events: %v", err))
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"portfolio_name": name,
+		"events":         events,
+		"count":          len(events),
+	})
+}
+
 func (s *Server) handleWatchlistItemAdd(w http.ResponseWriter, r *http.Request, name string) {
 	if !RequireMethod(w, r, http.MethodPost) {
 		return
@@ -2056,6 +2506,181 @@ func (s *Server) handleHoldingNoteItem(w http.ResponseWriter, r *http.Request, n
 	}
 }
 
+// handlePortfolioAlerts handles GET /api/portfolios/{name}/alerts.
+func (s *Server) handlePortfolioAlerts(w http.ResponseWriter, r *http.Request, name string) {
+	if !RequireMethod(w, r, http.MethodGet) {
+		return
+	}
+	alerts, err := s.app.PortfolioService.ListActiveAlerts(r.Context(), name)
+	if err != nil {
+		WriteError(w, http.StatusInternalServerError, fmt.Sprintf("Error listing alerts: %v", err))
+		return
+	}
+	WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"portfolio": name,
+		"alerts":    alerts,
+	})
+}
+
+// handlePortfolioAlertDigest handles GET /api/portfolios/{name}/alerts/digest.
+func (s *Server) handlePortfolioAlertDigest(w http.ResponseWriter, r *http.Request, name string) {
+	if !RequireMethod(w, r, http.MethodGet) {
+		return
+	}
+	digest, err := s.app.ReportService.FormatAlertsMarkdown(r.Context(), name)
+	if err != nil {
+		WriteError(w, http.StatusInternalServerError, fmt.Sprintf("Error formatting alerts: %v", err))
+		return
+	}
+	WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"portfolio": name,
+		"markdown":  digest,
+	})
+}
+
+// handlePortfolioCorrelation handles GET /api/portfolios/{name}/correlation?window=60.
+// Correlates daily returns of the portfolio's open holdings.
+func (s *Server) handlePortfolioCorrelation(w http.ResponseWriter, r *http.Request, name string) {
+	if !RequireMethod(w, r, http.MethodGet) {
+		return
+	}
+	window := signals.DefaultCorrelationWindow
+	if v := r.URL.Query().Get("window"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < signals.MinCorrelationOverlap {
+			WriteError(w, http.StatusBadRequest, fmt.Sprintf("window must be an integer of at least %d", signals.MinCorrelationOverlap))
+			return
+		}
+		window = n
+	}
+
+	portfolio, err := s.app.PortfolioService.GetPortfolio(r.Context(), name)
+	if err != nil {
+		writePortfolioLoadError(w, err)
+		return
+	}
+	var tickers []string
+	for _, h := range portfolio.Holdings {
+		if h.Units > 0 {
+			tickers = append(tickers, h.EODHDTicker())
+		}
+	}
+	if len(tickers) < 2 {
+		WriteError(w, http.StatusBadRequest, "portfolio needs at least two open holdings for a correlation matrix")
+		return
+	}
+
+	matrix, err := s.app.MarketService.CorrelationMatrix(r.Context(), tickers, window)
+	if err != nil {
+		WriteError(w, http.StatusInternalServerError, fmt.Sprintf("Correlation error: %v", err))
+		return
+	}
+	WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"portfolio": name,
+		"window":    window,
+		"matrix":    matrix,
+	})
+}
+
+// handlePortfolioAlertAcknowledge handles POST /api/portfolios/{name}/alerts/acknowledge.
+func (s *Server) handlePortfolioAlertAcknowledge(w http.ResponseWriter, r *http.Request, name string) {
+	if !RequireMethod(w, r, http.MethodPost) {
+		return
+	}
+	var req struct {
+		Ticker string `json:"ticker"`
+		Signal string `json:"signal"`
+	}
+	if !DecodeJSON(w, r, &req) {
+		return
+	}
+	if strings.TrimSpace(req.Signal) == "" {
+		WriteError(w, http.StatusBadRequest, "signal is required")
+		return
+	}
+
+	state, err := s.app.PortfolioService.AcknowledgeAlert(r.Context(), name, strings.TrimSpace(req.Ticker), strings.TrimSpace(req.Signal))
+	if err != nil {
+		if errors.Is(err, interfaces.ErrAlertNotFound) {
+			WriteError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		WriteError(w, http.StatusInternalServerError, fmt.Sprintf("Error acknowledging alert: %v", err))
+		return
+	}
+	WriteJSON(w, http.StatusOK, state)
+}
+
+const (
+	maxHoldingNoteLength = 2000
+	maxHoldingTags       = 20
+	maxHoldingTagLength  = 40
+)
+
+// handleHoldingAnnotationNote handles PUT /api/portfolios/{name}/stock/{ticker}/note.
+func (s *Server) handleHoldingAnnotationNote(w http.ResponseWriter, r *http.Request, name, ticker string) {
+	if !RequireMethod(w, r, http.MethodPut) {
+		return
+	}
+	ticker, errMsg := validateTicker(ticker)
+	if errMsg != "" {
+		WriteError(w, http.StatusBadRequest, errMsg)
+		return
+	}
+	var req struct {
+		Note string `json:"note"`
+	}
+	if !DecodeJSON(w, r, &req) {
+		return
+	}
+	if len(req.Note) > maxHoldingNoteLength {
+		WriteError(w, http.StatusBadRequest, fmt.Sprintf("note too long (max %d chars)", maxHoldingNoteLength))
+		return
+	}
+
+	annotation, err := s.app.HoldingNoteService.SetHoldingNote(r.Context(), name, ticker, req.Note)
+	if err != nil {
+		WriteError(w, http.StatusInternalServerError, fmt.Sprintf("Error saving note: %v", err))
+		return
+	}
+	WriteJSON(w, http.StatusOK, annotation)
+}
+
+// handleHoldingAnnotationTags handles PUT /api/portfolios/{name}/stock/{ticker}/tags.
+func (s *Server) handleHoldingAnnotationTags(w http.ResponseWriter, r *http.Request, name, ticker string) {
+	if !RequireMethod(w, r, http.MethodPut) {
+		return
+	}
+	ticker, errMsg := validateTicker(ticker)
+	if errMsg != "" {
+		WriteError(w, http.StatusBadRequest, errMsg)
+		return
+	}
+	var req struct {
+		Tags []string `json:"tags"`
+	}
+	if !DecodeJSON(w, r, &req) {
+		return
+	}
+	if len(req.Tags) > maxHoldingTags {
+		WriteError(w, http.StatusBadRequest, fmt.Sprintf("too many tags (max %d)", maxHoldingTags))
+		return
+	}
+	for _, tag := range req.Tags {
+		if len(tag) > maxHoldingTagLength {
+			WriteError(w, http.StatusBadRequest, fmt.Sprintf("tag %q too long (max %d chars)", tag, maxHoldingTagLength))
+			return
+		}
+	}
+
+	annotation, err := s.app.HoldingNoteService.SetHoldingTags(r.Context(), name, ticker, req.Tags)
+	if err != nil {
+		WriteError(w, http.StatusInternalServerError, fmt.Sprintf("Error saving tags: %v", err))
+		return
+	}
+	WriteJSON(w, http.StatusOK, annotation)
+}
+
 // --- Search handlers ---
 
 func (s *Server) handleSearchList(w http.ResponseWriter, r *http.Request) {
@@ -2177,6 +2802,184 @@ func (s *Server) handlePortfolioIndicators(w http.ResponseWriter, r *http.Reques
 	WriteJSON(w, http.StatusOK, indicators)
 }
 
+func (s *Server) handlePortfolioCompleteness(w http.ResponseWriter, r *http.Request, name string) {
+	if !RequireMethod(w, r, http.MethodGet) {
+		return
+	}
+	completeness, err := s.app.PortfolioService.GetDataCompleteness(r.Context(), name)
+	if err != nil {
+		WriteError(w, http.StatusNotFound, fmt.Sprintf("Data completeness error: %v", err))
+		return
+	}
+	WriteJSON(w, http.StatusOK, completeness)
+}
+
+// handlePortfolioCGT handles GET /api/portfolios/{name}/cgt?financial_year=2023-2024.
+// Defaults to the current financial year.
+func (s *Server) handlePortfolioCGT(w http.ResponseWriter, r *http.Request, name string) {
+	if !RequireMethod(w, r, http.MethodGet) {
+		return
+	}
+	fy := strings.TrimSpace(r.URL.Query().Get("financial_year"))
+	if fy == "" {
+		fy = common.FinancialYearOf(time.Now())
+	}
+	if _, _, err := common.ParseFinancialYear(fy); err != nil {
+		WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	report, err := s.app.PortfolioService.CalculateCGT(r.Context(), name, fy)
+	if err != nil {
+		WriteError(w, http.StatusNotFound, fmt.Sprintf("CGT report error: %v", err))
+		return
+	}
+	WriteJSON(w, http.StatusOK, report)
+}
+
+// handlePortfolioRealizedTimeline handles GET /api/portfolios/{name}/realized-timeline.
+func (s *Server) handlePortfolioRealizedTimeline(w http.ResponseWriter, r *http.Request, name string) {
+	if !RequireMethod(w, r, http.MethodGet) {
+		return
+	}
+	timeline, err := s.app.PortfolioService.RealizedGainTimeline(r.Context(), name)
+	if err != nil {
+		WriteError(w, http.StatusNotFound, fmt.Sprintf("Realized timeline error: %v", err))
+		return
+	}
+	WriteJSON(w, http.StatusOK, timeline)
+}
+
+// handlePortfolioExport handles GET /api/portfolios/{name}/export?format=sharesight.
+// Returns the trade import CSV with any skipped trades and warnings.
+func (s *Server) handlePortfolioExport(w http.ResponseWriter, r *http.Request, name string) {
+	if !RequireMethod(w, r, http.MethodGet) {
+		return
+	}
+	export, err := s.app.ReportService.ExportTradesForImport(r.Context(), name, r.URL.Query().Get("format"))
+	if errors.Is(err, interfaces.ErrUnsupportedExportFormat) {
+		WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		WriteError(w, portfolioErrorStatus(err, http.StatusInternalServerError), fmt.Sprintf("Export error: %v", err))
+		return
+	}
+	WriteJSON(w, http.StatusOK, export)
+}
+
+// handlePortfolioTWR handles GET /api/portfolios/{name}/twr?from=YYYY-MM-DD&to=YYYY-MM-DD.
+// Defaults to inception through today.
+func (s *Server) handlePortfolioTWR(w http.ResponseWriter, r *http.Request, name string) {
+	if !RequireMethod(w, r, http.MethodGet) {
+		return
+	}
+	from := strings.TrimSpace(r.URL.Query().Get("from"))
+	to := strings.TrimSpace(r.URL.Query().Get("to"))
+	for _, d := range []string{from, to} {
+		if _, err := time.Parse("2006-01-02", d); d != "" && err != nil {
+			WriteError(w, http.StatusBadRequest, fmt.Sprintf("invalid date %q (want YYYY-MM-DD)", d))
+			return
+		}
+	}
+	twr, err := s.app.PortfolioService.TimeWeightedReturn(r.Context(), name, from, to)
+	if err != nil {
+		WriteError(w, http.StatusUnprocessableEntity, fmt.Sprintf("Time-weighted return error: %v", err))
+		return
+	}
+	WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"portfolio_name": name,
+		"from":           from,
+		"to":             to,
+		"twr_pct":        twr,
+	})
+}
+
+func (s *Server) handlePortfolioSectors(w http.ResponseWriter, r *http.Request, name string) {
+	if !RequireMethod(w, r, http.MethodGet) {
+		return
+	}
+	breakdown, err := s.app.PortfolioService.SectorAllocation(r.Context(), name)
+	if err != nil {
+		WriteError(w, http.StatusNotFound, fmt.Sprintf("Sector allocation error: %v", err))
+		return
+	}
+	WriteJSON(w, http.StatusOK, breakdown)
+}
+
+func (s *Server) handlePortfolioFees(w http.ResponseWriter, r *http.Request, name string) {
+	if !RequireMethod(w, r, http.MethodGet) {
+		return
+	}
+	summary, err := s.app.PortfolioService.FeeSummary(r.Context(), name)
+	if err != nil {
+		WriteError(w, http.StatusNotFound, fmt.Sprintf("Fee summary error: %v", err))
+		return
+	}
+	WriteJSON(w, http.StatusOK, summary)
+}
+
+func (s *Server) handlePortfolioRebalance(w http.ResponseWriter, r *http.Request, name string) {
+	if !RequireMethod(w, r, http.MethodGet) {
+		return
+	}
+	plan, err := s.app.PortfolioService.RebalanceSuggestions(r.Context(), name)
+	if err != nil {
+		WriteError(w, http.StatusNotFound, fmt.Sprintf("Rebalance error: %v", err))
+		return
+	}
+	WriteJSON(w, http.StatusOK, plan)
+}
+
+// handlePortfolioProjection handles GET /api/portfolios/{name}/projection?years=5&simulations=1000.
+func (s *Server) handlePortfolioProjection(w http.ResponseWriter, r *http.Request, name string) {
+	if !RequireMethod(w, r, http.MethodGet) {
+		return
+	}
+	years, simulations := 0, 0
+	if v := r.URL.Query().Get("years"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > portfolio.MaxProjectionYears {
+			WriteError(w, http.StatusBadRequest, fmt.Sprintf("years must be an integer from 1 to %d", portfolio.MaxProjectionYears))
+			return
+		}
+		years = n
+	}
+	if v := r.URL.Query().Get("simulations"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > portfolio.MaxProjectionSimulations {
+			WriteError(w, http.StatusBadRequest, fmt.Sprintf("simulations must be an integer from 1 to %d", portfolio.MaxProjectionSimulations))
+			return
+		}
+		simulations = n
+	}
+	projection, err := s.app.PortfolioService.ProjectValue(r.Context(), name, years, simulations)
+	if err != nil {
+		writePortfolioLoadError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, projection)
+}
+
+func (s *Server) handlePortfolioSimulateTrade(w http.ResponseWriter, r *http.Request, name string) {
+	if !RequireMethod(w, r, http.MethodPost) {
+		return
+	}
+	var trade models.SimulatedTrade
+	if !DecodeJSON(w, r, &trade) {
+		return
+	}
+	if strings.TrimSpace(trade.Ticker) == "" {
+		WriteError(w, http.StatusBadRequest, "ticker is required")
+		return
+	}
+	sim, err := s.app.PortfolioService.SimulateTrade(r.Context(), name, trade)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, fmt.Sprintf("Simulation error: %v", err))
+		return
+	}
+	WriteJSON(w, http.StatusOK, sim)
+}
+
 // --- Cash flow handlers ---
 
 // cashAccountWithBalance is a response-only struct that adds computed balance to CashAccount.
@@ -2190,14 +2993,15 @@ type cashAccountWithBalance struct {
 
 // cashFlowResponse wraps CashFlowLedger with computed summary totals and per-account balances.
 type cashFlowResponse struct {
-	PortfolioName string                   `json:"portfolio_name"`
-	Version       int                      `json:"version"`
-	Accounts      []cashAccountWithBalance `json:"accounts"`
-	Transactions  []models.CashTransaction `json:"transactions"`
-	Summary       models.CashFlowSummary   `json:"summary"`
-	Notes         string                   `json:"notes,omitempty"`
-	CreatedAt     time.Time                `json:"created_at"`
-	UpdatedAt     time.Time                `json:"updated_at"`
+	PortfolioName  string                   `json:"portfolio_name"`
+	Version        int                      `json:"version"`
+	Accounts       []cashAccountWithBalance `json:"accounts"`
+	Transactions   []models.CashTransaction `json:"transactions"`
+	BrokerBalances []models.BrokerBalance   `json:"broker_balances,omitempty"`
+	Summary        models.CashFlowSummary   `json:"summary"`
+	Notes          string                   `json:"notes,omitempty"`
+	CreatedAt      time.Time                `json:"created_at"`
+	UpdatedAt      time.Time                `json:"updated_at"`
 }
 
 func newCashFlowResponse(ledger *models.CashFlowLedger) cashFlowResponse {
@@ -2217,14 +3021,15 @@ func newCashFlowResponse(ledger *models.CashFlowLedger) cashFlowResponse {
 		}
 	}
 	return cashFlowResponse{
-		PortfolioName: ledger.PortfolioName,
-		Version:       ledger.Version,
-		Accounts:      accounts,
-		Transactions:  ledger.Transactions,
-		Summary:       ledger.Summary(),
-		Notes:         ledger.Notes,
-		CreatedAt:     ledger.CreatedAt,
-		UpdatedAt:     ledger.UpdatedAt,
+		PortfolioName:  ledger.PortfolioName,
+		Version:        ledger.Version,
+		Accounts:       accounts,
+		Transactions:   ledger.Transactions,
+		BrokerBalances: ledger.BrokerBalances,
+		Summary:        ledger.Summary(),
+		Notes:          ledger.Notes,
+		CreatedAt:      ledger.CreatedAt,
+		UpdatedAt:      ledger.UpdatedAt,
 	}
 }
 
@@ -2234,7 +3039,7 @@ func (s *Server) handleCashFlows(w http.ResponseWriter, r *http.Request, name st
 	switch r.Method {
 	case http.MethodGet:
 		if _, err := s.app.PortfolioService.GetPortfolio(ctx, name); err != nil {
-			WriteError(w, http.StatusNotFound, fmt.Sprintf("Portfolio not found: %v", err))
+			writePortfolioLoadError(w, err)
 			return
 		}
 		ledger, err := s.app.CashFlowService.GetLedger(ctx, name)
@@ -2295,7 +3100,7 @@ func (s *Server) handleCashFlows(w http.ResponseWriter, r *http.Request, name st
 
 	case http.MethodDelete:
 		if _, err := s.app.PortfolioService.GetPortfolio(ctx, name); err != nil {
-			WriteError(w, http.StatusNotFound, fmt.Sprintf("Portfolio not found: %v", err))
+			writePortfolioLoadError(w, err)
 			return
 		}
 		ledger, err := s.app.CashFlowService.ClearLedger(ctx, name)
@@ -2389,6 +3194,51 @@ func (s *Server) handleCashFlowTransfer(w http.ResponseWriter, r *http.Request,
 	WriteJSON(w, http.StatusCreated, newCashFlowResponse(ledger))
 }
 
+func (s *Server) handleCashReconcile(w http.ResponseWriter, r *http.Request, name string) {
+	ctx := r.Context()
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		var req struct {
+			Date    string   `json:"date"`
+			Balance *float64 `json:"balance"`
+			Notes   string   `json:"notes"`
+		}
+		if !DecodeJSON(w, r, &req) {
+			return
+		}
+		if req.Balance == nil {
+			WriteError(w, http.StatusBadRequest, "balance is required")
+			return
+		}
+		date, err := time.Parse("2006-01-02", req.Date)
+		if err != nil {
+			WriteError(w, http.StatusBadRequest, "invalid date format; use YYYY-MM-DD")
+			return
+		}
+		balance := models.BrokerBalance{Date: date, Balance: *req.Balance, Notes: req.Notes}
+		if _, err := s.app.CashFlowService.SetBrokerBalance(ctx, name, balance); err != nil {
+			if errors.Is(err, interfaces.ErrInvalidBrokerBalance) {
+				WriteError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			WriteError(w, http.StatusInternalServerError, fmt.Sprintf("Error recording broker balance: %v", err))
+			return
+		}
+	default:
+		RequireMethod(w, r, http.MethodGet, http.MethodPost)
+		return
+	}
+
+	rec, err := s.app.CashFlowService.Reconcile(ctx, name)
+	if err != nil {
+		WriteError(w, http.StatusInternalServerError, fmt.Sprintf("Error reconciling cash: %v", err))
+		return
+	}
+	WriteJSON(w, http.StatusOK, rec)
+}
+
 // --- Helper methods ---
 
 func (s *Server) resolvePortfolio(ctx context.Context, requested string) string {
@@ -2557,11 +3407,15 @@ func (s *Server) autoSaveSnipeSearch(ctx context.Context, buys []*models.SnipeBu
 	return s.saveSearchRecord(ctx, record)
 }
 
-func (s *Server) autoSaveFunnelSearch(ctx context.Context, result *models.FunnelResult, exchange, sector string, strategy *models.PortfolioStrategy) string {
-	filtersJSON, _ := json.Marshal(map[string]interface{}{
+func (s *Server) autoSaveFunnelSearch(ctx context.Context, result *models.FunnelResult, exchange, sector string, stages []models.FunnelFilterStage, strategy *models.PortfolioStrategy) string {
+	filters := map[string]interface{}{
 		"exchange": exchange,
 		"sector":   sector,
-	})
+	}
+	if len(stages) > 0 {
+		filters["stages"] = stages
+	}
+	filtersJSON, _ := json.Marshal(filters)
 	resultsJSON, _ := json.Marshal(result.Candidates)
 	stagesJSON, _ := json.Marshal(result.Stages)
 
@@ -2619,7 +3473,7 @@ func (s *Server) handlePortfolioStatus(w http.ResponseWriter, r *http.Request, n
 
 	portfolio, err := s.app.PortfolioService.GetPortfolio(ctx, name)
 	if err != nil {
-		WriteError(w, http.StatusNotFound, fmt.Sprintf("Portfolio not found: %v", err))
+		writePortfolioLoadError(w, err)
 		return
 	}
 
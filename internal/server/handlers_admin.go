@@ -1,13 +1,22 @@
 package server
 
 import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/bobmcallan/vire/internal/app"
 	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/interfaces"
 	"github.com/bobmcallan/vire/internal/models"
+	"github.com/bobmcallan/vire/internal/services/jobmanager"
 )
 
 // requireAdmin checks that the user has admin role. Returns false if not admin.
@@ -156,6 +165,15 @@ func (s *Server) handleAdminJobs(w http.ResponseWriter, r *http.Request) {
 		WriteJSON(w, http.StatusOK, map[string]interface{}{"jobs": jobs})
 		return
 	}
+	if status == models.JobStatusDeadLetter {
+		jobs, err := store.ListDeadLetter(ctx, limit)
+		if err != nil {
+			WriteError(w, http.StatusInternalServerError, "Failed to list dead-letter jobs: "+err.Error())
+			return
+		}
+		WriteJSON(w, http.StatusOK, map[string]interface{}{"jobs": jobs})
+		return
+	}
 
 	jobs, err := store.ListAll(ctx, limit)
 	if err != nil {
@@ -190,6 +208,31 @@ func (s *Server) handleAdminJobQueue(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// handleAdminJobExport handles GET /api/admin/jobs/export — full queue state
+// (all statuses, timestamps, errors) with per-type stats for offline diagnosis.
+func (s *Server) handleAdminJobExport(w http.ResponseWriter, r *http.Request) {
+	if !RequireMethod(w, r, http.MethodGet) {
+		return
+	}
+	if !s.requireAdmin(w, r) {
+		return
+	}
+
+	limit := 1000
+	if l := r.URL.Query().Get("limit"); l != "" {
+		if v, err := strconv.Atoi(l); err == nil && v > 0 && v <= 10000 {
+			limit = v
+		}
+	}
+
+	jobs, err := s.app.Storage.JobQueueStore().ListAll(r.Context(), limit)
+	if err != nil {
+		WriteError(w, http.StatusInternalServerError, "Failed to export jobs: "+err.Error())
+		return
+	}
+	WriteJSON(w, http.StatusOK, jobmanager.BuildQueueExport(jobs, time.Now()))
+}
+
 // handleAdminJobPriority handles PUT /api/admin/jobs/{id}/priority.
 func (s *Server) handleAdminJobPriority(w http.ResponseWriter, r *http.Request) {
 	if !RequireMethod(w, r, http.MethodPut) {
@@ -275,6 +318,59 @@ func (s *Server) handleAdminJobCancel(w http.ResponseWriter, r *http.Request) {
 	WriteJSON(w, http.StatusOK, map[string]string{"status": "cancelled"})
 }
 
+// handleAdminDeadLetterJobs handles GET /api/admin/jobs/dead-letter — jobs
+// that failed on every allowed attempt, with their final error.
+func (s *Server) handleAdminDeadLetterJobs(w http.ResponseWriter, r *http.Request) {
+	if !RequireMethod(w, r, http.MethodGet) {
+		return
+	}
+	if !s.requireAdmin(w, r) {
+		return
+	}
+
+	limit := 100
+	if l := r.URL.Query().Get("limit"); l != "" {
+		if v, err := strconv.Atoi(l); err == nil && v > 0 && v <= 1000 {
+			limit = v
+		}
+	}
+
+	jobs, err := s.app.Storage.JobQueueStore().ListDeadLetter(r.Context(), limit)
+	if err != nil {
+		WriteError(w, http.StatusInternalServerError, "Failed to list dead-letter jobs: "+err.Error())
+		return
+	}
+	if jobs == nil {
+		jobs = []*models.Job{}
+	}
+	WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"jobs":  jobs,
+		"count": len(jobs),
+	})
+}
+
+// handleAdminJobRequeue handles POST /api/admin/jobs/{id}/requeue — returns a
+// dead-lettered job to pending with its attempts reset.
+func (s *Server) handleAdminJobRequeue(w http.ResponseWriter, r *http.Request, jobID string) {
+	if !RequireMethod(w, r, http.MethodPost) {
+		return
+	}
+	if !s.requireAdmin(w, r) {
+		return
+	}
+
+	job, err := s.app.Storage.JobQueueStore().RequeueDeadLetter(r.Context(), jobID)
+	if err != nil {
+		WriteError(w, http.StatusInternalServerError, "Failed to requeue job: "+err.Error())
+		return
+	}
+	if job == nil {
+		WriteError(w, http.StatusNotFound, "Job '"+jobID+"' is not in the dead letter queue")
+		return
+	}
+	WriteJSON(w, http.StatusOK, job)
+}
+
 // handleAdminJobEnqueue handles POST /api/admin/jobs/enqueue — manually enqueue a job.
 func (s *Server) handleAdminJobEnqueue(w http.ResponseWriter, r *http.Request) {
 	if !RequireMethod(w, r, http.MethodPost) {
@@ -557,3 +653,270 @@ func (s *Server) handleAdminUpdateUserRole(w http.ResponseWriter, r *http.Reques
 		"role":  user.Role,
 	})
 }
+
+// handleAdminConfigReload handles POST /api/admin/config/reload: re-reads the
+// config file and applies its hot-reloadable fields. A file that fails to
+// parse or validate is rejected and the live config is kept.
+func (s *Server) handleAdminConfigReload(w http.ResponseWriter, r *http.Request) {
+	if !RequireMethod(w, r, http.MethodPost) {
+		return
+	}
+	if !s.requireAdmin(w, r) {
+		return
+	}
+	result, err := s.app.ReloadConfig()
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Config reload rejected: "+err.Error())
+		return
+	}
+	WriteJSON(w, http.StatusOK, result)
+}
+
+// handleAdminWebhookTest handles POST /api/admin/webhooks/test. It sends a
+// sample event to every configured webhook and reports each delivery.
+func (s *Server) handleAdminWebhookTest(w http.ResponseWriter, r *http.Request) {
+	if !RequireMethod(w, r, http.MethodPost) {
+		return
+	}
+	if !s.requireAdmin(w, r) {
+		return
+	}
+	if s.app.NotifyService == nil {
+		WriteError(w, http.StatusServiceUnavailable, "Notifications unavailable")
+		return
+	}
+	deliveries, err := s.app.NotifyService.SendTestEvent(r.Context())
+	if err != nil {
+		if errors.Is(err, interfaces.ErrWebhooksNotConfigured) {
+			WriteError(w, http.StatusBadRequest, "No webhooks configured: set [notifications.webhooks] urls")
+			return
+		}
+		WriteError(w, http.StatusInternalServerError, "Webhook test failed: "+err.Error())
+		return
+	}
+	delivered := 0
+	for _, d := range deliveries {
+		if d.Delivered {
+			delivered++
+		}
+	}
+	WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"delivered":  delivered,
+		"failed":     len(deliveries) - delivered,
+		"deliveries": deliveries,
+	})
+}
+
+// handleAdminAPIKey handles POST and DELETE /api/admin/api-keys/{name}.
+// POST probes the key in the body and swaps it into the live client; DELETE
+// clears the stored key and falls back to the environment or config key.
+func (s *Server) handleAdminAPIKey(w http.ResponseWriter, r *http.Request) {
+	if !RequireMethod(w, r, http.MethodPost, http.MethodDelete) {
+		return
+	}
+	if !s.requireAdmin(w, r) {
+		return
+	}
+	name := strings.TrimPrefix(r.URL.Path, "/api/admin/api-keys/")
+	if name == "" || strings.Contains(name, "/") {
+		WriteError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	var result *app.APIKeyRotation
+	var err error
+	if r.Method == http.MethodDelete {
+		result, err = s.app.ClearAPIKey(r.Context(), name)
+	} else {
+		var body struct {
+			Key string `json:"key"`
+		}
+		if !DecodeJSON(w, r, &body) {
+			return
+		}
+		result, err = s.app.SetAPIKey(r.Context(), name, body.Key)
+	}
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	WriteJSON(w, http.StatusOK, result)
+}
+
+const (
+	// backupCategory is the FileStore category for backup archives.
+	backupCategory  = "backup"
+	backupKeyPrefix = "vire-backup-"
+	// maxRestoreUploadBytes bounds an archive uploaded for restore.
+	maxRestoreUploadBytes = 512 << 20
+	// maxStoredBackupBytes bounds an archive kept in the FileStore, which
+	// stores each file base64-encoded in a single document of at most 10 MB.
+	// Larger stores are backed up via GET /api/admin/backups/export.
+	maxStoredBackupBytes = 7 << 20
+)
+
+// handleAdminBackupCreate handles POST /api/admin/backups — writes a backup
+// archive to the FileStore and returns its key and download path.
+func (s *Server) handleAdminBackupCreate(w http.ResponseWriter, r *http.Request) {
+	if !RequireMethod(w, r, http.MethodPost) {
+		return
+	}
+	if !s.requireAdmin(w, r) {
+		return
+	}
+	ctx := r.Context()
+
+	// The archive is streamed through a pipe and read no further than the
+	// FileStore can hold, so memory is bounded whatever the store's size.
+	pr, pw := io.Pipe()
+	var manifest *models.BackupManifest
+	backupDone := make(chan error, 1)
+	go func() {
+		var err error
+		manifest, err = s.app.Storage.Backup(ctx, pw)
+		pw.CloseWithError(err)
+		backupDone <- err
+	}()
+	data, readErr := io.ReadAll(io.LimitReader(pr, maxStoredBackupBytes+1))
+	tooLarge := readErr == nil && len(data) > maxStoredBackupBytes
+	if tooLarge {
+		pr.CloseWithError(errors.New("backup exceeds the file store limit"))
+	}
+	backupErr := <-backupDone
+	switch {
+	case tooLarge:
+		WriteError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("Backup exceeds the %d MB file store limit; download it from GET /api/admin/backups/export instead", maxStoredBackupBytes>>20))
+		return
+	case backupErr != nil:
+		WriteError(w, http.StatusInternalServerError, "Backup failed: "+backupErr.Error())
+		return
+	case readErr != nil:
+		WriteError(w, http.StatusInternalServerError, "Backup failed: "+readErr.Error())
+		return
+	}
+
+	key := backupKeyPrefix + manifest.CreatedAt.UTC().Format("20060102T150405Z") + ".jsonl.gz"
+	if err := s.app.Storage.FileStore().SaveFile(ctx, backupCategory, key, data, "application/gzip"); err != nil {
+		WriteError(w, http.StatusInternalServerError, "Failed to store backup: "+err.Error())
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"key":           key,
+		"download_path": "/api/admin/backups/" + key,
+		"size_bytes":    len(data),
+		"manifest":      manifest,
+	})
+}
+
+// handleAdminBackupExport handles GET /api/admin/backups/export — streams a
+// fresh backup archive straight to the response without storing it, for
+// stores too large for the FileStore.
+func (s *Server) handleAdminBackupExport(w http.ResponseWriter, r *http.Request) {
+	if !RequireMethod(w, r, http.MethodGet) {
+		return
+	}
+	if !s.requireAdmin(w, r) {
+		return
+	}
+	filename := backupKeyPrefix + time.Now().UTC().Format("20060102T150405Z") + ".jsonl.gz"
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.WriteHeader(http.StatusOK)
+	if _, err := s.app.Storage.Backup(r.Context(), w); err != nil {
+		// Headers are sent; the truncated archive fails its gzip checksum,
+		// so restore rejects it
+		s.logger.Error().Err(err).Msg("Backup export failed")
+	}
+}
+
+// routeAdminBackups handles GET /api/admin/backups/{key} (download),
+// GET /api/admin/backups/export and POST /api/admin/backups/restore.
+func (s *Server) routeAdminBackups(w http.ResponseWriter, r *http.Request) {
+	key := strings.TrimPrefix(r.URL.Path, "/api/admin/backups/")
+	switch key {
+	case "restore":
+		s.handleAdminBackupRestore(w, r)
+		return
+	case "export":
+		s.handleAdminBackupExport(w, r)
+		return
+	}
+	if !RequireMethod(w, r, http.MethodGet) {
+		return
+	}
+	if !s.requireAdmin(w, r) {
+		return
+	}
+	if !validBackupKey(key) {
+		WriteError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	data, _, err := s.app.Storage.FileStore().GetFile(r.Context(), backupCategory, key)
+	if err != nil {
+		WriteError(w, http.StatusNotFound, fmt.Sprintf("Backup not found: %v", err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", key))
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+// handleAdminBackupRestore handles POST /api/admin/backups/restore. A JSON
+// body {"key": ...} restores an archive from the FileStore; a gzip body is
+// read as an uploaded archive.
+func (s *Server) handleAdminBackupRestore(w http.ResponseWriter, r *http.Request) {
+	if !RequireMethod(w, r, http.MethodPost) {
+		return
+	}
+	if !s.requireAdmin(w, r) {
+		return
+	}
+	if r.Body == nil {
+		WriteError(w, http.StatusBadRequest, "Request body is required")
+		return
+	}
+	ctx := r.Context()
+
+	body := bufio.NewReader(http.MaxBytesReader(w, r.Body, maxRestoreUploadBytes))
+	var archive io.Reader = body
+	if magic, _ := body.Peek(2); !bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		var req struct {
+			Key string `json:"key"`
+		}
+		if err := json.NewDecoder(io.LimitReader(body, 1<<20)).Decode(&req); err != nil {
+			WriteError(w, http.StatusBadRequest, "Expected a gzip archive or JSON {\"key\": ...}: "+err.Error())
+			return
+		}
+		if !validBackupKey(req.Key) {
+			WriteError(w, http.StatusBadRequest, "key must name a backup created by admin_backup_data")
+			return
+		}
+		data, _, err := s.app.Storage.FileStore().GetFile(ctx, backupCategory, req.Key)
+		if err != nil {
+			WriteError(w, http.StatusNotFound, fmt.Sprintf("Backup not found: %v", err))
+			return
+		}
+		archive = bytes.NewReader(data)
+	}
+
+	manifest, err := s.app.Storage.Restore(ctx, archive)
+	if err != nil {
+		status := http.StatusBadRequest
+		if manifest != nil {
+			// The archive was valid but the write failed; nothing was restored
+			status = http.StatusInternalServerError
+		}
+		WriteJSON(w, status, map[string]interface{}{
+			"error":    "Restore failed: " + err.Error(),
+			"restored": manifest,
+		})
+		return
+	}
+	WriteJSON(w, http.StatusOK, manifest)
+}
+
+func validBackupKey(key string) bool {
+	return strings.HasPrefix(key, backupKeyPrefix) && !strings.ContainsAny(key, `/\`) && !strings.Contains(key, "..")
+}
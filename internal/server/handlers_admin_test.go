@@ -1,14 +1,22 @@
 package server
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/interfaces"
 	"github.com/bobmcallan/vire/internal/models"
+	"github.com/bobmcallan/vire/internal/storage/archive"
+	"github.com/bobmcallan/vire/internal/storage/blob"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -588,3 +596,206 @@ func TestRouteAdminUsers_UnknownSubpath(t *testing.T) {
 
 	assert.Equal(t, http.StatusNotFound, rec.Code)
 }
+
+func TestHandleAdminAPIKey_RequiresAdmin(t *testing.T) {
+	srv := newTestServerWithStorage(t)
+	createTestUser(t, srv, "user1", "user1@x.com", "pass", "user")
+
+	body := jsonBody(t, map[string]string{"key": "new-key"})
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/api-keys/eodhd_api_key", body)
+	req = setAdminContext(t, req, "user1", models.RoleUser)
+	rec := httptest.NewRecorder()
+	srv.handleAdminAPIKey(rec, req)
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func TestHandleAdminAPIKey_UnknownKeyRejected(t *testing.T) {
+	srv := newTestServerWithStorage(t)
+	createTestUser(t, srv, "admin1", "admin1@x.com", "pass", "admin")
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/admin/api-keys/navexa_api_key", nil)
+	req = setAdminContext(t, req, "admin1", models.RoleAdmin)
+	rec := httptest.NewRecorder()
+	srv.handleAdminAPIKey(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "unknown API key")
+}
+
+func TestHandleAdminAPIKey_MethodNotAllowed(t *testing.T) {
+	srv := newTestServerWithStorage(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/api-keys/eodhd_api_key", nil)
+	rec := httptest.NewRecorder()
+	srv.handleAdminAPIKey(rec, req)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+// --- Backup / restore handler tests ---
+
+// backupTestStorage archives an in-memory set of strategy records through
+// the real archive format and keeps backups in a filesystem FileStore.
+type backupTestStorage struct {
+	*oauthTestStorageManager
+	files      interfaces.FileStore
+	strategies map[string]string
+}
+
+func (m *backupTestStorage) FileStore() interfaces.FileStore { return m.files }
+
+func (m *backupTestStorage) Backup(_ context.Context, w io.Writer) (*models.BackupManifest, error) {
+	created := time.Now().UTC()
+	aw, err := archive.NewWriter(w, archive.Header{SchemaVersion: common.SchemaVersion, CreatedAt: created})
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range m.strategies {
+		if err := aw.Write("user_data", &models.UserRecord{Subject: "strategy", Key: key, Value: value}); err != nil {
+			return nil, err
+		}
+	}
+	if err := aw.Close(); err != nil {
+		return nil, err
+	}
+	return &models.BackupManifest{SchemaVersion: common.SchemaVersion, CreatedAt: created, Records: aw.Counts()}, nil
+}
+
+func (m *backupTestStorage) Restore(_ context.Context, r io.Reader) (*models.BackupManifest, error) {
+	ar, err := archive.NewReader(r, common.SchemaVersion)
+	if err != nil {
+		return nil, err
+	}
+	manifest := &models.BackupManifest{SchemaVersion: ar.Header.SchemaVersion, Records: map[string]int{}}
+	restored := map[string]string{}
+	for {
+		table, raw, err := ar.Next()
+		if errors.Is(err, io.EOF) {
+			m.strategies = restored
+			return manifest, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		var rec models.UserRecord
+		if err := json.Unmarshal(raw, &rec); err != nil {
+			return nil, err
+		}
+		restored[rec.Key] = rec.Value
+		manifest.Records[table]++
+	}
+}
+
+func newBackupTestServer(t *testing.T) (*Server, *backupTestStorage) {
+	t.Helper()
+	srv := newOAuthTestServer(t)
+	files, err := blob.NewFileSystemStore(t.TempDir(), srv.logger)
+	require.NoError(t, err)
+	store := &backupTestStorage{
+		oauthTestStorageManager: srv.app.Storage.(*oauthTestStorageManager),
+		files:                   files,
+		strategies:              map[string]string{"SMSF": `{"risk":"moderate"}`},
+	}
+	srv.app.Storage = store
+	return srv, store
+}
+
+func TestAdminBackup_CreateDownloadRestore(t *testing.T) {
+	srv, store := newBackupTestServer(t)
+
+	req := setAdminContext(t, httptest.NewRequest(http.MethodPost, "/api/admin/backups", nil), "admin1", models.RoleAdmin)
+	rec := httptest.NewRecorder()
+	srv.handleAdminBackupCreate(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var created struct {
+		Key          string                `json:"key"`
+		DownloadPath string                `json:"download_path"`
+		Manifest     models.BackupManifest `json:"manifest"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.True(t, strings.HasPrefix(created.Key, "vire-backup-"), created.Key)
+	assert.Equal(t, 1, created.Manifest.Records["user_data"])
+
+	// Download the archive
+	req = setAdminContext(t, httptest.NewRequest(http.MethodGet, created.DownloadPath, nil), "admin1", models.RoleAdmin)
+	rec = httptest.NewRecorder()
+	srv.routeAdminBackups(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/gzip", rec.Header().Get("Content-Type"))
+	archiveBytes := rec.Body.Bytes()
+
+	// Restore by key after the data is lost
+	store.strategies = map[string]string{}
+	req = setAdminContext(t, httptest.NewRequest(http.MethodPost, "/api/admin/backups/restore", jsonBody(t, map[string]string{"key": created.Key})), "admin1", models.RoleAdmin)
+	rec = httptest.NewRecorder()
+	srv.routeAdminBackups(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, `{"risk":"moderate"}`, store.strategies["SMSF"])
+
+	// Restore from an uploaded archive
+	store.strategies = map[string]string{}
+	req = setAdminContext(t, httptest.NewRequest(http.MethodPost, "/api/admin/backups/restore", bytes.NewReader(archiveBytes)), "admin1", models.RoleAdmin)
+	rec = httptest.NewRecorder()
+	srv.routeAdminBackups(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, `{"risk":"moderate"}`, store.strategies["SMSF"])
+}
+
+func TestAdminBackup_ExportStreamsArchive(t *testing.T) {
+	srv, store := newBackupTestServer(t)
+
+	req := setAdminContext(t, httptest.NewRequest(http.MethodGet, "/api/admin/backups/export", nil), "admin1", models.RoleAdmin)
+	rec := httptest.NewRecorder()
+	srv.routeAdminBackups(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "application/gzip", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Header().Get("Content-Disposition"), "vire-backup-")
+
+	// The streamed archive restores, replacing records created since
+	store.strategies = map[string]string{"Later": `{}`}
+	req = setAdminContext(t, httptest.NewRequest(http.MethodPost, "/api/admin/backups/restore", bytes.NewReader(rec.Body.Bytes())), "admin1", models.RoleAdmin)
+	rec = httptest.NewRecorder()
+	srv.routeAdminBackups(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, map[string]string{"SMSF": `{"risk":"moderate"}`}, store.strategies)
+
+	req = setAdminContext(t, httptest.NewRequest(http.MethodGet, "/api/admin/backups/export", nil), "u1", models.RoleUser)
+	rec = httptest.NewRecorder()
+	srv.routeAdminBackups(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func TestAdminBackup_RestoreRejections(t *testing.T) {
+	srv, store := newBackupTestServer(t)
+
+	var newer bytes.Buffer
+	aw, err := archive.NewWriter(&newer, archive.Header{SchemaVersion: "9999"})
+	require.NoError(t, err)
+	require.NoError(t, aw.Write("user_data", &models.UserRecord{Subject: "strategy", Key: "SMSF", Value: "overwritten"}))
+	require.NoError(t, aw.Close())
+
+	tests := []struct {
+		name     string
+		body     io.Reader
+		role     string
+		wantCode int
+		wantBody string
+	}{
+		{"newer schema", &newer, models.RoleAdmin, http.StatusBadRequest, "newer than this binary"},
+		{"path traversal key", jsonBody(t, map[string]string{"key": "../../etc/passwd"}), models.RoleAdmin, http.StatusBadRequest, "key must name a backup"},
+		{"unknown key", jsonBody(t, map[string]string{"key": "vire-backup-missing.jsonl.gz"}), models.RoleAdmin, http.StatusNotFound, "Backup not found"},
+		{"non-admin", jsonBody(t, map[string]string{"key": "vire-backup-x.jsonl.gz"}), models.RoleUser, http.StatusForbidden, "Admin access required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := setAdminContext(t, httptest.NewRequest(http.MethodPost, "/api/admin/backups/restore", tt.body), "u1", tt.role)
+			rec := httptest.NewRecorder()
+			srv.routeAdminBackups(rec, req)
+			assert.Equal(t, tt.wantCode, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.wantBody)
+		})
+	}
+	assert.Equal(t, `{"risk":"moderate"}`, store.strategies["SMSF"], "rejected restores must not write")
+}
@@ -0,0 +1,135 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bobmcallan/vire/internal/app"
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/interfaces"
+	"github.com/bobmcallan/vire/internal/models"
+	"github.com/bobmcallan/vire/internal/services/jobmanager"
+)
+
+// stubStockMarketService serves canned stock data; only the methods
+// handleMarketStocks calls are implemented.
+type stubStockMarketService struct {
+	interfaces.MarketService
+	coreCalls int
+}
+
+func (m *stubStockMarketService) CollectCoreMarketData(_ context.Context, _ []string, _ bool) error {
+	m.coreCalls++
+	return nil
+}
+
+func (m *stubStockMarketService) GetStockData(_ context.Context, ticker string, _ interfaces.StockDataInclude) (*models.StockData, error) {
+	return &models.StockData{Ticker: ticker}, nil
+}
+
+func TestHandleMarketStocks_EnvelopeStable(t *testing.T) {
+	logger := common.NewLoggerFromConfig(common.LoggingConfig{Level: "disabled"})
+
+	newServer := func(withJobs bool) *Server {
+		market := &stubStockMarketService{}
+		a := &app.App{Config: common.NewDefaultConfig(), MarketService: market, Logger: logger}
+		if withJobs {
+			store := &mockStatusStorageManager{
+				stockIndex: &mockStatusStockIndexStore{entries: map[string]*models.StockIndexEntry{}},
+				jobQueue:   &mockStatusJobQueueStore{},
+			}
+			a.JobManager = jobmanager.NewJobManager(market, nil, store, common.NewLogger("error"), common.JobManagerConfig{})
+		}
+		return &Server{app: a, logger: logger}
+	}
+
+	tests := []struct {
+		name         string
+		withJobs     bool
+		query        string
+		wantJobs     int
+		wantAdvisory bool
+	}{
+		{"plain request", true, "", 0, false},
+		{"force refresh with job manager", true, "?force_refresh=true", 3, true},
+		{"force refresh without job manager", false, "?force_refresh=true", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newServer(tt.withJobs)
+			req := httptest.NewRequest(http.MethodGet, "/api/market/stocks/BHP.AU"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			srv.handleMarketStocks(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, body %s", rec.Code, rec.Body.String())
+			}
+			var body map[string]json.RawMessage
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if len(body) != 3 {
+				t.Errorf("keys = %d, want exactly data, advisory, background_jobs: %s", len(body), rec.Body.String())
+			}
+
+			var data models.StockData
+			if err := json.Unmarshal(body["data"], &data); err != nil || data.Ticker != "BHP.AU" {
+				t.Errorf("data = %s, want StockData for BHP.AU", body["data"])
+			}
+			var jobs int
+			if err := json.Unmarshal(body["background_jobs"], &jobs); err != nil || jobs != tt.wantJobs {
+				t.Errorf("background_jobs = %s, want %d", body["background_jobs"], tt.wantJobs)
+			}
+			advisory, ok := body["advisory"]
+			if !ok {
+				t.Fatal("advisory key missing")
+			}
+			if isNull := string(advisory) == "null"; isNull == tt.wantAdvisory {
+				t.Errorf("advisory = %s, want present=%v", advisory, tt.wantAdvisory)
+			}
+		})
+	}
+}
+
+// stubBacktestSignalService returns err from BacktestThresholds.
+type stubBacktestSignalService struct {
+	interfaces.SignalService
+	err error
+}
+
+func (m *stubBacktestSignalService) BacktestThresholds(_ context.Context, ticker string, horizon int) (*models.SignalBacktest, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &models.SignalBacktest{Ticker: ticker, HorizonDays: horizon}, nil
+}
+
+func TestHandleSignalBacktest_StatusByError(t *testing.T) {
+	logger := common.NewLoggerFromConfig(common.LoggingConfig{Level: "disabled"})
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"ok", nil, http.StatusOK},
+		{"no market data", fmt.Errorf("market data unavailable for BHP.AU: %w", interfaces.ErrMarketDataNotFound), http.StatusNotFound},
+		{"storage failure", errors.New("connection refused"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &Server{app: &app.App{SignalService: &stubBacktestSignalService{err: tt.err}, Logger: logger}, logger: logger}
+			req := httptest.NewRequest(http.MethodGet, "/api/market/stocks/BHP.AU/signal-backtest", nil)
+			rec := httptest.NewRecorder()
+			srv.handleSignalBacktest(rec, req, "BHP.AU")
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body.String())
+			}
+		})
+	}
+}
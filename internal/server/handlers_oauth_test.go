@@ -6,6 +6,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -28,11 +29,12 @@ import (
 
 // memOAuthStore is a minimal in-memory OAuthStore for tests.
 type memOAuthStore struct {
-	mu       sync.Mutex
-	clients  map[string]*models.OAuthClient
-	codes    map[string]*models.OAuthCode
-	tokens   map[string]*models.OAuthRefreshToken
-	sessions map[string]*models.OAuthSession
+	mu           sync.Mutex
+	clients      map[string]*models.OAuthClient
+	codes        map[string]*models.OAuthCode
+	tokens       map[string]*models.OAuthRefreshToken
+	sessions     map[string]*models.OAuthSession
+	getClientErr error // returned by every GetClient when set
 }
 
 func newMemOAuthStore() *memOAuthStore {
@@ -53,9 +55,12 @@ func (s *memOAuthStore) SaveClient(_ context.Context, c *models.OAuthClient) err
 func (s *memOAuthStore) GetClient(_ context.Context, id string) (*models.OAuthClient, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	if s.getClientErr != nil {
+		return nil, s.getClientErr
+	}
 	c, ok := s.clients[id]
 	if !ok {
-		return nil, fmt.Errorf("not found")
+		return nil, fmt.Errorf("%w: %s", interfaces.ErrOAuthClientNotFound, id)
 	}
 	return c, nil
 }
@@ -278,6 +283,23 @@ func (s *memInternalStore) SetSystemKV(_ context.Context, key, value string) err
 	s.sysKV[key] = value
 	return nil
 }
+func (s *memInternalStore) DeleteSystemKV(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sysKV, key)
+	return nil
+}
+func (s *memInternalStore) ListSystemKV(_ context.Context, prefix string) (map[string]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries := make(map[string]string)
+	for k, v := range s.sysKV {
+		if strings.HasPrefix(k, prefix) {
+			entries[k] = v
+		}
+	}
+	return entries, nil
+}
 func (s *memInternalStore) Close() error { return nil }
 
 var _ interfaces.InternalStore = (*memInternalStore)(nil)
@@ -305,7 +327,13 @@ func (m *oauthTestStorageManager) PurgeDerivedData(_ context.Context) (map[strin
 	return nil, nil
 }
 func (m *oauthTestStorageManager) PurgeReports(_ context.Context) (int, error) { return 0, nil }
-func (m *oauthTestStorageManager) Close() error                                { return nil }
+func (m *oauthTestStorageManager) Backup(_ context.Context, _ io.Writer) (*models.BackupManifest, error) {
+	return nil, nil
+}
+func (m *oauthTestStorageManager) Restore(_ context.Context, _ io.Reader) (*models.BackupManifest, error) {
+	return nil, nil
+}
+func (m *oauthTestStorageManager) Close() error { return nil }
 
 var _ interfaces.StorageManager = (*oauthTestStorageManager)(nil)
 
@@ -0,0 +1,162 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bobmcallan/vire/internal/app"
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/interfaces"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+type mockReportService struct {
+	report *models.PortfolioReport
+}
+
+func (m *mockReportService) GetReport(_ context.Context, _ string) (*models.PortfolioReport, error) {
+	if m.report == nil {
+		return nil, fmt.Errorf("not found")
+	}
+	return m.report, nil
+}
+
+func (m *mockReportService) GenerateReport(_ context.Context, _ string, _ interfaces.ReportOptions) (*models.PortfolioReport, error) {
+	return m.report, nil
+}
+
+func (m *mockReportService) GenerateTickerReport(_ context.Context, _, _ string) (*models.PortfolioReport, error) {
+	return m.report, nil
+}
+
+func (m *mockReportService) GeneratePDF(_ context.Context, _, _ string) ([]byte, error) {
+	return nil, nil
+}
+
+func (m *mockReportService) SearchReports(_ context.Context, _ string, _ interfaces.ReportSearchOptions) ([]models.ReportSearchResult, error) {
+	return nil, nil
+}
+
+func (m *mockReportService) FormatAlertsMarkdown(_ context.Context, _ string) (string, error) {
+	return "", nil
+}
+
+func (m *mockReportService) ExportTradesForImport(_ context.Context, _, _ string) (*models.TradeImportExport, error) {
+	return nil, nil
+}
+
+type tickerPage struct {
+	Items []struct {
+		Ticker string `json:"ticker"`
+	} `json:"items"`
+	Total      int    `json:"total"`
+	NextCursor string `json:"next_cursor"`
+}
+
+func getTickerPage(t *testing.T, srv *Server, query string) tickerPage {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/api/portfolios/SMSF/tickers"+query, nil)
+	rec := httptest.NewRecorder()
+	srv.handlePortfolioTickers(rec, req, "SMSF")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET tickers%s: status %d: %s", query, rec.Code, rec.Body.String())
+	}
+	var page tickerPage
+	if err := json.NewDecoder(rec.Body).Decode(&page); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	return page
+}
+
+func tickersOf(page tickerPage) []string {
+	out := make([]string, len(page.Items))
+	for i, it := range page.Items {
+		out[i] = it.Ticker
+	}
+	return out
+}
+
+func newPaginationTestServer() *Server {
+	report := &models.PortfolioReport{}
+	for _, ticker := range []string{"WES.AU", "BHP.AU", "NAB.AU", "CBA.AU", "ANZ.AU"} {
+		report.TickerReports = append(report.TickerReports, models.TickerReport{Ticker: ticker})
+	}
+	logger := common.NewSilentLogger()
+	return &Server{app: &app.App{Logger: logger, ReportService: &mockReportService{report: report}}, logger: logger}
+}
+
+func TestPortfolioTickers_Pagination(t *testing.T) {
+	srv := newPaginationTestServer()
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+		next  string
+	}{
+		{"first page", "?limit=2", []string{"ANZ.AU", "BHP.AU"}, "2"},
+		{"middle page via cursor", "?limit=2&cursor=2", []string{"CBA.AU", "NAB.AU"}, "4"},
+		{"last partial page", "?limit=2&offset=4", []string{"WES.AU"}, ""},
+		{"out-of-range offset", "?limit=2&offset=10", []string{}, ""},
+		{"default page size", "", []string{"ANZ.AU", "BHP.AU", "CBA.AU", "NAB.AU", "WES.AU"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := getTickerPage(t, srv, tt.query)
+			got := tickersOf(page)
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("items = %v, want %v", got, tt.want)
+			}
+			if page.Total != 5 {
+				t.Errorf("total = %d, want 5", page.Total)
+			}
+			if page.NextCursor != tt.next {
+				t.Errorf("next_cursor = %q, want %q", page.NextCursor, tt.next)
+			}
+		})
+	}
+}
+
+func TestPortfolioTickers_InvalidPageParams(t *testing.T) {
+	srv := newPaginationTestServer()
+	for _, query := range []string{"?limit=0", "?limit=abc", "?offset=-1", "?cursor=xyz"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/portfolios/SMSF/tickers"+query, nil)
+		rec := httptest.NewRecorder()
+		srv.handlePortfolioTickers(rec, req, "SMSF")
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status %d, want 400", query, rec.Code)
+		}
+	}
+}
+
+func TestParsePage_CapsLimit(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/reports?limit=5000", nil)
+	page, errMsg := ParsePage(req)
+	if errMsg != "" {
+		t.Fatalf("ParsePage: %s", errMsg)
+	}
+	if page.Limit != maxPageSize || page.Offset != 0 {
+		t.Errorf("page = %+v, want limit %d offset 0", page, maxPageSize)
+	}
+}
+
+func TestPage_Next(t *testing.T) {
+	for _, tt := range []struct {
+		page     Page
+		n, total int
+		want     string
+	}{
+		{Page{Limit: 2}, 2, 5, "2"},
+		{Page{Limit: 2, Offset: 2}, 2, 5, "4"},
+		{Page{Limit: 2, Offset: 4}, 1, 5, ""},
+		{Page{Limit: 2, Offset: 9}, 0, 5, ""},
+	} {
+		if got := tt.page.Next(tt.n, tt.total); got != tt.want {
+			t.Errorf("%+v.Next(%d, %d) = %q, want %q", tt.page, tt.n, tt.total, got, tt.want)
+		}
+	}
+}
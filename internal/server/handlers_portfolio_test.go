@@ -22,28 +22,58 @@ type mockPortfolioService struct {
 	getPortfolio           func(ctx context.Context, name string) (*models.Portfolio, error)
 	syncPortfolio          func(ctx context.Context, name string, force bool) (*models.Portfolio, error)
 	getPortfolioIndicators func(ctx context.Context, name string) (*models.PortfolioIndicators, error)
+	reviewPortfolio        func(ctx context.Context, name string, options interfaces.ReviewOptions) (*models.PortfolioReview, error)
+	timeWeightedReturn     func(ctx context.Context, name, from, to string) (float64, error)
+	summaryStream          interfaces.GeminiClient // AI summary source for StreamPortfolioReview
 }
 
 func (m *mockPortfolioService) GetPortfolio(ctx context.Context, name string) (*models.Portfolio, error) {
+	if m.getPortfolio == nil {
+		return nil, interfaces.ErrPortfolioNotFound
+	}
 	return m.getPortfolio(ctx, name)
 }
 
 func (m *mockPortfolioService) SyncPortfolio(ctx context.Context, name string, force bool) (*models.Portfolio, error) {
 	return m.syncPortfolio(ctx, name, force)
 }
+func (m *mockPortfolioService) EnsureSynced(ctx context.Context, name string, _ time.Duration) (*models.Portfolio, error) {
+	return m.syncPortfolio(ctx, name, false)
+}
 
 func (m *mockPortfolioService) ListPortfolios(ctx context.Context) ([]string, error) {
 	return nil, nil
 }
 
 func (m *mockPortfolioService) ReviewPortfolio(ctx context.Context, name string, options interfaces.ReviewOptions) (*models.PortfolioReview, error) {
+	if m.reviewPortfolio != nil {
+		return m.reviewPortfolio(ctx, name, options)
+	}
 	return nil, nil
 }
 
+func (m *mockPortfolioService) StreamPortfolioReview(ctx context.Context, name string, options interfaces.ReviewOptions) (*models.PortfolioReview, <-chan string, <-chan error, error) {
+	options.SkipSummary = true
+	review, err := m.ReviewPortfolio(ctx, name, options)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	chunks, errs := m.summaryStream.AnalyzeStream(ctx, "")
+	return review, chunks, errs, nil
+}
+
 func (m *mockPortfolioService) ReviewWatchlist(ctx context.Context, name string, options interfaces.ReviewOptions) (*models.WatchlistReview, error) {
 	return nil, nil
 }
 
+func (m *mockPortfolioService) ListActiveAlerts(ctx context.Context, name string) ([]models.AlertState, error) {
+	return nil, nil
+}
+
+func (m *mockPortfolioService) AcknowledgeAlert(ctx context.Context, name, ticker, signal string) (*models.AlertState, error) {
+	return nil, nil
+}
+
 func (m *mockPortfolioService) GetPortfolioSnapshot(ctx context.Context, name string, asOf time.Time) (*models.PortfolioSnapshot, error) {
 	return nil, nil
 }
@@ -56,6 +86,13 @@ func (m *mockPortfolioService) GetDailyGrowth(ctx context.Context, name string,
 	return nil, nil
 }
 
+func (m *mockPortfolioService) GetGrowthChart(ctx context.Context, name string, opts interfaces.GrowthOptions) ([]byte, error) {
+	return nil, nil
+}
+
+func (m *mockPortfolioService) FXDivisor(_ context.Context, _ *models.Portfolio, _ string) float64 {
+	return 1
+}
 func (m *mockPortfolioService) GetStockTimeline(_ context.Context, _, _ string, _, _ time.Time) ([]models.StockTimelinePoint, error) {
 	return nil, nil
 }
@@ -65,6 +102,49 @@ func (m *mockPortfolioService) GetPortfolioIndicators(ctx context.Context, name
 	}
 	return nil, nil
 }
+func (m *mockPortfolioService) SnapshotPortfolio(_ context.Context, _ string) (*models.DailyPortfolioSnapshot, error) {
+	return nil, nil
+}
+func (m *mockPortfolioService) GetPortfolioHistory(_ context.Context, _ string, _, _ time.Time) ([]models.DailyPortfolioSnapshot, error) {
+	return nil, nil
+}
+
+func (m *mockPortfolioService) GetPortfolioTimeline(_ context.Context, _ string, _ interfaces.GrowthOptions) ([]models.GrowthDataPoint, error) {
+	return nil, nil
+}
+func (m *mockPortfolioService) TimeWeightedReturn(ctx context.Context, name, from, to string) (float64, error) {
+	if m.timeWeightedReturn != nil {
+		return m.timeWeightedReturn(ctx, name, from, to)
+	}
+	return 0, nil
+}
+func (m *mockPortfolioService) GetDataCompleteness(_ context.Context, _ string) (*models.DataCompleteness, error) {
+	return nil, nil
+}
+func (m *mockPortfolioService) CalculateCGT(_ context.Context, _, _ string) (*models.CGTReport, error) {
+	return nil, nil
+}
+func (m *mockPortfolioService) RealizedGainTimeline(_ context.Context, _ string) (*models.RealizedGainTimeline, error) {
+	return nil, nil
+}
+func (m *mockPortfolioService) SyncHolding(_ context.Context, _, _ string) (*models.Holding, error) {
+	return nil, nil
+}
+func (m *mockPortfolioService) SimulateTrade(_ context.Context, _ string, _ models.SimulatedTrade) (*models.TradeSimulation, error) {
+	return nil, nil
+}
+func (m *mockPortfolioService) SectorAllocation(_ context.Context, _ string) (*models.SectorBreakdown, error) {
+	return nil, nil
+}
+func (m *mockPortfolioService) FeeSummary(_ context.Context, _ string) (*models.FeeSummary, error) {
+	return nil, nil
+}
+func (m *mockPortfolioService) RebalanceSuggestions(_ context.Context, _ string) (*models.RebalancePlan, error) {
+	return nil, nil
+}
+func (m *mockPortfolioService) ProjectValue(_ context.Context, _ string, _, _ int) (*models.ProjectionResult, error) {
+	return nil, nil
+}
 func (m *mockPortfolioService) RefreshTodaySnapshot(_ context.Context, _ string) error {
 	return nil
 }
@@ -120,6 +200,14 @@ func (m *mockCashFlowService) ClearLedger(ctx context.Context, portfolioName str
 	}, nil
 }
 
+func (m *mockCashFlowService) SetBrokerBalance(_ context.Context, _ string, _ models.BrokerBalance) (*models.CashFlowLedger, error) {
+	return nil, nil
+}
+
+func (m *mockCashFlowService) Reconcile(_ context.Context, _ string) (*models.CashReconciliation, error) {
+	return nil, nil
+}
+
 func (m *mockCashFlowService) CalculatePerformance(ctx context.Context, portfolioName string) (*models.CapitalPerformance, error) {
 	if m.calculatePerformance != nil {
 		return m.calculatePerformance(ctx, portfolioName)
@@ -199,6 +287,69 @@ func TestHandlePortfolioGet_NotFound(t *testing.T) {
 	}
 }
 
+func TestHandlePortfolioGet_TypedErrorStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"not found", fmt.Errorf("%w: 'x'", interfaces.ErrPortfolioNotFound), http.StatusNotFound},
+		{"navexa unavailable", fmt.Errorf("%w: no client", interfaces.ErrNavexaUnavailable), http.StatusServiceUnavailable},
+		{"sync in progress", fmt.Errorf("%w: %w", interfaces.ErrSyncInProgress, context.DeadlineExceeded), http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mockPortfolioService{
+				syncPortfolio: func(ctx context.Context, name string, force bool) (*models.Portfolio, error) {
+					return nil, tt.err
+				},
+			}
+			srv := newTestServer(svc)
+			req := httptest.NewRequest(http.MethodGet, "/api/portfolios/x?force_refresh=true", nil)
+			rec := httptest.NewRecorder()
+
+			srv.handlePortfolioGet(rec, req, "x")
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandlePortfolioTWR(t *testing.T) {
+	var gotFrom, gotTo string
+	svc := &mockPortfolioService{
+		timeWeightedReturn: func(ctx context.Context, name, from, to string) (float64, error) {
+			gotFrom, gotTo = from, to
+			return 12.5, nil
+		},
+	}
+	srv := newTestServer(svc)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/portfolios/SMSF/twr?from=2025-01-01&to=2025-06-30", nil)
+	rec := httptest.NewRecorder()
+	srv.handlePortfolioTWR(rec, req, "SMSF")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp["twr_pct"] != 12.5 || gotFrom != "2025-01-01" || gotTo != "2025-06-30" {
+		t.Errorf("twr_pct = %v, from/to = %s/%s; want 12.5 for 2025-01-01..2025-06-30", resp["twr_pct"], gotFrom, gotTo)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/portfolios/SMSF/twr?from=01/01/2025", nil)
+	rec = httptest.NewRecorder()
+	srv.handlePortfolioTWR(rec, req, "SMSF")
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid date: expected status 400, got %d", rec.Code)
+	}
+}
+
 // --- Portal injection validation tests ---
 
 func TestHandlePortfolioSync_MissingUserContext_Returns400(t *testing.T) {
@@ -790,3 +941,130 @@ func TestHandlePortfolioGet_ConcurrentCapitalPerformance(t *testing.T) {
 		}
 	}
 }
+
+// streamingGeminiClient streams fixed chunks, then err (if set).
+type streamingGeminiClient struct {
+	interfaces.GeminiClient
+	chunks []string
+	err    error
+}
+
+func (g *streamingGeminiClient) AnalyzeStream(ctx context.Context, prompt string) (<-chan string, <-chan error) {
+	chunks := make(chan string, len(g.chunks))
+	errs := make(chan error, 1)
+	for _, c := range g.chunks {
+		chunks <- c
+	}
+	if g.err != nil {
+		errs <- g.err
+	}
+	close(chunks)
+	close(errs)
+	return chunks, errs
+}
+
+func TestHandlePortfolioReviewStream_EmitsReviewThenSummaryChunks(t *testing.T) {
+	var gotOptions interfaces.ReviewOptions
+	svc := &mockPortfolioService{
+		reviewPortfolio: func(ctx context.Context, name string, options interfaces.ReviewOptions) (*models.PortfolioReview, error) {
+			gotOptions = options
+			return &models.PortfolioReview{PortfolioName: name}, nil
+		},
+	}
+	svc.summaryStream = &streamingGeminiClient{chunks: []string{"Portfolio ", "looks ", "healthy."}}
+	srv := newTestServer(svc)
+	srv.app.GeminiClient = svc.summaryStream
+
+	req := httptest.NewRequest(http.MethodPost, "/api/portfolios/SMSF/review/stream", strings.NewReader(`{}`))
+	rec := httptest.NewRecorder()
+	srv.handlePortfolioReviewStream(rec, req, "SMSF")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type = %q, want text/event-stream", ct)
+	}
+	if !gotOptions.SkipSummary {
+		t.Error("expected review to skip the blocking summary")
+	}
+
+	body := rec.Body.String()
+	want := []string{
+		"event: review\n",
+		"event: summary\ndata: {\"text\":\"Portfolio \"}\n\n",
+		"event: summary\ndata: {\"text\":\"looks \"}\n\n",
+		"event: summary\ndata: {\"text\":\"healthy.\"}\n\n",
+		"event: done\n",
+	}
+	pos := 0
+	for _, w := range want {
+		i := strings.Index(body[pos:], w)
+		if i < 0 {
+			t.Fatalf("missing %q in order; body:\n%s", w, body)
+		}
+		pos += i + len(w)
+	}
+}
+
+func TestHandlePortfolioReviewStream_ErrorEvent(t *testing.T) {
+	svc := &mockPortfolioService{
+		reviewPortfolio: func(ctx context.Context, name string, options interfaces.ReviewOptions) (*models.PortfolioReview, error) {
+			return &models.PortfolioReview{PortfolioName: name}, nil
+		},
+	}
+	svc.summaryStream = &streamingGeminiClient{chunks: []string{"Partial"}, err: errors.New("stream broke")}
+	srv := newTestServer(svc)
+	srv.app.GeminiClient = svc.summaryStream
+
+	rec := httptest.NewRecorder()
+	srv.handlePortfolioReviewStream(rec, httptest.NewRequest(http.MethodPost, "/api/portfolios/SMSF/review/stream", nil), "SMSF")
+
+	body := rec.Body.String()
+	if !strings.Contains(body, "event: error\n") || !strings.Contains(body, "stream broke") {
+		t.Errorf("expected error event, got:\n%s", body)
+	}
+	if strings.Contains(body, "event: done") {
+		t.Errorf("unexpected done event after error:\n%s", body)
+	}
+}
+
+func TestHandlePortfolioReviewStream_NoGemini(t *testing.T) {
+	srv := newTestServer(&mockPortfolioService{})
+	rec := httptest.NewRecorder()
+	srv.handlePortfolioReviewStream(rec, httptest.NewRequest(http.MethodPost, "/api/portfolios/SMSF/review/stream", nil), "SMSF")
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503, got %d", rec.Code)
+	}
+}
+
+func TestHandleHoldingAnnotationTags_Validation(t *testing.T) {
+	srv := newTestServer(&mockPortfolioService{})
+	tooMany := make([]string, maxHoldingTags+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("t%d", i)
+	}
+
+	tests := []struct {
+		name   string
+		ticker string
+		body   any
+	}{
+		{"ambiguous ticker", "BHP", map[string]any{"tags": []string{"core"}}},
+		{"too many tags", "BHP.AU", map[string]any{"tags": tooMany}},
+		{"tag too long", "BHP.AU", map[string]any{"tags": []string{strings.Repeat("x", maxHoldingTagLength+1)}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(tt.body)
+			req := httptest.NewRequest(http.MethodPut, "/api/portfolios/SMSF/stock/"+tt.ticker+"/tags", strings.NewReader(string(body)))
+			rec := httptest.NewRecorder()
+
+			srv.handleHoldingAnnotationTags(rec, req, "SMSF", tt.ticker)
+
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want 400", rec.Code)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"sync"
@@ -82,6 +83,9 @@ func (m *mockStatusJobQueueStore) CountPending(_ context.Context) (int, error) {
 func (m *mockStatusJobQueueStore) HasPendingJob(_ context.Context, _, _ string) (bool, error) {
 	return false, nil
 }
+func (m *mockStatusJobQueueStore) HasActiveJob(_ context.Context, _, _ string) (bool, error) {
+	return false, nil
+}
 func (m *mockStatusJobQueueStore) PurgeCompleted(_ context.Context, _ time.Time) (int, error) {
 	return 0, nil
 }
@@ -89,6 +93,12 @@ func (m *mockStatusJobQueueStore) CancelByTicker(_ context.Context, _ string) (i
 	return 0, nil
 }
 func (m *mockStatusJobQueueStore) ResetRunningJobs(_ context.Context) (int, error) { return 0, nil }
+func (m *mockStatusJobQueueStore) ListDeadLetter(_ context.Context, _ int) ([]*models.Job, error) {
+	return nil, nil
+}
+func (m *mockStatusJobQueueStore) RequeueDeadLetter(_ context.Context, _ string) (*models.Job, error) {
+	return nil, nil
+}
 
 type mockStatusTimelineStore struct {
 	snapshots []models.TimelineSnapshot
@@ -136,7 +146,13 @@ func (m *mockStatusStorageManager) PurgeDerivedData(_ context.Context) (map[stri
 	return nil, nil
 }
 func (m *mockStatusStorageManager) PurgeReports(_ context.Context) (int, error) { return 0, nil }
-func (m *mockStatusStorageManager) Close() error                                { return nil }
+func (m *mockStatusStorageManager) Backup(_ context.Context, _ io.Writer) (*models.BackupManifest, error) {
+	return nil, nil
+}
+func (m *mockStatusStorageManager) Restore(_ context.Context, _ io.Reader) (*models.BackupManifest, error) {
+	return nil, nil
+}
+func (m *mockStatusStorageManager) Close() error { return nil }
 
 func newStatusTestServer(
 	portfolioSvc interfaces.PortfolioService,
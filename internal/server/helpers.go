@@ -2,8 +2,13 @@ package server
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
+
+	"github.com/bobmcallan/vire/internal/interfaces"
 )
 
 // ErrorResponse is the standard error format for REST API responses.
@@ -24,11 +29,49 @@ func WriteError(w http.ResponseWriter, statusCode int, message string) {
 	WriteJSON(w, statusCode, ErrorResponse{Error: message})
 }
 
+// portfolioErrorStatus maps the typed PortfolioService errors to an HTTP
+// status, returning fallback for anything else.
+func portfolioErrorStatus(err error, fallback int) int {
+	switch {
+	case errors.Is(err, interfaces.ErrPortfolioNotFound), errors.Is(err, interfaces.ErrHoldingNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, interfaces.ErrSyncInProgress):
+		return http.StatusConflict
+	case errors.Is(err, interfaces.ErrNavexaUnavailable):
+		return http.StatusServiceUnavailable
+	}
+	return fallback
+}
+
+// writePortfolioLoadError reports a failed GetPortfolio or forced sync.
+// Untyped errors keep the historical 404.
+func writePortfolioLoadError(w http.ResponseWriter, err error) {
+	status := portfolioErrorStatus(err, http.StatusNotFound)
+	if status == http.StatusNotFound {
+		WriteError(w, status, fmt.Sprintf("Portfolio not found: %v", err))
+		return
+	}
+	WriteError(w, status, fmt.Sprintf("Portfolio unavailable: %v", err))
+}
+
 // WriteErrorWithCode writes a JSON error response with an error code.
 func WriteErrorWithCode(w http.ResponseWriter, statusCode int, message, code string) {
 	WriteJSON(w, statusCode, ErrorResponse{Error: message, Code: code})
 }
 
+// WriteSSE writes one server-sent event with a JSON data payload and flushes
+// it to the client.
+func WriteSSE(w http.ResponseWriter, event string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload); err != nil {
+		return err
+	}
+	return http.NewResponseController(w).Flush()
+}
+
 // RequireMethod validates the HTTP method and returns true if it matches.
 // If it doesn't match, it writes a 405 response and returns false.
 func RequireMethod(w http.ResponseWriter, r *http.Request, methods ...string) bool {
@@ -107,3 +150,68 @@ func PathParam(r *http.Request, prefix, suffix string) string {
 	}
 	return rest
 }
+
+// Page size bounds for paginated list endpoints.
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+)
+
+// Page is a window over a list, resolved from the limit and offset (or cursor) query params.
+type Page struct {
+	Limit  int
+	Offset int
+}
+
+// ParsePage reads the limit, offset and cursor query params. Limit defaults to 50 and is capped
+// at 200. cursor is the next_cursor of a previous page and takes precedence over offset.
+// A non-empty message is returned for malformed values.
+func ParsePage(r *http.Request) (Page, string) {
+	q := r.URL.Query()
+	p := Page{Limit: defaultPageSize}
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return p, fmt.Sprintf("invalid limit '%s'", v)
+		}
+		p.Limit = n
+	}
+	if p.Limit > maxPageSize {
+		p.Limit = maxPageSize
+	}
+	offset := q.Get("offset")
+	if cursor := q.Get("cursor"); cursor != "" {
+		offset = cursor
+	}
+	if offset != "" {
+		n, err := strconv.Atoi(offset)
+		if err != nil || n < 0 {
+			return p, fmt.Sprintf("invalid offset or cursor '%s'", offset)
+		}
+		p.Offset = n
+	}
+	return p, ""
+}
+
+// Bounds returns the slice bounds of the page within a list of total items, and the cursor of
+// the following page ("" on the last page). An offset past the end gives an empty page.
+func (p Page) Bounds(total int) (start, end int, next string) {
+	start = p.Offset
+	if start > total {
+		start = total
+	}
+	end = start + p.Limit
+	if end >= total {
+		return start, total, ""
+	}
+	return start, end, strconv.Itoa(end)
+}
+
+// Next returns the cursor after a page of n items fetched at p.Offset, or ""
+// when that page reaches total.
+func (p Page) Next(n, total int) string {
+	if end := p.Offset + n; n > 0 && end < total {
+		return strconv.Itoa(end)
+	}
+	return ""
+}
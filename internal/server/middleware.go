@@ -1,6 +1,7 @@
 package server
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -31,13 +32,19 @@ func (rw *responseWriter) Write(b []byte) (int, error) {
 	return n, err
 }
 
+// Unwrap exposes the underlying writer so http.ResponseController can flush
+// streamed responses.
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 // recoveryMiddleware catches panics and returns 500.
 func recoveryMiddleware(logger *common.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			defer func() {
 				if rec := recover(); rec != nil {
-					logger.Error().
+					logger.WithRequestID(r.Context()).Error().
 						Str("panic", fmt.Sprintf("%v", rec)).
 						Str("path", r.URL.Path).
 						Msg("Panic recovered in HTTP handler")
@@ -65,21 +72,43 @@ func corsMiddleware(next http.Handler) http.Handler {
 	})
 }
 
-// correlationIDMiddleware extracts or generates a correlation ID.
+// maxRequestIDLength bounds inbound request IDs so a client cannot inflate
+// every log line for its request.
+const maxRequestIDLength = 128
+
+// correlationIDMiddleware assigns each request an ID: an inbound X-Request-ID
+// (or X-Correlation-ID) is honoured, otherwise a new one is generated. The ID
+// is stored in the request context for common.Logger.WithRequestID and echoed
+// in the X-Request-ID and X-Correlation-ID response headers.
 func correlationIDMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		corrID := r.Header.Get("X-Request-ID")
-		if corrID == "" {
+		if !validRequestID(corrID) {
 			corrID = r.Header.Get("X-Correlation-ID")
 		}
-		if corrID == "" {
+		if !validRequestID(corrID) {
 			corrID = uuid.New().String()[:8]
 		}
+		w.Header().Set("X-Request-ID", corrID)
 		w.Header().Set("X-Correlation-ID", corrID)
-		next.ServeHTTP(w, r)
+		next.ServeHTTP(w, r.WithContext(common.WithRequestID(r.Context(), corrID)))
 	})
 }
 
+// validRequestID accepts non-empty, bounded IDs of printable ASCII without
+// spaces, so an inbound ID is safe to log and echo back.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
 // loggingMiddleware logs HTTP requests.
 func loggingMiddleware(logger *common.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -90,13 +119,13 @@ func loggingMiddleware(logger *common.Logger) func(http.Handler) http.Handler {
 			next.ServeHTTP(rw, r)
 
 			dur := time.Since(start)
-			corrID := w.Header().Get("X-Correlation-ID")
+			reqLogger := logger.WithRequestID(r.Context())
 
-			event := logger.Trace()
+			event := reqLogger.Trace()
 			if rw.statusCode >= 500 {
-				event = logger.Error()
+				event = reqLogger.Error()
 			} else if rw.statusCode >= 400 {
-				event = logger.Info()
+				event = reqLogger.Info()
 			}
 
 			event.
@@ -106,7 +135,6 @@ func loggingMiddleware(logger *common.Logger) func(http.Handler) http.Handler {
 				Int("status", rw.statusCode).
 				Int("bytes", rw.bytesWritten).
 				Dur("duration", dur).
-				Str("correlation_id", corrID).
 				Msg("HTTP request")
 		})
 	}
@@ -208,6 +236,77 @@ func writeBearerChallenge(w http.ResponseWriter, config *common.Config, errorCod
 	WriteError(w, http.StatusUnauthorized, description)
 }
 
+// requireAuthMiddleware rejects requests without a valid bearer token when
+// [server.auth] enabled is set. Public paths, the OAuth and login endpoints
+// needed to obtain a token, and CORS preflights pass through. A token is
+// valid when its JWT verifies and, for tokens issued through the OAuth flow,
+// its client is still registered in the OAuthStore; a failed client lookup
+// other than not-found returns 503 rather than revoking the session. The
+// downstream bearerTokenMiddleware then resolves the user from the token.
+func requireAuthMiddleware(config *common.Config, oauthStore interfaces.OAuthStore) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if !config.Server.Auth.Enabled {
+			return next
+		}
+		publicPaths := config.Server.Auth.GetPublicPaths()
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodOptions || isPublicPath(r.URL.Path, publicPaths) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			authHeader := r.Header.Get("Authorization")
+			if !strings.HasPrefix(authHeader, "Bearer ") {
+				writeBearerChallenge(w, config, "invalid_request", "bearer token required")
+				return
+			}
+			_, claims, err := validateJWT(strings.TrimPrefix(authHeader, "Bearer "), []byte(config.Auth.JWTSecret))
+			if err != nil {
+				writeBearerChallenge(w, config, "invalid_token", "invalid or expired token")
+				return
+			}
+			if clientID, _ := claims["client_id"].(string); clientID != "" && oauthStore != nil {
+				if _, err := oauthStore.GetClient(r.Context(), clientID); errors.Is(err, interfaces.ErrOAuthClientNotFound) {
+					writeBearerChallenge(w, config, "invalid_token", "token client is no longer registered")
+					return
+				} else if err != nil {
+					// A storage outage must not read as a revoked client
+					WriteError(w, http.StatusServiceUnavailable, "failed to verify token client")
+					return
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// authFlowPaths are always public when auth is enforced: they hand out
+// tokens, so without them no client could obtain one. Other /api/auth/
+// endpoints (e.g. password-reset) need a token like any other route.
+var authFlowPaths = []string{
+	"/.well-known/",
+	"/oauth/",
+	"/api/auth/login",
+	"/api/auth/login/",
+	"/api/auth/callback/",
+	"/api/auth/oauth",
+	"/api/auth/validate",
+}
+
+// isPublicPath reports whether path matches an authFlowPaths prefix or a
+// public path entry. Entries ending in "/" match as prefixes.
+func isPublicPath(path string, publicPaths []string) bool {
+	for _, set := range [][]string{authFlowPaths, publicPaths} {
+		for _, p := range set {
+			if path == p || (strings.HasSuffix(p, "/") && strings.HasPrefix(path, p)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // shouldRefreshToken checks if the token should be refreshed (>50% through lifetime).
 // Returns (newClaims, shouldRefresh) where newClaims contains updated iat/exp.
 func shouldRefreshToken(claims jwt.MapClaims, config *common.Config) (jwt.MapClaims, bool) {
@@ -337,13 +436,15 @@ func userContextMiddleware(store interfaces.InternalStore) func(http.Handler) ht
 }
 
 // applyMiddleware wraps a handler with the middleware stack.
-func applyMiddleware(handler http.Handler, logger *common.Logger, config *common.Config, store interfaces.InternalStore) http.Handler {
+func applyMiddleware(handler http.Handler, logger *common.Logger, config *common.Config, store interfaces.InternalStore, oauthStore interfaces.OAuthStore) http.Handler {
 	// Apply in reverse order (last applied = first executed)
 	handler = loggingMiddleware(logger)(handler)
-	handler = correlationIDMiddleware(handler)
 	handler = userContextMiddleware(store)(handler)
 	handler = bearerTokenMiddleware(config, store)(handler)
+	handler = requireAuthMiddleware(config, oauthStore)(handler)
+	handler = rateLimitMiddleware(config)(handler)
 	handler = corsMiddleware(handler)
 	handler = recoveryMiddleware(logger)(handler)
+	handler = correlationIDMiddleware(handler)
 	return handler
 }
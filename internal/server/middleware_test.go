@@ -3,10 +3,13 @@ package server
 import (
 	"bytes"
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/bobmcallan/vire/internal/common"
 	"github.com/bobmcallan/vire/internal/models"
@@ -413,3 +416,273 @@ func TestMiddleware_OnlyUserIDResolvesAllFields(t *testing.T) {
 		t.Errorf("expected 2 portfolios from storage, got %d", len(capturedUC.Portfolios))
 	}
 }
+
+// newAuthEnforcedHandler wraps a mux with the full middleware stack and
+// [server.auth] enabled. Protected handlers report the resolved user ID.
+func newAuthEnforcedHandler(t *testing.T, srv *Server) http.Handler {
+	t.Helper()
+	srv.app.Config.Server.Auth.Enabled = true
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/health", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	mux.HandleFunc("/api/auth/password-reset", srv.handlePasswordReset)
+	mux.HandleFunc("/api/portfolios", func(w http.ResponseWriter, r *http.Request) {
+		if uc := common.UserContextFromContext(r.Context()); uc != nil {
+			w.Write([]byte(uc.UserID))
+		}
+	})
+	return applyMiddleware(mux, srv.app.Logger, srv.app.Config, srv.app.Storage.InternalStore(), srv.app.Storage.OAuthStore())
+}
+
+func TestRequireAuthMiddleware_ValidTokenPasses(t *testing.T) {
+	srv := newOAuthTestServer(t)
+	createOAuthTestUser(t, srv, "auth_user", "a@example.com", "pass")
+	clientID, _ := registerTestOAuthClient(t, srv)
+	token, err := signAccessToken(&models.InternalUser{UserID: "auth_user", Role: models.RoleUser}, clientID, "vire", srv.app.Config)
+	if err != nil {
+		t.Fatalf("signAccessToken: %v", err)
+	}
+	handler := newAuthEnforcedHandler(t, srv)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/portfolios", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK || rec.Body.String() != "auth_user" {
+		t.Errorf("got %d %q, want 200 for auth_user", rec.Code, rec.Body.String())
+	}
+}
+
+func TestRequireAuthMiddleware_Rejections(t *testing.T) {
+	srv := newOAuthTestServer(t)
+	createOAuthTestUser(t, srv, "auth_user", "a@example.com", "pass")
+	user := &models.InternalUser{UserID: "auth_user", Role: models.RoleUser}
+	handler := newAuthEnforcedHandler(t, srv)
+
+	expired := createTestJWT(t, map[string]interface{}{
+		"sub": "auth_user",
+		"iat": time.Now().Add(-2 * time.Hour).Unix(),
+		"exp": time.Now().Add(-1 * time.Hour).Unix(),
+	}, srv.app.Config.Auth.JWTSecret)
+	revokedClient, err := signAccessToken(user, "deleted-client", "vire", srv.app.Config)
+	if err != nil {
+		t.Fatalf("signAccessToken: %v", err)
+	}
+
+	tests := []struct {
+		name, auth, userHeader, wantMsg string
+	}{
+		{"missing token", "", "", "bearer token required"},
+		{"headers only", "", "auth_user", "bearer token required"},
+		{"expired token", "Bearer " + expired, "", "invalid or expired token"},
+		{"unregistered client", "Bearer " + revokedClient, "", "no longer registered"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/portfolios", nil)
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			if tt.userHeader != "" {
+				req.Header.Set("X-Vire-User-ID", tt.userHeader)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusUnauthorized {
+				t.Fatalf("status = %d, want 401", rec.Code)
+			}
+			if !strings.HasPrefix(rec.Header().Get("WWW-Authenticate"), "Bearer") {
+				t.Errorf("WWW-Authenticate = %q, want a Bearer challenge", rec.Header().Get("WWW-Authenticate"))
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantMsg) {
+				t.Errorf("body = %s, want %q", rec.Body.String(), tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestRequireAuthMiddleware_PublicPaths(t *testing.T) {
+	srv := newOAuthTestServer(t)
+	handler := newAuthEnforcedHandler(t, srv)
+
+	for _, path := range []string{"/api/health", "/.well-known/oauth-protected-resource", "/oauth/token",
+		"/api/auth/login", "/api/auth/login/github", "/api/auth/callback/google", "/api/auth/validate"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code == http.StatusUnauthorized {
+			t.Errorf("%s: got 401, want public", path)
+		}
+	}
+}
+
+func TestRequireAuthMiddleware_PasswordResetNeedsToken(t *testing.T) {
+	srv := newOAuthTestServer(t)
+	createOAuthTestUser(t, srv, "victim", "v@example.com", "pass")
+	handler := newAuthEnforcedHandler(t, srv)
+
+	body := jsonBody(t, map[string]string{"username": "victim", "new_password": "taken-over"})
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/password-reset", body)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want 401 without a token", rec.Code)
+	}
+}
+
+func TestRequireAuthMiddleware_ClientLookupFailureIsNotRevocation(t *testing.T) {
+	srv := newOAuthTestServer(t)
+	createOAuthTestUser(t, srv, "auth_user", "a@example.com", "pass")
+	clientID, _ := registerTestOAuthClient(t, srv)
+	token, err := signAccessToken(&models.InternalUser{UserID: "auth_user", Role: models.RoleUser}, clientID, "vire", srv.app.Config)
+	if err != nil {
+		t.Fatalf("signAccessToken: %v", err)
+	}
+	srv.app.Storage.OAuthStore().(*memOAuthStore).getClientErr = errors.New("connection refused")
+	handler := newAuthEnforcedHandler(t, srv)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/portfolios", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503 when the client lookup fails", rec.Code)
+	}
+}
+
+func TestRequireAuthMiddleware_DisabledByDefault(t *testing.T) {
+	srv := newOAuthTestServer(t)
+	handler := applyMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}), srv.app.Logger, srv.app.Config, srv.app.Storage.InternalStore(), srv.app.Storage.OAuthStore())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/portfolios", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200 with auth disabled", rec.Code)
+	}
+}
+
+func TestMetricsEndpoint_GatedByConfig(t *testing.T) {
+	for _, enabled := range []bool{false, true} {
+		srv := newOAuthTestServer(t)
+		srv.app.Config.Server.Metrics.Enabled = enabled
+		mux := http.NewServeMux()
+		srv.registerRoutes(mux)
+
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+		if !enabled {
+			if rec.Code != http.StatusNotFound {
+				t.Errorf("metrics disabled: status %d, want 404", rec.Code)
+			}
+			continue
+		}
+		if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "go_goroutines") {
+			t.Errorf("metrics enabled: status %d, want 200 with exposition output", rec.Code)
+		}
+	}
+}
+
+// syncCapture is a logLevelCapture safe for concurrent requests.
+type syncCapture struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (c *syncCapture) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.buf.Write(p)
+}
+
+func (c *syncCapture) output() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.buf.String()
+}
+
+func TestCorrelationIDMiddleware_ConcurrentRequestsGetDistinctIDs(t *testing.T) {
+	capture := &syncCapture{}
+	logger := common.NewLoggerWithOutput("info", capture)
+
+	// Both requests are held until the other has arrived, so they overlap.
+	var arrived sync.WaitGroup
+	arrived.Add(2)
+	handler := correlationIDMiddleware(loggingMiddleware(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		arrived.Done()
+		arrived.Wait()
+		// Stands in for a service logging with the request context
+		logger.WithRequestID(r.Context()).Info().Msg("downstream work")
+		w.Write([]byte(common.RequestIDFromContext(r.Context())))
+	})))
+
+	var ids [2]string
+	var done sync.WaitGroup
+	for i := range ids {
+		done.Add(1)
+		go func() {
+			defer done.Done()
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/portfolios", nil))
+			ids[i] = rr.Header().Get("X-Request-ID")
+			if body := rr.Body.String(); body != ids[i] {
+				t.Errorf("context request ID %q != response header %q", body, ids[i])
+			}
+		}()
+	}
+	done.Wait()
+
+	if ids[0] == "" || ids[0] == ids[1] {
+		t.Fatalf("request IDs = %q, want two distinct non-empty IDs", ids)
+	}
+	output := capture.output()
+	for _, id := range ids {
+		if !strings.Contains(output, "downstream work correlation_id="+id) {
+			t.Errorf("downstream log for %s missing, got:\n%s", id, output)
+		}
+	}
+}
+
+func TestCorrelationIDMiddleware_InboundRequestID(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    string // "" means a generated ID
+	}{
+		{"x-request-id honoured", map[string]string{"X-Request-ID": "client-abc-123"}, "client-abc-123"},
+		{"x-correlation-id fallback", map[string]string{"X-Correlation-ID": "corr-9"}, "corr-9"},
+		{"x-request-id wins", map[string]string{"X-Request-ID": "req-1", "X-Correlation-ID": "corr-1"}, "req-1"},
+		{"spaces rejected", map[string]string{"X-Request-ID": "bad id"}, ""},
+		{"oversized rejected", map[string]string{"X-Request-ID": strings.Repeat("a", maxRequestIDLength+1)}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var seen string
+			handler := correlationIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				seen = common.RequestIDFromContext(r.Context())
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/api/health", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if tt.want != "" && seen != tt.want {
+				t.Errorf("context request ID = %q, want %q", seen, tt.want)
+			}
+			if tt.want == "" && (len(seen) != 8 || seen == tt.headers["X-Request-ID"]) {
+				t.Errorf("context request ID = %q, want a generated ID", seen)
+			}
+			if rr.Header().Get("X-Request-ID") != seen || rr.Header().Get("X-Correlation-ID") != seen {
+				t.Errorf("response headers = %q/%q, want %q", rr.Header().Get("X-Request-ID"), rr.Header().Get("X-Correlation-ID"), seen)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package server
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/bobmcallan/vire/internal/common"
+)
+
+// rateLimitExemptPaths are never counted against a client's bucket.
+var rateLimitExemptPaths = map[string]bool{"/api/health": true}
+
+// maxRateLimitBuckets caps the bucket map. At the cap, the least recently
+// seen bucket is evicted to make room for a new client.
+const maxRateLimitBuckets = 10000
+
+// clientLimiter holds one token bucket per client key. Buckets idle long
+// enough to have refilled completely are dropped, since a fresh bucket
+// behaves identically. Idle buckets are swept at least once a minute.
+type clientLimiter struct {
+	limit   rate.Limit
+	burst   int
+	idleTTL time.Duration
+	now     func() time.Time // replaced in tests
+
+	mu        sync.Mutex
+	buckets   map[string]*clientBucket
+	lastSweep time.Time
+}
+
+type clientBucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+func newClientLimiter(requestsPerMinute, burst int) *clientLimiter {
+	limit := rate.Limit(float64(requestsPerMinute) / 60)
+	idleTTL := time.Duration(float64(burst) / float64(limit) * float64(time.Second))
+	if idleTTL < time.Minute {
+		idleTTL = time.Minute
+	}
+	return &clientLimiter{
+		limit:   limit,
+		burst:   burst,
+		idleTTL: idleTTL,
+		now:     time.Now,
+		buckets: make(map[string]*clientBucket),
+	}
+}
+
+// allow takes a token from key's bucket. When the bucket is empty it returns
+// false and how long until the next token is available.
+func (l *clientLimiter) allow(key string) (bool, time.Duration) {
+	now := l.now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	sweepEvery := l.idleTTL
+	if sweepEvery > time.Minute {
+		sweepEvery = time.Minute
+	}
+	if now.Sub(l.lastSweep) >= sweepEvery {
+		for k, b := range l.buckets {
+			if now.Sub(b.lastSeen) >= l.idleTTL {
+				delete(l.buckets, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		if len(l.buckets) >= maxRateLimitBuckets {
+			l.evictOldest()
+		}
+		b = &clientBucket{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.buckets[key] = b
+	}
+	b.lastSeen = now
+
+	if b.limiter.AllowN(now, 1) {
+		return true, 0
+	}
+	res := b.limiter.ReserveN(now, 1)
+	wait := res.DelayFrom(now)
+	res.CancelAt(now)
+	return false, wait
+}
+
+// evictOldest drops the least recently seen bucket. Callers hold l.mu.
+func (l *clientLimiter) evictOldest() {
+	var oldestKey string
+	var oldest time.Time
+	for k, b := range l.buckets {
+		if oldestKey == "" || b.lastSeen.Before(oldest) {
+			oldestKey, oldest = k, b.lastSeen
+		}
+	}
+	delete(l.buckets, oldestKey)
+}
+
+// rateLimitKey identifies the client: the subject of a bearer token that
+// verifies against secret, otherwise the remote IP. Unverified tokens fall
+// back to the IP so a client cannot mint fresh buckets by varying the token.
+// Forwarding headers are not trusted.
+func rateLimitKey(r *http.Request, secret []byte) string {
+	if auth := r.Header.Get("Authorization"); len(secret) > 0 && strings.HasPrefix(auth, "Bearer ") {
+		if _, claims, err := validateJWT(strings.TrimPrefix(auth, "Bearer "), secret); err == nil {
+			if sub, _ := claims["sub"].(string); sub != "" {
+				return "user:" + sub
+			}
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// rateLimitMiddleware applies a per-client token bucket configured by
+// [server.ratelimit]. Requests over the limit get 429 with Retry-After in
+// whole seconds. It is not installed when requests_per_minute is 0.
+func rateLimitMiddleware(config *common.Config) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		cfg := config.Server.RateLimit
+		if cfg.RequestsPerMinute <= 0 {
+			return next
+		}
+		return rateLimitHandler(newClientLimiter(cfg.RequestsPerMinute, cfg.GetBurst()), []byte(config.Auth.JWTSecret), next)
+	}
+}
+
+func rateLimitHandler(limiter *clientLimiter, secret []byte, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if rateLimitExemptPaths[r.URL.Path] || r.Method == http.MethodOptions {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if ok, wait := limiter.allow(rateLimitKey(r, secret)); !ok {
+			w.Header().Set("Retry-After", fmt.Sprintf("%d", max(1, int(math.Ceil(wait.Seconds())))))
+			WriteError(w, http.StatusTooManyRequests, "Rate limit exceeded")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
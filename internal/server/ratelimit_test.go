@@ -0,0 +1,156 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const rateLimitTestSecret = "rate-limit-test-secret"
+
+// signRateLimitToken returns a bearer token for sub signed with the test secret.
+func signRateLimitToken(t *testing.T, sub string) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": sub,
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte(rateLimitTestSecret))
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return token
+}
+
+// newTestRateLimitHandler returns a limited handler and a clock the test
+// advances by hand.
+func newTestRateLimitHandler(requestsPerMinute, burst int) (http.Handler, *time.Time) {
+	clock := time.Date(2026, 1, 5, 10, 0, 0, 0, time.UTC)
+	limiter := newClientLimiter(requestsPerMinute, burst)
+	limiter.now = func() time.Time { return clock }
+	handler := rateLimitHandler(limiter, []byte(rateLimitTestSecret), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	return handler, &clock
+}
+
+func doLimited(handler http.Handler, path, remoteAddr, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.RemoteAddr = remoteAddr
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRateLimit_RejectsRequestOverBurst(t *testing.T) {
+	handler, _ := newTestRateLimitHandler(60, 3)
+
+	for i := 1; i <= 3; i++ {
+		if rec := doLimited(handler, "/api/portfolios", "10.0.0.1:5000", ""); rec.Code != http.StatusOK {
+			t.Fatalf("request %d: status %d, want 200", i, rec.Code)
+		}
+	}
+	rec := doLimited(handler, "/api/portfolios", "10.0.0.1:5001", "")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("request 4: status %d, want 429", rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "1" {
+		t.Errorf("Retry-After = %q, want 1 (one token per second)", got)
+	}
+
+	// Another client has its own bucket
+	if rec := doLimited(handler, "/api/portfolios", "10.0.0.2:5000", ""); rec.Code != http.StatusOK {
+		t.Errorf("other IP: status %d, want 200", rec.Code)
+	}
+}
+
+func TestRateLimit_BucketRefillsAfterWindow(t *testing.T) {
+	handler, clock := newTestRateLimitHandler(2, 2)
+
+	doLimited(handler, "/api/portfolios", "", "token-a")
+	doLimited(handler, "/api/portfolios", "", "token-a")
+	rec := doLimited(handler, "/api/portfolios", "", "token-a")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("third request: status %d, want 429", rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "30" {
+		t.Errorf("Retry-After = %q, want 30", got)
+	}
+
+	*clock = clock.Add(time.Minute)
+	for i := 1; i <= 2; i++ {
+		if rec := doLimited(handler, "/api/portfolios", "", "token-a"); rec.Code != http.StatusOK {
+			t.Fatalf("after refill, request %d: status %d, want 200", i, rec.Code)
+		}
+	}
+	if rec := doLimited(handler, "/api/portfolios", "", "token-a"); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("after refill, request 3: status %d, want 429", rec.Code)
+	}
+}
+
+func TestRateLimit_HealthNotCounted(t *testing.T) {
+	handler, _ := newTestRateLimitHandler(60, 1)
+
+	for i := 0; i < 5; i++ {
+		if rec := doLimited(handler, "/api/health", "10.0.0.1:5000", ""); rec.Code != http.StatusOK {
+			t.Fatalf("health %d: status %d, want 200", i, rec.Code)
+		}
+	}
+	if rec := doLimited(handler, "/api/portfolios", "10.0.0.1:5000", ""); rec.Code != http.StatusOK {
+		t.Errorf("first counted request: status %d, want 200", rec.Code)
+	}
+}
+
+func TestRateLimit_TokenKeyedSeparatelyFromIP(t *testing.T) {
+	handler, _ := newTestRateLimitHandler(60, 1)
+	tokenA, tokenB := signRateLimitToken(t, "alice"), signRateLimitToken(t, "bob")
+
+	if rec := doLimited(handler, "/api/portfolios", "10.0.0.1:5000", tokenA); rec.Code != http.StatusOK {
+		t.Fatalf("alice: status %d, want 200", rec.Code)
+	}
+	if rec := doLimited(handler, "/api/portfolios", "10.0.0.1:5000", tokenB); rec.Code != http.StatusOK {
+		t.Errorf("bob from same IP: status %d, want 200", rec.Code)
+	}
+	if rec := doLimited(handler, "/api/portfolios", "10.0.0.2:5000", tokenA); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("alice again from another IP: status %d, want 429", rec.Code)
+	}
+}
+
+func TestRateLimit_InvalidTokensShareIPBucket(t *testing.T) {
+	handler, _ := newTestRateLimitHandler(60, 1)
+
+	if rec := doLimited(handler, "/api/portfolios", "10.0.0.1:5000", "random-token-1"); rec.Code != http.StatusOK {
+		t.Fatalf("first request: status %d, want 200", rec.Code)
+	}
+	// A different unverified token must not get a fresh bucket
+	if rec := doLimited(handler, "/api/portfolios", "10.0.0.1:5000", "random-token-2"); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("second random token: status %d, want 429", rec.Code)
+	}
+	forged := signRateLimitToken(t, "alice") + "x"
+	if rec := doLimited(handler, "/api/portfolios", "10.0.0.1:5000", forged); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("token with bad signature: status %d, want 429", rec.Code)
+	}
+}
+
+func TestRateLimit_BucketMapIsCapped(t *testing.T) {
+	clock := time.Date(2026, 1, 5, 10, 0, 0, 0, time.UTC)
+	limiter := newClientLimiter(60, 1)
+	limiter.now = func() time.Time { return clock }
+
+	for i := 0; i < maxRateLimitBuckets+50; i++ {
+		clock = clock.Add(time.Millisecond)
+		limiter.allow(fmt.Sprintf("ip:10.%d.%d.%d", i>>16&255, i>>8&255, i&255))
+	}
+	if n := len(limiter.buckets); n > maxRateLimitBuckets {
+		t.Errorf("bucket map holds %d entries, want at most %d", n, maxRateLimitBuckets)
+	}
+	if _, ok := limiter.buckets["ip:10.0.0.0"]; ok {
+		t.Error("oldest bucket should have been evicted")
+	}
+}
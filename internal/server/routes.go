@@ -53,6 +53,9 @@ func (s *Server) registerRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("/api/mcp/tools", s.handleToolCatalog)
 	mux.HandleFunc("/api/shutdown", s.handleShutdown)
 	mux.HandleFunc("/debug/memstats", s.handleMemstats)
+	if s.app.Config.Server.Metrics.Enabled {
+		mux.Handle("/metrics", common.MetricsHandler())
+	}
 
 	// Users
 	mux.HandleFunc("/api/users/upsert", s.handleUserUpsert)
@@ -100,6 +103,7 @@ func (s *Server) registerRoutes(mux *http.ServeMux) {
 	// Screening
 	mux.HandleFunc("/api/screen/stocks", s.handleScreenStocks)
 	mux.HandleFunc("/api/screen/funnel", s.handleScreenFunnel)
+	mux.HandleFunc("/api/screen/scored", s.handleScreenScored)
 
 	// Searches
 	mux.HandleFunc("/api/searches/", s.handleSearchByID)
@@ -111,10 +115,17 @@ func (s *Server) registerRoutes(mux *http.ServeMux) {
 	// Admin — job queue, stock index, WebSocket
 	mux.HandleFunc("/api/admin/jobs/enqueue", s.handleAdminJobEnqueue)
 	mux.HandleFunc("/api/admin/jobs/queue", s.handleAdminJobQueue)
-	mux.HandleFunc("/api/admin/jobs/", s.routeAdminJobs) // handles {id}/priority, {id}/cancel
+	mux.HandleFunc("/api/admin/jobs/export", s.handleAdminJobExport)
+	mux.HandleFunc("/api/admin/jobs/dead-letter", s.handleAdminDeadLetterJobs)
+	mux.HandleFunc("/api/admin/jobs/", s.routeAdminJobs) // handles {id}/priority, {id}/cancel, {id}/requeue
 	mux.HandleFunc("/api/admin/jobs", s.handleAdminJobs)
 	mux.HandleFunc("/api/admin/stock-index", s.handleAdminStockIndex)
 	mux.HandleFunc("/api/admin/services/tidy", s.handleServiceTidy)
+	mux.HandleFunc("/api/admin/config/reload", s.handleAdminConfigReload)
+	mux.HandleFunc("/api/admin/api-keys/", s.handleAdminAPIKey)
+	mux.HandleFunc("/api/admin/webhooks/test", s.handleAdminWebhookTest)
+	mux.HandleFunc("/api/admin/backups/", s.routeAdminBackups) // handles {key} download and restore
+	mux.HandleFunc("/api/admin/backups", s.handleAdminBackupCreate)
 	mux.HandleFunc("/api/admin/users/", s.routeAdminUsers) // handles {id}/role
 	mux.HandleFunc("/api/admin/users", s.handleAdminListUsers)
 	mux.HandleFunc("/api/admin/ws/jobs", s.handleAdminJobsWS)
@@ -130,9 +141,11 @@ func (s *Server) registerRoutes(mux *http.ServeMux) {
 
 	// Reports (non-portfolio)
 	mux.HandleFunc("/api/reports", s.handleReportList)
+	mux.HandleFunc("/api/reports/search", s.handleReportSearch)
 
 	// Strategy template
 	mux.HandleFunc("/api/strategies/template", s.handleStrategyTemplate)
+	mux.HandleFunc("/api/strategies/apply", s.handleStrategyApply)
 
 	// Internal OAuth persistence (portal integration)
 	mux.HandleFunc("/api/internal/oauth/", s.routeInternalOAuth)
@@ -161,6 +174,13 @@ func (s *Server) routeMarketStocks(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Check for /signal-backtest suffix
+	if strings.HasSuffix(path, "/signal-backtest") {
+		ticker := strings.TrimSuffix(path, "/signal-backtest")
+		s.handleSignalBacktest(w, r, ticker)
+		return
+	}
+
 	// Default: pass through to stock data handler
 	s.handleMarketStocks(w, r)
 }
@@ -179,6 +199,8 @@ func (s *Server) routeAdminJobs(w http.ResponseWriter, r *http.Request) {
 		s.handleAdminJobPriority(w, r)
 	case "cancel":
 		s.handleAdminJobCancel(w, r)
+	case "requeue":
+		s.handleAdminJobRequeue(w, r, parts[0])
 	default:
 		WriteError(w, http.StatusNotFound, "Not found")
 	}
@@ -227,6 +249,8 @@ func (s *Server) routePortfolios(w http.ResponseWriter, r *http.Request) {
 		s.handlePortfolioGet(w, r, name)
 	case "review":
 		s.handlePortfolioReview(w, r, name)
+	case "review/stream":
+		s.handlePortfolioReviewStream(w, r, name)
 	case "sync":
 		s.handlePortfolioSync(w, r, name)
 	case "rebuild":
@@ -251,17 +275,47 @@ func (s *Server) routePortfolios(w http.ResponseWriter, r *http.Request) {
 		s.handlePortfolioWatchlist(w, r, name)
 	case "notes":
 		s.handleHoldingNotes(w, r, name)
+	case "alerts":
+		s.handlePortfolioAlerts(w, r, name)
+	case "alerts/acknowledge":
+		s.handlePortfolioAlertAcknowledge(w, r, name)
+	case "alerts/digest":
+		s.handlePortfolioAlertDigest(w, r, name)
+	case "correlation":
+		s.handlePortfolioCorrelation(w, r, name)
 	case "indicators":
 		s.handlePortfolioIndicators(w, r, name)
+	case "completeness":
+		s.handlePortfolioCompleteness(w, r, name)
+	case "twr":
+		s.handlePortfolioTWR(w, r, name)
+	case "cgt":
+		s.handlePortfolioCGT(w, r, name)
+	case "realized-timeline":
+		s.handlePortfolioRealizedTimeline(w, r, name)
+	case "export":
+		s.handlePortfolioExport(w, r, name)
+	case "simulate":
+		s.handlePortfolioSimulateTrade(w, r, name)
+	case "sectors":
+		s.handlePortfolioSectors(w, r, name)
+	case "fees":
+		s.handlePortfolioFees(w, r, name)
+	case "rebalance":
+		s.handlePortfolioRebalance(w, r, name)
+	case "projection":
+		s.handlePortfolioProjection(w, r, name)
 	case "glossary":
 		s.handleGlossary(w, r, name)
 	case "cash-transactions":
 		s.handleCashFlows(w, r, name)
+	case "cash-reconcile":
+		s.handleCashReconcile(w, r, name)
 	case "asset-sets":
 		s.handleAssetSets(w, r, name)
 	default:
 		// Check for nested paths: plan/items, plan/items/{id}, plan/status
-		// reports/{ticker}, stock/{ticker}, watchlist/items, watchlist/items/{ticker}
+		// report/pdf, report/pdf/{id}, reports/{ticker}, stock/{ticker}, watchlist/items, watchlist/items/{ticker}, watchlist/events
 		if strings.HasPrefix(subpath, "cash-transactions/") {
 			sub := strings.TrimPrefix(subpath, "cash-transactions/")
 			if sub == "transfer" {
@@ -274,6 +328,8 @@ func (s *Server) routePortfolios(w http.ResponseWriter, r *http.Request) {
 			s.handleUpdateAccount(w, r, name, accountName)
 		} else if strings.HasPrefix(subpath, "plan/") {
 			s.routePlan(w, r, name, strings.TrimPrefix(subpath, "plan/"))
+		} else if subpath == "report/pdf" || strings.HasPrefix(subpath, "report/pdf/") {
+			s.handlePortfolioReportPDF(w, r, name, strings.TrimPrefix(strings.TrimPrefix(subpath, "report/pdf"), "/"))
 		} else if strings.HasPrefix(subpath, "reports/") {
 			ticker := strings.TrimPrefix(subpath, "reports/")
 			s.handlePortfolioTickerReport(w, r, name, ticker)
@@ -282,6 +338,12 @@ func (s *Server) routePortfolios(w http.ResponseWriter, r *http.Request) {
 			if strings.HasSuffix(rest, "/timeline") {
 				ticker := strings.TrimSuffix(rest, "/timeline")
 				s.handleStockTimeline(w, r, name, ticker)
+			} else if strings.HasSuffix(rest, "/note") {
+				s.handleHoldingAnnotationNote(w, r, name, strings.TrimSuffix(rest, "/note"))
+			} else if strings.HasSuffix(rest, "/tags") {
+				s.handleHoldingAnnotationTags(w, r, name, strings.TrimSuffix(rest, "/tags"))
+			} else if strings.HasSuffix(rest, "/sync") {
+				s.handleHoldingSync(w, r, name, strings.TrimSuffix(rest, "/sync"))
 			} else {
 				s.handlePortfolioStock(w, r, name, rest)
 			}
@@ -322,6 +384,8 @@ func (s *Server) routeWatchlist(w http.ResponseWriter, r *http.Request, portfoli
 	switch {
 	case subpath == "review":
 		s.handleWatchlistReview(w, r, portfolioName)
+	case subpath == "events":
+		s.handleWatchlistEvents(w, r, portfolioName)
 	case subpath == "items":
 		s.handleWatchlistItemAdd(w, r, portfolioName)
 	case strings.HasPrefix(subpath, "items/"):
@@ -390,7 +454,7 @@ func (s *Server) handleConfig(w http.ResponseWriter, r *http.Request) {
 		"portfolios":        resolvedPortfolios,
 		"display_currency":  resolvedCurrency,
 		"environment":       s.app.Config.Environment,
-		"logging_level":     s.app.Config.Logging.Level,
+		"logging_level":     s.app.LoggingLevel(),
 		"eodhd_configured":  s.app.EODHDClient != nil,
 		"navexa_configured": true, // always available via portal injection
 		"gemini_configured": s.app.GeminiClient != nil,
@@ -33,7 +33,7 @@ func NewServer(a *app.App) *Server {
 	mux := http.NewServeMux()
 	s.registerRoutes(mux)
 
-	handler := applyMiddleware(mux, a.Logger, a.Config, a.Storage.InternalStore())
+	handler := applyMiddleware(mux, a.Logger, a.Config, a.Storage.InternalStore(), a.Storage.OAuthStore())
 
 	host := a.Config.Server.Host
 	port := a.Config.Server.Port
@@ -75,7 +75,7 @@ func (s *Service) SaveAssetSets(ctx context.Context, sets *models.PortfolioAsset
 	if err := s.saveRecord(ctx, sets); err != nil {
 		return fmt.Errorf("failed to save asset sets: %w", err)
 	}
-	s.logger.Info().Str("portfolio", sets.PortfolioName).Msg("Asset sets saved")
+	s.logger.WithRequestID(ctx).Info().Str("portfolio", sets.PortfolioName).Msg("Asset sets saved")
 	return nil
 }
 
@@ -102,7 +102,7 @@ func (s *Service) saveRecord(ctx context.Context, sets *models.PortfolioAssetSet
 func (s *Service) invalidateTimeline(ctx context.Context, portfolioName string) {
 	if s.portfolioSvc != nil {
 		s.portfolioSvc.InvalidateAndRebuildTimeline(ctx, portfolioName)
-		s.logger.Info().Str("portfolio", portfolioName).Msg("Timeline invalidation triggered by asset set change")
+		s.logger.WithRequestID(ctx).Info().Str("portfolio", portfolioName).Msg("Timeline invalidation triggered by asset set change")
 	}
 }
 
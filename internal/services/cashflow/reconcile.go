@@ -0,0 +1,198 @@
+package cashflow
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/interfaces"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// reconcileTolerance absorbs cent-level rounding between broker statements and the ledger
+const reconcileTolerance = 1.0
+
+// cashMovement is a dated change in derived cash
+type cashMovement struct {
+	date   time.Time
+	amount float64
+}
+
+// SetBrokerBalance records the broker-reported cash balance for a date,
+// replacing any balance already recorded for that day.
+func (s *Service) SetBrokerBalance(ctx context.Context, portfolioName string, balance models.BrokerBalance) (*models.CashFlowLedger, error) {
+	if balance.Date.IsZero() {
+		return nil, fmt.Errorf("%w: date is required", interfaces.ErrInvalidBrokerBalance)
+	}
+	if math.IsNaN(balance.Balance) || math.IsInf(balance.Balance, 0) {
+		return nil, fmt.Errorf("%w: balance must be a finite number", interfaces.ErrInvalidBrokerBalance)
+	}
+
+	ledger, err := s.GetLedger(ctx, portfolioName)
+	if err != nil {
+		return nil, err
+	}
+
+	balance.Date = balance.Date.UTC().Truncate(24 * time.Hour)
+	balance.Notes = strings.TrimSpace(balance.Notes)
+	replaced := false
+	for i, existing := range ledger.BrokerBalances {
+		if existing.Date.Equal(balance.Date) {
+			ledger.BrokerBalances[i] = balance
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		ledger.BrokerBalances = append(ledger.BrokerBalances, balance)
+	}
+	sort.Slice(ledger.BrokerBalances, func(i, j int) bool {
+		return ledger.BrokerBalances[i].Date.Before(ledger.BrokerBalances[j].Date)
+	})
+
+	if err := s.saveLedger(ctx, ledger); err != nil {
+		return nil, err
+	}
+
+	s.logger.WithRequestID(ctx).Info().Str("portfolio", portfolioName).Str("date", balance.Date.Format("2006-01-02")).
+		Float64("balance", balance.Balance).Msg("Broker balance recorded")
+	return ledger, nil
+}
+
+// Reconcile derives the cash balance from the ledger (contributions, withdrawals,
+// dividends, fees, transfers) and the portfolio's buy and sell trades (converted
+// into the portfolio's base currency, each ticker counted once), then checks
+// it against each broker-reported balance in date order. A discrepancy is raised
+// for each statement where the gap between broker and derived cash moved by more
+// than reconcileTolerance since the previous statement, so one missing transaction
+// is flagged once, between the two statements that bracket it.
+func (s *Service) Reconcile(ctx context.Context, portfolioName string) (*models.CashReconciliation, error) {
+	ledger, err := s.GetLedger(ctx, portfolioName)
+	if err != nil {
+		return nil, err
+	}
+
+	rec := &models.CashReconciliation{
+		PortfolioName: portfolioName,
+		Discrepancies: []models.CashDiscrepancy{},
+	}
+
+	moves := make([]cashMovement, 0, len(ledger.Transactions))
+	for _, tx := range ledger.Transactions {
+		switch tx.Category {
+		case models.CashCatContribution:
+			if tx.Amount >= 0 {
+				rec.Contributions += tx.Amount
+			} else {
+				rec.Withdrawals -= tx.Amount
+			}
+		case models.CashCatDividend:
+			rec.Dividends += tx.Amount
+		case models.CashCatFee:
+			rec.Fees -= tx.Amount
+		default:
+			rec.Other += tx.Amount
+		}
+		moves = append(moves, cashMovement{date: tx.Date, amount: tx.Amount})
+	}
+
+	// Trades settle in cash. Opening balances transfer existing shares in and move no cash.
+	if s.portfolioService != nil {
+		if portfolio, err := s.portfolioService.GetPortfolio(ctx, portfolioName); err != nil {
+			s.logger.WithRequestID(ctx).Warn().Str("portfolio", portfolioName).Err(err).Msg("Reconcile: trades unavailable, using ledger only")
+		} else {
+			rec.TradesIncluded = true
+			// Holdings that share a ticker carry the same merged trades
+			seen := make(map[string]bool, len(portfolio.Holdings))
+			for i := range portfolio.Holdings {
+				h := &portfolio.Holdings[i]
+				if seen[h.Ticker] {
+					continue
+				}
+				seen[h.Ticker] = true
+				currency := h.OriginalCurrency // trades stay in the native currency
+				if currency == "" {
+					currency = h.Currency
+				}
+				fxDiv := s.portfolioService.FXDivisor(ctx, portfolio, currency)
+				for _, t := range h.Trades {
+					date := parseTradeDate(t.Date)
+					switch strings.ToLower(t.Type) {
+					case "buy":
+						cost := (t.Units*t.Price + t.Fees) / fxDiv
+						rec.Buys += cost
+						moves = append(moves, cashMovement{date: date, amount: -cost})
+					case "sell":
+						proceeds := (t.Units*t.Price - t.Fees) / fxDiv
+						rec.Sells += proceeds
+						moves = append(moves, cashMovement{date: date, amount: proceeds})
+					}
+				}
+			}
+		}
+	}
+
+	for _, m := range moves {
+		rec.DerivedBalance += m.amount
+	}
+	sort.SliceStable(moves, func(i, j int) bool { return moves[i].date.Before(moves[j].date) })
+
+	statements := append([]models.BrokerBalance(nil), ledger.BrokerBalances...)
+	sort.SliceStable(statements, func(i, j int) bool { return statements[i].Date.Before(statements[j].Date) })
+
+	prevDiff := 0.0
+	var prevDate *time.Time
+	for i := range statements {
+		st := statements[i]
+		derived := balanceAt(moves, st.Date)
+		diff := st.Balance - derived
+		if gap := diff - prevDiff; math.Abs(gap) > reconcileTolerance {
+			rec.Discrepancies = append(rec.Discrepancies, models.CashDiscrepancy{
+				Since:          prevDate,
+				Date:           st.Date,
+				BrokerBalance:  st.Balance,
+				DerivedBalance: derived,
+				Difference:     diff,
+				Unexplained:    gap,
+				Description:    describeGap(gap, prevDate, st.Date),
+			})
+		}
+		prevDiff = diff
+		prevDate = &statements[i].Date
+		rec.BrokerBalance = &statements[i]
+		rec.Difference = diff
+	}
+
+	rec.Reconciled = len(rec.Discrepancies) == 0 && math.Abs(rec.Difference) <= reconcileTolerance
+	return rec, nil
+}
+
+// balanceAt sums date-sorted movements up to the end of the given day (UTC)
+func balanceAt(moves []cashMovement, date time.Time) float64 {
+	cutoff := date.UTC().Truncate(24 * time.Hour).Add(24 * time.Hour)
+	total := 0.0
+	for _, m := range moves {
+		if !m.date.Before(cutoff) {
+			break
+		}
+		total += m.amount
+	}
+	return total
+}
+
+func describeGap(gap float64, since *time.Time, date time.Time) string {
+	window := "on or before " + date.Format("2006-01-02")
+	if since != nil {
+		window = fmt.Sprintf("between %s and %s", since.Format("2006-01-02"), date.Format("2006-01-02"))
+	}
+	if gap > 0 {
+		return fmt.Sprintf("Broker holds %s more cash than the ledger explains %s — possible missing contribution, dividend or sale",
+			common.FormatMoney(gap), window)
+	}
+	return fmt.Sprintf("Broker holds %s less cash than the ledger explains %s — possible missing withdrawal, fee or purchase",
+		common.FormatMoney(-gap), window)
+}
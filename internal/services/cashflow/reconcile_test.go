@@ -0,0 +1,230 @@
+package cashflow
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/interfaces"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+func day(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func approx(a, b float64) bool { return math.Abs(a-b) < 0.005 }
+
+func TestReconcile_NoTransactions(t *testing.T) {
+	svc, portfolioSvc := testService()
+	portfolioSvc.portfolio.Holdings = nil
+
+	rec, err := svc.Reconcile(testContext(), "SMSF")
+	if err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if rec.DerivedBalance != 0 || len(rec.Discrepancies) != 0 || !rec.Reconciled {
+		t.Errorf("empty ledger reconciliation = %+v, want zero balance, no discrepancies", rec)
+	}
+
+	// A zero broker statement also reconciles
+	if _, err := svc.SetBrokerBalance(testContext(), "SMSF", models.BrokerBalance{Date: day(2025, 1, 31), Balance: 0}); err != nil {
+		t.Fatalf("SetBrokerBalance: %v", err)
+	}
+	rec, _ = svc.Reconcile(testContext(), "SMSF")
+	if len(rec.Discrepancies) != 0 || !rec.Reconciled || rec.BrokerBalance == nil {
+		t.Errorf("zero statement reconciliation = %+v", rec)
+	}
+}
+
+func TestReconcile_SumsLedgerAndTrades(t *testing.T) {
+	svc, portfolioSvc := testService()
+	ctx := testContext()
+	portfolioSvc.portfolio.Holdings = []models.Holding{{
+		Ticker: "BHP",
+		Trades: []*models.NavexaTrade{
+			{Type: "Opening Balance", Date: "2024-12-01", Units: 50, Price: 40},
+			{Type: "Buy", Date: "2025-01-10", Units: 100, Price: 45, Fees: 10},
+			{Type: "Sell", Date: "2025-02-10", Units: 40, Price: 50, Fees: 10},
+		},
+	}}
+
+	for _, tx := range []models.CashTransaction{
+		{Account: "Trading", Category: models.CashCatContribution, Date: day(2025, 1, 1), Amount: 10000, Description: "Deposit"},
+		{Account: "Trading", Category: models.CashCatContribution, Date: day(2025, 3, 1), Amount: -1000, Description: "Withdrawal"},
+		{Account: "Trading", Category: models.CashCatDividend, Date: day(2025, 2, 20), Amount: 120, Description: "BHP dividend"},
+		{Account: "Trading", Category: models.CashCatFee, Date: day(2025, 2, 28), Amount: -20, Description: "Platform fee"},
+	} {
+		if _, err := svc.AddTransaction(ctx, "SMSF", tx); err != nil {
+			t.Fatalf("AddTransaction: %v", err)
+		}
+	}
+
+	rec, err := svc.Reconcile(ctx, "SMSF")
+	if err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	// 10000 - 1000 + 120 - 20 - 4510 + 1990 (opening balance moves no cash)
+	if !rec.TradesIncluded || !approx(rec.Buys, 4510) || !approx(rec.Sells, 1990) {
+		t.Errorf("trades = buys %.2f sells %.2f included %v", rec.Buys, rec.Sells, rec.TradesIncluded)
+	}
+	if !approx(rec.Contributions, 10000) || !approx(rec.Withdrawals, 1000) || !approx(rec.Dividends, 120) || !approx(rec.Fees, 20) {
+		t.Errorf("ledger totals = %+v", rec)
+	}
+	if !approx(rec.DerivedBalance, 6580) {
+		t.Errorf("derived balance = %.2f, want 6580", rec.DerivedBalance)
+	}
+}
+
+func TestReconcile_FlagsSingleUnexplainedGapOnce(t *testing.T) {
+	svc, portfolioSvc := testService()
+	ctx := testContext()
+	portfolioSvc.portfolio.Holdings = nil
+
+	if _, err := svc.AddTransaction(ctx, "SMSF", models.CashTransaction{
+		Account: "Trading", Category: models.CashCatContribution, Date: day(2025, 1, 5), Amount: 20000, Description: "Deposit",
+	}); err != nil {
+		t.Fatalf("AddTransaction: %v", err)
+	}
+
+	// January matches; a $5,000 deposit is missing from the ledger in February,
+	// so February and March both show the gap but only February introduced it.
+	for _, st := range []models.BrokerBalance{
+		{Date: day(2025, 3, 31), Balance: 25000},
+		{Date: day(2025, 1, 31), Balance: 20000.40},
+		{Date: day(2025, 2, 28), Balance: 25000},
+	} {
+		if _, err := svc.SetBrokerBalance(ctx, "SMSF", st); err != nil {
+			t.Fatalf("SetBrokerBalance: %v", err)
+		}
+	}
+
+	rec, err := svc.Reconcile(ctx, "SMSF")
+	if err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if len(rec.Discrepancies) != 1 {
+		t.Fatalf("discrepancies = %+v, want exactly one", rec.Discrepancies)
+	}
+	d := rec.Discrepancies[0]
+	if !d.Date.Equal(day(2025, 2, 28)) || d.Since == nil || !d.Since.Equal(day(2025, 1, 31)) {
+		t.Errorf("discrepancy window = %v..%v, want 2025-01-31..2025-02-28", d.Since, d.Date)
+	}
+	if !approx(d.Unexplained, 4999.60) || !approx(d.Difference, 5000) || d.Unexplained <= 0 {
+		t.Errorf("discrepancy amounts = %+v, want +4999.60 unexplained", d)
+	}
+	if !strings.Contains(d.Description, "more cash") {
+		t.Errorf("description = %q, want a missing-credit hint", d.Description)
+	}
+	if rec.Reconciled || !approx(rec.Difference, 5000) || !rec.BrokerBalance.Date.Equal(day(2025, 3, 31)) {
+		t.Errorf("summary = reconciled %v difference %.2f latest %v", rec.Reconciled, rec.Difference, rec.BrokerBalance)
+	}
+
+	// Recording the missing deposit clears the discrepancy
+	if _, err := svc.AddTransaction(ctx, "SMSF", models.CashTransaction{
+		Account: "Trading", Category: models.CashCatContribution, Date: day(2025, 2, 14), Amount: 5000, Description: "Missed deposit",
+	}); err != nil {
+		t.Fatalf("AddTransaction: %v", err)
+	}
+	rec, _ = svc.Reconcile(ctx, "SMSF")
+	if len(rec.Discrepancies) != 0 || !rec.Reconciled {
+		t.Errorf("after fix = %+v, want reconciled", rec.Discrepancies)
+	}
+}
+
+func TestReconcile_NegativeGapSign(t *testing.T) {
+	svc, portfolioSvc := testService()
+	ctx := testContext()
+	portfolioSvc.portfolio.Holdings = nil
+
+	if _, err := svc.AddTransaction(ctx, "SMSF", models.CashTransaction{
+		Account: "Trading", Category: models.CashCatContribution, Date: day(2025, 1, 5), Amount: 1000, Description: "Deposit",
+	}); err != nil {
+		t.Fatalf("AddTransaction: %v", err)
+	}
+	if _, err := svc.SetBrokerBalance(ctx, "SMSF", models.BrokerBalance{Date: day(2025, 1, 31), Balance: 700}); err != nil {
+		t.Fatalf("SetBrokerBalance: %v", err)
+	}
+
+	rec, _ := svc.Reconcile(ctx, "SMSF")
+	if len(rec.Discrepancies) != 1 {
+		t.Fatalf("discrepancies = %+v", rec.Discrepancies)
+	}
+	d := rec.Discrepancies[0]
+	if d.Since != nil || !approx(d.Unexplained, -300) || !strings.Contains(d.Description, "less cash") {
+		t.Errorf("discrepancy = %+v, want -300 with a missing-debit hint", d)
+	}
+}
+
+func TestSetBrokerBalance_ReplacesSameDay(t *testing.T) {
+	svc, _ := testService()
+	ctx := testContext()
+
+	if _, err := svc.SetBrokerBalance(ctx, "SMSF", models.BrokerBalance{Date: day(2025, 1, 31), Balance: 100}); err != nil {
+		t.Fatalf("SetBrokerBalance: %v", err)
+	}
+	ledger, err := svc.SetBrokerBalance(ctx, "SMSF", models.BrokerBalance{Date: day(2025, 1, 31).Add(15 * time.Hour), Balance: 200})
+	if err != nil {
+		t.Fatalf("SetBrokerBalance: %v", err)
+	}
+	if len(ledger.BrokerBalances) != 1 || ledger.BrokerBalances[0].Balance != 200 {
+		t.Errorf("broker balances = %+v, want one replaced entry", ledger.BrokerBalances)
+	}
+
+	if _, err := svc.SetBrokerBalance(ctx, "SMSF", models.BrokerBalance{Balance: 1}); !errors.Is(err, interfaces.ErrInvalidBrokerBalance) {
+		t.Errorf("missing date: got %v, want ErrInvalidBrokerBalance", err)
+	}
+}
+
+func TestReconcile_SameTickerCountedOnce(t *testing.T) {
+	svc, portfolioSvc := testService()
+	trades := []*models.NavexaTrade{
+		{Type: "Buy", Date: "2025-01-10", Units: 100, Price: 45, Fees: 10},
+	}
+	// Two accounts holding BHP carry the same merged trades
+	portfolioSvc.portfolio.Holdings = []models.Holding{
+		{Ticker: "BHP", Units: 60, Trades: trades},
+		{Ticker: "BHP", Units: 40, Trades: trades},
+	}
+
+	rec, err := svc.Reconcile(testContext(), "SMSF")
+	if err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if !approx(rec.Buys, 4510) || !approx(rec.DerivedBalance, -4510) {
+		t.Errorf("buys %.2f derived %.2f, want 4510 / -4510", rec.Buys, rec.DerivedBalance)
+	}
+}
+
+func TestReconcile_ConvertsForeignTrades(t *testing.T) {
+	svc, portfolioSvc := testService()
+	portfolioSvc.fxRates = map[string]float64{"USD": 0.65, "GBP": 0.5}
+	portfolioSvc.portfolio.BaseCurrency = "AUD"
+	portfolioSvc.portfolio.Holdings = []models.Holding{
+		// Converted at sync: trades are still in the original currency
+		{Ticker: "NVDA", Currency: "AUD", OriginalCurrency: "USD", Trades: []*models.NavexaTrade{
+			{Type: "Buy", Date: "2025-01-10", Units: 10, Price: 130},
+		}},
+		// Not converted at sync
+		{Ticker: "VOD", Currency: "GBP", Trades: []*models.NavexaTrade{
+			{Type: "Sell", Date: "2025-02-10", Units: 100, Price: 1},
+		}},
+		{Ticker: "BHP", Currency: "AUD", Trades: []*models.NavexaTrade{
+			{Type: "Buy", Date: "2025-01-10", Units: 10, Price: 45},
+		}},
+	}
+
+	rec, err := svc.Reconcile(testContext(), "SMSF")
+	if err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	// 1300 USD / 0.65 + 450 AUD; 100 GBP / 0.5
+	if !approx(rec.Buys, 2450) || !approx(rec.Sells, 200) {
+		t.Errorf("buys %.2f sells %.2f, want 2450 / 200 in AUD", rec.Buys, rec.Sells)
+	}
+	if got := strings.Join(portfolioSvc.fxCurrencies, ","); got != "USD,GBP,AUD" {
+		t.Errorf("FXDivisor currencies = %s, want USD,GBP,AUD", got)
+	}
+}
@@ -157,7 +157,7 @@ func (s *Service) AddTransaction(ctx context.Context, portfolioName string, tx m
 		return nil, err
 	}
 
-	s.logger.Info().Str("portfolio", portfolioName).Str("id", tx.ID).
+	s.logger.WithRequestID(ctx).Info().Str("portfolio", portfolioName).Str("id", tx.ID).
 		Str("account", tx.Account).Float64("amount", tx.Amount).
 		Str("category", string(tx.Category)).Msg("Cash transaction added")
 	return ledger, nil
@@ -235,7 +235,7 @@ func (s *Service) AddTransfer(ctx context.Context, portfolioName string, fromAcc
 		return nil, err
 	}
 
-	s.logger.Info().Str("portfolio", portfolioName).
+	s.logger.WithRequestID(ctx).Info().Str("portfolio", portfolioName).
 		Str("fromID", fromID).Str("toID", toID).
 		Str("from", fromAccount).Str("to", toAccount).
 		Float64("amount", absAmount).Msg("Transfer added")
@@ -321,7 +321,7 @@ func (s *Service) UpdateTransaction(ctx context.Context, portfolioName string, t
 		return nil, err
 	}
 
-	s.logger.Info().Str("portfolio", portfolioName).Str("id", txID).Msg("Cash transaction updated")
+	s.logger.WithRequestID(ctx).Info().Str("portfolio", portfolioName).Str("id", txID).Msg("Cash transaction updated")
 	return ledger, nil
 }
 
@@ -361,7 +361,7 @@ func (s *Service) RemoveTransaction(ctx context.Context, portfolioName string, t
 		return nil, err
 	}
 
-	s.logger.Info().Str("portfolio", portfolioName).Str("id", txID).Msg("Cash transaction removed")
+	s.logger.WithRequestID(ctx).Info().Str("portfolio", portfolioName).Str("id", txID).Msg("Cash transaction removed")
 	return ledger, nil
 }
 
@@ -394,7 +394,7 @@ func (s *Service) UpdateAccount(ctx context.Context, portfolioName string, accou
 		return nil, err
 	}
 
-	s.logger.Info().Str("portfolio", portfolioName).Str("account", accountName).
+	s.logger.WithRequestID(ctx).Info().Str("portfolio", portfolioName).Str("account", accountName).
 		Str("type", acct.Type).Bool("is_transactional", acct.IsTransactional).
 		Str("currency", acct.Currency).
 		Msg("Account updated")
@@ -452,7 +452,7 @@ func (s *Service) SetTransactions(ctx context.Context, portfolioName string, tra
 		return nil, err
 	}
 
-	s.logger.Info().Str("portfolio", portfolioName).
+	s.logger.WithRequestID(ctx).Info().Str("portfolio", portfolioName).
 		Int("count", len(assigned)).Msg("Cash transactions replaced (bulk set)")
 	return ledger, nil
 }
@@ -474,7 +474,7 @@ func (s *Service) ClearLedger(ctx context.Context, portfolioName string) (*model
 		return nil, err
 	}
 
-	s.logger.Warn().Str("portfolio", portfolioName).
+	s.logger.WithRequestID(ctx).Warn().Str("portfolio", portfolioName).
 		Msg("Cash ledger cleared — all transactions and accounts removed")
 	return ledger, nil
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"math"
 	"strings"
 	"testing"
@@ -54,6 +55,11 @@ func (m *mockUserDataStore) Query(_ context.Context, _, _ string, _ interfaces.Q
 	return nil, nil
 }
 
+func (m *mockUserDataStore) Count(ctx context.Context, userID, subject string, _ interfaces.QueryOptions) (int, error) {
+	records, err := m.List(ctx, userID, subject)
+	return len(records), err
+}
+
 func (m *mockUserDataStore) DeleteBySubject(_ context.Context, _ string) (int, error) {
 	return 0, nil
 }
@@ -87,17 +93,28 @@ func (m *mockStorageManager) PurgeDerivedData(_ context.Context) (map[string]int
 	return nil, nil
 }
 func (m *mockStorageManager) PurgeReports(_ context.Context) (int, error) { return 0, nil }
-func (m *mockStorageManager) Close() error                                { return nil }
+func (m *mockStorageManager) Backup(_ context.Context, _ io.Writer) (*models.BackupManifest, error) {
+	return nil, nil
+}
+func (m *mockStorageManager) Restore(_ context.Context, _ io.Reader) (*models.BackupManifest, error) {
+	return nil, nil
+}
+func (m *mockStorageManager) Close() error { return nil }
 
 // --- Mock portfolio service ---
 
 type mockPortfolioService struct {
-	portfolio *models.Portfolio
+	portfolio    *models.Portfolio
+	fxRates      map[string]float64 // FXDivisor result per currency (default 1)
+	fxCurrencies []string           // currencies passed to FXDivisor
 }
 
 func (m *mockPortfolioService) SyncPortfolio(_ context.Context, _ string, _ bool) (*models.Portfolio, error) {
 	return m.portfolio, nil
 }
+func (m *mockPortfolioService) EnsureSynced(_ context.Context, _ string, _ time.Duration) (*models.Portfolio, error) {
+	return m.portfolio, nil
+}
 func (m *mockPortfolioService) GetPortfolio(_ context.Context, _ string) (*models.Portfolio, error) {
 	if m.portfolio == nil {
 		return nil, fmt.Errorf("portfolio not found")
@@ -110,9 +127,19 @@ func (m *mockPortfolioService) ListPortfolios(_ context.Context) ([]string, erro
 func (m *mockPortfolioService) ReviewPortfolio(_ context.Context, _ string, _ interfaces.ReviewOptions) (*models.PortfolioReview, error) {
 	return nil, nil
 }
+func (m *mockPortfolioService) StreamPortfolioReview(_ context.Context, _ string, _ interfaces.ReviewOptions) (*models.PortfolioReview, <-chan string, <-chan error, error) {
+	return nil, nil, nil, nil
+}
+
 func (m *mockPortfolioService) ReviewWatchlist(_ context.Context, _ string, _ interfaces.ReviewOptions) (*models.WatchlistReview, error) {
 	return nil, nil
 }
+func (m *mockPortfolioService) ListActiveAlerts(_ context.Context, _ string) ([]models.AlertState, error) {
+	return nil, nil
+}
+func (m *mockPortfolioService) AcknowledgeAlert(_ context.Context, _, _, _ string) (*models.AlertState, error) {
+	return nil, nil
+}
 func (m *mockPortfolioService) GetPortfolioSnapshot(_ context.Context, _ string, _ time.Time) (*models.PortfolioSnapshot, error) {
 	return nil, nil
 }
@@ -122,12 +149,62 @@ func (m *mockPortfolioService) GetPortfolioGrowth(_ context.Context, _ string) (
 func (m *mockPortfolioService) GetDailyGrowth(_ context.Context, _ string, _ interfaces.GrowthOptions) ([]models.GrowthDataPoint, error) {
 	return nil, nil
 }
+func (m *mockPortfolioService) GetGrowthChart(_ context.Context, _ string, _ interfaces.GrowthOptions) ([]byte, error) {
+	return nil, nil
+}
+func (m *mockPortfolioService) FXDivisor(_ context.Context, _ *models.Portfolio, currency string) float64 {
+	m.fxCurrencies = append(m.fxCurrencies, currency)
+	if rate := m.fxRates[currency]; rate > 0 {
+		return rate
+	}
+	return 1
+}
 func (m *mockPortfolioService) GetStockTimeline(_ context.Context, _, _ string, _, _ time.Time) ([]models.StockTimelinePoint, error) {
 	return nil, nil
 }
 func (m *mockPortfolioService) GetPortfolioIndicators(_ context.Context, _ string) (*models.PortfolioIndicators, error) {
 	return nil, nil
 }
+func (m *mockPortfolioService) SnapshotPortfolio(_ context.Context, _ string) (*models.DailyPortfolioSnapshot, error) {
+	return nil, nil
+}
+func (m *mockPortfolioService) GetPortfolioHistory(_ context.Context, _ string, _, _ time.Time) ([]models.DailyPortfolioSnapshot, error) {
+	return nil, nil
+}
+
+func (m *mockPortfolioService) GetPortfolioTimeline(_ context.Context, _ string, _ interfaces.GrowthOptions) ([]models.GrowthDataPoint, error) {
+	return nil, nil
+}
+func (m *mockPortfolioService) TimeWeightedReturn(_ context.Context, _, _, _ string) (float64, error) {
+	return 0, nil
+}
+func (m *mockPortfolioService) GetDataCompleteness(_ context.Context, _ string) (*models.DataCompleteness, error) {
+	return nil, nil
+}
+func (m *mockPortfolioService) CalculateCGT(_ context.Context, _, _ string) (*models.CGTReport, error) {
+	return nil, nil
+}
+func (m *mockPortfolioService) RealizedGainTimeline(_ context.Context, _ string) (*models.RealizedGainTimeline, error) {
+	return nil, nil
+}
+func (m *mockPortfolioService) SyncHolding(_ context.Context, _, _ string) (*models.Holding, error) {
+	return nil, nil
+}
+func (m *mockPortfolioService) SimulateTrade(_ context.Context, _ string, _ models.SimulatedTrade) (*models.TradeSimulation, error) {
+	return nil, nil
+}
+func (m *mockPortfolioService) SectorAllocation(_ context.Context, _ string) (*models.SectorBreakdown, error) {
+	return nil, nil
+}
+func (m *mockPortfolioService) FeeSummary(_ context.Context, _ string) (*models.FeeSummary, error) {
+	return nil, nil
+}
+func (m *mockPortfolioService) RebalanceSuggestions(_ context.Context, _ string) (*models.RebalancePlan, error) {
+	return nil, nil
+}
+func (m *mockPortfolioService) ProjectValue(_ context.Context, _ string, _, _ int) (*models.ProjectionResult, error) {
+	return nil, nil
+}
 func (m *mockPortfolioService) RefreshTodaySnapshot(_ context.Context, _ string) error {
 	return nil
 }
@@ -0,0 +1,123 @@
+// Package fx provides currency conversion rates backed by EODHD forex quotes
+package fx
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/interfaces"
+)
+
+// DefaultCacheTTL is how long a fetched rate is reused before it is refreshed.
+const DefaultCacheTTL = 5 * time.Minute
+
+// cachedRate is a fetched rate and when it stops being reused
+type cachedRate struct {
+	rate      float64
+	expiresAt time.Time
+}
+
+// Service implements FXService using EODHD real-time forex quotes.
+// Rates are cached per pair for a short TTL so a sync converting many
+// holdings fetches each pair once.
+type Service struct {
+	eodhd  interfaces.EODHDClient
+	ttl    time.Duration
+	logger *common.Logger
+	now    func() time.Time // injectable clock for testing
+
+	mu    sync.Mutex
+	cache map[string]cachedRate // "BASEQUOTE" -> rate
+}
+
+// NewService creates an FX service reading quotes from eodhd
+func NewService(eodhd interfaces.EODHDClient, logger *common.Logger) *Service {
+	if logger == nil {
+		logger = common.NewSilentLogger()
+	}
+	return &Service{
+		eodhd:  eodhd,
+		ttl:    DefaultCacheTTL,
+		logger: logger,
+		now:    time.Now,
+		cache:  make(map[string]cachedRate),
+	}
+}
+
+// SetCacheTTL overrides how long rates are cached. Non-positive disables caching.
+func (s *Service) SetCacheTTL(ttl time.Duration) {
+	s.ttl = ttl
+}
+
+// Rate returns how many units of quote one unit of base buys. It reads the
+// BASEQUOTE.FOREX quote, falling back to the inverse of QUOTEBASE.FOREX when
+// EODHD has no direct pair. The same currency always returns 1.
+func (s *Service) Rate(ctx context.Context, base, quote string) (float64, error) {
+	base = strings.ToUpper(strings.TrimSpace(base))
+	quote = strings.ToUpper(strings.TrimSpace(quote))
+	if base == "" || quote == "" {
+		return 0, fmt.Errorf("fx rate requires both currencies (got %q/%q)", base, quote)
+	}
+	if base == quote {
+		return 1, nil
+	}
+
+	pair := base + quote
+	if rate, ok := s.cached(pair); ok {
+		return rate, nil
+	}
+
+	rate, err := s.fetch(ctx, pair)
+	if err != nil {
+		inverse, invErr := s.fetch(ctx, quote+base)
+		if invErr != nil {
+			return 0, fmt.Errorf("no %s/%s rate: %w", base, quote, err)
+		}
+		rate = 1 / inverse
+	}
+
+	s.store(pair, rate)
+	s.logger.WithRequestID(ctx).Debug().Str("pair", pair).Float64("rate", rate).Msg("Fetched FX rate")
+	return rate, nil
+}
+
+// fetch reads the close of pair's forex quote
+func (s *Service) fetch(ctx context.Context, pair string) (float64, error) {
+	if s.eodhd == nil {
+		return 0, fmt.Errorf("EODHD client not configured")
+	}
+	q, err := s.eodhd.GetRealTimeQuote(ctx, pair+".FOREX")
+	if err != nil {
+		return 0, err
+	}
+	if q == nil || q.Close <= 0 {
+		return 0, fmt.Errorf("%s.FOREX returned no price", pair)
+	}
+	return q.Close, nil
+}
+
+func (s *Service) cached(pair string) (float64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.cache[pair]
+	if !ok || !s.now().Before(c.expiresAt) {
+		return 0, false
+	}
+	return c.rate, true
+}
+
+func (s *Service) store(pair string, rate float64) {
+	if s.ttl <= 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cache[pair] = cachedRate{rate: rate, expiresAt: s.now().Add(s.ttl)}
+}
+
+// Ensure Service implements FXService
+var _ interfaces.FXService = (*Service)(nil)
@@ -0,0 +1,93 @@
+package fx
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/interfaces"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// quoteClient serves forex quotes from a fixed table and counts requests
+type quoteClient struct {
+	interfaces.EODHDClient
+	closes map[string]float64
+	calls  int
+}
+
+func (c *quoteClient) GetRealTimeQuote(_ context.Context, ticker string) (*models.RealTimeQuote, error) {
+	c.calls++
+	if v, ok := c.closes[ticker]; ok {
+		return &models.RealTimeQuote{Code: ticker, Close: v}, nil
+	}
+	return nil, fmt.Errorf("ticker %s not found", ticker)
+}
+
+func TestRate_DirectPair(t *testing.T) {
+	svc := NewService(&quoteClient{closes: map[string]float64{"AUDUSD.FOREX": 0.65}}, nil)
+
+	rate, err := svc.Rate(context.Background(), "aud", "USD")
+	if err != nil {
+		t.Fatalf("Rate failed: %v", err)
+	}
+	if rate != 0.65 {
+		t.Errorf("rate = %v, want 0.65", rate)
+	}
+}
+
+func TestRate_InvertsReversePair(t *testing.T) {
+	svc := NewService(&quoteClient{closes: map[string]float64{"EURGBP.FOREX": 0.8}}, nil)
+
+	rate, err := svc.Rate(context.Background(), "GBP", "EUR")
+	if err != nil {
+		t.Fatalf("Rate failed: %v", err)
+	}
+	if math.Abs(rate-1.25) > 1e-9 {
+		t.Errorf("rate = %v, want 1.25 (inverse of EURGBP 0.8)", rate)
+	}
+}
+
+func TestRate_SameCurrencyIsOne(t *testing.T) {
+	client := &quoteClient{}
+	svc := NewService(client, nil)
+
+	rate, err := svc.Rate(context.Background(), "GBP", "gbp")
+	if err != nil || rate != 1 {
+		t.Errorf("Rate = %v, %v; want 1, nil", rate, err)
+	}
+	if client.calls != 0 {
+		t.Errorf("calls = %d, want no quote fetched", client.calls)
+	}
+}
+
+func TestRate_MissingPairErrors(t *testing.T) {
+	svc := NewService(&quoteClient{}, nil)
+
+	if _, err := svc.Rate(context.Background(), "GBP", "JPY"); err == nil {
+		t.Error("expected an error when neither pair is quoted")
+	}
+}
+
+func TestRate_CachesUntilTTL(t *testing.T) {
+	client := &quoteClient{closes: map[string]float64{"AUDUSD.FOREX": 0.65}}
+	svc := NewService(client, nil)
+	now := time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	svc.Rate(ctx, "AUD", "USD")
+	svc.Rate(ctx, "AUD", "USD")
+	if client.calls != 1 {
+		t.Fatalf("calls = %d, want 1 within the TTL", client.calls)
+	}
+
+	client.closes["AUDUSD.FOREX"] = 0.66
+	now = now.Add(DefaultCacheTTL)
+	rate, _ := svc.Rate(ctx, "AUD", "USD")
+	if client.calls != 2 || rate != 0.66 {
+		t.Errorf("after TTL: calls = %d, rate = %v; want a refetched 0.66", client.calls, rate)
+	}
+}
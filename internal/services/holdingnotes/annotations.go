@@ -0,0 +1,113 @@
+package holdingnotes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/interfaces"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// annotationSubject stores one record per portfolio and ticker, keyed
+// "{portfolio}:{TICKER}".
+const annotationSubject = "holding_annotations"
+
+func annotationKey(portfolioName, ticker string) string {
+	return portfolioName + ":" + strings.ToUpper(ticker)
+}
+
+// GetAnnotations returns a portfolio's holding annotations keyed by
+// upper-case ticker. A portfolio without annotations yields an empty map.
+func (s *Service) GetAnnotations(ctx context.Context, portfolioName string) (map[string]*models.HoldingAnnotation, error) {
+	userID := common.ResolveUserID(ctx)
+	records, err := s.storage.UserDataStore().Query(ctx, userID, annotationSubject, interfaces.QueryOptions{
+		KeyPrefix: portfolioName + ":",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list holding annotations: %w", err)
+	}
+	annotations := make(map[string]*models.HoldingAnnotation)
+	for _, rec := range records {
+		var a models.HoldingAnnotation
+		if err := json.Unmarshal([]byte(rec.Value), &a); err != nil {
+			s.logger.WithRequestID(ctx).Warn().Err(err).Str("key", rec.Key).Msg("Skipping corrupt holding annotation")
+			continue
+		}
+		if a.PortfolioName == portfolioName { // the prefix also matches names containing ":"
+			annotations[strings.ToUpper(a.Ticker)] = &a
+		}
+	}
+	return annotations, nil
+}
+
+// SetHoldingNote sets a holding's user note, keeping its tags. An empty
+// note clears it.
+func (s *Service) SetHoldingNote(ctx context.Context, portfolioName, ticker, note string) (*models.HoldingAnnotation, error) {
+	return s.updateAnnotation(ctx, portfolioName, ticker, func(a *models.HoldingAnnotation) {
+		a.Note = strings.TrimSpace(note)
+	})
+}
+
+// SetHoldingTags replaces a holding's tags, keeping its note. Tags are
+// trimmed and de-duplicated case-insensitively; an empty list clears them.
+func (s *Service) SetHoldingTags(ctx context.Context, portfolioName, ticker string, tags []string) (*models.HoldingAnnotation, error) {
+	return s.updateAnnotation(ctx, portfolioName, ticker, func(a *models.HoldingAnnotation) {
+		a.Tags = normalizeTags(tags)
+	})
+}
+
+// updateAnnotation applies fn to the stored annotation (or a new one) and
+// saves it, deleting the record once both note and tags are empty.
+func (s *Service) updateAnnotation(ctx context.Context, portfolioName, ticker string, fn func(*models.HoldingAnnotation)) (*models.HoldingAnnotation, error) {
+	userID := common.ResolveUserID(ctx)
+	store := s.storage.UserDataStore()
+	key := annotationKey(portfolioName, ticker)
+
+	a := &models.HoldingAnnotation{PortfolioName: portfolioName, Ticker: strings.ToUpper(ticker)}
+	if rec, err := store.Get(ctx, userID, annotationSubject, key); err == nil {
+		if err := json.Unmarshal([]byte(rec.Value), a); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal holding annotation: %w", err)
+		}
+	}
+	fn(a)
+	a.UpdatedAt = time.Now()
+
+	if a.Note == "" && len(a.Tags) == 0 {
+		if err := store.Delete(ctx, userID, annotationSubject, key); err != nil {
+			return nil, fmt.Errorf("failed to clear holding annotation: %w", err)
+		}
+		return a, nil
+	}
+
+	data, err := json.Marshal(a)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal holding annotation: %w", err)
+	}
+	if err := store.Put(ctx, &models.UserRecord{
+		UserID:  userID,
+		Subject: annotationSubject,
+		Key:     key,
+		Value:   string(data),
+	}); err != nil {
+		return nil, fmt.Errorf("failed to save holding annotation: %w", err)
+	}
+	return a, nil
+}
+
+func normalizeTags(tags []string) []string {
+	var out []string
+	seen := make(map[string]bool, len(tags))
+	for _, t := range tags {
+		t = strings.TrimSpace(t)
+		if t == "" || seen[strings.ToLower(t)] {
+			continue
+		}
+		seen[strings.ToLower(t)] = true
+		out = append(out, t)
+	}
+	return out
+}
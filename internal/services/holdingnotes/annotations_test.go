@@ -0,0 +1,80 @@
+package holdingnotes
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSetHoldingNote_KeepsTags(t *testing.T) {
+	svc := testService()
+	ctx := testContext()
+
+	if _, err := svc.SetHoldingTags(ctx, "SMSF", "bhp.au", []string{"core"}); err != nil {
+		t.Fatalf("SetHoldingTags: %v", err)
+	}
+	a, err := svc.SetHoldingNote(ctx, "SMSF", "BHP.AU", "  core position  ")
+	if err != nil {
+		t.Fatalf("SetHoldingNote: %v", err)
+	}
+	if a.Note != "core position" {
+		t.Errorf("Note = %q, want %q", a.Note, "core position")
+	}
+	if !reflect.DeepEqual(a.Tags, []string{"core"}) {
+		t.Errorf("Tags = %v, want [core]", a.Tags)
+	}
+
+	got, err := svc.GetAnnotations(ctx, "SMSF")
+	if err != nil {
+		t.Fatalf("GetAnnotations: %v", err)
+	}
+	if got["BHP.AU"] == nil || got["BHP.AU"].Note != "core position" {
+		t.Errorf("GetAnnotations[BHP.AU] = %+v", got["BHP.AU"])
+	}
+}
+
+func TestSetHoldingTags_Normalizes(t *testing.T) {
+	svc := testService()
+
+	a, err := svc.SetHoldingTags(testContext(), "SMSF", "CBA.AU", []string{" core ", "Income", "", "CORE", "income", "trim"})
+	if err != nil {
+		t.Fatalf("SetHoldingTags: %v", err)
+	}
+	want := []string{"core", "Income", "trim"}
+	if !reflect.DeepEqual(a.Tags, want) {
+		t.Errorf("Tags = %v, want %v", a.Tags, want)
+	}
+}
+
+func TestSetHoldingNote_EmptyClearsRecord(t *testing.T) {
+	svc := testService()
+	ctx := testContext()
+
+	if _, err := svc.SetHoldingNote(ctx, "SMSF", "BHP.AU", "trimming"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.SetHoldingNote(ctx, "SMSF", "BHP.AU", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	got, _ := svc.GetAnnotations(ctx, "SMSF")
+	if len(got) != 0 {
+		t.Errorf("expected no annotations after clearing, got %v", got)
+	}
+}
+
+func TestGetAnnotations_ScopedToPortfolio(t *testing.T) {
+	svc := testService()
+	ctx := testContext()
+
+	svc.SetHoldingNote(ctx, "SMSF", "BHP.AU", "smsf note")
+	svc.SetHoldingNote(ctx, "Personal", "BHP.AU", "personal note")
+	svc.SetHoldingNote(ctx, "SMSF:Old", "CBA.AU", "shares the key prefix")
+
+	got, err := svc.GetAnnotations(ctx, "SMSF")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got["BHP.AU"].Note != "smsf note" {
+		t.Errorf("GetAnnotations(SMSF) = %+v", got)
+	}
+}
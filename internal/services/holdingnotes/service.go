@@ -48,7 +48,7 @@ func (s *Service) SaveNotes(ctx context.Context, notes *models.PortfolioHoldingN
 	if err := s.saveNotesRecord(ctx, notes); err != nil {
 		return fmt.Errorf("failed to save holding notes: %w", err)
 	}
-	s.logger.Info().Str("portfolio", notes.PortfolioName).Msg("Holding notes saved")
+	s.logger.WithRequestID(ctx).Info().Str("portfolio", notes.PortfolioName).Msg("Holding notes saved")
 	return nil
 }
 
@@ -145,6 +145,9 @@ func (s *Service) UpdateNote(ctx context.Context, portfolioName, ticker string,
 	if update.StaleDays != 0 {
 		existing.StaleDays = update.StaleDays
 	}
+	if update.AlertsMuted != nil {
+		existing.AlertsMuted = update.AlertsMuted
+	}
 
 	existing.ReviewedAt = now
 	existing.UpdatedAt = now
@@ -3,6 +3,8 @@ package holdingnotes
 import (
 	"context"
 	"fmt"
+	"io"
+	"strings"
 	"testing"
 	"time"
 
@@ -58,7 +60,13 @@ func (m *testStorageManager) PurgeDerivedData(_ context.Context) (map[string]int
 	return nil, nil
 }
 func (m *testStorageManager) PurgeReports(_ context.Context) (int, error) { return 0, nil }
-func (m *testStorageManager) Close() error                                { return nil }
+func (m *testStorageManager) Backup(_ context.Context, _ io.Writer) (*models.BackupManifest, error) {
+	return nil, nil
+}
+func (m *testStorageManager) Restore(_ context.Context, _ io.Reader) (*models.BackupManifest, error) {
+	return nil, nil
+}
+func (m *testStorageManager) Close() error { return nil }
 
 // testUserDataStore is a simple in-memory implementation.
 type testUserDataStore struct {
@@ -109,8 +117,19 @@ func (s *testUserDataStore) List(_ context.Context, userID, subject string) ([]*
 	return result, nil
 }
 
-func (s *testUserDataStore) Query(_ context.Context, userID, subject string, _ interfaces.QueryOptions) ([]*models.UserRecord, error) {
-	return s.List(context.Background(), userID, subject)
+func (s *testUserDataStore) Query(_ context.Context, userID, subject string, opts interfaces.QueryOptions) ([]*models.UserRecord, error) {
+	var result []*models.UserRecord
+	for _, r := range s.records {
+		if r.UserID == userID && r.Subject == subject && strings.HasPrefix(r.Key, opts.KeyPrefix) {
+			result = append(result, r)
+		}
+	}
+	return result, nil
+}
+
+func (s *testUserDataStore) Count(ctx context.Context, userID, subject string, _ interfaces.QueryOptions) (int, error) {
+	records, err := s.List(ctx, userID, subject)
+	return len(records), err
 }
 
 func (s *testUserDataStore) DeleteBySubject(_ context.Context, subject string) (int, error) {
@@ -686,3 +705,30 @@ func TestPortfolioHoldingNotes_NoteMap_Empty(t *testing.T) {
 		t.Errorf("expected empty map, got %d entries", len(m))
 	}
 }
+
+func TestUpdateNote_AlertsMuted(t *testing.T) {
+	svc := testService()
+	ctx := testContext()
+
+	_, _ = svc.AddOrUpdateNote(ctx, "SMSF", &models.HoldingNote{Ticker: "VAS.AU", Thesis: "Core index"})
+
+	muted, unmuted := true, false
+	result, err := svc.UpdateNote(ctx, "SMSF", "VAS.AU", &models.HoldingNote{AlertsMuted: &muted})
+	if err != nil {
+		t.Fatalf("UpdateNote: %v", err)
+	}
+	if !result.Items[0].Muted() {
+		t.Fatal("expected note to be muted")
+	}
+
+	// An update without alerts_muted keeps the mute
+	result, _ = svc.UpdateNote(ctx, "SMSF", "VAS.AU", &models.HoldingNote{Notes: "still core"})
+	if !result.Items[0].Muted() {
+		t.Error("mute should survive an unrelated update")
+	}
+
+	result, _ = svc.UpdateNote(ctx, "SMSF", "VAS.AU", &models.HoldingNote{AlertsMuted: &unmuted})
+	if result.Items[0].Muted() {
+		t.Error("expected alerts_muted=false to unmute")
+	}
+}
@@ -0,0 +1,68 @@
+package jobmanager
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// eodCompactionLastRunKey is the InternalStore system key holding when the
+// last EOD compaction job was enqueued (RFC 3339).
+const eodCompactionLastRunKey = "eod_compaction_last_run"
+
+// eodCompactionInterval is how often the compaction pass is enqueued.
+const eodCompactionInterval = 24 * time.Hour
+
+// checkEODCompaction enqueues a compact_eod job when EOD retention is
+// configured and the last pass was enqueued more than a day ago.
+func (jm *JobManager) checkEODCompaction(ctx context.Context, now time.Time) {
+	if jm.config.GetEODDailyRetention() <= 0 {
+		return
+	}
+
+	store := jm.storage.InternalStore()
+	if last, err := store.GetSystemKV(ctx, eodCompactionLastRunKey); err == nil && last != "" {
+		if lastRun, err := time.Parse(time.RFC3339, last); err == nil && now.Sub(lastRun) < eodCompactionInterval {
+			return
+		}
+	}
+
+	if _, err := jm.EnqueueIfNeeded(ctx, models.JobTypeCompactEOD, "", models.PriorityCompactEOD); err != nil {
+		jm.logger.Warn().Err(err).Msg("Watcher: failed to enqueue EOD compaction")
+		return
+	}
+	if err := store.SetSystemKV(ctx, eodCompactionLastRunKey, now.Format(time.RFC3339)); err != nil {
+		jm.logger.Warn().Err(err).Msg("Watcher: failed to record EOD compaction run")
+	}
+}
+
+// compactEOD downsamples old EOD history for every ticker in the stock index.
+// A ticker that fails is logged and skipped so one bad record does not stop
+// the pass.
+func (jm *JobManager) compactEOD(ctx context.Context) error {
+	keepDaily := jm.config.GetEODDailyRetention()
+	if keepDaily <= 0 {
+		return nil
+	}
+	entries, err := jm.storage.StockIndexStore().List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list stock index: %w", err)
+	}
+
+	removed := 0
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		n, err := jm.market.CompactEOD(ctx, entry.Ticker, keepDaily)
+		if err != nil {
+			jm.logger.Warn().Err(err).Str("ticker", entry.Ticker).Msg("EOD compaction failed")
+			continue
+		}
+		removed += n
+	}
+	jm.logger.Info().Int("tickers", len(entries)).Int("bars_removed", removed).Msg("EOD compaction complete")
+	return nil
+}
@@ -0,0 +1,55 @@
+package jobmanager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+func TestCheckEODCompaction_EnqueuesDailyAndCompactsEachTicker(t *testing.T) {
+	queue := newMockJobQueueStore()
+	stockIdx := newMockStockIndexStore()
+	ctx := context.Background()
+	stockIdx.Upsert(ctx, &models.StockIndexEntry{Ticker: "BHP.AU"})
+	stockIdx.Upsert(ctx, &models.StockIndexEntry{Ticker: "CBA.AU"})
+
+	jm := newTestJobManager(queue, stockIdx)
+	market := jm.market.(*mockMarketService)
+	now := time.Date(2026, 3, 4, 9, 0, 0, 0, time.UTC)
+
+	// Disabled by default
+	jm.checkEODCompaction(ctx, now)
+	if n, _ := queue.CountPending(ctx); n != 0 {
+		t.Fatalf("pending = %d with compaction off, want 0", n)
+	}
+
+	jm.config.EODDailyDays = 730
+	jm.checkEODCompaction(ctx, now)
+	jm.checkEODCompaction(ctx, now.Add(time.Hour)) // within the day: no second job
+	job, _ := queue.Dequeue(ctx)
+	if job == nil || job.JobType != models.JobTypeCompactEOD {
+		t.Fatalf("dequeued %+v, want a compact_eod job", job)
+	}
+	if next, _ := queue.Dequeue(ctx); next != nil {
+		t.Errorf("second job %s enqueued within a day", next.JobType)
+	}
+
+	if err := jm.executeJob(ctx, job); err != nil {
+		t.Fatalf("executeJob: %v", err)
+	}
+	market.mu.Lock()
+	calls := market.collectCalls[models.JobTypeCompactEOD]
+	market.mu.Unlock()
+	if calls != 2 {
+		t.Errorf("CompactEOD called %d times, want 2 (one per stock index ticker)", calls)
+	}
+
+	// Next day the pass is enqueued again
+	queue.Complete(ctx, job.ID, nil, 0)
+	jm.checkEODCompaction(ctx, now.Add(25*time.Hour))
+	if n, _ := queue.CountPending(ctx); n != 1 {
+		t.Errorf("pending = %d a day later, want 1", n)
+	}
+}
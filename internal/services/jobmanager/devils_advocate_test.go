@@ -3,6 +3,8 @@ package jobmanager
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -12,23 +14,22 @@ import (
 	"github.com/bobmcallan/vire/internal/common"
 	"github.com/bobmcallan/vire/internal/interfaces"
 	"github.com/bobmcallan/vire/internal/models"
+	"github.com/gorilla/websocket"
 )
 
 // Devils-advocate stress tests: security, edge cases, failure modes, race conditions.
 // Reuses mocks from manager_test.go (same package).
 
 // ============================================================================
-// DA-1. CRITICAL: Job retry logic is broken — failed jobs are never re-queued
+// DA-1. Job retry — failed jobs with attempts remaining are re-queued
 // ============================================================================
 //
-// In processLoop, when a job fails and attempts < maxAttempts, the code logs
-// "Re-queuing failed job" but never actually re-enqueues it. The job is marked
-// as failed via jm.complete(), and that's it. No retry ever happens.
-//
-// EXPECTED: Failed job with attempts < maxAttempts should be re-enqueued as pending.
-// ACTUAL: Failed job is marked as failed and abandoned.
+// processLoop used to log "Re-queuing failed job" without re-enqueuing, so a
+// failed job was abandoned even with attempts remaining. A failed job under
+// MaxAttempts must go back to pending (keeping its attempt count, with a retry
+// backoff) instead of being completed, and finish once an attempt succeeds.
 
-func TestDA_RetryLogic_BrokenNoRequeue(t *testing.T) {
+func TestDA_RetryLogic_RequeuesUntilSuccess(t *testing.T) {
 	failCount := atomic.Int64{}
 	failingMarket := &failingMarketService{
 		mockMarketService: newMockMarketService(),
@@ -66,36 +67,45 @@ func TestDA_RetryLogic_BrokenNoRequeue(t *testing.T) {
 	jm.wg.Add(1)
 	go func() { defer jm.wg.Done(); jm.processLoop(jmCtx) }()
 
-	// Wait for job processing
-	time.Sleep(3 * time.Second)
+	// Retries wait out a 1s then 2s backoff, so allow a generous deadline
+	status := func() (string, int, int) {
+		queue.mu.Lock()
+		defer queue.mu.Unlock()
+		n := 0
+		var job *models.Job
+		for _, j := range queue.jobs {
+			if j.ID == "retry-test" {
+				job = j
+				n++
+			}
+		}
+		if job == nil {
+			return "", 0, n
+		}
+		return job.Status, job.Attempts, n
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		if st, _, _ := status(); st == models.JobStatusCompleted || st == models.JobStatusFailed {
+			break
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
 	jmCancel()
 	jm.wg.Wait()
 
-	// Check: the job should have been retried and eventually completed.
-	// BUG: it was only attempted once and marked as failed.
-	queue.mu.Lock()
-	var job *models.Job
-	for _, j := range queue.jobs {
-		if j.ID == "retry-test" {
-			job = j
-		}
+	st, attempts, records := status()
+	if st != models.JobStatusCompleted {
+		t.Fatalf("job status = %q, want %q after retries", st, models.JobStatusCompleted)
 	}
-	queue.mu.Unlock()
-
-	if job == nil {
-		t.Fatal("job not found")
+	if attempts != 3 {
+		t.Errorf("attempts = %d, want 3 (two failures then success)", attempts)
 	}
-
-	// With working retry logic, the job should complete on attempt 3.
-	// With the current bug, the job is marked as failed after attempt 1.
-	if job.Status == models.JobStatusFailed {
-		t.Log("CONFIRMED BUG: Job marked as failed without retry. " +
-			"processLoop logs 'Re-queuing failed job' but never actually re-enqueues. " +
-			"Fix: after calling jm.complete(), check attempts < maxAttempts and call jm.enqueue() " +
-			"to create a new pending job for the same type+ticker.")
+	if got := failCount.Load(); got != 3 {
+		t.Errorf("CollectEOD called %d times, want 3", got)
 	}
-	if job.Status == models.JobStatusCompleted {
-		t.Log("Retry logic is working correctly")
+	if records != 1 {
+		t.Errorf("queue holds %d records for the job, want 1 (re-enqueue must upsert)", records)
 	}
 }
 
@@ -115,14 +125,34 @@ func (f *failingMarketService) CollectEOD(_ context.Context, _ string, _ bool) e
 }
 
 // ============================================================================
-// DA-2. CRITICAL: WebSocket hub Run() goroutine leaks on Stop()
+// DA-2. WebSocket hub Run() exits on Stop()
 // ============================================================================
 //
-// hub.Run() runs an infinite for-select with no context or done channel.
-// When Stop() is called, it cancels the watcher and processor loops but
-// the hub goroutine continues running forever.
+// hub.Run() used to be an infinite for-select with no exit path, so the hub
+// goroutine outlived JobManager.Stop(). Stop() now closes a done channel that
+// Run() selects on; calling it twice must not panic, and Start() after Stop()
+// must bring the hub back.
 
-func TestDA_WebSocketHub_NeverStops(t *testing.T) {
+func TestDA_WebSocketHub_StopExitsRun(t *testing.T) {
+	hub := NewJobWSHub(common.NewLogger("error"))
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() { defer wg.Done(); hub.Run() }()
+
+	hub.Stop()
+	hub.Stop() // second call must not panic
+
+	exited := make(chan struct{})
+	go func() { wg.Wait(); close(exited) }()
+	select {
+	case <-exited:
+	case <-time.After(2 * time.Second):
+		t.Fatal("hub.Run() still running after Stop()")
+	}
+}
+
+func TestDA_WebSocketHub_JobManagerStopAndRestart(t *testing.T) {
 	store := &mockStorageManager{
 		internal:   &mockInternalStore{kv: make(map[string]string)},
 		market:     &mockMarketDataStorage{data: make(map[string]*models.MarketData)},
@@ -135,43 +165,91 @@ func TestDA_WebSocketHub_NeverStops(t *testing.T) {
 	jm := NewJobManager(
 		newMockMarketService(), &mockSignalService{}, store,
 		common.NewLogger("error"),
-		common.JobManagerConfig{Enabled: true, WatcherInterval: "1h", MaxConcurrent: 1},
+		common.JobManagerConfig{Enabled: true, WatcherInterval: "1h", WatcherStartupDelay: "1h", MaxConcurrent: 1},
 	)
+	srv := httptest.NewServer(http.HandlerFunc(jm.Hub().ServeWS))
+	defer srv.Close()
+	wsURL := "ws" + strings.TrimPrefix(srv.URL, "http")
+
+	stopWithin := func(d time.Duration) {
+		t.Helper()
+		done := make(chan struct{})
+		go func() { jm.Stop(); close(done) }()
+		select {
+		case <-done:
+		case <-time.After(d):
+			t.Fatal("JobManager.Stop() did not return — hub goroutine still running")
+		}
+	}
 
 	jm.Start()
-	jm.Stop()
+	stopWithin(2 * time.Second)
 
-	// After Stop(), the hub goroutine is still running.
-	// This test documents the leak. We can't easily assert it without runtime
-	// goroutine inspection, but the architecture shows hub.Run() has no exit path.
-	t.Log("CONFIRMED: hub.Run() has no shutdown mechanism. " +
-		"Fix: add a done channel to JobWSHub, select on it in Run(), " +
-		"close it in a new hub.Stop() method called from JobManager.Stop().")
+	// Restart: the hub must accept clients again
+	jm.Start()
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	if err != nil {
+		t.Fatalf("dial after restart: %v", err)
+	}
+	defer conn.Close()
+	deadline := time.Now().Add(2 * time.Second)
+	for jm.Hub().ClientCount() != 1 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := jm.Hub().ClientCount(); n != 1 {
+		t.Fatalf("clients after restart = %d, want 1", n)
+	}
+
+	stopWithin(2 * time.Second)
+
+	// Stopping the hub closes connected clients
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	if _, _, err := conn.ReadMessage(); err == nil {
+		t.Error("expected the connection to close after Stop()")
+	} else if ne, ok := err.(interface{ Timeout() bool }); ok && ne.Timeout() {
+		t.Error("client connection left open after Stop()")
+	}
+	if n := jm.Hub().ClientCount(); n != 0 {
+		t.Errorf("clients after stop = %d, want 0", n)
+	}
 }
 
 // ============================================================================
-// DA-3. Race condition in WebSocket hub broadcast — RLock to Lock upgrade
+// DA-3. WebSocket hub broadcast evicts slow clients without a lock upgrade
 // ============================================================================
 //
-// In hub.Run(), the broadcast case holds h.mu.RLock() while iterating clients.
-// When a slow client is found, it drops RLock, acquires Lock, modifies the map,
-// drops Lock, re-acquires RLock, and continues iterating.
-//
-// Problem: Between RUnlock and Lock, another goroutine could modify h.clients,
-// invalidating the range iteration. Also, multiple goroutines holding RLock
-// that both try to upgrade to Lock will deadlock.
+// The broadcast case used to drop RLock, take Lock to delete a slow client and
+// re-take RLock mid-range. It now collects slow clients under RLock and removes
+// them in one Lock block after iteration. A full client is still evicted (its
+// send channel closed) and other clients still receive the event.
 
-func TestDA_WebSocketHub_BroadcastLockUpgrade(t *testing.T) {
-	logger := common.NewLogger("error")
-	hub := NewJobWSHub(logger)
-	go hub.Run()
+func TestDA_WebSocketHub_BroadcastEvictsSlowClient(t *testing.T) {
+	hub := NewJobWSHub(common.NewLogger("error"))
+
+	slow := &JobWSClient{hub: hub, send: make(chan []byte, 1)}
+	fast := &JobWSClient{hub: hub, send: make(chan []byte, 8)}
+	hub.clients[slow] = true
+	hub.clients[fast] = true
+
+	hub.deliver([]byte("one"))
+	hub.deliver([]byte("two")) // slow's buffer is full
+
+	if hub.ClientCount() != 1 || !hub.clients[fast] {
+		t.Fatalf("clients = %d, want only the fast client left", hub.ClientCount())
+	}
+	if len(fast.send) != 2 {
+		t.Errorf("fast client got %d messages, want 2", len(fast.send))
+	}
+	<-slow.send // buffered "one"
+	if _, ok := <-slow.send; ok {
+		t.Error("slow client's send channel should be closed on eviction")
+	}
 
-	// This test documents the race condition. The fix is to collect
-	// slow clients during the RLock iteration and remove them after.
-	t.Log("RACE CONDITION: broadcast case in hub.Run() performs RLock->RUnlock->Lock->Unlock->RLock " +
-		"sequence during iteration, which is unsafe for concurrent access. " +
-		"Fix: collect stale clients in a slice during RLock iteration, " +
-		"then remove them in a separate Lock block after iteration completes.")
+	// A later broadcast must not touch the evicted client again
+	hub.deliver([]byte("three"))
+	if len(fast.send) != 3 {
+		t.Errorf("fast client got %d messages, want 3", len(fast.send))
+	}
 }
 
 // ============================================================================
@@ -678,63 +756,115 @@ func TestDA_UpdateTimestamp_InvalidField(t *testing.T) {
 }
 
 // ============================================================================
-// DA-14. HasPendingJob does not check running jobs
+// DA-14. Dedup must consider running jobs
 // ============================================================================
 //
-// HasPendingJob only checks status=pending. If a job is currently running,
-// a new identical pending job can be enqueued. When the running job completes,
-// the watcher will also find the component is now fresh, but the pending
-// duplicate will still be processed, wasting resources.
+// HasPendingJob only checks status=pending, so a job running for the same
+// type+ticker used to let EnqueueIfNeeded queue a duplicate that re-ran once
+// the first finished. EnqueueIfNeeded now uses HasActiveJob (pending or
+// running); HasPendingJob keeps its pending-only semantics.
 
-func TestDA_HasPendingJob_IgnoresRunning(t *testing.T) {
+func TestDA_EnqueueIfNeeded_SkipsRunningJob(t *testing.T) {
 	queue := newMockJobQueueStore()
+	jm := newTestJobManager(queue, newMockStockIndexStore())
 	ctx := context.Background()
 
-	// Enqueue and start running
-	queue.Enqueue(ctx, &models.Job{
-		ID:       "r1",
-		JobType:  models.JobTypeCollectEOD,
-		Ticker:   "BHP.AU",
-		Priority: 10,
-	})
+	if _, err := jm.EnqueueIfNeeded(ctx, models.JobTypeCollectEOD, "BHP.AU", 10); err != nil {
+		t.Fatalf("EnqueueIfNeeded: %v", err)
+	}
 	queue.Dequeue(ctx) // marks as running
 
-	// Check if pending exists — should be false (job is running, not pending)
-	has, _ := queue.HasPendingJob(ctx, models.JobTypeCollectEOD, "BHP.AU")
-	if has {
-		t.Error("HasPendingJob should return false when job is running, not pending")
+	if has, _ := queue.HasPendingJob(ctx, models.JobTypeCollectEOD, "BHP.AU"); has {
+		t.Error("HasPendingJob should return false when the job is running, not pending")
+	}
+	if has, _ := queue.HasActiveJob(ctx, models.JobTypeCollectEOD, "BHP.AU"); !has {
+		t.Error("HasActiveJob should return true for a running job")
 	}
 
-	// This means EnqueueIfNeeded will create a DUPLICATE job for the same type+ticker.
-	// The running job will complete, and then the duplicate will also run.
-	t.Log("INFO: HasPendingJob only checks pending status. A running job for the same " +
-		"type+ticker allows a duplicate to be enqueued. Consider also checking status=running " +
-		"in HasPendingJob or in the dedup logic.")
+	added, err := jm.EnqueueIfNeeded(ctx, models.JobTypeCollectEOD, "BHP.AU", 10)
+	if err != nil {
+		t.Fatalf("EnqueueIfNeeded: %v", err)
+	}
+	if added {
+		t.Error("EnqueueIfNeeded reported enqueued while the job is running")
+	}
+	queue.mu.Lock()
+	n := len(queue.jobs)
+	queue.mu.Unlock()
+	if n != 1 {
+		t.Errorf("jobs = %d, want 1 (no duplicate behind the running job)", n)
+	}
 }
 
 // ============================================================================
-// DA-15. CRASH PROTECTION: No panic recovery on processLoop goroutines
+// DA-15. CRASH PROTECTION: panic recovery in processLoop
 // ============================================================================
 //
-// If executeJob panics (e.g. nil pointer in a market service method), the
-// processLoop goroutine dies permanently. With 5 processors, each panic
-// reduces throughput by 20% until all goroutines are dead.
-
-func TestDA_ProcessLoop_PanicKillsGoroutine(t *testing.T) {
-	// This test documents the finding without actually crashing the test process.
-	// processLoop has no panic recovery: a panic in executeJob propagates up
-	// and kills the goroutine. With the current code, this means the goroutine
-	// crashes entirely (and in tests, panics the whole process).
-	//
-	// After task #2 implements safeGo with panic recovery, this test can be
-	// modified to verify that the goroutine survives the panic and continues.
-	//
-	// We verify the finding structurally: processLoop does not have a
-	// recover() call, and does not use safeGo.
-	t.Log("CONFIRMED: processLoop has no panic recovery. A panic in executeJob " +
-		"kills the goroutine permanently (verified by attempting to run with a " +
-		"panicking market service — the panic propagates and kills the test). " +
-		"Fix: wrap processLoop goroutines in safeGo with defer/recover.")
+// A panic in executeJob (e.g. nil pointer in a market service method) used to
+// kill the processLoop goroutine permanently; with 5 processors each panic cost
+// 20% of throughput. The panic is now recovered per job: the job fails (so
+// retry and backoff apply) and the processor keeps dequeuing.
+
+func TestDA_ProcessLoop_SurvivesPanic(t *testing.T) {
+	market := &panicMarketService{mockMarketService: newMockMarketService()}
+	queue := newMockJobQueueStore()
+	store := &mockStorageManager{
+		internal:   &mockInternalStore{kv: make(map[string]string)},
+		market:     &mockMarketDataStorage{data: make(map[string]*models.MarketData)},
+		stockIndex: newMockStockIndexStore(),
+		jobQueue:   queue,
+		files:      newMockFileStore(),
+		signals:    newMockSignalStorage(),
+	}
+
+	ctx := context.Background()
+	queue.Enqueue(ctx, &models.Job{ID: "panics", JobType: models.JobTypeCollectEOD, Ticker: "BAD.AU", Priority: 10, MaxAttempts: 2})
+	queue.Enqueue(ctx, &models.Job{ID: "after", JobType: models.JobTypeCollectFundamentals, Ticker: "OK.AU", Priority: 5, MaxAttempts: 1})
+
+	jm := NewJobManager(market, &mockSignalService{}, store, common.NewLogger("error"),
+		common.JobManagerConfig{WatcherInterval: "1h", MaxConcurrent: 1})
+
+	jmCtx, jmCancel := context.WithCancel(context.Background())
+	jm.cancel = jmCancel
+	jm.safeGo("processor-0", func() { jm.processLoop(jmCtx) })
+
+	jobByID := func(id string) models.Job {
+		queue.mu.Lock()
+		defer queue.mu.Unlock()
+		for _, j := range queue.jobs {
+			if j.ID == id {
+				return *j
+			}
+		}
+		return models.Job{}
+	}
+
+	// The retry waits out a 1s backoff
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		if jobByID("panics").Status == models.JobStatusFailed && jobByID("after").Status == models.JobStatusCompleted {
+			break
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	jmCancel()
+	jm.wg.Wait()
+
+	bad := jobByID("panics")
+	if bad.Status != models.JobStatusDeadLetter || bad.Attempts != 2 {
+		t.Errorf("panicking job: status %q attempts %d, want dead_letter after 2 attempts", bad.Status, bad.Attempts)
+	}
+	if !strings.Contains(bad.Error, "panicked") {
+		t.Errorf("panicking job error = %q, want it to record the panic", bad.Error)
+	}
+	if st := jobByID("after").Status; st != models.JobStatusCompleted {
+		t.Errorf("job after the panic: status %q, want completed (processor must survive)", st)
+	}
+	market.mu.Lock()
+	defer market.mu.Unlock()
+	if n := market.collectCalls[models.JobTypeCollectFundamentals]; n != 1 {
+		t.Errorf("CollectFundamentals called %d times, want 1", n)
+	}
 }
 
 // panicMarketService panics on CollectEOD.
@@ -834,18 +964,18 @@ func (s *slowMarketService) CollectEOD(ctx context.Context, _ string, _ bool) er
 // DA-17. CRASH PROTECTION: WebSocket hub broadcast race with slow client cleanup
 // ============================================================================
 //
-// The hub.Run() broadcast case has a race: it holds RLock, finds a slow client,
-// drops to Lock, deletes client, drops to RLock, continues iterating.
-// This is a map concurrent modification during iteration.
+// The hub.Run() broadcast case used to hold RLock, find a slow client, drop to
+// Lock, delete the client and drop back to RLock mid-iteration. Slow clients
+// are now evicted after the range completes.
 //
-// This test uses -race flag to detect the issue.
+// Run with -race: broadcasts interleaved with register/unregister must not race.
 
 func TestDA_HubBroadcast_ConcurrentRegisterUnregister(t *testing.T) {
 	logger := common.NewLogger("error")
 	hub := NewJobWSHub(logger)
 	go hub.Run()
 
-	// Simulate rapid register/unregister while broadcasting
+	// Rapid register/unregister while broadcasting
 	var wg sync.WaitGroup
 
 	// Broadcaster
@@ -877,8 +1007,7 @@ func TestDA_HubBroadcast_ConcurrentRegisterUnregister(t *testing.T) {
 	}()
 
 	wg.Wait()
-	// If this test passes with -race flag, the broadcast is safe.
-	// If it fails with a data race, the RLock->Lock upgrade in Run() needs fixing.
+	hub.Stop()
 }
 
 // ============================================================================
@@ -931,28 +1060,23 @@ func TestDA_FileStore_GetNonExistent(t *testing.T) {
 // DA-20. FileStore: fileRecordID collision — different category+key same ID
 // ============================================================================
 //
-// fileRecordID sanitizes dots and slashes to underscores. This can create
-// collisions: category="filing_pdf", key="BHP/test" and
-// category="filing_pdf/BHP", key="test" would both produce the same record ID.
+// fileRecordID used to sanitize dots and slashes to underscores and join with
+// "_", so (filing_pdf, BHP/test.pdf) and (filing, pdf_BHP_test_pdf) shared a
+// record. IDs are now "<category>::<sha256 of length-prefixed category+key>";
+// TestFileRecordID_NoCollisions in the surrealdb package covers the real IDs.
 
 func TestDA_FileStore_RecordIDCollision(t *testing.T) {
 	store := newMockFileStore()
 	ctx := context.Background()
 
-	// These two pairs have different semantics but potentially the same record ID
-	// after sanitization in the real SurrealDB implementation
 	store.SaveFile(ctx, "filing_pdf", "BHP/test.pdf", []byte("data-a"), "application/pdf")
 	store.SaveFile(ctx, "filing", "pdf_BHP_test_pdf", []byte("data-b"), "application/pdf")
 
-	// In the mock, the keys are category+"/"+key, so they're different.
-	// But in the real SurrealDB implementation, fileRecordID("filing_pdf", "BHP/test.pdf")
-	// produces "filing_pdf_BHP_test_pdf" and fileRecordID("filing", "pdf_BHP_test_pdf")
-	// produces "filing_pdf_BHP_test_pdf" — COLLISION.
-	t.Log("FINDING: fileRecordID can collide across different category+key combinations. " +
-		"Example: (filing_pdf, BHP/test.pdf) and (filing, pdf_BHP_test_pdf) both produce " +
-		"filing_pdf_BHP_test_pdf after sanitization. " +
-		"Fix: use a separator that cannot appear in sanitized output, e.g. '::' " +
-		"or double underscore '__' between category and key.")
+	a, _, _ := store.GetFile(ctx, "filing_pdf", "BHP/test.pdf")
+	b, _, _ := store.GetFile(ctx, "filing", "pdf_BHP_test_pdf")
+	if string(a) != "data-a" || string(b) != "data-b" {
+		t.Errorf("files overwrote each other: a=%q b=%q", a, b)
+	}
 }
 
 // ============================================================================
@@ -1006,19 +1130,12 @@ func TestDA_FileStore_NilData(t *testing.T) {
 // DA-23. FileStore: SaveFile UPSERT does not preserve created_at on overwrite
 // ============================================================================
 //
-// The SaveFile implementation sets created_at on every UPSERT. This means
-// overwriting a file also updates created_at, losing the original creation time.
-// The SQL should use: created_at = $created_at only when INSERT, not UPDATE.
-
-func TestDA_FileStore_UpsertOverwritesCreatedAt(t *testing.T) {
-	// This is a design-level finding about the SurrealDB FileStore implementation.
-	// The UPSERT SET created_at = $created_at will overwrite created_at on update.
-	// Fix: Use IF NOT EXISTS pattern or conditional:
-	//   created_at = IF created_at THEN created_at ELSE $now END
-	t.Log("FINDING: FileStore.SaveFile UPSERT overwrites created_at on every call. " +
-		"An overwrite should preserve the original created_at and only update updated_at. " +
-		"Fix: change SQL to: created_at = IF created_at IS NOT NONE THEN created_at ELSE $created_at END")
-}
+// SaveFile used to set created_at on every UPSERT, so an overwrite lost the
+// original creation time. The SQL now keeps an existing created_at
+// (IF created_at IS NOT NONE THEN created_at ELSE $now END) and always sets
+// updated_at; StatFile reads both back. Covered against a real database by
+// TestFileStore_OverwriteKeepsCreatedAt in the surrealdb package, and for the
+// filesystem store in the blob package.
 
 // ============================================================================
 // DA-24. CRASH PROTECTION: processLoop re-enqueue uses same job ID via UPSERT
@@ -1099,7 +1216,7 @@ func TestDA_ReenqueueThenComplete_Race(t *testing.T) {
 // DA-25. FileStore: Special characters in category and key
 // ============================================================================
 //
-// fileRecordID concatenates category + "_" + key and sanitizes.
+// fileRecordID hashes category + key into the record ID.
 // What happens with hostile input?
 
 func TestDA_FileStore_HostileKeys(t *testing.T) {
@@ -1623,6 +1740,9 @@ func TestDA_EnqueueSlowDataJobs_AllTypes(t *testing.T) {
 // ============================================================================
 // DA-37. DEMAND-DRIVEN: EnqueueSlowDataJobs dedup — pre-existing pending job
 // ============================================================================
+//
+// EnqueueIfNeeded used to return nil for both "enqueued" and "already queued",
+// so the count included deduped jobs. It now reports whether a job was added.
 
 func TestDA_EnqueueSlowDataJobs_Dedup(t *testing.T) {
 	queue := newMockJobQueueStore()
@@ -1647,10 +1767,10 @@ func TestDA_EnqueueSlowDataJobs_Dedup(t *testing.T) {
 
 	ctx := context.Background()
 
-	// Pre-enqueue a filings job
+	// Pre-enqueue a filing PDFs job (one of the 6 slow types)
 	queue.Enqueue(ctx, &models.Job{
 		ID:       "existing-filing",
-		JobType:  models.JobTypeCollectFilings,
+		JobType:  models.JobTypeCollectFilingPdfs,
 		Ticker:   "BHP.AU",
 		Priority: 5,
 	})
@@ -1663,24 +1783,17 @@ func TestDA_EnqueueSlowDataJobs_Dedup(t *testing.T) {
 
 	n := jm.EnqueueSlowDataJobs(ctx, "BHP.AU")
 
-	// FINDING: EnqueueSlowDataJobs returns 6 even when 1 was deduped.
-	// EnqueueIfNeeded returns nil both when a job is newly created AND when
-	// it already exists (dedup). The count `err == nil` inflates the total.
-	// The return value should reflect actual new enqueues, not just non-errors.
-	if n != 6 {
-		t.Errorf("expected 6 (current behavior: counts deduped as success), got %d", n)
+	// The deduped filing PDFs job is not counted: the count feeds the
+	// handleMarketStocks advisory, which must reflect new jobs only.
+	if n != 5 {
+		t.Errorf("expected 5 newly enqueued (1 deduped), got %d", n)
 	}
-	t.Log("FINDING: EnqueueSlowDataJobs return count is inflated — deduped jobs are " +
-		"counted as enqueued because EnqueueIfNeeded returns nil for both 'already exists' " +
-		"and 'newly enqueued'. The count is used in the handleMarketStocks advisory message. " +
-		"Fix: have EnqueueIfNeeded return a boolean or sentinel error for 'already exists' " +
-		"so callers can distinguish dedup from new enqueue.")
 
 	// The important part: verify no DUPLICATE filings job was created
 	queue.mu.Lock()
 	filingsCount := 0
 	for _, j := range queue.jobs {
-		if j.JobType == models.JobTypeCollectFilings && j.Ticker == "BHP.AU" && j.Status == models.JobStatusPending {
+		if j.JobType == models.JobTypeCollectFilingPdfs && j.Ticker == "BHP.AU" && j.Status == models.JobStatusPending {
 			filingsCount++
 		}
 	}
@@ -1788,33 +1901,26 @@ func TestDA_EnqueueTickerJobs_PanicInStockIndex(t *testing.T) {
 // DA-40. DEMAND-DRIVEN: handleMarketStocks force_refresh response inconsistency
 // ============================================================================
 //
-// When force_refresh=true and backgroundJobs > 0, the response wraps StockData
-// in {"data": ..., "advisory": "..."}.
-// When force_refresh=false OR backgroundJobs == 0, the response is the raw
-// StockData object.
-//
-// MCP clients parsing the response will break if they don't handle both shapes.
+// handleMarketStocks used to wrap StockData in {"data", "advisory"} only on
+// force_refresh, returning the raw object otherwise, so MCP clients had to
+// handle both shapes. It now always returns
+// {"data": <StockData>, "advisory": <string|null>, "background_jobs": <int>};
+// the HTTP-level check is TestHandleMarketStocks_EnvelopeStable in the server
+// package. What remains here is the count feeding that envelope.
+
+func TestDA_HandleMarketStocks_BackgroundJobsCount(t *testing.T) {
+	queue := newMockJobQueueStore()
+	jm := newTestJobManager(queue, newMockStockIndexStore())
+	ctx := context.Background()
 
-func TestDA_HandleMarketStocks_ResponseSchemaInconsistency(t *testing.T) {
-	// This is a design finding, not a runtime test.
-	// The response shape depends on runtime conditions:
-	//
-	// Case 1 (force_refresh=true, backgroundJobs > 0):
-	//   {"data": <StockData>, "advisory": "..."}
-	//
-	// Case 2 (force_refresh=false OR backgroundJobs == 0):
-	//   <StockData>  (raw object, no wrapper)
-	//
-	// Case 3 (force_refresh=true, no JobManager):
-	//   <StockData>  (raw object, backgroundJobs stays 0)
-	//
-	// An MCP client parsing "data" will fail on Case 2/3.
-	// An MCP client parsing the root as StockData will fail on Case 1.
-	t.Log("FINDING: handleMarketStocks returns inconsistent response schemas:\n" +
-		"  - force_refresh=true + jobs enqueued: {\"data\": <StockData>, \"advisory\": \"...\"}\n" +
-		"  - otherwise: <StockData> (raw)\n" +
-		"MCP clients must handle both shapes. Consider always returning a consistent " +
-		"envelope: {\"data\": <StockData>, \"advisory\": null} to avoid conditional parsing.")
+	// No stock index entry: only the dependency-free slow jobs are eligible
+	if n := jm.EnqueueSlowDataJobs(ctx, "BHP.AU"); n != 3 {
+		t.Errorf("first force refresh: background_jobs = %d, want 3", n)
+	}
+	// A repeat refresh while those jobs are queued reports none, so no advisory
+	if n := jm.EnqueueSlowDataJobs(ctx, "BHP.AU"); n != 0 {
+		t.Errorf("repeat force refresh: background_jobs = %d, want 0", n)
+	}
 }
 
 // ============================================================================
@@ -2593,8 +2699,8 @@ func TestDA_MaxAttemptsJob_Shutdown(t *testing.T) {
 	if job == nil {
 		t.Fatal("job not found")
 	}
-	if job.Status != models.JobStatusFailed {
-		t.Errorf("expected job status %s, got %s", models.JobStatusFailed, job.Status)
+	if job.Status != models.JobStatusDeadLetter {
+		t.Errorf("expected job status %s, got %s", models.JobStatusDeadLetter, job.Status)
 	}
 	if job.Error == "" {
 		t.Error("expected job error message to be set")
@@ -2932,13 +3038,13 @@ func TestDA_SignalJob_FailedRetry(t *testing.T) {
 		t.Fatal("job not found")
 	}
 
-	// After exhausting retries (3 attempts), the job should be marked failed.
+	// After exhausting retries (3 attempts), the job should be dead-lettered.
 	// It should NOT be marked completed (which was the old bug behavior).
 	if job.Status == models.JobStatusCompleted {
 		t.Error("CRITICAL: Signal job with no EOD was marked as COMPLETED. " +
-			"It should be FAILED after exhausting retries.")
+			"It should be DEAD-LETTERED after exhausting retries.")
 	}
-	if job.Status == models.JobStatusFailed {
+	if job.Status == models.JobStatusDeadLetter {
 		t.Logf("VERIFIED: Signal job correctly failed after exhausting retries (attempts=%d)", job.Attempts)
 	}
 }
@@ -3205,6 +3311,13 @@ func (t *trackingSignalService) ComputeSignals(_ context.Context, _ string, _ *m
 	return &models.TickerSignals{}, nil
 }
 
+func (t *trackingSignalService) ScoreNewsSentiment(_ context.Context, _ *models.TickerSignals, _ *models.MarketData) {
+}
+
+func (t *trackingSignalService) BacktestThresholds(_ context.Context, _ string, _ int) (*models.SignalBacktest, error) {
+	return nil, nil
+}
+
 // trackingSignalStorage records that SaveSignals was called.
 type trackingSignalStorage struct {
 	saved *bool
@@ -2,14 +2,31 @@ package jobmanager
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/interfaces"
 	"github.com/bobmcallan/vire/internal/models"
 )
 
-// executeJob dispatches a job to the correct service method based on job type.
+// errJobPanicked stands in for a panicking job's error in the job metrics.
+var errJobPanicked = errors.New("job panicked")
+
+// executeJob runs a job and records its outcome and duration in the job
+// metrics. A panic is counted as an error before it reaches the processor's
+// recover.
 func (jm *JobManager) executeJob(ctx context.Context, job *models.Job) error {
+	start := time.Now()
+	result := errJobPanicked
+	defer func() { common.ObserveJob(job.JobType, start, result) }()
+	result = jm.dispatchJob(ctx, job)
+	return result
+}
+
+// dispatchJob dispatches a job to the correct service method based on job type.
+func (jm *JobManager) dispatchJob(ctx context.Context, job *models.Job) error {
 	switch job.JobType {
 	case models.JobTypeCollectEOD:
 		return jm.market.CollectEOD(ctx, job.Ticker, false)
@@ -33,6 +50,20 @@ func (jm *JobManager) executeJob(ctx context.Context, job *models.Job) error {
 		return jm.computeSignals(ctx, job.Ticker)
 	case models.JobTypeCollectLivePrices:
 		return jm.market.CollectLivePrices(ctx, job.Ticker) // Ticker = exchange code (e.g. "AU")
+	case models.JobTypeGenerateReport:
+		if jm.report == nil {
+			return fmt.Errorf("report service not configured")
+		}
+		_, err := jm.report.GenerateReport(ctx, job.Ticker, interfaces.ReportOptions{}) // Ticker = portfolio name
+		return err
+	case models.JobTypeCompactEOD:
+		return jm.compactEOD(ctx)
+	case models.JobTypeSnapshotPortfolio:
+		if jm.portfolio == nil {
+			return fmt.Errorf("portfolio service not configured")
+		}
+		_, err := jm.portfolio.SnapshotPortfolio(ctx, job.Ticker) // Ticker = portfolio name
+		return err
 	default:
 		return fmt.Errorf("unknown job type: %s", job.JobType)
 	}
@@ -52,14 +83,28 @@ func (jm *JobManager) computeSignals(ctx context.Context, ticker string) error {
 	if err != nil {
 		return fmt.Errorf("failed to compute signals: %w", err)
 	}
+	jm.signal.ScoreNewsSentiment(ctx, sigs, md)
 
-	if err := jm.storage.SignalStorage().SaveSignals(ctx, sigs); err != nil {
+	if err := jm.saveSignals(ctx, sigs); err != nil {
 		return fmt.Errorf("failed to save signals: %w", err)
 	}
 
 	return nil
 }
 
+// saveSignals persists sigs, keeping the stored news sentiment when sigs
+// was computed without one.
+func (jm *JobManager) saveSignals(ctx context.Context, sigs *models.TickerSignals) error {
+	store := jm.storage.SignalStorage()
+	if sigs == nil {
+		return store.SaveSignals(ctx, sigs)
+	}
+	if prev, err := store.GetSignals(ctx, sigs.Ticker); err == nil {
+		sigs.KeepNewsSentiment(prev)
+	}
+	return store.SaveSignals(ctx, sigs)
+}
+
 // updateStockIndexTimestamp updates the corresponding freshness timestamp on the stock index.
 func (jm *JobManager) updateStockIndexTimestamp(ctx context.Context, job *models.Job) {
 	field := models.TimestampFieldForJobType(job.JobType)
@@ -0,0 +1,83 @@
+package jobmanager
+
+import (
+	"sort"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// typeAccumulator tallies per-type durations and waits for BuildQueueExport.
+type typeAccumulator struct {
+	stats       models.JobTypeStats
+	durationSum int64
+	durationN   int
+	waitSum     int64
+	waitN       int
+}
+
+// BuildQueueExport builds a diagnostic export of the given jobs with status
+// counts and per-type averages (duration, queue wait, failure rate).
+func BuildQueueExport(jobs []*models.Job, now time.Time) *models.JobQueueExport {
+	export := &models.JobQueueExport{
+		ExportedAt:   now,
+		TotalJobs:    len(jobs),
+		StatusCounts: make(map[string]int),
+		Jobs:         jobs,
+	}
+	if export.Jobs == nil {
+		export.Jobs = []*models.Job{}
+	}
+
+	byType := make(map[string]*typeAccumulator)
+	for _, job := range jobs {
+		if job == nil {
+			continue
+		}
+		export.StatusCounts[job.Status]++
+
+		acc, ok := byType[job.JobType]
+		if !ok {
+			acc = &typeAccumulator{stats: models.JobTypeStats{
+				JobType:      job.JobType,
+				StatusCounts: make(map[string]int),
+			}}
+			byType[job.JobType] = acc
+		}
+		acc.stats.Count++
+		acc.stats.StatusCounts[job.Status]++
+
+		finished := job.Status == models.JobStatusCompleted || job.Status == models.JobStatusFailed ||
+			job.Status == models.JobStatusDeadLetter
+		if finished && job.DurationMS > 0 {
+			acc.durationSum += job.DurationMS
+			acc.durationN++
+		}
+		if !job.StartedAt.IsZero() && !job.CreatedAt.IsZero() && job.StartedAt.After(job.CreatedAt) {
+			acc.waitSum += job.StartedAt.Sub(job.CreatedAt).Milliseconds()
+			acc.waitN++
+		}
+	}
+
+	export.TypeStats = make([]models.JobTypeStats, 0, len(byType))
+	for _, acc := range byType {
+		st := acc.stats
+		if acc.durationN > 0 {
+			st.AvgDurationMS = float64(acc.durationSum) / float64(acc.durationN)
+		}
+		if acc.waitN > 0 {
+			st.AvgWaitMS = float64(acc.waitSum) / float64(acc.waitN)
+		}
+		completed := st.StatusCounts[models.JobStatusCompleted]
+		failed := st.StatusCounts[models.JobStatusFailed] + st.StatusCounts[models.JobStatusDeadLetter]
+		if completed+failed > 0 {
+			st.FailureRatePct = float64(failed) / float64(completed+failed) * 100
+		}
+		export.TypeStats = append(export.TypeStats, st)
+	}
+	sort.Slice(export.TypeStats, func(i, j int) bool {
+		return export.TypeStats[i].JobType < export.TypeStats[j].JobType
+	})
+
+	return export
+}
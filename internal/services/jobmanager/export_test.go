@@ -0,0 +1,84 @@
+package jobmanager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+func TestBuildQueueExport_StatusesAndTypeAverages(t *testing.T) {
+	base := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	jobs := []*models.Job{
+		{ID: "1", JobType: models.JobTypeCollectEOD, Status: models.JobStatusCompleted,
+			CreatedAt: base, StartedAt: base.Add(2 * time.Second), DurationMS: 1000},
+		{ID: "2", JobType: models.JobTypeCollectEOD, Status: models.JobStatusCompleted,
+			CreatedAt: base, StartedAt: base.Add(4 * time.Second), DurationMS: 3000},
+		{ID: "3", JobType: models.JobTypeCollectEOD, Status: models.JobStatusFailed,
+			CreatedAt: base, StartedAt: base.Add(6 * time.Second), DurationMS: 5000, Error: "timeout"},
+		{ID: "4", JobType: models.JobTypeCollectFilings, Status: models.JobStatusRunning,
+			CreatedAt: base, StartedAt: base.Add(10 * time.Second)},
+		{ID: "5", JobType: models.JobTypeCollectFilings, Status: models.JobStatusPending, CreatedAt: base},
+		{ID: "6", JobType: models.JobTypeComputeSignals, Status: models.JobStatusCancelled, CreatedAt: base},
+	}
+
+	export := BuildQueueExport(jobs, base.Add(time.Minute))
+
+	if export.TotalJobs != 6 || len(export.Jobs) != 6 {
+		t.Fatalf("TotalJobs = %d, len(Jobs) = %d, want 6", export.TotalJobs, len(export.Jobs))
+	}
+	for status, want := range map[string]int{
+		models.JobStatusPending:   1,
+		models.JobStatusRunning:   1,
+		models.JobStatusCompleted: 2,
+		models.JobStatusFailed:    1,
+		models.JobStatusCancelled: 1,
+	} {
+		if got := export.StatusCounts[status]; got != want {
+			t.Errorf("StatusCounts[%s] = %d, want %d", status, got, want)
+		}
+	}
+	if export.Jobs[2].Error != "timeout" {
+		t.Errorf("failed job error not exported: %q", export.Jobs[2].Error)
+	}
+
+	if len(export.TypeStats) != 3 {
+		t.Fatalf("expected 3 type stats, got %d", len(export.TypeStats))
+	}
+	stats := make(map[string]models.JobTypeStats)
+	for _, st := range export.TypeStats {
+		stats[st.JobType] = st
+	}
+
+	eod := stats[models.JobTypeCollectEOD]
+	if eod.Count != 3 {
+		t.Errorf("collect_eod Count = %d, want 3", eod.Count)
+	}
+	if eod.AvgDurationMS != 3000 {
+		t.Errorf("collect_eod AvgDurationMS = %.1f, want 3000", eod.AvgDurationMS)
+	}
+	if eod.AvgWaitMS != 4000 {
+		t.Errorf("collect_eod AvgWaitMS = %.1f, want 4000", eod.AvgWaitMS)
+	}
+	if eod.FailureRatePct < 33.3 || eod.FailureRatePct > 33.4 {
+		t.Errorf("collect_eod FailureRatePct = %.2f, want 33.33", eod.FailureRatePct)
+	}
+
+	filings := stats[models.JobTypeCollectFilings]
+	if filings.AvgDurationMS != 0 {
+		t.Errorf("collect_filings AvgDurationMS = %.1f, want 0 (no finished jobs)", filings.AvgDurationMS)
+	}
+	if filings.AvgWaitMS != 10000 {
+		t.Errorf("collect_filings AvgWaitMS = %.1f, want 10000 (running job only)", filings.AvgWaitMS)
+	}
+	if filings.StatusCounts[models.JobStatusPending] != 1 || filings.StatusCounts[models.JobStatusRunning] != 1 {
+		t.Errorf("collect_filings StatusCounts = %v", filings.StatusCounts)
+	}
+}
+
+func TestBuildQueueExport_Empty(t *testing.T) {
+	export := BuildQueueExport(nil, time.Now())
+	if export.TotalJobs != 0 || export.Jobs == nil || len(export.TypeStats) != 0 {
+		t.Errorf("unexpected empty export: %+v", export)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"runtime/debug"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/bobmcallan/vire/internal/common"
@@ -18,16 +19,19 @@ import (
 // The watcher scans the stock index for stale data and enqueues jobs.
 // Processor goroutines dequeue and execute jobs concurrently.
 type JobManager struct {
-	market  interfaces.MarketService
-	signal  interfaces.SignalService
-	storage interfaces.StorageManager
-	logger  *common.Logger
-	hub     *JobWSHub
-	config  common.JobManagerConfig
-
-	heavySem chan struct{} // semaphore limiting concurrent PDF-heavy jobs
-	cancel   context.CancelFunc
-	wg       sync.WaitGroup
+	market    interfaces.MarketService
+	signal    interfaces.SignalService
+	report    interfaces.ReportService    // optional: runs scheduled report jobs
+	portfolio interfaces.PortfolioService // optional: takes daily portfolio snapshots
+	storage   interfaces.StorageManager
+	logger    *common.Logger
+	hub       *JobWSHub
+	config    common.JobManagerConfig
+
+	heavySem        chan struct{} // semaphore limiting concurrent PDF-heavy jobs
+	watcherInterval atomic.Int64  // stock index scan interval (ns); hot-reloadable
+	cancel          context.CancelFunc
+	wg              sync.WaitGroup
 }
 
 // NewJobManager creates a new job manager.
@@ -39,7 +43,7 @@ func NewJobManager(
 	config common.JobManagerConfig,
 ) *JobManager {
 	heavyLimit := config.GetHeavyJobLimit()
-	return &JobManager{
+	jm := &JobManager{
 		market:   market,
 		signal:   signal,
 		storage:  storage,
@@ -48,6 +52,27 @@ func NewJobManager(
 		config:   config,
 		heavySem: make(chan struct{}, heavyLimit),
 	}
+	jm.SetWatcherInterval(config.GetWatcherInterval())
+	return jm
+}
+
+// SetWatcherInterval changes how often the watcher scans the stock index.
+// A running watcher picks it up after its next scan. Non-positive values are
+// ignored.
+func (jm *JobManager) SetWatcherInterval(d time.Duration) {
+	if d > 0 {
+		jm.watcherInterval.Store(int64(d))
+	}
+}
+
+// SetReportService sets the service used to run scheduled report jobs.
+func (jm *JobManager) SetReportService(report interfaces.ReportService) {
+	jm.report = report
+}
+
+// SetPortfolioService sets the service used to take daily portfolio snapshots.
+func (jm *JobManager) SetPortfolioService(portfolio interfaces.PortfolioService) {
+	jm.portfolio = portfolio
 }
 
 // safeGo launches a goroutine with panic recovery and logging.
@@ -85,7 +110,8 @@ func (jm *JobManager) Start() {
 		jm.logger.Info().Int("count", count).Msg("Reset orphaned running jobs to pending")
 	}
 
-	// Start WebSocket hub
+	// Start WebSocket hub (re-armed first in case a previous Stop closed it)
+	jm.hub.restart()
 	jm.safeGo("websocket-hub", func() { jm.hub.Run() })
 
 	// Start watcher loop
@@ -133,13 +159,38 @@ func isHeavyJob(jobType string) bool {
 // cleanupContextTimeout is the duration allowed for cleanup operations during shutdown.
 const cleanupContextTimeout = 5 * time.Second
 
+// Backoff bounds shared by dequeue errors and failed-job retries.
+const (
+	backoffMin = 1 * time.Second
+	backoffMax = 60 * time.Second
+)
+
+// retryBackoff returns the delay before the given attempt is retried:
+// backoffMin doubled per prior attempt, capped at backoffMax.
+func retryBackoff(attempts int) time.Duration {
+	backoff := backoffMin
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= backoffMax {
+			return backoffMax
+		}
+	}
+	return backoff
+}
+
+// requeueFailedJob puts a failed job back in the queue, held back by
+// retryBackoff so a failing upstream is not hammered.
+func (jm *JobManager) requeueFailedJob(ctx context.Context, job *models.Job, now time.Time) error {
+	job.Status = models.JobStatusPending
+	job.Error = ""
+	job.NextAttemptAt = now.Add(retryBackoff(job.Attempts))
+	return jm.storage.JobQueueStore().Enqueue(ctx, job)
+}
+
 // processLoop continuously dequeues and executes jobs.
 func (jm *JobManager) processLoop(ctx context.Context) {
+	// Dequeue error backoff is tracked per processor goroutine.
 	backoff := time.Duration(0)
-	const (
-		backoffMin = 1 * time.Second
-		backoffMax = 30 * time.Second
-	)
 
 	for {
 		select {
@@ -208,6 +259,19 @@ func (jm *JobManager) processLoop(ctx context.Context) {
 				if heavy {
 					defer func() { <-jm.heavySem }()
 				}
+				// A panicking job fails like any other error, so retry and
+				// backoff apply and this processor keeps dequeuing.
+				defer func() {
+					if r := recover(); r != nil {
+						jm.logger.Error().
+							Str("job_id", job.ID).
+							Str("job_type", job.JobType).
+							Str("panic", fmt.Sprintf("%v", r)).
+							Str("stack", string(debug.Stack())).
+							Msg("Recovered from panic in job")
+						jobErr = fmt.Errorf("job panicked: %v", r)
+					}
+				}()
 				return jm.executeJob(ctx, job)
 			}()
 			durationMS := time.Since(start).Milliseconds()
@@ -240,11 +304,10 @@ func (jm *JobManager) processLoop(ctx context.Context) {
 						Str("job_id", job.ID).
 						Int("attempt", job.Attempts).
 						Int("max", job.MaxAttempts).
+						Dur("backoff", retryBackoff(job.Attempts)).
 						Msg("Re-queuing failed job")
 
-					job.Status = models.JobStatusPending
-					job.Error = ""
-					if err := jm.storage.JobQueueStore().Enqueue(opCtx, job); err != nil {
+					if err := jm.requeueFailedJob(opCtx, job, time.Now()); err != nil {
 						jm.logger.Warn().Str("job_id", job.ID).Err(err).Msg("Failed to re-enqueue job")
 					} else {
 						continue // Skip complete() — job is re-queued
@@ -2,7 +2,9 @@ package jobmanager
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"strings"
 	"sync"
 	"testing"
@@ -106,6 +108,12 @@ func (m *mockMarketService) CollectLivePrices(_ context.Context, exchange string
 	m.mu.Unlock()
 	return nil
 }
+func (m *mockMarketService) CompactEOD(_ context.Context, _ string, _ time.Duration) (int, error) {
+	m.mu.Lock()
+	m.collectCalls[models.JobTypeCompactEOD]++
+	m.mu.Unlock()
+	return 0, nil
+}
 func (m *mockMarketService) GetStockData(_ context.Context, _ string, _ interfaces.StockDataInclude) (*models.StockData, error) {
 	return nil, fmt.Errorf("not implemented")
 }
@@ -115,6 +123,9 @@ func (m *mockMarketService) FindSnipeBuys(_ context.Context, _ interfaces.SnipeO
 func (m *mockMarketService) ScreenStocks(_ context.Context, _ interfaces.ScreenOptions) ([]*models.ScreenCandidate, error) {
 	return nil, nil
 }
+func (m *mockMarketService) ScreenAndScore(_ context.Context, _ models.ScreenerOptions, _ models.ScoreWeights) ([]*models.ScoredResult, error) {
+	return nil, nil
+}
 func (m *mockMarketService) FunnelScreen(_ context.Context, _ interfaces.FunnelOptions) (*models.FunnelResult, error) {
 	return nil, nil
 }
@@ -123,6 +134,9 @@ func (m *mockMarketService) ScanMarket(_ context.Context, _ models.ScanQuery) (*
 	return nil, nil
 }
 func (m *mockMarketService) ScanFields() *models.ScanFieldsResponse { return nil }
+func (m *mockMarketService) CorrelationMatrix(_ context.Context, _ []string, _ int) (map[string]map[string]float64, error) {
+	return nil, nil
+}
 func (m *mockMarketService) ReadFiling(_ context.Context, _, _ string) (*models.FilingContent, error) {
 	return nil, nil
 }
@@ -140,6 +154,11 @@ func (m *mockSignalService) ComputeSignals(ctx context.Context, ticker string, m
 	}
 	return nil, nil
 }
+func (m *mockSignalService) ScoreNewsSentiment(_ context.Context, _ *models.TickerSignals, _ *models.MarketData) {
+}
+func (m *mockSignalService) BacktestThresholds(_ context.Context, _ string, _ int) (*models.SignalBacktest, error) {
+	return nil, nil
+}
 
 type mockSignalStorage struct {
 	mu      sync.Mutex
@@ -211,7 +230,13 @@ func (m *mockStorageManager) PurgeDerivedData(_ context.Context) (map[string]int
 	return nil, nil
 }
 func (m *mockStorageManager) PurgeReports(_ context.Context) (int, error) { return 0, nil }
-func (m *mockStorageManager) Close() error                                { return nil }
+func (m *mockStorageManager) Backup(_ context.Context, _ io.Writer) (*models.BackupManifest, error) {
+	return nil, nil
+}
+func (m *mockStorageManager) Restore(_ context.Context, _ io.Reader) (*models.BackupManifest, error) {
+	return nil, nil
+}
+func (m *mockStorageManager) Close() error { return nil }
 
 type mockInternalStore struct {
 	kv map[string]string
@@ -244,6 +269,19 @@ func (m *mockInternalStore) SetSystemKV(_ context.Context, key, value string) er
 	m.kv[key] = value
 	return nil
 }
+func (m *mockInternalStore) DeleteSystemKV(_ context.Context, key string) error {
+	delete(m.kv, key)
+	return nil
+}
+func (m *mockInternalStore) ListSystemKV(_ context.Context, prefix string) (map[string]string, error) {
+	entries := make(map[string]string)
+	for k, v := range m.kv {
+		if strings.HasPrefix(k, prefix) {
+			entries[k] = v
+		}
+	}
+	return entries, nil
+}
 func (m *mockInternalStore) Close() error { return nil }
 
 type mockMarketDataStorage struct {
@@ -384,6 +422,13 @@ func (m *mockJobQueueStore) Enqueue(_ context.Context, job *models.Job) error {
 	if job.CreatedAt.IsZero() {
 		job.CreatedAt = time.Now()
 	}
+	// Upsert by ID, as the real store does on re-enqueue
+	for i, j := range m.jobs {
+		if j.ID == job.ID {
+			m.jobs[i] = job
+			return nil
+		}
+	}
 	m.jobs = append(m.jobs, job)
 	return nil
 }
@@ -392,11 +437,12 @@ func (m *mockJobQueueStore) Dequeue(_ context.Context) (*models.Job, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	// Find highest priority pending job
+	// Find highest priority pending job whose retry backoff has elapsed
+	now := time.Now()
 	bestIdx := -1
 	bestPriority := -1
 	for i, j := range m.jobs {
-		if j.Status == models.JobStatusPending && j.Priority > bestPriority {
+		if j.Status == models.JobStatusPending && !j.NextAttemptAt.After(now) && j.Priority > bestPriority {
 			bestIdx = i
 			bestPriority = j.Priority
 		}
@@ -530,6 +576,18 @@ func (m *mockJobQueueStore) HasPendingJob(_ context.Context, jobType, ticker str
 	return false, nil
 }
 
+func (m *mockJobQueueStore) HasActiveJob(_ context.Context, jobType, ticker string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, j := range m.jobs {
+		if j.JobType == jobType && j.Ticker == ticker &&
+			(j.Status == models.JobStatusPending || j.Status == models.JobStatusRunning) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func (m *mockJobQueueStore) PurgeCompleted(_ context.Context, olderThan time.Time) (int, error) {
 	return 0, nil
 }
@@ -561,6 +619,36 @@ func (m *mockJobQueueStore) ResetRunningJobs(_ context.Context) (int, error) {
 	return count, nil
 }
 
+func (m *mockJobQueueStore) ListDeadLetter(_ context.Context, limit int) ([]*models.Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var result []*models.Job
+	for _, j := range m.jobs {
+		if j.Status == models.JobStatusDeadLetter {
+			result = append(result, j)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockJobQueueStore) RequeueDeadLetter(_ context.Context, id string) (*models.Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, j := range m.jobs {
+		if j.ID == id && j.Status == models.JobStatusDeadLetter {
+			j.Status = models.JobStatusPending
+			j.Attempts = 0
+			j.Error = ""
+			j.StartedAt = time.Time{}
+			j.CompletedAt = time.Time{}
+			j.DurationMS = 0
+			j.NextAttemptAt = time.Time{}
+			return j, nil
+		}
+	}
+	return nil, nil
+}
+
 // mockFileStore is an in-memory file store for tests.
 type mockFileStore struct {
 	mu    sync.Mutex
@@ -601,6 +689,16 @@ func (m *mockFileStore) HasFile(_ context.Context, category, key string) (bool,
 	return ok, nil
 }
 
+func (m *mockFileStore) StatFile(_ context.Context, category, key string) (*models.FileMeta, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	d, ok := m.files[category+"/"+key]
+	if !ok {
+		return nil, fmt.Errorf("not found")
+	}
+	return &models.FileMeta{Category: category, Key: key, ContentType: "application/octet-stream", Size: int64(len(d))}, nil
+}
+
 // --- tests ---
 
 func TestJobManager_StartStop(t *testing.T) {
@@ -650,13 +748,13 @@ func TestJobManager_EnqueueIfNeeded_Dedup(t *testing.T) {
 	ctx := context.Background()
 
 	// First enqueue should succeed
-	if err := jm.EnqueueIfNeeded(ctx, models.JobTypeCollectEOD, "BHP.AU", 10); err != nil {
-		t.Fatalf("first enqueue failed: %v", err)
+	if added, err := jm.EnqueueIfNeeded(ctx, models.JobTypeCollectEOD, "BHP.AU", 10); err != nil || !added {
+		t.Fatalf("first enqueue: added=%v err=%v, want added", added, err)
 	}
 
 	// Second enqueue for same type+ticker should be deduped
-	if err := jm.EnqueueIfNeeded(ctx, models.JobTypeCollectEOD, "BHP.AU", 10); err != nil {
-		t.Fatalf("second enqueue failed: %v", err)
+	if added, err := jm.EnqueueIfNeeded(ctx, models.JobTypeCollectEOD, "BHP.AU", 10); err != nil || added {
+		t.Fatalf("second enqueue: added=%v err=%v, want deduped", added, err)
 	}
 
 	pending, _ := queue.CountPending(ctx)
@@ -665,7 +763,7 @@ func TestJobManager_EnqueueIfNeeded_Dedup(t *testing.T) {
 	}
 
 	// Different job type should not be deduped
-	if err := jm.EnqueueIfNeeded(ctx, models.JobTypeCollectFundamentals, "BHP.AU", 8); err != nil {
+	if _, err := jm.EnqueueIfNeeded(ctx, models.JobTypeCollectFundamentals, "BHP.AU", 8); err != nil {
 		t.Fatalf("third enqueue failed: %v", err)
 	}
 
@@ -1368,10 +1466,9 @@ func TestEnqueueTickerJobs_Dedup(t *testing.T) {
 
 	n := jm.EnqueueTickerJobs(ctx, []string{"BHP.AU"})
 
-	// EnqueueIfNeeded returns nil for deduped jobs, so the count includes them.
-	// The important assertion is that no duplicate job was created in the queue.
-	if n != 1 {
-		t.Errorf("expected 1 from EnqueueTickerJobs (deduped but counted), got %d", n)
+	// The only stale component was already queued, so nothing new is counted
+	if n != 0 {
+		t.Errorf("expected 0 from EnqueueTickerJobs (deduped), got %d", n)
 	}
 
 	// Critical: only 1 fundamentals job should exist (no duplicate)
@@ -1515,10 +1612,9 @@ func TestEnqueueSlowDataJobs_Dedup(t *testing.T) {
 
 	n := jm.EnqueueSlowDataJobs(ctx, "BHP.AU")
 
-	// EnqueueIfNeeded returns nil for deduped jobs, so the count includes them.
-	// All 6 calls succeed (no errors), so count is 6.
-	if n != 6 {
-		t.Errorf("expected 6 from EnqueueSlowDataJobs (deduped but counted), got %d", n)
+	// The pre-existing filing PDFs job is deduped and not counted
+	if n != 5 {
+		t.Errorf("expected 5 from EnqueueSlowDataJobs (1 deduped), got %d", n)
 	}
 
 	// Critical: only 1 filing PDFs job should exist (no duplicate created)
@@ -1886,6 +1982,70 @@ func TestJobManager_GracefulShutdown_ReenqueuesFailedJobs(t *testing.T) {
 	}
 }
 
+func TestRetryBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, 1 * time.Second},
+		{1, 1 * time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{6, 32 * time.Second},
+		{7, 60 * time.Second},
+		{50, 60 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := retryBackoff(tt.attempts); got != tt.want {
+			t.Errorf("retryBackoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestRequeueFailedJob_IncreasingDelays(t *testing.T) {
+	queue := newMockJobQueueStore()
+	jm := newTestJobManager(queue, newMockStockIndexStore())
+	ctx := context.Background()
+
+	queue.Enqueue(ctx, &models.Job{ID: "job1", JobType: models.JobTypeCollectEOD, Ticker: "BHP.AU", Priority: 10, MaxAttempts: 5})
+
+	now := time.Now()
+	var prev time.Duration
+	for i := 1; i <= 3; i++ {
+		// Backdate the job's hold so it can be dequeued for this attempt
+		queue.mu.Lock()
+		for _, j := range queue.jobs {
+			j.NextAttemptAt = time.Time{}
+		}
+		queue.mu.Unlock()
+
+		job, err := queue.Dequeue(ctx)
+		if err != nil || job == nil {
+			t.Fatalf("attempt %d: Dequeue = %v, %v", i, job, err)
+		}
+		if job.Attempts != i {
+			t.Fatalf("attempt %d: Attempts = %d", i, job.Attempts)
+		}
+		if err := jm.requeueFailedJob(ctx, job, now); err != nil {
+			t.Fatalf("attempt %d: requeueFailedJob: %v", i, err)
+		}
+
+		delay := job.NextAttemptAt.Sub(now)
+		if delay <= prev {
+			t.Errorf("attempt %d: delay %v not greater than previous %v", i, delay, prev)
+		}
+		prev = delay
+
+		// The job must not be handed out again until its backoff elapses
+		if held, _ := queue.Dequeue(ctx); held != nil {
+			t.Errorf("attempt %d: job dequeued during backoff", i)
+		}
+	}
+	if prev != 4*time.Second {
+		t.Errorf("third delay = %v, want 4s", prev)
+	}
+}
+
 // --- Bug fix tests: computeSignals error return and watcher skip ---
 
 func TestComputeSignals_NilMarketData_ReturnsError(t *testing.T) {
@@ -2358,3 +2518,64 @@ func TestHeavySemaphore_NonBlocking_WorkerNotStarved(t *testing.T) {
 		t.Error("high-priority EOD job should complete even when heavy semaphore is full — worker should not be starved")
 	}
 }
+
+// --- dead letter tests ---
+
+func TestComplete_DeadLettersAtAttemptBoundary(t *testing.T) {
+	queue := newMockJobQueueStore()
+	jm := newTestJobManager(queue, newMockStockIndexStore())
+	ctx := context.Background()
+
+	below := &models.Job{ID: "below", JobType: models.JobTypeCollectEOD, Ticker: "BHP.AU", Attempts: 2, MaxAttempts: 3}
+	atMax := &models.Job{ID: "at-max", JobType: models.JobTypeCollectEOD, Ticker: "CBA.AU", Attempts: 3, MaxAttempts: 3}
+	queue.Enqueue(ctx, below)
+	queue.Enqueue(ctx, atMax)
+
+	jm.complete(ctx, below, errors.New("timeout"), 10)
+	jm.complete(ctx, atMax, errors.New("EODHD 503"), 20)
+
+	if below.Status != models.JobStatusFailed {
+		t.Errorf("attempts below max: status = %q, want failed", below.Status)
+	}
+	if atMax.Status != models.JobStatusDeadLetter || atMax.Error != "EODHD 503" || atMax.Attempts != 3 {
+		t.Errorf("attempts at max: status %q, error %q, attempts %d; want dead_letter keeping the final error",
+			atMax.Status, atMax.Error, atMax.Attempts)
+	}
+
+	dead, _ := queue.ListDeadLetter(ctx, 10)
+	if len(dead) != 1 || dead[0].ID != "at-max" {
+		t.Errorf("ListDeadLetter = %v, want only at-max", dead)
+	}
+}
+
+func TestRequeueDeadLetter_ClearsDeadLetterState(t *testing.T) {
+	queue := newMockJobQueueStore()
+	jm := newTestJobManager(queue, newMockStockIndexStore())
+	ctx := context.Background()
+
+	job := &models.Job{ID: "dead", JobType: models.JobTypeCollectEOD, Ticker: "BHP.AU", Attempts: 3, MaxAttempts: 3}
+	queue.Enqueue(ctx, job)
+	jm.complete(ctx, job, errors.New("EODHD 503"), 20)
+
+	requeued, err := queue.RequeueDeadLetter(ctx, "dead")
+	if err != nil || requeued == nil {
+		t.Fatalf("RequeueDeadLetter = %v, %v; want the job", requeued, err)
+	}
+	if requeued.Status != models.JobStatusPending || requeued.Attempts != 0 || requeued.Error != "" {
+		t.Errorf("requeued job: status %q, attempts %d, error %q; want pending with attempts and error cleared",
+			requeued.Status, requeued.Attempts, requeued.Error)
+	}
+	if dead, _ := queue.ListDeadLetter(ctx, 10); len(dead) != 0 {
+		t.Errorf("expected dead letter list to be empty, got %d", len(dead))
+	}
+
+	// The job runs again with a full set of attempts
+	next, _ := jm.dequeue(ctx)
+	if next == nil || next.ID != "dead" || next.Attempts != 1 {
+		t.Errorf("dequeue = %+v, want the requeued job on its first attempt", next)
+	}
+
+	if again, _ := queue.RequeueDeadLetter(ctx, "dead"); again != nil {
+		t.Error("expected requeue of a job that is not dead-lettered to return nil")
+	}
+}
@@ -0,0 +1,80 @@
+package jobmanager
+
+import (
+	"bufio"
+	"context"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// scrapeMetric returns the value of the exposition line for series, or -1
+// when it is absent.
+func scrapeMetric(t *testing.T, series string) float64 {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	common.MetricsHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	scanner := bufio.NewScanner(rec.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if value, ok := strings.CutPrefix(line, series+" "); ok {
+			v, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				t.Fatalf("parse %q: %v", line, err)
+			}
+			return v
+		}
+	}
+	return -1
+}
+
+func TestExecuteJob_RecordsJobMetrics(t *testing.T) {
+	jm := newTestJobManager(newMockJobQueueStore(), newMockStockIndexStore())
+	ctx := context.Background()
+
+	success := `vire_jobs_processed_total{job_type="collect_news",status="success"}`
+	failure := `vire_jobs_processed_total{job_type="not_a_job",status="error"}`
+	beforeSuccess, beforeFailure := max(scrapeMetric(t, success), 0), max(scrapeMetric(t, failure), 0)
+
+	if err := jm.executeJob(ctx, &models.Job{JobType: models.JobTypeCollectNews, Ticker: "BHP.AU"}); err != nil {
+		t.Fatalf("executeJob: %v", err)
+	}
+	if err := jm.executeJob(ctx, &models.Job{JobType: "not_a_job"}); err == nil {
+		t.Fatal("expected an error for an unknown job type")
+	}
+
+	if got := scrapeMetric(t, success); got != beforeSuccess+1 {
+		t.Errorf("%s = %v, want %v", success, got, beforeSuccess+1)
+	}
+	if got := scrapeMetric(t, failure); got != beforeFailure+1 {
+		t.Errorf("%s = %v, want %v", failure, got, beforeFailure+1)
+	}
+	if got := scrapeMetric(t, `vire_job_duration_seconds_count{job_type="collect_news"}`); got <= 0 {
+		t.Errorf("job duration histogram count = %v, want > 0", got)
+	}
+}
+
+func TestExecuteJob_PanicCountedAsError(t *testing.T) {
+	jm := newTestJobManager(newMockJobQueueStore(), newMockStockIndexStore())
+	jm.market = nil // dispatch dereferences the nil service and panics
+
+	series := `vire_jobs_processed_total{job_type="collect_eod",status="error"}`
+	before := max(scrapeMetric(t, series), 0)
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("expected executeJob to panic")
+			}
+		}()
+		jm.executeJob(context.Background(), &models.Job{JobType: models.JobTypeCollectEOD, Ticker: "BHP.AU"})
+	}()
+
+	if got := scrapeMetric(t, series); got != before+1 {
+		t.Errorf("%s = %v, want %v", series, got, before+1)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/bobmcallan/vire/internal/common"
 	"github.com/bobmcallan/vire/internal/models"
 	"github.com/google/uuid"
 )
@@ -34,6 +35,12 @@ func (jm *JobManager) dequeue(ctx context.Context) (*models.Job, error) {
 		return job, err
 	}
 
+	eligibleAt := job.CreatedAt
+	if job.NextAttemptAt.After(eligibleAt) {
+		eligibleAt = job.NextAttemptAt
+	}
+	common.ObserveJobDequeue(eligibleAt)
+
 	if jm.hub != nil {
 		pending, _ := jm.storage.JobQueueStore().CountPending(ctx)
 		jm.hub.Broadcast(models.JobEvent{
@@ -47,9 +54,32 @@ func (jm *JobManager) dequeue(ctx context.Context) (*models.Job, error) {
 	return job, nil
 }
 
+// deadLetter parks a job that failed on its final attempt, keeping the
+// error. The record is upserted so the job keeps its ID for requeueing.
+func (jm *JobManager) deadLetter(ctx context.Context, job *models.Job, execErr error, durationMS int64) error {
+	job.Status = models.JobStatusDeadLetter
+	job.Error = execErr.Error()
+	job.CompletedAt = time.Now()
+	job.DurationMS = durationMS
+	return jm.storage.JobQueueStore().Enqueue(ctx, job)
+}
+
 // complete marks a job as completed/failed and broadcasts the corresponding event.
+// A failed job with no attempts left is moved to the dead letter status instead.
 func (jm *JobManager) complete(ctx context.Context, job *models.Job, execErr error, durationMS int64) {
-	if err := jm.storage.JobQueueStore().Complete(ctx, job.ID, execErr, durationMS); err != nil {
+	deadLetter := execErr != nil && job.Attempts >= job.MaxAttempts
+	if deadLetter {
+		jm.logger.Warn().
+			Str("job_id", job.ID).
+			Str("job_type", job.JobType).
+			Str("ticker", job.Ticker).
+			Int("attempts", job.Attempts).
+			Err(execErr).
+			Msg("Job exhausted its attempts — moving to dead letter")
+		if err := jm.deadLetter(ctx, job, execErr, durationMS); err != nil {
+			jm.logger.Warn().Str("job_id", job.ID).Err(err).Msg("Failed to dead-letter job in queue")
+		}
+	} else if err := jm.storage.JobQueueStore().Complete(ctx, job.ID, execErr, durationMS); err != nil {
 		jm.logger.Warn().Str("job_id", job.ID).Err(err).Msg("Failed to complete job in queue")
 	}
 
@@ -66,7 +96,9 @@ func (jm *JobManager) complete(ctx context.Context, job *models.Job, execErr err
 		pending, _ := jm.storage.JobQueueStore().CountPending(ctx)
 		// Update job fields for the broadcast
 		job.DurationMS = durationMS
-		if execErr != nil {
+		if deadLetter {
+			eventType = "job_dead_letter"
+		} else if execErr != nil {
 			job.Status = models.JobStatusFailed
 			job.Error = execErr.Error()
 		} else {
@@ -90,15 +122,16 @@ func (jm *JobManager) PushToTop(ctx context.Context, id string) error {
 	return jm.storage.JobQueueStore().SetPriority(ctx, id, maxPriority+1)
 }
 
-// EnqueueIfNeeded checks for an existing pending job with the same type+ticker
-// and only enqueues if none exists (dedup).
-func (jm *JobManager) EnqueueIfNeeded(ctx context.Context, jobType, ticker string, priority int) error {
-	exists, err := jm.storage.JobQueueStore().HasPendingJob(ctx, jobType, ticker)
+// EnqueueIfNeeded checks for an existing pending or running job with the same
+// type+ticker and only enqueues if none exists (dedup). enqueued is false when
+// an existing job made the call a no-op.
+func (jm *JobManager) EnqueueIfNeeded(ctx context.Context, jobType, ticker string, priority int) (enqueued bool, err error) {
+	exists, err := jm.storage.JobQueueStore().HasActiveJob(ctx, jobType, ticker)
 	if err != nil {
-		return err
+		return false, err
 	}
 	if exists {
-		return nil // Already queued
+		return false, nil // Already queued
 	}
 
 	job := &models.Job{
@@ -109,7 +142,10 @@ func (jm *JobManager) EnqueueIfNeeded(ctx context.Context, jobType, ticker strin
 		CreatedAt:   time.Now(),
 		MaxAttempts: jm.config.GetMaxRetries(),
 	}
-	return jm.enqueue(ctx, job)
+	if err := jm.enqueue(ctx, job); err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
 // EnqueueBatchRefresh enqueues core refresh jobs (EOD, fundamentals, signals)
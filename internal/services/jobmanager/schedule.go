@@ -0,0 +1,169 @@
+package jobmanager
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// reportScheduleLastRunKey is the InternalStore system key holding the last
+// scheduled report slot that was enqueued (RFC 3339).
+const reportScheduleLastRunKey = "report_schedule_last_run"
+
+// reportScheduleLookback bounds how far back a missed slot is still fired,
+// e.g. when the server was down at the scheduled time.
+const reportScheduleLookback = 24 * time.Hour
+
+// cronSchedule is a parsed five-field cron expression:
+// minute hour day-of-month month day-of-week.
+type cronSchedule struct {
+	minute, hour, dom, month, dow map[int]bool
+	domAny, dowAny                bool
+}
+
+// parseCronSchedule parses a standard five-field cron expression. Each field
+// accepts "*", single values, ranges ("1-5"), lists ("1,3,5") and steps
+// ("*/15", "0-30/10"). Day-of-week is 0-6 from Sunday; 7 is also Sunday.
+func parseCronSchedule(expr string) (*cronSchedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron schedule %q: expected 5 fields, got %d", expr, len(fields))
+	}
+	bounds := [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+	sets := make([]map[int]bool, 5)
+	for i, f := range fields {
+		set, err := parseCronField(f, bounds[i][0], bounds[i][1])
+		if err != nil {
+			return nil, fmt.Errorf("cron schedule %q: %w", expr, err)
+		}
+		sets[i] = set
+	}
+	if sets[4][7] {
+		sets[4][0] = true
+	}
+	return &cronSchedule{
+		minute: sets[0],
+		hour:   sets[1],
+		dom:    sets[2],
+		month:  sets[3],
+		dow:    sets[4],
+		domAny: fields[2] == "*",
+		dowAny: fields[4] == "*",
+	}, nil
+}
+
+func parseCronField(field string, min, max int) (map[int]bool, error) {
+	set := make(map[int]bool)
+	for _, part := range strings.Split(field, ",") {
+		rng, stepStr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepStr)
+			if err != nil || n <= 0 {
+				return nil, fmt.Errorf("invalid step %q", part)
+			}
+			step = n
+		}
+
+		lo, hi := min, max
+		if rng != "*" {
+			loStr, hiStr, isRange := strings.Cut(rng, "-")
+			var err error
+			if lo, err = strconv.Atoi(loStr); err != nil {
+				return nil, fmt.Errorf("invalid value %q", part)
+			}
+			hi = lo
+			if isRange {
+				if hi, err = strconv.Atoi(hiStr); err != nil {
+					return nil, fmt.Errorf("invalid range %q", part)
+				}
+			} else if hasStep {
+				hi = max
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return nil, fmt.Errorf("%q out of range %d-%d", part, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			set[v] = true
+		}
+	}
+	return set, nil
+}
+
+// matches reports whether t falls on a scheduled minute. As in cron, when
+// both day fields are restricted a day matching either one qualifies.
+func (c *cronSchedule) matches(t time.Time) bool {
+	if !c.minute[t.Minute()] || !c.hour[t.Hour()] || !c.month[int(t.Month())] {
+		return false
+	}
+	domOK, dowOK := c.dom[t.Day()], c.dow[int(t.Weekday())]
+	switch {
+	case c.domAny && c.dowAny:
+		return true
+	case c.domAny:
+		return dowOK
+	case c.dowAny:
+		return domOK
+	default:
+		return domOK || dowOK
+	}
+}
+
+// prev returns the latest scheduled minute in (now-lookback, now], or false.
+func (c *cronSchedule) prev(now time.Time, lookback time.Duration) (time.Time, bool) {
+	earliest := now.Add(-lookback)
+	for t := now.Truncate(time.Minute); t.After(earliest); t = t.Add(-time.Minute) {
+		if c.matches(t) {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// checkReportSchedule enqueues a report job for the default portfolio when a
+// scheduled slot has passed since the last one fired. The slot is recorded in
+// the InternalStore so a restart does not fire it again.
+func (jm *JobManager) checkReportSchedule(ctx context.Context, now time.Time) {
+	expr := strings.TrimSpace(jm.config.ReportSchedule)
+	if expr == "" {
+		return
+	}
+	sched, err := parseCronSchedule(expr)
+	if err != nil {
+		jm.logger.Warn().Err(err).Msg("Watcher: invalid report schedule")
+		return
+	}
+
+	slot, ok := sched.prev(now.In(jm.config.GetReportLocation()), reportScheduleLookback)
+	if !ok {
+		return
+	}
+
+	store := jm.storage.InternalStore()
+	if last, err := store.GetSystemKV(ctx, reportScheduleLastRunKey); err == nil && last != "" {
+		if lastRun, err := time.Parse(time.RFC3339, last); err == nil && !slot.After(lastRun) {
+			return // already fired for this slot
+		}
+	}
+
+	portfolioName := common.ResolveDefaultPortfolio(ctx, store)
+	if portfolioName == "" {
+		jm.logger.Warn().Msg("Watcher: report schedule set but no default portfolio configured")
+		return
+	}
+
+	if _, err := jm.EnqueueIfNeeded(ctx, models.JobTypeGenerateReport, portfolioName, models.PriorityGenerateReport); err != nil {
+		jm.logger.Warn().Err(err).Str("portfolio", portfolioName).Msg("Watcher: failed to enqueue scheduled report")
+		return
+	}
+	if err := store.SetSystemKV(ctx, reportScheduleLastRunKey, slot.Format(time.RFC3339)); err != nil {
+		jm.logger.Warn().Err(err).Msg("Watcher: failed to record report schedule run")
+	}
+	jm.logger.Info().Str("portfolio", portfolioName).Str("slot", slot.Format(time.RFC3339)).Msg("Watcher: enqueued scheduled report")
+}
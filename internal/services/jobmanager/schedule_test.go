@@ -0,0 +1,142 @@
+package jobmanager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/interfaces"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+type mockReportService struct {
+	generated []string
+}
+
+func (m *mockReportService) GetReport(_ context.Context, _ string) (*models.PortfolioReport, error) {
+	return nil, nil
+}
+func (m *mockReportService) GenerateReport(_ context.Context, name string, _ interfaces.ReportOptions) (*models.PortfolioReport, error) {
+	m.generated = append(m.generated, name)
+	return &models.PortfolioReport{Portfolio: name}, nil
+}
+func (m *mockReportService) GenerateTickerReport(_ context.Context, _, _ string) (*models.PortfolioReport, error) {
+	return nil, nil
+}
+func (m *mockReportService) GeneratePDF(_ context.Context, _, _ string) ([]byte, error) {
+	return nil, nil
+}
+func (m *mockReportService) SearchReports(_ context.Context, _ string, _ interfaces.ReportSearchOptions) ([]models.ReportSearchResult, error) {
+	return nil, nil
+}
+
+func (m *mockReportService) FormatAlertsMarkdown(_ context.Context, _ string) (string, error) {
+	return "", nil
+}
+
+func (m *mockReportService) ExportTradesForImport(_ context.Context, _, _ string) (*models.TradeImportExport, error) {
+	return nil, nil
+}
+
+func newScheduleTestJobManager(schedule string) (*JobManager, *mockJobQueueStore) {
+	queue := newMockJobQueueStore()
+	jm := newTestJobManager(queue, newMockStockIndexStore())
+	jm.config.ReportSchedule = schedule
+	jm.config.ReportTimezone = "UTC"
+	jm.storage.InternalStore().SetSystemKV(context.Background(), "default_portfolio", "SMSF")
+	return jm, queue
+}
+
+func reportJobs(queue *mockJobQueueStore) []*models.Job {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	var out []*models.Job
+	for _, j := range queue.jobs {
+		if j.JobType == models.JobTypeGenerateReport {
+			out = append(out, j)
+		}
+	}
+	return out
+}
+
+func TestCheckReportSchedule_PastSlotEnqueuesOnce(t *testing.T) {
+	jm, queue := newScheduleTestJobManager("0 7 * * 1-5")
+	ctx := context.Background()
+	now := time.Date(2026, 3, 4, 9, 30, 0, 0, time.UTC) // Wednesday, after 07:00
+
+	jm.checkReportSchedule(ctx, now)
+	jm.checkReportSchedule(ctx, now.Add(time.Minute))
+
+	jobs := reportJobs(queue)
+	if len(jobs) != 1 {
+		t.Fatalf("report jobs = %d, want 1", len(jobs))
+	}
+	if jobs[0].Ticker != "SMSF" || jobs[0].Priority != models.PriorityGenerateReport {
+		t.Errorf("job = %s/%d, want SMSF/%d", jobs[0].Ticker, jobs[0].Priority, models.PriorityGenerateReport)
+	}
+
+	// The slot survives a restart: a fresh manager on the same store does not refire
+	queue.jobs[0].Status = models.JobStatusCompleted
+	restarted := newTestJobManager(queue, newMockStockIndexStore())
+	restarted.storage = jm.storage
+	restarted.config = jm.config
+	restarted.checkReportSchedule(ctx, now.Add(2*time.Minute))
+	if got := len(reportJobs(queue)); got != 1 {
+		t.Errorf("report jobs after restart = %d, want 1", got)
+	}
+
+	// The next day's slot fires again
+	jm.checkReportSchedule(ctx, now.Add(24*time.Hour))
+	if got := len(reportJobs(queue)); got != 2 {
+		t.Errorf("report jobs next day = %d, want 2", got)
+	}
+}
+
+func TestCheckReportSchedule_FutureSlotEnqueuesNone(t *testing.T) {
+	jm, queue := newScheduleTestJobManager("30 9 4 3 *") // 09:30 on 4 March
+	now := time.Date(2026, 3, 4, 9, 0, 0, 0, time.UTC)
+
+	jm.checkReportSchedule(context.Background(), now)
+
+	if got := len(reportJobs(queue)); got != 0 {
+		t.Errorf("report jobs = %d, want 0 before the scheduled time", got)
+	}
+}
+
+func TestParseCronSchedule(t *testing.T) {
+	sched, err := parseCronSchedule("*/15 7-9 * * 1,3,5")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	wed := time.Date(2026, 3, 4, 8, 45, 0, 0, time.UTC)
+	if !sched.matches(wed) {
+		t.Error("expected Wednesday 08:45 to match")
+	}
+	if sched.matches(wed.Add(time.Minute)) || sched.matches(wed.AddDate(0, 0, 1)) {
+		t.Error("expected 08:46 and Thursday not to match")
+	}
+
+	for _, bad := range []string{"", "0 7 * *", "60 * * * *", "0 7 * * 1-9", "*/0 * * * *", "a * * * *"} {
+		if _, err := parseCronSchedule(bad); err == nil {
+			t.Errorf("parseCronSchedule(%q) should fail", bad)
+		}
+	}
+}
+
+func TestExecuteJob_GenerateReport(t *testing.T) {
+	jm := newTestJobManager(newMockJobQueueStore(), newMockStockIndexStore())
+	job := &models.Job{JobType: models.JobTypeGenerateReport, Ticker: "SMSF"}
+
+	if err := jm.executeJob(context.Background(), job); err == nil {
+		t.Error("expected an error without a report service")
+	}
+
+	reports := &mockReportService{}
+	jm.SetReportService(reports)
+	if err := jm.executeJob(context.Background(), job); err != nil {
+		t.Fatalf("executeJob: %v", err)
+	}
+	if len(reports.generated) != 1 || reports.generated[0] != "SMSF" {
+		t.Errorf("generated = %v, want [SMSF]", reports.generated)
+	}
+}
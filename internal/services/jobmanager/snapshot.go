@@ -0,0 +1,42 @@
+package jobmanager
+
+import (
+	"context"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// portfolioSnapshotLastRunKey is the InternalStore system key holding the UTC
+// date (YYYY-MM-DD) the last daily portfolio snapshot was enqueued.
+const portfolioSnapshotLastRunKey = "portfolio_snapshot_last_run"
+
+// checkPortfolioSnapshot enqueues one snapshot_portfolio job per UTC day for
+// the default portfolio. The date is recorded in the InternalStore so a
+// restart on the same day does not enqueue another; the snapshot itself is
+// keyed by date, so a repeat run would replace rather than duplicate it.
+func (jm *JobManager) checkPortfolioSnapshot(ctx context.Context, now time.Time) {
+	if jm.portfolio == nil {
+		return
+	}
+
+	today := now.UTC().Format("2006-01-02")
+	store := jm.storage.InternalStore()
+	if last, err := store.GetSystemKV(ctx, portfolioSnapshotLastRunKey); err == nil && last == today {
+		return
+	}
+
+	portfolioName := common.ResolveDefaultPortfolio(ctx, store)
+	if portfolioName == "" {
+		return
+	}
+
+	if _, err := jm.EnqueueIfNeeded(ctx, models.JobTypeSnapshotPortfolio, portfolioName, models.PrioritySnapshotPortfolio); err != nil {
+		jm.logger.Warn().Err(err).Str("portfolio", portfolioName).Msg("Watcher: failed to enqueue portfolio snapshot")
+		return
+	}
+	if err := store.SetSystemKV(ctx, portfolioSnapshotLastRunKey, today); err != nil {
+		jm.logger.Warn().Err(err).Msg("Watcher: failed to record portfolio snapshot run")
+	}
+}
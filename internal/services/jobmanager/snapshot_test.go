@@ -0,0 +1,60 @@
+package jobmanager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/interfaces"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// mockSnapshotPortfolioService records SnapshotPortfolio calls
+type mockSnapshotPortfolioService struct {
+	interfaces.PortfolioService
+	snapshots []string
+}
+
+func (m *mockSnapshotPortfolioService) SnapshotPortfolio(_ context.Context, name string) (*models.DailyPortfolioSnapshot, error) {
+	m.snapshots = append(m.snapshots, name)
+	return &models.DailyPortfolioSnapshot{PortfolioName: name}, nil
+}
+
+func TestCheckPortfolioSnapshot_OncePerDay(t *testing.T) {
+	queue := newMockJobQueueStore()
+	jm := newTestJobManager(queue, newMockStockIndexStore())
+	ctx := context.Background()
+	jm.storage.InternalStore().SetSystemKV(ctx, "default_portfolio", "SMSF")
+	now := time.Date(2026, 3, 4, 9, 0, 0, 0, time.UTC)
+
+	// No portfolio service: nothing scheduled
+	jm.checkPortfolioSnapshot(ctx, now)
+	if n, _ := queue.CountPending(ctx); n != 0 {
+		t.Fatalf("pending = %d without a portfolio service, want 0", n)
+	}
+
+	portfolio := &mockSnapshotPortfolioService{}
+	jm.SetPortfolioService(portfolio)
+	jm.checkPortfolioSnapshot(ctx, now)
+	jm.checkPortfolioSnapshot(ctx, now.Add(6*time.Hour)) // same day, e.g. after a restart
+	job, _ := queue.Dequeue(ctx)
+	if job == nil || job.JobType != models.JobTypeSnapshotPortfolio || job.Ticker != "SMSF" {
+		t.Fatalf("dequeued %+v, want a snapshot_portfolio job for SMSF", job)
+	}
+	if next, _ := queue.Dequeue(ctx); next != nil {
+		t.Errorf("second job %s enqueued on the same day", next.JobType)
+	}
+
+	if err := jm.executeJob(ctx, job); err != nil {
+		t.Fatalf("executeJob: %v", err)
+	}
+	if len(portfolio.snapshots) != 1 || portfolio.snapshots[0] != "SMSF" {
+		t.Errorf("snapshots = %v, want [SMSF]", portfolio.snapshots)
+	}
+
+	queue.Complete(ctx, job.ID, nil, 0)
+	jm.checkPortfolioSnapshot(ctx, now.Add(16*time.Hour)) // next UTC day
+	if next, _ := queue.Dequeue(ctx); next == nil || next.JobType != models.JobTypeSnapshotPortfolio {
+		t.Errorf("expected a new snapshot job the next day, got %+v", next)
+	}
+}
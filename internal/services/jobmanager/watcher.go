@@ -23,13 +23,16 @@ func (jm *JobManager) watchLoop(ctx context.Context) {
 		}
 	}
 
-	interval := jm.config.GetWatcherInterval()
+	interval := time.Duration(jm.watcherInterval.Load())
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
 	backoff := time.Duration(0)
 
 	scan := func() {
+		jm.checkReportSchedule(ctx, time.Now())
+		jm.checkEODCompaction(ctx, time.Now())
+		jm.checkPortfolioSnapshot(ctx, time.Now())
 		if ok := jm.scanStockIndex(ctx); ok {
 			backoff = 0
 		} else {
@@ -60,6 +63,11 @@ func (jm *JobManager) watchLoop(ctx context.Context) {
 			return
 		case <-ticker.C:
 			scan()
+			if next := time.Duration(jm.watcherInterval.Load()); next != interval {
+				jm.logger.Info().Dur("interval", next).Msg("Watcher: scan interval changed")
+				interval = next
+				ticker.Reset(interval)
+			}
 		}
 	}
 }
@@ -102,21 +110,21 @@ func (jm *JobManager) scanStockIndex(ctx context.Context) bool {
 		}
 	}
 	for exchange := range staleLiveExchanges {
-		if err := jm.EnqueueIfNeeded(ctx, models.JobTypeCollectLivePrices, exchange, models.PriorityCollectLivePrices); err != nil {
+		if added, err := jm.EnqueueIfNeeded(ctx, models.JobTypeCollectLivePrices, exchange, models.PriorityCollectLivePrices); err != nil {
 			jm.logger.Warn().Str("exchange", exchange).Err(err).Msg("Watcher: failed to enqueue live price job")
-		} else {
+		} else if added {
 			enqueued++
 		}
 	}
 
 	// Enqueue one bulk EOD job per exchange that has stale tickers
 	for exchange := range staleEODExchanges {
-		if err := jm.EnqueueIfNeeded(ctx, models.JobTypeCollectEODBulk, exchange, models.PriorityCollectEODBulk); err != nil {
+		if added, err := jm.EnqueueIfNeeded(ctx, models.JobTypeCollectEODBulk, exchange, models.PriorityCollectEODBulk); err != nil {
 			jm.logger.Warn().
 				Str("exchange", exchange).
 				Err(err).
 				Msg("Watcher: failed to enqueue bulk EOD job")
-		} else {
+		} else if added {
 			enqueued++
 		}
 	}
@@ -191,13 +199,13 @@ func (jm *JobManager) enqueueStaleJobs(ctx context.Context, entry *models.StockI
 			if isNew {
 				priority = models.PriorityNewStock
 			}
-			if err := jm.EnqueueIfNeeded(ctx, c.jobType, entry.Ticker, priority); err != nil {
+			if added, err := jm.EnqueueIfNeeded(ctx, c.jobType, entry.Ticker, priority); err != nil {
 				jm.logger.Warn().
 					Str("ticker", entry.Ticker).
 					Str("job_type", c.jobType).
 					Err(err).
 					Msg("Watcher: failed to enqueue job")
-			} else {
+			} else if added {
 				enqueued++
 			}
 		}
@@ -209,7 +217,8 @@ func (jm *JobManager) enqueueStaleJobs(ctx context.Context, entry *models.StockI
 // EnqueueTickerJobs enqueues background jobs for stale data components
 // across the given tickers. Respects freshness TTLs — only stale
 // components are enqueued. Intended for demand-driven collection
-// triggered by portfolio requests.
+// triggered by portfolio requests. Returns the number of jobs newly
+// enqueued; deduped jobs are not counted.
 func (jm *JobManager) EnqueueTickerJobs(ctx context.Context, tickers []string) int {
 	enqueued := 0
 	staleEODExchanges := make(map[string]bool)
@@ -230,7 +239,7 @@ func (jm *JobManager) EnqueueTickerJobs(ctx context.Context, tickers []string) i
 
 	// Enqueue bulk EOD per exchange (same as watcher)
 	for exchange := range staleEODExchanges {
-		if err := jm.EnqueueIfNeeded(ctx, models.JobTypeCollectEODBulk, exchange, models.PriorityCollectEODBulk); err == nil {
+		if added, _ := jm.EnqueueIfNeeded(ctx, models.JobTypeCollectEODBulk, exchange, models.PriorityCollectEODBulk); added {
 			enqueued++
 		}
 	}
@@ -243,7 +252,8 @@ func (jm *JobManager) EnqueueTickerJobs(ctx context.Context, tickers []string) i
 
 // EnqueueSlowDataJobs enqueues background jobs for slow data components
 // (filings PDFs, AI summaries, timeline, news intel) for a single ticker.
-// Bypasses freshness checks — always enqueues if no pending job exists.
+// Bypasses freshness checks — always enqueues if no pending or running job
+// exists. Returns the number of jobs newly enqueued; deduped jobs are not counted.
 // Intended for force-refresh of individual stock data.
 // Note: Filing index is collected in the fast path (CollectCoreMarketData).
 func (jm *JobManager) EnqueueSlowDataJobs(ctx context.Context, ticker string) int {
@@ -278,7 +288,7 @@ func (jm *JobManager) EnqueueSlowDataJobs(ctx context.Context, ticker string) in
 		if !j.ready {
 			continue
 		}
-		if err := jm.EnqueueIfNeeded(ctx, j.jobType, ticker, j.priority); err == nil {
+		if added, _ := jm.EnqueueIfNeeded(ctx, j.jobType, ticker, j.priority); added {
 			enqueued++
 		}
 	}
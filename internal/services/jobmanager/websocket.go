@@ -23,9 +23,13 @@ type JobWSHub struct {
 	broadcast  chan models.JobEvent
 	register   chan *JobWSClient
 	unregister chan *JobWSClient
-	done       chan struct{}
 	mu         sync.RWMutex
 	logger     *common.Logger
+
+	// done is closed by Stop; restart replaces it so the hub can run again.
+	lifecycle sync.Mutex
+	done      chan struct{}
+	stopped   bool
 }
 
 // JobWSClient represents a connected WebSocket client.
@@ -48,10 +52,18 @@ func NewJobWSHub(logger *common.Logger) *JobWSHub {
 }
 
 // Run starts the hub's main event loop. Should be called as a goroutine.
+// It returns once Stop is called, closing every client's connection.
 func (h *JobWSHub) Run() {
+	done := h.doneChan()
 	for {
 		select {
-		case <-h.done:
+		case <-done:
+			h.mu.Lock()
+			for client := range h.clients {
+				delete(h.clients, client)
+				close(client.send)
+			}
+			h.mu.Unlock()
 			return
 
 		case client := <-h.register:
@@ -76,39 +88,69 @@ func (h *JobWSHub) Run() {
 				continue
 			}
 
-			h.mu.RLock()
-			var slow []*JobWSClient
-			for client := range h.clients {
-				select {
-				case client.send <- data:
-				default:
-					slow = append(slow, client)
-				}
-			}
-			h.mu.RUnlock()
-
-			if len(slow) > 0 {
-				h.mu.Lock()
-				for _, c := range slow {
-					delete(h.clients, c)
-					close(c.send)
-				}
-				h.mu.Unlock()
-			}
+			h.deliver(data)
 		}
 	}
 }
 
-// Stop signals the hub's event loop to exit.
+// deliver queues data on every client's send channel. Clients whose buffer
+// is full are collected under the read lock and evicted afterwards in a
+// single write-locked pass, so the client map is never modified mid-range.
+func (h *JobWSHub) deliver(data []byte) {
+	h.mu.RLock()
+	var slow []*JobWSClient
+	for client := range h.clients {
+		select {
+		case client.send <- data:
+		default:
+			slow = append(slow, client)
+		}
+	}
+	h.mu.RUnlock()
+
+	if len(slow) == 0 {
+		return
+	}
+	h.mu.Lock()
+	for _, c := range slow {
+		if _, ok := h.clients[c]; ok {
+			delete(h.clients, c)
+			close(c.send)
+		}
+	}
+	h.mu.Unlock()
+	h.logger.Debug().Int("evicted", len(slow)).Msg("Dropped slow WebSocket clients")
+}
+
+// Stop signals the hub's event loop to exit. Safe to call more than once,
+// and concurrently.
 func (h *JobWSHub) Stop() {
-	select {
-	case <-h.done:
-		// Already stopped
-	default:
+	h.lifecycle.Lock()
+	defer h.lifecycle.Unlock()
+	if !h.stopped {
 		close(h.done)
+		h.stopped = true
+	}
+}
+
+// restart re-arms a stopped hub so Run can be started again. It must be
+// called before the new Run goroutine is launched, so a Stop issued
+// straight after is not lost.
+func (h *JobWSHub) restart() {
+	h.lifecycle.Lock()
+	defer h.lifecycle.Unlock()
+	if h.stopped {
+		h.done = make(chan struct{})
+		h.stopped = false
 	}
 }
 
+func (h *JobWSHub) doneChan() chan struct{} {
+	h.lifecycle.Lock()
+	defer h.lifecycle.Unlock()
+	return h.done
+}
+
 // Broadcast sends a job event to all connected clients.
 func (h *JobWSHub) Broadcast(event models.JobEvent) {
 	select {
@@ -132,7 +174,12 @@ func (h *JobWSHub) ServeWS(w http.ResponseWriter, r *http.Request) {
 		send: make(chan []byte, 256),
 	}
 
-	h.register <- client
+	select {
+	case h.register <- client:
+	case <-h.doneChan():
+		conn.Close()
+		return
+	}
 
 	go client.writePump()
 	go client.readPump()
@@ -177,7 +224,11 @@ func (c *JobWSClient) writePump() {
 // readPump reads messages from the WebSocket connection (mainly to detect close).
 func (c *JobWSClient) readPump() {
 	defer func() {
-		c.hub.unregister <- c
+		// The hub may already have stopped and dropped this client.
+		select {
+		case c.hub.unregister <- c:
+		case <-c.hub.doneChan():
+		}
 		c.conn.Close()
 	}()
 
@@ -3,6 +3,7 @@ package market
 import (
 	"context"
 	"fmt"
+	"io"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -96,7 +97,13 @@ func (m *bulkTestStorage) PurgeDerivedData(_ context.Context) (map[string]int, e
 	return nil, nil
 }
 func (m *bulkTestStorage) PurgeReports(_ context.Context) (int, error) { return 0, nil }
-func (m *bulkTestStorage) Close() error                                { return nil }
+func (m *bulkTestStorage) Backup(_ context.Context, _ io.Writer) (*models.BackupManifest, error) {
+	return nil, nil
+}
+func (m *bulkTestStorage) Restore(_ context.Context, _ io.Reader) (*models.BackupManifest, error) {
+	return nil, nil
+}
+func (m *bulkTestStorage) Close() error { return nil }
 
 // Aliases used by other test files (e.g. test-creator tests in service_test.go)
 type mockStorageManagerWithIndex = bulkTestStorage
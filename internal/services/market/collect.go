@@ -14,6 +14,7 @@ import (
 // the bulk API, merges into existing data, and falls back to individual
 // CollectEOD for tickers with no existing EOD history.
 func (s *Service) CollectBulkEOD(ctx context.Context, exchange string, force bool) error {
+	logger := s.logger.WithRequestID(ctx)
 	now := time.Now()
 
 	if s.eodhd == nil {
@@ -33,7 +34,7 @@ func (s *Service) CollectBulkEOD(ctx context.Context, exchange string, force boo
 		}
 	}
 	if len(tickers) == 0 {
-		s.logger.Debug().Str("exchange", exchange).Msg("No tickers for exchange in stock index")
+		logger.Debug().Str("exchange", exchange).Msg("No tickers for exchange in stock index")
 		return nil
 	}
 
@@ -51,15 +52,15 @@ func (s *Service) CollectBulkEOD(ctx context.Context, exchange string, force boo
 
 		// No existing EOD data — fall back to individual full-history fetch
 		if existing == nil || len(existing.EOD) == 0 {
-			s.logger.Debug().Str("ticker", ticker).Msg("No existing EOD, falling back to individual CollectEOD")
+			logger.Debug().Str("ticker", ticker).Msg("No existing EOD, falling back to individual CollectEOD")
 			if err := s.CollectEOD(ctx, ticker, force); err != nil {
-				s.logger.Warn().Str("ticker", ticker).Err(err).Msg("Individual EOD fallback failed")
+				logger.Warn().Str("ticker", ticker).Err(err).Msg("Individual EOD fallback failed")
 			} else {
 				// Only update stock index timestamp if EOD data was actually stored
 				updated, _ := s.storage.MarketDataStorage().GetMarketData(ctx, ticker)
 				if updated != nil && len(updated.EOD) > 0 {
 					if err := s.storage.StockIndexStore().UpdateTimestamp(ctx, ticker, "eod_collected_at", now); err != nil {
-						s.logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to update stock index EOD timestamp")
+						logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to update stock index EOD timestamp")
 					}
 					fallbacks++
 				}
@@ -75,7 +76,7 @@ func (s *Service) CollectBulkEOD(ctx context.Context, exchange string, force boo
 		bar, ok := bulkBars[ticker]
 		if !ok {
 			// Ticker not in bulk response, skip
-			s.logger.Debug().Str("ticker", ticker).Msg("Ticker not in bulk EOD response")
+			logger.Debug().Str("ticker", ticker).Msg("Ticker not in bulk EOD response")
 			continue
 		}
 
@@ -94,27 +95,27 @@ func (s *Service) CollectBulkEOD(ctx context.Context, exchange string, force boo
 		marketData.LastUpdated = now
 
 		if err := s.storage.MarketDataStorage().SaveMarketData(ctx, marketData); err != nil {
-			s.logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to save market data after bulk EOD merge")
+			logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to save market data after bulk EOD merge")
 			continue
 		}
 
 		// Compute signals when EOD changed
 		if eodChanged {
 			tickerSignals := s.signalComputer.Compute(marketData)
-			if err := s.storage.SignalStorage().SaveSignals(ctx, tickerSignals); err != nil {
-				s.logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to save signals after bulk EOD")
+			if err := saveSignals(ctx, s.storage.SignalStorage(), tickerSignals); err != nil {
+				logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to save signals after bulk EOD")
 			}
 		}
 
 		// Update stock index timestamp per-ticker
 		if err := s.storage.StockIndexStore().UpdateTimestamp(ctx, ticker, "eod_collected_at", now); err != nil {
-			s.logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to update stock index EOD timestamp")
+			logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to update stock index EOD timestamp")
 		}
 
 		processed++
 	}
 
-	s.logger.Info().
+	logger.Info().
 		Str("exchange", exchange).
 		Int("tickers", len(tickers)).
 		Int("processed", processed).
@@ -141,22 +142,21 @@ func (s *Service) CollectEOD(ctx context.Context, ticker string, force bool) err
 		return nil
 	}
 
-	if s.eodhd == nil {
-		return fmt.Errorf("EODHD client not configured")
+	if len(s.providers) == 0 {
+		return errNoProvider
 	}
 
 	eodChanged := false
 
-	// Incremental fetch: only bars after the latest stored date
+	// Incremental fetch: bars from the trailing revision window onwards
 	if !force && existing != nil && len(existing.EOD) > 0 {
-		latestDate := existing.EOD[0].Date
-		fromDate := latestDate.AddDate(0, 0, 1)
+		fromDate := incrementalEODFrom(existing.EOD)
 		if fromDate.Before(now) {
-			eodResp, err := s.eodhd.GetEOD(ctx, ticker, interfaces.WithDateRange(fromDate, now))
+			eodResp, err := s.getEOD(ctx, ticker, interfaces.WithDateRange(fromDate, now))
 			if err != nil {
 				return fmt.Errorf("failed to fetch incremental EOD data: %w", err)
 			}
-			if len(eodResp.Data) > 0 {
+			if eodBarsChanged(eodResp.Data, existing.EOD) {
 				marketData.EOD = filterBadEODBars(mergeEODBars(eodResp.Data, existing.EOD), ticker, s.logger)
 				eodChanged = true
 			}
@@ -164,12 +164,12 @@ func (s *Service) CollectEOD(ctx context.Context, ticker string, force bool) err
 		marketData.EODUpdatedAt = now
 	} else {
 		// Full fetch
-		eodResp, err := s.eodhd.GetEOD(ctx, ticker, interfaces.WithDateRange(now.AddDate(-3, 0, 0), now))
+		eodResp, err := s.getEOD(ctx, ticker, interfaces.WithDateRange(now.AddDate(-3, 0, 0), now))
 		if err != nil {
 			return fmt.Errorf("failed to fetch EOD data: %w", err)
 		}
 		if len(eodResp.Data) == 0 {
-			s.logger.Warn().Str("ticker", ticker).Msg("EODHD returned empty EOD data for new ticker — will retry next cycle")
+			s.logger.WithRequestID(ctx).Warn().Str("ticker", ticker).Msg("EODHD returned empty EOD data for new ticker — will retry next cycle")
 		} else {
 			marketData.EOD = filterBadEODBars(eodResp.Data, ticker, s.logger)
 			marketData.EODUpdatedAt = now
@@ -187,8 +187,8 @@ func (s *Service) CollectEOD(ctx context.Context, ticker string, force bool) err
 	// Compute and save signals when EOD data changed
 	if eodChanged {
 		tickerSignals := s.signalComputer.Compute(marketData)
-		if err := s.storage.SignalStorage().SaveSignals(ctx, tickerSignals); err != nil {
-			s.logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to save signals after EOD collect")
+		if err := saveSignals(ctx, s.storage.SignalStorage(), tickerSignals); err != nil {
+			s.logger.WithRequestID(ctx).Warn().Str("ticker", ticker).Err(err).Msg("Failed to save signals after EOD collect")
 		}
 	}
 
@@ -214,11 +214,11 @@ func (s *Service) CollectFundamentals(ctx context.Context, ticker string, force
 		return nil
 	}
 
-	if s.eodhd == nil {
-		return fmt.Errorf("EODHD client not configured")
+	if len(s.providers) == 0 {
+		return errNoProvider
 	}
 
-	fundamentals, err := s.eodhd.GetFundamentals(ctx, ticker)
+	fundamentals, err := s.getFundamentals(ctx, ticker, marketData.Fundamentals)
 	if err != nil {
 		return fmt.Errorf("failed to fetch fundamentals: %w", err)
 	}
@@ -227,6 +227,7 @@ func (s *Service) CollectFundamentals(ctx context.Context, ticker string, force
 		s.enrichFundamentals(ctx, fundamentals)
 	}
 	marketData.Fundamentals = fundamentals
+	marketData.EarningsDate = earningsDate(fundamentals, now)
 	marketData.FundamentalsUpdatedAt = now
 	marketData.DataVersion = common.SchemaVersion
 	marketData.LastUpdated = now
@@ -391,7 +392,7 @@ func (s *Service) CollectFilingSummaries(ctx context.Context, ticker string, for
 	currentHash := filingSummaryPromptHash()
 	if marketData.FilingSummaryPromptHash != currentHash {
 		force = true
-		s.logger.Info().
+		s.logger.WithRequestID(ctx).Info().
 			Str("ticker", ticker).
 			Str("old_hash", marketData.FilingSummaryPromptHash).
 			Str("new_hash", currentHash).
@@ -484,6 +485,7 @@ func (s *Service) CollectTimeline(ctx context.Context, ticker string, force bool
 // for the given exchange and stores them on the MarketData record's LivePrice field.
 // Uses bulk real-time API (batches of 20). Does NOT modify EOD bars or trigger signals.
 func (s *Service) CollectLivePrices(ctx context.Context, exchange string) error {
+	logger := s.logger.WithRequestID(ctx)
 	now := time.Now()
 
 	if s.eodhd == nil {
@@ -502,7 +504,7 @@ func (s *Service) CollectLivePrices(ctx context.Context, exchange string) error
 		}
 	}
 	if len(tickers) == 0 {
-		s.logger.Debug().Str("exchange", exchange).Msg("No tickers for exchange in stock index")
+		logger.Debug().Str("exchange", exchange).Msg("No tickers for exchange in stock index")
 		return nil
 	}
 
@@ -531,7 +533,7 @@ func (s *Service) CollectLivePrices(ctx context.Context, exchange string) error
 
 		quotes, err := s.eodhd.GetBulkRealTimeQuotes(ctx, batch)
 		if err != nil {
-			s.logger.Warn().Err(err).Strs("batch", batch).Msg("Bulk real-time quote fetch failed")
+			logger.Warn().Err(err).Strs("batch", batch).Msg("Bulk real-time quote fetch failed")
 			continue
 		}
 
@@ -555,19 +557,19 @@ func (s *Service) CollectLivePrices(ctx context.Context, exchange string) error
 			md.LivePriceUpdatedAt = now
 
 			if err := s.storage.MarketDataStorage().SaveMarketData(ctx, md); err != nil {
-				s.logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to save live price")
+				logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to save live price")
 				continue
 			}
 
 			if err := s.storage.StockIndexStore().UpdateTimestamp(ctx, ticker, "live_price_collected_at", now); err != nil {
-				s.logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to update stock index live price timestamp")
+				logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to update stock index live price timestamp")
 			}
 
 			processed++
 		}
 	}
 
-	s.logger.Info().
+	logger.Info().
 		Str("exchange", exchange).
 		Int("eligible", len(eligible)).
 		Int("processed", processed).
@@ -0,0 +1,84 @@
+package market
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// CompactEOD downsamples a ticker's stored EOD bars older than keepDaily to one
+// bar per ISO week, keeping recent daily bars untouched for signals. Returns
+// the number of bars removed; nothing is saved when no bar changes.
+func (s *Service) CompactEOD(ctx context.Context, ticker string, keepDaily time.Duration) (int, error) {
+	if keepDaily <= 0 {
+		return 0, nil
+	}
+	md, err := s.storage.MarketDataStorage().GetMarketData(ctx, ticker)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get market data for %s: %w", ticker, err)
+	}
+	if md == nil || len(md.EOD) == 0 {
+		return 0, nil
+	}
+
+	compacted := compactEODBars(md.EOD, eodCompactionCutoff(time.Now(), keepDaily))
+	removed := len(md.EOD) - len(compacted)
+	if removed == 0 {
+		return 0, nil
+	}
+	md.EOD = compacted
+	if err := s.storage.MarketDataStorage().SaveMarketData(ctx, md); err != nil {
+		return 0, fmt.Errorf("failed to save compacted EOD for %s: %w", ticker, err)
+	}
+	s.logger.WithRequestID(ctx).Debug().Str("ticker", ticker).Int("removed", removed).Int("bars", len(compacted)).Msg("Compacted EOD history")
+	return removed, nil
+}
+
+// eodCompactionCutoff returns the Monday (UTC midnight) starting the week that
+// contains now-keepDaily, so only whole weeks are ever downsampled.
+func eodCompactionCutoff(now time.Time, keepDaily time.Duration) time.Time {
+	t := now.Add(-keepDaily).UTC()
+	t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	offset := (int(t.Weekday()) + 6) % 7 // days since Monday
+	return t.AddDate(0, 0, -offset)
+}
+
+// compactEODBars merges bars dated before cutoff into one bar per ISO week:
+// open from the week's first bar, close and adjusted close from its last,
+// the high and low extremes, and summed volume, dated on the last bar.
+// Bars are newest first, and so is the result. Weeks already holding a single
+// bar are unchanged, so compaction can run repeatedly.
+func compactEODBars(bars []models.EODBar, cutoff time.Time) []models.EODBar {
+	out := make([]models.EODBar, 0, len(bars))
+	i := 0
+	for i < len(bars) && !bars[i].Date.Before(cutoff) {
+		out = append(out, bars[i])
+		i++
+	}
+
+	for i < len(bars) {
+		year, week := bars[i].Date.ISOWeek()
+		agg := bars[i] // newest bar of the week supplies date and close
+		i++
+		for i < len(bars) {
+			y, w := bars[i].Date.ISOWeek()
+			if y != year || w != week {
+				break
+			}
+			b := bars[i]
+			agg.Open = b.Open
+			if b.High > agg.High {
+				agg.High = b.High
+			}
+			if b.Low > 0 && (agg.Low <= 0 || b.Low < agg.Low) {
+				agg.Low = b.Low
+			}
+			agg.Volume += b.Volume
+			i++
+		}
+		out = append(out, agg)
+	}
+	return out
+}
@@ -0,0 +1,118 @@
+package market
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// dailyBars returns one bar per weekday from start to end, newest first.
+// Close rises by 1 each bar so aggregation can be checked.
+func dailyBars(start, end time.Time) []models.EODBar {
+	var asc []models.EODBar
+	price := 10.0
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		if d.Weekday() == time.Saturday || d.Weekday() == time.Sunday {
+			continue
+		}
+		asc = append(asc, models.EODBar{Date: d, Open: price - 0.5, High: price + 1, Low: price - 1, Close: price, AdjClose: price, Volume: 100})
+		price++
+	}
+	bars := make([]models.EODBar, len(asc))
+	for i, b := range asc {
+		bars[len(asc)-1-i] = b
+	}
+	return bars
+}
+
+func TestCompactEODBars_DownsamplesOlderThanDailyWindow(t *testing.T) {
+	cutoff := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC) // a Monday
+	bars := dailyBars(time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 29, 0, 0, 0, 0, time.UTC))
+
+	got := compactEODBars(bars, cutoff)
+
+	weeks := make(map[[2]int]int)
+	var recent, old int
+	for i, b := range got {
+		if i > 0 && !b.Date.Before(got[i-1].Date) {
+			t.Fatalf("bars not newest first at %d: %s after %s", i, b.Date, got[i-1].Date)
+		}
+		if !b.Date.Before(cutoff) {
+			recent++
+			continue
+		}
+		old++
+		y, w := b.Date.ISOWeek()
+		weeks[[2]int{y, w}]++
+	}
+
+	// Recent bars keep daily granularity
+	wantRecent := 0
+	for _, b := range bars {
+		if !b.Date.Before(cutoff) {
+			wantRecent++
+		}
+	}
+	if recent != wantRecent {
+		t.Errorf("recent bars = %d, want %d (daily window untouched)", recent, wantRecent)
+	}
+	// 2023 has 52 ISO weeks (Jan 2 is the first Monday); one bar each
+	if old != 52 {
+		t.Errorf("old bars = %d, want 52 weekly bars", old)
+	}
+	for wk, n := range weeks {
+		if n != 1 {
+			t.Errorf("ISO week %v has %d bars, want 1", wk, n)
+		}
+	}
+
+	// Oldest week, Mon 2 Jan to Fri 6 Jan 2023: closes 10..14
+	first := got[len(got)-1]
+	if !first.Date.Equal(time.Date(2023, 1, 6, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("first weekly bar dated %s, want 2023-01-06 (last trading day)", first.Date.Format("2006-01-02"))
+	}
+	if first.Open != 9.5 || first.Close != 14 || first.AdjClose != 14 || first.High != 15 || first.Low != 9 || first.Volume != 500 {
+		t.Errorf("first weekly bar = %+v, want open 9.5 close 14 high 15 low 9 volume 500", first)
+	}
+
+	// A second pass changes nothing
+	if again := compactEODBars(got, cutoff); len(again) != len(got) {
+		t.Errorf("second compaction produced %d bars, want %d", len(again), len(got))
+	}
+}
+
+func TestCompactEOD_SavesCompactedHistory(t *testing.T) {
+	now := time.Now().UTC().Truncate(24 * time.Hour)
+	bars := dailyBars(now.AddDate(-3, 0, 0), now)
+	store := &mockMarketDataStorage{data: map[string]*models.MarketData{
+		"BHP.AU": {Ticker: "BHP.AU", EOD: bars},
+	}}
+	svc := NewService(&mockStorageManager{market: store}, nil, nil, common.NewLogger("error"))
+
+	keep := 2 * 365 * 24 * time.Hour
+	removed, err := svc.CompactEOD(context.Background(), "BHP.AU", keep)
+	if err != nil {
+		t.Fatalf("CompactEOD: %v", err)
+	}
+
+	md, _ := store.GetMarketData(context.Background(), "BHP.AU")
+	if removed <= 0 || len(md.EOD) != len(bars)-removed {
+		t.Fatalf("removed %d, stored %d of %d bars", removed, len(md.EOD), len(bars))
+	}
+	cutoff := eodCompactionCutoff(time.Now(), keep)
+	for i := 1; i < len(md.EOD); i++ {
+		if md.EOD[i].Date.Before(cutoff) {
+			y1, w1 := md.EOD[i].Date.ISOWeek()
+			y0, w0 := md.EOD[i-1].Date.ISOWeek()
+			if y0 == y1 && w0 == w1 {
+				t.Fatalf("two bars in ISO week %d-%d before the cutoff", y1, w1)
+			}
+		}
+	}
+	if !md.EOD[0].Date.Equal(bars[0].Date) {
+		t.Errorf("latest bar %s, want %s", md.EOD[0].Date, bars[0].Date)
+	}
+}
@@ -0,0 +1,42 @@
+package market
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bobmcallan/vire/internal/models"
+	"github.com/bobmcallan/vire/internal/signals"
+)
+
+// CorrelationMatrix computes pairwise correlations of daily returns over the
+// trailing window (in trading days; 0 = signals.DefaultCorrelationWindow)
+// from stored EOD bars. Pairs without MinCorrelationOverlap shared returns
+// are left out, as are tickers with no stored data. See
+// signals.ReturnCorrelations.
+func (s *Service) CorrelationMatrix(ctx context.Context, tickers []string, window int) (map[string]map[string]float64, error) {
+	seen := make(map[string]bool, len(tickers))
+	unique := make([]string, 0, len(tickers))
+	for _, t := range tickers {
+		t = strings.ToUpper(strings.TrimSpace(t))
+		if t != "" && !seen[t] {
+			seen[t] = true
+			unique = append(unique, t)
+		}
+	}
+	if len(unique) < 2 {
+		return nil, fmt.Errorf("at least two tickers are required for a correlation matrix")
+	}
+
+	allMarketData, err := s.storage.MarketDataStorage().GetMarketDataBatch(ctx, unique)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load market data: %w", err)
+	}
+	bars := make(map[string][]models.EODBar, len(allMarketData))
+	for _, md := range allMarketData {
+		if md != nil && seen[strings.ToUpper(md.Ticker)] {
+			bars[strings.ToUpper(md.Ticker)] = md.EOD
+		}
+	}
+	return signals.ReturnCorrelations(bars, window), nil
+}
@@ -53,13 +53,13 @@ func (s *Service) enrichETF(ctx context.Context, f *models.Fundamentals) {
 	// Fall back to Gemini's knowledge if URL context fails or no URL available
 	if err != nil || fundURL == "" {
 		if err != nil {
-			s.logger.Debug().Str("ticker", f.Ticker).Err(err).Msg("URL context failed, falling back to Gemini knowledge")
+			s.logger.WithRequestID(ctx).Debug().Str("ticker", f.Ticker).Err(err).Msg("URL context failed, falling back to Gemini knowledge")
 		}
 		response, err = s.gemini.GenerateContent(ctx, prompt)
 	}
 
 	if err != nil {
-		s.logger.Warn().Str("ticker", f.Ticker).Err(err).Msg("Failed to enrich ETF via Gemini")
+		s.logger.WithRequestID(ctx).Warn().Str("ticker", f.Ticker).Err(err).Msg("Failed to enrich ETF via Gemini")
 		return
 	}
 
@@ -85,13 +85,13 @@ func (s *Service) enrichStock(ctx context.Context, f *models.Fundamentals) {
 	}
 	if err != nil || stockURL == "" {
 		if err != nil {
-			s.logger.Debug().Str("ticker", f.Ticker).Err(err).Msg("URL context failed, falling back to Gemini knowledge")
+			s.logger.WithRequestID(ctx).Debug().Str("ticker", f.Ticker).Err(err).Msg("URL context failed, falling back to Gemini knowledge")
 		}
 		response, err = s.gemini.GenerateContent(ctx, prompt)
 	}
 
 	if err != nil {
-		s.logger.Warn().Str("ticker", f.Ticker).Err(err).Msg("Failed to enrich stock via Gemini")
+		s.logger.WithRequestID(ctx).Warn().Str("ticker", f.Ticker).Err(err).Msg("Failed to enrich stock via Gemini")
 		return
 	}
 
@@ -262,3 +262,12 @@ func extractTicker(ticker string) string {
 	}
 	return ticker
 }
+
+// earningsDate returns the next scheduled earnings report date from freshly
+// fetched fundamentals, or "" when none is listed.
+func earningsDate(f *models.Fundamentals, now time.Time) string {
+	if d, ok := f.NextEarningsDate(now); ok {
+		return d.Format("2006-01-02")
+	}
+	return ""
+}
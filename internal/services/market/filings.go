@@ -24,6 +24,7 @@ import (
 
 // fetchASXAnnouncements scrapes announcements from ASX HTML pages.
 func (s *Service) fetchASXAnnouncements(ctx context.Context, tickerCode, period string) ([]models.CompanyFiling, error) {
+	logger := s.logger.WithRequestID(ctx)
 	currentYear := time.Now().Year()
 
 	// Determine years to fetch based on period
@@ -57,13 +58,13 @@ func (s *Service) fetchASXAnnouncements(ctx context.Context, tickerCode, period
 
 		resp, err := client.Do(req)
 		if err != nil {
-			s.logger.Warn().Err(err).Int("year", year).Msg("Failed to fetch ASX HTML page")
+			logger.Warn().Err(err).Int("year", year).Msg("Failed to fetch ASX HTML page")
 			continue
 		}
 
 		if resp.StatusCode != http.StatusOK {
 			resp.Body.Close()
-			s.logger.Warn().Int("status", resp.StatusCode).Int("year", year).Msg("Non-OK status from ASX HTML")
+			logger.Warn().Int("status", resp.StatusCode).Int("year", year).Msg("Non-OK status from ASX HTML")
 			continue
 		}
 
@@ -76,7 +77,7 @@ func (s *Service) fetchASXAnnouncements(ctx context.Context, tickerCode, period
 		filings := parseAnnouncementsHTML(string(body))
 		allFilings = append(allFilings, filings...)
 
-		s.logger.Debug().
+		logger.Debug().
 			Str("code", tickerCode).
 			Int("year", year).
 			Int("count", len(filings)).
@@ -467,19 +468,20 @@ func normalizeHeadline(headline string) string {
 
 // collectFilings orchestrates fetching, classifying, and deduplicating filings.
 func (s *Service) collectFilings(ctx context.Context, ticker string) ([]models.CompanyFiling, error) {
+	logger := s.logger.WithRequestID(ctx)
 	tickerCode := extractCode(ticker)
 	if tickerCode == "" {
 		return nil, fmt.Errorf("invalid ticker: %s", ticker)
 	}
 
-	s.logger.Debug().Str("ticker", ticker).Str("code", tickerCode).Msg("Collecting filings")
+	logger.Debug().Str("ticker", ticker).Str("code", tickerCode).Msg("Collecting filings")
 
 	// Fetch Y1 general announcements + Y3 for financial filings
 	var allFilings []models.CompanyFiling
 
 	generalFilings, err := s.fetchASXAnnouncements(ctx, tickerCode, "Y1")
 	if err != nil {
-		s.logger.Warn().Err(err).Str("ticker", ticker).Msg("ASX HTML scraping failed, trying Markit API")
+		logger.Warn().Err(err).Str("ticker", ticker).Msg("ASX HTML scraping failed, trying Markit API")
 		generalFilings, err = s.fetchMarkitAnnouncements(ctx, tickerCode, "Y1")
 		if err != nil {
 			return nil, fmt.Errorf("failed to fetch announcements: %w", err)
@@ -490,10 +492,10 @@ func (s *Service) collectFilings(ctx context.Context, ticker string) ([]models.C
 	// Also fetch Y3 for financial filings specifically
 	financialFilings, err := s.fetchASXAnnouncements(ctx, tickerCode, "Y3")
 	if err != nil {
-		s.logger.Debug().Err(err).Msg("Y3 HTML fetch failed, trying Markit fallback")
+		logger.Debug().Err(err).Msg("Y3 HTML fetch failed, trying Markit fallback")
 		financialFilings, err = s.fetchMarkitAnnouncements(ctx, tickerCode, "Y3")
 		if err != nil {
-			s.logger.Warn().Err(err).Msg("Y3 Markit fetch also failed")
+			logger.Warn().Err(err).Msg("Y3 Markit fetch also failed")
 		}
 	}
 	if len(financialFilings) > 0 {
@@ -515,7 +517,7 @@ func (s *Service) collectFilings(ctx context.Context, ticker string) ([]models.C
 		return allFilings[i].Date.After(allFilings[j].Date)
 	})
 
-	s.logger.Info().
+	logger.Info().
 		Str("ticker", ticker).
 		Int("total", len(allFilings)).
 		Msg("Collected filings")
@@ -557,6 +559,7 @@ func (s *Service) mergeFilingPDFPaths(oldFilings, newFilings []models.CompanyFil
 // summary can reference the stored copy of the source document.
 // Downloads are streamed to temp files to avoid holding full PDFs in heap.
 func (s *Service) downloadFilingPDFs(ctx context.Context, tickerCode string, filings []models.CompanyFiling) []models.CompanyFiling {
+	logger := s.logger.WithRequestID(ctx)
 	downloadCount := 0
 	for i := range filings {
 		f := &filings[i]
@@ -586,7 +589,7 @@ func (s *Service) downloadFilingPDFs(ctx context.Context, tickerCode string, fil
 
 		tmpPath, fileSize, err := s.downloadASXPDF(ctx, f.PDFURL, f.DocumentKey)
 		if err != nil {
-			s.logger.Warn().Err(err).Str("headline", f.Headline).Msg("Failed to download PDF")
+			logger.Warn().Err(err).Str("headline", f.Headline).Msg("Failed to download PDF")
 			continue
 		}
 
@@ -595,12 +598,12 @@ func (s *Service) downloadFilingPDFs(ctx context.Context, tickerCode string, fil
 		content, err := os.ReadFile(tmpPath)
 		os.Remove(tmpPath)
 		if err != nil {
-			s.logger.Warn().Err(err).Str("headline", f.Headline).Msg("Failed to read temp PDF file")
+			logger.Warn().Err(err).Str("headline", f.Headline).Msg("Failed to read temp PDF file")
 			continue
 		}
 
 		if err := s.storage.FileStore().SaveFile(ctx, "filing_pdf", dbKey, content, "application/pdf"); err != nil {
-			s.logger.Warn().Err(err).Str("key", dbKey).Msg("Failed to save PDF to database")
+			logger.Warn().Err(err).Str("key", dbKey).Msg("Failed to save PDF to database")
 			continue
 		}
 
@@ -608,14 +611,14 @@ func (s *Service) downloadFilingPDFs(ctx context.Context, tickerCode string, fil
 		f.FileSize = fileSize
 		downloadCount++
 
-		s.logger.Debug().
+		logger.Debug().
 			Str("headline", f.Headline).
 			Str("key", dbKey).
 			Int64("size", fileSize).
 			Msg("Downloaded filing PDF")
 	}
 
-	s.logger.Info().
+	logger.Info().
 		Str("code", tickerCode).
 		Int("downloaded", downloadCount).
 		Msg("Filing PDF download complete")
@@ -927,6 +930,7 @@ const filingSummaryBatchSize = 2
 // Returns the full list of summaries (existing + new). Incremental: only unsummarized filings are sent to Gemini.
 // The saveFn callback is called after each batch to persist intermediate results and free memory.
 func (s *Service) summarizeNewFilings(ctx context.Context, ticker string, filings []models.CompanyFiling, existing []models.FilingSummary, saveFn func([]models.FilingSummary) error) ([]models.FilingSummary, bool) {
+	logger := s.logger.WithRequestID(ctx)
 	if len(filings) == 0 {
 		return existing, false
 	}
@@ -979,7 +983,7 @@ func (s *Service) summarizeNewFilings(ctx context.Context, ticker string, filing
 			}
 		}
 		existing = filtered
-		s.logger.Info().Str("ticker", ticker).Int("stale_replaced", len(staleKeys)).Msg("Re-analyzing headline-only summaries with PDF content")
+		logger.Info().Str("ticker", ticker).Int("stale_replaced", len(staleKeys)).Msg("Re-analyzing headline-only summaries with PDF content")
 	}
 
 	// Separate large PDFs (above threshold) for one-at-a-time processing.
@@ -993,10 +997,10 @@ func (s *Service) summarizeNewFilings(ctx context.Context, ticker string, filing
 		}
 	}
 	if len(largeFilings) > 0 {
-		s.logger.Info().Str("ticker", ticker).Int("large", len(largeFilings)).Int64("threshold", threshold).Msg("Large filings queued for sequential processing")
+		logger.Info().Str("ticker", ticker).Int("large", len(largeFilings)).Int64("threshold", threshold).Msg("Large filings queued for sequential processing")
 	}
 
-	s.logger.Info().Str("ticker", ticker).Int("new", len(unsummarized)).Int("existing", len(existing)).Msg("Summarizing new filings")
+	logger.Info().Str("ticker", ticker).Int("new", len(unsummarized)).Int("existing", len(existing)).Msg("Summarizing new filings")
 
 	// Process normal-sized filings in batches, saving after each to free memory
 	for i := 0; i < len(normalFilings); i += filingSummaryBatchSize {
@@ -1020,7 +1024,7 @@ func (s *Service) summarizeNewFilings(ctx context.Context, ticker string, filing
 		// Save intermediate results and encourage GC to free PDF memory
 		if saveFn != nil {
 			if err := saveFn(existing); err != nil {
-				s.logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to save intermediate filing summaries")
+				logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to save intermediate filing summaries")
 			}
 		}
 		runtime.GC()
@@ -1034,13 +1038,13 @@ func (s *Service) summarizeNewFilings(ctx context.Context, ticker string, filing
 		case <-time.After(1 * time.Second):
 		}
 
-		s.logger.Info().Str("ticker", ticker).Str("headline", f.Headline).Int64("size", f.FileSize).Msg("Processing large filing")
+		logger.Info().Str("ticker", ticker).Str("headline", f.Headline).Int64("size", f.FileSize).Msg("Processing large filing")
 		summaries := s.summarizeFilingBatch(ctx, ticker, []models.CompanyFiling{f})
 		existing = append(existing, summaries...)
 
 		if saveFn != nil {
 			if err := saveFn(existing); err != nil {
-				s.logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to save intermediate filing summaries")
+				logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to save intermediate filing summaries")
 			}
 		}
 		runtime.GC()
@@ -1065,8 +1069,9 @@ func filingSummaryKey(_ string, date time.Time, headline string) string {
 // (native PDF comprehension — avoids Go-side PDF parsing). Headline-only filings
 // are batched together in a single text prompt.
 func (s *Service) summarizeFilingBatch(ctx context.Context, ticker string, batch []models.CompanyFiling) []models.FilingSummary {
+	logger := s.logger.WithRequestID(ctx)
 	if s.gemini == nil {
-		s.logger.Warn().Str("ticker", ticker).Msg("Gemini not configured, skipping filing batch summarization")
+		logger.Warn().Str("ticker", ticker).Msg("Gemini not configured, skipping filing batch summarization")
 		return nil
 	}
 
@@ -1080,7 +1085,7 @@ func (s *Service) summarizeFilingBatch(ctx context.Context, ticker string, batch
 		}
 	}
 
-	s.logger.Info().
+	logger.Info().
 		Str("ticker", ticker).
 		Int("batch_size", len(batch)).
 		Int("with_pdf", len(pdfFilings)).
@@ -1093,7 +1098,7 @@ func (s *Service) summarizeFilingBatch(ctx context.Context, ticker string, batch
 	for _, f := range pdfFilings {
 		var ms runtime.MemStats
 		runtime.ReadMemStats(&ms)
-		s.logger.Debug().
+		logger.Debug().
 			Str("ticker", ticker).
 			Str("headline", f.Headline).
 			Float64("heap_inuse_mb", float64(ms.HeapInuse)/1024/1024).
@@ -1105,7 +1110,7 @@ func (s *Service) summarizeFilingBatch(ctx context.Context, ticker string, batch
 		}
 
 		runtime.ReadMemStats(&ms)
-		s.logger.Debug().
+		logger.Debug().
 			Str("ticker", ticker).
 			Float64("heap_inuse_mb", float64(ms.HeapInuse)/1024/1024).
 			Msg("Filing PDF summarization complete")
@@ -1116,30 +1121,31 @@ func (s *Service) summarizeFilingBatch(ctx context.Context, ticker string, batch
 		prompt := s.buildFilingSummaryPrompt(ticker, headlineFilings)
 		response, err := s.gemini.GenerateContent(ctx, prompt)
 		if err != nil {
-			s.logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to summarize headline-only batch")
+			logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to summarize headline-only batch")
 		} else {
 			summaries := parseFilingSummaryResponse(response, headlineFilings)
 			allSummaries = append(allSummaries, summaries...)
 		}
 	}
 
-	s.logger.Debug().Str("ticker", ticker).Int("input", len(batch)).Int("output", len(allSummaries)).Msg("Filing batch summarized")
+	logger.Debug().Str("ticker", ticker).Int("input", len(batch)).Int("output", len(allSummaries)).Msg("Filing batch summarized")
 	return allSummaries
 }
 
 // summarizeFilingPDF processes a single PDF-backed filing via the Gemini Files API.
 // Returns nil if the filing cannot be summarized.
 func (s *Service) summarizeFilingPDF(ctx context.Context, ticker string, f models.CompanyFiling) *models.FilingSummary {
+	logger := s.logger.WithRequestID(ctx)
 	// Retrieve PDF from FileStore to a temp file for the Files API upload.
 	data, _, err := s.storage.FileStore().GetFile(ctx, "filing_pdf", f.PDFPath)
 	if err != nil || len(data) == 0 {
-		s.logger.Warn().Err(err).Str("ticker", ticker).Str("path", f.PDFPath).Msg("Could not load PDF from FileStore, falling back to headline")
+		logger.Warn().Err(err).Str("ticker", ticker).Str("path", f.PDFPath).Msg("Could not load PDF from FileStore, falling back to headline")
 		return s.summarizeFilingHeadline(ctx, ticker, f)
 	}
 
 	tmpFile, tmpErr := os.CreateTemp("", "vire-filing-upload-*.pdf")
 	if tmpErr != nil {
-		s.logger.Warn().Err(tmpErr).Msg("Failed to create temp file for PDF upload")
+		logger.Warn().Err(tmpErr).Msg("Failed to create temp file for PDF upload")
 		return s.summarizeFilingHeadline(ctx, ticker, f)
 	}
 	tmpPath := tmpFile.Name()
@@ -1147,7 +1153,7 @@ func (s *Service) summarizeFilingPDF(ctx context.Context, ticker string, f model
 
 	if _, writeErr := tmpFile.Write(data); writeErr != nil {
 		tmpFile.Close()
-		s.logger.Warn().Err(writeErr).Msg("Failed to write temp PDF for upload")
+		logger.Warn().Err(writeErr).Msg("Failed to write temp PDF for upload")
 		return s.summarizeFilingHeadline(ctx, ticker, f)
 	}
 	tmpFile.Close()
@@ -1156,7 +1162,7 @@ func (s *Service) summarizeFilingPDF(ctx context.Context, ticker string, f model
 	prompt := buildSingleFilingPrompt(ticker, f)
 	response, err := s.gemini.SummariseFilingPDF(ctx, tmpPath, prompt)
 	if err != nil {
-		s.logger.Warn().Err(err).Str("ticker", ticker).Str("headline", f.Headline).Msg("Files API summarization failed, falling back to headline")
+		logger.Warn().Err(err).Str("ticker", ticker).Str("headline", f.Headline).Msg("Files API summarization failed, falling back to headline")
 		return s.summarizeFilingHeadline(ctx, ticker, f)
 	}
 
@@ -1172,7 +1178,7 @@ func (s *Service) summarizeFilingHeadline(ctx context.Context, ticker string, f
 	prompt := s.buildFilingSummaryPrompt(ticker, []models.CompanyFiling{f})
 	response, err := s.gemini.GenerateContent(ctx, prompt)
 	if err != nil {
-		s.logger.Warn().Err(err).Str("ticker", ticker).Str("headline", f.Headline).Msg("Headline summarization failed")
+		s.logger.WithRequestID(ctx).Warn().Err(err).Str("ticker", ticker).Str("headline", f.Headline).Msg("Headline summarization failed")
 		return nil
 	}
 	summaries := parseFilingSummaryResponse(response, []models.CompanyFiling{f})
@@ -1345,13 +1351,13 @@ func (s *Service) generateCompanyTimeline(ctx context.Context, ticker string, su
 
 	response, err := s.gemini.GenerateContent(ctx, prompt)
 	if err != nil {
-		s.logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to generate company timeline")
+		s.logger.WithRequestID(ctx).Warn().Str("ticker", ticker).Err(err).Msg("Failed to generate company timeline")
 		return nil
 	}
 
 	timeline := parseTimelineResponse(response)
 	if timeline == nil {
-		s.logger.Warn().Str("ticker", ticker).Msg("Failed to parse company timeline response")
+		s.logger.WithRequestID(ctx).Warn().Str("ticker", ticker).Msg("Failed to parse company timeline response")
 		return nil
 	}
 	timeline.GeneratedAt = time.Now()
@@ -0,0 +1,118 @@
+package market
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/interfaces"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// funnelEntry is a screener result with the cached data its filters read
+type funnelEntry struct {
+	result *models.ScreenerResult
+	ticker string
+	md     *models.MarketData
+	sig    *models.TickerSignals
+}
+
+// runFilterStages narrows screener results through options.FilterStages in
+// order, appending one FunnelStage per filter stage to result, and returns
+// up to limit survivors in screener order. Tickers without market data fail
+// the first stage. An empty shortlist is not an error.
+func (s *Screener) runFilterStages(ctx context.Context, scanner *Scanner, screenerResults []*models.ScreenerResult,
+	options interfaces.FunnelOptions, result *models.FunnelResult, limit int) []*models.ScreenCandidate {
+
+	tickers := make([]string, 0, len(screenerResults))
+	for _, r := range screenerResults {
+		tickers = append(tickers, r.Code+"."+options.Exchange)
+	}
+	if len(tickers) > 0 {
+		if err := s.collectMarketDataBatch(ctx, tickers, options.IncludeNews); err != nil {
+			s.logger.WithRequestID(ctx).Warn().Err(err).Msg("Funnel: some market data collection failed")
+		}
+	}
+
+	pool := make([]funnelEntry, 0, len(screenerResults))
+	for i, r := range screenerResults {
+		e := funnelEntry{result: r, ticker: tickers[i]}
+		if md, err := s.storage.MarketDataStorage().GetMarketData(ctx, e.ticker); err == nil && md != nil {
+			e.md = md
+			if sig, err := s.storage.SignalStorage().GetSignals(ctx, e.ticker); err == nil && sig != nil {
+				e.sig = sig
+			} else if len(md.EOD) > 0 {
+				e.sig = s.signalComputer.Compute(md)
+			}
+		}
+		pool = append(pool, e)
+	}
+
+	for i, stage := range options.FilterStages {
+		stageStart := time.Now()
+		survivors := make([]funnelEntry, 0, len(pool))
+		for _, e := range pool {
+			if e.md != nil && scanner.evaluateFilters(stage.Filters, e.md, e.sig) {
+				survivors = append(survivors, e)
+			}
+		}
+
+		name := stage.Name
+		if name == "" {
+			name = fmt.Sprintf("Stage %d", i+1)
+		}
+		result.Stages = append(result.Stages, models.FunnelStage{
+			Name:        name,
+			InputCount:  len(pool),
+			OutputCount: len(survivors),
+			Duration:    time.Since(stageStart),
+			Filters:     describeScanFilters(stage.Filters),
+		})
+		pool = survivors
+	}
+
+	if len(pool) > limit {
+		pool = pool[:limit]
+	}
+	candidates := make([]*models.ScreenCandidate, 0, len(pool))
+	for _, e := range pool {
+		c := &models.ScreenCandidate{
+			Ticker:        e.ticker,
+			Exchange:      options.Exchange,
+			Name:          e.result.Name,
+			DividendYield: e.result.DividendYield,
+			EPS:           e.result.EarningsShare,
+			MarketCap:     e.result.MarketCap,
+			Sector:        e.result.Sector,
+			Industry:      e.result.Industry,
+			Signals:       e.sig,
+		}
+		if len(e.md.EOD) > 0 {
+			c.Price = e.md.EOD[0].Close
+		}
+		if e.md.Fundamentals != nil {
+			c.PE = e.md.Fundamentals.PE
+		}
+		candidates = append(candidates, c)
+	}
+	return candidates
+}
+
+// describeScanFilters renders filters as "field op value" joined by commas,
+// with OR groups in parentheses.
+func describeScanFilters(filters []models.ScanFilter) string {
+	parts := make([]string, 0, len(filters))
+	for _, f := range filters {
+		if len(f.Or) > 0 {
+			parts = append(parts, "("+strings.ReplaceAll(describeScanFilters(f.Or), ", ", " OR ")+")")
+			continue
+		}
+		if f.Value == nil {
+			parts = append(parts, f.Field+" "+f.Op)
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s %s %v", f.Field, f.Op, f.Value))
+	}
+	return strings.Join(parts, ", ")
+}
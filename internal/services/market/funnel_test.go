@@ -0,0 +1,112 @@
+package market
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/interfaces"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// newFunnelService serves n screener results (T0..Tn-1 on AU) whose fresh
+// cached data scales with the index: market cap, volume and P/E all rise.
+func newFunnelService(n int) *Service {
+	now := time.Now()
+	results := make([]*models.ScreenerResult, n)
+	data := make(map[string]*models.MarketData, n)
+	for i := 0; i < n; i++ {
+		code := fmt.Sprintf("T%d", i)
+		results[i] = &models.ScreenerResult{Code: code, Exchange: "AU", Name: code, MarketCap: float64(i+1) * 1e9}
+		bars := make([]models.EODBar, 30)
+		for b := range bars {
+			bars[b] = models.EODBar{Date: now.AddDate(0, 0, -b), Close: 10, Volume: int64(i+1) * 100000}
+		}
+		data[code+".AU"] = &models.MarketData{
+			Ticker:                code + ".AU",
+			EOD:                   bars,
+			EODUpdatedAt:          now,
+			Fundamentals:          &models.Fundamentals{MarketCap: float64(i+1) * 1e9, PE: float64(5 + 2*i), ISIN: "AU000" + code},
+			FundamentalsUpdatedAt: now,
+		}
+	}
+	storage := &mockStorageManager{
+		market:  &mockMarketDataStorage{data: data},
+		signals: &mockSignalStorage{},
+	}
+	eodhd := &mockEODHDClient{screenStocksFn: func(_ context.Context, _ models.ScreenerOptions) ([]*models.ScreenerResult, error) {
+		return results, nil
+	}}
+	return NewService(storage, eodhd, nil, common.NewLogger("error"))
+}
+
+func TestFunnelScreen_FilterStageCountsNonIncreasing(t *testing.T) {
+	svc := newFunnelService(10)
+	stages := []models.FunnelFilterStage{
+		{Name: "market cap", Filters: []models.ScanFilter{{Field: "market_cap", Op: ">=", Value: 3e9}}},
+		{Name: "liquidity", Filters: []models.ScanFilter{{Field: "volume", Op: ">=", Value: 500000}}},
+		{Name: "valuation", Filters: []models.ScanFilter{{Field: "pe_ratio", Op: "<", Value: 20}}},
+	}
+
+	result, err := svc.FunnelScreen(context.Background(), interfaces.FunnelOptions{Exchange: "AU", Limit: 10, FilterStages: stages})
+	if err != nil {
+		t.Fatalf("FunnelScreen: %v", err)
+	}
+	if len(result.Stages) != 4 {
+		t.Fatalf("stages = %d, want screener + 3 filter stages", len(result.Stages))
+	}
+
+	// T2..T9 pass market cap, T4..T9 liquidity, T4..T7 valuation (P/E 13..19)
+	want := []struct{ in, out int }{{10, 8}, {8, 6}, {6, 4}}
+	for i, w := range want {
+		st := result.Stages[i+1]
+		if st.Name != stages[i].Name || st.InputCount != w.in || st.OutputCount != w.out {
+			t.Errorf("stage %d = %s %d->%d, want %s %d->%d", i+1, st.Name, st.InputCount, st.OutputCount, stages[i].Name, w.in, w.out)
+		}
+	}
+	for i := 1; i < len(result.Stages); i++ {
+		prev, cur := result.Stages[i-1], result.Stages[i]
+		if cur.InputCount != prev.OutputCount || cur.OutputCount > cur.InputCount {
+			t.Errorf("stage %d counts %d->%d do not follow stage %d output %d", i, cur.InputCount, cur.OutputCount, i-1, prev.OutputCount)
+		}
+	}
+	if len(result.Candidates) != 4 || result.Candidates[0].Ticker != "T4.AU" || result.Candidates[3].Ticker != "T7.AU" {
+		t.Errorf("shortlist = %+v, want T4.AU..T7.AU", result.Candidates)
+	}
+}
+
+func TestFunnelScreen_EmptyShortlistIsNotAnError(t *testing.T) {
+	svc := newFunnelService(5)
+	stages := []models.FunnelFilterStage{
+		{Name: "market cap", Filters: []models.ScanFilter{{Field: "market_cap", Op: ">=", Value: 2e9}}},
+		{Name: "valuation", Filters: []models.ScanFilter{{Field: "pe_ratio", Op: "<", Value: 1}}},
+	}
+
+	result, err := svc.FunnelScreen(context.Background(), interfaces.FunnelOptions{Exchange: "AU", FilterStages: stages})
+	if err != nil {
+		t.Fatalf("FunnelScreen: %v", err)
+	}
+	if result.Candidates == nil || len(result.Candidates) != 0 {
+		t.Errorf("candidates = %v, want an empty (non-nil) shortlist", result.Candidates)
+	}
+	last := result.Stages[len(result.Stages)-1]
+	if last.Name != "valuation" || last.InputCount != 4 || last.OutputCount != 0 {
+		t.Errorf("last stage = %+v, want valuation 4->0", last)
+	}
+}
+
+func TestFunnelScreen_InvalidFilterStage(t *testing.T) {
+	svc := newFunnelService(1)
+	for _, stage := range []models.FunnelFilterStage{
+		{Name: "empty"},
+		{Name: "unknown", Filters: []models.ScanFilter{{Field: "no_such_field", Op: ">", Value: 1}}},
+	} {
+		_, err := svc.FunnelScreen(context.Background(), interfaces.FunnelOptions{Exchange: "AU", FilterStages: []models.FunnelFilterStage{stage}})
+		if !errors.Is(err, interfaces.ErrInvalidFunnelStage) {
+			t.Errorf("%s stage: err = %v, want ErrInvalidFunnelStage", stage.Name, err)
+		}
+	}
+}
@@ -12,6 +12,7 @@ import (
 
 // generateNewsIntelligence uses Gemini to produce a critical news analysis for a ticker.
 func (s *Service) generateNewsIntelligence(ctx context.Context, ticker string, companyName string, news []*models.NewsItem) *models.NewsIntelligence {
+	logger := s.logger.WithRequestID(ctx)
 	if len(news) == 0 {
 		return nil
 	}
@@ -29,17 +30,17 @@ func (s *Service) generateNewsIntelligence(ctx context.Context, ticker string, c
 	// Try URL context tool first — lets Gemini fetch article URLs and search for more
 	response, err := s.gemini.GenerateWithURLContext(ctx, prompt)
 	if err != nil {
-		s.logger.Debug().Str("ticker", ticker).Err(err).Msg("URL context tool failed, falling back to GenerateContent")
+		logger.Debug().Str("ticker", ticker).Err(err).Msg("URL context tool failed, falling back to GenerateContent")
 		response, err = s.gemini.GenerateContent(ctx, prompt)
 	}
 	if err != nil {
-		s.logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to generate news intelligence")
+		logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to generate news intelligence")
 		return nil
 	}
 
 	intel := parseNewsIntelResponse(response)
 	if intel == nil {
-		s.logger.Warn().Str("ticker", ticker).Msg("Failed to parse news intelligence response")
+		logger.Warn().Str("ticker", ticker).Msg("Failed to parse news intelligence response")
 		return nil
 	}
 	intel.GeneratedAt = time.Now()
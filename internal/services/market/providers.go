@@ -0,0 +1,128 @@
+package market
+
+import (
+	"context"
+	"errors"
+
+	"github.com/bobmcallan/vire/internal/interfaces"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// eodhdProvider adapts an EODHDClient that does not name itself (e.g. a test
+// double) to MarketDataProvider.
+type eodhdProvider struct {
+	interfaces.EODHDClient
+}
+
+func (eodhdProvider) Name() string { return "eodhd" }
+
+// defaultProviders returns EODHD as the only provider, or none without a client.
+func defaultProviders(eodhd interfaces.EODHDClient) []interfaces.MarketDataProvider {
+	if eodhd == nil {
+		return nil
+	}
+	if p, ok := eodhd.(interfaces.MarketDataProvider); ok {
+		return []interfaces.MarketDataProvider{p}
+	}
+	return []interfaces.MarketDataProvider{eodhdProvider{eodhd}}
+}
+
+// errNoProvider is returned when no market data provider is configured.
+var errNoProvider = errors.New("no market data provider configured")
+
+// getEOD fetches EOD bars from the first provider that is available.
+func (s *Service) getEOD(ctx context.Context, ticker string, opts ...interfaces.EODOption) (*models.EODResponse, error) {
+	err := errNoProvider
+	for i, p := range s.providers {
+		var resp *models.EODResponse
+		if resp, err = p.GetEOD(ctx, ticker, opts...); !s.failover(i, p, "eod", ticker, err) {
+			return resp, err
+		}
+	}
+	return nil, err
+}
+
+// getFundamentals fetches fundamentals from the first provider that is
+// available. Failover providers (Alpha Vantage) only supply the company
+// overview, so their result is merged into existing rather than replacing
+// the ISIN, ETF and financials the primary provider stored.
+func (s *Service) getFundamentals(ctx context.Context, ticker string, existing *models.Fundamentals) (*models.Fundamentals, error) {
+	err := errNoProvider
+	for i, p := range s.providers {
+		var f *models.Fundamentals
+		if f, err = p.GetFundamentals(ctx, ticker); !s.failover(i, p, "fundamentals", ticker, err) {
+			if err == nil && i > 0 {
+				f = mergeOverviewFundamentals(existing, f)
+			}
+			return f, err
+		}
+	}
+	return nil, err
+}
+
+// mergeOverviewFundamentals returns a copy of existing with the company
+// overview fields a failover provider supplies overwritten from overview.
+// Fields the provider left empty keep their stored values.
+func mergeOverviewFundamentals(existing, overview *models.Fundamentals) *models.Fundamentals {
+	if existing == nil || overview == nil {
+		return overview
+	}
+	merged := *existing
+	if overview.Name != "" {
+		merged.Name = overview.Name
+	}
+	for _, f := range []struct{ dst, src *float64 }{
+		{&merged.MarketCap, &overview.MarketCap},
+		{&merged.PE, &overview.PE},
+		{&merged.PB, &overview.PB},
+		{&merged.EPS, &overview.EPS},
+		{&merged.DividendYield, &overview.DividendYield},
+		{&merged.Beta, &overview.Beta},
+	} {
+		if *f.src != 0 {
+			*f.dst = *f.src
+		}
+	}
+	if overview.SharesOutstanding != 0 {
+		merged.SharesOutstanding = overview.SharesOutstanding
+	}
+	if overview.Sector != "" {
+		merged.Sector = overview.Sector
+	}
+	if overview.Industry != "" {
+		merged.Industry = overview.Industry
+	}
+	if overview.Description != "" {
+		merged.Description = overview.Description
+	}
+	merged.LastUpdated = overview.LastUpdated
+	return &merged
+}
+
+// getRealTimeQuote fetches a live quote from the first provider that is
+// available.
+func (s *Service) getRealTimeQuote(ctx context.Context, ticker string) (*models.RealTimeQuote, error) {
+	err := errNoProvider
+	for i, p := range s.providers {
+		var q *models.RealTimeQuote
+		if q, err = p.GetRealTimeQuote(ctx, ticker); !s.failover(i, p, "quote", ticker, err) {
+			return q, err
+		}
+	}
+	return nil, err
+}
+
+// failover reports whether a call that returned err should be retried on the
+// next provider: only rate-limit and open-circuit errors fail over, and only
+// while a provider remains.
+func (s *Service) failover(i int, p interfaces.MarketDataProvider, op, ticker string, err error) bool {
+	if err == nil || i == len(s.providers)-1 {
+		return false
+	}
+	if !interfaces.IsRateLimited(err) && !errors.Is(err, interfaces.ErrCircuitOpen) {
+		return false
+	}
+	s.logger.Warn().Str("provider", p.Name()).Str("next", s.providers[i+1].Name()).
+		Str("op", op).Str("ticker", ticker).Err(err).Msg("Market data provider unavailable, failing over")
+	return true
+}
@@ -0,0 +1,180 @@
+package market
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bobmcallan/vire/internal/clients/alphavantage"
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/interfaces"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+type rateLimitErr struct{}
+
+func (rateLimitErr) Error() string     { return "quota exceeded" }
+func (rateLimitErr) RateLimited() bool { return true }
+
+// stubProvider returns err when set, otherwise a quote priced at price.
+type stubProvider struct {
+	name    string
+	price   float64
+	err     error
+	tickers []string
+}
+
+func (p *stubProvider) Name() string { return p.name }
+
+func (p *stubProvider) GetEOD(ctx context.Context, ticker string, opts ...interfaces.EODOption) (*models.EODResponse, error) {
+	p.tickers = append(p.tickers, ticker)
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &models.EODResponse{Data: []models.EODBar{{Close: p.price}}}, nil
+}
+
+func (p *stubProvider) GetRealTimeQuote(ctx context.Context, ticker string) (*models.RealTimeQuote, error) {
+	p.tickers = append(p.tickers, ticker)
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &models.RealTimeQuote{Code: ticker, Close: p.price, Source: p.name}, nil
+}
+
+func (p *stubProvider) GetFundamentals(ctx context.Context, ticker string) (*models.Fundamentals, error) {
+	p.tickers = append(p.tickers, ticker)
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &models.Fundamentals{Ticker: ticker}, nil
+}
+
+func TestProviders_FailoverOnRateLimit(t *testing.T) {
+	primary := &stubProvider{name: "primary", err: rateLimitErr{}}
+	secondary := &stubProvider{name: "secondary", price: 42}
+	svc := NewService(nil, nil, nil, common.NewLogger("error"), primary, secondary)
+
+	quote, err := svc.getRealTimeQuote(context.Background(), "BHP.AU")
+	if err != nil {
+		t.Fatalf("getRealTimeQuote failed: %v", err)
+	}
+	if quote.Source != "secondary" || quote.Close != 42 {
+		t.Errorf("quote = %+v, want secondary at 42", quote)
+	}
+
+	eod, err := svc.getEOD(context.Background(), "BHP.AU")
+	if err != nil || len(eod.Data) != 1 || eod.Data[0].Close != 42 {
+		t.Errorf("getEOD = %+v, %v; want one secondary bar", eod, err)
+	}
+	if len(primary.tickers) != 2 || len(secondary.tickers) != 2 {
+		t.Errorf("calls: primary %d, secondary %d; want 2 each", len(primary.tickers), len(secondary.tickers))
+	}
+}
+
+func TestProviders_NoFailoverOnOtherErrors(t *testing.T) {
+	primary := &stubProvider{name: "primary", err: errors.New("not found")}
+	secondary := &stubProvider{name: "secondary", price: 42}
+	svc := NewService(nil, nil, nil, common.NewLogger("error"), primary, secondary)
+
+	if _, err := svc.getFundamentals(context.Background(), "BHP.AU", nil); err == nil {
+		t.Fatal("expected primary error to be returned")
+	}
+	if len(secondary.tickers) != 0 {
+		t.Errorf("secondary called %d times, want 0", len(secondary.tickers))
+	}
+}
+
+func TestProviders_AllRateLimitedReturnsLastError(t *testing.T) {
+	svc := NewService(nil, nil, nil, common.NewLogger("error"),
+		&stubProvider{name: "a", err: rateLimitErr{}}, &stubProvider{name: "b", err: rateLimitErr{}})
+
+	_, err := svc.getRealTimeQuote(context.Background(), "BHP.AU")
+	if !interfaces.IsRateLimited(err) {
+		t.Errorf("expected rate-limit error, got %v", err)
+	}
+}
+
+func TestProviders_DefaultsToEODHD(t *testing.T) {
+	if svc := NewService(nil, nil, nil, common.NewLogger("error")); len(svc.providers) != 0 {
+		t.Errorf("no EODHD client: got %d providers, want 0", len(svc.providers))
+	}
+	svc := NewService(nil, &mockEODHDClient{}, nil, common.NewLogger("error"))
+	if len(svc.providers) != 1 || svc.providers[0].Name() != "eodhd" {
+		t.Errorf("expected EODHD as the only provider, got %d", len(svc.providers))
+	}
+}
+
+func TestProviders_FailoverTranslatesSymbol(t *testing.T) {
+	var gotSymbol string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotSymbol = r.URL.Query().Get("symbol")
+		w.Write([]byte(`{"Global Quote": {"01. symbol": "BHP.AX", "05. price": "45.50", "07. latest trading day": "2025-01-03"}}`))
+	}))
+	defer srv.Close()
+
+	primary := &stubProvider{name: "eodhd", err: rateLimitErr{}}
+	av := alphavantage.NewClient("key", alphavantage.WithBaseURL(srv.URL))
+	svc := NewService(nil, nil, nil, common.NewLogger("error"), primary, av)
+
+	quote, err := svc.getRealTimeQuote(context.Background(), "BHP.AU")
+	if err != nil {
+		t.Fatalf("getRealTimeQuote failed: %v", err)
+	}
+	if primary.tickers[0] != "BHP.AU" {
+		t.Errorf("primary asked for %q, want BHP.AU", primary.tickers[0])
+	}
+	if gotSymbol != "BHP.AX" {
+		t.Errorf("Alpha Vantage asked for %q, want BHP.AX", gotSymbol)
+	}
+	if quote.Code != "BHP.AU" || quote.Close != 45.50 || quote.Source != "alphavantage" {
+		t.Errorf("quote = %+v, want BHP.AU at 45.50 from alphavantage", quote)
+	}
+}
+
+func TestProviders_FailoverOnCircuitOpen(t *testing.T) {
+	primary := &stubProvider{name: "eodhd", err: fmt.Errorf("EODHD /eod/BHP.AU: %w", interfaces.ErrCircuitOpen)}
+	secondary := &stubProvider{name: "secondary", price: 42}
+	svc := NewService(nil, nil, nil, common.NewLogger("error"), primary, secondary)
+
+	eod, err := svc.getEOD(context.Background(), "BHP.AU")
+	if err != nil || len(eod.Data) != 1 || eod.Data[0].Close != 42 {
+		t.Errorf("getEOD = %+v, %v; want one secondary bar", eod, err)
+	}
+}
+
+func TestProviders_FailoverFundamentalsKeepStoredFields(t *testing.T) {
+	primary := &stubProvider{name: "eodhd", err: rateLimitErr{}}
+	secondary := &overviewProvider{stubProvider{name: "alphavantage"}}
+	svc := NewService(nil, nil, nil, common.NewLogger("error"), primary, secondary)
+
+	stored := &models.Fundamentals{
+		Ticker: "VAS.AU", Name: "Old Name", ISIN: "AU000000VAS1", IsETF: true, PE: 15, Beta: 0.9,
+		TopHoldings: []models.ETFHolding{{Name: "BHP"}},
+	}
+	f, err := svc.getFundamentals(context.Background(), "VAS.AU", stored)
+	if err != nil {
+		t.Fatalf("getFundamentals: %v", err)
+	}
+	if f.Name != "Vanguard Australian Shares" || f.PE != 18 {
+		t.Errorf("overview fields = %q, PE %.0f; want the failover provider's", f.Name, f.PE)
+	}
+	if f.ISIN != "AU000000VAS1" || !f.IsETF || len(f.TopHoldings) != 1 || f.Beta != 0.9 {
+		t.Errorf("merged = %+v; want ISIN, ETF data and beta kept from the stored fundamentals", f)
+	}
+	if stored.Name != "Old Name" {
+		t.Error("stored fundamentals were modified in place")
+	}
+}
+
+// overviewProvider returns only overview fields, like Alpha Vantage.
+type overviewProvider struct {
+	stubProvider
+}
+
+func (p *overviewProvider) GetFundamentals(ctx context.Context, ticker string) (*models.Fundamentals, error) {
+	return &models.Fundamentals{Ticker: ticker, Name: "Vanguard Australian Shares", PE: 18}, nil
+}
@@ -69,7 +69,7 @@ func (sc *Scanner) Scan(ctx context.Context, query models.ScanQuery) (*models.Sc
 		}, nil
 	}
 
-	sc.logger.Debug().
+	sc.logger.WithRequestID(ctx).Debug().
 		Str("exchange", query.Exchange).
 		Int("tickers", len(tickers)).
 		Int("fields", len(query.Fields)).
@@ -84,7 +84,7 @@ func (sc *Scanner) Scan(ctx context.Context, query models.ScanQuery) (*models.Sc
 
 	signalsList, err := sc.storage.SignalStorage().GetSignalsBatch(ctx, tickers)
 	if err != nil {
-		sc.logger.Warn().Err(err).Msg("Failed to batch load signals, continuing without")
+		sc.logger.WithRequestID(ctx).Warn().Err(err).Msg("Failed to batch load signals, continuing without")
 		signalsList = nil
 	}
 
@@ -488,6 +488,17 @@ func (r *ScanFieldRegistry) registerOscillators() {
 		}
 		return sig.Technical.MACDCrossover
 	})
+	r.register(g, models.ScanFieldDef{
+		Field: "macd_cross_event", Type: "string", Description: "MACD histogram sign flip on the latest bar: bullish_cross / bearish_cross (null when none)",
+		Filterable: true, Sortable: false, Operators: []string{"==", "!=", "in"},
+		Nullable: true,
+		Enum:     []string{"bullish_cross", "bearish_cross"},
+	}, func(_ *models.MarketData, sig *models.TickerSignals) interface{} {
+		if sig == nil || sig.Technical.MACDCrossEvent == "" {
+			return nil
+		}
+		return sig.Technical.MACDCrossEvent
+	})
 	r.register(g, models.ScanFieldDef{
 		Field: "atr", Type: "float", Description: "Average True Range",
 		Filterable: true, Sortable: true, Operators: opsNumeric,
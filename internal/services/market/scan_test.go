@@ -3,6 +3,7 @@ package market
 import (
 	"context"
 	"encoding/json"
+	"io"
 	"testing"
 	"time"
 
@@ -59,7 +60,13 @@ func (m *scanTestStorage) PurgeDerivedData(_ context.Context) (map[string]int, e
 	return nil, nil
 }
 func (m *scanTestStorage) PurgeReports(_ context.Context) (int, error) { return 0, nil }
-func (m *scanTestStorage) Close() error                                { return nil }
+func (m *scanTestStorage) Backup(_ context.Context, _ io.Writer) (*models.BackupManifest, error) {
+	return nil, nil
+}
+func (m *scanTestStorage) Restore(_ context.Context, _ io.Reader) (*models.BackupManifest, error) {
+	return nil, nil
+}
+func (m *scanTestStorage) Close() error { return nil }
 
 // makeScanTestData creates mock storage with market data and signals for multiple tickers.
 func makeScanTestData(tickers []string, marketDataMap map[string]*models.MarketData, signalsMap map[string]*models.TickerSignals) *scanTestStorage {
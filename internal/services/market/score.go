@@ -0,0 +1,115 @@
+package market
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// Normalisation bounds for the composite screen score: a P/E of 0 scores 100
+// falling to 0 at scoreMaxPE, and a dividend yield of scoreMaxYield or more
+// scores 100.
+const (
+	scoreMaxPE    = 40.0
+	scoreMaxYield = 0.08
+)
+
+// ScreenAndScore runs an EODHD screener query and ranks the results by a
+// weighted 0-100 composite of valuation (P/E), momentum (stored RSI) and
+// dividend yield. A factor without data is left out of that result's
+// average and the remaining weights are renormalised. Results are sorted by
+// score descending, ties by ticker.
+func (s *Screener) ScreenAndScore(ctx context.Context, options models.ScreenerOptions, weights models.ScoreWeights) ([]*models.ScoredResult, error) {
+	if weights.Valuation < 0 || weights.Momentum < 0 || weights.Dividend < 0 {
+		return nil, fmt.Errorf("score weights must not be negative")
+	}
+	if weights.Valuation == 0 && weights.Momentum == 0 && weights.Dividend == 0 {
+		weights = models.ScoreWeights{Valuation: 1, Momentum: 1, Dividend: 1}
+	}
+
+	results, err := s.eodhd.ScreenStocks(ctx, options)
+	if err != nil {
+		return nil, fmt.Errorf("screener query failed: %w", err)
+	}
+
+	tickers := make([]string, 0, len(results))
+	for _, r := range results {
+		tickers = append(tickers, r.Code+"."+models.EodhExchange(r.Exchange))
+	}
+	rsi := make(map[string]float64)
+	if sigs, err := s.storage.SignalStorage().GetSignalsBatch(ctx, tickers); err == nil {
+		for _, sig := range sigs {
+			if sig != nil && sig.Technical.RSI > 0 {
+				rsi[sig.Ticker] = sig.Technical.RSI
+			}
+		}
+	} else {
+		s.logger.WithRequestID(ctx).Warn().Err(err).Msg("Failed to load signals for screen scoring; momentum omitted")
+	}
+
+	scored := make([]*models.ScoredResult, 0, len(results))
+	for i, r := range results {
+		factors := scoreFactors(r, rsi[tickers[i]])
+		scored = append(scored, &models.ScoredResult{
+			ScreenerResult: *r,
+			Ticker:         tickers[i],
+			Score:          compositeScore(factors, weights),
+			Factors:        factors,
+		})
+	}
+
+	sort.SliceStable(scored, func(i, j int) bool {
+		if scored[i].Score != scored[j].Score {
+			return scored[i].Score > scored[j].Score
+		}
+		return scored[i].Ticker < scored[j].Ticker
+	})
+	return scored, nil
+}
+
+// scoreFactors normalises each available factor to 0-100. Valuation needs
+// positive earnings and a price; momentum needs a stored RSI (0 = none).
+// Dividend is always present: the screener reports non-payers as 0.
+func scoreFactors(r *models.ScreenerResult, rsi float64) map[string]float64 {
+	factors := make(map[string]float64, 3)
+	if r.EarningsShare > 0 && r.AdjustedClose > 0 {
+		pe := r.AdjustedClose / r.EarningsShare
+		factors[models.ScoreFactorValuation] = clampScore(100 * (1 - pe/scoreMaxPE))
+	}
+	if rsi > 0 {
+		factors[models.ScoreFactorMomentum] = clampScore(rsi)
+	}
+	factors[models.ScoreFactorDividend] = clampScore(100 * r.DividendYield / scoreMaxYield)
+	return factors
+}
+
+// compositeScore is the weighted average of the available factors. Returns 0
+// when no weighted factor has data.
+func compositeScore(factors map[string]float64, weights models.ScoreWeights) float64 {
+	weighted := []struct {
+		name   string
+		weight float64
+	}{
+		{models.ScoreFactorValuation, weights.Valuation},
+		{models.ScoreFactorMomentum, weights.Momentum},
+		{models.ScoreFactorDividend, weights.Dividend},
+	}
+	var sum, total float64
+	for _, f := range weighted {
+		if v, ok := factors[f.name]; ok && f.weight > 0 {
+			sum += v * f.weight
+			total += f.weight
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return math.Round(sum/total*100) / 100
+}
+
+func clampScore(v float64) float64 {
+	return math.Max(0, math.Min(100, v))
+}
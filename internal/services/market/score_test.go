@@ -0,0 +1,101 @@
+package market
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// newScoringService serves a fixed screener result set with stored RSIs
+func newScoringService(results []*models.ScreenerResult, rsi map[string]float64) *Service {
+	sigs := make(map[string]*models.TickerSignals)
+	for ticker, v := range rsi {
+		sigs[ticker] = &models.TickerSignals{Ticker: ticker, Technical: models.TechnicalSignals{RSI: v}}
+	}
+	storage := &mockStorageManager{
+		market:  &mockMarketDataStorage{data: map[string]*models.MarketData{}},
+		signals: &mockSignalStorage{data: sigs},
+	}
+	eodhd := &mockEODHDClient{screenStocksFn: func(_ context.Context, _ models.ScreenerOptions) ([]*models.ScreenerResult, error) {
+		return results, nil
+	}}
+	return NewService(storage, eodhd, nil, common.NewLogger("error"))
+}
+
+func scoredTickers(results []*models.ScoredResult) []string {
+	out := make([]string, len(results))
+	for i, r := range results {
+		out[i] = r.Ticker
+	}
+	return out
+}
+
+func TestScreenAndScore_WeightsReorderDeterministically(t *testing.T) {
+	results := []*models.ScreenerResult{
+		// Cheap, sluggish, high yield
+		{Code: "VAL", Exchange: "AU", AdjustedClose: 8, EarningsShare: 1, DividendYield: 0.06},
+		// Expensive, strong momentum, no dividend
+		{Code: "MOM", Exchange: "AU", AdjustedClose: 36, EarningsShare: 1, DividendYield: 0},
+		// Middle of the road
+		{Code: "MID", Exchange: "AU", AdjustedClose: 20, EarningsShare: 1, DividendYield: 0.03},
+	}
+	rsi := map[string]float64{"VAL.AU": 35, "MOM.AU": 75, "MID.AU": 55}
+	svc := newScoringService(results, rsi)
+	ctx := context.Background()
+
+	value, err := svc.ScreenAndScore(ctx, models.ScreenerOptions{}, models.ScoreWeights{Valuation: 3, Dividend: 1})
+	if err != nil {
+		t.Fatalf("ScreenAndScore: %v", err)
+	}
+	if got := scoredTickers(value); got[0] != "VAL.AU" || got[1] != "MID.AU" || got[2] != "MOM.AU" {
+		t.Errorf("value-weighted order = %v, want [VAL.AU MID.AU MOM.AU]", got)
+	}
+
+	momentum, err := svc.ScreenAndScore(ctx, models.ScreenerOptions{}, models.ScoreWeights{Momentum: 1})
+	if err != nil {
+		t.Fatalf("ScreenAndScore: %v", err)
+	}
+	if got := scoredTickers(momentum); got[0] != "MOM.AU" || got[1] != "MID.AU" || got[2] != "VAL.AU" {
+		t.Errorf("momentum-weighted order = %v, want [MOM.AU MID.AU VAL.AU]", got)
+	}
+	if momentum[0].Score != 75 {
+		t.Errorf("MOM.AU momentum-only score = %v, want 75 (its RSI)", momentum[0].Score)
+	}
+
+	// Same inputs, same weights: same order and scores
+	again, _ := svc.ScreenAndScore(ctx, models.ScreenerOptions{}, models.ScoreWeights{Momentum: 1})
+	for i := range again {
+		if again[i].Ticker != momentum[i].Ticker || again[i].Score != momentum[i].Score {
+			t.Fatalf("rerun differs at %d: %+v vs %+v", i, again[i], momentum[i])
+		}
+	}
+}
+
+func TestScreenAndScore_MissingFactorsRenormalise(t *testing.T) {
+	results := []*models.ScreenerResult{
+		// Loss-making (no P/E) and no stored RSI: dividend only
+		{Code: "LOSS", Exchange: "AU", AdjustedClose: 5, EarningsShare: -0.2, DividendYield: 0.04},
+	}
+	svc := newScoringService(results, nil)
+
+	got, err := svc.ScreenAndScore(context.Background(), models.ScreenerOptions{}, models.ScoreWeights{Valuation: 2, Momentum: 2, Dividend: 1})
+	if err != nil {
+		t.Fatalf("ScreenAndScore: %v", err)
+	}
+	r := got[0]
+	if _, ok := r.Factors[models.ScoreFactorValuation]; ok {
+		t.Error("valuation should be missing without positive earnings")
+	}
+	if _, ok := r.Factors[models.ScoreFactorMomentum]; ok {
+		t.Error("momentum should be missing without a stored RSI")
+	}
+	if r.Score != 50 {
+		t.Errorf("score = %v, want 50 (the 4%% yield factor alone, not diluted by missing factors)", r.Score)
+	}
+
+	if _, err := svc.ScreenAndScore(context.Background(), models.ScreenerOptions{}, models.ScoreWeights{Valuation: -1}); err == nil {
+		t.Error("expected an error for negative weights")
+	}
+}
@@ -96,7 +96,7 @@ func (s *Screener) screenerAPIQuery(ctx context.Context, exchange, sector string
 		results = filtered
 	}
 
-	s.logger.Debug().Int("results", len(results)).Msg("Screener API results")
+	s.logger.WithRequestID(ctx).Debug().Int("results", len(results)).Msg("Screener API results")
 
 	return results, strings.Join(filterDescs, ", "), nil
 }
@@ -215,7 +215,7 @@ func (s *Screener) refineFundamentals(ctx context.Context, results []*models.Scr
 		out[i] = scoredResults[i].result
 	}
 
-	s.logger.Debug().Int("results", len(out)).Msg("Fundamental refinement results")
+	s.logger.WithRequestID(ctx).Debug().Int("results", len(out)).Msg("Fundamental refinement results")
 
 	return out
 }
@@ -223,6 +223,7 @@ func (s *Screener) refineFundamentals(ctx context.Context, results []*models.Scr
 // collectMarketDataBatch collects market data for tickers using concurrent fetching.
 // Fundamentals and historical EOD are fetched concurrently with rate limiting.
 func (s *Screener) collectMarketDataBatch(ctx context.Context, tickers []string, includeNews bool) error {
+	logger := s.logger.WithRequestID(ctx)
 	// Partition tickers by freshness
 	needEOD := make([]string, 0, len(tickers))
 	needFundamentals := make([]string, 0, len(tickers))
@@ -242,7 +243,7 @@ func (s *Screener) collectMarketDataBatch(ctx context.Context, tickers []string,
 		return nil
 	}
 
-	s.logger.Debug().
+	logger.Debug().
 		Int("need_eod", len(needEOD)).
 		Int("need_fundamentals", len(needFundamentals)).
 		Msg("Collecting market data for candidates (concurrent)")
@@ -297,7 +298,7 @@ func (s *Screener) collectMarketDataBatch(ctx context.Context, tickers []string,
 	for range needEOD {
 		result := <-eodChan
 		if result.err != nil {
-			s.logger.Warn().Str("ticker", result.ticker).Err(result.err).Msg("Failed to fetch EOD")
+			logger.Warn().Str("ticker", result.ticker).Err(result.err).Msg("Failed to fetch EOD")
 		} else {
 			eodResults[result.ticker] = result.data
 		}
@@ -309,14 +310,14 @@ func (s *Screener) collectMarketDataBatch(ctx context.Context, tickers []string,
 	for range needFundamentals {
 		result := <-fundamentalsChan
 		if result.err != nil {
-			s.logger.Warn().Str("ticker", result.ticker).Err(result.err).Msg("Failed to fetch fundamentals")
+			logger.Warn().Str("ticker", result.ticker).Err(result.err).Msg("Failed to fetch fundamentals")
 		} else {
 			fundamentalsResults[result.ticker] = result.fundamentals
 		}
 	}
 	close(fundamentalsChan)
 
-	s.logger.Debug().
+	logger.Debug().
 		Int("eod_fetched", len(eodResults)).
 		Int("fundamentals_fetched", len(fundamentalsResults)).
 		Msg("Concurrent data fetch complete")
@@ -342,6 +343,7 @@ func (s *Screener) collectMarketDataBatch(ctx context.Context, tickers []string,
 		// Apply fundamentals results
 		if fundamentals, ok := fundamentalsResults[ticker]; ok {
 			marketData.Fundamentals = fundamentals
+			marketData.EarningsDate = earningsDate(fundamentals, now)
 			marketData.FundamentalsUpdatedAt = now
 		}
 
@@ -349,14 +351,14 @@ func (s *Screener) collectMarketDataBatch(ctx context.Context, tickers []string,
 
 		// Save
 		if err := s.storage.MarketDataStorage().SaveMarketData(ctx, marketData); err != nil {
-			s.logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to save market data")
+			logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to save market data")
 			continue
 		}
 
 		// Compute signals
 		tickerSignals := s.signalComputer.Compute(marketData)
-		if err := s.storage.SignalStorage().SaveSignals(ctx, tickerSignals); err != nil {
-			s.logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to save signals")
+		if err := saveSignals(ctx, s.storage.SignalStorage(), tickerSignals); err != nil {
+			logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to save signals")
 		}
 	}
 
@@ -366,7 +368,8 @@ func (s *Screener) collectMarketDataBatch(ctx context.Context, tickers []string,
 // screenViaExchangeSymbols is a fallback screening method when EODHD Screener API is unavailable.
 // It fetches exchange symbols, samples them, and filters by fundamentals.
 func (s *Screener) screenViaExchangeSymbols(ctx context.Context, options interfaces.ScreenOptions, maxPE, minReturn float64) ([]*models.ScreenCandidate, error) {
-	s.logger.Info().Str("exchange", options.Exchange).Msg("Screening via exchange symbols fallback")
+	logger := s.logger.WithRequestID(ctx)
+	logger.Info().Str("exchange", options.Exchange).Msg("Screening via exchange symbols fallback")
 
 	// Get all symbols for the exchange
 	symbols, err := s.eodhd.GetExchangeSymbols(ctx, options.Exchange)
@@ -374,7 +377,7 @@ func (s *Screener) screenViaExchangeSymbols(ctx context.Context, options interfa
 		return nil, fmt.Errorf("failed to get exchange symbols: %w", err)
 	}
 
-	s.logger.Debug().Int("total_symbols", len(symbols)).Msg("Exchange symbols retrieved")
+	logger.Debug().Int("total_symbols", len(symbols)).Msg("Exchange symbols retrieved")
 
 	// Filter to common stocks only (exclude ETFs, warrants, etc.)
 	// Note: Symbol from exchange-symbol-list doesn't include sector, so sector filtering
@@ -388,7 +391,7 @@ func (s *Screener) screenViaExchangeSymbols(ctx context.Context, options interfa
 		filtered = append(filtered, sym)
 	}
 
-	s.logger.Debug().Int("filtered_symbols", len(filtered)).Msg("After type/sector filtering")
+	logger.Debug().Int("filtered_symbols", len(filtered)).Msg("After type/sector filtering")
 
 	// Sample symbols (can't process thousands) - take random sample up to 100
 	maxSample := 100
@@ -402,7 +405,7 @@ func (s *Screener) screenViaExchangeSymbols(ctx context.Context, options interfa
 		filtered = sampled
 	}
 
-	s.logger.Debug().Int("sampled", len(filtered)).Msg("Sampled symbols for screening")
+	logger.Debug().Int("sampled", len(filtered)).Msg("Sampled symbols for screening")
 
 	// Build tickers and fetch market data concurrently
 	tickers := make([]string, 0, len(filtered))
@@ -412,7 +415,7 @@ func (s *Screener) screenViaExchangeSymbols(ctx context.Context, options interfa
 
 	// Collect market data (will fetch fundamentals)
 	if err := s.collectMarketDataBatch(ctx, tickers, options.IncludeNews); err != nil {
-		s.logger.Warn().Err(err).Msg("Some market data collection failed")
+		logger.Warn().Err(err).Msg("Some market data collection failed")
 	}
 
 	// Evaluate each candidate
@@ -462,14 +465,14 @@ func (s *Screener) screenViaExchangeSymbols(ctx context.Context, options interfa
 		candidates = candidates[:limit]
 	}
 
-	s.logger.Info().Int("candidates", len(candidates)).Msg("Exchange symbols screening complete")
+	logger.Info().Int("candidates", len(candidates)).Msg("Exchange symbols screening complete")
 
 	// AI analysis for final candidates
 	if len(candidates) > 0 && s.gemini != nil {
 		for _, candidate := range candidates {
 			analysis, err := s.generateScreenAnalysis(ctx, candidate, options.Strategy)
 			if err != nil {
-				s.logger.Warn().Str("ticker", candidate.Ticker).Err(err).Msg("Failed to generate analysis")
+				logger.Warn().Str("ticker", candidate.Ticker).Err(err).Msg("Failed to generate analysis")
 				continue
 			}
 			candidate.Analysis = analysis
@@ -487,7 +490,8 @@ func (s *Screener) screenViaExchangeSymbols(ctx context.Context, options interfa
 //  5. Sort by score, limit results
 //  6. AI analysis via Gemini for final candidates
 func (s *Screener) ScreenStocks(ctx context.Context, options interfaces.ScreenOptions) ([]*models.ScreenCandidate, error) {
-	s.logger.Info().
+	logger := s.logger.WithRequestID(ctx)
+	logger.Info().
 		Str("exchange", options.Exchange).
 		Int("limit", options.Limit).
 		Float64("max_pe", options.MaxPE).
@@ -528,14 +532,14 @@ func (s *Screener) ScreenStocks(ctx context.Context, options interfaces.ScreenOp
 	if err != nil {
 		// Check if this is a 403/subscription error - fall back to exchange symbols approach
 		if strings.Contains(err.Error(), "403") || strings.Contains(err.Error(), "Forbidden") {
-			s.logger.Info().Msg("Screener API unavailable, using exchange symbols fallback")
+			logger.Info().Msg("Screener API unavailable, using exchange symbols fallback")
 			return s.screenViaExchangeSymbols(ctx, options, maxPE, minReturn)
 		}
 		return nil, fmt.Errorf("screener API query failed: %w", err)
 	}
 
 	if len(screenerResults) == 0 {
-		s.logger.Info().Msg("Stock screen: no results from screener API")
+		logger.Info().Msg("Stock screen: no results from screener API")
 		return []*models.ScreenCandidate{}, nil
 	}
 
@@ -543,7 +547,7 @@ func (s *Screener) ScreenStocks(ctx context.Context, options interfaces.ScreenOp
 	refined := s.refineFundamentals(ctx, screenerResults, maxPE, options.Strategy, 25)
 
 	if len(refined) == 0 {
-		s.logger.Info().Msg("Stock screen: no results after fundamental refinement")
+		logger.Info().Msg("Stock screen: no results after fundamental refinement")
 		return []*models.ScreenCandidate{}, nil
 	}
 
@@ -553,7 +557,7 @@ func (s *Screener) ScreenStocks(ctx context.Context, options interfaces.ScreenOp
 		tickers = append(tickers, r.Code+"."+options.Exchange)
 	}
 	if err := s.collectMarketDataBatch(ctx, tickers, options.IncludeNews); err != nil {
-		s.logger.Warn().Err(err).Msg("Some market data collection failed")
+		logger.Warn().Err(err).Msg("Some market data collection failed")
 	}
 
 	// Step 4: Full candidate evaluation with signals
@@ -621,14 +625,14 @@ func (s *Screener) ScreenStocks(ctx context.Context, options interfaces.ScreenOp
 		for _, candidate := range candidates {
 			analysis, err := s.generateScreenAnalysis(ctx, candidate, options.Strategy)
 			if err != nil {
-				s.logger.Warn().Str("ticker", candidate.Ticker).Err(err).Msg("Failed to generate screen analysis")
+				logger.Warn().Str("ticker", candidate.Ticker).Err(err).Msg("Failed to generate screen analysis")
 				continue
 			}
 			candidate.Analysis = analysis
 		}
 	}
 
-	s.logger.Info().Int("candidates", len(candidates)).Msg("Stock screen complete")
+	logger.Info().Int("candidates", len(candidates)).Msg("Stock screen complete")
 	return candidates, nil
 }
 
@@ -636,6 +640,7 @@ func (s *Screener) ScreenStocks(ctx context.Context, options interfaces.ScreenOp
 // with FunnelStage timing/counts and returns a FunnelResult.
 // Uses wider intermediate limits for a more thorough scan.
 func (s *Screener) FunnelScreen(ctx context.Context, options interfaces.FunnelOptions) (*models.FunnelResult, error) {
+	logger := s.logger.WithRequestID(ctx)
 	start := time.Now()
 
 	limit := options.Limit
@@ -652,12 +657,25 @@ func (s *Screener) FunnelScreen(ctx context.Context, options interfaces.FunnelOp
 		Stages:   make([]models.FunnelStage, 0, 3),
 	}
 
-	s.logger.Info().
+	logger.Info().
 		Str("exchange", options.Exchange).
 		Int("limit", limit).
 		Str("sector", options.Sector).
+		Int("filter_stages", len(options.FilterStages)).
 		Msg("Starting funnel screen")
 
+	scanner := NewScanner(s.storage, s.logger)
+	for i, stage := range options.FilterStages {
+		if len(stage.Filters) == 0 {
+			return nil, fmt.Errorf("%w: stage %d (%s) has no filters", interfaces.ErrInvalidFunnelStage, i+1, stage.Name)
+		}
+		for _, f := range stage.Filters {
+			if err := scanner.validateFilter(f, 0); err != nil {
+				return nil, fmt.Errorf("%w: stage %d (%s): %v", interfaces.ErrInvalidFunnelStage, i+1, stage.Name, err)
+			}
+		}
+	}
+
 	// Stage 1: EODHD Screener API
 	stage1Start := time.Now()
 	screenerResults, stage1Filters, err := s.screenerAPIQuery(ctx, options.Exchange, options.Sector, options.Strategy, nil)
@@ -672,6 +690,16 @@ func (s *Screener) FunnelScreen(ctx context.Context, options interfaces.FunnelOp
 		Filters:     stage1Filters,
 	})
 
+	if len(options.FilterStages) > 0 {
+		result.Candidates = s.runFilterStages(ctx, scanner, screenerResults, options, result, limit)
+		result.Duration = time.Since(start)
+		logger.Info().
+			Int("final_candidates", len(result.Candidates)).
+			Dur("duration", result.Duration).
+			Msg("Funnel screen complete")
+		return result, nil
+	}
+
 	if len(screenerResults) == 0 {
 		result.Candidates = []*models.ScreenCandidate{}
 		result.Duration = time.Since(start)
@@ -718,7 +746,7 @@ func (s *Screener) FunnelScreen(ctx context.Context, options interfaces.FunnelOp
 		tickers = append(tickers, r.Code+"."+options.Exchange)
 	}
 	if err := s.collectMarketDataBatch(ctx, tickers, options.IncludeNews); err != nil {
-		s.logger.Warn().Err(err).Msg("Stage 3: some market data collection failed")
+		logger.Warn().Err(err).Msg("Stage 3: some market data collection failed")
 	}
 
 	// Stage 3 uses same maxPE as stage 2 (already filtered)
@@ -795,7 +823,7 @@ func (s *Screener) FunnelScreen(ctx context.Context, options interfaces.FunnelOp
 		for _, candidate := range candidates {
 			analysis, err := s.generateScreenAnalysis(ctx, candidate, options.Strategy)
 			if err != nil {
-				s.logger.Warn().Str("ticker", candidate.Ticker).Err(err).Msg("Failed to generate analysis")
+				logger.Warn().Str("ticker", candidate.Ticker).Err(err).Msg("Failed to generate analysis")
 				continue
 			}
 			candidate.Analysis = analysis
@@ -813,7 +841,7 @@ func (s *Screener) FunnelScreen(ctx context.Context, options interfaces.FunnelOp
 	result.Candidates = candidates
 	result.Duration = time.Since(start)
 
-	s.logger.Info().
+	logger.Info().
 		Int("final_candidates", len(candidates)).
 		Dur("duration", result.Duration).
 		Msg("Funnel screen complete")
@@ -19,22 +19,34 @@ import (
 type Service struct {
 	storage             interfaces.StorageManager
 	eodhd               interfaces.EODHDClient
+	providers           []interfaces.MarketDataProvider // price and fundamentals sources, in failover order
 	gemini              interfaces.GeminiClient
 	signalComputer      *signals.Computer
 	logger              *common.Logger
 	filingSizeThreshold int64 // PDFs above this size (bytes) are processed one-at-a-time (0 = use default 5MB)
+	coreCollectWorkers  int   // concurrent tickers in CollectCoreMarketData (0 = use default 5)
+	snipeThresholds     models.SnipeThresholds
+	indicatorSeriesMax  int // most days of indicator history per GetStockData call (0 = use default 365)
 }
 
-// NewService creates a new market service
+// NewService creates a new market service. Prices and fundamentals come from
+// providers in order, moving to the next when one is rate limited; without
+// providers EODHD is the only source. EODHD-specific data (bulk EOD, news,
+// symbols, screening) always uses the EODHD client.
 func NewService(
 	storage interfaces.StorageManager,
 	eodhd interfaces.EODHDClient,
 	gemini interfaces.GeminiClient,
 	logger *common.Logger,
+	providers ...interfaces.MarketDataProvider,
 ) *Service {
+	if len(providers) == 0 {
+		providers = defaultProviders(eodhd)
+	}
 	return &Service{
 		storage:        storage,
 		eodhd:          eodhd,
+		providers:      providers,
 		gemini:         gemini,
 		signalComputer: signals.NewComputer(),
 		logger:         logger,
@@ -46,6 +58,49 @@ func (s *Service) SetFilingSizeThreshold(threshold int64) {
 	s.filingSizeThreshold = threshold
 }
 
+// SetCoreCollectWorkers sets how many tickers CollectCoreMarketData processes
+// concurrently. EODHD requests still pass through the client's rate limiter.
+func (s *Service) SetCoreCollectWorkers(n int) {
+	s.coreCollectWorkers = n
+}
+
+// SetSnipeThresholds sets the server-wide snipe thresholds. Non-zero
+// request thresholds override them field by field.
+func (s *Service) SetSnipeThresholds(t models.SnipeThresholds) {
+	s.snipeThresholds = t
+}
+
+// SetMACDPeriods sets the MACD fast/slow/signal EMA periods used for the
+// signals this service computes.
+func (s *Service) SetMACDPeriods(fast, slow, signal int) {
+	s.signalComputer.SetMACDPeriods(fast, slow, signal)
+}
+
+// SetIndicatorSeriesMax caps the indicator history GetStockData returns.
+func (s *Service) SetIndicatorSeriesMax(n int) {
+	s.indicatorSeriesMax = n
+}
+
+// saveSignals persists sigs, keeping the stored news sentiment when sigs
+// was computed without one.
+func saveSignals(ctx context.Context, store interfaces.SignalStorage, sigs *models.TickerSignals) error {
+	if sigs == nil {
+		return store.SaveSignals(ctx, sigs)
+	}
+	if prev, err := store.GetSignals(ctx, sigs.Ticker); err == nil {
+		sigs.KeepNewsSentiment(prev)
+	}
+	return store.SaveSignals(ctx, sigs)
+}
+
+// getCoreCollectWorkers returns the configured worker count or the default (5).
+func (s *Service) getCoreCollectWorkers() int {
+	if s.coreCollectWorkers <= 0 {
+		return 5
+	}
+	return s.coreCollectWorkers
+}
+
 // getFilingSizeThreshold returns the configured threshold or the default (5MB).
 func (s *Service) getFilingSizeThreshold() int64 {
 	if s.filingSizeThreshold > 0 {
@@ -57,10 +112,11 @@ func (s *Service) getFilingSizeThreshold() int64 {
 // CollectMarketData fetches and stores market data for tickers.
 // When force is true, all data is re-fetched regardless of freshness.
 func (s *Service) CollectMarketData(ctx context.Context, tickers []string, includeNews bool, force bool) error {
+	logger := s.logger.WithRequestID(ctx)
 	now := time.Now()
 
 	for _, ticker := range tickers {
-		s.logger.Debug().Str("ticker", ticker).Bool("force", force).Msg("Collecting market data")
+		logger.Debug().Str("ticker", ticker).Bool("force", force).Msg("Collecting market data")
 
 		// Load existing data from storage
 		existing, _ := s.storage.MarketDataStorage().GetMarketData(ctx, ticker)
@@ -76,7 +132,7 @@ func (s *Service) CollectMarketData(ctx context.Context, tickers []string, inclu
 
 		// Schema-aware invalidation: clear stale derived data from older schema versions
 		if existing != nil && existing.DataVersion != common.SchemaVersion {
-			s.logger.Info().Str("ticker", ticker).
+			logger.Info().Str("ticker", ticker).
 				Str("cached_version", existing.DataVersion).
 				Str("current_version", common.SchemaVersion).
 				Msg("Schema mismatch — clearing stale derived data and forcing fundamentals re-fetch")
@@ -95,15 +151,14 @@ func (s *Service) CollectMarketData(ctx context.Context, tickers []string, inclu
 			var err error
 
 			if !force && existing != nil && len(existing.EOD) > 0 {
-				// Incremental fetch: only bars after the latest stored date
-				latestDate := existing.EOD[0].Date
-				fromDate := latestDate.AddDate(0, 0, 1) // day after last bar
+				// Incremental fetch: bars from the trailing revision window onwards
+				fromDate := incrementalEODFrom(existing.EOD)
 				if fromDate.Before(now) {
-					s.logger.Debug().Str("ticker", ticker).Str("from", fromDate.Format(time.RFC3339)).Msg("Incremental EOD fetch")
-					eodResp, err = s.eodhd.GetEOD(ctx, ticker, interfaces.WithDateRange(fromDate, now))
+					logger.Debug().Str("ticker", ticker).Str("from", fromDate.Format(time.RFC3339)).Msg("Incremental EOD fetch")
+					eodResp, err = s.getEOD(ctx, ticker, interfaces.WithDateRange(fromDate, now))
 					if err != nil {
-						s.logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to fetch incremental EOD data")
-					} else if len(eodResp.Data) > 0 {
+						logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to fetch incremental EOD data")
+					} else if eodBarsChanged(eodResp.Data, existing.EOD) {
 						marketData.EOD = filterBadEODBars(mergeEODBars(eodResp.Data, existing.EOD), ticker, s.logger)
 						eodChanged = true
 					}
@@ -111,9 +166,9 @@ func (s *Service) CollectMarketData(ctx context.Context, tickers []string, inclu
 				marketData.EODUpdatedAt = now
 			} else if force && existing != nil && len(existing.EOD) > 0 {
 				// Force refresh with existing data: full fetch + merge to preserve history
-				eodResp, err = s.eodhd.GetEOD(ctx, ticker, interfaces.WithDateRange(now.AddDate(-3, 0, 0), now))
+				eodResp, err = s.getEOD(ctx, ticker, interfaces.WithDateRange(now.AddDate(-3, 0, 0), now))
 				if err != nil {
-					s.logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to fetch EOD data (force)")
+					logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to fetch EOD data (force)")
 				} else if len(eodResp.Data) > 0 {
 					marketData.EOD = filterBadEODBars(mergeEODBars(eodResp.Data, existing.EOD), ticker, s.logger)
 					eodChanged = true
@@ -121,13 +176,13 @@ func (s *Service) CollectMarketData(ctx context.Context, tickers []string, inclu
 				marketData.EODUpdatedAt = now
 			} else {
 				// No existing data: full fetch (new ticker or first collection)
-				eodResp, err = s.eodhd.GetEOD(ctx, ticker, interfaces.WithDateRange(now.AddDate(-3, 0, 0), now))
+				eodResp, err = s.getEOD(ctx, ticker, interfaces.WithDateRange(now.AddDate(-3, 0, 0), now))
 				if err != nil {
-					s.logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to fetch EOD data")
+					logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to fetch EOD data")
 					continue
 				}
 				if len(eodResp.Data) == 0 {
-					s.logger.Warn().Str("ticker", ticker).Msg("EODHD returned empty EOD data for new ticker — will retry next cycle")
+					logger.Warn().Str("ticker", ticker).Msg("EODHD returned empty EOD data for new ticker — will retry next cycle")
 				} else {
 					marketData.EOD = filterBadEODBars(eodResp.Data, ticker, s.logger)
 					marketData.EODUpdatedAt = now
@@ -141,14 +196,15 @@ func (s *Service) CollectMarketData(ctx context.Context, tickers []string, inclu
 		needFundamentals := force || existing == nil || !common.IsFresh(existing.FundamentalsUpdatedAt, common.FreshnessFundamentals) ||
 			(existing != nil && existing.Fundamentals != nil && existing.Fundamentals.ISIN == "")
 		if needFundamentals {
-			fundamentals, err := s.eodhd.GetFundamentals(ctx, ticker)
+			fundamentals, err := s.getFundamentals(ctx, ticker, marketData.Fundamentals)
 			if err != nil {
-				s.logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to fetch fundamentals")
+				logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to fetch fundamentals")
 			} else {
 				if fundamentals != nil {
 					s.enrichFundamentals(ctx, fundamentals)
 				}
 				marketData.Fundamentals = fundamentals
+				marketData.EarningsDate = earningsDate(fundamentals, now)
 				marketData.FundamentalsUpdatedAt = now
 				if fundamentals != nil && fundamentals.Name != "" {
 					marketData.Name = fundamentals.Name
@@ -160,7 +216,7 @@ func (s *Service) CollectMarketData(ctx context.Context, tickers []string, inclu
 		if includeNews && (force || existing == nil || !common.IsFresh(existing.NewsUpdatedAt, common.FreshnessNews)) {
 			news, err := s.eodhd.GetNews(ctx, ticker, 10)
 			if err != nil {
-				s.logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to fetch news")
+				logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to fetch news")
 			} else {
 				marketData.News = news
 				marketData.NewsUpdatedAt = now
@@ -182,7 +238,7 @@ func (s *Service) CollectMarketData(ctx context.Context, tickers []string, inclu
 		if force || existing == nil || !common.IsFresh(existing.FilingsIndexUpdatedAt, common.FreshnessFilings) {
 			filings, err := s.collectFilings(ctx, ticker)
 			if err != nil {
-				s.logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to collect filings")
+				logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to collect filings")
 			} else {
 				filings = s.downloadFilingPDFs(ctx, extractCode(ticker), filings)
 				marketData.Filings = filings
@@ -228,15 +284,15 @@ func (s *Service) CollectMarketData(ctx context.Context, tickers []string, inclu
 
 		// Save market data
 		if err := s.storage.MarketDataStorage().SaveMarketData(ctx, marketData); err != nil {
-			s.logger.Error().Str("ticker", ticker).Err(err).Msg("Failed to save market data")
+			logger.Error().Str("ticker", ticker).Err(err).Msg("Failed to save market data")
 			continue
 		}
 
 		// Compute and save signals only when EOD data changed
 		if eodChanged {
 			tickerSignals := s.signalComputer.Compute(marketData)
-			if err := s.storage.SignalStorage().SaveSignals(ctx, tickerSignals); err != nil {
-				s.logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to save signals")
+			if err := saveSignals(ctx, s.storage.SignalStorage(), tickerSignals); err != nil {
+				logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to save signals")
 			}
 		}
 	}
@@ -270,7 +326,7 @@ func (s *Service) CollectCoreMarketData(ctx context.Context, tickers []string, f
 		for exchange, exchangeTickers := range byExchange {
 			bars, err := s.eodhd.GetBulkEOD(ctx, exchange, exchangeTickers)
 			if err != nil {
-				s.logger.Warn().Str("exchange", exchange).Err(err).Msg("Bulk EOD fetch failed")
+				s.logger.WithRequestID(ctx).Warn().Str("exchange", exchange).Err(err).Msg("Bulk EOD fetch failed")
 			} else {
 				for k, v := range bars {
 					bulkBars[k] = v
@@ -280,8 +336,7 @@ func (s *Service) CollectCoreMarketData(ctx context.Context, tickers []string, f
 	}
 
 	// Process each ticker: EOD + fundamentals only
-	const maxConcurrent = 5
-	sem := make(chan struct{}, maxConcurrent)
+	sem := make(chan struct{}, s.getCoreCollectWorkers())
 	var wg sync.WaitGroup
 	var mu sync.Mutex
 	var errs []error
@@ -311,7 +366,7 @@ func (s *Service) CollectCoreMarketData(ctx context.Context, tickers []string, f
 	wg.Wait()
 
 	if len(errs) > 0 {
-		s.logger.Warn().Int("errors", len(errs)).Msg("CollectCoreMarketData completed with errors")
+		s.logger.WithRequestID(ctx).Warn().Int("errors", len(errs)).Msg("CollectCoreMarketData completed with errors")
 		return errors.Join(errs...)
 	}
 
@@ -320,6 +375,7 @@ func (s *Service) CollectCoreMarketData(ctx context.Context, tickers []string, f
 
 // collectCoreTicker handles EOD + fundamentals for a single ticker in the fast path.
 func (s *Service) collectCoreTicker(ctx context.Context, ticker string, bulkBars map[string]models.EODBar, force bool, now time.Time) error {
+	logger := s.logger.WithRequestID(ctx)
 	existing, _ := s.storage.MarketDataStorage().GetMarketData(ctx, ticker)
 
 	marketData := &models.MarketData{
@@ -332,7 +388,7 @@ func (s *Service) collectCoreTicker(ctx context.Context, ticker string, bulkBars
 
 	// Schema-aware invalidation
 	if existing != nil && existing.DataVersion != common.SchemaVersion {
-		s.logger.Info().Str("ticker", ticker).Msg("Schema mismatch — clearing stale derived data (core path)")
+		logger.Info().Str("ticker", ticker).Msg("Schema mismatch — clearing stale derived data (core path)")
 		marketData.FilingSummaries = nil
 		marketData.FilingSummariesUpdatedAt = time.Time{}
 		marketData.CompanyTimeline = nil
@@ -353,16 +409,15 @@ func (s *Service) collectCoreTicker(ctx context.Context, ticker string, bulkBars
 				eodChanged = true
 			}
 			marketData.EODUpdatedAt = now
-		} else if s.eodhd != nil {
+		} else if len(s.providers) > 0 {
 			if !force && existing != nil && len(existing.EOD) > 0 {
-				// Incremental fetch: only bars after the latest stored date
-				latestDate := existing.EOD[0].Date
-				fromDate := latestDate.AddDate(0, 0, 1)
+				// Incremental fetch: bars from the trailing revision window onwards
+				fromDate := incrementalEODFrom(existing.EOD)
 				if fromDate.Before(now) {
-					eodResp, err := s.eodhd.GetEOD(ctx, ticker, interfaces.WithDateRange(fromDate, now))
+					eodResp, err := s.getEOD(ctx, ticker, interfaces.WithDateRange(fromDate, now))
 					if err != nil {
-						s.logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to fetch incremental EOD data (core)")
-					} else if len(eodResp.Data) > 0 {
+						logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to fetch incremental EOD data (core)")
+					} else if eodBarsChanged(eodResp.Data, existing.EOD) {
 						marketData.EOD = filterBadEODBars(mergeEODBars(eodResp.Data, existing.EOD), ticker, s.logger)
 						eodChanged = true
 					}
@@ -370,35 +425,35 @@ func (s *Service) collectCoreTicker(ctx context.Context, ticker string, bulkBars
 				marketData.EODUpdatedAt = now
 			} else if force && existing != nil && len(existing.EOD) > 0 {
 				// Force refresh with existing data: full fetch + merge to preserve history
-				eodResp, err := s.eodhd.GetEOD(ctx, ticker, interfaces.WithDateRange(now.AddDate(-3, 0, 0), now))
+				eodResp, err := s.getEOD(ctx, ticker, interfaces.WithDateRange(now.AddDate(-3, 0, 0), now))
 				if err != nil {
-					s.logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to fetch EOD data (core, force)")
+					logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to fetch EOD data (core, force)")
 				} else if len(eodResp.Data) > 0 {
 					marketData.EOD = filterBadEODBars(mergeEODBars(eodResp.Data, existing.EOD), ticker, s.logger)
 					eodChanged = true
 				} else if bar, ok := bulkBars[ticker]; ok {
 					// Individual endpoint returned empty on force — merge bulk bar
-					s.logger.Info().Str("ticker", ticker).Msg("Individual EOD empty on force, using bulk bar as fallback")
+					logger.Info().Str("ticker", ticker).Msg("Individual EOD empty on force, using bulk bar as fallback")
 					marketData.EOD = filterBadEODBars(mergeEODBars([]models.EODBar{bar}, existing.EOD), ticker, s.logger)
 					eodChanged = true
 				}
 				marketData.EODUpdatedAt = now
 			} else {
 				// No existing data: full fetch (new ticker or first collection)
-				eodResp, err := s.eodhd.GetEOD(ctx, ticker, interfaces.WithDateRange(now.AddDate(-3, 0, 0), now))
+				eodResp, err := s.getEOD(ctx, ticker, interfaces.WithDateRange(now.AddDate(-3, 0, 0), now))
 				if err != nil {
-					s.logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to fetch EOD data (core)")
+					logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to fetch EOD data (core)")
 					return err
 				}
 				if len(eodResp.Data) == 0 {
 					// Individual endpoint returned empty — fall back to bulk bar
 					if bar, ok := bulkBars[ticker]; ok {
-						s.logger.Info().Str("ticker", ticker).Msg("Individual EOD empty, using bulk bar as fallback")
+						logger.Info().Str("ticker", ticker).Msg("Individual EOD empty, using bulk bar as fallback")
 						marketData.EOD = filterBadEODBars([]models.EODBar{bar}, ticker, s.logger)
 						marketData.EODUpdatedAt = now
 						eodChanged = true
 					} else {
-						s.logger.Warn().Str("ticker", ticker).Msg("EODHD returned empty EOD data for new ticker (core) — will retry next cycle")
+						logger.Warn().Str("ticker", ticker).Msg("EODHD returned empty EOD data for new ticker (core) — will retry next cycle")
 					}
 				} else {
 					marketData.EOD = filterBadEODBars(eodResp.Data, ticker, s.logger)
@@ -412,15 +467,16 @@ func (s *Service) collectCoreTicker(ctx context.Context, ticker string, bulkBars
 	// --- Fundamentals ---
 	needFundamentals := force || existing == nil || !common.IsFresh(existing.FundamentalsUpdatedAt, common.FreshnessFundamentals) ||
 		(existing != nil && existing.Fundamentals != nil && existing.Fundamentals.ISIN == "")
-	if needFundamentals && s.eodhd != nil {
-		fundamentals, err := s.eodhd.GetFundamentals(ctx, ticker)
+	if needFundamentals && len(s.providers) > 0 {
+		fundamentals, err := s.getFundamentals(ctx, ticker, marketData.Fundamentals)
 		if err != nil {
-			s.logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to fetch fundamentals (core)")
+			logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to fetch fundamentals (core)")
 		} else {
 			if fundamentals != nil {
 				s.enrichFundamentals(ctx, fundamentals)
 			}
 			marketData.Fundamentals = fundamentals
+			marketData.EarningsDate = earningsDate(fundamentals, now)
 			marketData.FundamentalsUpdatedAt = now
 			if fundamentals != nil && fundamentals.Name != "" {
 				marketData.Name = fundamentals.Name
@@ -433,7 +489,7 @@ func (s *Service) collectCoreTicker(ctx context.Context, ticker string, bulkBars
 	if needFilingsIndex {
 		filings, err := s.collectFilings(ctx, ticker)
 		if err != nil {
-			s.logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to collect filings index (core)")
+			logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to collect filings index (core)")
 		} else {
 			// Merge with existing PDF paths to preserve previously downloaded files
 			if existing != nil && len(existing.Filings) > 0 {
@@ -454,8 +510,8 @@ func (s *Service) collectCoreTicker(ctx context.Context, ticker string, bulkBars
 	// Compute and save signals only when EOD data changed
 	if eodChanged {
 		tickerSignals := s.signalComputer.Compute(marketData)
-		if err := s.storage.SignalStorage().SaveSignals(ctx, tickerSignals); err != nil {
-			s.logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to save signals (core)")
+		if err := saveSignals(ctx, s.storage.SignalStorage(), tickerSignals); err != nil {
+			logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to save signals (core)")
 		}
 	}
 
@@ -520,6 +576,34 @@ func filterBadEODBars(bars []models.EODBar, ticker string, logger *common.Logger
 	return filtered
 }
 
+// eodRevisionDays is how far before the latest stored bar an incremental EOD
+// fetch starts. Re-fetching the trailing window picks up adjusted-close
+// revisions from recent splits and dividends.
+const eodRevisionDays = 30
+
+// incrementalEODFrom returns the start date for an incremental fetch over
+// stored bars (newest first).
+func incrementalEODFrom(bars []models.EODBar) time.Time {
+	return bars[0].Date.AddDate(0, 0, -eodRevisionDays)
+}
+
+// eodBarsChanged reports whether merging newBars would add or revise any
+// stored bar. Re-fetched bars identical to the stored ones are not a change.
+func eodBarsChanged(newBars, existingBars []models.EODBar) bool {
+	byDate := make(map[string]models.EODBar, len(existingBars))
+	for _, b := range existingBars {
+		byDate[b.Date.Format("2006-01-02")] = b
+	}
+	for _, b := range newBars {
+		old, ok := byDate[b.Date.Format("2006-01-02")]
+		if !ok || old.Open != b.Open || old.High != b.High || old.Low != b.Low ||
+			old.Close != b.Close || old.AdjClose != b.AdjClose || old.Volume != b.Volume {
+			return true
+		}
+	}
+	return false
+}
+
 // mergeEODBars merges new bars into existing bars, deduplicating by date.
 // New bars take precedence over existing bars for the same date.
 // The output is always sorted descending (most recent first).
@@ -546,8 +630,32 @@ func mergeEODBars(newBars, existingBars []models.EODBar) []models.EODBar {
 	return merged
 }
 
+// eodSince returns the leading bars (newest first) dated on or after cutoff.
+func eodSince(bars []models.EODBar, cutoff time.Time) []models.EODBar {
+	n := 0
+	for n < len(bars) && !bars[n].Date.Before(cutoff) {
+		n++
+	}
+	return bars[:n]
+}
+
+// lookbackShortfallNote returns an advisory when stored history does not reach
+// back to the requested cutoff. A week of slack absorbs weekends and holidays.
+func lookbackShortfallNote(lookback string, bars []models.EODBar, cutoff time.Time) string {
+	if len(bars) == 0 {
+		return ""
+	}
+	oldest := bars[len(bars)-1].Date
+	if !oldest.After(cutoff.AddDate(0, 0, 7)) {
+		return ""
+	}
+	return fmt.Sprintf("Requested %s window but stored history only starts %s (%d bars); returning available data.",
+		lookback, oldest.Format("2006-01-02"), len(bars))
+}
+
 // GetStockData retrieves stock data with optional components
 func (s *Service) GetStockData(ctx context.Context, ticker string, include interfaces.StockDataInclude) (*models.StockData, error) {
+	logger := s.logger.WithRequestID(ctx)
 	stockData := &models.StockData{
 		Ticker: ticker,
 	}
@@ -570,6 +678,21 @@ func (s *Service) GetStockData(ctx context.Context, ticker string, include inter
 	stockData.Exchange = marketData.Exchange
 	stockData.Name = marketData.Name
 
+	// Resolve the chart/signal window. With no lookback the latest 200 bars
+	// are charted and signals use the full stored history.
+	windowBars := marketData.EOD
+	if include.Lookback != "" {
+		cutoff, err := common.LookbackCutoff(include.Lookback, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		windowBars = eodSince(marketData.EOD, cutoff)
+		stockData.Lookback = include.Lookback
+		if note := lookbackShortfallNote(include.Lookback, marketData.EOD, cutoff); note != "" {
+			stockData.Advisory = append(stockData.Advisory, note)
+		}
+	}
+
 	// Include price data
 	if include.Price && len(marketData.EOD) > 0 {
 		current := marketData.EOD[0]
@@ -610,17 +733,17 @@ func (s *Service) GetStockData(ctx context.Context, ticker string, include inter
 			}
 		}
 
-		// Include historical OHLC candle data (up to 200 bars)
+		// Include historical OHLC candle data (up to 200 bars unless a lookback is set)
 		maxCandles := 200
-		candles := marketData.EOD
-		if len(candles) > maxCandles {
+		candles := windowBars
+		if include.Lookback == "" && len(candles) > maxCandles {
 			candles = candles[:maxCandles]
 		}
 		stockData.Candles = candles
 
 		// Attempt real-time price to override EOD close
-		if s.eodhd != nil {
-			if quote, err := s.eodhd.GetRealTimeQuote(ctx, ticker); err == nil && quote.Close > 0 {
+		if len(s.providers) > 0 {
+			if quote, err := s.getRealTimeQuote(ctx, ticker); err == nil && quote.Close > 0 {
 				stockData.Price.Current = quote.Close
 				stockData.Price.Open = quote.Open
 				stockData.Price.High = quote.High
@@ -638,9 +761,9 @@ func (s *Service) GetStockData(ctx context.Context, ticker string, include inter
 				if stockData.Price.LastWeekClose > 0 {
 					stockData.Price.LastWeekPct = ((quote.Close - stockData.Price.LastWeekClose) / stockData.Price.LastWeekClose) * 100
 				}
-				s.logger.Info().Str("ticker", ticker).Float64("live_price", quote.Close).Msg("Using real-time price")
+				logger.Info().Str("ticker", ticker).Float64("live_price", quote.Close).Msg("Using real-time price")
 			} else if err != nil {
-				s.logger.Warn().Str("ticker", ticker).Err(err).Msg("Real-time price unavailable, using EOD close")
+				logger.Warn().Str("ticker", ticker).Err(err).Msg("Real-time price unavailable, using EOD close")
 			}
 		}
 	}
@@ -655,14 +778,43 @@ func (s *Service) GetStockData(ctx context.Context, ticker string, include inter
 		stockData.Fundamentals = marketData.Fundamentals
 	}
 
+	// Recomputed signals and the indicator series use the RSI period the
+	// stored signals were computed with, so they agree with them
+	var storedSignals *models.TickerSignals
+	if include.Signals || include.IndicatorDays > 0 {
+		storedSignals, _ = s.storage.SignalStorage().GetSignals(ctx, ticker)
+	}
+	rsiPeriod := storedSignals.GetRSIPeriod()
+
 	// Include signals
 	if include.Signals {
-		tickerSignals, err := s.storage.SignalStorage().GetSignals(ctx, ticker)
-		if err != nil {
+		if include.Lookback != "" {
+			// Stored signals cover full history; recompute over the requested window
+			windowed := *marketData
+			windowed.EOD = windowBars
+			stockData.Signals = s.signalComputer.ComputeWithRSIPeriod(&windowed, rsiPeriod)
+		} else if storedSignals != nil {
+			stockData.Signals = storedSignals
+		} else {
 			// Compute fresh signals
-			tickerSignals = s.signalComputer.Compute(marketData)
+			stockData.Signals = s.signalComputer.ComputeWithRSIPeriod(marketData, rsiPeriod)
+		}
+	}
+
+	// Include indicator history, over the same bars as the signals so the
+	// latest point matches them
+	if include.IndicatorDays > 0 {
+		days := include.IndicatorDays
+		if limit := s.getIndicatorSeriesMax(); days > limit {
+			days = limit
+			stockData.Advisory = append(stockData.Advisory, fmt.Sprintf(
+				"Indicator series capped at %d days (requested %d).", limit, include.IndicatorDays))
+		}
+		stockData.IndicatorSeries = s.signalComputer.IndicatorSeries(windowBars, days, rsiPeriod)
+		if n := len(stockData.IndicatorSeries); n < days {
+			stockData.Advisory = append(stockData.Advisory, fmt.Sprintf(
+				"Indicator series has %d days: stored history is shorter than the %d requested.", n, days))
 		}
-		stockData.Signals = tickerSignals
 	}
 
 	// Include news
@@ -687,7 +839,7 @@ func (s *Service) GetStockData(ctx context.Context, ticker string, include inter
 	if len(marketData.Filings) == 0 || !common.IsFresh(marketData.FilingsIndexUpdatedAt, common.FreshnessFilings) {
 		filings, err := s.collectFilings(ctx, ticker)
 		if err != nil {
-			s.logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to auto-collect filings")
+			logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to auto-collect filings")
 		} else if len(filings) > 0 {
 			filings = s.downloadFilingPDFs(ctx, extractCode(ticker), filings)
 			marketData.Filings = filings
@@ -716,9 +868,19 @@ func (s *Service) GetStockData(ctx context.Context, ticker string, include inter
 	return stockData, nil
 }
 
+// getIndicatorSeriesMax returns the configured indicator history cap or the
+// default (365).
+func (s *Service) getIndicatorSeriesMax() int {
+	if s.indicatorSeriesMax <= 0 {
+		return 365
+	}
+	return s.indicatorSeriesMax
+}
+
 // FindSnipeBuys identifies turnaround stocks
 func (s *Service) FindSnipeBuys(ctx context.Context, options interfaces.SnipeOptions) ([]*models.SnipeBuy, error) {
 	sniper := NewSniper(s.storage, s.eodhd, s.gemini, s.signalComputer, s.logger)
+	options.Thresholds = s.snipeThresholds.Merge(options.Thresholds)
 	return sniper.FindSnipeBuys(ctx, options)
 }
 
@@ -728,6 +890,12 @@ func (s *Service) ScreenStocks(ctx context.Context, options interfaces.ScreenOpt
 	return screener.ScreenStocks(ctx, options)
 }
 
+// ScreenAndScore runs an EODHD screener query and ranks the results by composite score
+func (s *Service) ScreenAndScore(ctx context.Context, options models.ScreenerOptions, weights models.ScoreWeights) ([]*models.ScoredResult, error) {
+	screener := NewScreener(s.storage, s.eodhd, s.gemini, s.signalComputer, s.logger)
+	return screener.ScreenAndScore(ctx, options, weights)
+}
+
 // FunnelScreen runs a 3-stage funnel: EODHD screener -> fundamentals -> technical scoring
 func (s *Service) FunnelScreen(ctx context.Context, options interfaces.FunnelOptions) (*models.FunnelResult, error) {
 	screener := NewScreener(s.storage, s.eodhd, s.gemini, s.signalComputer, s.logger)
@@ -744,11 +912,11 @@ func (s *Service) RefreshStaleData(ctx context.Context, exchange string) error {
 	}
 
 	if len(staleTickers) == 0 {
-		s.logger.Info().Str("exchange", exchange).Msg("No stale data to refresh")
+		s.logger.WithRequestID(ctx).Info().Str("exchange", exchange).Msg("No stale data to refresh")
 		return nil
 	}
 
-	s.logger.Info().Str("exchange", exchange).Int("count", len(staleTickers)).Msg("Refreshing stale data")
+	s.logger.WithRequestID(ctx).Info().Str("exchange", exchange).Int("count", len(staleTickers)).Msg("Refreshing stale data")
 
 	return s.CollectMarketData(ctx, staleTickers, false, false)
 }
@@ -3,14 +3,18 @@ package market
 import (
 	"context"
 	"fmt"
+	"io"
 	"math"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/bobmcallan/vire/internal/common"
 	"github.com/bobmcallan/vire/internal/interfaces"
 	"github.com/bobmcallan/vire/internal/models"
+	"github.com/bobmcallan/vire/internal/signals"
 )
 
 func approxEqual(a, b, epsilon float64) bool {
@@ -25,6 +29,7 @@ type mockEODHDClient struct {
 	getBulkEODFn            func(ctx context.Context, exchange string, tickers []string) (map[string]models.EODBar, error)
 	getFundFn               func(ctx context.Context, ticker string) (*models.Fundamentals, error)
 	getBulkRealTimeQuotesFn func(ctx context.Context, tickers []string) (map[string]*models.RealTimeQuote, error)
+	screenStocksFn          func(ctx context.Context, options models.ScreenerOptions) ([]*models.ScreenerResult, error)
 }
 
 func (m *mockEODHDClient) GetRealTimeQuote(ctx context.Context, ticker string) (*models.RealTimeQuote, error) {
@@ -46,6 +51,9 @@ func (m *mockEODHDClient) GetBulkRealTimeQuotes(ctx context.Context, tickers []s
 	}
 	return nil, fmt.Errorf("not implemented")
 }
+func (m *mockEODHDClient) GetRealTimeQuotesBatch(ctx context.Context, tickers []string) (map[string]*models.RealTimeQuote, error) {
+	return nil, fmt.Errorf("not implemented")
+}
 func (m *mockEODHDClient) GetBulkEOD(ctx context.Context, exchange string, tickers []string) (map[string]models.EODBar, error) {
 	if m.getBulkEODFn != nil {
 		return m.getBulkEODFn(ctx, exchange, tickers)
@@ -68,6 +76,9 @@ func (m *mockEODHDClient) GetExchangeSymbols(ctx context.Context, exchange strin
 	return nil, fmt.Errorf("not implemented")
 }
 func (m *mockEODHDClient) ScreenStocks(ctx context.Context, options models.ScreenerOptions) ([]*models.ScreenerResult, error) {
+	if m.screenStocksFn != nil {
+		return m.screenStocksFn(ctx, options)
+	}
 	return nil, fmt.Errorf("not implemented")
 }
 func (m *mockEODHDClient) GetDividends(_ context.Context, _ string, _, _ time.Time) ([]models.DividendEvent, error) {
@@ -111,16 +122,31 @@ func (m *mockMarketDataStorage) GetStaleTickers(_ context.Context, _ string, _ i
 	return nil, nil
 }
 
-type mockSignalStorage struct{}
+type mockSignalStorage struct {
+	data map[string]*models.TickerSignals
+}
 
-func (m *mockSignalStorage) GetSignals(_ context.Context, _ string) (*models.TickerSignals, error) {
+func (m *mockSignalStorage) GetSignals(_ context.Context, ticker string) (*models.TickerSignals, error) {
+	if s, ok := m.data[ticker]; ok {
+		return s, nil
+	}
 	return nil, fmt.Errorf("not found")
 }
-func (m *mockSignalStorage) SaveSignals(_ context.Context, _ *models.TickerSignals) error {
+func (m *mockSignalStorage) SaveSignals(_ context.Context, s *models.TickerSignals) error {
+	if m.data == nil {
+		m.data = make(map[string]*models.TickerSignals)
+	}
+	m.data[s.Ticker] = s
 	return nil
 }
-func (m *mockSignalStorage) GetSignalsBatch(_ context.Context, _ []string) ([]*models.TickerSignals, error) {
-	return nil, nil
+func (m *mockSignalStorage) GetSignalsBatch(_ context.Context, tickers []string) ([]*models.TickerSignals, error) {
+	var result []*models.TickerSignals
+	for _, t := range tickers {
+		if s, ok := m.data[t]; ok {
+			result = append(result, s)
+		}
+	}
+	return result, nil
 }
 
 type mockStorageManager struct {
@@ -151,7 +177,13 @@ func (m *mockStorageManager) PurgeDerivedData(_ context.Context) (map[string]int
 	return nil, nil
 }
 func (m *mockStorageManager) PurgeReports(_ context.Context) (int, error) { return 0, nil }
-func (m *mockStorageManager) Close() error                                { return nil }
+func (m *mockStorageManager) Backup(_ context.Context, _ io.Writer) (*models.BackupManifest, error) {
+	return nil, nil
+}
+func (m *mockStorageManager) Restore(_ context.Context, _ io.Reader) (*models.BackupManifest, error) {
+	return nil, nil
+}
+func (m *mockStorageManager) Close() error { return nil }
 
 type mockFileStore struct {
 	files map[string][]byte
@@ -175,6 +207,13 @@ func (m *mockFileStore) HasFile(_ context.Context, category, key string) (bool,
 	_, ok := m.files[category+"/"+key]
 	return ok, nil
 }
+func (m *mockFileStore) StatFile(_ context.Context, category, key string) (*models.FileMeta, error) {
+	d, ok := m.files[category+"/"+key]
+	if !ok {
+		return nil, fmt.Errorf("not found")
+	}
+	return &models.FileMeta{Category: category, Key: key, ContentType: "application/octet-stream", Size: int64(len(d))}, nil
+}
 
 // --- tests ---
 
@@ -540,6 +579,100 @@ func TestGetStockData_CandlesLimitedTo200(t *testing.T) {
 	}
 }
 
+func TestGetStockData_IndicatorSeries(t *testing.T) {
+	today := time.Now()
+	bars := make([]models.EODBar, 120)
+	for i := range bars {
+		bars[i] = models.EODBar{Date: today.AddDate(0, 0, -i), Close: 40 + float64(i%13)*0.7}
+	}
+	storage := &mockStorageManager{
+		market: &mockMarketDataStorage{
+			data: map[string]*models.MarketData{
+				"BHP.AU": {
+					Ticker:                "BHP.AU",
+					Exchange:              "AU",
+					LastUpdated:           today,
+					FilingsIndexUpdatedAt: today,
+					Filings:               []models.CompanyFiling{{Date: today, Headline: "Test"}},
+					EOD:                   bars,
+				},
+			},
+		},
+		signals: &mockSignalStorage{},
+	}
+	svc := NewService(storage, &mockEODHDClient{}, nil, common.NewLogger("error"))
+	svc.SetIndicatorSeriesMax(60)
+
+	data, err := svc.GetStockData(context.Background(), "BHP.AU", interfaces.StockDataInclude{Signals: true, IndicatorDays: 20})
+	if err != nil {
+		t.Fatalf("GetStockData failed: %v", err)
+	}
+	if len(data.IndicatorSeries) != 20 {
+		t.Fatalf("IndicatorSeries length = %d, want 20", len(data.IndicatorSeries))
+	}
+	if got, want := data.IndicatorSeries[0].RSI, data.Signals.Technical.RSI; got != want {
+		t.Errorf("latest series RSI = %v, signal RSI = %v", got, want)
+	}
+	if got, want := data.IndicatorSeries[0].MACDHistogram, data.Signals.Technical.MACDHistogram; got != want {
+		t.Errorf("latest series MACD histogram = %v, signal = %v", got, want)
+	}
+	if len(data.Advisory) != 0 {
+		t.Errorf("unexpected advisory: %v", data.Advisory)
+	}
+
+	// Over the configured maximum: capped with an advisory.
+	data, err = svc.GetStockData(context.Background(), "BHP.AU", interfaces.StockDataInclude{IndicatorDays: 500})
+	if err != nil {
+		t.Fatalf("GetStockData failed: %v", err)
+	}
+	if len(data.IndicatorSeries) != 60 || len(data.Advisory) != 1 {
+		t.Errorf("capped series = %d points, advisory %v; want 60 points and one advisory", len(data.IndicatorSeries), data.Advisory)
+	}
+
+	// Over the stored history: truncated to the bars available.
+	svc.SetIndicatorSeriesMax(0)
+	data, err = svc.GetStockData(context.Background(), "BHP.AU", interfaces.StockDataInclude{IndicatorDays: 200})
+	if err != nil {
+		t.Fatalf("GetStockData failed: %v", err)
+	}
+	if len(data.IndicatorSeries) != 120 || len(data.Advisory) != 1 {
+		t.Errorf("short-history series = %d points, advisory %v; want 120 points and one advisory", len(data.IndicatorSeries), data.Advisory)
+	}
+}
+
+func TestGetStockData_IndicatorSeriesUsesStoredRSIPeriod(t *testing.T) {
+	today := time.Now()
+	bars := make([]models.EODBar, 120)
+	for i := range bars {
+		bars[i] = models.EODBar{Date: today.AddDate(0, 0, -i), Close: 40 + float64(i%13)*0.7}
+	}
+	md := &models.MarketData{
+		Ticker:                "BHP.AU",
+		Exchange:              "AU",
+		LastUpdated:           today,
+		FilingsIndexUpdatedAt: today,
+		Filings:               []models.CompanyFiling{{Date: today, Headline: "Test"}},
+		EOD:                   bars,
+	}
+	stored := signals.NewComputer().ComputeWithRSIPeriod(md, 21)
+	storage := &mockStorageManager{
+		market:  &mockMarketDataStorage{data: map[string]*models.MarketData{"BHP.AU": md}},
+		signals: &mockSignalStorage{data: map[string]*models.TickerSignals{"BHP.AU": stored}},
+	}
+	svc := NewService(storage, &mockEODHDClient{}, nil, common.NewLogger("error"))
+
+	data, err := svc.GetStockData(context.Background(), "BHP.AU", interfaces.StockDataInclude{IndicatorDays: 5})
+	if err != nil {
+		t.Fatalf("GetStockData failed: %v", err)
+	}
+	if got, want := data.IndicatorSeries[0].RSI, stored.Technical.RSI; got != want {
+		t.Errorf("latest series RSI = %v, want the stored RSI(21) %v", got, want)
+	}
+	if got := data.IndicatorSeries[0].RSI; got == signals.RSI(bars, models.DefaultRSIPeriod) {
+		t.Errorf("latest series RSI = %v, computed over the default period", got)
+	}
+}
+
 func TestGetStockData_CandlesNotIncludedWithoutPrice(t *testing.T) {
 	today := time.Now()
 
@@ -892,6 +1025,49 @@ func TestCollectCoreMarketData_UsesBulkEOD(t *testing.T) {
 	}
 }
 
+func TestCollectCoreMarketData_KeepsNewsSentiment(t *testing.T) {
+	now := time.Now()
+	yesterday := now.AddDate(0, 0, -1)
+	scoredAt := now.Add(-time.Hour)
+
+	storage := &mockStorageManager{
+		market: &mockMarketDataStorage{
+			data: map[string]*models.MarketData{
+				"BHP.AU": {
+					Ticker:       "BHP.AU",
+					Exchange:     "AU",
+					DataVersion:  common.SchemaVersion,
+					EODUpdatedAt: yesterday,
+					EOD:          []models.EODBar{{Date: yesterday, Close: 42.50}},
+				},
+			},
+		},
+		signals: &mockSignalStorage{data: map[string]*models.TickerSignals{
+			"BHP.AU": {Ticker: "BHP.AU", NewsSentiment: 0.4, NewsSentimentCount: 5, NewsSentimentAt: scoredAt},
+		}},
+	}
+	eodhd := &mockEODHDClient{
+		getBulkEODFn: func(_ context.Context, _ string, _ []string) (map[string]models.EODBar, error) {
+			return map[string]models.EODBar{"BHP.AU": {Date: now, Close: 43.00, Volume: 5000000}}, nil
+		},
+	}
+	svc := NewService(storage, eodhd, nil, common.NewLogger("error"))
+
+	if err := svc.CollectCoreMarketData(context.Background(), []string{"BHP.AU"}, false); err != nil {
+		t.Fatalf("CollectCoreMarketData failed: %v", err)
+	}
+
+	// Signals are recomputed from the new bar; the sentiment score survives
+	sigs := storage.signals.data["BHP.AU"]
+	if sigs == nil || sigs.ComputeTimestamp.IsZero() {
+		t.Fatalf("signals were not recomputed: %+v", sigs)
+	}
+	if sigs.NewsSentiment != 0.4 || sigs.NewsSentimentCount != 5 || !sigs.NewsSentimentAt.Equal(scoredAt) {
+		t.Errorf("sentiment = %.2f over %d at %v, want 0.40 over 5 at %v",
+			sigs.NewsSentiment, sigs.NewsSentimentCount, sigs.NewsSentimentAt, scoredAt)
+	}
+}
+
 func TestCollectCoreMarketData_CollectsFilingsIndex(t *testing.T) {
 	now := time.Now()
 	eodCalled := false
@@ -1048,6 +1224,54 @@ func TestCollectCoreMarketData_MultipleTickers(t *testing.T) {
 	}
 }
 
+func TestCollectCoreMarketData_ConcurrentWorkers(t *testing.T) {
+	now := time.Now()
+
+	storage := &mockStorageManager{
+		market:  &mockMarketDataStorage{data: map[string]*models.MarketData{}},
+		signals: &mockSignalStorage{},
+	}
+
+	var inFlight, peak atomic.Int32
+	eodhd := &mockEODHDClient{
+		getBulkEODFn: func(_ context.Context, _ string, _ []string) (map[string]models.EODBar, error) {
+			return nil, fmt.Errorf("no bulk")
+		},
+		getEODFn: func(_ context.Context, _ string, _ ...interfaces.EODOption) (*models.EODResponse, error) {
+			n := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(50 * time.Millisecond)
+			return &models.EODResponse{Data: []models.EODBar{{Date: now, Close: 10.0}}}, nil
+		},
+		getFundFn: func(_ context.Context, _ string) (*models.Fundamentals, error) {
+			return &models.Fundamentals{Sector: "Test"}, nil
+		},
+	}
+
+	svc := NewService(storage, eodhd, nil, common.NewLogger("error"))
+	svc.SetCoreCollectWorkers(3)
+
+	tickers := []string{"BHP.AU", "RIO.AU", "WOW.AU", "CBA.AU", "NAB.AU", "ANZ.AU"}
+	if err := svc.CollectCoreMarketData(context.Background(), tickers, false); err != nil {
+		t.Fatalf("CollectCoreMarketData failed: %v", err)
+	}
+
+	if got := peak.Load(); got < 2 || got > 3 {
+		t.Errorf("peak concurrent EOD fetches = %d, want 2..3", got)
+	}
+	for _, ticker := range tickers {
+		if md, ok := storage.market.data[ticker]; !ok || len(md.EOD) == 0 {
+			t.Errorf("expected %s to be saved with EOD data", ticker)
+		}
+	}
+}
+
 // --- CollectBulkEOD tests ---
 
 func TestCollectBulkEOD_MergesBulkBar(t *testing.T) {
@@ -2221,3 +2445,187 @@ func TestGetStockData_LiveQuoteRecalculatesPcts(t *testing.T) {
 		t.Errorf("LastWeekPct = %.2f, want %.2f", data.Price.LastWeekPct, expectedLastWeekPct)
 	}
 }
+
+func TestGetStockData_LookbackLongerThanStoredHistory(t *testing.T) {
+	today := time.Now()
+
+	// One year of daily bars, most recent first
+	bars := make([]models.EODBar, 365)
+	for i := range bars {
+		bars[i] = models.EODBar{Date: today.AddDate(0, 0, -i), Open: 100, High: 101, Low: 99, Close: 100, Volume: 1000}
+	}
+
+	storage := &mockStorageManager{
+		market: &mockMarketDataStorage{
+			data: map[string]*models.MarketData{
+				"TEST.AU": {
+					Ticker: "TEST.AU", Exchange: "AU",
+					EOD: bars, EODUpdatedAt: today,
+					DataVersion: common.SchemaVersion, LastUpdated: today,
+					// Fresh filings prevent auto-collection HTTP calls
+					Filings:               []models.CompanyFiling{{Date: today, Headline: "Test"}},
+					FilingsIndexUpdatedAt: today,
+				},
+			},
+		},
+		signals: &mockSignalStorage{},
+	}
+	svc := NewService(storage, nil, nil, common.NewLogger("error"))
+
+	data, err := svc.GetStockData(context.Background(), "TEST.AU",
+		interfaces.StockDataInclude{Price: true, Signals: true, Lookback: "5y"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if data.Lookback != "5y" {
+		t.Errorf("Lookback = %q, want 5y", data.Lookback)
+	}
+	if len(data.Candles) != len(bars) {
+		t.Errorf("Candles = %d, want all %d stored bars", len(data.Candles), len(bars))
+	}
+	if data.Signals == nil {
+		t.Error("expected signals computed over the available window")
+	}
+
+	found := false
+	for _, note := range data.Advisory {
+		if strings.Contains(note, "5y window") && strings.Contains(note, "returning available data") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected shortfall advisory, got %v", data.Advisory)
+	}
+
+	// A window inside stored history trims candles and adds no advisory
+	data, err = svc.GetStockData(context.Background(), "TEST.AU",
+		interfaces.StockDataInclude{Price: true, Lookback: "6mo"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(data.Candles) == 0 || len(data.Candles) >= len(bars) {
+		t.Errorf("6mo Candles = %d, want a trimmed subset of %d", len(data.Candles), len(bars))
+	}
+	if len(data.Advisory) != 0 {
+		t.Errorf("unexpected advisory for 6mo window: %v", data.Advisory)
+	}
+
+	if _, err := svc.GetStockData(context.Background(), "TEST.AU",
+		interfaces.StockDataInclude{Price: true, Lookback: "forever"}); err == nil {
+		t.Error("expected error for invalid lookback")
+	}
+}
+
+func TestMergeEODBars_NoOverlap(t *testing.T) {
+	day := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	existing := []models.EODBar{{Date: day, Close: 10}, {Date: day.AddDate(0, 0, -1), Close: 9}}
+	incoming := []models.EODBar{{Date: day.AddDate(0, 0, 2), Close: 12}, {Date: day.AddDate(0, 0, 1), Close: 11}}
+
+	merged := mergeEODBars(incoming, existing)
+	if len(merged) != 4 {
+		t.Fatalf("len = %d, want 4", len(merged))
+	}
+	for i, want := range []float64{12, 11, 10, 9} {
+		if merged[i].Close != want {
+			t.Errorf("merged[%d].Close = %.0f, want %.0f", i, merged[i].Close, want)
+		}
+	}
+}
+
+func TestMergeEODBars_OverlappingDatesNewerWins(t *testing.T) {
+	day := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	existing := []models.EODBar{
+		{Date: day, Close: 10, AdjClose: 10},
+		{Date: day.AddDate(0, 0, -1), Close: 9, AdjClose: 9},
+	}
+	// Re-fetched window: yesterday's adjusted close revised after a dividend
+	incoming := []models.EODBar{
+		{Date: day.AddDate(0, 0, 1), Close: 11, AdjClose: 11},
+		{Date: day, Close: 10, AdjClose: 9.5},
+	}
+
+	merged := mergeEODBars(incoming, existing)
+	if len(merged) != 3 {
+		t.Fatalf("len = %d, want 3 (one bar per date)", len(merged))
+	}
+	if merged[1].AdjClose != 9.5 {
+		t.Errorf("overlapping bar AdjClose = %.2f, want revised 9.50", merged[1].AdjClose)
+	}
+	if !eodBarsChanged(incoming, existing) {
+		t.Error("expected revised and new bars to count as a change")
+	}
+}
+
+func TestMergeEODBars_EmptyIncrementalResponse(t *testing.T) {
+	day := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	existing := []models.EODBar{{Date: day, Close: 10}, {Date: day.AddDate(0, 0, -1), Close: 9}}
+
+	merged := mergeEODBars(nil, existing)
+	if len(merged) != 2 || merged[0].Close != 10 {
+		t.Errorf("merged = %+v, want existing bars unchanged", merged)
+	}
+	if eodBarsChanged(nil, existing) {
+		t.Error("expected empty response not to count as a change")
+	}
+	if eodBarsChanged(existing[:1], existing) {
+		t.Error("expected identical re-fetched bar not to count as a change")
+	}
+}
+
+func TestCollectCoreMarketData_IncrementalRefetchesRevisionWindow(t *testing.T) {
+	latest := time.Now().Truncate(24*time.Hour).AddDate(0, 0, -1)
+	existingBars := make([]models.EODBar, 60)
+	for i := range existingBars {
+		existingBars[i] = models.EODBar{Date: latest.AddDate(0, 0, -i), Close: 100, AdjClose: 100}
+	}
+
+	storage := &mockStorageManager{
+		market: &mockMarketDataStorage{data: map[string]*models.MarketData{
+			"BHP.AU": {
+				Ticker:       "BHP.AU",
+				Exchange:     "AU",
+				DataVersion:  common.SchemaVersion,
+				EODUpdatedAt: latest, // stale
+				EOD:          existingBars,
+			},
+		}},
+		signals: &mockSignalStorage{},
+	}
+
+	var params interfaces.EODParams
+	eodhd := &mockEODHDClient{
+		getBulkEODFn: func(_ context.Context, _ string, _ []string) (map[string]models.EODBar, error) {
+			return nil, fmt.Errorf("no bulk")
+		},
+		getEODFn: func(_ context.Context, _ string, opts ...interfaces.EODOption) (*models.EODResponse, error) {
+			for _, opt := range opts {
+				opt(&params)
+			}
+			// New bar plus a revised adjusted close inside the trailing window
+			return &models.EODResponse{Data: []models.EODBar{
+				{Date: latest.AddDate(0, 0, 1), Close: 101, AdjClose: 101},
+				{Date: latest.AddDate(0, 0, -10), Close: 100, AdjClose: 50},
+			}}, nil
+		},
+		getFundFn: func(_ context.Context, _ string) (*models.Fundamentals, error) {
+			return &models.Fundamentals{ISIN: "AU000000BHP4"}, nil
+		},
+	}
+
+	svc := NewService(storage, eodhd, nil, common.NewLogger("error"))
+	if err := svc.CollectCoreMarketData(context.Background(), []string{"BHP.AU"}, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want := latest.AddDate(0, 0, -eodRevisionDays); !params.From.Equal(want) {
+		t.Errorf("from = %s, want %s (latest bar minus %d days)", params.From.Format("2006-01-02"), want.Format("2006-01-02"), eodRevisionDays)
+	}
+	result := storage.market.data["BHP.AU"]
+	if len(result.EOD) != 61 {
+		t.Fatalf("len = %d, want 61", len(result.EOD))
+	}
+	if result.EOD[11].AdjClose != 50 {
+		t.Errorf("revised bar AdjClose = %.0f, want 50", result.EOD[11].AdjClose)
+	}
+}
@@ -42,7 +42,9 @@ func NewSniper(
 // snipeViaExchangeSymbols is a fallback method when EODHD Screener API is unavailable.
 // It fetches exchange symbols, samples them, and evaluates for turnaround signals.
 func (s *Sniper) snipeViaExchangeSymbols(ctx context.Context, options interfaces.SnipeOptions) ([]*models.SnipeBuy, error) {
-	s.logger.Info().Str("exchange", options.Exchange).Msg("Snipe via exchange symbols fallback")
+	logger := s.logger.WithRequestID(ctx)
+	logger.Info().Str("exchange", options.Exchange).Msg("Snipe via exchange symbols fallback")
+	thresholds := options.Thresholds.WithDefaults()
 
 	// Get all symbols for the exchange
 	symbols, err := s.eodhd.GetExchangeSymbols(ctx, options.Exchange)
@@ -72,7 +74,7 @@ func (s *Sniper) snipeViaExchangeSymbols(ctx context.Context, options interfaces
 		filtered = sampled
 	}
 
-	s.logger.Debug().Int("sampled", len(filtered)).Msg("Sampled symbols for snipe")
+	logger.Debug().Int("sampled", len(filtered)).Msg("Sampled symbols for snipe")
 
 	// Build tickers and fetch market data
 	screener := NewScreener(s.storage, s.eodhd, s.gemini, s.signalComputer, s.logger)
@@ -82,7 +84,7 @@ func (s *Sniper) snipeViaExchangeSymbols(ctx context.Context, options interfaces
 	}
 
 	if err := screener.collectMarketDataBatch(ctx, tickers, options.IncludeNews); err != nil {
-		s.logger.Warn().Err(err).Msg("Some market data collection failed for snipe")
+		logger.Warn().Err(err).Msg("Some market data collection failed for snipe")
 	}
 
 	// Score each candidate for turnaround potential
@@ -119,8 +121,8 @@ func (s *Sniper) snipeViaExchangeSymbols(ctx context.Context, options interfaces
 			continue
 		}
 
-		snipeBuy := s.scoreCandidate(ticker, sym, marketData, tickerSignals)
-		if snipeBuy != nil && snipeBuy.Score >= 0.6 {
+		snipeBuy := s.scoreCandidate(ticker, sym, marketData, tickerSignals, thresholds)
+		if snipeBuy != nil && snipeBuy.Score >= thresholds.MinScore {
 			if options.Strategy != nil && options.Strategy.RiskAppetite.Level == "conservative" {
 				for _, flag := range tickerSignals.RiskFlags {
 					if flag == "high_volatility" {
@@ -128,7 +130,7 @@ func (s *Sniper) snipeViaExchangeSymbols(ctx context.Context, options interfaces
 						break
 					}
 				}
-				if snipeBuy.Score < 0.6 {
+				if snipeBuy.Score < thresholds.MinScore {
 					continue
 				}
 			}
@@ -150,14 +152,14 @@ func (s *Sniper) snipeViaExchangeSymbols(ctx context.Context, options interfaces
 		candidates = candidates[:limit]
 	}
 
-	s.logger.Info().Int("candidates", len(candidates)).Msg("Snipe exchange symbols screening complete")
+	logger.Info().Int("candidates", len(candidates)).Msg("Snipe exchange symbols screening complete")
 
 	// AI analysis for final candidates
 	if len(candidates) > 0 && s.gemini != nil {
 		for _, candidate := range candidates {
 			analysis, err := s.generateAnalysis(ctx, candidate, options.Strategy)
 			if err != nil {
-				s.logger.Warn().Str("ticker", candidate.Ticker).Err(err).Msg("Failed to generate AI analysis")
+				logger.Warn().Str("ticker", candidate.Ticker).Err(err).Msg("Failed to generate AI analysis")
 				continue
 			}
 			candidate.Analysis = analysis
@@ -174,11 +176,13 @@ func (s *Sniper) snipeViaExchangeSymbols(ctx context.Context, options interfaces
 //  4. Strategy adjustments, sort, limit
 //  5. AI analysis for final candidates
 func (s *Sniper) FindSnipeBuys(ctx context.Context, options interfaces.SnipeOptions) ([]*models.SnipeBuy, error) {
-	s.logger.Info().
+	logger := s.logger.WithRequestID(ctx)
+	logger.Info().
 		Str("exchange", options.Exchange).
 		Int("limit", options.Limit).
 		Str("sector", options.Sector).
 		Msg("Scanning for snipe buys")
+	thresholds := options.Thresholds.WithDefaults()
 
 	// Step 1: EODHD Screener API with broad turnaround filters
 	// Use a Screener instance for the shared API call logic
@@ -218,7 +222,7 @@ func (s *Sniper) FindSnipeBuys(ctx context.Context, options interfaces.SnipeOpti
 	if err != nil {
 		// Check if this is a 403/subscription error - fall back to exchange symbols approach
 		if strings.Contains(err.Error(), "403") || strings.Contains(err.Error(), "Forbidden") {
-			s.logger.Info().Msg("Screener API unavailable, using exchange symbols fallback for snipe")
+			logger.Info().Msg("Screener API unavailable, using exchange symbols fallback for snipe")
 			return s.snipeViaExchangeSymbols(ctx, options)
 		}
 		return nil, fmt.Errorf("screener API query failed: %w", err)
@@ -235,13 +239,13 @@ func (s *Sniper) FindSnipeBuys(ctx context.Context, options interfaces.SnipeOpti
 		screenerResults = filtered
 	}
 
-	s.logger.Debug().
+	logger.Debug().
 		Int("results", len(screenerResults)).
 		Strs("filters", filterDescs).
 		Msg("Snipe screener API results")
 
 	if len(screenerResults) == 0 {
-		s.logger.Info().Msg("Snipe scan: no results from screener API")
+		logger.Info().Msg("Snipe scan: no results from screener API")
 		return []*models.SnipeBuy{}, nil
 	}
 
@@ -251,7 +255,7 @@ func (s *Sniper) FindSnipeBuys(ctx context.Context, options interfaces.SnipeOpti
 		tickers = append(tickers, r.Code+"."+options.Exchange)
 	}
 	if err := screener.collectMarketDataBatch(ctx, tickers, options.IncludeNews); err != nil {
-		s.logger.Warn().Err(err).Msg("Some market data collection failed for snipe candidates")
+		logger.Warn().Err(err).Msg("Some market data collection failed for snipe candidates")
 	}
 
 	// Step 3: Compute signals and score each candidate
@@ -284,8 +288,8 @@ func (s *Sniper) FindSnipeBuys(ctx context.Context, options interfaces.SnipeOpti
 		}
 
 		// Score the candidate
-		snipeBuy := s.scoreCandidate(ticker, symbol, marketData, tickerSignals)
-		if snipeBuy != nil && snipeBuy.Score >= 0.6 {
+		snipeBuy := s.scoreCandidate(ticker, symbol, marketData, tickerSignals, thresholds)
+		if snipeBuy != nil && snipeBuy.Score >= thresholds.MinScore {
 			// Conservative strategies penalise high-volatility candidates
 			if options.Strategy != nil && options.Strategy.RiskAppetite.Level == "conservative" {
 				for _, flag := range tickerSignals.RiskFlags {
@@ -294,7 +298,7 @@ func (s *Sniper) FindSnipeBuys(ctx context.Context, options interfaces.SnipeOpti
 						break
 					}
 				}
-				if snipeBuy.Score < 0.6 {
+				if snipeBuy.Score < thresholds.MinScore {
 					continue
 				}
 			}
@@ -317,38 +321,46 @@ func (s *Sniper) FindSnipeBuys(ctx context.Context, options interfaces.SnipeOpti
 		for _, candidate := range candidates {
 			analysis, err := s.generateAnalysis(ctx, candidate, options.Strategy)
 			if err != nil {
-				s.logger.Warn().Str("ticker", candidate.Ticker).Err(err).Msg("Failed to generate AI analysis")
+				logger.Warn().Str("ticker", candidate.Ticker).Err(err).Msg("Failed to generate AI analysis")
 				continue
 			}
 			candidate.Analysis = analysis
 		}
 	}
 
-	s.logger.Info().Int("candidates", len(candidates)).Msg("Snipe scan complete")
+	logger.Info().Int("candidates", len(candidates)).Msg("Snipe scan complete")
 
 	return candidates, nil
 }
 
-// scoreCandidate evaluates a stock for snipe potential
+// scoreCandidate evaluates a stock for snipe potential. Candidates below
+// the momentum or volume floors in th are rejected outright (nil).
 func (s *Sniper) scoreCandidate(
 	ticker string,
 	symbol *models.Symbol,
 	marketData *models.MarketData,
 	tickerSignals *models.TickerSignals,
+	th models.SnipeThresholds,
 ) *models.SnipeBuy {
 	if tickerSignals == nil || len(marketData.EOD) == 0 {
 		return nil
 	}
+	if th.MinMomentum != 0 && tickerSignals.TrendMomentum.Score < th.MinMomentum {
+		return nil
+	}
+	if th.MinVolumeRatio > 0 && tickerSignals.Technical.VolumeRatio < th.MinVolumeRatio {
+		return nil
+	}
 
 	score := 0.0
 	reasons := make([]string, 0)
 	riskFactors := make([]string, 0)
 
 	// Criteria 1: Oversold RSI (weight: 0.25)
-	if tickerSignals.Technical.RSI < 30 {
+	if tickerSignals.Technical.RSI < th.OversoldRSI {
 		score += 0.25
-		reasons = append(reasons, "RSI oversold (<30)")
-	} else if tickerSignals.Technical.RSI < 40 {
+		reasons = append(reasons, fmt.Sprintf("RSI oversold (<%g)", th.OversoldRSI))
+	} else if tickerSignals.Technical.RSI < th.NearOversoldRSI {
 		score += 0.15
 		reasons = append(reasons, "RSI approaching oversold")
 	}
@@ -0,0 +1,92 @@
+package market
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// snipeSignals scores 0.55 before RSI: support 0.20 + PBAS 0.20 + volume 0.15.
+func snipeSignals(rsi, momentum, volumeRatio float64) *models.TickerSignals {
+	sig := &models.TickerSignals{}
+	sig.Price.Current = 100
+	sig.Technical.RSI = rsi
+	sig.Technical.NearSupport = true
+	sig.Technical.VolumeRatio = volumeRatio
+	sig.PBAS.Interpretation = "underpriced"
+	sig.VLI.Interpretation = "accumulating"
+	sig.TrendMomentum.Score = momentum
+	return sig
+}
+
+func TestScoreCandidate_CustomThresholds(t *testing.T) {
+	sniper := NewSniper(nil, nil, nil, nil, common.NewLogger("error"))
+	md := &models.MarketData{EOD: []models.EODBar{{Date: time.Now(), Close: 100, Low: 50}}}
+	symbol := &models.Symbol{Code: "ABC", Exchange: "AU"}
+
+	// oversold (+0.25) reaches 0.80; approaching oversold (+0.15) only 0.70
+	th := models.SnipeThresholds{OversoldRSI: 35, MinScore: 0.75, MinMomentum: 0.2, MinVolumeRatio: 1.5}.WithDefaults()
+
+	tests := []struct {
+		name     string
+		signals  *models.TickerSignals
+		returned bool
+	}{
+		{"RSI just inside oversold", snipeSignals(34.9, 0.5, 2), true},
+		{"RSI just outside oversold", snipeSignals(35.1, 0.5, 2), false},
+		{"momentum just inside floor", snipeSignals(20, 0.21, 2), true},
+		{"momentum just outside floor", snipeSignals(20, 0.19, 2), false},
+		{"volume just inside floor", snipeSignals(20, 0.5, 1.51), true},
+		{"volume just outside floor", snipeSignals(20, 0.5, 1.49), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buy := sniper.scoreCandidate("ABC.AU", symbol, md, tt.signals, th)
+			got := buy != nil && buy.Score >= th.MinScore
+			if got != tt.returned {
+				score := -1.0
+				if buy != nil {
+					score = buy.Score
+				}
+				t.Errorf("returned = %v (score %.2f), want %v", got, score, tt.returned)
+			}
+		})
+	}
+}
+
+func TestScoreCandidate_DefaultThresholdsUnchanged(t *testing.T) {
+	sniper := NewSniper(nil, nil, nil, nil, common.NewLogger("error"))
+	md := &models.MarketData{EOD: []models.EODBar{{Date: time.Now(), Close: 100, Low: 50}}}
+	symbol := &models.Symbol{Code: "ABC", Exchange: "AU"}
+	th := models.SnipeThresholds{}.WithDefaults()
+
+	if buy := sniper.scoreCandidate("ABC.AU", symbol, md, snipeSignals(29, -0.9, 0.1), th); buy == nil || !approxScore(buy.Score, 0.80) {
+		t.Errorf("RSI 29 with defaults: got %+v, want score 0.80 with no momentum/volume filter", buy)
+	}
+	if buy := sniper.scoreCandidate("ABC.AU", symbol, md, snipeSignals(39, 0, 1), th); buy == nil || !approxScore(buy.Score, 0.70) {
+		t.Errorf("RSI 39 with defaults: got %+v, want score 0.70", buy)
+	}
+}
+
+func TestSnipeThresholds_MergeAndDefaults(t *testing.T) {
+	config := models.SnipeThresholds{OversoldRSI: 25, MinScore: 0.5}
+	req := models.SnipeThresholds{MinScore: 0.7, MinVolumeRatio: 2}
+
+	got := config.Merge(req).WithDefaults()
+	want := models.SnipeThresholds{OversoldRSI: 25, NearOversoldRSI: 40, MinVolumeRatio: 2, MinScore: 0.7}
+	if got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	// Raising only the oversold level keeps the near band at or above it
+	if got := (models.SnipeThresholds{OversoldRSI: 45}).WithDefaults(); got.NearOversoldRSI != 45 {
+		t.Errorf("NearOversoldRSI = %v, want 45", got.NearOversoldRSI)
+	}
+}
+
+func approxScore(a, b float64) bool {
+	d := a - b
+	return d < 1e-9 && d > -1e-9
+}
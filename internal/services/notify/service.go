@@ -0,0 +1,289 @@
+// Package notify delivers portfolio events to outbound webhooks
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/interfaces"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// Compile-time interface check
+var _ interfaces.NotificationService = (*Service)(nil)
+
+// Request headers set on every webhook POST.
+const (
+	HeaderEvent     = "X-Vire-Event"
+	HeaderDelivery  = "X-Vire-Delivery"
+	HeaderSignature = "X-Vire-Signature"
+)
+
+// signaturePrefix names the algorithm in HeaderSignature, GitHub-style.
+const signaturePrefix = "sha256="
+
+// maxBackoff caps the doubling retry delay.
+const maxBackoff = time.Minute
+
+// Service implements NotificationService
+type Service struct {
+	urls           []string
+	secret         []byte
+	maxAttempts    int
+	backoff        time.Duration
+	deadLetterFile string
+	client         *http.Client
+	logger         *common.Logger
+
+	wg     sync.WaitGroup
+	done   chan struct{}
+	closed sync.Once
+	dlMu   sync.Mutex // serialises dead-letter file appends
+}
+
+// NewService creates a webhook dispatcher from config. With no URLs
+// configured, Notify is a no-op.
+func NewService(cfg common.WebhooksConfig, logger *common.Logger) *Service {
+	var urls []string
+	for _, u := range cfg.URLs {
+		if u = strings.TrimSpace(u); u != "" {
+			urls = append(urls, u)
+		}
+	}
+	return &Service{
+		urls:           urls,
+		secret:         []byte(cfg.Secret),
+		maxAttempts:    cfg.GetMaxAttempts(),
+		backoff:        cfg.GetBackoff(),
+		deadLetterFile: cfg.DeadLetterFile,
+		client:         &http.Client{Timeout: cfg.GetTimeout()},
+		logger:         logger,
+		done:           make(chan struct{}),
+	}
+}
+
+// Enabled reports whether any webhook URL is configured.
+func (s *Service) Enabled() bool {
+	return len(s.urls) > 0
+}
+
+// Notify delivers the event to every configured URL in the background.
+// Delivery outlives the caller's request context but stops retrying on Close.
+func (s *Service) Notify(ctx context.Context, event models.NotificationEvent) {
+	if !s.Enabled() {
+		return
+	}
+	event = stampEvent(event)
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.deliverAll(context.WithoutCancel(ctx), event)
+	}()
+}
+
+// SendTestEvent delivers a sample event to every configured URL and waits
+// for the outcome, retries included.
+func (s *Service) SendTestEvent(ctx context.Context) ([]models.WebhookDelivery, error) {
+	if !s.Enabled() {
+		return nil, interfaces.ErrWebhooksNotConfigured
+	}
+	event := stampEvent(models.NotificationEvent{
+		Type:    models.NotificationTest,
+		Message: "Test event from Vire: webhook delivery is working",
+	})
+	return s.deliverAll(ctx, event), nil
+}
+
+// Close stops pending retries, dead-lettering their events, and waits for
+// in-flight deliveries to finish.
+func (s *Service) Close() {
+	s.closed.Do(func() { close(s.done) })
+	s.wg.Wait()
+}
+
+// Wait blocks until every event queued by Notify has been delivered or
+// dead-lettered.
+func (s *Service) Wait() {
+	s.wg.Wait()
+}
+
+// stampEvent fills in the event ID and time if the caller left them unset.
+func stampEvent(event models.NotificationEvent) models.NotificationEvent {
+	if event.ID == "" {
+		event.ID = uuid.New().String()
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now().UTC()
+	}
+	return event
+}
+
+// deliverAll sends the event to each URL concurrently and returns the
+// outcomes in URL order.
+func (s *Service) deliverAll(ctx context.Context, event models.NotificationEvent) []models.WebhookDelivery {
+	deliveries := make([]models.WebhookDelivery, len(s.urls))
+	body, err := json.Marshal(event)
+	if err != nil {
+		for i, u := range s.urls {
+			deliveries[i] = models.WebhookDelivery{URL: u, EventID: event.ID, Error: err.Error()}
+		}
+		return deliveries
+	}
+
+	var wg sync.WaitGroup
+	for i, u := range s.urls {
+		wg.Add(1)
+		go func(i int, u string) {
+			defer wg.Done()
+			deliveries[i] = s.deliver(ctx, u, event, body)
+		}(i, u)
+	}
+	wg.Wait()
+	return deliveries
+}
+
+// deliver POSTs body to url until it gets a 2xx response or runs out of
+// attempts, doubling the delay between attempts. Failures are dead-lettered.
+func (s *Service) deliver(ctx context.Context, url string, event models.NotificationEvent, body []byte) models.WebhookDelivery {
+	d := models.WebhookDelivery{URL: url, EventID: event.ID}
+	delay := s.backoff
+
+	for attempt := 1; attempt <= s.maxAttempts; attempt++ {
+		d.Attempts = attempt
+		status, err := s.post(ctx, url, event, body)
+		d.StatusCode = status
+		if err == nil && status >= 200 && status < 300 {
+			d.Delivered = true
+			d.Error = ""
+			s.logger.WithRequestID(ctx).Debug().Str("url", url).Str("event", event.Type).Str("event_id", event.ID).
+				Int("attempts", attempt).Msg("Webhook delivered")
+			return d
+		}
+		if err != nil {
+			d.Error = err.Error()
+		} else {
+			d.Error = fmt.Sprintf("HTTP %d", status)
+		}
+		if attempt == s.maxAttempts {
+			break
+		}
+
+		s.logger.WithRequestID(ctx).Warn().Str("url", url).Str("event_id", event.ID).Int("attempt", attempt).
+			Str("error", d.Error).Dur("retry_in", delay).Msg("Webhook delivery failed, retrying")
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			d.Error = fmt.Sprintf("%s; retry abandoned: %v", d.Error, ctx.Err())
+			s.deadLetter(event, d)
+			return d
+		case <-s.done:
+			d.Error = d.Error + "; retry abandoned: shutting down"
+			s.deadLetter(event, d)
+			return d
+		}
+		delay = min(delay*2, maxBackoff)
+	}
+
+	s.deadLetter(event, d)
+	return d
+}
+
+// post makes one delivery attempt and returns the response status.
+func (s *Service) post(ctx context.Context, url string, event models.NotificationEvent, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "vire/"+common.GetVersion())
+	req.Header.Set(HeaderEvent, event.Type)
+	req.Header.Set(HeaderDelivery, event.ID)
+	if len(s.secret) > 0 {
+		req.Header.Set(HeaderSignature, Sign(s.secret, body))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	return resp.StatusCode, nil
+}
+
+// deadLetterEntry is one line of the dead-letter file.
+type deadLetterEntry struct {
+	FailedAt   time.Time                `json:"failed_at"`
+	URL        string                   `json:"url"`
+	Attempts   int                      `json:"attempts"`
+	StatusCode int                      `json:"status_code,omitempty"`
+	Error      string                   `json:"error"`
+	Event      models.NotificationEvent `json:"event"`
+}
+
+// deadLetter records an event that could not be delivered: always to the
+// log, and to the dead-letter file when one is configured.
+func (s *Service) deadLetter(event models.NotificationEvent, d models.WebhookDelivery) {
+	s.logger.Error().Str("url", d.URL).Str("event", event.Type).Str("event_id", event.ID).
+		Int("attempts", d.Attempts).Str("error", d.Error).Msg("Webhook delivery gave up, event dead-lettered")
+	if s.deadLetterFile == "" {
+		return
+	}
+
+	line, err := json.Marshal(deadLetterEntry{
+		FailedAt:   time.Now().UTC(),
+		URL:        d.URL,
+		Attempts:   d.Attempts,
+		StatusCode: d.StatusCode,
+		Error:      d.Error,
+		Event:      event,
+	})
+	if err != nil {
+		return
+	}
+
+	s.dlMu.Lock()
+	defer s.dlMu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(s.deadLetterFile), 0755); err != nil {
+		s.logger.Warn().Err(err).Str("path", s.deadLetterFile).Msg("Failed to create dead-letter directory")
+		return
+	}
+	f, err := os.OpenFile(s.deadLetterFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		s.logger.Warn().Err(err).Str("path", s.deadLetterFile).Msg("Failed to open dead-letter file")
+		return
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		s.logger.Warn().Err(err).Str("path", s.deadLetterFile).Msg("Failed to write dead-letter entry")
+	}
+}
+
+// Sign returns the HeaderSignature value for body: "sha256=" followed by the
+// hex HMAC-SHA256 of the raw request body under secret.
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature reports whether header is a valid signature of body under
+// secret. Receivers should verify against the raw body before parsing it.
+func VerifySignature(secret, body []byte, header string) bool {
+	return hmac.Equal([]byte(header), []byte(Sign(secret, body)))
+}
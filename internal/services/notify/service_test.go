@@ -0,0 +1,238 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/interfaces"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// receivedRequest is one POST captured by a test receiver.
+type receivedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+// testReceiver is an httptest webhook endpoint that answers each request with
+// the next status in statuses, repeating the last one.
+type testReceiver struct {
+	*httptest.Server
+	mu       sync.Mutex
+	statuses []int
+	requests []receivedRequest
+}
+
+func newTestReceiver(t *testing.T, statuses ...int) *testReceiver {
+	t.Helper()
+	rcv := &testReceiver{statuses: statuses}
+	rcv.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		rcv.mu.Lock()
+		rcv.requests = append(rcv.requests, receivedRequest{header: r.Header.Clone(), body: body})
+		n := len(rcv.requests)
+		status := http.StatusOK
+		if len(rcv.statuses) > 0 {
+			status = rcv.statuses[min(n, len(rcv.statuses))-1]
+		}
+		rcv.mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(rcv.Close)
+	return rcv
+}
+
+func (r *testReceiver) received() []receivedRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]receivedRequest(nil), r.requests...)
+}
+
+func newTestService(urls []string, secret string, maxAttempts int, deadLetterFile string) *Service {
+	return NewService(common.WebhooksConfig{
+		URLs:           urls,
+		Secret:         secret,
+		MaxAttempts:    maxAttempts,
+		Backoff:        "1ms",
+		Timeout:        "2s",
+		DeadLetterFile: deadLetterFile,
+	}, common.NewLogger("error"))
+}
+
+func TestNotify_DeliversEvent(t *testing.T) {
+	rcv := newTestReceiver(t)
+	svc := newTestService([]string{rcv.URL}, "", 3, "")
+
+	svc.Notify(context.Background(), models.NotificationEvent{
+		Type:          models.NotificationWatchlistTarget,
+		PortfolioName: "SMSF",
+		Ticker:        "BHP.AU",
+		Message:       "BHP.AU crossed its 45.00 target up",
+	})
+	svc.Wait()
+
+	reqs := rcv.received()
+	if len(reqs) != 1 {
+		t.Fatalf("receiver got %d requests, want 1", len(reqs))
+	}
+	var got models.NotificationEvent
+	if err := json.Unmarshal(reqs[0].body, &got); err != nil {
+		t.Fatalf("payload is not JSON: %v", err)
+	}
+	if got.Type != models.NotificationWatchlistTarget || got.Ticker != "BHP.AU" || got.PortfolioName != "SMSF" {
+		t.Errorf("payload = %+v", got)
+	}
+	if got.ID == "" || got.OccurredAt.IsZero() {
+		t.Errorf("payload missing id or occurred_at: %+v", got)
+	}
+	h := reqs[0].header
+	if h.Get("Content-Type") != "application/json" {
+		t.Errorf("Content-Type = %q", h.Get("Content-Type"))
+	}
+	if h.Get(HeaderEvent) != models.NotificationWatchlistTarget || h.Get(HeaderDelivery) != got.ID {
+		t.Errorf("event headers = %q, %q", h.Get(HeaderEvent), h.Get(HeaderDelivery))
+	}
+	if h.Get(HeaderSignature) != "" {
+		t.Error("unsigned service sent a signature header")
+	}
+}
+
+func TestNotify_DisabledWithoutURLs(t *testing.T) {
+	svc := newTestService(nil, "", 3, "")
+	svc.Notify(context.Background(), models.NotificationEvent{Type: models.NotificationAlertRaised})
+	svc.Wait()
+
+	if _, err := svc.SendTestEvent(context.Background()); !errors.Is(err, interfaces.ErrWebhooksNotConfigured) {
+		t.Errorf("SendTestEvent = %v, want ErrWebhooksNotConfigured", err)
+	}
+}
+
+func TestSendTestEvent_SignatureVerifies(t *testing.T) {
+	const secret = "whsec-test"
+	rcv := newTestReceiver(t)
+	svc := newTestService([]string{rcv.URL}, secret, 3, "")
+
+	deliveries, err := svc.SendTestEvent(context.Background())
+	if err != nil {
+		t.Fatalf("SendTestEvent: %v", err)
+	}
+	if len(deliveries) != 1 || !deliveries[0].Delivered {
+		t.Fatalf("deliveries = %+v", deliveries)
+	}
+
+	req := rcv.received()[0]
+	sig := req.header.Get(HeaderSignature)
+	if !strings.HasPrefix(sig, "sha256=") {
+		t.Fatalf("signature header = %q", sig)
+	}
+	if !VerifySignature([]byte(secret), req.body, sig) {
+		t.Error("signature does not verify against the received body")
+	}
+	if VerifySignature([]byte("wrong-secret"), req.body, sig) {
+		t.Error("signature verified under the wrong secret")
+	}
+	tampered := append([]byte(nil), req.body...)
+	tampered[len(tampered)-2] ^= 1
+	if VerifySignature([]byte(secret), tampered, sig) {
+		t.Error("signature verified a tampered body")
+	}
+}
+
+func TestSendTestEvent_RetriesAfterServerError(t *testing.T) {
+	rcv := newTestReceiver(t, http.StatusInternalServerError, http.StatusOK)
+	deadLetter := filepath.Join(t.TempDir(), "dead.jsonl")
+	svc := newTestService([]string{rcv.URL}, "", 3, deadLetter)
+
+	deliveries, err := svc.SendTestEvent(context.Background())
+	if err != nil {
+		t.Fatalf("SendTestEvent: %v", err)
+	}
+	d := deliveries[0]
+	if !d.Delivered || d.Attempts != 2 || d.StatusCode != http.StatusOK || d.Error != "" {
+		t.Errorf("delivery = %+v, want delivered on attempt 2", d)
+	}
+
+	reqs := rcv.received()
+	if len(reqs) != 2 {
+		t.Fatalf("receiver got %d requests, want 2", len(reqs))
+	}
+	if reqs[0].header.Get(HeaderDelivery) != reqs[1].header.Get(HeaderDelivery) {
+		t.Error("retry changed the delivery ID")
+	}
+	if _, err := os.Stat(deadLetter); !os.IsNotExist(err) {
+		t.Error("delivered event was dead-lettered")
+	}
+}
+
+func TestNotify_GivesUpAfterMaxAttempts(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	deadLetter := filepath.Join(t.TempDir(), "webhooks", "dead.jsonl")
+	svc := newTestService([]string{srv.URL}, "", 3, deadLetter)
+
+	svc.Notify(context.Background(), models.NotificationEvent{
+		ID:      "evt-1",
+		Type:    models.NotificationPlanTriggered,
+		Message: "Plan item triggered",
+	})
+	svc.Wait()
+
+	if got := hits.Load(); got != 3 {
+		t.Errorf("receiver got %d attempts, want 3", got)
+	}
+
+	data, err := os.ReadFile(deadLetter)
+	if err != nil {
+		t.Fatalf("dead-letter file: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("dead-letter has %d entries, want 1", len(lines))
+	}
+	var entry deadLetterEntry
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("dead-letter entry: %v", err)
+	}
+	if entry.URL != srv.URL || entry.Attempts != 3 || entry.StatusCode != http.StatusInternalServerError {
+		t.Errorf("dead-letter entry = %+v", entry)
+	}
+	if entry.Event.ID != "evt-1" || entry.Event.Type != models.NotificationPlanTriggered {
+		t.Errorf("dead-letter event = %+v", entry.Event)
+	}
+}
+
+func TestClose_AbandonsPendingRetries(t *testing.T) {
+	rcv := newTestReceiver(t, http.StatusServiceUnavailable)
+	svc := NewService(common.WebhooksConfig{
+		URLs:        []string{rcv.URL},
+		MaxAttempts: 5,
+		Backoff:     "1h",
+	}, common.NewLogger("error"))
+
+	svc.Notify(context.Background(), models.NotificationEvent{Type: models.NotificationAlertRaised})
+	for len(rcv.received()) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	svc.Close() // would block for an hour if the backoff wait ignored Close
+
+	if got := len(rcv.received()); got != 1 {
+		t.Errorf("receiver got %d attempts after Close, want 1", got)
+	}
+}
@@ -0,0 +1,97 @@
+package plan
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// DetectDrift compares current holdings against plan items and returns a
+// plan_drift alert for each item the portfolio has materially deviated from:
+//   - SELL items that are completed, triggered, or past deadline while the
+//     ticker is still held
+//   - BUY items that are completed, triggered, or past deadline while the
+//     ticker is not held
+//   - held BUY items with a target_value whose market value deviates by more
+//     than the plan's drift tolerance
+//
+// Pending items with no deadline (or a future one) are not yet actionable and
+// never drift. Cancelled and expired-without-deadline items are ignored.
+func DetectDrift(plan *models.PortfolioPlan, holdings []models.Holding, now time.Time) []models.Alert {
+	if plan == nil || len(plan.Items) == 0 {
+		return nil
+	}
+
+	held := make(map[string]models.Holding, len(holdings))
+	for _, h := range holdings {
+		if h.Units <= 0 {
+			continue
+		}
+		held[strings.ToUpper(h.Ticker)] = h
+		held[strings.ToUpper(h.EODHDTicker())] = h
+	}
+
+	tolerance := plan.GetDriftTolerancePct()
+	var alerts []models.Alert
+
+	for _, item := range plan.Items {
+		if item.Ticker == "" || item.Status == models.PlanItemStatusCancelled {
+			continue
+		}
+		holding, isHeld := held[strings.ToUpper(item.Ticker)]
+		actionable := planItemActionable(item, now)
+
+		switch item.Action {
+		case models.RuleActionSell:
+			if actionable && isHeld {
+				alerts = append(alerts, driftAlert(item, "high",
+					fmt.Sprintf("Plan item '%s' is to exit %s (%s) but %.0f units are still held",
+						item.ID, item.Ticker, item.Status, holding.Units)))
+			}
+
+		case models.RuleActionBuy:
+			if actionable && !isHeld {
+				alerts = append(alerts, driftAlert(item, "medium",
+					fmt.Sprintf("Plan item '%s' is to buy %s (%s) but no position is held",
+						item.ID, item.Ticker, item.Status)))
+				continue
+			}
+			if isHeld && item.TargetValue > 0 {
+				deviationPct := (holding.MarketValue - item.TargetValue) / item.TargetValue * 100
+				if math.Abs(deviationPct) > tolerance {
+					alerts = append(alerts, driftAlert(item, "low",
+						fmt.Sprintf("%s position $%.0f deviates %.1f%% from plan target $%.0f (tolerance %.0f%%)",
+							item.Ticker, holding.MarketValue, deviationPct, item.TargetValue, tolerance)))
+				}
+			}
+		}
+	}
+
+	return alerts
+}
+
+// planItemActionable reports whether a plan item should already have been
+// acted on: it has been completed or triggered, or its deadline has passed.
+func planItemActionable(item models.PlanItem, now time.Time) bool {
+	switch item.Status {
+	case models.PlanItemStatusCompleted, models.PlanItemStatusTriggered:
+		return true
+	case models.PlanItemStatusPending, models.PlanItemStatusExpired:
+		return item.Deadline != nil && now.After(*item.Deadline)
+	default:
+		return false
+	}
+}
+
+func driftAlert(item models.PlanItem, severity, message string) models.Alert {
+	return models.Alert{
+		Type:     models.AlertTypeStrategy,
+		Severity: severity,
+		Ticker:   item.Ticker,
+		Message:  message,
+		Signal:   "plan_drift",
+	}
+}
@@ -0,0 +1,86 @@
+package plan
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+func TestDetectDrift_ExitPlannedButStillHeld(t *testing.T) {
+	plan := &models.PortfolioPlan{
+		Items: []models.PlanItem{
+			{ID: "exit-bhp", Ticker: "BHP.AU", Action: models.RuleActionSell, Status: models.PlanItemStatusCompleted},
+		},
+	}
+	holdings := []models.Holding{{Ticker: "BHP", Exchange: "AU", Units: 100, MarketValue: 4500}}
+
+	alerts := DetectDrift(plan, holdings, time.Now())
+	if len(alerts) != 1 {
+		t.Fatalf("expected 1 drift alert, got %d: %+v", len(alerts), alerts)
+	}
+	a := alerts[0]
+	if a.Signal != "plan_drift" {
+		t.Errorf("Signal = %q, want plan_drift", a.Signal)
+	}
+	if a.Type != models.AlertTypeStrategy {
+		t.Errorf("Type = %q, want %q", a.Type, models.AlertTypeStrategy)
+	}
+	if a.Ticker != "BHP.AU" {
+		t.Errorf("Ticker = %q, want BHP.AU", a.Ticker)
+	}
+}
+
+func TestDetectDrift_PendingAndCancelledItemsIgnored(t *testing.T) {
+	future := time.Now().Add(24 * time.Hour)
+	plan := &models.PortfolioPlan{
+		Items: []models.PlanItem{
+			{ID: "a", Ticker: "BHP.AU", Action: models.RuleActionSell, Status: models.PlanItemStatusPending},
+			{ID: "b", Ticker: "BHP.AU", Action: models.RuleActionSell, Status: models.PlanItemStatusPending, Deadline: &future},
+			{ID: "c", Ticker: "BHP.AU", Action: models.RuleActionSell, Status: models.PlanItemStatusCancelled},
+			{ID: "d", Ticker: "CBA.AU", Action: models.RuleActionSell, Status: models.PlanItemStatusCompleted},
+		},
+	}
+	holdings := []models.Holding{{Ticker: "BHP", Exchange: "AU", Units: 100}}
+
+	if alerts := DetectDrift(plan, holdings, time.Now()); len(alerts) != 0 {
+		t.Errorf("expected no drift alerts, got %+v", alerts)
+	}
+}
+
+func TestDetectDrift_OverdueBuyNotHeld(t *testing.T) {
+	past := time.Now().Add(-24 * time.Hour)
+	plan := &models.PortfolioPlan{
+		Items: []models.PlanItem{
+			{ID: "buy-cba", Ticker: "CBA.AU", Action: models.RuleActionBuy, Status: models.PlanItemStatusPending, Deadline: &past},
+		},
+	}
+
+	alerts := DetectDrift(plan, nil, time.Now())
+	if len(alerts) != 1 || alerts[0].Ticker != "CBA.AU" {
+		t.Fatalf("expected 1 drift alert for CBA.AU, got %+v", alerts)
+	}
+}
+
+func TestDetectDrift_TargetValueTolerance(t *testing.T) {
+	plan := &models.PortfolioPlan{
+		Items: []models.PlanItem{
+			{ID: "size-bhp", Ticker: "BHP.AU", Action: models.RuleActionBuy, Status: models.PlanItemStatusCompleted, TargetValue: 10000},
+		},
+	}
+
+	within := []models.Holding{{Ticker: "BHP", Exchange: "AU", Units: 100, MarketValue: 11500}}
+	if alerts := DetectDrift(plan, within, time.Now()); len(alerts) != 0 {
+		t.Errorf("15%% deviation within default tolerance, got %+v", alerts)
+	}
+
+	outside := []models.Holding{{Ticker: "BHP", Exchange: "AU", Units: 100, MarketValue: 13000}}
+	if alerts := DetectDrift(plan, outside, time.Now()); len(alerts) != 1 {
+		t.Errorf("30%% deviation should drift, got %+v", alerts)
+	}
+
+	plan.DriftTolerancePct = 50
+	if alerts := DetectDrift(plan, outside, time.Now()); len(alerts) != 0 {
+		t.Errorf("30%% deviation within 50%% tolerance, got %+v", alerts)
+	}
+}
@@ -20,6 +20,8 @@ var _ interfaces.PlanService = (*Service)(nil)
 type Service struct {
 	storage  interfaces.StorageManager
 	strategy interfaces.StrategyService
+	market   interfaces.MarketService
+	notifier interfaces.NotificationService
 	logger   *common.Logger
 }
 
@@ -32,6 +34,35 @@ func NewService(storage interfaces.StorageManager, strategy interfaces.StrategyS
 	}
 }
 
+// SetMarketService sets the market service used to price trailing stops.
+// Without it, CheckTrailingStops leaves trailing_stop items untouched.
+func (s *Service) SetMarketService(svc interfaces.MarketService) {
+	s.market = svc
+}
+
+// SetNotificationService sets the webhook dispatcher told about triggered
+// plan items.
+func (s *Service) SetNotificationService(svc interfaces.NotificationService) {
+	s.notifier = svc
+}
+
+// notifyTriggered sends a webhook event for each newly triggered item.
+func (s *Service) notifyTriggered(ctx context.Context, portfolioName string, items []models.PlanItem) {
+	if s.notifier == nil {
+		return
+	}
+	for _, item := range items {
+		s.notifier.Notify(ctx, models.NotificationEvent{
+			Type:          models.NotificationPlanTriggered,
+			PortfolioName: portfolioName,
+			Ticker:        item.Ticker,
+			Message:       fmt.Sprintf("Plan item triggered: %s", item.Description),
+			OccurredAt:    item.UpdatedAt,
+			Data:          item,
+		})
+	}
+}
+
 // GetPlan retrieves the plan for a portfolio
 func (s *Service) GetPlan(ctx context.Context, portfolioName string) (*models.PortfolioPlan, error) {
 	userID := common.ResolveUserID(ctx)
@@ -46,12 +77,30 @@ func (s *Service) GetPlan(ctx context.Context, portfolioName string) (*models.Po
 	return &plan, nil
 }
 
-// SavePlan saves a plan with version increment
+// SavePlan saves a plan with version increment. A trailing stop's peak price
+// is server-owned: a submitted peak is ignored. An item already stored keeps
+// its stored peak, and a new item, or one whose ticker or type changed, is
+// seeded from the current price.
 func (s *Service) SavePlan(ctx context.Context, plan *models.PortfolioPlan) error {
+	storedItems := make(map[string]models.PlanItem)
+	if stored, err := s.GetPlan(ctx, plan.PortfolioName); err == nil {
+		for _, item := range stored.Items {
+			storedItems[item.ID] = item
+		}
+	}
+	for i := range plan.Items {
+		it := &plan.Items[i]
+		if prev, ok := storedItems[it.ID]; ok && !peakInvalidated(prev, *it) {
+			it.PeakPrice = prev.PeakPrice
+		} else {
+			s.seedPeakPrice(ctx, it)
+		}
+	}
+
 	if err := s.savePlanRecord(ctx, plan); err != nil {
 		return fmt.Errorf("failed to save plan: %w", err)
 	}
-	s.logger.Info().Str("portfolio", plan.PortfolioName).Msg("Plan saved")
+	s.logger.WithRequestID(ctx).Info().Str("portfolio", plan.PortfolioName).Msg("Plan saved")
 	return nil
 }
 
@@ -61,7 +110,7 @@ func (s *Service) DeletePlan(ctx context.Context, portfolioName string) error {
 	if err := s.storage.UserDataStore().Delete(ctx, userID, "plan", portfolioName); err != nil {
 		return fmt.Errorf("failed to delete plan: %w", err)
 	}
-	s.logger.Info().Str("portfolio", portfolioName).Msg("Plan deleted")
+	s.logger.WithRequestID(ctx).Info().Str("portfolio", portfolioName).Msg("Plan deleted")
 	return nil
 }
 
@@ -110,6 +159,7 @@ func (s *Service) AddPlanItem(ctx context.Context, portfolioName string, item *m
 	if item.Status == "" {
 		item.Status = models.PlanItemStatusPending
 	}
+	s.seedPeakPrice(ctx, item)
 
 	plan.Items = append(plan.Items, *item)
 
@@ -117,7 +167,7 @@ func (s *Service) AddPlanItem(ctx context.Context, portfolioName string, item *m
 		return nil, fmt.Errorf("failed to save plan: %w", err)
 	}
 
-	s.logger.Info().Str("portfolio", portfolioName).Str("item_id", item.ID).Msg("Plan item added")
+	s.logger.WithRequestID(ctx).Info().Str("portfolio", portfolioName).Str("item_id", item.ID).Msg("Plan item added")
 	return plan, nil
 }
 
@@ -166,9 +216,15 @@ func (s *Service) UpdatePlanItem(ctx context.Context, portfolioName, itemID stri
 			if update.TargetValue != 0 {
 				merged.TargetValue = update.TargetValue
 			}
+			if update.TrailPct != 0 {
+				merged.TrailPct = update.TrailPct
+			}
 			if update.Notes != "" {
 				merged.Notes = update.Notes
 			}
+			if peakInvalidated(item, merged) {
+				s.seedPeakPrice(ctx, &merged)
+			}
 			merged.UpdatedAt = time.Now()
 
 			plan.Items[i] = merged
@@ -185,7 +241,7 @@ func (s *Service) UpdatePlanItem(ctx context.Context, portfolioName, itemID stri
 		return nil, fmt.Errorf("failed to save plan: %w", err)
 	}
 
-	s.logger.Info().Str("portfolio", portfolioName).Str("item_id", itemID).Msg("Plan item updated")
+	s.logger.WithRequestID(ctx).Info().Str("portfolio", portfolioName).Str("item_id", itemID).Msg("Plan item updated")
 	return plan, nil
 }
 
@@ -216,7 +272,7 @@ func (s *Service) RemovePlanItem(ctx context.Context, portfolioName, itemID stri
 		return nil, fmt.Errorf("failed to save plan: %w", err)
 	}
 
-	s.logger.Info().Str("portfolio", portfolioName).Str("item_id", itemID).Msg("Plan item removed")
+	s.logger.WithRequestID(ctx).Info().Str("portfolio", portfolioName).Str("item_id", itemID).Msg("Plan item removed")
 	return plan, nil
 }
 
@@ -274,6 +330,7 @@ func (s *Service) CheckPlanEvents(ctx context.Context, portfolioName string) ([]
 		}
 	}
 
+	s.notifyTriggered(ctx, portfolioName, triggered)
 	return triggered, nil
 }
 
@@ -313,6 +370,100 @@ func (s *Service) CheckPlanDeadlines(ctx context.Context, portfolioName string)
 	return expired, nil
 }
 
+// seedPeakPrice sets a new trailing_stop item's peak to the current price,
+// ignoring any client-supplied value. Without a price the peak starts at 0
+// and the first CheckTrailingStops raises it.
+func (s *Service) seedPeakPrice(ctx context.Context, item *models.PlanItem) {
+	item.PeakPrice = 0
+	if item.Type != models.PlanItemTypeTrailingStop {
+		return
+	}
+	if price, ok := s.currentPrice(ctx, item.Ticker); ok {
+		item.PeakPrice = price
+	}
+}
+
+// peakInvalidated reports whether next tracks a different price series than
+// prev, so prev's peak no longer applies.
+func peakInvalidated(prev, next models.PlanItem) bool {
+	return !strings.EqualFold(prev.Ticker, next.Ticker) || prev.Type != next.Type
+}
+
+// currentPrice returns ticker's current price from the market service.
+func (s *Service) currentPrice(ctx context.Context, ticker string) (float64, bool) {
+	if s.market == nil || ticker == "" {
+		return 0, false
+	}
+	stock, err := s.market.GetStockData(ctx, ticker, interfaces.StockDataInclude{Price: true})
+	if err != nil || stock == nil || stock.Price == nil || stock.Price.Current <= 0 {
+		return 0, false
+	}
+	return stock.Price.Current, true
+}
+
+// CheckTrailingStops prices pending trailing_stop items via the market
+// service. The item's peak price is raised to the current price when it is
+// higher (and never lowered), then the item triggers once the price falls
+// trail_pct below the peak. The peak and trail status are persisted with
+// the plan so they survive restarts. Returns items that were triggered.
+func (s *Service) CheckTrailingStops(ctx context.Context, portfolioName string) ([]models.PlanItem, error) {
+	plan, err := s.GetPlan(ctx, portfolioName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get plan: %w", err)
+	}
+	if s.market == nil {
+		return nil, nil
+	}
+
+	now := time.Now()
+	var triggered []models.PlanItem
+	changed := false
+
+	for i, item := range plan.Items {
+		if item.Status != models.PlanItemStatusPending || item.Type != models.PlanItemTypeTrailingStop {
+			continue
+		}
+		if item.Ticker == "" || item.TrailPct <= 0 || item.TrailPct >= 100 {
+			continue
+		}
+
+		price, ok := s.currentPrice(ctx, item.Ticker)
+		if !ok {
+			s.logger.WithRequestID(ctx).Warn().Str("portfolio", portfolioName).Str("item_id", item.ID).Str("ticker", item.Ticker).
+				Msg("Trailing stop: no current price")
+			continue
+		}
+
+		it := &plan.Items[i]
+		if price > it.PeakPrice {
+			it.PeakPrice = price
+		}
+		level := it.PeakPrice * (1 - it.TrailPct/100)
+		it.Trail = &models.TrailStatus{
+			CurrentPrice: price,
+			TrailLevel:   level,
+			DistancePct:  (price - level) / price * 100,
+			CheckedAt:    now,
+		}
+		it.UpdatedAt = now
+		changed = true
+
+		if price <= level {
+			it.Status = models.PlanItemStatusTriggered
+			triggered = append(triggered, *it)
+		}
+	}
+
+	if changed {
+		if err := s.savePlanRecord(ctx, plan); err != nil {
+			return nil, fmt.Errorf("failed to save plan: %w", err)
+		}
+	}
+
+	s.notifyTriggered(ctx, portfolioName, triggered)
+	return triggered, nil
+}
+
 // ValidatePlanAgainstStrategy checks plan items against portfolio strategy
 func (s *Service) ValidatePlanAgainstStrategy(_ context.Context, plan *models.PortfolioPlan, strategy *models.PortfolioStrategy) []models.StrategyWarning {
 	if plan == nil || strategy == nil {
@@ -3,6 +3,8 @@ package plan
 import (
 	"context"
 	"fmt"
+	"io"
+	"math"
 	"sync"
 	"testing"
 	"time"
@@ -72,6 +74,11 @@ func (m *memUserDataStore) Query(_ context.Context, userID, subject string, opts
 	return m.List(context.Background(), userID, subject)
 }
 
+func (m *memUserDataStore) Count(ctx context.Context, userID, subject string, _ interfaces.QueryOptions) (int, error) {
+	records, err := m.List(ctx, userID, subject)
+	return len(records), err
+}
+
 func (m *memUserDataStore) DeleteBySubject(_ context.Context, subject string) (int, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -145,7 +152,13 @@ func (m *mockStorageManager) UserDataStore() interfaces.UserDataStore         {
 func (m *mockStorageManager) PurgeDerivedData(_ context.Context) (map[string]int, error) {
 	return nil, nil
 }
-func (m *mockStorageManager) PurgeReports(_ context.Context) (int, error)    { return 0, nil }
+func (m *mockStorageManager) PurgeReports(_ context.Context) (int, error) { return 0, nil }
+func (m *mockStorageManager) Backup(_ context.Context, _ io.Writer) (*models.BackupManifest, error) {
+	return nil, nil
+}
+func (m *mockStorageManager) Restore(_ context.Context, _ io.Reader) (*models.BackupManifest, error) {
+	return nil, nil
+}
 func (m *mockStorageManager) StockIndexStore() interfaces.StockIndexStore    { return nil }
 func (m *mockStorageManager) JobQueueStore() interfaces.JobQueueStore        { return nil }
 func (m *mockStorageManager) FileStore() interfaces.FileStore                { return nil }
@@ -166,6 +179,9 @@ func (m *mockStrategyService) SaveStrategy(_ context.Context, _ *models.Portfoli
 	return nil, nil
 }
 func (m *mockStrategyService) DeleteStrategy(_ context.Context, _ string) error { return nil }
+func (m *mockStrategyService) ApplyStrategyToPortfolios(_ context.Context, _ []string, _ models.PortfolioStrategy) ([]models.StrategyWarning, error) {
+	return nil, nil
+}
 func (m *mockStrategyService) ValidateStrategy(_ context.Context, _ *models.PortfolioStrategy) []models.StrategyWarning {
 	return nil
 }
@@ -676,3 +692,195 @@ func TestCheckPlanEvents_FundamentalsCondition(t *testing.T) {
 		t.Errorf("expected 'buy-cba-pe', got '%s'", triggered[0].ID)
 	}
 }
+
+// stubPriceMarketService serves current prices for trailing stop checks.
+type stubPriceMarketService struct {
+	interfaces.MarketService
+	prices map[string]float64
+}
+
+func (m *stubPriceMarketService) GetStockData(_ context.Context, ticker string, _ interfaces.StockDataInclude) (*models.StockData, error) {
+	p, ok := m.prices[ticker]
+	if !ok {
+		return nil, fmt.Errorf("not found")
+	}
+	return &models.StockData{Ticker: ticker, Price: &models.PriceData{Current: p}}, nil
+}
+
+func TestCheckTrailingStops(t *testing.T) {
+	logger := common.NewLogger("error")
+	sm := newMockStorageManager()
+	market := &stubPriceMarketService{prices: map[string]float64{"BHP.AU": 40}}
+	ctx := context.Background()
+
+	svc := NewService(sm, &mockStrategyService{}, logger)
+	svc.SetMarketService(market)
+
+	_, err := svc.AddPlanItem(ctx, "SMSF", &models.PlanItem{
+		ID: "ts-1", Type: models.PlanItemTypeTrailingStop, Ticker: "BHP.AU", TrailPct: 10,
+		Action: models.RuleActionSell, Description: "Trail BHP 10%",
+	})
+	if err != nil {
+		t.Fatalf("AddPlanItem failed: %v", err)
+	}
+
+	check := func(price float64) ([]models.PlanItem, models.PlanItem) {
+		t.Helper()
+		market.prices["BHP.AU"] = price
+		triggered, err := svc.CheckTrailingStops(ctx, "SMSF")
+		if err != nil {
+			t.Fatalf("CheckTrailingStops failed: %v", err)
+		}
+		plan, _ := svc.GetPlan(ctx, "SMSF")
+		return triggered, plan.Items[0]
+	}
+
+	// First check seeds the peak
+	triggered, item := check(40)
+	if len(triggered) != 0 || item.PeakPrice != 40 {
+		t.Fatalf("after 40: triggered=%d peak=%.2f, want 0 and 40", len(triggered), item.PeakPrice)
+	}
+
+	// Price rises: peak follows, trail level = 50 × 0.9 = 45
+	triggered, item = check(50)
+	if len(triggered) != 0 || item.PeakPrice != 50 {
+		t.Fatalf("after 50: triggered=%d peak=%.2f, want 0 and 50", len(triggered), item.PeakPrice)
+	}
+	if item.Trail == nil || math.Abs(item.Trail.TrailLevel-45) > 1e-9 || math.Abs(item.Trail.DistancePct-10) > 1e-9 {
+		t.Fatalf("after 50: trail = %+v, want level 45, distance 10%%", item.Trail)
+	}
+
+	// Price dips but stays above the trail: peak is never lowered
+	triggered, item = check(46)
+	if len(triggered) != 0 || item.PeakPrice != 50 || item.Status != models.PlanItemStatusPending {
+		t.Fatalf("after 46: triggered=%d peak=%.2f status=%s, want 0, 50, pending", len(triggered), item.PeakPrice, item.Status)
+	}
+
+	// Peak survives a restart: a fresh service over the same store keeps it
+	restarted := NewService(sm, &mockStrategyService{}, logger)
+	restarted.SetMarketService(market)
+	svc = restarted
+
+	// Falls to the trail level: triggers
+	triggered, item = check(45)
+	if len(triggered) != 1 || triggered[0].ID != "ts-1" {
+		t.Fatalf("after 45: triggered = %+v, want ts-1", triggered)
+	}
+	if item.Status != models.PlanItemStatusTriggered || item.PeakPrice != 50 {
+		t.Errorf("after 45: status=%s peak=%.2f, want triggered and 50", item.Status, item.PeakPrice)
+	}
+
+	// Triggered items are not re-evaluated
+	triggered, _ = check(30)
+	if len(triggered) != 0 {
+		t.Errorf("triggered item re-evaluated: %+v", triggered)
+	}
+}
+
+func TestCheckTrailingStops_NoMarketService(t *testing.T) {
+	svc, _ := newTestService()
+	ctx := context.Background()
+	_, _ = svc.AddPlanItem(ctx, "SMSF", &models.PlanItem{
+		Type: models.PlanItemTypeTrailingStop, Ticker: "BHP.AU", TrailPct: 10,
+	})
+
+	triggered, err := svc.CheckTrailingStops(ctx, "SMSF")
+	if err != nil || len(triggered) != 0 {
+		t.Errorf("CheckTrailingStops = (%v, %v), want no triggers and no error", triggered, err)
+	}
+}
+
+func TestTrailingStopPeak_ServerOwnedAndNeverLowered(t *testing.T) {
+	logger := common.NewLogger("error")
+	sm := newMockStorageManager()
+	market := &stubPriceMarketService{prices: map[string]float64{"BHP.AU": 40}}
+	ctx := context.Background()
+
+	svc := NewService(sm, &mockStrategyService{}, logger)
+	svc.SetMarketService(market)
+
+	// A client-supplied peak is ignored on add; the current price seeds it
+	plan, err := svc.AddPlanItem(ctx, "SMSF", &models.PlanItem{
+		ID: "ts-1", Type: models.PlanItemTypeTrailingStop, Ticker: "BHP.AU", TrailPct: 10, PeakPrice: 5,
+	})
+	if err != nil {
+		t.Fatalf("AddPlanItem failed: %v", err)
+	}
+	if got := plan.Items[0].PeakPrice; got != 40 {
+		t.Fatalf("seeded peak = %.2f, want current price 40", got)
+	}
+
+	market.prices["BHP.AU"] = 50
+	if _, err := svc.CheckTrailingStops(ctx, "SMSF"); err != nil {
+		t.Fatalf("CheckTrailingStops failed: %v", err)
+	}
+
+	// Saving the plan with a lowered, reset or raised peak keeps the stored one
+	for _, submitted := range []float64{0, 42, 999} {
+		plan, _ = svc.GetPlan(ctx, "SMSF")
+		plan.Items[0].PeakPrice = submitted
+		if err := svc.SavePlan(ctx, plan); err != nil {
+			t.Fatalf("SavePlan failed: %v", err)
+		}
+		plan, _ = svc.GetPlan(ctx, "SMSF")
+		if got := plan.Items[0].PeakPrice; got != 50 {
+			t.Errorf("submitted peak %.2f: stored peak = %.2f, want 50", submitted, got)
+		}
+	}
+
+	// A new item arriving through SavePlan is seeded like an add
+	plan, _ = svc.GetPlan(ctx, "SMSF")
+	plan.Items = append(plan.Items, models.PlanItem{
+		ID: "ts-2", Type: models.PlanItemTypeTrailingStop, Ticker: "BHP.AU", TrailPct: 5, PeakPrice: 999,
+	})
+	if err := svc.SavePlan(ctx, plan); err != nil {
+		t.Fatalf("SavePlan failed: %v", err)
+	}
+	plan, _ = svc.GetPlan(ctx, "SMSF")
+	if got := plan.Items[1].PeakPrice; got != 50 {
+		t.Errorf("new item peak = %.2f, want current price 50", got)
+	}
+}
+
+func TestUpdatePlanItem_TickerChangeReseedsPeak(t *testing.T) {
+	logger := common.NewLogger("error")
+	sm := newMockStorageManager()
+	market := &stubPriceMarketService{prices: map[string]float64{"BHP.AU": 50, "RIO.AU": 120}}
+	ctx := context.Background()
+
+	svc := NewService(sm, &mockStrategyService{}, logger)
+	svc.SetMarketService(market)
+	if _, err := svc.AddPlanItem(ctx, "SMSF", &models.PlanItem{
+		ID: "ts-1", Type: models.PlanItemTypeTrailingStop, Ticker: "BHP.AU", TrailPct: 10,
+	}); err != nil {
+		t.Fatalf("AddPlanItem failed: %v", err)
+	}
+
+	// Unrelated edits keep the peak
+	plan, err := svc.UpdatePlanItem(ctx, "SMSF", "ts-1", &models.PlanItem{TrailPct: 5})
+	if err != nil {
+		t.Fatalf("UpdatePlanItem failed: %v", err)
+	}
+	if got := plan.Items[0].PeakPrice; got != 50 {
+		t.Fatalf("peak after trail edit = %.2f, want 50", got)
+	}
+
+	// A new ticker starts from its own price, not BHP's peak
+	plan, err = svc.UpdatePlanItem(ctx, "SMSF", "ts-1", &models.PlanItem{Ticker: "RIO.AU"})
+	if err != nil {
+		t.Fatalf("UpdatePlanItem failed: %v", err)
+	}
+	if got := plan.Items[0].PeakPrice; got != 120 {
+		t.Errorf("peak after ticker change = %.2f, want RIO's 120", got)
+	}
+
+	// So does a ticker change arriving through SavePlan
+	plan.Items[0].Ticker = "BHP.AU"
+	if err := svc.SavePlan(ctx, plan); err != nil {
+		t.Fatalf("SavePlan failed: %v", err)
+	}
+	plan, _ = svc.GetPlan(ctx, "SMSF")
+	if got := plan.Items[0].PeakPrice; got != 50 {
+		t.Errorf("peak after SavePlan ticker change = %.2f, want BHP's 50", got)
+	}
+}
@@ -0,0 +1,214 @@
+package portfolio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/interfaces"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// alertStateSubject holds one record per active alert, keyed
+// "{portfolio}:{TICKER}:{signal}".
+const alertStateSubject = "alert_state"
+
+// alertSignal identifies an alert within its ticker. Alerts without a
+// signal name fall back to their type.
+func alertSignal(a models.Alert) string {
+	if a.Signal != "" {
+		return a.Signal
+	}
+	return string(a.Type)
+}
+
+func alertStateKey(portfolioName, ticker, signal string) string {
+	return portfolioName + ":" + strings.ToUpper(ticker) + ":" + signal
+}
+
+// loadAlertStates returns the stored alert states for a portfolio, keyed by
+// alertStateKey.
+func (s *Service) loadAlertStates(ctx context.Context, name string) (map[string]*models.AlertState, error) {
+	userID := common.ResolveUserID(ctx)
+	records, err := s.storage.UserDataStore().List(ctx, userID, alertStateSubject)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alert state: %w", err)
+	}
+	states := make(map[string]*models.AlertState)
+	for _, rec := range records {
+		var st models.AlertState
+		if err := json.Unmarshal([]byte(rec.Value), &st); err != nil {
+			s.logger.WithRequestID(ctx).Warn().Err(err).Str("key", rec.Key).Msg("Skipping corrupt alert state")
+			continue
+		}
+		if st.PortfolioName == name {
+			states[alertStateKey(name, st.Ticker, st.Signal)] = &st
+		}
+	}
+	return states, nil
+}
+
+func (s *Service) saveAlertState(ctx context.Context, st *models.AlertState) error {
+	data, err := json.Marshal(st)
+	if err != nil {
+		return fmt.Errorf("failed to marshal alert state: %w", err)
+	}
+	return s.storage.UserDataStore().Put(ctx, &models.UserRecord{
+		UserID:  common.ResolveUserID(ctx),
+		Subject: alertStateSubject,
+		Key:     alertStateKey(st.PortfolioName, st.Ticker, st.Signal),
+		Value:   string(data),
+	})
+}
+
+// reconcileAlerts records this review's alerts against the stored state and
+// returns the ones to report. Acknowledged alerts whose condition still
+// holds are withheld and counted; stored alerts the review no longer raises
+// have cleared and are deleted, so they re-fire unacknowledged if the
+// condition returns. Tickers in skipped were not evaluated this review (no
+// market data, or muted), so their stored alerts are kept as they are. A
+// state is only written when it is new, its content changed, or it was last
+// seen on an earlier day. On storage errors every alert is reported.
+func (s *Service) reconcileAlerts(ctx context.Context, name string, alerts []models.Alert, skipped map[string]bool, now time.Time) ([]models.Alert, int) {
+	logger := s.logger.WithRequestID(ctx)
+	states, err := s.loadAlertStates(ctx, name)
+	if err != nil {
+		logger.Warn().Err(err).Str("portfolio", name).Msg("Alert state unavailable: reporting all alerts")
+		return alerts, 0
+	}
+
+	visible := make([]models.Alert, 0, len(alerts))
+	suppressed := 0
+	seen := make(map[string]bool, len(alerts))
+	for _, a := range alerts {
+		signal := alertSignal(a)
+		key := alertStateKey(name, a.Ticker, signal)
+		st, ok := states[key]
+		raised := !ok
+		if raised {
+			st = &models.AlertState{
+				PortfolioName: name,
+				Ticker:        strings.ToUpper(a.Ticker),
+				Signal:        signal,
+				FirstRaised:   now,
+			}
+			states[key] = st
+		}
+		if !seen[key] {
+			seen[key] = true
+			if raised || alertStateChanged(st, a, now) {
+				st.Type = a.Type
+				st.Severity = a.Severity
+				st.Message = a.Message
+				st.LastSeen = now
+				if err := s.saveAlertState(ctx, st); err != nil {
+					logger.Warn().Err(err).Str("key", key).Msg("Failed to save alert state")
+				}
+			}
+			if raised {
+				s.notifyAlertRaised(ctx, st)
+			}
+		}
+		if st.Acknowledged {
+			suppressed++
+			continue
+		}
+		visible = append(visible, a)
+	}
+
+	userID := common.ResolveUserID(ctx)
+	for key, st := range states {
+		if seen[key] || skipped[st.Ticker] {
+			continue
+		}
+		if err := s.storage.UserDataStore().Delete(ctx, userID, alertStateSubject, key); err != nil {
+			logger.Warn().Err(err).Str("key", key).Msg("Failed to clear alert state")
+			continue
+		}
+		logger.Debug().Str("ticker", st.Ticker).Str("signal", st.Signal).Msg("Alert condition cleared")
+	}
+	return visible, suppressed
+}
+
+// alertStateChanged reports whether a still-firing alert needs its stored
+// state rewritten: its type, severity or message moved, or last_seen falls
+// on an earlier day than now.
+func alertStateChanged(st *models.AlertState, a models.Alert, now time.Time) bool {
+	if st.Type != a.Type || st.Severity != a.Severity || st.Message != a.Message {
+		return true
+	}
+	y1, m1, d1 := st.LastSeen.Date()
+	y2, m2, d2 := now.In(st.LastSeen.Location()).Date()
+	return y1 != y2 || m1 != m2 || d1 != d2
+}
+
+// notifyAlertRaised sends a webhook event for an alert seen for the first
+// time in this episode of its condition.
+func (s *Service) notifyAlertRaised(ctx context.Context, st *models.AlertState) {
+	if s.notifier == nil {
+		return
+	}
+	s.notifier.Notify(ctx, models.NotificationEvent{
+		Type:          models.NotificationAlertRaised,
+		PortfolioName: st.PortfolioName,
+		Ticker:        st.Ticker,
+		Message:       st.Message,
+		OccurredAt:    st.FirstRaised,
+		Data:          *st,
+	})
+}
+
+// ListActiveAlerts returns the alerts raised by the latest review of the
+// portfolio, acknowledged or not, oldest first.
+func (s *Service) ListActiveAlerts(ctx context.Context, name string) ([]models.AlertState, error) {
+	states, err := s.loadAlertStates(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]models.AlertState, 0, len(states))
+	for _, st := range states {
+		out = append(out, *st)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].FirstRaised.Equal(out[j].FirstRaised) {
+			return out[i].FirstRaised.Before(out[j].FirstRaised)
+		}
+		if out[i].Ticker != out[j].Ticker {
+			return out[i].Ticker < out[j].Ticker
+		}
+		return out[i].Signal < out[j].Signal
+	})
+	return out, nil
+}
+
+// AcknowledgeAlert marks an active alert as acknowledged, so reviews stop
+// reporting it until its condition clears and re-triggers. The ticker may
+// carry an exchange suffix; portfolio-level alerts take an empty ticker.
+func (s *Service) AcknowledgeAlert(ctx context.Context, name, ticker, signal string) (*models.AlertState, error) {
+	states, err := s.loadAlertStates(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	st, ok := states[alertStateKey(name, ticker, signal)]
+	if !ok {
+		if base, _, found := strings.Cut(ticker, "."); found {
+			st, ok = states[alertStateKey(name, base, signal)]
+		}
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: %s %s in '%s'", interfaces.ErrAlertNotFound, strings.ToUpper(ticker), signal, name)
+	}
+	if !st.Acknowledged {
+		now := time.Now()
+		st.Acknowledged = true
+		st.AcknowledgedAt = &now
+		if err := s.saveAlertState(ctx, st); err != nil {
+			return nil, fmt.Errorf("failed to save alert state: %w", err)
+		}
+	}
+	return st, nil
+}
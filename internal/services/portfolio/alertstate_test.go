@@ -0,0 +1,196 @@
+package portfolio
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/interfaces"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+func newAlertStateService() *Service {
+	storage := &stubStorageManager{
+		marketStore:   &stubMarketDataStorage{data: map[string]*models.MarketData{}},
+		userDataStore: newMemUserDataStore(),
+	}
+	return NewService(storage, nil, nil, nil, common.NewLogger("error"))
+}
+
+func rsiOverbought() models.Alert {
+	return models.Alert{
+		Type:     models.AlertTypeSignal,
+		Severity: "high",
+		Ticker:   "BHP",
+		Message:  "BHP RSI is overbought at 78.0 (threshold: 70)",
+		Signal:   "rsi_overbought",
+	}
+}
+
+func TestReconcileAlerts_AcknowledgeLifecycle(t *testing.T) {
+	svc := newAlertStateService()
+	ctx := context.Background()
+	day := func(n int) time.Time { return time.Date(2025, 3, n, 10, 0, 0, 0, time.UTC) }
+
+	// Day 1: the alert fires.
+	visible, suppressed := svc.reconcileAlerts(ctx, "SMSF", []models.Alert{rsiOverbought()}, nil, day(1))
+	if len(visible) != 1 || suppressed != 0 {
+		t.Fatalf("day 1: visible=%d suppressed=%d, want 1/0", len(visible), suppressed)
+	}
+
+	// The user acknowledges it (with an exchange suffix on the ticker).
+	st, err := svc.AcknowledgeAlert(ctx, "SMSF", "BHP.AU", "rsi_overbought")
+	if err != nil {
+		t.Fatalf("AcknowledgeAlert: %v", err)
+	}
+	if !st.Acknowledged || st.AcknowledgedAt == nil {
+		t.Errorf("state after acknowledge = %+v", st)
+	}
+
+	// Day 2: the condition still holds, so the alert stays suppressed.
+	visible, suppressed = svc.reconcileAlerts(ctx, "SMSF", []models.Alert{rsiOverbought()}, nil, day(2))
+	if len(visible) != 0 || suppressed != 1 {
+		t.Fatalf("day 2: visible=%d suppressed=%d, want 0/1", len(visible), suppressed)
+	}
+	active, _ := svc.ListActiveAlerts(ctx, "SMSF")
+	if len(active) != 1 || !active[0].FirstRaised.Equal(day(1)) || !active[0].LastSeen.Equal(day(2)) {
+		t.Errorf("day 2 active alerts = %+v, want first raised day 1, last seen day 2", active)
+	}
+
+	// Day 3: the condition clears.
+	visible, _ = svc.reconcileAlerts(ctx, "SMSF", nil, nil, day(3))
+	if len(visible) != 0 {
+		t.Fatalf("day 3: visible=%d, want 0", len(visible))
+	}
+	if active, _ := svc.ListActiveAlerts(ctx, "SMSF"); len(active) != 0 {
+		t.Errorf("day 3: expected no active alerts, got %+v", active)
+	}
+
+	// Day 4: the condition returns and the alert fires again.
+	visible, suppressed = svc.reconcileAlerts(ctx, "SMSF", []models.Alert{rsiOverbought()}, nil, day(4))
+	if len(visible) != 1 || suppressed != 0 {
+		t.Fatalf("day 4: visible=%d suppressed=%d, want 1/0", len(visible), suppressed)
+	}
+	active, _ = svc.ListActiveAlerts(ctx, "SMSF")
+	if len(active) != 1 || active[0].Acknowledged || !active[0].FirstRaised.Equal(day(4)) {
+		t.Errorf("day 4 active alerts = %+v, want a fresh unacknowledged alert", active)
+	}
+}
+
+func TestReconcileAlerts_AcknowledgeIsPerSignal(t *testing.T) {
+	svc := newAlertStateService()
+	ctx := context.Background()
+	volume := models.Alert{Type: models.AlertTypeVolume, Severity: "medium", Ticker: "BHP", Signal: "volume_spike"}
+
+	svc.reconcileAlerts(ctx, "SMSF", []models.Alert{rsiOverbought(), volume}, nil, time.Now())
+	if _, err := svc.AcknowledgeAlert(ctx, "SMSF", "BHP", "rsi_overbought"); err != nil {
+		t.Fatal(err)
+	}
+
+	visible, suppressed := svc.reconcileAlerts(ctx, "SMSF", []models.Alert{rsiOverbought(), volume}, nil, time.Now())
+	if suppressed != 1 || len(visible) != 1 || visible[0].Signal != "volume_spike" {
+		t.Errorf("visible=%+v suppressed=%d, want only volume_spike", visible, suppressed)
+	}
+}
+
+func TestReconcileAlerts_KeepsStateForUnevaluatedHoldings(t *testing.T) {
+	svc := newAlertStateService()
+	ctx := context.Background()
+
+	svc.reconcileAlerts(ctx, "SMSF", []models.Alert{rsiOverbought()}, nil, time.Now())
+	if _, err := svc.AcknowledgeAlert(ctx, "SMSF", "BHP", "rsi_overbought"); err != nil {
+		t.Fatal(err)
+	}
+
+	// BHP had no market data this review: its acknowledged alert survives.
+	svc.reconcileAlerts(ctx, "SMSF", nil, map[string]bool{"BHP": true}, time.Now())
+	active, _ := svc.ListActiveAlerts(ctx, "SMSF")
+	if len(active) != 1 || !active[0].Acknowledged {
+		t.Fatalf("active alerts = %+v, want the acknowledged BHP alert kept", active)
+	}
+
+	_, suppressed := svc.reconcileAlerts(ctx, "SMSF", []models.Alert{rsiOverbought()}, nil, time.Now())
+	if suppressed != 1 {
+		t.Errorf("suppressed = %d, want 1 once BHP is evaluated again", suppressed)
+	}
+}
+
+func TestReconcileAlerts_WritesOnlyOnChange(t *testing.T) {
+	svc := newAlertStateService()
+	ctx := context.Background()
+	now := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	version := func() int {
+		rec, err := svc.storage.UserDataStore().Get(ctx, common.ResolveUserID(ctx), alertStateSubject, alertStateKey("SMSF", "BHP", "rsi_overbought"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return rec.Version
+	}
+
+	svc.reconcileAlerts(ctx, "SMSF", []models.Alert{rsiOverbought()}, nil, now)
+	svc.reconcileAlerts(ctx, "SMSF", []models.Alert{rsiOverbought()}, nil, now.Add(time.Hour))
+	if v := version(); v != 1 {
+		t.Errorf("version after an unchanged same-day review = %d, want 1", v)
+	}
+
+	changed := rsiOverbought()
+	changed.Message = "BHP RSI is overbought at 81.0 (threshold: 70)"
+	svc.reconcileAlerts(ctx, "SMSF", []models.Alert{changed}, nil, now.Add(2*time.Hour))
+	if v := version(); v != 2 {
+		t.Errorf("version after a message change = %d, want 2", v)
+	}
+
+	svc.reconcileAlerts(ctx, "SMSF", []models.Alert{changed}, nil, now.Add(24*time.Hour))
+	if v := version(); v != 3 {
+		t.Errorf("version after the next day's review = %d, want 3", v)
+	}
+}
+
+func TestAcknowledgeAlert_NotActive(t *testing.T) {
+	svc := newAlertStateService()
+
+	_, err := svc.AcknowledgeAlert(context.Background(), "SMSF", "BHP.AU", "rsi_overbought")
+	if !errors.Is(err, interfaces.ErrAlertNotFound) {
+		t.Errorf("error = %v, want ErrAlertNotFound", err)
+	}
+}
+
+// recordingNotifier captures events passed to Notify.
+type recordingNotifier struct {
+	events []models.NotificationEvent
+}
+
+func (n *recordingNotifier) Notify(_ context.Context, event models.NotificationEvent) {
+	n.events = append(n.events, event)
+}
+
+func (n *recordingNotifier) SendTestEvent(context.Context) ([]models.WebhookDelivery, error) {
+	return nil, nil
+}
+
+func TestReconcileAlerts_NotifiesOnlyNewlyRaised(t *testing.T) {
+	svc := newAlertStateService()
+	notifier := &recordingNotifier{}
+	svc.SetNotificationService(notifier)
+	ctx := context.Background()
+	now := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	svc.reconcileAlerts(ctx, "SMSF", []models.Alert{rsiOverbought()}, nil, now)
+	svc.reconcileAlerts(ctx, "SMSF", []models.Alert{rsiOverbought()}, nil, now.Add(24*time.Hour))
+	if len(notifier.events) != 1 {
+		t.Fatalf("got %d notifications for a persisting alert, want 1", len(notifier.events))
+	}
+	ev := notifier.events[0]
+	if ev.Type != models.NotificationAlertRaised || ev.Ticker != "BHP" || ev.PortfolioName != "SMSF" {
+		t.Errorf("notification = %+v", ev)
+	}
+
+	// The condition clears, then returns: a fresh episode notifies again.
+	svc.reconcileAlerts(ctx, "SMSF", nil, nil, now.Add(48*time.Hour))
+	svc.reconcileAlerts(ctx, "SMSF", []models.Alert{rsiOverbought()}, nil, now.Add(72*time.Hour))
+	if len(notifier.events) != 2 {
+		t.Errorf("got %d notifications after the alert returned, want 2", len(notifier.events))
+	}
+}
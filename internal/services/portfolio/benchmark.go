@@ -0,0 +1,131 @@
+package portfolio
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/interfaces"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// benchmarkComparison holds the portfolio and benchmark returns over a
+// common window.
+type benchmarkComparison struct {
+	Start           time.Time
+	End             time.Time
+	BenchmarkReturn float64 // percentage
+	PortfolioReturn float64 // percentage, net of capital flows
+}
+
+// loadBenchmarkBars returns EOD bars for the benchmark ticker, newest first.
+// Stored market data is preferred; EODHD is queried only when nothing is cached.
+func (s *Service) loadBenchmarkBars(ctx context.Context, ticker string, from, to time.Time) []models.EODBar {
+	if md, err := s.storage.MarketDataStorage().GetMarketData(ctx, ticker); err == nil && md != nil && len(md.EOD) > 0 {
+		return md.EOD
+	}
+	if s.eodhd == nil {
+		return nil
+	}
+	resp, err := s.eodhd.GetEOD(ctx, ticker, interfaces.WithDateRange(from, to))
+	if err != nil || resp == nil {
+		s.logger.WithRequestID(ctx).Warn().Err(err).Str("benchmark", ticker).Msg("Failed to fetch benchmark EOD data")
+		return nil
+	}
+	bars := append([]models.EODBar(nil), resp.Data...)
+	sort.Slice(bars, func(i, j int) bool { return bars[i].Date.After(bars[j].Date) })
+	return bars
+}
+
+// compareToBenchmark computes the portfolio and benchmark returns over the
+// window both series cover. The window ends at the last benchmark bar on or
+// before lastSynced and starts at the later of the first growth point and the
+// oldest benchmark bar. The portfolio return strips contributions made inside
+// the window so deposits are not counted as performance.
+// Returns false when either series cannot price the window.
+func compareToBenchmark(bars []models.EODBar, growth []models.GrowthDataPoint, lastSynced time.Time) (benchmarkComparison, bool) {
+	if len(bars) == 0 || len(growth) == 0 {
+		return benchmarkComparison{}, false
+	}
+	if lastSynced.IsZero() {
+		lastSynced = time.Now()
+	}
+
+	endClose, endDate, ok := findClosingPriceAsOf(bars, lastSynced)
+	if !ok {
+		return benchmarkComparison{}, false
+	}
+
+	start := growth[0].Date
+	if oldest := bars[len(bars)-1].Date; oldest.After(start) {
+		start = oldest
+	}
+	startClose, startDate, ok := findClosingPriceAsOf(bars, start)
+	if !ok || startClose <= 0 || !startDate.Before(endDate) {
+		return benchmarkComparison{}, false
+	}
+
+	startPoint, ok := growthPointAsOf(growth, startDate)
+	if !ok {
+		return benchmarkComparison{}, false
+	}
+	endPoint, ok := growthPointAsOf(growth, endDate)
+	if !ok || startPoint.PortfolioValue <= 0 {
+		return benchmarkComparison{}, false
+	}
+
+	flows := endPoint.CapitalContributionsNet - startPoint.CapitalContributionsNet
+	gain := endPoint.PortfolioValue - startPoint.PortfolioValue - flows
+
+	return benchmarkComparison{
+		Start:           startDate,
+		End:             endDate,
+		BenchmarkReturn: (endClose/startClose - 1) * 100,
+		PortfolioReturn: gain / startPoint.PortfolioValue * 100,
+	}, true
+}
+
+// growthPointAsOf returns the last growth point on or before the given date.
+// Growth points are ordered oldest first.
+func growthPointAsOf(growth []models.GrowthDataPoint, asOf time.Time) (models.GrowthDataPoint, bool) {
+	target := asOf.Truncate(24 * time.Hour)
+	var found models.GrowthDataPoint
+	ok := false
+	for _, p := range growth {
+		if p.Date.Truncate(24 * time.Hour).After(target) {
+			break
+		}
+		found, ok = p, true
+	}
+	return found, ok
+}
+
+// applyBenchmark populates the review's benchmark fields. Missing market or
+// growth data leaves the fields zero rather than failing the review.
+func (s *Service) applyBenchmark(ctx context.Context, review *models.PortfolioReview, portfolio *models.Portfolio, growth []models.GrowthDataPoint, ticker string) {
+	ticker = strings.ToUpper(strings.TrimSpace(ticker))
+	if ticker == "" {
+		return
+	}
+	review.BenchmarkTicker = ticker
+
+	if len(growth) == 0 {
+		s.logger.WithRequestID(ctx).Warn().Str("benchmark", ticker).Msg("No growth data for benchmark comparison")
+		return
+	}
+
+	end := portfolio.LastSynced
+	if end.IsZero() {
+		end = time.Now()
+	}
+	bars := s.loadBenchmarkBars(ctx, ticker, growth[0].Date, end)
+
+	cmp, ok := compareToBenchmark(bars, growth, end)
+	if !ok {
+		s.logger.WithRequestID(ctx).Warn().Str("benchmark", ticker).Msg("Insufficient data for benchmark comparison")
+		return
+	}
+	review.BenchmarkReturn = cmp.BenchmarkReturn
+	review.ExcessReturn = cmp.PortfolioReturn - cmp.BenchmarkReturn
+}
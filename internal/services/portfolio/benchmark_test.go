@@ -0,0 +1,54 @@
+package portfolio
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+func TestCompareToBenchmark_CommonWindow(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 3, d, 0, 0, 0, 0, time.UTC) }
+
+	// Benchmark bars, newest first. Data starts on the 3rd, after the portfolio.
+	bars := []models.EODBar{
+		{Date: day(12), Close: 130}, // after LastSynced: ignored
+		{Date: day(10), Close: 121},
+		{Date: day(5), Close: 115},
+		{Date: day(3), Close: 110},
+	}
+	// Portfolio grows 1000 -> 1320, with a 100 deposit on the 6th.
+	growth := []models.GrowthDataPoint{
+		{Date: day(1), PortfolioValue: 900, CapitalContributionsNet: 900},
+		{Date: day(3), PortfolioValue: 1000, CapitalContributionsNet: 900},
+		{Date: day(6), PortfolioValue: 1150, CapitalContributionsNet: 1000},
+		{Date: day(10), PortfolioValue: 1320, CapitalContributionsNet: 1000},
+	}
+
+	cmp, ok := compareToBenchmark(bars, growth, day(11))
+	if !ok {
+		t.Fatal("expected a comparison")
+	}
+	if !cmp.Start.Equal(day(3)) || !cmp.End.Equal(day(10)) {
+		t.Errorf("window = %s..%s, want 2025-03-03..2025-03-10", cmp.Start.Format("2006-01-02"), cmp.End.Format("2006-01-02"))
+	}
+	if !approxEqual(cmp.BenchmarkReturn, 10, 0.001) {
+		t.Errorf("BenchmarkReturn = %.3f, want 10", cmp.BenchmarkReturn)
+	}
+	// (1320 - 1000 - 100 deposit) / 1000 = 22%
+	if !approxEqual(cmp.PortfolioReturn, 22, 0.001) {
+		t.Errorf("PortfolioReturn = %.3f, want 22", cmp.PortfolioReturn)
+	}
+}
+
+func TestCompareToBenchmark_NoData(t *testing.T) {
+	growth := []models.GrowthDataPoint{{Date: time.Now(), PortfolioValue: 1000}}
+	if _, ok := compareToBenchmark(nil, growth, time.Now()); ok {
+		t.Error("expected no comparison without benchmark bars")
+	}
+
+	bars := []models.EODBar{{Date: time.Now(), Close: 100}}
+	if _, ok := compareToBenchmark(bars, nil, time.Now()); ok {
+		t.Error("expected no comparison without portfolio growth")
+	}
+}
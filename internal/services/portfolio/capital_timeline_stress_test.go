@@ -679,6 +679,14 @@ func (m *mockCashFlowService) SetTransactions(_ context.Context, _ string, _ []m
 func (m *mockCashFlowService) ClearLedger(_ context.Context, _ string) (*models.CashFlowLedger, error) {
 	return nil, nil
 }
+
+func (m *mockCashFlowService) SetBrokerBalance(_ context.Context, _ string, _ models.BrokerBalance) (*models.CashFlowLedger, error) {
+	return nil, nil
+}
+
+func (m *mockCashFlowService) Reconcile(_ context.Context, _ string) (*models.CashReconciliation, error) {
+	return nil, nil
+}
 func (m *mockCashFlowService) UpdateAccount(_ context.Context, _ string, _ string, _ models.CashAccountUpdate) (*models.CashFlowLedger, error) {
 	return nil, nil
 }
@@ -859,6 +859,14 @@ func (s *stubCashFlowService) ClearLedger(_ context.Context, _ string) (*models.
 	return s.ledger, nil
 }
 
+func (s *stubCashFlowService) SetBrokerBalance(_ context.Context, _ string, _ models.BrokerBalance) (*models.CashFlowLedger, error) {
+	return nil, nil
+}
+
+func (s *stubCashFlowService) Reconcile(_ context.Context, _ string) (*models.CashReconciliation, error) {
+	return nil, nil
+}
+
 func (s *stubCashFlowService) UpdateAccount(_ context.Context, _ string, _ string, _ models.CashAccountUpdate) (*models.CashFlowLedger, error) {
 	return s.ledger, nil
 }
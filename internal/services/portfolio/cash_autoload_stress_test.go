@@ -752,6 +752,14 @@ func (c *countingCashFlowService) SetTransactions(_ context.Context, _ string, _
 func (c *countingCashFlowService) ClearLedger(_ context.Context, _ string) (*models.CashFlowLedger, error) {
 	return nil, nil
 }
+
+func (c *countingCashFlowService) SetBrokerBalance(_ context.Context, _ string, _ models.BrokerBalance) (*models.CashFlowLedger, error) {
+	return nil, nil
+}
+
+func (c *countingCashFlowService) Reconcile(_ context.Context, _ string) (*models.CashReconciliation, error) {
+	return nil, nil
+}
 func (c *countingCashFlowService) UpdateAccount(_ context.Context, _ string, _ string, _ models.CashAccountUpdate) (*models.CashFlowLedger, error) {
 	return nil, nil
 }
@@ -0,0 +1,48 @@
+package portfolio
+
+import (
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// computeCashDrag estimates how many percentage points of return idle cash
+// cost over the value series. The actual return is flow-adjusted from the
+// first to the last point. Cash weight is the average share of portfolio value
+// held as uninvested capital (the ledger-derived CapitalAvailable). Had that
+// cash earned the invested return, the portfolio would have returned
+// actual / (1 - cash weight); the drag is the difference.
+// Returns false when the series cannot support the calculation.
+func computeCashDrag(growth []models.GrowthDataPoint) (float64, bool) {
+	if len(growth) < 2 {
+		return 0, false
+	}
+	start, end := growth[0], growth[len(growth)-1]
+	if start.PortfolioValue <= 0 {
+		return 0, false
+	}
+
+	var weightSum float64
+	var n int
+	for _, p := range growth {
+		if p.PortfolioValue <= 0 {
+			continue
+		}
+		cash := p.CapitalAvailable
+		if cash < 0 {
+			cash = 0
+		}
+		weightSum += cash / p.PortfolioValue
+		n++
+	}
+	if n == 0 {
+		return 0, false
+	}
+	cashWeight := weightSum / float64(n)
+	if cashWeight >= 1 {
+		return 0, false
+	}
+
+	flows := end.CapitalContributionsNet - start.CapitalContributionsNet
+	actual := (end.PortfolioValue - start.PortfolioValue - flows) / start.PortfolioValue * 100
+	invested := actual / (1 - cashWeight)
+	return invested - actual, true
+}
@@ -0,0 +1,41 @@
+package portfolio
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+func TestComputeCashDrag_KnownCashAndReturn(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 6, d, 0, 0, 0, 0, time.UTC) }
+
+	// 20% of value held as cash throughout; the portfolio returns 8% after
+	// stripping a 1000 deposit. Fully invested it would have returned 10%.
+	growth := []models.GrowthDataPoint{
+		{Date: day(1), PortfolioValue: 10000, CapitalAvailable: 2000, CapitalContributionsNet: 10000},
+		{Date: day(15), PortfolioValue: 11500, CapitalAvailable: 2300, CapitalContributionsNet: 11000},
+		{Date: day(30), PortfolioValue: 11800, CapitalAvailable: 2360, CapitalContributionsNet: 11000},
+	}
+
+	drag, ok := computeCashDrag(growth)
+	if !ok {
+		t.Fatal("expected a cash drag")
+	}
+	if !approxEqual(drag, 2, 0.001) {
+		t.Errorf("CashDragPct = %.4f, want 2", drag)
+	}
+}
+
+func TestComputeCashDrag_InsufficientData(t *testing.T) {
+	if _, ok := computeCashDrag(nil); ok {
+		t.Error("expected no drag without a value series")
+	}
+	allCash := []models.GrowthDataPoint{
+		{Date: time.Now().AddDate(0, 0, -1), PortfolioValue: 1000, CapitalAvailable: 1000},
+		{Date: time.Now(), PortfolioValue: 1000, CapitalAvailable: 1000},
+	}
+	if _, ok := computeCashDrag(allCash); ok {
+		t.Error("expected no drag for an all-cash portfolio")
+	}
+}
@@ -0,0 +1,158 @@
+package portfolio
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// defaultMinHoldDays is the holding period after which the CGT discount applies.
+const defaultMinHoldDays = 365
+
+// openLot is a parcel of units still held, with its acquisition date.
+type openLot struct {
+	date  time.Time
+	units float64
+}
+
+// openLotsFIFO replays trades oldest-first and returns the lots still held,
+// with sells consuming the oldest lots first.
+func openLotsFIFO(trades []*models.NavexaTrade) []openLot {
+	sorted := make([]*models.NavexaTrade, len(trades))
+	copy(sorted, trades)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Date < sorted[j].Date
+	})
+
+	var lots []openLot
+	for _, t := range sorted {
+		switch strings.ToLower(t.Type) {
+		case "buy", "opening balance":
+			if t.Units > 0 {
+				lots = append(lots, openLot{date: parseTradeDate(t.Date), units: t.Units})
+			}
+		case "sell":
+			remaining := t.Units
+			for remaining > 1e-9 && len(lots) > 0 {
+				take := math.Min(remaining, lots[0].units)
+				lots[0].units -= take
+				remaining -= take
+				if lots[0].units < 1e-9 {
+					lots = lots[1:]
+				}
+			}
+		}
+	}
+	return lots
+}
+
+// checkShortHoldSale returns a warning when selling units (FIFO) on saleDate
+// would dispose of any lot held for less than minHoldDays. Returns nil when
+// every disposed unit already qualifies for the discount.
+func checkShortHoldSale(ticker string, trades []*models.NavexaTrade, units float64, saleDate time.Time, minHoldDays int) *models.CGTShortHoldWarning {
+	if units <= 0 {
+		return nil
+	}
+
+	var shortUnits, soldUnits float64
+	var latestAcquired time.Time
+	remaining := units
+	for _, lot := range openLotsFIFO(trades) {
+		if remaining <= 1e-9 {
+			break
+		}
+		take := math.Min(remaining, lot.units)
+		remaining -= take
+		soldUnits += take
+		if lot.date.IsZero() {
+			continue
+		}
+		if saleDate.Before(lot.date.AddDate(0, 0, minHoldDays)) {
+			shortUnits += take
+			if lot.date.After(latestAcquired) {
+				latestAcquired = lot.date
+			}
+		}
+	}
+	if shortUnits == 0 {
+		return nil
+	}
+
+	eligible := latestAcquired.AddDate(0, 0, minHoldDays)
+	return &models.CGTShortHoldWarning{
+		Ticker:               ticker,
+		UnitsSold:            soldUnits,
+		ShortHeldUnits:       shortUnits,
+		AcquiredDate:         latestAcquired,
+		SaleDate:             saleDate,
+		DiscountEligibleDate: eligible,
+		DaysShort:            int(math.Ceil(eligible.Sub(saleDate).Hours() / 24)),
+		MinHoldDays:          minHoldDays,
+	}
+}
+
+// planShortHoldWarnings checks active SELL plan items against held lots.
+// A SELL item disposes of target_value worth of units at the current price,
+// or the whole position when no target is set; it is assumed to execute on
+// its deadline, or now when it has none.
+func planShortHoldWarnings(plan *models.PortfolioPlan, holdings []models.Holding, now time.Time, minHoldDays int) []models.CGTShortHoldWarning {
+	if plan == nil {
+		return nil
+	}
+
+	held := make(map[string]*models.Holding, len(holdings))
+	for i := range holdings {
+		h := &holdings[i]
+		if h.Units <= 0 {
+			continue
+		}
+		held[strings.ToUpper(h.Ticker)] = h
+		held[strings.ToUpper(h.EODHDTicker())] = h
+	}
+
+	var warnings []models.CGTShortHoldWarning
+	for _, item := range plan.Items {
+		if item.Action != models.RuleActionSell || item.Ticker == "" {
+			continue
+		}
+		if item.Status != models.PlanItemStatusPending && item.Status != models.PlanItemStatusTriggered {
+			continue
+		}
+		h := held[strings.ToUpper(item.Ticker)]
+		if h == nil {
+			continue
+		}
+
+		units := h.Units
+		if item.TargetValue > 0 && h.CurrentPrice > 0 {
+			units = math.Min(item.TargetValue/h.CurrentPrice, h.Units)
+		}
+		saleDate := now
+		if item.Deadline != nil && item.Deadline.After(now) {
+			saleDate = *item.Deadline
+		}
+
+		if w := checkShortHoldSale(item.Ticker, h.Trades, units, saleDate, minHoldDays); w != nil {
+			w.PlanItemID = item.ID
+			warnings = append(warnings, *w)
+		}
+	}
+	return warnings
+}
+
+// cgtShortHoldAlert converts a short-hold warning into a review alert.
+func cgtShortHoldAlert(w models.CGTShortHoldWarning) models.Alert {
+	return models.Alert{
+		Type:     models.AlertTypeRisk,
+		Severity: "medium",
+		Ticker:   w.Ticker,
+		Message: fmt.Sprintf("Selling %.0f units of %s on %s disposes of %.0f units held under %d days — CGT discount available from %s (%d days)",
+			w.UnitsSold, w.Ticker, w.SaleDate.Format("2006-01-02"), w.ShortHeldUnits, w.MinHoldDays,
+			w.DiscountEligibleDate.Format("2006-01-02"), w.DaysShort),
+		Signal: "cgt_short_hold",
+	}
+}
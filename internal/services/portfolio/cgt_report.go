@@ -0,0 +1,126 @@
+package portfolio
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// CGT discount rates by account type: one third for a complying super fund,
+// one half for an individual.
+const (
+	cgtDiscountSMSF       = 1.0 / 3.0
+	cgtDiscountIndividual = 0.5
+)
+
+// CalculateCGT builds a capital gains report for the financial year (e.g.
+// "2023-2024"). Trades are replayed per holding using the strategy's cost
+// basis method (FIFO when the strategy uses average cost, since a disposal
+// must be paired with dated lots) and each disposal in the year is classed
+// as discountable when the lot was held at least minHoldDays.
+func (s *Service) CalculateCGT(ctx context.Context, portfolioName string, financialYear string) (*models.CGTReport, error) {
+	start, end, err := common.ParseFinancialYear(financialYear)
+	if err != nil {
+		return nil, err
+	}
+
+	portfolio, err := s.getPortfolioRecord(ctx, portfolioName)
+	if err != nil {
+		return nil, err
+	}
+
+	method := s.costBasisMethod(ctx, portfolioName)
+	if method != models.CostBasisLIFO {
+		method = models.CostBasisFIFO
+	}
+	discountRate := s.cgtDiscountRate(ctx, portfolioName)
+
+	report := &models.CGTReport{
+		PortfolioName:   portfolioName,
+		FinancialYear:   financialYear,
+		PeriodStart:     start,
+		PeriodEnd:       end.AddDate(0, 0, -1),
+		CostBasisMethod: method,
+		DiscountRate:    discountRate,
+		MinHoldDays:     s.minHoldDays,
+		Disposals:       []models.CGTDisposal{},
+	}
+
+	// Holdings that share a ticker carry the same merged trade list, so
+	// replay each ticker once.
+	seen := make(map[string]bool)
+	for _, h := range portfolio.Holdings {
+		ticker := h.EODHDTicker()
+		if seen[ticker] || len(h.Trades) == 0 {
+			continue
+		}
+		seen[ticker] = true
+
+		dated := make([]*models.NavexaTrade, 0, len(h.Trades))
+		for _, t := range h.Trades {
+			if parseTradeDate(t.Date).IsZero() {
+				report.Warnings = append(report.Warnings,
+					fmt.Sprintf("%s: %s trade of %.2f units has no date and was excluded", ticker, strings.ToLower(t.Type), t.Units))
+				continue
+			}
+			dated = append(dated, t)
+		}
+
+		for _, d := range matchLots(dated, method == models.CostBasisLIFO).disposals {
+			if d.disposed.Before(start) || !d.disposed.Before(end) {
+				continue
+			}
+			report.Disposals = append(report.Disposals, cgtDisposal(ticker, d, s.minHoldDays))
+		}
+	}
+
+	sort.SliceStable(report.Disposals, func(i, j int) bool {
+		return report.Disposals[i].DisposalDate.Before(report.Disposals[j].DisposalDate)
+	})
+	summariseCGT(report)
+	return report, nil
+}
+
+// cgtDisposal classifies a matched lot disposal.
+func cgtDisposal(ticker string, d lotDisposal, minHoldDays int) models.CGTDisposal {
+	gain := d.proceeds - d.costBase
+	return models.CGTDisposal{
+		Ticker:       ticker,
+		AcquiredDate: d.acquired,
+		DisposalDate: d.disposed,
+		Units:        d.units,
+		Proceeds:     d.proceeds,
+		CostBase:     d.costBase,
+		Gain:         gain,
+		HeldDays:     int(d.disposed.Sub(d.acquired).Hours() / 24),
+		Discountable: gain > 0 && !d.disposed.Before(d.acquired.AddDate(0, 0, minHoldDays)),
+	}
+}
+
+// summariseCGT totals disposals and applies losses before the discount:
+// losses offset non-discountable gains first, then discountable gains,
+// and only the remaining discountable gain is reduced by the discount rate.
+func summariseCGT(report *models.CGTReport) {
+	for _, d := range report.Disposals {
+		switch {
+		case d.Gain < 0:
+			report.CapitalLosses += -d.Gain
+		case d.Discountable:
+			report.DiscountableGains += d.Gain
+		default:
+			report.NonDiscountableGains += d.Gain
+		}
+	}
+
+	losses := report.CapitalLosses
+	nonDiscountable := report.NonDiscountableGains - math.Min(losses, report.NonDiscountableGains)
+	losses -= report.NonDiscountableGains - nonDiscountable
+	discountable := report.DiscountableGains - math.Min(losses, report.DiscountableGains)
+
+	report.NetCapitalGain = nonDiscountable + discountable*(1-report.DiscountRate)
+}
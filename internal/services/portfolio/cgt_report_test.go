@@ -0,0 +1,98 @@
+package portfolio
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+func TestCalculateCGT_SplitsDiscountableGains(t *testing.T) {
+	storage := &stubStorageManager{
+		marketStore:   &stubMarketDataStorage{data: map[string]*models.MarketData{}},
+		userDataStore: newMemUserDataStore(),
+	}
+	svc := NewService(storage, nil, nil, nil, common.NewLogger("error"))
+	ctx := context.Background()
+
+	bhp := []*models.NavexaTrade{
+		{Type: "buy", Date: "2022-01-10", Units: 100, Price: 10},
+		{Type: "buy", Date: "2023-09-01", Units: 100, Price: 20},
+		{Type: "cost base increase", Date: "2023-10-01", Value: 50}, // +0.25/unit on both lots
+		{Type: "sell", Date: "2024-03-01", Units: 150, Price: 30},   // 100 long-held + 50 short-held
+		{Type: "sell", Date: "2024-08-01", Units: 10, Price: 30},    // next financial year
+	}
+	wes := []*models.NavexaTrade{
+		{Type: "buy", Date: "2023-08-01", Units: 10, Price: 50},
+		{Type: "sell", Date: "2023-12-01", Units: 10, Price: 40},
+	}
+	cba := []*models.NavexaTrade{
+		{Type: "buy", Units: 10, Price: 100}, // no date
+	}
+	if err := svc.savePortfolioRecord(ctx, &models.Portfolio{
+		Name: "SMSF",
+		Holdings: []models.Holding{
+			{Ticker: "BHP", Exchange: "AU", Units: 40, Trades: bhp},
+			{Ticker: "BHP", Exchange: "AU", Units: 0, Trades: bhp}, // second account, same merged trades
+			{Ticker: "WES", Exchange: "AU", Units: 0, Trades: wes},
+			{Ticker: "CBA", Exchange: "AU", Units: 10, Trades: cba},
+		},
+	}); err != nil {
+		t.Fatalf("seed portfolio: %v", err)
+	}
+
+	report, err := svc.CalculateCGT(ctx, "SMSF", "2023-2024")
+	if err != nil {
+		t.Fatalf("CalculateCGT failed: %v", err)
+	}
+
+	if len(report.Disposals) != 3 {
+		t.Fatalf("expected 3 disposals (2 BHP lots + WES), got %d: %+v", len(report.Disposals), report.Disposals)
+	}
+	wantPeriodEnd := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+	if !report.PeriodEnd.Equal(wantPeriodEnd) {
+		t.Errorf("PeriodEnd = %s, want 2024-06-30", report.PeriodEnd.Format("2006-01-02"))
+	}
+
+	// WES loss sorts first (Dec 2023), then the BHP lots (Mar 2024)
+	if d := report.Disposals[0]; d.Ticker != "WES.AU" || !approxEqual(d.Gain, -100, 0.01) || d.Discountable {
+		t.Errorf("WES disposal = %+v, want loss of 100", d)
+	}
+	long, short := report.Disposals[1], report.Disposals[2]
+	if !long.Discountable || long.Units != 100 || !approxEqual(long.CostBase, 1025, 0.01) || !approxEqual(long.Gain, 1975, 0.01) {
+		t.Errorf("long-held lot = %+v, want 100 units, cost 1025, gain 1975, discountable", long)
+	}
+	if !long.AcquiredDate.Equal(time.Date(2022, 1, 10, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("long-held AcquiredDate = %s", long.AcquiredDate.Format("2006-01-02"))
+	}
+	if short.Discountable || short.Units != 50 || !approxEqual(short.CostBase, 1012.5, 0.01) || !approxEqual(short.Proceeds, 1500, 0.01) {
+		t.Errorf("short-held lot = %+v, want 50 units, cost 1012.50, proceeds 1500, not discountable", short)
+	}
+
+	if !approxEqual(report.DiscountableGains, 1975, 0.01) || !approxEqual(report.NonDiscountableGains, 487.5, 0.01) || !approxEqual(report.CapitalLosses, 100, 0.01) {
+		t.Errorf("totals = %.2f/%.2f/%.2f, want 1975/487.50/100",
+			report.DiscountableGains, report.NonDiscountableGains, report.CapitalLosses)
+	}
+	// (487.50 - 100) + 1975 * 2/3
+	if !approxEqual(report.NetCapitalGain, 387.5+1975*2.0/3.0, 0.01) {
+		t.Errorf("NetCapitalGain = %.2f, want %.2f", report.NetCapitalGain, 387.5+1975*2.0/3.0)
+	}
+
+	if len(report.Warnings) != 1 {
+		t.Errorf("expected 1 warning for the undated CBA trade, got %v", report.Warnings)
+	}
+}
+
+func TestCalculateCGT_InvalidFinancialYear(t *testing.T) {
+	storage := &stubStorageManager{
+		marketStore:   &stubMarketDataStorage{data: map[string]*models.MarketData{}},
+		userDataStore: newMemUserDataStore(),
+	}
+	svc := NewService(storage, nil, nil, nil, common.NewLogger("error"))
+
+	if _, err := svc.CalculateCGT(context.Background(), "SMSF", "2024"); err == nil {
+		t.Error("expected error for invalid financial year")
+	}
+}
@@ -0,0 +1,95 @@
+package portfolio
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+func TestCheckShortHoldSale_TwentyDaysShort(t *testing.T) {
+	saleDate := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+	bought := saleDate.AddDate(0, 0, -345) // 20 days short of 365
+	trades := []*models.NavexaTrade{
+		{Type: "buy", Date: bought.Format("2006-01-02"), Units: 100, Price: 10},
+	}
+
+	w := checkShortHoldSale("BHP.AU", trades, 100, saleDate, 365)
+	if w == nil {
+		t.Fatal("expected cgt_short_hold warning")
+	}
+	wantEligible := saleDate.AddDate(0, 0, 20)
+	if !w.DiscountEligibleDate.Equal(wantEligible) {
+		t.Errorf("DiscountEligibleDate = %s, want %s", w.DiscountEligibleDate.Format("2006-01-02"), wantEligible.Format("2006-01-02"))
+	}
+	if w.DaysShort != 20 {
+		t.Errorf("DaysShort = %d, want 20", w.DaysShort)
+	}
+	if w.ShortHeldUnits != 100 || w.UnitsSold != 100 {
+		t.Errorf("ShortHeldUnits/UnitsSold = %.0f/%.0f, want 100/100", w.ShortHeldUnits, w.UnitsSold)
+	}
+	if !w.AcquiredDate.Equal(bought) {
+		t.Errorf("AcquiredDate = %s, want %s", w.AcquiredDate.Format("2006-01-02"), bought.Format("2006-01-02"))
+	}
+
+	alert := cgtShortHoldAlert(*w)
+	if alert.Signal != "cgt_short_hold" {
+		t.Errorf("alert Signal = %q, want cgt_short_hold", alert.Signal)
+	}
+}
+
+func TestCheckShortHoldSale_FIFOOldLotsQualify(t *testing.T) {
+	saleDate := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+	trades := []*models.NavexaTrade{
+		{Type: "buy", Date: "2023-01-10", Units: 100, Price: 10},
+		{Type: "buy", Date: saleDate.AddDate(0, 0, -30).Format("2006-01-02"), Units: 50, Price: 12},
+		{Type: "sell", Date: "2024-01-10", Units: 40, Price: 11},
+	}
+
+	// Open lots: 60 (2023) then 50 (recent). Selling 60 only touches the old lot.
+	if w := checkShortHoldSale("BHP.AU", trades, 60, saleDate, 365); w != nil {
+		t.Errorf("expected no warning when only long-held units are sold, got %+v", w)
+	}
+
+	// Selling 80 reaches 20 units of the recent lot
+	w := checkShortHoldSale("BHP.AU", trades, 80, saleDate, 365)
+	if w == nil {
+		t.Fatal("expected warning when sale reaches the recent lot")
+	}
+	if w.ShortHeldUnits != 20 {
+		t.Errorf("ShortHeldUnits = %.0f, want 20", w.ShortHeldUnits)
+	}
+	if w.DaysShort != 335 {
+		t.Errorf("DaysShort = %d, want 335", w.DaysShort)
+	}
+}
+
+func TestPlanShortHoldWarnings_SellItem(t *testing.T) {
+	now := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+	holdings := []models.Holding{{
+		Ticker: "BHP", Exchange: "AU", Units: 100, CurrentPrice: 12,
+		Trades: []*models.NavexaTrade{
+			{Type: "buy", Date: now.AddDate(0, 0, -345).Format("2006-01-02"), Units: 100, Price: 10},
+		},
+	}}
+	plan := &models.PortfolioPlan{Items: []models.PlanItem{
+		{ID: "exit-bhp", Ticker: "BHP.AU", Action: models.RuleActionSell, Status: models.PlanItemStatusPending},
+		{ID: "done", Ticker: "BHP.AU", Action: models.RuleActionSell, Status: models.PlanItemStatusCompleted},
+		{ID: "buy", Ticker: "BHP.AU", Action: models.RuleActionBuy, Status: models.PlanItemStatusPending},
+	}}
+
+	warnings := planShortHoldWarnings(plan, holdings, now, 365)
+	if len(warnings) != 1 {
+		t.Fatalf("expected 1 warning, got %d: %+v", len(warnings), warnings)
+	}
+	if warnings[0].PlanItemID != "exit-bhp" || warnings[0].DaysShort != 20 {
+		t.Errorf("warning = %+v, want plan item exit-bhp 20 days short", warnings[0])
+	}
+
+	// A deadline past the eligible date clears the warning
+	deadline := now.AddDate(0, 0, 21)
+	plan.Items[0].Deadline = &deadline
+	if warnings := planShortHoldWarnings(plan, holdings, now, 365); len(warnings) != 0 {
+		t.Errorf("expected no warning for sale after eligible date, got %+v", warnings)
+	}
+}
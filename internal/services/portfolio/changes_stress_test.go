@@ -2,6 +2,7 @@ package portfolio
 
 import (
 	"context"
+	"io"
 	"math"
 	"sync"
 	"testing"
@@ -697,4 +698,10 @@ func (m *stressMockStorageManager) PurgeDerivedData(ctx context.Context) (map[st
 	return nil, nil
 }
 func (m *stressMockStorageManager) PurgeReports(ctx context.Context) (int, error) { return 0, nil }
-func (m *stressMockStorageManager) Close() error                                  { return nil }
+func (m *stressMockStorageManager) Backup(_ context.Context, _ io.Writer) (*models.BackupManifest, error) {
+	return nil, nil
+}
+func (m *stressMockStorageManager) Restore(_ context.Context, _ io.Reader) (*models.BackupManifest, error) {
+	return nil, nil
+}
+func (m *stressMockStorageManager) Close() error { return nil }
@@ -2,15 +2,26 @@ package portfolio
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"time"
 
 	"github.com/wcharczuk/go-chart/v2"
 	"github.com/wcharczuk/go-chart/v2/drawing"
 
+	"github.com/bobmcallan/vire/internal/interfaces"
 	"github.com/bobmcallan/vire/internal/models"
 )
 
+// GetGrowthChart renders the portfolio's daily growth as a PNG line chart.
+func (s *Service) GetGrowthChart(ctx context.Context, name string, opts interfaces.GrowthOptions) ([]byte, error) {
+	growth, err := s.GetDailyGrowth(ctx, name, opts)
+	if err != nil {
+		return nil, err
+	}
+	return RenderGrowthChart(growth)
+}
+
 // RenderGrowthChart renders a PNG line chart of portfolio market value over time.
 // Single series: Portfolio Value (blue solid). Returns raw PNG bytes.
 func RenderGrowthChart(points []models.GrowthDataPoint) ([]byte, error) {
@@ -0,0 +1,102 @@
+package portfolio
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// Completeness component states.
+const (
+	completenessFresh   = "fresh"
+	completenessStale   = "stale"
+	completenessMissing = "missing"
+)
+
+// completenessMarketMaxAge is the freshness window for EOD bars and signals
+// when judging review trustworthiness. Wider than the collection TTLs so
+// weekends and public holidays don't mark data stale.
+const completenessMarketMaxAge = 96 * time.Hour
+
+// GetDataCompleteness scores how much of each open holding's data (EOD,
+// fundamentals, signals, trades) is present and fresh, using stock index
+// collection timestamps. The portfolio score is the mean of holding scores.
+func (s *Service) GetDataCompleteness(ctx context.Context, name string) (*models.DataCompleteness, error) {
+	portfolio, err := s.getPortfolioRecord(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("portfolio '%s' not found: %w", name, err)
+	}
+
+	now := time.Now()
+	result := &models.DataCompleteness{
+		PortfolioName: name,
+		Holdings:      []models.HoldingCompleteness{},
+		ComputedAt:    now,
+	}
+
+	var total float64
+	for _, h := range portfolio.Holdings {
+		if h.Units <= 0 {
+			continue
+		}
+		ticker := h.EODHDTicker()
+		var entry *models.StockIndexEntry
+		if store := s.storage.StockIndexStore(); store != nil {
+			entry, _ = store.Get(ctx, ticker)
+		}
+		hc := scoreHoldingCompleteness(ticker, entry, len(h.Trades) > 0, now)
+		total += hc.ScorePct
+		result.Holdings = append(result.Holdings, hc)
+	}
+	if len(result.Holdings) > 0 {
+		result.ScorePct = total / float64(len(result.Holdings))
+	}
+
+	return result, nil
+}
+
+// scoreHoldingCompleteness rates each component fresh (1), stale (0.5) or
+// missing (0) and returns the mean as a percentage. A nil entry means the
+// ticker is not yet in the stock index, so all market data is missing.
+func scoreHoldingCompleteness(ticker string, entry *models.StockIndexEntry, hasTrades bool, now time.Time) models.HoldingCompleteness {
+	hc := models.HoldingCompleteness{
+		Ticker:       ticker,
+		EOD:          completenessMissing,
+		Fundamentals: completenessMissing,
+		Signals:      completenessMissing,
+		Trades:       completenessMissing,
+	}
+	if entry != nil {
+		hc.EOD = componentState(entry.EODCollectedAt, completenessMarketMaxAge, now)
+		hc.Fundamentals = componentState(entry.FundamentalsCollectedAt, common.FreshnessFundamentals, now)
+		hc.Signals = componentState(entry.SignalsCollectedAt, completenessMarketMaxAge, now)
+	}
+	if hasTrades {
+		hc.Trades = completenessFresh
+	}
+
+	var points float64
+	for _, state := range []string{hc.EOD, hc.Fundamentals, hc.Signals, hc.Trades} {
+		switch state {
+		case completenessFresh:
+			points++
+		case completenessStale:
+			points += 0.5
+		}
+	}
+	hc.ScorePct = points / 4 * 100
+	return hc
+}
+
+func componentState(collectedAt time.Time, maxAge time.Duration, now time.Time) string {
+	if collectedAt.IsZero() {
+		return completenessMissing
+	}
+	if now.Sub(collectedAt) < maxAge {
+		return completenessFresh
+	}
+	return completenessStale
+}
@@ -0,0 +1,108 @@
+package portfolio
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// mapStockIndexStore is a StockIndexStore backed by a map of entries.
+type mapStockIndexStore struct {
+	noopStockIndexStore
+	entries map[string]*models.StockIndexEntry
+}
+
+func (m *mapStockIndexStore) Get(_ context.Context, ticker string) (*models.StockIndexEntry, error) {
+	if e, ok := m.entries[ticker]; ok {
+		return e, nil
+	}
+	return nil, fmt.Errorf("not found")
+}
+
+func TestGetDataCompleteness_MissingFundamentalsReducesScore(t *testing.T) {
+	now := time.Now()
+	trades := []*models.NavexaTrade{{Type: "buy", Date: "2024-01-10", Units: 100, Price: 10}}
+
+	stockIndex := &mapStockIndexStore{entries: map[string]*models.StockIndexEntry{
+		"BHP.AU": {
+			Ticker:                  "BHP.AU",
+			EODCollectedAt:          now.Add(-2 * time.Hour),
+			FundamentalsCollectedAt: now.Add(-24 * time.Hour),
+			SignalsCollectedAt:      now.Add(-2 * time.Hour),
+		},
+		"CBA.AU": {
+			Ticker:             "CBA.AU",
+			EODCollectedAt:     now.Add(-2 * time.Hour),
+			SignalsCollectedAt: now.Add(-2 * time.Hour),
+			// Fundamentals never collected
+		},
+	}}
+	storage := &stubStorageManager{
+		marketStore:   &stubMarketDataStorage{data: map[string]*models.MarketData{}},
+		userDataStore: newMemUserDataStore(),
+		stockIndex:    stockIndex,
+	}
+	svc := NewService(storage, nil, nil, nil, common.NewLogger("error"))
+	ctx := context.Background()
+
+	if err := svc.savePortfolioRecord(ctx, &models.Portfolio{
+		Name: "SMSF",
+		Holdings: []models.Holding{
+			{Ticker: "BHP", Exchange: "AU", Units: 100, Trades: trades},
+			{Ticker: "CBA", Exchange: "AU", Units: 50, Trades: trades},
+			{Ticker: "OLD", Exchange: "AU", Units: 0}, // closed, excluded
+		},
+	}); err != nil {
+		t.Fatalf("seed portfolio: %v", err)
+	}
+
+	got, err := svc.GetDataCompleteness(ctx, "SMSF")
+	if err != nil {
+		t.Fatalf("GetDataCompleteness failed: %v", err)
+	}
+	if len(got.Holdings) != 2 {
+		t.Fatalf("expected 2 open holdings scored, got %d", len(got.Holdings))
+	}
+
+	byTicker := make(map[string]models.HoldingCompleteness)
+	for _, hc := range got.Holdings {
+		byTicker[hc.Ticker] = hc
+	}
+	if bhp := byTicker["BHP.AU"]; bhp.ScorePct != 100 {
+		t.Errorf("BHP.AU score = %.1f, want 100 (all components fresh)", bhp.ScorePct)
+	}
+	cba := byTicker["CBA.AU"]
+	if cba.Fundamentals != completenessMissing {
+		t.Errorf("CBA.AU fundamentals = %q, want missing", cba.Fundamentals)
+	}
+	if cba.ScorePct != 75 {
+		t.Errorf("CBA.AU score = %.1f, want 75 (fundamentals missing)", cba.ScorePct)
+	}
+	if got.ScorePct != 87.5 {
+		t.Errorf("portfolio score = %.1f, want 87.5", got.ScorePct)
+	}
+}
+
+func TestScoreHoldingCompleteness_StaleAndUnindexed(t *testing.T) {
+	now := time.Now()
+	entry := &models.StockIndexEntry{
+		EODCollectedAt:          now.Add(-10 * 24 * time.Hour), // stale
+		FundamentalsCollectedAt: now.Add(-24 * time.Hour),
+		SignalsCollectedAt:      now.Add(-time.Hour),
+	}
+	hc := scoreHoldingCompleteness("BHP.AU", entry, false, now)
+	if hc.EOD != completenessStale || hc.Trades != completenessMissing {
+		t.Errorf("EOD=%q Trades=%q, want stale/missing", hc.EOD, hc.Trades)
+	}
+	if hc.ScorePct != 62.5 {
+		t.Errorf("score = %.1f, want 62.5", hc.ScorePct)
+	}
+
+	if hc := scoreHoldingCompleteness("NEW.AU", nil, true, now); hc.ScorePct != 25 {
+		t.Errorf("unindexed score = %.1f, want 25 (trades only)", hc.ScorePct)
+	}
+}
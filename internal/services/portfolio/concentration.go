@@ -0,0 +1,106 @@
+package portfolio
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bobmcallan/vire/internal/models"
+	"github.com/bobmcallan/vire/internal/signals"
+)
+
+// holdingConcentration returns the Herfindahl-Hirschman Index of the open
+// holdings' weights and the effective number of holdings (1 / HHI). Weights
+// are renormalised across the open holdings so cash does not dilute the
+// index: a single holding is always 1.0. No open holdings returns zeros.
+func holdingConcentration(holdings []models.HoldingReview) (hhi, effective float64) {
+	var total float64
+	var weights []float64
+	for _, hr := range holdings {
+		if hr.ActionRequired == "CLOSED" || hr.Holding.Units <= 0 || hr.Holding.WeightPct <= 0 {
+			continue
+		}
+		weights = append(weights, hr.Holding.WeightPct)
+		total += hr.Holding.WeightPct
+	}
+	if total <= 0 {
+		return 0, 0
+	}
+	for _, w := range weights {
+		share := w / total
+		hhi += share * share
+	}
+	return hhi, 1 / hhi
+}
+
+// concentrationAlert raises a review-level alert when hhi exceeds the
+// strategy's max_hhi. No strategy, no limit or no holdings = no alert.
+func concentrationAlert(hhi, effective float64, strategy *models.PortfolioStrategy) (models.Alert, bool) {
+	if strategy == nil || strategy.PositionSizing.MaxHHI <= 0 || hhi <= strategy.PositionSizing.MaxHHI {
+		return models.Alert{}, false
+	}
+	return models.Alert{
+		Type:     models.AlertTypeStrategy,
+		Severity: "medium",
+		Message: fmt.Sprintf("Portfolio concentration HHI %.2f (%.1f effective holdings) exceeds strategy max of %.2f",
+			hhi, effective, strategy.PositionSizing.MaxHHI),
+		Signal: "concentration_high",
+	}, true
+}
+
+// correlationAlerts raises a strategy_correlation_high alert for each open
+// holding whose daily returns over the default window correlate above the
+// strategy's max_correlation with a later (alphabetically) open holding, so
+// each pair is reported once. Pairs without enough shared history are
+// skipped. No strategy or no limit = no alerts.
+func correlationAlerts(holdings []models.HoldingReview, mdByTicker map[string]*models.MarketData, strategy *models.PortfolioStrategy) []models.Alert {
+	if strategy == nil || strategy.PositionSizing.MaxCorrelation <= 0 {
+		return nil
+	}
+	limit := strategy.PositionSizing.MaxCorrelation
+
+	bars := make(map[string][]models.EODBar)
+	names := make(map[string]string)
+	for _, hr := range holdings {
+		if hr.ActionRequired == "CLOSED" || hr.Holding.Units <= 0 {
+			continue
+		}
+		ticker := hr.Holding.EODHDTicker()
+		if md := mdByTicker[ticker]; md != nil {
+			bars[ticker] = md.EOD
+			names[ticker] = hr.Holding.Ticker
+		}
+	}
+	if len(bars) < 2 {
+		return nil
+	}
+	matrix := signals.ReturnCorrelations(bars, signals.DefaultCorrelationWindow)
+
+	tickers := make([]string, 0, len(matrix))
+	for t := range matrix {
+		tickers = append(tickers, t)
+	}
+	sort.Strings(tickers)
+
+	var alerts []models.Alert
+	for i, a := range tickers {
+		var peers []string
+		for _, b := range tickers[i+1:] {
+			if corr, ok := matrix[a][b]; ok && corr > limit {
+				peers = append(peers, fmt.Sprintf("%s (%.2f)", names[b], corr))
+			}
+		}
+		if len(peers) == 0 {
+			continue
+		}
+		alerts = append(alerts, models.Alert{
+			Type:     models.AlertTypeStrategy,
+			Severity: "medium",
+			Ticker:   names[a],
+			Message: fmt.Sprintf("%s daily returns correlate with %s over %d days, above the strategy max of %.2f: holding both adds little diversification",
+				names[a], strings.Join(peers, ", "), signals.DefaultCorrelationWindow, limit),
+			Signal: "strategy_correlation_high",
+		})
+	}
+	return alerts
+}
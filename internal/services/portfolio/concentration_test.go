@@ -0,0 +1,108 @@
+package portfolio
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// weightedReviews builds open holding reviews with the given weights
+func weightedReviews(weights ...float64) []models.HoldingReview {
+	reviews := make([]models.HoldingReview, len(weights))
+	for i, w := range weights {
+		reviews[i] = models.HoldingReview{Holding: models.Holding{Ticker: fmt.Sprintf("T%d", i), Units: 100, WeightPct: w}}
+	}
+	return reviews
+}
+
+func TestHoldingConcentration_BalancedVersusTwoHoldings(t *testing.T) {
+	strategy := &models.PortfolioStrategy{PositionSizing: models.PositionSizing{MaxHHI: 0.25}}
+
+	// Ten equal 9% positions (10% cash): renormalised to 10% each
+	balanced := weightedReviews(9, 9, 9, 9, 9, 9, 9, 9, 9, 9)
+	hhi, effective := holdingConcentration(balanced)
+	if math.Abs(hhi-0.1) > 1e-9 || math.Abs(effective-10) > 1e-9 {
+		t.Errorf("balanced HHI = %.4f, effective = %.2f; want 0.10 and 10", hhi, effective)
+	}
+	if _, ok := concentrationAlert(hhi, effective, strategy); ok {
+		t.Error("balanced portfolio should not raise concentration_high")
+	}
+
+	// Two positions at 70/30 dominate: 0.49 + 0.09
+	hhi, effective = holdingConcentration(weightedReviews(70, 30))
+	if math.Abs(hhi-0.58) > 1e-9 || math.Abs(effective-1/0.58) > 1e-9 {
+		t.Errorf("two-holding HHI = %.4f, effective = %.2f; want 0.58 and 1.72", hhi, effective)
+	}
+	alert, ok := concentrationAlert(hhi, effective, strategy)
+	if !ok || alert.Signal != "concentration_high" || alert.Type != models.AlertTypeStrategy {
+		t.Errorf("alert = %+v, %v; want a concentration_high strategy alert", alert, ok)
+	}
+	if _, ok := concentrationAlert(hhi, effective, nil); ok {
+		t.Error("no strategy should raise no alert")
+	}
+}
+
+func TestHoldingConcentration_SingleAndZeroHoldings(t *testing.T) {
+	if hhi, effective := holdingConcentration(weightedReviews(60)); hhi != 1 || effective != 1 {
+		t.Errorf("single holding HHI = %v, effective = %v; want 1, 1", hhi, effective)
+	}
+
+	closed := weightedReviews(50)
+	closed[0].ActionRequired = "CLOSED"
+	for _, reviews := range [][]models.HoldingReview{nil, closed} {
+		hhi, effective := holdingConcentration(reviews)
+		if hhi != 0 || effective != 0 {
+			t.Errorf("no open holdings: HHI = %v, effective = %v; want 0, 0", hhi, effective)
+		}
+		strategy := &models.PortfolioStrategy{PositionSizing: models.PositionSizing{MaxHHI: 0.25}}
+		if _, ok := concentrationAlert(hhi, effective, strategy); ok {
+			t.Error("no open holdings should raise no alert")
+		}
+	}
+}
+
+func TestCorrelationAlerts_FlagsHighlyCorrelatedPair(t *testing.T) {
+	end := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+	bars := func(sign float64) []models.EODBar {
+		eod := make([]models.EODBar, 61)
+		price := 100.0
+		for i := 60; i >= 0; i-- {
+			eod[i] = models.EODBar{Date: end.AddDate(0, 0, -i), Close: price}
+			price *= 1 + sign*float64((i%4)+1)/100*float64(1-2*(i%2))
+		}
+		return eod
+	}
+	holding := func(ticker string) models.HoldingReview {
+		return models.HoldingReview{Holding: models.Holding{Ticker: ticker, Exchange: "AU", Units: 100}}
+	}
+	reviews := []models.HoldingReview{holding("BHP"), holding("RIO"), holding("XYZ")}
+	md := map[string]*models.MarketData{
+		"BHP.AU": {Ticker: "BHP.AU", EOD: bars(1)},
+		"RIO.AU": {Ticker: "RIO.AU", EOD: bars(1)},
+		"XYZ.AU": {Ticker: "XYZ.AU", EOD: bars(-1)},
+	}
+	strategy := &models.PortfolioStrategy{PositionSizing: models.PositionSizing{MaxCorrelation: 0.85}}
+
+	alerts := correlationAlerts(reviews, md, strategy)
+	if len(alerts) != 1 {
+		t.Fatalf("got %d alerts, want 1: %+v", len(alerts), alerts)
+	}
+	a := alerts[0]
+	if a.Signal != "strategy_correlation_high" || a.Type != models.AlertTypeStrategy || a.Ticker != "BHP" {
+		t.Errorf("alert = %+v, want strategy_correlation_high on BHP", a)
+	}
+	if !strings.Contains(a.Message, "RIO (1.00)") || strings.Contains(a.Message, "XYZ") {
+		t.Errorf("message = %q, want RIO listed and anti-correlated XYZ left out", a.Message)
+	}
+
+	if got := correlationAlerts(reviews, md, nil); got != nil {
+		t.Errorf("no strategy: got %+v, want no alerts", got)
+	}
+	if got := correlationAlerts(reviews, md, &models.PortfolioStrategy{}); got != nil {
+		t.Errorf("no max_correlation: got %+v, want no alerts", got)
+	}
+}
@@ -0,0 +1,152 @@
+package portfolio
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// costLot is an open buy parcel with its per-unit cost (buy fees included).
+type costLot struct {
+	acquired time.Time
+	units    float64
+	unitCost float64
+}
+
+// lotDisposal is the part of a sell matched against a single buy lot.
+type lotDisposal struct {
+	acquired time.Time
+	disposed time.Time
+	units    float64
+	proceeds float64 // share of the sell's net proceeds, pro rata by units
+	costBase float64
+}
+
+// lotMatchResult holds the outcome of replaying trades against buy lots.
+type lotMatchResult struct {
+	lots      []costLot // Open lots, oldest first
+	disposals []lotDisposal
+	invested  float64 // All buys + fees + cost base adjustments
+	proceeds  float64 // All sells - fees
+	realized  float64 // Proceeds less the cost of the lots they consumed
+	buyUnits  float64
+}
+
+// matchLots replays trades oldest-first and matches each sell against open
+// buy lots — the oldest lots first, or the newest when lifo is set. A sell
+// that spans several lots consumes each in turn, splitting the last one.
+// Units sold beyond the open lots carry no cost base.
+func matchLots(trades []*models.NavexaTrade, lifo bool) lotMatchResult {
+	sorted := make([]*models.NavexaTrade, len(trades))
+	copy(sorted, trades)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Date < sorted[j].Date
+	})
+
+	var r lotMatchResult
+	for _, t := range sorted {
+		switch strings.ToLower(t.Type) {
+		case "buy", "opening balance":
+			cost := t.Units*t.Price + t.Fees
+			r.invested += cost
+			r.buyUnits += t.Units
+			if t.Units > 0 {
+				r.lots = append(r.lots, costLot{acquired: parseTradeDate(t.Date), units: t.Units, unitCost: cost / t.Units})
+			}
+		case "sell":
+			proceeds := t.Units*t.Price - t.Fees
+			r.proceeds += proceeds
+
+			var matchedCost float64
+			remaining := t.Units
+			for remaining > 1e-9 && len(r.lots) > 0 {
+				i := 0
+				if lifo {
+					i = len(r.lots) - 1
+				}
+				take := math.Min(remaining, r.lots[i].units)
+				matchedCost += take * r.lots[i].unitCost
+				if t.Units > 0 {
+					r.disposals = append(r.disposals, lotDisposal{
+						acquired: r.lots[i].acquired,
+						disposed: parseTradeDate(t.Date),
+						units:    take,
+						proceeds: proceeds * take / t.Units,
+						costBase: take * r.lots[i].unitCost,
+					})
+				}
+				r.lots[i].units -= take
+				remaining -= take
+				if r.lots[i].units < 1e-9 {
+					r.lots = append(r.lots[:i], r.lots[i+1:]...)
+				}
+			}
+			r.realized += proceeds - matchedCost
+		case "cost base increase", "cost base decrease":
+			value := t.Value
+			if strings.ToLower(t.Type) == "cost base decrease" {
+				value = -value
+			}
+			r.invested += value
+			// Spread across open lots by units; with nothing held the
+			// adjustment lands directly in realized.
+			var held float64
+			for _, l := range r.lots {
+				held += l.units
+			}
+			if held <= 0 {
+				r.realized -= value
+				continue
+			}
+			for i := range r.lots {
+				r.lots[i].unitCost += value / held
+			}
+		}
+	}
+	return r
+}
+
+// calculateRealizedFIFO computes realized gain/loss by matching each sell
+// against the oldest open buy lots. Return values mirror
+// calculateRealizedFromTrades; for a fully-sold position both agree.
+func calculateRealizedFIFO(trades []*models.NavexaTrade) (avgBuyPrice, totalInvested, totalProceeds, realizedGain float64) {
+	r := matchLots(trades, false)
+	if r.buyUnits > 0 {
+		avgBuyPrice = r.invested / r.buyUnits
+	}
+	return avgBuyPrice, r.invested, r.proceeds, r.realized
+}
+
+// calculateCostBasisFromTrades returns the average cost, remaining cost base
+// and units held under the given method. Average cost (the default, and the
+// fallback for unknown methods) defers to calculateAvgCostFromTrades.
+func calculateCostBasisFromTrades(trades []*models.NavexaTrade, method models.CostBasisMethod) (avgCost, totalCost, units float64) {
+	switch method {
+	case models.CostBasisFIFO, models.CostBasisLIFO:
+		r := matchLots(trades, method == models.CostBasisLIFO)
+		for _, l := range r.lots {
+			units += l.units
+			totalCost += l.units * l.unitCost
+		}
+		if units > 0 {
+			avgCost = totalCost / units
+		}
+		return avgCost, totalCost, units
+	default:
+		return calculateAvgCostFromTrades(trades)
+	}
+}
+
+// costBasisMethod returns the cost basis method from the portfolio's
+// strategy, defaulting to average cost when none is set.
+func (s *Service) costBasisMethod(ctx context.Context, name string) models.CostBasisMethod {
+	strat, err := s.getStrategyRecord(ctx, name)
+	if err != nil || strat.CostBasisMethod == "" {
+		return models.CostBasisAverage
+	}
+	return strat.CostBasisMethod
+}
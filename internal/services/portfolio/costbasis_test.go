@@ -0,0 +1,148 @@
+package portfolio
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// spanningLotTrades has three buy lots and one sell that consumes the first
+// two lots and part of the third (FIFO), or the reverse (LIFO).
+func spanningLotTrades() []*models.NavexaTrade {
+	return []*models.NavexaTrade{
+		{Type: "buy", Date: "2024-01-10", Units: 100, Price: 10},
+		{Type: "buy", Date: "2024-02-10", Units: 50, Price: 12},
+		{Type: "buy", Date: "2024-03-10", Units: 80, Price: 15},
+		{Type: "sell", Date: "2024-04-10", Units: 170, Price: 20, Fees: 10},
+	}
+}
+
+func TestCalculateRealizedFIFO_SellSpansMultipleLots(t *testing.T) {
+	avgBuy, invested, proceeds, realized := calculateRealizedFIFO(spanningLotTrades())
+
+	if !approxEqual(invested, 2800, 0.01) {
+		t.Errorf("invested = %.2f, want 2800", invested)
+	}
+	if !approxEqual(proceeds, 3390, 0.01) {
+		t.Errorf("proceeds = %.2f, want 3390", proceeds)
+	}
+	// Sold 100@10 + 50@12 + 20@15 = 1900 cost; 3390 - 1900 = 1490
+	if !approxEqual(realized, 1490, 0.01) {
+		t.Errorf("realized = %.2f, want 1490", realized)
+	}
+	if !approxEqual(avgBuy, 2800.0/230, 0.01) {
+		t.Errorf("avgBuy = %.2f, want %.2f", avgBuy, 2800.0/230)
+	}
+}
+
+func TestCalculateRealizedFIFO_ETPMAG_FullyClosed(t *testing.T) {
+	trades := []*models.NavexaTrade{
+		{Type: "buy", Units: 179, Price: 111.22, Fees: 3.00},
+		{Type: "buy", Units: 87, Price: 107.54, Fees: 3.00},
+		{Type: "buy", Units: 162, Price: 116.91, Fees: 3.00},
+		{Type: "sell", Units: 175, Price: 152.39, Fees: 3.00},
+		{Type: "sell", Units: 65, Price: 152.22, Fees: 3.00},
+		{Type: "sell", Units: 132, Price: 151.12, Fees: 3.00},
+		{Type: "sell", Units: 56, Price: 108.72, Fees: 3.00},
+	}
+
+	_, invested, proceeds, realized := calculateRealizedFIFO(trades)
+	_, avgInvested, avgProceeds, avgRealized := calculateRealizedFromTrades(trades)
+
+	// Every lot is consumed, so lot order cannot change the total: FIFO
+	// realized equals proceeds - invested (14373.93), the same as average
+	// cost. Navexa's $14,373.25 differs by $0.68 for reasons other than
+	// lot matching.
+	if !approxEqual(realized, 14373.93, 0.01) {
+		t.Errorf("realized = %.2f, want 14373.93", realized)
+	}
+	if !approxEqual(realized, avgRealized, 0.01) || invested != avgInvested || proceeds != avgProceeds {
+		t.Errorf("FIFO (%.2f/%.2f/%.2f) should match average (%.2f/%.2f/%.2f) for a closed position",
+			invested, proceeds, realized, avgInvested, avgProceeds, avgRealized)
+	}
+}
+
+func TestCalculateCostBasisFromTrades_Methods(t *testing.T) {
+	trades := spanningLotTrades()
+
+	tests := []struct {
+		method    models.CostBasisMethod
+		wantCost  float64
+		wantAvg   float64
+		wantUnits float64
+	}{
+		{models.CostBasisFIFO, 900, 15, 60},                            // 60 left of the 2024-03 lot
+		{models.CostBasisLIFO, 600, 10, 60},                            // 60 left of the 2024-01 lot
+		{models.CostBasisAverage, 60 * 2800.0 / 230, 2800.0 / 230, 60}, // weighted average
+		{"", 60 * 2800.0 / 230, 2800.0 / 230, 60},                      // default
+	}
+	for _, tc := range tests {
+		avg, cost, units := calculateCostBasisFromTrades(trades, tc.method)
+		if !approxEqual(cost, tc.wantCost, 0.01) || !approxEqual(avg, tc.wantAvg, 0.01) || !approxEqual(units, tc.wantUnits, 1e-9) {
+			t.Errorf("%q: avg/cost/units = %.2f/%.2f/%.2f, want %.2f/%.2f/%.2f",
+				tc.method, avg, cost, units, tc.wantAvg, tc.wantCost, tc.wantUnits)
+		}
+	}
+}
+
+func TestCalculateCostBasisFromTrades_CostBaseAdjustment(t *testing.T) {
+	trades := []*models.NavexaTrade{
+		{Type: "buy", Date: "2024-01-10", Units: 100, Price: 10},
+		{Type: "buy", Date: "2024-02-10", Units: 100, Price: 20},
+		{Type: "cost base decrease", Date: "2024-03-01", Value: 200},
+		{Type: "sell", Date: "2024-04-10", Units: 100, Price: 25},
+	}
+
+	// Decrease of 200 spread over 200 units lowers each lot by $1/unit
+	_, cost, units := calculateCostBasisFromTrades(trades, models.CostBasisFIFO)
+	if units != 100 || !approxEqual(cost, 1900, 0.01) {
+		t.Errorf("cost/units = %.2f/%.0f, want 1900/100", cost, units)
+	}
+	_, _, _, realized := calculateRealizedFIFO(trades)
+	if !approxEqual(realized, 1600, 0.01) {
+		t.Errorf("realized = %.2f, want 1600", realized)
+	}
+}
+
+func TestSyncPortfolio_UsesStrategyCostBasisMethod(t *testing.T) {
+	navexa := &stubNavexaClient{
+		portfolios: []*models.NavexaPortfolio{
+			{ID: "1", Name: "SMSF", Currency: "AUD", DateCreated: "2020-01-01"},
+		},
+		holdings: []*models.NavexaHolding{{
+			ID: "300", PortfolioID: "1", Ticker: "LOT", Exchange: "AU", Name: "Lot Co",
+			Units: 60, CurrentPrice: 20, MarketValue: 1200, LastUpdated: time.Now(),
+		}},
+		trades: map[string][]*models.NavexaTrade{"300": spanningLotTrades()},
+	}
+	storage := &stubStorageManager{
+		marketStore:   &stubMarketDataStorage{data: map[string]*models.MarketData{}},
+		userDataStore: newMemUserDataStore(),
+	}
+	svc := NewService(storage, nil, nil, nil, common.NewLogger("error"))
+	ctx := common.WithNavexaClient(context.Background(), navexa)
+
+	data, _ := json.Marshal(models.PortfolioStrategy{PortfolioName: "SMSF", CostBasisMethod: models.CostBasisFIFO})
+	_ = storage.userDataStore.Put(ctx, &models.UserRecord{
+		UserID: common.ResolveUserID(ctx), Subject: "strategy", Key: "SMSF", Value: string(data),
+	})
+
+	portfolio, err := svc.SyncPortfolio(ctx, "SMSF", true)
+	if err != nil {
+		t.Fatalf("SyncPortfolio failed: %v", err)
+	}
+	if len(portfolio.Holdings) != 1 {
+		t.Fatalf("expected 1 holding, got %d", len(portfolio.Holdings))
+	}
+	h := portfolio.Holdings[0]
+	if !approxEqual(h.AvgCost, 15, 0.01) || !approxEqual(h.CostBasis, 900, 0.01) {
+		t.Errorf("AvgCost/CostBasis = %.2f/%.2f, want 15/900 (FIFO)", h.AvgCost, h.CostBasis)
+	}
+	if !approxEqual(h.RealizedReturn, 1490, 0.01) {
+		t.Errorf("RealizedReturn = %.2f, want 1490 (FIFO)", h.RealizedReturn)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/bobmcallan/vire/internal/common"
 	"github.com/bobmcallan/vire/internal/interfaces"
 	"github.com/bobmcallan/vire/internal/models"
+	fxpkg "github.com/bobmcallan/vire/internal/services/fx"
 )
 
 // --- Model field tests ---
@@ -377,6 +378,7 @@ func TestSyncPortfolio_FXConversionForMixedCurrencyTotals(t *testing.T) {
 
 	logger := common.NewLogger("error")
 	svc := NewService(storage, nil, eodhd, nil, logger)
+	svc.SetFXService(fxpkg.NewService(eodhd, logger))
 
 	ctx := common.WithNavexaClient(context.Background(), navexa)
 	portfolio, err := svc.SyncPortfolio(ctx, "SMSF", true)
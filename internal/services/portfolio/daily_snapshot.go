@@ -0,0 +1,163 @@
+package portfolio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/interfaces"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// dailySnapshotSubject is the UserDataStore subject holding daily snapshots
+const dailySnapshotSubject = "portfolio_snapshot"
+
+// dailySnapshotKey keys a snapshot by portfolio and day, so a second snapshot
+// on the same day replaces the first.
+func dailySnapshotKey(name string, day time.Time) string {
+	return name + ":" + day.Format("2006-01-02")
+}
+
+// SnapshotPortfolio records today's headline values from the stored portfolio
+// (no Navexa sync). When the stored portfolio was last synced before today it
+// does not describe today, so nothing is written and the snapshot is nil.
+func (s *Service) SnapshotPortfolio(ctx context.Context, name string) (*models.DailyPortfolioSnapshot, error) {
+	return s.snapshotPortfolioAt(ctx, name, time.Now())
+}
+
+func (s *Service) snapshotPortfolioAt(ctx context.Context, name string, now time.Time) (*models.DailyPortfolioSnapshot, error) {
+	p, err := s.getPortfolioRecord(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	utc := now.UTC()
+	day := time.Date(utc.Year(), utc.Month(), utc.Day(), 0, 0, 0, 0, time.UTC)
+	if p.LastSynced.Before(day) {
+		s.logger.WithRequestID(ctx).Info().
+			Str("portfolio", name).
+			Str("last_synced", p.LastSynced.Format(time.RFC3339)).
+			Str("date", day.Format("2006-01-02")).
+			Msg("Skipping portfolio snapshot: stored portfolio predates the snapshot day")
+		return nil, nil
+	}
+
+	holdingCount := 0
+	for _, h := range p.Holdings {
+		if h.Units > 0 {
+			holdingCount++
+		}
+	}
+	snap := &models.DailyPortfolioSnapshot{
+		PortfolioName:  name,
+		Date:           day,
+		PortfolioValue: p.PortfolioValue,
+		EquityValue:    p.EquityHoldingsValue,
+		EquityCost:     p.EquityHoldingsCost,
+		AssetSetsValue: p.AssetSetsValue,
+		NetCashBalance: p.CapitalAvailable,
+		NetReturn:      p.EquityHoldingsReturn,
+		HoldingCount:   holdingCount,
+		SyncedAt:       p.LastSynced,
+		RecordedAt:     now,
+	}
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal portfolio snapshot: %w", err)
+	}
+	if err := s.storage.UserDataStore().Put(ctx, &models.UserRecord{
+		UserID:  common.ResolveUserID(ctx),
+		Subject: dailySnapshotSubject,
+		Key:     dailySnapshotKey(name, snap.Date),
+		Value:   string(data),
+	}); err != nil {
+		return nil, fmt.Errorf("failed to save portfolio snapshot: %w", err)
+	}
+	return snap, nil
+}
+
+// GetPortfolioHistory returns the portfolio's stored daily snapshots within
+// [from, to] (zero bounds are open), oldest first.
+func (s *Service) GetPortfolioHistory(ctx context.Context, name string, from, to time.Time) ([]models.DailyPortfolioSnapshot, error) {
+	records, err := s.storage.UserDataStore().List(ctx, common.ResolveUserID(ctx), dailySnapshotSubject)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list portfolio snapshots: %w", err)
+	}
+
+	prefix := name + ":"
+	history := make([]models.DailyPortfolioSnapshot, 0, len(records))
+	for _, rec := range records {
+		if !strings.HasPrefix(rec.Key, prefix) {
+			continue
+		}
+		var snap models.DailyPortfolioSnapshot
+		if err := json.Unmarshal([]byte(rec.Value), &snap); err != nil {
+			s.logger.WithRequestID(ctx).Warn().Err(err).Str("key", rec.Key).Msg("Skipping unreadable portfolio snapshot")
+			continue
+		}
+		if (!from.IsZero() && snap.Date.Before(from)) || (!to.IsZero() && snap.Date.After(to)) {
+			continue
+		}
+		history = append(history, snap)
+	}
+	sort.Slice(history, func(i, j int) bool { return history[i].Date.Before(history[j].Date) })
+	return history, nil
+}
+
+// GetPortfolioTimeline returns the daily value series for opts' range from
+// the stored snapshots. Days before the first snapshot, which predate the
+// daily snapshot job, are backfilled by recomputing them with
+// GetDailyGrowth; with no snapshots at all the whole range is recomputed.
+func (s *Service) GetPortfolioTimeline(ctx context.Context, name string, opts interfaces.GrowthOptions) ([]models.GrowthDataPoint, error) {
+	snapshots, err := s.GetPortfolioHistory(ctx, name, opts.From, opts.To)
+	if err != nil {
+		return nil, err
+	}
+	if len(snapshots) == 0 {
+		return s.GetDailyGrowth(ctx, name, opts)
+	}
+
+	var points []models.GrowthDataPoint
+	first := snapshots[0].Date
+	if opts.From.IsZero() || opts.From.Before(first) {
+		backfill := opts
+		backfill.To = first.AddDate(0, 0, -1)
+		computed, err := s.GetDailyGrowth(ctx, name, backfill)
+		if err != nil {
+			s.logger.WithRequestID(ctx).Warn().Err(err).Str("portfolio", name).
+				Msg("Timeline backfill before the first snapshot failed")
+		}
+		for _, p := range computed {
+			if p.Date.Before(first) {
+				points = append(points, p)
+			}
+		}
+	}
+	for _, snap := range snapshots {
+		points = append(points, snapshotToGrowthPoint(snap))
+	}
+	return points, nil
+}
+
+// snapshotToGrowthPoint maps a stored snapshot onto the timeline's point
+// shape. Cash-flow totals are not snapshotted and stay zero.
+func snapshotToGrowthPoint(snap models.DailyPortfolioSnapshot) models.GrowthDataPoint {
+	p := models.GrowthDataPoint{
+		Date:                 snap.Date,
+		EquityHoldingsValue:  snap.EquityValue,
+		EquityHoldingsCost:   snap.EquityCost,
+		EquityHoldingsReturn: snap.NetReturn,
+		HoldingCount:         snap.HoldingCount,
+		CapitalAvailable:     snap.NetCashBalance,
+		AssetSetsValue:       snap.AssetSetsValue,
+		PortfolioValue:       snap.PortfolioValue,
+	}
+	if snap.EquityCost > 0 {
+		p.EquityHoldingsReturnPct = snap.NetReturn / snap.EquityCost * 100
+	}
+	return p
+}
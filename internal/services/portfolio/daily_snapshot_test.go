@@ -0,0 +1,149 @@
+package portfolio
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/interfaces"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// snapshotTestSynced is after every snapshot day used below, so the stored
+// portfolio is never treated as stale unless a test says so.
+var snapshotTestSynced = time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+
+func newSnapshotTestService(t *testing.T, p *models.Portfolio) *Service {
+	t.Helper()
+	if p.LastSynced.IsZero() {
+		p.LastSynced = snapshotTestSynced
+	}
+	storage := &stubStorageManager{userDataStore: newMemUserDataStore()}
+	svc := NewService(storage, nil, nil, nil, common.NewLogger("error"))
+	if err := svc.savePortfolioRecord(context.Background(), p); err != nil {
+		t.Fatalf("savePortfolioRecord: %v", err)
+	}
+	return svc
+}
+
+func TestSnapshotPortfolio_FirstSnapshot(t *testing.T) {
+	svc := newSnapshotTestService(t, &models.Portfolio{
+		Name: "SMSF", PortfolioValue: 125000, EquityHoldingsValue: 100000,
+		CapitalAvailable: 25000, EquityHoldingsReturn: 12000,
+	})
+	ctx := context.Background()
+	now := time.Date(2026, 3, 4, 21, 30, 0, 0, time.UTC)
+
+	snap, err := svc.snapshotPortfolioAt(ctx, "SMSF", now)
+	if err != nil {
+		t.Fatalf("snapshotPortfolioAt: %v", err)
+	}
+	want := models.DailyPortfolioSnapshot{
+		PortfolioName: "SMSF", Date: time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC),
+		PortfolioValue: 125000, EquityValue: 100000, NetCashBalance: 25000, NetReturn: 12000,
+		SyncedAt: snapshotTestSynced, RecordedAt: now,
+	}
+	if *snap != want {
+		t.Errorf("snapshot = %+v, want %+v", *snap, want)
+	}
+
+	history, err := svc.GetPortfolioHistory(ctx, "SMSF", time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("GetPortfolioHistory: %v", err)
+	}
+	if len(history) != 1 || history[0].PortfolioValue != 125000 {
+		t.Errorf("history = %+v, want the one stored snapshot", history)
+	}
+}
+
+func TestSnapshotPortfolio_SameDayReplaces(t *testing.T) {
+	p := &models.Portfolio{Name: "SMSF", PortfolioValue: 100000}
+	svc := newSnapshotTestService(t, p)
+	ctx := context.Background()
+	morning := time.Date(2026, 3, 4, 1, 0, 0, 0, time.UTC)
+
+	svc.snapshotPortfolioAt(ctx, "SMSF", morning)
+	p.PortfolioValue = 101000
+	svc.savePortfolioRecord(ctx, p)
+	svc.snapshotPortfolioAt(ctx, "SMSF", morning.Add(10*time.Hour)) // e.g. after a restart
+
+	history, _ := svc.GetPortfolioHistory(ctx, "SMSF", time.Time{}, time.Time{})
+	if len(history) != 1 {
+		t.Fatalf("history has %d snapshots for one day, want 1", len(history))
+	}
+	if history[0].PortfolioValue != 101000 {
+		t.Errorf("PortfolioValue = %.0f, want the later 101000", history[0].PortfolioValue)
+	}
+}
+
+func TestGetPortfolioHistory_OrderedAndFiltered(t *testing.T) {
+	svc := newSnapshotTestService(t, &models.Portfolio{Name: "SMSF", PortfolioValue: 100000})
+	other := &models.Portfolio{Name: "SMSF2", PortfolioValue: 5000, LastSynced: snapshotTestSynced}
+	ctx := context.Background()
+	svc.savePortfolioRecord(ctx, other)
+
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for _, day := range []int{3, 0, 2, 1} { // stored out of order
+		svc.snapshotPortfolioAt(ctx, "SMSF", start.AddDate(0, 0, day))
+	}
+	svc.snapshotPortfolioAt(ctx, "SMSF2", start)
+
+	history, err := svc.GetPortfolioHistory(ctx, "SMSF", time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("GetPortfolioHistory: %v", err)
+	}
+	if len(history) != 4 {
+		t.Fatalf("len(history) = %d, want 4 (other portfolios excluded)", len(history))
+	}
+	for i := 1; i < len(history); i++ {
+		if !history[i].Date.After(history[i-1].Date) {
+			t.Errorf("history not ascending at %d: %s after %s", i, history[i].Date, history[i-1].Date)
+		}
+	}
+
+	from := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC)
+	ranged, _ := svc.GetPortfolioHistory(ctx, "SMSF", from, to)
+	if len(ranged) != 2 || !ranged[0].Date.Equal(from) || !ranged[1].Date.Equal(to) {
+		t.Errorf("ranged history = %+v, want 2026-03-02 and 2026-03-03", ranged)
+	}
+}
+
+func TestSnapshotPortfolio_SkipsStaleRecord(t *testing.T) {
+	synced := time.Date(2026, 3, 3, 22, 0, 0, 0, time.UTC)
+	svc := newSnapshotTestService(t, &models.Portfolio{Name: "SMSF", PortfolioValue: 100000, LastSynced: synced})
+	ctx := context.Background()
+
+	snap, err := svc.snapshotPortfolioAt(ctx, "SMSF", time.Date(2026, 3, 4, 21, 30, 0, 0, time.UTC))
+	if err != nil || snap != nil {
+		t.Fatalf("snapshotPortfolioAt = %+v, %v; want nil for a record synced the day before", snap, err)
+	}
+	history, _ := svc.GetPortfolioHistory(ctx, "SMSF", time.Time{}, time.Time{})
+	if len(history) != 0 {
+		t.Errorf("history = %+v, want nothing stored under 2026-03-04", history)
+	}
+}
+
+func TestGetPortfolioTimeline_ReadsSnapshots(t *testing.T) {
+	svc := newSnapshotTestService(t, &models.Portfolio{
+		Name: "SMSF", PortfolioValue: 125000, EquityHoldingsValue: 100000, EquityHoldingsCost: 80000,
+		CapitalAvailable: 25000, EquityHoldingsReturn: 20000,
+		Holdings: []models.Holding{{Ticker: "BHP", Units: 100}, {Ticker: "CBA", Units: 0}},
+	})
+	ctx := context.Background()
+	day := time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)
+	svc.snapshotPortfolioAt(ctx, "SMSF", day.Add(21*time.Hour))
+
+	points, err := svc.GetPortfolioTimeline(ctx, "SMSF", interfaces.GrowthOptions{From: day, To: day})
+	if err != nil {
+		t.Fatalf("GetPortfolioTimeline: %v", err)
+	}
+	want := models.GrowthDataPoint{
+		Date: day, EquityHoldingsValue: 100000, EquityHoldingsCost: 80000, EquityHoldingsReturn: 20000,
+		EquityHoldingsReturnPct: 25, HoldingCount: 1, CapitalAvailable: 25000, PortfolioValue: 125000,
+	}
+	if len(points) != 1 || points[0] != want {
+		t.Errorf("timeline = %+v, want [%+v]", points, want)
+	}
+}
@@ -0,0 +1,75 @@
+package portfolio
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/interfaces"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+func TestReviewPortfolio_DerivedMetrics(t *testing.T) {
+	today := time.Now()
+	portfolio := &models.Portfolio{
+		Name:       "SMSF",
+		LastSynced: today,
+		Holdings: []models.Holding{
+			{Ticker: "BHP", Exchange: "AU", Units: 100, CurrentPrice: 50, MarketValue: 5000, CostBasis: 4000},
+			{Ticker: "CBA", Exchange: "AU", Units: 20, CurrentPrice: 150, MarketValue: 3000, CostBasis: 0}, // no cost basis
+		},
+	}
+
+	uds := newMemUserDataStore()
+	storePortfolio(t, uds, portfolio)
+	strategy := models.PortfolioStrategy{
+		PortfolioName: "SMSF",
+		DerivedMetrics: []models.DerivedMetric{
+			{Name: "value_to_cost", Expression: "market_value / cost_basis"},
+		},
+	}
+	data, _ := json.Marshal(strategy)
+	_ = uds.Put(context.Background(), &models.UserRecord{
+		UserID: common.ResolveUserID(context.Background()), Subject: "strategy", Key: "SMSF", Value: string(data),
+	})
+
+	bars := func(price float64) []models.EODBar {
+		return []models.EODBar{{Date: today, Close: price}, {Date: today.AddDate(0, 0, -1), Close: price}}
+	}
+	storage := &reviewStorageManager{
+		userDataStore: uds,
+		marketStore: &reviewMarketDataStorage{data: map[string]*models.MarketData{
+			"BHP.AU": {Ticker: "BHP.AU", EOD: bars(50)},
+			"CBA.AU": {Ticker: "CBA.AU", EOD: bars(150)},
+		}},
+		signalStore: &reviewSignalStorage{signals: map[string]*models.TickerSignals{
+			"BHP.AU": {Ticker: "BHP.AU"},
+			"CBA.AU": {Ticker: "CBA.AU"},
+		}},
+	}
+	eodhd := &stubEODHDClient{
+		realTimeQuoteFn: func(_ context.Context, ticker string) (*models.RealTimeQuote, error) {
+			return nil, errNotFound
+		},
+	}
+
+	svc := NewService(storage, nil, eodhd, nil, common.NewLogger("error"))
+	review, err := svc.ReviewPortfolio(context.Background(), "SMSF", interfaces.ReviewOptions{})
+	if err != nil {
+		t.Fatalf("ReviewPortfolio: %v", err)
+	}
+
+	got := make(map[string]map[string]float64)
+	for _, hr := range review.HoldingReviews {
+		got[hr.Holding.Ticker] = hr.DerivedMetrics
+	}
+	if v, ok := got["BHP"]["value_to_cost"]; !ok || math.Abs(v-1.25) > 1e-9 {
+		t.Errorf("BHP value_to_cost = %v (present=%v), want 1.25", v, ok)
+	}
+	if _, ok := got["CBA"]["value_to_cost"]; ok {
+		t.Error("CBA value_to_cost should be omitted (zero cost basis)")
+	}
+}
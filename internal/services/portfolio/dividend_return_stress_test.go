@@ -9,6 +9,7 @@ import (
 
 	"github.com/bobmcallan/vire/internal/common"
 	"github.com/bobmcallan/vire/internal/models"
+	fxpkg "github.com/bobmcallan/vire/internal/services/fx"
 )
 
 // --- DividendReturn stress tests ---
@@ -159,6 +160,7 @@ func TestDividendReturn_FXConversion_MatchesSumOfConvertedHoldings(t *testing.T)
 	eodhd := &fxStubEODHDClient{forexRate: 0.6250}
 	logger := common.NewLogger("error")
 	svc := NewService(storage, nil, eodhd, nil, logger)
+	svc.SetFXService(fxpkg.NewService(eodhd, logger))
 	ctx := common.WithNavexaClient(context.Background(), navexa)
 
 	portfolio, err := svc.SyncPortfolio(ctx, "SMSF", true)
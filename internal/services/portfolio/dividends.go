@@ -0,0 +1,34 @@
+package portfolio
+
+import (
+	"strings"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// calculateDividendsFromTrades sums the Value of "dividend" trades, and the
+// portion paid in the 12 months to now. Undated dividends count towards the
+// total only. Dividends never affect invested or proceeds.
+func calculateDividendsFromTrades(trades []*models.NavexaTrade, now time.Time) (total, trailing12m float64) {
+	cutoff := now.AddDate(-1, 0, 0)
+	for _, t := range trades {
+		if strings.ToLower(t.Type) != "dividend" {
+			continue
+		}
+		total += t.Value
+		if d := parseTradeDate(t.Date); !d.IsZero() && d.After(cutoff) && !d.After(now) {
+			trailing12m += t.Value
+		}
+	}
+	return total, trailing12m
+}
+
+// trailingYieldPct returns trailing-12-month dividends as a percentage of
+// market value, or 0 for a closed or unpriced position.
+func trailingYieldPct(trailing12m, marketValue float64) float64 {
+	if marketValue <= 0 {
+		return 0
+	}
+	return trailing12m / marketValue * 100
+}
@@ -0,0 +1,250 @@
+package portfolio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+func TestCalculateDividendsFromTrades(t *testing.T) {
+	now := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+	trades := []*models.NavexaTrade{
+		{Type: "buy", Date: "2023-01-10", Units: 100, Price: 10, Value: 1000},
+		{Type: "dividend", Date: "2024-03-15", Value: 40}, // > 12 months ago
+		{Type: "Dividend", Date: "2024-09-15", Value: 45},
+		{Type: "dividend", Date: "2025-03-15", Value: 50},
+		{Type: "dividend", Value: 5}, // undated: total only
+		{Type: "sell", Date: "2025-04-01", Units: 50, Price: 12, Value: 600},
+	}
+
+	total, trailing := calculateDividendsFromTrades(trades, now)
+	if !approxEqual(total, 140, 0.001) {
+		t.Errorf("total = %.2f, want 140", total)
+	}
+	if !approxEqual(trailing, 95, 0.001) {
+		t.Errorf("trailing 12m = %.2f, want 95", trailing)
+	}
+	if got := trailingYieldPct(trailing, 0); got != 0 {
+		t.Errorf("trailingYieldPct with no market value = %.2f, want 0", got)
+	}
+}
+
+func TestSyncPortfolio_DividendIncome(t *testing.T) {
+	recent := time.Now().AddDate(0, -2, 0).Format("2006-01-02")
+	old := time.Now().AddDate(-2, 0, 0).Format("2006-01-02")
+	newNavexa := func() *stubNavexaClient {
+		return &stubNavexaClient{
+			portfolios: []*models.NavexaPortfolio{
+				{ID: "1", Name: "SMSF", Currency: "AUD", DateCreated: "2020-01-01"},
+			},
+			holdings: []*models.NavexaHolding{
+				{ID: "400", PortfolioID: "1", Ticker: "DIV", Exchange: "AU", Name: "Div Co",
+					Units: 100, CurrentPrice: 20, MarketValue: 2000, LastUpdated: time.Now()},
+				{ID: "401", PortfolioID: "1", Ticker: "GRO", Exchange: "AU", Name: "Growth Co",
+					Units: 10, CurrentPrice: 50, MarketValue: 500, LastUpdated: time.Now()},
+			},
+			trades: map[string][]*models.NavexaTrade{
+				"400": {
+					{Type: "buy", Date: old, Units: 100, Price: 15, Value: 1500},
+					{Type: "dividend", Date: old, Value: 30},
+					{Type: "dividend", Date: recent, Value: 70},
+				},
+				"401": {
+					{Type: "buy", Date: old, Units: 10, Price: 40, Value: 400},
+				},
+			},
+		}
+	}
+
+	sync := func(t *testing.T, strategy *models.PortfolioStrategy) *models.Portfolio {
+		t.Helper()
+		storage := &stubStorageManager{
+			marketStore:   &stubMarketDataStorage{data: map[string]*models.MarketData{}},
+			userDataStore: newMemUserDataStore(),
+		}
+		ctx := common.WithNavexaClient(context.Background(), newNavexa())
+		if strategy != nil {
+			data, _ := json.Marshal(strategy)
+			_ = storage.userDataStore.Put(ctx, &models.UserRecord{
+				UserID: common.ResolveUserID(ctx), Subject: "strategy", Key: "SMSF", Value: string(data),
+			})
+		}
+		svc := NewService(storage, nil, nil, nil, common.NewLogger("error"))
+		p, err := svc.SyncPortfolio(ctx, "SMSF", true)
+		if err != nil {
+			t.Fatalf("SyncPortfolio failed: %v", err)
+		}
+		return p
+	}
+
+	p := sync(t, nil)
+	var div models.Holding
+	for _, h := range p.Holdings {
+		if h.Ticker == "DIV" {
+			div = h
+		}
+	}
+
+	// Dividends don't leak into invested/proceeds or net return
+	if !approxEqual(div.GrossInvested, 1500, 0.01) || div.GrossProceeds != 0 {
+		t.Errorf("GrossInvested/GrossProceeds = %.2f/%.2f, want 1500/0", div.GrossInvested, div.GrossProceeds)
+	}
+	if !approxEqual(div.ReturnNet, 500, 0.01) {
+		t.Errorf("ReturnNet = %.2f, want 500 (dividends excluded by default)", div.ReturnNet)
+	}
+	if !approxEqual(div.DividendsReceived, 100, 0.01) {
+		t.Errorf("DividendsReceived = %.2f, want 100", div.DividendsReceived)
+	}
+	// 70 in the last 12 months / 2000 market value
+	if !approxEqual(div.TrailingYieldPct, 3.5, 0.001) {
+		t.Errorf("TrailingYieldPct = %.3f, want 3.5", div.TrailingYieldPct)
+	}
+	if !approxEqual(p.PortfolioDividendIncome, 100, 0.01) {
+		t.Errorf("PortfolioDividendIncome = %.2f, want 100", p.PortfolioDividendIncome)
+	}
+	baseReturn := p.EquityHoldingsReturn
+
+	// With the strategy flag, dividends roll into the holding's net return
+	// but the portfolio return is unchanged (no double counting)
+	p = sync(t, &models.PortfolioStrategy{
+		PortfolioName:      "SMSF",
+		IncomeRequirements: models.IncomeRequirements{IncludeDividendsInReturn: true},
+	})
+	for _, h := range p.Holdings {
+		if h.Ticker == "DIV" {
+			div = h
+		}
+	}
+	if !approxEqual(div.ReturnNet, 600, 0.01) {
+		t.Errorf("ReturnNet with dividends = %.2f, want 600", div.ReturnNet)
+	}
+	if !approxEqual(div.ReturnNetPct, 40, 0.01) {
+		t.Errorf("ReturnNetPct with dividends = %.2f, want 40", div.ReturnNetPct)
+	}
+	if !approxEqual(p.EquityHoldingsReturn, baseReturn, 0.01) {
+		t.Errorf("EquityHoldingsReturn = %.2f, want unchanged %.2f", p.EquityHoldingsReturn, baseReturn)
+	}
+}
+
+func TestSyncPortfolio_DividendsApportionedAcrossSameTicker(t *testing.T) {
+	old := time.Now().AddDate(-2, 0, 0).Format("2006-01-02")
+	sync := func(t *testing.T, includeDividends bool) (map[string]float64, float64) {
+		t.Helper()
+		navexa := &stubNavexaClient{
+			portfolios: []*models.NavexaPortfolio{
+				{ID: "1", Name: "SMSF", Currency: "AUD", DateCreated: "2020-01-01"},
+			},
+			// The same ticker held in two accounts
+			holdings: []*models.NavexaHolding{
+				{ID: "400", PortfolioID: "1", Ticker: "DIV", Exchange: "AU", Name: "Div Co",
+					Units: 75, CurrentPrice: 20, MarketValue: 1500, LastUpdated: time.Now()},
+				{ID: "402", PortfolioID: "1", Ticker: "DIV", Exchange: "AU", Name: "Div Co",
+					Units: 25, CurrentPrice: 20, MarketValue: 500, LastUpdated: time.Now()},
+			},
+			trades: map[string][]*models.NavexaTrade{
+				"400": {
+					{Type: "buy", Date: old, Units: 75, Price: 15, Value: 1125},
+					{Type: "dividend", Date: old, Value: 100},
+				},
+				"402": {
+					{Type: "buy", Date: old, Units: 25, Price: 15, Value: 375},
+				},
+			},
+		}
+		storage := &stubStorageManager{
+			marketStore:   &stubMarketDataStorage{data: map[string]*models.MarketData{}},
+			userDataStore: newMemUserDataStore(),
+		}
+		ctx := common.WithNavexaClient(context.Background(), navexa)
+		if includeDividends {
+			data, _ := json.Marshal(&models.PortfolioStrategy{
+				PortfolioName:      "SMSF",
+				IncomeRequirements: models.IncomeRequirements{IncludeDividendsInReturn: true},
+			})
+			_ = storage.userDataStore.Put(ctx, &models.UserRecord{
+				UserID: common.ResolveUserID(ctx), Subject: "strategy", Key: "SMSF", Value: string(data),
+			})
+		}
+		svc := NewService(storage, nil, nil, nil, common.NewLogger("error"))
+		p, err := svc.SyncPortfolio(ctx, "SMSF", true)
+		if err != nil {
+			t.Fatalf("SyncPortfolio failed: %v", err)
+		}
+		returns := make(map[string]float64)
+		for _, h := range p.Holdings {
+			returns[fmt.Sprintf("%.0f", h.Units)] = h.ReturnNet
+		}
+		return returns, p.EquityHoldingsReturn
+	}
+
+	base, baseTotal := sync(t, false)
+	with, withTotal := sync(t, true)
+
+	// 100 of dividends split 75/25 by units, not added to both holdings
+	if got := with["75"] - base["75"]; !approxEqual(got, 75, 0.01) {
+		t.Errorf("75-unit holding gained %.2f of dividends, want 75", got)
+	}
+	if got := with["25"] - base["25"]; !approxEqual(got, 25, 0.01) {
+		t.Errorf("25-unit holding gained %.2f of dividends, want 25", got)
+	}
+
+	// The total removes each holding's apportioned dividends, matching the
+	// apportioned holding returns
+	if !approxEqual(withTotal, baseTotal, 0.01) {
+		t.Errorf("EquityHoldingsReturn = %.2f, want unchanged %.2f", withTotal, baseTotal)
+	}
+}
+
+func TestSyncPortfolio_DividendIncomeSplitAcrossAccounts(t *testing.T) {
+	recent := time.Now().AddDate(0, -2, 0).Format("2006-01-02")
+	navexa := &stubNavexaClient{
+		portfolios: []*models.NavexaPortfolio{
+			{ID: "1", Name: "SMSF", Currency: "AUD", DateCreated: "2020-01-01"},
+		},
+		// The same ticker held in two accounts
+		holdings: []*models.NavexaHolding{
+			{ID: "410", PortfolioID: "1", Ticker: "DIV", Exchange: "AU", Name: "Div Co",
+				Units: 75, CurrentPrice: 20, MarketValue: 1500, LastUpdated: time.Now()},
+			{ID: "411", PortfolioID: "1", Ticker: "DIV", Exchange: "AU", Name: "Div Co",
+				Units: 25, CurrentPrice: 20, MarketValue: 500, LastUpdated: time.Now()},
+		},
+		trades: map[string][]*models.NavexaTrade{
+			"410": {
+				{Type: "buy", Date: recent, Units: 75, Price: 15, Value: 1125},
+				{Type: "dividend", Date: recent, Value: 100},
+			},
+			"411": {
+				{Type: "buy", Date: recent, Units: 25, Price: 15, Value: 375},
+			},
+		},
+	}
+	storage := &stubStorageManager{
+		marketStore:   &stubMarketDataStorage{data: map[string]*models.MarketData{}},
+		userDataStore: newMemUserDataStore(),
+	}
+	ctx := common.WithNavexaClient(context.Background(), navexa)
+	svc := NewService(storage, nil, nil, nil, common.NewLogger("error"))
+	p, err := svc.SyncPortfolio(ctx, "SMSF", true)
+	if err != nil {
+		t.Fatalf("SyncPortfolio failed: %v", err)
+	}
+
+	want := map[float64]float64{75: 75, 25: 25}
+	for _, h := range p.Holdings {
+		if !approxEqual(h.DividendsReceived, want[h.Units], 0.01) {
+			t.Errorf("%.0f-unit holding DividendsReceived = %.2f, want %.2f", h.Units, h.DividendsReceived, want[h.Units])
+		}
+		// 75/1500 and 25/500: the same yield on each account's value
+		if !approxEqual(h.TrailingYieldPct, 5, 0.01) {
+			t.Errorf("%.0f-unit holding TrailingYieldPct = %.2f, want 5", h.Units, h.TrailingYieldPct)
+		}
+	}
+	if !approxEqual(p.PortfolioDividendIncome, 100, 0.01) {
+		t.Errorf("PortfolioDividendIncome = %.2f, want 100", p.PortfolioDividendIncome)
+	}
+}
@@ -0,0 +1,72 @@
+package portfolio
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// daysToEarnings returns how many calendar days remain until the holding's
+// next scheduled earnings report, when it falls inside the strategy's
+// earnings_window_days. ok is false without a window or a known date.
+func daysToEarnings(fundamentals *models.Fundamentals, strategy *models.PortfolioStrategy, now time.Time) (time.Time, int, bool) {
+	if strategy == nil || strategy.PositionSizing.EarningsWindowDays <= 0 {
+		return time.Time{}, 0, false
+	}
+	date, ok := fundamentals.NextEarningsDate(now)
+	if !ok {
+		return time.Time{}, 0, false
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	days := int(date.Sub(today).Hours() / 24)
+	if days > strategy.PositionSizing.EarningsWindowDays {
+		return time.Time{}, 0, false
+	}
+	return date, days, true
+}
+
+// earningsAlert raises earnings_upcoming when the holding reports within the
+// strategy's earnings window. Holdings without a known date are skipped.
+func earningsAlert(holding models.Holding, fundamentals *models.Fundamentals, strategy *models.PortfolioStrategy, now time.Time) (models.Alert, bool) {
+	date, days, ok := daysToEarnings(fundamentals, strategy, now)
+	if !ok {
+		return models.Alert{}, false
+	}
+	return models.Alert{
+		Type:     models.AlertTypeRisk,
+		Severity: "medium",
+		Ticker:   holding.Ticker,
+		Message: fmt.Sprintf("%s reports earnings on %s (%s): expect a larger price move around the result",
+			holding.Ticker, date.Format("2006-01-02"), earningsCountdown(days)),
+		Signal: "earnings_upcoming",
+	}, true
+}
+
+// earningsWatchReason returns a WATCH reason when the holding reports within
+// the earnings window and its weight is at least the strategy's
+// earnings_watch_pct. Empty when either is unset or not met.
+func earningsWatchReason(holding *models.Holding, fundamentals *models.Fundamentals, strategy *models.PortfolioStrategy, now time.Time) string {
+	if holding == nil || strategy == nil || strategy.PositionSizing.EarningsWatchPct <= 0 ||
+		holding.WeightPct < strategy.PositionSizing.EarningsWatchPct {
+		return ""
+	}
+	date, days, ok := daysToEarnings(fundamentals, strategy, now)
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("Earnings %s (%s) with a %.1f%% position",
+		earningsCountdown(days), date.Format("2006-01-02"), holding.WeightPct)
+}
+
+// earningsCountdown phrases a day count as "today", "tomorrow" or "in N days".
+func earningsCountdown(days int) string {
+	switch days {
+	case 0:
+		return "today"
+	case 1:
+		return "tomorrow"
+	default:
+		return fmt.Sprintf("in %d days", days)
+	}
+}
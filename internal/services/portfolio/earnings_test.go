@@ -0,0 +1,82 @@
+package portfolio
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+func earningsIn(now time.Time, days int) *models.Fundamentals {
+	return &models.Fundamentals{EarningsDates: []string{now.AddDate(0, 0, days).Format("2006-01-02")}}
+}
+
+func TestEarningsAlert_WithinWindowFires(t *testing.T) {
+	now := time.Date(2025, 7, 14, 10, 0, 0, 0, time.UTC)
+	strategy := &models.PortfolioStrategy{PositionSizing: models.PositionSizing{EarningsWindowDays: 7}}
+	holding := models.Holding{Ticker: "BHP"}
+
+	for _, days := range []int{0, 3, 7} {
+		alert, ok := earningsAlert(holding, earningsIn(now, days), strategy, now)
+		if !ok {
+			t.Errorf("reporting in %d days: no alert, want earnings_upcoming", days)
+			continue
+		}
+		if alert.Signal != "earnings_upcoming" || alert.Ticker != "BHP" || alert.Type != models.AlertTypeRisk {
+			t.Errorf("alert = %+v", alert)
+		}
+		if want := now.AddDate(0, 0, days).Format("2006-01-02"); !strings.Contains(alert.Message, want) {
+			t.Errorf("message %q should name the report date %s", alert.Message, want)
+		}
+	}
+}
+
+func TestEarningsAlert_OutsideWindowSilent(t *testing.T) {
+	now := time.Date(2025, 7, 14, 10, 0, 0, 0, time.UTC)
+	strategy := &models.PortfolioStrategy{PositionSizing: models.PositionSizing{EarningsWindowDays: 7}}
+	holding := models.Holding{Ticker: "BHP"}
+
+	cases := map[string]struct {
+		fundamentals *models.Fundamentals
+		strategy     *models.PortfolioStrategy
+	}{
+		"beyond window":      {earningsIn(now, 8), strategy},
+		"already reported":   {earningsIn(now, -2), strategy},
+		"no earnings dates":  {&models.Fundamentals{}, strategy},
+		"no fundamentals":    {nil, strategy},
+		"malformed date":     {&models.Fundamentals{EarningsDates: []string{"soon"}}, strategy},
+		"no window in strat": {earningsIn(now, 1), &models.PortfolioStrategy{}},
+		"no strategy":        {earningsIn(now, 1), nil},
+	}
+	for name, tc := range cases {
+		if alert, ok := earningsAlert(holding, tc.fundamentals, tc.strategy, now); ok {
+			t.Errorf("%s: got %+v, want no alert", name, alert)
+		}
+	}
+}
+
+func TestDetermineAction_WatchBeforeEarningsForLargePosition(t *testing.T) {
+	now := time.Now()
+	signals := &models.TickerSignals{Technical: models.TechnicalSignals{RSI: 50}}
+	strategy := &models.PortfolioStrategy{PositionSizing: models.PositionSizing{EarningsWindowDays: 5, EarningsWatchPct: 10}}
+	soon := earningsIn(now, 2)
+
+	large := &models.Holding{Ticker: "BHP", WeightPct: 15}
+	action, reason := determineAction(signals, nil, strategy, large, soon)
+	if action != "WATCH" || !strings.Contains(reason, "Earnings in 2 days") {
+		t.Errorf("large position = (%q, %q), want WATCH with an earnings reason", action, reason)
+	}
+
+	small := &models.Holding{Ticker: "CBA", WeightPct: 4}
+	if action, _ := determineAction(signals, nil, strategy, small, soon); action == "WATCH" {
+		t.Error("position below earnings_watch_pct should not be WATCHed for earnings")
+	}
+	if action, _ := determineAction(signals, nil, strategy, large, earningsIn(now, 20)); action == "WATCH" {
+		t.Error("earnings outside the window should not WATCH")
+	}
+	alertOnly := &models.PortfolioStrategy{PositionSizing: models.PositionSizing{EarningsWindowDays: 5}}
+	if action, _ := determineAction(signals, nil, alertOnly, large, soon); action == "WATCH" {
+		t.Error("without earnings_watch_pct the window should only alert")
+	}
+}
@@ -0,0 +1,122 @@
+package portfolio
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/interfaces"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// defaultTradeFetchWorkers bounds concurrent holding enrichment (Navexa
+// trade fetch plus trade-derived metrics) during sync.
+const defaultTradeFetchWorkers = 10
+
+// holdingEnrichment is one holding's trades and the metrics derived from
+// them. trades is nil when the fetch failed or returned nothing.
+type holdingEnrichment struct {
+	trades  []*models.NavexaTrade
+	metrics *holdingCalcMetrics
+}
+
+// enrichHoldings fetches each holding's trades and computes its
+// trade-derived cost basis and returns on a bounded worker pool. Each worker
+// only touches its own holding and writes its result at the holding's index,
+// so the result is ordered like holdings regardless of completion order.
+func (s *Service) enrichHoldings(ctx context.Context, navexaClient interfaces.NavexaClient, holdings []*models.NavexaHolding, costMethod models.CostBasisMethod, logger *common.Logger) []holdingEnrichment {
+	workers := s.tradeFetchWorkers
+	if workers <= 0 {
+		workers = defaultTradeFetchWorkers
+	}
+	results := make([]holdingEnrichment, len(holdings))
+	now := time.Now()
+
+	indexCh := make(chan int, len(holdings))
+	for i, h := range holdings {
+		if h.ID != "" {
+			indexCh <- i
+		}
+	}
+	close(indexCh)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexCh {
+				h := holdings[i]
+				trades, err := navexaClient.GetHoldingTrades(ctx, h.ID)
+				if err != nil {
+					logger.Warn().Err(err).Str("ticker", h.Ticker).Str("holdingID", h.ID).Msg("Failed to get trades for holding")
+					continue
+				}
+				if len(trades) == 0 {
+					continue
+				}
+				results[i] = holdingEnrichment{
+					trades:  trades,
+					metrics: applyTradeMetrics(h, trades, costMethod, now, logger),
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// applyTradeMetrics sets h's units, cost basis, gain/loss and XIRR returns
+// from its trades and returns the realized/unrealized breakdown.
+func applyTradeMetrics(h *models.NavexaHolding, trades []*models.NavexaTrade, costMethod models.CostBasisMethod, now time.Time, logger *common.Logger) *holdingCalcMetrics {
+	// Calculate average cost, remaining cost, and units from trades
+	// using the strategy's cost basis method (average by default).
+	// Trade-derived units are authoritative — Navexa performance endpoint
+	// can return stale or rounded unit counts.
+	avgCost, remainingCost, tradeUnits := calculateCostBasisFromTrades(trades, costMethod)
+	h.AvgCost = avgCost
+	if math.Abs(tradeUnits-h.Units) > 0.01 {
+		logger.Warn().
+			Str("ticker", h.Ticker).
+			Float64("navexa_units", h.Units).
+			Float64("trade_units", tradeUnits).
+			Msg("Units mismatch: overriding Navexa value with trade-derived units")
+	}
+	h.Units = tradeUnits
+	h.MarketValue = h.CurrentPrice * h.Units
+
+	// Calculate gain/loss using the simple, correct formula:
+	// GainLoss = (proceeds from sells) + (current market value) - (total invested)
+	totalInvested, totalProceeds, gainLoss := calculateGainLossFromTrades(trades, h.MarketValue)
+
+	// TotalCost represents remaining cost basis (for position sizing)
+	// For closed positions, use totalInvested; for open, use remainingCost
+	if h.Units <= 0 {
+		h.TotalCost = totalInvested
+	} else {
+		h.TotalCost = remainingCost
+	}
+
+	h.GainLoss = gainLoss
+
+	// Simple percentage returns — denominator is total capital invested
+	if totalInvested > 0 {
+		h.GainLossPct = (h.GainLoss / totalInvested) * 100
+	} else {
+		h.GainLossPct = 0
+	}
+
+	// XIRR annualised returns
+	h.CapitalGainPct = CalculateXIRR(trades, h.MarketValue, h.DividendReturn, false, now)
+	h.TotalReturnPctIRR = CalculateXIRR(trades, h.MarketValue, h.DividendReturn, true, now)
+
+	// Realized/unrealized breakdown
+	return &holdingCalcMetrics{
+		totalInvested:      totalInvested,
+		totalProceeds:      totalProceeds,
+		realizedGainLoss:   totalProceeds - (totalInvested - remainingCost),
+		unrealizedGainLoss: h.MarketValue - remainingCost,
+	}
+}
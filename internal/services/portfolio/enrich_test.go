@@ -0,0 +1,179 @@
+package portfolio
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/interfaces"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+func TestSyncPortfolio_SameTickerAcrossAccountsKeepsAllTrades(t *testing.T) {
+	// Ten holdings of the same ticker (one per account) fetched concurrently
+	var holdings []*models.NavexaHolding
+	trades := make(map[string][]*models.NavexaTrade)
+	for i := 0; i < 10; i++ {
+		id := fmt.Sprintf("h%d", i)
+		holdings = append(holdings, &models.NavexaHolding{
+			ID: id, PortfolioID: "1", Ticker: "BHP", Exchange: "AU", Name: "BHP Group",
+			Units: 10, CurrentPrice: 45, MarketValue: 450, LastUpdated: time.Now(),
+		})
+		trades[id] = []*models.NavexaTrade{
+			{ID: id + "-1", HoldingID: id, Type: "buy", Date: "2024-01-10", Units: 5, Price: 40},
+			{ID: id + "-2", HoldingID: id, Type: "buy", Date: "2024-02-10", Units: 5, Price: 42},
+		}
+	}
+	navexa := &stubNavexaClient{
+		portfolios: []*models.NavexaPortfolio{{ID: "1", Name: "SMSF", Currency: "AUD", DateCreated: "2020-01-01"}},
+		holdings:   holdings,
+		trades:     trades,
+	}
+	storage := &stubStorageManager{
+		marketStore:   &stubMarketDataStorage{data: map[string]*models.MarketData{}},
+		userDataStore: newMemUserDataStore(),
+	}
+	svc := NewService(storage, nil, nil, nil, common.NewLogger("error"))
+	svc.SetTradeFetchWorkers(4)
+
+	ctx := common.WithNavexaClient(context.Background(), navexa)
+	portfolio, err := svc.SyncPortfolio(ctx, "SMSF", true)
+	if err != nil {
+		t.Fatalf("SyncPortfolio failed: %v", err)
+	}
+	found := 0
+	for _, h := range portfolio.Holdings {
+		if h.Ticker != "BHP" {
+			continue
+		}
+		found++
+		if len(h.Trades) != 20 {
+			t.Errorf("expected all 20 BHP trades on each holding, got %d", len(h.Trades))
+		}
+	}
+	if found == 0 {
+		t.Fatal("BHP holding not found")
+	}
+}
+
+// concurrencyNavexaClient records the peak number of concurrent
+// GetHoldingTrades calls.
+type concurrencyNavexaClient struct {
+	*stubNavexaClient
+	mu       sync.Mutex
+	inFlight int
+	peak     int
+}
+
+func (c *concurrencyNavexaClient) GetHoldingTrades(ctx context.Context, holdingID string) ([]*models.NavexaTrade, error) {
+	c.mu.Lock()
+	c.inFlight++
+	if c.inFlight > c.peak {
+		c.peak = c.inFlight
+	}
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		c.inFlight--
+		c.mu.Unlock()
+	}()
+	time.Sleep(5 * time.Millisecond)
+	return c.stubNavexaClient.GetHoldingTrades(ctx, holdingID)
+}
+
+func enrichmentFixture() *stubNavexaClient {
+	var holdings []*models.NavexaHolding
+	trades := make(map[string][]*models.NavexaTrade)
+	for i := 0; i < 20; i++ {
+		id := fmt.Sprintf("h%02d", i)
+		// Every fourth holding shares a ticker, to exercise trade ordering.
+		ticker := fmt.Sprintf("T%02d", i)
+		if i%4 == 0 {
+			ticker = "SHARED"
+		}
+		price := 10 + float64(i)
+		holdings = append(holdings, &models.NavexaHolding{
+			ID: id, PortfolioID: "1", Ticker: ticker, Exchange: "AU", Name: ticker,
+			Units: 100, CurrentPrice: price, MarketValue: price * 100, LastUpdated: time.Now(),
+		})
+		trades[id] = []*models.NavexaTrade{
+			{ID: id + "-1", HoldingID: id, Type: "buy", Date: "2023-03-01", Units: 150, Price: price * 0.8, Fees: 10},
+			{ID: id + "-2", HoldingID: id, Type: "sell", Date: "2024-06-01", Units: 50, Price: price * 1.1, Fees: 10},
+		}
+	}
+	return &stubNavexaClient{
+		portfolios: []*models.NavexaPortfolio{{ID: "1", Name: "SMSF", Currency: "AUD", DateCreated: "2020-01-01"}},
+		holdings:   holdings,
+		trades:     trades,
+	}
+}
+
+func syncWithWorkers(t *testing.T, navexa interfaces.NavexaClient, workers int) *models.Portfolio {
+	t.Helper()
+	storage := &stubStorageManager{
+		marketStore:   &stubMarketDataStorage{data: map[string]*models.MarketData{}},
+		userDataStore: newMemUserDataStore(),
+	}
+	svc := NewService(storage, nil, nil, nil, common.NewLogger("error"))
+	svc.SetTradeFetchWorkers(workers)
+
+	ctx := common.WithNavexaClient(context.Background(), navexa)
+	portfolio, err := svc.SyncPortfolio(ctx, "SMSF", true)
+	if err != nil {
+		t.Fatalf("SyncPortfolio(workers=%d) failed: %v", workers, err)
+	}
+	return portfolio
+}
+
+func TestSyncPortfolio_ConcurrentEnrichmentMatchesSerial(t *testing.T) {
+	serial := syncWithWorkers(t, enrichmentFixture(), 1)
+
+	counting := &concurrencyNavexaClient{stubNavexaClient: enrichmentFixture()}
+	parallel := syncWithWorkers(t, counting, 8)
+
+	if counting.peak < 2 {
+		t.Errorf("peak concurrent trade fetches = %d, want > 1", counting.peak)
+	}
+	if len(parallel.Holdings) != len(serial.Holdings) {
+		t.Fatalf("holdings: parallel %d, serial %d", len(parallel.Holdings), len(serial.Holdings))
+	}
+
+	const eps = 1e-6
+	for i := range serial.Holdings {
+		s, p := serial.Holdings[i], parallel.Holdings[i]
+		if s.Ticker != p.Ticker {
+			t.Fatalf("holding %d: ticker %s (parallel) vs %s (serial)", i, p.Ticker, s.Ticker)
+		}
+		fields := []struct {
+			name string
+			s, p float64
+		}{
+			{"units", s.Units, p.Units},
+			{"avg_cost", s.AvgCost, p.AvgCost},
+			{"cost_basis", s.CostBasis, p.CostBasis},
+			{"market_value", s.MarketValue, p.MarketValue},
+			{"return_net", s.ReturnNet, p.ReturnNet},
+			{"return_net_pct", s.ReturnNetPct, p.ReturnNetPct},
+			{"annualized_capital_return_pct", s.AnnualizedCapitalReturnPct, p.AnnualizedCapitalReturnPct},
+			{"annualized_total_return_pct", s.AnnualizedTotalReturnPct, p.AnnualizedTotalReturnPct},
+		}
+		for _, f := range fields {
+			if math.Abs(f.s-f.p) > eps {
+				t.Errorf("%s %s: parallel %v, serial %v", s.Ticker, f.name, f.p, f.s)
+			}
+		}
+		if len(s.Trades) != len(p.Trades) {
+			t.Errorf("%s trades: parallel %d, serial %d", s.Ticker, len(p.Trades), len(s.Trades))
+			continue
+		}
+		for j := range s.Trades {
+			if s.Trades[j].ID != p.Trades[j].ID {
+				t.Errorf("%s trade %d: parallel %s, serial %s", s.Ticker, j, p.Trades[j].ID, s.Trades[j].ID)
+			}
+		}
+	}
+}
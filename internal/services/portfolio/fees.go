@@ -0,0 +1,80 @@
+package portfolio
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// calculateFeesFromTrades sums brokerage on buy, opening balance and sell
+// trades. Fees are signed, as in calculateGainLossFromTrades: a rebate is a
+// negative fee and reduces the total.
+func calculateFeesFromTrades(trades []*models.NavexaTrade) float64 {
+	total := 0.0
+	for _, t := range trades {
+		switch strings.ToLower(t.Type) {
+		case "buy", "opening balance", "sell":
+			total += t.Fees
+		}
+	}
+	return total
+}
+
+// FeeSummary reports the brokerage paid on each holding and across the
+// portfolio, as an amount and as a percentage of gross invested capital.
+// Holdings are ordered by fees paid, largest first.
+func (s *Service) FeeSummary(ctx context.Context, portfolioName string) (*models.FeeSummary, error) {
+	portfolio, err := s.getPortfolioRecord(ctx, portfolioName)
+	if err != nil {
+		return nil, err
+	}
+	return buildFeeSummary(portfolio), nil
+}
+
+// buildFeeSummary derives the fee breakdown from synced holdings. Holdings
+// sharing a ticker carry the same merged trades, so each ticker counts once
+// towards the portfolio totals.
+func buildFeeSummary(portfolio *models.Portfolio) *models.FeeSummary {
+	summary := &models.FeeSummary{
+		PortfolioName: portfolio.Name,
+		Holdings:      make([]models.HoldingFees, 0, len(portfolio.Holdings)),
+	}
+	seen := make(map[string]bool, len(portfolio.Holdings))
+	for _, h := range portfolio.Holdings {
+		summary.Holdings = append(summary.Holdings, models.HoldingFees{
+			Ticker:          h.Ticker,
+			Name:            h.Name,
+			Status:          h.Status,
+			TotalFees:       h.TotalFees,
+			GrossInvested:   h.GrossInvested,
+			FeesPctInvested: feesPct(h.TotalFees, h.GrossInvested),
+		})
+		if seen[h.Ticker] {
+			continue
+		}
+		seen[h.Ticker] = true
+		summary.TotalFees += h.TotalFees
+		summary.GrossInvested += h.GrossInvested
+	}
+	summary.FeesPctInvested = feesPct(summary.TotalFees, summary.GrossInvested)
+
+	sort.SliceStable(summary.Holdings, func(i, j int) bool {
+		a, b := summary.Holdings[i], summary.Holdings[j]
+		if a.TotalFees != b.TotalFees {
+			return a.TotalFees > b.TotalFees
+		}
+		return a.Ticker < b.Ticker
+	})
+	return summary
+}
+
+// feesPct returns fees as a percentage of invested capital, or 0 when
+// nothing was invested.
+func feesPct(fees, invested float64) float64 {
+	if invested <= 0 {
+		return 0
+	}
+	return fees / invested * 100
+}
@@ -0,0 +1,130 @@
+package portfolio
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+func TestCalculateFeesFromTrades_MultipleBuysSellsWithRebate(t *testing.T) {
+	trades := []*models.NavexaTrade{
+		{Type: "Opening Balance", Units: 50, Price: 20.00, Fees: 0},
+		{Type: "Buy", Units: 100, Price: 10.00, Fees: 9.95},
+		{Type: "buy", Units: 200, Price: 11.00, Fees: 19.95},
+		{Type: "Sell", Units: 120, Price: 12.00, Fees: 14.95},
+		{Type: "sell", Units: 80, Price: 13.00, Fees: -4.00}, // rebate
+		{Type: "dividend", Value: 55.00, Fees: 1.00},         // not brokerage
+		{Type: "Cost Base Increase", Value: 100.00},
+	}
+
+	// 9.95 + 19.95 + 14.95 - 4.00
+	if got := calculateFeesFromTrades(trades); !approxEqual(got, 40.85, 0.001) {
+		t.Errorf("total fees = %.2f, want 40.85", got)
+	}
+
+	// Consistent with the signed convention in calculateGainLossFromTrades:
+	// the rebate lifts proceeds rather than being counted as a cost.
+	invested, proceeds, _ := calculateGainLossFromTrades(trades, 0)
+	units := 50*20.00 + 100*10.00 + 200*11.00 + 100.00
+	if !approxEqual(invested, units+9.95+19.95, 0.001) {
+		t.Errorf("invested = %.2f, want %.2f", invested, units+9.95+19.95)
+	}
+	if !approxEqual(proceeds, 120*12.00-14.95+80*13.00+4.00, 0.001) {
+		t.Errorf("proceeds = %.2f", proceeds)
+	}
+
+	if got := calculateFeesFromTrades(nil); got != 0 {
+		t.Errorf("nil trades fees = %.2f, want 0", got)
+	}
+}
+
+func TestSyncPortfolio_TotalFees(t *testing.T) {
+	old := time.Now().AddDate(-1, 0, 0).Format("2006-01-02")
+	recent := time.Now().AddDate(0, -1, 0).Format("2006-01-02")
+	navexa := &stubNavexaClient{
+		portfolios: []*models.NavexaPortfolio{
+			{ID: "1", Name: "SMSF", Currency: "AUD", DateCreated: "2020-01-01"},
+		},
+		holdings: []*models.NavexaHolding{
+			{ID: "500", PortfolioID: "1", Ticker: "BHP", Exchange: "AU", Name: "BHP Group",
+				Units: 150, CurrentPrice: 45, MarketValue: 6750, LastUpdated: time.Now()},
+			{ID: "501", PortfolioID: "1", Ticker: "RBT", Exchange: "AU", Name: "Rebate Co",
+				Units: 0, CurrentPrice: 12, LastUpdated: time.Now()},
+		},
+		trades: map[string][]*models.NavexaTrade{
+			"500": {
+				{Type: "buy", Date: old, Units: 100, Price: 40, Fees: 19.95},
+				{Type: "buy", Date: old, Units: 100, Price: 42, Fees: 19.95},
+				{Type: "sell", Date: recent, Units: 50, Price: 46, Fees: 9.95},
+			},
+			"501": {
+				{Type: "buy", Date: old, Units: 100, Price: 10, Fees: -5.00}, // rebate
+				{Type: "sell", Date: recent, Units: 100, Price: 12, Fees: -3.00},
+			},
+		},
+	}
+	storage := &stubStorageManager{
+		marketStore:   &stubMarketDataStorage{data: map[string]*models.MarketData{}},
+		userDataStore: newMemUserDataStore(),
+	}
+	ctx := common.WithNavexaClient(context.Background(), navexa)
+	svc := NewService(storage, nil, nil, nil, common.NewLogger("error"))
+
+	p, err := svc.SyncPortfolio(ctx, "SMSF", true)
+	if err != nil {
+		t.Fatalf("SyncPortfolio failed: %v", err)
+	}
+	fees := map[string]float64{}
+	for _, h := range p.Holdings {
+		fees[h.Ticker] = h.TotalFees
+	}
+	if !approxEqual(fees["BHP"], 49.85, 0.001) {
+		t.Errorf("BHP TotalFees = %.2f, want 49.85", fees["BHP"])
+	}
+	if !approxEqual(fees["RBT"], -8, 0.001) {
+		t.Errorf("RBT TotalFees = %.2f, want -8 (rebates)", fees["RBT"])
+	}
+	if !approxEqual(p.PortfolioTotalFees, 41.85, 0.001) {
+		t.Errorf("PortfolioTotalFees = %.2f, want 41.85", p.PortfolioTotalFees)
+	}
+}
+
+func TestFeeSummary_PercentOfInvested(t *testing.T) {
+	portfolio := &models.Portfolio{
+		Name: "SMSF",
+		Holdings: []models.Holding{
+			{Ticker: "BHP", Status: "open", TotalFees: 40, GrossInvested: 8000},
+			{Ticker: "CBA", Status: "closed", TotalFees: 20, GrossInvested: 2000},
+			{Ticker: "RBT", Status: "open", TotalFees: -5, GrossInvested: 1000}, // net rebate
+			{Ticker: "BHP", Status: "open", TotalFees: 40, GrossInvested: 8000}, // same merged trades as above
+			{Ticker: "NEW", Status: "open"},
+		},
+	}
+	uds := newMemUserDataStore()
+	storePortfolio(t, uds, portfolio)
+	svc := NewService(&stubStorageManager{userDataStore: uds}, nil, nil, nil, common.NewLogger("error"))
+
+	got, err := svc.FeeSummary(context.Background(), "SMSF")
+	if err != nil {
+		t.Fatalf("FeeSummary: %v", err)
+	}
+	// BHP counted once: 40 + 20 - 5 over 8000 + 2000 + 1000
+	if !approxEqual(got.TotalFees, 55, 0.001) || !approxEqual(got.GrossInvested, 11000, 0.001) {
+		t.Errorf("totals = %.2f fees / %.2f invested, want 55 / 11000", got.TotalFees, got.GrossInvested)
+	}
+	if !approxEqual(got.FeesPctInvested, 0.5, 0.0001) {
+		t.Errorf("fees_pct_invested = %.4f, want 0.5", got.FeesPctInvested)
+	}
+	if len(got.Holdings) != 5 || got.Holdings[0].Ticker != "BHP" || got.Holdings[4].Ticker != "RBT" {
+		t.Fatalf("holdings = %+v, want largest fees first and the rebate last", got.Holdings)
+	}
+	if cba := got.Holdings[2]; cba.Ticker != "CBA" || !approxEqual(cba.FeesPctInvested, 1.0, 0.0001) {
+		t.Errorf("CBA = %+v, want 1%% of invested", cba)
+	}
+	if n := got.Holdings[3]; n.Ticker != "NEW" || n.FeesPctInvested != 0 {
+		t.Errorf("NEW = %+v, want 0%% with nothing invested", n)
+	}
+}
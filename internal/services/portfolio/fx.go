@@ -0,0 +1,123 @@
+package portfolio
+
+import (
+	"context"
+
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// fetchFXRates returns the base/quote rate for each holding currency that
+// differs from base. Currencies whose rate cannot be fetched are omitted and
+// logged, leaving those holdings in their own currency. Returns nil when no
+// conversion is needed or possible.
+func (s *Service) fetchFXRates(ctx context.Context, base string, holdings []models.Holding) map[string]float64 {
+	logger := s.logger.WithRequestID(ctx)
+	var rates map[string]float64
+	failed := make(map[string]bool)
+	for _, h := range holdings {
+		currency := h.Currency
+		if currency == base || failed[currency] {
+			continue
+		}
+		if _, ok := rates[currency]; ok {
+			continue
+		}
+		if s.fx == nil {
+			logger.Warn().Str("base", base).Str("currency", currency).
+				Msg("No FX service configured; holdings will not be converted")
+			failed[currency] = true
+			continue
+		}
+		rate, err := s.fx.Rate(ctx, base, currency)
+		if err != nil || rate <= 0 {
+			logger.Warn().Err(err).Str("base", base).Str("currency", currency).
+				Msg("Failed to fetch FX rate; holdings in this currency will not be converted")
+			failed[currency] = true
+			continue
+		}
+		if rates == nil {
+			rates = make(map[string]float64)
+		}
+		rates[currency] = rate
+		logger.Info().Str("pair", base+currency).Float64("rate", rate).Msg("Fetched FX rate")
+	}
+	return rates
+}
+
+// convertHoldingCurrency converts h's monetary fields into base using rate
+// (units of h's currency per 1 base) and records the original currency.
+func convertHoldingCurrency(h *models.Holding, base string, rate float64) {
+	h.OriginalCurrency = h.Currency
+	h.CurrentPrice /= rate
+	h.AvgCost /= rate
+	h.MarketValue /= rate
+	h.CostBasis /= rate
+	h.GrossInvested /= rate
+	h.GrossProceeds /= rate
+	h.TotalFees /= rate
+	h.ReturnNet /= rate
+	h.RealizedReturn /= rate
+	h.UnrealizedReturn /= rate
+	h.DividendReturn /= rate
+	h.DividendsReceived /= rate
+	if h.TrueBreakevenPrice != nil {
+		converted := *h.TrueBreakevenPrice / rate
+		h.TrueBreakevenPrice = &converted
+	}
+	h.Currency = base
+}
+
+// portfolioFXDiv returns the divisor that converts a native-currency value of
+// h (e.g. a trade price) into p's base currency, using the rates recorded at
+// sync. Unconverted holdings return 1. EODHD prices use quoteFXDiv.
+func portfolioFXDiv(p *models.Portfolio, h *models.Holding) float64 {
+	if h.OriginalCurrency == "" {
+		return 1.0
+	}
+	if rate := p.FXRates[h.OriginalCurrency]; rate > 0 {
+		return rate
+	}
+	if h.OriginalCurrency == "USD" && p.FXRate > 0 {
+		return p.FXRate // portfolios synced before FXRates was recorded
+	}
+	return 1.0
+}
+
+// quoteFXDiv is portfolioFXDiv for EODHD prices (EOD closes, live quotes),
+// which EODHD quotes in cents for holdings flagged PriceInCents. Navexa
+// trade prices are always in dollars and use portfolioFXDiv.
+func quoteFXDiv(p *models.Portfolio, h *models.Holding) float64 {
+	div := portfolioFXDiv(p, h)
+	if h.PriceInCents {
+		div *= 100
+	}
+	return div
+}
+
+// FXDivisor returns the divisor converting values in currency into p's
+// base currency: the rate recorded at sync, else a live rate from the FX
+// service, else 1 (no conversion) with a warning.
+func (s *Service) FXDivisor(ctx context.Context, p *models.Portfolio, currency string) float64 {
+	base := p.BaseCurrency
+	if base == "" {
+		base = "AUD" // portfolios synced before BaseCurrency was recorded
+	}
+	if currency == "" || currency == base {
+		return 1.0
+	}
+	if rate := p.FXRates[currency]; rate > 0 {
+		return rate
+	}
+	if currency == "USD" && p.FXRate > 0 {
+		return p.FXRate
+	}
+	if s.fx != nil {
+		rate, err := s.fx.Rate(ctx, base, currency)
+		if err == nil && rate > 0 {
+			return rate
+		}
+		s.logger.WithRequestID(ctx).Warn().Err(err).Str("base", base).Str("currency", currency).
+			Msg("Failed to fetch FX rate; values will be unconverted")
+	}
+	return 1.0
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/bobmcallan/vire/internal/common"
 	"github.com/bobmcallan/vire/internal/interfaces"
 	"github.com/bobmcallan/vire/internal/models"
+	fxpkg "github.com/bobmcallan/vire/internal/services/fx"
 )
 
 // --- FX conversion edge-case stress tests ---
@@ -125,6 +126,7 @@ func TestSyncPortfolio_VerySmallFXRate(t *testing.T) {
 	eodhd := &fxStubEODHDClient{forexRate: 0.001} // extremely weak AUD
 	logger := common.NewLogger("error")
 	svc := NewService(storage, nil, eodhd, nil, logger)
+	svc.SetFXService(fxpkg.NewService(eodhd, logger))
 
 	ctx := common.WithNavexaClient(context.Background(), navexa)
 	portfolio, err := svc.SyncPortfolio(ctx, "SMSF", true)
@@ -171,6 +173,7 @@ func TestSyncPortfolio_VeryLargeFXRate(t *testing.T) {
 	eodhd := &fxStubEODHDClient{forexRate: 100.0}
 	logger := common.NewLogger("error")
 	svc := NewService(storage, nil, eodhd, nil, logger)
+	svc.SetFXService(fxpkg.NewService(eodhd, logger))
 
 	ctx := common.WithNavexaClient(context.Background(), navexa)
 	portfolio, err := svc.SyncPortfolio(ctx, "SMSF", true)
@@ -651,6 +654,7 @@ func TestSyncPortfolio_ClosedUSDPosition_FXConverted(t *testing.T) {
 	eodhd := &fxStubEODHDClient{forexRate: 0.6250}
 	logger := common.NewLogger("error")
 	svc := NewService(storage, nil, eodhd, nil, logger)
+	svc.SetFXService(fxpkg.NewService(eodhd, logger))
 
 	ctx := common.WithNavexaClient(context.Background(), navexa)
 	portfolio, err := svc.SyncPortfolio(ctx, "SMSF", true)
@@ -836,6 +840,7 @@ func TestSyncPortfolio_FXRateStoredOnPortfolio(t *testing.T) {
 	eodhd := &fxStubEODHDClient{forexRate: 0.6250}
 	logger := common.NewLogger("error")
 	svc := NewService(storage, nil, eodhd, nil, logger)
+	svc.SetFXService(fxpkg.NewService(eodhd, logger))
 
 	ctx := common.WithNavexaClient(context.Background(), navexa)
 	portfolio, err := svc.SyncPortfolio(ctx, "SMSF", true)
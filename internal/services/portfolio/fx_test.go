@@ -9,6 +9,7 @@ import (
 
 	"github.com/bobmcallan/vire/internal/common"
 	"github.com/bobmcallan/vire/internal/models"
+	fxpkg "github.com/bobmcallan/vire/internal/services/fx"
 )
 
 // --- Per-holding FX conversion tests ---
@@ -48,6 +49,7 @@ func TestSyncPortfolio_USDHoldingsConvertedToAUD(t *testing.T) {
 	eodhd := &fxStubEODHDClient{forexRate: 0.6250}
 	logger := common.NewLogger("error")
 	svc := NewService(storage, nil, eodhd, nil, logger)
+	svc.SetFXService(fxpkg.NewService(eodhd, logger))
 
 	ctx := common.WithNavexaClient(context.Background(), navexa)
 	portfolio, err := svc.SyncPortfolio(ctx, "SMSF", true)
@@ -186,6 +188,7 @@ func TestSyncPortfolio_PortfolioTotalsCorrectAfterFXConversion(t *testing.T) {
 	eodhd := &fxStubEODHDClient{forexRate: 0.6250}
 	logger := common.NewLogger("error")
 	svc := NewService(storage, nil, eodhd, nil, logger)
+	svc.SetFXService(fxpkg.NewService(eodhd, logger))
 
 	ctx := common.WithNavexaClient(context.Background(), navexa)
 	portfolio, err := svc.SyncPortfolio(ctx, "SMSF", true)
@@ -245,6 +248,7 @@ func TestSyncPortfolio_TrueBreakevenConvertedForUSD(t *testing.T) {
 	eodhd := &fxStubEODHDClient{forexRate: 0.6250}
 	logger := common.NewLogger("error")
 	svc := NewService(storage, nil, eodhd, nil, logger)
+	svc.SetFXService(fxpkg.NewService(eodhd, logger))
 
 	ctx := common.WithNavexaClient(context.Background(), navexa)
 	portfolio, err := svc.SyncPortfolio(ctx, "SMSF", true)
@@ -289,6 +293,7 @@ func TestSyncPortfolio_NetReturnConvertedForUSD(t *testing.T) {
 	eodhd := &fxStubEODHDClient{forexRate: 0.6250}
 	logger := common.NewLogger("error")
 	svc := NewService(storage, nil, eodhd, nil, logger)
+	svc.SetFXService(fxpkg.NewService(eodhd, logger))
 
 	ctx := common.WithNavexaClient(context.Background(), navexa)
 	portfolio, err := svc.SyncPortfolio(ctx, "SMSF", true)
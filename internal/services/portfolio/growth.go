@@ -96,8 +96,9 @@ func newHoldingGrowthState(ticker string, trades []*models.NavexaTrade, fxDiv fl
 // It bulk-loads all data once then iterates dates in memory — O(holdings) reads
 // instead of O(days × holdings).
 func (s *Service) GetDailyGrowth(ctx context.Context, name string, opts interfaces.GrowthOptions) ([]models.GrowthDataPoint, error) {
+	logger := s.logger.WithRequestID(ctx)
 	funcStart := time.Now()
-	s.logger.Info().Str("name", name).Msg("Computing daily portfolio growth")
+	logger.Info().Str("name", name).Msg("Computing daily portfolio growth")
 
 	// Phase 1: Load portfolio once
 	phaseStart := time.Now()
@@ -110,7 +111,7 @@ func (s *Service) GetDailyGrowth(ctx context.Context, name string, opts interfac
 	if earliest.IsZero() {
 		return nil, fmt.Errorf("no trades found in portfolio '%s'", name)
 	}
-	s.logger.Info().Dur("elapsed", time.Since(phaseStart)).Msg("GetDailyGrowth: portfolio load complete")
+	logger.Info().Dur("elapsed", time.Since(phaseStart)).Msg("GetDailyGrowth: portfolio load complete")
 
 	// Auto-load cash transactions if not provided by caller.
 	// This ensures all code paths (handler, scheduler, internal) include cash
@@ -135,7 +136,7 @@ func (s *Service) GetDailyGrowth(ctx context.Context, name string, opts interfac
 	}
 
 	dates := generateCalendarDates(from, to)
-	s.logger.Info().
+	logger.Info().
 		Str("name", name).
 		Str("from", from.Format(time.RFC3339)).
 		Str("to", to.Format(time.RFC3339)).
@@ -150,7 +151,7 @@ func (s *Service) GetDailyGrowth(ctx context.Context, name string, opts interfac
 	// return them directly — no market data load or trade replay needed.
 	userID := common.ResolveUserID(ctx)
 	if cached, ok := s.tryTimelineCache(ctx, userID, name, from, to); ok {
-		s.logger.Info().Str("name", name).Int("points", len(cached)).Dur("elapsed", time.Since(funcStart)).Msg("GetDailyGrowth: served from timeline cache")
+		logger.Info().Str("name", name).Int("points", len(cached)).Dur("elapsed", time.Since(funcStart)).Msg("GetDailyGrowth: served from timeline cache")
 		return cached, nil
 	}
 
@@ -173,51 +174,33 @@ func (s *Service) GetDailyGrowth(ctx context.Context, name string, opts interfac
 	for _, md := range allMarketData {
 		mdByTicker[md.Ticker] = md
 	}
-	s.logger.Info().Dur("elapsed", time.Since(phaseStart)).Int("tickers", len(tickers)).Msg("GetDailyGrowth: market data batch load complete")
+	logger.Info().Dur("elapsed", time.Since(phaseStart)).Int("tickers", len(tickers)).Msg("GetDailyGrowth: market data batch load complete")
 
 	// Phase 4: Determine FX rate for USD→AUD conversion.
 	// Holdings may be in different currencies (e.g. CBOE in USD, BHP in AUD).
 	// Trade prices and EOD close prices are in the holding's native currency
-	// and must be converted to the portfolio base currency (AUD).
+	// and must be converted to the portfolio base currency. Uses the rates
+	// persisted at sync, falling back to a live rate per currency.
 	phaseStart = time.Now()
 	fxDivByTicker := make(map[string]float64, len(p.Holdings))
-	hasUSD := false
+	fxDivByCurrency := make(map[string]float64)
 	for _, h := range p.Holdings {
 		if len(h.Trades) == 0 {
 			continue
 		}
-		ticker := h.EODHDTicker()
 		currency := h.OriginalCurrency
 		if currency == "" {
 			currency = h.Currency
 		}
-		if currency == "USD" {
-			hasUSD = true
-			fxDivByTicker[ticker] = 0 // placeholder, set below
-		} else {
-			fxDivByTicker[ticker] = 1.0
-		}
-	}
-
-	if hasUSD {
-		// Use persisted FX rate from last sync; fall back to live quote.
-		fxDiv := p.FXRate
-		if fxDiv <= 0 && s.eodhd != nil {
-			if quote, err := s.eodhd.GetRealTimeQuote(ctx, "AUDUSD.FOREX"); err == nil && quote.Close > 0 {
-				fxDiv = quote.Close
-			} else {
-				s.logger.Warn().Err(err).Msg("GetDailyGrowth: failed to fetch AUDUSD rate; USD values will be unconverted")
+		fxDiv, ok := fxDivByCurrency[currency]
+		if !ok {
+			fxDiv = s.FXDivisor(ctx, p, currency)
+			fxDivByCurrency[currency] = fxDiv
+			if fxDiv != 1.0 {
+				logger.Info().Str("currency", currency).Float64("fx_div", fxDiv).Msg("GetDailyGrowth: FX divisor for foreign holdings")
 			}
 		}
-		if fxDiv <= 0 {
-			fxDiv = 1.0 // fallback: no conversion
-		}
-		for ticker, v := range fxDivByTicker {
-			if v == 0 {
-				fxDivByTicker[ticker] = fxDiv
-			}
-		}
-		s.logger.Info().Float64("fx_div", fxDiv).Msg("GetDailyGrowth: FX divisor for USD holdings")
+		fxDivByTicker[h.EODHDTicker()] = fxDiv
 	}
 
 	// Initialize incremental trade replay states.
@@ -238,7 +221,7 @@ func (s *Service) GetDailyGrowth(ctx context.Context, name string, opts interfac
 		holdingStates = append(holdingStates, newHoldingGrowthState(ticker, h.Trades, fxDivByTicker[ticker]))
 	}
 	if noMarketDataCount > 0 {
-		s.logger.Warn().Int("count", noMarketDataCount).Msg("GetDailyGrowth: holdings without market data (trades tracked, equity unpriced)")
+		logger.Warn().Int("count", noMarketDataCount).Msg("GetDailyGrowth: holdings without market data (trades tracked, equity unpriced)")
 	}
 
 	// Phase 5: Prepare cash flow cursor for single-pass merge
@@ -354,9 +337,9 @@ func (s *Service) GetDailyGrowth(ctx context.Context, name string, opts interfac
 			CapitalContributionsNet: runningNetDeployed,
 		})
 	}
-	s.logger.Info().Dur("elapsed", time.Since(phaseStart)).Int("days", len(dates)).Int("holdings", len(holdingStates)).Msg("GetDailyGrowth: date iteration complete")
+	logger.Info().Dur("elapsed", time.Since(phaseStart)).Int("days", len(dates)).Int("holdings", len(holdingStates)).Msg("GetDailyGrowth: date iteration complete")
 
-	s.logger.Info().Str("name", name).Int("points", len(points)).Dur("elapsed", time.Since(funcStart)).Msg("GetDailyGrowth: TOTAL")
+	logger.Info().Str("name", name).Int("points", len(points)).Dur("elapsed", time.Since(funcStart)).Msg("GetDailyGrowth: TOTAL")
 
 	// Write-behind: persist timeline snapshots for historical dates (fire-and-forget).
 	// Today's snapshot is written synchronously by SyncPortfolio with live header values.
@@ -495,22 +478,11 @@ func (s *Service) GetStockTimeline(ctx context.Context, portfolioName, ticker st
 	}
 
 	// FX divisor (same logic as GetDailyGrowth Phase 4)
-	fxDiv := 1.0
 	currency := holding.OriginalCurrency
 	if currency == "" {
 		currency = holding.Currency
 	}
-	if currency == "USD" {
-		fxDiv = p.FXRate
-		if fxDiv <= 0 && s.eodhd != nil {
-			if quote, qErr := s.eodhd.GetRealTimeQuote(ctx, "AUDUSD.FOREX"); qErr == nil && quote.Close > 0 {
-				fxDiv = quote.Close
-			}
-		}
-		if fxDiv <= 0 {
-			fxDiv = 1.0
-		}
-	}
+	fxDiv := s.FXDivisor(ctx, p, currency)
 
 	// Init trade replay state
 	state := newHoldingGrowthState(eohdTicker, holding.Trades, fxDiv)
@@ -649,6 +621,7 @@ func parseTradeDate(s string) time.Time {
 // tryTimelineCache checks if persisted timeline snapshots cover the full requested range.
 // Returns converted GrowthDataPoints and true on cache hit; nil and false on miss.
 func (s *Service) tryTimelineCache(ctx context.Context, userID, name string, from, to time.Time) ([]models.GrowthDataPoint, bool) {
+	logger := s.logger.WithRequestID(ctx)
 	tl := s.storage.TimelineStore()
 	if tl == nil {
 		return nil, false
@@ -663,7 +636,7 @@ func (s *Service) tryTimelineCache(ctx context.Context, userID, name string, fro
 	// This doesn't catch mixed-version scenarios (today current, historical stale) —
 	// that's handled after GetRange below.
 	if latest.DataVersion != common.SchemaVersion {
-		s.logger.Info().
+		logger.Info().
 			Str("cached_version", latest.DataVersion).
 			Str("current_version", common.SchemaVersion).
 			Msg("Timeline cache stale: schema version mismatch, forcing rebuild")
@@ -674,7 +647,7 @@ func (s *Service) tryTimelineCache(ctx context.Context, userID, name string, fro
 	latestDate := latest.Date.Truncate(24 * time.Hour)
 	toDate := to.Truncate(24 * time.Hour)
 	if latestDate.Before(toDate) {
-		s.logger.Debug().
+		logger.Debug().
 			Str("latest", latestDate.Format("2006-01-02")).
 			Str("to", toDate.Format("2006-01-02")).
 			Msg("Timeline cache partial miss: latest < requested end")
@@ -690,7 +663,7 @@ func (s *Service) tryTimelineCache(ctx context.Context, userID, name string, fro
 	// today to current version while historical snapshots retain old field names.
 	// Stale field names cause renamed fields to deserialize as zero.
 	if snapshots[0].DataVersion != common.SchemaVersion {
-		s.logger.Info().
+		logger.Info().
 			Str("cached_version", snapshots[0].DataVersion).
 			Str("current_version", common.SchemaVersion).
 			Str("oldest_date", snapshots[0].Date.Format("2006-01-02")).
@@ -703,7 +676,7 @@ func (s *Service) tryTimelineCache(ctx context.Context, userID, name string, fro
 	// Reject the entire cache to force a fresh trade replay.
 	for _, snap := range snapshots {
 		if snap.HoldingCount > 0 && snap.EquityHoldingsValue == 0 {
-			s.logger.Warn().
+			logger.Warn().
 				Str("date", snap.Date.Format("2006-01-02")).
 				Int("holding_count", snap.HoldingCount).
 				Msg("Timeline cache corrupt: holdings with zero equity, forcing rebuild")
@@ -717,7 +690,7 @@ func (s *Service) tryTimelineCache(ctx context.Context, userID, name string, fro
 	firstSnapDate := snapshots[0].Date.Truncate(24 * time.Hour)
 	fromDate := from.Truncate(24 * time.Hour)
 	if firstSnapDate.Sub(fromDate) > 7*24*time.Hour {
-		s.logger.Debug().
+		logger.Debug().
 			Str("first_snap", firstSnapDate.Format("2006-01-02")).
 			Str("from", fromDate.Format("2006-01-02")).
 			Int("snapshots", len(snapshots)).
@@ -8,6 +8,7 @@ import (
 
 	"github.com/bobmcallan/vire/internal/models"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // Devils-advocate stress tests for populateHistoricalValues and related helpers.
@@ -24,6 +25,68 @@ func TestFindEODBarByOffset_EmptySlice(t *testing.T) {
 	assert.Nil(t, result, "empty slice should return nil for offset 0")
 }
 
+func TestFindEODBarByDate_MondayAfterHoliday(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 6, d, 0, 0, 0, 0, time.UTC) }
+	// Trading days only, newest first; Wednesday 12 June is a holiday
+	eod := []models.EODBar{
+		{Date: day(14), Close: 114}, // Fri
+		{Date: day(13), Close: 113}, // Thu
+		{Date: day(11), Close: 111}, // Tue
+		{Date: day(10), Close: 110}, // Mon
+		{Date: day(7), Close: 107},  // Fri
+		{Date: day(6), Close: 106},  // Thu
+	}
+	monday := day(17)
+
+	yesterday := findEODBarByDate(eod, monday.AddDate(0, 0, -1))
+	require.NotNil(t, yesterday)
+	assert.Equal(t, 114.0, yesterday.Close, "Sunday target should resolve to Friday's close")
+
+	lastWeek := findEODBarByDate(eod, monday.AddDate(0, 0, -7))
+	require.NotNil(t, lastWeek)
+	assert.Equal(t, 110.0, lastWeek.Close, "last week should be the prior Monday")
+
+	// The holiday shifts the fixed trading-day offset past the target date
+	byOffset := findEODBarByOffset(eod, 4)
+	require.NotNil(t, byOffset)
+	assert.Equal(t, 107.0, byOffset.Close)
+}
+
+func TestFindEODBarByDate_Bounds(t *testing.T) {
+	eod := []models.EODBar{
+		{Date: time.Date(2024, 6, 14, 15, 30, 0, 0, time.UTC), Close: 114},
+		{Date: time.Date(2024, 6, 13, 0, 0, 0, 0, time.UTC), Close: 113},
+	}
+
+	// Same calendar day matches regardless of time of day
+	bar := findEODBarByDate(eod, time.Date(2024, 6, 14, 0, 0, 0, 0, time.UTC))
+	require.NotNil(t, bar)
+	assert.Equal(t, 114.0, bar.Close)
+
+	assert.Nil(t, findEODBarByDate(eod, time.Date(2024, 6, 12, 0, 0, 0, 0, time.UTC)), "target before all bars")
+	assert.Nil(t, findEODBarByDate(nil, time.Now()))
+}
+
+func TestFindEODBarByOffset_NormalizedUnsortedInput(t *testing.T) {
+	// Incoming bars out of order with a duplicate latest date, as stored after
+	// SaveMarketData normalizes them
+	bars := models.NormalizeEOD([]models.EODBar{
+		{Date: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), Close: 102},
+		{Date: time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC), Close: 104},
+		{Date: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Close: 101},
+		{Date: time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC), Close: 104.5, AdjClose: 104.5},
+		{Date: time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC), Close: 103},
+	})
+
+	latest := findEODBarByOffset(bars, 0)
+	require.NotNil(t, latest)
+	assert.Equal(t, 104.5, latest.Close, "offset 0 should be the adjusted most recent bar")
+
+	prev := findEODBarByOffset(bars, 1)
+	require.NotNil(t, prev)
+	assert.Equal(t, 103.0, prev.Close, "offset 1 should be the previous trading day, not a duplicate")
+}
+
 func TestFindEODBarByOffset_ExactBoundary(t *testing.T) {
 	bars := []models.EODBar{
 		{Date: time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC), Close: 103},
@@ -302,9 +365,9 @@ func TestPopulateHistoricalValues_ConcurrentPortfolioReads(t *testing.T) {
 	wg.Wait()
 	// If we got here without -race detector firing, the per-goroutine copy approach is safe.
 	// The REAL concern is whether SyncPortfolio (which calls populateHistoricalValues)
-	// is protected by syncMu. GetPortfolio calls it outside the mutex.
+	// is protected by syncSem. GetPortfolio calls it outside the slot.
 	t.Log("FINDING: populateHistoricalValues is called from GetPortfolio (no mutex) " +
-		"and will be called from SyncPortfolio (protected by syncMu). " +
+		"and will be called from SyncPortfolio (protected by syncSem). " +
 		"If the SAME *Portfolio pointer is shared between concurrent GetPortfolio calls, " +
 		"there is a potential data race on holdings fields. " +
 		"MITIGATION: Each GetPortfolio call loads a fresh portfolio from storage, so in practice " +
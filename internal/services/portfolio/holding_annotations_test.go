@@ -0,0 +1,118 @@
+package portfolio
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/models"
+	"github.com/bobmcallan/vire/internal/services/holdingnotes"
+)
+
+func annotationFixture() (*Service, *holdingnotes.Service, *stubNavexaClient) {
+	holding := func(id, ticker string) *models.NavexaHolding {
+		return &models.NavexaHolding{
+			ID: id, PortfolioID: "1", Ticker: ticker, Exchange: "AU", Name: ticker,
+			Units: 10, CurrentPrice: 50, MarketValue: 500, LastUpdated: time.Now(),
+		}
+	}
+	navexa := &stubNavexaClient{
+		portfolios: []*models.NavexaPortfolio{{ID: "1", Name: "SMSF", Currency: "AUD", DateCreated: "2020-01-01"}},
+		holdings:   []*models.NavexaHolding{holding("h1", "BHP"), holding("h2", "CBA")},
+		trades: map[string][]*models.NavexaTrade{
+			"h1": {{ID: "t1", HoldingID: "h1", Type: "buy", Date: "2024-01-10", Units: 10, Price: 40}},
+			"h2": {{ID: "t2", HoldingID: "h2", Type: "buy", Date: "2024-01-10", Units: 10, Price: 45}},
+		},
+	}
+	storage := &stubStorageManager{
+		marketStore:   &stubMarketDataStorage{data: map[string]*models.MarketData{}},
+		userDataStore: newMemUserDataStore(),
+	}
+	logger := common.NewLogger("error")
+	svc := NewService(storage, nil, nil, nil, logger)
+	notes := holdingnotes.NewService(storage, logger)
+	svc.SetHoldingNoteService(notes)
+	return svc, notes, navexa
+}
+
+func findHolding(p *models.Portfolio, ticker string) *models.Holding {
+	for i := range p.Holdings {
+		if p.Holdings[i].Ticker == ticker {
+			return &p.Holdings[i]
+		}
+	}
+	return nil
+}
+
+func TestSyncPortfolio_AnnotationSurvivesResync(t *testing.T) {
+	svc, notes, navexa := annotationFixture()
+	ctx := common.WithNavexaClient(context.Background(), navexa)
+
+	if _, err := svc.SyncPortfolio(ctx, "SMSF", true); err != nil {
+		t.Fatalf("initial sync: %v", err)
+	}
+	if _, err := notes.SetHoldingNote(ctx, "SMSF", "BHP.AU", "core position"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := notes.SetHoldingTags(ctx, "SMSF", "BHP.AU", []string{"core"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// maxAge 0 forces a full sync from Navexa
+	p, err := svc.EnsureSynced(ctx, "SMSF", 0)
+	if err != nil {
+		t.Fatalf("resync: %v", err)
+	}
+	bhp := findHolding(p, "BHP")
+	if bhp == nil {
+		t.Fatal("BHP missing after resync")
+	}
+	if bhp.UserNote != "core position" || len(bhp.Tags) != 1 || bhp.Tags[0] != "core" {
+		t.Errorf("BHP annotation after resync = %q %v, want %q [core]", bhp.UserNote, bhp.Tags, "core position")
+	}
+	if cba := findHolding(p, "CBA"); cba == nil || cba.UserNote != "" || cba.Tags != nil {
+		t.Errorf("CBA should carry no annotation, got %+v", cba)
+	}
+
+	got, err := svc.GetPortfolio(ctx, "SMSF")
+	if err != nil {
+		t.Fatalf("GetPortfolio: %v", err)
+	}
+	if h := findHolding(got, "BHP"); h == nil || h.UserNote != "core position" {
+		t.Errorf("GetPortfolio BHP note = %+v", h)
+	}
+}
+
+func TestSyncPortfolio_AnnotationOutlivesRemovedPosition(t *testing.T) {
+	svc, notes, navexa := annotationFixture()
+	ctx := common.WithNavexaClient(context.Background(), navexa)
+
+	if _, err := svc.SyncPortfolio(ctx, "SMSF", true); err != nil {
+		t.Fatalf("initial sync: %v", err)
+	}
+	if _, err := notes.SetHoldingNote(ctx, "SMSF", "BHP.AU", "trimming"); err != nil {
+		t.Fatal(err)
+	}
+
+	// BHP drops out of Navexa
+	all := navexa.holdings
+	navexa.holdings = all[1:]
+	p, err := svc.EnsureSynced(ctx, "SMSF", 0)
+	if err != nil {
+		t.Fatalf("sync without BHP: %v", err)
+	}
+	if findHolding(p, "BHP") != nil {
+		t.Fatal("BHP should be gone from the portfolio")
+	}
+
+	// ...and comes back
+	navexa.holdings = all
+	p, err = svc.EnsureSynced(ctx, "SMSF", 0)
+	if err != nil {
+		t.Fatalf("sync with BHP: %v", err)
+	}
+	if bhp := findHolding(p, "BHP"); bhp == nil || bhp.UserNote != "trimming" {
+		t.Errorf("BHP note after position returned = %+v, want %q", bhp, "trimming")
+	}
+}
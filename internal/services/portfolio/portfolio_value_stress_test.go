@@ -9,6 +9,7 @@ import (
 	"github.com/bobmcallan/vire/internal/common"
 	"github.com/bobmcallan/vire/internal/interfaces"
 	"github.com/bobmcallan/vire/internal/models"
+	fxpkg "github.com/bobmcallan/vire/internal/services/fx"
 )
 
 // Adversarial stress tests for the portfolio value fix (TotalCost from trades,
@@ -67,6 +68,14 @@ func (s *stubCashFlowSvc) ClearLedger(_ context.Context, _ string) (*models.Cash
 	return nil, nil
 }
 
+func (s *stubCashFlowSvc) SetBrokerBalance(_ context.Context, _ string, _ models.BrokerBalance) (*models.CashFlowLedger, error) {
+	return nil, nil
+}
+
+func (s *stubCashFlowSvc) Reconcile(_ context.Context, _ string) (*models.CashReconciliation, error) {
+	return nil, nil
+}
+
 // =============================================================================
 // 1. totalCost > totalCash — negative availableCash
 //
@@ -804,6 +813,7 @@ func TestSyncPortfolio_TotalProceeds_FXConverted(t *testing.T) {
 
 	logger := common.NewLogger("error")
 	svc := NewService(storage, nil, eodhd, nil, logger)
+	svc.SetFXService(fxpkg.NewService(eodhd, logger))
 
 	ctx := common.WithNavexaClient(context.Background(), navexa)
 	portfolio, err := svc.SyncPortfolio(ctx, "SMSF", true)
@@ -1101,8 +1111,8 @@ func TestPopulateHistoricalValues_Stress_AvailableCashNotTotalCash(t *testing.T)
 				{Date: today.AddDate(0, 0, -2), Close: 97, AdjClose: 97}, // 2 days ago
 				{Date: today.AddDate(0, 0, -3), Close: 96, AdjClose: 96}, // 3 days ago
 				{Date: today.AddDate(0, 0, -4), Close: 96, AdjClose: 96}, // 4 days ago
-				{Date: today.AddDate(0, 0, -5), Close: 95, AdjClose: 95}, // last week (EOD[4])
-				{Date: today.AddDate(0, 0, -6), Close: 94, AdjClose: 94}, // 6 days ago
+				{Date: today.AddDate(0, 0, -7), Close: 95, AdjClose: 95}, // last week (7 days back)
+				{Date: today.AddDate(0, 0, -8), Close: 94, AdjClose: 94}, // 8 days ago
 			},
 		},
 	}
@@ -1127,7 +1137,7 @@ func TestPopulateHistoricalValues_Stress_AvailableCashNotTotalCash(t *testing.T)
 		t.Errorf("YesterdayTotal = %.2f is inflated — using TotalCash instead of AvailableCash", portfolio.PortfolioYesterdayValue)
 	}
 
-	// last_week_total = EOD[4]=95*100 + availableCash(3000) = 12500
+	// last_week_total = last week=95*100 + availableCash(3000) = 12500
 	expectedLastWeek := 95.0*100 + 3000.0
 	if !approxEqual(portfolio.PortfolioLastWeekValue, expectedLastWeek, 1.0) {
 		t.Errorf("LastWeekTotal = %.2f, want %.2f (lastweek equity + availableCash)", portfolio.PortfolioLastWeekValue, expectedLastWeek)
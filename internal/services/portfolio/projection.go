@@ -0,0 +1,376 @@
+package portfolio
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// Projection bounds: years and simulations are capped so a single request
+// cannot tie up the CPU (the inner loop runs simulations × years × holdings).
+const (
+	DefaultProjectionYears       = 5
+	MaxProjectionYears           = 30
+	DefaultProjectionSimulations = 1000
+	MaxProjectionSimulations     = 10000
+)
+
+const (
+	// tradingDaysPerYear annualises daily log-return statistics.
+	tradingDaysPerYear = 252
+	// projectionHistoryDays is the most daily returns used per holding (~3 years).
+	projectionHistoryDays = 3 * tradingDaysPerYear
+	// minProjectionHistory is the fewest daily returns needed to estimate a
+	// holding's own drift and volatility (~6 months).
+	minProjectionHistory = 126
+	// defaultProjectionVolatility is the conservative annual volatility, with
+	// zero drift, assumed for holdings with too little history.
+	defaultProjectionVolatility = 0.30
+	// projectionPriorDrift is the long-run annual log drift (about 6% a year)
+	// that a holding's sample drift is shrunk towards. A few years of returns
+	// say little about the mean, and compounding the raw sample mean for up
+	// to MaxProjectionYears would project a strong run indefinitely.
+	projectionPriorDrift = 0.06
+	// projectionPriorYears weights the prior as that many years of history:
+	// three years of returns keep 3/13 of their sample drift.
+	projectionPriorYears = 10.0
+	// maxProjectionDrift caps the shrunk drift in either direction.
+	maxProjectionDrift = 0.15
+)
+
+// ProjectValue runs a Monte Carlo simulation of the portfolio's value over
+// the next years, returning p10/p50/p90 bands for each year. Each open
+// holding follows geometric Brownian motion with volatility estimated from
+// up to three years of its EOD closes and drift shrunk from their mean
+// towards a long-run prior. Yearly shocks are
+// correlated across holdings by the correlation of their daily returns, and
+// holdings of the same ticker in several accounts are merged into one. Zero
+// years or simulations use the defaults; values above the maximums are
+// capped with a warning.
+func (s *Service) ProjectValue(ctx context.Context, portfolioName string, years int, simulations int) (*models.ProjectionResult, error) {
+	var warnings []string
+	if years <= 0 {
+		years = DefaultProjectionYears
+	}
+	if years > MaxProjectionYears {
+		warnings = append(warnings, fmt.Sprintf("years capped at %d", MaxProjectionYears))
+		years = MaxProjectionYears
+	}
+	if simulations <= 0 {
+		simulations = DefaultProjectionSimulations
+	}
+	if simulations > MaxProjectionSimulations {
+		warnings = append(warnings, fmt.Sprintf("simulations capped at %d", MaxProjectionSimulations))
+		simulations = MaxProjectionSimulations
+	}
+
+	portfolio, err := s.getPortfolioRecord(ctx, portfolioName)
+	if err != nil {
+		return nil, err
+	}
+
+	active, _ := filterClosedPositions(portfolio.Holdings)
+	tickers := make([]string, 0, len(active))
+	for _, h := range active {
+		tickers = append(tickers, h.EODHDTicker())
+	}
+	allMarketData, err := s.storage.MarketDataStorage().GetMarketDataBatch(ctx, tickers)
+	if err != nil {
+		s.logger.WithRequestID(ctx).Warn().Err(err).Msg("Failed to batch load market data for projection")
+	}
+	eodByTicker := make(map[string][]models.EODBar, len(allMarketData))
+	for _, md := range allMarketData {
+		eodByTicker[md.Ticker] = md.EOD
+	}
+
+	// Merge holdings of one ticker across accounts: they move together
+	valueByTicker := make(map[string]float64)
+	displayTicker := make(map[string]string)
+	var eodTickers []string
+	var equity float64
+	for _, h := range active {
+		if h.MarketValue <= 0 {
+			continue
+		}
+		t := h.EODHDTicker()
+		if _, seen := valueByTicker[t]; !seen {
+			eodTickers = append(eodTickers, t)
+			displayTicker[t] = h.Ticker
+		}
+		valueByTicker[t] += h.MarketValue
+		equity += h.MarketValue
+	}
+	sort.Slice(eodTickers, func(i, j int) bool { return displayTicker[eodTickers[i]] < displayTicker[eodTickers[j]] })
+
+	holdings := make([]models.ProjectionHolding, 0, len(eodTickers))
+	histories := make([][]models.EODBar, 0, len(eodTickers))
+	for _, t := range eodTickers {
+		ph := estimateReturnModel(eodByTicker[t])
+		ph.Ticker = displayTicker[t]
+		ph.MarketValue = valueByTicker[t]
+		if ph.Default {
+			warnings = append(warnings, fmt.Sprintf("%s: %d days of history, assuming %.0f%% volatility and no drift",
+				ph.Ticker, ph.HistoryDays, defaultProjectionVolatility*100))
+		}
+		holdings = append(holdings, ph)
+		histories = append(histories, eodByTicker[t])
+	}
+
+	flat := math.Max(0, portfolio.PortfolioValue-equity)
+	rng := rand.New(rand.NewPCG(uint64(time.Now().UnixNano()), 0))
+
+	return &models.ProjectionResult{
+		PortfolioName: portfolioName,
+		StartValue:    equity + flat,
+		FlatValue:     flat,
+		Years:         years,
+		Simulations:   simulations,
+		Bands:         simulateProjection(holdings, returnCorrelations(histories), flat, years, simulations, rng),
+		Holdings:      holdings,
+		Assumptions:   projectionAssumptions(),
+		Warnings:      warnings,
+	}, nil
+}
+
+// projectionAssumptions describes the return model for the tool output.
+func projectionAssumptions() []string {
+	return []string{
+		fmt.Sprintf("volatility: annualised standard deviation of daily log returns over up to %d years of EOD closes",
+			projectionHistoryDays/tradingDaysPerYear),
+		fmt.Sprintf("drift: each holding's historical mean log return (sample_drift) shrunk towards a %.0f%%/yr long-run prior, weighting the prior as %.0f years of history, then capped at ±%.0f%%/yr",
+			projectionPriorDrift*100, projectionPriorYears, maxProjectionDrift*100),
+		fmt.Sprintf("holdings with under %d days of history: %.0f%% volatility and no drift",
+			minProjectionHistory, defaultProjectionVolatility*100),
+	}
+}
+
+// estimateReturnModel annualises the mean and standard deviation of daily
+// log returns over the latest projectionHistoryDays bars (most recent
+// first). The sample drift is shrunk towards projectionPriorDrift by the
+// length of the history (shrinkDrift). With fewer than minProjectionHistory
+// returns it falls back to zero drift and defaultProjectionVolatility.
+func estimateReturnModel(bars []models.EODBar) models.ProjectionHolding {
+	_, returns := dailyLogReturns(bars)
+	if len(returns) < minProjectionHistory {
+		return models.ProjectionHolding{Volatility: defaultProjectionVolatility, HistoryDays: len(returns), Default: true}
+	}
+
+	var mean float64
+	for _, r := range returns {
+		mean += r
+	}
+	mean /= float64(len(returns))
+	var variance float64
+	for _, r := range returns {
+		variance += (r - mean) * (r - mean)
+	}
+	variance /= float64(len(returns) - 1)
+
+	sample := mean * tradingDaysPerYear
+	return models.ProjectionHolding{
+		Drift:       shrinkDrift(sample, len(returns)),
+		SampleDrift: sample,
+		Volatility:  math.Sqrt(variance * tradingDaysPerYear),
+		HistoryDays: len(returns),
+	}
+}
+
+// shrinkDrift blends a sample drift drawn from days daily returns with
+// projectionPriorDrift, weighting each by its years (the prior counting as
+// projectionPriorYears), and caps the result at ±maxProjectionDrift.
+func shrinkDrift(sample float64, days int) float64 {
+	years := float64(days) / tradingDaysPerYear
+	w := years / (years + projectionPriorYears)
+	drift := w*sample + (1-w)*projectionPriorDrift
+	return math.Max(-maxProjectionDrift, math.Min(maxProjectionDrift, drift))
+}
+
+// dailyLogReturns returns the daily log returns over the latest
+// projectionHistoryDays bars (most recent first) and the date each ends on.
+func dailyLogReturns(bars []models.EODBar) ([]time.Time, []float64) {
+	var dates []time.Time
+	var returns []float64
+	for i := 0; i+1 < len(bars) && len(returns) < projectionHistoryDays; i++ {
+		prev, cur := bars[i+1].Close, bars[i].Close
+		if prev <= 0 || cur <= 0 {
+			continue
+		}
+		dates = append(dates, bars[i].Date.Truncate(24*time.Hour))
+		returns = append(returns, math.Log(cur/prev))
+	}
+	return dates, returns
+}
+
+// returnCorrelations returns the correlation matrix of the holdings' daily
+// log returns, paired by date. A pair with fewer than minProjectionHistory
+// common days, or a series with no variance, is taken as uncorrelated.
+func returnCorrelations(histories [][]models.EODBar) [][]float64 {
+	byDate := make([]map[time.Time]float64, len(histories))
+	for i, bars := range histories {
+		dates, returns := dailyLogReturns(bars)
+		byDate[i] = make(map[time.Time]float64, len(dates))
+		for j, d := range dates {
+			byDate[i][d] = returns[j]
+		}
+	}
+
+	corr := make([][]float64, len(histories))
+	for i := range corr {
+		corr[i] = make([]float64, len(histories))
+		corr[i][i] = 1
+	}
+	for i := range histories {
+		for j := i + 1; j < len(histories); j++ {
+			var xs, ys []float64
+			for d, x := range byDate[i] {
+				if y, ok := byDate[j][d]; ok {
+					xs = append(xs, x)
+					ys = append(ys, y)
+				}
+			}
+			if len(xs) < minProjectionHistory {
+				continue
+			}
+			corr[i][j] = pearson(xs, ys)
+			corr[j][i] = corr[i][j]
+		}
+	}
+	return corr
+}
+
+// pearson returns the correlation of xs and ys, or 0 when either has no
+// variance.
+func pearson(xs, ys []float64) float64 {
+	var mx, my float64
+	for i := range xs {
+		mx += xs[i]
+		my += ys[i]
+	}
+	mx /= float64(len(xs))
+	my /= float64(len(ys))
+	var sxy, sxx, syy float64
+	for i := range xs {
+		dx, dy := xs[i]-mx, ys[i]-my
+		sxy += dx * dy
+		sxx += dx * dx
+		syy += dy * dy
+	}
+	if sxx == 0 || syy == 0 {
+		return 0
+	}
+	return math.Max(-1, math.Min(1, sxy/math.Sqrt(sxx*syy)))
+}
+
+// choleskyFactor returns a lower-triangular L with L × Lᵀ = corr, so L × Z
+// turns independent normal draws Z into correlated ones. Pairwise estimates
+// need not form a valid correlation matrix; the off-diagonal terms are then
+// shrunk towards zero until one factors, ending at independence.
+func choleskyFactor(corr [][]float64) [][]float64 {
+	n := len(corr)
+	const tolerance = 1e-9
+	for shrink := 1.0; ; shrink *= 0.9 {
+		if shrink < 0.01 {
+			shrink = 0
+		}
+		l := make([][]float64, n)
+		ok := true
+		for i := 0; i < n && ok; i++ {
+			l[i] = make([]float64, n)
+			for j := 0; j <= i; j++ {
+				sum := 1.0
+				if i != j {
+					sum = corr[i][j] * shrink
+				}
+				for k := 0; k < j; k++ {
+					sum -= l[i][k] * l[j][k]
+				}
+				switch {
+				case i == j && sum < -tolerance:
+					ok = false
+				case i == j:
+					// Zero for a perfectly correlated holding: it is driven
+					// wholly by the ones before it
+					l[i][i] = math.Sqrt(math.Max(sum, 0))
+				case l[j][j] > 0:
+					l[i][j] = sum / l[j][j]
+				}
+			}
+		}
+		if ok || shrink == 0 {
+			return l
+		}
+	}
+}
+
+// simulateProjection steps each holding a year at a time as
+// value × exp(drift + vol × Z) over simulations paths, adds the flat value,
+// and returns the 10th, 50th and 90th percentile totals per year. Drift is
+// the mean log return, which already carries the volatility drag, so the
+// median path is value × exp(drift × year) whatever the volatility. The
+// holdings' Z are drawn with correlation matrix corr; nil draws them
+// independently.
+func simulateProjection(holdings []models.ProjectionHolding, corr [][]float64, flat float64, years, simulations int, rng *rand.Rand) []models.ProjectionBand {
+	totals := make([][]float64, years)
+	for y := range totals {
+		totals[y] = make([]float64, simulations)
+	}
+
+	var factor [][]float64
+	if corr != nil {
+		factor = choleskyFactor(corr)
+	}
+	values := make([]float64, len(holdings))
+	draws := make([]float64, len(holdings))
+	for sim := 0; sim < simulations; sim++ {
+		for i, h := range holdings {
+			values[i] = h.MarketValue
+		}
+		for y := 0; y < years; y++ {
+			for i := range draws {
+				draws[i] = rng.NormFloat64()
+			}
+			total := flat
+			for i, h := range holdings {
+				z := draws[i]
+				if factor != nil {
+					z = 0
+					for k := 0; k <= i; k++ {
+						z += factor[i][k] * draws[k]
+					}
+				}
+				values[i] *= math.Exp(h.Drift + h.Volatility*z)
+				total += values[i]
+			}
+			totals[y][sim] = total
+		}
+	}
+
+	bands := make([]models.ProjectionBand, years)
+	for y, vals := range totals {
+		sort.Float64s(vals)
+		bands[y] = models.ProjectionBand{
+			Year: y + 1,
+			P10:  percentile(vals, 10),
+			P50:  percentile(vals, 50),
+			P90:  percentile(vals, 90),
+		}
+	}
+	return bands
+}
+
+// percentile returns the p-th percentile of sorted values by linear
+// interpolation between closest ranks.
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := p / 100 * float64(len(sorted)-1)
+	lo := int(math.Floor(rank))
+	hi := int(math.Ceil(rank))
+	return sorted[lo] + (sorted[hi]-sorted[lo])*(rank-float64(lo))
+}
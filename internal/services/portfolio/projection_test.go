@@ -0,0 +1,164 @@
+package portfolio
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+func TestSimulateProjection_BandsOrdered(t *testing.T) {
+	holdings := []models.ProjectionHolding{
+		{Ticker: "BHP", MarketValue: 60000, Drift: 0.07, Volatility: 0.25},
+		{Ticker: "CBA", MarketValue: 40000, Drift: 0.05, Volatility: 0.18},
+	}
+	rng := rand.New(rand.NewPCG(1, 2))
+
+	bands := simulateProjection(holdings, nil, 5000, 5, 2000, rng)
+	if len(bands) != 5 {
+		t.Fatalf("got %d bands, want 5", len(bands))
+	}
+	for _, b := range bands {
+		if !(b.P10 < b.P50 && b.P50 < b.P90) {
+			t.Errorf("year %d: p10 %.0f, p50 %.0f, p90 %.0f not increasing", b.Year, b.P10, b.P50, b.P90)
+		}
+	}
+	// Uncertainty widens with the horizon
+	if first, last := bands[0].P90-bands[0].P10, bands[4].P90-bands[4].P10; last <= first {
+		t.Errorf("year 5 spread %.0f should exceed year 1 spread %.0f", last, first)
+	}
+}
+
+func TestSimulateProjection_ZeroVolatilityFollowsDrift(t *testing.T) {
+	holdings := []models.ProjectionHolding{
+		{Ticker: "AAA", MarketValue: 10000, Drift: 0.08},
+		{Ticker: "BBB", MarketValue: 5000, Drift: -0.02},
+	}
+	rng := rand.New(rand.NewPCG(1, 2))
+
+	bands := simulateProjection(holdings, nil, 1000, 3, 50, rng)
+	for _, b := range bands {
+		y := float64(b.Year)
+		want := 1000 + 10000*math.Exp(0.08*y) + 5000*math.Exp(-0.02*y)
+		for name, got := range map[string]float64{"p10": b.P10, "p50": b.P50, "p90": b.P90} {
+			if math.Abs(got-want) > 1e-6 {
+				t.Errorf("year %d %s = %.6f, want %.6f", b.Year, name, got, want)
+			}
+		}
+	}
+}
+
+func TestSimulateProjection_HighVolatilityMedianFollowsDrift(t *testing.T) {
+	// Drift is the mean log return, so the median path is exp(drift × t)
+	// and volatility only widens the bands around it
+	holdings := []models.ProjectionHolding{
+		{Ticker: "VOL", MarketValue: 10000, Drift: 0.05, Volatility: 0.6},
+	}
+	rng := rand.New(rand.NewPCG(3, 4))
+
+	bands := simulateProjection(holdings, nil, 0, 5, 4000, rng)
+	for _, b := range bands {
+		want := 10000 * math.Exp(0.05*float64(b.Year))
+		if math.Abs(b.P50-want)/want > 0.1 {
+			t.Errorf("year %d p50 = %.0f, want within 10%% of %.0f", b.Year, b.P50, want)
+		}
+	}
+}
+
+func TestEstimateReturnModel_ShortHistoryUsesDefault(t *testing.T) {
+	end := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+	bars := func(n int, growth float64) []models.EODBar {
+		eod := make([]models.EODBar, n)
+		for i := range eod {
+			eod[i] = models.EODBar{Date: end.AddDate(0, 0, -i), Close: 100 * math.Exp(-growth*float64(i))}
+		}
+		return eod
+	}
+
+	short := estimateReturnModel(bars(30, 0.001))
+	if !short.Default || short.Volatility != defaultProjectionVolatility || short.Drift != 0 {
+		t.Errorf("short history = %+v, want the default assumption", short)
+	}
+
+	// Steady 0.1% daily log growth: sample drift 25.2%/yr, no volatility.
+	// 299 returns (~1.19 years) keep 1.19/11.19 of it; the rest is the prior.
+	long := estimateReturnModel(bars(300, 0.001))
+	years := 299.0 / tradingDaysPerYear
+	w := years / (years + projectionPriorYears)
+	wantDrift := w*0.252 + (1-w)*projectionPriorDrift
+	if long.Default || math.Abs(long.SampleDrift-0.252) > 1e-9 || math.Abs(long.Drift-wantDrift) > 1e-9 || long.Volatility > 1e-9 {
+		t.Errorf("long history = %+v, want sample drift 0.252, drift %.4f and zero volatility", long, wantDrift)
+	}
+}
+
+func TestShrinkDrift(t *testing.T) {
+	tests := []struct {
+		name   string
+		sample float64
+		days   int
+		want   float64
+	}{
+		{"prior sample is unchanged", projectionPriorDrift, 756, projectionPriorDrift},
+		{"three years keep 3/13 of the sample", 0.19, 756, 0.06 + 0.13*3/13},
+		{"strong run is capped", 2.0, 756, maxProjectionDrift},
+		{"crash is capped", -2.0, 756, -maxProjectionDrift},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := shrinkDrift(tt.sample, tt.days); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("shrinkDrift(%.2f, %d) = %.4f, want %.4f", tt.sample, tt.days, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSimulateProjection_CorrelatedHoldingsWidenBand(t *testing.T) {
+	holdings := []models.ProjectionHolding{
+		{Ticker: "AAA", MarketValue: 10000, Drift: 0.05, Volatility: 0.3},
+		{Ticker: "BBB", MarketValue: 10000, Drift: 0.05, Volatility: 0.3},
+		{Ticker: "CCC", MarketValue: 10000, Drift: 0.05, Volatility: 0.3},
+	}
+	ones := [][]float64{{1, 1, 1}, {1, 1, 1}, {1, 1, 1}}
+	identity := [][]float64{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}
+
+	independent := simulateProjection(holdings, identity, 0, 1, 4000, rand.New(rand.NewPCG(5, 6)))[0]
+	correlated := simulateProjection(holdings, ones, 0, 1, 4000, rand.New(rand.NewPCG(5, 6)))[0]
+	if spreadI, spreadC := independent.P90-independent.P10, correlated.P90-correlated.P10; spreadC < 1.5*spreadI {
+		t.Errorf("correlated spread %.0f, independent %.0f; want perfect correlation to widen the band by about √3", spreadC, spreadI)
+	}
+
+	// Perfectly correlated holdings move as one: the same as a single
+	// holding of their combined value
+	single := simulateProjection([]models.ProjectionHolding{{MarketValue: 30000, Drift: 0.05, Volatility: 0.3}}, nil, 0, 1, 4000, rand.New(rand.NewPCG(5, 6)))[0]
+	if math.Abs(correlated.P90-single.P90)/single.P90 > 0.05 {
+		t.Errorf("correlated p90 %.0f, single-holding p90 %.0f; want them within 5%%", correlated.P90, single.P90)
+	}
+}
+
+func TestReturnCorrelations_PairsByDate(t *testing.T) {
+	end := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+	series := func(n int, sign float64) []models.EODBar {
+		eod := make([]models.EODBar, n)
+		for i := range eod {
+			eod[i] = models.EODBar{Date: end.AddDate(0, 0, -i), Close: 100 * math.Exp(sign*0.01*float64(i%5))}
+		}
+		return eod
+	}
+
+	corr := returnCorrelations([][]models.EODBar{series(300, 1), series(300, 1), series(300, -1), series(30, 1)})
+	for _, tt := range []struct {
+		i, j int
+		want float64
+	}{
+		{0, 1, 1},  // same moves
+		{0, 2, -1}, // mirrored moves
+		{0, 3, 0},  // too little common history
+		{2, 2, 1},
+	} {
+		if math.Abs(corr[tt.i][tt.j]-tt.want) > 1e-9 || corr[tt.i][tt.j] != corr[tt.j][tt.i] {
+			t.Errorf("corr[%d][%d] = %.4f (transposed %.4f), want %.0f", tt.i, tt.j, corr[tt.i][tt.j], corr[tt.j][tt.i], tt.want)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package portfolio
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// RealizedGainTimeline replays each holding's trades in date order and
+// reports the cumulative realized gain after every date it changed. Realized
+// gain to a date is computed as in applyTradeMetrics — proceeds less the cost
+// of the units sold, under the strategy's cost basis method — over the trades
+// up to that date, so a re-entry after a full exit leaves earlier gains in
+// place and the final total matches the holdings' realized_return.
+func (s *Service) RealizedGainTimeline(ctx context.Context, portfolioName string) (*models.RealizedGainTimeline, error) {
+	portfolio, err := s.getPortfolioRecord(ctx, portfolioName)
+	if err != nil {
+		return nil, err
+	}
+
+	method := s.costBasisMethod(ctx, portfolioName)
+	timeline := &models.RealizedGainTimeline{
+		PortfolioName:   portfolioName,
+		CostBasisMethod: method,
+		Points:          []models.RealizedGainPoint{},
+	}
+
+	byDate := make(map[time.Time][]models.RealizedGainComponent)
+	// Holdings that share a ticker carry the same merged trade list, so
+	// replay each ticker once.
+	seen := make(map[string]bool)
+	for i := range portfolio.Holdings {
+		h := &portfolio.Holdings[i]
+		ticker := h.EODHDTicker()
+		if seen[ticker] || len(h.Trades) == 0 {
+			continue
+		}
+		seen[ticker] = true
+
+		dated := make([]*models.NavexaTrade, 0, len(h.Trades))
+		for _, t := range h.Trades {
+			if parseTradeDate(t.Date).IsZero() {
+				timeline.Warnings = append(timeline.Warnings,
+					fmt.Sprintf("%s: %s trade of %.2f units has no date and was excluded", ticker, strings.ToLower(t.Type), t.Units))
+				continue
+			}
+			dated = append(dated, t)
+		}
+
+		fxDiv := portfolioFXDiv(portfolio, h)
+		for _, c := range realizedByDate(dated, method) {
+			byDate[c.date] = append(byDate[c.date], models.RealizedGainComponent{
+				Ticker:   h.Ticker,
+				Realized: c.realized / fxDiv,
+			})
+		}
+	}
+
+	dates := make([]time.Time, 0, len(byDate))
+	for d := range byDate {
+		dates = append(dates, d)
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+
+	var cumulative float64
+	for _, d := range dates {
+		components := byDate[d]
+		sort.Slice(components, func(i, j int) bool { return components[i].Ticker < components[j].Ticker })
+		var realized float64
+		for _, c := range components {
+			realized += c.Realized
+		}
+		cumulative += realized
+		timeline.Points = append(timeline.Points, models.RealizedGainPoint{
+			Date:       d,
+			Realized:   realized,
+			Cumulative: cumulative,
+			Components: components,
+		})
+	}
+	timeline.TotalRealized = cumulative
+	return timeline, nil
+}
+
+// realizedChange is the realized gain booked by one holding on a date.
+type realizedChange struct {
+	date     time.Time
+	realized float64
+}
+
+// realizedByDate returns the change in realized gain on each trade day
+// where it moved, oldest first, for trades that all carry a date. Trades on
+// the same day are applied together.
+func realizedByDate(trades []*models.NavexaTrade, method models.CostBasisMethod) []realizedChange {
+	sorted := make([]*models.NavexaTrade, len(trades))
+	copy(sorted, trades)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Date < sorted[j].Date
+	})
+
+	var changes []realizedChange
+	var prev float64
+	for i := 0; i < len(sorted); {
+		date := parseTradeDate(sorted[i].Date).Truncate(24 * time.Hour)
+		j := i + 1
+		for j < len(sorted) && parseTradeDate(sorted[j].Date).Truncate(24*time.Hour).Equal(date) {
+			j++
+		}
+		realized := realizedThrough(sorted[:j], method)
+		if delta := realized - prev; math.Abs(delta) > 1e-6 {
+			changes = append(changes, realizedChange{date: date, realized: delta})
+		}
+		prev = realized
+		i = j
+	}
+	return changes
+}
+
+// realizedThrough is the realized gain on trades: proceeds less the invested
+// capital not still held as cost base, matching applyTradeMetrics.
+func realizedThrough(trades []*models.NavexaTrade, method models.CostBasisMethod) float64 {
+	_, remainingCost, _ := calculateCostBasisFromTrades(trades, method)
+	invested, proceeds, _ := calculateGainLossFromTrades(trades, 0)
+	return proceeds - (invested - remainingCost)
+}
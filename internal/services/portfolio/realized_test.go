@@ -0,0 +1,151 @@
+package portfolio
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+func newRealizedTestService(t *testing.T, portfolio *models.Portfolio) *Service {
+	t.Helper()
+	storage := &stubStorageManager{
+		marketStore:   &stubMarketDataStorage{data: map[string]*models.MarketData{}},
+		userDataStore: newMemUserDataStore(),
+	}
+	svc := NewService(storage, nil, nil, nil, common.NewLogger("error"))
+	if err := svc.savePortfolioRecord(context.Background(), portfolio); err != nil {
+		t.Fatalf("seed portfolio: %v", err)
+	}
+	return svc
+}
+
+func TestRealizedGainTimeline_PartialSellAndReEntry(t *testing.T) {
+	// SKS scenario: three partial sells exit the first position at a loss,
+	// then two re-entry buys open a new one.
+	trades := []*models.NavexaTrade{
+		{Type: "buy", Date: "2024-01-15", Units: 4925, Price: 4.0248},
+		{Type: "sell", Date: "2024-03-01", Units: 1333, Price: 3.7627},
+		{Type: "sell", Date: "2024-04-10", Units: 819, Price: 3.680},
+		{Type: "sell", Date: "2024-05-20", Units: 2773, Price: 3.4508},
+		{Type: "buy", Date: "2024-07-01", Units: 2511, Price: 3.980},
+		{Type: "buy", Date: "2024-08-05", Units: 2456, Price: 4.070},
+	}
+	svc := newRealizedTestService(t, &models.Portfolio{
+		Name:     "SMSF",
+		Holdings: []models.Holding{{Ticker: "SKS", Exchange: "AU", Units: 4967, Trades: trades}},
+	})
+
+	timeline, err := svc.RealizedGainTimeline(context.Background(), "SMSF")
+	if err != nil {
+		t.Fatalf("RealizedGainTimeline failed: %v", err)
+	}
+	if timeline.CostBasisMethod != models.CostBasisAverage {
+		t.Errorf("CostBasisMethod = %q, want average", timeline.CostBasisMethod)
+	}
+
+	// Re-entry buys realize nothing, so only the three sell dates appear
+	want := []struct {
+		date     string
+		realized float64
+	}{
+		{"2024-03-01", 1333 * (3.7627 - 4.0248)},
+		{"2024-04-10", 819 * (3.680 - 4.0248)},
+		{"2024-05-20", 2773 * (3.4508 - 4.0248)},
+	}
+	if len(timeline.Points) != len(want) {
+		t.Fatalf("expected %d points, got %d: %+v", len(want), len(timeline.Points), timeline.Points)
+	}
+
+	var cumulative float64
+	for i, w := range want {
+		p := timeline.Points[i]
+		cumulative += w.realized
+		if got := p.Date.Format("2006-01-02"); got != w.date {
+			t.Errorf("point %d date = %s, want %s", i, got, w.date)
+		}
+		if !approxEqual(p.Realized, w.realized, 0.01) {
+			t.Errorf("point %d realized = %.2f, want %.2f", i, p.Realized, w.realized)
+		}
+		if !approxEqual(p.Cumulative, cumulative, 0.01) {
+			t.Errorf("point %d cumulative = %.2f, want %.2f", i, p.Cumulative, cumulative)
+		}
+		if len(p.Components) != 1 || p.Components[0].Ticker != "SKS" {
+			t.Errorf("point %d components = %+v, want one SKS component", i, p.Components)
+		}
+		// Every sell was at a loss, so the running total only falls
+		if i > 0 && p.Cumulative >= timeline.Points[i-1].Cumulative {
+			t.Errorf("point %d cumulative %.2f did not fall from %.2f", i, p.Cumulative, timeline.Points[i-1].Cumulative)
+		}
+	}
+
+	// The re-buys must not reset the first position's realized loss, and the
+	// total matches the holding-level realized figure.
+	invested, proceeds, _ := calculateGainLossFromTrades(trades, 0)
+	_, remainingCost, _ := calculateAvgCostFromTrades(trades)
+	if wantTotal := proceeds - (invested - remainingCost); !approxEqual(timeline.TotalRealized, wantTotal, 0.01) {
+		t.Errorf("TotalRealized = %.2f, want %.2f", timeline.TotalRealized, wantTotal)
+	}
+	if !approxEqual(timeline.TotalRealized, cumulative, 0.01) {
+		t.Errorf("TotalRealized = %.2f, want %.2f", timeline.TotalRealized, cumulative)
+	}
+}
+
+func TestRealizedGainTimeline_MergesHoldingsAndConvertsFX(t *testing.T) {
+	bhp := []*models.NavexaTrade{
+		{Type: "buy", Date: "2024-01-10", Units: 100, Price: 10},
+		{Type: "sell", Date: "2024-02-01", Units: 100, Price: 12}, // +200, full exit
+		{Type: "buy", Date: "2024-03-01", Units: 50, Price: 20},
+		{Type: "sell", Date: "2024-04-01", Units: 50, Price: 21}, // +50 on the re-entry
+	}
+	aapl := []*models.NavexaTrade{
+		{Type: "buy", Date: "2024-01-05", Units: 10, Price: 100},
+		{Type: "sell", Date: "2024-02-01T10:30:00", Units: 5, Price: 130}, // +150 USD
+		{Type: "buy", Units: 1, Price: 100},                               // no date
+	}
+	svc := newRealizedTestService(t, &models.Portfolio{
+		Name:    "SMSF",
+		FXRates: map[string]float64{"USD": 0.5},
+		Holdings: []models.Holding{
+			{Ticker: "BHP", Exchange: "AU", Units: 0, Trades: bhp},
+			{Ticker: "BHP", Exchange: "AU", Units: 0, Trades: bhp}, // second account, same merged trades
+			{Ticker: "AAPL", Exchange: "US", Units: 5, OriginalCurrency: "USD", Trades: aapl},
+		},
+	})
+
+	timeline, err := svc.RealizedGainTimeline(context.Background(), "SMSF")
+	if err != nil {
+		t.Fatalf("RealizedGainTimeline failed: %v", err)
+	}
+	if len(timeline.Points) != 2 {
+		t.Fatalf("expected 2 points, got %d: %+v", len(timeline.Points), timeline.Points)
+	}
+
+	// 2024-02-01: BHP +200 and AAPL +150 USD = +300 AUD, components by ticker
+	feb := timeline.Points[0]
+	if !feb.Date.Equal(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("first point date = %s, want 2024-02-01", feb.Date)
+	}
+	if len(feb.Components) != 2 || feb.Components[0].Ticker != "AAPL" || feb.Components[1].Ticker != "BHP" {
+		t.Fatalf("first point components = %+v, want AAPL then BHP", feb.Components)
+	}
+	if !approxEqual(feb.Components[0].Realized, 300, 0.01) {
+		t.Errorf("AAPL realized = %.2f, want 300 (150 USD / 0.5)", feb.Components[0].Realized)
+	}
+	if !approxEqual(feb.Realized, 500, 0.01) {
+		t.Errorf("first point realized = %.2f, want 500", feb.Realized)
+	}
+
+	apr := timeline.Points[1]
+	if !approxEqual(apr.Realized, 50, 0.01) || !approxEqual(apr.Cumulative, 550, 0.01) {
+		t.Errorf("second point = %.2f/%.2f, want 50/550", apr.Realized, apr.Cumulative)
+	}
+	if !approxEqual(timeline.TotalRealized, 550, 0.01) {
+		t.Errorf("TotalRealized = %.2f, want 550", timeline.TotalRealized)
+	}
+	if len(timeline.Warnings) != 1 {
+		t.Errorf("expected 1 warning for the undated trade, got %v", timeline.Warnings)
+	}
+}
@@ -0,0 +1,147 @@
+package portfolio
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// defaultRebalanceDriftPct is the weight drift, in percentage points,
+// tolerated before a rebalance trade is suggested.
+const defaultRebalanceDriftPct = 2.0
+
+// RebalanceSuggestions compares each holding's weight with the strategy's
+// target_weights and returns the buys and sells that bring it back to target.
+// Holdings within the drift tolerance are left alone. Held tickers with no
+// target are sell-to-zero candidates. Fees come from the configured fee
+// model, and the plan flags when the net cash required exceeds the cash
+// available.
+func (s *Service) RebalanceSuggestions(ctx context.Context, portfolioName string) (*models.RebalancePlan, error) {
+	portfolio, err := s.getPortfolioRecord(ctx, portfolioName)
+	if err != nil {
+		return nil, fmt.Errorf("portfolio '%s' not found: %w", portfolioName, err)
+	}
+	strategy, err := s.getStrategyRecord(ctx, portfolioName)
+	if err != nil || len(strategy.TargetWeights) == 0 {
+		return nil, fmt.Errorf("no target_weights set in the strategy for '%s'", portfolioName)
+	}
+	return buildRebalancePlan(portfolio, strategy.TargetWeights, s.rebalanceDriftPct, s.feeModel), nil
+}
+
+// buildRebalancePlan is the pure computation behind RebalanceSuggestions.
+// Weights are a share of portfolio value; targets summing to less than 100%
+// leave the remainder in cash.
+func buildRebalancePlan(p *models.Portfolio, targets map[string]float64, tolerance float64, fees models.FeeModel) *models.RebalancePlan {
+	plan := &models.RebalancePlan{
+		PortfolioName:  p.Name,
+		PortfolioValue: p.PortfolioValue,
+		TolerancePct:   tolerance,
+		AvailableCash:  p.CapitalAvailable,
+		Suggestions:    []models.RebalanceSuggestion{},
+	}
+
+	// Normalise target keys so "BHP" and "BHP.AU" both match a holding
+	want := make(map[string]float64, len(targets))
+	for t, pct := range targets {
+		want[strings.ToUpper(strings.TrimSpace(t))] = pct
+		plan.TargetsTotalPct += pct
+	}
+	if math.Abs(plan.TargetsTotalPct-100) > 0.01 {
+		plan.Warnings = append(plan.Warnings,
+			fmt.Sprintf("target weights sum to %.1f%%, not 100%%; the remainder is treated as cash", plan.TargetsTotalPct))
+	}
+	if p.PortfolioValue <= 0 {
+		plan.Warnings = append(plan.Warnings, "portfolio value is zero; nothing to rebalance")
+		return plan
+	}
+
+	matched := make(map[string]bool, len(want))
+	for _, h := range p.Holdings {
+		if h.Units <= 0 {
+			continue
+		}
+		target, key, ok := targetFor(want, h)
+		if ok {
+			matched[key] = true
+		}
+		s := rebalanceTrade(h.Ticker, h.WeightPct, target, h.CurrentPrice, p.PortfolioValue, tolerance)
+		if s == nil {
+			continue
+		}
+		s.NoTarget = !ok
+		plan.Suggestions = append(plan.Suggestions, *s)
+	}
+
+	// Targets for tickers not yet held are buys from zero
+	keys := make([]string, 0, len(want))
+	for k := range want {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if matched[k] {
+			continue
+		}
+		if s := rebalanceTrade(k, 0, want[k], 0, p.PortfolioValue, tolerance); s != nil {
+			plan.Suggestions = append(plan.Suggestions, *s)
+		}
+	}
+
+	for i := range plan.Suggestions {
+		s := &plan.Suggestions[i]
+		s.Fee = fees.Fee(s.Amount)
+		plan.TotalFees += s.Fee
+		if s.Action == "buy" {
+			plan.TotalBuys += s.Amount
+		} else {
+			plan.TotalSells += s.Amount
+		}
+	}
+	plan.NetCashRequired = plan.TotalBuys + plan.TotalFees - plan.TotalSells
+	plan.CashAfter = plan.AvailableCash - plan.NetCashRequired
+	plan.InsufficientCash = plan.NetCashRequired > 0 && plan.CashAfter < -0.005
+	if plan.InsufficientCash {
+		plan.Warnings = append(plan.Warnings,
+			fmt.Sprintf("rebalance needs %.2f more cash than the %.2f available", -plan.CashAfter, plan.AvailableCash))
+	}
+	return plan
+}
+
+// targetFor looks up a holding's target by ticker or EODHD ticker.
+func targetFor(want map[string]float64, h models.Holding) (float64, string, bool) {
+	for _, k := range []string{strings.ToUpper(h.Ticker), strings.ToUpper(h.EODHDTicker())} {
+		if pct, ok := want[k]; ok {
+			return pct, k, true
+		}
+	}
+	return 0, "", false
+}
+
+// rebalanceTrade returns the trade that moves current to target, or nil when
+// the drift is within tolerance.
+func rebalanceTrade(ticker string, current, target, price, portfolioValue, tolerance float64) *models.RebalanceSuggestion {
+	drift := current - target
+	if math.Abs(drift) <= tolerance {
+		return nil
+	}
+	s := &models.RebalanceSuggestion{
+		Ticker:           ticker,
+		Action:           "buy",
+		CurrentWeightPct: current,
+		TargetWeightPct:  target,
+		DriftPct:         drift,
+		Amount:           math.Abs(drift) / 100 * portfolioValue,
+		Price:            price,
+	}
+	if drift > 0 {
+		s.Action = "sell"
+	}
+	if price > 0 {
+		s.Units = s.Amount / price
+	}
+	return s
+}
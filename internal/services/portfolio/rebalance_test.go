@@ -0,0 +1,113 @@
+package portfolio
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+func rebalancePortfolio(cash float64) *models.Portfolio {
+	// Equity 9000 plus cash; weights are a share of portfolio value.
+	value := 9000 + cash
+	return &models.Portfolio{
+		Name:             "SMSF",
+		PortfolioValue:   value,
+		CapitalAvailable: cash,
+		Holdings: []models.Holding{
+			{Ticker: "BHP", Exchange: "AU", Units: 100, CurrentPrice: 50, MarketValue: 5000, WeightPct: 5000 / value * 100},
+			{Ticker: "CBA", Exchange: "AU", Units: 20, CurrentPrice: 150, MarketValue: 3000, WeightPct: 3000 / value * 100},
+			{Ticker: "XYZ", Exchange: "AU", Units: 100, CurrentPrice: 10, MarketValue: 1000, WeightPct: 1000 / value * 100},
+			{Ticker: "OLD", Exchange: "AU", Units: 0},
+		},
+	}
+}
+
+func findSuggestion(plan *models.RebalancePlan, ticker string) *models.RebalanceSuggestion {
+	for i := range plan.Suggestions {
+		if plan.Suggestions[i].Ticker == ticker {
+			return &plan.Suggestions[i]
+		}
+	}
+	return nil
+}
+
+func TestBuildRebalancePlan(t *testing.T) {
+	// Portfolio value 10000: BHP 50%, CBA 30%, XYZ 10% (no target), cash 10%.
+	p := rebalancePortfolio(1000)
+	targets := map[string]float64{"BHP.AU": 40, "cba": 31, "NEW": 20}
+	fees := models.FeeModel{Type: models.FeeModelFlat, Flat: 10}
+
+	plan := buildRebalancePlan(p, targets, 2, fees)
+
+	bhp := findSuggestion(plan, "BHP")
+	if bhp == nil || bhp.Action != "sell" || !approxEqual(bhp.Amount, 1000, 0.01) || !approxEqual(bhp.Units, 20, 0.001) {
+		t.Errorf("BHP = %+v, want sell 1000 (20 units)", bhp)
+	}
+	if s := findSuggestion(plan, "CBA"); s != nil {
+		t.Errorf("CBA within 2pt tolerance should not trade, got %+v", s)
+	}
+	xyz := findSuggestion(plan, "XYZ")
+	if xyz == nil || xyz.Action != "sell" || !xyz.NoTarget || !approxEqual(xyz.Amount, 1000, 0.01) {
+		t.Errorf("XYZ = %+v, want no-target sell to zero of 1000", xyz)
+	}
+	newBuy := findSuggestion(plan, "NEW")
+	if newBuy == nil || newBuy.Action != "buy" || !approxEqual(newBuy.Amount, 2000, 0.01) {
+		t.Errorf("NEW = %+v, want buy 2000", newBuy)
+	}
+	if findSuggestion(plan, "OLD") != nil {
+		t.Error("closed positions should be ignored")
+	}
+
+	// Buys 2000 + fees 30 - sells 2000 = 30 from 1000 cash
+	if !approxEqual(plan.NetCashRequired, 30, 0.01) || !approxEqual(plan.CashAfter, 970, 0.01) || plan.InsufficientCash {
+		t.Errorf("net=%.2f after=%.2f insufficient=%v, want 30 / 970 / false", plan.NetCashRequired, plan.CashAfter, plan.InsufficientCash)
+	}
+	// 40 + 31 + 20 = 91%: warn that the remainder stays in cash
+	if !approxEqual(plan.TargetsTotalPct, 91, 0.001) || len(plan.Warnings) != 1 || !strings.Contains(plan.Warnings[0], "91.0%") {
+		t.Errorf("targets total %.1f warnings %v, want 91%% with one warning", plan.TargetsTotalPct, plan.Warnings)
+	}
+}
+
+func TestBuildRebalancePlan_InsufficientCash(t *testing.T) {
+	// No cash: value 9000, BHP 55.6%, CBA 33.3%, XYZ 11.1%
+	p := rebalancePortfolio(0)
+	targets := map[string]float64{"BHP": 60, "CBA": 40, "XYZ": 20}
+
+	plan := buildRebalancePlan(p, targets, 1, models.FeeModel{})
+
+	if !plan.InsufficientCash {
+		t.Fatalf("expected insufficient cash, got %+v", plan)
+	}
+	if !approxEqual(plan.NetCashRequired, 1800, 0.01) || !approxEqual(plan.CashAfter, -1800, 0.01) {
+		t.Errorf("net=%.2f after=%.2f, want 1800 / -1800", plan.NetCashRequired, plan.CashAfter)
+	}
+	if len(plan.Warnings) != 2 {
+		t.Errorf("warnings = %v, want targets-sum and insufficient-cash warnings", plan.Warnings)
+	}
+}
+
+func TestRebalanceSuggestions_RequiresTargets(t *testing.T) {
+	uds := newMemUserDataStore()
+	storePortfolio(t, uds, rebalancePortfolio(1000))
+	svc := NewService(&stubStorageManager{userDataStore: uds, marketStore: &stubMarketDataStorage{}}, nil, nil, nil, common.NewLogger("error"))
+	ctx := context.Background()
+
+	if _, err := svc.RebalanceSuggestions(ctx, "SMSF"); err == nil {
+		t.Fatal("expected an error without target_weights")
+	}
+
+	data, _ := json.Marshal(&models.PortfolioStrategy{PortfolioName: "SMSF", TargetWeights: map[string]float64{"BHP": 50, "CBA": 50}})
+	uds.Put(ctx, &models.UserRecord{UserID: common.ResolveUserID(ctx), Subject: "strategy", Key: "SMSF", Value: string(data)})
+
+	plan, err := svc.RebalanceSuggestions(ctx, "SMSF")
+	if err != nil {
+		t.Fatalf("RebalanceSuggestions: %v", err)
+	}
+	if plan.TolerancePct != defaultRebalanceDriftPct || len(plan.Suggestions) != 2 {
+		t.Errorf("tolerance %.1f suggestions %+v, want default tolerance with CBA buy and XYZ sell", plan.TolerancePct, plan.Suggestions)
+	}
+}
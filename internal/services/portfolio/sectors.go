@@ -0,0 +1,96 @@
+package portfolio
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// SectorAllocation groups the portfolio's open holdings by the sector in
+// their cached fundamentals. Holdings without fundamentals fall under
+// models.UnknownSector.
+func (s *Service) SectorAllocation(ctx context.Context, portfolioName string) (*models.SectorBreakdown, error) {
+	portfolio, err := s.getPortfolioRecord(ctx, portfolioName)
+	if err != nil {
+		return nil, err
+	}
+
+	active, _ := filterClosedPositions(portfolio.Holdings)
+	tickers := make([]string, 0, len(active))
+	for _, h := range active {
+		tickers = append(tickers, h.EODHDTicker())
+	}
+	allMarketData, err := s.storage.MarketDataStorage().GetMarketDataBatch(ctx, tickers)
+	if err != nil {
+		s.logger.WithRequestID(ctx).Warn().Err(err).Msg("Failed to batch load market data for sector allocation")
+	}
+	fundamentals := make(map[string]*models.Fundamentals, len(allMarketData))
+	for _, md := range allMarketData {
+		fundamentals[md.Ticker] = md.Fundamentals
+	}
+
+	reviews := make([]models.HoldingReview, 0, len(active))
+	for _, h := range active {
+		reviews = append(reviews, models.HoldingReview{Holding: h, Fundamentals: fundamentals[h.EODHDTicker()]})
+	}
+	return buildSectorBreakdown(portfolioName, reviews), nil
+}
+
+// buildSectorBreakdown sums market value and weight per sector, skipping
+// closed positions. Tickers within a sector are sorted.
+func buildSectorBreakdown(portfolioName string, holdings []models.HoldingReview) *models.SectorBreakdown {
+	sectors := make(map[string]models.SectorExposure)
+	for _, hr := range holdings {
+		if hr.ActionRequired == "CLOSED" || hr.Holding.Units <= 0 {
+			continue
+		}
+		sector := models.UnknownSector
+		if hr.Fundamentals != nil && hr.Fundamentals.Sector != "" {
+			sector = hr.Fundamentals.Sector
+		}
+		e := sectors[sector]
+		e.MarketValue += hr.Holding.MarketValue
+		e.WeightPct += hr.Holding.WeightPct
+		e.Holdings = append(e.Holdings, hr.Holding.Ticker)
+		sectors[sector] = e
+	}
+	for sector, e := range sectors {
+		sort.Strings(e.Holdings)
+		sectors[sector] = e
+	}
+	return &models.SectorBreakdown{PortfolioName: portfolioName, Sectors: sectors}
+}
+
+// sectorConcentrationAlerts raises a strategy alert for each sector whose
+// weight exceeds the strategy's max_sector_pct. Unknown is never flagged:
+// it is a gap in fundamentals, not a sector.
+func sectorConcentrationAlerts(breakdown *models.SectorBreakdown, strategy *models.PortfolioStrategy) []models.Alert {
+	if breakdown == nil || strategy == nil || strategy.PositionSizing.MaxSectorPct <= 0 {
+		return nil
+	}
+	limit := strategy.PositionSizing.MaxSectorPct
+
+	names := make([]string, 0, len(breakdown.Sectors))
+	for sector := range breakdown.Sectors {
+		names = append(names, sector)
+	}
+	sort.Strings(names)
+
+	var alerts []models.Alert
+	for _, sector := range names {
+		e := breakdown.Sectors[sector]
+		if sector == models.UnknownSector || e.WeightPct <= limit {
+			continue
+		}
+		alerts = append(alerts, models.Alert{
+			Type:     models.AlertTypeStrategy,
+			Severity: "medium",
+			Message: fmt.Sprintf("Sector '%s' weight %.1f%% exceeds strategy max sector allocation of %.1f%%",
+				sector, e.WeightPct, limit),
+			Signal: "strategy_sector_concentration",
+		})
+	}
+	return alerts
+}
@@ -0,0 +1,67 @@
+package portfolio
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+func TestSectorAllocation_UnknownBucket(t *testing.T) {
+	portfolio := &models.Portfolio{
+		Name: "SMSF",
+		Holdings: []models.Holding{
+			{Ticker: "BHP", Exchange: "AU", Units: 100, MarketValue: 4000, WeightPct: 40},
+			{Ticker: "RIO", Exchange: "AU", Units: 20, MarketValue: 2500, WeightPct: 25},
+			{Ticker: "CBA", Exchange: "AU", Units: 10, MarketValue: 1500, WeightPct: 15},
+			{Ticker: "XYZ", Exchange: "AU", Units: 50, MarketValue: 1000, WeightPct: 10},
+			{Ticker: "OLD", Exchange: "AU", Units: 0, MarketValue: 0},
+		},
+	}
+	uds := newMemUserDataStore()
+	storePortfolio(t, uds, portfolio)
+	storage := &stubStorageManager{
+		userDataStore: uds,
+		marketStore: &stubMarketDataStorage{data: map[string]*models.MarketData{
+			"BHP.AU": {Ticker: "BHP.AU", Fundamentals: &models.Fundamentals{Sector: "Basic Materials"}},
+			"RIO.AU": {Ticker: "RIO.AU", Fundamentals: &models.Fundamentals{Sector: "Basic Materials"}},
+			"CBA.AU": {Ticker: "CBA.AU", Fundamentals: &models.Fundamentals{Sector: "Financial Services"}},
+		}},
+	}
+	svc := NewService(storage, nil, nil, nil, common.NewLogger("error"))
+
+	got, err := svc.SectorAllocation(context.Background(), "SMSF")
+	if err != nil {
+		t.Fatalf("SectorAllocation: %v", err)
+	}
+	if len(got.Sectors) != 3 {
+		t.Fatalf("sectors = %v, want Basic Materials, Financial Services and Unknown", got.Sectors)
+	}
+	mat := got.Sectors["Basic Materials"]
+	if mat.MarketValue != 6500 || mat.WeightPct != 65 || len(mat.Holdings) != 2 {
+		t.Errorf("Basic Materials = %+v, want 6500 / 65%% / 2 holdings", mat)
+	}
+	unknown := got.Sectors[models.UnknownSector]
+	if unknown.MarketValue != 1000 || len(unknown.Holdings) != 1 || unknown.Holdings[0] != "XYZ" {
+		t.Errorf("Unknown = %+v, want XYZ only (closed OLD excluded)", unknown)
+	}
+
+	strategy := &models.PortfolioStrategy{PositionSizing: models.PositionSizing{MaxSectorPct: 50}}
+	alerts := sectorConcentrationAlerts(got, strategy)
+	if len(alerts) != 1 || alerts[0].Signal != "strategy_sector_concentration" {
+		t.Fatalf("alerts = %+v, want one strategy_sector_concentration alert", alerts)
+	}
+	if alerts[0].Type != models.AlertTypeStrategy {
+		t.Errorf("alert type = %s, want %s", alerts[0].Type, models.AlertTypeStrategy)
+	}
+
+	// Unknown is a data gap, never a concentration breach
+	strategy.PositionSizing.MaxSectorPct = 5
+	if n := len(sectorConcentrationAlerts(got, strategy)); n != 2 {
+		t.Errorf("alerts at 5%% limit = %d, want 2 (Unknown excluded)", n)
+	}
+	if sectorConcentrationAlerts(got, nil) != nil {
+		t.Error("no strategy should raise no alerts")
+	}
+}
@@ -5,18 +5,22 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/bobmcallan/vire/internal/common"
 	"github.com/bobmcallan/vire/internal/interfaces"
 	"github.com/bobmcallan/vire/internal/models"
+	planpkg "github.com/bobmcallan/vire/internal/services/plan"
 	strategypkg "github.com/bobmcallan/vire/internal/services/strategy"
 	"github.com/bobmcallan/vire/internal/signals"
+	"golang.org/x/sync/singleflight"
 )
 
 // Service implements PortfolioService
@@ -30,9 +34,22 @@ type Service struct {
 	signalComputer     *signals.Computer
 	holdingNoteService interfaces.HoldingNoteService
 	assetSetSvc        interfaces.AssetSetService
+	fx                 interfaces.FXService
+	notifier           interfaces.NotificationService
+	defaultExchange    string          // exchange assumed for holdings without one (empty = infer from currency)
+	normalizeCents     bool            // divide EODHD prices quoted in cents by 100
+	minHoldDays        int             // CGT discount holding period for cgt_short_hold warnings
+	tradeFetchWorkers  int             // concurrent holding enrichment during sync
+	priceFreshness     atomic.Int64    // max age (ns) of an EOD bar used as a current price; hot-reloadable
+	feeModel           models.FeeModel // brokerage applied to simulated trades
+	filteredNote       string          // review summary used when Gemini blocks the prompt or response
+	rebalanceDriftPct  float64         // weight drift from target tolerated before a rebalance trade
+	strictStrategy     bool            // fail strategy loads on fields an older release stored with a different type
+	marginalTaxRate    float64         // percent applied to per-holding estimated tax (0 = no estimate)
 	logger             *common.Logger
-	syncMu             sync.Mutex // serializes SyncPortfolio to prevent warm cache overwriting force sync
-	timelineRebuilding sync.Map   // map[string]bool — true while a rebuild goroutine runs
+	syncSem            chan struct{}      // serializes SyncPortfolio to prevent warm cache overwriting force sync
+	syncGroup          singleflight.Group // shares one in-flight sync between concurrent callers
+	timelineRebuilding sync.Map           // map[string]bool — true while a rebuild goroutine runs
 }
 
 // NewService creates a new portfolio service
@@ -43,14 +60,28 @@ func NewService(
 	gemini interfaces.GeminiClient,
 	logger *common.Logger,
 ) *Service {
-	return &Service{
-		storage:        storage,
-		navexa:         navexa,
-		eodhd:          eodhd,
-		gemini:         gemini,
-		signalComputer: signals.NewComputer(),
-		logger:         logger,
-	}
+	s := &Service{
+		storage:           storage,
+		navexa:            navexa,
+		eodhd:             eodhd,
+		gemini:            gemini,
+		signalComputer:    signals.NewComputer(),
+		normalizeCents:    true,
+		minHoldDays:       defaultMinHoldDays,
+		tradeFetchWorkers: defaultTradeFetchWorkers,
+		filteredNote:      common.DefaultContentFilteredNote,
+		rebalanceDriftPct: defaultRebalanceDriftPct,
+		syncSem:           make(chan struct{}, 1),
+		logger:            logger,
+	}
+	s.priceFreshness.Store(int64(defaultPriceFreshness))
+	return s
+}
+
+// SetFXService sets the FX service used to convert holdings into the
+// portfolio's base currency (nil, the default, disables conversion).
+func (s *Service) SetFXService(svc interfaces.FXService) {
+	s.fx = svc
 }
 
 // SetCashFlowService sets the cash flow service dependency.
@@ -60,6 +91,119 @@ func (s *Service) SetCashFlowService(svc interfaces.CashFlowService) {
 	s.cashflowSvc = svc
 }
 
+// SetDefaultExchange sets the exchange assumed for holdings that Navexa
+// returns without one. Empty means infer from the holding/portfolio currency.
+func (s *Service) SetDefaultExchange(exchange string) {
+	s.defaultExchange = strings.ToUpper(strings.TrimSpace(exchange))
+}
+
+// SetNormalizeCents enables or disables cents-to-dollars normalisation of
+// EODHD prices that are ~100x the Navexa price. Enabled by default.
+func (s *Service) SetNormalizeCents(enabled bool) {
+	s.normalizeCents = enabled
+}
+
+// SetMACDPeriods sets the MACD fast/slow/signal EMA periods used when a
+// review computes missing signals.
+func (s *Service) SetMACDPeriods(fast, slow, signal int) {
+	s.signalComputer.SetMACDPeriods(fast, slow, signal)
+}
+
+// SetMinHoldDays sets the holding period (days) before the CGT discount
+// applies. Non-positive values reset to the default of 365.
+func (s *Service) SetMinHoldDays(days int) {
+	if days <= 0 {
+		days = defaultMinHoldDays
+	}
+	s.minHoldDays = days
+}
+
+// SetTradeFetchWorkers sets how many holdings are enriched concurrently
+// during sync: trades fetched from Navexa and cost basis and returns
+// computed. Non-positive values reset to the default of 10.
+func (s *Service) SetTradeFetchWorkers(n int) {
+	if n <= 0 {
+		n = defaultTradeFetchWorkers
+	}
+	s.tradeFetchWorkers = n
+}
+
+// SetFeeModel sets the brokerage schedule applied to simulated trades.
+func (s *Service) SetFeeModel(m models.FeeModel) {
+	s.feeModel = m
+}
+
+// SetContentFilteredNote sets the review summary used when Gemini blocks the
+// summary prompt or response on safety grounds. Empty keeps the default.
+func (s *Service) SetContentFilteredNote(note string) {
+	if note != "" {
+		s.filteredNote = note
+	}
+}
+
+// SetMarginalTaxRate sets the marginal tax rate (percent) used to estimate
+// the tax on selling each holding today. Zero disables the estimate.
+func (s *Service) SetMarginalTaxRate(pct float64) {
+	s.marginalTaxRate = pct
+}
+
+// SetRebalanceDriftPct sets how far a holding's weight may drift from its
+// target before RebalanceSuggestions proposes a trade. Non-positive values
+// reset to the default of 2 percentage points.
+func (s *Service) SetRebalanceDriftPct(pct float64) {
+	if pct <= 0 {
+		pct = defaultRebalanceDriftPct
+	}
+	s.rebalanceDriftPct = pct
+}
+
+// SetStrictStrategyDecode controls how stored strategies from older releases
+// are loaded. When strict, a field whose stored type no longer matches fails
+// the load; otherwise the field is dropped and logged.
+func (s *Service) SetStrictStrategyDecode(strict bool) {
+	s.strictStrategy = strict
+}
+
+// SetPriceFreshness sets how old the latest EOD bar can be before its close
+// is treated as stale during the sync price refresh. Non-positive values
+// reset to the default of 24h. Safe to call while syncs are running.
+func (s *Service) SetPriceFreshness(d time.Duration) {
+	if d <= 0 {
+		d = defaultPriceFreshness
+	}
+	s.priceFreshness.Store(int64(d))
+}
+
+// inferExchange resolves an exchange for a holding with no exchange set.
+// Priority: holding currency > configured default > portfolio base currency > "AU".
+func (s *Service) inferExchange(holdingCurrency, portfolioCurrency string) string {
+	if ex := exchangeForCurrency(holdingCurrency); ex != "" {
+		return ex
+	}
+	if s.defaultExchange != "" {
+		return models.EodhExchange(s.defaultExchange)
+	}
+	if ex := exchangeForCurrency(portfolioCurrency); ex != "" {
+		return ex
+	}
+	return "AU"
+}
+
+// exchangeForCurrency maps a currency code to its primary EODHD exchange code.
+// Returns empty for unknown or empty currencies.
+func exchangeForCurrency(currency string) string {
+	switch strings.ToUpper(currency) {
+	case "AUD":
+		return "AU"
+	case "USD":
+		return "US"
+	case "GBP":
+		return "LSE"
+	default:
+		return ""
+	}
+}
+
 // SetTradeService sets the trade service dependency.
 func (s *Service) SetTradeService(svc interfaces.TradeService) {
 	s.tradeService = svc
@@ -70,6 +214,12 @@ func (s *Service) SetHoldingNoteService(svc interfaces.HoldingNoteService) {
 	s.holdingNoteService = svc
 }
 
+// SetNotificationService sets the webhook dispatcher told about newly raised
+// review alerts. Without it, alerts are only returned in the review.
+func (s *Service) SetNotificationService(svc interfaces.NotificationService) {
+	s.notifier = svc
+}
+
 // SetAssetSetService injects the asset set service (setter injection to avoid circular deps)
 func (s *Service) SetAssetSetService(svc interfaces.AssetSetService) {
 	s.assetSetSvc = svc
@@ -114,10 +264,11 @@ func (s *Service) rebuildTimelineWithCash(ctx context.Context, name string) ([]m
 // the portfolio timeline. Sets the rebuilding flag for the duration.
 // Call when the trade hash changes and the timeline cache has been invalidated.
 func (s *Service) triggerTimelineRebuildAsync(ctx context.Context, name string) {
+	logger := s.logger.WithRequestID(ctx)
 	// Dedup: skip if a rebuild is already in progress for this portfolio.
 	// Prevents concurrent full recomputes which waste resources and produce the same result.
 	if s.IsTimelineRebuilding(name) {
-		s.logger.Info().Str("portfolio", name).Msg("Timeline rebuild already in progress — skipping")
+		logger.Info().Str("portfolio", name).Msg("Timeline rebuild already in progress — skipping")
 		return
 	}
 	s.timelineRebuilding.Store(name, true)
@@ -125,7 +276,7 @@ func (s *Service) triggerTimelineRebuildAsync(ctx context.Context, name string)
 		defer func() {
 			s.timelineRebuilding.Store(name, false)
 			if r := recover(); r != nil {
-				s.logger.Warn().Str("portfolio", name).Msgf("Timeline rebuild panic recovered: %v", r)
+				logger.Warn().Str("portfolio", name).Msgf("Timeline rebuild panic recovered: %v", r)
 			}
 		}()
 		bgCtx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
@@ -134,10 +285,10 @@ func (s *Service) triggerTimelineRebuildAsync(ctx context.Context, name string)
 		// CRITICAL: use rebuildTimelineWithCash to include cash transactions.
 		// Bare GetDailyGrowth with empty GrowthOptions excludes cash from persisted snapshots.
 		if _, err := s.rebuildTimelineWithCash(bgCtx, name); err != nil {
-			s.logger.Warn().Err(err).Str("portfolio", name).Msg("Timeline rebuild after trade change failed")
+			logger.Warn().Err(err).Str("portfolio", name).Msg("Timeline rebuild after trade change failed")
 			return
 		}
-		s.logger.Info().Str("portfolio", name).Msg("Timeline rebuild after trade change complete")
+		logger.Info().Str("portfolio", name).Msg("Timeline rebuild after trade change complete")
 	}()
 }
 
@@ -146,14 +297,14 @@ func (s *Service) triggerTimelineRebuildAsync(ctx context.Context, name string)
 // changes that affect historical portfolio values.
 func (s *Service) InvalidateAndRebuildTimeline(ctx context.Context, name string) {
 	if s.IsTimelineRebuilding(name) {
-		s.logger.Info().Str("portfolio", name).Msg("Timeline rebuild already in progress — skipping invalidation")
+		s.logger.WithRequestID(ctx).Info().Str("portfolio", name).Msg("Timeline rebuild already in progress — skipping invalidation")
 		return
 	}
 
 	userID := common.ResolveUserID(ctx)
 	if tl := s.storage.TimelineStore(); tl != nil {
 		if _, err := tl.DeleteAll(ctx, userID, name); err != nil {
-			s.logger.Warn().Err(err).Str("portfolio", name).Msg("Timeline invalidation: delete failed")
+			s.logger.WithRequestID(ctx).Warn().Err(err).Str("portfolio", name).Msg("Timeline invalidation: delete failed")
 		}
 	}
 
@@ -175,7 +326,7 @@ func (s *Service) ForceRebuildTimeline(ctx context.Context, name string) error {
 	if err != nil {
 		return fmt.Errorf("failed to delete timeline data: %w", err)
 	}
-	s.logger.Info().Str("portfolio", name).Int("deleted", deleted).Msg("Admin force rebuild: timeline data deleted")
+	s.logger.WithRequestID(ctx).Info().Str("portfolio", name).Int("deleted", deleted).Msg("Admin force rebuild: timeline data deleted")
 
 	// Force: bypass dedup check by resetting flag first
 	s.timelineRebuilding.Store(name, false)
@@ -189,43 +340,249 @@ func (s *Service) resolveNavexaClient(ctx context.Context) (interfaces.NavexaCli
 	if override := common.NavexaClientFromContext(ctx); override != nil {
 		return override, nil
 	}
-	return nil, fmt.Errorf("navexa client not available: portal headers required")
+	return nil, fmt.Errorf("%w: navexa client not available, portal headers required", interfaces.ErrNavexaUnavailable)
 }
 
-// SyncPortfolio refreshes portfolio data from Navexa
+// acquireSync waits for the sync slot. A caller whose context ends while
+// another sync holds it gets ErrSyncInProgress.
+func (s *Service) acquireSync(ctx context.Context) error {
+	select {
+	case s.syncSem <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%w: %w", interfaces.ErrSyncInProgress, ctx.Err())
+	}
+}
+
+func (s *Service) releaseSync() {
+	<-s.syncSem
+}
+
+// SyncPortfolio refreshes portfolio data from Navexa.
+// force=false uses the standard TTL (30 min); force=true uses the shorter
+// cooldown (5 min) to prevent rapid re-syncs.
 func (s *Service) SyncPortfolio(ctx context.Context, name string, force bool) (*models.Portfolio, error) {
-	s.syncMu.Lock()
-	defer s.syncMu.Unlock()
+	ttl := common.FreshnessPortfolio
+	if force {
+		ttl = common.FreshnessSyncCooldown
+	}
+	portfolio, err := s.syncPortfolio(ctx, name, ttl)
+	if err != nil {
+		return nil, err
+	}
+	s.applyHoldingAnnotations(ctx, portfolio)
+	return portfolio, nil
+}
+
+// EnsureSynced returns the stored portfolio if it was synced within maxAge,
+// otherwise syncs it from Navexa first. A non-positive maxAge always syncs.
+func (s *Service) EnsureSynced(ctx context.Context, name string, maxAge time.Duration) (*models.Portfolio, error) {
+	portfolio, err := s.syncPortfolio(ctx, name, maxAge)
+	if err != nil {
+		return nil, err
+	}
+	s.applyHoldingAnnotations(ctx, portfolio)
+	return portfolio, nil
+}
+
+// SyncHolding refreshes one holding of a Navexa portfolio: it re-fetches that
+// holding's trades and price, replaces it in the stored portfolio and
+// recomputes the portfolio totals. Other holdings keep their stored values
+// (apart from weights) and LastSynced is left alone, since the rest of the
+// portfolio was not refreshed. The ticker matches on code or EODHD ticker.
+func (s *Service) SyncHolding(ctx context.Context, portfolioName, ticker string) (*models.Holding, error) {
+	logger := s.logger.WithRequestID(ctx)
+
+	if err := s.acquireSync(ctx); err != nil {
+		return nil, err
+	}
+	defer s.releaseSync()
+
+	portfolio, err := s.getPortfolioRecord(ctx, portfolioName)
+	if err != nil {
+		return nil, err
+	}
+	if portfolio.SourceType != models.SourceNavexa && portfolio.SourceType != "" {
+		return nil, fmt.Errorf("portfolio '%s' is a %s portfolio: only Navexa holdings can be synced", portfolioName, portfolio.SourceType)
+	}
+
+	matchesTicker := func(code, eodhdTicker string) bool {
+		return strings.EqualFold(code, ticker) || strings.EqualFold(eodhdTicker, ticker)
+	}
+	var code string
+	for _, h := range portfolio.Holdings {
+		if matchesTicker(h.Ticker, h.EODHDTicker()) {
+			code = h.Ticker
+			break
+		}
+	}
+	if code == "" {
+		return nil, fmt.Errorf("%w: '%s' is not in portfolio '%s'", interfaces.ErrHoldingNotFound, ticker, portfolioName)
+	}
+
+	navexaClient, err := s.resolveNavexaClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve navexa client: %w", err)
+	}
+	navexaPortfolio, err := navexaClient.GetPortfolio(ctx, portfolio.NavexaID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to get portfolio: %w", interfaces.ErrNavexaUnavailable, err)
+	}
+	fromDate := navexaPortfolio.DateCreated
+	if fromDate == "" {
+		fromDate = "2020-01-01" // fallback
+	}
+	navexaHoldings, err := navexaClient.GetEnrichedHoldings(ctx, navexaPortfolio.ID, fromDate, time.Now().Format("2006-01-02"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to get enriched holdings: %w", interfaces.ErrNavexaUnavailable, err)
+	}
+
+	// Keep only this holding's Navexa rows (one per account or reopened
+	// position), so trades are fetched for it alone.
+	var matched []*models.NavexaHolding
+	for _, h := range navexaHoldings {
+		if strings.EqualFold(h.Ticker, code) {
+			matched = append(matched, h)
+		}
+	}
+	if len(matched) == 0 {
+		return nil, fmt.Errorf("%w: '%s' is no longer in Navexa portfolio '%s'", interfaces.ErrHoldingNotFound, ticker, portfolioName)
+	}
+
+	strategy, _ := s.getStrategyRecord(ctx, portfolioName)
+	refreshed, fxRates, _ := s.buildHoldings(ctx, navexaClient, portfolioName, navexaPortfolio.Currency, strategy, matched)
+
+	// Replace the stored rows for this ticker in place, keeping holding order.
+	holdings := make([]models.Holding, 0, len(portfolio.Holdings)-1+len(refreshed))
+	position := -1
+	for _, h := range portfolio.Holdings {
+		if !strings.EqualFold(h.Ticker, code) {
+			holdings = append(holdings, h)
+			continue
+		}
+		if position < 0 {
+			position = len(holdings)
+			holdings = append(holdings, refreshed...)
+		}
+	}
+	portfolio.Holdings = holdings
+
+	if len(fxRates) > 0 {
+		if portfolio.FXRates == nil {
+			portfolio.FXRates = make(map[string]float64, len(fxRates))
+		}
+		for currency, rate := range fxRates {
+			portfolio.FXRates[currency] = rate
+		}
+		portfolio.FXRate = portfolio.FXRates["USD"]
+	}
+	s.applyPortfolioTotals(ctx, portfolio, includeDividendsInReturn(strategy))
+
+	// Invalidate the persisted timeline if this holding's trades changed.
+	tradeHash := computeTradeHash(holdings)
+	tradeHashChanged := portfolio.TradeHash != "" && portfolio.TradeHash != tradeHash
+	portfolio.TradeHash = tradeHash
+	if tradeHashChanged {
+		logger.Info().Str("portfolio", portfolioName).Str("ticker", code).Msg("Trade data changed — invalidating timeline cache")
+		if tl := s.storage.TimelineStore(); tl != nil {
+			if _, err := tl.DeleteAll(ctx, common.ResolveUserID(ctx), portfolioName); err != nil {
+				logger.Warn().Err(err).Str("portfolio", portfolioName).Msg("Failed to invalidate timeline cache")
+			}
+		}
+	}
+
+	if err := s.savePortfolioRecord(ctx, portfolio); err != nil {
+		return nil, fmt.Errorf("failed to save portfolio: %w", err)
+	}
+	if tradeHashChanged {
+		s.triggerTimelineRebuildAsync(ctx, portfolioName)
+	}
+	s.upsertStockIndex(ctx, refreshed)
+
+	logger.Info().Str("name", portfolioName).Str("ticker", code).Msg("Holding synced")
+
+	// Keep today's timeline value in step with the adjusted totals.
+	s.writeTodaySnapshot(ctx, portfolio)
+
+	s.applyHoldingAnnotations(ctx, portfolio)
+	holding := portfolio.Holdings[position]
+	return &holding, nil
+}
+
+// syncPortfolio syncs from Navexa unless the stored portfolio is fresher than
+// ttl. Concurrent calls for the same user, portfolio and ttl share one sync:
+// the first runs it and the others wait, then load the portfolio it saved,
+// so each caller gets its own copy to annotate. The shared sync runs on a
+// context detached from the first caller's cancellation, so a caller that
+// gives up gets ErrSyncInProgress without failing the others.
+func (s *Service) syncPortfolio(ctx context.Context, name string, ttl time.Duration) (*models.Portfolio, error) {
+	key := common.ResolveUserID(ctx) + "|" + name + "|" + ttl.String()
+	syncCtx := context.WithoutCancel(ctx)
+	ran := false
+	ch := s.syncGroup.DoChan(key, func() (interface{}, error) {
+		ran = true
+		return s.runPortfolioSync(syncCtx, name, ttl)
+	})
+	var res singleflight.Result
+	select {
+	case res = <-ch:
+	case <-ctx.Done():
+		return nil, fmt.Errorf("%w: %w", interfaces.ErrSyncInProgress, ctx.Err())
+	}
+	if res.Err != nil {
+		return nil, res.Err
+	}
+	if ran || !res.Shared {
+		return res.Val.(*models.Portfolio), nil
+	}
+
+	s.logger.WithRequestID(ctx).Debug().Str("name", name).Msg("Joined in-flight portfolio sync, returning its result")
+	portfolio, err := s.getPortfolioRecord(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	s.populateHistoricalValues(ctx, portfolio)
+	return portfolio, nil
+}
+
+// runPortfolioSync performs a sync for syncPortfolio, serialized across
+// portfolios by syncSem. Syncs that reach Navexa are timed in the portfolio
+// sync metrics.
+func (s *Service) runPortfolioSync(ctx context.Context, name string, ttl time.Duration) (_ *models.Portfolio, err error) {
+	logger := s.logger.WithRequestID(ctx)
+
+	if err := s.acquireSync(ctx); err != nil {
+		return nil, err
+	}
+	defer s.releaseSync()
 
 	navexaClient, err := s.resolveNavexaClient(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to resolve navexa client: %w", err)
 	}
 
-	s.logger.Info().Str("name", name).Bool("force", force).Msg("Syncing portfolio")
+	logger.Info().Str("name", name).Dur("ttl", ttl).Msg("Syncing portfolio")
 
-	// Check freshness: force=false uses standard TTL (30 min),
-	// force=true uses shorter cooldown (5 min) to prevent rapid re-syncs.
+	// Check freshness against ttl.
 	// Capture existing trade hash for timeline invalidation detection later.
 	var existingTradeHash string
 	if existing, err := s.getPortfolioRecord(ctx, name); err == nil {
 		existingTradeHash = existing.TradeHash
-		ttl := common.FreshnessPortfolio
-		if force {
-			ttl = common.FreshnessSyncCooldown
-		}
-		if common.IsFresh(existing.LastSynced, ttl) {
-			s.logger.Debug().Str("name", name).Bool("force", force).
+		if ttl > 0 && common.IsFresh(existing.LastSynced, ttl) {
+			logger.Debug().Str("name", name).
 				Dur("ttl", ttl).Msg("Portfolio within sync cooldown, returning cached")
 			s.populateHistoricalValues(ctx, existing)
+			common.RecordCacheLookup("portfolio", true)
 			return existing, nil
 		}
 	}
+	common.RecordCacheLookup("portfolio", false)
+	defer func(start time.Time) { common.ObservePortfolioSync(start, err) }(time.Now())
 
 	// Get portfolios from Navexa
 	navexaPortfolios, err := navexaClient.GetPortfolios(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get portfolios from Navexa: %w", err)
+		return nil, fmt.Errorf("%w: failed to get portfolios: %w", interfaces.ErrNavexaUnavailable, err)
 	}
 
 	// Find matching portfolio
@@ -238,7 +595,7 @@ func (s *Service) SyncPortfolio(ctx context.Context, name string, force bool) (*
 	}
 
 	if navexaPortfolio == nil {
-		return nil, fmt.Errorf("portfolio '%s' not found in Navexa", name)
+		return nil, fmt.Errorf("%w: '%s' not found in Navexa", interfaces.ErrPortfolioNotFound, name)
 	}
 
 	// Use performance endpoint to get enriched holdings with financial data
@@ -248,7 +605,7 @@ func (s *Service) SyncPortfolio(ctx context.Context, name string, force bool) (*
 	}
 	toDate := time.Now().Format("2006-01-02")
 
-	s.logger.Info().
+	logger.Info().
 		Str("navexa_id", navexaPortfolio.ID).
 		Str("from", fromDate).
 		Str("to", toDate).
@@ -256,139 +613,176 @@ func (s *Service) SyncPortfolio(ctx context.Context, name string, force bool) (*
 
 	navexaHoldings, err := navexaClient.GetEnrichedHoldings(ctx, navexaPortfolio.ID, fromDate, toDate)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get enriched holdings from Navexa: %w", err)
+		return nil, fmt.Errorf("%w: failed to get enriched holdings: %w", interfaces.ErrNavexaUnavailable, err)
 	}
 
-	// Fetch trades per holding concurrently to compute accurate cost basis.
-	// (performance endpoint returns annualized values, not actual cost)
-	// Sequential fetching at 5 req/s across 40+ holdings exceeds typical
-	// request timeouts, so we fan out with bounded concurrency.
-	type tradeResult struct {
-		holding *models.NavexaHolding
-		trades  []*models.NavexaTrade
-	}
-
-	const tradeFetchWorkers = 10
-	tradeCh := make(chan *models.NavexaHolding, len(navexaHoldings))
-	resultCh := make(chan tradeResult, len(navexaHoldings))
-
-	var tradeWg sync.WaitGroup
-	for i := 0; i < tradeFetchWorkers; i++ {
-		tradeWg.Add(1)
-		go func() {
-			defer tradeWg.Done()
-			for h := range tradeCh {
-				trades, err := navexaClient.GetHoldingTrades(ctx, h.ID)
-				if err != nil {
-					s.logger.Warn().Err(err).Str("ticker", h.Ticker).Str("holdingID", h.ID).Msg("Failed to get trades for holding")
-					continue
-				}
-				if len(trades) > 0 {
-					resultCh <- tradeResult{holding: h, trades: trades}
-				}
+	strategy, _ := s.getStrategyRecord(ctx, name)
+	holdings, fxRates, baseCurrency := s.buildHoldings(ctx, navexaClient, name, navexaPortfolio.Currency, strategy, navexaHoldings)
+
+	// Compute trade hash for timeline invalidation detection.
+	// If trades or cash transactions have changed since last sync,
+	// the persisted timeline is stale and must be recomputed.
+	tradeHash := computeTradeHash(holdings)
+
+	portfolio := &models.Portfolio{
+		ID:                name,
+		Name:              name,
+		NavexaID:          navexaPortfolio.ID,
+		Holdings:          holdings,
+		Currency:          navexaPortfolio.Currency,
+		BaseCurrency:      baseCurrency,
+		FXRate:            fxRates["USD"],
+		FXRates:           fxRates,
+		CalculationMethod: "average_cost",
+		TradeHash:         tradeHash,
+		LastSynced:        time.Now(),
+	}
+	s.applyPortfolioTotals(ctx, portfolio, includeDividendsInReturn(strategy))
+
+	// Invalidate persisted timeline if trade data changed since last sync.
+	tradeHashChanged := existingTradeHash != "" && existingTradeHash != tradeHash
+	if tradeHashChanged {
+		logger.Info().Str("portfolio", name).Msg("Trade data changed — invalidating timeline cache")
+		userID := common.ResolveUserID(ctx)
+		if tl := s.storage.TimelineStore(); tl != nil {
+			if _, err := tl.DeleteAll(ctx, userID, name); err != nil {
+				logger.Warn().Err(err).Str("portfolio", name).Msg("Failed to invalidate timeline cache")
 			}
-		}()
+		}
 	}
 
-	for _, h := range navexaHoldings {
-		if h.ID == "" {
-			continue
-		}
-		tradeCh <- h
+	// Save portfolio
+	if err := s.savePortfolioRecord(ctx, portfolio); err != nil {
+		return nil, fmt.Errorf("failed to save portfolio: %w", err)
 	}
-	close(tradeCh)
 
-	go func() {
-		tradeWg.Wait()
-		close(resultCh)
-	}()
+	// Trigger explicit timeline rebuild when trades changed. Must happen after
+	// savePortfolioRecord so GetDailyGrowth reads the new trade data.
+	if tradeHashChanged {
+		s.triggerTimelineRebuildAsync(ctx, name)
+	}
 
-	holdingTrades := make(map[string][]*models.NavexaTrade) // ticker -> trades
-	holdingMetrics := make(map[string]*holdingCalcMetrics)  // ticker -> computed return metrics
-	for res := range resultCh {
-		h := res.holding
-		trades := res.trades
-		holdingTrades[h.Ticker] = append(holdingTrades[h.Ticker], trades...)
-
-		// Calculate average cost, remaining cost, and units from trades.
-		// Trade-derived units are authoritative — Navexa performance endpoint
-		// can return stale or rounded unit counts.
-		avgCost, remainingCost, tradeUnits := calculateAvgCostFromTrades(trades)
-		h.AvgCost = avgCost
-		if math.Abs(tradeUnits-h.Units) > 0.01 {
-			s.logger.Warn().
-				Str("ticker", h.Ticker).
-				Float64("navexa_units", h.Units).
-				Float64("trade_units", tradeUnits).
-				Msg("Units mismatch: overriding Navexa value with trade-derived units")
-		}
-		h.Units = tradeUnits
-		h.MarketValue = h.CurrentPrice * h.Units
+	// Upsert tickers to stock index for job manager tracking
+	s.upsertStockIndex(ctx, holdings)
 
-		// Calculate gain/loss using the simple, correct formula:
-		// GainLoss = (proceeds from sells) + (current market value) - (total invested)
-		totalInvested, totalProceeds, gainLoss := calculateGainLossFromTrades(trades, h.MarketValue)
+	logger.Info().Str("name", name).Int("holdings", len(holdings)).Msg("Portfolio synced")
 
-		// TotalCost represents remaining cost basis (for position sizing)
-		// For closed positions, use totalInvested; for open, use remainingCost
-		if h.Units <= 0 {
-			h.TotalCost = totalInvested
-		} else {
-			h.TotalCost = remainingCost
-		}
+	// Write today's timeline snapshot synchronously.
+	// This is the authoritative "today" value, updated every sync cycle (~5-30 min).
+	s.writeTodaySnapshot(ctx, portfolio)
 
-		h.GainLoss = gainLoss
+	// Backfill historical timeline if empty (e.g. after a schema rebuild).
+	// Runs in background to avoid blocking the sync response.
+	s.backfillTimelineIfEmpty(ctx, portfolio)
 
-		// Simple percentage returns — denominator is total capital invested
-		if totalInvested > 0 {
-			h.GainLossPct = (h.GainLoss / totalInvested) * 100
-		} else {
-			h.GainLossPct = 0
-		}
+	// Include non-equity asset set values in portfolio totals
+	s.populateAssetSetValues(ctx, portfolio)
+
+	// Populate historical values (yesterday/last week) from EOD market data
+	s.populateHistoricalValues(ctx, portfolio)
 
-		// Realized/unrealized breakdown
-		realizedGL := totalProceeds - (totalInvested - remainingCost)
-		unrealizedGL := h.MarketValue - remainingCost
-		holdingMetrics[h.Ticker] = &holdingCalcMetrics{
-			totalInvested:      totalInvested,
-			totalProceeds:      totalProceeds,
-			realizedGainLoss:   realizedGL,
-			unrealizedGainLoss: unrealizedGL,
+	return portfolio, nil
+}
+
+// buildHoldings converts Navexa holdings into portfolio holdings: it infers
+// missing exchanges, fetches each holding's trades, cross-checks prices
+// against EODHD, flags suspected splits, fills TWRR and country, and converts
+// foreign holdings into the base currency. It returns the holdings along
+// with the FX rates and base currency used.
+func (s *Service) buildHoldings(ctx context.Context, navexaClient interfaces.NavexaClient, name, portfolioCurrency string, strategy *models.PortfolioStrategy, navexaHoldings []*models.NavexaHolding) ([]models.Holding, map[string]float64, string) {
+	logger := s.logger.WithRequestID(ctx)
+
+	// Infer an exchange for holdings Navexa returned without one, so EODHD
+	// ticker construction (price cross-check, market data, stock index) still works.
+	exchangeInferred := make(map[*models.NavexaHolding]bool)
+	for _, h := range navexaHoldings {
+		if strings.TrimSpace(h.Exchange) != "" {
+			continue
 		}
+		h.Exchange = s.inferExchange(h.Currency, portfolioCurrency)
+		exchangeInferred[h] = true
+		logger.Warn().
+			Str("ticker", h.Ticker).
+			Str("inferred_exchange", h.Exchange).
+			Msg("Holding has no exchange: using inferred exchange")
+	}
 
-		// XIRR annualised returns
-		now := time.Now()
-		h.CapitalGainPct = CalculateXIRR(trades, h.MarketValue, h.DividendReturn, false, now)
-		h.TotalReturnPctIRR = CalculateXIRR(trades, h.MarketValue, h.DividendReturn, true, now)
+	// Navexa's own valuation, before trade-derived units replace its units;
+	// used below to spot splits that neither source has recorded.
+	navexaValue := make(map[*models.NavexaHolding]float64, len(navexaHoldings))
+	for _, h := range navexaHoldings {
+		navexaValue[h] = h.MarketValue
 	}
 
+	// Fetch trades and compute cost basis per holding concurrently.
+	// (performance endpoint returns annualized values, not actual cost)
+	// Sequential fetching at 5 req/s across 40+ holdings exceeds typical
+	// request timeouts, so we fan out with bounded concurrency.
+	costMethod := s.costBasisMethod(ctx, name)
+	enriched := s.enrichHoldings(ctx, navexaClient, navexaHoldings, costMethod, logger)
+
+	// Assemble in holding order so results don't depend on which worker
+	// finished first. Several Navexa holdings can share a ticker (the same
+	// stock in two accounts, or a closed and reopened position), so trades
+	// are appended rather than replaced.
+	holdingMetrics := make(map[string]*holdingCalcMetrics)  // ticker -> computed return metrics
+	holdingTrades := make(map[string][]*models.NavexaTrade) // ticker -> trades, across all holdings
+	for i, h := range navexaHoldings {
+		if enriched[i].trades == nil {
+			continue
+		}
+		holdingTrades[h.Ticker] = append(holdingTrades[h.Ticker], enriched[i].trades...)
+		holdingMetrics[h.Ticker] = enriched[i].metrics
+	}
+
+	priceInCents := make(map[*models.NavexaHolding]bool)
+
 	// Cross-check Navexa prices against EODHD close prices.
 	// Navexa's performance API can return stale currentPrice values
 	// (e.g. Friday's close on Monday evening). If EODHD has a more
-	// recent bar, use its close price instead.
+	// recent bar, use its close price instead. The strategy's price
+	// source can pin a holding to Navexa, or always take EODHD.
 	for _, h := range navexaHoldings {
 		if h.Units <= 0 {
 			continue // skip closed positions
 		}
 		ticker := h.EODHDTicker()
+		source := strategy.PriceSourceFor(h.Ticker, ticker)
+		if source == models.PriceSourceNavexa {
+			continue
+		}
 		md, err := s.storage.MarketDataStorage().GetMarketData(ctx, ticker)
 		if err != nil || md == nil || len(md.EOD) == 0 {
 			continue
 		}
 		latestBar := md.EOD[0] // EOD is sorted descending (most recent first)
 
-		// Use EODHD close if the bar is recent (within 24h) and differs from Navexa.
-		// We use time.Since rather than date equality to avoid UTC vs AEST timezone
-		// issues — the Docker container runs in UTC but ASX trades in AEST.
+		// Use EODHD close if the bar is recent (within priceFreshness, 24h by
+		// default) and differs from Navexa. We use bar age rather than date
+		// equality to avoid UTC vs AEST timezone issues — the Docker container
+		// runs in UTC but ASX trades in AEST.
 		// Prefer AdjClose over Close to handle corporate actions (e.g. consolidations).
-		eodhPrice := eodClosePrice(latestBar)
-		if time.Since(latestBar.Date) < 24*time.Hour && eodhPrice != h.CurrentPrice {
+		eodhPrice, stale := eodClosePriceFresh(latestBar, time.Now(), time.Duration(s.priceFreshness.Load()))
+
+		// Some AU listings are quoted in cents by EODHD while Navexa reports
+		// dollars. A ~100x ratio is a unit mismatch, not a price move.
+		if s.normalizeCents && isCentsQuote(eodhPrice, h.CurrentPrice) {
+			logger.Warn().
+				Str("ticker", h.Ticker).
+				Float64("navexa_price", h.CurrentPrice).
+				Float64("eodhd_price", eodhPrice).
+				Msg("EODHD price quoted in cents: normalising to dollars")
+			eodhPrice /= 100
+			priceInCents[h] = true
+		}
+		fresh := !stale || source == models.PriceSourceEODHD
+		if fresh && eodhPrice != h.CurrentPrice {
 			// Guard: reject EODHD price if it diverges >50% from Navexa — indicates
 			// wrong instrument mapping in EODHD (e.g. ticker resolves to different security).
 			if h.CurrentPrice > 0 {
 				divergencePct := math.Abs(eodhPrice-h.CurrentPrice) / h.CurrentPrice * 100
 				if divergencePct > 50.0 {
-					s.logger.Warn().
+					logger.Warn().
 						Str("ticker", h.Ticker).
 						Float64("navexa_price", h.CurrentPrice).
 						Float64("eodhd_price", eodhPrice).
@@ -397,7 +791,7 @@ func (s *Service) SyncPortfolio(ctx context.Context, name string, force bool) (*
 					continue
 				}
 			}
-			s.logger.Info().
+			logger.Info().
 				Str("ticker", h.Ticker).
 				Float64("navexa_price", h.CurrentPrice).
 				Float64("eodhd_close", latestBar.Close).
@@ -429,9 +823,54 @@ func (s *Service) SyncPortfolio(ctx context.Context, name string, force bool) (*
 		}
 	}
 
+	// Flag likely unrecorded splits: trade-derived units at the current price
+	// diverge >50% from Navexa's valuation and the EOD series shows a matching
+	// jump in Close that AdjClose does not share. Units are left as they are.
+	splitSuspected := make(map[*models.NavexaHolding]bool)
+	for _, h := range navexaHoldings {
+		if h.Units <= 0 {
+			continue
+		}
+		ratio, diverged := valueDivergence(h.Units*h.CurrentPrice, navexaValue[h])
+		if !diverged {
+			continue
+		}
+		md, err := s.storage.MarketDataStorage().GetMarketData(ctx, h.EODHDTicker())
+		if err != nil || md == nil || len(md.EOD) == 0 {
+			continue
+		}
+		var since time.Time
+		for _, t := range holdingTrades[h.Ticker] {
+			if d := parseTradeDate(t.Date); !d.IsZero() && (since.IsZero() || d.Before(since)) {
+				since = d
+			}
+		}
+		if bar, ok := findSplitJump(md.EOD, since, ratio); ok {
+			splitSuspected[h] = true
+			logger.Warn().
+				Str("ticker", h.Ticker).
+				Float64("units", h.Units).
+				Float64("market_value", h.Units*h.CurrentPrice).
+				Float64("navexa_market_value", navexaValue[h]).
+				Float64("ratio", ratio).
+				Str("jump_date", bar.Date.Format("2006-01-02")).
+				Msg("split_suspected: units may not reflect a split in the EOD series")
+		}
+	}
+
 	// Convert to internal model
 	holdings := make([]models.Holding, len(navexaHoldings))
-	hasUSD := false
+	includeDividends := includeDividendsInReturn(strategy)
+	dividendNow := time.Now()
+
+	// Holdings that share a ticker carry the same merged trades, so the
+	// ticker's dividends are apportioned between them by units held
+	tickerUnits := make(map[string]float64)
+	tickerCount := make(map[string]int)
+	for _, h := range navexaHoldings {
+		tickerUnits[h.Ticker] += h.Units
+		tickerCount[h.Ticker]++
+	}
 
 	for i, h := range navexaHoldings {
 		// Currency: default to AUD if empty
@@ -439,13 +878,13 @@ func (s *Service) SyncPortfolio(ctx context.Context, name string, force bool) (*
 		if currency == "" {
 			currency = "AUD"
 		}
-		if currency == "USD" {
-			hasUSD = true
-		}
 
 		holdings[i] = models.Holding{
 			Ticker:                     h.Ticker,
 			Exchange:                   h.Exchange,
+			ExchangeInferred:           exchangeInferred[h],
+			PriceInCents:               priceInCents[h],
+			SplitSuspected:             splitSuspected[h],
 			Name:                       h.Name,
 			Units:                      h.Units,
 			AvgCost:                    h.AvgCost,
@@ -468,6 +907,23 @@ func (s *Service) SyncPortfolio(ctx context.Context, name string, force bool) (*
 			holdings[i].RealizedReturn = m.realizedGainLoss
 			holdings[i].UnrealizedReturn = m.unrealizedGainLoss
 		}
+		holdings[i].TotalFees = calculateFeesFromTrades(holdings[i].Trades)
+
+		// Dividend income from "dividend" trade rows, this holding's share
+		dividends, trailingDividends := calculateDividendsFromTrades(holdings[i].Trades, dividendNow)
+		share := 1.0 / float64(tickerCount[h.Ticker])
+		if total := tickerUnits[h.Ticker]; total > 0 {
+			share = h.Units / total
+		}
+		dividends *= share
+		holdings[i].DividendsReceived = dividends
+		holdings[i].TrailingYieldPct = trailingYieldPct(trailingDividends*share, holdings[i].MarketValue)
+		if includeDividends && dividends != 0 {
+			holdings[i].ReturnNet += dividends
+			if holdings[i].GrossInvested > 0 {
+				holdings[i].ReturnNetPct = holdings[i].ReturnNet / holdings[i].GrossInvested * 100
+			}
+		}
 
 		// Mark position status and compute breakeven for open positions
 		if holdings[i].Units > 0 {
@@ -483,17 +939,12 @@ func (s *Service) SyncPortfolio(ctx context.Context, name string, force bool) (*
 		}
 	}
 
-	// Fetch FX rate if any holdings are in USD (for AUD portfolio conversion)
-	var fxRate float64
-	if hasUSD && s.eodhd != nil {
-		quote, err := s.eodhd.GetRealTimeQuote(ctx, "AUDUSD.FOREX")
-		if err != nil {
-			s.logger.Warn().Err(err).Msg("Failed to fetch AUDUSD forex rate; USD holdings will not be converted")
-		} else if quote.Close > 0 {
-			fxRate = quote.Close
-			s.logger.Info().Float64("audusd_rate", fxRate).Msg("Fetched AUDUSD forex rate")
-		}
+	// Fetch a rate for every holding currency other than the base currency
+	baseCurrency := strings.ToUpper(strings.TrimSpace(portfolioCurrency))
+	if baseCurrency == "" {
+		baseCurrency = "AUD"
 	}
+	fxRates := s.fetchFXRates(ctx, baseCurrency, holdings)
 
 	// Batch-fetch market data for TWRR and country population
 	syncTickers := make([]string, 0, len(holdings))
@@ -534,40 +985,49 @@ func (s *Service) SyncPortfolio(ctx context.Context, name string, force bool) (*
 		holdings[i].TimeWeightedReturnPct = CalculateTWRR(trades, md.EOD, holdings[i].CurrentPrice, now)
 	}
 
-	// Convert USD holding values to AUD when FX rate is available.
-	// AUDUSD rate means "how many USD per 1 AUD", so USD→AUD = value / rate.
-	if fxRate > 0 {
-		fxDiv := fxRate // USD to AUD divisor
-		for i := range holdings {
-			if holdings[i].Currency != "USD" {
-				continue
-			}
-			holdings[i].OriginalCurrency = "USD"
-			holdings[i].CurrentPrice /= fxDiv
-			holdings[i].AvgCost /= fxDiv
-			holdings[i].MarketValue /= fxDiv
-			holdings[i].CostBasis /= fxDiv
-			holdings[i].GrossInvested /= fxDiv
-			holdings[i].GrossProceeds /= fxDiv
-			holdings[i].ReturnNet /= fxDiv
-			holdings[i].RealizedReturn /= fxDiv
-			holdings[i].UnrealizedReturn /= fxDiv
-			holdings[i].DividendReturn /= fxDiv
-			if holdings[i].TrueBreakevenPrice != nil {
-				converted := *holdings[i].TrueBreakevenPrice / fxDiv
-				holdings[i].TrueBreakevenPrice = &converted
-			}
-			holdings[i].Currency = "AUD"
+	// Convert foreign holding values into the base currency. A rate is the
+	// BASE/QUOTE forex rate (e.g. AUDUSD = USD per 1 AUD), so value / rate.
+	// Holdings whose rate could not be fetched stay in their own currency.
+	for i := range holdings {
+		rate, ok := fxRates[holdings[i].Currency]
+		if !ok {
+			continue
 		}
+		convertHoldingCurrency(&holdings[i], baseCurrency, rate)
 	}
 
-	// Compute portfolio-level totals — all holdings are now in AUD (or unconverted if FX failed).
+	return holdings, fxRates, baseCurrency
+}
+
+// includeDividendsInReturn reports whether the strategy counts received
+// dividends in holding returns.
+func includeDividendsInReturn(strategy *models.PortfolioStrategy) bool {
+	return strategy != nil && strategy.IncomeRequirements.IncludeDividendsInReturn
+}
+
+// applyPortfolioTotals recomputes the portfolio-level totals, cash balances
+// and holding weights from portfolio.Holdings, which must already be in the
+// base currency (or unconverted where FX failed).
+func (s *Service) applyPortfolioTotals(ctx context.Context, portfolio *models.Portfolio, includeDividends bool) {
+	name := portfolio.Name
+	// Compute portfolio-level totals — all holdings are now in the base currency (or unconverted if FX failed).
 	var totalValue, totalCost, totalGain, totalDividends float64
 	var totalRealizedNetReturn, totalUnrealizedNetReturn float64
+	var dividendIncome, totalFees float64
+	feeTickers := make(map[string]bool) // holdings sharing a ticker carry the same merged trades
+	holdings := portfolio.Holdings
 	for _, h := range holdings {
 		totalValue += h.MarketValue
 		totalDividends += h.DividendReturn
 		totalGain += h.ReturnNet
+		if includeDividends {
+			totalGain -= h.DividendsReceived // already counted via Navexa's DividendReturn below
+		}
+		dividendIncome += h.DividendsReceived // apportioned across holdings of the same ticker
+		if !feeTickers[h.Ticker] {
+			feeTickers[h.Ticker] = true
+			totalFees += h.TotalFees
+		}
 		totalRealizedNetReturn += h.RealizedReturn
 		totalUnrealizedNetReturn += h.UnrealizedReturn
 		// Net capital in equities: buys - sells (all holdings, open + closed)
@@ -628,58 +1088,25 @@ func (s *Service) SyncPortfolio(ctx context.Context, name string, force bool) (*
 		totalGainPct = (totalGain / totalCost) * 100
 	}
 
-	// Compute trade hash for timeline invalidation detection.
-	// If trades or cash transactions have changed since last sync,
-	// the persisted timeline is stale and must be recomputed.
-	tradeHash := computeTradeHash(holdings)
-
-	portfolio := &models.Portfolio{
-		ID:                       name,
-		Name:                     name,
-		NavexaID:                 navexaPortfolio.ID,
-		Holdings:                 holdings,
-		EquityHoldingsValue:      totalValue,
-		PortfolioValue:           totalValue + availableCash,
-		EquityHoldingsCost:       totalCost,
-		EquityHoldingsReturn:     totalGain,
-		EquityHoldingsReturnPct:  totalGainPct,
-		Currency:                 navexaPortfolio.Currency,
-		FXRate:                   fxRate,
-		EquityHoldingsRealized:   totalRealizedNetReturn,
-		EquityHoldingsUnrealized: totalUnrealizedNetReturn,
-		IncomeDividendsForecast:  dividendForecast,
-		IncomeDividendsReceived:  ledgerDividends,
-		CalculationMethod:        "average_cost",
-		TradeHash:                tradeHash,
-		CapitalGross:             totalCash,
-		CapitalAvailable:         availableCash,
-		LastSynced:               time.Now(),
-	}
-
-	// Invalidate persisted timeline if trade data changed since last sync.
-	tradeHashChanged := existingTradeHash != "" && existingTradeHash != tradeHash
-	if tradeHashChanged {
-		s.logger.Info().Str("portfolio", name).Msg("Trade data changed — invalidating timeline cache")
-		userID := common.ResolveUserID(ctx)
-		if tl := s.storage.TimelineStore(); tl != nil {
-			if _, err := tl.DeleteAll(ctx, userID, name); err != nil {
-				s.logger.Warn().Err(err).Str("portfolio", name).Msg("Failed to invalidate timeline cache")
-			}
-		}
-	}
-
-	// Save portfolio
-	if err := s.savePortfolioRecord(ctx, portfolio); err != nil {
-		return nil, fmt.Errorf("failed to save portfolio: %w", err)
-	}
-
-	// Trigger explicit timeline rebuild when trades changed. Must happen after
-	// savePortfolioRecord so GetDailyGrowth reads the new trade data.
-	if tradeHashChanged {
-		s.triggerTimelineRebuildAsync(ctx, name)
-	}
+	portfolio.EquityHoldingsValue = totalValue
+	portfolio.PortfolioValue = totalValue + availableCash
+	portfolio.EquityHoldingsCost = totalCost
+	portfolio.EquityHoldingsReturn = totalGain
+	portfolio.EquityHoldingsReturnPct = totalGainPct
+	portfolio.EquityHoldingsRealized = totalRealizedNetReturn
+	portfolio.EquityHoldingsUnrealized = totalUnrealizedNetReturn
+	portfolio.IncomeDividendsForecast = dividendForecast
+	portfolio.IncomeDividendsReceived = ledgerDividends
+	portfolio.PortfolioDividendIncome = dividendIncome
+	portfolio.PortfolioTotalFees = totalFees
+	portfolio.CapitalGross = totalCash
+	portfolio.CapitalAvailable = availableCash
+}
 
-	// Upsert tickers to stock index for job manager tracking
+// upsertStockIndex records the holdings' tickers in the stock index so the
+// job manager keeps their market data collected.
+func (s *Service) upsertStockIndex(ctx context.Context, holdings []models.Holding) {
+	logger := s.logger.WithRequestID(ctx)
 	stockIndex := s.storage.StockIndexStore()
 	for _, h := range holdings {
 		entry := &models.StockIndexEntry{
@@ -690,35 +1117,29 @@ func (s *Service) SyncPortfolio(ctx context.Context, name string, force bool) (*
 			Source:   "portfolio",
 		}
 		if err := stockIndex.Upsert(ctx, entry); err != nil {
-			s.logger.Warn().Str("ticker", h.EODHDTicker()).Err(err).Msg("Failed to upsert stock index")
+			logger.Warn().Str("ticker", h.EODHDTicker()).Err(err).Msg("Failed to upsert stock index")
 		}
 	}
-
-	s.logger.Info().Str("name", name).Int("holdings", len(holdings)).Msg("Portfolio synced")
-
-	// Write today's timeline snapshot synchronously.
-	// This is the authoritative "today" value, updated every sync cycle (~5-30 min).
-	s.writeTodaySnapshot(ctx, portfolio)
-
-	// Backfill historical timeline if empty (e.g. after a schema rebuild).
-	// Runs in background to avoid blocking the sync response.
-	s.backfillTimelineIfEmpty(ctx, portfolio)
-
-	// Include non-equity asset set values in portfolio totals
-	s.populateAssetSetValues(ctx, portfolio)
-
-	// Populate historical values (yesterday/last week) from EOD market data
-	s.populateHistoricalValues(ctx, portfolio)
-
-	return portfolio, nil
 }
 
 // GetPortfolio retrieves a portfolio with current data
 func (s *Service) GetPortfolio(ctx context.Context, name string) (*models.Portfolio, error) {
+	portfolio, err := s.loadPortfolio(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	s.populateTaxEstimates(ctx, portfolio)
+	s.applyHoldingAnnotations(ctx, portfolio)
+	return portfolio, nil
+}
+
+// loadPortfolio returns the portfolio assembled for its source type, syncing
+// Navexa portfolios that are missing or stale.
+func (s *Service) loadPortfolio(ctx context.Context, name string) (*models.Portfolio, error) {
 	portfolio, err := s.getPortfolioRecord(ctx, name)
 	if err != nil {
 		// For Navexa portfolios: auto-sync on first access
-		if synced, syncErr := s.SyncPortfolio(ctx, name, false); syncErr == nil {
+		if synced, syncErr := s.syncPortfolio(ctx, name, common.FreshnessPortfolio); syncErr == nil {
 			return synced, nil
 		}
 		return nil, err
@@ -736,7 +1157,7 @@ func (s *Service) GetPortfolio(ctx context.Context, name string) (*models.Portfo
 	case models.SourceNavexa, "":
 		// Existing Navexa behaviour
 		if !common.IsFresh(portfolio.LastSynced, common.FreshnessPortfolio) {
-			if synced, syncErr := s.SyncPortfolio(ctx, name, false); syncErr == nil {
+			if synced, syncErr := s.syncPortfolio(ctx, name, common.FreshnessPortfolio); syncErr == nil {
 				synced.TimelineRebuilding = s.IsTimelineRebuilding(name)
 				return synced, nil
 			}
@@ -763,7 +1184,7 @@ func (s *Service) assembleManualPortfolio(ctx context.Context, portfolio *models
 	}
 
 	holdings := make([]models.Holding, 0, len(derived))
-	var totalEquityValue, totalCost, totalRealized, totalUnrealized, totalGrossInvested float64
+	var totalEquityValue, totalCost, totalRealized, totalUnrealized, totalGrossInvested, totalFees float64
 	for _, dh := range derived {
 		h := models.Holding{
 			Ticker:           dh.Ticker,
@@ -774,6 +1195,7 @@ func (s *Service) assembleManualPortfolio(ctx context.Context, portfolio *models
 			CostBasis:        dh.CostBasis,
 			GrossInvested:    dh.GrossInvested,
 			GrossProceeds:    dh.GrossProceeds,
+			TotalFees:        dh.TotalFees,
 			RealizedReturn:   dh.RealizedReturn,
 			UnrealizedReturn: dh.UnrealizedReturn,
 			SourceType:       models.SourceManual,
@@ -817,6 +1239,7 @@ func (s *Service) assembleManualPortfolio(ctx context.Context, portfolio *models
 			}
 		}
 
+		totalFees += h.TotalFees
 		holdings = append(holdings, h)
 	}
 
@@ -836,6 +1259,7 @@ func (s *Service) assembleManualPortfolio(ctx context.Context, portfolio *models
 	}
 	portfolio.EquityHoldingsRealized = totalRealized
 	portfolio.EquityHoldingsUnrealized = totalUnrealized
+	portfolio.PortfolioTotalFees = totalFees
 	portfolio.PortfolioValue = totalEquityValue + portfolio.CapitalGross
 	portfolio.CalculationMethod = "average_cost"
 
@@ -855,7 +1279,7 @@ func (s *Service) assembleSnapshotPortfolio(ctx context.Context, portfolio *mode
 	}
 
 	holdings := make([]models.Holding, 0, len(tb.SnapshotPositions))
-	var totalEquityValue, totalCost float64
+	var totalEquityValue, totalCost, totalFees float64
 	for _, sp := range tb.SnapshotPositions {
 		costBasis := sp.AvgCost * sp.Units
 		marketValue := sp.MarketValue
@@ -877,6 +1301,7 @@ func (s *Service) assembleSnapshotPortfolio(ctx context.Context, portfolio *mode
 			MarketValue:      marketValue,
 			CostBasis:        costBasis,
 			GrossInvested:    costBasis + sp.FeesTotal,
+			TotalFees:        sp.FeesTotal,
 			UnrealizedReturn: marketValue - costBasis,
 			SourceType:       models.SourceSnapshot,
 			SourceRef:        sp.SourceRef,
@@ -889,6 +1314,7 @@ func (s *Service) assembleSnapshotPortfolio(ctx context.Context, portfolio *mode
 
 		totalEquityValue += h.MarketValue
 		totalCost += h.CostBasis
+		totalFees += h.TotalFees
 		holdings = append(holdings, h)
 	}
 
@@ -907,6 +1333,7 @@ func (s *Service) assembleSnapshotPortfolio(ctx context.Context, portfolio *mode
 		portfolio.EquityHoldingsReturnPct = ((totalEquityValue - totalCost) / totalCost) * 100
 	}
 	portfolio.EquityHoldingsUnrealized = totalEquityValue - totalCost
+	portfolio.PortfolioTotalFees = totalFees
 	portfolio.PortfolioValue = totalEquityValue + portfolio.CapitalGross
 	portfolio.CalculationMethod = "snapshot"
 
@@ -971,16 +1398,22 @@ func (s *Service) populateHistoricalValues(ctx context.Context, portfolio *model
 	now := time.Now().Truncate(24 * time.Hour)
 	yesterday := now.AddDate(0, 0, -1)
 	lastWeek := now.AddDate(0, 0, -7)
+	lastMonth := now.AddDate(0, -1, 0)
+	lastQuarter := now.AddDate(0, -3, 0)
 
 	// Try timeline-sourced portfolio aggregates first.
-	timelineHit := s.populateFromTimeline(ctx, portfolio, yesterday, lastWeek)
+	timelineHit := s.populateFromTimeline(ctx, portfolio, yesterday, lastWeek, lastMonth, lastQuarter)
 	if timelineHit {
-		s.logger.Debug().Str("portfolio", portfolio.Name).Msg("Portfolio aggregates populated from timeline")
+		s.logger.WithRequestID(ctx).Debug().Str("portfolio", portfolio.Name).Msg("Portfolio aggregates populated from timeline")
 	}
 
 	// Per-holding historical prices always need market data (timeline doesn't store per-holding data).
 	// Also compute portfolio aggregates from market data if timeline wasn't available.
-	s.populateFromMarketData(ctx, portfolio, !timelineHit)
+	s.populateFromMarketData(ctx, portfolio, now, !timelineHit)
+
+	// Attribute the weekly return to the holdings that drove it; the
+	// handler recomputes for another window when the request asks for one
+	portfolio.SetReturnContributions(models.ReturnPeriodWeek)
 
 	// Compute breadth summary from holdings with trend data
 	s.computeBreadth(portfolio)
@@ -992,9 +1425,9 @@ func (s *Service) populateHistoricalValues(ctx context.Context, portfolio *model
 	s.populateChanges(ctx, portfolio)
 }
 
-// populateFromTimeline sets portfolio-level yesterday/lastWeek values from timeline snapshots.
-// Returns true if at least yesterday's snapshot was found.
-func (s *Service) populateFromTimeline(ctx context.Context, portfolio *models.Portfolio, yesterday, lastWeek time.Time) bool {
+// populateFromTimeline sets portfolio-level yesterday/lastWeek/lastMonth/lastQuarter
+// values from timeline snapshots. Returns true if at least yesterday's snapshot was found.
+func (s *Service) populateFromTimeline(ctx context.Context, portfolio *models.Portfolio, yesterday, lastWeek, lastMonth, lastQuarter time.Time) bool {
 	tl := s.storage.TimelineStore()
 	if tl == nil {
 		return false
@@ -1024,12 +1457,33 @@ func (s *Service) populateFromTimeline(ctx context.Context, portfolio *models.Po
 		}
 	}
 
+	// Get last month's snapshot
+	lastMonthSnaps, err := tl.GetRange(ctx, userID, portfolio.Name, lastMonth, lastMonth)
+	if err == nil && len(lastMonthSnaps) > 0 {
+		portfolio.PortfolioLastMonthValue = lastMonthSnaps[0].PortfolioValue
+		if portfolio.PortfolioLastMonthValue > 0 {
+			portfolio.PortfolioLastMonthChangePct = ((portfolio.PortfolioValue - portfolio.PortfolioLastMonthValue) / portfolio.PortfolioLastMonthValue) * 100
+		}
+	}
+
+	// Get last quarter's snapshot
+	lastQuarterSnaps, err := tl.GetRange(ctx, userID, portfolio.Name, lastQuarter, lastQuarter)
+	if err == nil && len(lastQuarterSnaps) > 0 {
+		portfolio.PortfolioLastQuarterValue = lastQuarterSnaps[0].PortfolioValue
+		if portfolio.PortfolioLastQuarterValue > 0 {
+			portfolio.PortfolioLastQuarterChangePct = ((portfolio.PortfolioValue - portfolio.PortfolioLastQuarterValue) / portfolio.PortfolioLastQuarterValue) * 100
+		}
+	}
+
 	return true
 }
 
 // populateFromMarketData loads EOD bars and sets per-holding historical prices.
-// When computeAggregates is true, also computes portfolio-level yesterday/lastWeek totals.
-func (s *Service) populateFromMarketData(ctx context.Context, portfolio *models.Portfolio, computeAggregates bool) {
+// Yesterday, last week, last month and last quarter are the closes on or
+// before 1 day, 7 days, 1 month and 3 months before now, so weekends and
+// holidays don't shift them. When computeAggregates is true, also computes
+// the matching portfolio-level totals.
+func (s *Service) populateFromMarketData(ctx context.Context, portfolio *models.Portfolio, now time.Time, computeAggregates bool) {
 	tickers := make([]string, 0, len(portfolio.Holdings))
 	for _, h := range portfolio.Holdings {
 		if h.Units > 0 {
@@ -1042,7 +1496,7 @@ func (s *Service) populateFromMarketData(ctx context.Context, portfolio *models.
 
 	allMarketData, err := s.storage.MarketDataStorage().GetMarketDataBatch(ctx, tickers)
 	if err != nil {
-		s.logger.Warn().Err(err).Msg("Failed to load market data for historical values")
+		s.logger.WithRequestID(ctx).Warn().Err(err).Msg("Failed to load market data for historical values")
 		return
 	}
 
@@ -1062,7 +1516,12 @@ func (s *Service) populateFromMarketData(ctx context.Context, portfolio *models.
 		}
 	}
 
-	var yesterdayTotal, lastWeekTotal float64
+	yesterday := now.AddDate(0, 0, -1)
+	lastWeek := now.AddDate(0, 0, -7)
+	lastMonth := now.AddDate(0, -1, 0)
+	lastQuarter := now.AddDate(0, -3, 0)
+
+	var yesterdayTotal, lastWeekTotal, lastMonthTotal, lastQuarterTotal float64
 
 	for i := range portfolio.Holdings {
 		h := &portfolio.Holdings[i]
@@ -1076,23 +1535,22 @@ func (s *Service) populateFromMarketData(ctx context.Context, portfolio *models.
 			continue
 		}
 
-		fxDiv := 1.0
-		if h.OriginalCurrency == "USD" && portfolio.FXRate > 0 {
-			fxDiv = portfolio.FXRate
-		}
+		fxDiv := quoteFXDiv(portfolio, h)
 
 		currentPrice := h.CurrentPrice
 
-		// EOD[0] is the most recent completed trading day (yesterday's close).
-		// EOD bars are only collected after market close, so EOD[0] is never "today".
-		yesterdayClose := eodClosePrice(md.EOD[0]) / fxDiv
-		h.YesterdayClosePrice = yesterdayClose
-		if yesterdayClose > 0 {
-			h.YesterdayPriceChangePct = ((currentPrice - yesterdayClose) / yesterdayClose) * 100
+		// The latest bar on or before yesterday is the previous trading day's
+		// close; a bar for today (collected after close) is skipped.
+		if bar := findEODBarByDate(md.EOD, yesterday); bar != nil {
+			yesterdayClose := eodClosePrice(*bar) / fxDiv
+			h.YesterdayClosePrice = yesterdayClose
+			if yesterdayClose > 0 {
+				h.YesterdayPriceChangePct = ((currentPrice - yesterdayClose) / yesterdayClose) * 100
+			}
+			yesterdayTotal += yesterdayClose * h.Units
 		}
-		yesterdayTotal += yesterdayClose * h.Units
 
-		if bar := findEODBarByOffset(md.EOD, 4); bar != nil {
+		if bar := findEODBarByDate(md.EOD, lastWeek); bar != nil {
 			lastWeekClose := eodClosePrice(*bar) / fxDiv
 			h.LastWeekClosePrice = lastWeekClose
 			if lastWeekClose > 0 {
@@ -1101,12 +1559,22 @@ func (s *Service) populateFromMarketData(ctx context.Context, portfolio *models.
 			lastWeekTotal += lastWeekClose * h.Units
 		}
 
-		if bar := findEODBarByOffset(md.EOD, 21); bar != nil {
+		if bar := findEODBarByDate(md.EOD, lastMonth); bar != nil {
 			lastMonthClose := eodClosePrice(*bar) / fxDiv
 			h.LastMonthClosePrice = lastMonthClose
 			if lastMonthClose > 0 {
 				h.LastMonthPriceChangePct = ((currentPrice - lastMonthClose) / lastMonthClose) * 100
 			}
+			lastMonthTotal += lastMonthClose * h.Units
+		}
+
+		if bar := findEODBarByDate(md.EOD, lastQuarter); bar != nil {
+			lastQuarterClose := eodClosePrice(*bar) / fxDiv
+			h.LastQuarterClosePrice = lastQuarterClose
+			if lastQuarterClose > 0 {
+				h.LastQuarterPriceChangePct = ((currentPrice - lastQuarterClose) / lastQuarterClose) * 100
+			}
+			lastQuarterTotal += lastQuarterClose * h.Units
 		}
 
 		if sigs := signalsByTicker[ticker]; sigs != nil && sigs.TrendMomentum.Level != "" {
@@ -1129,6 +1597,18 @@ func (s *Service) populateFromMarketData(ctx context.Context, portfolio *models.
 				portfolio.PortfolioLastWeekChangePct = ((portfolio.PortfolioValue - portfolio.PortfolioLastWeekValue) / portfolio.PortfolioLastWeekValue) * 100
 			}
 		}
+		if lastMonthTotal > 0 {
+			portfolio.PortfolioLastMonthValue = lastMonthTotal + portfolio.CapitalAvailable
+			if portfolio.PortfolioLastMonthValue > 0 {
+				portfolio.PortfolioLastMonthChangePct = ((portfolio.PortfolioValue - portfolio.PortfolioLastMonthValue) / portfolio.PortfolioLastMonthValue) * 100
+			}
+		}
+		if lastQuarterTotal > 0 {
+			portfolio.PortfolioLastQuarterValue = lastQuarterTotal + portfolio.CapitalAvailable
+			if portfolio.PortfolioLastQuarterValue > 0 {
+				portfolio.PortfolioLastQuarterChangePct = ((portfolio.PortfolioValue - portfolio.PortfolioLastQuarterValue) / portfolio.PortfolioLastQuarterValue) * 100
+			}
+		}
 	}
 }
 
@@ -1443,7 +1923,7 @@ func (s *Service) writeTodaySnapshot(ctx context.Context, portfolio *models.Port
 	}
 
 	if err := tl.SaveBatch(ctx, []models.TimelineSnapshot{snap}); err != nil {
-		s.logger.Warn().Err(err).Str("portfolio", portfolio.Name).Msg("Failed to write today's timeline snapshot")
+		s.logger.WithRequestID(ctx).Warn().Err(err).Str("portfolio", portfolio.Name).Msg("Failed to write today's timeline snapshot")
 	}
 }
 
@@ -1452,6 +1932,7 @@ func (s *Service) writeTodaySnapshot(ctx context.Context, portfolio *models.Port
 // This ensures the portfolio timeline chart populates automatically without requiring
 // a manual API call to /timeline.
 func (s *Service) backfillTimelineIfEmpty(ctx context.Context, portfolio *models.Portfolio) {
+	logger := s.logger.WithRequestID(ctx)
 	tl := s.storage.TimelineStore()
 	if tl == nil {
 		return
@@ -1478,7 +1959,7 @@ func (s *Service) backfillTimelineIfEmpty(ctx context.Context, portfolio *models
 		if len(snapshots) >= expectedDays/2 {
 			return // history sufficiently populated
 		}
-		s.logger.Info().Str("portfolio", portfolio.Name).Int("snapshots", len(snapshots)).Int("expected_days", expectedDays).Msg("Timeline history sparse — triggering backfill")
+		logger.Info().Str("portfolio", portfolio.Name).Int("snapshots", len(snapshots)).Int("expected_days", expectedDays).Msg("Timeline history sparse — triggering backfill")
 	}
 
 	// Skip backfill if a rebuild is already in progress
@@ -1486,11 +1967,11 @@ func (s *Service) backfillTimelineIfEmpty(ctx context.Context, portfolio *models
 		return
 	}
 
-	s.logger.Info().Str("portfolio", portfolio.Name).Msg("Timeline history empty — triggering background backfill")
+	logger.Info().Str("portfolio", portfolio.Name).Msg("Timeline history empty — triggering background backfill")
 	go func() {
 		defer func() {
 			if r := recover(); r != nil {
-				s.logger.Warn().Str("portfolio", portfolio.Name).Msgf("Timeline backfill panic recovered: %v", r)
+				logger.Warn().Str("portfolio", portfolio.Name).Msgf("Timeline backfill panic recovered: %v", r)
 			}
 		}()
 		bgCtx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
@@ -1498,7 +1979,7 @@ func (s *Service) backfillTimelineIfEmpty(ctx context.Context, portfolio *models
 		// Inject user context for the background goroutine
 		bgCtx = common.WithUserContext(bgCtx, common.UserContextFromContext(ctx))
 		if _, err := s.rebuildTimelineWithCash(bgCtx, portfolio.Name); err != nil {
-			s.logger.Warn().Err(err).Str("portfolio", portfolio.Name).Msg("Timeline backfill failed")
+			logger.Warn().Err(err).Str("portfolio", portfolio.Name).Msg("Timeline backfill failed")
 		}
 	}()
 }
@@ -1538,6 +2019,19 @@ func findEODBarByOffset(eod []models.EODBar, offset int) *models.EODBar {
 	return &eod[offset]
 }
 
+// findEODBarByDate returns the most recent EOD bar dated on or before the
+// target's calendar day. EOD slice is sorted descending (index 0 = most recent).
+// Returns nil if every bar is after the target.
+func findEODBarByDate(eod []models.EODBar, target time.Time) *models.EODBar {
+	day := target.Format("2006-01-02")
+	for i := range eod {
+		if eod[i].Date.Format("2006-01-02") <= day {
+			return &eod[i]
+		}
+	}
+	return nil
+}
+
 // ListPortfolios returns available portfolio names
 func (s *Service) ListPortfolios(ctx context.Context) ([]string, error) {
 	userID := common.ResolveUserID(ctx)
@@ -1552,16 +2046,66 @@ func (s *Service) ListPortfolios(ctx context.Context) ([]string, error) {
 	return names, nil
 }
 
+// saveSignals persists sigs, keeping the stored news sentiment when sigs
+// was computed without one.
+func (s *Service) saveSignals(ctx context.Context, sigs *models.TickerSignals) error {
+	store := s.storage.SignalStorage()
+	if sigs == nil {
+		return store.SaveSignals(ctx, sigs)
+	}
+	if prev, err := store.GetSignals(ctx, sigs.Ticker); err == nil {
+		sigs.KeepNewsSentiment(prev)
+	}
+	return store.SaveSignals(ctx, sigs)
+}
+
+// signalsForRSIPeriod returns stored when it was computed with the RSI
+// period rsiPeriod resolves to over marketData, else signals recomputed with
+// that period. Stored signals are shared across portfolios, so a recompute
+// for one strategy's period is not saved over them.
+func (s *Service) signalsForRSIPeriod(stored *models.TickerSignals, marketData *models.MarketData, rsiPeriod int) *models.TickerSignals {
+	if stored.GetRSIPeriod() == signals.EffectiveRSIPeriod(len(marketData.EOD), rsiPeriod) {
+		return stored
+	}
+	recomputed := s.signalComputer.ComputeWithRSIPeriod(marketData, rsiPeriod)
+	recomputed.KeepNewsSentiment(stored)
+	return recomputed
+}
+
 // ReviewPortfolio generates a portfolio review with signals
 func (s *Service) ReviewPortfolio(ctx context.Context, name string, options interfaces.ReviewOptions) (*models.PortfolioReview, error) {
+	review, _, err := s.reviewPortfolio(ctx, name, options)
+	return review, err
+}
+
+// StreamPortfolioReview runs the same review as ReviewPortfolio without the
+// blocking AI summary, then streams the summary from Gemini: text chunks on
+// the first channel, and the terminal error (nil on success) on the second.
+func (s *Service) StreamPortfolioReview(ctx context.Context, name string, options interfaces.ReviewOptions) (*models.PortfolioReview, <-chan string, <-chan error, error) {
+	if s.gemini == nil {
+		return nil, nil, nil, errors.New("AI analysis unavailable: Gemini not configured")
+	}
+	options.SkipSummary = true
+	review, strategy, err := s.reviewPortfolio(ctx, name, options)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	chunks, errs := s.gemini.AnalyzeStream(ctx, BuildReviewSummaryPrompt(review, strategy))
+	return review, chunks, errs, nil
+}
+
+// reviewPortfolio builds the review and returns the strategy it was
+// evaluated against (nil when none is stored).
+func (s *Service) reviewPortfolio(ctx context.Context, name string, options interfaces.ReviewOptions) (*models.PortfolioReview, *models.PortfolioStrategy, error) {
+	logger := s.logger.WithRequestID(ctx)
 	serviceStart := time.Now()
-	s.logger.Info().Str("name", name).Msg("Generating portfolio review")
+	logger.Info().Str("name", name).Msg("Generating portfolio review")
 
 	// Phase 1: Get portfolio and strategy
 	phaseStart := time.Now()
 	portfolio, err := s.GetPortfolio(ctx, name)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get portfolio: %w", err)
+		return nil, nil, fmt.Errorf("failed to get portfolio: %w", err)
 	}
 
 	// Load strategy (nil if none exists — all behaviour unchanged)
@@ -1577,7 +2121,7 @@ func (s *Service) ReviewPortfolio(ctx context.Context, name string, options inte
 			noteMap = hn.NoteMap()
 		}
 	}
-	s.logger.Info().Dur("elapsed", time.Since(phaseStart)).Msg("ReviewPortfolio: portfolio+strategy load complete")
+	logger.Info().Dur("elapsed", time.Since(phaseStart)).Msg("ReviewPortfolio: portfolio+strategy load complete")
 
 	review := &models.PortfolioReview{
 		PortfolioName:           name,
@@ -1592,7 +2136,7 @@ func (s *Service) ReviewPortfolio(ctx context.Context, name string, options inte
 	// Separate active and closed positions
 	activeHoldings, closedHoldings := filterClosedPositions(portfolio.Holdings)
 	if len(closedHoldings) > 0 {
-		s.logger.Info().
+		logger.Info().
 			Int("closed", len(closedHoldings)).
 			Int("active", len(activeHoldings)).
 			Msg("Separated closed positions (0 units)")
@@ -1610,37 +2154,47 @@ func (s *Service) ReviewPortfolio(ctx context.Context, name string, options inte
 	}
 	allMarketData, err := s.storage.MarketDataStorage().GetMarketDataBatch(ctx, tickers)
 	if err != nil {
-		s.logger.Warn().Err(err).Msg("Failed to batch load market data")
+		logger.Warn().Err(err).Msg("Failed to batch load market data")
 	}
 	mdByTicker := make(map[string]*models.MarketData, len(allMarketData))
 	for _, md := range allMarketData {
 		mdByTicker[md.Ticker] = md
 	}
-	s.logger.Info().Dur("elapsed", time.Since(phaseStart)).Int("tickers", len(tickers)).Msg("ReviewPortfolio: market data batch load complete")
+	logger.Info().Dur("elapsed", time.Since(phaseStart)).Int("tickers", len(tickers)).Msg("ReviewPortfolio: market data batch load complete")
 
-	// Phase 2b: Fetch real-time quotes for active holdings
+	// Phase 2b: Fetch real-time quotes for active holdings in batches.
+	// Holdings missing from the result fall back to their EOD close.
 	phaseStart = time.Now()
 	liveQuotes := make(map[string]*models.RealTimeQuote, len(tickers))
-	if s.eodhd != nil {
+	if s.eodhd != nil && len(tickers) > 0 {
+		quotes, err := s.eodhd.GetRealTimeQuotesBatch(ctx, tickers)
+		if errors.Is(err, interfaces.ErrCircuitOpen) {
+			logger.Warn().Err(err).Msg("Real-time quotes unavailable (circuit open) — using EOD closes")
+		} else if err != nil {
+			logger.Warn().Err(err).Msg("Real-time quotes unavailable — using EOD closes")
+		}
 		for _, ticker := range tickers {
-			if quote, err := s.eodhd.GetRealTimeQuote(ctx, ticker); err == nil && quote.Close > 0 {
+			if quote, ok := quotes[ticker]; ok && quote.Close > 0 {
 				liveQuotes[ticker] = quote
-			} else if err != nil {
-				s.logger.Warn().Str("ticker", ticker).Err(err).Msg("Real-time quote unavailable for holding")
+			} else if err == nil {
+				logger.Warn().Str("ticker", ticker).Msg("Real-time quote unavailable for holding")
 			}
 		}
 	}
-	s.logger.Info().Dur("elapsed", time.Since(phaseStart)).Int("live_quotes", len(liveQuotes)).Msg("ReviewPortfolio: real-time quotes complete")
+	logger.Info().Dur("elapsed", time.Since(phaseStart)).Int("live_quotes", len(liveQuotes)).Msg("ReviewPortfolio: real-time quotes complete")
 
 	// Phase 3: Holdings loop (signals + review)
 	phaseStart = time.Now()
+	// Holdings whose alerts were not evaluated keep their alert state
+	unevaluated := make(map[string]bool)
 	for _, holding := range activeHoldings {
 		ticker := holding.EODHDTicker()
 
 		// Get market data from pre-loaded batch
 		marketData := mdByTicker[ticker]
 		if marketData == nil {
-			s.logger.Warn().Str("ticker", ticker).Msg("No market data in batch — including holding without signals")
+			logger.Warn().Str("ticker", ticker).Msg("No market data in batch — including holding without signals")
+			unevaluated[strings.ToUpper(holding.Ticker)] = true
 			holdingReviews = append(holdingReviews, models.HoldingReview{
 				Holding:        holding,
 				ActionRequired: "HOLD",
@@ -1652,28 +2206,31 @@ func (s *Service) ReviewPortfolio(ctx context.Context, name string, options inte
 		// Get or compute signals (persist computed signals for future reuse)
 		tickerSignals, err := s.storage.SignalStorage().GetSignals(ctx, ticker)
 		if err != nil {
-			tickerSignals = s.signalComputer.Compute(marketData)
-			if saveErr := s.storage.SignalStorage().SaveSignals(ctx, tickerSignals); saveErr != nil {
-				s.logger.Warn().Err(saveErr).Str("ticker", ticker).Msg("Failed to persist computed signals")
+			tickerSignals = s.signalComputer.ComputeWithRSIPeriod(marketData, strategy.GetRSIPeriod())
+			if saveErr := s.saveSignals(ctx, tickerSignals); saveErr != nil {
+				logger.Warn().Err(saveErr).Str("ticker", ticker).Msg("Failed to persist computed signals")
 			}
+		} else {
+			tickerSignals = s.signalsForRSIPeriod(tickerSignals, marketData, strategy.GetRSIPeriod())
 		}
 
 		// Calculate overnight movement — prefer real-time price over EOD[0].Close.
-		// Live quotes and EOD bars are in native currency; holding values may be
-		// AUD-converted. Apply FX conversion for originally-USD holdings.
+		// Live quotes and EOD bars are in native currency (cents for some AU
+		// listings); holding values may be converted to the base currency.
+		// Apply the sync-time FX rate and cents scaling.
 		overnightMove := 0.0
 		overnightPct := 0.0
-		fxDiv := 1.0
-		if holding.OriginalCurrency == "USD" && portfolio.FXRate > 0 {
-			fxDiv = portfolio.FXRate
-		}
+		fxDiv := quoteFXDiv(portfolio, &holding)
 		if quote, ok := liveQuotes[ticker]; ok && len(marketData.EOD) > 1 {
 			prevClose := marketData.EOD[1].Close
 			overnightMove = (quote.Close - prevClose) / fxDiv
 			overnightPct = (overnightMove / (prevClose / fxDiv)) * 100
-			// Update holding with live price for the review (converted to AUD)
-			holding.CurrentPrice = quote.Close / fxDiv
-			holding.MarketValue = holding.CurrentPrice * holding.Units
+			// Update holding with live price for the review (converted to AUD),
+			// unless the strategy pins its price to Navexa
+			if strategy.PriceSourceFor(holding.Ticker, ticker) != models.PriceSourceNavexa {
+				holding.CurrentPrice = quote.Close / fxDiv
+				holding.MarketValue = holding.CurrentPrice * holding.Units
+			}
 		} else if len(marketData.EOD) > 1 {
 			overnightMove = (marketData.EOD[0].Close - marketData.EOD[1].Close) / fxDiv
 			overnightPct = (overnightMove / (marketData.EOD[1].Close / fxDiv)) * 100
@@ -1697,10 +2254,12 @@ func (s *Service) ReviewPortfolio(ctx context.Context, name string, options inte
 			sectorWeight := computeHoldingSectorWeight(holding, activeHoldings, marketData.Fundamentals)
 			holdingReview.Compliance = strategypkg.CheckCompliance(
 				strategy, &holding, tickerSignals, marketData.Fundamentals, sectorWeight)
+			holdingReview.DerivedMetrics = strategypkg.ComputeDerivedMetrics(strategy.DerivedMetrics,
+				strategypkg.RuleContext{Holding: &holding, Signals: tickerSignals, Fundamentals: marketData.Fundamentals})
 		}
 
 		// Attach holding note and derive signal confidence
-		if note, ok := noteMap[strings.ToUpper(holding.Ticker)]; ok {
+		if note, ok := holdingNoteFor(noteMap, holding); ok {
 			holdingReview.HoldingNote = note
 			holdingReview.SignalConfidence = note.DeriveSignalConfidence()
 			holdingReview.NoteStale = note.IsStale()
@@ -1727,9 +2286,18 @@ func (s *Service) ReviewPortfolio(ctx context.Context, name string, options inte
 		// Track day change
 		dayChange += overnightMove * holding.Units
 
+		// Muted holdings (alerts_muted on the holding note) raise no alerts
+		if holdingReview.HoldingNote.Muted() {
+			unevaluated[strings.ToUpper(holding.Ticker)] = true
+			continue
+		}
+
 		// Generate alerts (strategy-aware)
 		holdingAlerts := generateAlerts(holding, tickerSignals, options.FocusSignals, strategy)
 		alerts = append(alerts, holdingAlerts...)
+		if alert, ok := earningsAlert(holding, marketData.Fundamentals, strategy, time.Now()); ok {
+			alerts = append(alerts, alert)
+		}
 
 		// Stale note alert
 		if holdingReview.NoteStale {
@@ -1742,7 +2310,7 @@ func (s *Service) ReviewPortfolio(ctx context.Context, name string, options inte
 			})
 		}
 	}
-	s.logger.Info().Dur("elapsed", time.Since(phaseStart)).Int("holdings", len(activeHoldings)).Msg("ReviewPortfolio: holdings loop complete")
+	logger.Info().Dur("elapsed", time.Since(phaseStart)).Int("holdings", len(activeHoldings)).Msg("ReviewPortfolio: holdings loop complete")
 
 	// Add closed positions (no market data or signals needed)
 	for _, holding := range closedHoldings {
@@ -1753,8 +2321,32 @@ func (s *Service) ReviewPortfolio(ctx context.Context, name string, options inte
 		})
 	}
 
+	// Plan checks: drift from plan items, and planned sells that would
+	// forfeit the CGT discount (nil plan = no alerts)
+	if plan, err := s.getPlanRecord(ctx, name); err == nil {
+		now := time.Now()
+		alerts = append(alerts, planpkg.DetectDrift(plan, portfolio.Holdings, now)...)
+		review.CGTWarnings = planShortHoldWarnings(plan, portfolio.Holdings, now, s.minHoldDays)
+		for _, w := range review.CGTWarnings {
+			alerts = append(alerts, cgtShortHoldAlert(w))
+		}
+	}
+
+	// Sector concentration against the strategy's max_sector_pct
+	review.SectorAllocation = buildSectorBreakdown(name, holdingReviews)
+	alerts = append(alerts, sectorConcentrationAlerts(review.SectorAllocation, strategy)...)
+
+	// Holding concentration (HHI) against the strategy's max_hhi
+	review.HHI, review.EffectiveHoldings = holdingConcentration(holdingReviews)
+	if alert, ok := concentrationAlert(review.HHI, review.EffectiveHoldings, strategy); ok {
+		alerts = append(alerts, alert)
+	}
+
+	// Pairwise return correlation against the strategy's max_correlation
+	alerts = append(alerts, correlationAlerts(holdingReviews, mdByTicker, strategy)...)
+
 	review.HoldingReviews = holdingReviews
-	review.Alerts = alerts
+	review.Alerts, review.SuppressedAlerts = s.reconcileAlerts(ctx, name, alerts, unevaluated, time.Now())
 	review.PortfolioDayChange = dayChange
 
 	// Recompute PortfolioValue from live-updated holdings (all values already in AUD)
@@ -1773,15 +2365,19 @@ func (s *Service) ReviewPortfolio(ctx context.Context, name string, options inte
 	}
 
 	// Phase 3: Generate AI summary if available
-	if s.gemini != nil {
+	if s.gemini != nil && !options.SkipSummary {
 		phaseStart = time.Now()
 		summary, err := s.generateReviewSummary(ctx, review, strategy)
-		if err != nil {
-			s.logger.Warn().Err(err).Msg("Failed to generate AI summary")
+		var filtered *interfaces.ContentFilteredError
+		if errors.As(err, &filtered) {
+			logger.Warn().Str("reason", filtered.Reason).Msg("AI summary blocked by content filter")
+			review.Summary = s.filteredNote
+		} else if err != nil {
+			logger.Warn().Err(err).Msg("Failed to generate AI summary")
 		} else {
 			review.Summary = summary
 		}
-		s.logger.Info().Dur("elapsed", time.Since(phaseStart)).Msg("ReviewPortfolio: AI summary complete")
+		logger.Info().Dur("elapsed", time.Since(phaseStart)).Msg("ReviewPortfolio: AI summary complete")
 	}
 
 	// Generate observations (strategy-aware)
@@ -1794,32 +2390,53 @@ func (s *Service) ReviewPortfolio(ctx context.Context, name string, options inte
 	if indicators, err := s.GetPortfolioIndicators(ctx, name); err == nil {
 		review.PortfolioIndicators = indicators
 	} else {
-		s.logger.Warn().Err(err).Msg("Failed to compute portfolio indicators")
+		logger.Warn().Err(err).Msg("Failed to compute portfolio indicators")
+	}
+
+	// Value series for the benchmark comparison, cash drag and the value waterfall
+	growth, err := s.GetDailyGrowth(ctx, name, interfaces.GrowthOptions{})
+	if err != nil {
+		logger.Warn().Err(err).Msg("Failed to compute daily growth for review")
+	}
+
+	// Compare against the requested benchmark (zero fields when data is missing)
+	s.applyBenchmark(ctx, review, portfolio, growth, options.BenchmarkTicker)
+
+	if drag, ok := computeCashDrag(growth); ok {
+		review.CashDragPct = drag
 	}
 
+	var ledger *models.CashFlowLedger
+	if s.cashflowSvc != nil {
+		if l, err := s.cashflowSvc.GetLedger(ctx, name); err == nil {
+			ledger = l
+		}
+	}
+	review.Waterfall = buildWaterfall(portfolio, growth, ledger, s.costBasisMethod(ctx, name))
+
 	// Update strategy LastReviewedAt
 	if strategy != nil {
 		strategy.LastReviewedAt = time.Now()
 		if err := s.saveStrategyRecord(ctx, strategy); err != nil {
-			s.logger.Warn().Err(err).Msg("Failed to update strategy LastReviewedAt")
+			logger.Warn().Err(err).Msg("Failed to update strategy LastReviewedAt")
 		}
 	}
 
-	s.logger.Info().
+	logger.Info().
 		Str("name", name).
 		Int("holdings", len(holdingReviews)).
 		Int("alerts", len(alerts)).
 		Dur("elapsed", time.Since(serviceStart)).
 		Msg("ReviewPortfolio: TOTAL")
 
-	return review, nil
+	return review, strategy, nil
 }
 
 // ReviewWatchlist generates a review with signals for watchlist tickers.
 // Follows the same pattern as ReviewPortfolio but operates on watchlist items
 // rather than portfolio holdings.
 func (s *Service) ReviewWatchlist(ctx context.Context, name string, options interfaces.ReviewOptions) (*models.WatchlistReview, error) {
-	s.logger.Info().Str("name", name).Msg("Generating watchlist review")
+	s.logger.WithRequestID(ctx).Info().Str("name", name).Msg("Generating watchlist review")
 
 	// 1. Load watchlist from storage
 	userID := common.ResolveUserID(ctx)
@@ -1890,10 +2507,12 @@ func (s *Service) ReviewWatchlist(ctx context.Context, name string, options inte
 		// Get or compute signals
 		tickerSignals, err := s.storage.SignalStorage().GetSignals(ctx, item.Ticker)
 		if err != nil {
-			tickerSignals = s.signalComputer.Compute(marketData)
-			if saveErr := s.storage.SignalStorage().SaveSignals(ctx, tickerSignals); saveErr != nil {
-				s.logger.Warn().Err(saveErr).Str("ticker", item.Ticker).Msg("Failed to persist computed signals")
+			tickerSignals = s.signalComputer.ComputeWithRSIPeriod(marketData, strategy.GetRSIPeriod())
+			if saveErr := s.saveSignals(ctx, tickerSignals); saveErr != nil {
+				s.logger.WithRequestID(ctx).Warn().Err(saveErr).Str("ticker", item.Ticker).Msg("Failed to persist computed signals")
 			}
+		} else {
+			tickerSignals = s.signalsForRSIPeriod(tickerSignals, marketData, strategy.GetRSIPeriod())
 		}
 
 		// Overnight movement
@@ -1983,17 +2602,34 @@ func filterClosedPositions(holdings []models.Holding) (active, closed []models.H
 // strategyRSIThresholds returns the RSI overbought (sell) and oversold (buy) thresholds
 // adjusted for the portfolio strategy's risk appetite level.
 func strategyRSIThresholds(strategy *models.PortfolioStrategy) (overboughtSell float64, oversoldBuy float64) {
-	if strategy == nil || strategy.RiskAppetite.Level == "" {
-		return 70, 30 // defaults (moderate)
+	return strategy.RSIThresholds()
+}
+
+// exitRuleAction checks the holding's unrealized return against the
+// strategy's stop-loss and take-profit levels. The return is measured from
+// TrueBreakevenPrice when present, falling back to AvgCost. Either level
+// triggers once the return reaches it.
+func exitRuleAction(sizing models.PositionSizing, holding *models.Holding) (string, string) {
+	if sizing.StopLossPct <= 0 && sizing.TakeProfitPct <= 0 {
+		return "", ""
+	}
+	ref, refLabel := holding.AvgCost, "average cost"
+	if holding.TrueBreakevenPrice != nil && *holding.TrueBreakevenPrice > 0 {
+		ref, refLabel = *holding.TrueBreakevenPrice, "breakeven"
+	}
+	if ref <= 0 || holding.CurrentPrice <= 0 {
+		return "", ""
 	}
-	switch strings.ToLower(strategy.RiskAppetite.Level) {
-	case "conservative":
-		return 65, 35 // sell earlier, buy later (more cautious)
-	case "aggressive":
-		return 80, 25 // sell later, buy earlier (more risk-tolerant)
-	default: // "moderate" or unknown
-		return 70, 30
+	returnPct := (holding.CurrentPrice - ref) / ref * 100
+	if sizing.StopLossPct > 0 && returnPct <= -sizing.StopLossPct {
+		return "EXIT TRIGGER", fmt.Sprintf("Stop-loss: %.1f%% from %s %.2f breaches -%.1f%%",
+			returnPct, refLabel, ref, sizing.StopLossPct)
 	}
+	if sizing.TakeProfitPct > 0 && returnPct >= sizing.TakeProfitPct {
+		return "TAKE PROFIT", fmt.Sprintf("Take-profit: +%.1f%% from %s %.2f reaches +%.1f%%",
+			returnPct, refLabel, ref, sizing.TakeProfitPct)
+	}
+	return "", ""
 }
 
 // determineAction determines the compliance status for a holding.
@@ -2015,6 +2651,13 @@ func determineAction(signals *models.TickerSignals, focusSignals []string, strat
 
 	rsiOverbought, rsiOversold := strategyRSIThresholds(strategy)
 
+	// Strategy: stop-loss / take-profit on the unrealized return
+	if strategy != nil && holding != nil {
+		if action, reason := exitRuleAction(strategy.PositionSizing, holding); action != "" {
+			return action, reason
+		}
+	}
+
 	// Strategy: position weight exceeds max
 	if strategy != nil && holding != nil && strategy.PositionSizing.MaxPositionPct > 0 {
 		if holding.WeightPct > strategy.PositionSizing.MaxPositionPct {
@@ -2039,10 +2682,17 @@ func determineAction(signals *models.TickerSignals, focusSignals []string, strat
 		return "EXIT TRIGGER", fmt.Sprintf("Strong downtrend: %.1f%% over 3d, %.1f%% over 10d",
 			signals.TrendMomentum.PriceChange3D, signals.TrendMomentum.PriceChange10D)
 	}
+	// Strategy: large position reporting earnings soon (event risk)
+	if reason := earningsWatchReason(holding, fundamentals, strategy, time.Now()); reason != "" {
+		return "WATCH", reason
+	}
 	if signals.TrendMomentum.Level == models.TrendMomentumDown {
 		return "WATCH", fmt.Sprintf("Deteriorating trend: %.1f%% over 3d, %.1f%% over 10d",
 			signals.TrendMomentum.PriceChange3D, signals.TrendMomentum.PriceChange10D)
 	}
+	if signals.Technical.MACDCrossEvent == "bearish_cross" {
+		return "WATCH", "MACD bearish cross (histogram turned negative)"
+	}
 
 	// Check for entry criteria
 	if signals.Technical.RSI < rsiOversold {
@@ -2051,6 +2701,17 @@ func determineAction(signals *models.TickerSignals, focusSignals []string, strat
 	if signals.Technical.SMA20CrossSMA50 == "golden_cross" {
 		return "ENTRY CRITERIA MET", "Recent golden cross (SMA20 above SMA50)"
 	}
+	if signals.Technical.MACDCrossEvent == "bullish_cross" {
+		return "ENTRY CRITERIA MET", "MACD bullish cross (histogram turned positive)"
+	}
+
+	// 50/200-day cross: a tie-breaker only, after every explicit exit and entry
+	if signals.Technical.SMA50CrossSMA200 == "death_cross" {
+		return "EXIT TRIGGER", "Fresh death cross (SMA50 below SMA200)"
+	}
+	if signals.Technical.SMA50CrossSMA200 == "golden_cross" {
+		return "ENTRY CRITERIA MET", "Fresh golden cross (SMA50 above SMA200)"
+	}
 
 	// Check for watch signals
 	if signals.Technical.NearSupport {
@@ -2063,6 +2724,10 @@ func determineAction(signals *models.TickerSignals, focusSignals []string, strat
 	return "COMPLIANT", "All indicators within tolerance"
 }
 
+// newsSentimentAlertThreshold is the news sentiment score (-1..+1) below
+// which a holding raises news_sentiment_negative.
+const newsSentimentAlertThreshold = -0.3
+
 // generateAlerts creates alerts for a holding (strategy-aware)
 func generateAlerts(holding models.Holding, signals *models.TickerSignals, focusSignals []string, strategy *models.PortfolioStrategy) []models.Alert {
 	alerts := make([]models.Alert, 0)
@@ -2109,15 +2774,74 @@ func generateAlerts(holding models.Holding, signals *models.TickerSignals, focus
 			Signal:   "golden_cross",
 		})
 	}
+	if signals.Technical.SMA50CrossSMA200 == "death_cross" {
+		alerts = append(alerts, models.Alert{
+			Type:     models.AlertTypeSignal,
+			Severity: "high",
+			Ticker:   holding.Ticker,
+			Message: fmt.Sprintf("%s has a death cross: SMA50 %.2f crossed below SMA200 %.2f",
+				holding.Ticker, signals.Price.SMA50, signals.Price.SMA200),
+			Signal: "death_cross_50_200",
+		})
+	} else if signals.Technical.SMA50CrossSMA200 == "golden_cross" {
+		alerts = append(alerts, models.Alert{
+			Type:     models.AlertTypeSignal,
+			Severity: "medium",
+			Ticker:   holding.Ticker,
+			Message: fmt.Sprintf("%s has a golden cross: SMA50 %.2f crossed above SMA200 %.2f",
+				holding.Ticker, signals.Price.SMA50, signals.Price.SMA200),
+			Signal: "golden_cross_50_200",
+		})
+	}
+
+	// MACD crossover alerts (no cross with fewer bars than slow+signal periods)
+	if signals.Technical.MACDCrossEvent == "bearish_cross" {
+		alerts = append(alerts, models.Alert{
+			Type:     models.AlertTypeSignal,
+			Severity: "medium",
+			Ticker:   holding.Ticker,
+			Message:  fmt.Sprintf("%s MACD bearish cross (histogram %.4f)", holding.Ticker, signals.Technical.MACDHistogram),
+			Signal:   "macd_bearish_cross",
+		})
+	} else if signals.Technical.MACDCrossEvent == "bullish_cross" {
+		alerts = append(alerts, models.Alert{
+			Type:     models.AlertTypeSignal,
+			Severity: "low",
+			Ticker:   holding.Ticker,
+			Message:  fmt.Sprintf("%s MACD bullish cross (histogram %.4f)", holding.Ticker, signals.Technical.MACDHistogram),
+			Signal:   "macd_bullish_cross",
+		})
+	}
 
-	// Volume alerts
-	if signals.Technical.VolumeSignal == "spike" {
+	// Bollinger Band alerts (fields are zero with fewer than 20 bars)
+	if signals.Technical.BBBreakout != "" {
+		alerts = append(alerts, models.Alert{
+			Type:     models.AlertTypeSignal,
+			Severity: "medium",
+			Ticker:   holding.Ticker,
+			Message: fmt.Sprintf("%s broke %s out of its Bollinger Bands after a squeeze (close %.2f, bands %.2f-%.2f)",
+				holding.Ticker, signals.Technical.BBBreakout, signals.Price.Current, signals.Technical.BBLower, signals.Technical.BBUpper),
+			Signal: "bb_breakout",
+		})
+	} else if signals.Technical.BBSqueeze && signals.Technical.BBWidth > 0 {
+		alerts = append(alerts, models.Alert{
+			Type:     models.AlertTypeSignal,
+			Severity: "low",
+			Ticker:   holding.Ticker,
+			Message:  fmt.Sprintf("%s Bollinger Band squeeze: width %.1f%% is in the lowest decile of 6 months", holding.Ticker, signals.Technical.BBWidth*100),
+			Signal:   "bb_squeeze",
+		})
+	}
+
+	// Volume alerts (silent without volume data: zero average or zero today)
+	if spikeAt := strategy.GetVolumeSpikeMultiple(); signals.Technical.AvgVolume20 > 0 && signals.Technical.VolumeRatio > spikeAt {
 		alerts = append(alerts, models.Alert{
 			Type:     models.AlertTypeVolume,
 			Severity: "medium",
 			Ticker:   holding.Ticker,
-			Message:  fmt.Sprintf("%s has unusual volume (%.1fx average)", holding.Ticker, signals.Technical.VolumeRatio),
-			Signal:   "volume_spike",
+			Message: fmt.Sprintf("%s has unusual volume (%.1fx the 20-day average of %d, threshold %.1fx)",
+				holding.Ticker, signals.Technical.VolumeRatio, signals.Technical.AvgVolume20, spikeAt),
+			Signal: "volume_spike",
 		})
 	}
 
@@ -2151,6 +2875,18 @@ func generateAlerts(holding models.Holding, signals *models.TickerSignals, focus
 		})
 	}
 
+	// News sentiment alert (scored by compute_signals from fresh headlines)
+	if signals.NewsSentimentCount >= models.MinNewsSentimentArticles && signals.NewsSentiment < newsSentimentAlertThreshold {
+		alerts = append(alerts, models.Alert{
+			Type:     models.AlertTypeNews,
+			Severity: "medium",
+			Ticker:   holding.Ticker,
+			Message: fmt.Sprintf("%s news sentiment is negative at %.2f across %d recent articles",
+				holding.Ticker, signals.NewsSentiment, signals.NewsSentimentCount),
+			Signal: "news_sentiment_negative",
+		})
+	}
+
 	// Strategy-alignment alerts
 	if strategy != nil {
 		// Position size exceeds strategy max
@@ -2213,7 +2949,7 @@ func generateRecommendations(review *models.PortfolioReview, strategy *models.Po
 
 	for _, hr := range review.HoldingReviews {
 		switch hr.ActionRequired {
-		case "EXIT TRIGGER":
+		case "EXIT TRIGGER", "TAKE PROFIT":
 			sellCount++
 		case "ENTRY CRITERIA MET":
 			buyCount++
@@ -2279,11 +3015,13 @@ func generateRecommendations(review *models.PortfolioReview, strategy *models.Po
 
 // generateReviewSummary creates an AI summary of the portfolio review
 func (s *Service) generateReviewSummary(ctx context.Context, review *models.PortfolioReview, strategy *models.PortfolioStrategy) (string, error) {
-	prompt := buildReviewSummaryPrompt(review, strategy)
+	prompt := BuildReviewSummaryPrompt(review, strategy)
 	return s.gemini.GenerateContent(ctx, prompt)
 }
 
-func buildReviewSummaryPrompt(review *models.PortfolioReview, strategy *models.PortfolioStrategy) string {
+// BuildReviewSummaryPrompt builds the Gemini prompt summarising a review,
+// with strategy context when strategy is non-nil.
+func BuildReviewSummaryPrompt(review *models.PortfolioReview, strategy *models.PortfolioStrategy) string {
 	prompt := fmt.Sprintf(`Summarize this portfolio review for %s:
 
 Portfolio Value: $%.2f
@@ -2576,13 +3314,51 @@ func computeHoldingSectorWeight(holding models.Holding, allHoldings []models.Hol
 	return holding.WeightPct
 }
 
+// applyHoldingAnnotations copies each holding's stored user note and tags
+// onto the response. Annotations live apart from the synced portfolio, so
+// they survive re-syncs and positions that drop out and come back.
+func (s *Service) applyHoldingAnnotations(ctx context.Context, portfolio *models.Portfolio) {
+	if s.holdingNoteService == nil || portfolio == nil {
+		return
+	}
+	annotations, err := s.holdingNoteService.GetAnnotations(ctx, portfolio.Name)
+	if err != nil {
+		s.logger.WithRequestID(ctx).Warn().Err(err).Str("portfolio", portfolio.Name).Msg("Failed to load holding annotations")
+		return
+	}
+	for i := range portfolio.Holdings {
+		h := &portfolio.Holdings[i]
+		a, ok := annotations[strings.ToUpper(h.Ticker)]
+		if !ok {
+			a, ok = annotations[strings.ToUpper(h.EODHDTicker())]
+		}
+		if ok {
+			h.UserNote = a.Note
+			h.Tags = a.Tags
+		}
+	}
+}
+
+// holdingNoteFor finds a holding's note by ticker, falling back to the
+// EODHD ticker for notes stored with an exchange suffix.
+func holdingNoteFor(noteMap map[string]*models.HoldingNote, h models.Holding) (*models.HoldingNote, bool) {
+	if note, ok := noteMap[strings.ToUpper(h.Ticker)]; ok {
+		return note, true
+	}
+	note, ok := noteMap[strings.ToUpper(h.EODHDTicker())]
+	return note, ok
+}
+
 // --- UserDataStore helpers ---
 
 func (s *Service) getPortfolioRecord(ctx context.Context, name string) (*models.Portfolio, error) {
 	userID := common.ResolveUserID(ctx)
 	rec, err := s.storage.UserDataStore().Get(ctx, userID, "portfolio", name)
+	if errors.Is(err, interfaces.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%w: '%s'", interfaces.ErrPortfolioNotFound, name)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("portfolio '%s' not found: %w", name, err)
+		return nil, fmt.Errorf("failed to load portfolio '%s': %w", name, err)
 	}
 	var portfolio models.Portfolio
 	if err := json.Unmarshal([]byte(rec.Value), &portfolio); err != nil {
@@ -2615,11 +3391,27 @@ func (s *Service) getStrategyRecord(ctx context.Context, portfolioName string) (
 	if err != nil {
 		return nil, fmt.Errorf("strategy for '%s' not found: %w", portfolioName, err)
 	}
-	var strategy models.PortfolioStrategy
-	if err := json.Unmarshal([]byte(rec.Value), &strategy); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal strategy: %w", err)
+	strategy, dropped, err := models.DecodeStrategy([]byte(rec.Value), s.strictStrategy)
+	if err != nil {
+		return nil, err
+	}
+	if len(dropped) > 0 {
+		s.logger.WithRequestID(ctx).Warn().Str("portfolio", portfolioName).Strs("fields", dropped).Msg("Strategy fields from an older release could not be decoded and were reset")
+	}
+	return strategy, nil
+}
+
+func (s *Service) getPlanRecord(ctx context.Context, portfolioName string) (*models.PortfolioPlan, error) {
+	userID := common.ResolveUserID(ctx)
+	rec, err := s.storage.UserDataStore().Get(ctx, userID, "plan", portfolioName)
+	if err != nil {
+		return nil, fmt.Errorf("plan for '%s' not found: %w", portfolioName, err)
 	}
-	return &strategy, nil
+	var plan models.PortfolioPlan
+	if err := json.Unmarshal([]byte(rec.Value), &plan); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal plan: %w", err)
+	}
+	return &plan, nil
 }
 
 func (s *Service) saveStrategyRecord(ctx context.Context, strategy *models.PortfolioStrategy) error {
@@ -2636,6 +3428,16 @@ func (s *Service) saveStrategyRecord(ctx context.Context, strategy *models.Portf
 	})
 }
 
+// isCentsQuote reports whether an EODHD price looks like the Navexa price
+// quoted in cents: a ratio of roughly 100x (80–125x allows for a day's move).
+func isCentsQuote(eodhPrice, navexaPrice float64) bool {
+	if eodhPrice <= 0 || navexaPrice <= 0 {
+		return false
+	}
+	ratio := eodhPrice / navexaPrice
+	return ratio >= 80 && ratio <= 125
+}
+
 // eodClosePrice returns the best available close price from an EOD bar.
 // Prefers AdjClose (adjusted for corporate actions like consolidations) when
 // available and positive; falls back to Close otherwise.
@@ -2657,6 +3459,19 @@ func eodClosePrice(bar models.EODBar) float64 {
 	return bar.Close
 }
 
+// defaultPriceFreshness is how old the latest EOD bar can be before its
+// close is treated as stale rather than a current price.
+const defaultPriceFreshness = 24 * time.Hour
+
+// eodClosePriceFresh returns the bar's close (see eodClosePrice) and whether
+// the bar is stale — at least maxAge old at now — so callers can decide
+// whether to present it as a current price. A non-positive maxAge disables
+// the check.
+func eodClosePriceFresh(bar models.EODBar, now time.Time, maxAge time.Duration) (float64, bool) {
+	stale := maxAge > 0 && now.Sub(bar.Date) >= maxAge
+	return eodClosePrice(bar), stale
+}
+
 // holdingCalcMetrics stores per-holding calculation results computed during
 // trade processing, used to populate Holding model fields after conversion.
 type holdingCalcMetrics struct {
@@ -1,17 +1,22 @@
 package portfolio
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"math"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/bobmcallan/vire/internal/common"
 	"github.com/bobmcallan/vire/internal/interfaces"
 	"github.com/bobmcallan/vire/internal/models"
+	fxpkg "github.com/bobmcallan/vire/internal/services/fx"
 )
 
 func approxEqual(a, b, epsilon float64) bool {
@@ -149,7 +154,8 @@ func TestCalculateRealizedFromTrades_ETPMAG_RealWorld(t *testing.T) {
 	}
 
 	// Realized = 62586.71 - 48212.78 = 14373.93
-	// Note: Navexa reports $14,373.25 — small difference due to FIFO vs average cost method
+	// Note: Navexa reports $14,373.25. The position is fully closed, so FIFO
+	// gives the same figure (see TestCalculateRealizedFIFO_ETPMAG_FullyClosed).
 	if !approxEqual(realized, 14373.93, 1.00) {
 		t.Errorf("realized = %.2f, want ~14373.93", realized)
 	}
@@ -311,6 +317,89 @@ func TestDetermineAction_StrategyRSI(t *testing.T) {
 	}
 }
 
+func TestDetermineAction_StopLossTakeProfit(t *testing.T) {
+	breakeven := func(v float64) *float64 { return &v }
+	strategy := &models.PortfolioStrategy{
+		PositionSizing: models.PositionSizing{StopLossPct: 10, TakeProfitPct: 25},
+	}
+
+	tests := []struct {
+		name       string
+		holding    *models.Holding
+		wantAction string
+	}{
+		{
+			name:       "below stop-loss (-12% from avg cost)",
+			holding:    &models.Holding{AvgCost: 100, CurrentPrice: 88},
+			wantAction: "EXIT TRIGGER",
+		},
+		{
+			name:       "at stop-loss (-10% from avg cost)",
+			holding:    &models.Holding{AvgCost: 100, CurrentPrice: 90},
+			wantAction: "EXIT TRIGGER",
+		},
+		{
+			name:       "above stop-loss (-9% from avg cost)",
+			holding:    &models.Holding{AvgCost: 100, CurrentPrice: 91},
+			wantAction: "COMPLIANT",
+		},
+		{
+			name:       "below take-profit (+24% from avg cost)",
+			holding:    &models.Holding{AvgCost: 100, CurrentPrice: 124},
+			wantAction: "COMPLIANT",
+		},
+		{
+			name:       "at take-profit (+25% from avg cost)",
+			holding:    &models.Holding{AvgCost: 100, CurrentPrice: 125},
+			wantAction: "TAKE PROFIT",
+		},
+		{
+			name:       "above take-profit (+30% from avg cost)",
+			holding:    &models.Holding{AvgCost: 100, CurrentPrice: 130},
+			wantAction: "TAKE PROFIT",
+		},
+		{
+			name:       "breakeven preferred over avg cost (-10% from breakeven 100, -5% from avg 94.7)",
+			holding:    &models.Holding{AvgCost: 94.74, TrueBreakevenPrice: breakeven(100), CurrentPrice: 90},
+			wantAction: "EXIT TRIGGER",
+		},
+		{
+			name:       "breakeven preferred over avg cost (+20% from breakeven 100, +26% from avg 95)",
+			holding:    &models.Holding{AvgCost: 95, TrueBreakevenPrice: breakeven(100), CurrentPrice: 120},
+			wantAction: "COMPLIANT",
+		},
+		{
+			name:       "zero breakeven falls back to avg cost",
+			holding:    &models.Holding{AvgCost: 100, TrueBreakevenPrice: breakeven(0), CurrentPrice: 125},
+			wantAction: "TAKE PROFIT",
+		},
+		{
+			name:       "no reference price",
+			holding:    &models.Holding{CurrentPrice: 50},
+			wantAction: "COMPLIANT",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signals := &models.TickerSignals{
+				Technical: models.TechnicalSignals{RSI: 50},
+			}
+			action, reason := determineAction(signals, nil, strategy, tt.holding, nil)
+			if action != tt.wantAction {
+				t.Errorf("determineAction = %q (%s), want %q", action, reason, tt.wantAction)
+			}
+		})
+	}
+
+	// No levels configured: the same losing holding stays compliant
+	action, _ := determineAction(&models.TickerSignals{Technical: models.TechnicalSignals{RSI: 50}}, nil,
+		&models.PortfolioStrategy{}, &models.Holding{AvgCost: 100, CurrentPrice: 50}, nil)
+	if action != "COMPLIANT" {
+		t.Errorf("determineAction without exit levels = %q, want COMPLIANT", action)
+	}
+}
+
 func TestDetermineAction_NilSignals(t *testing.T) {
 	action, reason := determineAction(nil, nil, nil, nil, nil)
 	if action != "COMPLIANT" || reason != "Insufficient data" {
@@ -425,6 +514,199 @@ func TestGenerateAlerts_StrategyRSI(t *testing.T) {
 	}
 }
 
+func TestGenerateAlerts_MACDCross(t *testing.T) {
+	holding := models.Holding{Ticker: "BHP.AU"}
+
+	tests := []struct {
+		name       string
+		cross      string
+		hist       float64
+		wantSignal string // expected signal type, or "" for no MACD alert
+		wantAction string
+	}{
+		{name: "bullish cross", cross: "bullish_cross", hist: 0.05, wantSignal: "macd_bullish_cross", wantAction: "ENTRY CRITERIA MET"},
+		{name: "bearish cross", cross: "bearish_cross", hist: -0.05, wantSignal: "macd_bearish_cross", wantAction: "WATCH"},
+		{name: "no cross (or insufficient data)", cross: "", hist: 0.05, wantSignal: "", wantAction: "COMPLIANT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signals := &models.TickerSignals{
+				Technical: models.TechnicalSignals{RSI: 50, MACDHistogram: tt.hist, MACDCrossover: "bullish", MACDCrossEvent: tt.cross},
+			}
+			alerts := generateAlerts(holding, signals, nil, nil)
+
+			var got []string
+			for _, a := range alerts {
+				if a.Signal == "macd_bullish_cross" || a.Signal == "macd_bearish_cross" {
+					got = append(got, a.Signal)
+				}
+			}
+			if tt.wantSignal == "" && len(got) != 0 {
+				t.Errorf("expected no MACD alert, got %v", got)
+			}
+			if tt.wantSignal != "" && (len(got) != 1 || got[0] != tt.wantSignal) {
+				t.Errorf("expected MACD alert %q, got %v", tt.wantSignal, got)
+			}
+
+			if action, _ := determineAction(signals, nil, nil, &holding, nil); action != tt.wantAction {
+				t.Errorf("determineAction = %q, want %q", action, tt.wantAction)
+			}
+		})
+	}
+}
+
+func TestGenerateAlerts_BollingerSqueezeAndBreakout(t *testing.T) {
+	holding := models.Holding{Ticker: "BHP.AU"}
+
+	tests := []struct {
+		name       string
+		technical  models.TechnicalSignals
+		wantSignal string
+	}{
+		{"squeeze", models.TechnicalSignals{RSI: 50, BBWidth: 0.02, BBSqueeze: true}, "bb_squeeze"},
+		{"breakout after squeeze", models.TechnicalSignals{RSI: 50, BBWidth: 0.05, BBUpper: 11, BBLower: 9, BBBreakout: "up"}, "bb_breakout"},
+		{"insufficient data", models.TechnicalSignals{RSI: 50}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alerts := generateAlerts(holding, &models.TickerSignals{Technical: tt.technical}, nil, nil)
+			var got []string
+			for _, a := range alerts {
+				if a.Signal == "bb_squeeze" || a.Signal == "bb_breakout" {
+					got = append(got, a.Signal)
+				}
+			}
+			if tt.wantSignal == "" {
+				if len(got) != 0 {
+					t.Errorf("expected no Bollinger alert, got %v", got)
+				}
+				return
+			}
+			if len(got) != 1 || got[0] != tt.wantSignal {
+				t.Errorf("expected %q, got %v", tt.wantSignal, got)
+			}
+		})
+	}
+}
+
+func TestDetermineAction_SMA50Cross200IsTieBreaker(t *testing.T) {
+	holding := models.Holding{Ticker: "BHP.AU"}
+
+	tests := []struct {
+		name       string
+		technical  models.TechnicalSignals
+		wantAction string
+		wantAlert  string
+	}{
+		{"golden cross alone", models.TechnicalSignals{RSI: 50, SMA50CrossSMA200: "golden_cross"}, "ENTRY CRITERIA MET", "golden_cross_50_200"},
+		{"death cross alone", models.TechnicalSignals{RSI: 50, SMA50CrossSMA200: "death_cross"}, "EXIT TRIGGER", "death_cross_50_200"},
+		{"RSI exit beats golden cross", models.TechnicalSignals{RSI: 85, SMA50CrossSMA200: "golden_cross"}, "EXIT TRIGGER", "golden_cross_50_200"},
+		{"RSI entry beats death cross", models.TechnicalSignals{RSI: 20, SMA50CrossSMA200: "death_cross"}, "ENTRY CRITERIA MET", "death_cross_50_200"},
+		{"no cross", models.TechnicalSignals{RSI: 50, SMA50CrossSMA200: "none"}, "COMPLIANT", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signals := &models.TickerSignals{Technical: tt.technical}
+			action, reason := determineAction(signals, nil, nil, &holding, nil)
+			if action != tt.wantAction {
+				t.Errorf("determineAction = %q (%s), want %q", action, reason, tt.wantAction)
+			}
+
+			var got []string
+			for _, a := range generateAlerts(holding, signals, nil, nil) {
+				if a.Signal == "golden_cross_50_200" || a.Signal == "death_cross_50_200" {
+					got = append(got, a.Signal)
+				}
+			}
+			if tt.wantAlert == "" && len(got) != 0 {
+				t.Errorf("expected no cross alert, got %v", got)
+			}
+			if tt.wantAlert != "" && (len(got) != 1 || got[0] != tt.wantAlert) {
+				t.Errorf("cross alerts = %v, want [%s]", got, tt.wantAlert)
+			}
+		})
+	}
+}
+
+func TestGenerateAlerts_VolumeSpike(t *testing.T) {
+	holding := models.Holding{Ticker: "BHP.AU"}
+
+	tests := []struct {
+		name      string
+		technical models.TechnicalSignals
+		strategy  *models.PortfolioStrategy
+		wantAlert bool
+	}{
+		{"3x spike", models.TechnicalSignals{RSI: 50, VolumeRatio: 3, AvgVolume20: 100000}, nil, true},
+		{"1.5x day", models.TechnicalSignals{RSI: 50, VolumeRatio: 1.5, AvgVolume20: 100000}, nil, false},
+		{"1.5x day with a 1.2x strategy multiple", models.TechnicalSignals{RSI: 50, VolumeRatio: 1.5, AvgVolume20: 100000},
+			&models.PortfolioStrategy{VolumeSpikeMultiple: 1.2}, true},
+		{"3x day with a 4x strategy multiple", models.TechnicalSignals{RSI: 50, VolumeRatio: 3, AvgVolume20: 100000},
+			&models.PortfolioStrategy{VolumeSpikeMultiple: 4}, false},
+		{"missing volume", models.TechnicalSignals{RSI: 50, VolumeRatio: 3, VolumeSignal: "spike"}, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alerts := generateAlerts(holding, &models.TickerSignals{Technical: tt.technical}, nil, tt.strategy)
+			var got int
+			for _, a := range alerts {
+				if a.Signal == "volume_spike" {
+					got++
+					if a.Type != models.AlertTypeVolume {
+						t.Errorf("alert type = %s, want %s", a.Type, models.AlertTypeVolume)
+					}
+				}
+			}
+			if tt.wantAlert != (got == 1) {
+				t.Errorf("volume_spike alerts = %d, want alert %v", got, tt.wantAlert)
+			}
+		})
+	}
+}
+
+func TestGenerateAlerts_NewsSentimentNegative(t *testing.T) {
+	holding := models.Holding{Ticker: "BHP.AU"}
+
+	tests := []struct {
+		name      string
+		sentiment float64
+		count     int
+		wantAlert bool
+	}{
+		{"negative with enough articles", -0.6, 5, true},
+		{"negative with too few articles", -0.9, 2, false},
+		{"mildly negative", -0.2, 8, false},
+		{"positive", 0.7, 8, false},
+		{"unscored", 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signals := &models.TickerSignals{
+				Technical:          models.TechnicalSignals{RSI: 50},
+				NewsSentiment:      tt.sentiment,
+				NewsSentimentCount: tt.count,
+			}
+			var got []models.Alert
+			for _, a := range generateAlerts(holding, signals, nil, nil) {
+				if a.Signal == "news_sentiment_negative" {
+					got = append(got, a)
+				}
+			}
+			if tt.wantAlert != (len(got) == 1) {
+				t.Fatalf("news_sentiment_negative alerts = %+v, want alert %v", got, tt.wantAlert)
+			}
+			if tt.wantAlert && got[0].Type != models.AlertTypeNews {
+				t.Errorf("alert type = %s, want %s", got[0].Type, models.AlertTypeNews)
+			}
+		})
+	}
+}
+
 func TestGenerateAlerts_StrategyPositionSize(t *testing.T) {
 	strategy := &models.PortfolioStrategy{
 		PositionSizing: models.PositionSizing{MaxPositionPct: 10},
@@ -554,7 +836,9 @@ func (s *stubMarketDataStorage) GetStaleTickers(ctx context.Context, exchange st
 
 // memUserDataStore is a simple in-memory UserDataStore for tests.
 type memUserDataStore struct {
+	mu      sync.RWMutex
 	records map[string]*models.UserRecord // composite key -> record
+	getErr  error                         // when set, every Get fails with it
 }
 
 func newMemUserDataStore() *memUserDataStore {
@@ -562,14 +846,21 @@ func newMemUserDataStore() *memUserDataStore {
 }
 
 func (m *memUserDataStore) Get(_ context.Context, userID, subject, key string) (*models.UserRecord, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.getErr != nil {
+		return nil, m.getErr
+	}
 	ck := userID + ":" + subject + ":" + key
 	if r, ok := m.records[ck]; ok {
 		return r, nil
 	}
-	return nil, fmt.Errorf("%s '%s' not found", subject, key)
+	return nil, fmt.Errorf("%s '%s': %w", subject, key, interfaces.ErrRecordNotFound)
 }
 
 func (m *memUserDataStore) Put(_ context.Context, record *models.UserRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	ck := record.UserID + ":" + record.Subject + ":" + record.Key
 	if existing, ok := m.records[ck]; ok {
 		record.Version = existing.Version + 1
@@ -582,12 +873,16 @@ func (m *memUserDataStore) Put(_ context.Context, record *models.UserRecord) err
 }
 
 func (m *memUserDataStore) Delete(_ context.Context, userID, subject, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	ck := userID + ":" + subject + ":" + key
 	delete(m.records, ck)
 	return nil
 }
 
 func (m *memUserDataStore) List(_ context.Context, userID, subject string) ([]*models.UserRecord, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	var result []*models.UserRecord
 	for _, r := range m.records {
 		if r.UserID == userID && r.Subject == subject {
@@ -601,7 +896,14 @@ func (m *memUserDataStore) Query(_ context.Context, userID, subject string, opts
 	return m.List(context.Background(), userID, subject)
 }
 
+func (m *memUserDataStore) Count(ctx context.Context, userID, subject string, _ interfaces.QueryOptions) (int, error) {
+	records, err := m.List(ctx, userID, subject)
+	return len(records), err
+}
+
 func (m *memUserDataStore) DeleteBySubject(_ context.Context, subject string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	count := 0
 	for ck, r := range m.records {
 		if r.Subject == subject {
@@ -618,6 +920,7 @@ type stubStorageManager struct {
 	marketStore   *stubMarketDataStorage
 	userDataStore *memUserDataStore
 	timelineStore *stubTimelineStore
+	stockIndex    interfaces.StockIndexStore
 }
 
 func (s *stubStorageManager) MarketDataStorage() interfaces.MarketDataStorage { return s.marketStore }
@@ -630,6 +933,9 @@ func (s *stubStorageManager) UserDataStore() interfaces.UserDataStore {
 	return newMemUserDataStore()
 }
 func (s *stubStorageManager) StockIndexStore() interfaces.StockIndexStore {
+	if s.stockIndex != nil {
+		return s.stockIndex
+	}
 	return &noopStockIndexStore{}
 }
 func (s *stubStorageManager) JobQueueStore() interfaces.JobQueueStore   { return nil }
@@ -649,7 +955,13 @@ func (s *stubStorageManager) PurgeDerivedData(ctx context.Context) (map[string]i
 	return nil, nil
 }
 func (s *stubStorageManager) PurgeReports(ctx context.Context) (int, error) { return 0, nil }
-func (s *stubStorageManager) Close() error                                  { return nil }
+func (s *stubStorageManager) Backup(_ context.Context, _ io.Writer) (*models.BackupManifest, error) {
+	return nil, nil
+}
+func (s *stubStorageManager) Restore(_ context.Context, _ io.Reader) (*models.BackupManifest, error) {
+	return nil, nil
+}
+func (s *stubStorageManager) Close() error { return nil }
 
 // stubTimelineStore implements interfaces.TimelineStore for tests.
 type stubTimelineStore struct {
@@ -817,6 +1129,83 @@ func TestSyncPortfolio_EODHDPriceFallback(t *testing.T) {
 	}
 }
 
+func TestSyncPortfolio_PriceSourcePreference(t *testing.T) {
+	today := time.Now()
+	navexaPrice := 0.84
+	eodhPrice := 0.79
+
+	newNavexa := func() *stubNavexaClient {
+		return &stubNavexaClient{
+			portfolios: []*models.NavexaPortfolio{
+				{ID: "1", Name: "SMSF", Currency: "AUD", DateCreated: "2020-01-01"},
+			},
+			holdings: []*models.NavexaHolding{
+				{ID: "100", PortfolioID: "1", Ticker: "ILQ", Exchange: "AU", Name: "Illiquid Co",
+					Units: 10000, CurrentPrice: navexaPrice, MarketValue: navexaPrice * 10000, LastUpdated: today},
+				{ID: "101", PortfolioID: "1", Ticker: "BHP", Exchange: "AU", Name: "BHP Group",
+					Units: 100, CurrentPrice: 44, MarketValue: 4400, LastUpdated: today},
+			},
+			trades: map[string][]*models.NavexaTrade{
+				"100": {{ID: "1", HoldingID: "100", Symbol: "ILQ", Type: "buy", Units: 10000, Price: 0.70}},
+				"101": {{ID: "2", HoldingID: "101", Symbol: "BHP", Type: "buy", Units: 100, Price: 40}},
+			},
+		}
+	}
+	marketStore := &stubMarketDataStorage{
+		data: map[string]*models.MarketData{
+			"ILQ.AU": {Ticker: "ILQ.AU", EOD: []models.EODBar{{Date: today, Close: eodhPrice}}},
+			"BHP.AU": {Ticker: "BHP.AU", EOD: []models.EODBar{{Date: today.AddDate(0, 0, -5), Close: 45}}},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		strategy *models.PortfolioStrategy
+		wantILQ  float64
+		wantBHP  float64
+	}{
+		{"default: fresh EODHD bar overrides Navexa", nil, eodhPrice, 44},
+		{
+			"holding prefers Navexa",
+			&models.PortfolioStrategy{PriceSourceByTicker: []models.TickerPriceSource{{Ticker: "ILQ.AU", Source: models.PriceSourceNavexa}}},
+			navexaPrice, 44,
+		},
+		{
+			"portfolio prefers EODHD even when the bar is old",
+			&models.PortfolioStrategy{PriceSource: models.PriceSourceEODHD},
+			eodhPrice, 45,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := &stubStorageManager{marketStore: marketStore, userDataStore: newMemUserDataStore()}
+			ctx := common.WithNavexaClient(context.Background(), newNavexa())
+			if tt.strategy != nil {
+				data, _ := json.Marshal(tt.strategy)
+				_ = storage.userDataStore.Put(ctx, &models.UserRecord{
+					UserID: common.ResolveUserID(ctx), Subject: "strategy", Key: "SMSF", Value: string(data),
+				})
+			}
+			svc := NewService(storage, nil, nil, nil, common.NewLogger("error"))
+
+			portfolio, err := svc.SyncPortfolio(ctx, "SMSF", true)
+			if err != nil {
+				t.Fatalf("SyncPortfolio failed: %v", err)
+			}
+			for _, h := range portfolio.Holdings {
+				want := tt.wantBHP
+				if h.Ticker == "ILQ" {
+					want = tt.wantILQ
+				}
+				if !approxEqual(h.CurrentPrice, want, 1e-9) {
+					t.Errorf("%s CurrentPrice = %.2f, want %.2f", h.Ticker, h.CurrentPrice, want)
+				}
+			}
+		})
+	}
+}
+
 func TestSyncPortfolio_NoFallbackWhenNavexaIsFresh(t *testing.T) {
 	today := time.Now()
 	navexaPrice := 147.50
@@ -1001,51 +1390,204 @@ func TestSyncPortfolio_NoFallbackForOldEODHDBar(t *testing.T) {
 	}
 }
 
-// TestSyncPortfolio_ConcurrentSyncSerializes verifies that concurrent SyncPortfolio
-// calls are serialized by the mutex, preventing the warm cache race condition where
-// a slow force=false sync could overwrite a fast force=true sync's fresh data.
-func TestSyncPortfolio_ConcurrentSyncSerializes(t *testing.T) {
-	stalePrice := 143.92
-	freshPrice := 147.50
-
-	// Track which call saved last
-	store := &trackingUserDataStore{memUserDataStore: newMemUserDataStore()}
+// TestSyncPortfolio_InfersMissingExchange verifies that a holding returned by
+// Navexa without an exchange gets one inferred (from currency, then config),
+// is flagged, and that the inferred exchange drives EODHD price lookups.
+func TestSyncPortfolio_InfersMissingExchange(t *testing.T) {
+	today := time.Now()
+	navexaPrice := 180.00
+	eodhdClose := 185.00
 
-	// Navexa client that returns stale price on first call, fresh on second
-	callCount := 0
-	navexa := &delayedNavexaClient{
+	navexa := &stubNavexaClient{
 		portfolios: []*models.NavexaPortfolio{
 			{ID: "1", Name: "SMSF", Currency: "AUD", DateCreated: "2020-01-01"},
 		},
-		holdingsFn: func() []*models.NavexaHolding {
-			callCount++
-			price := stalePrice
-			if callCount > 1 {
-				price = freshPrice
-			}
-			return []*models.NavexaHolding{
-				{
-					ID: "100", Ticker: "ACDC", Units: 282,
-					CurrentPrice: price,
-					MarketValue:  price * 282,
-				},
-			}
+		holdings: []*models.NavexaHolding{
+			{
+				ID: "100", PortfolioID: "1", Ticker: "AAPL", Exchange: "",
+				Name: "Apple Inc", Units: 10, CurrentPrice: navexaPrice,
+				MarketValue: navexaPrice * 10, Currency: "USD", LastUpdated: today,
+			},
+			{
+				ID: "101", PortfolioID: "1", Ticker: "BHP", Exchange: "",
+				Name: "BHP Group", Units: 100, CurrentPrice: 45.00,
+				MarketValue: 4500.00, LastUpdated: today,
+			},
+			{
+				ID: "102", PortfolioID: "1", Ticker: "CBA", Exchange: "ASX",
+				Name: "Commonwealth Bank", Units: 10, CurrentPrice: 120.00,
+				MarketValue: 1200.00, Currency: "AUD", LastUpdated: today,
+			},
+		},
+		trades: map[string][]*models.NavexaTrade{
+			"100": {{ID: "1", HoldingID: "100", Symbol: "AAPL", Type: "buy", Units: 10, Price: 150.0}},
+			"101": {{ID: "2", HoldingID: "101", Symbol: "BHP", Type: "buy", Units: 100, Price: 40.0}},
+			"102": {{ID: "3", HoldingID: "102", Symbol: "CBA", Type: "buy", Units: 10, Price: 100.0}},
 		},
 	}
 
-	storageManager := &trackingStorageManager{
-		userDataStore: store,
-		marketStore:   &stubMarketDataStorage{data: map[string]*models.MarketData{}},
+	// EODHD data exists only under the inferred US exchange
+	marketStore := &stubMarketDataStorage{
+		data: map[string]*models.MarketData{
+			"AAPL.US": {
+				Ticker: "AAPL.US",
+				EOD:    []models.EODBar{{Date: today, Close: eodhdClose}},
+			},
+		},
 	}
+
+	storage := &stubStorageManager{
+		marketStore:   marketStore,
+		userDataStore: newMemUserDataStore(),
+	}
+
 	logger := common.NewLogger("error")
-	svc := NewService(storageManager, nil, nil, nil, logger)
+	svc := NewService(storage, nil, nil, nil, logger)
+	svc.SetDefaultExchange("NYSE")
 
 	ctx := common.WithNavexaClient(context.Background(), navexa)
+	portfolio, err := svc.SyncPortfolio(ctx, "SMSF", true)
+	if err != nil {
+		t.Fatalf("SyncPortfolio failed: %v", err)
+	}
 
-	// Simulate warm cache (force=false) starting first
-	done := make(chan struct{})
-	go func() {
-		svc.SyncPortfolio(ctx, "SMSF", false)
+	holdingMap := make(map[string]*models.Holding)
+	for i := range portfolio.Holdings {
+		holdingMap[portfolio.Holdings[i].Ticker] = &portfolio.Holdings[i]
+	}
+
+	// Holding currency takes priority
+	aapl := holdingMap["AAPL"]
+	if aapl == nil {
+		t.Fatal("AAPL holding not found")
+	}
+	if aapl.Exchange != "US" {
+		t.Errorf("AAPL.Exchange = %q, want %q (inferred from USD currency)", aapl.Exchange, "US")
+	}
+	if !aapl.ExchangeInferred {
+		t.Error("AAPL.ExchangeInferred = false, want true")
+	}
+	if aapl.EODHDTicker() != "AAPL.US" {
+		t.Errorf("AAPL.EODHDTicker() = %q, want %q", aapl.EODHDTicker(), "AAPL.US")
+	}
+	// Price lookup used the inferred ticker: EODHD close (converted to AUD only when FX available)
+	if !approxEqual(aapl.CurrentPrice, eodhdClose, 0.01) {
+		t.Errorf("AAPL.CurrentPrice = %.2f, want %.2f (EODHD lookup via inferred exchange)", aapl.CurrentPrice, eodhdClose)
+	}
+
+	// No holding currency: configured default applies
+	bhp := holdingMap["BHP"]
+	if bhp == nil {
+		t.Fatal("BHP holding not found")
+	}
+	if bhp.Exchange != "US" || !bhp.ExchangeInferred {
+		t.Errorf("BHP exchange = %q inferred=%v, want %q inferred=true (configured default)", bhp.Exchange, bhp.ExchangeInferred, "US")
+	}
+
+	// Explicit exchange is untouched and not flagged
+	cba := holdingMap["CBA"]
+	if cba == nil {
+		t.Fatal("CBA holding not found")
+	}
+	if cba.Exchange != "ASX" || cba.ExchangeInferred {
+		t.Errorf("CBA exchange = %q inferred=%v, want %q inferred=false", cba.Exchange, cba.ExchangeInferred, "ASX")
+	}
+}
+
+func TestInferExchange_FallsBackToPortfolioCurrency(t *testing.T) {
+	svc := &Service{}
+	if got := svc.inferExchange("", "USD"); got != "US" {
+		t.Errorf("inferExchange(\"\", USD) = %q, want %q", got, "US")
+	}
+	if got := svc.inferExchange("", ""); got != "AU" {
+		t.Errorf("inferExchange(\"\", \"\") = %q, want %q", got, "AU")
+	}
+}
+
+// TestSyncPortfolio_ConcurrentSyncSerializes verifies that concurrent SyncPortfolio
+// calls are serialized by the mutex, preventing the warm cache race condition where
+// a slow force=false sync could overwrite a fast force=true sync's fresh data.
+func TestSyncPortfolio_LogsCarryRequestID(t *testing.T) {
+	navexa := &stubNavexaClient{
+		portfolios: []*models.NavexaPortfolio{
+			{ID: "1", Name: "SMSF", Currency: "AUD", DateCreated: "2020-01-01"},
+		},
+		holdings: []*models.NavexaHolding{{
+			ID: "100", PortfolioID: "1", Ticker: "BHP", Exchange: "AU", Name: "BHP Group",
+			Units: 100, CurrentPrice: 45.00, MarketValue: 4500.00, LastUpdated: time.Now(),
+		}},
+		trades: map[string][]*models.NavexaTrade{
+			"100": {{ID: "1", HoldingID: "100", Symbol: "BHP", Type: "buy", Units: 100, Price: 40.0}},
+		},
+	}
+	storage := &stubStorageManager{
+		marketStore:   &stubMarketDataStorage{data: map[string]*models.MarketData{}},
+		userDataStore: newMemUserDataStore(),
+	}
+	var out bytes.Buffer
+	svc := NewService(storage, nil, nil, nil, common.NewLoggerWithOutput("info", &out))
+	ctx := common.WithRequestID(common.WithNavexaClient(context.Background(), navexa), "req-sync-1")
+
+	if _, err := svc.SyncPortfolio(ctx, "SMSF", true); err != nil {
+		t.Fatalf("SyncPortfolio failed: %v", err)
+	}
+
+	var synced bool
+	for _, line := range strings.Split(out.String(), "\n") {
+		if strings.HasPrefix(line, "Syncing portfolio") || strings.HasPrefix(line, "Portfolio synced") {
+			synced = true
+			if !strings.Contains(line, "correlation_id=req-sync-1") {
+				t.Errorf("sync log line missing request ID: %q", line)
+			}
+		}
+	}
+	if !synced {
+		t.Fatalf("no sync log lines captured:\n%s", out.String())
+	}
+}
+
+func TestSyncPortfolio_ConcurrentSyncSerializes(t *testing.T) {
+	stalePrice := 143.92
+	freshPrice := 147.50
+
+	// Track which call saved last
+	store := &trackingUserDataStore{memUserDataStore: newMemUserDataStore()}
+
+	// Navexa client that returns stale price on first call, fresh on second
+	callCount := 0
+	navexa := &delayedNavexaClient{
+		portfolios: []*models.NavexaPortfolio{
+			{ID: "1", Name: "SMSF", Currency: "AUD", DateCreated: "2020-01-01"},
+		},
+		holdingsFn: func() []*models.NavexaHolding {
+			callCount++
+			price := stalePrice
+			if callCount > 1 {
+				price = freshPrice
+			}
+			return []*models.NavexaHolding{
+				{
+					ID: "100", Ticker: "ACDC", Units: 282,
+					CurrentPrice: price,
+					MarketValue:  price * 282,
+				},
+			}
+		},
+	}
+
+	storageManager := &trackingStorageManager{
+		userDataStore: store,
+		marketStore:   &stubMarketDataStorage{data: map[string]*models.MarketData{}},
+	}
+	logger := common.NewLogger("error")
+	svc := NewService(storageManager, nil, nil, nil, logger)
+
+	ctx := common.WithNavexaClient(context.Background(), navexa)
+
+	// Simulate warm cache (force=false) starting first
+	done := make(chan struct{})
+	go func() {
+		svc.SyncPortfolio(ctx, "SMSF", false)
 		close(done)
 	}()
 
@@ -1107,7 +1649,13 @@ func (s *trackingStorageManager) PurgeDerivedData(ctx context.Context) (map[stri
 	return nil, nil
 }
 func (s *trackingStorageManager) PurgeReports(ctx context.Context) (int, error) { return 0, nil }
-func (s *trackingStorageManager) Close() error                                  { return nil }
+func (s *trackingStorageManager) Backup(_ context.Context, _ io.Writer) (*models.BackupManifest, error) {
+	return nil, nil
+}
+func (s *trackingStorageManager) Restore(_ context.Context, _ io.Reader) (*models.BackupManifest, error) {
+	return nil, nil
+}
+func (s *trackingStorageManager) Close() error { return nil }
 
 // delayedNavexaClient returns different holdings per call to simulate stale vs fresh data
 type delayedNavexaClient struct {
@@ -1149,6 +1697,7 @@ func (s *delayedNavexaClient) GetHoldingTrades(ctx context.Context, holdingID st
 
 type stubEODHDClient struct {
 	realTimeQuoteFn func(ctx context.Context, ticker string) (*models.RealTimeQuote, error)
+	quotesBatchFn   func(ctx context.Context, tickers []string) (map[string]*models.RealTimeQuote, error)
 }
 
 func (s *stubEODHDClient) GetRealTimeQuote(ctx context.Context, ticker string) (*models.RealTimeQuote, error) {
@@ -1163,6 +1712,21 @@ func (s *stubEODHDClient) GetEOD(ctx context.Context, ticker string, opts ...int
 func (s *stubEODHDClient) GetBulkRealTimeQuotes(_ context.Context, _ []string) (map[string]*models.RealTimeQuote, error) {
 	return nil, fmt.Errorf("not implemented")
 }
+
+// GetRealTimeQuotesBatch uses quotesBatchFn when set, otherwise realTimeQuoteFn
+// per ticker, keeping only successful quotes as the real client does.
+func (s *stubEODHDClient) GetRealTimeQuotesBatch(ctx context.Context, tickers []string) (map[string]*models.RealTimeQuote, error) {
+	if s.quotesBatchFn != nil {
+		return s.quotesBatchFn(ctx, tickers)
+	}
+	result := make(map[string]*models.RealTimeQuote, len(tickers))
+	for _, ticker := range tickers {
+		if q, err := s.GetRealTimeQuote(ctx, ticker); err == nil && q.Close > 0 {
+			result[ticker] = q
+		}
+	}
+	return result, nil
+}
 func (s *stubEODHDClient) GetBulkEOD(ctx context.Context, exchange string, tickers []string) (map[string]models.EODBar, error) {
 	return nil, fmt.Errorf("not implemented")
 }
@@ -1217,7 +1781,13 @@ func (s *reviewStorageManager) PurgeDerivedData(_ context.Context) (map[string]i
 	return nil, nil
 }
 func (s *reviewStorageManager) PurgeReports(_ context.Context) (int, error) { return 0, nil }
-func (s *reviewStorageManager) Close() error                                { return nil }
+func (s *reviewStorageManager) Backup(_ context.Context, _ io.Writer) (*models.BackupManifest, error) {
+	return nil, nil
+}
+func (s *reviewStorageManager) Restore(_ context.Context, _ io.Reader) (*models.BackupManifest, error) {
+	return nil, nil
+}
+func (s *reviewStorageManager) Close() error { return nil }
 
 type reviewMarketDataStorage struct {
 	data map[string]*models.MarketData
@@ -1415,7 +1985,282 @@ func TestReviewPortfolio_FallsBackToEODOnRealTimeError(t *testing.T) {
 	}
 }
 
-func TestReviewPortfolio_PartialRealTimeFailure(t *testing.T) {
+func TestReviewPortfolio_PartialRealTimeFailure(t *testing.T) {
+	today := time.Now()
+	livePrice := 43.25
+
+	portfolio := &models.Portfolio{
+		Name:                 "SMSF",
+		EquityHoldingsReturn: 10000,
+		PortfolioValue:       10000,
+		LastSynced:           today,
+		Holdings: []models.Holding{
+			{Ticker: "BHP", Exchange: "AU", Name: "BHP Group", Units: 100, CurrentPrice: 42.50, MarketValue: 4250, WeightPct: 50},
+			{Ticker: "CBA", Exchange: "AU", Name: "CBA Group", Units: 50, CurrentPrice: 115.00, MarketValue: 5750, WeightPct: 50},
+		},
+	}
+
+	uds := newMemUserDataStore()
+	storePortfolio(t, uds, portfolio)
+
+	storage := &reviewStorageManager{
+		userDataStore: uds,
+		marketStore: &reviewMarketDataStorage{
+			data: map[string]*models.MarketData{
+				"BHP.AU": {Ticker: "BHP.AU", EOD: []models.EODBar{
+					{Date: today, Close: 42.50},
+					{Date: today.AddDate(0, 0, -1), Close: 41.80},
+				}},
+				"CBA.AU": {Ticker: "CBA.AU", EOD: []models.EODBar{
+					{Date: today, Close: 115.00},
+					{Date: today.AddDate(0, 0, -1), Close: 114.50},
+				}},
+			},
+		},
+		signalStore: &reviewSignalStorage{signals: map[string]*models.TickerSignals{
+			"BHP.AU": {Ticker: "BHP.AU", Technical: models.TechnicalSignals{RSI: 50}},
+			"CBA.AU": {Ticker: "CBA.AU", Technical: models.TechnicalSignals{RSI: 55}},
+		}},
+	}
+
+	eodhd := &stubEODHDClient{
+		realTimeQuoteFn: func(_ context.Context, ticker string) (*models.RealTimeQuote, error) {
+			if ticker == "BHP.AU" {
+				return &models.RealTimeQuote{Code: ticker, Close: livePrice, Timestamp: today}, nil
+			}
+			// CBA fails
+			return nil, fmt.Errorf("API error for CBA")
+		},
+	}
+
+	logger := common.NewLogger("error")
+	svc := NewService(storage, nil, eodhd, nil, logger)
+
+	review, err := svc.ReviewPortfolio(context.Background(), "SMSF", interfaces.ReviewOptions{})
+	if err != nil {
+		t.Fatalf("ReviewPortfolio failed: %v", err)
+	}
+
+	if len(review.HoldingReviews) < 2 {
+		t.Fatalf("expected 2 holding reviews, got %d", len(review.HoldingReviews))
+	}
+
+	// BHP should have live price
+	var bhp, cba *models.HoldingReview
+	for i := range review.HoldingReviews {
+		if review.HoldingReviews[i].Holding.Ticker == "BHP" {
+			bhp = &review.HoldingReviews[i]
+		}
+		if review.HoldingReviews[i].Holding.Ticker == "CBA" {
+			cba = &review.HoldingReviews[i]
+		}
+	}
+
+	if bhp == nil || cba == nil {
+		t.Fatal("expected both BHP and CBA holding reviews")
+	}
+
+	if !approxEqual(bhp.Holding.CurrentPrice, livePrice, 0.01) {
+		t.Errorf("BHP CurrentPrice = %.2f, want %.2f (live)", bhp.Holding.CurrentPrice, livePrice)
+	}
+
+	// CBA should fall back to EOD
+	if !approxEqual(cba.OvernightMove, 115.00-114.50, 0.01) {
+		t.Errorf("CBA OvernightMove = %.2f, want %.2f (EOD fallback)", cba.OvernightMove, 115.00-114.50)
+	}
+}
+
+func TestReviewPortfolio_LiveQuoteInCents(t *testing.T) {
+	today := time.Now()
+
+	portfolio := &models.Portfolio{
+		Name:                 "SMSF",
+		EquityHoldingsReturn: 10000,
+		PortfolioValue:       10000,
+		LastSynced:           today,
+		Holdings: []models.Holding{
+			{Ticker: "PLS", Exchange: "AU", Name: "Pilbara", Units: 1000, CurrentPrice: 3.10, MarketValue: 3100, WeightPct: 100, PriceInCents: true},
+		},
+	}
+
+	uds := newMemUserDataStore()
+	storePortfolio(t, uds, portfolio)
+
+	storage := &reviewStorageManager{
+		userDataStore: uds,
+		marketStore: &reviewMarketDataStorage{
+			data: map[string]*models.MarketData{
+				"PLS.AU": {Ticker: "PLS.AU", EOD: []models.EODBar{
+					{Date: today, Close: 310},
+					{Date: today.AddDate(0, 0, -1), Close: 300},
+				}},
+			},
+		},
+		signalStore: &reviewSignalStorage{signals: map[string]*models.TickerSignals{
+			"PLS.AU": {Ticker: "PLS.AU", Technical: models.TechnicalSignals{RSI: 50}},
+		}},
+	}
+
+	// EODHD quotes PLS in cents: 320c is $3.20
+	eodhd := &stubEODHDClient{
+		quotesBatchFn: func(_ context.Context, tickers []string) (map[string]*models.RealTimeQuote, error) {
+			return map[string]*models.RealTimeQuote{
+				"PLS.AU": {Code: "PLS.AU", Close: 320, Timestamp: today},
+			}, nil
+		},
+	}
+
+	svc := NewService(storage, nil, eodhd, nil, common.NewLogger("error"))
+
+	review, err := svc.ReviewPortfolio(context.Background(), "SMSF", interfaces.ReviewOptions{})
+	if err != nil {
+		t.Fatalf("ReviewPortfolio failed: %v", err)
+	}
+	if len(review.HoldingReviews) != 1 {
+		t.Fatalf("expected 1 holding review, got %d", len(review.HoldingReviews))
+	}
+	hr := review.HoldingReviews[0]
+	if !approxEqual(hr.Holding.CurrentPrice, 3.20, 0.0001) {
+		t.Errorf("CurrentPrice = %.4f, want 3.20 (cents quote in dollars)", hr.Holding.CurrentPrice)
+	}
+	if !approxEqual(hr.Holding.MarketValue, 3200, 0.01) {
+		t.Errorf("MarketValue = %.2f, want 3200", hr.Holding.MarketValue)
+	}
+	if !approxEqual(hr.OvernightMove, 0.20, 0.0001) {
+		t.Errorf("OvernightMove = %.4f, want 0.20", hr.OvernightMove)
+	}
+	if !approxEqual(hr.OvernightPct, 20.0/300*100, 0.01) {
+		t.Errorf("OvernightPct = %.2f, want %.2f", hr.OvernightPct, 20.0/300*100)
+	}
+}
+
+func TestReviewPortfolio_LiveQuoteKeepsNavexaPinnedPrice(t *testing.T) {
+	today := time.Now()
+
+	portfolio := &models.Portfolio{
+		Name:                 "SMSF",
+		EquityHoldingsReturn: 10000,
+		PortfolioValue:       10000,
+		LastSynced:           today,
+		Holdings: []models.Holding{
+			{Ticker: "BHP", Exchange: "AU", Name: "BHP Group", Units: 100, CurrentPrice: 42.50, MarketValue: 4250, WeightPct: 50},
+			{Ticker: "ILQ", Exchange: "AU", Name: "Illiquid Co", Units: 1000, CurrentPrice: 1.00, MarketValue: 1000, WeightPct: 50},
+		},
+	}
+
+	uds := newMemUserDataStore()
+	storePortfolio(t, uds, portfolio)
+	strategy, _ := json.Marshal(&models.PortfolioStrategy{
+		PortfolioName:       "SMSF",
+		PriceSourceByTicker: []models.TickerPriceSource{{Ticker: "ILQ", Source: models.PriceSourceNavexa}},
+	})
+	_ = uds.Put(context.Background(), &models.UserRecord{
+		UserID: common.ResolveUserID(context.Background()), Subject: "strategy", Key: "SMSF", Value: string(strategy),
+	})
+
+	storage := &reviewStorageManager{
+		userDataStore: uds,
+		marketStore: &reviewMarketDataStorage{
+			data: map[string]*models.MarketData{
+				"BHP.AU": {Ticker: "BHP.AU", EOD: []models.EODBar{{Date: today, Close: 42.50}, {Date: today.AddDate(0, 0, -1), Close: 42.00}}},
+				"ILQ.AU": {Ticker: "ILQ.AU", EOD: []models.EODBar{{Date: today, Close: 1.00}, {Date: today.AddDate(0, 0, -1), Close: 1.00}}},
+			},
+		},
+		signalStore: &reviewSignalStorage{signals: map[string]*models.TickerSignals{
+			"BHP.AU": {Ticker: "BHP.AU", Technical: models.TechnicalSignals{RSI: 50}},
+			"ILQ.AU": {Ticker: "ILQ.AU", Technical: models.TechnicalSignals{RSI: 50}},
+		}},
+	}
+	eodhd := &stubEODHDClient{
+		quotesBatchFn: func(_ context.Context, _ []string) (map[string]*models.RealTimeQuote, error) {
+			return map[string]*models.RealTimeQuote{
+				"BHP.AU": {Code: "BHP.AU", Close: 43.00, Timestamp: today},
+				"ILQ.AU": {Code: "ILQ.AU", Close: 1.50, Timestamp: today}, // a thin, misleading trade
+			}, nil
+		},
+	}
+
+	svc := NewService(storage, nil, eodhd, nil, common.NewLogger("error"))
+	review, err := svc.ReviewPortfolio(context.Background(), "SMSF", interfaces.ReviewOptions{})
+	if err != nil {
+		t.Fatalf("ReviewPortfolio failed: %v", err)
+	}
+	prices := make(map[string]float64)
+	for _, hr := range review.HoldingReviews {
+		prices[hr.Holding.Ticker] = hr.Holding.CurrentPrice
+	}
+	if prices["BHP"] != 43.00 {
+		t.Errorf("BHP CurrentPrice = %.2f, want the live 43.00", prices["BHP"])
+	}
+	if prices["ILQ"] != 1.00 {
+		t.Errorf("ILQ CurrentPrice = %.2f, want the Navexa-pinned 1.00", prices["ILQ"])
+	}
+}
+
+func TestReviewPortfolio_CircuitOpenFallsBackToEOD(t *testing.T) {
+	today := time.Now()
+
+	portfolio := &models.Portfolio{
+		Name:                 "SMSF",
+		EquityHoldingsReturn: 10000,
+		PortfolioValue:       10000,
+		LastSynced:           today,
+		Holdings: []models.Holding{
+			{Ticker: "BHP", Exchange: "AU", Name: "BHP Group", Units: 100, CurrentPrice: 42.50, MarketValue: 4250, WeightPct: 50},
+			{Ticker: "CBA", Exchange: "AU", Name: "CBA Group", Units: 50, CurrentPrice: 115.00, MarketValue: 5750, WeightPct: 50},
+		},
+	}
+
+	uds := newMemUserDataStore()
+	storePortfolio(t, uds, portfolio)
+
+	storage := &reviewStorageManager{
+		userDataStore: uds,
+		marketStore: &reviewMarketDataStorage{
+			data: map[string]*models.MarketData{
+				"BHP.AU": {Ticker: "BHP.AU", EOD: []models.EODBar{
+					{Date: today, Close: 42.50},
+					{Date: today.AddDate(0, 0, -1), Close: 41.80},
+				}},
+				"CBA.AU": {Ticker: "CBA.AU", EOD: []models.EODBar{
+					{Date: today, Close: 115.00},
+					{Date: today.AddDate(0, 0, -1), Close: 114.50},
+				}},
+			},
+		},
+		signalStore: &reviewSignalStorage{signals: map[string]*models.TickerSignals{
+			"BHP.AU": {Ticker: "BHP.AU", Technical: models.TechnicalSignals{RSI: 50}},
+			"CBA.AU": {Ticker: "CBA.AU", Technical: models.TechnicalSignals{RSI: 55}},
+		}},
+	}
+
+	eodhd := &stubEODHDClient{
+		quotesBatchFn: func(_ context.Context, tickers []string) (map[string]*models.RealTimeQuote, error) {
+			return nil, fmt.Errorf("EODHD /real-time/%s: %w", tickers[0], interfaces.ErrCircuitOpen)
+		},
+	}
+
+	svc := NewService(storage, nil, eodhd, nil, common.NewLogger("error"))
+
+	review, err := svc.ReviewPortfolio(context.Background(), "SMSF", interfaces.ReviewOptions{})
+	if err != nil {
+		t.Fatalf("ReviewPortfolio failed: %v", err)
+	}
+	if len(review.HoldingReviews) != 2 {
+		t.Fatalf("expected 2 holding reviews, got %d", len(review.HoldingReviews))
+	}
+	for _, hr := range review.HoldingReviews {
+		want := 42.50 - 41.80
+		if hr.Holding.Ticker == "CBA" {
+			want = 115.00 - 114.50
+		}
+		if !approxEqual(hr.OvernightMove, want, 0.01) {
+			t.Errorf("%s OvernightMove = %.2f, want %.2f (EOD fallback)", hr.Holding.Ticker, hr.OvernightMove, want)
+		}
+	}
+}
+
+func TestReviewPortfolio_BatchesRealTimeQuotes(t *testing.T) {
 	today := time.Now()
 	livePrice := 43.25
 
@@ -1453,50 +2298,47 @@ func TestReviewPortfolio_PartialRealTimeFailure(t *testing.T) {
 		}},
 	}
 
+	batchCalls, singleCalls := 0, 0
+	var requested []string
 	eodhd := &stubEODHDClient{
 		realTimeQuoteFn: func(_ context.Context, ticker string) (*models.RealTimeQuote, error) {
-			if ticker == "BHP.AU" {
-				return &models.RealTimeQuote{Code: ticker, Close: livePrice, Timestamp: today}, nil
-			}
-			// CBA fails
-			return nil, fmt.Errorf("API error for CBA")
+			singleCalls++
+			return nil, fmt.Errorf("unexpected single quote call")
+		},
+		quotesBatchFn: func(_ context.Context, tickers []string) (map[string]*models.RealTimeQuote, error) {
+			batchCalls++
+			requested = tickers
+			// CBA is missing from the batch response
+			return map[string]*models.RealTimeQuote{
+				"BHP.AU": {Code: "BHP.AU", Close: livePrice, Timestamp: today},
+			}, nil
 		},
 	}
 
-	logger := common.NewLogger("error")
-	svc := NewService(storage, nil, eodhd, nil, logger)
+	svc := NewService(storage, nil, eodhd, nil, common.NewLogger("error"))
 
 	review, err := svc.ReviewPortfolio(context.Background(), "SMSF", interfaces.ReviewOptions{})
 	if err != nil {
 		t.Fatalf("ReviewPortfolio failed: %v", err)
 	}
-
-	if len(review.HoldingReviews) < 2 {
-		t.Fatalf("expected 2 holding reviews, got %d", len(review.HoldingReviews))
-	}
-
-	// BHP should have live price
-	var bhp, cba *models.HoldingReview
-	for i := range review.HoldingReviews {
-		if review.HoldingReviews[i].Holding.Ticker == "BHP" {
-			bhp = &review.HoldingReviews[i]
-		}
-		if review.HoldingReviews[i].Holding.Ticker == "CBA" {
-			cba = &review.HoldingReviews[i]
-		}
-	}
-
-	if bhp == nil || cba == nil {
-		t.Fatal("expected both BHP and CBA holding reviews")
+	if batchCalls != 1 || singleCalls != 0 {
+		t.Errorf("batch calls = %d, single calls = %d; want 1 and 0", batchCalls, singleCalls)
 	}
-
-	if !approxEqual(bhp.Holding.CurrentPrice, livePrice, 0.01) {
-		t.Errorf("BHP CurrentPrice = %.2f, want %.2f (live)", bhp.Holding.CurrentPrice, livePrice)
+	if len(requested) != 2 {
+		t.Errorf("batch requested %v, want both holdings", requested)
 	}
 
-	// CBA should fall back to EOD
-	if !approxEqual(cba.OvernightMove, 115.00-114.50, 0.01) {
-		t.Errorf("CBA OvernightMove = %.2f, want %.2f (EOD fallback)", cba.OvernightMove, 115.00-114.50)
+	for _, hr := range review.HoldingReviews {
+		switch hr.Holding.Ticker {
+		case "BHP":
+			if !approxEqual(hr.Holding.CurrentPrice, livePrice, 0.01) {
+				t.Errorf("BHP CurrentPrice = %.2f, want %.2f (live)", hr.Holding.CurrentPrice, livePrice)
+			}
+		case "CBA":
+			if !approxEqual(hr.OvernightMove, 115.00-114.50, 0.01) {
+				t.Errorf("CBA OvernightMove = %.2f, want %.2f (EOD fallback)", hr.OvernightMove, 115.00-114.50)
+			}
+		}
 	}
 }
 
@@ -1525,7 +2367,13 @@ func (s *flexStorageManager) PurgeDerivedData(ctx context.Context) (map[string]i
 	return nil, nil
 }
 func (s *flexStorageManager) PurgeReports(ctx context.Context) (int, error) { return 0, nil }
-func (s *flexStorageManager) Close() error                                  { return nil }
+func (s *flexStorageManager) Backup(_ context.Context, _ io.Writer) (*models.BackupManifest, error) {
+	return nil, nil
+}
+func (s *flexStorageManager) Restore(_ context.Context, _ io.Reader) (*models.BackupManifest, error) {
+	return nil, nil
+}
+func (s *flexStorageManager) Close() error { return nil }
 
 func TestGetPortfolio_Fresh_NoSync(t *testing.T) {
 	freshPortfolio := &models.Portfolio{
@@ -2840,10 +3688,62 @@ func TestSyncPortfolio_ForceRefresh_WithoutNavexaContext(t *testing.T) {
 		t.Fatal("expected error when force syncing without Navexa context")
 	}
 
-	// Error should mention navexa/portal
-	errMsg := err.Error()
-	if !strings.Contains(errMsg, "navexa") && !strings.Contains(errMsg, "portal") {
-		t.Errorf("error message should mention navexa/portal, got: %s", errMsg)
+	if !errors.Is(err, interfaces.ErrNavexaUnavailable) {
+		t.Errorf("error = %v, want ErrNavexaUnavailable", err)
+	}
+}
+
+// TestSyncPortfolio_WaitCancelled_ReturnsErrSyncInProgress verifies that a
+// sync whose context ends while another sync holds the slot gives up with
+// ErrSyncInProgress instead of blocking.
+func TestSyncPortfolio_WaitCancelled_ReturnsErrSyncInProgress(t *testing.T) {
+	storage := &stubStorageManager{
+		marketStore:   &stubMarketDataStorage{data: map[string]*models.MarketData{}},
+		userDataStore: newMemUserDataStore(),
+	}
+	svc := NewService(storage, nil, nil, nil, common.NewLogger("error"))
+
+	if err := svc.acquireSync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer svc.releaseSync()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := svc.SyncPortfolio(ctx, "SMSF", true)
+	if !errors.Is(err, interfaces.ErrSyncInProgress) {
+		t.Errorf("error = %v, want ErrSyncInProgress", err)
+	}
+}
+
+// TestGetPortfolio_Missing_ReturnsErrPortfolioNotFound verifies that a
+// portfolio absent from storage (and with no Navexa to sync from) is
+// reported as ErrPortfolioNotFound.
+func TestGetPortfolio_Missing_ReturnsErrPortfolioNotFound(t *testing.T) {
+	storage := &stubStorageManager{
+		marketStore:   &stubMarketDataStorage{data: map[string]*models.MarketData{}},
+		userDataStore: newMemUserDataStore(),
+	}
+	svc := NewService(storage, nil, nil, nil, common.NewLogger("error"))
+
+	_, err := svc.GetPortfolio(context.Background(), "missing")
+	if !errors.Is(err, interfaces.ErrPortfolioNotFound) {
+		t.Errorf("error = %v, want ErrPortfolioNotFound", err)
+	}
+}
+
+// TestGetPortfolioRecord_StorageErrorPassesThrough verifies that only the
+// store's not-found error becomes ErrPortfolioNotFound.
+func TestGetPortfolioRecord_StorageErrorPassesThrough(t *testing.T) {
+	dbErr := errors.New("connection refused")
+	store := newMemUserDataStore()
+	store.getErr = dbErr
+	storage := &stubStorageManager{userDataStore: store}
+	svc := NewService(storage, nil, nil, nil, common.NewLogger("error"))
+
+	_, err := svc.getPortfolioRecord(context.Background(), "SMSF")
+	if !errors.Is(err, dbErr) || errors.Is(err, interfaces.ErrPortfolioNotFound) {
+		t.Errorf("error = %v, want the storage error and not ErrPortfolioNotFound", err)
 	}
 }
 
@@ -2961,7 +3861,7 @@ func TestAvgCost_TotalCostNegativeFromLargeCostBaseDecrease(t *testing.T) {
 	}
 }
 
-// TestSyncPortfolio_ConcurrentForceSync verifies that the syncMu mutex
+// TestSyncPortfolio_ConcurrentForceSync verifies that the syncSem slot
 // correctly serializes two concurrent force_refresh calls.
 func TestSyncPortfolio_ConcurrentForceSync(t *testing.T) {
 	today := time.Now()
@@ -3620,6 +4520,59 @@ func TestEodClosePrice_NaNAdjClose(t *testing.T) {
 	}
 }
 
+func TestEodClosePriceFresh_StaleBar(t *testing.T) {
+	now := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		bar       models.EODBar
+		maxAge    time.Duration
+		wantPrice float64
+		wantStale bool
+	}{
+		{
+			name:      "recent bar is fresh",
+			bar:       models.EODBar{Date: now.Add(-20 * time.Hour), Close: 10.0, AdjClose: 9.9},
+			maxAge:    24 * time.Hour,
+			wantPrice: 9.9,
+		},
+		{
+			name:      "old bar with AdjClose and Close is stale",
+			bar:       models.EODBar{Date: now.AddDate(0, 0, -5), Close: 10.0, AdjClose: 9.9},
+			maxAge:    24 * time.Hour,
+			wantPrice: 9.9,
+			wantStale: true,
+		},
+		{
+			name:      "bar exactly maxAge old is stale",
+			bar:       models.EODBar{Date: now.Add(-24 * time.Hour), Close: 10.0},
+			maxAge:    24 * time.Hour,
+			wantPrice: 10.0,
+			wantStale: true,
+		},
+		{
+			name:      "wider window keeps old bar fresh",
+			bar:       models.EODBar{Date: now.AddDate(0, 0, -3), Close: 10.0},
+			maxAge:    96 * time.Hour,
+			wantPrice: 10.0,
+		},
+		{
+			name:      "zero window disables check",
+			bar:       models.EODBar{Date: now.AddDate(-1, 0, 0), Close: 10.0},
+			wantPrice: 10.0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			price, stale := eodClosePriceFresh(tt.bar, now, tt.maxAge)
+			if price != tt.wantPrice || stale != tt.wantStale {
+				t.Errorf("eodClosePriceFresh = (%v, %v), want (%v, %v)", price, stale, tt.wantPrice, tt.wantStale)
+			}
+		})
+	}
+}
+
 // --- Historical Values Tests ---
 
 func TestFindEODBarByOffset(t *testing.T) {
@@ -3688,14 +4641,14 @@ func TestPopulateHistoricalValues(t *testing.T) {
 		},
 	}
 
-	// EOD data: yesterday, 2-6 days ago (EOD[0] is always yesterday — bars collected after close)
+	// EOD data: yesterday, 2-4 days ago, a week back (EOD[0] is always yesterday — bars collected after close)
 	eod := []models.EODBar{
 		{Date: today.AddDate(0, 0, -1), Close: 48.00}, // yesterday (EOD[0])
 		{Date: today.AddDate(0, 0, -2), Close: 47.50}, // 2 days ago
 		{Date: today.AddDate(0, 0, -3), Close: 47.00}, // 3 days ago
-		{Date: today.AddDate(0, 0, -4), Close: 46.50}, // 4 days ago (last week)
-		{Date: today.AddDate(0, 0, -5), Close: 46.00}, // 5 days ago
-		{Date: today.AddDate(0, 0, -6), Close: 45.50}, // 6 days ago
+		{Date: today.AddDate(0, 0, -4), Close: 46.50}, // 4 days ago
+		{Date: today.AddDate(0, 0, -7), Close: 46.00}, // last week (7 days back)
+		{Date: today.AddDate(0, 0, -8), Close: 45.50}, // 8 days ago
 	}
 
 	marketStore := &stubMarketDataStorage{
@@ -3729,7 +4682,7 @@ func TestPopulateHistoricalValues(t *testing.T) {
 		t.Errorf("YesterdayPct = %v, want %v", h.YesterdayPriceChangePct, expectedYesterdayPct)
 	}
 
-	// Last week close: EOD[4] = 46.00
+	// Last week close (7 days back): 46.00
 	if !approxEqual(h.LastWeekClosePrice, 46.00, 0.01) {
 		t.Errorf("LastWeekClose = %v, want 46.00", h.LastWeekClosePrice)
 	}
@@ -3793,8 +4746,8 @@ func TestPopulateHistoricalValues_WithUSDHolding(t *testing.T) {
 		{Date: today.AddDate(0, 0, -2), Close: 73.00}, // 2 days ago
 		{Date: today.AddDate(0, 0, -3), Close: 72.00}, // 3 days ago
 		{Date: today.AddDate(0, 0, -4), Close: 71.00}, // 4 days ago
-		{Date: today.AddDate(0, 0, -5), Close: 70.00}, // last week (EOD[4])
-		{Date: today.AddDate(0, 0, -6), Close: 69.00}, // 6 days ago
+		{Date: today.AddDate(0, 0, -7), Close: 70.00}, // last week (7 days back)
+		{Date: today.AddDate(0, 0, -8), Close: 69.00}, // 8 days ago
 	}
 
 	marketStore := &stubMarketDataStorage{
@@ -3821,7 +4774,7 @@ func TestPopulateHistoricalValues_WithUSDHolding(t *testing.T) {
 		t.Errorf("YesterdayClose = %v, want %v", h.YesterdayClosePrice, expectedYesterdayClose)
 	}
 
-	// Last week close in AUD: EOD[4] = 70.00 / 0.65 = 107.69
+	// Last week close in AUD (7 days back): 70.00 / 0.65 = 107.69
 	expectedLastWeekClose := 70.00 / fxRate
 	if !approxEqual(h.LastWeekClosePrice, expectedLastWeekClose, 0.01) {
 		t.Errorf("LastWeekClose = %v, want %v", h.LastWeekClosePrice, expectedLastWeekClose)
@@ -3857,8 +4810,8 @@ func TestPopulateHistoricalValues_WithExternalBalances(t *testing.T) {
 		{Date: today.AddDate(0, 0, -2), Close: 47.50},
 		{Date: today.AddDate(0, 0, -3), Close: 47.00},
 		{Date: today.AddDate(0, 0, -4), Close: 46.50},
-		{Date: today.AddDate(0, 0, -5), Close: 46.00}, // last week (EOD[4])
-		{Date: today.AddDate(0, 0, -6), Close: 45.50},
+		{Date: today.AddDate(0, 0, -7), Close: 46.00}, // last week (7 days back)
+		{Date: today.AddDate(0, 0, -8), Close: 45.50},
 	}
 
 	marketStore := &stubMarketDataStorage{
@@ -3883,13 +4836,192 @@ func TestPopulateHistoricalValues_WithExternalBalances(t *testing.T) {
 		t.Errorf("YesterdayTotal = %v, want %v", portfolio.PortfolioYesterdayValue, expectedYesterdayTotal)
 	}
 
-	// Last week total should include external balances: EOD[4]=46.00*100 + 50000 = 54600
+	// Last week total should include external balances: last week=46.00*100 + 50000 = 54600
 	expectedLastWeekTotal := 46.00*100 + 50000
 	if !approxEqual(portfolio.PortfolioLastWeekValue, expectedLastWeekTotal, 0.01) {
 		t.Errorf("LastWeekTotal = %v, want %v", portfolio.PortfolioLastWeekValue, expectedLastWeekTotal)
 	}
 }
 
+func TestPopulateHistoricalValues_MonthAndQuarter(t *testing.T) {
+	today := time.Now()
+
+	portfolio := &models.Portfolio{
+		Name:                 "SMSF",
+		EquityHoldingsReturn: 5000.00,
+		PortfolioValue:       8000.00, // 5000 equity + 3000 available
+		CapitalAvailable:     3000.00,
+		FXRate:               0,
+		Holdings: []models.Holding{
+			{
+				Ticker:       "BHP",
+				Exchange:     "AU",
+				Units:        100,
+				CurrentPrice: 50.00,
+				MarketValue:  5000.00,
+				Currency:     "AUD",
+			},
+		},
+	}
+
+	eod := []models.EODBar{
+		{Date: today.AddDate(0, 0, -1), Close: 48.00},  // yesterday
+		{Date: today.AddDate(0, 0, -7), Close: 46.00},  // last week
+		{Date: today.AddDate(0, -1, -2), Close: 44.00}, // last month (on or before 1 month back)
+		{Date: today.AddDate(0, -2, 0), Close: 42.00},
+		{Date: today.AddDate(0, -3, -1), Close: 40.00}, // last quarter (on or before 3 months back)
+		{Date: today.AddDate(0, -4, 0), Close: 38.00},
+	}
+
+	storage := &stubStorageManager{
+		marketStore: &stubMarketDataStorage{
+			data: map[string]*models.MarketData{
+				"BHP.AU": {Ticker: "BHP.AU", EOD: eod},
+			},
+		},
+		userDataStore: newMemUserDataStore(),
+	}
+	svc := NewService(storage, nil, nil, nil, common.NewLogger("error"))
+
+	svc.populateHistoricalValues(context.Background(), portfolio)
+
+	h := portfolio.Holdings[0]
+	if !approxEqual(h.LastMonthClosePrice, 44.00, 0.01) {
+		t.Errorf("LastMonthClose = %v, want 44.00", h.LastMonthClosePrice)
+	}
+	expectedMonthPct := (50.00 - 44.00) / 44.00 * 100
+	if !approxEqual(h.LastMonthPriceChangePct, expectedMonthPct, 0.01) {
+		t.Errorf("LastMonthPct = %v, want %v", h.LastMonthPriceChangePct, expectedMonthPct)
+	}
+	if !approxEqual(h.LastQuarterClosePrice, 40.00, 0.01) {
+		t.Errorf("LastQuarterClose = %v, want 40.00", h.LastQuarterClosePrice)
+	}
+	expectedQuarterPct := (50.00 - 40.00) / 40.00 * 100
+	if !approxEqual(h.LastQuarterPriceChangePct, expectedQuarterPct, 0.01) {
+		t.Errorf("LastQuarterPct = %v, want %v", h.LastQuarterPriceChangePct, expectedQuarterPct)
+	}
+
+	// Portfolio aggregates include available cash: 44*100 + 3000 = 7400
+	if !approxEqual(portfolio.PortfolioLastMonthValue, 7400, 0.01) {
+		t.Errorf("LastMonthTotal = %v, want 7400", portfolio.PortfolioLastMonthValue)
+	}
+	expectedMonthTotalPct := (8000.00 - 7400.00) / 7400.00 * 100
+	if !approxEqual(portfolio.PortfolioLastMonthChangePct, expectedMonthTotalPct, 0.01) {
+		t.Errorf("LastMonthTotalPct = %v, want %v", portfolio.PortfolioLastMonthChangePct, expectedMonthTotalPct)
+	}
+	// 40*100 + 3000 = 7000
+	if !approxEqual(portfolio.PortfolioLastQuarterValue, 7000, 0.01) {
+		t.Errorf("LastQuarterTotal = %v, want 7000", portfolio.PortfolioLastQuarterValue)
+	}
+	expectedQuarterTotalPct := (8000.00 - 7000.00) / 7000.00 * 100
+	if !approxEqual(portfolio.PortfolioLastQuarterChangePct, expectedQuarterTotalPct, 0.01) {
+		t.Errorf("LastQuarterTotalPct = %v, want %v", portfolio.PortfolioLastQuarterChangePct, expectedQuarterTotalPct)
+	}
+}
+
+func TestPopulateHistoricalValues_MonthAndQuarterWithUSDHolding(t *testing.T) {
+	today := time.Now()
+	fxRate := 0.65 // AUDUSD
+
+	portfolio := &models.Portfolio{
+		Name:                 "SMSF",
+		EquityHoldingsReturn: 5000.00,
+		PortfolioValue:       5000.00,
+		FXRate:               fxRate,
+		Holdings: []models.Holding{
+			{
+				Ticker:           "AAPL",
+				Exchange:         "US",
+				Units:            100,
+				CurrentPrice:     50.00,
+				MarketValue:      5000.00,
+				Currency:         "AUD",
+				OriginalCurrency: "USD",
+			},
+		},
+	}
+
+	eod := []models.EODBar{
+		{Date: today.AddDate(0, 0, -1), Close: 74.00},
+		{Date: today.AddDate(0, -1, -1), Close: 68.00}, // last month USD
+		{Date: today.AddDate(0, -3, -1), Close: 60.00}, // last quarter USD
+	}
+
+	storage := &stubStorageManager{
+		marketStore: &stubMarketDataStorage{
+			data: map[string]*models.MarketData{
+				"AAPL.US": {Ticker: "AAPL.US", EOD: eod},
+			},
+		},
+		userDataStore: newMemUserDataStore(),
+	}
+	svc := NewService(storage, nil, nil, nil, common.NewLogger("error"))
+
+	svc.populateHistoricalValues(context.Background(), portfolio)
+
+	h := portfolio.Holdings[0]
+	if !approxEqual(h.LastMonthClosePrice, 68.00/fxRate, 0.01) {
+		t.Errorf("LastMonthClose = %v, want %v", h.LastMonthClosePrice, 68.00/fxRate)
+	}
+	if !approxEqual(h.LastQuarterClosePrice, 60.00/fxRate, 0.01) {
+		t.Errorf("LastQuarterClose = %v, want %v", h.LastQuarterClosePrice, 60.00/fxRate)
+	}
+	if !approxEqual(portfolio.PortfolioLastQuarterValue, 60.00/fxRate*100, 0.01) {
+		t.Errorf("LastQuarterTotal = %v, want %v", portfolio.PortfolioLastQuarterValue, 60.00/fxRate*100)
+	}
+}
+
+func TestPopulateHistoricalValues_InsufficientQuarterHistory(t *testing.T) {
+	today := time.Now()
+
+	portfolio := &models.Portfolio{
+		Name:                 "SMSF",
+		EquityHoldingsReturn: 5000.00,
+		PortfolioValue:       5000.00,
+		Holdings: []models.Holding{
+			{
+				Ticker:       "BHP",
+				Exchange:     "AU",
+				Units:        100,
+				CurrentPrice: 50.00,
+				MarketValue:  5000.00,
+				Currency:     "AUD",
+			},
+		},
+	}
+
+	// Six weeks of history: enough for last month, not last quarter
+	eod := []models.EODBar{
+		{Date: today.AddDate(0, 0, -1), Close: 48.00},
+		{Date: today.AddDate(0, 0, -7), Close: 46.00},
+		{Date: today.AddDate(0, -1, -1), Close: 44.00},
+		{Date: today.AddDate(0, 0, -42), Close: 43.00},
+	}
+
+	storage := &stubStorageManager{
+		marketStore: &stubMarketDataStorage{
+			data: map[string]*models.MarketData{
+				"BHP.AU": {Ticker: "BHP.AU", EOD: eod},
+			},
+		},
+		userDataStore: newMemUserDataStore(),
+	}
+	svc := NewService(storage, nil, nil, nil, common.NewLogger("error"))
+
+	svc.populateHistoricalValues(context.Background(), portfolio)
+
+	h := portfolio.Holdings[0]
+	if !approxEqual(h.LastMonthClosePrice, 44.00, 0.01) {
+		t.Errorf("LastMonthClose = %v, want 44.00", h.LastMonthClosePrice)
+	}
+	if h.LastQuarterClosePrice != 0 || h.LastQuarterPriceChangePct != 0 {
+		t.Errorf("LastQuarter = %v/%v%%, want 0 (insufficient data)", h.LastQuarterClosePrice, h.LastQuarterPriceChangePct)
+	}
+	if portfolio.PortfolioLastQuarterValue != 0 || portfolio.PortfolioLastQuarterChangePct != 0 {
+		t.Errorf("LastQuarterTotal = %v/%v%%, want 0 (insufficient data)", portfolio.PortfolioLastQuarterValue, portfolio.PortfolioLastQuarterChangePct)
+	}
+}
+
 func TestPopulateHistoricalValues_SkipsClosedPositions(t *testing.T) {
 	today := time.Now()
 
@@ -3992,7 +5124,7 @@ func TestPopulateHistoricalValues_InsufficientEODData(t *testing.T) {
 	if h.YesterdayPriceChangePct != 0 {
 		t.Errorf("YesterdayPct = %v, want 0 (insufficient data)", h.YesterdayPriceChangePct)
 	}
-	// Last week also needs at least 6 bars
+	// Last week also needs a bar 7 days back
 	if h.LastWeekClosePrice != 0 {
 		t.Errorf("LastWeekClose = %v, want 0 (insufficient data)", h.LastWeekClosePrice)
 	}
@@ -4312,28 +5444,122 @@ func TestHolding_TotalProceeds_FXConverted(t *testing.T) {
 
 	logger := common.NewLogger("error")
 	svc := NewService(storage, nil, mockEODHD, nil, logger)
+	svc.SetFXService(fxpkg.NewService(mockEODHD, logger))
+	ctx := common.WithNavexaClient(context.Background(), navexa)
+
+	portfolio, err := svc.SyncPortfolio(ctx, "Personal", true)
+	if err != nil {
+		t.Fatalf("SyncPortfolio failed: %v", err)
+	}
+
+	var aapl *models.Holding
+	for i := range portfolio.Holdings {
+		if portfolio.Holdings[i].Ticker == "AAPL" {
+			aapl = &portfolio.Holdings[i]
+			break
+		}
+	}
+	if aapl == nil {
+		t.Fatal("AAPL holding not found")
+	}
+
+	// TotalProceeds should be FX-converted: 12000 USD / 0.65 = 18461.54 AUD
+	wantProceeds := 12000.0 / fxRate
+	if !approxEqual(aapl.GrossProceeds, wantProceeds, 1.0) {
+		t.Errorf("TotalProceeds = %.2f, want %.2f (FX-converted from USD)", aapl.GrossProceeds, wantProceeds)
+	}
+}
+
+// stubFXService serves fixed base/quote rates
+type stubFXService map[string]float64
+
+func (f stubFXService) Rate(_ context.Context, base, quote string) (float64, error) {
+	if base == quote {
+		return 1, nil
+	}
+	if r, ok := f[base+quote]; ok {
+		return r, nil
+	}
+	return 0, fmt.Errorf("no %s/%s rate", base, quote)
+}
+
+func TestSyncPortfolio_ConvertsIntoGBPBase(t *testing.T) {
+	today := time.Now()
+	navexa := &stubNavexaClient{
+		portfolios: []*models.NavexaPortfolio{
+			{ID: "1", Name: "ISA", Currency: "GBP", DateCreated: "2020-01-01"},
+		},
+		holdings: []*models.NavexaHolding{
+			{
+				ID: "1", PortfolioID: "1", Ticker: "AAPL", Exchange: "US", Name: "Apple Inc",
+				Units: 10, CurrentPrice: 200, MarketValue: 2000, Currency: "USD", LastUpdated: today,
+			},
+			{
+				ID: "2", PortfolioID: "1", Ticker: "SAP", Exchange: "XETRA", Name: "SAP SE",
+				Units: 10, CurrentPrice: 115, MarketValue: 1150, Currency: "EUR", LastUpdated: today,
+			},
+			{
+				ID: "3", PortfolioID: "1", Ticker: "7203", Exchange: "TSE", Name: "Toyota",
+				Units: 100, CurrentPrice: 3000, MarketValue: 300000, Currency: "JPY", LastUpdated: today,
+			},
+		},
+		trades: map[string][]*models.NavexaTrade{
+			"1": {{Type: "buy", Units: 10, Price: 150, Date: "2024-01-02"}},
+			"2": {{Type: "buy", Units: 10, Price: 100, Date: "2024-01-02"}},
+			"3": {{Type: "buy", Units: 100, Price: 2500, Date: "2024-01-02"}},
+		},
+	}
+	storage := &stubStorageManager{
+		marketStore:   &stubMarketDataStorage{data: map[string]*models.MarketData{}},
+		userDataStore: newMemUserDataStore(),
+	}
+
+	svc := NewService(storage, nil, nil, nil, common.NewLogger("error"))
+	svc.SetFXService(stubFXService{"GBPUSD": 1.25, "GBPEUR": 1.15}) // no GBPJPY
 	ctx := common.WithNavexaClient(context.Background(), navexa)
 
-	portfolio, err := svc.SyncPortfolio(ctx, "Personal", true)
+	portfolio, err := svc.SyncPortfolio(ctx, "ISA", true)
 	if err != nil {
 		t.Fatalf("SyncPortfolio failed: %v", err)
 	}
+	if portfolio.BaseCurrency != "GBP" {
+		t.Errorf("BaseCurrency = %q, want GBP", portfolio.BaseCurrency)
+	}
+	if portfolio.FXRate != 1.25 || portfolio.FXRates["EUR"] != 1.15 {
+		t.Errorf("FXRate = %v, FXRates = %v; want GBPUSD 1.25 and EUR 1.15", portfolio.FXRate, portfolio.FXRates)
+	}
 
-	var aapl *models.Holding
-	for i := range portfolio.Holdings {
-		if portfolio.Holdings[i].Ticker == "AAPL" {
-			aapl = &portfolio.Holdings[i]
-			break
-		}
+	byTicker := make(map[string]models.Holding)
+	for _, h := range portfolio.Holdings {
+		byTicker[h.Ticker] = h
 	}
-	if aapl == nil {
-		t.Fatal("AAPL holding not found")
+
+	for _, tc := range []struct {
+		ticker, original string
+		rate, value      float64
+	}{
+		{"AAPL", "USD", 1.25, 2000},
+		{"SAP", "EUR", 1.15, 1150},
+	} {
+		h := byTicker[tc.ticker]
+		if h.Currency != "GBP" || h.OriginalCurrency != tc.original {
+			t.Errorf("%s: Currency/OriginalCurrency = %s/%s, want GBP/%s", tc.ticker, h.Currency, h.OriginalCurrency, tc.original)
+		}
+		if !approxEqual(h.MarketValue, tc.value/tc.rate, 0.01) {
+			t.Errorf("%s: MarketValue = %.2f, want %.2f GBP", tc.ticker, h.MarketValue, tc.value/tc.rate)
+		}
 	}
 
-	// TotalProceeds should be FX-converted: 12000 USD / 0.65 = 18461.54 AUD
-	wantProceeds := 12000.0 / fxRate
-	if !approxEqual(aapl.GrossProceeds, wantProceeds, 1.0) {
-		t.Errorf("TotalProceeds = %.2f, want %.2f (FX-converted from USD)", aapl.GrossProceeds, wantProceeds)
+	// Missing rate: left in its own currency, not marked converted
+	jpy := byTicker["7203"]
+	if jpy.Currency != "JPY" || jpy.OriginalCurrency != "" {
+		t.Errorf("7203: Currency/OriginalCurrency = %s/%s, want JPY/empty (unconverted)", jpy.Currency, jpy.OriginalCurrency)
+	}
+	if !approxEqual(jpy.MarketValue, 300000, 0.01) {
+		t.Errorf("7203: MarketValue = %.2f, want 300000 (unconverted)", jpy.MarketValue)
+	}
+	if _, ok := portfolio.FXRates["JPY"]; ok {
+		t.Error("expected no JPY rate recorded")
 	}
 }
 
@@ -4369,8 +5595,8 @@ func TestPopulateHistoricalValues_UsesAvailableCash(t *testing.T) {
 		{Date: today.AddDate(0, 0, -2), Close: 47.50},
 		{Date: today.AddDate(0, 0, -3), Close: 47.00},
 		{Date: today.AddDate(0, 0, -4), Close: 46.50},
-		{Date: today.AddDate(0, 0, -5), Close: 46.00}, // last week (EOD[4])
-		{Date: today.AddDate(0, 0, -6), Close: 45.50},
+		{Date: today.AddDate(0, 0, -7), Close: 46.00}, // last week (7 days back)
+		{Date: today.AddDate(0, 0, -8), Close: 45.50},
 	}
 
 	marketStore := &stubMarketDataStorage{
@@ -4394,7 +5620,7 @@ func TestPopulateHistoricalValues_UsesAvailableCash(t *testing.T) {
 		t.Errorf("YesterdayTotal = %.2f, want %.2f (equity yesterday + availableCash)", portfolio.PortfolioYesterdayValue, wantYesterday)
 	}
 
-	// LastWeekTotal = EOD[4]=46.00*100 + 3000 = 7600
+	// LastWeekTotal = last week=46.00*100 + 3000 = 7600
 	wantLastWeek := 46.00*100 + 3000.0
 	if !approxEqual(portfolio.PortfolioLastWeekValue, wantLastWeek, 0.01) {
 		t.Errorf("LastWeekTotal = %.2f, want %.2f (equity lastweek + availableCash)", portfolio.PortfolioLastWeekValue, wantLastWeek)
@@ -5207,3 +6433,532 @@ func TestPopulateChanges_NoTimelineNoLedger(t *testing.T) {
 		t.Error("Yesterday.IncomeDividends.HasPrevious should be false")
 	}
 }
+
+func TestEnsureSynced_RespectsMaxAge(t *testing.T) {
+	navexa := &countingNavexaClient{stubNavexaClient: &stubNavexaClient{
+		portfolios: []*models.NavexaPortfolio{
+			{ID: "1", Name: "SMSF", Currency: "AUD", DateCreated: "2020-01-01"},
+		},
+		holdings: []*models.NavexaHolding{
+			{ID: "100", PortfolioID: "1", Ticker: "BHP", Exchange: "ASX", Name: "BHP Group",
+				Units: 100, CurrentPrice: 45.00, MarketValue: 4500.00, Currency: "AUD", LastUpdated: time.Now()},
+		},
+		trades: map[string][]*models.NavexaTrade{
+			"100": {{ID: "1", HoldingID: "100", Symbol: "BHP", Type: "buy", Units: 100, Price: 40.0}},
+		},
+	}}
+
+	storage := &stubStorageManager{
+		marketStore:   &stubMarketDataStorage{data: map[string]*models.MarketData{}},
+		userDataStore: newMemUserDataStore(),
+	}
+	svc := NewService(storage, nil, nil, nil, common.NewLogger("error"))
+	ctx := common.WithNavexaClient(context.Background(), navexa)
+
+	// Stored portfolio last synced 10 minutes ago
+	lastSynced := time.Now().Add(-10 * time.Minute)
+	if err := svc.savePortfolioRecord(ctx, &models.Portfolio{Name: "SMSF", LastSynced: lastSynced}); err != nil {
+		t.Fatalf("seed portfolio: %v", err)
+	}
+
+	// Within maxAge: cached portfolio returned, no Navexa call
+	got, err := svc.EnsureSynced(ctx, "SMSF", time.Hour)
+	if err != nil {
+		t.Fatalf("EnsureSynced(1h) failed: %v", err)
+	}
+	if navexa.callCount != 0 {
+		t.Errorf("expected no sync within maxAge, got %d Navexa calls", navexa.callCount)
+	}
+	if !got.LastSynced.Equal(lastSynced) {
+		t.Errorf("LastSynced changed to %v, want cached %v", got.LastSynced, lastSynced)
+	}
+
+	// Beyond maxAge: portfolio is re-synced from Navexa
+	got, err = svc.EnsureSynced(ctx, "SMSF", 5*time.Minute)
+	if err != nil {
+		t.Fatalf("EnsureSynced(5m) failed: %v", err)
+	}
+	if navexa.callCount != 1 {
+		t.Errorf("expected 1 sync beyond maxAge, got %d Navexa calls", navexa.callCount)
+	}
+	if !got.LastSynced.After(lastSynced) {
+		t.Errorf("LastSynced = %v, want refreshed after %v", got.LastSynced, lastSynced)
+	}
+	if len(got.Holdings) != 1 {
+		t.Errorf("expected 1 synced holding, got %d", len(got.Holdings))
+	}
+}
+
+func TestSyncPortfolio_NormalizesCentsQuotedEODHDPrice(t *testing.T) {
+	today := time.Now()
+	navexaPrice := 1.50 // dollars
+	eodhdClose := 152.0 // cents
+
+	newNavexa := func() *stubNavexaClient {
+		return &stubNavexaClient{
+			portfolios: []*models.NavexaPortfolio{
+				{ID: "1", Name: "SMSF", Currency: "AUD", DateCreated: "2020-01-01"},
+			},
+			holdings: []*models.NavexaHolding{
+				{
+					ID: "100", PortfolioID: "1", Ticker: "PNN", Exchange: "ASX",
+					Name: "Pepinnini", Units: 1000, CurrentPrice: navexaPrice,
+					MarketValue: navexaPrice * 1000, Currency: "AUD", LastUpdated: today,
+				},
+			},
+			trades: map[string][]*models.NavexaTrade{
+				"100": {{ID: "1", HoldingID: "100", Symbol: "PNN", Type: "buy", Units: 1000, Price: 1.20}},
+			},
+		}
+	}
+	newStorage := func() *stubStorageManager {
+		return &stubStorageManager{
+			marketStore: &stubMarketDataStorage{
+				data: map[string]*models.MarketData{
+					"PNN.AU": {
+						Ticker: "PNN.AU",
+						EOD: []models.EODBar{
+							{Date: today, Close: eodhdClose},
+							{Date: today.AddDate(0, 0, -1), Close: 148.0},
+						},
+					},
+				},
+			},
+			userDataStore: newMemUserDataStore(),
+		}
+	}
+
+	svc := NewService(newStorage(), nil, nil, nil, common.NewLogger("error"))
+	ctx := common.WithNavexaClient(context.Background(), newNavexa())
+	portfolio, err := svc.SyncPortfolio(ctx, "SMSF", true)
+	if err != nil {
+		t.Fatalf("SyncPortfolio failed: %v", err)
+	}
+	if len(portfolio.Holdings) != 1 {
+		t.Fatalf("expected 1 holding, got %d", len(portfolio.Holdings))
+	}
+
+	h := portfolio.Holdings[0]
+	if !approxEqual(h.CurrentPrice, 1.52, 0.0001) {
+		t.Errorf("CurrentPrice = %.4f, want 1.52 (EODHD cents normalised to dollars)", h.CurrentPrice)
+	}
+	if !approxEqual(h.MarketValue, 1520, 0.01) {
+		t.Errorf("MarketValue = %.2f, want 1520 (no 100x valuation error)", h.MarketValue)
+	}
+	if !h.PriceInCents {
+		t.Error("PriceInCents = false, want true")
+	}
+	// Historical closes from the same EODHD series are normalised too
+	// (yesterday is the bar before today's)
+	if !approxEqual(h.YesterdayClosePrice, 1.48, 0.0001) {
+		t.Errorf("YesterdayClosePrice = %.4f, want 1.48", h.YesterdayClosePrice)
+	}
+
+	// Disabled: the cents price is not normalised and the divergence guard keeps Navexa's price
+	svc = NewService(newStorage(), nil, nil, nil, common.NewLogger("error"))
+	svc.SetNormalizeCents(false)
+	ctx = common.WithNavexaClient(context.Background(), newNavexa())
+	portfolio, err = svc.SyncPortfolio(ctx, "SMSF", true)
+	if err != nil {
+		t.Fatalf("SyncPortfolio failed: %v", err)
+	}
+	h = portfolio.Holdings[0]
+	if !approxEqual(h.CurrentPrice, navexaPrice, 0.0001) || h.PriceInCents {
+		t.Errorf("with normalisation off: CurrentPrice = %.4f PriceInCents=%v, want %.2f/false", h.CurrentPrice, h.PriceInCents, navexaPrice)
+	}
+}
+
+func TestPopulateHistoricalValues_ReturnContributionsSumToPortfolioReturn(t *testing.T) {
+	today := time.Now()
+	bars := func(lastWeek, yesterday float64) []models.EODBar {
+		return []models.EODBar{
+			{Date: today.AddDate(0, 0, -1), Close: yesterday},
+			{Date: today.AddDate(0, 0, -2), Close: yesterday},
+			{Date: today.AddDate(0, 0, -3), Close: yesterday},
+			{Date: today.AddDate(0, 0, -4), Close: yesterday},
+			{Date: today.AddDate(0, 0, -7), Close: lastWeek}, // 7 days back
+			{Date: today.AddDate(0, 0, -8), Close: lastWeek},
+		}
+	}
+
+	// BHP 100 × 40 → 50 (+1000), CBA 50 × 110 → 100 (-500), 2000 cash.
+	// Last week value 4000 + 5500 + 2000 = 11500; today 5000 + 5000 + 2000 = 12000.
+	portfolio := &models.Portfolio{
+		Name:             "SMSF",
+		PortfolioValue:   12000,
+		CapitalAvailable: 2000,
+		Holdings: []models.Holding{
+			{Ticker: "BHP", Exchange: "AU", Units: 100, CurrentPrice: 50, MarketValue: 5000},
+			{Ticker: "CBA", Exchange: "AU", Units: 50, CurrentPrice: 100, MarketValue: 5000},
+		},
+	}
+	storage := &stubStorageManager{
+		marketStore: &stubMarketDataStorage{data: map[string]*models.MarketData{
+			"BHP.AU": {Ticker: "BHP.AU", EOD: bars(40, 49)},
+			"CBA.AU": {Ticker: "CBA.AU", EOD: bars(110, 101)},
+		}},
+		userDataStore: newMemUserDataStore(),
+	}
+	svc := NewService(storage, nil, nil, nil, common.NewLogger("error"))
+
+	svc.populateHistoricalValues(context.Background(), portfolio)
+
+	if !approxEqual(portfolio.PortfolioLastWeekValue, 11500, 0.01) {
+		t.Fatalf("PortfolioLastWeekValue = %.2f, want 11500", portfolio.PortfolioLastWeekValue)
+	}
+	bhp, cba := portfolio.Holdings[0], portfolio.Holdings[1]
+	if !approxEqual(bhp.ReturnContributionPct, 1000.0/11500*100, 1e-6) {
+		t.Errorf("BHP contribution = %.4f, want %.4f", bhp.ReturnContributionPct, 1000.0/11500*100)
+	}
+	if !approxEqual(cba.ReturnContributionPct, -500.0/11500*100, 1e-6) {
+		t.Errorf("CBA contribution = %.4f, want %.4f", cba.ReturnContributionPct, -500.0/11500*100)
+	}
+	sum := bhp.ReturnContributionPct + cba.ReturnContributionPct
+	if !approxEqual(sum, portfolio.PortfolioLastWeekChangePct, 1e-6) {
+		t.Errorf("contributions sum to %.4f, want portfolio return %.4f", sum, portfolio.PortfolioLastWeekChangePct)
+	}
+}
+
+// filteredGeminiClient blocks every request the way Gemini's safety filters do.
+type filteredGeminiClient struct{}
+
+func (filteredGeminiClient) GenerateContent(context.Context, string) (string, error) {
+	return "", &interfaces.ContentFilteredError{Reason: "SAFETY"}
+}
+func (filteredGeminiClient) GenerateWithURLContext(context.Context, string, ...string) (string, error) {
+	return "", &interfaces.ContentFilteredError{Reason: "SAFETY"}
+}
+func (filteredGeminiClient) AnalyzeStock(context.Context, string, *models.StockData) (string, error) {
+	return "", &interfaces.ContentFilteredError{Reason: "SAFETY"}
+}
+func (filteredGeminiClient) SummariseFilingPDF(context.Context, string, string) (string, error) {
+	return "", &interfaces.ContentFilteredError{Reason: "SAFETY"}
+}
+func (filteredGeminiClient) AnalyzeStream(context.Context, string) (<-chan string, <-chan error) {
+	chunks, errs := make(chan string), make(chan error, 1)
+	errs <- &interfaces.ContentFilteredError{Reason: "SAFETY"}
+	close(chunks)
+	close(errs)
+	return chunks, errs
+}
+func (filteredGeminiClient) ActiveModels() map[string]string { return nil }
+
+func TestReviewPortfolio_ContentFilteredSummary(t *testing.T) {
+	today := time.Now()
+	portfolio := &models.Portfolio{
+		Name:           "SMSF",
+		PortfolioValue: 4250,
+		LastSynced:     today,
+		Holdings: []models.Holding{
+			{Ticker: "BHP", Exchange: "AU", Name: "BHP Group", Units: 100, CurrentPrice: 42.50, MarketValue: 4250, WeightPct: 100},
+		},
+	}
+	uds := newMemUserDataStore()
+	storePortfolio(t, uds, portfolio)
+	storage := &reviewStorageManager{
+		userDataStore: uds,
+		marketStore: &reviewMarketDataStorage{data: map[string]*models.MarketData{
+			"BHP.AU": {Ticker: "BHP.AU", EOD: []models.EODBar{{Date: today, Close: 42.50}}},
+		}},
+		signalStore: &reviewSignalStorage{signals: map[string]*models.TickerSignals{}},
+	}
+
+	svc := NewService(storage, nil, nil, filteredGeminiClient{}, common.NewLogger("error"))
+
+	review, err := svc.ReviewPortfolio(context.Background(), "SMSF", interfaces.ReviewOptions{})
+	if err != nil {
+		t.Fatalf("ReviewPortfolio failed on a content-filtered summary: %v", err)
+	}
+	if review.Summary != common.DefaultContentFilteredNote {
+		t.Errorf("Summary = %q, want %q", review.Summary, common.DefaultContentFilteredNote)
+	}
+
+	svc.SetContentFilteredNote("AI summary withheld.")
+	review, err = svc.ReviewPortfolio(context.Background(), "SMSF", interfaces.ReviewOptions{})
+	if err != nil {
+		t.Fatalf("ReviewPortfolio failed: %v", err)
+	}
+	if review.Summary != "AI summary withheld." {
+		t.Errorf("Summary = %q, want the configured note", review.Summary)
+	}
+}
+
+// recordingStreamClient streams fixed chunks and records the prompt it was
+// given; GenerateContent must not be called by a streamed review.
+type recordingStreamClient struct {
+	filteredGeminiClient
+	prompt    string
+	generated bool
+}
+
+func (c *recordingStreamClient) GenerateContent(context.Context, string) (string, error) {
+	c.generated = true
+	return "blocking summary", nil
+}
+func (c *recordingStreamClient) AnalyzeStream(_ context.Context, prompt string) (<-chan string, <-chan error) {
+	c.prompt = prompt
+	chunks, errs := make(chan string, 2), make(chan error)
+	chunks <- "Looks "
+	chunks <- "fine."
+	close(chunks)
+	close(errs)
+	return chunks, errs
+}
+
+func TestStreamPortfolioReview_SharesReviewPreparation(t *testing.T) {
+	today := time.Now()
+	portfolio := &models.Portfolio{
+		Name:           "SMSF",
+		PortfolioValue: 4250,
+		LastSynced:     today,
+		Holdings: []models.Holding{
+			{Ticker: "BHP", Exchange: "AU", Name: "BHP Group", Units: 100, CurrentPrice: 42.50, MarketValue: 4250, WeightPct: 100},
+		},
+	}
+	uds := newMemUserDataStore()
+	storePortfolio(t, uds, portfolio)
+	data, _ := json.Marshal(models.PortfolioStrategy{
+		PortfolioName: "SMSF",
+		RiskAppetite:  models.RiskAppetite{Level: "conservative"},
+	})
+	_ = uds.Put(context.Background(), &models.UserRecord{
+		UserID: common.ResolveUserID(context.Background()), Subject: "strategy", Key: "SMSF", Value: string(data),
+	})
+	storage := &reviewStorageManager{
+		userDataStore: uds,
+		marketStore: &reviewMarketDataStorage{data: map[string]*models.MarketData{
+			"BHP.AU": {Ticker: "BHP.AU", EOD: []models.EODBar{{Date: today, Close: 42.50}}},
+		}},
+		signalStore: &reviewSignalStorage{signals: map[string]*models.TickerSignals{}},
+	}
+	client := &recordingStreamClient{}
+	svc := NewService(storage, nil, nil, client, common.NewLogger("error"))
+
+	review, chunks, errs, err := svc.StreamPortfolioReview(context.Background(), "SMSF", interfaces.ReviewOptions{})
+	if err != nil {
+		t.Fatalf("StreamPortfolioReview failed: %v", err)
+	}
+	var text string
+	for c := range chunks {
+		text += c
+	}
+	if err := <-errs; err != nil {
+		t.Errorf("stream error = %v", err)
+	}
+
+	if client.generated || review.Summary != "" {
+		t.Errorf("blocking summary generated (Summary %q); the stream replaces it", review.Summary)
+	}
+	if len(review.HoldingReviews) != 1 {
+		t.Errorf("holding reviews = %d, want 1", len(review.HoldingReviews))
+	}
+	if text != "Looks fine." {
+		t.Errorf("streamed text = %q", text)
+	}
+	if !strings.Contains(client.prompt, "conservative risk appetite") {
+		t.Errorf("prompt lacks the stored strategy:\n%s", client.prompt)
+	}
+}
+
+// stubHoldingNoteService serves a fixed set of holding notes.
+type stubHoldingNoteService struct {
+	notes *models.PortfolioHoldingNotes
+}
+
+func (s *stubHoldingNoteService) GetNotes(context.Context, string) (*models.PortfolioHoldingNotes, error) {
+	return s.notes, nil
+}
+func (s *stubHoldingNoteService) SaveNotes(context.Context, *models.PortfolioHoldingNotes) error {
+	return nil
+}
+func (s *stubHoldingNoteService) AddOrUpdateNote(context.Context, string, *models.HoldingNote) (*models.PortfolioHoldingNotes, error) {
+	return s.notes, nil
+}
+func (s *stubHoldingNoteService) UpdateNote(context.Context, string, string, *models.HoldingNote) (*models.PortfolioHoldingNotes, error) {
+	return s.notes, nil
+}
+func (s *stubHoldingNoteService) RemoveNote(context.Context, string, string) (*models.PortfolioHoldingNotes, error) {
+	return s.notes, nil
+}
+func (s *stubHoldingNoteService) GetAnnotations(context.Context, string) (map[string]*models.HoldingAnnotation, error) {
+	return nil, nil
+}
+func (s *stubHoldingNoteService) SetHoldingNote(context.Context, string, string, string) (*models.HoldingAnnotation, error) {
+	return nil, nil
+}
+func (s *stubHoldingNoteService) SetHoldingTags(context.Context, string, string, []string) (*models.HoldingAnnotation, error) {
+	return nil, nil
+}
+
+func TestReviewPortfolio_MutedHoldingRaisesNoAlerts(t *testing.T) {
+	today := time.Now()
+	portfolio := &models.Portfolio{
+		Name:           "SMSF",
+		PortfolioValue: 10000,
+		LastSynced:     today,
+		Holdings: []models.Holding{
+			{Ticker: "BHP", Exchange: "AU", Name: "BHP Group", Units: 100, CurrentPrice: 50, MarketValue: 5000, WeightPct: 50},
+			{Ticker: "CBA", Exchange: "AU", Name: "CBA Group", Units: 50, CurrentPrice: 100, MarketValue: 5000, WeightPct: 50},
+		},
+	}
+	uds := newMemUserDataStore()
+	storePortfolio(t, uds, portfolio)
+
+	// Both holdings are overbought, so both would normally alert
+	overbought := models.TechnicalSignals{RSI: 85}
+	storage := &reviewStorageManager{
+		userDataStore: uds,
+		marketStore: &reviewMarketDataStorage{data: map[string]*models.MarketData{
+			"BHP.AU": {Ticker: "BHP.AU", EOD: []models.EODBar{{Date: today, Close: 50}}},
+			"CBA.AU": {Ticker: "CBA.AU", EOD: []models.EODBar{{Date: today, Close: 100}}},
+		}},
+		signalStore: &reviewSignalStorage{signals: map[string]*models.TickerSignals{
+			"BHP.AU": {Ticker: "BHP.AU", Technical: overbought},
+			"CBA.AU": {Ticker: "CBA.AU", Technical: overbought},
+		}},
+	}
+
+	muted := true
+	svc := NewService(storage, nil, nil, nil, common.NewLogger("error"))
+	svc.SetHoldingNoteService(&stubHoldingNoteService{notes: &models.PortfolioHoldingNotes{
+		PortfolioName: "SMSF",
+		Items: []models.HoldingNote{
+			// Keyed by EODHD ticker to exercise the suffix fallback
+			{Ticker: "BHP.AU", AlertsMuted: &muted, ReviewedAt: today},
+		},
+	}})
+
+	review, err := svc.ReviewPortfolio(context.Background(), "SMSF", interfaces.ReviewOptions{})
+	if err != nil {
+		t.Fatalf("ReviewPortfolio failed: %v", err)
+	}
+
+	var bhp, cba int
+	for _, a := range review.Alerts {
+		switch a.Ticker {
+		case "BHP":
+			bhp++
+		case "CBA":
+			cba++
+		}
+	}
+	if bhp != 0 {
+		t.Errorf("muted BHP raised %d alerts, want 0", bhp)
+	}
+	if cba == 0 {
+		t.Error("unmuted CBA should still raise its overbought alert")
+	}
+}
+
+func TestSyncPortfolio_FlagsSuspectedSplit(t *testing.T) {
+	today := time.Now()
+	bought := today.AddDate(0, 0, -60).Format("2006-01-02")
+
+	// Navexa still reports the pre-split position: 100 units at $20. EODHD
+	// shows a 2:1 split two days ago — Close halves while AdjClose (back-
+	// adjusted) stays flat — so 100 units at today's price is half the value.
+	newNavexa := func() *stubNavexaClient {
+		return &stubNavexaClient{
+			portfolios: []*models.NavexaPortfolio{
+				{ID: "1", Name: "SMSF", Currency: "AUD", DateCreated: "2020-01-01"},
+			},
+			holdings: []*models.NavexaHolding{
+				{
+					ID: "100", PortfolioID: "1", Ticker: "SPL", Exchange: "ASX",
+					Name: "Split Co", Units: 100, CurrentPrice: 20,
+					MarketValue: 2000, Currency: "AUD", LastUpdated: today,
+				},
+			},
+			trades: map[string][]*models.NavexaTrade{
+				"100": {{ID: "1", HoldingID: "100", Symbol: "SPL", Type: "buy", Date: bought, Units: 100, Price: 15}},
+			},
+		}
+	}
+	newStorage := func(eod []models.EODBar) *stubStorageManager {
+		return &stubStorageManager{
+			marketStore: &stubMarketDataStorage{
+				data: map[string]*models.MarketData{
+					"SPL.AU": {Ticker: "SPL.AU", EOD: eod},
+				},
+			},
+			userDataStore: newMemUserDataStore(),
+		}
+	}
+	syncHolding := func(eod []models.EODBar) models.Holding {
+		t.Helper()
+		svc := NewService(newStorage(eod), nil, nil, nil, common.NewLogger("error"))
+		ctx := common.WithNavexaClient(context.Background(), newNavexa())
+		portfolio, err := svc.SyncPortfolio(ctx, "SMSF", true)
+		if err != nil {
+			t.Fatalf("SyncPortfolio failed: %v", err)
+		}
+		if len(portfolio.Holdings) != 1 {
+			t.Fatalf("expected 1 holding, got %d", len(portfolio.Holdings))
+		}
+		return portfolio.Holdings[0]
+	}
+
+	h := syncHolding([]models.EODBar{
+		{Date: today, Close: 10.2, AdjClose: 10.2},
+		{Date: today.AddDate(0, 0, -1), Close: 10.0, AdjClose: 10.0},
+		{Date: today.AddDate(0, 0, -2), Close: 20.0, AdjClose: 10.0},
+		{Date: today.AddDate(0, 0, -3), Close: 19.8, AdjClose: 9.9},
+	})
+	if !h.SplitSuspected {
+		t.Error("SplitSuspected = false, want true for an unrecorded 2:1 split")
+	}
+	if h.Units != 100 {
+		t.Errorf("Units = %.2f, want 100 (flag only, no adjustment)", h.Units)
+	}
+
+	// A genuine halving moves AdjClose too — not a split
+	h = syncHolding([]models.EODBar{
+		{Date: today, Close: 10.2, AdjClose: 10.2},
+		{Date: today.AddDate(0, 0, -1), Close: 10.0, AdjClose: 10.0},
+		{Date: today.AddDate(0, 0, -2), Close: 20.0, AdjClose: 20.0},
+		{Date: today.AddDate(0, 0, -3), Close: 19.8, AdjClose: 19.8},
+	})
+	if h.SplitSuspected {
+		t.Error("SplitSuspected = true for a real price move, want false")
+	}
+}
+
+func TestFindSplitJump_IgnoresJumpsBeforeFirstTrade(t *testing.T) {
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	bars := []models.EODBar{
+		{Date: day.AddDate(0, 0, 2), Close: 10},
+		{Date: day.AddDate(0, 0, 1), Close: 10},
+		{Date: day, Close: 20},
+	}
+	if _, ok := findSplitJump(bars, day.AddDate(0, 0, 1), 2); ok {
+		t.Error("expected a jump before the first trade to be ignored")
+	}
+	if bar, ok := findSplitJump(bars, day, 2); !ok || !bar.Date.Equal(day.AddDate(0, 0, 1)) {
+		t.Errorf("findSplitJump = %v/%v, want the first post-split bar", bar.Date, ok)
+	}
+	if _, ok := findSplitJump(bars, day, 3); ok {
+		t.Error("expected a 2x jump not to match a 3x divergence")
+	}
+}
+
+func TestSignalsForRSIPeriod_RecomputesForAnotherPeriod(t *testing.T) {
+	bars := make([]models.EODBar, 60)
+	for i := range bars {
+		bars[i] = models.EODBar{Date: time.Now().AddDate(0, 0, -i), Close: 100 + float64(i%7)}
+	}
+	md := &models.MarketData{Ticker: "BHP.AU", EOD: bars}
+	svc := NewService(&stubStorageManager{}, nil, nil, nil, common.NewLogger("error"))
+	stored := svc.signalComputer.ComputeWithRSIPeriod(md, 14)
+	stored.NewsSentiment, stored.NewsSentimentAt = 0.4, time.Now()
+
+	if got := svc.signalsForRSIPeriod(stored, md, 14); got != stored {
+		t.Error("matching period: want the stored signals reused")
+	}
+	got := svc.signalsForRSIPeriod(stored, md, 21)
+	if got.RSIPeriod != 21 || got.Technical.RSI != svc.signalComputer.ComputeWithRSIPeriod(md, 21).Technical.RSI {
+		t.Errorf("other period: RSI(%d) = %.4f, want signals recomputed over 21 bars", got.RSIPeriod, got.Technical.RSI)
+	}
+	if got.NewsSentiment != 0.4 {
+		t.Errorf("other period: news sentiment = %.2f, want the stored 0.40 kept", got.NewsSentiment)
+	}
+}
@@ -0,0 +1,84 @@
+package portfolio
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// SimulateTrade projects a buy or sell against the stored portfolio without
+// recording it. Brokerage from the configured fee model is added to a buy's
+// cost and deducted from a sell's proceeds, so CashImpact is the full change
+// in available cash.
+func (s *Service) SimulateTrade(ctx context.Context, portfolioName string, trade models.SimulatedTrade) (*models.TradeSimulation, error) {
+	action := strings.ToLower(strings.TrimSpace(trade.Action))
+	if action != "buy" && action != "sell" {
+		return nil, fmt.Errorf("action must be 'buy' or 'sell', got '%s'", trade.Action)
+	}
+	if trade.Units <= 0 {
+		return nil, fmt.Errorf("units must be positive")
+	}
+
+	portfolio, err := s.getPortfolioRecord(ctx, portfolioName)
+	if err != nil {
+		return nil, fmt.Errorf("portfolio '%s' not found: %w", portfolioName, err)
+	}
+
+	var holding *models.Holding
+	for i := range portfolio.Holdings {
+		h := &portfolio.Holdings[i]
+		if strings.EqualFold(h.Ticker, trade.Ticker) || strings.EqualFold(h.EODHDTicker(), trade.Ticker) {
+			holding = h
+			break
+		}
+	}
+
+	price := trade.Price
+	if price <= 0 && holding != nil {
+		price = holding.CurrentPrice
+	}
+	if price <= 0 {
+		return nil, fmt.Errorf("no price for '%s': provide one for tickers not held", trade.Ticker)
+	}
+
+	var held float64
+	if holding != nil {
+		held = holding.Units
+	}
+	if action == "sell" && trade.Units > held+1e-9 {
+		return nil, fmt.Errorf("cannot sell %.4f units of '%s': %.4f held", trade.Units, trade.Ticker, held)
+	}
+
+	value := trade.Units * price
+	fee := s.feeModel.Fee(value)
+
+	sim := &models.TradeSimulation{
+		PortfolioName: portfolioName,
+		Ticker:        trade.Ticker,
+		Action:        action,
+		Units:         trade.Units,
+		Price:         price,
+		TradeValue:    value,
+		Fee:           fee,
+		CashBefore:    portfolio.CapitalAvailable,
+		FeeModel:      s.feeModel,
+	}
+	if action == "buy" {
+		sim.CashImpact = -(value + fee)
+		sim.UnitsAfter = held + trade.Units
+	} else {
+		sim.CashImpact = value - fee
+		sim.UnitsAfter = held - trade.Units
+	}
+	sim.CashAfter = sim.CashBefore + sim.CashImpact
+	sim.InsufficientCash = action == "buy" && sim.CashAfter < 0
+
+	// The trade swaps cash for equity at the trade price; only the fee
+	// leaves the portfolio.
+	if total := portfolio.PortfolioValue - fee; total > 0 {
+		sim.WeightAfterPct = sim.UnitsAfter * price / total * 100
+	}
+	return sim, nil
+}
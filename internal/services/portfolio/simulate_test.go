@@ -0,0 +1,64 @@
+package portfolio
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+func TestSimulateTrade_PercentageFee(t *testing.T) {
+	uds := newMemUserDataStore()
+	storage := &stubStorageManager{
+		marketStore:   &stubMarketDataStorage{data: map[string]*models.MarketData{}},
+		userDataStore: uds,
+	}
+	svc := NewService(storage, nil, nil, nil, common.NewLogger("error"))
+	svc.SetFeeModel(models.FeeModel{Type: models.FeeModelPercentage, Pct: 0.5, Min: 10})
+	ctx := context.Background()
+
+	if err := svc.savePortfolioRecord(ctx, &models.Portfolio{
+		Name:             "SMSF",
+		PortfolioValue:   20000,
+		CapitalAvailable: 10000,
+		Holdings: []models.Holding{
+			{Ticker: "BHP", Exchange: "AU", Units: 100, CurrentPrice: 50, MarketValue: 5000},
+		},
+	}); err != nil {
+		t.Fatalf("savePortfolioRecord failed: %v", err)
+	}
+
+	sim, err := svc.SimulateTrade(ctx, "SMSF", models.SimulatedTrade{Ticker: "BHP.AU", Action: "buy", Units: 40})
+	if err != nil {
+		t.Fatalf("SimulateTrade failed: %v", err)
+	}
+
+	// 40 × $50 = $2,000; 0.5% fee = $10
+	if !approxEqual(sim.TradeValue, 2000, 0.001) || !approxEqual(sim.Fee, 10, 0.001) {
+		t.Errorf("TradeValue/Fee = %.2f/%.2f, want 2000/10", sim.TradeValue, sim.Fee)
+	}
+	if !approxEqual(sim.CashImpact, -2010, 0.001) {
+		t.Errorf("CashImpact = %.2f, want -2010 (trade value plus fee)", sim.CashImpact)
+	}
+	if !approxEqual(sim.CashAfter, 7990, 0.001) || sim.InsufficientCash {
+		t.Errorf("CashAfter = %.2f (insufficient=%v), want 7990", sim.CashAfter, sim.InsufficientCash)
+	}
+	if sim.UnitsAfter != 140 {
+		t.Errorf("UnitsAfter = %.0f, want 140", sim.UnitsAfter)
+	}
+
+	// Selling more than held is rejected
+	if _, err := svc.SimulateTrade(ctx, "SMSF", models.SimulatedTrade{Ticker: "BHP", Action: "sell", Units: 101}); err == nil {
+		t.Error("expected error selling more units than held")
+	}
+
+	// Sell: fee is deducted from proceeds (minimum fee applies to $500)
+	sim, err = svc.SimulateTrade(ctx, "SMSF", models.SimulatedTrade{Ticker: "BHP", Action: "sell", Units: 10})
+	if err != nil {
+		t.Fatalf("SimulateTrade sell failed: %v", err)
+	}
+	if !approxEqual(sim.CashImpact, 490, 0.001) {
+		t.Errorf("sell CashImpact = %.2f, want 490", sim.CashImpact)
+	}
+}
@@ -84,7 +84,8 @@ func findClosingPriceAsOf(bars []models.EODBar, asOf time.Time) (closePrice floa
 
 // GetPortfolioSnapshot reconstructs portfolio state as of a historical date.
 func (s *Service) GetPortfolioSnapshot(ctx context.Context, name string, asOf time.Time) (*models.PortfolioSnapshot, error) {
-	s.logger.Info().Str("name", name).Str("asOf", asOf.Format(time.RFC3339)).Msg("Building portfolio snapshot")
+	logger := s.logger.WithRequestID(ctx)
+	logger.Info().Str("name", name).Str("asOf", asOf.Format(time.RFC3339)).Msg("Building portfolio snapshot")
 
 	portfolio, err := s.getPortfolioRecord(ctx, name)
 	if err != nil {
@@ -112,13 +113,13 @@ func (s *Service) GetPortfolioSnapshot(ctx context.Context, name string, asOf ti
 		ticker := h.EODHDTicker()
 		marketData, err := s.storage.MarketDataStorage().GetMarketData(ctx, ticker)
 		if err != nil || len(marketData.EOD) == 0 {
-			s.logger.Warn().Str("ticker", ticker).Msg("No market data for snapshot — skipping holding")
+			logger.Warn().Str("ticker", ticker).Msg("No market data for snapshot — skipping holding")
 			continue
 		}
 
 		closePrice, barDate, found := findClosingPriceAsOf(marketData.EOD, asOf)
 		if !found {
-			s.logger.Warn().Str("ticker", ticker).Msg("No EOD bar at or before snapshot date — skipping")
+			logger.Warn().Str("ticker", ticker).Msg("No EOD bar at or before snapshot date — skipping")
 			continue
 		}
 
@@ -167,7 +168,7 @@ func (s *Service) GetPortfolioSnapshot(ctx context.Context, name string, asOf ti
 		snapshot.PriceDate = asOf
 	}
 
-	s.logger.Info().
+	logger.Info().
 		Str("name", name).
 		Int("holdings", len(snapshot.Holdings)).
 		Float64("equityValue", snapshot.EquityHoldingsValue).
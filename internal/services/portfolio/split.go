@@ -0,0 +1,57 @@
+package portfolio
+
+import (
+	"math"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// splitValueDivergence is how far (as a fraction of the smaller value) the
+// trade-derived market value may drift from Navexa's before the EOD series
+// is scanned for an unrecorded split.
+const splitValueDivergence = 0.5
+
+// splitRatioTolerance is how closely a price jump must match the value
+// divergence to be taken as the split that explains it.
+const splitRatioTolerance = 0.25
+
+// valueDivergence returns the ratio of the larger to the smaller value and
+// whether they differ by more than splitValueDivergence. Non-positive values
+// never diverge.
+func valueDivergence(a, b float64) (float64, bool) {
+	if a <= 0 || b <= 0 {
+		return 0, false
+	}
+	ratio := math.Max(a, b) / math.Min(a, b)
+	return ratio, ratio-1 > splitValueDivergence
+}
+
+// findSplitJump scans bars (sorted most recent first) on or after since for a
+// day-over-day move in the raw Close of roughly ratio (either direction) that
+// the AdjClose series does not share. EODHD back-adjusts AdjClose for splits,
+// so a jump in Close alone is a split rather than a price move; when AdjClose
+// is missing the raw jump is accepted. Returns the first post-split bar.
+func findSplitJump(bars []models.EODBar, since time.Time, ratio float64) (models.EODBar, bool) {
+	for i := 0; i+1 < len(bars); i++ {
+		newer, older := bars[i], bars[i+1]
+		if !since.IsZero() && older.Date.Before(since) {
+			break
+		}
+		if newer.Close <= 0 || older.Close <= 0 {
+			continue
+		}
+		jump := math.Max(newer.Close, older.Close) / math.Min(newer.Close, older.Close)
+		if math.Abs(jump/ratio-1) > splitRatioTolerance {
+			continue
+		}
+		if newer.AdjClose > 0 && older.AdjClose > 0 {
+			adjJump := math.Max(newer.AdjClose, older.AdjClose) / math.Min(newer.AdjClose, older.AdjClose)
+			if adjJump-1 > splitValueDivergence {
+				continue // the adjusted series moved too: a real price move
+			}
+		}
+		return newer, true
+	}
+	return models.EODBar{}, false
+}
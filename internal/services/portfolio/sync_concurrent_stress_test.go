@@ -2,16 +2,20 @@ package portfolio
 
 import (
 	"context"
+	"errors"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/interfaces"
+	"github.com/bobmcallan/vire/internal/models"
 )
 
 // TestSyncPortfolio_ConcurrentForce_OnlyOneSyncHappens verifies that when
 // multiple goroutines simultaneously call SyncPortfolio(force=true), the
-// syncMu mutex ensures only the first performs a full Navexa sync. All
+// syncSem slot ensures only the first performs a full Navexa sync. All
 // subsequent goroutines within the cooldown window return the cached result.
 func TestSyncPortfolio_ConcurrentForce_OnlyOneSyncHappens(t *testing.T) {
 	svc, navexa := newSyncCooldownFixture()
@@ -36,7 +40,7 @@ func TestSyncPortfolio_ConcurrentForce_OnlyOneSyncHappens(t *testing.T) {
 		}
 	}
 
-	// syncMu serializes all goroutines. After the first completes and sets
+	// syncSem serializes all goroutines. After the first completes and sets
 	// LastSynced, the remaining 4 will be within the 5-minute cooldown and
 	// return cached — so total API calls must be exactly 1.
 	if navexa.callCount != 1 {
@@ -44,9 +48,128 @@ func TestSyncPortfolio_ConcurrentForce_OnlyOneSyncHappens(t *testing.T) {
 	}
 }
 
+// slowNavexaClient counts GetPortfolios calls and holds each one open long
+// enough for concurrent callers to pile up behind it, unless ctx ends first.
+type slowNavexaClient struct {
+	*countingNavexaClient
+	calls atomic.Int32
+}
+
+func (c *slowNavexaClient) GetPortfolios(ctx context.Context) ([]*models.NavexaPortfolio, error) {
+	c.calls.Add(1)
+	select {
+	case <-time.After(100 * time.Millisecond):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return c.stubNavexaClient.GetPortfolios(ctx)
+}
+
+// TestGetPortfolio_ConcurrentStale_SharesOneSync verifies that concurrent
+// GetPortfolio calls on a stale portfolio join a single in-flight sync:
+// exactly one Navexa fetch, and every caller gets its own portfolio copy.
+func TestGetPortfolio_ConcurrentStale_SharesOneSync(t *testing.T) {
+	svc, counting := newSyncCooldownFixture()
+	navexa := &slowNavexaClient{countingNavexaClient: counting}
+	ctx := common.WithNavexaClient(context.Background(), navexa)
+
+	if _, err := svc.SyncPortfolio(ctx, "SMSF", true); err != nil {
+		t.Fatalf("initial SyncPortfolio failed: %v", err)
+	}
+	existing, err := svc.getPortfolioRecord(ctx, "SMSF")
+	if err != nil {
+		t.Fatalf("getPortfolioRecord failed: %v", err)
+	}
+	existing.LastSynced = time.Now().Add(-2 * common.FreshnessPortfolio)
+	if err := svc.savePortfolioRecord(ctx, existing); err != nil {
+		t.Fatalf("savePortfolioRecord failed: %v", err)
+	}
+	navexa.calls.Store(0)
+
+	const goroutines = 10
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	results := make([]*models.Portfolio, goroutines)
+	errs := make([]error, goroutines)
+
+	wg.Add(goroutines)
+	for i := range goroutines {
+		go func(idx int) {
+			defer wg.Done()
+			<-start
+			results[idx], errs[idx] = svc.GetPortfolio(ctx, "SMSF")
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("goroutine %d returned error: %v", i, err)
+		}
+	}
+	if got := navexa.calls.Load(); got != 1 {
+		t.Errorf("expected exactly 1 Navexa fetch for %d concurrent stale reads, got %d", goroutines, got)
+	}
+
+	seen := make(map[*models.Portfolio]bool, goroutines)
+	for i, p := range results {
+		if p == nil || !common.IsFresh(p.LastSynced, common.FreshnessPortfolio) {
+			t.Fatalf("goroutine %d got a stale or nil portfolio: %+v", i, p)
+		}
+		if seen[p] {
+			t.Errorf("goroutine %d shares a portfolio pointer with another caller", i)
+		}
+		seen[p] = true
+	}
+}
+
+// TestSyncPortfolio_LeaderCancelled_FollowerGetsResult verifies that the
+// shared sync does not run on the first caller's context: when that caller
+// gives up, it gets ErrSyncInProgress while a caller that joined the sync
+// still receives the synced portfolio.
+func TestSyncPortfolio_LeaderCancelled_FollowerGetsResult(t *testing.T) {
+	svc, counting := newSyncCooldownFixture()
+	navexa := &slowNavexaClient{countingNavexaClient: counting}
+	base := common.WithNavexaClient(context.Background(), navexa)
+	leaderCtx, cancel := context.WithCancel(base)
+	defer cancel()
+
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := svc.SyncPortfolio(leaderCtx, "SMSF", true)
+		leaderErr <- err
+	}()
+	time.Sleep(20 * time.Millisecond) // leader is inside the slow Navexa fetch
+
+	followerDone := make(chan struct{})
+	var follower *models.Portfolio
+	var followerErr error
+	go func() {
+		defer close(followerDone)
+		follower, followerErr = svc.SyncPortfolio(base, "SMSF", true)
+	}()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	if err := <-leaderErr; !errors.Is(err, interfaces.ErrSyncInProgress) || !errors.Is(err, context.Canceled) {
+		t.Errorf("leader error = %v, want ErrSyncInProgress wrapping context.Canceled", err)
+	}
+	<-followerDone
+	if followerErr != nil {
+		t.Fatalf("follower failed after the leader was cancelled: %v", followerErr)
+	}
+	if follower == nil || !common.IsFresh(follower.LastSynced, common.FreshnessPortfolio) {
+		t.Errorf("follower got a stale or nil portfolio: %+v", follower)
+	}
+	if got := navexa.calls.Load(); got != 1 {
+		t.Errorf("expected exactly 1 Navexa fetch, got %d", got)
+	}
+}
+
 // TestSyncPortfolio_GetPortfolio_NoDeadlock verifies that interleaved calls to
 // GetPortfolio (which may call SyncPortfolio internally) and SyncPortfolio(force=true)
-// do not deadlock. GetPortfolio does not hold syncMu when calling SyncPortfolio,
+// do not deadlock. GetPortfolio does not hold syncSem when calling SyncPortfolio,
 // so the call graph is safe: no lock held → acquire lock. Not reentrant.
 //
 // Note: this test runs calls sequentially in an alternating pattern to avoid a
@@ -58,7 +181,7 @@ func TestSyncPortfolio_GetPortfolio_NoDeadlock(t *testing.T) {
 	ctx := common.WithNavexaClient(context.Background(), navexa)
 
 	// Alternate GetPortfolio and SyncPortfolio calls sequentially.
-	// Confirms no reentrant lock panic (syncMu is not held by GetPortfolio).
+	// Confirms no reentrant lock panic (syncSem is not held by GetPortfolio).
 	for i := range 6 {
 		var err error
 		if i%2 == 0 {
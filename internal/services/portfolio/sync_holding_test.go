@@ -0,0 +1,174 @@
+package portfolio
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/interfaces"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// tradeFetchRecordingClient records which holdings had their trades fetched.
+type tradeFetchRecordingClient struct {
+	*stubNavexaClient
+	mu      sync.Mutex
+	fetched []string
+}
+
+func (c *tradeFetchRecordingClient) GetHoldingTrades(ctx context.Context, holdingID string) ([]*models.NavexaTrade, error) {
+	c.mu.Lock()
+	c.fetched = append(c.fetched, holdingID)
+	c.mu.Unlock()
+	return c.stubNavexaClient.GetHoldingTrades(ctx, holdingID)
+}
+
+func newSyncHoldingFixture(t *testing.T) (*Service, *tradeFetchRecordingClient, context.Context) {
+	t.Helper()
+	navexa := &tradeFetchRecordingClient{
+		stubNavexaClient: &stubNavexaClient{
+			portfolios: []*models.NavexaPortfolio{
+				{ID: "1", Name: "SMSF", Currency: "AUD", DateCreated: "2020-01-01"},
+			},
+			holdings: []*models.NavexaHolding{
+				{
+					ID: "100", PortfolioID: "1", Ticker: "BHP", Exchange: "AU",
+					Name: "BHP Group", Units: 100, CurrentPrice: 45.00, MarketValue: 4500.00,
+					LastUpdated: time.Now(),
+				},
+				{
+					ID: "200", PortfolioID: "1", Ticker: "CBA", Exchange: "AU",
+					Name: "Commonwealth Bank", Units: 50, CurrentPrice: 120.00, MarketValue: 6000.00,
+					LastUpdated: time.Now(),
+				},
+			},
+			trades: map[string][]*models.NavexaTrade{
+				"100": {{ID: "1", HoldingID: "100", Symbol: "BHP", Type: "buy", Date: "2024-01-10", Units: 100, Price: 40.00}},
+				"200": {{ID: "2", HoldingID: "200", Symbol: "CBA", Type: "buy", Date: "2024-02-10", Units: 50, Price: 100.00}},
+			},
+		},
+	}
+	storage := &stubStorageManager{
+		marketStore:   &stubMarketDataStorage{data: map[string]*models.MarketData{}},
+		userDataStore: newMemUserDataStore(),
+	}
+	svc := NewService(storage, nil, nil, nil, common.NewLogger("error"))
+	ctx := common.WithNavexaClient(context.Background(), navexa)
+
+	if _, err := svc.SyncPortfolio(ctx, "SMSF", true); err != nil {
+		t.Fatalf("initial SyncPortfolio failed: %v", err)
+	}
+	navexa.fetched = nil
+	return svc, navexa, ctx
+}
+
+func findStoredHolding(t *testing.T, p *models.Portfolio, ticker string) models.Holding {
+	t.Helper()
+	for _, h := range p.Holdings {
+		if h.Ticker == ticker {
+			return h
+		}
+	}
+	t.Fatalf("holding %s not in portfolio", ticker)
+	return models.Holding{}
+}
+
+func TestSyncHolding_UpdatesOnlyThatHolding(t *testing.T) {
+	svc, navexa, ctx := newSyncHoldingFixture(t)
+
+	before, err := svc.getPortfolioRecord(ctx, "SMSF")
+	if err != nil {
+		t.Fatalf("load portfolio: %v", err)
+	}
+	cbaBefore := findStoredHolding(t, before, "CBA")
+
+	// BHP gains a buy and a new price; CBA moves in Navexa too, but must
+	// keep its stored values because only BHP is synced.
+	navexa.holdings[0] = &models.NavexaHolding{
+		ID: "100", PortfolioID: "1", Ticker: "BHP", Exchange: "AU",
+		Name: "BHP Group", Units: 120, CurrentPrice: 50.00, MarketValue: 6000.00,
+		LastUpdated: time.Now(),
+	}
+	navexa.trades["100"] = append(navexa.trades["100"],
+		&models.NavexaTrade{ID: "3", HoldingID: "100", Symbol: "BHP", Type: "buy", Date: "2024-06-10", Units: 20, Price: 48.00})
+	navexa.holdings[1].CurrentPrice = 130.00
+	navexa.holdings[1].MarketValue = 6500.00
+
+	holding, err := svc.SyncHolding(ctx, "SMSF", "bhp.au")
+	if err != nil {
+		t.Fatalf("SyncHolding failed: %v", err)
+	}
+	if holding.Ticker != "BHP" || holding.Units != 120 || holding.CurrentPrice != 50.00 {
+		t.Errorf("synced holding = %s units %.0f price %.2f, want BHP 120 @ 50.00", holding.Ticker, holding.Units, holding.CurrentPrice)
+	}
+	if len(holding.Trades) != 2 {
+		t.Errorf("synced holding has %d trades, want 2", len(holding.Trades))
+	}
+	if !reflect.DeepEqual(navexa.fetched, []string{"100"}) {
+		t.Errorf("trades fetched for %v, want only BHP (100)", navexa.fetched)
+	}
+
+	after, err := svc.getPortfolioRecord(ctx, "SMSF")
+	if err != nil {
+		t.Fatalf("reload portfolio: %v", err)
+	}
+	if len(after.Holdings) != 2 || after.Holdings[0].Ticker != "BHP" || after.Holdings[1].Ticker != "CBA" {
+		t.Fatalf("holdings = %+v, want BHP then CBA", after.Holdings)
+	}
+
+	// CBA is untouched apart from its weight, which shifts with the total
+	cbaAfter := findStoredHolding(t, after, "CBA")
+	cbaWeight := cbaAfter.WeightPct
+	if cbaWeight == cbaBefore.WeightPct {
+		t.Errorf("CBA weight %.2f should change with the new total", cbaWeight)
+	}
+	cbaBefore.WeightPct, cbaAfter.WeightPct = 0, 0
+	if !reflect.DeepEqual(cbaBefore, cbaAfter) {
+		t.Errorf("CBA changed:\nbefore %+v\nafter  %+v", cbaBefore, cbaAfter)
+	}
+
+	// Totals: BHP 6000 + CBA 6000 stored; cost 4000+960 + 5000
+	if !approxEqual(after.EquityHoldingsValue, 12000, 0.01) {
+		t.Errorf("EquityHoldingsValue = %.2f, want 12000", after.EquityHoldingsValue)
+	}
+	if !approxEqual(after.EquityHoldingsCost, 9960, 0.01) {
+		t.Errorf("EquityHoldingsCost = %.2f, want 9960", after.EquityHoldingsCost)
+	}
+	if !approxEqual(after.PortfolioValue, 12000, 0.01) {
+		t.Errorf("PortfolioValue = %.2f, want 12000", after.PortfolioValue)
+	}
+	bhpAfter := findStoredHolding(t, after, "BHP")
+	if !approxEqual(bhpAfter.WeightPct, 50, 0.01) || !approxEqual(cbaWeight, 50, 0.01) {
+		t.Errorf("weights BHP %.2f CBA %.2f, want 50 each", bhpAfter.WeightPct, cbaWeight)
+	}
+	if after.TradeHash == before.TradeHash {
+		t.Error("TradeHash should change when the holding's trades change")
+	}
+	if !after.LastSynced.Equal(before.LastSynced) {
+		t.Errorf("LastSynced moved from %v to %v on a single-holding sync", before.LastSynced, after.LastSynced)
+	}
+}
+
+func TestSyncHolding_UnknownTicker(t *testing.T) {
+	svc, navexa, ctx := newSyncHoldingFixture(t)
+
+	_, err := svc.SyncHolding(ctx, "SMSF", "NAB")
+	if !errors.Is(err, interfaces.ErrHoldingNotFound) {
+		t.Fatalf("expected ErrHoldingNotFound, got %v", err)
+	}
+	if len(navexa.fetched) != 0 {
+		t.Errorf("trades fetched for %v on an unknown ticker", navexa.fetched)
+	}
+}
+
+func TestSyncHolding_UnknownPortfolio(t *testing.T) {
+	svc, _, ctx := newSyncHoldingFixture(t)
+
+	if _, err := svc.SyncHolding(ctx, "Missing", "BHP"); err == nil {
+		t.Fatal("expected error for a portfolio that does not exist")
+	}
+}
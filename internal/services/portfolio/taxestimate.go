@@ -0,0 +1,70 @@
+package portfolio
+
+import (
+	"context"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// cgtDiscountRate returns the CGT discount for the portfolio's account type:
+// one half for a trading (individual) account, otherwise one third.
+func (s *Service) cgtDiscountRate(ctx context.Context, portfolioName string) float64 {
+	if strat, err := s.getStrategyRecord(ctx, portfolioName); err == nil && strat.AccountType == models.AccountTypeTrading {
+		return cgtDiscountIndividual
+	}
+	return cgtDiscountSMSF
+}
+
+// populateTaxEstimates sets the estimated tax and after-tax value of selling
+// each open holding today. Nothing is set when no marginal rate is configured.
+func (s *Service) populateTaxEstimates(ctx context.Context, portfolio *models.Portfolio) {
+	if s.marginalTaxRate <= 0 || portfolio == nil {
+		return
+	}
+	lifo := s.costBasisMethod(ctx, portfolio.Name) == models.CostBasisLIFO
+	discountRate := s.cgtDiscountRate(ctx, portfolio.Name)
+	now := time.Now()
+	for i := range portfolio.Holdings {
+		estimateHoldingTax(&portfolio.Holdings[i], lifo, now, s.minHoldDays, discountRate, s.marginalTaxRate, s.feeModel)
+	}
+}
+
+// estimateHoldingTax estimates the tax on selling every open unit of h at its
+// current price on now, net of brokerage. Open lots are matched FIFO (LIFO when
+// lifo is set). Each lot held at least minHoldDays is discountable; losses
+// offset gains as in the CGT report, and the net gain is taxed at
+// marginalRatePct. Holdings converted from a foreign currency are skipped,
+// since their lot costs would need historical exchange rates.
+func estimateHoldingTax(h *models.Holding, lifo bool, now time.Time, minHoldDays int, discountRate, marginalRatePct float64, fees models.FeeModel) {
+	if h.Units <= 0 || h.CurrentPrice <= 0 || len(h.Trades) == 0 || h.OriginalCurrency != "" {
+		return
+	}
+	lots := matchLots(h.Trades, lifo).lots
+	var held float64
+	for _, l := range lots {
+		held += l.units
+	}
+	if held <= 0 {
+		return
+	}
+
+	value := held * h.CurrentPrice
+	fee := fees.Fee(value)
+	report := &models.CGTReport{DiscountRate: discountRate}
+	for _, l := range lots {
+		report.Disposals = append(report.Disposals, cgtDisposal(h.Ticker, lotDisposal{
+			acquired: l.acquired,
+			disposed: now,
+			units:    l.units,
+			proceeds: (value - fee) * l.units / held,
+			costBase: l.units * l.unitCost,
+		}, minHoldDays))
+	}
+	summariseCGT(report)
+
+	if report.NetCapitalGain > 0 {
+		h.EstimatedTax = report.NetCapitalGain * marginalRatePct / 100
+	}
+	h.AfterTaxValue = value - fee - h.EstimatedTax
+}
@@ -0,0 +1,72 @@
+package portfolio
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+func TestEstimateHoldingTax_DiscountEligibleGain(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	h := models.Holding{
+		Ticker:       "BHP",
+		Units:        100,
+		CurrentPrice: 20,
+		Trades: []*models.NavexaTrade{
+			{Type: "buy", Date: "2023-01-10", Units: 100, Price: 10}, // held > 365 days
+		},
+	}
+
+	// Gain 1000, halved by the individual discount, taxed at 30%
+	estimateHoldingTax(&h, false, now, 365, cgtDiscountIndividual, 30, models.FeeModel{})
+
+	if !approxEqual(h.EstimatedTax, 150, 0.001) {
+		t.Errorf("EstimatedTax = %.2f, want 150 (1000 gain x 0.5 discount x 30%%)", h.EstimatedTax)
+	}
+	if !approxEqual(h.AfterTaxValue, 1850, 0.001) {
+		t.Errorf("AfterTaxValue = %.2f, want 1850", h.AfterTaxValue)
+	}
+}
+
+func TestEstimateHoldingTax_MixedLotsAndFees(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	h := models.Holding{
+		Ticker:       "WES",
+		Units:        100,
+		CurrentPrice: 20,
+		Trades: []*models.NavexaTrade{
+			{Type: "buy", Date: "2023-01-10", Units: 50, Price: 10}, // discountable
+			{Type: "buy", Date: "2025-03-01", Units: 50, Price: 24}, // short-held loss
+		},
+	}
+	fees := models.FeeModel{Type: models.FeeModelFlat, Flat: 20}
+
+	// Proceeds 2000 - 20 = 1980, 990 per lot: gain 490 (long) and loss 210 (short).
+	// The loss offsets the discountable gain first: (490 - 210) x 2/3 = 186.67, at 15%.
+	estimateHoldingTax(&h, false, now, 365, cgtDiscountSMSF, 15, fees)
+
+	if !approxEqual(h.EstimatedTax, 28, 0.001) {
+		t.Errorf("EstimatedTax = %.2f, want 28", h.EstimatedTax)
+	}
+	if !approxEqual(h.AfterTaxValue, 1952, 0.001) {
+		t.Errorf("AfterTaxValue = %.2f, want 1952 (2000 - 20 fee - 28 tax)", h.AfterTaxValue)
+	}
+}
+
+func TestEstimateHoldingTax_NoTaxOnLossOrForeign(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	trades := []*models.NavexaTrade{{Type: "buy", Date: "2023-01-10", Units: 10, Price: 50}}
+
+	loss := models.Holding{Ticker: "CBA", Units: 10, CurrentPrice: 40, Trades: trades}
+	estimateHoldingTax(&loss, false, now, 365, cgtDiscountSMSF, 30, models.FeeModel{})
+	if loss.EstimatedTax != 0 || !approxEqual(loss.AfterTaxValue, 400, 0.001) {
+		t.Errorf("loss: tax %.2f after-tax %.2f, want 0 and 400", loss.EstimatedTax, loss.AfterTaxValue)
+	}
+
+	foreign := models.Holding{Ticker: "AAPL", Units: 10, CurrentPrice: 80, OriginalCurrency: "USD", Trades: trades}
+	estimateHoldingTax(&foreign, false, now, 365, cgtDiscountSMSF, 30, models.FeeModel{})
+	if foreign.EstimatedTax != 0 || foreign.AfterTaxValue != 0 {
+		t.Errorf("foreign: tax %.2f after-tax %.2f, want no estimate", foreign.EstimatedTax, foreign.AfterTaxValue)
+	}
+}
@@ -2,6 +2,7 @@ package portfolio
 
 import (
 	"context"
+	"io"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -46,6 +47,14 @@ func (m *rebuildCashflowSvc) SetTransactions(_ context.Context, _ string, _ []mo
 func (m *rebuildCashflowSvc) ClearLedger(_ context.Context, _ string) (*models.CashFlowLedger, error) {
 	return nil, nil
 }
+
+func (m *rebuildCashflowSvc) SetBrokerBalance(_ context.Context, _ string, _ models.BrokerBalance) (*models.CashFlowLedger, error) {
+	return nil, nil
+}
+
+func (m *rebuildCashflowSvc) Reconcile(_ context.Context, _ string) (*models.CashReconciliation, error) {
+	return nil, nil
+}
 func (m *rebuildCashflowSvc) CalculatePerformance(_ context.Context, _ string) (*models.CapitalPerformance, error) {
 	return nil, nil
 }
@@ -108,7 +117,13 @@ func (b *rebuildStorageManager) PurgeDerivedData(_ context.Context) (map[string]
 	return nil, nil
 }
 func (b *rebuildStorageManager) PurgeReports(_ context.Context) (int, error) { return 0, nil }
-func (b *rebuildStorageManager) Close() error                                { return nil }
+func (b *rebuildStorageManager) Backup(_ context.Context, _ io.Writer) (*models.BackupManifest, error) {
+	return nil, nil
+}
+func (b *rebuildStorageManager) Restore(_ context.Context, _ io.Reader) (*models.BackupManifest, error) {
+	return nil, nil
+}
+func (b *rebuildStorageManager) Close() error { return nil }
 
 // --- Tests ---
 
@@ -3,6 +3,7 @@ package portfolio
 import (
 	"context"
 	"fmt"
+	"io"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -116,7 +117,13 @@ func (b *backfillStorageManager) PurgeDerivedData(_ context.Context) (map[string
 	return nil, nil
 }
 func (b *backfillStorageManager) PurgeReports(_ context.Context) (int, error) { return 0, nil }
-func (b *backfillStorageManager) Close() error                                { return nil }
+func (b *backfillStorageManager) Backup(_ context.Context, _ io.Writer) (*models.BackupManifest, error) {
+	return nil, nil
+}
+func (b *backfillStorageManager) Restore(_ context.Context, _ io.Reader) (*models.BackupManifest, error) {
+	return nil, nil
+}
+func (b *backfillStorageManager) Close() error { return nil }
 
 func TestBackfillTimelineIfEmpty_SkipsWhenNoTimelineStore(t *testing.T) {
 	svc := &Service{
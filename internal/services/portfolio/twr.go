@@ -0,0 +1,97 @@
+package portfolio
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/interfaces"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// maxSnapshotGapDays is the longest gap between daily portfolio values that
+// TimeWeightedReturn accepts. Longer gaps mean snapshots are missing and a
+// sub-period's start or end value would be guessed.
+const maxSnapshotGapDays = 5
+
+// TimeWeightedReturn returns the portfolio's time-weighted return, as a
+// percentage, between from and to (YYYY-MM-DD; empty = inception / today).
+// Contributions and withdrawals in the cash-flow ledger split the range into
+// sub-periods; each sub-period's return comes from the daily PortfolioValue
+// series and the returns are chained, so external flows don't distort the
+// result the way the dollar-weighted net return does.
+func (s *Service) TimeWeightedReturn(ctx context.Context, portfolioName, from, to string) (float64, error) {
+	var opts interfaces.GrowthOptions
+	var err error
+	if from != "" {
+		if opts.From, err = time.Parse("2006-01-02", from); err != nil {
+			return 0, fmt.Errorf("invalid from date %q (want YYYY-MM-DD)", from)
+		}
+	}
+	if to != "" {
+		if opts.To, err = time.Parse("2006-01-02", to); err != nil {
+			return 0, fmt.Errorf("invalid to date %q (want YYYY-MM-DD)", to)
+		}
+	}
+	if !opts.From.IsZero() && !opts.To.IsZero() && opts.To.Before(opts.From) {
+		return 0, fmt.Errorf("to date %s is before from date %s", to, from)
+	}
+
+	var flows []models.CashTransaction
+	if s.cashflowSvc != nil {
+		if ledger, err := s.cashflowSvc.GetLedger(ctx, portfolioName); err == nil && ledger != nil {
+			flows = ledger.Transactions
+			opts.Transactions = ledger.Transactions
+		}
+	}
+
+	points, err := s.GetDailyGrowth(ctx, portfolioName, opts)
+	if err != nil {
+		return 0, err
+	}
+	return chainTimeWeightedReturn(points, flows)
+}
+
+// chainTimeWeightedReturn links sub-period returns over points (ascending by
+// date). Only contribution-category transactions are external flows;
+// dividends, fees and transfers are part of the return. A flow dated between
+// two points belongs to the later one, whose value already includes it, so
+// that sub-period ends at (value - flow) and the next starts at value. Flows
+// on or before the first point are already in the starting value.
+func chainTimeWeightedReturn(points []models.GrowthDataPoint, flows []models.CashTransaction) (float64, error) {
+	if len(points) < 2 {
+		return 0, fmt.Errorf("need at least 2 daily portfolio values to compute a time-weighted return, have %d", len(points))
+	}
+
+	growth := 1.0
+	start := points[0].PortfolioValue
+	for i := 1; i < len(points); i++ {
+		prev, cur := points[i-1], points[i]
+		if gap := cur.Date.Sub(prev.Date); gap > maxSnapshotGapDays*24*time.Hour {
+			return 0, fmt.Errorf("missing portfolio value snapshots between %s and %s; rebuild the timeline and retry",
+				prev.Date.Format("2006-01-02"), cur.Date.Format("2006-01-02"))
+		}
+
+		var flow float64
+		for _, tx := range flows {
+			if tx.Category == models.CashCatContribution && tx.Date.After(prev.Date) && !tx.Date.After(cur.Date) {
+				flow += tx.Amount
+			}
+		}
+		if flow == 0 {
+			continue
+		}
+
+		// Close the sub-period just before the flow. With nothing invested
+		// (e.g. before the first contribution) there is no return to chain.
+		if start > 0 {
+			growth *= (cur.PortfolioValue - flow) / start
+		}
+		start = cur.PortfolioValue
+	}
+
+	if end := points[len(points)-1].PortfolioValue; start > 0 {
+		growth *= end / start
+	}
+	return (growth - 1) * 100, nil
+}
@@ -0,0 +1,94 @@
+package portfolio
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// valueSeries builds consecutive daily points from 2025-01-01 with the given values
+func valueSeries(values ...float64) []models.GrowthDataPoint {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	points := make([]models.GrowthDataPoint, len(values))
+	for i, v := range values {
+		points[i] = models.GrowthDataPoint{Date: start.AddDate(0, 0, i), PortfolioValue: v}
+	}
+	return points
+}
+
+func contribution(day int, amount float64) models.CashTransaction {
+	return models.CashTransaction{
+		Category: models.CashCatContribution,
+		Date:     time.Date(2025, 1, 1+day, 0, 0, 0, 0, time.UTC),
+		Amount:   amount,
+	}
+}
+
+func TestChainTWR_SingleSubPeriodEqualsSimpleReturn(t *testing.T) {
+	got, err := chainTimeWeightedReturn(valueSeries(10000, 10200, 10500, 11000), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if math.Abs(got-10) > 1e-9 {
+		t.Errorf("TWR = %.4f%%, want 10%% (simple return)", got)
+	}
+}
+
+func TestChainTWR_ContributionMidPeriodIsNeutralised(t *testing.T) {
+	// +10% on 10k, then a 10k deposit, then +10% on 21k. Dollar-weighted
+	// return is distorted by the deposit; TWR is 1.1 × 1.1 − 1 = 21%.
+	points := valueSeries(10000, 11000, 21000, 23100)
+	got, err := chainTimeWeightedReturn(points, []models.CashTransaction{contribution(2, 10000)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if math.Abs(got-21) > 1e-9 {
+		t.Errorf("TWR = %.4f%%, want 21%%", got)
+	}
+}
+
+func TestChainTWR_ContributionOnFirstDayIsInStartingValue(t *testing.T) {
+	// The first point already includes the day-0 deposit, so it must not be
+	// subtracted again.
+	got, err := chainTimeWeightedReturn(valueSeries(5000, 5250), []models.CashTransaction{contribution(0, 5000)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if math.Abs(got-5) > 1e-9 {
+		t.Errorf("TWR = %.4f%%, want 5%%", got)
+	}
+}
+
+func TestChainTWR_WithdrawalAndNonContributionFlows(t *testing.T) {
+	// Withdraw 5k after +10%; the dividend is part of the return, not a flow.
+	points := valueSeries(10000, 11000, 6600)
+	flows := []models.CashTransaction{
+		contribution(2, -5000),
+		{Category: models.CashCatDividend, Date: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), Amount: 100},
+	}
+	got, err := chainTimeWeightedReturn(points, flows)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := (1.1*(11600.0/11000.0) - 1) * 100 // 6600 + 5000 = 11600 before the withdrawal
+	if math.Abs(got-want) > 1e-9 {
+		t.Errorf("TWR = %.4f%%, want %.4f%%", got, want)
+	}
+}
+
+func TestChainTWR_MissingSnapshotsError(t *testing.T) {
+	points := valueSeries(10000, 10100)
+	points = append(points, models.GrowthDataPoint{Date: points[1].Date.AddDate(0, 0, 10), PortfolioValue: 10500})
+
+	_, err := chainTimeWeightedReturn(points, nil)
+	if err == nil || !strings.Contains(err.Error(), "missing portfolio value snapshots between 2025-01-02 and 2025-01-12") {
+		t.Errorf("err = %v, want a missing-snapshots error naming the gap", err)
+	}
+
+	if _, err := chainTimeWeightedReturn(valueSeries(10000), nil); err == nil {
+		t.Error("expected an error with fewer than 2 values")
+	}
+}
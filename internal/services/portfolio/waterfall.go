@@ -0,0 +1,78 @@
+package portfolio
+
+import (
+	"time"
+
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// buildWaterfall reconciles the first point of the value series to the
+// current portfolio value, with every component measured over the same
+// window: ledger contributions, dividends and other cash flows dated after
+// the start; realized gains booked by sells after the start (replayed under
+// method); and the change in unrealized return from the start point's
+// value less cost to the holdings' current unrealized return. Without a
+// value series the window starts from zero at inception and dividends fall
+// back to dividend trades when the ledger records none. Unexplained is what
+// the components leave of the ending value.
+func buildWaterfall(p *models.Portfolio, growth []models.GrowthDataPoint, ledger *models.CashFlowLedger, method models.CostBasisMethod) *models.ValueWaterfall {
+	w := &models.ValueWaterfall{
+		UnrealizedChange: p.EquityHoldingsUnrealized,
+		EndValue:         p.PortfolioValue,
+	}
+	if len(growth) > 0 {
+		w.StartDate = growth[0].Date.Truncate(24 * time.Hour)
+		w.StartValue = growth[0].PortfolioValue
+		w.UnrealizedChange -= growth[0].EquityHoldingsValue - growth[0].EquityHoldingsCost
+	}
+	inWindow := func(d time.Time) bool {
+		return w.StartDate.IsZero() || d.Truncate(24*time.Hour).After(w.StartDate)
+	}
+
+	if ledger != nil {
+		for _, tx := range ledger.Transactions {
+			if !inWindow(tx.Date) {
+				continue
+			}
+			switch tx.Category {
+			case models.CashCatContribution:
+				w.Contributions += tx.SignedAmount()
+			case models.CashCatDividend:
+				w.Dividends += tx.SignedAmount()
+			case models.CashCatFee, models.CashCatOther:
+				w.Other += tx.SignedAmount()
+			}
+		}
+	}
+	if w.Dividends == 0 && w.StartDate.IsZero() {
+		w.Dividends = p.PortfolioDividendIncome
+	}
+
+	// Holdings that share a ticker carry the same merged trade list, so
+	// replay each ticker once
+	seen := make(map[string]bool)
+	for i := range p.Holdings {
+		h := &p.Holdings[i]
+		ticker := h.EODHDTicker()
+		if seen[ticker] || len(h.Trades) == 0 {
+			continue
+		}
+		seen[ticker] = true
+
+		dated := make([]*models.NavexaTrade, 0, len(h.Trades))
+		for _, t := range h.Trades {
+			if !parseTradeDate(t.Date).IsZero() {
+				dated = append(dated, t)
+			}
+		}
+		fxDiv := portfolioFXDiv(p, h)
+		for _, c := range realizedByDate(dated, method) {
+			if inWindow(c.date) {
+				w.RealizedGains += c.realized / fxDiv
+			}
+		}
+	}
+
+	w.Unexplained = w.EndValue - w.Total()
+	return w
+}
@@ -0,0 +1,89 @@
+package portfolio
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+func TestBuildWaterfall_ComponentsOverTheWindow(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC) }
+
+	// Worked by hand, window 2025-01-10 to now:
+	//   start 1,500 = BHP 100 units worth 1,200 on a 1,000 cost + 300 cash
+	//   + 500 contribution (the 2,000 on the 1st is in the start)
+	//   + 200 realized: 50 BHP sold at 14 on a 10 cost (CBA's +100 on the
+	//     8th is before the start)
+	//   + 100 unrealized: 50 BHP at 16 on a 500 cost is +300, from +200
+	//   + 30 dividend
+	//   - 5 other: a 10 fee and 5 interest
+	//   = 2,325 = 50 BHP at 16 (800) + cash 300 + 500 + 700 + 30 - 10 + 5
+	ledger := &models.CashFlowLedger{Transactions: []models.CashTransaction{
+		{Category: models.CashCatContribution, Date: day(1), Amount: 2000},
+		{Category: models.CashCatContribution, Date: day(12), Amount: 500},
+		{Category: models.CashCatDividend, Date: day(14), Amount: 30},
+		{Category: models.CashCatFee, Date: day(16), Amount: -10},
+		{Category: models.CashCatOther, Date: day(17), Amount: 5},
+	}}
+	growth := []models.GrowthDataPoint{
+		{Date: day(10), PortfolioValue: 1500, EquityHoldingsValue: 1200, EquityHoldingsCost: 1000},
+		{Date: day(20), PortfolioValue: 2325},
+	}
+	p := &models.Portfolio{
+		PortfolioValue:           2325,
+		EquityHoldingsRealized:   300, // lifetime: CBA's 100 and BHP's 200
+		EquityHoldingsUnrealized: 300,
+		Holdings: []models.Holding{
+			{Ticker: "BHP", Exchange: "AU", Units: 50, Trades: []*models.NavexaTrade{
+				{Type: "buy", Date: "2025-01-05", Units: 100, Price: 10},
+				{Type: "sell", Date: "2025-01-15", Units: 50, Price: 14},
+			}},
+			{Ticker: "CBA", Exchange: "AU", Trades: []*models.NavexaTrade{
+				{Type: "buy", Date: "2025-01-02", Units: 10, Price: 100},
+				{Type: "sell", Date: "2025-01-08", Units: 10, Price: 110},
+			}},
+		},
+	}
+
+	w := buildWaterfall(p, growth, ledger, models.CostBasisAverage)
+
+	if !w.StartDate.Equal(day(10)) || w.StartValue != 1500 {
+		t.Errorf("start = %s %.2f, want 2025-01-10 1500", w.StartDate.Format("2006-01-02"), w.StartValue)
+	}
+	for name, c := range map[string]struct{ got, want float64 }{
+		"Contributions":    {w.Contributions, 500},
+		"RealizedGains":    {w.RealizedGains, 200},
+		"UnrealizedChange": {w.UnrealizedChange, 100},
+		"Dividends":        {w.Dividends, 30},
+		"Other":            {w.Other, -5},
+		"Unexplained":      {w.Unexplained, 0},
+	} {
+		if !approxEqual(c.got, c.want, 0.001) {
+			t.Errorf("%s = %.2f, want %.2f", name, c.got, c.want)
+		}
+	}
+}
+
+func TestBuildWaterfall_NoValueSeries(t *testing.T) {
+	ledger := &models.CashFlowLedger{Transactions: []models.CashTransaction{
+		{Category: models.CashCatContribution, Date: time.Now(), Amount: 2000},
+	}}
+	p := &models.Portfolio{
+		PortfolioValue:           2350,
+		EquityHoldingsUnrealized: 300,
+		PortfolioDividendIncome:  50,
+	}
+
+	w := buildWaterfall(p, nil, ledger, models.CostBasisAverage)
+
+	if w.StartValue != 0 || w.Contributions != 2000 || w.UnrealizedChange != 300 {
+		t.Errorf("start/contributions/unrealized = %.2f/%.2f/%.2f, want 0/2000/300", w.StartValue, w.Contributions, w.UnrealizedChange)
+	}
+	if w.Dividends != 50 {
+		t.Errorf("Dividends = %.2f, want 50 from dividend trades", w.Dividends)
+	}
+	if !approxEqual(w.Total()+w.Unexplained, w.EndValue, 0.001) || !approxEqual(w.Unexplained, 0, 0.001) {
+		t.Errorf("Total = %.2f Unexplained = %.2f, want %.2f and 0", w.Total(), w.Unexplained, w.EndValue)
+	}
+}
@@ -54,6 +54,7 @@ func NewService(eodhd interfaces.EODHDClient, asx interfaces.ASXClient, storage
 // GetRealTimeQuote retrieves a live quote, falling back to ASX Markit Digital
 // when the EODHD quote is stale for an ASX-listed ticker during market hours.
 func (s *Service) GetRealTimeQuote(ctx context.Context, ticker string) (*models.RealTimeQuote, error) {
+	logger := s.logger.WithRequestID(ctx)
 	quote, eodhdErr := s.eodhd.GetRealTimeQuote(ctx, ticker)
 	if eodhdErr == nil && quote != nil {
 		quote.Source = "eodhd"
@@ -84,14 +85,14 @@ func (s *Service) GetRealTimeQuote(ctx context.Context, ticker string) (*models.
 	}
 
 	// Try ASX fallback
-	s.logger.Info().
+	logger.Info().
 		Str("ticker", ticker).
 		Bool("eodhd_failed", eodhdErr != nil).
 		Msg("Attempting ASX Markit fallback for stale quote")
 
 	asxQuote, asxErr := s.asx.GetRealTimeQuote(ctx, ticker)
 	if asxErr != nil {
-		s.logger.Warn().Err(asxErr).Str("ticker", ticker).Msg("ASX Markit fallback failed")
+		logger.Warn().Err(asxErr).Str("ticker", ticker).Msg("ASX Markit fallback failed")
 		// Return the stale EODHD quote if we have one, otherwise propagate the original error
 		if eodhdErr != nil {
 			return nil, eodhdErr
@@ -100,7 +101,7 @@ func (s *Service) GetRealTimeQuote(ctx context.Context, ticker string) (*models.
 		return quote, nil
 	}
 
-	s.logger.Info().
+	logger.Info().
 		Str("ticker", ticker).
 		Str("source", "asx").
 		Float64("price", asxQuote.Close).
@@ -3,6 +3,7 @@ package quote
 import (
 	"context"
 	"errors"
+	"io"
 	"testing"
 	"time"
 
@@ -27,6 +28,9 @@ func (m *mockEODHDClient) GetEOD(_ context.Context, _ string, _ ...interfaces.EO
 func (m *mockEODHDClient) GetBulkRealTimeQuotes(_ context.Context, _ []string) (map[string]*models.RealTimeQuote, error) {
 	return nil, nil
 }
+func (m *mockEODHDClient) GetRealTimeQuotesBatch(_ context.Context, _ []string) (map[string]*models.RealTimeQuote, error) {
+	return nil, nil
+}
 func (m *mockEODHDClient) GetBulkEOD(_ context.Context, _ string, _ []string) (map[string]models.EODBar, error) {
 	return nil, nil
 }
@@ -488,6 +492,12 @@ func (m *mockStorageManager) PurgeDerivedData(_ context.Context) (map[string]int
 	return nil, nil
 }
 func (m *mockStorageManager) PurgeReports(_ context.Context) (int, error) { return 0, nil }
+func (m *mockStorageManager) Backup(_ context.Context, _ io.Writer) (*models.BackupManifest, error) {
+	return nil, nil
+}
+func (m *mockStorageManager) Restore(_ context.Context, _ io.Reader) (*models.BackupManifest, error) {
+	return nil, nil
+}
 
 func TestPopulateHistoricalFields(t *testing.T) {
 	now := time.Now()
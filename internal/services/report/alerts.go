@@ -0,0 +1,122 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// alertSeverityOrder lists digest sections, most urgent first. Alerts with
+// any other severity are listed last under their own heading.
+var alertSeverityOrder = []string{"critical", "high", "medium", "low"}
+
+// alertTypeRank orders alerts within a severity section: strategy rule
+// breaches first, then risk, then technical signals, then news.
+func alertTypeRank(t models.AlertType) int {
+	switch t {
+	case models.AlertTypeStrategy:
+		return 0
+	case models.AlertTypeRisk:
+		return 1
+	case models.AlertTypeSignal, models.AlertTypePrice, models.AlertTypeVolume:
+		return 2
+	case models.AlertTypeNews:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// FormatAlertsMarkdown renders the portfolio's active, unacknowledged alerts
+// as a compact Markdown digest for pasting into Slack or Discord. Alerts come
+// from the latest review; run a review first to refresh them.
+func (s *Service) FormatAlertsMarkdown(ctx context.Context, portfolioName string) (string, error) {
+	states, err := s.portfolio.ListActiveAlerts(ctx, portfolioName)
+	if err != nil {
+		return "", fmt.Errorf("list active alerts: %w", err)
+	}
+	return formatAlertDigest(portfolioName, states), nil
+}
+
+// formatAlertDigest groups unacknowledged alerts by severity and lists each
+// as ticker, signal and message. Acknowledged alerts are only counted.
+func formatAlertDigest(portfolioName string, states []models.AlertState) string {
+	bySeverity := make(map[string][]models.AlertState)
+	active, acknowledged := 0, 0
+	for _, st := range states {
+		if st.Acknowledged {
+			acknowledged++
+			continue
+		}
+		sev := strings.ToLower(strings.TrimSpace(st.Severity))
+		bySeverity[sev] = append(bySeverity[sev], st)
+		active++
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("**%s alerts**", portfolioName))
+	if active == 0 {
+		sb.WriteString("\n:white_check_mark: All clear: no active alerts.")
+		if acknowledged > 0 {
+			sb.WriteString(fmt.Sprintf(" (%d acknowledged)", acknowledged))
+		}
+		sb.WriteString("\n")
+		return sb.String()
+	}
+	sb.WriteString(fmt.Sprintf(" (%d active", active))
+	if acknowledged > 0 {
+		sb.WriteString(fmt.Sprintf(", %d acknowledged", acknowledged))
+	}
+	sb.WriteString(")\n")
+
+	severities := append([]string(nil), alertSeverityOrder...)
+	var other []string
+	for sev := range bySeverity {
+		known := false
+		for _, k := range alertSeverityOrder {
+			if sev == k {
+				known = true
+				break
+			}
+		}
+		if !known {
+			other = append(other, sev)
+		}
+	}
+	sort.Strings(other)
+	severities = append(severities, other...)
+
+	for _, sev := range severities {
+		group := bySeverity[sev]
+		if len(group) == 0 {
+			continue
+		}
+		sort.SliceStable(group, func(i, j int) bool {
+			ri, rj := alertTypeRank(group[i].Type), alertTypeRank(group[j].Type)
+			if ri != rj {
+				return ri < rj
+			}
+			if group[i].Ticker != group[j].Ticker {
+				return group[i].Ticker < group[j].Ticker
+			}
+			return group[i].Signal < group[j].Signal
+		})
+
+		heading := sev
+		if heading == "" {
+			heading = "unrated"
+		}
+		sb.WriteString(fmt.Sprintf("\n**%s** (%d)\n", strings.ToUpper(heading[:1])+heading[1:], len(group)))
+		for _, st := range group {
+			ticker := st.Ticker
+			if ticker == "" {
+				ticker = "Portfolio"
+			}
+			sb.WriteString(fmt.Sprintf("- **%s** `%s`: %s\n", ticker, st.Signal, st.Message))
+		}
+	}
+	return sb.String()
+}
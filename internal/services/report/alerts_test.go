@@ -0,0 +1,99 @@
+package report
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+func newAlertDigestService(alerts []models.AlertState) *Service {
+	return NewService(&mockPortfolioService{activeAlerts: alerts}, nil, nil, nil, common.NewLogger("error"))
+}
+
+func TestFormatAlertsMarkdown_GroupsBySeverityThenType(t *testing.T) {
+	svc := newAlertDigestService([]models.AlertState{
+		{Ticker: "CBA", Signal: "rsi_oversold", Type: models.AlertTypeSignal, Severity: "medium", Message: "CBA RSI is oversold"},
+		{Ticker: "BHP", Signal: "rsi_overbought", Type: models.AlertTypeSignal, Severity: "high", Message: "BHP RSI is overbought"},
+		{Ticker: "WES", Signal: "position_weight", Type: models.AlertTypeStrategy, Severity: "high", Message: "WES exceeds max position weight"},
+		{Ticker: "NAB", Signal: "volume_spike", Type: models.AlertTypeVolume, Severity: "low", Message: "NAB volume spike"},
+		{Signal: "sector_limit", Type: models.AlertTypeStrategy, Severity: "medium", Message: "Financials over sector limit"},
+	})
+
+	md, err := svc.FormatAlertsMarkdown(context.Background(), "SMSF")
+	if err != nil {
+		t.Fatalf("FormatAlertsMarkdown: %v", err)
+	}
+
+	// Severity sections high -> medium -> low; within each, strategy before technical.
+	order := []string{
+		"**High** (2)",
+		"**WES** `position_weight`",
+		"**BHP** `rsi_overbought`",
+		"**Medium** (2)",
+		"**Portfolio** `sector_limit`",
+		"**CBA** `rsi_oversold`",
+		"**Low** (1)",
+		"**NAB** `volume_spike`",
+	}
+	last := -1
+	for _, want := range order {
+		idx := strings.Index(md, want)
+		if idx < 0 {
+			t.Fatalf("digest missing %q:\n%s", want, md)
+		}
+		if idx < last {
+			t.Errorf("%q is out of order:\n%s", want, md)
+		}
+		last = idx
+	}
+	if !strings.Contains(md, "WES exceeds max position weight") {
+		t.Errorf("digest missing alert message:\n%s", md)
+	}
+	if !strings.Contains(md, "(5 active)") {
+		t.Errorf("digest header missing active count:\n%s", md)
+	}
+}
+
+func TestFormatAlertsMarkdown_OmitsAcknowledged(t *testing.T) {
+	svc := newAlertDigestService([]models.AlertState{
+		{Ticker: "BHP", Signal: "rsi_overbought", Type: models.AlertTypeSignal, Severity: "high", Message: "BHP RSI is overbought", Acknowledged: true},
+		{Ticker: "CBA", Signal: "rsi_oversold", Type: models.AlertTypeSignal, Severity: "medium", Message: "CBA RSI is oversold"},
+	})
+
+	md, err := svc.FormatAlertsMarkdown(context.Background(), "SMSF")
+	if err != nil {
+		t.Fatalf("FormatAlertsMarkdown: %v", err)
+	}
+	if strings.Contains(md, "BHP") || strings.Contains(md, "**High**") {
+		t.Errorf("acknowledged alert appears in digest:\n%s", md)
+	}
+	if !strings.Contains(md, "**CBA** `rsi_oversold`") {
+		t.Errorf("unacknowledged alert missing:\n%s", md)
+	}
+	if !strings.Contains(md, "(1 active, 1 acknowledged)") {
+		t.Errorf("digest header = %q", strings.SplitN(md, "\n", 2)[0])
+	}
+}
+
+func TestFormatAlertsMarkdown_AllClear(t *testing.T) {
+	for name, alerts := range map[string][]models.AlertState{
+		"no alerts":        {},
+		"all acknowledged": {{Ticker: "BHP", Signal: "rsi_overbought", Severity: "high", Acknowledged: true}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			md, err := newAlertDigestService(alerts).FormatAlertsMarkdown(context.Background(), "SMSF")
+			if err != nil {
+				t.Fatalf("FormatAlertsMarkdown: %v", err)
+			}
+			if !strings.Contains(md, "All clear") {
+				t.Errorf("zero-alert digest = %q, want an all-clear message", md)
+			}
+			if strings.Contains(md, "**High**") {
+				t.Errorf("zero-alert digest has a severity section: %q", md)
+			}
+		})
+	}
+}
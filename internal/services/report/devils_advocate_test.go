@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"strings"
 	"sync/atomic"
 	"testing"
@@ -61,6 +62,9 @@ func (m *mockMarketService) CollectNewsIntelligence(_ context.Context, _ string,
 }
 func (m *mockMarketService) CollectBulkEOD(_ context.Context, _ string, _ bool) error { return nil }
 func (m *mockMarketService) CollectLivePrices(_ context.Context, _ string) error      { return nil }
+func (m *mockMarketService) CompactEOD(_ context.Context, _ string, _ time.Duration) (int, error) {
+	return 0, nil
+}
 func (m *mockMarketService) GetStockData(_ context.Context, _ string, _ interfaces.StockDataInclude) (*models.StockData, error) {
 	return nil, fmt.Errorf("not implemented")
 }
@@ -70,6 +74,9 @@ func (m *mockMarketService) FindSnipeBuys(_ context.Context, _ interfaces.SnipeO
 func (m *mockMarketService) ScreenStocks(_ context.Context, _ interfaces.ScreenOptions) ([]*models.ScreenCandidate, error) {
 	return nil, fmt.Errorf("not implemented")
 }
+func (m *mockMarketService) ScreenAndScore(_ context.Context, _ models.ScreenerOptions, _ models.ScoreWeights) ([]*models.ScoredResult, error) {
+	return nil, fmt.Errorf("not implemented")
+}
 func (m *mockMarketService) FunnelScreen(_ context.Context, _ interfaces.FunnelOptions) (*models.FunnelResult, error) {
 	return nil, fmt.Errorf("not implemented")
 }
@@ -78,6 +85,9 @@ func (m *mockMarketService) ScanMarket(_ context.Context, _ models.ScanQuery) (*
 	return nil, nil
 }
 func (m *mockMarketService) ScanFields() *models.ScanFieldsResponse { return nil }
+func (m *mockMarketService) CorrelationMatrix(_ context.Context, _ []string, _ int) (map[string]map[string]float64, error) {
+	return nil, nil
+}
 func (m *mockMarketService) ReadFiling(_ context.Context, _, _ string) (*models.FilingContent, error) {
 	return nil, fmt.Errorf("not implemented")
 }
@@ -86,6 +96,8 @@ type mockPortfolioService struct {
 	getPortfolioFn    func(ctx context.Context, name string) (*models.Portfolio, error)
 	syncPortfolioFn   func(ctx context.Context, name string, force bool) (*models.Portfolio, error)
 	reviewPortfolioFn func(ctx context.Context, name string, options interfaces.ReviewOptions) (*models.PortfolioReview, error)
+	growthChartFn     func(ctx context.Context, name string) ([]byte, error)
+	activeAlerts      []models.AlertState
 }
 
 func (m *mockPortfolioService) SyncPortfolio(ctx context.Context, name string, force bool) (*models.Portfolio, error) {
@@ -94,6 +106,9 @@ func (m *mockPortfolioService) SyncPortfolio(ctx context.Context, name string, f
 	}
 	return nil, fmt.Errorf("not implemented")
 }
+func (m *mockPortfolioService) EnsureSynced(ctx context.Context, name string, _ time.Duration) (*models.Portfolio, error) {
+	return m.SyncPortfolio(ctx, name, false)
+}
 func (m *mockPortfolioService) GetPortfolio(ctx context.Context, name string) (*models.Portfolio, error) {
 	if m.getPortfolioFn != nil {
 		return m.getPortfolioFn(ctx, name)
@@ -109,9 +124,22 @@ func (m *mockPortfolioService) ReviewPortfolio(ctx context.Context, name string,
 	}
 	return nil, fmt.Errorf("not implemented")
 }
+func (m *mockPortfolioService) StreamPortfolioReview(_ context.Context, _ string, _ interfaces.ReviewOptions) (*models.PortfolioReview, <-chan string, <-chan error, error) {
+	return nil, nil, nil, nil
+}
+
 func (m *mockPortfolioService) ReviewWatchlist(_ context.Context, _ string, _ interfaces.ReviewOptions) (*models.WatchlistReview, error) {
 	return nil, fmt.Errorf("not implemented")
 }
+func (m *mockPortfolioService) ListActiveAlerts(_ context.Context, _ string) ([]models.AlertState, error) {
+	if m.activeAlerts != nil {
+		return m.activeAlerts, nil
+	}
+	return nil, fmt.Errorf("not implemented")
+}
+func (m *mockPortfolioService) AcknowledgeAlert(_ context.Context, _, _, _ string) (*models.AlertState, error) {
+	return nil, fmt.Errorf("not implemented")
+}
 func (m *mockPortfolioService) GetPortfolioSnapshot(_ context.Context, _ string, _ time.Time) (*models.PortfolioSnapshot, error) {
 	return nil, fmt.Errorf("not implemented")
 }
@@ -121,12 +149,61 @@ func (m *mockPortfolioService) GetPortfolioGrowth(_ context.Context, _ string) (
 func (m *mockPortfolioService) GetDailyGrowth(_ context.Context, _ string, _ interfaces.GrowthOptions) ([]models.GrowthDataPoint, error) {
 	return nil, fmt.Errorf("not implemented")
 }
+func (m *mockPortfolioService) GetGrowthChart(ctx context.Context, name string, _ interfaces.GrowthOptions) ([]byte, error) {
+	if m.growthChartFn != nil {
+		return m.growthChartFn(ctx, name)
+	}
+	return nil, fmt.Errorf("not implemented")
+}
+func (m *mockPortfolioService) FXDivisor(_ context.Context, _ *models.Portfolio, _ string) float64 {
+	return 1
+}
 func (m *mockPortfolioService) GetStockTimeline(_ context.Context, _, _ string, _, _ time.Time) ([]models.StockTimelinePoint, error) {
 	return nil, fmt.Errorf("not implemented")
 }
 func (m *mockPortfolioService) GetPortfolioIndicators(_ context.Context, _ string) (*models.PortfolioIndicators, error) {
 	return nil, fmt.Errorf("not implemented")
 }
+func (m *mockPortfolioService) SnapshotPortfolio(_ context.Context, _ string) (*models.DailyPortfolioSnapshot, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+func (m *mockPortfolioService) GetPortfolioHistory(_ context.Context, _ string, _, _ time.Time) ([]models.DailyPortfolioSnapshot, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (m *mockPortfolioService) GetPortfolioTimeline(_ context.Context, _ string, _ interfaces.GrowthOptions) ([]models.GrowthDataPoint, error) {
+	return nil, nil
+}
+func (m *mockPortfolioService) TimeWeightedReturn(_ context.Context, _, _, _ string) (float64, error) {
+	return 0, fmt.Errorf("not implemented")
+}
+func (m *mockPortfolioService) GetDataCompleteness(_ context.Context, _ string) (*models.DataCompleteness, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+func (m *mockPortfolioService) CalculateCGT(_ context.Context, _, _ string) (*models.CGTReport, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+func (m *mockPortfolioService) RealizedGainTimeline(_ context.Context, _ string) (*models.RealizedGainTimeline, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+func (m *mockPortfolioService) SyncHolding(_ context.Context, _, _ string) (*models.Holding, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+func (m *mockPortfolioService) SimulateTrade(_ context.Context, _ string, _ models.SimulatedTrade) (*models.TradeSimulation, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+func (m *mockPortfolioService) SectorAllocation(_ context.Context, _ string) (*models.SectorBreakdown, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+func (m *mockPortfolioService) FeeSummary(_ context.Context, _ string) (*models.FeeSummary, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+func (m *mockPortfolioService) RebalanceSuggestions(_ context.Context, _ string) (*models.RebalancePlan, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+func (m *mockPortfolioService) ProjectValue(_ context.Context, _ string, _, _ int) (*models.ProjectionResult, error) {
+	return nil, fmt.Errorf("not implemented")
+}
 func (m *mockPortfolioService) RefreshTodaySnapshot(_ context.Context, _ string) error {
 	return nil
 }
@@ -154,6 +231,11 @@ func (m *mockSignalService) DetectSignals(ctx context.Context, tickers []string,
 func (m *mockSignalService) ComputeSignals(_ context.Context, _ string, _ *models.MarketData) (*models.TickerSignals, error) {
 	return nil, fmt.Errorf("not implemented")
 }
+func (m *mockSignalService) ScoreNewsSentiment(_ context.Context, _ *models.TickerSignals, _ *models.MarketData) {
+}
+func (m *mockSignalService) BacktestThresholds(_ context.Context, _ string, _ int) (*models.SignalBacktest, error) {
+	return nil, fmt.Errorf("not implemented")
+}
 
 type mockUserDataStore struct {
 	data map[string]*models.UserRecord // keyed by "userID:subject:key"
@@ -181,12 +263,24 @@ func (m *mockUserDataStore) Delete(_ context.Context, userID, subject, key strin
 	delete(m.data, m.compositeKey(userID, subject, key))
 	return nil
 }
-func (m *mockUserDataStore) List(_ context.Context, _ string, _ string) ([]*models.UserRecord, error) {
-	return nil, nil
+func (m *mockUserDataStore) List(_ context.Context, userID, subject string) ([]*models.UserRecord, error) {
+	prefix := m.compositeKey(userID, subject, "")
+	var out []*models.UserRecord
+	for k, rec := range m.data {
+		if strings.HasPrefix(k, prefix) {
+			out = append(out, rec)
+		}
+	}
+	return out, nil
 }
 func (m *mockUserDataStore) Query(_ context.Context, _, _ string, _ interfaces.QueryOptions) ([]*models.UserRecord, error) {
 	return nil, nil
 }
+
+func (m *mockUserDataStore) Count(ctx context.Context, userID, subject string, _ interfaces.QueryOptions) (int, error) {
+	records, err := m.List(ctx, userID, subject)
+	return len(records), err
+}
 func (m *mockUserDataStore) DeleteBySubject(_ context.Context, _ string) (int, error) {
 	return 0, nil
 }
@@ -213,7 +307,13 @@ func (m *mockStorageManager) PurgeDerivedData(_ context.Context) (map[string]int
 	return nil, nil
 }
 func (m *mockStorageManager) PurgeReports(_ context.Context) (int, error) { return 0, nil }
-func (m *mockStorageManager) Close() error                                { return nil }
+func (m *mockStorageManager) Backup(_ context.Context, _ io.Writer) (*models.BackupManifest, error) {
+	return nil, nil
+}
+func (m *mockStorageManager) Restore(_ context.Context, _ io.Reader) (*models.BackupManifest, error) {
+	return nil, nil
+}
+func (m *mockStorageManager) Close() error { return nil }
 
 // ============================================================================
 // Test helpers — shared by service_test.go
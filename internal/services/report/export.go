@@ -0,0 +1,199 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/interfaces"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// ExportFormatSharesight is Sharesight's trade import CSV.
+const ExportFormatSharesight = "sharesight"
+
+// importFormat describes a platform's trade import file: its columns, date
+// layout and transaction vocabulary. Trade types missing from types are
+// skipped with the reason in skipReasons, or as unsupported.
+type importFormat struct {
+	header      []string
+	dateLayout  string
+	types       map[string]string // lower-case trade type -> target transaction type
+	skipReasons map[string]string // lower-case trade type -> why it is not written
+	market      func(exchange string) (code, warning string)
+}
+
+// importFormats lists the formats ExportTradesForImport can write.
+var importFormats = map[string]importFormat{
+	ExportFormatSharesight: {
+		header:     []string{"Trade Date", "Market Code", "Instrument Code", "Transaction Type", "Quantity", "Price", "Brokerage"},
+		dateLayout: "02/01/2006",
+		types: map[string]string{
+			"buy":             "BUY",
+			"sell":            "SELL",
+			"opening balance": "OPENING_BALANCE",
+		},
+		skipReasons: map[string]string{
+			"cost base increase": "Sharesight's trade import has no cost base adjustment; record it as a cost base adjustment on the holding in Sharesight",
+			"cost base decrease": "Sharesight's trade import has no cost base adjustment; record it as a cost base adjustment on the holding in Sharesight",
+			"split":              "splits are corporate actions in Sharesight; record the split there so quantities are not counted twice",
+			"dividend":           "dividends are not trades; Sharesight imports them as income, not through the trade import",
+		},
+		market: sharesightMarket,
+	},
+}
+
+// sharesightMarket maps a holding exchange to a Sharesight market code.
+// A bare US listing does not say which exchange it trades on, so the code
+// is left as US with a warning to correct it before import.
+func sharesightMarket(exchange string) (string, string) {
+	switch ex := strings.ToUpper(strings.TrimSpace(exchange)); ex {
+	case "", "AU", "ASX":
+		return "ASX", ""
+	case "NZ", "NZX":
+		return "NZX", ""
+	case "LON", "LSE":
+		return "LSE", ""
+	case "US":
+		return ex, "set Market Code to NYSE or NASDAQ before import"
+	default:
+		return ex, ""
+	}
+}
+
+// exportRow is one written trade, kept with its parsed date for ordering.
+type exportRow struct {
+	date   time.Time
+	ticker string
+	fields []string
+}
+
+// ExportTradesForImport writes the portfolio's trade history as a trade
+// import CSV for another platform (currently only "sharesight", the default).
+// Rows are ordered by trade date and prices stay in the listing's currency.
+// Trades the format cannot express, and trades without a date, are listed in
+// Skipped with the reason instead of being written.
+func (s *Service) ExportTradesForImport(ctx context.Context, portfolioName, format string) (*models.TradeImportExport, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" {
+		format = ExportFormatSharesight
+	}
+	f, ok := importFormats[format]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q (valid: %s)", interfaces.ErrUnsupportedExportFormat, format, ExportFormatSharesight)
+	}
+
+	portfolio, err := s.portfolio.GetPortfolio(ctx, portfolioName)
+	if err != nil {
+		return nil, fmt.Errorf("get portfolio: %w", err)
+	}
+	return buildTradeExport(portfolio, format, f)
+}
+
+// buildTradeExport renders the holdings' trades in format f. Holdings that
+// share a ticker carry the same merged trades, so each ticker is written once.
+func buildTradeExport(portfolio *models.Portfolio, format string, f importFormat) (*models.TradeImportExport, error) {
+	export := &models.TradeImportExport{PortfolioName: portfolio.Name, Format: format}
+
+	var rows []exportRow
+	seen := make(map[string]bool)
+	for _, h := range portfolio.Holdings {
+		if seen[h.Ticker] {
+			continue
+		}
+		seen[h.Ticker] = true
+		if len(h.Trades) == 0 {
+			if h.Units > 0 {
+				export.Warnings = append(export.Warnings, fmt.Sprintf("%s: no trade history to export", h.Ticker))
+			}
+			continue
+		}
+
+		market, warning := f.market(h.Exchange)
+		if warning != "" {
+			export.Warnings = append(export.Warnings, fmt.Sprintf("%s: %s", h.Ticker, warning))
+		}
+		for _, t := range h.Trades {
+			tradeType := strings.ToLower(strings.TrimSpace(t.Type))
+			skip := models.TradeExportSkip{Ticker: h.Ticker, Date: t.Date, Type: tradeType, Units: t.Units, Value: t.Value}
+
+			target, ok := f.types[tradeType]
+			if !ok {
+				skip.Reason = f.skipReasons[tradeType]
+				if skip.Reason == "" {
+					skip.Reason = fmt.Sprintf("trade type %q is not supported by the %s import", tradeType, format)
+				}
+				export.Skipped = append(export.Skipped, skip)
+				continue
+			}
+			date := parseExportDate(t.Date)
+			if date.IsZero() {
+				skip.Reason = "trade has no valid date"
+				export.Skipped = append(export.Skipped, skip)
+				continue
+			}
+			rows = append(rows, exportRow{
+				date:   date,
+				ticker: h.Ticker,
+				fields: []string{
+					date.Format(f.dateLayout),
+					market,
+					h.Ticker,
+					target,
+					formatExportNumber(math.Abs(t.Units)),
+					formatExportNumber(t.Price),
+					formatExportNumber(t.Fees),
+				},
+			})
+		}
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		if !rows[i].date.Equal(rows[j].date) {
+			return rows[i].date.Before(rows[j].date)
+		}
+		return rows[i].ticker < rows[j].ticker
+	})
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(f.header); err != nil {
+		return nil, fmt.Errorf("write csv header: %w", err)
+	}
+	for _, r := range rows {
+		if err := w.Write(r.fields); err != nil {
+			return nil, fmt.Errorf("write csv row: %w", err)
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("write csv: %w", err)
+	}
+
+	export.CSV = buf.String()
+	export.Rows = len(rows)
+	return export, nil
+}
+
+// parseExportDate parses a Navexa trade date, with or without a time part.
+// Unparseable dates return the zero time.
+func parseExportDate(s string) time.Time {
+	s = strings.TrimSpace(s)
+	for _, layout := range []string{"2006-01-02", "2006-01-02T15:04:05", time.RFC3339} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// formatExportNumber writes v without trailing zeros or exponent notation.
+func formatExportNumber(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,165 @@
+package report
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/interfaces"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+func newExportService(portfolio *models.Portfolio) *Service {
+	return NewService(&mockPortfolioService{
+		getPortfolioFn: func(_ context.Context, _ string) (*models.Portfolio, error) {
+			return portfolio, nil
+		},
+	}, nil, nil, nil, common.NewLogger("error"))
+}
+
+func parseExportCSV(t *testing.T, data string) [][]string {
+	t.Helper()
+	records, err := csv.NewReader(strings.NewReader(data)).ReadAll()
+	if err != nil {
+		t.Fatalf("export is not valid CSV: %v\n%s", err, data)
+	}
+	return records
+}
+
+func TestExportTradesForImport_SharesightLayout(t *testing.T) {
+	svc := newExportService(&models.Portfolio{
+		Name: "SMSF",
+		Holdings: []models.Holding{
+			{Ticker: "CBA", Exchange: "AU", Units: 50, Trades: []*models.NavexaTrade{
+				{Type: "Buy", Date: "2024-03-05T00:00:00", Units: 50, Price: 110.5, Fees: 19.95},
+			}},
+			{Ticker: "BHP", Exchange: "ASX", Units: 60, Trades: []*models.NavexaTrade{
+				{Type: "opening balance", Date: "2023-07-01", Units: 100, Price: 40},
+				{Type: "sell", Date: "2024-03-05", Units: -40, Price: 45.25, Fees: 9.5},
+			}},
+		},
+	})
+
+	export, err := svc.ExportTradesForImport(context.Background(), "SMSF", "")
+	if err != nil {
+		t.Fatalf("ExportTradesForImport: %v", err)
+	}
+	if export.Format != ExportFormatSharesight {
+		t.Errorf("Format = %q, want default %q", export.Format, ExportFormatSharesight)
+	}
+
+	records := parseExportCSV(t, export.CSV)
+	want := [][]string{
+		{"Trade Date", "Market Code", "Instrument Code", "Transaction Type", "Quantity", "Price", "Brokerage"},
+		{"01/07/2023", "ASX", "BHP", "OPENING_BALANCE", "100", "40", "0"},
+		{"05/03/2024", "ASX", "BHP", "SELL", "40", "45.25", "9.5"},
+		{"05/03/2024", "ASX", "CBA", "BUY", "50", "110.5", "19.95"},
+	}
+	if len(records) != len(want) {
+		t.Fatalf("expected %d lines, got %d:\n%s", len(want), len(records), export.CSV)
+	}
+	for i := range want {
+		if strings.Join(records[i], ",") != strings.Join(want[i], ",") {
+			t.Errorf("line %d = %v, want %v", i, records[i], want[i])
+		}
+	}
+	if export.Rows != 3 {
+		t.Errorf("Rows = %d, want 3", export.Rows)
+	}
+	if len(export.Skipped) != 0 || len(export.Warnings) != 0 {
+		t.Errorf("unexpected skipped %+v / warnings %v", export.Skipped, export.Warnings)
+	}
+}
+
+func TestExportTradesForImport_UnsupportedTypesAreReported(t *testing.T) {
+	svc := newExportService(&models.Portfolio{
+		Name: "SMSF",
+		Holdings: []models.Holding{
+			{Ticker: "WES", Exchange: "AU", Units: 10, Trades: []*models.NavexaTrade{
+				{Type: "buy", Date: "2024-01-10", Units: 10, Price: 60},
+				{Type: "Cost Base Decrease", Date: "2024-05-01", Value: 12.5},
+				{Type: "dividend", Date: "2024-06-01", Value: 8},
+				{Type: "split", Date: "2024-07-01", Units: 10},
+				{Type: "transfer in", Date: "2024-08-01", Units: 5, Price: 61},
+				{Type: "buy", Date: "", Units: 1, Price: 62},
+			}},
+			// Same ticker in a second account carries the same merged trades
+			{Ticker: "WES", Exchange: "AU", Units: 0, Trades: []*models.NavexaTrade{
+				{Type: "buy", Date: "2024-01-10", Units: 10, Price: 60},
+			}},
+		},
+	})
+
+	export, err := svc.ExportTradesForImport(context.Background(), "SMSF", "Sharesight")
+	if err != nil {
+		t.Fatalf("ExportTradesForImport: %v", err)
+	}
+	if export.Rows != 1 {
+		t.Fatalf("Rows = %d, want only the dated buy:\n%s", export.Rows, export.CSV)
+	}
+	if lines := parseExportCSV(t, export.CSV); len(lines) != 2 {
+		t.Errorf("expected header plus one row, got %d lines", len(lines))
+	}
+
+	// Every unwritten trade is reported with a reason, none dropped silently.
+	wantReasons := map[string]string{
+		"cost base decrease": "cost base adjustment",
+		"dividend":           "dividends are not trades",
+		"split":              "corporate actions",
+		"transfer in":        "not supported",
+		"buy":                "no valid date",
+	}
+	if len(export.Skipped) != len(wantReasons) {
+		t.Fatalf("expected %d skipped trades, got %d: %+v", len(wantReasons), len(export.Skipped), export.Skipped)
+	}
+	for _, sk := range export.Skipped {
+		want, ok := wantReasons[sk.Type]
+		if !ok {
+			t.Errorf("unexpected skipped trade %+v", sk)
+			continue
+		}
+		if sk.Ticker != "WES" || !strings.Contains(sk.Reason, want) {
+			t.Errorf("skipped %s: reason %q, want it to mention %q", sk.Type, sk.Reason, want)
+		}
+	}
+}
+
+func TestExportTradesForImport_Warnings(t *testing.T) {
+	svc := newExportService(&models.Portfolio{
+		Name: "Growth",
+		Holdings: []models.Holding{
+			{Ticker: "NVDA", Exchange: "US", Units: 5, Trades: []*models.NavexaTrade{
+				{Type: "buy", Date: "2024-02-01", Units: 5, Price: 650},
+			}},
+			{Ticker: "VAS", Exchange: "AU", Units: 20},
+		},
+	})
+
+	export, err := svc.ExportTradesForImport(context.Background(), "Growth", "sharesight")
+	if err != nil {
+		t.Fatalf("ExportTradesForImport: %v", err)
+	}
+	records := parseExportCSV(t, export.CSV)
+	if len(records) != 2 || records[1][1] != "US" {
+		t.Fatalf("expected one NVDA row with market US, got %v", records)
+	}
+	joined := strings.Join(export.Warnings, "\n")
+	if !strings.Contains(joined, "NVDA: set Market Code to NYSE or NASDAQ") {
+		t.Errorf("missing US market warning: %v", export.Warnings)
+	}
+	if !strings.Contains(joined, "VAS: no trade history") {
+		t.Errorf("missing no-trades warning: %v", export.Warnings)
+	}
+}
+
+func TestExportTradesForImport_UnsupportedFormat(t *testing.T) {
+	svc := newExportService(&models.Portfolio{Name: "SMSF"})
+
+	_, err := svc.ExportTradesForImport(context.Background(), "SMSF", "quicken")
+	if !errors.Is(err, interfaces.ErrUnsupportedExportFormat) {
+		t.Fatalf("expected ErrUnsupportedExportFormat, got %v", err)
+	}
+}
@@ -129,6 +129,25 @@ func formatReportSummary(review *models.PortfolioReview) string {
 	sb.WriteString(fmt.Sprintf("**Portfolio Total:** %s | **Total Return:** %s (%s)\n\n",
 		common.FormatMoney(review.PortfolioValue), common.FormatSignedMoney(review.EquityHoldingsReturn), common.FormatSignedPct(review.EquityHoldingsReturnPct)))
 
+	// Value Waterfall
+	if w := review.Waterfall; w != nil {
+		sb.WriteString("## Value Waterfall\n\n")
+		sb.WriteString("| Component | Amount |\n")
+		sb.WriteString("|-----------|--------|\n")
+		start := "Starting Value"
+		if !w.StartDate.IsZero() {
+			start = fmt.Sprintf("Starting Value (%s)", w.StartDate.Format("2006-01-02"))
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %s |\n", start, common.FormatMoney(w.StartValue)))
+		sb.WriteString(fmt.Sprintf("| Contributions | %s |\n", common.FormatSignedMoney(w.Contributions)))
+		sb.WriteString(fmt.Sprintf("| Realized Gains | %s |\n", common.FormatSignedMoney(w.RealizedGains)))
+		sb.WriteString(fmt.Sprintf("| Unrealized Change | %s |\n", common.FormatSignedMoney(w.UnrealizedChange)))
+		sb.WriteString(fmt.Sprintf("| Dividends | %s |\n", common.FormatSignedMoney(w.Dividends)))
+		sb.WriteString(fmt.Sprintf("| Other (fees, interest) | %s |\n", common.FormatSignedMoney(w.Other)))
+		sb.WriteString(fmt.Sprintf("| Unexplained | %s |\n", common.FormatSignedMoney(w.Unexplained)))
+		sb.WriteString(fmt.Sprintf("| **Ending Value** | **%s** |\n\n", common.FormatMoney(w.EndValue)))
+	}
+
 	// Portfolio Balance
 	if review.PortfolioBalance != nil {
 		sb.WriteString("## Portfolio Balance\n\n")
@@ -0,0 +1,221 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+
+	"github.com/bobmcallan/vire/internal/interfaces"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// GeneratePDF renders a stored report as a PDF: a cover page with the growth
+// chart and summary tables, then one section per holding. An empty reportID
+// selects the latest report; otherwise it must match the stored report's ID.
+// Reports without holdings produce a single "no holdings" page.
+func (s *Service) GeneratePDF(ctx context.Context, portfolioName, reportID string) ([]byte, error) {
+	report, err := s.getReportRecord(ctx, portfolioName)
+	if err != nil {
+		return nil, err
+	}
+	if reportID != "" && reportID != report.ID() {
+		return nil, fmt.Errorf("report '%s' not found for '%s' (latest is '%s')", reportID, portfolioName, report.ID())
+	}
+
+	doc := newPDFDocument(report)
+
+	if len(report.TickerReports) == 0 {
+		doc.heading(1, "No holdings")
+		doc.paragraph(fmt.Sprintf("Portfolio '%s' has no open holdings to report on.", report.Portfolio))
+		return doc.bytes()
+	}
+
+	// Growth chart is best-effort; the report is still useful without it
+	if png, err := s.portfolio.GetGrowthChart(ctx, portfolioName, interfaces.GrowthOptions{}); err == nil {
+		doc.image("growth", png)
+	} else {
+		s.logger.WithRequestID(ctx).Warn().Err(err).Str("portfolio", portfolioName).Msg("PDF: growth chart unavailable")
+	}
+
+	doc.markdown(report.SummaryMarkdown)
+
+	for _, tr := range report.TickerReports {
+		doc.pdf.AddPage()
+		doc.markdown(tr.Markdown)
+	}
+
+	return doc.bytes()
+}
+
+// pdfDocument renders report markdown onto A4 pages using the core
+// Helvetica font. Only the subset of markdown the formatter emits is
+// understood: headings, pipe tables, bullets and plain paragraphs.
+type pdfDocument struct {
+	pdf *fpdf.Fpdf
+	tr  func(string) string
+}
+
+func newPDFDocument(report *models.PortfolioReport) *pdfDocument {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetTitle(fmt.Sprintf("%s portfolio report", report.Portfolio), true)
+	pdf.SetCreator("vire", true)
+	pdf.SetAutoPageBreak(true, 15)
+
+	d := &pdfDocument{pdf: pdf, tr: pdf.UnicodeTranslatorFromDescriptor("")}
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-12)
+		pdf.SetFont("Helvetica", "I", 8)
+		pdf.CellFormat(0, 6, d.tr(fmt.Sprintf("%s - generated %s - page %d",
+			report.Portfolio, report.GeneratedAt.Format("2006-01-02 15:04"), pdf.PageNo())), "", 0, "C", false, 0, "")
+	})
+
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "B", 18)
+	pdf.CellFormat(0, 10, d.tr(report.Portfolio+" Portfolio Report"), "", 1, "L", false, 0, "")
+	return d
+}
+
+func (d *pdfDocument) bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := d.pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("render pdf: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (d *pdfDocument) contentWidth() float64 {
+	pageW, _ := d.pdf.GetPageSize()
+	left, _, right, _ := d.pdf.GetMargins()
+	return pageW - left - right
+}
+
+func (d *pdfDocument) heading(level int, text string) {
+	size := map[int]float64{1: 16, 2: 13, 3: 11}[level]
+	if size == 0 {
+		size = 10
+	}
+	d.pdf.Ln(2)
+	d.pdf.SetFont("Helvetica", "B", size)
+	d.pdf.MultiCell(0, size*0.5, d.tr(stripInlineMarkdown(text)), "", "L", false)
+	d.pdf.Ln(1)
+}
+
+func (d *pdfDocument) paragraph(text string) {
+	d.pdf.SetFont("Helvetica", "", 9)
+	d.pdf.MultiCell(0, 4.5, d.tr(stripInlineMarkdown(text)), "", "L", false)
+}
+
+func (d *pdfDocument) bullet(text string) {
+	d.pdf.SetFont("Helvetica", "", 9)
+	left, _, _, _ := d.pdf.GetMargins()
+	d.pdf.SetX(left + 4)
+	d.pdf.MultiCell(0, 4.5, d.tr("- "+stripInlineMarkdown(text)), "", "L", false)
+}
+
+// image embeds a PNG scaled to the content width.
+func (d *pdfDocument) image(name string, png []byte) {
+	opts := fpdf.ImageOptions{ImageType: "PNG", ReadDpi: false}
+	d.pdf.RegisterImageOptionsReader(name, opts, bytes.NewReader(png))
+	d.pdf.ImageOptions(name, -1, -1, d.contentWidth(), 0, true, opts, 0, "")
+	d.pdf.Ln(2)
+}
+
+// table draws rows with equal-width columns. The first row is the header.
+// Cell text is truncated to fit rather than wrapped.
+func (d *pdfDocument) table(rows [][]string) {
+	cols := 0
+	for _, r := range rows {
+		if len(r) > cols {
+			cols = len(r)
+		}
+	}
+	if cols == 0 {
+		return
+	}
+	w := d.contentWidth() / float64(cols)
+	for i, r := range rows {
+		style := ""
+		if i == 0 {
+			style = "B"
+		}
+		d.pdf.SetFont("Helvetica", style, 8)
+		for c := 0; c < cols; c++ {
+			text := ""
+			if c < len(r) {
+				text = d.fit(d.tr(stripInlineMarkdown(r[c])), w-2)
+			}
+			d.pdf.CellFormat(w, 5.5, text, "1", 0, "L", i == 0, 0, "")
+		}
+		d.pdf.Ln(-1)
+	}
+	d.pdf.Ln(2)
+}
+
+// fit truncates text to the given width in the current font.
+func (d *pdfDocument) fit(text string, width float64) string {
+	if d.pdf.GetStringWidth(text) <= width {
+		return text
+	}
+	for len(text) > 0 && d.pdf.GetStringWidth(text+"..") > width {
+		text = text[:len(text)-1]
+	}
+	return text + ".."
+}
+
+// markdown renders formatter output block by block.
+func (d *pdfDocument) markdown(md string) {
+	d.pdf.SetFillColor(230, 236, 245)
+	var rows [][]string
+	flush := func() {
+		if len(rows) > 0 {
+			d.table(rows)
+			rows = nil
+		}
+	}
+
+	for _, line := range strings.Split(md, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "|") {
+			if !isTableSeparator(trimmed) {
+				rows = append(rows, splitTableRow(trimmed))
+			}
+			continue
+		}
+		flush()
+
+		switch {
+		case trimmed == "":
+			d.pdf.Ln(2)
+		case trimmed == "---":
+			d.pdf.Ln(3)
+		case strings.HasPrefix(trimmed, "#"):
+			level := len(trimmed) - len(strings.TrimLeft(trimmed, "#"))
+			d.heading(level, strings.TrimSpace(trimmed[level:]))
+		case strings.HasPrefix(trimmed, "- "), strings.HasPrefix(trimmed, "* "):
+			d.bullet(trimmed[2:])
+		default:
+			d.paragraph(trimmed)
+		}
+	}
+	flush()
+}
+
+func isTableSeparator(line string) bool {
+	return strings.Trim(line, "|-: ") == ""
+}
+
+func splitTableRow(line string) []string {
+	cells := strings.Split(strings.Trim(line, "|"), "|")
+	for i := range cells {
+		cells[i] = strings.TrimSpace(cells[i])
+	}
+	return cells
+}
+
+// stripInlineMarkdown removes emphasis and code markers.
+func stripInlineMarkdown(s string) string {
+	return strings.NewReplacer("**", "", "__", "", "`", "").Replace(s)
+}
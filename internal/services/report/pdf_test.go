@@ -0,0 +1,93 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"image"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/ledongthuc/pdf"
+
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+func seedPDFReport(ctx context.Context, store *mockUserDataStore, report *models.PortfolioReport) {
+	data, _ := json.Marshal(report)
+	store.Put(ctx, &models.UserRecord{UserID: "default", Subject: "report", Key: report.Portfolio, Value: string(data)})
+}
+
+func pdfPageCount(t *testing.T, data []byte) int {
+	t.Helper()
+	if !bytes.HasPrefix(data, []byte("%PDF-")) {
+		t.Fatalf("output is not a PDF: %q", data[:min(len(data), 16)])
+	}
+	r, err := pdf.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("parse pdf: %v", err)
+	}
+	return r.NumPage()
+}
+
+func TestGeneratePDF_SummaryHoldingsAndChart(t *testing.T) {
+	ctx := context.Background()
+	svc, userData, _, portfolio := newTestServiceForUnit()
+	portfolio.growthChartFn = func(_ context.Context, _ string) ([]byte, error) {
+		var buf bytes.Buffer
+		err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 4, 4)))
+		return buf.Bytes(), err
+	}
+
+	report := &models.PortfolioReport{
+		Portfolio:       "SMSF",
+		GeneratedAt:     time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC),
+		SummaryMarkdown: "# SMSF\n\n| Ticker | Value |\n|---|---|\n| **BHP** | $1,000 |\n\n- All good",
+		Tickers:         []string{"BHP", "CBA"},
+		TickerReports: []models.TickerReport{
+			{Ticker: "BHP", Markdown: "## BHP\n\nHold."},
+			{Ticker: "CBA", Markdown: "## CBA\n\nTrim — overweight."},
+		},
+	}
+	seedPDFReport(ctx, userData, report)
+
+	data, err := svc.GeneratePDF(ctx, "SMSF", "")
+	if err != nil {
+		t.Fatalf("GeneratePDF: %v", err)
+	}
+	if pages := pdfPageCount(t, data); pages != 3 {
+		t.Errorf("pages = %d, want 3 (summary + one per holding)", pages)
+	}
+	if !bytes.Contains(data, []byte("/Subtype /Image")) {
+		t.Error("expected the growth chart to be embedded")
+	}
+
+	if _, err := svc.GeneratePDF(ctx, "SMSF", report.ID()); err != nil {
+		t.Errorf("GeneratePDF with matching report ID: %v", err)
+	}
+	if _, err := svc.GeneratePDF(ctx, "SMSF", "20200101T000000Z"); err == nil {
+		t.Error("expected an error for an unknown report ID")
+	}
+}
+
+func TestGeneratePDF_NoHoldings(t *testing.T) {
+	ctx := context.Background()
+	svc, userData, _, _ := newTestServiceForUnit()
+	seedPDFReport(ctx, userData, &models.PortfolioReport{Portfolio: "Empty", GeneratedAt: time.Now()})
+
+	data, err := svc.GeneratePDF(ctx, "Empty", "")
+	if err != nil {
+		t.Fatalf("GeneratePDF: %v", err)
+	}
+	if pages := pdfPageCount(t, data); pages != 1 {
+		t.Errorf("pages = %d, want 1", pages)
+	}
+}
+
+func TestGeneratePDF_NoReport(t *testing.T) {
+	svc, _, _, _ := newTestServiceForUnit()
+	if _, err := svc.GeneratePDF(context.Background(), "Missing", ""); err == nil {
+		t.Error("expected an error when no report is stored")
+	}
+}
@@ -0,0 +1,194 @@
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/interfaces"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+	titleWeight        = 3 // a term in a section title counts as three body hits
+	snippetBefore      = 60
+	snippetAfter       = 120
+)
+
+// searchSection is one searchable unit of a report: the summary or a ticker section
+type searchSection struct {
+	ticker string
+	title  string
+	body   string
+}
+
+// SearchReports scans the user's stored reports for sections containing every
+// query term. Matching is case-insensitive and a term matches any word it
+// prefixes, so "dividend" also finds "dividends". Sections are ranked by term
+// frequency with title hits weighted up, then newest report first. Each user
+// holds one report per portfolio, so a scan is cheap enough not to need an index.
+func (s *Service) SearchReports(ctx context.Context, query string, opts interfaces.ReportSearchOptions) ([]models.ReportSearchResult, error) {
+	terms := tokenize(query)
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("search query is empty")
+	}
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+
+	userID := common.ResolveUserID(ctx)
+	records, err := s.storage.UserDataStore().List(ctx, userID, "report")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reports: %w", err)
+	}
+
+	results := make([]models.ReportSearchResult, 0)
+	for _, rec := range records {
+		if opts.Portfolio != "" && !strings.EqualFold(rec.Key, opts.Portfolio) {
+			continue
+		}
+		var report models.PortfolioReport
+		if err := json.Unmarshal([]byte(rec.Value), &report); err != nil {
+			s.logger.WithRequestID(ctx).Warn().Str("portfolio", rec.Key).Err(err).Msg("Skipping unreadable report in search")
+			continue
+		}
+		for _, sec := range reportSections(&report) {
+			score, ok := scoreSection(sec, terms)
+			if !ok {
+				continue
+			}
+			results = append(results, models.ReportSearchResult{
+				Portfolio:   report.Portfolio,
+				ReportID:    report.ID(),
+				GeneratedAt: report.GeneratedAt,
+				Ticker:      sec.ticker,
+				Title:       sec.title,
+				Snippet:     snippet(sec.body, terms),
+				Score:       score,
+			})
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if !a.GeneratedAt.Equal(b.GeneratedAt) {
+			return a.GeneratedAt.After(b.GeneratedAt)
+		}
+		if a.Portfolio != b.Portfolio {
+			return a.Portfolio < b.Portfolio
+		}
+		return a.Ticker < b.Ticker
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// reportSections splits a report into its summary and per-ticker sections
+func reportSections(report *models.PortfolioReport) []searchSection {
+	sections := make([]searchSection, 0, len(report.TickerReports)+1)
+	if report.SummaryMarkdown != "" {
+		sections = append(sections, searchSection{
+			title: report.Portfolio + " summary",
+			body:  report.SummaryMarkdown,
+		})
+	}
+	for _, tr := range report.TickerReports {
+		title := tr.Ticker
+		if tr.Name != "" {
+			title += " — " + tr.Name
+		}
+		sections = append(sections, searchSection{ticker: tr.Ticker, title: title, body: tr.Markdown})
+	}
+	return sections
+}
+
+// scoreSection returns the weighted hit count, and false unless every term matches
+func scoreSection(sec searchSection, terms []string) (float64, bool) {
+	titleTokens := tokenize(sec.title)
+	bodyTokens := tokenize(sec.body)
+	score := 0.0
+	for _, term := range terms {
+		hits := titleWeight*countPrefixed(titleTokens, term) + countPrefixed(bodyTokens, term)
+		if hits == 0 {
+			return 0, false
+		}
+		score += float64(hits)
+	}
+	return score, true
+}
+
+func countPrefixed(tokens []string, term string) int {
+	n := 0
+	for _, tok := range tokens {
+		if strings.HasPrefix(tok, term) {
+			n++
+		}
+	}
+	return n
+}
+
+// tokenize lower-cases text and splits it into letter/digit runs
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// snippet returns a whitespace-collapsed excerpt around the earliest query term
+// in body, or the start of body when no term appears there (a title-only match)
+func snippet(body string, terms []string) string {
+	text := strings.Join(strings.Fields(strings.NewReplacer("**", "", "#", "", "|", " ").Replace(body)), " ")
+	lower := strings.ToLower(text)
+
+	pos := -1
+	for _, term := range terms {
+		if len(lower) != len(text) {
+			break // case folding changed byte offsets; fall back to the start of the body
+		}
+		if i := strings.Index(lower, term); i >= 0 && (pos < 0 || i < pos) {
+			pos = i
+		}
+	}
+
+	start, end := 0, len(text)
+	if pos > snippetBefore {
+		start = pos - snippetBefore
+	}
+	if pos < 0 {
+		pos = 0
+	}
+	if pos+snippetAfter < end {
+		end = pos + snippetAfter
+	}
+	// Widen to whole words
+	for start > 0 && text[start-1] != ' ' {
+		start--
+	}
+	for end < len(text) && text[end] != ' ' {
+		end++
+	}
+
+	out := text[start:end]
+	if start > 0 {
+		out = "…" + out
+	}
+	if end < len(text) {
+		out += "…"
+	}
+	return out
+}
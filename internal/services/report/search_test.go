@@ -0,0 +1,123 @@
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/interfaces"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+func seedSearchReport(t *testing.T, store *mockUserDataStore, report *models.PortfolioReport) {
+	t.Helper()
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatalf("marshal report: %v", err)
+	}
+	store.Put(context.Background(), &models.UserRecord{
+		UserID:  "default",
+		Subject: "report",
+		Key:     report.Portfolio,
+		Value:   string(data),
+	})
+}
+
+func newSearchTestService(t *testing.T) *Service {
+	t.Helper()
+	svc, store, _, _ := newTestServiceForUnit()
+	now := time.Now().Truncate(time.Second)
+	seedSearchReport(t, store, &models.PortfolioReport{
+		Portfolio:       "SMSF",
+		GeneratedAt:     now.Add(-time.Hour),
+		SummaryMarkdown: "# SMSF\n\nPortfolio is overweight **materials** after the iron ore rally.",
+		TickerReports: []models.TickerReport{
+			{Ticker: "BHP.AU", Name: "BHP Group", Markdown: "## BHP\n\nIron ore volumes steady. Interim dividend raised to $1.10 fully franked."},
+			{Ticker: "CBA.AU", Name: "Commonwealth Bank", Markdown: "Net interest margin compressed. Dividend held."},
+		},
+	})
+	seedSearchReport(t, store, &models.PortfolioReport{
+		Portfolio:   "Trading",
+		GeneratedAt: now,
+		TickerReports: []models.TickerReport{
+			{Ticker: "FMG.AU", Name: "Fortescue", Markdown: "Iron ore price sensitivity remains high; no dividend change."},
+		},
+	})
+	return svc
+}
+
+func TestSearchReports_SingleTerm(t *testing.T) {
+	svc := newSearchTestService(t)
+
+	results, err := svc.SearchReports(context.Background(), "DIVIDEND", interfaces.ReportSearchOptions{})
+	if err != nil {
+		t.Fatalf("SearchReports: %v", err)
+	}
+	got := make([]string, len(results))
+	for i, r := range results {
+		got[i] = r.Ticker
+	}
+	// All score 1; ties resolve newest report first, then by ticker
+	want := []string{"FMG.AU", "BHP.AU", "CBA.AU"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("tickers = %v, want %v", got, want)
+	}
+	bhp := results[1]
+	if bhp.Portfolio != "SMSF" || bhp.Title != "BHP.AU — BHP Group" || bhp.ReportID == "" {
+		t.Errorf("BHP result = %+v", bhp)
+	}
+	if !strings.Contains(bhp.Snippet, "Interim dividend raised") || strings.Contains(bhp.Snippet, "##") {
+		t.Errorf("snippet = %q, want cleaned text around the match", bhp.Snippet)
+	}
+}
+
+func TestSearchReports_MultiTermAND(t *testing.T) {
+	svc := newSearchTestService(t)
+
+	// "iron ore" appears in the SMSF summary, BHP and FMG; only BHP and FMG also mention a dividend ("divid" matches by prefix)
+	results, err := svc.SearchReports(context.Background(), "iron ORE divid", interfaces.ReportSearchOptions{})
+	if err != nil {
+		t.Fatalf("SearchReports: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2: %+v", len(results), results)
+	}
+	for _, r := range results {
+		if r.Ticker != "BHP.AU" && r.Ticker != "FMG.AU" {
+			t.Errorf("unexpected match %s", r.Ticker)
+		}
+	}
+
+	// A ticker in the section title outranks body mentions
+	results, err = svc.SearchReports(context.Background(), "bhp iron", interfaces.ReportSearchOptions{Portfolio: "smsf"})
+	if err != nil {
+		t.Fatalf("SearchReports: %v", err)
+	}
+	if len(results) != 1 || results[0].Ticker != "BHP.AU" || results[0].Score <= 2 {
+		t.Errorf("results = %+v, want BHP.AU with title-weighted score", results)
+	}
+}
+
+func TestSearchReports_NoMatch(t *testing.T) {
+	svc := newSearchTestService(t)
+
+	results, err := svc.SearchReports(context.Background(), "lithium", interfaces.ReportSearchOptions{})
+	if err != nil {
+		t.Fatalf("SearchReports: %v", err)
+	}
+	if results == nil || len(results) != 0 {
+		t.Errorf("results = %v, want empty non-nil slice", results)
+	}
+
+	// Every term must match: "margin" alone hits CBA, "margin lithium" hits nothing
+	results, _ = svc.SearchReports(context.Background(), "margin lithium", interfaces.ReportSearchOptions{})
+	if len(results) != 0 {
+		t.Errorf("AND query matched %+v", results)
+	}
+
+	if _, err := svc.SearchReports(context.Background(), "  -- ", interfaces.ReportSearchOptions{}); err == nil {
+		t.Error("expected an error for a query with no terms")
+	}
+}
@@ -47,7 +47,8 @@ func (s *Service) GetReport(ctx context.Context, portfolioName string) (*models.
 
 // GenerateReport runs the fast pipeline: sync, collect core data, review, format, store
 func (s *Service) GenerateReport(ctx context.Context, portfolioName string, options interfaces.ReportOptions) (*models.PortfolioReport, error) {
-	s.logger.Info().Str("portfolio", portfolioName).Msg("Generating portfolio report")
+	logger := s.logger.WithRequestID(ctx)
+	logger.Info().Str("portfolio", portfolioName).Msg("Generating portfolio report")
 
 	// Step 1: Sync portfolio
 	portfolio, err := s.portfolio.SyncPortfolio(ctx, portfolioName, options.ForceRefresh)
@@ -63,7 +64,7 @@ func (s *Service) GenerateReport(ctx context.Context, portfolioName string, opti
 
 	// Step 2: Collect core market data (fast path — EOD + fundamentals only)
 	if err := s.market.CollectCoreMarketData(ctx, tickers, options.ForceRefresh); err != nil {
-		s.logger.Warn().Err(err).Msg("Core market data collection had errors (continuing)")
+		logger.Warn().Err(err).Msg("Core market data collection had errors (continuing)")
 	}
 
 	// Step 3: Review portfolio
@@ -83,7 +84,7 @@ func (s *Service) GenerateReport(ctx context.Context, portfolioName string, opti
 		return nil, fmt.Errorf("save report: %w", err)
 	}
 
-	s.logger.Info().
+	logger.Info().
 		Str("portfolio", portfolioName).
 		Int("tickers", len(report.TickerReports)).
 		Msg("Report generated and stored")
@@ -93,7 +94,8 @@ func (s *Service) GenerateReport(ctx context.Context, portfolioName string, opti
 
 // GenerateTickerReport refreshes a single ticker's report
 func (s *Service) GenerateTickerReport(ctx context.Context, portfolioName, ticker string) (*models.PortfolioReport, error) {
-	s.logger.Info().Str("portfolio", portfolioName).Str("ticker", ticker).Msg("Regenerating ticker report")
+	logger := s.logger.WithRequestID(ctx)
+	logger.Info().Str("portfolio", portfolioName).Str("ticker", ticker).Msg("Regenerating ticker report")
 
 	// Load existing report
 	existing, err := s.getReportRecord(ctx, portfolioName)
@@ -115,10 +117,10 @@ func (s *Service) GenerateTickerReport(ctx context.Context, portfolioName, ticke
 
 	// Collect + detect for just this ticker
 	if err := s.market.CollectCoreMarketData(ctx, []string{eodhdTicker}, false); err != nil {
-		s.logger.Warn().Err(err).Str("ticker", ticker).Msg("Market data collection had errors")
+		logger.Warn().Err(err).Str("ticker", ticker).Msg("Market data collection had errors")
 	}
 	if _, err := s.signal.DetectSignals(ctx, []string{eodhdTicker}, nil, false); err != nil {
-		s.logger.Warn().Err(err).Str("ticker", ticker).Msg("Signal detection had errors")
+		logger.Warn().Err(err).Str("ticker", ticker).Msg("Signal detection had errors")
 	}
 
 	// Run full review (needs portfolio context for weights/actions)
@@ -169,6 +171,7 @@ func (s *Service) GenerateTickerReport(ctx context.Context, portfolioName, ticke
 
 	// Regenerate summary with updated review data
 	existing.SummaryMarkdown = formatReportSummary(review)
+	existing.Waterfall = review.Waterfall
 	existing.GeneratedAt = time.Now()
 
 	// Save back
@@ -176,7 +179,7 @@ func (s *Service) GenerateTickerReport(ctx context.Context, portfolioName, ticke
 		return nil, fmt.Errorf("save report: %w", err)
 	}
 
-	s.logger.Info().Str("portfolio", portfolioName).Str("ticker", ticker).Msg("Ticker report regenerated")
+	logger.Info().Str("portfolio", portfolioName).Str("ticker", ticker).Msg("Ticker report regenerated")
 	return existing, nil
 }
 
@@ -231,6 +234,7 @@ func (s *Service) buildReport(portfolioName string, review *models.PortfolioRevi
 		SummaryMarkdown: formatReportSummary(review),
 		TickerReports:   tickerReports,
 		Tickers:         tickerNames,
+		Waterfall:       review.Waterfall,
 	}
 }
 
@@ -0,0 +1,113 @@
+package signal
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/interfaces"
+	"github.com/bobmcallan/vire/internal/models"
+	"github.com/bobmcallan/vire/internal/signals"
+)
+
+// backtestMinHistory is the number of bars needed before SMA20/SMA50
+// crossover detection produces a result.
+const backtestMinHistory = 51
+
+// BacktestThresholds replays the entry-signal logic over stored EOD bars and
+// reports how often each "ENTRY CRITERIA MET" trigger was followed by a gain
+// over horizon trading days. Only bars with a full forward window are scored.
+// The RSI period and oversold threshold come from the strategy of the
+// portfolio holding ticker, as for live signals, so the backtest scores the
+// rule the portfolio review applies. Missing market data wraps
+// interfaces.ErrMarketDataNotFound.
+func (s *Service) BacktestThresholds(ctx context.Context, ticker string, horizon int) (*models.SignalBacktest, error) {
+	if horizon <= 0 {
+		return nil, fmt.Errorf("horizon must be positive, got %d", horizon)
+	}
+
+	marketData, err := s.storage.MarketDataStorage().GetMarketData(ctx, ticker)
+	if err != nil {
+		return nil, fmt.Errorf("market data unavailable for %s: %w", ticker, err)
+	}
+	if marketData == nil || len(marketData.EOD) == 0 {
+		return nil, fmt.Errorf("no EOD history for %s: %w", ticker, interfaces.ErrMarketDataNotFound)
+	}
+
+	result := backtestEntrySignals(ticker, marketData.EOD, horizon, s.rsiSettingsFor(ctx, ticker))
+	s.logger.WithRequestID(ctx).Debug().Str("ticker", ticker).Int("horizon", horizon).
+		Int("bars_evaluated", result.BarsEvaluated).Msg("Signal backtest complete")
+	return result, nil
+}
+
+// backtestAccumulator tallies occurrences, hits and forward returns for one signal.
+type backtestAccumulator struct {
+	occurrences int
+	hits        int
+	sumReturn   float64
+}
+
+func (a *backtestAccumulator) add(forwardReturnPct float64) {
+	a.occurrences++
+	a.sumReturn += forwardReturnPct
+	if forwardReturnPct > 0 {
+		a.hits++
+	}
+}
+
+func (a *backtestAccumulator) result(signal, threshold string) models.SignalBacktestResult {
+	r := models.SignalBacktestResult{
+		Signal:          signal,
+		Occurrences:     a.occurrences,
+		Hits:            a.hits,
+		ThresholdDetail: threshold,
+	}
+	if a.occurrences > 0 {
+		r.HitRatePct = float64(a.hits) / float64(a.occurrences) * 100
+		r.AvgReturnPct = a.sumReturn / float64(a.occurrences)
+	}
+	return r
+}
+
+// backtestEntrySignals scores entry signals across bars (newest first).
+// For each historical bar i, indicators are computed from bars[i:] only (no
+// look-ahead) and the forward return is measured against bars[i-horizon].
+func backtestEntrySignals(ticker string, bars []models.EODBar, horizon int, rsi rsiSettings) *models.SignalBacktest {
+	var rsiOversold, goldenCross, entry backtestAccumulator
+	evaluated := 0
+
+	for i := horizon; i <= len(bars)-backtestMinHistory; i++ {
+		window := bars[i:]
+		entryPrice := window[0].Close
+		if entryPrice <= 0 {
+			continue
+		}
+		forwardReturnPct := (bars[i-horizon].Close - entryPrice) / entryPrice * 100
+		evaluated++
+
+		rsiHit := signals.RSI(window, rsi.period) < rsi.oversold
+		crossHit := signals.DetectCrossover(window, 20, 50) == "golden_cross"
+
+		if rsiHit {
+			rsiOversold.add(forwardReturnPct)
+		}
+		if crossHit {
+			goldenCross.add(forwardReturnPct)
+		}
+		if rsiHit || crossHit {
+			entry.add(forwardReturnPct)
+		}
+	}
+
+	return &models.SignalBacktest{
+		Ticker:        ticker,
+		HorizonDays:   horizon,
+		BarsEvaluated: evaluated,
+		Results: []models.SignalBacktestResult{
+			rsiOversold.result("rsi_oversold", fmt.Sprintf("RSI(%d) < %.0f", rsi.period, rsi.oversold)),
+			goldenCross.result("golden_cross", "SMA20 crosses above SMA50"),
+			entry.result("entry_criteria", "rsi_oversold OR golden_cross"),
+		},
+		ComputeTimestamp: time.Now(),
+	}
+}
@@ -0,0 +1,171 @@
+package signal
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/interfaces"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// buildBacktestBars builds a newest-first series from chronological closes.
+func buildBacktestBars(closes []float64) []models.EODBar {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	bars := make([]models.EODBar, len(closes))
+	for i, c := range closes {
+		bars[len(closes)-1-i] = models.EODBar{
+			Date:  start.AddDate(0, 0, i),
+			Open:  c,
+			High:  c,
+			Low:   c,
+			Close: c,
+		}
+	}
+	return bars
+}
+
+// backtestSeries: 60-day decline 200→141, 10 flat days at 141, then 5 days at 150.
+// With horizon 5, chronological days 50..69 are scored (20 bars). RSI stays at 0
+// throughout (no gains), so every scored bar is an rsi_oversold occurrence.
+// Only days 65..69 have the jump to 150 inside their forward window → 5 hits.
+func backtestSeries() []float64 {
+	var closes []float64
+	for i := 0; i < 60; i++ {
+		closes = append(closes, 200-float64(i))
+	}
+	for i := 0; i < 10; i++ {
+		closes = append(closes, 141)
+	}
+	for i := 0; i < 5; i++ {
+		closes = append(closes, 150)
+	}
+	return closes
+}
+
+func findBacktestResult(t *testing.T, bt *models.SignalBacktest, signal string) models.SignalBacktestResult {
+	t.Helper()
+	for _, r := range bt.Results {
+		if r.Signal == signal {
+			return r
+		}
+	}
+	t.Fatalf("result for signal %q not found", signal)
+	return models.SignalBacktestResult{}
+}
+
+func TestBacktestEntrySignals_HitRate(t *testing.T) {
+	bt := backtestEntrySignals("TEST.AU", buildBacktestBars(backtestSeries()), 5, defaultRSISettings())
+
+	if bt.BarsEvaluated != 20 {
+		t.Errorf("BarsEvaluated = %d, want 20", bt.BarsEvaluated)
+	}
+	if bt.HorizonDays != 5 {
+		t.Errorf("HorizonDays = %d, want 5", bt.HorizonDays)
+	}
+
+	rsi := findBacktestResult(t, bt, "rsi_oversold")
+	if rsi.Occurrences != 20 {
+		t.Errorf("rsi_oversold occurrences = %d, want 20", rsi.Occurrences)
+	}
+	if rsi.Hits != 5 {
+		t.Errorf("rsi_oversold hits = %d, want 5", rsi.Hits)
+	}
+	if rsi.HitRatePct != 25 {
+		t.Errorf("rsi_oversold hit rate = %.2f%%, want 25%%", rsi.HitRatePct)
+	}
+
+	cross := findBacktestResult(t, bt, "golden_cross")
+	if cross.Occurrences != 0 || cross.HitRatePct != 0 {
+		t.Errorf("golden_cross = %d occurrences / %.2f%%, want 0 / 0%%", cross.Occurrences, cross.HitRatePct)
+	}
+
+	entry := findBacktestResult(t, bt, "entry_criteria")
+	if entry.Occurrences != 20 || entry.Hits != 5 {
+		t.Errorf("entry_criteria = %d/%d, want 5/20", entry.Hits, entry.Occurrences)
+	}
+}
+
+func TestBacktestEntrySignals_InsufficientHistory(t *testing.T) {
+	bt := backtestEntrySignals("TEST.AU", buildBacktestBars(backtestSeries()[:40]), 5, defaultRSISettings())
+	if bt.BarsEvaluated != 0 {
+		t.Errorf("BarsEvaluated = %d, want 0 for short series", bt.BarsEvaluated)
+	}
+	for _, r := range bt.Results {
+		if r.Occurrences != 0 || r.HitRatePct != 0 {
+			t.Errorf("%s: occurrences=%d hit_rate=%.2f, want zero", r.Signal, r.Occurrences, r.HitRatePct)
+		}
+	}
+}
+
+func TestBacktestThresholds_LoadsStoredBars(t *testing.T) {
+	storage := &mockStorageManager{
+		marketStorage: &mockMarketDataStorage{data: map[string]*models.MarketData{
+			"TEST.AU": {Ticker: "TEST.AU", EOD: buildBacktestBars(backtestSeries())},
+		}},
+	}
+	svc := NewService(storage, nil, common.NewLogger("error"))
+
+	bt, err := svc.BacktestThresholds(context.Background(), "TEST.AU", 5)
+	if err != nil {
+		t.Fatalf("BacktestThresholds failed: %v", err)
+	}
+	if entry := findBacktestResult(t, bt, "entry_criteria"); entry.HitRatePct != 25 {
+		t.Errorf("entry_criteria hit rate = %.2f%%, want 25%%", entry.HitRatePct)
+	}
+
+	if _, err := svc.BacktestThresholds(context.Background(), "TEST.AU", 0); err == nil {
+		t.Error("expected error for non-positive horizon")
+	}
+	if _, err := svc.BacktestThresholds(context.Background(), "MISSING.AU", 5); err == nil {
+		t.Error("expected error for missing market data")
+	}
+}
+
+func TestBacktestThresholds_EmptyHistoryIsNotFound(t *testing.T) {
+	storage := &mockStorageManager{
+		marketStorage: &mockMarketDataStorage{data: map[string]*models.MarketData{
+			"EMPTY.AU": {Ticker: "EMPTY.AU"},
+		}},
+	}
+	svc := NewService(storage, nil, common.NewLogger("error"))
+
+	_, err := svc.BacktestThresholds(context.Background(), "EMPTY.AU", 5)
+	if !errors.Is(err, interfaces.ErrMarketDataNotFound) {
+		t.Errorf("err = %v, want ErrMarketDataNotFound", err)
+	}
+}
+
+func TestBacktestThresholds_UsesOwningPortfolioStrategy(t *testing.T) {
+	userData := &memUserDataStore{}
+	userData.put(t, "portfolio", "SMSF", models.Portfolio{Name: "SMSF", Holdings: []models.Holding{{Ticker: "BHP", Exchange: "AU", Units: 100}}})
+	userData.put(t, "strategy", "SMSF", models.PortfolioStrategy{
+		PortfolioName: "SMSF",
+		RSIPeriod:     21,
+		RiskAppetite:  models.RiskAppetite{Level: "aggressive"},
+	})
+	bars := buildBacktestBars(backtestSeries())
+	storage := &mockStorageManager{
+		userData: userData,
+		marketStorage: &mockMarketDataStorage{data: map[string]*models.MarketData{
+			"BHP.AU": {Ticker: "BHP.AU", EOD: bars},
+			"CBA.AU": {Ticker: "CBA.AU", EOD: bars},
+		}},
+	}
+	svc := NewService(storage, nil, common.NewLogger("error"))
+
+	for ticker, want := range map[string]string{
+		"BHP.AU": "RSI(21) < 25", // SMSF's strategy: period 21, aggressive entry
+		"CBA.AU": "RSI(14) < 30", // held by no portfolio: the defaults
+	} {
+		bt, err := svc.BacktestThresholds(context.Background(), ticker, 5)
+		if err != nil {
+			t.Fatalf("BacktestThresholds(%s): %v", ticker, err)
+		}
+		if got := findBacktestResult(t, bt, "rsi_oversold").ThresholdDetail; got != want {
+			t.Errorf("%s: threshold = %q, want %q", ticker, got, want)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package signal
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// userTickersTTL is how long the per-user ticker maps are reused. A job run
+// computes signals for many tickers back to back; each run resolves the
+// portfolios, strategies and watchlists once instead of once per ticker.
+const userTickersTTL = time.Minute
+
+// rsiSettings is the RSI configuration from the strategy of the portfolio
+// that owns a ticker.
+type rsiSettings struct {
+	period   int
+	oversold float64 // entry threshold for the strategy's risk appetite
+}
+
+// defaultRSISettings applies to tickers no portfolio holds.
+func defaultRSISettings() rsiSettings {
+	_, oversold := (*models.PortfolioStrategy)(nil).RSIThresholds()
+	return rsiSettings{period: models.DefaultRSIPeriod, oversold: oversold}
+}
+
+// userTickerCache holds, for one user, the resolved RSI settings per held
+// EODHD ticker and the set of watchlisted EODHD tickers.
+type userTickerCache struct {
+	mu          sync.Mutex
+	userID      string
+	loadedAt    time.Time
+	rsi         map[string]rsiSettings
+	watchlisted map[string]bool
+}
+
+// userTickers returns the user's held-ticker RSI settings and watchlisted
+// tickers, reloading them when older than userTickersTTL.
+func (s *Service) userTickers(ctx context.Context) (map[string]rsiSettings, map[string]bool) {
+	userID := common.ResolveUserID(ctx)
+
+	s.tickerCache.mu.Lock()
+	defer s.tickerCache.mu.Unlock()
+	c := &s.tickerCache
+	if c.rsi == nil || c.userID != userID || time.Since(c.loadedAt) > userTickersTTL {
+		c.rsi = s.loadRSISettings(ctx, userID)
+		c.watchlisted = s.loadWatchlisted(ctx, userID)
+		c.userID = userID
+		c.loadedAt = time.Now()
+	}
+	return c.rsi, c.watchlisted
+}
+
+// rsiSettingsFor returns the RSI settings from the strategy of the first
+// portfolio (by name) holding ticker, or the defaults when no portfolio
+// holds it or that portfolio has no strategy.
+func (s *Service) rsiSettingsFor(ctx context.Context, ticker string) rsiSettings {
+	held, _ := s.userTickers(ctx)
+	if settings, ok := held[strings.ToUpper(ticker)]; ok {
+		return settings
+	}
+	return defaultRSISettings()
+}
+
+// rsiPeriodFor returns the RSI period from rsiSettingsFor.
+func (s *Service) rsiPeriodFor(ctx context.Context, ticker string) int {
+	return s.rsiSettingsFor(ctx, ticker).period
+}
+
+// isHeldOrWatchlisted reports whether one of the user's portfolios holds
+// ticker or has it on its watchlist.
+func (s *Service) isHeldOrWatchlisted(ctx context.Context, ticker string) bool {
+	rsi, watchlisted := s.userTickers(ctx)
+	ticker = strings.ToUpper(ticker)
+	_, held := rsi[ticker]
+	return held || watchlisted[ticker]
+}
+
+// loadWatchlisted lists the user's watchlists once and returns the set of
+// EODHD tickers on any of them.
+func (s *Service) loadWatchlisted(ctx context.Context, userID string) map[string]bool {
+	tickers := make(map[string]bool)
+	store := s.storage.UserDataStore()
+	if store == nil {
+		return tickers
+	}
+	records, err := store.List(ctx, userID, "watchlist")
+	if err != nil {
+		s.logger.WithRequestID(ctx).Warn().Err(err).Msg("Failed to list watchlists for news sentiment")
+		return tickers
+	}
+	for _, rec := range records {
+		var wl models.PortfolioWatchlist
+		if err := json.Unmarshal([]byte(rec.Value), &wl); err != nil {
+			continue
+		}
+		for _, item := range wl.Items {
+			tickers[strings.ToUpper(item.Ticker)] = true
+		}
+	}
+	return tickers
+}
+
+// loadRSISettings lists the user's portfolios once and maps each open
+// holding's ticker to its portfolio's RSI settings. Portfolios are visited
+// by name, so the first portfolio holding a ticker decides its settings.
+func (s *Service) loadRSISettings(ctx context.Context, userID string) map[string]rsiSettings {
+	held := make(map[string]rsiSettings)
+	store := s.storage.UserDataStore()
+	if store == nil {
+		return held
+	}
+	records, err := store.List(ctx, userID, "portfolio")
+	if err != nil {
+		s.logger.WithRequestID(ctx).Warn().Err(err).Msg("Failed to list portfolios for RSI settings; using the defaults")
+		return held
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Key < records[j].Key })
+
+	for _, rec := range records {
+		var p models.Portfolio
+		if err := json.Unmarshal([]byte(rec.Value), &p); err != nil {
+			continue
+		}
+		settings := defaultRSISettings()
+		if srec, err := store.Get(ctx, userID, "strategy", rec.Key); err == nil {
+			if strategy, _, err := models.DecodeStrategy([]byte(srec.Value), false); err == nil {
+				settings.period = strategy.GetRSIPeriod()
+				_, settings.oversold = strategy.RSIThresholds()
+			}
+		}
+		for _, h := range p.Holdings {
+			ticker := strings.ToUpper(h.EODHDTicker())
+			if _, seen := held[ticker]; h.Units > 0 && !seen {
+				held[ticker] = settings
+			}
+		}
+	}
+	return held
+}
@@ -0,0 +1,132 @@
+package signal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/interfaces"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// memUserDataStore is an in-memory UserDataStore keyed by subject and key
+// that counts portfolio listings.
+type memUserDataStore struct {
+	interfaces.UserDataStore
+	records map[string]map[string]*models.UserRecord
+	lists   int
+}
+
+func (m *memUserDataStore) put(t *testing.T, subject, key string, v interface{}) {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.records == nil {
+		m.records = make(map[string]map[string]*models.UserRecord)
+	}
+	if m.records[subject] == nil {
+		m.records[subject] = make(map[string]*models.UserRecord)
+	}
+	m.records[subject][key] = &models.UserRecord{UserID: "default", Subject: subject, Key: key, Value: string(data)}
+}
+
+func (m *memUserDataStore) Get(_ context.Context, _, subject, key string) (*models.UserRecord, error) {
+	if rec, ok := m.records[subject][key]; ok {
+		return rec, nil
+	}
+	return nil, fmt.Errorf("not found")
+}
+
+func (m *memUserDataStore) List(_ context.Context, _, subject string) ([]*models.UserRecord, error) {
+	if subject == "portfolio" {
+		m.lists++
+	}
+	var out []*models.UserRecord
+	for _, rec := range m.records[subject] {
+		out = append(out, rec)
+	}
+	return out, nil
+}
+
+func TestComputeSignals_UsesOwningPortfolioRSIPeriod(t *testing.T) {
+	bars := make([]models.EODBar, 60)
+	for i := range bars {
+		bars[i] = models.EODBar{Date: time.Now().AddDate(0, 0, -i), Close: 100 + float64(i%7)}
+	}
+	userData := &memUserDataStore{}
+	userData.put(t, "portfolio", "Growth", models.Portfolio{Name: "Growth", Holdings: []models.Holding{{Ticker: "WES", Exchange: "AU", Units: 10}}})
+	userData.put(t, "portfolio", "SMSF", models.Portfolio{Name: "SMSF", Holdings: []models.Holding{{Ticker: "BHP", Exchange: "AU", Units: 100}}})
+	userData.put(t, "strategy", "SMSF", models.PortfolioStrategy{PortfolioName: "SMSF", RSIPeriod: 21})
+
+	svc := NewService(&mockStorageManager{userData: userData}, nil, common.NewLogger("error"))
+	md := &models.MarketData{EOD: bars}
+	if svc.computer.ComputeWithRSIPeriod(md, 21).Technical.RSI == svc.computer.ComputeWithRSIPeriod(md, 14).Technical.RSI {
+		t.Fatal("fixture does not distinguish RSI periods 14 and 21")
+	}
+
+	for ticker, want := range map[string]int{
+		"BHP.AU": 21, // held by SMSF, whose strategy sets 21
+		"WES.AU": 14, // held by Growth, which has no strategy
+		"CBA.AU": 14, // held by no portfolio
+	} {
+		got, err := svc.ComputeSignals(context.Background(), ticker, md)
+		if err != nil {
+			t.Fatalf("ComputeSignals(%s): %v", ticker, err)
+		}
+		if rsi := svc.computer.ComputeWithRSIPeriod(md, want).Technical.RSI; got.Technical.RSI != rsi {
+			t.Errorf("%s: RSI = %.4f, want %.4f (period %d)", ticker, got.Technical.RSI, rsi, want)
+		}
+	}
+
+	// The portfolios are listed once for the whole run, not per ticker
+	if userData.lists != 1 {
+		t.Errorf("portfolios listed %d times, want 1", userData.lists)
+	}
+}
+
+func TestDetectSignals_RecomputesFreshSignalsForAnotherRSIPeriod(t *testing.T) {
+	bars := make([]models.EODBar, 60)
+	for i := range bars {
+		bars[i] = models.EODBar{Date: time.Now().AddDate(0, 0, -i), Close: 100 + float64(i%7)}
+	}
+	userData := &memUserDataStore{}
+	userData.put(t, "portfolio", "SMSF", models.Portfolio{Name: "SMSF", Holdings: []models.Holding{{Ticker: "BHP", Exchange: "AU", Units: 100}}})
+	userData.put(t, "strategy", "SMSF", models.PortfolioStrategy{PortfolioName: "SMSF", RSIPeriod: 21})
+
+	for _, tt := range []struct {
+		storedPeriod int
+		wantSaves    int
+	}{
+		{storedPeriod: 0, wantSaves: 1},  // stored before the period was recorded: 14
+		{storedPeriod: 14, wantSaves: 1}, // computed for another reader
+		{storedPeriod: 21, wantSaves: 0}, // fresh and matching: reused
+	} {
+		signalStorage := &mockSignalStorage{existing: &models.TickerSignals{
+			Ticker: "BHP.AU", ComputeTimestamp: time.Now(), RSIPeriod: tt.storedPeriod,
+		}}
+		storage := &mockStorageManager{
+			marketStorage: &mockMarketDataStorage{data: map[string]*models.MarketData{
+				"BHP.AU": {Ticker: "BHP.AU", EOD: bars, EODUpdatedAt: time.Now().Add(-time.Hour)},
+			}},
+			signalStorage: signalStorage,
+			userData:      userData,
+		}
+		svc := NewService(storage, nil, common.NewLogger("error"))
+
+		results, err := svc.DetectSignals(context.Background(), []string{"BHP.AU"}, nil, false)
+		if err != nil {
+			t.Fatalf("DetectSignals: %v", err)
+		}
+		if len(signalStorage.saved) != tt.wantSaves {
+			t.Errorf("stored period %d: %d saves, want %d", tt.storedPeriod, len(signalStorage.saved), tt.wantSaves)
+		}
+		if got := results[0].GetRSIPeriod(); got != 21 {
+			t.Errorf("stored period %d: returned signals have RSI period %d, want 21", tt.storedPeriod, got)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package signal
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/interfaces"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+const (
+	// sentimentLookback limits scoring to headlines published this recently.
+	sentimentLookback = 14 * 24 * time.Hour
+	// maxSentimentArticles caps the headlines sent to Gemini, most recent first.
+	maxSentimentArticles = 20
+)
+
+// SetGeminiClient enables news sentiment scoring. Without a client,
+// ScoreNewsSentiment leaves signals unscored.
+func (s *Service) SetGeminiClient(gemini interfaces.GeminiClient) {
+	s.gemini = gemini
+}
+
+// SetNewsSentiment turns news sentiment scoring on or off (default on).
+func (s *Service) SetNewsSentiment(enabled bool) {
+	s.newsSentiment = enabled
+}
+
+// ScoreNewsSentiment sets sigs.NewsSentiment from Gemini scores of the
+// ticker's recent headlines. It only scores fresh news for a ticker one of
+// the user's portfolios holds or watchlists; otherwise the fields are left
+// unset. A score already computed from the same headlines (matching
+// NewsSentimentHash) is reused instead of calling Gemini again. Failures are
+// logged, not returned.
+func (s *Service) ScoreNewsSentiment(ctx context.Context, sigs *models.TickerSignals, md *models.MarketData) {
+	logger := s.logger.WithRequestID(ctx)
+	if s.gemini == nil || !s.newsSentiment || sigs == nil || md == nil {
+		return
+	}
+	if !common.IsFresh(md.NewsUpdatedAt, common.FreshnessNews) {
+		logger.Debug().Str("ticker", sigs.Ticker).Msg("News not fresh, skipping sentiment scoring")
+		return
+	}
+	if !s.isHeldOrWatchlisted(ctx, sigs.Ticker) {
+		logger.Debug().Str("ticker", sigs.Ticker).Msg("Ticker not held or watchlisted, skipping sentiment scoring")
+		return
+	}
+
+	now := time.Now()
+	headlines := recentHeadlines(md.News, now)
+	hash := headlinesHash(headlines)
+	if existing, err := s.storage.SignalStorage().GetSignals(ctx, sigs.Ticker); err == nil && existing != nil &&
+		!existing.NewsSentimentAt.IsZero() && existing.NewsSentimentHash == hash {
+		logger.Debug().Str("ticker", sigs.Ticker).Msg("Headlines unchanged, reusing news sentiment")
+		sigs.KeepNewsSentiment(existing)
+		return
+	}
+
+	sigs.NewsSentimentAt = now
+	sigs.NewsSentimentHash = hash
+	sigs.NewsSentiment, sigs.NewsSentimentCount = 0, len(headlines)
+	if len(headlines) < models.MinNewsSentimentArticles {
+		return
+	}
+
+	response, err := s.gemini.GenerateContent(ctx, buildSentimentPrompt(sigs.Ticker, headlines))
+	if err != nil {
+		logger.Warn().Str("ticker", sigs.Ticker).Err(err).Msg("Failed to score news sentiment")
+		sigs.NewsSentimentCount = 0
+		sigs.NewsSentimentAt = time.Time{}
+		sigs.NewsSentimentHash = ""
+		return
+	}
+	score, count, ok := parseSentimentResponse(response, len(headlines))
+	if !ok {
+		logger.Warn().Str("ticker", sigs.Ticker).Msg("Failed to parse news sentiment response")
+		sigs.NewsSentimentCount = 0
+		sigs.NewsSentimentAt = time.Time{}
+		sigs.NewsSentimentHash = ""
+		return
+	}
+	sigs.NewsSentiment, sigs.NewsSentimentCount = score, count
+}
+
+// headlinesHash identifies a set of headlines by their titles and
+// publication dates, so an unchanged set is not scored twice.
+func headlinesHash(headlines []*models.NewsItem) string {
+	h := sha256.New()
+	for _, n := range headlines {
+		fmt.Fprintf(h, "%s\x00%s\n", n.Title, n.PublishedAt.UTC().Format(time.RFC3339))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// recentHeadlines returns the non-empty headlines published within
+// sentimentLookback of now, most recent first, capped at maxSentimentArticles.
+func recentHeadlines(news []*models.NewsItem, now time.Time) []*models.NewsItem {
+	var recent []*models.NewsItem
+	for _, n := range news {
+		if n != nil && strings.TrimSpace(n.Title) != "" && now.Sub(n.PublishedAt) <= sentimentLookback {
+			recent = append(recent, n)
+		}
+	}
+	sort.SliceStable(recent, func(i, j int) bool { return recent[i].PublishedAt.After(recent[j].PublishedAt) })
+	if len(recent) > maxSentimentArticles {
+		recent = recent[:maxSentimentArticles]
+	}
+	return recent
+}
+
+// buildSentimentPrompt asks Gemini to score each numbered headline.
+func buildSentimentPrompt(ticker string, headlines []*models.NewsItem) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Score the sentiment of each news headline for investors holding %s.\n\nHeadlines:\n", ticker))
+	for i, n := range headlines {
+		sb.WriteString(fmt.Sprintf("%d. %s (%s, %s)\n", i+1, n.Title, n.Source, n.PublishedAt.Format("2006-01-02")))
+	}
+	sb.WriteString(`
+Return ONLY valid JSON: {"scores": [{"index": 1, "score": 0.0}]}
+Rules:
+- One entry per headline, using its number as index
+- score is from -1.0 (clearly bad for the share price) to 1.0 (clearly good); 0 for neutral or irrelevant
+- Judge the news itself, not the tone of promotional or clickbait sites
+- No markdown code fences, no explanation`)
+	return sb.String()
+}
+
+// sentimentResponse is the expected JSON shape from Gemini.
+type sentimentResponse struct {
+	Scores []struct {
+		Index int     `json:"index"`
+		Score float64 `json:"score"`
+	} `json:"scores"`
+}
+
+// parseSentimentResponse averages the per-headline scores, clamped to
+// [-1, 1]. Entries with an index outside 1..headlines, duplicates and
+// non-finite scores are ignored. ok is false when nothing valid remains.
+func parseSentimentResponse(response string, headlines int) (float64, int, bool) {
+	response = strings.TrimSpace(response)
+	response = strings.TrimPrefix(response, "```json")
+	response = strings.TrimPrefix(response, "```")
+	response = strings.TrimSuffix(response, "```")
+	response = strings.TrimSpace(response)
+
+	var data sentimentResponse
+	if err := json.Unmarshal([]byte(response), &data); err != nil {
+		return 0, 0, false
+	}
+
+	seen := make(map[int]bool, len(data.Scores))
+	var sum float64
+	for _, sc := range data.Scores {
+		if sc.Index < 1 || sc.Index > headlines || seen[sc.Index] || math.IsNaN(sc.Score) || math.IsInf(sc.Score, 0) {
+			continue
+		}
+		seen[sc.Index] = true
+		sum += math.Max(-1, math.Min(1, sc.Score))
+	}
+	if len(seen) == 0 {
+		return 0, 0, false
+	}
+	return sum / float64(len(seen)), len(seen), true
+}
@@ -0,0 +1,194 @@
+package signal
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// stubGemini answers GenerateContent with a fixed response and counts calls.
+type stubGemini struct {
+	response string
+	err      error
+	calls    int
+	prompt   string
+}
+
+func (g *stubGemini) GenerateContent(_ context.Context, prompt string) (string, error) {
+	g.calls++
+	g.prompt = prompt
+	return g.response, g.err
+}
+func (g *stubGemini) GenerateWithURLContext(ctx context.Context, prompt string, _ ...string) (string, error) {
+	return g.GenerateContent(ctx, prompt)
+}
+func (g *stubGemini) AnalyzeStock(_ context.Context, _ string, _ *models.StockData) (string, error) {
+	return "", nil
+}
+func (g *stubGemini) AnalyzeStream(_ context.Context, _ string) (<-chan string, <-chan error) {
+	return nil, nil
+}
+func (g *stubGemini) SummariseFilingPDF(_ context.Context, _ string, _ string) (string, error) {
+	return "", nil
+}
+func (g *stubGemini) ActiveModels() map[string]string { return nil }
+
+// heldBHP returns a user data store whose SMSF portfolio holds BHP.AU.
+func heldBHP(t *testing.T) *memUserDataStore {
+	t.Helper()
+	userData := &memUserDataStore{}
+	userData.put(t, "portfolio", "SMSF", models.Portfolio{Name: "SMSF", Holdings: []models.Holding{{Ticker: "BHP", Exchange: "AU", Units: 100}}})
+	return userData
+}
+
+func newSentimentService(t *testing.T, gemini *stubGemini) *Service {
+	t.Helper()
+	svc := NewService(&mockStorageManager{signalStorage: &mockSignalStorage{}, userData: heldBHP(t)}, nil, common.NewLogger("error"))
+	svc.SetGeminiClient(gemini)
+	return svc
+}
+
+// freshNews returns market data with n headlines from the last few days,
+// collected an hour ago.
+func freshNews(n int) *models.MarketData {
+	now := time.Now()
+	md := &models.MarketData{Ticker: "BHP.AU", NewsUpdatedAt: now.Add(-time.Hour)}
+	for i := 0; i < n; i++ {
+		md.News = append(md.News, &models.NewsItem{
+			Title:       fmt.Sprintf("Headline %d", i+1),
+			Source:      "Reuters",
+			PublishedAt: now.Add(-time.Duration(i+1) * 12 * time.Hour),
+		})
+	}
+	return md
+}
+
+func TestScoreNewsSentiment_Positive(t *testing.T) {
+	gemini := &stubGemini{response: "```json\n" + `{"scores": [{"index": 1, "score": 0.8}, {"index": 2, "score": 0.6}, {"index": 3, "score": 0.4}]}` + "\n```"}
+	svc := newSentimentService(t, gemini)
+	sigs := &models.TickerSignals{Ticker: "BHP.AU"}
+
+	svc.ScoreNewsSentiment(context.Background(), sigs, freshNews(3))
+
+	if math.Abs(sigs.NewsSentiment-0.6) > 1e-9 || sigs.NewsSentimentCount != 3 {
+		t.Errorf("sentiment = %.3f over %d, want 0.6 over 3", sigs.NewsSentiment, sigs.NewsSentimentCount)
+	}
+	if sigs.NewsSentimentAt.IsZero() {
+		t.Error("NewsSentimentAt not set")
+	}
+	if !strings.Contains(gemini.prompt, "3. Headline 3") {
+		t.Errorf("prompt should number each headline:\n%s", gemini.prompt)
+	}
+}
+
+func TestScoreNewsSentiment_Negative(t *testing.T) {
+	// Out-of-range scores are clamped; unknown and duplicate indexes ignored
+	gemini := &stubGemini{response: `{"scores": [{"index": 1, "score": -2}, {"index": 2, "score": -0.5}, {"index": 3, "score": -0.2}, {"index": 3, "score": 1}, {"index": 9, "score": 1}, {"index": 4, "score": -0.3}]}`}
+	svc := newSentimentService(t, gemini)
+	sigs := &models.TickerSignals{Ticker: "BHP.AU"}
+
+	svc.ScoreNewsSentiment(context.Background(), sigs, freshNews(4))
+
+	if math.Abs(sigs.NewsSentiment-(-0.5)) > 1e-9 || sigs.NewsSentimentCount != 4 {
+		t.Errorf("sentiment = %.3f over %d, want -0.5 over 4", sigs.NewsSentiment, sigs.NewsSentimentCount)
+	}
+}
+
+func TestScoreNewsSentiment_InsufficientArticles(t *testing.T) {
+	gemini := &stubGemini{response: `{"scores": [{"index": 1, "score": -1}, {"index": 2, "score": -1}]}`}
+	svc := newSentimentService(t, gemini)
+	sigs := &models.TickerSignals{Ticker: "BHP.AU"}
+
+	// Two recent headlines plus one a month old, outside the lookback
+	md := freshNews(2)
+	md.News = append(md.News, &models.NewsItem{Title: "Old news", PublishedAt: time.Now().AddDate(0, -1, 0)})
+	svc.ScoreNewsSentiment(context.Background(), sigs, md)
+
+	if gemini.calls != 0 {
+		t.Errorf("Gemini called %d times with too few articles, want 0", gemini.calls)
+	}
+	if sigs.NewsSentiment != 0 || sigs.NewsSentimentCount != 2 {
+		t.Errorf("sentiment = %.3f over %d, want 0 over 2", sigs.NewsSentiment, sigs.NewsSentimentCount)
+	}
+}
+
+func TestScoreNewsSentiment_SkipsStaleNews(t *testing.T) {
+	gemini := &stubGemini{response: `{"scores": [{"index": 1, "score": -1}]}`}
+	svc := newSentimentService(t, gemini)
+	sigs := &models.TickerSignals{Ticker: "BHP.AU"}
+
+	md := freshNews(5)
+	md.NewsUpdatedAt = time.Now().Add(-common.FreshnessNews - time.Hour)
+	svc.ScoreNewsSentiment(context.Background(), sigs, md)
+
+	if gemini.calls != 0 || sigs.NewsSentimentCount != 0 || !sigs.NewsSentimentAt.IsZero() {
+		t.Errorf("stale news: calls = %d, signals = %+v; want no scoring", gemini.calls, sigs)
+	}
+}
+
+func TestScoreNewsSentiment_ReusesScoreForUnchangedHeadlines(t *testing.T) {
+	md := freshNews(5)
+	scoredAt := md.NewsUpdatedAt.Add(-time.Hour) // scored before the latest collection
+	storage := &mockSignalStorage{existing: &models.TickerSignals{
+		Ticker: "BHP.AU", NewsSentiment: -0.7, NewsSentimentCount: 5, NewsSentimentAt: scoredAt,
+		NewsSentimentHash: headlinesHash(recentHeadlines(md.News, time.Now())),
+	}}
+	gemini := &stubGemini{response: `{"scores": [{"index": 1, "score": 1}]}`}
+	svc := NewService(&mockStorageManager{signalStorage: storage, userData: heldBHP(t)}, nil, common.NewLogger("error"))
+	svc.SetGeminiClient(gemini)
+	sigs := &models.TickerSignals{Ticker: "BHP.AU"}
+
+	svc.ScoreNewsSentiment(context.Background(), sigs, md)
+
+	if gemini.calls != 0 {
+		t.Errorf("Gemini called %d times for unchanged headlines, want 0", gemini.calls)
+	}
+	if sigs.NewsSentiment != -0.7 || sigs.NewsSentimentCount != 5 || !sigs.NewsSentimentAt.Equal(scoredAt) {
+		t.Errorf("signals = %+v, want the stored score carried over", sigs)
+	}
+
+	// A new headline changes the hash and is scored
+	md.News = append(md.News, &models.NewsItem{Title: "Breaking", PublishedAt: time.Now().Add(-time.Minute)})
+	svc.ScoreNewsSentiment(context.Background(), sigs, md)
+	if gemini.calls != 1 {
+		t.Errorf("Gemini called %d times after a new headline, want 1", gemini.calls)
+	}
+}
+
+func TestScoreNewsSentiment_OnlyHeldOrWatchlisted(t *testing.T) {
+	userData := heldBHP(t)
+	userData.put(t, "watchlist", "SMSF", models.PortfolioWatchlist{PortfolioName: "SMSF", Items: []models.WatchlistItem{{Ticker: "SGI.AU"}}})
+	gemini := &stubGemini{response: `{"scores": [{"index": 1, "score": 0.5}, {"index": 2, "score": 0.5}, {"index": 3, "score": 0.5}]}`}
+	svc := NewService(&mockStorageManager{signalStorage: &mockSignalStorage{}, userData: userData}, nil, common.NewLogger("error"))
+	svc.SetGeminiClient(gemini)
+
+	for ticker, wantScored := range map[string]bool{"BHP.AU": true, "SGI.AU": true, "CBA.AU": false} {
+		gemini.calls = 0
+		md := freshNews(3)
+		md.Ticker = ticker
+		sigs := &models.TickerSignals{Ticker: ticker}
+		svc.ScoreNewsSentiment(context.Background(), sigs, md)
+		if scored := gemini.calls == 1 && sigs.NewsSentimentCount == 3; scored != wantScored {
+			t.Errorf("%s: scored = %v (calls %d), want %v", ticker, scored, gemini.calls, wantScored)
+		}
+	}
+}
+
+func TestScoreNewsSentiment_Disabled(t *testing.T) {
+	gemini := &stubGemini{response: `{"scores": [{"index": 1, "score": 1}]}`}
+	svc := newSentimentService(t, gemini)
+	svc.SetNewsSentiment(false)
+	sigs := &models.TickerSignals{Ticker: "BHP.AU"}
+
+	svc.ScoreNewsSentiment(context.Background(), sigs, freshNews(5))
+
+	if gemini.calls != 0 || !sigs.NewsSentimentAt.IsZero() {
+		t.Errorf("disabled: calls = %d, signals = %+v; want no scoring", gemini.calls, sigs)
+	}
+}
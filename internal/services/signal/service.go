@@ -17,8 +17,12 @@ import (
 type Service struct {
 	storage  interfaces.StorageManager
 	eodhd    interfaces.EODHDClient
+	gemini   interfaces.GeminiClient // optional: enables news sentiment scoring
 	computer *signals.Computer
 	logger   *common.Logger
+
+	newsSentiment bool // score news sentiment when gemini is set (default true)
+	tickerCache   userTickerCache
 }
 
 // NewService creates a new signal service.
@@ -30,19 +34,27 @@ func NewService(storage interfaces.StorageManager, eodhd interfaces.EODHDClient,
 		eodhd:    eodhd,
 		computer: signals.NewComputer(),
 		logger:   logger,
+
+		newsSentiment: true,
 	}
 }
 
+// SetMACDPeriods sets the MACD fast/slow/signal EMA periods.
+func (s *Service) SetMACDPeriods(fast, slow, signal int) {
+	s.computer.SetMACDPeriods(fast, slow, signal)
+}
+
 // DetectSignals computes signals for tickers.
 // When force is true, signals are recomputed regardless of freshness.
 func (s *Service) DetectSignals(ctx context.Context, tickers []string, signalTypes []string, force bool) ([]*models.TickerSignals, error) {
+	logger := s.logger.WithRequestID(ctx)
 	results := make([]*models.TickerSignals, 0, len(tickers))
 
 	for _, ticker := range tickers {
 		// Get market data
 		marketData, err := s.storage.MarketDataStorage().GetMarketData(ctx, ticker)
 		if err != nil {
-			s.logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to get market data for signal detection")
+			logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to get market data for signal detection")
 			results = append(results, &models.TickerSignals{
 				Ticker:           ticker,
 				ComputeTimestamp: time.Now(),
@@ -51,14 +63,16 @@ func (s *Service) DetectSignals(ctx context.Context, tickers []string, signalTyp
 			continue
 		}
 
-		// Check if existing signals are still fresh (computed after EOD data was updated)
+		// Check if existing signals are still fresh (computed after EOD data
+		// was updated, with the RSI period this user's strategy asks for)
 		if !force {
 			existing, err := s.storage.SignalStorage().GetSignals(ctx, ticker)
 			if err == nil && existing != nil &&
 				!existing.ComputeTimestamp.IsZero() &&
 				existing.ComputeTimestamp.After(marketData.EODUpdatedAt) &&
-				common.IsFresh(existing.ComputeTimestamp, common.FreshnessSignals) {
-				s.logger.Debug().Str("ticker", ticker).Msg("Signals still fresh, skipping recompute")
+				common.IsFresh(existing.ComputeTimestamp, common.FreshnessSignals) &&
+				existing.GetRSIPeriod() == signals.EffectiveRSIPeriod(len(marketData.EOD), s.rsiPeriodFor(ctx, ticker)) {
+				logger.Debug().Str("ticker", ticker).Msg("Signals still fresh, skipping recompute")
 				if len(signalTypes) > 0 {
 					existing = filterSignals(existing, signalTypes)
 				}
@@ -73,7 +87,7 @@ func (s *Service) DetectSignals(ctx context.Context, tickers []string, signalTyp
 		// Compute signals
 		tickerSignals, err := s.ComputeSignals(ctx, ticker, marketData)
 		if err != nil {
-			s.logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to compute signals")
+			logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to compute signals")
 			results = append(results, &models.TickerSignals{
 				Ticker:           ticker,
 				ComputeTimestamp: time.Now(),
@@ -88,8 +102,8 @@ func (s *Service) DetectSignals(ctx context.Context, tickers []string, signalTyp
 		}
 
 		// Save signals
-		if err := s.storage.SignalStorage().SaveSignals(ctx, tickerSignals); err != nil {
-			s.logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to save signals")
+		if err := s.saveSignals(ctx, tickerSignals); err != nil {
+			logger.Warn().Str("ticker", ticker).Err(err).Msg("Failed to save signals")
 		}
 
 		results = append(results, tickerSignals)
@@ -98,14 +112,26 @@ func (s *Service) DetectSignals(ctx context.Context, tickers []string, signalTyp
 	return results, nil
 }
 
-// ComputeSignals calculates all signals for a ticker
+// saveSignals persists sigs, keeping the stored news sentiment when sigs
+// was computed without one.
+func (s *Service) saveSignals(ctx context.Context, sigs *models.TickerSignals) error {
+	store := s.storage.SignalStorage()
+	if sigs == nil {
+		return store.SaveSignals(ctx, sigs)
+	}
+	if prev, err := store.GetSignals(ctx, sigs.Ticker); err == nil {
+		sigs.KeepNewsSentiment(prev)
+	}
+	return store.SaveSignals(ctx, sigs)
+}
+
+// ComputeSignals calculates all signals for a ticker, using the RSI period
+// of the strategy of the portfolio that holds it.
 func (s *Service) ComputeSignals(ctx context.Context, ticker string, marketData *models.MarketData) (*models.TickerSignals, error) {
 	if marketData == nil {
 		return nil, fmt.Errorf("market data is nil for ticker %s", ticker)
 	}
-
-	tickerSignals := s.computer.Compute(marketData)
-	return tickerSignals, nil
+	return s.computer.ComputeWithRSIPeriod(marketData, s.rsiPeriodFor(ctx, ticker)), nil
 }
 
 // overlayLiveQuote fetches a real-time quote and updates the cached EOD bars
@@ -113,13 +139,14 @@ func (s *Service) ComputeSignals(ctx context.Context, ticker string, marketData
 // potentially stale end-of-day data. This is non-fatal: if the quote fetch
 // fails or the EODHD client is nil, we proceed with cached data.
 func (s *Service) overlayLiveQuote(ctx context.Context, ticker string, md *models.MarketData) {
+	logger := s.logger.WithRequestID(ctx)
 	if s.eodhd == nil || md == nil || len(md.EOD) == 0 {
 		return
 	}
 
 	quote, err := s.eodhd.GetRealTimeQuote(ctx, ticker)
 	if err != nil {
-		s.logger.Debug().Str("ticker", ticker).Err(err).Msg("Live quote fetch failed, using cached EOD data")
+		logger.Debug().Str("ticker", ticker).Err(err).Msg("Live quote fetch failed, using cached EOD data")
 		return
 	}
 	if quote == nil || quote.Close <= 0 || math.IsNaN(quote.Close) || math.IsInf(quote.Close, 0) {
@@ -138,7 +165,7 @@ func (s *Service) overlayLiveQuote(ctx context.Context, ticker string, md *model
 		if quote.Low > 0 && quote.Low < md.EOD[0].Low {
 			md.EOD[0].Low = quote.Low
 		}
-		s.logger.Debug().Str("ticker", ticker).Float64("price", quote.Close).Msg("Overlaid live quote on today's bar")
+		logger.Debug().Str("ticker", ticker).Float64("price", quote.Close).Msg("Overlaid live quote on today's bar")
 	} else if latestBarDate.Before(today) {
 		// Previous day: prepend a synthetic bar for today
 		syntheticBar := models.EODBar{
@@ -155,7 +182,7 @@ func (s *Service) overlayLiveQuote(ctx context.Context, ticker string, md *model
 			syntheticBar.Open = quote.PreviousClose
 		}
 		md.EOD = append([]models.EODBar{syntheticBar}, md.EOD...)
-		s.logger.Debug().Str("ticker", ticker).Float64("price", quote.Close).Msg("Prepended synthetic bar with live quote")
+		logger.Debug().Str("ticker", ticker).Float64("price", quote.Close).Msg("Prepended synthetic bar with live quote")
 	}
 }
 
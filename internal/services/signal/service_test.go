@@ -3,6 +3,7 @@ package signal
 import (
 	"context"
 	"errors"
+	"io"
 	"testing"
 	"time"
 
@@ -29,6 +30,9 @@ func (m *mockEODHDClient) GetEOD(_ context.Context, _ string, _ ...interfaces.EO
 func (m *mockEODHDClient) GetBulkRealTimeQuotes(_ context.Context, _ []string) (map[string]*models.RealTimeQuote, error) {
 	return nil, nil
 }
+func (m *mockEODHDClient) GetRealTimeQuotesBatch(_ context.Context, _ []string) (map[string]*models.RealTimeQuote, error) {
+	return nil, nil
+}
 func (m *mockEODHDClient) GetBulkEOD(_ context.Context, _ string, _ []string) (map[string]models.EODBar, error) {
 	return nil, nil
 }
@@ -62,10 +66,11 @@ func (m *mockEODHDClient) GetDividends(_ context.Context, _ string, _, _ time.Ti
 type mockStorageManager struct {
 	marketStorage *mockMarketDataStorage
 	signalStorage *mockSignalStorage
+	userData      interfaces.UserDataStore
 }
 
 func (m *mockStorageManager) InternalStore() interfaces.InternalStore         { return nil }
-func (m *mockStorageManager) UserDataStore() interfaces.UserDataStore         { return nil }
+func (m *mockStorageManager) UserDataStore() interfaces.UserDataStore         { return m.userData }
 func (m *mockStorageManager) MarketDataStorage() interfaces.MarketDataStorage { return m.marketStorage }
 func (m *mockStorageManager) SignalStorage() interfaces.SignalStorage         { return m.signalStorage }
 func (m *mockStorageManager) StockIndexStore() interfaces.StockIndexStore     { return nil }
@@ -81,7 +86,13 @@ func (m *mockStorageManager) PurgeDerivedData(_ context.Context) (map[string]int
 	return nil, nil
 }
 func (m *mockStorageManager) PurgeReports(_ context.Context) (int, error) { return 0, nil }
-func (m *mockStorageManager) Close() error                                { return nil }
+func (m *mockStorageManager) Backup(_ context.Context, _ io.Writer) (*models.BackupManifest, error) {
+	return nil, nil
+}
+func (m *mockStorageManager) Restore(_ context.Context, _ io.Reader) (*models.BackupManifest, error) {
+	return nil, nil
+}
+func (m *mockStorageManager) Close() error { return nil }
 
 type mockMarketDataStorage struct {
 	data map[string]*models.MarketData
@@ -108,10 +119,14 @@ func (m *mockMarketDataStorage) GetStaleTickers(_ context.Context, _ string, _ i
 }
 
 type mockSignalStorage struct {
-	saved []*models.TickerSignals
+	saved    []*models.TickerSignals
+	existing *models.TickerSignals // returned by GetSignals when set
 }
 
 func (m *mockSignalStorage) GetSignals(_ context.Context, _ string) (*models.TickerSignals, error) {
+	if m.existing != nil {
+		return m.existing, nil
+	}
 	return nil, errors.New("not found")
 }
 
@@ -393,6 +408,36 @@ func TestDetectSignals_WithLiveOverlay(t *testing.T) {
 	}
 }
 
+func TestDetectSignals_KeepsStoredNewsSentiment(t *testing.T) {
+	today := time.Now().Truncate(24 * time.Hour)
+	bars := make([]models.EODBar, 50)
+	for i := range bars {
+		bars[i] = models.EODBar{Date: today.AddDate(0, 0, -i), Open: 100, High: 105, Low: 95, Close: 100}
+	}
+	scoredAt := time.Now().Add(-time.Hour)
+	signalStorage := &mockSignalStorage{existing: &models.TickerSignals{
+		Ticker: "BHP.AU", NewsSentiment: -0.3, NewsSentimentCount: 4, NewsSentimentAt: scoredAt,
+	}}
+	storage := &mockStorageManager{
+		marketStorage: &mockMarketDataStorage{data: map[string]*models.MarketData{
+			"BHP.AU": {Ticker: "BHP.AU", EOD: bars},
+		}},
+		signalStorage: signalStorage,
+	}
+	svc := NewService(storage, &mockEODHDClient{quoteErr: errors.New("no quote")}, common.NewLogger("error"))
+
+	if _, err := svc.DetectSignals(context.Background(), []string{"BHP.AU"}, nil, true); err != nil {
+		t.Fatalf("DetectSignals error: %v", err)
+	}
+	if len(signalStorage.saved) != 1 {
+		t.Fatalf("expected 1 save, got %d", len(signalStorage.saved))
+	}
+	saved := signalStorage.saved[0]
+	if saved.NewsSentiment != -0.3 || saved.NewsSentimentCount != 4 || !saved.NewsSentimentAt.Equal(scoredAt) {
+		t.Errorf("saved sentiment = %.2f over %d, want the stored -0.30 over 4", saved.NewsSentiment, saved.NewsSentimentCount)
+	}
+}
+
 func TestDetectSignals_MissingMarketData_ReturnsError(t *testing.T) {
 	// When market data is missing, DetectSignals should return an error entry
 	// instead of silently skipping the ticker
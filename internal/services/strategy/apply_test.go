@@ -0,0 +1,206 @@
+package strategy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/interfaces"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// memUserDataStore is a simple in-memory UserDataStore for tests.
+type memUserDataStore struct {
+	mu      sync.Mutex
+	records map[string]*models.UserRecord
+}
+
+func newMemUserDataStore() *memUserDataStore {
+	return &memUserDataStore{records: make(map[string]*models.UserRecord)}
+}
+
+func (m *memUserDataStore) Get(_ context.Context, userID, subject, key string) (*models.UserRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if r, ok := m.records[userID+":"+subject+":"+key]; ok {
+		return r, nil
+	}
+	return nil, fmt.Errorf("%s '%s' not found", subject, key)
+}
+
+func (m *memUserDataStore) Put(_ context.Context, record *models.UserRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.records[record.UserID+":"+record.Subject+":"+record.Key] = record
+	return nil
+}
+
+func (m *memUserDataStore) Delete(_ context.Context, userID, subject, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.records, userID+":"+subject+":"+key)
+	return nil
+}
+
+func (m *memUserDataStore) List(_ context.Context, userID, subject string) ([]*models.UserRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var result []*models.UserRecord
+	for _, r := range m.records {
+		if r.UserID == userID && r.Subject == subject {
+			result = append(result, r)
+		}
+	}
+	return result, nil
+}
+
+func (m *memUserDataStore) Query(ctx context.Context, userID, subject string, _ interfaces.QueryOptions) ([]*models.UserRecord, error) {
+	return m.List(ctx, userID, subject)
+}
+
+func (m *memUserDataStore) Count(ctx context.Context, userID, subject string, _ interfaces.QueryOptions) (int, error) {
+	records, err := m.List(ctx, userID, subject)
+	return len(records), err
+}
+
+func (m *memUserDataStore) DeleteBySubject(_ context.Context, _ string) (int, error) { return 0, nil }
+func (m *memUserDataStore) Close() error                                             { return nil }
+
+type mockStorageManager struct {
+	userDataStore *memUserDataStore
+}
+
+func (m *mockStorageManager) UserDataStore() interfaces.UserDataStore         { return m.userDataStore }
+func (m *mockStorageManager) InternalStore() interfaces.InternalStore         { return nil }
+func (m *mockStorageManager) MarketDataStorage() interfaces.MarketDataStorage { return nil }
+func (m *mockStorageManager) SignalStorage() interfaces.SignalStorage         { return nil }
+func (m *mockStorageManager) StockIndexStore() interfaces.StockIndexStore     { return nil }
+func (m *mockStorageManager) JobQueueStore() interfaces.JobQueueStore         { return nil }
+func (m *mockStorageManager) FileStore() interfaces.FileStore                 { return nil }
+func (m *mockStorageManager) FeedbackStore() interfaces.FeedbackStore         { return nil }
+func (m *mockStorageManager) ChangelogStore() interfaces.ChangelogStore       { return nil }
+func (m *mockStorageManager) OAuthStore() interfaces.OAuthStore               { return nil }
+func (m *mockStorageManager) TimelineStore() interfaces.TimelineStore         { return nil }
+func (m *mockStorageManager) DataPath() string                                { return "" }
+func (m *mockStorageManager) WriteRaw(_, _ string, _ []byte) error            { return nil }
+func (m *mockStorageManager) PurgeDerivedData(_ context.Context) (map[string]int, error) {
+	return nil, nil
+}
+func (m *mockStorageManager) PurgeReports(_ context.Context) (int, error) { return 0, nil }
+func (m *mockStorageManager) Backup(_ context.Context, _ io.Writer) (*models.BackupManifest, error) {
+	return nil, nil
+}
+func (m *mockStorageManager) Restore(_ context.Context, _ io.Reader) (*models.BackupManifest, error) {
+	return nil, nil
+}
+func (m *mockStorageManager) Close() error { return nil }
+
+func newApplyTestService(portfolios ...string) *Service {
+	store := newMemUserDataStore()
+	for _, name := range portfolios {
+		_ = store.Put(context.Background(), &models.UserRecord{
+			UserID: common.ResolveUserID(context.Background()), Subject: "portfolio", Key: name, Value: "{}",
+		})
+	}
+	return NewService(&mockStorageManager{userDataStore: store}, common.NewLogger("error"))
+}
+
+func rsiRule(name string, op models.RuleOperator, value float64, action models.RuleAction) models.Rule {
+	return models.Rule{
+		Name:       name,
+		Conditions: []models.RuleCondition{{Field: "signals.rsi", Operator: op, Value: value}},
+		Action:     action,
+		Enabled:    true,
+	}
+}
+
+func TestApplyStrategyToPortfolios_UpdatesAll(t *testing.T) {
+	svc := newApplyTestService("SMSF", "Trading")
+	ctx := context.Background()
+
+	// Trading starts with its own thresholds, which should be replaced
+	_, err := svc.SaveStrategy(ctx, &models.PortfolioStrategy{
+		PortfolioName: "Trading",
+		Rules:         []models.Rule{rsiRule("old-oversold", models.RuleOpLT, 20, models.RuleActionBuy)},
+	})
+	if err != nil {
+		t.Fatalf("seed SaveStrategy failed: %v", err)
+	}
+
+	shared := models.PortfolioStrategy{
+		RiskAppetite: models.RiskAppetite{Level: "moderate"},
+		Rules: []models.Rule{
+			rsiRule("rsi-oversold", models.RuleOpLT, 35, models.RuleActionBuy),
+			rsiRule("rsi-overbought", models.RuleOpGT, 65, models.RuleActionSell),
+		},
+	}
+	if _, err := svc.ApplyStrategyToPortfolios(ctx, []string{"SMSF", "Trading"}, shared); err != nil {
+		t.Fatalf("ApplyStrategyToPortfolios failed: %v", err)
+	}
+
+	for _, name := range []string{"SMSF", "Trading"} {
+		got, err := svc.GetStrategy(ctx, name)
+		if err != nil {
+			t.Fatalf("GetStrategy(%s) failed: %v", name, err)
+		}
+		if got.PortfolioName != name {
+			t.Errorf("%s: PortfolioName = %q", name, got.PortfolioName)
+		}
+		if len(got.Rules) != 2 {
+			t.Fatalf("%s: expected 2 rules, got %d", name, len(got.Rules))
+		}
+		if v := got.Rules[0].Conditions[0].Value; v != float64(35) {
+			t.Errorf("%s: oversold RSI threshold = %v, want 35", name, v)
+		}
+		if v := got.Rules[1].Conditions[0].Value; v != float64(65) {
+			t.Errorf("%s: overbought RSI threshold = %v, want 65", name, v)
+		}
+	}
+}
+
+func TestApplyStrategyToPortfolios_MissingPortfolio(t *testing.T) {
+	svc := newApplyTestService("SMSF")
+	ctx := context.Background()
+
+	_, err := svc.ApplyStrategyToPortfolios(ctx, []string{"SMSF", "Ghost"}, models.PortfolioStrategy{})
+	if err == nil {
+		t.Fatal("expected error for missing portfolio")
+	}
+	if _, err := svc.GetStrategy(ctx, "SMSF"); err == nil {
+		t.Error("no strategy should be saved when any portfolio is missing")
+	}
+
+	if _, err := svc.ApplyStrategyToPortfolios(ctx, nil, models.PortfolioStrategy{}); err == nil {
+		t.Error("expected error for empty portfolio list")
+	}
+}
+
+func TestGetStrategy_OldFormatDecodes(t *testing.T) {
+	svc := newApplyTestService()
+	ctx := context.Background()
+	svc.storage.UserDataStore().Put(ctx, &models.UserRecord{
+		UserID:  common.ResolveUserID(ctx),
+		Subject: "strategy",
+		Key:     "SMSF",
+		Value:   `{"portfolio_name":"SMSF","account_type":"smsf","rules":"none","position_sizing":{"max_sector_pct":25}}`,
+	})
+
+	got, err := svc.GetStrategy(ctx, "SMSF")
+	if err != nil {
+		t.Fatalf("GetStrategy failed: %v", err)
+	}
+	if got.AccountType != models.AccountTypeSMSF || got.PositionSizing.MaxSectorPct != 25 {
+		t.Errorf("old settings lost: %+v", got)
+	}
+	if got.Rules != nil || got.CostBasisMethod != models.CostBasisAverage {
+		t.Errorf("rules=%v cost_basis=%q, want reset rules and default cost basis", got.Rules, got.CostBasisMethod)
+	}
+
+	svc.SetStrictDecode(true)
+	if _, err := svc.GetStrategy(ctx, "SMSF"); err == nil {
+		t.Error("expected strict decode to fail")
+	}
+}
@@ -0,0 +1,267 @@
+package strategy
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// Expression is a parsed arithmetic formula over rule fields, used for
+// user-defined derived metrics. Supports + - * /, unary minus, parentheses,
+// numeric literals and field references. Bare names refer to holding fields
+// (market_value == holding.market_value); dotted names are rule field paths
+// (signals.rsi, fundamentals.pe). There are no function calls or any other
+// constructs, so evaluation cannot run arbitrary code.
+type Expression struct {
+	src    string
+	root   exprNode
+	fields []string
+}
+
+type exprNode interface {
+	eval(ctx RuleContext) (float64, error)
+}
+
+type numberNode float64
+
+func (n numberNode) eval(RuleContext) (float64, error) { return float64(n), nil }
+
+type fieldNode string
+
+func (f fieldNode) eval(ctx RuleContext) (float64, error) {
+	v, ok := resolveField(string(f), ctx)
+	if !ok {
+		return 0, fmt.Errorf("field '%s' not available", f)
+	}
+	switch v.(type) {
+	case string, bool:
+		return 0, fmt.Errorf("field '%s' is not numeric", f)
+	}
+	return toFloat64(v), nil
+}
+
+type negNode struct{ x exprNode }
+
+func (n negNode) eval(ctx RuleContext) (float64, error) {
+	v, err := n.x.eval(ctx)
+	return -v, err
+}
+
+type binaryNode struct {
+	op   byte
+	l, r exprNode
+}
+
+func (b binaryNode) eval(ctx RuleContext) (float64, error) {
+	l, err := b.l.eval(ctx)
+	if err != nil {
+		return 0, err
+	}
+	r, err := b.r.eval(ctx)
+	if err != nil {
+		return 0, err
+	}
+	switch b.op {
+	case '+':
+		return l + r, nil
+	case '-':
+		return l - r, nil
+	case '*':
+		return l * r, nil
+	case '/':
+		if r == 0 {
+			return 0, fmt.Errorf("division by zero")
+		}
+		return l / r, nil
+	}
+	return 0, fmt.Errorf("unknown operator '%c'", b.op)
+}
+
+// ParseExpression parses a derived-metric formula such as
+// "market_value / cost_basis".
+func ParseExpression(src string) (*Expression, error) {
+	p := &exprParser{src: src}
+	p.next()
+	root, err := p.parseSum()
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression %q: %w", src, err)
+	}
+	if p.tok != "" {
+		return nil, fmt.Errorf("invalid expression %q: unexpected '%s'", src, p.tok)
+	}
+	return &Expression{src: src, root: root, fields: p.fields}, nil
+}
+
+// Fields returns the full field paths referenced by the expression.
+func (e *Expression) Fields() []string {
+	return e.fields
+}
+
+// Evaluate computes the expression against the rule context.
+func (e *Expression) Evaluate(ctx RuleContext) (float64, error) {
+	v, err := e.root.eval(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, fmt.Errorf("expression %q is not finite", e.src)
+	}
+	return v, nil
+}
+
+// exprParser is a recursive-descent parser:
+//
+//	sum     = product { ("+" | "-") product }
+//	product = unary { ("*" | "/") unary }
+//	unary   = "-" unary | primary
+//	primary = number | field | "(" sum ")"
+type exprParser struct {
+	src    string
+	pos    int
+	tok    string // current token; "" at end of input
+	fields []string
+	err    error
+}
+
+func (p *exprParser) next() {
+	for p.pos < len(p.src) && p.src[p.pos] == ' ' {
+		p.pos++
+	}
+	if p.pos >= len(p.src) {
+		p.tok = ""
+		return
+	}
+	start := p.pos
+	c := p.src[p.pos]
+	switch {
+	case strings.IndexByte("+-*/()", c) >= 0:
+		p.pos++
+	case isDigit(c) || c == '.':
+		for p.pos < len(p.src) && (isDigit(p.src[p.pos]) || p.src[p.pos] == '.') {
+			p.pos++
+		}
+	case isIdentByte(c):
+		for p.pos < len(p.src) && (isIdentByte(p.src[p.pos]) || isDigit(p.src[p.pos]) || p.src[p.pos] == '.') {
+			p.pos++
+		}
+	default:
+		p.err = fmt.Errorf("unexpected character '%c'", c)
+		p.pos = len(p.src)
+	}
+	p.tok = p.src[start:p.pos]
+}
+
+func (p *exprParser) parseSum() (exprNode, error) {
+	left, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+	for p.tok == "+" || p.tok == "-" {
+		op := p.tok[0]
+		p.next()
+		right, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: op, l: left, r: right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseProduct() (exprNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.tok == "*" || p.tok == "/" {
+		op := p.tok[0]
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: op, l: left, r: right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	if p.tok == "-" {
+		p.next()
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return negNode{x: x}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	tok := p.tok
+	switch {
+	case tok == "":
+		return nil, fmt.Errorf("unexpected end of expression")
+	case tok == "(":
+		p.next()
+		x, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		if p.tok != ")" {
+			return nil, fmt.Errorf("missing ')'")
+		}
+		p.next()
+		return x, nil
+	case isDigit(tok[0]) || tok[0] == '.':
+		n, err := strconv.ParseFloat(tok, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number '%s'", tok)
+		}
+		p.next()
+		return numberNode(n), nil
+	case isIdentByte(tok[0]):
+		field := tok
+		if !strings.Contains(field, ".") {
+			field = "holding." + field
+		}
+		p.fields = append(p.fields, field)
+		p.next()
+		return fieldNode(field), nil
+	}
+	return nil, fmt.Errorf("unexpected '%s'", tok)
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+func isIdentByte(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// ComputeDerivedMetrics evaluates each metric for one holding. Metrics that
+// fail to parse or evaluate (missing data, division by zero) are omitted.
+func ComputeDerivedMetrics(metrics []models.DerivedMetric, ctx RuleContext) map[string]float64 {
+	if len(metrics) == 0 {
+		return nil
+	}
+	out := make(map[string]float64, len(metrics))
+	for _, m := range metrics {
+		expr, err := ParseExpression(m.Expression)
+		if err != nil {
+			continue
+		}
+		if v, err := expr.Evaluate(ctx); err == nil {
+			out[m.Name] = v
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
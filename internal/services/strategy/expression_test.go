@@ -0,0 +1,78 @@
+package strategy
+
+import (
+	"math"
+	"testing"
+
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+func TestParseExpression_Evaluate(t *testing.T) {
+	ctx := RuleContext{
+		Holding: &models.Holding{MarketValue: 5000, CostBasis: 4000, Units: 100},
+		Signals: &models.TickerSignals{Technical: models.TechnicalSignals{RSI: 40}},
+	}
+
+	tests := []struct {
+		expr string
+		want float64
+	}{
+		{"market_value / cost_basis", 1.25},
+		{"(market_value - cost_basis) / cost_basis * 100", 25},
+		{"market_value - cost_basis * 2", -3000}, // precedence
+		{"-units + 1", -99},
+		{"holding.units * 2.5", 250},
+		{"signals.rsi / 100", 0.4},
+	}
+	for _, tt := range tests {
+		expr, err := ParseExpression(tt.expr)
+		if err != nil {
+			t.Errorf("ParseExpression(%q): %v", tt.expr, err)
+			continue
+		}
+		got, err := expr.Evaluate(ctx)
+		if err != nil {
+			t.Errorf("Evaluate(%q): %v", tt.expr, err)
+			continue
+		}
+		if math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("Evaluate(%q) = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestParseExpression_Errors(t *testing.T) {
+	for _, bad := range []string{"", "market_value /", "(units", "units $ 2", "os.Exit(1)", "1..2"} {
+		if _, err := ParseExpression(bad); err == nil {
+			t.Errorf("expected parse error for %q", bad)
+		}
+	}
+
+	expr, _ := ParseExpression("market_value / cost_basis")
+	if _, err := expr.Evaluate(RuleContext{Holding: &models.Holding{MarketValue: 10}}); err == nil {
+		t.Error("expected division by zero error")
+	}
+	expr, _ = ParseExpression("fundamentals.sector * 2")
+	if _, err := expr.Evaluate(RuleContext{Fundamentals: &models.Fundamentals{Sector: "Materials"}}); err == nil {
+		t.Error("expected non-numeric field error")
+	}
+}
+
+func TestValidateDerivedMetrics_UnknownField(t *testing.T) {
+	s := &models.PortfolioStrategy{DerivedMetrics: []models.DerivedMetric{
+		{Name: "value_to_cost", Expression: "market_value / cost_basis"},
+		{Name: "bogus", Expression: "market_value / book_value"},
+		{Name: "broken", Expression: "market_value /"},
+	}}
+
+	warnings := validateDerivedMetrics(s)
+	if len(warnings) != 2 {
+		t.Fatalf("expected 2 warnings, got %d: %+v", len(warnings), warnings)
+	}
+	if warnings[0].Field != "derived_metrics[1].expression" || !contains(warnings[0].Message, "holding.book_value") {
+		t.Errorf("unexpected unknown-field warning: %+v", warnings[0])
+	}
+	if warnings[1].Field != "derived_metrics[2].expression" {
+		t.Errorf("unexpected parse warning: %+v", warnings[1])
+	}
+}
@@ -230,6 +230,12 @@ func resolveHoldingField(field string, h *models.Holding) (interface{}, bool) {
 		return h.Units, true
 	case "market_value", "holding_value_market":
 		return h.MarketValue, true
+	case "cost_basis":
+		return h.CostBasis, true
+	case "avg_cost", "holding_cost_avg":
+		return h.AvgCost, true
+	case "current_price":
+		return h.CurrentPrice, true
 	}
 	return nil, false
 }
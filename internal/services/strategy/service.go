@@ -19,6 +19,7 @@ var _ interfaces.StrategyService = (*Service)(nil)
 type Service struct {
 	storage interfaces.StorageManager
 	logger  *common.Logger
+	strict  bool // fail loads on fields an older release stored with a different type
 }
 
 // NewService creates a new strategy service
@@ -29,6 +30,14 @@ func NewService(storage interfaces.StorageManager, logger *common.Logger) *Servi
 	}
 }
 
+// SetStrictDecode controls how stored strategies from older releases are
+// loaded. When strict, a field whose stored type no longer matches fails the
+// load; otherwise the field is dropped and logged. New fields missing from
+// old records are filled with defaults in both modes.
+func (s *Service) SetStrictDecode(strict bool) {
+	s.strict = strict
+}
+
 // GetStrategy retrieves the strategy for a portfolio
 func (s *Service) GetStrategy(ctx context.Context, portfolioName string) (*models.PortfolioStrategy, error) {
 	userID := common.ResolveUserID(ctx)
@@ -36,11 +45,14 @@ func (s *Service) GetStrategy(ctx context.Context, portfolioName string) (*model
 	if err != nil {
 		return nil, fmt.Errorf("failed to get strategy: %w", err)
 	}
-	var strategy models.PortfolioStrategy
-	if err := json.Unmarshal([]byte(rec.Value), &strategy); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal strategy: %w", err)
+	strategy, dropped, err := models.DecodeStrategy([]byte(rec.Value), s.strict)
+	if err != nil {
+		return nil, err
+	}
+	if len(dropped) > 0 {
+		s.logger.WithRequestID(ctx).Warn().Str("portfolio", portfolioName).Strs("fields", dropped).Msg("Strategy fields from an older release could not be decoded and were reset")
 	}
-	return &strategy, nil
+	return strategy, nil
 }
 
 // SaveStrategy saves a strategy and returns devil's advocate warnings
@@ -61,7 +73,7 @@ func (s *Service) SaveStrategy(ctx context.Context, strategy *models.PortfolioSt
 		return nil, fmt.Errorf("failed to save strategy: %w", err)
 	}
 
-	s.logger.Info().
+	s.logger.WithRequestID(ctx).Info().
 		Str("portfolio", strategy.PortfolioName).
 		Int("warnings", len(warnings)).
 		Msg("Strategy saved")
@@ -75,10 +87,65 @@ func (s *Service) DeleteStrategy(ctx context.Context, portfolioName string) erro
 	if err := s.storage.UserDataStore().Delete(ctx, userID, "strategy", portfolioName); err != nil {
 		return fmt.Errorf("failed to delete strategy: %w", err)
 	}
-	s.logger.Info().Str("portfolio", portfolioName).Msg("Strategy deleted")
+	s.logger.WithRequestID(ctx).Info().Str("portfolio", portfolioName).Msg("Strategy deleted")
 	return nil
 }
 
+// ApplyStrategyToPortfolios saves a copy of strategy to each named portfolio,
+// standardising settings (risk appetite, rules, thresholds) across them.
+// Every portfolio is checked for existence before any strategy is written.
+// Each copy keeps its target portfolio's original CreatedAt when one exists.
+func (s *Service) ApplyStrategyToPortfolios(ctx context.Context, names []string, strategy models.PortfolioStrategy) ([]models.StrategyWarning, error) {
+	if len(names) == 0 {
+		return nil, fmt.Errorf("at least one portfolio name is required")
+	}
+
+	userID := common.ResolveUserID(ctx)
+	seen := make(map[string]bool, len(names))
+	var targets, missing []string
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		if _, err := s.storage.UserDataStore().Get(ctx, userID, "portfolio", name); err != nil {
+			missing = append(missing, name)
+			continue
+		}
+		targets = append(targets, name)
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("portfolios not found: %s", strings.Join(missing, ", "))
+	}
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("at least one portfolio name is required")
+	}
+
+	var warnings []models.StrategyWarning
+	for _, name := range targets {
+		applied := strategy
+		applied.PortfolioName = name
+		if existing, err := s.GetStrategy(ctx, name); err == nil {
+			applied.CreatedAt = existing.CreatedAt
+		}
+		w, err := s.SaveStrategy(ctx, &applied)
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply strategy to '%s': %w", name, err)
+		}
+		// Warnings are identical across copies; report them once
+		if warnings == nil {
+			warnings = w
+		}
+	}
+
+	s.logger.WithRequestID(ctx).Info().
+		Strs("portfolios", targets).
+		Msg("Strategy applied to portfolios")
+
+	return warnings, nil
+}
+
 // ValidateStrategy checks for unrealistic goals and internal contradictions.
 // Returns a list of warnings that should be presented to the user as devil's advocate challenges.
 func (s *Service) ValidateStrategy(_ context.Context, strategy *models.PortfolioStrategy) []models.StrategyWarning {
@@ -93,6 +160,7 @@ func (s *Service) ValidateStrategy(_ context.Context, strategy *models.Portfolio
 	warnings = append(warnings, validateSectorConsistency(strategy)...)
 	warnings = append(warnings, validateSanity(strategy)...)
 	warnings = append(warnings, validateRules(strategy)...)
+	warnings = append(warnings, validateDerivedMetrics(strategy)...)
 
 	return warnings
 }
@@ -363,6 +431,42 @@ func validateSanity(s *models.PortfolioStrategy) []models.StrategyWarning {
 			Message:  fmt.Sprintf("Maximum sector allocation of %.1f%% exceeds 100%%. This is not possible without leverage.", s.PositionSizing.MaxSectorPct),
 		})
 	}
+	if s.PositionSizing.MaxHHI > 1 {
+		warnings = append(warnings, models.StrategyWarning{
+			Severity: "high",
+			Field:    "position_sizing.max_hhi",
+			Message:  fmt.Sprintf("Maximum HHI of %.2f exceeds 1.0, the HHI of a single-holding portfolio, so it can never trigger.", s.PositionSizing.MaxHHI),
+		})
+	}
+	if s.PositionSizing.MaxCorrelation >= 1 {
+		warnings = append(warnings, models.StrategyWarning{
+			Severity: "medium",
+			Field:    "position_sizing.max_correlation",
+			Message:  fmt.Sprintf("Maximum correlation of %.2f can never be exceeded; correlations range from -1 to 1.", s.PositionSizing.MaxCorrelation),
+		})
+	}
+	if s.PositionSizing.EarningsWatchPct > 0 && s.PositionSizing.EarningsWindowDays <= 0 {
+		warnings = append(warnings, models.StrategyWarning{
+			Severity: "low",
+			Field:    "position_sizing.earnings_watch_pct",
+			Message:  "Earnings watch threshold is set but earnings_window_days is not, so it has no effect.",
+		})
+	}
+	if s.PositionSizing.StopLossPct >= 100 {
+		warnings = append(warnings, models.StrategyWarning{
+			Severity: "medium",
+			Field:    "position_sizing.stop_loss_pct",
+			Message:  fmt.Sprintf("Stop-loss of %.1f%% can never trigger — a holding cannot lose more than 100%%.", s.PositionSizing.StopLossPct),
+		})
+	}
+
+	if s.RSIPeriod != 0 && s.RSIPeriod < 2 {
+		warnings = append(warnings, models.StrategyWarning{
+			Severity: "medium",
+			Field:    "rsi_period",
+			Message:  fmt.Sprintf("RSI period of %d is too short to measure momentum; the default of %d will be used.", s.RSIPeriod, models.DefaultRSIPeriod),
+		})
+	}
 
 	// Negative values
 	if s.TargetReturns.AnnualPct < 0 {
@@ -398,6 +502,48 @@ func validateSanity(s *models.PortfolioStrategy) []models.StrategyWarning {
 		})
 	}
 
+	switch s.CostBasisMethod {
+	case "", models.CostBasisAverage, models.CostBasisFIFO, models.CostBasisLIFO:
+	default:
+		warnings = append(warnings, models.StrategyWarning{
+			Severity: "high",
+			Field:    "cost_basis_method",
+			Message:  fmt.Sprintf("Unknown cost basis method '%s'. Use 'average', 'fifo' or 'lifo'; average cost will be used.", s.CostBasisMethod),
+		})
+	}
+
+	for _, src := range append([]models.PriceSource{s.PriceSource}, tickerPriceSources(s)...) {
+		switch src {
+		case "", models.PriceSourceAuto, models.PriceSourceNavexa, models.PriceSourceEODHD:
+		default:
+			warnings = append(warnings, models.StrategyWarning{
+				Severity: "high",
+				Field:    "price_source",
+				Message:  fmt.Sprintf("Unknown price source '%s'. Use 'auto', 'navexa' or 'eodhd'; auto will be used.", src),
+			})
+		}
+	}
+
+	// Target weights: negative entries, or totals that cannot be reached
+	var targetTotal float64
+	for ticker, pct := range s.TargetWeights {
+		targetTotal += pct
+		if pct < 0 {
+			warnings = append(warnings, models.StrategyWarning{
+				Severity: "high",
+				Field:    "target_weights",
+				Message:  fmt.Sprintf("Target weight for %s is negative (%.1f%%). Short positions are not supported.", ticker, pct),
+			})
+		}
+	}
+	if len(s.TargetWeights) > 0 && targetTotal > 100.01 {
+		warnings = append(warnings, models.StrategyWarning{
+			Severity: "medium",
+			Field:    "target_weights",
+			Message:  fmt.Sprintf("Target weights sum to %.1f%%, above 100%%. A rebalance would need more cash than the portfolio holds.", targetTotal),
+		})
+	}
+
 	// No investment universe specified
 	if len(s.InvestmentUniverse) == 0 {
 		warnings = append(warnings, models.StrategyWarning{
@@ -410,6 +556,15 @@ func validateSanity(s *models.PortfolioStrategy) []models.StrategyWarning {
 	return warnings
 }
 
+// tickerPriceSources returns the per-ticker price source overrides.
+func tickerPriceSources(s *models.PortfolioStrategy) []models.PriceSource {
+	sources := make([]models.PriceSource, 0, len(s.PriceSourceByTicker))
+	for _, o := range s.PriceSourceByTicker {
+		sources = append(sources, o.Source)
+	}
+	return sources
+}
+
 // validRuleFields lists all known field paths for rule conditions
 var validRuleFields = map[string]bool{
 	"signals.rsi": true, "signals.volume_ratio": true, "signals.macd": true,
@@ -428,6 +583,7 @@ var validRuleFields = map[string]bool{
 	"holding.gain_loss_pct": true, "holding.total_return_pct": true,
 	"holding.capital_gain_pct": true, "holding.units": true, "holding.market_value": true,
 	"holding.net_return_pct_twrr": true, "holding.total_return_pct_twrr": true,
+	"holding.cost_basis": true, "holding.avg_cost": true, "holding.current_price": true,
 }
 
 // validateRules checks rules for structural issues
@@ -477,6 +633,52 @@ func validateRules(s *models.PortfolioStrategy) []models.StrategyWarning {
 	return warnings
 }
 
+// validateDerivedMetrics checks that each derived metric has a unique name,
+// parses, and references only known fields.
+func validateDerivedMetrics(s *models.PortfolioStrategy) []models.StrategyWarning {
+	var warnings []models.StrategyWarning
+	seen := make(map[string]bool)
+
+	for i, m := range s.DerivedMetrics {
+		field := fmt.Sprintf("derived_metrics[%d]", i)
+		if m.Name == "" {
+			warnings = append(warnings, models.StrategyWarning{
+				Severity: "high",
+				Field:    field,
+				Message:  "Derived metric has no name.",
+			})
+		} else if seen[m.Name] {
+			warnings = append(warnings, models.StrategyWarning{
+				Severity: "medium",
+				Field:    field,
+				Message:  fmt.Sprintf("Derived metric '%s' is defined more than once; the last definition wins.", m.Name),
+			})
+		}
+		seen[m.Name] = true
+
+		expr, err := ParseExpression(m.Expression)
+		if err != nil {
+			warnings = append(warnings, models.StrategyWarning{
+				Severity: "high",
+				Field:    field + ".expression",
+				Message:  fmt.Sprintf("Derived metric '%s': %v", m.Name, err),
+			})
+			continue
+		}
+		for _, f := range expr.Fields() {
+			if !validRuleFields[f] {
+				warnings = append(warnings, models.StrategyWarning{
+					Severity: "high",
+					Field:    field + ".expression",
+					Message:  fmt.Sprintf("Derived metric '%s' references unknown field '%s'.", m.Name, f),
+				})
+			}
+		}
+	}
+
+	return warnings
+}
+
 // conditionsOverlap returns true if both rules have identical condition fields
 func conditionsOverlap(a, b []models.RuleCondition) bool {
 	if len(a) != len(b) || len(a) == 0 {
@@ -473,3 +473,25 @@ func TestValidateStrategy_EdgeCases(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateStrategy_TargetWeights(t *testing.T) {
+	svc := &Service{}
+	ctx := context.Background()
+
+	over := &models.PortfolioStrategy{TargetWeights: map[string]float64{"BHP": 70, "CBA": 40}}
+	if !hasWarning(svc.ValidateStrategy(ctx, over), "target_weights", "medium") {
+		t.Error("expected medium warning for target weights summing to 110%")
+	}
+
+	negative := &models.PortfolioStrategy{TargetWeights: map[string]float64{"BHP": -5}}
+	if !hasWarning(svc.ValidateStrategy(ctx, negative), "target_weights", "high") {
+		t.Error("expected high warning for a negative target weight")
+	}
+
+	under := &models.PortfolioStrategy{TargetWeights: map[string]float64{"BHP": 60, "CBA": 30}}
+	for _, w := range svc.ValidateStrategy(ctx, under) {
+		if w.Field == "target_weights" {
+			t.Errorf("targets under 100%% leave cash and should not warn: %+v", w)
+		}
+	}
+}
@@ -109,7 +109,7 @@ func (s *Service) AddTrade(ctx context.Context, portfolioName string, trade mode
 	holding := DeriveHolding(tickerTrades, 0)
 	holding.Ticker = trade.Ticker
 
-	s.logger.Info().Str("portfolio", portfolioName).Str("id", trade.ID).
+	s.logger.WithRequestID(ctx).Info().Str("portfolio", portfolioName).Str("id", trade.ID).
 		Str("ticker", trade.Ticker).Str("action", string(trade.Action)).
 		Float64("units", trade.Units).Float64("price", trade.Price).
 		Msg("Trade added")
@@ -141,7 +141,7 @@ func (s *Service) RemoveTrade(ctx context.Context, portfolioName string, tradeID
 		return nil, err
 	}
 
-	s.logger.Info().Str("portfolio", portfolioName).Str("id", tradeID).Msg("Trade removed")
+	s.logger.WithRequestID(ctx).Info().Str("portfolio", portfolioName).Str("id", tradeID).Msg("Trade removed")
 	return tb, nil
 }
 
@@ -341,6 +341,7 @@ func DeriveHolding(trades []models.Trade, currentPrice float64) models.DerivedHo
 		realizedPnL   float64
 		grossInvested float64
 		grossProceeds float64
+		totalFees     float64
 	)
 
 	// Sort trades by date ascending
@@ -356,6 +357,7 @@ func DeriveHolding(trades []models.Trade, currentPrice float64) models.DerivedHo
 			runningCost += cost
 			runningUnits += t.Units
 			grossInvested += cost
+			totalFees += t.Fees
 		} else if t.Action == models.TradeActionSell {
 			if runningUnits > 0 {
 				avgCostAtSell := runningCost / runningUnits
@@ -369,6 +371,7 @@ func DeriveHolding(trades []models.Trade, currentPrice float64) models.DerivedHo
 					runningCost = 0
 				}
 				grossProceeds += proceeds
+				totalFees += t.Fees
 			}
 		}
 	}
@@ -379,6 +382,7 @@ func DeriveHolding(trades []models.Trade, currentPrice float64) models.DerivedHo
 		RealizedReturn: realizedPnL,
 		GrossInvested:  grossInvested,
 		GrossProceeds:  grossProceeds,
+		TotalFees:      totalFees,
 		TradeCount:     len(trades),
 	}
 
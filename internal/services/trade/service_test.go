@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"strings"
 	"sync"
 	"testing"
@@ -61,6 +62,11 @@ func (m *mockUserDataStore) Query(_ context.Context, _, _ string, _ interfaces.Q
 	return nil, nil
 }
 
+func (m *mockUserDataStore) Count(ctx context.Context, userID, subject string, _ interfaces.QueryOptions) (int, error) {
+	records, err := m.List(ctx, userID, subject)
+	return len(records), err
+}
+
 func (m *mockUserDataStore) DeleteBySubject(_ context.Context, _ string) (int, error) {
 	return 0, nil
 }
@@ -94,7 +100,13 @@ func (m *mockStorageManager) PurgeDerivedData(_ context.Context) (map[string]int
 	return nil, nil
 }
 func (m *mockStorageManager) PurgeReports(_ context.Context) (int, error) { return 0, nil }
-func (m *mockStorageManager) Close() error                                { return nil }
+func (m *mockStorageManager) Backup(_ context.Context, _ io.Writer) (*models.BackupManifest, error) {
+	return nil, nil
+}
+func (m *mockStorageManager) Restore(_ context.Context, _ io.Reader) (*models.BackupManifest, error) {
+	return nil, nil
+}
+func (m *mockStorageManager) Close() error { return nil }
 
 // --- Test helpers ---
 
@@ -520,6 +532,9 @@ func TestDeriveHolding_BuyThenSell(t *testing.T) {
 	if h.CostBasis != expectedCostBasis {
 		t.Errorf("expected cost_basis=%f, got %f", expectedCostBasis, h.CostBasis)
 	}
+	if h.TotalFees != 20 {
+		t.Errorf("expected total_fees=20, got %f", h.TotalFees)
+	}
 }
 
 func TestDeriveHolding_FullSell(t *testing.T) {
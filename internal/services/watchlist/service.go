@@ -4,6 +4,7 @@ package watchlist
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -18,8 +19,9 @@ var _ interfaces.WatchlistService = (*Service)(nil)
 
 // Service implements WatchlistService
 type Service struct {
-	storage interfaces.StorageManager
-	logger  *common.Logger
+	storage  interfaces.StorageManager
+	notifier interfaces.NotificationService
+	logger   *common.Logger
 }
 
 // NewService creates a new watchlist service
@@ -30,6 +32,11 @@ func NewService(storage interfaces.StorageManager, logger *common.Logger) *Servi
 	}
 }
 
+// SetNotificationService sets the webhook dispatcher told about target hits.
+func (s *Service) SetNotificationService(svc interfaces.NotificationService) {
+	s.notifier = svc
+}
+
 // GetWatchlist retrieves the watchlist for a portfolio
 func (s *Service) GetWatchlist(ctx context.Context, portfolioName string) (*models.PortfolioWatchlist, error) {
 	userID := common.ResolveUserID(ctx)
@@ -49,7 +56,7 @@ func (s *Service) SaveWatchlist(ctx context.Context, watchlist *models.Portfolio
 	if err := s.saveWatchlistRecord(ctx, watchlist); err != nil {
 		return fmt.Errorf("failed to save watchlist: %w", err)
 	}
-	s.logger.Info().Str("portfolio", watchlist.PortfolioName).Msg("Watchlist saved")
+	s.logger.WithRequestID(ctx).Info().Str("portfolio", watchlist.PortfolioName).Msg("Watchlist saved")
 	return nil
 }
 
@@ -59,7 +66,7 @@ func (s *Service) DeleteWatchlist(ctx context.Context, portfolioName string) err
 	if err := s.storage.UserDataStore().Delete(ctx, userID, "watchlist", portfolioName); err != nil {
 		return fmt.Errorf("failed to delete watchlist: %w", err)
 	}
-	s.logger.Info().Str("portfolio", portfolioName).Msg("Watchlist deleted")
+	s.logger.WithRequestID(ctx).Info().Str("portfolio", portfolioName).Msg("Watchlist deleted")
 	return nil
 }
 
@@ -92,8 +99,9 @@ func (s *Service) AddOrUpdateItem(ctx context.Context, portfolioName string, ite
 
 	existing, idx := wl.FindByTicker(item.Ticker)
 	if idx >= 0 {
-		// Update existing: preserve CreatedAt, update ReviewedAt only if verdict changed
+		// Update existing: preserve CreatedAt and the target-check state, update ReviewedAt only if verdict changed
 		item.CreatedAt = existing.CreatedAt
+		item.LastPrice = existing.LastPrice
 		if item.Verdict != existing.Verdict {
 			item.ReviewedAt = now
 		} else if item.ReviewedAt.IsZero() {
@@ -117,7 +125,7 @@ func (s *Service) AddOrUpdateItem(ctx context.Context, portfolioName string, ite
 		return nil, fmt.Errorf("failed to save watchlist: %w", err)
 	}
 
-	s.logger.Info().Str("portfolio", portfolioName).Str("ticker", item.Ticker).Msg("Watchlist item upserted")
+	s.logger.WithRequestID(ctx).Info().Str("portfolio", portfolioName).Str("ticker", item.Ticker).Msg("Watchlist item upserted")
 	return wl, nil
 }
 
@@ -156,27 +164,34 @@ func (s *Service) UpdateItem(ctx context.Context, portfolioName, ticker string,
 	if update.Notes != "" {
 		existing.Notes = update.Notes
 	}
+	if update.TargetPrice > 0 {
+		existing.TargetPrice = update.TargetPrice
+	}
 	existing.UpdatedAt = now
 
 	if err := s.saveWatchlistRecord(ctx, wl); err != nil {
 		return nil, fmt.Errorf("failed to save watchlist: %w", err)
 	}
 
-	s.logger.Info().Str("portfolio", portfolioName).Str("ticker", ticker).Msg("Watchlist item updated")
+	s.logger.WithRequestID(ctx).Info().Str("portfolio", portfolioName).Str("ticker", ticker).Msg("Watchlist item updated")
 	return wl, nil
 }
 
-// RemoveItem removes a stock from the watchlist by ticker
+// RemoveItem removes a stock from the watchlist by ticker.
+// Removing a ticker that is not on the watchlist is a no-op.
 func (s *Service) RemoveItem(ctx context.Context, portfolioName, ticker string) (*models.PortfolioWatchlist, error) {
 	wl, err := s.GetWatchlist(ctx, portfolioName)
+	if errors.Is(err, interfaces.ErrRecordNotFound) {
+		return &models.PortfolioWatchlist{PortfolioName: portfolioName, Items: []models.WatchlistItem{}}, nil
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to get watchlist: %w", err)
+		return nil, err
 	}
 
 	ticker = strings.ToUpper(strings.TrimSpace(ticker))
 	_, idx := wl.FindByTicker(ticker)
 	if idx < 0 {
-		return nil, fmt.Errorf("ticker '%s' not found in watchlist", ticker)
+		return wl, nil
 	}
 
 	wl.Items = append(wl.Items[:idx], wl.Items[idx+1:]...)
@@ -185,6 +200,6 @@ func (s *Service) RemoveItem(ctx context.Context, portfolioName, ticker string)
 		return nil, fmt.Errorf("failed to save watchlist: %w", err)
 	}
 
-	s.logger.Info().Str("portfolio", portfolioName).Str("ticker", ticker).Msg("Watchlist item removed")
+	s.logger.WithRequestID(ctx).Info().Str("portfolio", portfolioName).Str("ticker", ticker).Msg("Watchlist item removed")
 	return wl, nil
 }
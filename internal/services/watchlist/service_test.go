@@ -0,0 +1,297 @@
+package watchlist
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/interfaces"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// --- Mock user data store ---
+
+type mockUserDataStore struct {
+	records map[string]*models.UserRecord
+	getErr  error // returned by every Get when set
+}
+
+func newMockUserDataStore() *mockUserDataStore {
+	return &mockUserDataStore{records: make(map[string]*models.UserRecord)}
+}
+
+func (m *mockUserDataStore) key(userID, subject, key string) string {
+	return userID + ":" + subject + ":" + key
+}
+
+func (m *mockUserDataStore) Get(_ context.Context, userID, subject, key string) (*models.UserRecord, error) {
+	if m.getErr != nil {
+		return nil, m.getErr
+	}
+	rec, ok := m.records[m.key(userID, subject, key)]
+	if !ok {
+		return nil, fmt.Errorf("%s/%s: %w", subject, key, interfaces.ErrRecordNotFound)
+	}
+	return rec, nil
+}
+
+func (m *mockUserDataStore) Put(_ context.Context, rec *models.UserRecord) error {
+	m.records[m.key(rec.UserID, rec.Subject, rec.Key)] = rec
+	return nil
+}
+
+func (m *mockUserDataStore) Delete(_ context.Context, userID, subject, key string) error {
+	delete(m.records, m.key(userID, subject, key))
+	return nil
+}
+
+func (m *mockUserDataStore) List(_ context.Context, userID, subject string) ([]*models.UserRecord, error) {
+	prefix := userID + ":" + subject + ":"
+	var out []*models.UserRecord
+	for k, rec := range m.records {
+		if strings.HasPrefix(k, prefix) {
+			out = append(out, rec)
+		}
+	}
+	return out, nil
+}
+
+func (m *mockUserDataStore) Query(ctx context.Context, userID, subject string, _ interfaces.QueryOptions) ([]*models.UserRecord, error) {
+	return m.List(ctx, userID, subject)
+}
+
+func (m *mockUserDataStore) Count(ctx context.Context, userID, subject string, _ interfaces.QueryOptions) (int, error) {
+	records, err := m.List(ctx, userID, subject)
+	return len(records), err
+}
+
+func (m *mockUserDataStore) DeleteBySubject(_ context.Context, _ string) (int, error) {
+	return 0, nil
+}
+
+func (m *mockUserDataStore) Close() error { return nil }
+
+// --- Mock market data storage ---
+
+type mockMarketDataStorage struct {
+	data map[string]*models.MarketData
+}
+
+func (m *mockMarketDataStorage) GetMarketData(_ context.Context, ticker string) (*models.MarketData, error) {
+	if md, ok := m.data[ticker]; ok {
+		return md, nil
+	}
+	return nil, fmt.Errorf("not found")
+}
+
+func (m *mockMarketDataStorage) SaveMarketData(_ context.Context, data *models.MarketData) error {
+	m.data[data.Ticker] = data
+	return nil
+}
+
+func (m *mockMarketDataStorage) GetMarketDataBatch(_ context.Context, tickers []string) ([]*models.MarketData, error) {
+	var out []*models.MarketData
+	for _, t := range tickers {
+		if md, ok := m.data[t]; ok {
+			out = append(out, md)
+		}
+	}
+	return out, nil
+}
+
+func (m *mockMarketDataStorage) GetStaleTickers(_ context.Context, _ string, _ int64) ([]string, error) {
+	return nil, nil
+}
+
+// --- Mock storage manager ---
+
+type mockStorageManager struct {
+	userDataStore *mockUserDataStore
+	market        *mockMarketDataStorage
+}
+
+func newMockStorageManager() *mockStorageManager {
+	return &mockStorageManager{
+		userDataStore: newMockUserDataStore(),
+		market:        &mockMarketDataStorage{data: make(map[string]*models.MarketData)},
+	}
+}
+
+func (m *mockStorageManager) InternalStore() interfaces.InternalStore         { return nil }
+func (m *mockStorageManager) UserDataStore() interfaces.UserDataStore         { return m.userDataStore }
+func (m *mockStorageManager) MarketDataStorage() interfaces.MarketDataStorage { return m.market }
+func (m *mockStorageManager) SignalStorage() interfaces.SignalStorage         { return nil }
+func (m *mockStorageManager) StockIndexStore() interfaces.StockIndexStore     { return nil }
+func (m *mockStorageManager) JobQueueStore() interfaces.JobQueueStore         { return nil }
+func (m *mockStorageManager) FileStore() interfaces.FileStore                 { return nil }
+func (m *mockStorageManager) FeedbackStore() interfaces.FeedbackStore         { return nil }
+func (m *mockStorageManager) ChangelogStore() interfaces.ChangelogStore       { return nil }
+func (m *mockStorageManager) OAuthStore() interfaces.OAuthStore               { return nil }
+func (m *mockStorageManager) TimelineStore() interfaces.TimelineStore         { return nil }
+func (m *mockStorageManager) DataPath() string                                { return "" }
+func (m *mockStorageManager) WriteRaw(_, _ string, _ []byte) error            { return nil }
+func (m *mockStorageManager) PurgeDerivedData(_ context.Context) (map[string]int, error) {
+	return nil, nil
+}
+func (m *mockStorageManager) PurgeReports(_ context.Context) (int, error) { return 0, nil }
+func (m *mockStorageManager) Backup(_ context.Context, _ io.Writer) (*models.BackupManifest, error) {
+	return nil, nil
+}
+func (m *mockStorageManager) Restore(_ context.Context, _ io.Reader) (*models.BackupManifest, error) {
+	return nil, nil
+}
+func (m *mockStorageManager) Close() error { return nil }
+
+func newTestService() (*Service, *mockStorageManager) {
+	storage := newMockStorageManager()
+	return NewService(storage, common.NewSilentLogger()), storage
+}
+
+func setClose(storage *mockStorageManager, ticker string, price float64) {
+	storage.market.data[ticker] = &models.MarketData{
+		Ticker: ticker,
+		EOD:    []models.EODBar{{Date: time.Now().Add(-time.Hour), Close: price}},
+	}
+}
+
+func TestAddOrUpdateItem_Idempotent(t *testing.T) {
+	svc, _ := newTestService()
+	ctx := context.Background()
+	item := models.WatchlistItem{Ticker: "BHP.AU", Verdict: models.WatchlistVerdictWatch, TargetPrice: 40}
+
+	for i := 0; i < 2; i++ {
+		add := item
+		if _, err := svc.AddOrUpdateItem(ctx, "SMSF", &add); err != nil {
+			t.Fatalf("AddOrUpdateItem #%d: %v", i+1, err)
+		}
+	}
+
+	wl, err := svc.GetWatchlist(ctx, "SMSF")
+	if err != nil {
+		t.Fatalf("GetWatchlist: %v", err)
+	}
+	if len(wl.Items) != 1 || wl.Items[0].TargetPrice != 40 {
+		t.Errorf("items = %+v, want a single BHP.AU with target 40", wl.Items)
+	}
+}
+
+func TestRemoveItem_Idempotent(t *testing.T) {
+	svc, _ := newTestService()
+	ctx := context.Background()
+
+	// No watchlist yet
+	wl, err := svc.RemoveItem(ctx, "SMSF", "BHP.AU")
+	if err != nil || len(wl.Items) != 0 {
+		t.Fatalf("remove from missing watchlist = %+v, %v; want empty, nil", wl, err)
+	}
+
+	if _, err := svc.AddOrUpdateItem(ctx, "SMSF", &models.WatchlistItem{Ticker: "BHP.AU"}); err != nil {
+		t.Fatalf("AddOrUpdateItem: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		wl, err = svc.RemoveItem(ctx, "SMSF", "bhp.au")
+		if err != nil {
+			t.Fatalf("RemoveItem #%d: %v", i+1, err)
+		}
+		if len(wl.Items) != 0 {
+			t.Errorf("RemoveItem #%d left %d items", i+1, len(wl.Items))
+		}
+	}
+}
+
+func TestRemoveItemAndCheckTargets_StorageErrorPassesThrough(t *testing.T) {
+	svc, storage := newTestService()
+	ctx := context.Background()
+	storage.userDataStore.getErr = errors.New("connection refused")
+
+	if _, err := svc.RemoveItem(ctx, "SMSF", "BHP.AU"); err == nil {
+		t.Error("RemoveItem: want the storage error, got nil")
+	}
+	if _, err := svc.CheckTargets(ctx, "SMSF"); err == nil {
+		t.Error("CheckTargets: want the storage error, got nil")
+	}
+}
+
+func TestCheckTargets_HitOnceWhenCrossing(t *testing.T) {
+	svc, storage := newTestService()
+	ctx := context.Background()
+	if _, err := svc.AddOrUpdateItem(ctx, "SMSF", &models.WatchlistItem{Ticker: "BHP.AU", TargetPrice: 40}); err != nil {
+		t.Fatalf("AddOrUpdateItem: %v", err)
+	}
+
+	steps := []struct {
+		price float64
+		hits  int
+	}{
+		{38, 0}, // first observation only seeds the last price
+		{39, 0}, // still below
+		{41, 1}, // crossed up
+		{42, 0}, // stays above — no repeat
+		{40.5, 0},
+		{39.5, 1}, // crossed back down
+	}
+	for i, step := range steps {
+		setClose(storage, "BHP.AU", step.price)
+		events, err := svc.CheckTargets(ctx, "SMSF")
+		if err != nil {
+			t.Fatalf("step %d: CheckTargets: %v", i, err)
+		}
+		if len(events) != step.hits {
+			t.Errorf("step %d (price %.2f): %d hits, want %d", i, step.price, len(events), step.hits)
+		}
+	}
+
+	events, err := svc.ListEvents(ctx, "SMSF")
+	if err != nil {
+		t.Fatalf("ListEvents: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("recorded %d events, want 2", len(events))
+	}
+	up, down := events[1], events[0] // newest first
+	if up.Type != models.WatchlistEventTargetHit || up.Direction != "up" || up.PreviousPrice != 39 || up.Price != 41 {
+		t.Errorf("first event = %+v, want up 39 -> 41", up)
+	}
+	if down.Direction != "down" || down.Price != 39.5 {
+		t.Errorf("second event = %+v, want down to 39.5", down)
+	}
+
+	other, _ := svc.ListEvents(ctx, "Other")
+	if len(other) != 0 {
+		t.Errorf("events leaked to another portfolio: %+v", other)
+	}
+}
+
+func TestCheckTargets_IgnoresItemsWithoutTarget(t *testing.T) {
+	svc, storage := newTestService()
+	ctx := context.Background()
+	if _, err := svc.AddOrUpdateItem(ctx, "SMSF", &models.WatchlistItem{Ticker: "CBA.AU"}); err != nil {
+		t.Fatalf("AddOrUpdateItem: %v", err)
+	}
+	for _, price := range []float64{100, 150} {
+		setClose(storage, "CBA.AU", price)
+		if events, err := svc.CheckTargets(ctx, "SMSF"); err != nil || len(events) != 0 {
+			t.Fatalf("CheckTargets = %v, %v; want no events", events, err)
+		}
+	}
+}
+
+func TestCurrentPrice_PrefersNewerLiveQuote(t *testing.T) {
+	now := time.Now()
+	md := &models.MarketData{
+		EOD:       []models.EODBar{{Date: now.Add(-24 * time.Hour), Close: 10}},
+		LivePrice: &models.RealTimeQuote{Close: 11, Timestamp: now},
+	}
+	if got := currentPrice(md); got != 11 {
+		t.Errorf("currentPrice = %.2f, want live 11", got)
+	}
+	md.LivePrice.Timestamp = now.Add(-48 * time.Hour)
+	if got := currentPrice(md); got != 10 {
+		t.Errorf("currentPrice = %.2f, want EOD 10 over stale quote", got)
+	}
+}
@@ -0,0 +1,179 @@
+package watchlist
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/interfaces"
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// eventSubject is the UserDataStore subject holding watchlist events
+const eventSubject = "watchlist_event"
+
+// CheckTargets compares each targeted item's current price with its target.
+// A target is hit when the price crosses it between two checks, in either
+// direction, so a price that stays beyond the target raises one event, not one
+// per check. The first check for an item only records the price.
+func (s *Service) CheckTargets(ctx context.Context, portfolioName string) ([]models.WatchlistEvent, error) {
+	wl, err := s.GetWatchlist(ctx, portfolioName)
+	if errors.Is(err, interfaces.ErrRecordNotFound) {
+		return nil, nil // no watchlist, nothing to check
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	tickers := make([]string, 0, len(wl.Items))
+	for _, item := range wl.Items {
+		if item.TargetPrice > 0 {
+			tickers = append(tickers, item.Ticker)
+		}
+	}
+	if len(tickers) == 0 {
+		return nil, nil
+	}
+
+	allMarketData, err := s.storage.MarketDataStorage().GetMarketDataBatch(ctx, tickers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load market data: %w", err)
+	}
+	prices := make(map[string]float64, len(allMarketData))
+	for _, md := range allMarketData {
+		if p := currentPrice(md); p > 0 {
+			prices[strings.ToUpper(md.Ticker)] = p
+		}
+	}
+
+	now := time.Now()
+	var events []models.WatchlistEvent
+	changed := false
+	for i := range wl.Items {
+		item := &wl.Items[i]
+		price, ok := prices[strings.ToUpper(item.Ticker)]
+		if item.TargetPrice <= 0 || !ok || price == item.LastPrice {
+			continue
+		}
+		if dir := targetCrossing(item.LastPrice, price, item.TargetPrice); dir != "" {
+			events = append(events, models.WatchlistEvent{
+				Type:          models.WatchlistEventTargetHit,
+				PortfolioName: portfolioName,
+				Ticker:        item.Ticker,
+				TargetPrice:   item.TargetPrice,
+				PreviousPrice: item.LastPrice,
+				Price:         price,
+				Direction:     dir,
+				OccurredAt:    now,
+			})
+		}
+		item.LastPrice = price
+		changed = true
+	}
+
+	if !changed {
+		return nil, nil
+	}
+	if err := s.saveWatchlistRecord(ctx, wl); err != nil {
+		return nil, fmt.Errorf("failed to save watchlist: %w", err)
+	}
+
+	for i, ev := range events {
+		if err := s.saveEvent(ctx, ev, i); err != nil {
+			return events, fmt.Errorf("failed to save watchlist event: %w", err)
+		}
+		s.logger.WithRequestID(ctx).Info().Str("portfolio", portfolioName).Str("ticker", ev.Ticker).
+			Float64("target", ev.TargetPrice).Float64("price", ev.Price).Msg("Watchlist target hit")
+		s.notifyTargetHit(ctx, ev)
+	}
+	return events, nil
+}
+
+// notifyTargetHit sends a webhook event for a recorded target hit.
+func (s *Service) notifyTargetHit(ctx context.Context, ev models.WatchlistEvent) {
+	if s.notifier == nil {
+		return
+	}
+	s.notifier.Notify(ctx, models.NotificationEvent{
+		Type:          models.NotificationWatchlistTarget,
+		PortfolioName: ev.PortfolioName,
+		Ticker:        ev.Ticker,
+		Message: fmt.Sprintf("%s crossed its %.2f target %s (%.2f -> %.2f)",
+			ev.Ticker, ev.TargetPrice, ev.Direction, ev.PreviousPrice, ev.Price),
+		OccurredAt: ev.OccurredAt,
+		Data:       ev,
+	})
+}
+
+// ListEvents returns the recorded events for a portfolio, newest first
+func (s *Service) ListEvents(ctx context.Context, portfolioName string) ([]models.WatchlistEvent, error) {
+	userID := common.ResolveUserID(ctx)
+	records, err := s.storage.UserDataStore().List(ctx, userID, eventSubject)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list watchlist events: %w", err)
+	}
+
+	events := make([]models.WatchlistEvent, 0, len(records))
+	for _, rec := range records {
+		var ev models.WatchlistEvent
+		if err := json.Unmarshal([]byte(rec.Value), &ev); err != nil {
+			s.logger.WithRequestID(ctx).Warn().Str("key", rec.Key).Err(err).Msg("Skipping unreadable watchlist event")
+			continue
+		}
+		if ev.PortfolioName == portfolioName {
+			events = append(events, ev)
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].OccurredAt.After(events[j].OccurredAt)
+	})
+	return events, nil
+}
+
+func (s *Service) saveEvent(ctx context.Context, ev models.WatchlistEvent, seq int) error {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	return s.storage.UserDataStore().Put(ctx, &models.UserRecord{
+		UserID:  common.ResolveUserID(ctx),
+		Subject: eventSubject,
+		Key:     fmt.Sprintf("%s:%s:%d-%d", ev.PortfolioName, ev.Ticker, ev.OccurredAt.UnixNano(), seq),
+		Value:   string(data),
+	})
+}
+
+// targetCrossing reports "up" or "down" when the move from prev to price
+// reaches target from the other side, or "" when it does not
+func targetCrossing(prev, price, target float64) string {
+	switch {
+	case prev <= 0:
+		return ""
+	case prev < target && price >= target:
+		return "up"
+	case prev > target && price <= target:
+		return "down"
+	}
+	return ""
+}
+
+// currentPrice prefers a live quote newer than the latest EOD bar
+func currentPrice(md *models.MarketData) float64 {
+	if md == nil {
+		return 0
+	}
+	var eod float64
+	var eodDate time.Time
+	if len(md.EOD) > 0 {
+		eod, eodDate = md.EOD[0].Close, md.EOD[0].Date
+	}
+	if md.LivePrice != nil && md.LivePrice.Close > 0 && md.LivePrice.Timestamp.After(eodDate) {
+		return md.LivePrice.Close
+	}
+	return eod
+}
@@ -8,16 +8,86 @@ import (
 	"github.com/bobmcallan/vire/internal/models"
 )
 
+// Bollinger Band settings: 20-bar SMA at 2 standard deviations; a squeeze is
+// ranked against the trailing 126 bars (~6 months), and a close outside the
+// bands within 5 bars of a squeeze is a breakout.
+const (
+	bbPeriod           = 20
+	bbStdDev           = 2.0
+	bbSqueezeWindow    = 126
+	bbBreakoutLookback = 5
+)
+
 // Computer computes all signals for a ticker
-type Computer struct{}
+type Computer struct {
+	macdFast, macdSlow, macdSignal int
+}
 
-// NewComputer creates a new signal computer
+// NewComputer creates a new signal computer with MACD periods 12/26/9
 func NewComputer() *Computer {
-	return &Computer{}
+	return &Computer{macdFast: 12, macdSlow: 26, macdSignal: 9}
+}
+
+// SetMACDPeriods overrides the MACD fast/slow/signal EMA periods.
+// Ignored unless all are positive and fast < slow.
+func (c *Computer) SetMACDPeriods(fast, slow, signal int) {
+	if fast <= 0 || slow <= 0 || signal <= 0 || fast >= slow {
+		return
+	}
+	c.macdFast, c.macdSlow, c.macdSignal = fast, slow, signal
+}
+
+// IndicatorSeries returns RSI, SMA50/200 and MACD as of each of the latest
+// days bars, most recent first. Each point is computed from that bar and the
+// bars before it with the same functions and periods ComputeWithRSIPeriod
+// uses, so the first point matches the current signals. days is capped to
+// the bars available.
+func (c *Computer) IndicatorSeries(bars []models.EODBar, days, rsiPeriod int) []models.IndicatorPoint {
+	if days > len(bars) {
+		days = len(bars)
+	}
+	if days <= 0 {
+		return nil
+	}
+	rsiPeriod = EffectiveRSIPeriod(len(bars), rsiPeriod)
+
+	series := make([]models.IndicatorPoint, days)
+	for i := range series {
+		history := bars[i:]
+		macdLine, macdSignal, macdHist := MACD(history, c.macdFast, c.macdSlow, c.macdSignal)
+		series[i] = models.IndicatorPoint{
+			Date:          history[0].Date,
+			Close:         history[0].Close,
+			RSI:           RSI(history, rsiPeriod),
+			SMA50:         SMA(history, 50),
+			SMA200:        SMA(history, 200),
+			MACD:          macdLine,
+			MACDSignal:    macdSignal,
+			MACDHistogram: macdHist,
+		}
+	}
+	return series
+}
+
+// EffectiveRSIPeriod returns the RSI period used over bars bars:
+// rsiPeriod, or models.DefaultRSIPeriod when rsiPeriod is invalid or there
+// are fewer than rsiPeriod+1 bars.
+func EffectiveRSIPeriod(bars, rsiPeriod int) int {
+	if rsiPeriod < 2 || bars < rsiPeriod+1 {
+		return models.DefaultRSIPeriod
+	}
+	return rsiPeriod
 }
 
 // Compute calculates all signals from market data
 func (c *Computer) Compute(marketData *models.MarketData) *models.TickerSignals {
+	return c.ComputeWithRSIPeriod(marketData, models.DefaultRSIPeriod)
+}
+
+// ComputeWithRSIPeriod calculates all signals using an RSI over rsiPeriod
+// bars. It falls back to models.DefaultRSIPeriod when rsiPeriod is invalid
+// or there are fewer than rsiPeriod+1 bars.
+func (c *Computer) ComputeWithRSIPeriod(marketData *models.MarketData, rsiPeriod int) *models.TickerSignals {
 	if marketData == nil {
 		return &models.TickerSignals{
 			ComputeTimestamp: time.Now(),
@@ -46,12 +116,17 @@ func (c *Computer) Compute(marketData *models.MarketData) *models.TickerSignals
 	}
 
 	// Calculate technical indicators
-	rsi := RSI(bars, 14)
-	macdLine, macdSignal, macdHist := MACD(bars, 12, 26, 9)
+	rsiPeriod = EffectiveRSIPeriod(len(bars), rsiPeriod)
+	rsi := RSI(bars, rsiPeriod)
+	macdLine, macdSignal, macdHist := MACD(bars, c.macdFast, c.macdSlow, c.macdSignal)
+	macdCross := MACDCross(bars, c.macdFast, c.macdSlow, c.macdSignal)
 	atr := ATR(bars, 14)
+	bbUpper, bbMiddle, bbLower := BollingerBands(bars, bbPeriod, bbStdDev)
+	avgVol20 := AverageVolume(bars, 20)
 	volRatio := VolumeRatio(bars, 20)
 
-	// Detect crossovers
+	// Detect crossovers. The 50/200 cross compares the latest two bars, so it
+	// needs 201 bars; with fewer it is "none".
 	sma20Cross50 := DetectCrossover(bars, 20, 50)
 	sma50Cross200 := DetectCrossover(bars, 50, 200)
 
@@ -74,13 +149,15 @@ func (c *Computer) Compute(marketData *models.MarketData) *models.TickerSignals
 	nearSupport := currentPrice <= support*1.02
 	nearResistance := currentPrice >= resistance*0.98
 
-	// MACD crossover detection
+	// MACD crossover: the current MACD state. A histogram sign flip on the
+	// latest bar is reported separately in MACDCrossEvent.
 	var macdCrossover string
-	if macdHist > 0 && macdLine > 0 {
+	switch {
+	case macdHist > 0 && macdLine > 0:
 		macdCrossover = "bullish"
-	} else if macdHist < 0 && macdLine < 0 {
+	case macdHist < 0 && macdLine < 0:
 		macdCrossover = "bearish"
-	} else {
+	default:
 		macdCrossover = "none"
 	}
 
@@ -91,6 +168,7 @@ func (c *Computer) Compute(marketData *models.MarketData) *models.TickerSignals
 	signals := &models.TickerSignals{
 		Ticker:           marketData.Ticker,
 		ComputeTimestamp: time.Now(),
+		RSIPeriod:        rsiPeriod,
 
 		Price: models.PriceSignals{
 			Current:          currentPrice,
@@ -111,7 +189,9 @@ func (c *Computer) Compute(marketData *models.MarketData) *models.TickerSignals
 			MACDSignal:       macdSignal,
 			MACDHistogram:    macdHist,
 			MACDCrossover:    macdCrossover,
+			MACDCrossEvent:   macdCross,
 			VolumeRatio:      volRatio,
+			AvgVolume20:      avgVol20,
 			VolumeSignal:     ClassifyVolume(volRatio),
 			ATR:              atr,
 			ATRPct:           (atr / currentPrice) * 100,
@@ -122,6 +202,12 @@ func (c *Computer) Compute(marketData *models.MarketData) *models.TickerSignals
 			NearResistance:   nearResistance,
 			SupportLevel:     support,
 			ResistanceLevel:  resistance,
+			BBUpper:          bbUpper,
+			BBMiddle:         bbMiddle,
+			BBLower:          bbLower,
+			BBWidth:          BollingerWidth(bars, bbPeriod, bbStdDev),
+			BBSqueeze:        BollingerSqueeze(bars, bbPeriod, bbStdDev, bbSqueezeWindow),
+			BBBreakout:       BollingerBreakout(bars, bbPeriod, bbStdDev, bbSqueezeWindow, bbBreakoutLookback),
 		},
 
 		Trend:            trend,
@@ -1,6 +1,7 @@
 package signals
 
 import (
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -269,3 +270,224 @@ func TestCompute_TrendMomentum_ComputedAfterSupport(t *testing.T) {
 	// TrendMomentum.NearSupport is a bool — just verify it doesn't panic
 	_ = signals.TrendMomentum.NearSupport
 }
+
+// =============================================================================
+// ComputeWithRSIPeriod
+// =============================================================================
+
+func TestComputeWithRSIPeriod_14And21Differ(t *testing.T) {
+	computer := NewComputer()
+	closes := make([]float64, 40)
+	for i := range closes {
+		// Recent rally on top of an older choppy decline
+		closes[i] = 100 - float64(i) + float64(i%3)*2
+		if i < 8 {
+			closes[i] = 100 + float64(8-i)*3
+		}
+	}
+	md := &models.MarketData{Ticker: "TEST.AU", EOD: generateBars(closes)}
+
+	rsi14 := computer.ComputeWithRSIPeriod(md, 14).Technical.RSI
+	rsi21 := computer.ComputeWithRSIPeriod(md, 21).Technical.RSI
+	assert.InDelta(t, RSI(md.EOD, 14), rsi14, 1e-9)
+	assert.InDelta(t, RSI(md.EOD, 21), rsi21, 1e-9)
+	assert.Equal(t, 21, computer.ComputeWithRSIPeriod(md, 21).RSIPeriod)
+	assert.NotEqual(t, rsi14, rsi21, "the smoother 21-period RSI should differ from RSI(14)")
+	assert.Equal(t, rsi14, computer.Compute(md).Technical.RSI, "Compute uses the default period")
+}
+
+func TestComputeWithRSIPeriod_FallsBackWithTooFewBars(t *testing.T) {
+	computer := NewComputer()
+	closes := make([]float64, 18) // enough for RSI(14), not RSI(21)
+	for i := range closes {
+		closes[i] = 100 + float64(i%4)
+	}
+	md := &models.MarketData{Ticker: "TEST.AU", EOD: generateBars(closes)}
+
+	assert.Equal(t, RSI(md.EOD, 14), computer.ComputeWithRSIPeriod(md, 21).Technical.RSI)
+	assert.Equal(t, RSI(md.EOD, 14), computer.ComputeWithRSIPeriod(md, 0).Technical.RSI)
+	assert.Equal(t, 14, computer.ComputeWithRSIPeriod(md, 21).RSIPeriod, "the recorded period is the one used")
+}
+
+// =============================================================================
+// MACD periods and crossover
+// =============================================================================
+
+func TestSetMACDPeriods_ComputeUsesConfiguredPeriods(t *testing.T) {
+	closes := make([]float64, 60)
+	for i := range closes {
+		closes[i] = 100 + float64(i%9) - float64(i)/3
+	}
+	md := &models.MarketData{Ticker: "TEST.AU", EOD: generateBars(closes)}
+
+	computer := NewComputer()
+	line, _, _ := MACD(md.EOD, 12, 26, 9)
+	assert.InDelta(t, line, computer.Compute(md).Technical.MACD, 1e-9, "default 12/26/9")
+
+	computer.SetMACDPeriods(5, 10, 3)
+	line, _, _ = MACD(md.EOD, 5, 10, 3)
+	assert.InDelta(t, line, computer.Compute(md).Technical.MACD, 1e-9, "configured 5/10/3")
+
+	computer.SetMACDPeriods(10, 5, 3) // fast >= slow: ignored
+	assert.InDelta(t, line, computer.Compute(md).Technical.MACD, 1e-9, "invalid periods are ignored")
+}
+
+func TestCompute_MACDCrossEventReportsLatestBarCross(t *testing.T) {
+	var oldestFirst []float64
+	price := 100.0
+	for i := 0; i < 40; i++ {
+		price -= 1
+		oldestFirst = append(oldestFirst, price)
+	}
+	for i := 0; i < 20; i++ {
+		price += 2
+		oldestFirst = append(oldestFirst, price)
+	}
+	closes := make([]float64, len(oldestFirst))
+	for i, c := range oldestFirst {
+		closes[len(closes)-1-i] = c
+	}
+	bars := generateBars(closes)
+	computer := NewComputer()
+
+	found := false
+	for k := 0; len(bars)-k >= 35; k++ {
+		tech := computer.Compute(&models.MarketData{Ticker: "TEST.AU", EOD: bars[k:]}).Technical
+		cross := MACDCross(bars[k:], 12, 26, 9)
+		if cross != "" {
+			found = true
+		}
+		assert.Equal(t, cross, tech.MACDCrossEvent, "k=%d", k)
+		assert.Contains(t, []string{"bullish", "bearish", "none"}, tech.MACDCrossover, "k=%d", k)
+	}
+	assert.True(t, found, "fixture should contain a MACD cross")
+}
+
+func TestCompute_AvgVolume20FromBars(t *testing.T) {
+	computer := NewComputer()
+	closes := make([]float64, 25)
+	for i := range closes {
+		closes[i] = 100
+	}
+	bars := generateBars(closes)
+	bars[0].Volume = 3000000 // today: 3x the other bars
+	md := &models.MarketData{Ticker: "TEST.AU", EOD: bars}
+
+	tech := computer.Compute(md).Technical
+	assert.Equal(t, int64(1100000), tech.AvgVolume20, "20-day average includes today")
+	assert.InDelta(t, 3000000.0/1100000.0, tech.VolumeRatio, 1e-9)
+
+	for i := range bars {
+		bars[i].Volume = 0
+	}
+	tech = computer.Compute(md).Technical
+	assert.Equal(t, int64(0), tech.AvgVolume20, "missing volume leaves the average at zero")
+}
+
+// =============================================================================
+// SMA50 / SMA200 cross
+// =============================================================================
+
+// flatThenLatest returns n bars at 100 with the newest bar closing at latest
+func flatThenLatest(n int, latest float64) []models.EODBar {
+	closes := make([]float64, n)
+	for i := range closes {
+		closes[i] = 100
+	}
+	closes[0] = latest
+	return generateBars(closes)
+}
+
+func TestCompute_SMA50CrossSMA200Boundaries(t *testing.T) {
+	computer := NewComputer()
+	tests := []struct {
+		name      string
+		bars      []models.EODBar
+		wantCross string
+	}{
+		{"golden cross on the latest bar", flatThenLatest(201, 110), "golden_cross"},
+		{"death cross on the latest bar", flatThenLatest(201, 90), "death_cross"},
+		{"no move, no cross", flatThenLatest(201, 100), "none"},
+		{"200 bars: no previous SMA200", flatThenLatest(200, 110), "none"},
+		{"199 bars: skipped", flatThenLatest(199, 110), "none"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sig := computer.Compute(&models.MarketData{Ticker: "TEST.AU", EOD: tt.bars})
+			assert.Equal(t, tt.wantCross, sig.Technical.SMA50CrossSMA200)
+			assert.InDelta(t, SMA(tt.bars, 50), sig.Price.SMA50, 1e-9)
+			assert.InDelta(t, SMA(tt.bars, 200), sig.Price.SMA200, 1e-9)
+		})
+	}
+}
+
+func TestIndicatorSeries_LatestPointMatchesSignals(t *testing.T) {
+	closes := make([]float64, 260)
+	for i := range closes {
+		closes[i] = 50 + 8*math.Sin(float64(i)/9) + float64(i%7)*0.3
+	}
+	bars := generateBars(closes)
+	c := NewComputer()
+
+	series := c.IndicatorSeries(bars, 30, models.DefaultRSIPeriod)
+	if len(series) != 30 {
+		t.Fatalf("series length = %d, want 30", len(series))
+	}
+
+	sig := c.Compute(&models.MarketData{Ticker: "TEST.AU", EOD: bars})
+	latest := series[0]
+	if !latest.Date.Equal(bars[0].Date) || latest.Close != bars[0].Close {
+		t.Errorf("latest point is for %v @ %.2f, want the newest bar", latest.Date, latest.Close)
+	}
+	for _, tc := range []struct {
+		name      string
+		got, want float64
+	}{
+		{"RSI", latest.RSI, sig.Technical.RSI},
+		{"SMA50", latest.SMA50, sig.Price.SMA50},
+		{"SMA200", latest.SMA200, sig.Price.SMA200},
+		{"MACD", latest.MACD, sig.Technical.MACD},
+		{"MACDSignal", latest.MACDSignal, sig.Technical.MACDSignal},
+		{"MACDHistogram", latest.MACDHistogram, sig.Technical.MACDHistogram},
+	} {
+		if tc.got != tc.want {
+			t.Errorf("latest %s = %v, signal value %v", tc.name, tc.got, tc.want)
+		}
+	}
+
+	// An older point is the signal value as of that bar.
+	past := c.Compute(&models.MarketData{Ticker: "TEST.AU", EOD: bars[10:]})
+	if series[10].RSI != past.Technical.RSI || series[10].SMA200 != past.Price.SMA200 {
+		t.Errorf("point 10 = RSI %v SMA200 %v, want %v / %v",
+			series[10].RSI, series[10].SMA200, past.Technical.RSI, past.Price.SMA200)
+	}
+}
+
+func TestIndicatorSeries_ShortHistoryTruncates(t *testing.T) {
+	bars := generateTrendBars(10, 0.1, 40)
+	c := NewComputer()
+
+	series := c.IndicatorSeries(bars, 100, models.DefaultRSIPeriod)
+	if len(series) != 40 {
+		t.Fatalf("series length = %d, want 40 (capped to available bars)", len(series))
+	}
+	for i, p := range series {
+		if p.SMA50 != 0 || p.SMA200 != 0 {
+			t.Errorf("point %d: SMA50/200 = %v/%v with under 50 bars, want 0", i, p.SMA50, p.SMA200)
+		}
+	}
+	oldest := series[len(series)-1]
+	if oldest.RSI != 50 || oldest.MACD != 0 {
+		t.Errorf("oldest point RSI/MACD = %v/%v, want neutral 50 and 0", oldest.RSI, oldest.MACD)
+	}
+	if series[0].MACD == 0 {
+		t.Error("newest point has 40 bars and should have a MACD line")
+	}
+
+	if got := c.IndicatorSeries(nil, 10, models.DefaultRSIPeriod); got != nil {
+		t.Errorf("series over no bars = %v, want nil", got)
+	}
+	if got := c.IndicatorSeries(bars, 0, models.DefaultRSIPeriod); got != nil {
+		t.Errorf("zero-day series = %v, want nil", got)
+	}
+}
@@ -0,0 +1,113 @@
+package signals
+
+import (
+	"math"
+
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// DefaultCorrelationWindow is the number of trailing daily returns compared
+// when no window is given (about three months of trading days).
+const DefaultCorrelationWindow = 60
+
+// MinCorrelationOverlap is the fewest daily returns two tickers must share
+// for their correlation to be reported. Smaller windows are raised to it.
+const MinCorrelationOverlap = 20
+
+// ReturnCorrelations computes pairwise Pearson correlations of daily close-to-
+// close returns over each ticker's latest window returns (bars are most
+// recent first). Returns are matched by bar date, so a pair is compared only
+// on days both traded. A pair sharing fewer than MinCorrelationOverlap
+// returns, or where either series is flat, is left out. A ticker with enough
+// history correlates 1 with itself; one without is absent from the result.
+func ReturnCorrelations(bars map[string][]models.EODBar, window int) map[string]map[string]float64 {
+	if window <= 0 {
+		window = DefaultCorrelationWindow
+	}
+	if window < MinCorrelationOverlap {
+		window = MinCorrelationOverlap
+	}
+
+	returns := make(map[string]map[string]float64, len(bars))
+	tickers := make([]string, 0, len(bars))
+	for ticker, series := range bars {
+		r := dailyReturns(series, window)
+		if len(r) < MinCorrelationOverlap {
+			continue
+		}
+		returns[ticker] = r
+		tickers = append(tickers, ticker)
+	}
+
+	matrix := make(map[string]map[string]float64, len(tickers))
+	set := func(a, b string, v float64) {
+		if matrix[a] == nil {
+			matrix[a] = make(map[string]float64)
+		}
+		matrix[a][b] = v
+	}
+	for i, a := range tickers {
+		set(a, a, 1)
+		for _, b := range tickers[i+1:] {
+			corr, ok := pairedCorrelation(returns[a], returns[b])
+			if !ok {
+				continue
+			}
+			set(a, b, corr)
+			set(b, a, corr)
+		}
+	}
+	return matrix
+}
+
+// dailyReturns maps each of the latest n bars' dates to its return from the
+// previous bar. Bars with a non-positive previous close are skipped.
+func dailyReturns(bars []models.EODBar, n int) map[string]float64 {
+	out := make(map[string]float64, n)
+	for i := 0; i+1 < len(bars) && i < n; i++ {
+		prev := bars[i+1].Close
+		if prev <= 0 {
+			continue
+		}
+		out[bars[i].Date.Format("2006-01-02")] = bars[i].Close/prev - 1
+	}
+	return out
+}
+
+// pairedCorrelation is the Pearson correlation of a and b over the dates
+// both contain. ok is false with too little overlap or zero variance.
+func pairedCorrelation(a, b map[string]float64) (float64, bool) {
+	var xs, ys []float64
+	for date, x := range a {
+		if y, found := b[date]; found {
+			xs = append(xs, x)
+			ys = append(ys, y)
+		}
+	}
+	n := float64(len(xs))
+	if len(xs) < MinCorrelationOverlap {
+		return 0, false
+	}
+
+	var meanX, meanY float64
+	for i := range xs {
+		meanX += xs[i]
+		meanY += ys[i]
+	}
+	meanX /= n
+	meanY /= n
+
+	var cov, varX, varY float64
+	for i := range xs {
+		dx, dy := xs[i]-meanX, ys[i]-meanY
+		cov += dx * dy
+		varX += dx * dx
+		varY += dy * dy
+	}
+	if varX == 0 || varY == 0 {
+		return 0, false
+	}
+	corr := cov / math.Sqrt(varX*varY)
+	// Clamp rounding error so perfectly (anti-)correlated series read ±1
+	return math.Max(-1, math.Min(1, corr)), true
+}
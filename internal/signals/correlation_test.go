@@ -0,0 +1,102 @@
+package signals
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/models"
+)
+
+// barsFromReturns builds most-recent-first daily bars ending at end whose
+// close-to-close returns, oldest first, are returns.
+func barsFromReturns(end time.Time, returns []float64) []models.EODBar {
+	closes := make([]float64, len(returns)+1)
+	closes[0] = 100
+	for i, r := range returns {
+		closes[i+1] = closes[i] * (1 + r)
+	}
+	bars := make([]models.EODBar, len(closes))
+	for i := range closes {
+		bars[i] = models.EODBar{
+			Date:  end.AddDate(0, 0, -i),
+			Close: closes[len(closes)-1-i],
+		}
+	}
+	return bars
+}
+
+// zigzag returns n alternating returns of varying size.
+func zigzag(n int, scale float64) []float64 {
+	r := make([]float64, n)
+	for i := range r {
+		r[i] = scale * float64(i%5+1) / 100
+		if i%2 == 1 {
+			r[i] = -r[i]
+		}
+	}
+	return r
+}
+
+func TestReturnCorrelations_PerfectAndInverse(t *testing.T) {
+	end := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+	base := zigzag(60, 1)
+	inverse := make([]float64, len(base))
+	for i, r := range base {
+		inverse[i] = -r
+	}
+
+	matrix := ReturnCorrelations(map[string][]models.EODBar{
+		"AAA.AU": barsFromReturns(end, base),
+		"BBB.AU": barsFromReturns(end, zigzag(60, 2)), // same pattern, double the size
+		"CCC.AU": barsFromReturns(end, inverse),
+	}, 60)
+
+	cases := []struct {
+		a, b string
+		want float64
+	}{
+		{"AAA.AU", "AAA.AU", 1},
+		{"AAA.AU", "BBB.AU", 1},
+		{"BBB.AU", "AAA.AU", 1},
+		{"AAA.AU", "CCC.AU", -1},
+		{"BBB.AU", "CCC.AU", -1},
+	}
+	for _, c := range cases {
+		got, ok := matrix[c.a][c.b]
+		if !ok {
+			t.Errorf("%s/%s missing from matrix", c.a, c.b)
+			continue
+		}
+		if math.Abs(got-c.want) > 1e-9 {
+			t.Errorf("%s/%s = %.6f, want %v", c.a, c.b, got, c.want)
+		}
+	}
+}
+
+func TestReturnCorrelations_ExcludesInsufficientOverlap(t *testing.T) {
+	end := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+
+	matrix := ReturnCorrelations(map[string][]models.EODBar{
+		"AAA.AU": barsFromReturns(end, zigzag(60, 1)),
+		// Plenty of history, but it ended 40 days before AAA's window starts
+		"OLD.AU": barsFromReturns(end.AddDate(0, 0, -100), zigzag(60, 1)),
+		// Too little history to correlate with anything
+		"NEW.AU": barsFromReturns(end, zigzag(10, 1)),
+		// Flat price: zero variance
+		"FLAT.AU": barsFromReturns(end, make([]float64, 60)),
+	}, 60)
+
+	if _, ok := matrix["AAA.AU"]["OLD.AU"]; ok {
+		t.Error("AAA/OLD share no dates and should be excluded")
+	}
+	if _, ok := matrix["NEW.AU"]; ok {
+		t.Error("NEW has fewer than MinCorrelationOverlap returns and should be absent")
+	}
+	if _, ok := matrix["AAA.AU"]["FLAT.AU"]; ok {
+		t.Error("a flat series has no defined correlation and should be excluded")
+	}
+	if matrix["OLD.AU"]["OLD.AU"] != 1 {
+		t.Error("OLD has enough history and should correlate 1 with itself")
+	}
+}
@@ -85,24 +85,171 @@ func RSI(bars []models.EODBar, period int) float64 {
 	return 100 - (100 / (1 + rs))
 }
 
-// MACD calculates Moving Average Convergence Divergence
-// Returns MACD line, Signal line, and Histogram
+// MACD calculates Moving Average Convergence Divergence for the latest bar.
+// Returns MACD line, Signal line (EMA of the MACD line), and Histogram.
+// Returns zeros when there are fewer than slowPeriod bars; the signal line
+// and histogram stay zero until slowPeriod+signalPeriod-1 bars are available.
 func MACD(bars []models.EODBar, fastPeriod, slowPeriod, signalPeriod int) (float64, float64, float64) {
-	if len(bars) < slowPeriod {
+	macd, signal, hist := macdSeries(bars, fastPeriod, slowPeriod, signalPeriod)
+	if len(macd) == 0 {
 		return 0, 0, 0
 	}
+	if len(signal) == 0 {
+		return macd[len(macd)-1], 0, 0
+	}
+	return macd[len(macd)-1], signal[len(signal)-1], hist[len(hist)-1]
+}
 
-	fastEMA := EMA(bars, fastPeriod)
-	slowEMA := EMA(bars, slowPeriod)
-	macdLine := fastEMA - slowEMA
+// MACDCross reports whether the MACD histogram changed sign between the
+// previous and latest bar: "bullish_cross" (turned positive), "bearish_cross"
+// (turned negative), or "" for no cross. Requires slowPeriod+signalPeriod
+// bars (35 for 12/26/9); returns "" with less data.
+func MACDCross(bars []models.EODBar, fastPeriod, slowPeriod, signalPeriod int) string {
+	if len(bars) < slowPeriod+signalPeriod {
+		return ""
+	}
+	_, _, hist := macdSeries(bars, fastPeriod, slowPeriod, signalPeriod)
+	if len(hist) < 2 {
+		return ""
+	}
+	prev, latest := hist[len(hist)-2], hist[len(hist)-1]
+	switch {
+	case prev <= 0 && latest > 0:
+		return "bullish_cross"
+	case prev >= 0 && latest < 0:
+		return "bearish_cross"
+	}
+	return ""
+}
+
+// macdSeries computes MACD, signal and histogram series oldest-first.
+// bars[0] is the most recent bar. Each EMA is seeded with the SMA of its
+// first `period` inputs, matching EMA.
+func macdSeries(bars []models.EODBar, fastPeriod, slowPeriod, signalPeriod int) (macd, signal, hist []float64) {
+	if fastPeriod <= 0 || slowPeriod <= 0 || signalPeriod <= 0 || len(bars) < slowPeriod {
+		return nil, nil, nil
+	}
+
+	closes := make([]float64, len(bars))
+	for i, b := range bars {
+		closes[len(bars)-1-i] = b.Close
+	}
+
+	fast := emaSeries(closes, fastPeriod)
+	slow := emaSeries(closes, slowPeriod)
+	// Align the fast series with the slow one (both end at the latest bar)
+	fast = fast[len(fast)-len(slow):]
+	macd = make([]float64, len(slow))
+	for i := range slow {
+		macd[i] = fast[i] - slow[i]
+	}
 
-	// Calculate signal line (EMA of MACD line)
-	// For simplicity, we'll use a smoothed approximation
-	signalLine := macdLine * 0.9 // Simplified signal
+	signal = emaSeries(macd, signalPeriod)
+	if len(signal) == 0 {
+		return macd, nil, nil
+	}
+	aligned := macd[len(macd)-len(signal):]
+	hist = make([]float64, len(signal))
+	for i := range signal {
+		hist[i] = aligned[i] - signal[i]
+	}
+	return macd, signal, hist
+}
 
-	histogram := macdLine - signalLine
+// emaSeries returns the EMA of values (oldest-first) from the end of the SMA
+// seed window onward: len(values)-period+1 points, or nil if too short.
+func emaSeries(values []float64, period int) []float64 {
+	if len(values) < period {
+		return nil
+	}
+	var sum float64
+	for _, v := range values[:period] {
+		sum += v
+	}
+	multiplier := 2.0 / float64(period+1)
+	out := make([]float64, 0, len(values)-period+1)
+	ema := sum / float64(period)
+	out = append(out, ema)
+	for _, v := range values[period:] {
+		ema = (v-ema)*multiplier + ema
+		out = append(out, ema)
+	}
+	return out
+}
 
-	return macdLine, signalLine, histogram
+// BollingerBands calculates the upper, middle (SMA) and lower bands over
+// period bars at k standard deviations. Returns zeros with fewer than period bars.
+func BollingerBands(bars []models.EODBar, period int, k float64) (upper, middle, lower float64) {
+	if period <= 0 || len(bars) < period {
+		return 0, 0, 0
+	}
+	middle = SMA(bars, period)
+	var variance float64
+	for i := 0; i < period; i++ {
+		d := bars[i].Close - middle
+		variance += d * d
+	}
+	sd := math.Sqrt(variance / float64(period))
+	return middle + k*sd, middle, middle - k*sd
+}
+
+// BollingerWidth returns (upper - lower) / middle, or 0 if unavailable.
+func BollingerWidth(bars []models.EODBar, period int, k float64) float64 {
+	upper, middle, lower := BollingerBands(bars, period, k)
+	if middle == 0 {
+		return 0
+	}
+	return (upper - lower) / middle
+}
+
+// minSqueezeSamples is the fewest band widths needed to rank a squeeze.
+const minSqueezeSamples = 20
+
+// BollingerSqueeze reports whether the latest band width falls in the lowest
+// decile of the widths over the trailing window bars (including the latest).
+// Returns false when fewer than minSqueezeSamples widths are available.
+func BollingerSqueeze(bars []models.EODBar, period int, k float64, window int) bool {
+	widths := make([]float64, 0, window)
+	for i := 0; i < window && len(bars)-i >= period; i++ {
+		widths = append(widths, BollingerWidth(bars[i:], period, k))
+	}
+	if len(widths) < minSqueezeSamples {
+		return false
+	}
+	// Percentile rank: share of widths at or below the latest. A flat
+	// history (all widths equal) ranks at 100%, so it is not a squeeze.
+	latest := widths[0]
+	atOrBelow := 0
+	for _, w := range widths {
+		if w <= latest {
+			atOrBelow++
+		}
+	}
+	return float64(atOrBelow)/float64(len(widths)) <= 0.1
+}
+
+// BollingerBreakout returns "up" or "down" when the latest close is outside
+// the bands and a squeeze occurred within the previous lookback bars, else "".
+func BollingerBreakout(bars []models.EODBar, period int, k float64, window, lookback int) string {
+	if len(bars) < period+1 {
+		return ""
+	}
+	upper, _, lower := BollingerBands(bars, period, k)
+	var direction string
+	switch {
+	case bars[0].Close > upper:
+		direction = "up"
+	case bars[0].Close < lower:
+		direction = "down"
+	default:
+		return ""
+	}
+	for i := 1; i <= lookback && len(bars)-i >= period; i++ {
+		if BollingerSqueeze(bars[i:], period, k, window) {
+			return direction
+		}
+	}
+	return ""
 }
 
 // ATR calculates Average True Range
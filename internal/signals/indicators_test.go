@@ -125,6 +125,135 @@ func TestRSI_InsufficientData(t *testing.T) {
 	assert.Equal(t, 50.0, result) // neutral default
 }
 
+func TestMACD_SignalLineIsEMAOfMACD(t *testing.T) {
+	// Flat series: every EMA equals the price, so MACD, signal and histogram are 0
+	flat := make([]float64, 60)
+	for i := range flat {
+		flat[i] = 10
+	}
+	m, sig, hist := MACD(generateBars(flat), 12, 26, 9)
+	assert.InDelta(t, 0, m, 1e-9)
+	assert.InDelta(t, 0, sig, 1e-9)
+	assert.InDelta(t, 0, hist, 1e-9)
+
+	// Accelerating uptrend: MACD rising, so it sits above its signal line
+	up := make([]float64, 60)
+	for i := range up {
+		up[i] = 100 + float64((59-i)*(59-i))*0.05 // newest-first
+	}
+	m, sig, hist = MACD(generateBars(up), 12, 26, 9)
+	assert.Greater(t, m, 0.0)
+	assert.Greater(t, hist, 0.0)
+	assert.InDelta(t, m-sig, hist, 1e-9)
+}
+
+func TestMACDCross_BothDirections(t *testing.T) {
+	// V-shape (oldest-first): 50 bars falling, then 30 rising, then 30 falling
+	var oldestFirst []float64
+	price := 100.0
+	for i := 0; i < 50; i++ {
+		price -= 1
+		oldestFirst = append(oldestFirst, price)
+	}
+	for i := 0; i < 30; i++ {
+		price += 1.5
+		oldestFirst = append(oldestFirst, price)
+	}
+	for i := 0; i < 30; i++ {
+		price -= 1.5
+		oldestFirst = append(oldestFirst, price)
+	}
+	closes := make([]float64, len(oldestFirst))
+	for i, c := range oldestFirst {
+		closes[len(closes)-1-i] = c
+	}
+	bars := generateBars(closes)
+
+	// Walk back in time: bars[k:] makes bar k the latest
+	found := map[string]bool{}
+	for k := 0; len(bars)-k >= 35; k++ {
+		cross := MACDCross(bars[k:], 12, 26, 9)
+		if cross == "" {
+			continue
+		}
+		found[cross] = true
+		_, _, latest := MACD(bars[k:], 12, 26, 9)
+		_, _, prev := MACD(bars[k+1:], 12, 26, 9)
+		if cross == "bullish_cross" {
+			assert.True(t, prev <= 0 && latest > 0, "bullish cross at k=%d: prev=%f latest=%f", k, prev, latest)
+		} else {
+			assert.True(t, prev >= 0 && latest < 0, "bearish cross at k=%d: prev=%f latest=%f", k, prev, latest)
+		}
+	}
+	assert.True(t, found["bullish_cross"], "expected a bullish cross after the trough")
+	assert.True(t, found["bearish_cross"], "expected a bearish cross after the peak")
+}
+
+func TestMACDCross_InsufficientData(t *testing.T) {
+	closes := make([]float64, 34)
+	for i := range closes {
+		closes[i] = float64(i%2) + 10
+	}
+	assert.Equal(t, "", MACDCross(generateBars(closes), 12, 26, 9))
+}
+
+func TestBollingerBands_KnownValues(t *testing.T) {
+	// Alternating 9/11: mean 10, population std dev 1
+	closes := make([]float64, 20)
+	for i := range closes {
+		closes[i] = 9 + float64(i%2)*2
+	}
+	upper, middle, lower := BollingerBands(generateBars(closes), 20, 2)
+	assert.InDelta(t, 10, middle, 1e-9)
+	assert.InDelta(t, 12, upper, 1e-9)
+	assert.InDelta(t, 8, lower, 1e-9)
+	assert.InDelta(t, 0.4, BollingerWidth(generateBars(closes), 20, 2), 1e-9)
+}
+
+func TestBollinger_InsufficientData(t *testing.T) {
+	bars := generateBars([]float64{10, 11, 12, 11, 10})
+	upper, middle, lower := BollingerBands(bars, 20, 2)
+	assert.Equal(t, 0.0, upper+middle+lower)
+	assert.False(t, BollingerSqueeze(bars, 20, 2, 126))
+	assert.Equal(t, "", BollingerBreakout(bars, 20, 2, 126, 5))
+}
+
+// squeezeCloses returns newest-first closes: `calm` quiet bars (±0.1) after
+// 150 volatile bars (±5), optionally ending with a jump on the latest bar.
+func squeezeCloses(calm int, jump float64) []float64 {
+	var oldestFirst []float64
+	for i := 0; i < 150; i++ {
+		oldestFirst = append(oldestFirst, 100+float64(i%2*2-1)*5)
+	}
+	for i := 0; i < calm; i++ {
+		oldestFirst = append(oldestFirst, 100+float64(i%2*2-1)*0.1)
+	}
+	if jump != 0 {
+		oldestFirst = append(oldestFirst, 100+jump)
+	}
+	closes := make([]float64, len(oldestFirst))
+	for i, c := range oldestFirst {
+		closes[len(closes)-1-i] = c
+	}
+	return closes
+}
+
+func TestBollingerSqueeze_LowestDecile(t *testing.T) {
+	// Volatile throughout: latest width is typical, no squeeze
+	assert.False(t, BollingerSqueeze(generateBars(squeezeCloses(0, 0)), 20, 2, 126))
+
+	// 25 calm bars: latest width is among the narrowest of the window
+	assert.True(t, BollingerSqueeze(generateBars(squeezeCloses(25, 0)), 20, 2, 126))
+}
+
+func TestBollingerBreakout_AfterSqueeze(t *testing.T) {
+	assert.Equal(t, "up", BollingerBreakout(generateBars(squeezeCloses(25, 3)), 20, 2, 126, 5))
+	assert.Equal(t, "down", BollingerBreakout(generateBars(squeezeCloses(25, -3)), 20, 2, 126, 5))
+
+	// Close outside the bands without a prior squeeze is not a breakout
+	assert.Equal(t, "", BollingerBreakout(generateBars(squeezeCloses(0, 30)), 20, 2, 126, 5))
+}
+
 func TestClassifyRSI(t *testing.T) {
 	tests := []struct {
 		rsi      float64
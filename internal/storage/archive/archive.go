@@ -0,0 +1,156 @@
+// Package archive reads and writes Vire backup archives: a gzip-compressed
+// JSON-lines stream whose first line is a Header and whose remaining lines
+// each hold one stored record tagged with its table.
+package archive
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+const (
+	// Format identifies a Vire backup archive.
+	Format = "vire-backup"
+	// FormatVersion is bumped when the archive layout itself changes.
+	FormatVersion = 1
+)
+
+// ErrNewerSchema is returned when an archive was written by a binary with a
+// newer data schema than the one reading it.
+var ErrNewerSchema = errors.New("archive schema version is newer than this binary")
+
+// ErrOlderSchema is returned when an archive was written by a binary with an
+// older data schema. Schema migrations run against the live store at
+// startup, not against archives, so such an archive is refused rather than
+// restored in its old shape.
+var ErrOlderSchema = errors.New("archive schema version is older than this binary")
+
+// Header is the first line of every archive.
+type Header struct {
+	Format        string    `json:"format"`
+	FormatVersion int       `json:"format_version"`
+	SchemaVersion string    `json:"schema_version"`
+	AppVersion    string    `json:"app_version,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type entry struct {
+	Table  string          `json:"table"`
+	Record json.RawMessage `json:"record"`
+}
+
+// Writer streams records into an archive. Close must be called to flush it.
+type Writer struct {
+	gz     *gzip.Writer
+	enc    *json.Encoder
+	counts map[string]int
+}
+
+// NewWriter writes h (with Format and FormatVersion filled in) to w and
+// returns a Writer for the records that follow.
+func NewWriter(w io.Writer, h Header) (*Writer, error) {
+	h.Format = Format
+	h.FormatVersion = FormatVersion
+	gz := gzip.NewWriter(w)
+	enc := json.NewEncoder(gz)
+	if err := enc.Encode(h); err != nil {
+		return nil, fmt.Errorf("failed to write archive header: %w", err)
+	}
+	return &Writer{gz: gz, enc: enc, counts: make(map[string]int)}, nil
+}
+
+// Write appends one record belonging to table.
+func (w *Writer) Write(table string, record any) error {
+	raw, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s record: %w", table, err)
+	}
+	if err := w.enc.Encode(entry{Table: table, Record: raw}); err != nil {
+		return fmt.Errorf("failed to write %s record: %w", table, err)
+	}
+	w.counts[table]++
+	return nil
+}
+
+// Counts returns the number of records written per table.
+func (w *Writer) Counts() map[string]int {
+	return w.counts
+}
+
+// Close flushes the archive. It does not close the underlying writer.
+func (w *Writer) Close() error {
+	return w.gz.Close()
+}
+
+// Reader iterates the records of an archive.
+type Reader struct {
+	Header Header
+
+	gz  *gzip.Reader
+	dec *json.Decoder
+}
+
+// NewReader reads and checks the archive header. Archives whose schema
+// version differs from currentSchema are refused (see CheckSchema).
+func NewReader(r io.Reader, currentSchema string) (*Reader, error) {
+	gz, err := gzip.NewReader(bufio.NewReader(r))
+	if err != nil {
+		return nil, fmt.Errorf("not a backup archive: %w", err)
+	}
+	dec := json.NewDecoder(gz)
+	var h Header
+	if err := dec.Decode(&h); err != nil {
+		return nil, fmt.Errorf("failed to read archive header: %w", err)
+	}
+	if h.Format != Format {
+		return nil, fmt.Errorf("not a backup archive (format %q)", h.Format)
+	}
+	if h.FormatVersion > FormatVersion {
+		return nil, fmt.Errorf("archive format version %d is newer than supported version %d", h.FormatVersion, FormatVersion)
+	}
+	if err := CheckSchema(h.SchemaVersion, currentSchema); err != nil {
+		return nil, err
+	}
+	return &Reader{Header: h, gz: gz, dec: dec}, nil
+}
+
+// Next returns the next record and its table, or io.EOF at the end.
+func (r *Reader) Next() (string, json.RawMessage, error) {
+	var e entry
+	if err := r.dec.Decode(&e); err != nil {
+		if errors.Is(err, io.EOF) {
+			return "", nil, io.EOF
+		}
+		return "", nil, fmt.Errorf("corrupt archive: %w", err)
+	}
+	if e.Table == "" {
+		return "", nil, fmt.Errorf("corrupt archive: record without table")
+	}
+	return e.Table, e.Record, nil
+}
+
+// CheckSchema reports whether an archive written at schema version archived
+// can be restored by a binary at version current.
+func CheckSchema(archived, current string) error {
+	a, err := strconv.Atoi(archived)
+	if err != nil {
+		return fmt.Errorf("archive has unrecognised schema version %q", archived)
+	}
+	c, err := strconv.Atoi(current)
+	if err != nil {
+		return fmt.Errorf("unrecognised current schema version %q", current)
+	}
+	if a > c {
+		return fmt.Errorf("%w: archive %s, binary %s; upgrade before restoring", ErrNewerSchema, archived, current)
+	}
+	if a < c {
+		return fmt.Errorf("%w: archive %s, binary %s; restore it with a schema %s binary and upgrade so startup migrations convert it", ErrOlderSchema, archived, current, archived)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testRecord struct {
+	Ticker string  `json:"ticker"`
+	Close  float64 `json:"close"`
+}
+
+func writeArchive(t *testing.T, schema string, records map[string][]testRecord) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, Header{SchemaVersion: schema, CreatedAt: time.Now()})
+	if err != nil {
+		t.Fatalf("NewWriter: %v", err)
+	}
+	for table, recs := range records {
+		for _, r := range recs {
+			if err := w.Write(table, r); err != nil {
+				t.Fatalf("Write: %v", err)
+			}
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestArchive_RoundTrip(t *testing.T) {
+	in := map[string][]testRecord{
+		"market_data": {{"BHP.AU", 45.1}, {"CBA.AU", 120}},
+		"signals":     {{"BHP.AU", 0}},
+	}
+	data := writeArchive(t, "16", in)
+
+	r, err := NewReader(bytes.NewReader(data), "16")
+	if err != nil {
+		t.Fatalf("NewReader: %v", err)
+	}
+	if r.Header.Format != Format || r.Header.FormatVersion != FormatVersion || r.Header.SchemaVersion != "16" {
+		t.Errorf("header = %+v", r.Header)
+	}
+
+	got := map[string][]testRecord{}
+	for {
+		table, raw, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Next: %v", err)
+		}
+		var rec testRecord
+		if err := json.Unmarshal(raw, &rec); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		got[table] = append(got[table], rec)
+	}
+	for table, recs := range in {
+		if len(got[table]) != len(recs) {
+			t.Fatalf("%s: got %d records, want %d", table, len(got[table]), len(recs))
+		}
+		for i := range recs {
+			if got[table][i] != recs[i] {
+				t.Errorf("%s[%d] = %+v, want %+v", table, i, got[table][i], recs[i])
+			}
+		}
+	}
+}
+
+func TestArchive_SchemaVersions(t *testing.T) {
+	tests := []struct {
+		archived, current string
+		wantErr           string
+	}{
+		{"16", "16", ""},
+		{"15", "16", "older"},
+		{"17", "16", "newer"},
+		{"", "16", "unrecognised schema version"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.archived+"->"+tt.current, func(t *testing.T) {
+			data := writeArchive(t, tt.archived, nil)
+			_, err := NewReader(bytes.NewReader(data), tt.current)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("NewReader: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("NewReader error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	data := writeArchive(t, "17", nil)
+	if _, err := NewReader(bytes.NewReader(data), "16"); !errors.Is(err, ErrNewerSchema) {
+		t.Errorf("newer archive error = %v, want ErrNewerSchema", err)
+	}
+	data = writeArchive(t, "15", nil)
+	if _, err := NewReader(bytes.NewReader(data), "16"); !errors.Is(err, ErrOlderSchema) {
+		t.Errorf("older archive error = %v, want ErrOlderSchema", err)
+	}
+}
+
+func TestArchive_RejectsForeignInput(t *testing.T) {
+	if _, err := NewReader(strings.NewReader(`{"key":"x"}`), "16"); err == nil {
+		t.Error("plain JSON accepted as an archive")
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	gz.Write([]byte(`{"format":"something-else","schema_version":"16"}` + "\n"))
+	gz.Close()
+	if _, err := NewReader(&buf, "16"); err == nil || !strings.Contains(err.Error(), "not a backup archive") {
+		t.Errorf("foreign gzip error = %v, want not a backup archive", err)
+	}
+
+	buf.Reset()
+	gz = gzip.NewWriter(&buf)
+	gz.Write([]byte(`{"format":"vire-backup","format_version":1,"schema_version":"16"}` + "\n" + `{"table":"market_data","record":` + "\n"))
+	gz.Close()
+	r, err := NewReader(&buf, "16")
+	if err != nil {
+		t.Fatalf("NewReader: %v", err)
+	}
+	if _, _, err := r.Next(); err == nil || errors.Is(err, io.EOF) || !strings.Contains(err.Error(), "corrupt archive") {
+		t.Errorf("truncated record error = %v, want corrupt archive", err)
+	}
+}
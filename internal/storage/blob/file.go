@@ -7,9 +7,11 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/bobmcallan/vire/internal/common"
 	"github.com/bobmcallan/vire/internal/interfaces"
+	"github.com/bobmcallan/vire/internal/models"
 )
 
 // FileSystemStore implements interfaces.FileStore using local filesystem.
@@ -19,8 +21,21 @@ type FileSystemStore struct {
 }
 
 // fileMeta is the sidecar metadata stored alongside each blob file.
+// Sidecars written before timestamps were tracked have zero times.
 type fileMeta struct {
-	ContentType string `json:"content_type"`
+	ContentType string    `json:"content_type"`
+	CreatedAt   time.Time `json:"created_at,omitempty"`
+	UpdatedAt   time.Time `json:"updated_at,omitempty"`
+}
+
+// readMeta reads the sidecar for the blob at p. A missing or unreadable
+// sidecar yields the zero value.
+func readMeta(p string) fileMeta {
+	var meta fileMeta
+	if b, err := os.ReadFile(p + ".meta"); err == nil {
+		_ = json.Unmarshal(b, &meta)
+	}
+	return meta
 }
 
 // Compile-time check
@@ -88,8 +103,12 @@ func (s *FileSystemStore) SaveFile(ctx context.Context, category, key string, da
 		return fmt.Errorf("failed to persist blob %s/%s: %w", category, key, err)
 	}
 
-	// Write sidecar metadata
-	meta := fileMeta{ContentType: contentType}
+	// Write sidecar metadata, keeping the first save's created_at
+	now := time.Now()
+	meta := fileMeta{ContentType: contentType, CreatedAt: readMeta(p).CreatedAt, UpdatedAt: now}
+	if meta.CreatedAt.IsZero() {
+		meta.CreatedAt = now
+	}
 	metaBytes, err := json.Marshal(meta)
 	if err != nil {
 		return fmt.Errorf("failed to marshal metadata for %s/%s: %w", category, key, err)
@@ -122,12 +141,8 @@ func (s *FileSystemStore) GetFile(ctx context.Context, category, key string) ([]
 
 	// Read sidecar metadata for content type
 	contentType := "application/octet-stream"
-	metaBytes, err := os.ReadFile(p + ".meta")
-	if err == nil {
-		var meta fileMeta
-		if json.Unmarshal(metaBytes, &meta) == nil && meta.ContentType != "" {
-			contentType = meta.ContentType
-		}
+	if meta := readMeta(p); meta.ContentType != "" {
+		contentType = meta.ContentType
 	}
 
 	return data, contentType, nil
@@ -163,3 +178,40 @@ func (s *FileSystemStore) HasFile(ctx context.Context, category, key string) (bo
 	}
 	return false, fmt.Errorf("failed to stat blob %s/%s: %w", category, key, err)
 }
+
+// StatFile returns a blob's metadata from its sidecar. Blobs whose sidecar
+// predates timestamps report the file's modification time for both.
+func (s *FileSystemStore) StatFile(ctx context.Context, category, key string) (*models.FileMeta, error) {
+	p, err := s.blobPath(category, key)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(p)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("file not found: %s/%s", category, key)
+		}
+		return nil, fmt.Errorf("failed to stat blob %s/%s: %w", category, key, err)
+	}
+
+	meta := readMeta(p)
+	fm := &models.FileMeta{
+		Category:    category,
+		Key:         key,
+		ContentType: meta.ContentType,
+		Size:        info.Size(),
+		CreatedAt:   meta.CreatedAt,
+		UpdatedAt:   meta.UpdatedAt,
+	}
+	if fm.ContentType == "" {
+		fm.ContentType = "application/octet-stream"
+	}
+	if fm.UpdatedAt.IsZero() {
+		fm.UpdatedAt = info.ModTime()
+	}
+	if fm.CreatedAt.IsZero() {
+		fm.CreatedAt = fm.UpdatedAt
+	}
+	return fm, nil
+}
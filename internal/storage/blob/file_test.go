@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/bobmcallan/vire/internal/common"
 	"github.com/stretchr/testify/assert"
@@ -86,6 +87,28 @@ func TestFileSystemStore_OverwriteExisting(t *testing.T) {
 	assert.True(t, bytes.Equal(got, newData), "overwrite failed")
 }
 
+func TestFileSystemStore_OverwriteKeepsCreatedAt(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	require.NoError(t, store.SaveFile(ctx, "chart", "port/chart.png", []byte("v1"), "image/png"))
+	first, err := store.StatFile(ctx, "chart", "port/chart.png")
+	require.NoError(t, err)
+
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, store.SaveFile(ctx, "chart", "port/chart.png", []byte("version 2"), "image/png"))
+	second, err := store.StatFile(ctx, "chart", "port/chart.png")
+	require.NoError(t, err)
+
+	assert.True(t, second.CreatedAt.Equal(first.CreatedAt), "created_at changed on overwrite")
+	assert.True(t, second.UpdatedAt.After(first.UpdatedAt), "updated_at did not advance")
+	assert.Equal(t, int64(9), second.Size)
+	assert.Equal(t, "image/png", second.ContentType)
+
+	_, err = store.StatFile(ctx, "chart", "port/missing.png")
+	assert.Error(t, err)
+}
+
 func TestFileSystemStore_NestedKeys(t *testing.T) {
 	store := newTestStore(t)
 	ctx := context.Background()
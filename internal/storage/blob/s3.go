@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
@@ -14,6 +15,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/bobmcallan/vire/internal/common"
 	"github.com/bobmcallan/vire/internal/interfaces"
+	"github.com/bobmcallan/vire/internal/models"
 )
 
 // S3Store implements interfaces.FileStore using any S3-compatible object storage.
@@ -76,12 +78,30 @@ func (s *S3Store) objectKey(category, key string) string {
 	return category + "/" + key
 }
 
+// createdAtMetaKey is the user-metadata key holding an object's first save
+// time, since S3 only tracks the last modification.
+const createdAtMetaKey = "created-at"
+
 func (s *S3Store) SaveFile(ctx context.Context, category, key string, data []byte, contentType string) error {
+	objectKey := s.objectKey(category, key)
+
+	// Carry the first save's created-at over an overwrite
+	createdAt := time.Now().UTC().Format(time.RFC3339Nano)
+	if head, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(objectKey),
+	}); err == nil {
+		if v := head.Metadata[createdAtMetaKey]; v != "" {
+			createdAt = v
+		}
+	}
+
 	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
 		Bucket:      aws.String(s.bucket),
-		Key:         aws.String(s.objectKey(category, key)),
+		Key:         aws.String(objectKey),
 		Body:        bytes.NewReader(data),
 		ContentType: aws.String(contentType),
+		Metadata:    map[string]string{createdAtMetaKey: createdAt},
 	})
 	if err != nil {
 		return fmt.Errorf("failed to save blob %s/%s: %w", category, key, err)
@@ -143,3 +163,36 @@ func (s *S3Store) HasFile(ctx context.Context, category, key string) (bool, erro
 	}
 	return true, nil
 }
+
+// StatFile returns an object's metadata from a HEAD request. Objects saved
+// without created-at metadata report their last modification for both times.
+func (s *S3Store) StatFile(ctx context.Context, category, key string) (*models.FileMeta, error) {
+	head, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.objectKey(category, key)),
+	})
+	if err != nil {
+		var nsk *types.NoSuchKey
+		var notFound *types.NotFound
+		if errors.As(err, &nsk) || errors.As(err, &notFound) {
+			return nil, fmt.Errorf("file not found: %s/%s", category, key)
+		}
+		return nil, fmt.Errorf("failed to stat blob %s/%s: %w", category, key, err)
+	}
+
+	fm := &models.FileMeta{
+		Category:    category,
+		Key:         key,
+		ContentType: aws.ToString(head.ContentType),
+		Size:        aws.ToInt64(head.ContentLength),
+		UpdatedAt:   aws.ToTime(head.LastModified),
+	}
+	if fm.ContentType == "" {
+		fm.ContentType = "application/octet-stream"
+	}
+	fm.CreatedAt = fm.UpdatedAt
+	if t, err := time.Parse(time.RFC3339Nano, head.Metadata[createdAtMetaKey]); err == nil {
+		fm.CreatedAt = t
+	}
+	return fm, nil
+}
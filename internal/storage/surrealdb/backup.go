@@ -0,0 +1,254 @@
+package surrealdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/models"
+	"github.com/bobmcallan/vire/internal/storage/archive"
+	"github.com/surrealdb/surrealdb.go"
+	surrealmodels "github.com/surrealdb/surrealdb.go/pkg/models"
+)
+
+// backupPageSize bounds each SELECT during a backup; market data records
+// carry years of EOD bars, so whole-table reads are avoided.
+const backupPageSize = 200
+
+// backupTables lists the tables written to an archive, in restore order.
+var backupTables = []string{"user_data", "market_data", "signals", "stock_index"}
+
+// Backup implements interfaces.StorageManager.
+func (m *Manager) Backup(ctx context.Context, w io.Writer) (*models.BackupManifest, error) {
+	manifest := &models.BackupManifest{
+		SchemaVersion: common.SchemaVersion,
+		AppVersion:    common.GetVersion(),
+		CreatedAt:     time.Now().UTC(),
+	}
+	aw, err := archive.NewWriter(w, archive.Header{
+		SchemaVersion: manifest.SchemaVersion,
+		AppVersion:    manifest.AppVersion,
+		CreatedAt:     manifest.CreatedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, table := range backupTables {
+		var err error
+		switch table {
+		case "user_data":
+			err = exportTable[models.UserRecord](ctx, m.db, table, aw)
+		case "market_data":
+			err = exportTable[models.MarketData](ctx, m.db, table, aw)
+		case "signals":
+			err = exportTable[models.TickerSignals](ctx, m.db, table, aw)
+		case "stock_index":
+			err = exportTable[models.StockIndexEntry](ctx, m.db, table, aw)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := aw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finish archive: %w", err)
+	}
+
+	manifest.Records = aw.Counts()
+	m.logger.Info().
+		Int("user_data", manifest.Records["user_data"]).
+		Int("market_data", manifest.Records["market_data"]).
+		Int("signals", manifest.Records["signals"]).
+		Int("stock_index", manifest.Records["stock_index"]).
+		Msg("Backup written")
+	return manifest, nil
+}
+
+func exportTable[T any](ctx context.Context, db *surrealdb.DB, table string, aw *archive.Writer) error {
+	sql := fmt.Sprintf("SELECT * FROM %s ORDER BY id LIMIT $limit START $start", table)
+	for start := 0; ; start += backupPageSize {
+		vars := map[string]any{"limit": backupPageSize, "start": start}
+		results, err := surrealdb.Query[[]T](ctx, db, sql, vars)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", table, err)
+		}
+		if results == nil || len(*results) == 0 {
+			return nil
+		}
+		page := (*results)[0].Result
+		for i := range page {
+			if err := aw.Write(table, &page[i]); err != nil {
+				return err
+			}
+		}
+		if len(page) < backupPageSize {
+			return nil
+		}
+	}
+}
+
+// restoreStagingTable holds the records of an archive being restored until
+// they are swapped into their tables in one transaction.
+const restoreStagingTable = "backup_restore"
+
+// restoreSwapSQL empties every backed-up table, writes each staged record
+// under its target record ID and clears the staging table, all or nothing:
+// the tables end up exactly as the archive recorded them.
+var restoreSwapSQL = "BEGIN TRANSACTION;\n" +
+	"DELETE " + strings.Join(backupTables, ";\nDELETE ") + ";\n" +
+	`FOR $row IN (SELECT target, doc FROM backup_restore) {
+	LET $target = $row.target;
+	UPSERT $target CONTENT $row.doc;
+};
+DELETE backup_restore;
+COMMIT TRANSACTION;`
+
+// stagedRecord is one decoded archive record and the record ID it is
+// restored under.
+type stagedRecord struct {
+	Target surrealmodels.RecordID `json:"target"`
+	Doc    any                    `json:"doc"`
+}
+
+func decodeRecord[T any](table string, raw json.RawMessage) (*T, error) {
+	v := new(T)
+	if err := json.Unmarshal(raw, v); err != nil {
+		return nil, fmt.Errorf("corrupt %s record: %w", table, err)
+	}
+	return v, nil
+}
+
+// stageRecord decodes one archive record and returns it with the record ID
+// the live stores would write it under. ok is false for tables Restore does
+// not handle.
+func stageRecord(table string, raw json.RawMessage) (staged stagedRecord, ok bool, err error) {
+	switch table {
+	case "user_data":
+		rec, err := decodeRecord[models.UserRecord](table, raw)
+		if err != nil {
+			return staged, true, err
+		}
+		return stagedRecord{surrealmodels.NewRecordID(table, recordID(rec.UserID, rec.Subject, rec.Key)), rec}, true, nil
+	case "market_data":
+		md, err := decodeRecord[models.MarketData](table, raw)
+		if err != nil {
+			return staged, true, err
+		}
+		md.EOD = models.NormalizeEOD(md.EOD)
+		return stagedRecord{surrealmodels.NewRecordID(table, md.Ticker), md}, true, nil
+	case "signals":
+		sig, err := decodeRecord[models.TickerSignals](table, raw)
+		if err != nil {
+			return staged, true, err
+		}
+		return stagedRecord{surrealmodels.NewRecordID(table, sig.Ticker), sig}, true, nil
+	case "stock_index":
+		// Written as-is rather than via Upsert, which would reset the
+		// added/last-seen and collection timestamps.
+		entry, err := decodeRecord[models.StockIndexEntry](table, raw)
+		if err != nil {
+			return staged, true, err
+		}
+		return stagedRecord{surrealmodels.NewRecordID(table, tickerToID(entry.Ticker)), entry}, true, nil
+	}
+	return staged, false, nil
+}
+
+// Restore implements interfaces.StorageManager. The archive is decoded one
+// record at a time into a staging table, so memory use does not grow with
+// the archive; the staged records are then swapped into their tables in a
+// single transaction that first empties the backed-up tables, so records
+// created after the backup are removed. A corrupt archive or a failed write
+// leaves the live tables untouched.
+func (m *Manager) Restore(ctx context.Context, r io.Reader) (*models.BackupManifest, error) {
+	ar, err := archive.NewReader(r, common.SchemaVersion)
+	if err != nil {
+		return nil, err
+	}
+	manifest := &models.BackupManifest{
+		SchemaVersion: ar.Header.SchemaVersion,
+		AppVersion:    ar.Header.AppVersion,
+		CreatedAt:     ar.Header.CreatedAt,
+		Records:       make(map[string]int),
+	}
+
+	if err := m.clearRestoreStaging(ctx); err != nil {
+		return manifest, err
+	}
+	defer func() {
+		// The swap empties the staging table; this only matters after a failure
+		if err := m.clearRestoreStaging(context.WithoutCancel(ctx)); err != nil {
+			m.logger.Warn().Err(err).Msg("Failed to clear restore staging table")
+		}
+	}()
+
+	staged := make(map[string]int)
+	batch := make([]stagedRecord, 0, backupPageSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		sql := "INSERT INTO " + restoreStagingTable + " $rows"
+		if _, err := surrealdb.Query[any](ctx, m.db, sql, map[string]any{"rows": batch}); err != nil {
+			return fmt.Errorf("failed to stage restore records: %w", err)
+		}
+		batch = batch[:0]
+		return nil
+	}
+	for {
+		table, raw, err := ar.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		rec, ok, err := stageRecord(table, raw)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			if manifest.Skipped == nil {
+				manifest.Skipped = make(map[string]int)
+			}
+			manifest.Skipped[table]++
+			continue
+		}
+		batch = append(batch, rec)
+		staged[table]++
+		if len(batch) == backupPageSize {
+			if err := flush(); err != nil {
+				return manifest, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return manifest, err
+	}
+
+	if _, err := surrealdb.Query[any](ctx, m.db, restoreSwapSQL, nil); err != nil {
+		return manifest, fmt.Errorf("failed to swap in restored records: %w", err)
+	}
+	manifest.Records = staged
+
+	m.logger.Info().
+		Str("schema_version", manifest.SchemaVersion).
+		Int("user_data", manifest.Records["user_data"]).
+		Int("market_data", manifest.Records["market_data"]).
+		Int("signals", manifest.Records["signals"]).
+		Int("stock_index", manifest.Records["stock_index"]).
+		Msg("Backup restored")
+	return manifest, nil
+}
+
+func (m *Manager) clearRestoreStaging(ctx context.Context) error {
+	if _, err := surrealdb.Query[any](ctx, m.db, "DELETE "+restoreStagingTable, nil); err != nil {
+		return fmt.Errorf("failed to clear restore staging table: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package surrealdb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/models"
+	"github.com/bobmcallan/vire/internal/storage/archive"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/surrealdb/surrealdb.go"
+)
+
+func TestBackupRestore_RoundTrip(t *testing.T) {
+	mgr, _ := testManagerWithBlob(t)
+	ctx := context.Background()
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+
+	records := []*models.UserRecord{
+		{UserID: "u1", Subject: "portfolio", Key: "SMSF", Value: `{"name":"SMSF"}`, Version: 3, DateTime: day},
+		{UserID: "u1", Subject: "strategy", Key: "SMSF", Value: `{"risk":"moderate"}`, Version: 1, DateTime: day},
+		{UserID: "u1", Subject: "plan", Key: "SMSF", Value: `{"items":[]}`, Version: 2, DateTime: day},
+		{UserID: "u2", Subject: "report", Key: "Personal", Value: `{"summary":"ok"}`, Version: 1, DateTime: day},
+	}
+	for _, rec := range records {
+		require.NoError(t, mgr.userStore.Put(ctx, rec))
+	}
+	md := &models.MarketData{Ticker: "BHP.AU", Exchange: "AU", EOD: []models.EODBar{{Date: day, Close: 45.1}}}
+	require.NoError(t, mgr.marketStore.SaveMarketData(ctx, md))
+	require.NoError(t, mgr.marketStore.SaveSignals(ctx, &models.TickerSignals{Ticker: "BHP.AU", ComputeTimestamp: day}))
+	require.NoError(t, mgr.stockIndexStore.Upsert(ctx, &models.StockIndexEntry{Ticker: "BHP.AU", Code: "BHP", Exchange: "AU", Source: "portfolio"}))
+	require.NoError(t, mgr.stockIndexStore.UpdateTimestamp(ctx, "BHP.AU", "eod_collected_at", day))
+	indexBefore, err := mgr.stockIndexStore.Get(ctx, "BHP.AU")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	manifest, err := mgr.Backup(ctx, &buf)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"user_data": 4, "market_data": 1, "signals": 1, "stock_index": 1}, manifest.Records)
+
+	// Wipe every backed-up table
+	for _, table := range backupTables {
+		_, err := surrealdb.Query[any](ctx, mgr.db, "DELETE "+table, nil)
+		require.NoError(t, err)
+	}
+	_, err = mgr.userStore.Get(ctx, "u1", "strategy", "SMSF")
+	require.Error(t, err, "wipe should remove user data")
+
+	restored, err := mgr.Restore(ctx, bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, manifest.Records, restored.Records)
+
+	for _, want := range records {
+		got, err := mgr.userStore.Get(ctx, want.UserID, want.Subject, want.Key)
+		require.NoError(t, err, "%s/%s", want.Subject, want.Key)
+		assert.Equal(t, want.Value, got.Value)
+		assert.Equal(t, want.Version, got.Version)
+		assert.True(t, want.DateTime.Equal(got.DateTime))
+	}
+	gotMD, err := mgr.marketStore.GetMarketData(ctx, "BHP.AU")
+	require.NoError(t, err)
+	require.Len(t, gotMD.EOD, 1)
+	assert.Equal(t, 45.1, gotMD.EOD[0].Close)
+	gotSig, err := mgr.marketStore.GetSignals(ctx, "BHP.AU")
+	require.NoError(t, err)
+	assert.True(t, day.Equal(gotSig.ComputeTimestamp))
+	gotIdx, err := mgr.stockIndexStore.Get(ctx, "BHP.AU")
+	require.NoError(t, err)
+	assert.True(t, indexBefore.AddedAt.Equal(gotIdx.AddedAt), "added_at should survive restore")
+	assert.True(t, day.Equal(gotIdx.EODCollectedAt), "collection timestamps should survive restore")
+}
+
+func TestRestore_RemovesRecordsCreatedAfterBackup(t *testing.T) {
+	mgr, _ := testManagerWithBlob(t)
+	ctx := context.Background()
+
+	require.NoError(t, mgr.userStore.Put(ctx, &models.UserRecord{UserID: "u1", Subject: "strategy", Key: "SMSF", Value: `{"risk":"moderate"}`}))
+	var buf bytes.Buffer
+	_, err := mgr.Backup(ctx, &buf)
+	require.NoError(t, err)
+
+	require.NoError(t, mgr.userStore.Put(ctx, &models.UserRecord{UserID: "u1", Subject: "strategy", Key: "SMSF", Value: `{"risk":"high"}`}))
+	require.NoError(t, mgr.userStore.Put(ctx, &models.UserRecord{UserID: "u1", Subject: "note", Key: "later", Value: "{}"}))
+	require.NoError(t, mgr.marketStore.SaveMarketData(ctx, &models.MarketData{Ticker: "CBA.AU", Exchange: "AU"}))
+
+	_, err = mgr.Restore(ctx, bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+
+	got, err := mgr.userStore.Get(ctx, "u1", "strategy", "SMSF")
+	require.NoError(t, err)
+	assert.Equal(t, `{"risk":"moderate"}`, got.Value)
+	_, err = mgr.userStore.Get(ctx, "u1", "note", "later")
+	assert.Error(t, err, "a record created after the backup should be removed")
+	_, err = mgr.marketStore.GetMarketData(ctx, "CBA.AU")
+	assert.Error(t, err, "market data fetched after the backup should be removed")
+}
+
+func TestRestore_RefusesOtherSchemas(t *testing.T) {
+	mgr, _ := testManagerWithBlob(t)
+	ctx := context.Background()
+
+	for version, want := range map[string]error{"9999": archive.ErrNewerSchema, "1": archive.ErrOlderSchema} {
+		var buf bytes.Buffer
+		w, err := archive.NewWriter(&buf, archive.Header{SchemaVersion: version, CreatedAt: time.Now()})
+		require.NoError(t, err)
+		require.NoError(t, w.Write("user_data", &models.UserRecord{UserID: "u1", Subject: "strategy", Key: "SMSF", Value: "{}"}))
+		require.NoError(t, w.Close())
+
+		_, err = mgr.Restore(ctx, &buf)
+		require.True(t, errors.Is(err, want), "schema %s: got %v", version, err)
+		_, err = mgr.userStore.Get(ctx, "u1", "strategy", "SMSF")
+		assert.Error(t, err, "nothing should be written from a refused archive")
+	}
+}
+
+func TestRestore_CorruptRecordWritesNothing(t *testing.T) {
+	mgr, _ := testManagerWithBlob(t)
+	ctx := context.Background()
+
+	// More than one staging batch is written before the corrupt record
+	var buf bytes.Buffer
+	w, err := archive.NewWriter(&buf, archive.Header{SchemaVersion: common.SchemaVersion, CreatedAt: time.Now()})
+	require.NoError(t, err)
+	for i := 0; i < backupPageSize+1; i++ {
+		require.NoError(t, w.Write("user_data", &models.UserRecord{UserID: "u1", Subject: "note", Key: fmt.Sprintf("n%d", i), Value: "{}"}))
+	}
+	require.NoError(t, w.Write("market_data", "not a market data record"))
+	require.NoError(t, w.Close())
+
+	manifest, err := mgr.Restore(ctx, &buf)
+	require.Error(t, err)
+	assert.Nil(t, manifest, "a corrupt archive is a validation failure")
+	_, err = mgr.userStore.Get(ctx, "u1", "note", "n0")
+	assert.Error(t, err, "records staged before the corrupt one should not be restored")
+
+	staged, err := surrealdb.Query[[]map[string]any](ctx, mgr.db, "SELECT * FROM "+restoreStagingTable, nil)
+	require.NoError(t, err)
+	assert.Empty(t, (*staged)[0].Result, "staging table should be cleared")
+}
@@ -2,13 +2,18 @@ package surrealdb
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/bobmcallan/vire/internal/common"
 	"github.com/bobmcallan/vire/internal/interfaces"
+	"github.com/bobmcallan/vire/internal/models"
 	"github.com/surrealdb/surrealdb.go"
 	surrealmodels "github.com/surrealdb/surrealdb.go/pkg/models"
 )
@@ -35,11 +40,73 @@ func NewFileStore(db *surrealdb.DB, logger *common.Logger) *FileStore {
 	return &FileStore{db: db, logger: logger}
 }
 
-// fileRecordID builds a SurrealDB record ID from category and key.
-// Sanitizes dots and slashes to underscores for safe record IDs.
+// fileRecordID builds a SurrealDB record ID from category and key: the
+// sanitized category for readability, "::", then a SHA-256 of the
+// length-prefixed category and key. The length prefix makes the pair
+// unambiguous, and the hash keeps IDs short and free of null bytes or other
+// unsafe characters however long or hostile the key is.
 func fileRecordID(category, key string) string {
-	sanitized := strings.NewReplacer(".", "_", "/", "_").Replace(category + "_" + key)
-	return sanitized
+	h := sha256.Sum256([]byte(strconv.Itoa(len(category)) + ":" + category + key))
+	prefix := strings.Map(func(r rune) rune {
+		if r == '-' || r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return '_'
+	}, category)
+	return prefix + "::" + hex.EncodeToString(h[:])
+}
+
+// legacyFileRecordID is the pre-"::" record ID, which collided whenever two
+// category+key pairs sanitized to the same string. Only MigrateRecordIDs uses it.
+func legacyFileRecordID(category, key string) string {
+	return strings.NewReplacer(".", "_", "/", "_").Replace(category + "_" + key)
+}
+
+// MigrateRecordIDs moves records stored under legacy record IDs to the
+// current scheme. It is a one-time, idempotent migration: records already on
+// the current ID are left alone. Where the legacy scheme made two files share
+// a record, only the surviving file is moved. Returns the number moved.
+func (s *FileStore) MigrateRecordIDs(ctx context.Context) (int, error) {
+	results, err := surrealdb.Query[[]struct {
+		Category string `json:"category"`
+		Key      string `json:"key"`
+	}](ctx, s.db, "SELECT category, key FROM files", nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list files for migration: %w", err)
+	}
+	if results == nil || len(*results) == 0 {
+		return 0, nil
+	}
+
+	moved := 0
+	for _, f := range (*results)[0].Result {
+		legacy := legacyFileRecordID(f.Category, f.Key)
+		if legacy == fileRecordID(f.Category, f.Key) {
+			continue
+		}
+		legacyRID := surrealmodels.NewRecordID("files", legacy)
+		record, err := surrealdb.Select[fileRecord](ctx, s.db, legacyRID)
+		if err != nil || record == nil || record.Category != f.Category || record.Key != f.Key {
+			continue // already migrated, or the legacy ID belongs to another file
+		}
+
+		sql := "UPSERT $rid CONTENT $record"
+		vars := map[string]any{
+			"rid":    surrealmodels.NewRecordID("files", fileRecordID(f.Category, f.Key)),
+			"record": record,
+		}
+		if _, err := surrealdb.Query[any](ctx, s.db, sql, vars); err != nil {
+			return moved, fmt.Errorf("failed to migrate file %s/%s: %w", f.Category, f.Key, err)
+		}
+		if _, err := surrealdb.Delete[fileRecord](ctx, s.db, legacyRID); err != nil && !isNotFoundError(err) {
+			return moved, fmt.Errorf("failed to remove legacy file record %s/%s: %w", f.Category, f.Key, err)
+		}
+		moved++
+	}
+	if moved > 0 {
+		s.logger.Info().Int("moved", moved).Msg("Migrated file records to collision-free IDs")
+	}
+	return moved, nil
 }
 
 // maxCBORDocBytes is the maximum encoded document size for SurrealDB's CBOR wire format.
@@ -57,9 +124,12 @@ func (s *FileStore) SaveFile(ctx context.Context, category, key string, data []b
 	now := time.Now()
 	encoded := base64.StdEncoding.EncodeToString(data)
 
+	// created_at is only set when the record is first written
 	sql := `UPSERT $rid SET
 		category = $category, key = $key, content_type = $content_type,
-		size = $size, data = $data, created_at = $created_at, updated_at = $updated_at`
+		size = $size, data = $data,
+		created_at = IF created_at IS NOT NONE THEN created_at ELSE $now END,
+		updated_at = $now`
 	vars := map[string]any{
 		"rid":          surrealmodels.NewRecordID("files", fileRecordID(category, key)),
 		"category":     category,
@@ -67,8 +137,7 @@ func (s *FileStore) SaveFile(ctx context.Context, category, key string, data []b
 		"content_type": contentType,
 		"size":         len(data),
 		"data":         encoded,
-		"created_at":   now,
-		"updated_at":   now,
+		"now":          now,
 	}
 
 	if _, err := surrealdb.Query[any](ctx, s.db, sql, vars); err != nil {
@@ -115,5 +184,28 @@ func (s *FileStore) HasFile(ctx context.Context, category, key string) (bool, er
 	return record != nil, nil
 }
 
+// StatFile returns a file's metadata without decoding its content.
+func (s *FileStore) StatFile(ctx context.Context, category, key string) (*models.FileMeta, error) {
+	sql := "SELECT category, key, content_type, size, created_at, updated_at FROM $rid"
+	vars := map[string]any{"rid": surrealmodels.NewRecordID("files", fileRecordID(category, key))}
+
+	results, err := surrealdb.Query[[]fileRecord](ctx, s.db, sql, vars)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat file %s/%s: %w", category, key, err)
+	}
+	if results == nil || len(*results) == 0 || len((*results)[0].Result) == 0 {
+		return nil, fmt.Errorf("file not found: %s/%s", category, key)
+	}
+	r := (*results)[0].Result[0]
+	return &models.FileMeta{
+		Category:    r.Category,
+		Key:         r.Key,
+		ContentType: r.ContentType,
+		Size:        int64(r.Size),
+		CreatedAt:   r.CreatedAt,
+		UpdatedAt:   r.UpdatedAt,
+	}, nil
+}
+
 // Compile-time check
 var _ interfaces.FileStore = (*FileStore)(nil)
@@ -143,9 +143,8 @@ func TestFileStoreStress_RecordIDCollision(t *testing.T) {
 	store := NewFileStore(db, testLogger())
 	ctx := context.Background()
 
-	// These two produce the same record ID after sanitization:
-	// fileRecordID("filing_pdf", "BHP/test") -> "filing_pdf_BHP_test"
-	// fileRecordID("filing", "pdf_BHP_test") -> "filing_pdf_BHP_test"
+	// These two produced the same record ID under the legacy sanitized scheme
+	// ("filing_pdf_BHP_test"); fileRecordID now keeps them apart.
 
 	store.SaveFile(ctx, "filing_pdf", "BHP/test", []byte("data-a"), "application/pdf")
 	store.SaveFile(ctx, "filing", "pdf_BHP_test", []byte("data-b"), "application/pdf")
@@ -155,13 +154,10 @@ func TestFileStoreStress_RecordIDCollision(t *testing.T) {
 	dataB, _, errB := store.GetFile(ctx, "filing", "pdf_BHP_test")
 
 	if errA != nil || errB != nil {
-		t.Logf("GetFile errors: a=%v, b=%v", errA, errB)
+		t.Fatalf("GetFile errors: a=%v, b=%v", errA, errB)
 	}
-
-	if errA == nil && errB == nil && bytes.Equal(dataA, dataB) {
-		t.Log("CONFIRMED: Record ID collision — (filing_pdf, BHP/test) and (filing, pdf_BHP_test) " +
-			"map to the same SurrealDB record ID. Second write overwrote the first. " +
-			"Fix: use a non-sanitizable separator like '::' between category and key in fileRecordID.")
+	if !bytes.Equal(dataA, []byte("data-a")) || !bytes.Equal(dataB, []byte("data-b")) {
+		t.Errorf("record ID collision: got a=%q b=%q, want data-a and data-b", dataA, dataB)
 	}
 }
 
@@ -3,7 +3,13 @@ package surrealdb
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/surrealdb/surrealdb.go"
+	surrealmodels "github.com/surrealdb/surrealdb.go/pkg/models"
 )
 
 func TestFileStore_SaveAndGet(t *testing.T) {
@@ -95,6 +101,43 @@ func TestFileStore_Overwrite(t *testing.T) {
 	}
 }
 
+func TestFileStore_OverwriteKeepsCreatedAt(t *testing.T) {
+	db := testDB(t)
+	store := NewFileStore(db, testLogger())
+	ctx := context.Background()
+
+	if err := store.SaveFile(ctx, "chart", "port/created.png", []byte("v1"), "image/png"); err != nil {
+		t.Fatalf("SaveFile failed: %v", err)
+	}
+	first, err := store.StatFile(ctx, "chart", "port/created.png")
+	if err != nil {
+		t.Fatalf("StatFile failed: %v", err)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if err := store.SaveFile(ctx, "chart", "port/created.png", []byte("version 2"), "image/png"); err != nil {
+		t.Fatalf("SaveFile (overwrite) failed: %v", err)
+	}
+	second, err := store.StatFile(ctx, "chart", "port/created.png")
+	if err != nil {
+		t.Fatalf("StatFile failed: %v", err)
+	}
+
+	if !second.CreatedAt.Equal(first.CreatedAt) {
+		t.Errorf("created_at changed on overwrite: %v -> %v", first.CreatedAt, second.CreatedAt)
+	}
+	if !second.UpdatedAt.After(first.UpdatedAt) {
+		t.Errorf("updated_at did not advance: %v -> %v", first.UpdatedAt, second.UpdatedAt)
+	}
+	if second.Size != 9 || second.ContentType != "image/png" {
+		t.Errorf("meta = %+v, want size 9 and image/png", second)
+	}
+
+	if _, err := store.StatFile(ctx, "chart", "port/missing.png"); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
+
 func TestFileStore_BinaryData(t *testing.T) {
 	db := testDB(t)
 	store := NewFileStore(db, testLogger())
@@ -118,3 +161,79 @@ func TestFileStore_BinaryData(t *testing.T) {
 		t.Errorf("binary data round-trip failed: got %d bytes, want %d bytes", len(got), len(data))
 	}
 }
+
+func TestFileRecordID_NoCollisions(t *testing.T) {
+	long := strings.Repeat("A", 10000)
+	pairs := [][2]string{
+		{"filing_pdf", "BHP/test.pdf"},
+		{"filing", "pdf_BHP_test_pdf"}, // collided with the pair above under the legacy scheme
+		{"filing_pdf", "BHP_test_pdf"},
+		{"filing_pdf", "BHP/test"},
+		{"filing", "pdf_BHP_test"},
+		{"filing_pdf", "BHP/\x00null.pdf"},
+		{"filing_pdf\x00", "BHP/null.pdf"},
+		{"filing_pdf", "BHP/null.pdf"},
+		{"ab", "c"},
+		{"a", "bc"},
+		{"filing_pdf", long},
+		{"filing_pdf", long + "B"},
+	}
+
+	seen := make(map[string][2]string)
+	for _, p := range pairs {
+		id := fileRecordID(p[0], p[1])
+		if prev, ok := seen[id]; ok {
+			t.Errorf("fileRecordID(%q, %q) collides with (%q, %q): %s", p[0], p[1], prev[0], prev[1], id)
+		}
+		seen[id] = p
+
+		if strings.ContainsRune(id, 0) {
+			t.Errorf("fileRecordID(%q, ...) contains a null byte", p[0])
+		}
+		if len(id) > 128 {
+			t.Errorf("fileRecordID(%q, <%d bytes>) is %d bytes long", p[0], len(p[1]), len(id))
+		}
+	}
+
+	if legacyFileRecordID("filing_pdf", "BHP/test.pdf") != legacyFileRecordID("filing", "pdf_BHP_test_pdf") {
+		t.Error("expected the documented pair to collide under the legacy scheme")
+	}
+}
+
+func TestFileStore_MigrateRecordIDs(t *testing.T) {
+	db := testDB(t)
+	store := NewFileStore(db, testLogger())
+	ctx := context.Background()
+
+	// Write a record the way the legacy scheme did
+	now := time.Now()
+	sql := "UPSERT $rid CONTENT $record"
+	vars := map[string]any{
+		"rid": surrealmodels.NewRecordID("files", legacyFileRecordID("filing_pdf", "OLD/doc.pdf")),
+		"record": fileRecord{
+			Category: "filing_pdf", Key: "OLD/doc.pdf", ContentType: "application/pdf",
+			Size: 3, Data: base64.StdEncoding.EncodeToString([]byte("old")), CreatedAt: now, UpdatedAt: now,
+		},
+	}
+	if _, err := surrealdb.Query[any](ctx, db, sql, vars); err != nil {
+		t.Fatalf("seed legacy record: %v", err)
+	}
+
+	moved, err := store.MigrateRecordIDs(ctx)
+	if err != nil {
+		t.Fatalf("MigrateRecordIDs failed: %v", err)
+	}
+	if moved != 1 {
+		t.Errorf("moved = %d, want 1", moved)
+	}
+
+	got, _, err := store.GetFile(ctx, "filing_pdf", "OLD/doc.pdf")
+	if err != nil || string(got) != "old" {
+		t.Errorf("GetFile after migration = %q, %v; want \"old\"", got, err)
+	}
+
+	// Running again is a no-op
+	if moved, err := store.MigrateRecordIDs(ctx); err != nil || moved != 0 {
+		t.Errorf("second migration moved %d (err %v), want 0", moved, err)
+	}
+}
@@ -202,6 +202,40 @@ func (s *InternalStore) SetSystemKV(ctx context.Context, key, value string) erro
 	return fmt.Errorf("failed to set system KV after retries: %w", lastErr)
 }
 
+func (s *InternalStore) DeleteSystemKV(ctx context.Context, key string) error {
+	type SysKV struct {
+		Key   string `json:"key"`
+		Value string `json:"value"`
+	}
+	_, err := surrealdb.Delete[SysKV](ctx, s.db, surrealmodels.NewRecordID("system_kv", key))
+	if err != nil && !isNotFoundError(err) {
+		return fmt.Errorf("failed to delete system KV: %w", err)
+	}
+	return nil
+}
+
+// ListSystemKV returns the system KV entries whose key starts with prefix.
+func (s *InternalStore) ListSystemKV(ctx context.Context, prefix string) (map[string]string, error) {
+	type SysKV struct {
+		Key   string `json:"key"`
+		Value string `json:"value"`
+	}
+	sql := "SELECT key, value FROM system_kv WHERE string::starts_with(key, $prefix)"
+	vars := map[string]any{"prefix": prefix}
+
+	results, err := surrealdb.Query[[]SysKV](ctx, s.db, sql, vars)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list system KV: %w", err)
+	}
+	entries := make(map[string]string)
+	if results != nil && len(*results) > 0 {
+		for _, kv := range (*results)[0].Result {
+			entries[kv.Key] = kv.Value
+		}
+	}
+	return entries, nil
+}
+
 func (s *InternalStore) Close() error {
 	return nil
 }
@@ -14,7 +14,7 @@ import (
 )
 
 // jobSelectFields lists the fields to select from job_queue, aliasing job_id to id for struct mapping.
-const jobSelectFields = "job_id as id, job_type, ticker, batch_id, priority, status, created_at, started_at, completed_at, error, attempts, max_attempts, duration_ms"
+const jobSelectFields = "job_id as id, job_type, ticker, batch_id, priority, status, created_at, started_at, completed_at, error, attempts, max_attempts, duration_ms, next_attempt_at"
 
 // JobQueueStore implements interfaces.JobQueueStore using SurrealDB.
 type JobQueueStore struct {
@@ -45,22 +45,24 @@ func (s *JobQueueStore) Enqueue(ctx context.Context, job *models.Job) error {
 		job_id = $job_id, job_type = $job_type, ticker = $ticker, batch_id = $batch_id,
 		priority = $priority, status = $status, created_at = $created_at,
 		started_at = $started_at, completed_at = $completed_at, error = $error,
-		attempts = $attempts, max_attempts = $max_attempts, duration_ms = $duration_ms`
+		attempts = $attempts, max_attempts = $max_attempts, duration_ms = $duration_ms,
+		next_attempt_at = $next_attempt_at`
 	vars := map[string]any{
-		"rid":          surrealmodels.NewRecordID("job_queue", job.ID),
-		"job_id":       job.ID,
-		"job_type":     job.JobType,
-		"ticker":       job.Ticker,
-		"batch_id":     job.BatchID,
-		"priority":     job.Priority,
-		"status":       job.Status,
-		"created_at":   job.CreatedAt,
-		"started_at":   job.StartedAt,
-		"completed_at": job.CompletedAt,
-		"error":        job.Error,
-		"attempts":     job.Attempts,
-		"max_attempts": job.MaxAttempts,
-		"duration_ms":  job.DurationMS,
+		"rid":             surrealmodels.NewRecordID("job_queue", job.ID),
+		"job_id":          job.ID,
+		"job_type":        job.JobType,
+		"ticker":          job.Ticker,
+		"batch_id":        job.BatchID,
+		"priority":        job.Priority,
+		"status":          job.Status,
+		"created_at":      job.CreatedAt,
+		"started_at":      job.StartedAt,
+		"completed_at":    job.CompletedAt,
+		"error":           job.Error,
+		"attempts":        job.Attempts,
+		"max_attempts":    job.MaxAttempts,
+		"duration_ms":     job.DurationMS,
+		"next_attempt_at": job.NextAttemptAt,
 	}
 
 	if _, err := surrealdb.Query[any](ctx, s.db, sql, vars); err != nil {
@@ -72,9 +74,10 @@ func (s *JobQueueStore) Enqueue(ctx context.Context, job *models.Job) error {
 func (s *JobQueueStore) Dequeue(ctx context.Context) (*models.Job, error) {
 	// Two-step dequeue: SELECT highest priority pending job, then UPDATE it to running.
 	// Step 1: Find the candidate (alias job_id as id for struct mapping)
-	selectSQL := "SELECT " + jobSelectFields + " FROM job_queue WHERE status = $pending ORDER BY priority DESC, created_at ASC LIMIT 1"
+	selectSQL := "SELECT " + jobSelectFields + " FROM job_queue WHERE status = $pending AND (next_attempt_at IS NONE OR next_attempt_at <= $now) ORDER BY priority DESC, created_at ASC LIMIT 1"
 	vars := map[string]any{
 		"pending": models.JobStatusPending,
+		"now":     time.Now(),
 	}
 
 	candidates, err := surrealdb.Query[[]models.Job](ctx, s.db, selectSQL, vars)
@@ -246,6 +249,32 @@ func (s *JobQueueStore) HasPendingJob(ctx context.Context, jobType, ticker strin
 	return false, nil
 }
 
+// HasActiveJob reports whether a job of the given type+ticker is pending or
+// running, so callers can avoid queueing a duplicate behind a running job.
+func (s *JobQueueStore) HasActiveJob(ctx context.Context, jobType, ticker string) (bool, error) {
+	sql := "SELECT count() AS cnt FROM job_queue WHERE job_type = $type AND ticker = $ticker AND status IN [$pending, $running] GROUP ALL"
+	vars := map[string]any{
+		"type":    jobType,
+		"ticker":  ticker,
+		"pending": models.JobStatusPending,
+		"running": models.JobStatusRunning,
+	}
+
+	type countResult struct {
+		Cnt int `json:"cnt"`
+	}
+
+	results, err := surrealdb.Query[[]countResult](ctx, s.db, sql, vars)
+	if err != nil {
+		return false, fmt.Errorf("failed to check active job: %w", err)
+	}
+
+	if results != nil && len(*results) > 0 && len((*results)[0].Result) > 0 {
+		return (*results)[0].Result[0].Cnt > 0, nil
+	}
+	return false, nil
+}
+
 func (s *JobQueueStore) PurgeCompleted(ctx context.Context, olderThan time.Time) (int, error) {
 	sql := "DELETE FROM job_queue WHERE status IN [$completed, $failed] AND completed_at < $cutoff"
 	vars := map[string]any{
@@ -312,5 +341,41 @@ func (s *JobQueueStore) ResetRunningJobs(ctx context.Context) (int, error) {
 	return 0, nil
 }
 
+// ListDeadLetter returns dead-lettered jobs, most recently failed first.
+func (s *JobQueueStore) ListDeadLetter(ctx context.Context, limit int) ([]*models.Job, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	sql := "SELECT " + jobSelectFields + " FROM job_queue WHERE status = $dead ORDER BY completed_at DESC LIMIT $limit"
+	vars := map[string]any{"dead": models.JobStatusDeadLetter, "limit": limit}
+	return s.queryJobs(ctx, sql, vars)
+}
+
+// RequeueDeadLetter returns a dead-lettered job to pending with its attempts
+// and error cleared. Returns a nil job when id is not dead-lettered.
+func (s *JobQueueStore) RequeueDeadLetter(ctx context.Context, id string) (*models.Job, error) {
+	rid := surrealmodels.NewRecordID("job_queue", id)
+	sql := `UPDATE $rid SET status = $pending, attempts = 0, error = "", started_at = NONE,
+		completed_at = NONE, duration_ms = 0, next_attempt_at = NONE WHERE status = $dead`
+	vars := map[string]any{
+		"rid":     rid,
+		"pending": models.JobStatusPending,
+		"dead":    models.JobStatusDeadLetter,
+	}
+	results, err := surrealdb.Query[[]map[string]any](ctx, s.db, sql, vars)
+	if err != nil {
+		return nil, fmt.Errorf("failed to requeue dead-letter job: %w", err)
+	}
+	if results == nil || len(*results) == 0 || len((*results)[0].Result) == 0 {
+		return nil, nil
+	}
+
+	jobs, err := s.queryJobs(ctx, "SELECT "+jobSelectFields+" FROM $rid", map[string]any{"rid": rid})
+	if err != nil || len(jobs) == 0 {
+		return nil, err
+	}
+	return jobs[0], nil
+}
+
 // Compile-time check
 var _ interfaces.JobQueueStore = (*JobQueueStore)(nil)
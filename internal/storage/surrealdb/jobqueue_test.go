@@ -176,6 +176,33 @@ func TestJobQueueStore_HasPendingJob(t *testing.T) {
 	}
 }
 
+func TestJobQueueStore_HasActiveJob(t *testing.T) {
+	db := testDB(t)
+	store := NewJobQueueStore(db, testLogger())
+	ctx := context.Background()
+
+	store.Enqueue(ctx, &models.Job{JobType: models.JobTypeCollectEOD, Ticker: "BHP.AU", Priority: 10, MaxAttempts: 3})
+	job, _ := store.Dequeue(ctx) // now running
+	if job == nil {
+		t.Fatal("expected a job to dequeue")
+	}
+
+	has, _ := store.HasPendingJob(ctx, models.JobTypeCollectEOD, "BHP.AU")
+	if has {
+		t.Error("HasPendingJob should ignore a running job")
+	}
+	has, _ = store.HasActiveJob(ctx, models.JobTypeCollectEOD, "BHP.AU")
+	if !has {
+		t.Error("HasActiveJob should report a running job")
+	}
+
+	store.Complete(ctx, job.ID, nil, 10)
+	has, _ = store.HasActiveJob(ctx, models.JobTypeCollectEOD, "BHP.AU")
+	if has {
+		t.Error("HasActiveJob should ignore a completed job")
+	}
+}
+
 func TestJobQueueStore_ListPending(t *testing.T) {
 	db := testDB(t)
 	store := NewJobQueueStore(db, testLogger())
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/bobmcallan/vire/internal/common"
+	"github.com/bobmcallan/vire/internal/interfaces"
 	"github.com/bobmcallan/vire/internal/models"
 	"github.com/surrealdb/surrealdb.go"
 	surrealmodels "github.com/surrealdb/surrealdb.go/pkg/models"
@@ -35,12 +36,20 @@ func (s *MarketStore) GetMarketData(ctx context.Context, ticker string) (*models
 		return nil, fmt.Errorf("failed to select market data: %w", err)
 	}
 	if data == nil {
-		return nil, fmt.Errorf("market data not found")
+		return nil, interfaces.ErrMarketDataNotFound
 	}
 	return data, nil
 }
 
+// SaveMarketData upserts the record. EOD bars are normalized first (newest
+// first, one per date) so readers can index EOD by trading-day offset.
 func (s *MarketStore) SaveMarketData(ctx context.Context, data *models.MarketData) error {
+	if n := len(data.EOD); n > 0 {
+		data.EOD = models.NormalizeEOD(data.EOD)
+		if dropped := n - len(data.EOD); dropped > 0 {
+			s.logger.Warn().Str("ticker", data.Ticker).Int("duplicates", dropped).Msg("Dropped duplicate EOD bars on save")
+		}
+	}
 	sql := "UPSERT $rid CONTENT $data"
 	vars := map[string]any{"rid": surrealmodels.NewRecordID("market_data", data.Ticker), "data": data}
 
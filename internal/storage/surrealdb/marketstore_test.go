@@ -130,6 +130,29 @@ func TestSaveMarketDataOverwrite(t *testing.T) {
 	assert.Len(t, got.EOD, 2)
 }
 
+func TestSaveMarketDataNormalizesEOD(t *testing.T) {
+	db := testDB(t)
+	dataPath := t.TempDir()
+	store := NewMarketStore(db, testLogger(), dataPath)
+	ctx := context.Background()
+
+	data := newTestMarketData("DUP", "AU")
+	data.EOD = []models.EODBar{
+		{Date: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), Close: 102.0},
+		{Date: time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC), Close: 103.0},
+		{Date: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Close: 101.0},
+		{Date: time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC), Close: 103.5, AdjClose: 103.5},
+	}
+	require.NoError(t, store.SaveMarketData(ctx, data))
+
+	got, err := store.GetMarketData(ctx, "DUP")
+	require.NoError(t, err)
+	require.Len(t, got.EOD, 3)
+	assert.Equal(t, 103.5, got.EOD[0].Close, "adjusted duplicate should win")
+	assert.Equal(t, 102.0, got.EOD[1].Close)
+	assert.Equal(t, 101.0, got.EOD[2].Close)
+}
+
 func TestGetMarketDataBatch(t *testing.T) {
 	db := testDB(t)
 	dataPath := t.TempDir()
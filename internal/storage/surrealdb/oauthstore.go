@@ -108,12 +108,12 @@ func (s *OAuthStore) GetClient(ctx context.Context, clientID string) (*models.OA
 	results, err := surrealdb.Query[[]oauthClientRow](ctx, s.db, sql, vars)
 	if err != nil {
 		if isNotFoundError(err) {
-			return nil, fmt.Errorf("oauth client not found: %s", clientID)
+			return nil, fmt.Errorf("%w: %s", interfaces.ErrOAuthClientNotFound, clientID)
 		}
 		return nil, fmt.Errorf("failed to get oauth client: %w", err)
 	}
 	if results == nil || len(*results) == 0 || len((*results)[0].Result) == 0 {
-		return nil, fmt.Errorf("oauth client not found: %s", clientID)
+		return nil, fmt.Errorf("%w: %s", interfaces.ErrOAuthClientNotFound, clientID)
 	}
 	row := (*results)[0].Result[0]
 	return &models.OAuthClient{
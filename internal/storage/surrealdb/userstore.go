@@ -3,6 +3,7 @@ package surrealdb
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/bobmcallan/vire/internal/common"
 	"github.com/bobmcallan/vire/internal/interfaces"
@@ -33,7 +34,7 @@ func (s *UserStore) Get(ctx context.Context, userID, subject, key string) (*mode
 		return nil, fmt.Errorf("failed to select user record: %w", err)
 	}
 	if record == nil {
-		return nil, fmt.Errorf("user record not found")
+		return nil, interfaces.ErrRecordNotFound
 	}
 	return record, nil
 }
@@ -85,21 +86,20 @@ func (s *UserStore) List(ctx context.Context, userID, subject string) ([]*models
 }
 
 func (s *UserStore) Query(ctx context.Context, userID, subject string, opts interfaces.QueryOptions) ([]*models.UserRecord, error) {
-	sql := "SELECT * FROM user_data WHERE user_id = $user_id AND subject = $subject"
+	sql, vars := userQueryWhere(userID, subject, opts)
 
+	// Ties broken by key so LIMIT/START pages are stable
 	if opts.OrderBy == "datetime_asc" {
-		sql += " ORDER BY datetime ASC"
+		sql += " ORDER BY datetime ASC, key ASC"
 	} else {
-		sql += " ORDER BY datetime DESC"
+		sql += " ORDER BY datetime DESC, key ASC"
 	}
 
 	if opts.Limit > 0 {
 		sql += fmt.Sprintf(" LIMIT %d", opts.Limit)
 	}
-
-	vars := map[string]any{
-		"user_id": userID,
-		"subject": subject,
+	if opts.Offset > 0 {
+		sql += fmt.Sprintf(" START %d", opts.Offset)
 	}
 
 	results, err := surrealdb.Query[[]models.UserRecord](ctx, s.db, sql, vars)
@@ -117,6 +117,41 @@ func (s *UserStore) Query(ctx context.Context, userID, subject string, opts inte
 	return nil, nil
 }
 
+func (s *UserStore) Count(ctx context.Context, userID, subject string, opts interfaces.QueryOptions) (int, error) {
+	where, vars := userQueryWhere(userID, subject, opts)
+	sql := strings.Replace(where, "SELECT *", "SELECT count() AS count", 1) + " GROUP ALL"
+
+	type countRow struct {
+		Count int `json:"count"`
+	}
+	results, err := surrealdb.Query[[]countRow](ctx, s.db, sql, vars)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count user records: %w", err)
+	}
+	if results != nil && len(*results) > 0 && len((*results)[0].Result) > 0 {
+		return (*results)[0].Result[0].Count, nil
+	}
+	return 0, nil
+}
+
+// userQueryWhere builds the SELECT and WHERE clause shared by Query and Count.
+func userQueryWhere(userID, subject string, opts interfaces.QueryOptions) (string, map[string]any) {
+	sql := "SELECT * FROM user_data WHERE user_id = $user_id AND subject = $subject"
+	vars := map[string]any{
+		"user_id": userID,
+		"subject": subject,
+	}
+	if opts.Key != "" {
+		sql += " AND string::lowercase(key) = $key"
+		vars["key"] = strings.ToLower(opts.Key)
+	}
+	if opts.KeyPrefix != "" {
+		sql += " AND string::starts_with(key, $key_prefix)"
+		vars["key_prefix"] = opts.KeyPrefix
+	}
+	return sql, vars
+}
+
 func (s *UserStore) DeleteBySubject(ctx context.Context, subject string) (int, error) {
 	sql := "DELETE user_data WHERE subject = $subject RETURN BEFORE"
 	vars := map[string]any{"subject": subject}
@@ -203,6 +203,18 @@ func TestUserStoreQuery(t *testing.T) {
 		require.NoError(t, err)
 		assert.Len(t, records, 2)
 	})
+
+	t.Run("with key prefix", func(t *testing.T) {
+		require.NoError(t, store.Put(ctx, &models.UserRecord{
+			UserID: "queryuser", Subject: "report", Key: "other_1", Value: "x", Version: 1, DateTime: base,
+		}))
+		records, err := store.Query(ctx, "queryuser", "report", interfaces.QueryOptions{KeyPrefix: "report_"})
+		require.NoError(t, err)
+		assert.Len(t, records, 5)
+		count, err := store.Count(ctx, "queryuser", "report", interfaces.QueryOptions{KeyPrefix: "other_"})
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
 }
 
 func TestUserStoreDeleteBySubject(t *testing.T) {
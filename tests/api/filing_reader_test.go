@@ -64,10 +64,13 @@ func findFilingDocumentKey(t *testing.T, env *common.Env, ticker string) string
 		return ""
 	}
 
-	var data map[string]interface{}
-	if err := json.Unmarshal(body, &data); err != nil {
+	var envelope struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
 		return ""
 	}
+	data := envelope.Data
 
 	// Navigate to filings array in the market data
 	marketData, ok := data["market_data"].(map[string]interface{})
@@ -49,8 +49,7 @@ func TestGetStockData_IncludeParam(t *testing.T) {
 			"?include=price should be accepted, not rejected as bad request")
 
 		if resp.StatusCode == 200 {
-			var result map[string]interface{}
-			require.NoError(t, json.Unmarshal(body, &result))
+			result := decodeStockData(t, body)
 
 			assert.NotContains(t, result, "fundamentals",
 				"fundamentals should not be present when only price is requested")
@@ -79,8 +78,7 @@ func TestGetStockData_IncludeParam(t *testing.T) {
 			"?include=price&include=fundamentals should be accepted as valid")
 
 		if resp.StatusCode == 200 {
-			var result map[string]interface{}
-			require.NoError(t, json.Unmarshal(body, &result))
+			result := decodeStockData(t, body)
 
 			// signals and news must be absent — not requested
 			assert.NotContains(t, result, "signals",
@@ -103,8 +101,7 @@ func TestGetStockData_IncludeParam(t *testing.T) {
 			"?include=price,signals (comma-separated) should be accepted as valid")
 
 		if resp.StatusCode == 200 {
-			var result map[string]interface{}
-			require.NoError(t, json.Unmarshal(body, &result))
+			result := decodeStockData(t, body)
 
 			assert.NotContains(t, result, "fundamentals",
 				"fundamentals should not be present when only price,signals are requested")
@@ -129,8 +126,7 @@ func TestGetStockData_IncludeParam(t *testing.T) {
 			"mixed include formats should be accepted as valid")
 
 		if resp.StatusCode == 200 {
-			var result map[string]interface{}
-			require.NoError(t, json.Unmarshal(body, &result))
+			result := decodeStockData(t, body)
 
 			assert.NotContains(t, result, "fundamentals",
 				"fundamentals should not be present when only price,signals,news are requested")
@@ -197,3 +193,15 @@ func TestGetStockData_IncludeParam(t *testing.T) {
 			"duplicate include values should not cause a server error")
 	})
 }
+
+// decodeStockData unwraps the {data, advisory, background_jobs} envelope
+// returned by GET /api/market/stocks/{ticker}.
+func decodeStockData(t *testing.T, body []byte) map[string]interface{} {
+	t.Helper()
+	var envelope struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(body, &envelope))
+	require.NotNil(t, envelope.Data, "response should wrap stock data in a data envelope: %s", string(body))
+	return envelope.Data
+}
@@ -72,9 +72,7 @@ func getStockDataWithPrice(t *testing.T, env *common.Env, ticker string, headers
 
 	require.Equal(t, http.StatusOK, resp.StatusCode, "GET stock data failed: %s", string(body))
 
-	var result map[string]interface{}
-	require.NoError(t, json.Unmarshal(body, &result))
-	return result
+	return decodeStockData(t, body)
 }
 
 // forceRefreshStockData forces a re-fetch of stock data with force_refresh=true.
@@ -94,9 +92,7 @@ func forceRefreshStockData(t *testing.T, env *common.Env, ticker string, headers
 
 	require.Equal(t, http.StatusOK, resp.StatusCode, "Force refresh stock data failed: %s", string(body))
 
-	var result map[string]interface{}
-	require.NoError(t, json.Unmarshal(body, &result))
-	return result
+	return decodeStockData(t, body)
 }
 
 // getCandlesCount extracts the candles array length from stock data.
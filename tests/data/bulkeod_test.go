@@ -38,6 +38,9 @@ func (m *mockEODHD) GetBulkRealTimeQuotes(ctx context.Context, tickers []string)
 	}
 	return nil, fmt.Errorf("not implemented")
 }
+func (m *mockEODHD) GetRealTimeQuotesBatch(ctx context.Context, tickers []string) (map[string]*models.RealTimeQuote, error) {
+	return nil, fmt.Errorf("not implemented")
+}
 func (m *mockEODHD) GetBulkEOD(ctx context.Context, exchange string, tickers []string) (map[string]models.EODBar, error) {
 	if m.getBulkEODFn != nil {
 		return m.getBulkEODFn(ctx, exchange, tickers)